@@ -15,8 +15,9 @@ import (
 )
 
 type Routes struct {
-	Root    *mux.Router // ''
-	ApiRoot *mux.Router // 'api/v4'
+	Root     *mux.Router // ''
+	ApiRoot  *mux.Router // 'api/v4'
+	ApiRoot5 *mux.Router // 'api/v5'
 
 	Users          *mux.Router // 'api/v4/users'
 	User           *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}'
@@ -48,6 +49,9 @@ type Routes struct {
 	PostsForUser    *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/posts'
 	PostForUser     *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/posts/{post_id:[A-Za-z0-9]+}'
 
+	ThreadsForUser *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/threads'
+	ThreadForUser  *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/threads/{post_id:[A-Za-z0-9]+}'
+
 	Files *mux.Router // 'api/v4/files'
 	File  *mux.Router // 'api/v4/files/{file_id:[A-Za-z0-9]+}'
 
@@ -100,6 +104,18 @@ type Routes struct {
 	Roles   *mux.Router // 'api/v4/roles'
 	Schemes *mux.Router // 'api/v4/schemes'
 
+	ChannelBridges *mux.Router // 'api/v4/bridges'
+
+	UserGroups *mux.Router // 'api/v4/groups'
+
+	Reminders *mux.Router // 'api/v4/reminders'
+
+	Bots *mux.Router // 'api/v4/bots'
+	Bot  *mux.Router // 'api/v4/bots/{bot_user_id:[A-Za-z0-9]+}'
+
+	EventSubscriptions *mux.Router // 'api/v4/subscriptions'
+	EventSubscription  *mux.Router // 'api/v4/subscriptions/{subscription_id:[A-Za-z0-9]+}'
+
 	Emojis      *mux.Router // 'api/v4/emoji'
 	Emoji       *mux.Router // 'api/v4/emoji/{emoji_id:[A-Za-z0-9]+}'
 	EmojiByName *mux.Router // 'api/v4/emoji/name/{emoji_name:[A-Za-z0-9_-\.]+}'
@@ -122,6 +138,7 @@ func Init(a *app.App, root *mux.Router) *API {
 
 	api.BaseRoutes.Root = root
 	api.BaseRoutes.ApiRoot = root.PathPrefix(model.API_URL_SUFFIX).Subrouter()
+	api.BaseRoutes.ApiRoot5 = root.PathPrefix(model.API_URL_SUFFIX_V5).Subrouter()
 
 	api.BaseRoutes.Users = api.BaseRoutes.ApiRoot.PathPrefix("/users").Subrouter()
 	api.BaseRoutes.User = api.BaseRoutes.ApiRoot.PathPrefix("/users/{user_id:[A-Za-z0-9]+}").Subrouter()
@@ -153,8 +170,11 @@ func Init(a *app.App, root *mux.Router) *API {
 	api.BaseRoutes.PostsForUser = api.BaseRoutes.User.PathPrefix("/posts").Subrouter()
 	api.BaseRoutes.PostForUser = api.BaseRoutes.PostsForUser.PathPrefix("/{post_id:[A-Za-z0-9]+}").Subrouter()
 
+	api.BaseRoutes.ThreadsForUser = api.BaseRoutes.User.PathPrefix("/threads").Subrouter()
+	api.BaseRoutes.ThreadForUser = api.BaseRoutes.ThreadsForUser.PathPrefix("/{post_id:[A-Za-z0-9]+}").Subrouter()
+
 	api.BaseRoutes.Files = api.BaseRoutes.ApiRoot.PathPrefix("/files").Subrouter()
-	api.BaseRoutes.File = api.BaseRoutes.Files.PathPrefix("/{file_id:[A-Za-z0-9]+}").Subrouter()
+	api.BaseRoutes.File = api.BaseRoutes.ApiRoot.PathPrefix("/files/{file_id:[A-Za-z0-9]+}").Subrouter()
 	api.BaseRoutes.PublicFile = api.BaseRoutes.Root.PathPrefix("/files/{file_id:[A-Za-z0-9]+}/public").Subrouter()
 
 	api.BaseRoutes.Plugins = api.BaseRoutes.ApiRoot.PathPrefix("/plugins").Subrouter()
@@ -201,6 +221,18 @@ func Init(a *app.App, root *mux.Router) *API {
 	api.BaseRoutes.Roles = api.BaseRoutes.ApiRoot.PathPrefix("/roles").Subrouter()
 	api.BaseRoutes.Schemes = api.BaseRoutes.ApiRoot.PathPrefix("/schemes").Subrouter()
 
+	api.BaseRoutes.ChannelBridges = api.BaseRoutes.ApiRoot.PathPrefix("/bridges").Subrouter()
+
+	api.BaseRoutes.UserGroups = api.BaseRoutes.ApiRoot.PathPrefix("/groups").Subrouter()
+
+	api.BaseRoutes.Reminders = api.BaseRoutes.ApiRoot.PathPrefix("/reminders").Subrouter()
+
+	api.BaseRoutes.Bots = api.BaseRoutes.ApiRoot.PathPrefix("/bots").Subrouter()
+	api.BaseRoutes.Bot = api.BaseRoutes.Bots.PathPrefix("/{bot_user_id:[A-Za-z0-9]+}").Subrouter()
+
+	api.BaseRoutes.EventSubscriptions = api.BaseRoutes.ApiRoot.PathPrefix("/subscriptions").Subrouter()
+	api.BaseRoutes.EventSubscription = api.BaseRoutes.EventSubscriptions.PathPrefix("/{subscription_id:[A-Za-z0-9]+}").Subrouter()
+
 	api.BaseRoutes.Image = api.BaseRoutes.ApiRoot.PathPrefix("/image").Subrouter()
 
 	api.InitUser()
@@ -230,9 +262,28 @@ func Init(a *app.App, root *mux.Router) *API {
 	api.InitPlugin()
 	api.InitRole()
 	api.InitScheme()
+	api.InitChannelBridge()
+	api.InitThread()
+	api.InitUserGroup()
+	api.InitTimezone()
+	api.InitChannelOrganization()
+	api.InitSidebarCategory()
+	api.InitAutoResponder()
+	api.InitChannelNotifyDefaults()
+	api.InitReadReceipt()
+	api.InitReminder()
+	api.InitBot()
+	api.InitNotificationPreferences()
+	api.InitSms()
 	api.InitImage()
+	api.InitDebugRecording()
+	api.InitProfileCapture()
+	api.InitEventSubscription()
+	api.InitGraphQL()
+	api.InitInteractiveDialog()
 
 	root.Handle("/api/v4/{anything:.*}", http.HandlerFunc(api.Handle404))
+	root.Handle("/api/v5/{anything:.*}", http.HandlerFunc(api.Handle404))
 
 	a.InitEmailBatching()
 
@@ -131,6 +131,8 @@ func setupTestHelper(enterprise bool, updateConfig func(*model.Config)) *TestHel
 	th.App.Srv.Store.MarkSystemRanUnitTests()
 	th.App.DoAdvancedPermissionsMigration()
 	th.App.DoEmojisPermissionsMigration()
+	th.App.DoPrivateOutgoingWebhooksPermissionsMigration()
+	th.App.DoBotsPermissionsMigration()
 
 	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.TeamSettings.EnableOpenServer = true })
 
@@ -0,0 +1,86 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitAutoResponder() {
+	api.BaseRoutes.User.Handle("/auto_responder/schedule", api.ApiSessionRequired(getAutoResponderSchedule)).Methods("GET")
+	api.BaseRoutes.User.Handle("/auto_responder/schedule", api.ApiSessionRequired(setAutoResponderSchedule)).Methods("PUT")
+	api.BaseRoutes.User.Handle("/auto_responder/schedule", api.ApiSessionRequired(deleteAutoResponderSchedule)).Methods("DELETE")
+}
+
+func getAutoResponderSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	schedule, err := c.App.GetAutoResponderSchedule(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(schedule.ToJson()))
+}
+
+func setAutoResponderSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	schedule := model.AutoResponderScheduleFromJson(r.Body)
+	if schedule == nil {
+		c.SetInvalidParam("schedule")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	schedule.UserId = c.Params.UserId
+	schedule.CreateAt = 0
+
+	rschedule, err := c.App.SetAutoResponderSchedule(schedule)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("")
+	w.Write([]byte(rschedule.ToJson()))
+}
+
+func deleteAutoResponderSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := c.App.DeleteAutoResponderSchedule(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("")
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestAutoResponderSchedule(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	now := model.GetMillis()
+	schedule := &model.AutoResponderSchedule{StartAt: now + 60000, EndAt: now + 120000, SetStatus: true}
+
+	_, resp := Client.SetAutoResponderSchedule(th.BasicUser.Id, schedule)
+	CheckBadRequestStatus(t, resp)
+
+	patch := &model.UserPatch{NotifyProps: model.CopyStringMap(th.BasicUser.NotifyProps)}
+	patch.NotifyProps["auto_responder_message"] = "Out until Monday"
+	_, resp = Client.PatchUser(th.BasicUser.Id, patch)
+	CheckNoError(t, resp)
+
+	rschedule, resp := Client.SetAutoResponderSchedule(th.BasicUser.Id, schedule)
+	CheckNoError(t, resp)
+
+	if rschedule.UserId != th.BasicUser.Id || rschedule.Active {
+		t.Fatal("should have scheduled the auto responder without turning it on")
+	}
+
+	received, resp := Client.GetAutoResponderSchedule(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if received.StartAt != schedule.StartAt || received.EndAt != schedule.EndAt {
+		t.Fatal("should have returned the schedule")
+	}
+
+	_, resp = Client.SetAutoResponderSchedule(th.BasicUser.Id, &model.AutoResponderSchedule{StartAt: now - 120000, EndAt: now - 60000})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetAutoResponderSchedule(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.SetAutoResponderSchedule(th.BasicUser2.Id, schedule)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetAutoResponderSchedule(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	ok, resp := Client.DeleteAutoResponderSchedule(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have deleted the schedule")
+	}
+
+	_, resp = Client.GetAutoResponderSchedule(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitBot() {
+	api.BaseRoutes.Bots.Handle("", api.ApiSessionRequired(createBot)).Methods("POST")
+	api.BaseRoutes.Bots.Handle("", api.ApiSessionRequired(getBots)).Methods("GET")
+	api.BaseRoutes.Bot.Handle("", api.ApiSessionRequired(getBot)).Methods("GET")
+	api.BaseRoutes.Bot.Handle("", api.ApiSessionRequired(patchBot)).Methods("PUT")
+	api.BaseRoutes.Bot.Handle("/disable", api.ApiSessionRequired(disableBot)).Methods("POST")
+	api.BaseRoutes.Bot.Handle("/enable", api.ApiSessionRequired(enableBot)).Methods("POST")
+	api.BaseRoutes.Bot.Handle("/assign/{user_id:[A-Za-z0-9]+}", api.ApiSessionRequired(assignBot)).Methods("POST")
+}
+
+func createBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := model.BotFromJson(r.Body)
+	if bot == nil {
+		c.SetInvalidParam("bot")
+		return
+	}
+
+	if !*c.App.Config().ServiceSettings.EnableBotAccountCreation {
+		c.Err = model.NewAppError("createBot", "api.bot.create_disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_CREATE_BOT) {
+		c.SetPermissionError(model.PERMISSION_CREATE_BOT)
+		return
+	}
+
+	bot.OwnerId = c.Session.UserId
+
+	rbot, err := c.App.CreateBot(bot)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + rbot.UserId)
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rbot.ToJson()))
+}
+
+func getBots(c *Context, w http.ResponseWriter, r *http.Request) {
+	options := &model.BotGetOptions{
+		IncludeDeleted: r.URL.Query().Get("include_deleted") == "true",
+		OnlyOrphaned:   r.URL.Query().Get("only_orphaned") == "true",
+		Page:           c.Params.Page,
+		PerPage:        c.Params.PerPage,
+	}
+
+	// Users who can only manage their own bots only see those
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+		if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_BOTS) {
+			c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+			return
+		}
+
+		options.OwnerId = c.Session.UserId
+	}
+
+	bots, err := c.App.GetBots(options)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.BotListToJson(bots)))
+}
+
+func getBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBotUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if err := c.App.SessionHasPermissionToManageBot(c.Session, c.Params.BotUserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	bot, err := c.App.GetBot(c.Params.BotUserId, r.URL.Query().Get("include_deleted") == "true")
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(bot.ToJson()))
+}
+
+func patchBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBotUserId()
+	if c.Err != nil {
+		return
+	}
+
+	patch := model.BotPatchFromJson(r.Body)
+	if patch == nil {
+		c.SetInvalidParam("bot")
+		return
+	}
+
+	if err := c.App.SessionHasPermissionToManageBot(c.Session, c.Params.BotUserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	bot, err := c.App.PatchBot(c.Params.BotUserId, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + bot.UserId)
+
+	w.Write([]byte(bot.ToJson()))
+}
+
+func disableBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateBotActive(c, w, r, false)
+}
+
+func enableBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateBotActive(c, w, r, true)
+}
+
+func updateBotActive(c *Context, w http.ResponseWriter, r *http.Request, active bool) {
+	c.RequireBotUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if err := c.App.SessionHasPermissionToManageBot(c.Session, c.Params.BotUserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	bot, err := c.App.UpdateBotActive(c.Params.BotUserId, active)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if active {
+		c.LogAudit("enabled bot_user_id=" + bot.UserId)
+	} else {
+		c.LogAudit("disabled bot_user_id=" + bot.UserId)
+	}
+
+	w.Write([]byte(bot.ToJson()))
+}
+
+func assignBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBotUserId().RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if err := c.App.SessionHasPermissionToManageBot(c.Session, c.Params.BotUserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	// Giving a bot to someone else also requires permission to manage the bots of others
+	if c.Params.UserId != c.Session.UserId && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_BOTS)
+		return
+	}
+
+	bot, err := c.App.UpdateBotOwner(c.Params.BotUserId, c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + bot.UserId + ", owner_id=" + bot.OwnerId)
+
+	w.Write([]byte(bot.ToJson()))
+}
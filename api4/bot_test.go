@@ -0,0 +1,236 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestCreateBot(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	bot := &model.Bot{
+		Username:    "bot" + model.NewId(),
+		DisplayName: "Test Bot",
+		Description: "Tests things",
+	}
+
+	_, resp := th.SystemAdminClient.CreateBot(bot)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableBotAccountCreation = true })
+
+	_, resp = th.Client.CreateBot(bot)
+	CheckForbiddenStatus(t, resp)
+
+	bot.OwnerId = th.BasicUser.Id
+	rbot, resp := th.SystemAdminClient.CreateBot(bot)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.SystemAdminUser.Id, rbot.OwnerId, "should be owned by the user who created it")
+	assert.Equal(t, bot.Username, rbot.Username)
+	assert.Equal(t, bot.DisplayName, rbot.DisplayName)
+
+	user, resp := th.SystemAdminClient.GetUser(rbot.UserId, "")
+	CheckNoError(t, resp)
+	assert.True(t, user.IsBot)
+
+	_, resp = th.SystemAdminClient.CreateBot(bot)
+	CheckBadRequestStatus(t, resp)
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	th.AddPermissionToRole(model.PERMISSION_CREATE_BOT.Id, model.SYSTEM_USER_ROLE_ID)
+
+	rbot, resp = th.Client.CreateBot(&model.Bot{Username: "bot" + model.NewId()})
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, rbot.OwnerId)
+}
+
+func TestCreateUserAsBot(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	user := &model.User{Email: th.GenerateTestEmail(), Username: "user" + model.NewId(), Password: "Password1", IsBot: true}
+	ruser, resp := th.Client.CreateUser(user)
+	CheckNoError(t, resp)
+	assert.False(t, ruser.IsBot, "shouldn't let users sign up as bots")
+}
+
+func TestGetBots(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	ownBot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+	otherBot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser2.Id})
+	require.Nil(t, err)
+	orphanedBot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: model.NewId()})
+	require.Nil(t, err)
+
+	_, resp := th.Client.GetBots(0, 100, false, false)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.Client.GetBot(ownBot.UserId)
+	CheckForbiddenStatus(t, resp)
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_BOTS.Id, model.SYSTEM_USER_ROLE_ID)
+
+	bots, resp := th.Client.GetBots(0, 100, false, false)
+	CheckNoError(t, resp)
+	assert.Equal(t, []*model.Bot{ownBot}, bots, "should only get the user's own bots")
+
+	rbot, resp := th.Client.GetBot(ownBot.UserId)
+	CheckNoError(t, resp)
+	assert.Equal(t, ownBot, rbot)
+
+	_, resp = th.Client.GetBot(otherBot.UserId)
+	CheckForbiddenStatus(t, resp)
+
+	bots, resp = th.SystemAdminClient.GetBots(0, 1000, false, false)
+	CheckNoError(t, resp)
+	assert.True(t, len(bots) >= 3)
+
+	bots, resp = th.SystemAdminClient.GetBots(0, 1000, false, true)
+	CheckNoError(t, resp)
+	found := false
+	for _, bot := range bots {
+		assert.NotEqual(t, ownBot.UserId, bot.UserId)
+		if bot.UserId == orphanedBot.UserId {
+			found = true
+		}
+	}
+	assert.True(t, found, "should get bots whose owner doesn't exist")
+
+	_, resp = th.SystemAdminClient.GetBot(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestPatchBot(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	description := "Patched"
+	_, resp := th.Client.PatchBot(bot.UserId, &model.BotPatch{Description: &description})
+	CheckForbiddenStatus(t, resp)
+
+	username := "patched" + model.NewId()
+	rbot, resp := th.SystemAdminClient.PatchBot(bot.UserId, &model.BotPatch{Username: &username, Description: &description})
+	CheckNoError(t, resp)
+	assert.Equal(t, username, rbot.Username)
+	assert.Equal(t, description, rbot.Description)
+	assert.Equal(t, th.BasicUser.Id, rbot.OwnerId)
+
+	_, resp = th.SystemAdminClient.PatchBot(bot.UserId, &model.BotPatch{Username: &th.BasicUser2.Username})
+	CheckBadRequestStatus(t, resp)
+}
+
+func TestDisableEnableBot(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	_, resp := th.Client.DisableBot(bot.UserId)
+	CheckForbiddenStatus(t, resp)
+
+	rbot, resp := th.SystemAdminClient.DisableBot(bot.UserId)
+	CheckNoError(t, resp)
+	assert.NotZero(t, rbot.DeleteAt)
+
+	_, resp = th.SystemAdminClient.GetBot(bot.UserId)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetBotIncludeDeleted(bot.UserId)
+	CheckNoError(t, resp)
+
+	rbot, resp = th.SystemAdminClient.EnableBot(bot.UserId)
+	CheckNoError(t, resp)
+	assert.Zero(t, rbot.DeleteAt)
+
+	_, resp = th.SystemAdminClient.DisableBot(th.BasicUser2.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestAssignBot(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_BOTS.Id, model.SYSTEM_USER_ROLE_ID)
+
+	_, resp := th.Client.AssignBot(bot.UserId, th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	rbot, resp := th.SystemAdminClient.AssignBot(bot.UserId, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, th.BasicUser2.Id, rbot.OwnerId)
+
+	_, resp = th.SystemAdminClient.AssignBot(bot.UserId, model.NewId())
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestBotAccessToken(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableUserAccessTokens = true })
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	th.AddPermissionToRole(model.PERMISSION_CREATE_USER_ACCESS_TOKEN.Id, model.SYSTEM_USER_ROLE_ID)
+
+	_, resp := th.Client.CreateUserAccessToken(bot.UserId, "bot token")
+	CheckForbiddenStatus(t, resp)
+
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_BOTS.Id, model.SYSTEM_USER_ROLE_ID)
+
+	token, resp := th.Client.CreateUserAccessToken(bot.UserId, "bot token")
+	CheckNoError(t, resp)
+
+	client := th.CreateClient()
+	client.AuthToken = token.Token
+	user, resp := client.GetMe("")
+	CheckNoError(t, resp)
+	assert.Equal(t, bot.UserId, user.Id)
+
+	_, resp = th.SystemAdminClient.DisableBot(bot.UserId)
+	CheckNoError(t, resp)
+
+	_, resp = client.GetMe("")
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestBotLogin(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	user, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	require.Nil(t, th.App.UpdatePassword(user, "Password1"))
+
+	_, resp := th.CreateClient().Login(bot.Username, "Password1")
+	CheckUnauthorizedStatus(t, resp)
+}
@@ -11,42 +11,44 @@ import (
 )
 
 func (api *API) InitChannel() {
-	api.BaseRoutes.Channels.Handle("", api.ApiSessionRequired(createChannel)).Methods("POST")
-	api.BaseRoutes.Channels.Handle("/direct", api.ApiSessionRequired(createDirectChannel)).Methods("POST")
-	api.BaseRoutes.Channels.Handle("/group", api.ApiSessionRequired(createGroupChannel)).Methods("POST")
-	api.BaseRoutes.Channels.Handle("/members/{user_id:[A-Za-z0-9]+}/view", api.ApiSessionRequired(viewChannel)).Methods("POST")
+	api.BaseRoutes.Channels.Handle("", api.ApiSessionRequiredWithScope(createChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.Channels.Handle("/direct", api.ApiSessionRequiredWithScope(createDirectChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.Channels.Handle("/group", api.ApiSessionRequiredWithScope(createGroupChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.Channels.Handle("/external/{service:[A-Za-z0-9_-]+}/{external_id:[^/]+}", api.ApiSessionRequired(getChannelByRemoteId)).Methods("GET")
+	api.BaseRoutes.Channels.Handle("/members/{user_id:[A-Za-z0-9]+}/view", api.ApiSessionRequiredWithScope(viewChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
 	api.BaseRoutes.Channels.Handle("/{channel_id:[A-Za-z0-9]+}/scheme", api.ApiSessionRequired(updateChannelScheme)).Methods("PUT")
 
-	api.BaseRoutes.ChannelsForTeam.Handle("", api.ApiSessionRequired(getPublicChannelsForTeam)).Methods("GET")
-	api.BaseRoutes.ChannelsForTeam.Handle("/deleted", api.ApiSessionRequired(getDeletedChannelsForTeam)).Methods("GET")
-	api.BaseRoutes.ChannelsForTeam.Handle("/ids", api.ApiSessionRequired(getPublicChannelsByIdsForTeam)).Methods("POST")
-	api.BaseRoutes.ChannelsForTeam.Handle("/search", api.ApiSessionRequired(searchChannelsForTeam)).Methods("POST")
-	api.BaseRoutes.ChannelsForTeam.Handle("/autocomplete", api.ApiSessionRequired(autocompleteChannelsForTeam)).Methods("GET")
-	api.BaseRoutes.User.Handle("/teams/{team_id:[A-Za-z0-9]+}/channels", api.ApiSessionRequired(getChannelsForTeamForUser)).Methods("GET")
-
-	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequired(getChannel)).Methods("GET")
-	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequired(updateChannel)).Methods("PUT")
-	api.BaseRoutes.Channel.Handle("/patch", api.ApiSessionRequired(patchChannel)).Methods("PUT")
-	api.BaseRoutes.Channel.Handle("/convert", api.ApiSessionRequired(convertChannelToPrivate)).Methods("POST")
-	api.BaseRoutes.Channel.Handle("/restore", api.ApiSessionRequired(restoreChannel)).Methods("POST")
-	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequired(deleteChannel)).Methods("DELETE")
-	api.BaseRoutes.Channel.Handle("/stats", api.ApiSessionRequired(getChannelStats)).Methods("GET")
-	api.BaseRoutes.Channel.Handle("/pinned", api.ApiSessionRequired(getPinnedPosts)).Methods("GET")
-
-	api.BaseRoutes.ChannelForUser.Handle("/unread", api.ApiSessionRequired(getChannelUnread)).Methods("GET")
-
-	api.BaseRoutes.ChannelByName.Handle("", api.ApiSessionRequired(getChannelByName)).Methods("GET")
-	api.BaseRoutes.ChannelByNameForTeamName.Handle("", api.ApiSessionRequired(getChannelByNameForTeamName)).Methods("GET")
-
-	api.BaseRoutes.ChannelMembers.Handle("", api.ApiSessionRequired(getChannelMembers)).Methods("GET")
-	api.BaseRoutes.ChannelMembers.Handle("/ids", api.ApiSessionRequired(getChannelMembersByIds)).Methods("POST")
-	api.BaseRoutes.ChannelMembers.Handle("", api.ApiSessionRequired(addChannelMember)).Methods("POST")
-	api.BaseRoutes.ChannelMembersForUser.Handle("", api.ApiSessionRequired(getChannelMembersForUser)).Methods("GET")
-	api.BaseRoutes.ChannelMember.Handle("", api.ApiSessionRequired(getChannelMember)).Methods("GET")
-	api.BaseRoutes.ChannelMember.Handle("", api.ApiSessionRequired(removeChannelMember)).Methods("DELETE")
+	api.BaseRoutes.ChannelsForTeam.Handle("", api.ApiSessionRequiredWithScope(getPublicChannelsForTeam, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelsForTeam.Handle("/deleted", api.ApiSessionRequiredWithScope(getDeletedChannelsForTeam, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelsForTeam.Handle("/ids", api.ApiSessionRequiredWithScope(getPublicChannelsByIdsForTeam, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("POST")
+	api.BaseRoutes.ChannelsForTeam.Handle("/search", api.ApiSessionRequiredWithScope(searchChannelsForTeam, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("POST")
+	api.BaseRoutes.ChannelsForTeam.Handle("/autocomplete", api.ApiSessionRequiredWithScope(autocompleteChannelsForTeam, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.User.Handle("/teams/{team_id:[A-Za-z0-9]+}/channels", api.ApiSessionRequiredWithScope(getChannelsForTeamForUser, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.User.Handle("/channel_members/ids", api.ApiSessionRequiredWithScope(getChannelMembersForUserByChannelIds, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("POST")
+
+	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequiredWithScope(getChannel, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequiredWithScope(updateChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("PUT")
+	api.BaseRoutes.Channel.Handle("/patch", api.ApiSessionRequiredWithScope(patchChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("PUT")
+	api.BaseRoutes.Channel.Handle("/convert", api.ApiSessionRequiredWithScope(convertChannelToPrivate, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.Channel.Handle("/restore", api.ApiSessionRequiredWithScope(restoreChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.Channel.Handle("", api.ApiSessionRequiredWithScope(deleteChannel, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("DELETE")
+	api.BaseRoutes.Channel.Handle("/stats", api.ApiSessionRequiredWithScope(getChannelStats, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.Channel.Handle("/pinned", api.ApiSessionRequiredWithScope(getPinnedPosts, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+
+	api.BaseRoutes.ChannelForUser.Handle("/unread", api.ApiSessionRequiredWithScope(getChannelUnread, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+
+	api.BaseRoutes.ChannelByName.Handle("", api.ApiSessionRequiredWithScope(getChannelByName, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelByNameForTeamName.Handle("", api.ApiSessionRequiredWithScope(getChannelByNameForTeamName, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+
+	api.BaseRoutes.ChannelMembers.Handle("", api.ApiSessionRequiredWithScope(getChannelMembers, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelMembers.Handle("/ids", api.ApiSessionRequiredWithScope(getChannelMembersByIds, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("POST")
+	api.BaseRoutes.ChannelMembers.Handle("", api.ApiSessionRequiredWithScope(addChannelMember, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("POST")
+	api.BaseRoutes.ChannelMembersForUser.Handle("", api.ApiSessionRequiredWithScope(getChannelMembersForUser, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelMember.Handle("", api.ApiSessionRequiredWithScope(getChannelMember, model.OAUTH_SCOPE_READ_CHANNELS)).Methods("GET")
+	api.BaseRoutes.ChannelMember.Handle("", api.ApiSessionRequiredWithScope(removeChannelMember, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("DELETE")
 	api.BaseRoutes.ChannelMember.Handle("/roles", api.ApiSessionRequired(updateChannelMemberRoles)).Methods("PUT")
 	api.BaseRoutes.ChannelMember.Handle("/schemeRoles", api.ApiSessionRequired(updateChannelMemberSchemeRoles)).Methods("PUT")
-	api.BaseRoutes.ChannelMember.Handle("/notify_props", api.ApiSessionRequired(updateChannelMemberNotifyProps)).Methods("PUT")
+	api.BaseRoutes.ChannelMember.Handle("/notify_props", api.ApiSessionRequiredWithScope(updateChannelMemberNotifyProps, model.OAUTH_SCOPE_WRITE_CHANNELS)).Methods("PUT")
 }
 
 func createChannel(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -66,6 +68,11 @@ func createChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if channel.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
 	sc, err := c.App.CreateChannelWithUser(channel, c.Session.UserId)
 	if err != nil {
 		c.Err = err
@@ -77,6 +84,39 @@ func createChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(sc.ToJson()))
 }
 
+func getChannelByRemoteId(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireService().RequireExternalId()
+	if c.Err != nil {
+		return
+	}
+
+	channel, err := c.App.GetChannelByRemoteId(c.Params.Service, c.Params.ExternalId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if channel.Type == model.CHANNEL_OPEN {
+		if !c.App.SessionHasPermissionToTeam(c.Session, channel.TeamId, model.PERMISSION_READ_PUBLIC_CHANNEL) {
+			c.SetPermissionError(model.PERMISSION_READ_PUBLIC_CHANNEL)
+			return
+		}
+	} else {
+		if !c.App.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_READ_CHANNEL) {
+			c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+			return
+		}
+	}
+
+	err = c.App.FillInChannelProps(channel)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(channel.ToJson()))
+}
+
 func updateChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireChannelId()
 	if c.Err != nil {
@@ -260,6 +300,18 @@ func patchChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if patch.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	// Any member of a direct or group message can turn on its read receipts, but only channel admins can for others
+	if patch.ReadReceipts != nil && (oldChannel.Type == model.CHANNEL_OPEN || oldChannel.Type == model.CHANNEL_PRIVATE) &&
+		!c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_MANAGE_CHANNEL_ROLES) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CHANNEL_ROLES)
+		return
+	}
+
 	rchannel, err := c.App.PatchChannel(oldChannel, patch, c.Session.UserId)
 	if err != nil {
 		c.Err = err
@@ -505,12 +557,17 @@ func getPublicChannelsForTeam(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if c.HandleEtag(channels.Etag(), "Get Public Channels", w, r) {
+		return
+	}
+
 	err = c.App.FillInChannelsProps(channels)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	w.Header().Set(model.HEADER_ETAG_SERVER, channels.Etag())
 	w.Write([]byte(channels.ToJson()))
 }
 
@@ -790,6 +847,11 @@ func getChannelMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.HandleEtag(members.Etag(), "Get Channel Members", w, r) {
+		return
+	}
+
+	w.Header().Set(model.HEADER_ETAG_SERVER, members.Etag())
 	w.Write([]byte(members.ToJson()))
 }
 
@@ -861,6 +923,47 @@ func getChannelMembersForUser(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if c.HandleEtag(members.Etag(), "Get Channel Members For User", w, r) {
+		return
+	}
+
+	w.Header().Set(model.HEADER_ETAG_SERVER, members.Etag())
+	w.Write([]byte(members.ToJson()))
+}
+
+// getChannelMembersForUserByChannelIds returns the user's memberships in the given channels, leaving out the
+// channels that they aren't a member of, so that clients can restore the state of many channels in one request.
+func getChannelMembersForUserByChannelIds(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	channelIds := model.ArrayFromJson(r.Body)
+	if len(channelIds) == 0 || len(channelIds) > model.CHANNEL_MEMBERS_BY_CHANNEL_IDS_MAX {
+		c.SetInvalidParam("channel_ids")
+		return
+	}
+
+	for _, channelId := range channelIds {
+		if !model.IsValidId(channelId) {
+			c.SetInvalidParam("channel_ids")
+			return
+		}
+	}
+
+	// Users can only see the memberships of others that they could see one channel at a time as an administrator
+	if c.Session.UserId != c.Params.UserId && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	members, err := c.App.GetChannelMembersForUserByChannelIds(c.Params.UserId, channelIds)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
 	w.Write([]byte(members.ToJson()))
 }
 
@@ -0,0 +1,221 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitChannelBridge() {
+	api.BaseRoutes.ChannelBridges.Handle("", api.ApiSessionRequired(getChannelBridges)).Methods("GET")
+	api.BaseRoutes.ChannelBridges.Handle("", api.ApiSessionRequired(createChannelBridge)).Methods("POST")
+	api.BaseRoutes.ChannelBridges.Handle("/health", api.ApiSessionRequired(getChannelBridgesHealth)).Methods("GET")
+	api.BaseRoutes.ChannelBridges.Handle("/{bridge_id:[A-Za-z0-9]+}", api.ApiSessionRequired(getChannelBridge)).Methods("GET")
+	api.BaseRoutes.ChannelBridges.Handle("/{bridge_id:[A-Za-z0-9]+}", api.ApiSessionRequired(updateChannelBridge)).Methods("PUT")
+	api.BaseRoutes.ChannelBridges.Handle("/{bridge_id:[A-Za-z0-9]+}", api.ApiSessionRequired(deleteChannelBridge)).Methods("DELETE")
+	api.BaseRoutes.ChannelBridges.Handle("/{bridge_id:[A-Za-z0-9]+}/health", api.ApiSessionRequired(getChannelBridgeHealth)).Methods("GET")
+	api.BaseRoutes.ChannelBridges.Handle("/{bridge_id:[A-Za-z0-9]+}/events", api.ApiHandler(receiveChannelBridgeEvent)).Methods("POST")
+}
+
+func createChannelBridge(c *Context, w http.ResponseWriter, r *http.Request) {
+	bridge := model.ChannelBridgeFromJson(r.Body)
+	if bridge == nil {
+		c.SetInvalidParam("bridge")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	bridge.Id = ""
+	bridge.Token = ""
+	bridge.CreatorId = c.Session.UserId
+
+	rbridge, err := c.App.CreateChannelBridge(bridge)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bridge_id=" + rbridge.Id + " channel_id=" + rbridge.ChannelId)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rbridge.ToJson()))
+}
+
+func getChannelBridges(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	var bridges []*model.ChannelBridge
+	var err *model.AppError
+	if channelId := r.URL.Query().Get("channel_id"); len(channelId) > 0 {
+		bridges, err = c.App.GetChannelBridgesForChannel(channelId)
+	} else {
+		bridges, err = c.App.GetAllChannelBridges()
+	}
+
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	for _, bridge := range bridges {
+		bridge.Sanitize()
+	}
+
+	w.Write([]byte(model.ChannelBridgeListToJson(bridges)))
+}
+
+func getChannelBridge(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBridgeId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	bridge, err := c.App.GetChannelBridge(c.Params.BridgeId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(bridge.ToJson()))
+}
+
+func updateChannelBridge(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBridgeId()
+	if c.Err != nil {
+		return
+	}
+
+	updatedBridge := model.ChannelBridgeFromJson(r.Body)
+	if updatedBridge == nil {
+		c.SetInvalidParam("bridge")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	oldBridge, err := c.App.GetChannelBridge(c.Params.BridgeId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	rbridge, err := c.App.UpdateChannelBridge(oldBridge, updatedBridge)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bridge_id=" + rbridge.Id)
+	w.Write([]byte(rbridge.ToJson()))
+}
+
+func deleteChannelBridge(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBridgeId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if _, err := c.App.GetChannelBridge(c.Params.BridgeId); err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := c.App.DeleteChannelBridge(c.Params.BridgeId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bridge_id=" + c.Params.BridgeId)
+	ReturnStatusOK(w)
+}
+
+func getChannelBridgesHealth(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	bridges, err := c.App.GetAllChannelBridges()
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.ChannelBridgeHealthListToJson(c.App.GetChannelBridgesHealth(bridges))))
+}
+
+func getChannelBridgeHealth(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBridgeId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	bridge, err := c.App.GetChannelBridge(c.Params.BridgeId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(c.App.GetChannelBridgesHealth([]*model.ChannelBridge{bridge})[0].ToJson()))
+}
+
+func receiveChannelBridgeEvent(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireBridgeId()
+	if c.Err != nil {
+		return
+	}
+
+	if !*c.App.Config().ServiceSettings.EnableChannelBridges {
+		c.Err = model.NewAppError("receiveChannelBridgeEvent", "api.channel_bridge.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	event := model.ChannelBridgeEventFromJson(r.Body)
+	if event == nil {
+		c.SetInvalidParam("event")
+		return
+	}
+
+	// The adapter authenticates with the bridge's token rather than a session
+	bridge, err := c.App.GetChannelBridge(c.Params.BridgeId)
+	if err != nil || subtle.ConstantTimeCompare([]byte(r.Header.Get(model.HEADER_BRIDGE_TOKEN)), []byte(bridge.Token)) != 1 {
+		c.Err = model.NewAppError("receiveChannelBridgeEvent", "api.channel_bridge.invalid_token.app_error", nil, "bridge_id="+c.Params.BridgeId, http.StatusUnauthorized)
+		return
+	}
+
+	post, err := c.App.HandleChannelBridgeEvent(bridge, event)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(post.ToJson()))
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestChannelBridgeManagement(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	bridge := &model.ChannelBridge{
+		ChannelId:       th.BasicChannel.Id,
+		Service:         "slack",
+		RemoteChannelId: "C" + model.NewId(),
+		OutgoingURL:     "https://bridge.example.com/events",
+	}
+
+	_, resp := Client.CreateChannelBridge(bridge)
+	CheckForbiddenStatus(t, resp)
+
+	rbridge, resp := th.SystemAdminClient.CreateChannelBridge(bridge)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.SystemAdminUser.Id, rbridge.CreatorId)
+	assert.NotEmpty(t, rbridge.Token)
+
+	_, resp = th.SystemAdminClient.CreateChannelBridge(bridge)
+	CheckBadRequestStatus(t, resp)
+
+	bridges, resp := th.SystemAdminClient.GetChannelBridges(th.BasicChannel.Id)
+	CheckNoError(t, resp)
+	require.Len(t, bridges, 1)
+	assert.Equal(t, rbridge.Id, bridges[0].Id)
+	assert.Empty(t, bridges[0].Token, "should not return tokens in lists")
+
+	_, resp = Client.GetChannelBridge(rbridge.Id)
+	CheckForbiddenStatus(t, resp)
+
+	received, resp := th.SystemAdminClient.GetChannelBridge(rbridge.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, rbridge.Token, received.Token)
+
+	received.ConflictPolicy = model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS
+	received.ChannelId = th.BasicChannel2.Id
+	updated, resp := th.SystemAdminClient.UpdateChannelBridge(received)
+	CheckNoError(t, resp)
+	assert.Equal(t, model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS, updated.ConflictPolicy)
+	assert.Equal(t, th.BasicChannel.Id, updated.ChannelId, "should not move the bridge to another channel")
+
+	received.ConflictPolicy = "junk"
+	_, resp = th.SystemAdminClient.UpdateChannelBridge(received)
+	CheckBadRequestStatus(t, resp)
+
+	health, resp := th.SystemAdminClient.GetChannelBridgeHealth(rbridge.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, rbridge.Id, health.BridgeId)
+
+	_, resp = Client.GetChannelBridgesHealth()
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetChannelBridgesHealth()
+	CheckNoError(t, resp)
+
+	_, resp = Client.DeleteChannelBridge(rbridge.Id)
+	CheckForbiddenStatus(t, resp)
+
+	ok, resp := th.SystemAdminClient.DeleteChannelBridge(rbridge.Id)
+	CheckNoError(t, resp)
+	assert.True(t, ok)
+
+	_, resp = th.SystemAdminClient.GetChannelBridge(rbridge.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestChannelBridgeEvents(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	enableChannelBridges := *th.App.Config().ServiceSettings.EnableChannelBridges
+	allowedInternalConnections := *th.App.Config().ServiceSettings.AllowedUntrustedInternalConnections
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableChannelBridges = &enableChannelBridges })
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.ServiceSettings.AllowedUntrustedInternalConnections = &allowedInternalConnections
+		})
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowedUntrustedInternalConnections = "127.0.0.0/8" })
+
+	outgoing := make(chan *model.ChannelBridgeEvent, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := model.ChannelBridgeEventFromJson(r.Body)
+		outgoing <- event
+
+		if event.Type == model.CHANNEL_BRIDGE_EVENT_POSTED {
+			w.Write([]byte(`{"remote_post_id": "remote-` + event.PostId + `"}`))
+		}
+	}))
+	defer ts.Close()
+
+	bridge, resp := th.SystemAdminClient.CreateChannelBridge(&model.ChannelBridge{
+		ChannelId:       th.BasicChannel.Id,
+		Service:         "slack",
+		RemoteChannelId: "C" + model.NewId(),
+		OutgoingURL:     ts.URL,
+	})
+	CheckNoError(t, resp)
+
+	event := &model.ChannelBridgeEvent{
+		Type:         model.CHANNEL_BRIDGE_EVENT_POSTED,
+		Timestamp:    model.GetMillis(),
+		RemotePostId: model.NewId(),
+		Username:     "slackuser",
+		Message:      "hello from slack",
+	}
+
+	_, resp = Client.SendChannelBridgeEvent(bridge.Id, bridge.Token, event)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableChannelBridges = true })
+
+	_, resp = Client.SendChannelBridgeEvent(bridge.Id, model.NewId(), event)
+	CheckUnauthorizedStatus(t, resp)
+
+	post, resp := Client.SendChannelBridgeEvent(bridge.Id, bridge.Token, event)
+	CheckNoError(t, resp)
+	assert.Equal(t, th.BasicChannel.Id, post.ChannelId)
+	assert.Equal(t, "slackuser", post.Props["override_username"])
+	require.NotNil(t, post.RemoteId)
+	assert.Equal(t, model.NewRemoteId("slack", event.RemotePostId), *post.RemoteId)
+
+	// sending the same post again shouldn't duplicate it
+	repeated, resp := Client.SendChannelBridgeEvent(bridge.Id, bridge.Token, event)
+	CheckNoError(t, resp)
+	assert.Equal(t, post.Id, repeated.Id)
+
+	event.Type = model.CHANNEL_BRIDGE_EVENT_POST_EDITED
+	event.Timestamp = model.GetMillis()
+	event.Message = "edited in slack"
+	edited, resp := Client.SendChannelBridgeEvent(bridge.Id, bridge.Token, event)
+	CheckNoError(t, resp)
+	assert.Equal(t, "edited in slack", edited.Message)
+
+	// edits made before the post was last edited here are stale
+	event.Timestamp = edited.EditAt - 1
+	event.Message = "stale edit"
+	stale, resp := Client.SendChannelBridgeEvent(bridge.Id, bridge.Token, event)
+	CheckNoError(t, resp)
+	assert.Equal(t, "edited in slack", stale.Message)
+
+	// posts made here are sent to the bridge, but the ones that came from it aren't sent back
+	localPost := th.CreatePost()
+
+	select {
+	case sent := <-outgoing:
+		assert.Equal(t, model.CHANNEL_BRIDGE_EVENT_POSTED, sent.Type)
+		assert.Equal(t, localPost.Id, sent.PostId)
+		assert.Equal(t, bridge.RemoteChannelId, sent.RemoteChannelId)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "should have sent the local post to the bridge")
+	}
+
+	health, resp := th.SystemAdminClient.GetChannelBridgeHealth(bridge.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, int64(4), health.IncomingEvents)
+	assert.Equal(t, int64(1), health.Conflicts)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitChannelNotifyDefaults() {
+	api.BaseRoutes.Channel.Handle("/notify_defaults", api.ApiSessionRequired(getChannelNotifyDefaults)).Methods("GET")
+	api.BaseRoutes.Channel.Handle("/notify_defaults", api.ApiSessionRequired(updateChannelNotifyDefaults)).Methods("PUT")
+	api.BaseRoutes.Channel.Handle("/notify_defaults", api.ApiSessionRequired(deleteChannelNotifyDefaults)).Methods("DELETE")
+}
+
+// getManagedChannel returns the channel from the URL if the session belongs to one of its admins. Team and system
+// admins are treated as admins of every channel.
+func getManagedChannel(c *Context) *model.Channel {
+	if !c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_MANAGE_CHANNEL_ROLES) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_CHANNEL_ROLES)
+		return nil
+	}
+
+	channel, err := c.App.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	return channel
+}
+
+func getChannelNotifyDefaults(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	defaults, err := c.App.GetChannelNotifyDefaults(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(defaults.ToJson()))
+}
+
+func updateChannelNotifyDefaults(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	defaults := model.ChannelNotifyDefaultsFromJson(r.Body)
+	if defaults == nil {
+		c.SetInvalidParam("notify_defaults")
+		return
+	}
+
+	channel := getManagedChannel(c)
+	if c.Err != nil {
+		return
+	}
+
+	rdefaults, err := c.App.UpdateChannelNotifyDefaults(channel, defaults.NotifyProps, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("channel_id=" + channel.Id)
+	w.Write([]byte(rdefaults.ToJson()))
+}
+
+func deleteChannelNotifyDefaults(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	if getManagedChannel(c); c.Err != nil {
+		return
+	}
+
+	if err := c.App.DeleteChannelNotifyDefaults(c.Params.ChannelId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("channel_id=" + c.Params.ChannelId)
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestChannelNotifyDefaults(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+
+	defaults, resp := Client.GetChannelNotifyDefaults(channel.Id)
+	CheckNoError(t, resp)
+
+	if len(defaults.NotifyProps) != 0 {
+		t.Fatal("should have no defaults")
+	}
+
+	_, resp = Client.UpdateChannelNotifyDefaults(channel.Id, model.StringMap{model.DESKTOP_NOTIFY_PROP: "sometimes"})
+	CheckBadRequestStatus(t, resp)
+
+	defaults, resp = Client.UpdateChannelNotifyDefaults(channel.Id, model.StringMap{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_MENTION})
+	CheckNoError(t, resp)
+
+	if defaults.NotifyProps[model.DESKTOP_NOTIFY_PROP] != model.CHANNEL_NOTIFY_MENTION || defaults.UpdatedBy != th.BasicUser.Id {
+		t.Fatal("should have saved the defaults")
+	}
+
+	member, resp := Client.AddChannelMember(channel.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if member.NotifyProps[model.DESKTOP_NOTIFY_PROP] != model.CHANNEL_NOTIFY_MENTION {
+		t.Fatal("should have applied the defaults to the new member")
+	}
+
+	private := th.CreatePrivateChannel()
+
+	th.LoginBasic2()
+
+	defaults, resp = Client.GetChannelNotifyDefaults(channel.Id)
+	CheckNoError(t, resp)
+
+	if defaults.NotifyProps[model.DESKTOP_NOTIFY_PROP] != model.CHANNEL_NOTIFY_MENTION {
+		t.Fatal("should have returned the defaults")
+	}
+
+	_, resp = Client.UpdateChannelNotifyDefaults(channel.Id, model.StringMap{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_ALL})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.DeleteChannelNotifyDefaults(channel.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.GetChannelNotifyDefaults(private.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateChannelNotifyDefaults(channel.Id, model.StringMap{model.PUSH_NOTIFY_PROP: model.CHANNEL_NOTIFY_NONE})
+	CheckNoError(t, resp)
+
+	ok, resp := th.SystemAdminClient.DeleteChannelNotifyDefaults(channel.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have deleted the defaults")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitChannelOrganization() {
+	api.BaseRoutes.User.Handle("/channel_organization", api.ApiSessionRequired(getChannelOrganization)).Methods("GET")
+	api.BaseRoutes.User.Handle("/channel_organization", api.ApiSessionRequired(updateChannelOrganization)).Methods("PUT")
+}
+
+func getChannelOrganization(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	organization, err := c.App.GetChannelOrganization(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(organization.ToJson()))
+}
+
+func updateChannelOrganization(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	organization := model.ChannelOrganizationFromJson(r.Body)
+	if organization == nil {
+		c.SetInvalidParam("channel_organization")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	updatedOrganization, err := c.App.UpdateChannelOrganization(c.Params.UserId, organization)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(updatedOrganization.ToJson()))
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestChannelOrganization(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	organization, resp := Client.GetChannelOrganization(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if *organization != *model.DefaultChannelOrganization() {
+		t.Fatal("should have returned the default organization")
+	}
+
+	organization.Sorting = model.CHANNEL_ORGANIZATION_SORTING_RECENT
+	organization.UnreadsAtTop = false
+	_, resp = Client.UpdateChannelOrganization(th.BasicUser.Id, organization)
+	CheckNoError(t, resp)
+
+	received, resp := Client.GetChannelOrganization(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if *received != *organization {
+		t.Fatal("should have saved the organization")
+	}
+
+	organization.Grouping = "junk"
+	_, resp = Client.UpdateChannelOrganization(th.BasicUser.Id, organization)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelOrganization(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableChannelOrganization = false })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableChannelOrganization = true })
+
+	_, resp = Client.GetChannelOrganization(th.BasicUser.Id)
+	CheckNotImplementedStatus(t, resp)
+}
+
+func TestChannelOrganizationFromLegacyPreference(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.UpdatePreferences(th.BasicUser.Id, &model.Preferences{
+		{
+			UserId:   th.BasicUser.Id,
+			Category: model.PREFERENCE_CATEGORY_SIDEBAR_SETTINGS,
+			Name:     model.PREFERENCE_NAME_LEGACY_SIDEBAR_SETTINGS,
+			Value:    `{"grouping":"none","sorting":"recent","unreads_at_top":"true","favorite_at_top":"false"}`,
+		},
+	})
+	CheckNoError(t, resp)
+
+	organization, resp := Client.GetChannelOrganization(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if organization.Grouping != model.CHANNEL_ORGANIZATION_GROUPING_NONE || organization.Sorting != model.CHANNEL_ORGANIZATION_SORTING_RECENT || organization.FavoritesAtTop {
+		t.Fatal("should have read the legacy sidebar settings")
+	}
+
+	preference, resp := Client.GetPreferenceByCategoryAndName(th.BasicUser.Id, model.PREFERENCE_CATEGORY_SIDEBAR_SETTINGS, model.PREFERENCE_NAME_CHANNEL_ORGANIZATION)
+	CheckNoError(t, resp)
+
+	if preference.Value != organization.ToJson() {
+		t.Fatal("should have saved the typed organization")
+	}
+}
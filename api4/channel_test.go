@@ -486,6 +486,35 @@ func TestDeleteGroupChannel(t *testing.T) {
 	require.False(t, deleted, "should not have been able to delete group channel.")
 }
 
+func TestGetChannelByRemoteId(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	externalId := model.NewId()
+	remoteId := model.NewRemoteId("slack", externalId)
+
+	channel := &model.Channel{DisplayName: "Remote", Name: GenerateTestChannelName(), Type: model.CHANNEL_OPEN, TeamId: th.BasicTeam.Id, RemoteId: &remoteId}
+	_, resp := Client.CreateChannel(channel)
+	CheckForbiddenStatus(t, resp)
+
+	rchannel, resp := th.SystemAdminClient.CreateChannel(channel)
+	CheckNoError(t, resp)
+
+	received, resp := Client.GetChannelByRemoteId("slack", externalId, "")
+	CheckNoError(t, resp)
+	assert.Equal(t, rchannel.Id, received.Id)
+
+	_, resp = Client.PatchChannel(th.BasicChannel.Id, &model.ChannelPatch{RemoteId: &remoteId})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.GetChannelByRemoteId("slack", model.NewId(), "")
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.GetChannelByRemoteId("abcdefghijklmnopqrstuvwxyz0123456789", externalId, "")
+	CheckBadRequestStatus(t, resp)
+}
+
 func TestGetChannel(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -625,6 +654,9 @@ func TestGetPublicChannelsForTeam(t *testing.T) {
 		}
 	}
 
+	channels, resp = Client.GetPublicChannelsForTeam(team.Id, 0, 100, resp.Etag)
+	CheckEtag(t, channels, resp)
+
 	channels, resp = Client.GetPublicChannelsForTeam(team.Id, 0, 1, "")
 	CheckNoError(t, resp)
 	if len(channels) != 1 {
@@ -1223,6 +1255,19 @@ func TestGetChannelMembers(t *testing.T) {
 		t.Fatal("should be 0 users")
 	}
 
+	_, resp = Client.GetChannelMembers(th.BasicChannel.Id, 0, 60, "")
+	CheckNoError(t, resp)
+
+	members, resp = Client.GetChannelMembers(th.BasicChannel.Id, 0, 60, resp.Etag)
+	CheckEtag(t, members, resp)
+
+	th.App.UpdateChannelMemberNotifyProps(map[string]string{model.MARK_UNREAD_NOTIFY_PROP: model.CHANNEL_MARK_UNREAD_MENTION}, th.BasicChannel.Id, th.BasicUser.Id)
+	members, resp = Client.GetChannelMembers(th.BasicChannel.Id, 0, 60, resp.Etag)
+	CheckNoError(t, resp)
+	if members == nil || len(*members) != 3 {
+		t.Fatal("should get the members again after one was updated")
+	}
+
 	_, resp = Client.GetChannelMembers("", 0, 60, "")
 	CheckBadRequestStatus(t, resp)
 
@@ -1292,6 +1337,46 @@ func TestGetChannelMembersByIds(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestGetChannelMembersForUserByChannelIds(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	otherChannel := th.CreateChannelWithClient(th.SystemAdminClient, model.CHANNEL_OPEN)
+
+	members, resp := Client.GetChannelMembersForUserByChannelIds(th.BasicUser.Id, []string{th.BasicChannel.Id, th.BasicPrivateChannel.Id, otherChannel.Id, model.NewId()})
+	CheckNoError(t, resp)
+	require.Len(t, *members, 2, "should leave out channels that the user isn't a member of")
+	for _, member := range *members {
+		assert.Equal(t, th.BasicUser.Id, member.UserId)
+		assert.Contains(t, []string{th.BasicChannel.Id, th.BasicPrivateChannel.Id}, member.ChannelId)
+	}
+
+	_, resp = Client.GetChannelMembersForUserByChannelIds(th.BasicUser.Id, []string{})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelMembersForUserByChannelIds(th.BasicUser.Id, []string{"junk"})
+	CheckBadRequestStatus(t, resp)
+
+	tooMany := make([]string, model.CHANNEL_MEMBERS_BY_CHANNEL_IDS_MAX+1)
+	for i := range tooMany {
+		tooMany[i] = model.NewId()
+	}
+	_, resp = Client.GetChannelMembersForUserByChannelIds(th.BasicUser.Id, tooMany)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelMembersForUserByChannelIds(th.BasicUser2.Id, []string{th.BasicChannel.Id})
+	CheckForbiddenStatus(t, resp)
+
+	members, resp = th.SystemAdminClient.GetChannelMembersForUserByChannelIds(th.BasicUser2.Id, []string{th.BasicChannel.Id})
+	CheckNoError(t, resp)
+	require.Len(t, *members, 1)
+
+	Client.Logout()
+	_, resp = Client.GetChannelMembersForUserByChannelIds(th.BasicUser.Id, []string{th.BasicChannel.Id})
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetChannelMember(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -1351,6 +1436,9 @@ func TestGetChannelMembersForUser(t *testing.T) {
 		t.Fatal("should have 6 members on team")
 	}
 
+	members, resp = Client.GetChannelMembersForUser(th.BasicUser.Id, th.BasicTeam.Id, resp.Etag)
+	CheckEtag(t, members, resp)
+
 	_, resp = Client.GetChannelMembersForUser("", th.BasicTeam.Id, "")
 	CheckNotFoundStatus(t, resp)
 
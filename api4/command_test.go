@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-server/model"
@@ -149,6 +150,81 @@ func TestUpdateCommand(t *testing.T) {
 	CheckUnauthorizedStatus(t, resp)
 }
 
+func TestPatchCommand(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	enableCommands := *th.App.Config().ServiceSettings.EnableCommands
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableCommands = &enableCommands })
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableCommands = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_SLASH_COMMANDS.Id, model.TEAM_USER_ROLE_ID)
+	th.RemovePermissionFromRole(model.PERMISSION_MANAGE_OTHERS_SLASH_COMMANDS.Id, model.TEAM_USER_ROLE_ID)
+
+	cmd, err := th.App.CreateCommand(&model.Command{
+		CreatorId:   th.BasicUser.Id,
+		TeamId:      th.BasicTeam.Id,
+		URL:         "http://nowhere.com",
+		Method:      model.COMMAND_METHOD_POST,
+		Trigger:     "deploy",
+		DisplayName: "Deploy",
+	})
+	require.Nil(t, err)
+
+	description := "Deploys a service"
+	iconURL := "https://example.com/deploy.png"
+	hint := "[service] [version]"
+	patch := &model.CommandPatch{
+		Description:      &description,
+		IconURL:          &iconURL,
+		AutoCompleteHint: &hint,
+		AutocompleteData: &model.AutocompleteData{Trigger: "deploy", HelpText: description},
+	}
+
+	rcmd, resp := Client.PatchCommand(cmd.Id, patch)
+	CheckNoError(t, resp)
+	assert.Equal(t, description, rcmd.Description)
+	assert.Equal(t, iconURL, rcmd.IconURL)
+	assert.Equal(t, hint, rcmd.AutoCompleteHint)
+	assert.Equal(t, description, rcmd.AutocompleteData.HelpText)
+	assert.Equal(t, "Deploy", rcmd.DisplayName, "should keep fields that weren't patched")
+	assert.Equal(t, cmd.Token, rcmd.Token)
+	assert.Equal(t, cmd.URL, rcmd.URL)
+
+	patch.AutocompleteData = &model.AutocompleteData{Trigger: "other"}
+	_, resp = Client.PatchCommand(cmd.Id, patch)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.PatchCommand(GenerateTestId(), &model.CommandPatch{Description: &description})
+	CheckNotFoundStatus(t, resp)
+
+	otherCmd, err := th.App.CreateCommand(&model.Command{
+		CreatorId: th.SystemAdminUser.Id,
+		TeamId:    th.BasicTeam.Id,
+		URL:       "http://nowhere.com",
+		Method:    model.COMMAND_METHOD_POST,
+		Trigger:   "other",
+	})
+	require.Nil(t, err)
+
+	_, resp = Client.PatchCommand(otherCmd.Id, &model.CommandPatch{Description: &description})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.PatchCommand(otherCmd.Id, &model.CommandPatch{Description: &description})
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.PatchCommand(cmd.Id, &model.CommandPatch{Description: &description})
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestDeleteCommand(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -812,3 +888,90 @@ func TestExecuteCommandInTeamUserIsNotOn(t *testing.T) {
 	_, resp = client.ExecuteCommand(dmChannel.Id, "/postcommand")
 	CheckForbiddenStatus(t, resp)
 }
+
+func TestListCommandAutocompleteSuggestions(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	enableCommands := *th.App.Config().ServiceSettings.EnableCommands
+	allowedInternalConnections := *th.App.Config().ServiceSettings.AllowedUntrustedInternalConnections
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableCommands = &enableCommands })
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.ServiceSettings.AllowedUntrustedInternalConnections = &allowedInternalConnections
+		})
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableCommands = true })
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowedUntrustedInternalConnections = "127.0.0.0/8" })
+
+	token := model.NewId()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Token "+token, r.Header.Get("Authorization"))
+		require.Equal(t, "/deploy app ", r.URL.Query().Get("user_input"))
+		require.Equal(t, th.BasicChannel.Id, r.URL.Query().Get("channel_id"))
+
+		w.Write([]byte(model.AutocompleteListItemsToJson([]*model.AutocompleteListItem{{Item: "production"}, {Item: "staging"}})))
+	}))
+	defer ts.Close()
+
+	cmd := &model.Command{
+		CreatorId:    th.BasicUser.Id,
+		TeamId:       th.BasicTeam.Id,
+		URL:          ts.URL,
+		Method:       model.COMMAND_METHOD_POST,
+		Trigger:      "deploy",
+		Token:        token,
+		AutoComplete: true,
+		AutocompleteData: &model.AutocompleteData{
+			Trigger: "deploy",
+			SubCommands: []*model.AutocompleteData{
+				{
+					Trigger: "app",
+					Arguments: []*model.AutocompleteArg{
+						{Hint: "[environment]", Type: model.AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST, FetchURL: ts.URL + "/environments"},
+					},
+				},
+				{Trigger: "status"},
+			},
+		},
+	}
+
+	_, err := th.App.CreateCommand(cmd)
+	require.Nil(t, err)
+
+	commands, resp := Client.ListAutocompleteCommands(th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	for _, command := range commands {
+		if command.Trigger == "deploy" {
+			require.NotNil(t, command.AutocompleteData)
+			require.Equal(t, "", command.AutocompleteData.SubCommands[0].Arguments[0].FetchURL)
+		}
+	}
+
+	suggestions, resp := Client.ListCommandAutocompleteSuggestions("/dep", th.BasicTeam.Id, th.BasicChannel.Id)
+	CheckNoError(t, resp)
+	require.Len(t, suggestions, 1)
+	require.Equal(t, "/deploy", suggestions[0].Complete)
+
+	suggestions, resp = Client.ListCommandAutocompleteSuggestions("/deploy s", th.BasicTeam.Id, th.BasicChannel.Id)
+	CheckNoError(t, resp)
+	require.Len(t, suggestions, 1)
+	require.Equal(t, "/deploy status", suggestions[0].Complete)
+
+	suggestions, resp = Client.ListCommandAutocompleteSuggestions("/deploy app ", th.BasicTeam.Id, th.BasicChannel.Id)
+	CheckNoError(t, resp)
+	require.Len(t, suggestions, 2)
+	require.Equal(t, "/deploy app production", suggestions[0].Complete)
+
+	_, resp = Client.ListCommandAutocompleteSuggestions("deploy", th.BasicTeam.Id, th.BasicChannel.Id)
+	CheckBadRequestStatus(t, resp)
+
+	privateChannel := th.CreateChannelWithClient(th.SystemAdminClient, model.CHANNEL_PRIVATE)
+	_, resp = Client.ListCommandAutocompleteSuggestions("/deploy ", th.BasicTeam.Id, privateChannel.Id)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.ListCommandAutocompleteSuggestions("/deploy ", th.BasicTeam.Id, th.BasicChannel.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
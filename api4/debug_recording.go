@@ -0,0 +1,68 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitDebugRecording() {
+	api.BaseRoutes.ApiRoot.Handle("/debug/recording", api.ApiSessionRequired(startDebugRecording)).Methods("POST")
+	api.BaseRoutes.ApiRoot.Handle("/debug/recording", api.ApiSessionRequired(getDebugRecording)).Methods("GET")
+	api.BaseRoutes.ApiRoot.Handle("/debug/recording", api.ApiSessionRequired(stopDebugRecording)).Methods("DELETE")
+}
+
+func startDebugRecording(c *Context, w http.ResponseWriter, r *http.Request) {
+	filter := model.DebugRecordingFilterFromJson(r.Body)
+	if filter == nil {
+		c.SetInvalidParam("filter")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	recording, err := c.App.StartDebugRecording(filter, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("path_prefixes=" + strings.Join(filter.PathPrefixes, ",") + " integration_id=" + filter.IntegrationId)
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(recording.ToJson()))
+}
+
+func getDebugRecording(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	recording, err := c.App.GetDebugRecording()
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(recording.ToJson()))
+}
+
+func stopDebugRecording(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	c.App.StopDebugRecording()
+	c.LogAudit("")
+
+	ReturnStatusOK(w)
+}
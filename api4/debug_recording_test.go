@@ -0,0 +1,62 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestDebugRecording(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	filter := &model.DebugRecordingFilter{PathPrefixes: []string{model.API_URL_SUFFIX + "/users/login"}}
+
+	_, resp := Client.StartDebugRecording(filter)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetDebugRecording()
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.StartDebugRecording(&model.DebugRecordingFilter{})
+	CheckBadRequestStatus(t, resp)
+
+	recording, resp := th.SystemAdminClient.StartDebugRecording(filter)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.SystemAdminUser.Id, recording.StartedBy)
+
+	Client.Logout()
+	_, resp = Client.Login(th.BasicUser.Email, th.BasicUser.Password)
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetDebugRecording()
+	CheckForbiddenStatus(t, resp)
+
+	recording, resp = th.SystemAdminClient.GetDebugRecording()
+	CheckNoError(t, resp)
+	require.Len(t, recording.Requests, 1)
+
+	request := recording.Requests[0]
+	assert.Equal(t, "POST", request.Method)
+	assert.Equal(t, 200, request.StatusCode)
+	assert.Contains(t, request.RequestBody, th.BasicUser.Email)
+	assert.NotContains(t, request.RequestBody, th.BasicUser.Password)
+	assert.Equal(t, model.DEBUG_RECORDING_REDACTED, request.ResponseHeaders["Token"])
+
+	_, resp = Client.StopDebugRecording()
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.StopDebugRecording()
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.GetDebugRecording()
+	CheckNotFoundStatus(t, resp)
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitEventSubscription() {
+	api.BaseRoutes.EventSubscriptions.Handle("", api.ApiSessionRequired(createEventSubscription)).Methods("POST")
+	api.BaseRoutes.EventSubscriptions.Handle("", api.ApiSessionRequired(getEventSubscriptions)).Methods("GET")
+	api.BaseRoutes.EventSubscription.Handle("", api.ApiSessionRequired(getEventSubscription)).Methods("GET")
+	api.BaseRoutes.EventSubscription.Handle("/patch", api.ApiSessionRequired(patchEventSubscription)).Methods("PUT")
+	api.BaseRoutes.EventSubscription.Handle("", api.ApiSessionRequired(deleteEventSubscription)).Methods("DELETE")
+	api.BaseRoutes.EventSubscription.Handle("/regen_signing_secret", api.ApiSessionRequired(regenEventSubscriptionSigningSecret)).Methods("POST")
+}
+
+func createEventSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	subscription := model.EventSubscriptionFromJson(r.Body)
+	if subscription == nil {
+		c.SetInvalidParam("subscription")
+		return
+	}
+
+	c.LogAudit("attempt")
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, subscription.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	// Posts in private channels are only sent to subscriptions made by users who can read them
+	if len(subscription.ChannelId) > 0 {
+		channel, err := c.App.GetChannel(subscription.ChannelId)
+		if err != nil {
+			c.Err = err
+			return
+		}
+
+		if channel.Type != model.CHANNEL_OPEN && !c.App.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_READ_CHANNEL) {
+			c.LogAudit("fail - bad channel permissions")
+			c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+			return
+		}
+	}
+
+	subscription.Id = ""
+	subscription.CreatorId = c.Session.UserId
+
+	rsubscription, err := c.App.CreateEventSubscription(subscription)
+	if err != nil {
+		c.LogAudit("fail")
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success subscription_id=" + rsubscription.Id)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rsubscription.ToJson()))
+}
+
+func getEventSubscriptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	teamId := r.URL.Query().Get("team_id")
+	if len(teamId) != 26 {
+		c.SetInvalidParam("team_id")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, teamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	subscriptions, err := c.App.GetEventSubscriptionsForTeamPage(teamId, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.EventSubscriptionListToJson(subscriptions)))
+}
+
+// getEventSubscriptionForSession gets the subscription in the request's URL, checking that the session can manage it.
+func getEventSubscriptionForSession(c *Context) *model.EventSubscription {
+	c.RequireSubscriptionId()
+	if c.Err != nil {
+		return nil
+	}
+
+	subscription, err := c.App.GetEventSubscription(c.Params.SubscriptionId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, subscription.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return nil
+	}
+
+	if c.Session.UserId != subscription.CreatorId && !c.App.SessionHasPermissionToTeam(c.Session, subscription.TeamId, model.PERMISSION_MANAGE_OTHERS_WEBHOOKS) {
+		c.LogAudit("fail - inappropriate permissions")
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_WEBHOOKS)
+		return nil
+	}
+
+	return subscription
+}
+
+func getEventSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	subscription := getEventSubscriptionForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	w.Write([]byte(subscription.ToJson()))
+}
+
+func patchEventSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	patch := model.EventSubscriptionPatchFromJson(r.Body)
+	if patch == nil {
+		c.SetInvalidParam("subscription")
+		return
+	}
+
+	c.LogAudit("attempt")
+
+	subscription := getEventSubscriptionForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	rsubscription, err := c.App.PatchEventSubscription(subscription, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success")
+	w.Write([]byte(rsubscription.ToJson()))
+}
+
+func deleteEventSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.LogAudit("attempt")
+
+	subscription := getEventSubscriptionForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	if err := c.App.DeleteEventSubscription(subscription.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success")
+	ReturnStatusOK(w)
+}
+
+func regenEventSubscriptionSigningSecret(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.LogAudit("attempt")
+
+	subscription := getEventSubscriptionForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	rsubscription, err := c.App.RegenEventSubscriptionSigningSecret(subscription)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success")
+	w.Write([]byte(rsubscription.ToJson()))
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestEventSubscriptions(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.RemovePermissionFromRole(model.PERMISSION_MANAGE_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	subscription := &model.EventSubscription{
+		TeamId:      th.BasicTeam.Id,
+		ChannelId:   th.BasicChannel.Id,
+		EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+		CallbackURL: "http://nowhere.com",
+	}
+
+	_, resp := Client.CreateEventSubscription(subscription)
+	CheckForbiddenStatus(t, resp)
+
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	rsubscription, resp := Client.CreateEventSubscription(subscription)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, rsubscription.CreatorId)
+	assert.True(t, rsubscription.Active)
+	assert.NotEmpty(t, rsubscription.SigningSecret)
+
+	t.Run("validates the subscription", func(t *testing.T) {
+		_, resp := Client.CreateEventSubscription(&model.EventSubscription{
+			TeamId:      th.BasicTeam.Id,
+			EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+			CallbackURL: "http://nowhere.com",
+		})
+		CheckBadRequestStatus(t, resp)
+
+		otherTeam := th.CreateTeam()
+		_, resp = th.SystemAdminClient.CreateEventSubscription(&model.EventSubscription{
+			TeamId:      otherTeam.Id,
+			ChannelId:   th.BasicChannel.Id,
+			EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+			CallbackURL: "http://nowhere.com",
+		})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("requires access to private channels", func(t *testing.T) {
+		privateChannel := th.CreatePrivateChannel()
+
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp := Client.CreateEventSubscription(&model.EventSubscription{
+			TeamId:      th.BasicTeam.Id,
+			ChannelId:   privateChannel.Id,
+			EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+			CallbackURL: "http://nowhere.com",
+		})
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		fetched, resp := Client.GetEventSubscription(rsubscription.Id)
+		CheckNoError(t, resp)
+		assert.Equal(t, rsubscription.Id, fetched.Id)
+
+		subscriptions, resp := Client.GetEventSubscriptionsForTeam(th.BasicTeam.Id, 0, 60)
+		CheckNoError(t, resp)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, rsubscription.Id, subscriptions[0].Id)
+
+		_, resp = Client.GetEventSubscription(model.NewId())
+		CheckNotFoundStatus(t, resp)
+
+		_, resp = Client.GetEventSubscriptionsForTeam("junk", 0, 60)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("only the creator can manage a subscription without permission to manage others", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp := Client.GetEventSubscription(rsubscription.Id)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = Client.DeleteEventSubscription(rsubscription.Id)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = th.SystemAdminClient.GetEventSubscription(rsubscription.Id)
+		CheckNoError(t, resp)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		active := false
+		callbackURL := "http://somewhere.com"
+		patched, resp := Client.PatchEventSubscription(rsubscription.Id, &model.EventSubscriptionPatch{Active: &active, CallbackURL: &callbackURL})
+		CheckNoError(t, resp)
+		assert.False(t, patched.Active)
+		assert.Equal(t, callbackURL, patched.CallbackURL)
+		assert.Equal(t, model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED, patched.EventType)
+
+		callbackURL = "junk"
+		_, resp = Client.PatchEventSubscription(rsubscription.Id, &model.EventSubscriptionPatch{CallbackURL: &callbackURL})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("regenerate signing secret", func(t *testing.T) {
+		regenerated, resp := Client.RegenEventSubscriptionSigningSecret(rsubscription.Id)
+		CheckNoError(t, resp)
+		assert.NotEqual(t, rsubscription.SigningSecret, regenerated.SigningSecret)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+		_, resp := Client.CreateEventSubscription(subscription)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ok, resp := Client.DeleteEventSubscription(rsubscription.Id)
+		CheckNoError(t, resp)
+		assert.True(t, ok)
+
+		_, resp = Client.GetEventSubscription(rsubscription.Id)
+		CheckNotFoundStatus(t, resp)
+	})
+}
+
+func TestEventSubscriptionDelivery(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.ServiceSettings.EnableOutgoingWebhooks = true
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+	})
+
+	received := make(chan *model.EventSubscriptionPayload, 10)
+	var signingSecret string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(model.HEADER_OUTGOING_HOOK_TIMESTAMP), 10, 64)
+		assert.Equal(t, model.SignOutgoingWebhookPayload(signingSecret, timestamp, body), r.Header.Get(model.HEADER_OUTGOING_HOOK_SIGNATURE))
+
+		payload := &model.EventSubscriptionPayload{}
+		require.Nil(t, json.Unmarshal(body, payload))
+		received <- payload
+	}))
+	defer ts.Close()
+
+	subscription, resp := th.SystemAdminClient.CreateEventSubscription(&model.EventSubscription{
+		TeamId:      th.BasicTeam.Id,
+		ChannelId:   th.BasicChannel.Id,
+		EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+		CallbackURL: ts.URL,
+	})
+	CheckNoError(t, resp)
+	signingSecret = subscription.SigningSecret
+
+	post, resp := th.Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "hello"})
+	CheckNoError(t, resp)
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, subscription.Id, payload.SubscriptionId)
+		assert.Equal(t, model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED, payload.Event)
+		assert.Equal(t, th.BasicTeam.Id, payload.TeamId)
+		assert.Equal(t, th.BasicChannel.Id, payload.ChannelId)
+		require.NotNil(t, payload.Post)
+		assert.Equal(t, post.Id, payload.Post.Id)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "should have sent the new post to the subscription")
+	}
+
+	_, resp = th.Client.CreatePost(&model.Post{ChannelId: th.BasicChannel2.Id, Message: "hello"})
+	CheckNoError(t, resp)
+
+	select {
+	case <-received:
+		require.Fail(t, "shouldn't send posts in other channels to the subscription")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -20,6 +20,8 @@ import (
 const (
 	FILE_TEAM_ID = "noteam"
 
+	MAX_FILE_INFOS_PER_REQUEST = 200
+
 	PREVIEW_IMAGE_TYPE   = "image/jpeg"
 	THUMBNAIL_IMAGE_TYPE = "image/jpeg"
 )
@@ -46,12 +48,13 @@ var MEDIA_CONTENT_TYPES = [...]string{
 }
 
 func (api *API) InitFile() {
-	api.BaseRoutes.Files.Handle("", api.ApiSessionRequired(uploadFile)).Methods("POST")
-	api.BaseRoutes.File.Handle("", api.ApiSessionRequiredTrustRequester(getFile)).Methods("GET")
-	api.BaseRoutes.File.Handle("/thumbnail", api.ApiSessionRequiredTrustRequester(getFileThumbnail)).Methods("GET")
+	api.BaseRoutes.Files.Handle("", api.ApiSessionRequiredWithScope(uploadFile, model.OAUTH_SCOPE_WRITE_FILES)).Methods("POST")
+	api.BaseRoutes.Files.Handle("/infos", api.ApiSessionRequiredWithScope(getFileInfos, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
+	api.BaseRoutes.File.Handle("", api.ApiSessionRequiredTrustRequesterWithScope(getFile, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
+	api.BaseRoutes.File.Handle("/thumbnail", api.ApiSessionRequiredTrustRequesterWithScope(getFileThumbnail, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
 	api.BaseRoutes.File.Handle("/link", api.ApiSessionRequired(getFileLink)).Methods("GET")
-	api.BaseRoutes.File.Handle("/preview", api.ApiSessionRequiredTrustRequester(getFilePreview)).Methods("GET")
-	api.BaseRoutes.File.Handle("/info", api.ApiSessionRequired(getFileInfo)).Methods("GET")
+	api.BaseRoutes.File.Handle("/preview", api.ApiSessionRequiredTrustRequesterWithScope(getFilePreview, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
+	api.BaseRoutes.File.Handle("/info", api.ApiSessionRequiredWithScope(getFileInfo, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
 
 	api.BaseRoutes.PublicFile.Handle("", api.ApiHandler(getPublicFile)).Methods("GET")
 
@@ -75,7 +78,7 @@ func uploadFile(c *Context, w http.ResponseWriter, r *http.Request) {
 	var appErr *model.AppError
 
 	if err := r.ParseMultipartForm(*c.App.Config().FileSettings.MaxFileSize); err != nil && err != http.ErrNotMultipart {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.Err = model.NewAppError("uploadFile", "api.file.upload_file.parse.app_error", nil, err.Error(), http.StatusBadRequest)
 		return
 	} else if err == http.ErrNotMultipart {
 		defer r.Body.Close()
@@ -134,6 +137,10 @@ func uploadFile(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	if appErr != nil {
+		if app.IsUploadPolicyError(appErr) {
+			c.LogAudit("rejected " + appErr.DetailedError)
+		}
+
 		c.Err = appErr
 		return
 	}
@@ -317,6 +324,54 @@ func getFileInfo(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(info.ToJson()))
 }
 
+// getFileInfos returns the file infos for the comma separated file ids in the request. Files that the user isn't
+// allowed to see are left out rather than failing the whole request.
+func getFileInfos(c *Context, w http.ResponseWriter, r *http.Request) {
+	var fileIds []string
+	for _, fileId := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if len(fileId) == 0 {
+			continue
+		}
+
+		if len(fileId) != 26 {
+			c.SetInvalidUrlParam("ids")
+			return
+		}
+		fileIds = append(fileIds, fileId)
+	}
+
+	if len(fileIds) == 0 || len(fileIds) > MAX_FILE_INFOS_PER_REQUEST {
+		c.SetInvalidUrlParam("ids")
+		return
+	}
+
+	infos, err := c.App.GetFileInfosByIds(fileIds)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	canReadPost := make(map[string]bool)
+	allowed := []*model.FileInfo{}
+	for _, info := range infos {
+		if info.CreatorId != c.Session.UserId {
+			canRead, checked := canReadPost[info.PostId]
+			if !checked {
+				canRead = len(info.PostId) > 0 && c.App.SessionHasPermissionToChannelByPost(c.Session, info.PostId, model.PERMISSION_READ_CHANNEL)
+				canReadPost[info.PostId] = canRead
+			}
+
+			if !canRead {
+				continue
+			}
+		}
+
+		allowed = append(allowed, info)
+	}
+
+	w.Write([]byte(model.FileInfosToJson(allowed)))
+}
+
 func getPublicFile(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireFileId()
 	if c.Err != nil {
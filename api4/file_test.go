@@ -612,6 +612,69 @@ func TestGetFileInfo(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestGetFileInfosByIds(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	fileIds := make([]string, 2)
+	if data, err := readTestFile("test.png"); err != nil {
+		t.Fatal(err)
+	} else {
+		for i := range fileIds {
+			fileResp, resp := Client.UploadFile(data, th.BasicChannel.Id, "test.png")
+			CheckNoError(t, resp)
+			fileIds[i] = fileResp.FileInfos[0].Id
+		}
+	}
+
+	post, resp := Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "files", FileIds: fileIds[:1]})
+	CheckNoError(t, resp)
+
+	infos, resp := Client.GetFileInfosByIds(append(fileIds, model.NewId()))
+	CheckNoError(t, resp)
+
+	if len(infos) != 2 {
+		t.Fatal("should have returned the file infos that exist")
+	}
+
+	_, resp = Client.GetFileInfosByIds([]string{})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetFileInfosByIds([]string{"junk"})
+	CheckBadRequestStatus(t, resp)
+
+	tooMany := make([]string, MAX_FILE_INFOS_PER_REQUEST+1)
+	for i := range tooMany {
+		tooMany[i] = model.NewId()
+	}
+	_, resp = Client.GetFileInfosByIds(tooMany)
+	CheckBadRequestStatus(t, resp)
+
+	th.LoginBasic2()
+
+	infos, resp = Client.GetFileInfosByIds(fileIds)
+	CheckNoError(t, resp)
+
+	if len(infos) != 1 || infos[0].PostId != post.Id {
+		t.Fatal("should have only returned the file info attached to a post in a channel the user can read")
+	}
+
+	otherUser := th.CreateUser()
+	Client.Login(otherUser.Email, otherUser.Password)
+
+	infos, resp = Client.GetFileInfosByIds(fileIds)
+	CheckNoError(t, resp)
+
+	if len(infos) != 0 {
+		t.Fatal("shouldn't have returned file infos the user can't see")
+	}
+
+	Client.Logout()
+	_, resp = Client.GetFileInfosByIds(fileIds)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetPublicFile(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
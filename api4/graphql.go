@@ -0,0 +1,549 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	// The deepest that a query can nest its selections, which stops a single request from doing unbounded work
+	GRAPHQL_MAX_DEPTH = 8
+
+	// The most resolvers of a query that are run at the same time
+	GRAPHQL_MAX_PARALLELISM = 10
+)
+
+// The schema covers what the webapp loads when it starts, so that it can get all of it in one request. Each field
+// checks the same permissions as the matching REST endpoint, and the fields that return what other endpoints would are
+// nullable so that a field that fails doesn't stop the rest of the query from being returned. Timestamps are Floats since GraphQL's Int is only 32
+// bits.
+const graphQLSchemaString = `
+scalar Map
+
+schema {
+	query: Query
+}
+
+type Query {
+	user(id: String!): User
+	users(ids: [String!]!): [User!]
+	teams(userId: String!): [Team!]
+	teamMembers(userId: String!): [TeamMember!]
+	channels(userId: String!, teamId: String!): [Channel!]
+	channelMembers(userId: String!, teamId: String!): [ChannelMember!]
+	preferences(userId: String!, category: String): [Preference!]
+}
+
+type User {
+	id: String!
+	username: String!
+	email: String!
+	nickname: String!
+	firstName: String!
+	lastName: String!
+	position: String!
+	roles: String!
+	locale: String!
+	authService: String!
+	isBot: Boolean!
+	notifyProps: Map
+	props: Map
+	timezone: Map
+	lastPictureUpdate: Float!
+	createAt: Float!
+	updateAt: Float!
+	deleteAt: Float!
+}
+
+type Team {
+	id: String!
+	name: String!
+	displayName: String!
+	description: String!
+	type: String!
+	email: String!
+	companyName: String!
+	allowedDomains: String!
+	inviteId: String!
+	allowOpenInvite: Boolean!
+	schemeId: String!
+	lastTeamIconUpdate: Float!
+	createAt: Float!
+	updateAt: Float!
+	deleteAt: Float!
+}
+
+type TeamMember {
+	team: Team!
+	userId: String!
+	roles: String!
+	schemeUser: Boolean!
+	schemeAdmin: Boolean!
+	deleteAt: Float!
+	channels: [Channel!]
+	channelMembers: [ChannelMember!]
+}
+
+type Channel {
+	id: String!
+	teamId: String!
+	type: String!
+	name: String!
+	displayName: String!
+	header: String!
+	purpose: String!
+	creatorId: String!
+	schemeId: String!
+	props: Map
+	lastPostAt: Float!
+	totalMsgCount: Float!
+	createAt: Float!
+	updateAt: Float!
+	deleteAt: Float!
+}
+
+type ChannelMember {
+	channelId: String!
+	userId: String!
+	roles: String!
+	schemeUser: Boolean!
+	schemeAdmin: Boolean!
+	notifyProps: Map
+	lastViewedAt: Float!
+	msgCount: Float!
+	mentionCount: Float!
+	lastUpdateAt: Float!
+}
+
+type Preference {
+	userId: String!
+	category: String!
+	name: String!
+	value: String!
+}
+`
+
+var graphQLSchema = graphql.MustParseSchema(
+	graphQLSchemaString,
+	&graphQLResolver{},
+	graphql.MaxDepth(GRAPHQL_MAX_DEPTH),
+	graphql.MaxParallelism(GRAPHQL_MAX_PARALLELISM),
+	graphql.Logger(graphQLLogger{}),
+)
+
+func (api *API) InitGraphQL() {
+	api.BaseRoutes.ApiRoot5.Handle("/graphql", api.ApiSessionRequired(executeGraphQL)).Methods("POST")
+}
+
+func executeGraphQL(c *Context, w http.ResponseWriter, r *http.Request) {
+	request := model.GraphQLRequestFromJson(r.Body)
+	if request == nil || request.Query == "" {
+		c.SetInvalidParam("query")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLContextKey{}, c)
+
+	response := graphQLSchema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	b, err := json.Marshal(response)
+	if err != nil {
+		c.Err = model.NewAppError("executeGraphQL", "api.graphql.marshal.app_error", nil, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Errors in resolving part of a query are sent along with the rest of the result, as GraphQL clients expect
+	w.Write(b)
+}
+
+type graphQLContextKey struct{}
+
+func graphQLContext(ctx context.Context) *Context {
+	return ctx.Value(graphQLContextKey{}).(*Context)
+}
+
+// graphQLError adds the id and status code of an AppError to the GraphQL error that it becomes.
+type graphQLError struct {
+	*model.AppError
+}
+
+func (e graphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          e.Id,
+		"status_code": e.StatusCode,
+	}
+}
+
+func newGraphQLError(c *Context, err *model.AppError) error {
+	err.Translate(c.T)
+	return graphQLError{err}
+}
+
+func newGraphQLPermissionError(c *Context, permission *model.Permission) error {
+	return newGraphQLError(c, model.NewAppError("Permissions", "api.context.permissions.app_error", nil, "userId="+c.Session.UserId+", "+"permission="+permission.Id, http.StatusForbidden))
+}
+
+type graphQLLogger struct{}
+
+func (graphQLLogger) LogPanic(ctx context.Context, value interface{}) {
+	mlog.Error("Recovered from a panic while resolving a GraphQL query", mlog.String("panic", fmt.Sprint(value)))
+}
+
+// graphQLMap lets the maps of users, channels and channel members be returned as JSON objects.
+type graphQLMap map[string]interface{}
+
+func (graphQLMap) ImplementsGraphQLType(name string) bool {
+	return name == "Map"
+}
+
+func (m *graphQLMap) UnmarshalGraphQL(input interface{}) error {
+	values, ok := input.(map[string]interface{})
+	if !ok {
+		return model.NewAppError("UnmarshalGraphQL", "api.graphql.map.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	*m = values
+	return nil
+}
+
+func newGraphQLMap(m model.StringMap) *graphQLMap {
+	if m == nil {
+		return nil
+	}
+
+	gm := make(graphQLMap, len(m))
+	for key, value := range m {
+		gm[key] = value
+	}
+
+	return &gm
+}
+
+func graphQLString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+type graphQLResolver struct{}
+
+func resolveGraphQLUserId(c *Context, userId string) string {
+	if userId == model.ME {
+		return c.Session.UserId
+	}
+
+	return userId
+}
+
+func (*graphQLResolver) User(ctx context.Context, args struct{ Id string }) (*graphQLUser, error) {
+	c := graphQLContext(ctx)
+	userId := resolveGraphQLUserId(c, args.Id)
+
+	// No permission check required
+
+	user, err := c.App.GetUser(userId)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	if c.Session.UserId == user.Id {
+		user.Sanitize(map[string]bool{})
+	} else {
+		c.App.SanitizeProfile(user, c.IsSystemAdmin())
+	}
+
+	return &graphQLUser{user}, nil
+}
+
+func (*graphQLResolver) Users(ctx context.Context, args struct{ Ids []string }) (*[]*graphQLUser, error) {
+	c := graphQLContext(ctx)
+
+	if len(args.Ids) == 0 {
+		return &[]*graphQLUser{}, nil
+	}
+
+	// No permission check required
+
+	users, err := c.App.GetUsersByIds(args.Ids, c.IsSystemAdmin())
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	resolvers := make([]*graphQLUser, 0, len(users))
+	for _, user := range users {
+		resolvers = append(resolvers, &graphQLUser{user})
+	}
+
+	return &resolvers, nil
+}
+
+func (*graphQLResolver) Teams(ctx context.Context, args struct{ UserId string }) (*[]*graphQLTeam, error) {
+	c := graphQLContext(ctx)
+	userId := resolveGraphQLUserId(c, args.UserId)
+
+	if c.Session.UserId != userId && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_MANAGE_SYSTEM)
+	}
+
+	teams, err := c.App.GetTeamsForUser(userId)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	c.App.SanitizeTeams(c.Session, teams)
+
+	resolvers := make([]*graphQLTeam, 0, len(teams))
+	for _, team := range teams {
+		resolvers = append(resolvers, &graphQLTeam{team})
+	}
+
+	return &resolvers, nil
+}
+
+func (*graphQLResolver) TeamMembers(ctx context.Context, args struct{ UserId string }) (*[]*graphQLTeamMember, error) {
+	c := graphQLContext(ctx)
+	userId := resolveGraphQLUserId(c, args.UserId)
+
+	if !c.App.SessionHasPermissionToUser(c.Session, userId) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_EDIT_OTHER_USERS)
+	}
+
+	members, err := c.App.GetTeamMembersForUser(userId)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	resolvers := make([]*graphQLTeamMember, 0, len(members))
+	for _, member := range members {
+		resolvers = append(resolvers, &graphQLTeamMember{member})
+	}
+
+	return &resolvers, nil
+}
+
+func (*graphQLResolver) Channels(ctx context.Context, args struct{ UserId, TeamId string }) (*[]*graphQLChannel, error) {
+	c := graphQLContext(ctx)
+	return getGraphQLChannels(c, resolveGraphQLUserId(c, args.UserId), args.TeamId)
+}
+
+func getGraphQLChannels(c *Context, userId, teamId string) (*[]*graphQLChannel, error) {
+	if !c.App.SessionHasPermissionToUser(c.Session, userId) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_EDIT_OTHER_USERS)
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, teamId, model.PERMISSION_VIEW_TEAM) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_VIEW_TEAM)
+	}
+
+	channels, err := c.App.GetChannelsForUser(teamId, userId, false)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	if err := c.App.FillInChannelsProps(channels); err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	resolvers := make([]*graphQLChannel, 0, len(*channels))
+	for _, channel := range *channels {
+		resolvers = append(resolvers, &graphQLChannel{channel})
+	}
+
+	return &resolvers, nil
+}
+
+func (*graphQLResolver) ChannelMembers(ctx context.Context, args struct{ UserId, TeamId string }) (*[]*graphQLChannelMember, error) {
+	c := graphQLContext(ctx)
+	return getGraphQLChannelMembers(c, resolveGraphQLUserId(c, args.UserId), args.TeamId)
+}
+
+func getGraphQLChannelMembers(c *Context, userId, teamId string) (*[]*graphQLChannelMember, error) {
+	if !c.App.SessionHasPermissionToTeam(c.Session, teamId, model.PERMISSION_VIEW_TEAM) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_VIEW_TEAM)
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, userId) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_EDIT_OTHER_USERS)
+	}
+
+	members, err := c.App.GetChannelMembersForUser(teamId, userId)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	resolvers := make([]*graphQLChannelMember, 0, len(*members))
+	for i := range *members {
+		resolvers = append(resolvers, &graphQLChannelMember{&(*members)[i]})
+	}
+
+	return &resolvers, nil
+}
+
+func (*graphQLResolver) Preferences(ctx context.Context, args struct {
+	UserId   string
+	Category *string
+}) (*[]*graphQLPreference, error) {
+	c := graphQLContext(ctx)
+	userId := resolveGraphQLUserId(c, args.UserId)
+
+	if !c.App.SessionHasPermissionToUser(c.Session, userId) {
+		return nil, newGraphQLPermissionError(c, model.PERMISSION_EDIT_OTHER_USERS)
+	}
+
+	var preferences model.Preferences
+	var err *model.AppError
+	if args.Category != nil {
+		preferences, err = c.App.GetPreferenceByCategoryForUser(userId, *args.Category)
+	} else {
+		preferences, err = c.App.GetPreferencesForUser(userId)
+	}
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	resolvers := make([]*graphQLPreference, 0, len(preferences))
+	for i := range preferences {
+		resolvers = append(resolvers, &graphQLPreference{&preferences[i]})
+	}
+
+	return &resolvers, nil
+}
+
+type graphQLUser struct {
+	user *model.User
+}
+
+func (u *graphQLUser) Id() string                 { return u.user.Id }
+func (u *graphQLUser) Username() string           { return u.user.Username }
+func (u *graphQLUser) Email() string              { return u.user.Email }
+func (u *graphQLUser) Nickname() string           { return u.user.Nickname }
+func (u *graphQLUser) FirstName() string          { return u.user.FirstName }
+func (u *graphQLUser) LastName() string           { return u.user.LastName }
+func (u *graphQLUser) Position() string           { return u.user.Position }
+func (u *graphQLUser) Roles() string              { return u.user.Roles }
+func (u *graphQLUser) Locale() string             { return u.user.Locale }
+func (u *graphQLUser) AuthService() string        { return u.user.AuthService }
+func (u *graphQLUser) IsBot() bool                { return u.user.IsBot }
+func (u *graphQLUser) NotifyProps() *graphQLMap   { return newGraphQLMap(u.user.NotifyProps) }
+func (u *graphQLUser) Props() *graphQLMap         { return newGraphQLMap(u.user.Props) }
+func (u *graphQLUser) Timezone() *graphQLMap      { return newGraphQLMap(u.user.Timezone) }
+func (u *graphQLUser) LastPictureUpdate() float64 { return float64(u.user.LastPictureUpdate) }
+func (u *graphQLUser) CreateAt() float64          { return float64(u.user.CreateAt) }
+func (u *graphQLUser) UpdateAt() float64          { return float64(u.user.UpdateAt) }
+func (u *graphQLUser) DeleteAt() float64          { return float64(u.user.DeleteAt) }
+
+type graphQLTeam struct {
+	team *model.Team
+}
+
+func (t *graphQLTeam) Id() string                  { return t.team.Id }
+func (t *graphQLTeam) Name() string                { return t.team.Name }
+func (t *graphQLTeam) DisplayName() string         { return t.team.DisplayName }
+func (t *graphQLTeam) Description() string         { return t.team.Description }
+func (t *graphQLTeam) Type() string                { return t.team.Type }
+func (t *graphQLTeam) Email() string               { return t.team.Email }
+func (t *graphQLTeam) CompanyName() string         { return t.team.CompanyName }
+func (t *graphQLTeam) AllowedDomains() string      { return t.team.AllowedDomains }
+func (t *graphQLTeam) InviteId() string            { return t.team.InviteId }
+func (t *graphQLTeam) AllowOpenInvite() bool       { return t.team.AllowOpenInvite }
+func (t *graphQLTeam) SchemeId() string            { return graphQLString(t.team.SchemeId) }
+func (t *graphQLTeam) LastTeamIconUpdate() float64 { return float64(t.team.LastTeamIconUpdate) }
+func (t *graphQLTeam) CreateAt() float64           { return float64(t.team.CreateAt) }
+func (t *graphQLTeam) UpdateAt() float64           { return float64(t.team.UpdateAt) }
+func (t *graphQLTeam) DeleteAt() float64           { return float64(t.team.DeleteAt) }
+
+type graphQLTeamMember struct {
+	member *model.TeamMember
+}
+
+func (m *graphQLTeamMember) UserId() string    { return m.member.UserId }
+func (m *graphQLTeamMember) Roles() string     { return m.member.Roles }
+func (m *graphQLTeamMember) SchemeUser() bool  { return m.member.SchemeUser }
+func (m *graphQLTeamMember) SchemeAdmin() bool { return m.member.SchemeAdmin }
+func (m *graphQLTeamMember) DeleteAt() float64 { return float64(m.member.DeleteAt) }
+
+func (m *graphQLTeamMember) Team(ctx context.Context) (*graphQLTeam, error) {
+	c := graphQLContext(ctx)
+
+	// The member was already checked to be visible, and a member can always see its own team
+	team, err := c.App.GetTeam(m.member.TeamId)
+	if err != nil {
+		return nil, newGraphQLError(c, err)
+	}
+
+	c.App.SanitizeTeam(c.Session, team)
+
+	return &graphQLTeam{team}, nil
+}
+
+func (m *graphQLTeamMember) Channels(ctx context.Context) (*[]*graphQLChannel, error) {
+	return getGraphQLChannels(graphQLContext(ctx), m.member.UserId, m.member.TeamId)
+}
+
+func (m *graphQLTeamMember) ChannelMembers(ctx context.Context) (*[]*graphQLChannelMember, error) {
+	return getGraphQLChannelMembers(graphQLContext(ctx), m.member.UserId, m.member.TeamId)
+}
+
+type graphQLChannel struct {
+	channel *model.Channel
+}
+
+func (ch *graphQLChannel) Id() string             { return ch.channel.Id }
+func (ch *graphQLChannel) TeamId() string         { return ch.channel.TeamId }
+func (ch *graphQLChannel) Type() string           { return ch.channel.Type }
+func (ch *graphQLChannel) Name() string           { return ch.channel.Name }
+func (ch *graphQLChannel) DisplayName() string    { return ch.channel.DisplayName }
+func (ch *graphQLChannel) Header() string         { return ch.channel.Header }
+func (ch *graphQLChannel) Purpose() string        { return ch.channel.Purpose }
+func (ch *graphQLChannel) CreatorId() string      { return ch.channel.CreatorId }
+func (ch *graphQLChannel) SchemeId() string       { return graphQLString(ch.channel.SchemeId) }
+func (ch *graphQLChannel) LastPostAt() float64    { return float64(ch.channel.LastPostAt) }
+func (ch *graphQLChannel) TotalMsgCount() float64 { return float64(ch.channel.TotalMsgCount) }
+func (ch *graphQLChannel) CreateAt() float64      { return float64(ch.channel.CreateAt) }
+func (ch *graphQLChannel) UpdateAt() float64      { return float64(ch.channel.UpdateAt) }
+func (ch *graphQLChannel) DeleteAt() float64      { return float64(ch.channel.DeleteAt) }
+
+func (ch *graphQLChannel) Props() *graphQLMap {
+	if ch.channel.Props == nil {
+		return nil
+	}
+
+	props := graphQLMap(ch.channel.Props)
+	return &props
+}
+
+type graphQLChannelMember struct {
+	member *model.ChannelMember
+}
+
+func (m *graphQLChannelMember) ChannelId() string        { return m.member.ChannelId }
+func (m *graphQLChannelMember) UserId() string           { return m.member.UserId }
+func (m *graphQLChannelMember) Roles() string            { return m.member.Roles }
+func (m *graphQLChannelMember) SchemeUser() bool         { return m.member.SchemeUser }
+func (m *graphQLChannelMember) SchemeAdmin() bool        { return m.member.SchemeAdmin }
+func (m *graphQLChannelMember) NotifyProps() *graphQLMap { return newGraphQLMap(m.member.NotifyProps) }
+func (m *graphQLChannelMember) LastViewedAt() float64    { return float64(m.member.LastViewedAt) }
+func (m *graphQLChannelMember) MsgCount() float64        { return float64(m.member.MsgCount) }
+func (m *graphQLChannelMember) MentionCount() float64    { return float64(m.member.MentionCount) }
+func (m *graphQLChannelMember) LastUpdateAt() float64    { return float64(m.member.LastUpdateAt) }
+
+type graphQLPreference struct {
+	preference *model.Preference
+}
+
+func (p *graphQLPreference) UserId() string   { return p.preference.UserId }
+func (p *graphQLPreference) Category() string { return p.preference.Category }
+func (p *graphQLPreference) Name() string     { return p.preference.Name }
+func (p *graphQLPreference) Value() string    { return p.preference.Value }
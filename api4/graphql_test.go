@@ -0,0 +1,112 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestGraphQL(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	t.Run("bootstrap in one request", func(t *testing.T) {
+		response, resp := Client.GraphQL(&model.GraphQLRequest{
+			Query: `query Bootstrap($me: String!) {
+				user(id: $me) { id username }
+				teamMembers(userId: $me) {
+					roles
+					team { id name }
+					channels { id name }
+					channelMembers { channelId msgCount }
+				}
+				preferences(userId: $me) { category name value }
+			}`,
+			OperationName: "Bootstrap",
+			Variables:     map[string]interface{}{"me": model.ME},
+		})
+		CheckNoError(t, resp)
+		require.Empty(t, response.Errors)
+
+		var data struct {
+			User struct {
+				Id       string
+				Username string
+			}
+			TeamMembers []struct {
+				Roles string
+				Team  struct {
+					Id   string
+					Name string
+				}
+				Channels []struct {
+					Id   string
+					Name string
+				}
+				ChannelMembers []struct {
+					ChannelId string
+					MsgCount  float64
+				}
+			}
+			Preferences []struct {
+				Category string
+				Name     string
+				Value    string
+			}
+		}
+		require.Nil(t, json.Unmarshal(response.Data, &data))
+
+		assert.Equal(t, th.BasicUser.Id, data.User.Id)
+		assert.Equal(t, th.BasicUser.Username, data.User.Username)
+
+		require.Len(t, data.TeamMembers, 1)
+		assert.Equal(t, th.BasicTeam.Id, data.TeamMembers[0].Team.Id)
+
+		found := false
+		for _, channel := range data.TeamMembers[0].Channels {
+			if channel.Id == th.BasicChannel.Id {
+				found = true
+			}
+		}
+		assert.True(t, found, "should include the user's channels")
+		assert.Equal(t, len(data.TeamMembers[0].Channels), len(data.TeamMembers[0].ChannelMembers))
+	})
+
+	t.Run("permissions are checked for each field", func(t *testing.T) {
+		response, resp := Client.GraphQL(&model.GraphQLRequest{
+			Query: `{
+				user(id: "` + th.BasicUser2.Id + `") { id email }
+				preferences(userId: "` + th.BasicUser2.Id + `") { name }
+				channelMembers(userId: "` + th.BasicUser2.Id + `", teamId: "` + th.BasicTeam.Id + `") { channelId }
+			}`,
+		})
+		CheckNoError(t, resp)
+
+		require.Len(t, response.Errors, 2)
+		for _, err := range response.Errors {
+			assert.Equal(t, "api.context.permissions.app_error", err.Extensions["id"])
+			assert.Equal(t, float64(403), err.Extensions["status_code"])
+		}
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		response, resp := Client.GraphQL(&model.GraphQLRequest{Query: `{ posts { id } }`})
+		CheckNoError(t, resp)
+		assert.NotEmpty(t, response.Errors)
+
+		_, resp = Client.GraphQL(&model.GraphQLRequest{})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	Client.Logout()
+	_, resp := Client.GraphQL(&model.GraphQLRequest{Query: `{ user(id: "me") { id } }`})
+	CheckUnauthorizedStatus(t, resp)
+}
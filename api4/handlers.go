@@ -33,6 +33,20 @@ func (api *API) ApiSessionRequired(h func(*Context, http.ResponseWriter, *http.R
 	}
 }
 
+// ApiSessionRequiredWithScope is like ApiSessionRequired, but also allows requests from OAuth apps that were only given
+// the scope. Other routes can only be used by apps that were given the default scope.
+func (api *API) ApiSessionRequiredWithScope(h func(*Context, http.ResponseWriter, *http.Request), scope string) http.Handler {
+	return &web.Handler{
+		App:            api.App,
+		HandleFunc:     h,
+		RequireSession: true,
+		TrustRequester: false,
+		RequireMfa:     true,
+		IsStatic:       false,
+		OAuthScope:     scope,
+	}
+}
+
 func (api *API) ApiSessionRequiredMfa(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
 	return &web.Handler{
 		App:            api.App,
@@ -65,3 +79,15 @@ func (api *API) ApiSessionRequiredTrustRequester(h func(*Context, http.ResponseW
 		IsStatic:       false,
 	}
 }
+
+func (api *API) ApiSessionRequiredTrustRequesterWithScope(h func(*Context, http.ResponseWriter, *http.Request), scope string) http.Handler {
+	return &web.Handler{
+		App:            api.App,
+		HandleFunc:     h,
+		RequireSession: true,
+		TrustRequester: true,
+		RequireMfa:     true,
+		IsStatic:       false,
+		OAuthScope:     scope,
+	}
+}
@@ -4,11 +4,16 @@
 package api4
 
 import (
+	"bytes"
 	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
 )
 
 func (api *API) InitImage() {
 	api.BaseRoutes.Image.Handle("", api.ApiSessionRequiredTrustRequester(getImage)).Methods("GET")
+	api.BaseRoutes.Image.Handle("/thumbnails/{name}", api.ApiSessionRequiredTrustRequester(getLinkPreviewThumbnail)).Methods("GET")
 }
 
 func getImage(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -21,3 +26,23 @@ func getImage(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	http.NotFound(w, r)
 }
+
+func getLinkPreviewThumbnail(c *Context, w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	data, err := c.App.GetLinkPreviewThumbnail(name)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	contentType := "image/png"
+	if path.Ext(name) == ".jpg" {
+		contentType = "image/jpeg"
+	}
+
+	if err := writeFileResponse(name, contentType, int64(len(data)), bytes.NewReader(data), false, w, r); err != nil {
+		c.Err = err
+		return
+	}
+}
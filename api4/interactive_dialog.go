@@ -0,0 +1,48 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitInteractiveDialog() {
+	api.BaseRoutes.ApiRoot.Handle("/actions/dialogs/submit", api.ApiSessionRequired(submitDialog)).Methods("POST")
+}
+
+func submitDialog(c *Context, w http.ResponseWriter, r *http.Request) {
+	submit := model.SubmitDialogRequestFromJson(r.Body)
+	if submit == nil {
+		c.SetInvalidParam("dialog")
+		return
+	}
+
+	if submit.Dialog == nil {
+		c.SetInvalidParam("dialog.dialog")
+		return
+	}
+
+	if submit.TeamId != "" && !c.App.SessionHasPermissionToTeam(c.Session, submit.TeamId, model.PERMISSION_VIEW_TEAM) {
+		c.SetPermissionError(model.PERMISSION_VIEW_TEAM)
+		return
+	}
+
+	if submit.ChannelId != "" && !c.App.SessionHasPermissionToChannel(c.Session, submit.ChannelId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	// Dialogs are signed for the user that they were opened for, so nobody else can submit them
+	submit.UserId = c.Session.UserId
+
+	response, err := c.App.SubmitInteractiveDialog(submit, c.T)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(response.ToJson()))
+}
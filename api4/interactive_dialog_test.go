@@ -0,0 +1,204 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestSubmitInteractiveDialog(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	enableCommands := *th.App.Config().ServiceSettings.EnableCommands
+	allowedInternalConnections := *th.App.Config().ServiceSettings.AllowedUntrustedInternalConnections
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableCommands = &enableCommands })
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.ServiceSettings.AllowedUntrustedInternalConnections = &allowedInternalConnections
+		})
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableCommands = true })
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowedUntrustedInternalConnections = "127.0.0.0/8" })
+
+	dialog := &model.Dialog{
+		CallbackId: "callback",
+		Title:      "Report",
+		State:      "state",
+		Elements: []model.DialogElement{
+			{DisplayName: "Name", Name: "name", Type: model.DIALOG_ELEMENT_TYPE_TEXT},
+			{DisplayName: "Email", Name: "email", Type: model.DIALOG_ELEMENT_TYPE_TEXT, SubType: model.DIALOG_TEXT_SUBTYPE_EMAIL, Optional: true},
+		},
+	}
+
+	submissions := make(chan *model.SubmitDialogRequest, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		// The command opens the dialog, which is then submitted to the same URL
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.Write([]byte((&model.CommandResponse{Dialog: dialog}).ToJson()))
+			return
+		}
+
+		submission := model.SubmitDialogRequestFromJson(r.Body)
+		submissions <- submission
+
+		if submission.Submission["name"] == "taken" {
+			w.Write([]byte((&model.SubmitDialogResponse{Errors: map[string]string{"name": "taken"}}).ToJson()))
+		}
+	}))
+	defer ts.Close()
+
+	_, err := th.App.CreateCommand(&model.Command{
+		CreatorId: th.BasicUser.Id,
+		TeamId:    th.BasicTeam.Id,
+		URL:       ts.URL,
+		Method:    model.COMMAND_METHOD_POST,
+		Trigger:   "dialog",
+	})
+	require.Nil(t, err)
+
+	WebSocketClient, wsErr := th.CreateWebSocketClient()
+	require.Nil(t, wsErr)
+	WebSocketClient.Listen()
+	defer WebSocketClient.Close()
+
+	_, resp := Client.ExecuteCommand(th.BasicChannel.Id, "/dialog")
+	CheckNoError(t, resp)
+
+	var opened *model.OpenDialogRequest
+	timeout := time.After(5 * time.Second)
+	for opened == nil {
+		select {
+		case event := <-WebSocketClient.EventChannel:
+			if event.Event == model.WEBSOCKET_EVENT_OPEN_DIALOG {
+				opened = model.OpenDialogRequestFromJson(strings.NewReader(event.Data["dialog"].(string)))
+			}
+		case <-timeout:
+			t.Fatal("should have opened the dialog")
+		}
+	}
+	require.Equal(t, ts.URL, opened.URL)
+	require.Equal(t, dialog, opened.Dialog)
+
+	newSubmission := func(submission map[string]interface{}) model.SubmitDialogRequest {
+		return model.SubmitDialogRequest{
+			URL:        opened.URL,
+			ChannelId:  th.BasicChannel.Id,
+			TeamId:     th.BasicTeam.Id,
+			Submission: submission,
+			Dialog:     model.DialogFromJson(strings.NewReader(opened.Dialog.ToJson())),
+			Signature:  opened.Signature,
+		}
+	}
+
+	t.Run("valid submission", func(t *testing.T) {
+		response, resp := Client.SubmitInteractiveDialog(newSubmission(map[string]interface{}{"name": "Jane"}))
+		CheckNoError(t, resp)
+		assert.Empty(t, response.Errors)
+
+		submission := <-submissions
+		assert.Equal(t, th.BasicUser.Id, submission.UserId)
+		assert.Equal(t, "callback", submission.CallbackId)
+		assert.Equal(t, "state", submission.State)
+		assert.Equal(t, map[string]interface{}{"name": "Jane"}, submission.Submission)
+		assert.Empty(t, submission.URL)
+		assert.Nil(t, submission.Dialog)
+		assert.Empty(t, submission.Signature)
+	})
+
+	t.Run("invalid submission", func(t *testing.T) {
+		response, resp := Client.SubmitInteractiveDialog(newSubmission(map[string]interface{}{"email": "jane", "other": "value"}))
+		CheckNoError(t, resp)
+		assert.Len(t, response.Errors, 3)
+		assert.Contains(t, response.Errors, "name")
+		assert.Contains(t, response.Errors, "email")
+		assert.Contains(t, response.Errors, "other")
+
+		assert.Len(t, submissions, 0, "should not have passed the submission on to the integration")
+	})
+
+	t.Run("rejected by the integration", func(t *testing.T) {
+		response, resp := Client.SubmitInteractiveDialog(newSubmission(map[string]interface{}{"name": "taken"}))
+		CheckNoError(t, resp)
+		assert.Equal(t, map[string]string{"name": "taken"}, response.Errors)
+
+		<-submissions
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		request := newSubmission(nil)
+		request.Cancelled = true
+
+		response, resp := Client.SubmitInteractiveDialog(request)
+		CheckNoError(t, resp)
+		assert.Empty(t, response.Errors)
+
+		submission := <-submissions
+		assert.True(t, submission.Cancelled)
+		assert.Equal(t, "callback", submission.CallbackId)
+	})
+
+	t.Run("changed dialog", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{})
+		request.Dialog.Elements[0].Optional = true
+
+		_, resp := Client.SubmitInteractiveDialog(request)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("changed url", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{"name": "Jane"})
+		request.URL = ts.URL + "/other"
+
+		_, resp := Client.SubmitInteractiveDialog(request)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{"name": "Jane"})
+		request.Signature = ""
+
+		_, resp := Client.SubmitInteractiveDialog(request)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{"name": "Jane"})
+		request.ChannelId = ""
+		request.TeamId = ""
+
+		_, resp := th.SystemAdminClient.SubmitInteractiveDialog(request)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("channel without access", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{"name": "Jane"})
+		request.ChannelId = th.CreateChannelWithClient(th.SystemAdminClient, model.CHANNEL_PRIVATE).Id
+
+		_, resp := Client.SubmitInteractiveDialog(request)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("missing dialog", func(t *testing.T) {
+		request := newSubmission(map[string]interface{}{"name": "Jane"})
+		request.Dialog = nil
+
+		_, resp := Client.SubmitInteractiveDialog(request)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	assert.Len(t, submissions, 0)
+}
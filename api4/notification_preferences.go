@@ -0,0 +1,62 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitNotificationPreferences() {
+	api.BaseRoutes.User.Handle("/notification_preferences", api.ApiSessionRequired(exportNotificationPreferences)).Methods("GET")
+	api.BaseRoutes.User.Handle("/notification_preferences", api.ApiSessionRequired(importNotificationPreferences)).Methods("PUT")
+}
+
+func exportNotificationPreferences(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	preferences, err := c.App.ExportNotificationPreferences(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(preferences.ToJson()))
+}
+
+func importNotificationPreferences(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	preferences := model.NotificationPreferencesFromJson(r.Body)
+	if preferences == nil {
+		c.SetInvalidParam("notification_preferences")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	result, err := c.App.ImportNotificationPreferences(c.Params.UserId, preferences)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("from_user_id=" + preferences.UserId)
+	w.Write([]byte(result.ToJson()))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	if _, err := th.App.UpdateChannelMemberNotifyProps(map[string]string{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_NONE}, th.BasicChannel.Id, th.BasicUser.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	preferences, resp := Client.ExportNotificationPreferences(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if preferences.UserId != th.BasicUser.Id || len(preferences.Channels) == 0 {
+		t.Fatal("should have exported the user's preferences")
+	}
+
+	_, resp = Client.ExportNotificationPreferences(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.ImportNotificationPreferences(th.BasicUser2.Id, preferences)
+	CheckForbiddenStatus(t, resp)
+
+	result, resp := Client.ImportNotificationPreferences(th.BasicUser.Id, preferences)
+	CheckNoError(t, resp)
+
+	if result.ChannelsUpdated != len(preferences.Channels) || len(result.ChannelsSkipped) != 0 {
+		t.Fatal("should have restored every channel")
+	}
+
+	_, resp = th.SystemAdminClient.ImportNotificationPreferences(th.BasicUser2.Id, preferences)
+	CheckNoError(t, resp)
+
+	member, err := th.App.GetChannelMember(th.BasicChannel.Id, th.BasicUser2.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if member.NotifyProps[model.DESKTOP_NOTIFY_PROP] != model.CHANNEL_NOTIFY_NONE {
+		t.Fatal("should have applied the channel preferences to the other user")
+	}
+
+	preferences.Preferences = model.Preferences{{Category: model.PREFERENCE_CATEGORY_THEME, Name: "", Value: "{}"}}
+	_, resp = th.SystemAdminClient.ImportNotificationPreferences(th.BasicUser2.Id, preferences)
+	CheckBadRequestStatus(t, resp)
+}
@@ -4,9 +4,13 @@
 package api4
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/mattermost-server/app"
@@ -23,6 +27,11 @@ func (api *API) InitOAuth() {
 	api.BaseRoutes.OAuthApp.Handle("/info", api.ApiSessionRequired(getOAuthAppInfo)).Methods("GET")
 	api.BaseRoutes.OAuthApp.Handle("", api.ApiSessionRequired(deleteOAuthApp)).Methods("DELETE")
 	api.BaseRoutes.OAuthApp.Handle("/regen_secret", api.ApiSessionRequired(regenerateOAuthAppSecret)).Methods("POST")
+	api.BaseRoutes.OAuthApp.Handle("/patch", api.ApiSessionRequired(patchOAuthApp)).Methods("PUT")
+	api.BaseRoutes.OAuthApp.Handle("/icon", api.ApiSessionRequiredTrustRequester(getOAuthAppIcon)).Methods("GET")
+	api.BaseRoutes.OAuthApp.Handle("/icon", api.ApiSessionRequired(setOAuthAppIcon)).Methods("POST")
+	api.BaseRoutes.OAuthApp.Handle("/icon", api.ApiSessionRequired(removeOAuthAppIcon)).Methods("DELETE")
+	api.BaseRoutes.OAuth.Handle("/scopes", api.ApiSessionRequired(getOAuthScopes)).Methods("GET")
 
 	api.BaseRoutes.User.Handle("/oauth/apps/authorized", api.ApiSessionRequired(getAuthorizedOAuthApps)).Methods("GET")
 
@@ -247,6 +256,144 @@ func regenerateOAuthAppSecret(c *Context, w http.ResponseWriter, r *http.Request
 	w.Write([]byte(oauthApp.ToJson()))
 }
 
+// getOAuthAppForSession gets the app in the request's URL, checking that the session can manage it.
+func getOAuthAppForSession(c *Context) *model.OAuthApp {
+	c.RequireAppId()
+	if c.Err != nil {
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OAUTH) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OAUTH)
+		return nil
+	}
+
+	oauthApp, err := c.App.GetOAuthApp(c.Params.AppId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if oauthApp.CreatorId != c.Session.UserId && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH)
+		return nil
+	}
+
+	return oauthApp
+}
+
+func patchOAuthApp(c *Context, w http.ResponseWriter, r *http.Request) {
+	patch := model.OAuthAppPatchFromJson(r.Body)
+	if patch == nil {
+		c.SetInvalidParam("oauth_app")
+		return
+	}
+
+	c.LogAudit("attempt")
+
+	oauthApp := getOAuthAppForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	patchedApp, err := c.App.PatchOAuthApp(oauthApp, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success")
+	w.Write([]byte(patchedApp.ToJson()))
+}
+
+// getOAuthAppIcon returns the icon that was uploaded for an app. Any user can get it since it's shown to users who are
+// asked to authorize the app.
+func getOAuthAppIcon(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireAppId()
+	if c.Err != nil {
+		return
+	}
+
+	oauthApp, err := c.App.GetOAuthApp(c.Params.AppId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	etag := strconv.FormatInt(oauthApp.LastIconUpdate, 10)
+
+	if c.HandleEtag(etag, "Get OAuth App Icon", w, r) {
+		return
+	}
+
+	img, err := c.App.GetOAuthAppIcon(oauthApp)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%v, public", 24*60*60)) // 24 hrs
+	w.Header().Set(model.HEADER_ETAG_SERVER, etag)
+	w.Write(img)
+}
+
+func setOAuthAppIcon(c *Context, w http.ResponseWriter, r *http.Request) {
+	defer io.Copy(ioutil.Discard, r.Body)
+
+	oauthApp := getOAuthAppForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	if r.ContentLength > *c.App.Config().FileSettings.MaxFileSize {
+		c.Err = model.NewAppError("setOAuthAppIcon", "api.oauth.set_oauth_app_icon.too_large.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(*c.App.Config().FileSettings.MaxFileSize); err != nil {
+		c.Err = model.NewAppError("setOAuthAppIcon", "api.oauth.set_oauth_app_icon.parse.app_error", nil, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imageArray, ok := r.MultipartForm.File["image"]
+	if !ok || len(imageArray) == 0 {
+		c.Err = model.NewAppError("setOAuthAppIcon", "api.oauth.set_oauth_app_icon.no_file.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	updatedApp, err := c.App.SetOAuthAppIcon(oauthApp, imageArray[0])
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("app_id=" + updatedApp.Id)
+	w.Write([]byte(updatedApp.ToJson()))
+}
+
+func removeOAuthAppIcon(c *Context, w http.ResponseWriter, r *http.Request) {
+	oauthApp := getOAuthAppForSession(c)
+	if c.Err != nil {
+		return
+	}
+
+	updatedApp, err := c.App.RemoveOAuthAppIcon(oauthApp)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("app_id=" + updatedApp.Id)
+	w.Write([]byte(updatedApp.ToJson()))
+}
+
+// getOAuthScopes describes the scopes that apps can request so that users can be asked whether to allow them. Only the
+// scopes that were requested are described if the scope parameter is given.
+func getOAuthScopes(c *Context, w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(model.OAuthScopeListToJson(c.App.GetOAuthScopes(r.URL.Query().Get("scope"), c.T))))
+}
+
 func getAuthorizedOAuthApps(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -330,6 +477,9 @@ func authorizeOAuthPage(c *Context, w http.ResponseWriter, r *http.Request) {
 		RedirectUri:  r.URL.Query().Get("redirect_uri"),
 		Scope:        r.URL.Query().Get("scope"),
 		State:        r.URL.Query().Get("state"),
+
+		CodeChallenge:       r.URL.Query().Get("code_challenge"),
+		CodeChallengeMethod: r.URL.Query().Get("code_challenge_method"),
 	}
 
 	loginHint := r.URL.Query().Get("login_hint")
@@ -363,9 +513,9 @@ func authorizeOAuthPage(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	isAuthorized := false
 
-	if _, err := c.App.GetPreferenceByCategoryAndNameForUser(c.Session.UserId, model.PREFERENCE_CATEGORY_AUTHORIZED_OAUTH_APP, authRequest.ClientId); err == nil {
-		// when we support scopes we should check if the scopes match
-		isAuthorized = true
+	// Users are asked again when apps request scopes that they weren't allowed before
+	if pref, err := c.App.GetPreferenceByCategoryAndNameForUser(c.Session.UserId, model.PREFERENCE_CATEGORY_AUTHORIZED_OAUTH_APP, authRequest.ClientId); err == nil {
+		isAuthorized = model.OAuthScopeIncludes(pref.Value, authRequest.Scope)
 	}
 
 	// Automatically allow if the app is trusted
@@ -419,17 +569,15 @@ func getAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Public apps give a code verifier instead of their secret
 	secret := r.FormValue("client_secret")
-	if len(secret) == 0 {
-		c.Err = model.NewAppError("getAccessToken", "api.oauth.get_access_token.bad_client_secret.app_error", nil, "", http.StatusBadRequest)
-		return
-	}
+	codeVerifier := r.FormValue("code_verifier")
 
 	redirectUri := r.FormValue("redirect_uri")
 
 	c.LogAudit("attempt")
 
-	accessRsp, err := c.App.GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, code, secret, refreshToken)
+	accessRsp, err := c.App.GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, code, secret, codeVerifier, refreshToken)
 	if err != nil {
 		c.Err = err
 		return
@@ -5,6 +5,7 @@ package api4
 
 import (
 	"encoding/base64"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -580,6 +581,182 @@ func TestRegenerateOAuthAppSecret(t *testing.T) {
 	CheckNotImplementedStatus(t, resp)
 }
 
+func TestPatchOAuthApp(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+	AdminClient := th.SystemAdminClient
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	enableOAuthServiceProvider := th.App.Config().ServiceSettings.EnableOAuthServiceProvider
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = enableOAuthServiceProvider })
+	}()
+
+	// Grant permission to regular users.
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_OAUTH.Id, model.SYSTEM_USER_ROLE_ID)
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	oapp := &model.OAuthApp{Name: GenerateTestAppName(), Homepage: "https://nowhere.com", Description: "test", CallbackUrls: []string{"https://nowhere.com"}}
+
+	adminApp, resp := AdminClient.CreateOAuthApp(oapp)
+	CheckNoError(t, resp)
+
+	oapp.Name = GenerateTestAppName()
+	userApp, resp := Client.CreateOAuthApp(oapp)
+	CheckNoError(t, resp)
+
+	patch := &model.OAuthAppPatch{
+		Description:  model.NewString("patched"),
+		CallbackUrls: &model.StringArray{"https://nowhere.com/callback"},
+	}
+
+	patchedApp, resp := Client.PatchOAuthApp(userApp.Id, patch)
+	CheckNoError(t, resp)
+	assert.Equal(t, "patched", patchedApp.Description)
+	assert.Equal(t, model.StringArray{"https://nowhere.com/callback"}, patchedApp.CallbackUrls)
+	assert.Equal(t, userApp.Name, patchedApp.Name)
+	assert.Equal(t, userApp.Homepage, patchedApp.Homepage)
+	assert.Equal(t, userApp.ClientSecret, patchedApp.ClientSecret)
+
+	_, resp = Client.PatchOAuthApp(userApp.Id, &model.OAuthAppPatch{CallbackUrls: &model.StringArray{}})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.PatchOAuthApp(adminApp.Id, patch)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = AdminClient.PatchOAuthApp(userApp.Id, patch)
+	CheckNoError(t, resp)
+
+	_, resp = AdminClient.PatchOAuthApp(model.NewId(), patch)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = AdminClient.PatchOAuthApp("junk", patch)
+	CheckBadRequestStatus(t, resp)
+
+	// Revoke permission from regular users.
+	th.RemovePermissionFromRole(model.PERMISSION_MANAGE_OAUTH.Id, model.SYSTEM_USER_ROLE_ID)
+
+	_, resp = Client.PatchOAuthApp(userApp.Id, patch)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.PatchOAuthApp(userApp.Id, patch)
+	CheckUnauthorizedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = false })
+	_, resp = AdminClient.PatchOAuthApp(adminApp.Id, patch)
+	CheckNotImplementedStatus(t, resp)
+}
+
+func TestOAuthAppIcon(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+	AdminClient := th.SystemAdminClient
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	enableOAuthServiceProvider := th.App.Config().ServiceSettings.EnableOAuthServiceProvider
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = enableOAuthServiceProvider })
+	}()
+
+	// Grant permission to regular users.
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_OAUTH.Id, model.SYSTEM_USER_ROLE_ID)
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	data, err := readTestFile("test.png")
+	require.Nil(t, err)
+
+	oapp := &model.OAuthApp{Name: GenerateTestAppName(), Homepage: "https://nowhere.com", Description: "test", CallbackUrls: []string{"https://nowhere.com"}}
+
+	adminApp, resp := AdminClient.CreateOAuthApp(oapp)
+	CheckNoError(t, resp)
+
+	oapp.Name = GenerateTestAppName()
+	userApp, resp := Client.CreateOAuthApp(oapp)
+	CheckNoError(t, resp)
+
+	t.Run("get before an icon is uploaded", func(t *testing.T) {
+		_, resp := Client.GetOAuthAppIcon(userApp.Id, "")
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("upload and get", func(t *testing.T) {
+		updatedApp, resp := Client.SetOAuthAppIcon(userApp.Id, data)
+		CheckNoError(t, resp)
+		require.NotZero(t, updatedApp.LastIconUpdate)
+
+		icon, resp := th.Client.GetOAuthAppIcon(userApp.Id, "")
+		CheckNoError(t, resp)
+		require.NotEmpty(t, icon)
+
+		_, resp = th.Client.GetOAuthAppIcon(userApp.Id, resp.Etag)
+		require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+		// Any user can see the icon of an app that they're asked to authorize
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp = th.Client.GetOAuthAppIcon(userApp.Id, "")
+		CheckNoError(t, resp)
+	})
+
+	t.Run("updating the app keeps the icon", func(t *testing.T) {
+		_, resp := Client.PatchOAuthApp(userApp.Id, &model.OAuthAppPatch{Description: model.NewString("patched")})
+		CheckNoError(t, resp)
+
+		app, resp := Client.GetOAuthApp(userApp.Id)
+		CheckNoError(t, resp)
+		require.NotZero(t, app.LastIconUpdate)
+	})
+
+	t.Run("invalid image", func(t *testing.T) {
+		_, resp := Client.SetOAuthAppIcon(userApp.Id, []byte("not an image"))
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		_, resp := Client.SetOAuthAppIcon(adminApp.Id, data)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = Client.RemoveOAuthAppIcon(adminApp.Id)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = AdminClient.SetOAuthAppIcon(userApp.Id, data)
+		CheckNoError(t, resp)
+
+		_, resp = AdminClient.SetOAuthAppIcon(model.NewId(), data)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		updatedApp, resp := Client.RemoveOAuthAppIcon(userApp.Id)
+		CheckNoError(t, resp)
+		require.Zero(t, updatedApp.LastIconUpdate)
+
+		_, resp = Client.GetOAuthAppIcon(userApp.Id, "")
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("oauth service provider disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+		_, resp := AdminClient.SetOAuthAppIcon(adminApp.Id, data)
+		CheckNotImplementedStatus(t, resp)
+
+		_, resp = AdminClient.RemoveOAuthAppIcon(adminApp.Id)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	Client.Logout()
+	_, resp = Client.SetOAuthAppIcon(userApp.Id, data)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetAuthorizedOAuthAppsForUser(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -989,6 +1166,218 @@ func TestOAuthAccessToken(t *testing.T) {
 	Client.ClearOAuthToken()
 }
 
+func TestOAuthAccessTokenWithPKCE(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	Client := th.Client
+
+	enableOAuth := th.App.Config().ServiceSettings.EnableOAuthServiceProvider
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = enableOAuth })
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_OAUTH.Id, model.SYSTEM_USER_ROLE_ID)
+
+	oauthApp := &model.OAuthApp{Name: "TestApp" + model.NewId(), Homepage: "https://nowhere.com", Description: "test", CallbackUrls: []string{"https://nowhere.com"}, IsPublic: true}
+	oauthApp = Client.Must(Client.CreateOAuthApp(oauthApp)).(*model.OAuthApp)
+	require.True(t, oauthApp.IsPublic)
+
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	authRequest := &model.AuthorizeRequest{
+		ResponseType: model.AUTHCODE_RESPONSE_TYPE,
+		ClientId:     oauthApp.Id,
+		RedirectUri:  oauthApp.CallbackUrls[0],
+		Scope:        "all",
+		State:        "123",
+	}
+
+	redirect, resp := Client.AuthorizeOAuthApp(authRequest)
+	CheckNoError(t, resp)
+	rurl, _ := url.Parse(redirect)
+	assert.Equal(t, "invalid_request", rurl.Query().Get("error"), "should require public apps to give a code challenge")
+	assert.Empty(t, rurl.Query().Get("code"))
+
+	authRequest.CodeChallenge = challenge
+	authRequest.CodeChallengeMethod = "junk"
+	_, resp = Client.AuthorizeOAuthApp(authRequest)
+	CheckBadRequestStatus(t, resp)
+
+	authRequest.CodeChallengeMethod = model.PKCE_METHOD_S256
+	redirect, resp = Client.AuthorizeOAuthApp(authRequest)
+	CheckNoError(t, resp)
+	rurl, _ = url.Parse(redirect)
+	code := rurl.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	Client.Logout()
+
+	data := url.Values{"grant_type": []string{model.ACCESS_TOKEN_GRANT_TYPE}, "client_id": []string{oauthApp.Id}, "code": []string{code}, "redirect_uri": []string{oauthApp.CallbackUrls[0]}}
+
+	_, resp = Client.GetOAuthAccessToken(data)
+	CheckBadRequestStatus(t, resp)
+
+	data.Set("code_verifier", "junk"+verifier)
+	_, resp = Client.GetOAuthAccessToken(data)
+	CheckBadRequestStatus(t, resp)
+
+	data.Set("code_verifier", verifier)
+	token, resp := Client.GetOAuthAccessToken(data)
+	CheckNoError(t, resp)
+	require.NotEmpty(t, token.AccessToken)
+	require.NotEmpty(t, token.RefreshToken)
+
+	Client.AuthToken = token.AccessToken
+	Client.AuthType = model.HEADER_TOKEN
+	user, resp := Client.GetMe("")
+	CheckNoError(t, resp)
+	assert.Equal(t, th.BasicUser.Id, user.Id)
+
+	data = url.Values{"grant_type": []string{model.REFRESH_TOKEN_GRANT_TYPE}, "client_id": []string{oauthApp.Id}, "refresh_token": []string{token.RefreshToken}, "redirect_uri": []string{oauthApp.CallbackUrls[0]}}
+	_, resp = Client.GetOAuthAccessToken(data)
+	CheckNoError(t, resp)
+
+	t.Run("confidential apps", func(t *testing.T) {
+		th.LoginBasic()
+
+		confidentialApp := &model.OAuthApp{Name: "TestApp" + model.NewId(), Homepage: "https://nowhere.com", Description: "test", CallbackUrls: []string{"https://nowhere.com"}}
+		confidentialApp = Client.Must(Client.CreateOAuthApp(confidentialApp)).(*model.OAuthApp)
+
+		authRequest := &model.AuthorizeRequest{
+			ResponseType:  model.AUTHCODE_RESPONSE_TYPE,
+			ClientId:      confidentialApp.Id,
+			RedirectUri:   confidentialApp.CallbackUrls[0],
+			State:         "123",
+			CodeChallenge: verifier,
+		}
+
+		redirect, resp := Client.AuthorizeOAuthApp(authRequest)
+		CheckNoError(t, resp)
+		rurl, _ := url.Parse(redirect)
+
+		data := url.Values{"grant_type": []string{model.ACCESS_TOKEN_GRANT_TYPE}, "client_id": []string{confidentialApp.Id}, "code": []string{rurl.Query().Get("code")}, "redirect_uri": []string{confidentialApp.CallbackUrls[0]}, "code_verifier": []string{verifier}}
+
+		_, resp = Client.GetOAuthAccessToken(data)
+		CheckBadRequestStatus(t, resp)
+
+		data.Set("client_secret", confidentialApp.ClientSecret)
+		_, resp = Client.GetOAuthAccessToken(data)
+		CheckNoError(t, resp)
+	})
+}
+
+func TestOAuthScopes(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	Client := th.Client
+
+	enableOAuth := th.App.Config().ServiceSettings.EnableOAuthServiceProvider
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = enableOAuth })
+	}()
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_OAUTH.Id, model.SYSTEM_USER_ROLE_ID)
+
+	scopes, resp := Client.GetOAuthScopes("")
+	CheckNoError(t, resp)
+	require.Len(t, scopes, len(model.OAuthScopes))
+	assert.Equal(t, model.OAUTH_SCOPE_READ_USERS, scopes[0].Name)
+	assert.NotEmpty(t, scopes[0].Description)
+
+	scopes, resp = Client.GetOAuthScopes("read:posts junk")
+	CheckNoError(t, resp)
+	require.Len(t, scopes, 1, "should only describe the requested scopes")
+	assert.Equal(t, model.OAUTH_SCOPE_READ_POSTS, scopes[0].Name)
+
+	oauthApp := &model.OAuthApp{Name: "TestApp" + model.NewId(), Homepage: "https://nowhere.com", Description: "test", CallbackUrls: []string{"https://nowhere.com"}}
+	oauthApp = Client.Must(Client.CreateOAuthApp(oauthApp)).(*model.OAuthApp)
+
+	authRequest := &model.AuthorizeRequest{
+		ResponseType: model.AUTHCODE_RESPONSE_TYPE,
+		ClientId:     oauthApp.Id,
+		RedirectUri:  oauthApp.CallbackUrls[0],
+		Scope:        model.OAUTH_SCOPE_READ_USERS + " " + model.OAUTH_SCOPE_READ_POSTS,
+		State:        "123",
+	}
+
+	redirect, resp := Client.AuthorizeOAuthApp(authRequest)
+	CheckNoError(t, resp)
+	rurl, _ := url.Parse(redirect)
+
+	data := url.Values{"grant_type": []string{model.ACCESS_TOKEN_GRANT_TYPE}, "client_id": []string{oauthApp.Id}, "client_secret": []string{oauthApp.ClientSecret}, "code": []string{rurl.Query().Get("code")}, "redirect_uri": []string{oauthApp.CallbackUrls[0]}}
+	token, resp := Client.GetOAuthAccessToken(data)
+	CheckNoError(t, resp)
+	assert.Equal(t, authRequest.Scope, token.Scope)
+
+	appClient := th.CreateClient()
+	appClient.AuthToken = token.AccessToken
+	appClient.AuthType = model.HEADER_TOKEN
+
+	_, resp = appClient.GetMe("")
+	CheckNoError(t, resp)
+
+	_, resp = appClient.GetPostsForChannel(th.BasicChannel.Id, 0, 10, "")
+	CheckNoError(t, resp)
+
+	_, resp = appClient.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "from an app"})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = appClient.GetChannel(th.BasicChannel.Id, "")
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = appClient.GetSessions(model.ME, "")
+	CheckForbiddenStatus(t, resp)
+
+	_, err := model.NewWebSocketClient4(fmt.Sprintf("ws://localhost:%v", th.App.Srv.ListenAddr.Port), appClient.AuthToken)
+	assert.NotNil(t, err, "apps without the default scope shouldn't be able to connect to the websocket")
+
+	t.Run("authorizing a wider scope replaces the token", func(t *testing.T) {
+		authRequest.Scope = model.OAUTH_SCOPE_WRITE_USERS + " " + model.OAUTH_SCOPE_WRITE_POSTS
+
+		redirect, resp := Client.AuthorizeOAuthApp(authRequest)
+		CheckNoError(t, resp)
+		rurl, _ := url.Parse(redirect)
+
+		data.Set("code", rurl.Query().Get("code"))
+		token, resp := Client.GetOAuthAccessToken(data)
+		CheckNoError(t, resp)
+		appClient.AuthToken = token.AccessToken
+
+		_, resp = appClient.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "from an app"})
+		CheckNoError(t, resp)
+
+		_, resp = appClient.PatchUser(model.ME, &model.UserPatch{Nickname: model.NewString("app")})
+		CheckNoError(t, resp)
+	})
+
+	t.Run("credentials, roles and sessions need the default scope", func(t *testing.T) {
+		_, resp := appClient.CreateUserAccessToken(model.ME, "from an app")
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = appClient.UpdateUserPassword(model.ME, th.BasicUser.Password, "newpassword1")
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = appClient.UpdateUserRoles(model.ME, model.SYSTEM_USER_ROLE_ID+" "+model.SYSTEM_ADMIN_ROLE_ID)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp = appClient.RevokeAllSessions(model.ME)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestOAuthComplete(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
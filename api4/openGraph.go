@@ -4,7 +4,9 @@
 package api4
 
 import (
+	"encoding/json"
 	"net/http"
+	"reflect"
 
 	"github.com/dyatlov/go-opengraph/opengraph"
 	"github.com/mattermost/mattermost-server/model"
@@ -24,6 +26,23 @@ func (api *API) InitOpenGraph() {
 			(before.ServiceSettings.ImageProxyURL != after.ServiceSettings.ImageProxyType) {
 			openGraphDataCache.Purge()
 		}
+
+		// Images are replaced by thumbnails once they're enabled
+		if *before.ServiceSettings.EnableLinkPreviewThumbnails != *after.ServiceSettings.EnableLinkPreviewThumbnails ||
+			*before.ServiceSettings.LinkPreviewThumbnailWidth != *after.ServiceSettings.LinkPreviewThumbnailWidth {
+			openGraphDataCache.Purge()
+		}
+
+		// Previews come from somewhere else once link previews go offline or their fixtures change
+		if *before.ServiceSettings.LinkPreviewMode != *after.ServiceSettings.LinkPreviewMode ||
+			*before.ServiceSettings.LinkPreviewFixturesFile != *after.ServiceSettings.LinkPreviewFixturesFile {
+			openGraphDataCache.Purge()
+		}
+
+		// Issue links are unfurled differently once their provider is enabled or disabled
+		if !reflect.DeepEqual(before.IssueUnfurlSettings, after.IssueUnfurlSettings) {
+			openGraphDataCache.Purge()
+		}
 	})
 }
 
@@ -64,14 +83,14 @@ func getOpenGraphMetadata(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	og := c.App.GetOpenGraphMetadata(url)
+	preview := c.App.GetLinkPreview(url)
 
 	// If image proxy enabled modify open graph data to feed though proxy
 	if toProxyURL := c.App.ImageProxyAdder(); toProxyURL != nil {
-		og = OpenGraphDataWithProxyAddedToImageURLs(og, toProxyURL)
+		preview.OpenGraph = OpenGraphDataWithProxyAddedToImageURLs(preview.OpenGraph, toProxyURL)
 	}
 
-	ogJSON, err := og.ToJSON()
+	ogJSON, err := json.Marshal(preview)
 	openGraphDataCache.AddWithExpiresInSecs(props["url"], ogJSON, 3600) // Cache would expire after 1 hour
 	if err != nil {
 		w.Write([]byte(`{"url": ""}`))
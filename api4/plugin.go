@@ -6,8 +6,12 @@
 package api4
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/websocket"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 )
@@ -26,8 +30,13 @@ func (api *API) InitPlugin() {
 	api.BaseRoutes.Plugins.Handle("/statuses", api.ApiSessionRequired(getPluginStatuses)).Methods("GET")
 	api.BaseRoutes.Plugin.Handle("/enable", api.ApiSessionRequired(enablePlugin)).Methods("POST")
 	api.BaseRoutes.Plugin.Handle("/disable", api.ApiSessionRequired(disablePlugin)).Methods("POST")
+	api.BaseRoutes.Plugin.Handle("/settings/options", api.ApiSessionRequired(getPluginSettingOptions)).Methods("GET")
+	api.BaseRoutes.Plugin.Handle("/logs", api.ApiSessionRequired(getPluginLogs)).Methods("GET")
 
 	api.BaseRoutes.Plugins.Handle("/webapp", api.ApiHandler(getWebappPlugins)).Methods("GET")
+
+	api.BaseRoutes.Plugins.Handle("/marketplace", api.ApiSessionRequired(getMarketplacePlugins)).Methods("GET")
+	api.BaseRoutes.Plugins.Handle("/marketplace", api.ApiSessionRequired(installMarketplacePlugin)).Methods("POST")
 }
 
 func uploadPlugin(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -42,7 +51,7 @@ func uploadPlugin(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := r.ParseMultipartForm(MAXIMUM_PLUGIN_FILE_SIZE); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.Err = model.NewAppError("uploadPlugin", "api.plugin.upload.parse.app_error", nil, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -66,7 +75,12 @@ func uploadPlugin(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	manifest, unpackErr := c.App.InstallPlugin(file, false)
+	force := false
+	if values, ok := m.Value["force"]; ok && len(values) > 0 {
+		force, _ = strconv.ParseBool(values[0])
+	}
+
+	manifest, unpackErr := c.App.InstallPlugin(file, force)
 
 	if unpackErr != nil {
 		c.Err = unpackErr
@@ -211,3 +225,146 @@ func disablePlugin(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	ReturnStatusOK(w)
 }
+
+func getPluginSettingOptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePluginId()
+	if c.Err != nil {
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if len(key) == 0 {
+		c.SetInvalidUrlParam("key")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	options, err := c.App.GetPluginSettingOptions(c.Params.PluginId, key)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.PluginOptionListToJson(options)))
+}
+
+func getPluginLogs(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePluginId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if follow, _ := strconv.ParseBool(r.URL.Query().Get("follow")); follow {
+		followPluginLogs(c, w, r)
+		return
+	}
+
+	entries, err := c.App.GetPluginLogs(c.Params.PluginId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte("[" + strings.Join(entries, ",") + "]"))
+}
+
+// followPluginLogs upgrades the request to a websocket and sends each entry in a plugin's log as a text message,
+// starting with the most recent entries, until the client disconnects.
+func followPluginLogs(c *Context, w http.ResponseWriter, r *http.Request) {
+	entries, newEntries, unsubscribe, err := c.App.FollowPluginLogs(c.Params.PluginId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	defer unsubscribe()
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  model.SOCKET_MAX_MESSAGE_SIZE_KB,
+		WriteBufferSize: model.SOCKET_MAX_MESSAGE_SIZE_KB,
+		CheckOrigin:     c.App.OriginChecker(),
+	}
+
+	ws, upgradeErr := upgrader.Upgrade(w, r, nil)
+	if upgradeErr != nil {
+		mlog.Error(fmt.Sprintf("websocket connect err: %v", upgradeErr))
+		c.Err = model.NewAppError("followPluginLogs", "api.web_socket.connect.upgrade.app_error", nil, "", http.StatusInternalServerError)
+		return
+	}
+	defer ws.Close()
+
+	// Nothing is expected from the client, but reading is how a disconnect is noticed
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, entry := range entries {
+		if err := ws.WriteMessage(websocket.TextMessage, []byte(entry)); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case entry := <-newEntries:
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(entry)); err != nil {
+				return
+			}
+		case <-disconnected:
+			return
+		}
+	}
+}
+
+func getMarketplacePlugins(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	plugins, err := c.App.GetMarketplacePlugins()
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.MarketplacePluginListToJson(plugins)))
+}
+
+func installMarketplacePlugin(c *Context, w http.ResponseWriter, r *http.Request) {
+	request := model.MarketplaceInstallRequestFromJson(r.Body)
+	if request == nil || len(request.Id) == 0 {
+		c.SetInvalidParam("id")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	manifest, err := c.App.InstallMarketplacePlugin(request.Id)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("plugin_id=" + manifest.Id)
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(manifest.ToJson()))
+}
@@ -5,7 +5,10 @@ package api4
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,6 +16,8 @@ import (
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
 )
 
 func TestPlugin(t *testing.T) {
@@ -186,3 +191,144 @@ func TestPlugin(t *testing.T) {
 	_, resp = th.SystemAdminClient.RemovePlugin("bad.id")
 	CheckBadRequestStatus(t, resp)
 }
+
+func TestMarketplacePlugins(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	path, _ := utils.FindDir("tests")
+	bundle, err := ioutil.ReadFile(filepath.Join(path, "testplugin.tar.gz"))
+	require.NoError(t, err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, bundle))
+
+	marketplaceDir, err := ioutil.TempDir("", "marketplace")
+	require.NoError(t, err)
+	defer os.RemoveAll(marketplaceDir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(marketplaceDir, "testplugin.tar.gz"), bundle, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(marketplaceDir, "marketplace.json"), []byte(model.MarketplacePluginListToJson([]*model.MarketplacePlugin{
+		{Id: "testplugin", Name: "Test Plugin", Version: "0.0.1", DownloadURL: "testplugin.tar.gz", Signature: signature},
+		{Id: "unsigned", Name: "Unsigned", Version: "0.0.1", DownloadURL: "testplugin.tar.gz", Signature: base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))},
+		{Id: "mislabeled", Name: "Mislabeled", Version: "0.0.1", DownloadURL: "testplugin.tar.gz", Signature: signature},
+	})), 0600))
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.PluginSettings.Enable = true
+		*cfg.PluginSettings.EnableMarketplace = false
+		*cfg.PluginSettings.MarketplaceURL = marketplaceDir
+		*cfg.PluginSettings.MarketplacePublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	})
+
+	_, resp := th.SystemAdminClient.GetMarketplacePlugins()
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PluginSettings.EnableMarketplace = true })
+
+	_, resp = th.Client.GetMarketplacePlugins()
+	CheckForbiddenStatus(t, resp)
+
+	plugins, resp := th.SystemAdminClient.GetMarketplacePlugins()
+	CheckNoError(t, resp)
+	require.Len(t, plugins, 3)
+	assert.Equal(t, "testplugin", plugins[0].Id)
+	assert.Empty(t, plugins[0].InstalledVersion)
+
+	_, resp = th.Client.InstallMarketplacePlugin("testplugin")
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.InstallMarketplacePlugin("junk")
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.InstallMarketplacePlugin("unsigned")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.InstallMarketplacePlugin("mislabeled")
+	CheckBadRequestStatus(t, resp)
+
+	manifest, resp := th.SystemAdminClient.InstallMarketplacePlugin("testplugin")
+	defer os.RemoveAll("plugins/testplugin")
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, "testplugin", manifest.Id)
+
+	plugins, resp = th.SystemAdminClient.GetMarketplacePlugins()
+	CheckNoError(t, resp)
+	assert.Equal(t, manifest.Version, plugins[0].InstalledVersion)
+
+	// Reinstalling replaces the installed version
+	_, resp = th.SystemAdminClient.InstallMarketplacePlugin("testplugin")
+	CheckNoError(t, resp)
+
+	ok, resp := th.SystemAdminClient.RemovePlugin(manifest.Id)
+	CheckNoError(t, resp)
+	assert.True(t, ok)
+}
+
+func TestGetPluginLogs(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.PluginSettings.Enable = true
+		*cfg.PluginSettings.EnableUploads = true
+	})
+
+	path, _ := utils.FindDir("tests")
+	file, err := os.Open(filepath.Join(path, "testplugin.tar.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	manifest, resp := th.SystemAdminClient.UploadPlugin(file)
+	defer os.RemoveAll("plugins/testplugin")
+	CheckNoError(t, resp)
+
+	_, resp = th.Client.GetPluginLogs(manifest.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetPluginLogs("junk")
+	CheckNotFoundStatus(t, resp)
+
+	th.App.Plugins.LogBuffer(manifest.Id).Write([]byte(`{"level":"info","msg":"hello"}` + "\n"))
+
+	entries, resp := th.SystemAdminClient.GetPluginLogs(manifest.Id)
+	CheckNoError(t, resp)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "hello", entries[len(entries)-1]["msg"])
+}
+
+func TestUploadPluginForced(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.PluginSettings.Enable = true
+		*cfg.PluginSettings.EnableUploads = true
+	})
+
+	path, _ := utils.FindDir("tests")
+	bundle, err := ioutil.ReadFile(filepath.Join(path, "testplugin.tar.gz"))
+	require.NoError(t, err)
+
+	manifest, resp := th.SystemAdminClient.UploadPlugin(bytes.NewReader(bundle))
+	defer os.RemoveAll("plugins/testplugin")
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.UploadPlugin(bytes.NewReader(bundle))
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UploadPluginForced(bytes.NewReader(bundle))
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.EnablePlugin(manifest.Id)
+	CheckNoError(t, resp)
+	require.True(t, th.App.Plugins.IsActive(manifest.Id))
+
+	// Uploading an active plugin upgrades it in place
+	upgradedManifest, resp := th.SystemAdminClient.UploadPluginForced(bytes.NewReader(bundle))
+	CheckNoError(t, resp)
+	assert.Equal(t, manifest.Id, upgradedManifest.Id)
+	assert.True(t, th.App.Plugins.IsActive(manifest.Id))
+}
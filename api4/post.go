@@ -13,21 +13,28 @@ import (
 )
 
 func (api *API) InitPost() {
-	api.BaseRoutes.Posts.Handle("", api.ApiSessionRequired(createPost)).Methods("POST")
-	api.BaseRoutes.Post.Handle("", api.ApiSessionRequired(getPost)).Methods("GET")
-	api.BaseRoutes.Post.Handle("", api.ApiSessionRequired(deletePost)).Methods("DELETE")
+	api.BaseRoutes.Posts.Handle("", api.ApiSessionRequiredWithScope(createPost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("POST")
+	api.BaseRoutes.Post.Handle("", api.ApiSessionRequiredWithScope(getPost, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.Post.Handle("", api.ApiSessionRequiredWithScope(deletePost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("DELETE")
 	api.BaseRoutes.Posts.Handle("/ephemeral", api.ApiSessionRequired(createEphemeralPost)).Methods("POST")
-	api.BaseRoutes.Post.Handle("/thread", api.ApiSessionRequired(getPostThread)).Methods("GET")
-	api.BaseRoutes.Post.Handle("/files/info", api.ApiSessionRequired(getFileInfosForPost)).Methods("GET")
-	api.BaseRoutes.PostsForChannel.Handle("", api.ApiSessionRequired(getPostsForChannel)).Methods("GET")
-	api.BaseRoutes.PostsForUser.Handle("/flagged", api.ApiSessionRequired(getFlaggedPostsForUser)).Methods("GET")
-
-	api.BaseRoutes.Team.Handle("/posts/search", api.ApiSessionRequired(searchPosts)).Methods("POST")
-	api.BaseRoutes.Post.Handle("", api.ApiSessionRequired(updatePost)).Methods("PUT")
-	api.BaseRoutes.Post.Handle("/patch", api.ApiSessionRequired(patchPost)).Methods("PUT")
+	api.BaseRoutes.Posts.Handle("/external/{service:[A-Za-z0-9_-]+}/{external_id:[^/]+}", api.ApiSessionRequired(getPostByRemoteId)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/thread", api.ApiSessionRequiredWithScope(getPostThread, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/files/info", api.ApiSessionRequiredWithScope(getFileInfosForPost, model.OAUTH_SCOPE_READ_FILES)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/push_notification", api.ApiSessionRequired(getPushNotificationForPost)).Methods("GET")
+	api.BaseRoutes.PostsForChannel.Handle("", api.ApiSessionRequiredWithScope(getPostsForChannel, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.PostsForChannel.Handle("/events", api.ApiSessionRequiredWithScope(getPostEventsForChannel, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.PostsForUser.Handle("/flagged", api.ApiSessionRequiredWithScope(getFlaggedPostsForUser, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+
+	api.BaseRoutes.Team.Handle("/posts/search", api.ApiSessionRequiredWithScope(searchPosts, model.OAUTH_SCOPE_READ_POSTS)).Methods("POST")
+	api.BaseRoutes.Post.Handle("", api.ApiSessionRequiredWithScope(updatePost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("PUT")
+	api.BaseRoutes.Post.Handle("/patch", api.ApiSessionRequiredWithScope(patchPost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("PUT")
 	api.BaseRoutes.Post.Handle("/actions/{action_id:[A-Za-z0-9]+}", api.ApiSessionRequired(doPostAction)).Methods("POST")
-	api.BaseRoutes.Post.Handle("/pin", api.ApiSessionRequired(pinPost)).Methods("POST")
-	api.BaseRoutes.Post.Handle("/unpin", api.ApiSessionRequired(unpinPost)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/actions/{action_id:[A-Za-z0-9]+}/options", api.ApiSessionRequired(getPostActionOptions)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/pin", api.ApiSessionRequiredWithScope(pinPost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/unpin", api.ApiSessionRequiredWithScope(unpinPost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/acknowledgements", api.ApiSessionRequiredWithScope(getPostAcknowledgements, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.PostForUser.Handle("/ack", api.ApiSessionRequiredWithScope(acknowledgePost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("POST")
+	api.BaseRoutes.PostForUser.Handle("/ack", api.ApiSessionRequiredWithScope(unacknowledgePost, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("DELETE")
 }
 
 func createPost(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -58,6 +65,11 @@ func createPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		post.CreateAt = 0
 	}
 
+	if post.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
 	rp, err := c.App.CreatePostAsUser(c.App.PostWithProxyRemovedFromImageURLs(post))
 	if err != nil {
 		c.Err = err
@@ -120,6 +132,24 @@ func getPostsForChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var cursor *model.PostCursor
+	var cursorBefore bool
+
+	if value := r.URL.Query().Get("before_cursor"); len(value) > 0 {
+		var ok bool
+		if cursor, ok = model.PostCursorFromString(value); !ok {
+			c.SetInvalidParam("before_cursor")
+			return
+		}
+		cursorBefore = true
+	} else if value := r.URL.Query().Get("after_cursor"); len(value) > 0 {
+		var ok bool
+		if cursor, ok = model.PostCursorFromString(value); !ok {
+			c.SetInvalidParam("after_cursor")
+			return
+		}
+	}
+
 	if !c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_READ_CHANNEL) {
 		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
 		return
@@ -130,7 +160,21 @@ func getPostsForChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	etag := ""
 
 	if since > 0 {
+		etag = c.App.GetPostsEtag(c.Params.ChannelId)
+
+		if c.HandleEtag(etag, "Get Posts Since", w, r) {
+			return
+		}
+
 		list, err = c.App.GetPostsSince(c.Params.ChannelId, since)
+	} else if cursor != nil {
+		etag = c.App.GetPostsEtag(c.Params.ChannelId)
+
+		if c.HandleEtag(etag, "Get Posts By Cursor", w, r) {
+			return
+		}
+
+		list, err = c.App.GetPostsByCursor(c.Params.ChannelId, cursor, cursorBefore, c.Params.PerPage)
 	} else if len(afterPost) > 0 {
 		etag = c.App.GetPostsEtag(c.Params.ChannelId)
 
@@ -162,12 +206,64 @@ func getPostsForChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if list, err = c.App.PostListWithFileInfos(list); err != nil {
+		c.Err = err
+		return
+	}
+
+	// The first page gives the cursors to start from, since page numbers shift as posts are made in busy channels
+	if since == 0 && cursor == nil && len(afterPost) == 0 && len(beforePost) == 0 && c.Params.Page == 0 {
+		list.SetCursors(len(list.Order) == c.Params.PerPage)
+	}
+
 	if len(etag) > 0 {
 		w.Header().Set(model.HEADER_ETAG_SERVER, etag)
 	}
 	w.Write([]byte(c.App.PostListWithProxyAddedToImageURLs(list).ToJson()))
 }
 
+func getPostEventsForChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	after := int64(-1)
+	if afterString := r.URL.Query().Get("after"); len(afterString) > 0 {
+		var err error
+		if after, err = strconv.ParseInt(afterString, 10, 64); err != nil || after < 0 {
+			c.SetInvalidParam("after")
+			return
+		}
+	}
+
+	limit := model.POST_EVENTS_DEFAULT_LIMIT
+	if limitString := r.URL.Query().Get("limit"); len(limitString) > 0 {
+		var err error
+		if limit, err = strconv.Atoi(limitString); err != nil || limit <= 0 {
+			c.SetInvalidParam("limit")
+			return
+		}
+	}
+
+	if limit > model.POST_EVENTS_MAX_LIMIT {
+		limit = model.POST_EVENTS_MAX_LIMIT
+	}
+
+	if !c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	list, err := c.App.GetPostEventsForChannel(c.Params.ChannelId, after, limit)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(list.ToJson()))
+}
+
 func getFlaggedPostsForUser(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -240,6 +336,65 @@ func getPost(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(c.App.PostWithProxyAddedToImageURLs(post).ToJson()))
 }
 
+func getPushNotificationForPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	// Only members of the channel are sent notifications for its posts
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	msg, err := c.App.BuildFetchedPushNotificationMessage(c.Params.PostId, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(msg.ToJson()))
+}
+
+func getPostByRemoteId(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireService().RequireExternalId()
+	if c.Err != nil {
+		return
+	}
+
+	post, err := c.App.GetPostByRemoteId(c.Params.Service, c.Params.ExternalId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	channel, err := c.App.GetChannel(post.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_READ_CHANNEL) {
+		if channel.Type == model.CHANNEL_OPEN {
+			if !c.App.SessionHasPermissionToTeam(c.Session, channel.TeamId, model.PERMISSION_READ_PUBLIC_CHANNEL) {
+				c.SetPermissionError(model.PERMISSION_READ_PUBLIC_CHANNEL)
+				return
+			}
+		} else {
+			c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+			return
+		}
+	}
+
+	if c.HandleEtag(post.Etag(), "Get Post By Remote Id", w, r) {
+		return
+	}
+
+	w.Header().Set(model.HEADER_ETAG_SERVER, post.Etag())
+	w.Write([]byte(c.App.PostWithProxyAddedToImageURLs(post).ToJson()))
+}
+
 func deletePost(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequirePostId()
 	if c.Err != nil {
@@ -315,6 +470,11 @@ func getPostThread(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if list, err = c.App.PostListWithFileInfos(list); err != nil {
+		c.Err = err
+		return
+	}
+
 	w.Header().Set(model.HEADER_ETAG_SERVER, list.Etag())
 	w.Write([]byte(c.App.PostListWithProxyAddedToImageURLs(list).ToJson()))
 }
@@ -401,6 +561,7 @@ func updatePost(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	post.Id = c.Params.PostId
+	post.RemoteId = originalPost.RemoteId
 
 	rpost, err := c.App.UpdatePost(c.App.PostWithProxyRemovedFromImageURLs(post), false)
 	if err != nil {
@@ -442,6 +603,11 @@ func patchPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if post.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
 	patchedPost, err := c.App.PatchPost(c.Params.PostId, c.App.PostPatchWithProxyRemovedFromImageURLs(post))
 	if err != nil {
 		c.Err = err
@@ -482,6 +648,75 @@ func unpinPost(c *Context, w http.ResponseWriter, r *http.Request) {
 	saveIsPinnedPost(c, w, r, false)
 }
 
+func getPostAcknowledgements(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	acknowledgements, err := c.App.GetAcknowledgementsForPost(c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.PostAcknowledgementsToJson(acknowledgements)))
+}
+
+// requireAcknowledgementPermissions checks that the session belongs to the user acknowledging the post, since an
+// acknowledgement is only meaningful when it comes from the user themselves, and that they can read the post.
+func requireAcknowledgementPermissions(c *Context) {
+	c.RequireUserId().RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if c.Params.UserId != c.Session.UserId {
+		c.Err = model.NewAppError("requireAcknowledgementPermissions", "api.post.acknowledge.user_id.app_error", nil, "", http.StatusForbidden)
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+}
+
+func acknowledgePost(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireAcknowledgementPermissions(c)
+	if c.Err != nil {
+		return
+	}
+
+	acknowledgement, err := c.App.AcknowledgePost(c.Params.UserId, c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(acknowledgement.ToJson()))
+}
+
+func unacknowledgePost(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireAcknowledgementPermissions(c)
+	if c.Err != nil {
+		return
+	}
+
+	if err := c.App.UnacknowledgePost(c.Params.UserId, c.Params.PostId); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
 func getFileInfosForPost(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequirePostId()
 	if c.Err != nil {
@@ -531,3 +766,23 @@ func doPostAction(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	ReturnStatusOK(w)
 }
+
+func getPostActionOptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId().RequireActionId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	options, err := c.App.GetPostActionOptions(c.Params.PostId, c.Params.ActionId, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.PostActionOptionsListToJson(options)))
+}
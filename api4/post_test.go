@@ -15,6 +15,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/mattermost/mattermost-server/app"
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/utils"
@@ -749,6 +752,117 @@ func TestUnpinPost(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestAcknowledgePost(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	post := &model.Post{
+		ChannelId: th.BasicChannel.Id,
+		Message:   "please read",
+		Props: model.StringInterface{
+			model.POST_PROPS_PRIORITY:      model.POST_PRIORITY_URGENT,
+			model.POST_PROPS_REQUESTED_ACK: true,
+		},
+	}
+	post, resp := th.SystemAdminClient.CreatePost(post)
+	CheckNoError(t, resp)
+
+	acknowledgement, resp := Client.AcknowledgePost(th.BasicUser.Id, post.Id)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+
+	if acknowledgement.UserId != th.BasicUser.Id || acknowledgement.AcknowledgedAt == 0 {
+		t.Fatal("should have acknowledged the post")
+	}
+
+	again, resp := Client.AcknowledgePost(th.BasicUser.Id, post.Id)
+	CheckNoError(t, resp)
+
+	if again.AcknowledgedAt != acknowledgement.AcknowledgedAt {
+		t.Fatal("should have kept the first acknowledgement")
+	}
+
+	acknowledgements, resp := Client.GetPostAcknowledgements(post.Id)
+	CheckNoError(t, resp)
+
+	if len(acknowledgements) != 1 || acknowledgements[0].UserId != th.BasicUser.Id {
+		t.Fatal("should have returned the acknowledgement")
+	}
+
+	_, resp = Client.AcknowledgePost(th.SystemAdminUser.Id, post.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.AcknowledgePost(th.BasicUser.Id, th.BasicPost.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.AcknowledgePost(th.BasicUser.Id, GenerateTestId())
+	CheckForbiddenStatus(t, resp)
+
+	pass, resp := Client.UnacknowledgePost(th.BasicUser.Id, post.Id)
+	CheckNoError(t, resp)
+
+	if !pass {
+		t.Fatal("should have passed")
+	}
+
+	acknowledgements, resp = Client.GetPostAcknowledgements(post.Id)
+	CheckNoError(t, resp)
+
+	if len(acknowledgements) != 0 {
+		t.Fatal("should have removed the acknowledgement")
+	}
+
+	Client.Logout()
+	_, resp = Client.AcknowledgePost(th.BasicUser.Id, post.Id)
+	CheckUnauthorizedStatus(t, resp)
+
+	_, resp = Client.GetPostAcknowledgements(post.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestGetPostEventsForChannel(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetPostEventsForChannel(th.BasicChannel.Id, 0, 10)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.SqlSettings.EnablePostEvents = true })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.SqlSettings.EnablePostEvents = false })
+
+	list, resp := Client.GetPostEventsForChannel(th.BasicChannel.Id, -1, 10)
+	CheckNoError(t, resp)
+
+	if len(list.Events) != 0 {
+		t.Fatal("should only have returned the cursor")
+	}
+
+	list, resp = Client.GetPostEventsForChannel(th.BasicChannel.Id, list.Cursor, model.POST_EVENTS_MAX_LIMIT+1)
+	CheckNoError(t, resp)
+
+	if list.Events == nil {
+		t.Fatal("should have returned a page of events")
+	}
+
+	_, resp = Client.GetPostEventsForChannel("junk", 0, 10)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetPostEventsForChannel(th.BasicChannel.Id, 0, 0)
+	CheckBadRequestStatus(t, resp)
+
+	privateChannel := th.CreatePrivateChannel()
+	Client.Logout()
+	th.LoginBasic2()
+	_, resp = Client.GetPostEventsForChannel(privateChannel.Id, 0, 10)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetPostEventsForChannel(th.BasicChannel.Id, 0, 10)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetPostsForChannel(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -861,6 +975,43 @@ func TestGetPostsForChannel(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestGetPostsForChannelWithFileInfos(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	data, err := readTestFile("test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileResp, resp := Client.UploadFile(data, th.BasicChannel.Id, "test.png")
+	CheckNoError(t, resp)
+	fileId := fileResp.FileInfos[0].Id
+
+	post, resp := Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "files", FileIds: []string{fileId}})
+	CheckNoError(t, resp)
+	otherPost := th.CreatePost()
+
+	posts, resp := Client.GetPostsForChannel(th.BasicChannel.Id, 0, 60, "")
+	CheckNoError(t, resp)
+
+	if infos := posts.FileInfos[post.Id]; len(infos) != 1 || infos[0].Id != fileId {
+		t.Fatal("should have included the post's file infos")
+	}
+
+	if _, ok := posts.FileInfos[otherPost.Id]; ok {
+		t.Fatal("shouldn't have included file infos for a post without files")
+	}
+
+	thread, resp := Client.GetPostThread(post.Id, "")
+	CheckNoError(t, resp)
+
+	if infos := thread.FileInfos[post.Id]; len(infos) != 1 || infos[0].Id != fileId {
+		t.Fatal("should have included the thread's file infos")
+	}
+}
+
 func TestGetFlaggedPostsForUser(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -1126,6 +1277,50 @@ func TestGetPostsAfterAndBefore(t *testing.T) {
 	}
 }
 
+func TestGetPostsByCursor(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	post1 := th.CreatePostWithClient(Client, channel)
+	post2 := th.CreatePostWithClient(Client, channel)
+	post3 := th.CreatePostWithClient(Client, channel)
+
+	posts, resp := Client.GetPostsForChannel(channel.Id, 0, 2, "")
+	CheckNoError(t, resp)
+	require.Equal(t, []string{post3.Id, post2.Id}, posts.Order)
+	require.NotEmpty(t, posts.BeforeCursor)
+	require.NotEmpty(t, posts.AfterCursor)
+
+	// Posts made after the first page don't shift the pages that come after it
+	post4 := th.CreatePostWithClient(Client, channel)
+
+	older, resp := Client.GetPostsBeforeCursor(channel.Id, posts.BeforeCursor, 2)
+	CheckNoError(t, resp)
+	assert.Equal(t, post1.Id, older.Order[0])
+	assert.NotContains(t, older.Order, post2.Id)
+
+	newer, resp := Client.GetPostsAfterCursor(channel.Id, posts.AfterCursor, 2)
+	CheckNoError(t, resp)
+	assert.Equal(t, []string{post4.Id}, newer.Order)
+
+	newest, resp := Client.GetPostsAfterCursor(channel.Id, newer.AfterCursor, 2)
+	CheckNoError(t, resp)
+	assert.Empty(t, newest.Order)
+	assert.Equal(t, newer.AfterCursor, newest.AfterCursor, "should keep the cursor to check again")
+
+	_, resp = Client.GetPostsBeforeCursor(channel.Id, "junk", 2)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetPostsBeforeCursor(model.NewId(), posts.BeforeCursor, 2)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetPostsAfterCursor(channel.Id, posts.AfterCursor, 2)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetPost(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -1175,6 +1370,96 @@ func TestGetPost(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestGetPostByRemoteId(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	remoteId := model.NewRemoteId("jira", "ISSUE-"+model.NewId())
+	_, externalId, _ := model.ParseRemoteId(remoteId)
+
+	post := &model.Post{ChannelId: th.BasicChannel.Id, Message: "remote", RemoteId: &remoteId}
+	_, resp := Client.CreatePost(post)
+	CheckForbiddenStatus(t, resp)
+
+	post.UserId = th.BasicUser.Id
+	rpost, resp := th.SystemAdminClient.CreatePost(post)
+	CheckNoError(t, resp)
+
+	received, resp := Client.GetPostByRemoteId("jira", externalId, "")
+	CheckNoError(t, resp)
+	if received.Id != rpost.Id {
+		t.Fatal("post ids don't match")
+	}
+
+	_, resp = Client.GetPostByRemoteId("jira", model.NewId(), "")
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.PatchPost(th.BasicPost.Id, &model.PostPatch{RemoteId: &remoteId})
+	CheckForbiddenStatus(t, resp)
+
+	// full updates keep the remote id
+	rpost.Message = "edited"
+	rpost.RemoteId = nil
+	updated, resp := th.SystemAdminClient.UpdatePost(rpost.Id, rpost)
+	CheckNoError(t, resp)
+	if updated.RemoteId == nil || *updated.RemoteId != remoteId {
+		t.Fatal("remote id should not have changed")
+	}
+
+	privatePost := &model.Post{ChannelId: th.BasicPrivateChannel.Id, Message: "remote", RemoteId: model.NewString(model.NewRemoteId("jira", model.NewId()))}
+	privatePost, resp = th.SystemAdminClient.CreatePost(privatePost)
+	CheckNoError(t, resp)
+	_, privateExternalId, _ := model.ParseRemoteId(*privatePost.RemoteId)
+
+	Client.RemoveUserFromChannel(th.BasicPrivateChannel.Id, th.BasicUser.Id)
+
+	_, resp = Client.GetPostByRemoteId("jira", privateExternalId, "")
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestGetPushNotificationForPost(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	post, resp := th.SystemAdminClient.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "hello @" + th.BasicUser.Username})
+	CheckNoError(t, resp)
+
+	msg, resp := Client.GetPushNotificationForPost(post.Id)
+	CheckNoError(t, resp)
+
+	if msg.PostId != post.Id || msg.ChannelId != th.BasicChannel.Id {
+		t.Fatal("should have identified the post")
+	}
+
+	if msg.IsIdLoaded {
+		t.Fatal("fetched notifications should have their contents loaded")
+	}
+
+	if msg.Message != "@"+th.SystemAdminUser.Username+": hello @"+th.BasicUser.Username {
+		t.Fatal("should have included the full message, got " + msg.Message)
+	}
+
+	_, resp = Client.GetPushNotificationForPost("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetPushNotificationForPost(model.NewId())
+	CheckForbiddenStatus(t, resp)
+
+	privatePost, resp := th.SystemAdminClient.CreatePost(&model.Post{ChannelId: th.BasicPrivateChannel.Id, Message: "private"})
+	CheckNoError(t, resp)
+
+	Client.RemoveUserFromChannel(th.BasicPrivateChannel.Id, th.BasicUser.Id)
+
+	_, resp = Client.GetPushNotificationForPost(privatePost.Id)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetPushNotificationForPost(post.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestDeletePost(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -1599,7 +1884,7 @@ func TestSearchPostsWithDateFlags(t *testing.T) {
 	posts, _ = Client.SearchPosts(th.BasicTeam.Id, "before:2018-08-03 after:2018-08-01", false)
 	if len(posts.Order) != 1 {
 		t.Fatalf("wrong number of posts returned %v", len(posts.Order))
-	}	
+	}
 }
 
 func TestGetFileInfosForPost(t *testing.T) {
@@ -411,3 +411,35 @@ func TestDeletePreferencesWebsocket(t *testing.T) {
 		}
 	}
 }
+
+func TestServerManagedPreferences(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	flagged := model.Preference{
+		UserId:   th.BasicUser.Id,
+		Category: model.PREFERENCE_CATEGORY_USER_LIFECYCLE,
+		Name:     model.PREFERENCE_NAME_INACTIVE_FLAGGED_AT,
+		Value:    "1000",
+	}
+	if result := <-th.App.Srv.Store.Preference().Save(&model.Preferences{flagged}); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	postponed := flagged
+	postponed.Value = "99999999999999"
+
+	_, resp := Client.UpdatePreferences(th.BasicUser.Id, &model.Preferences{postponed})
+	CheckForbiddenStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.preference.server_managed.app_error")
+
+	_, resp = Client.DeletePreferences(th.BasicUser.Id, &model.Preferences{flagged})
+	CheckForbiddenStatus(t, resp)
+
+	preference, resp := Client.GetPreferenceByCategoryAndName(th.BasicUser.Id, model.PREFERENCE_CATEGORY_USER_LIFECYCLE, model.PREFERENCE_NAME_INACTIVE_FLAGGED_AT)
+	CheckNoError(t, resp)
+	if preference.Value != flagged.Value {
+		t.Fatal("shouldn't have changed the server managed preference")
+	}
+}
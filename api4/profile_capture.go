@@ -0,0 +1,90 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitProfileCapture() {
+	api.BaseRoutes.ApiRoot.Handle("/debug/profiles", api.ApiSessionRequired(startProfileCapture)).Methods("POST")
+	api.BaseRoutes.ApiRoot.Handle("/debug/profiles", api.ApiSessionRequired(getProfileCaptures)).Methods("GET")
+	api.BaseRoutes.ApiRoot.Handle("/debug/profiles/{job_id:[A-Za-z0-9]+}/{profile:[a-z]+}", api.ApiSessionRequired(downloadCapturedProfile)).Methods("GET")
+}
+
+func startProfileCapture(c *Context, w http.ResponseWriter, r *http.Request) {
+	request := model.ProfileCaptureRequestFromJson(r.Body)
+	if request == nil {
+		c.SetInvalidParam("profile_capture")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	job, err := c.App.StartProfileCapture(request, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("job_id=" + job.Id + " profiles=" + strings.Join(job.CapturedProfiles(), ",") + " duration_seconds=" + job.Data[model.PROFILE_CAPTURE_DATA_DURATION_SECONDS])
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(job.ToJson()))
+}
+
+func getProfileCaptures(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	jobs, err := c.App.GetProfileCaptures(c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.JobsToJson(jobs)))
+}
+
+func downloadCapturedProfile(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireJobId()
+	if c.Err != nil {
+		return
+	}
+
+	profile := mux.Vars(r)["profile"]
+	if !model.IsValidProfileCapture(profile) {
+		c.SetInvalidUrlParam("profile")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	data, err := c.App.GetCapturedProfile(c.Params.JobId, profile)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("job_id=" + c.Params.JobId + " profile=" + profile)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+c.Params.JobId+"-"+profile+".pprof\"")
+	w.Write(data)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestProfileCapture(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	request := &model.ProfileCaptureRequest{
+		Profiles:        []string{model.PROFILE_CAPTURE_CPU, model.PROFILE_CAPTURE_GOROUTINE},
+		DurationSeconds: 1,
+	}
+
+	_, resp := Client.StartProfileCapture(request)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.StartProfileCapture(&model.ProfileCaptureRequest{Profiles: []string{"block"}})
+	CheckBadRequestStatus(t, resp)
+
+	job, resp := th.SystemAdminClient.StartProfileCapture(request)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, model.JOB_TYPE_PROFILE_CAPTURE, job.Type)
+	assert.Equal(t, model.JOB_STATUS_IN_PROGRESS, job.Status)
+	assert.Equal(t, th.SystemAdminUser.Id, job.Data[model.PROFILE_CAPTURE_DATA_STARTED_BY])
+
+	_, resp = th.SystemAdminClient.StartProfileCapture(request)
+	CheckErrorMessage(t, resp, "app.profile_capture.in_progress.app_error")
+
+	_, resp = th.SystemAdminClient.DownloadCapturedProfile(job.Id, model.PROFILE_CAPTURE_CPU)
+	CheckBadRequestStatus(t, resp)
+
+	for i := 0; i < 50; i++ {
+		job, resp = th.SystemAdminClient.GetJob(job.Id)
+		CheckNoError(t, resp)
+
+		if job.Status != model.JOB_STATUS_IN_PROGRESS {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.Equal(t, model.JOB_STATUS_SUCCESS, job.Status)
+
+	jobs, resp := th.SystemAdminClient.GetProfileCaptures(0, 10)
+	CheckNoError(t, resp)
+	require.NotEmpty(t, jobs)
+	assert.Equal(t, job.Id, jobs[0].Id)
+
+	_, resp = Client.GetProfileCaptures(0, 10)
+	CheckForbiddenStatus(t, resp)
+
+	data, resp := th.SystemAdminClient.DownloadCapturedProfile(job.Id, model.PROFILE_CAPTURE_CPU)
+	CheckNoError(t, resp)
+	assert.NotEmpty(t, data)
+
+	_, resp = th.SystemAdminClient.DownloadCapturedProfile(job.Id, model.PROFILE_CAPTURE_HEAP)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.DownloadCapturedProfile(job.Id, model.PROFILE_CAPTURE_GOROUTINE)
+	CheckForbiddenStatus(t, resp)
+}
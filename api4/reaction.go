@@ -10,9 +10,9 @@ import (
 )
 
 func (api *API) InitReaction() {
-	api.BaseRoutes.Reactions.Handle("", api.ApiSessionRequired(saveReaction)).Methods("POST")
-	api.BaseRoutes.Post.Handle("/reactions", api.ApiSessionRequired(getReactions)).Methods("GET")
-	api.BaseRoutes.ReactionByNameForPostForUser.Handle("", api.ApiSessionRequired(deleteReaction)).Methods("DELETE")
+	api.BaseRoutes.Reactions.Handle("", api.ApiSessionRequiredWithScope(saveReaction, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("POST")
+	api.BaseRoutes.Post.Handle("/reactions", api.ApiSessionRequiredWithScope(getReactions, model.OAUTH_SCOPE_READ_POSTS)).Methods("GET")
+	api.BaseRoutes.ReactionByNameForPostForUser.Handle("", api.ApiSessionRequiredWithScope(deleteReaction, model.OAUTH_SCOPE_WRITE_POSTS)).Methods("DELETE")
 }
 
 func saveReaction(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitReadReceipt() {
+	api.BaseRoutes.Channel.Handle("/read_receipts", api.ApiSessionRequired(getReadReceiptsForChannel)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/read_receipts", api.ApiSessionRequired(getReadReceiptsForPost)).Methods("GET")
+}
+
+func getReadReceiptsForChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	channel, err := c.App.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	receipts, err := c.App.GetReadReceiptsForChannel(channel)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.ReadReceiptsToJson(receipts)))
+}
+
+func getReadReceiptsForPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	post, err := c.App.GetSinglePost(c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	channel, err := c.App.GetChannel(post.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	receipts, err := c.App.GetReadReceiptsForPost(post, channel)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.ReadReceiptsToJson(receipts)))
+}
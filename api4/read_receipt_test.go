@@ -0,0 +1,84 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestGetReadReceipts(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	th.AddUserToChannel(th.BasicUser2, channel)
+	post := th.CreatePostWithClient(Client, channel)
+
+	_, resp := Client.GetReadReceiptsForChannel(channel.Id)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceipts = model.READ_RECEIPTS_CHANNEL_OPT_IN })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceipts = model.READ_RECEIPTS_DISABLED })
+
+	_, resp = Client.GetReadReceiptsForPost(post.Id)
+	CheckNotImplementedStatus(t, resp)
+
+	th.LoginBasic2()
+	_, resp = Client.PatchChannel(channel.Id, &model.ChannelPatch{ReadReceipts: model.NewBool(true)})
+	CheckForbiddenStatus(t, resp)
+
+	th.LoginBasic()
+	th.MakeUserChannelAdmin(th.BasicUser, channel)
+	patched, resp := Client.PatchChannel(channel.Id, &model.ChannelPatch{ReadReceipts: model.NewBool(true)})
+	CheckNoError(t, resp)
+
+	if !patched.ReadReceipts {
+		t.Fatal("should have turned on read receipts")
+	}
+
+	receipts, resp := Client.GetReadReceiptsForPost(post.Id)
+	CheckNoError(t, resp)
+
+	if len(receipts) != 0 {
+		t.Fatal("nobody but the author should have seen the post")
+	}
+
+	th.LoginBasic2()
+	_, resp = Client.ViewChannel(th.BasicUser2.Id, &model.ChannelView{ChannelId: channel.Id})
+	CheckNoError(t, resp)
+
+	receipts, resp = Client.GetReadReceiptsForPost(post.Id)
+	CheckNoError(t, resp)
+
+	if len(receipts) != 1 || receipts[0].UserId != th.BasicUser2.Id {
+		t.Fatal("should have returned the member who viewed the post")
+	}
+
+	receipts, resp = Client.GetReadReceiptsForChannel(channel.Id)
+	CheckNoError(t, resp)
+
+	if len(receipts) != 2 {
+		t.Fatal("should have returned a receipt for each member")
+	}
+
+	_, resp = th.SystemAdminClient.GetReadReceiptsForChannel(th.BasicPrivateChannel.Id)
+	CheckNotImplementedStatus(t, resp)
+
+	otherUser := th.CreateUser()
+	th.LinkUserToTeam(otherUser, th.BasicTeam)
+	Client.Login(otherUser.Email, otherUser.Password)
+
+	_, resp = Client.GetReadReceiptsForChannel(channel.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.GetReadReceiptsForPost(post.Id)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetReadReceiptsForChannel(channel.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitReminder() {
+	api.BaseRoutes.User.Handle("/reminders", api.ApiSessionRequired(getRemindersForUser)).Methods("GET")
+	api.BaseRoutes.Reminders.Handle("/{reminder_id:[A-Za-z0-9]+}", api.ApiSessionRequired(cancelReminder)).Methods("DELETE")
+}
+
+func getRemindersForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	reminders, err := c.App.GetRemindersForUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.RemindersToJson(reminders)))
+}
+
+func cancelReminder(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireReminderId()
+	if c.Err != nil {
+		return
+	}
+
+	reminder, err := c.App.GetReminder(c.Params.ReminderId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, reminder.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := c.App.CancelReminder(reminder.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
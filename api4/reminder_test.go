@@ -0,0 +1,74 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestReminders(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	reminder, err := th.App.CreateReminder(&model.Reminder{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "call Bob",
+		RemindAt:  model.GetMillis() + 60000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reminders, resp := Client.GetRemindersForUser(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if len(reminders) != 1 || reminders[0].Id != reminder.Id {
+		t.Fatal("should have returned the reminder")
+	}
+
+	_, resp = Client.GetRemindersForUser(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.CancelReminder("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.CancelReminder(model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	th.LoginBasic2()
+
+	_, resp = Client.CancelReminder(reminder.Id)
+	CheckForbiddenStatus(t, resp)
+
+	reminders, resp = th.SystemAdminClient.GetRemindersForUser(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if len(reminders) != 1 {
+		t.Fatal("should have let a system admin see the reminders")
+	}
+
+	th.LoginBasic()
+
+	ok, resp := Client.CancelReminder(reminder.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have cancelled the reminder")
+	}
+
+	reminders, resp = Client.GetRemindersForUser(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if len(reminders) != 0 {
+		t.Fatal("should have no reminders left")
+	}
+
+	Client.Logout()
+	_, resp = Client.GetRemindersForUser(th.BasicUser.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
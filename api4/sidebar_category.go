@@ -0,0 +1,109 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitSidebarCategory() {
+	api.BaseRoutes.TeamForUser.Handle("/channels/categories", api.ApiSessionRequired(getSidebarCategories)).Methods("GET")
+	api.BaseRoutes.TeamForUser.Handle("/channels/categories", api.ApiSessionRequired(createSidebarCategory)).Methods("POST")
+	api.BaseRoutes.TeamForUser.Handle("/channels/categories/{category_id:[A-Za-z0-9]+}", api.ApiSessionRequired(updateSidebarCategory)).Methods("PUT")
+	api.BaseRoutes.TeamForUser.Handle("/channels/categories/{category_id:[A-Za-z0-9]+}", api.ApiSessionRequired(deleteSidebarCategory)).Methods("DELETE")
+}
+
+func getSidebarCategories(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	categories, err := c.App.GetSidebarCategories(c.Params.UserId, c.Params.TeamId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.SidebarCategoryListToJson(categories)))
+}
+
+func createSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	category := model.SidebarCategoryFromJson(r.Body)
+	if category == nil {
+		c.SetInvalidParam("category")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	createdCategory, err := c.App.CreateSidebarCategory(c.Params.UserId, c.Params.TeamId, category)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(createdCategory.ToJson()))
+}
+
+func updateSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId().RequireCategoryId()
+	if c.Err != nil {
+		return
+	}
+
+	category := model.SidebarCategoryFromJson(r.Body)
+	if category == nil || category.Id != c.Params.CategoryId {
+		c.SetInvalidParam("category")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	updatedCategory, err := c.App.UpdateSidebarCategory(c.Params.UserId, c.Params.TeamId, category)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(updatedCategory.ToJson()))
+}
+
+func deleteSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId().RequireCategoryId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := c.App.DeleteSidebarCategory(c.Params.UserId, c.Params.TeamId, c.Params.CategoryId); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
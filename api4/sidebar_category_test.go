@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestSidebarCategories(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	categories, resp := Client.GetSidebarCategoriesForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	assert.Empty(t, categories)
+
+	category, resp := Client.CreateSidebarCategoryForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id, &model.SidebarCategory{
+		DisplayName: "Projects",
+		ChannelIds:  []string{th.BasicChannel.Id},
+	})
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, category.UserId)
+	assert.Equal(t, th.BasicTeam.Id, category.TeamId)
+
+	categories, resp = Client.GetSidebarCategoriesForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	require.Len(t, categories, 1)
+	assert.Equal(t, category.Id, categories[0].Id)
+	assert.Equal(t, model.StringArray{th.BasicChannel.Id}, categories[0].ChannelIds)
+
+	category.DisplayName = "Old Projects"
+	category.ChannelIds = []string{th.BasicChannel2.Id, th.BasicChannel.Id}
+	updated, resp := Client.UpdateSidebarCategoryForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id, category)
+	CheckNoError(t, resp)
+	assert.Equal(t, "Old Projects", updated.DisplayName)
+	assert.Equal(t, model.StringArray{th.BasicChannel2.Id, th.BasicChannel.Id}, updated.ChannelIds)
+
+	t.Run("channels that the user isn't a member of", func(t *testing.T) {
+		otherChannel := th.CreateChannelWithClient(th.SystemAdminClient, model.CHANNEL_OPEN)
+
+		_, resp := Client.CreateSidebarCategoryForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id, &model.SidebarCategory{
+			DisplayName: "Other",
+			ChannelIds:  []string{otherChannel.Id},
+		})
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("another user's categories", func(t *testing.T) {
+		_, resp := Client.GetSidebarCategoriesForTeamForUser(th.BasicUser2.Id, th.BasicTeam.Id)
+		CheckForbiddenStatus(t, resp)
+
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp = Client.UpdateSidebarCategoryForTeamForUser(th.BasicUser2.Id, th.BasicTeam.Id, category)
+		CheckNotFoundStatus(t, resp)
+
+		_, resp = Client.DeleteSidebarCategoryForTeamForUser(th.BasicUser2.Id, th.BasicTeam.Id, category.Id)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	ok, resp := Client.DeleteSidebarCategoryForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id, category.Id)
+	CheckNoError(t, resp)
+	assert.True(t, ok)
+
+	categories, resp = Client.GetSidebarCategoriesForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	assert.Empty(t, categories)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableChannelOrganization = false })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableChannelOrganization = true })
+
+	_, resp = Client.GetSidebarCategoriesForTeamForUser(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNotImplementedStatus(t, resp)
+}
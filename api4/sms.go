@@ -0,0 +1,91 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitSms() {
+	api.BaseRoutes.User.Handle("/phone", api.ApiSessionRequired(sendPhoneVerificationCode)).Methods("POST")
+	api.BaseRoutes.User.Handle("/phone/verify", api.ApiSessionRequired(verifyPhoneNumber)).Methods("POST")
+	api.BaseRoutes.User.Handle("/phone", api.ApiSessionRequired(removePhoneNumber)).Methods("DELETE")
+}
+
+func sendPhoneVerificationCode(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	phoneNumber := props["phone_number"]
+	if len(phoneNumber) == 0 {
+		c.SetInvalidParam("phone_number")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := c.App.SendPhoneVerificationCode(c.Params.UserId, phoneNumber); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
+func verifyPhoneNumber(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	code := props["code"]
+	if len(code) == 0 {
+		c.SetInvalidParam("code")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	user, err := c.App.VerifyPhoneNumber(c.Params.UserId, code)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("phone number verified")
+	w.Write([]byte(user.ToJson()))
+}
+
+func removePhoneNumber(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	user, err := c.App.RemovePhoneNumber(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("phone number removed")
+	w.Write([]byte(user.ToJson()))
+}
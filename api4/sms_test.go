@@ -0,0 +1,72 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+type testSmsInterface struct {
+	messages []string
+}
+
+func (s *testSmsInterface) SendSms(phoneNumber string, message string) *model.AppError {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestPhoneNumber(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.SendPhoneVerificationCode(th.BasicUser.Id, "+15555550123")
+	CheckNotImplementedStatus(t, resp)
+
+	sms := &testSmsInterface{}
+	th.App.Sms = sms
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SmsSettings.Enable = true
+	})
+
+	_, resp = Client.SendPhoneVerificationCode(th.BasicUser.Id, "")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.SendPhoneVerificationCode(th.BasicUser.Id, "555")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.SendPhoneVerificationCode(th.BasicUser2.Id, "+15555550123")
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.SendPhoneVerificationCode(th.BasicUser.Id, "+15555550123")
+	CheckNoError(t, resp)
+
+	if len(sms.messages) != 1 {
+		t.Fatal("should have sent a verification code")
+	}
+	code := regexp.MustCompile(`[0-9]{6}`).FindString(sms.messages[0])
+
+	_, resp = Client.VerifyPhoneNumber(th.BasicUser.Id, "000000x")
+	CheckBadRequestStatus(t, resp)
+
+	user, resp := Client.VerifyPhoneNumber(th.BasicUser.Id, code)
+	CheckNoError(t, resp)
+
+	if user.GetPhoneNumber() != "+15555550123" {
+		t.Fatal("should have stored the verified phone number")
+	}
+
+	_, resp = Client.RemovePhoneNumber(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	user, resp = th.SystemAdminClient.RemovePhoneNumber(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if user.GetPhoneNumber() != "" {
+		t.Fatal("should have removed the phone number")
+	}
+}
@@ -10,9 +10,9 @@ import (
 )
 
 func (api *API) InitStatus() {
-	api.BaseRoutes.User.Handle("/status", api.ApiSessionRequired(getUserStatus)).Methods("GET")
-	api.BaseRoutes.Users.Handle("/status/ids", api.ApiSessionRequired(getUserStatusesByIds)).Methods("POST")
-	api.BaseRoutes.User.Handle("/status", api.ApiSessionRequired(updateUserStatus)).Methods("PUT")
+	api.BaseRoutes.User.Handle("/status", api.ApiSessionRequiredWithScope(getUserStatus, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
+	api.BaseRoutes.Users.Handle("/status/ids", api.ApiSessionRequiredWithScope(getUserStatusesByIds, model.OAUTH_SCOPE_READ_USERS)).Methods("POST")
+	api.BaseRoutes.User.Handle("/status", api.ApiSessionRequiredWithScope(updateUserStatus, model.OAUTH_SCOPE_WRITE_USERS)).Methods("PUT")
 }
 
 func getUserStatus(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -34,6 +34,7 @@ func getUserStatus(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	statusMap = c.App.SanitizeStatuses(statusMap, c.Session.UserId, c.IsSystemAdmin())
 	w.Write([]byte(statusMap[0].ToJson()))
 }
 
@@ -53,6 +54,7 @@ func getUserStatusesByIds(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	statusMap = c.App.SanitizeStatuses(statusMap, c.Session.UserId, c.IsSystemAdmin())
 	w.Write([]byte(model.StatusListToJson(statusMap)))
 }
 
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"runtime"
+	"strconv"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -20,11 +21,22 @@ func (api *API) InitSystem() {
 
 	api.BaseRoutes.System.Handle("/timezones", api.ApiSessionRequired(getSupportedTimezones)).Methods("GET")
 
+	api.BaseRoutes.System.Handle("/integration_traffic", api.ApiSessionRequired(getIntegrationTraffic)).Methods("GET")
+	api.BaseRoutes.System.Handle("/integration_traffic", api.ApiSessionRequired(resetIntegrationTraffic)).Methods("DELETE")
+	api.BaseRoutes.System.Handle("/integration_deliveries", api.ApiSessionRequired(getIntegrationDeliveries)).Methods("GET")
+
+	api.BaseRoutes.System.Handle("/store_tracing", api.ApiSessionRequired(getStoreTracing)).Methods("GET")
+	api.BaseRoutes.System.Handle("/store_tracing", api.ApiSessionRequired(resetStoreTracing)).Methods("DELETE")
+
+	api.BaseRoutes.System.Handle("/errors", api.ApiHandler(getErrorCatalog)).Methods("GET")
+
 	api.BaseRoutes.ApiRoot.Handle("/config", api.ApiSessionRequired(getConfig)).Methods("GET")
 	api.BaseRoutes.ApiRoot.Handle("/config", api.ApiSessionRequired(updateConfig)).Methods("PUT")
 	api.BaseRoutes.ApiRoot.Handle("/config/reload", api.ApiSessionRequired(configReload)).Methods("POST")
 	api.BaseRoutes.ApiRoot.Handle("/config/client", api.ApiHandler(getClientConfig)).Methods("GET")
 	api.BaseRoutes.ApiRoot.Handle("/config/environment", api.ApiSessionRequired(getEnvironmentConfig)).Methods("GET")
+	api.BaseRoutes.ApiRoot.Handle("/config/export", api.ApiSessionRequired(exportConfig)).Methods("GET")
+	api.BaseRoutes.ApiRoot.Handle("/config/import", api.ApiSessionRequired(importConfig)).Methods("POST")
 
 	api.BaseRoutes.ApiRoot.Handle("/license", api.ApiSessionRequired(addLicense)).Methods("POST")
 	api.BaseRoutes.ApiRoot.Handle("/license", api.ApiSessionRequired(removeLicense)).Methods("DELETE")
@@ -127,8 +139,10 @@ func updateConfig(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Do not allow plugin uploads to be toggled through the API
+	// Do not allow plugin uploads to be toggled through the API, or the key that marketplace plugins are checked with
+	// to be changed
 	cfg.PluginSettings.EnableUploads = c.App.GetConfig().PluginSettings.EnableUploads
+	cfg.PluginSettings.MarketplacePublicKey = c.App.GetConfig().PluginSettings.MarketplacePublicKey
 
 	err := c.App.SaveConfig(cfg, true)
 	if err != nil {
@@ -150,13 +164,93 @@ func getAudits(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audits, err := c.App.GetAuditsPage("", c.Params.Page, c.Params.PerPage)
+	query := r.URL.Query()
+
+	options := &model.AuditSearchOptions{
+		UserId:     query.Get("user_id"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+	}
+
+	if since := query.Get("since"); since != "" {
+		var err error
+		if options.Since, err = strconv.ParseInt(since, 10, 64); err != nil {
+			c.SetInvalidUrlParam("since")
+			return
+		}
+	}
+
+	if until := query.Get("until"); until != "" {
+		var err error
+		if options.Until, err = strconv.ParseInt(until, 10, 64); err != nil {
+			c.SetInvalidUrlParam("until")
+			return
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		var ok bool
+		if options.Cursor, ok = model.AuditCursorFromString(cursor); !ok {
+			c.SetInvalidUrlParam("cursor")
+			return
+		}
+	}
+
+	if err := options.IsValid(); err != nil {
+		c.Err = err
+		return
+	}
+
+	if format := query.Get("format"); format != "" {
+		if format != model.AUDIT_EXPORT_FORMAT_CSV && format != model.AUDIT_EXPORT_FORMAT_JSON {
+			c.SetInvalidUrlParam("format")
+			return
+		}
+
+		c.LogAudit("format=" + format + " " + options.ToQueryString())
+
+		if format == model.AUDIT_EXPORT_FORMAT_CSV {
+			w.Header().Set("Content-Type", "text/csv")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename=\"audits."+format+"\"")
+
+		if err := c.App.ExportAudits(options, format, w); err != nil {
+			// The response has already started so the error can only be logged.
+			mlog.Error(err.Error())
+		}
+		return
+	}
+
+	// Filtered audits are paged through using cursors, which unlike pages stay stable as new audits are recorded.
+	// Pages are still supported on their own for existing clients.
+	if c.Params.Page > 0 {
+		if *options != (model.AuditSearchOptions{}) {
+			c.SetInvalidUrlParam("page")
+			return
+		}
+
+		audits, err := c.App.GetAuditsPage("", c.Params.Page, c.Params.PerPage)
+		if err != nil {
+			c.Err = err
+			return
+		}
 
+		w.Write([]byte(audits.ToJson()))
+		return
+	}
+
+	audits, next, err := c.App.SearchAudits(options, c.Params.PerPage)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	if next != nil {
+		w.Header().Set(model.HEADER_NEXT_CURSOR, next.String())
+	}
+
 	w.Write([]byte(audits.ToJson()))
 }
 
@@ -188,6 +282,70 @@ func invalidateCaches(c *Context, w http.ResponseWriter, r *http.Request) {
 	ReturnStatusOK(w)
 }
 
+func getIntegrationTraffic(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write([]byte(c.App.GetIntegrationTrafficReport().ToJson()))
+}
+
+func resetIntegrationTraffic(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	c.App.ResetIntegrationTrafficReport()
+	c.LogAudit("")
+
+	ReturnStatusOK(w)
+}
+
+func getStoreTracing(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write([]byte(c.App.GetStoreTracingReport().ToJson()))
+}
+
+func resetStoreTracing(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	c.App.ResetStoreTracingReport()
+	c.LogAudit("")
+
+	ReturnStatusOK(w)
+}
+
+func getIntegrationDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	query := r.URL.Query()
+	deliveries, err := c.App.GetIntegrationDeliveries(query.Get("integration_type"), query.Get("integration_id"), c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.IntegrationDeliveryListToJson(deliveries)))
+}
+
+func getErrorCatalog(c *Context, w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(model.ErrorCatalogToJson(model.GetLocalizedErrorCatalog(c.T))))
+}
+
 func getLogs(c *Context, w http.ResponseWriter, r *http.Request) {
 	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
 		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
@@ -274,6 +432,46 @@ func getEnvironmentConfig(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(model.StringInterfaceToJson(envConfig)))
 }
 
+func exportConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	export := c.App.ExportConfig()
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write([]byte(export.ToJson()))
+}
+
+func importConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	export := model.ConfigExportFromJson(r.Body)
+	if export == nil || export.Config == nil {
+		c.SetInvalidParam("config")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	result, err := c.App.ImportConfig(export.Config, dryRun)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !dryRun {
+		c.LogAudit(fmt.Sprintf("importConfig changes=%v", len(result.Changes)))
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write([]byte(result.ToJson()))
+}
+
 func getClientLicense(c *Context, w http.ResponseWriter, r *http.Request) {
 	format := r.URL.Query().Get("format")
 
@@ -314,7 +512,7 @@ func addLicense(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	err := r.ParseMultipartForm(*c.App.Config().FileSettings.MaxFileSize)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.Err = model.NewAppError("addLicense", "api.license.add_license.parse.app_error", nil, err.Error(), http.StatusBadRequest)
 		return
 	}
 
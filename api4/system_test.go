@@ -1,12 +1,15 @@
 package api4
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -211,6 +214,56 @@ func TestGetEnvironmentConfig(t *testing.T) {
 	})
 }
 
+func TestExportImportConfig(t *testing.T) {
+	os.Setenv("MM_SERVICESETTINGS_SITEURL", "http://example.mattermost.com")
+	defer os.Unsetenv("MM_SERVICESETTINGS_SITEURL")
+
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	_, resp := th.Client.ExportConfig()
+	CheckForbiddenStatus(t, resp)
+
+	export, resp := th.SystemAdminClient.ExportConfig()
+	CheckNoError(t, resp)
+	assert.Equal(t, "http://example.mattermost.com", *export.Config.ServiceSettings.SiteURL)
+	assert.Contains(t, export.EnvironmentOverrides, "ServiceSettings.SiteURL")
+	assert.Equal(t, model.FAKE_SETTING, *export.Config.SqlSettings.DataSource)
+
+	_, resp = th.Client.ImportConfig(export, true)
+	CheckForbiddenStatus(t, resp)
+
+	siteName := th.App.Config().TeamSettings.SiteName
+	export.Config.TeamSettings.SiteName = "Imported"
+	*export.Config.ServiceSettings.SiteURL = "http://imported.mattermost.com"
+
+	result, resp := th.SystemAdminClient.ImportConfig(export, true)
+	CheckNoError(t, resp)
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, "ServiceSettings.SiteURL", result.Changes[0].Setting)
+	assert.True(t, result.Changes[0].EnvironmentOverride)
+	assert.Equal(t, "TeamSettings.SiteName", result.Changes[1].Setting)
+	assert.Equal(t, siteName, result.Changes[1].OldValue)
+	assert.Equal(t, "Imported", result.Changes[1].NewValue)
+	assert.False(t, result.RequiresRestart)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, siteName, th.App.Config().TeamSettings.SiteName)
+
+	result, resp = th.SystemAdminClient.ImportConfig(export, false)
+	CheckNoError(t, resp)
+	require.Len(t, result.Changes, 2)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, "Imported", th.App.Config().TeamSettings.SiteName)
+	assert.Equal(t, "http://example.mattermost.com", *th.App.Config().ServiceSettings.SiteURL, "should still be overridden by the environment")
+
+	*export.Config.TeamSettings.MaxUsersPerTeam = 0
+	_, resp = th.SystemAdminClient.ImportConfig(export, true)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.ImportConfig(&model.ConfigExport{}, true)
+	CheckBadRequestStatus(t, resp)
+}
+
 func TestGetOldClientConfig(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -385,6 +438,72 @@ func TestGetAudits(t *testing.T) {
 	CheckUnauthorizedStatus(t, resp)
 }
 
+func TestSearchAudits(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	// Each login is audited
+	for i := 0; i < 3; i++ {
+		th.LoginBasic()
+	}
+
+	options := &model.AuditSearchOptions{UserId: th.BasicUser.Id, TargetType: "users"}
+
+	audits, next, resp := th.SystemAdminClient.SearchAudits(options, 2)
+	CheckNoError(t, resp)
+
+	if len(audits) != 2 || next == nil {
+		t.Fatal("should have returned a full page with a cursor")
+	}
+
+	for _, audit := range audits {
+		if audit.UserId != th.BasicUser.Id || audit.TargetType() != "users" {
+			t.Fatal("should only have returned audits matching the filters")
+		}
+	}
+
+	options.Cursor = next
+	moreAudits, _, resp := th.SystemAdminClient.SearchAudits(options, 2)
+	CheckNoError(t, resp)
+
+	if len(moreAudits) == 0 || moreAudits[0].Id == audits[0].Id || moreAudits[0].Id == audits[1].Id {
+		t.Fatal("should have returned the next page")
+	}
+
+	options.Cursor = nil
+	data, resp := th.SystemAdminClient.ExportAudits(options, "csv")
+	CheckNoError(t, resp)
+
+	if records, err := csv.NewReader(bytes.NewReader(data)).ReadAll(); err != nil {
+		t.Fatal(err)
+	} else if len(records) < 4 || records[0][0] != "id" {
+		t.Fatal("should have exported a header and every matching audit")
+	}
+
+	data, resp = th.SystemAdminClient.ExportAudits(options, "json")
+	CheckNoError(t, resp)
+
+	if exported := model.AuditsFromJson(bytes.NewReader(data)); len(exported) < 3 {
+		t.Fatal("should have exported every matching audit")
+	}
+
+	_, resp = th.SystemAdminClient.ExportAudits(options, "xml")
+	CheckBadRequestStatus(t, resp)
+
+	_, _, resp = th.SystemAdminClient.SearchAudits(&model.AuditSearchOptions{TargetType: "users%"}, 10)
+	CheckBadRequestStatus(t, resp)
+
+	_, _, resp = th.SystemAdminClient.SearchAudits(&model.AuditSearchOptions{Since: 10, Until: 5}, 10)
+	CheckBadRequestStatus(t, resp)
+
+	_, _, resp = Client.SearchAudits(options, 10)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.ExportAudits(options, "csv")
+	CheckForbiddenStatus(t, resp)
+}
+
 func TestEmailTest(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -702,6 +821,30 @@ func TestSupportedTimezones(t *testing.T) {
 	assert.Equal(t, supportedTimezonesFromConfig, supportedTimezones)
 }
 
+func TestGetErrorCatalog(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+
+	catalog, resp := Client.GetErrorCatalog()
+	CheckNoError(t, resp)
+	require.Len(t, catalog, len(model.ErrorCatalog))
+
+	for _, entry := range catalog {
+		assert.NotEmpty(t, entry.Code)
+		assert.NotEqual(t, model.GetErrorCodeHintId(entry.Code), entry.Hint, "hints should be translated")
+	}
+
+	_, resp = Client.GetUser("junk", "")
+	CheckBadRequestStatus(t, resp)
+	assert.Equal(t, model.ERROR_CODE_INVALID_PARAM, resp.Error.Code)
+	assert.NotEmpty(t, resp.Error.Hint)
+
+	_, resp = Client.GetUser(model.NewId(), "")
+	CheckNotFoundStatus(t, resp)
+	assert.Equal(t, model.ERROR_CODE_NOT_FOUND, resp.Error.Code)
+}
+
 func TestRedirectLocation(t *testing.T) {
 	expected := "https://mattermost.com/wp-content/themes/mattermostv2/img/logo-light.svg"
 
@@ -734,3 +877,132 @@ func TestRedirectLocation(t *testing.T) {
 	_, resp = Client.GetRedirectLocation("", "")
 	CheckUnauthorizedStatus(t, resp)
 }
+
+func TestGetIntegrationTraffic(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := th.SystemAdminClient.ResetIntegrationTraffic()
+	CheckNoError(t, resp)
+
+	// The test client doesn't look like a browser, so its requests are counted as coming from a bot
+	_, resp = Client.GetMe("")
+	CheckNoError(t, resp)
+
+	report, resp := th.SystemAdminClient.GetIntegrationTraffic()
+	CheckNoError(t, resp)
+
+	found := false
+	for _, integration := range report.Integrations {
+		if integration.Type == model.INTEGRATION_TYPE_BOT && integration.UserId == th.BasicUser.Id {
+			found = true
+
+			if integration.RequestCount < 1 || integration.Id != "go-http-client" {
+				t.Fatal("should have counted the request", integration)
+			}
+		}
+	}
+
+	if !found {
+		t.Fatal("should have reported the basic user's traffic")
+	}
+
+	_, resp = Client.GetIntegrationTraffic()
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.ResetIntegrationTraffic()
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestGetStoreTracing(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.MetricsSettings.EnableStoreTracing = true })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.MetricsSettings.EnableStoreTracing = false })
+
+	_, resp := th.SystemAdminClient.ResetStoreTracing()
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetPinnedPosts(th.BasicChannel.Id, "")
+	CheckNoError(t, resp)
+
+	report, resp := th.SystemAdminClient.GetStoreTracing()
+	CheckNoError(t, resp)
+	require.True(t, report.Enabled)
+
+	found := false
+	for _, method := range report.Methods {
+		if method.Method == "sqlstore.SqlChannelStore.GetPinnedPosts" {
+			found = true
+			assert.True(t, method.CallCount >= 1)
+		}
+	}
+	assert.True(t, found, "should have traced getting the pinned posts")
+
+	_, resp = Client.GetStoreTracing()
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.ResetStoreTracing()
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestGetIntegrationDeliveries(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableIncomingWebhooks = true })
+
+	hook, resp := th.SystemAdminClient.CreateIncomingWebhook(&model.IncomingWebhook{ChannelId: th.BasicChannel.Id})
+	CheckNoError(t, resp)
+
+	post := func(body string) {
+		r, err := http.Post(Client.Url+"/hooks/"+hook.Id, "application/json", strings.NewReader(body))
+		require.Nil(t, err)
+		r.Body.Close()
+	}
+	post(`{"text": "hello"}`)
+	post(`{"text": ""}`)
+
+	// Deliveries are recorded in the background
+	var deliveries []*model.IntegrationDelivery
+	for i := 0; i < 50 && len(deliveries) < 2; i++ {
+		time.Sleep(100 * time.Millisecond)
+		deliveries, resp = th.SystemAdminClient.GetIntegrationDeliveries(model.INTEGRATION_TYPE_INCOMING_WEBHOOK, hook.Id, 0, 10)
+		CheckNoError(t, resp)
+	}
+	require.Len(t, deliveries, 2)
+
+	var succeeded, failed *model.IntegrationDelivery
+	for _, delivery := range deliveries {
+		assert.Equal(t, model.INTEGRATION_TYPE_INCOMING_WEBHOOK, delivery.IntegrationType)
+		assert.Equal(t, hook.Id, delivery.IntegrationId)
+
+		if delivery.StatusCode == http.StatusOK {
+			succeeded = delivery
+		} else {
+			failed = delivery
+		}
+	}
+
+	require.NotNil(t, succeeded)
+	assert.Contains(t, succeeded.Request, "hello")
+	assert.Empty(t, succeeded.Error)
+
+	require.NotNil(t, failed)
+	assert.Equal(t, http.StatusBadRequest, failed.StatusCode)
+	assert.NotEmpty(t, failed.Error)
+
+	deliveries, resp = th.SystemAdminClient.GetIntegrationDeliveries(model.INTEGRATION_TYPE_COMMAND, hook.Id, 0, 10)
+	CheckNoError(t, resp)
+	assert.Empty(t, deliveries)
+
+	_, resp = th.SystemAdminClient.GetIntegrationDeliveries("junk", "", 0, 10)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetIntegrationDeliveries("", "", 0, 10)
+	CheckForbiddenStatus(t, resp)
+}
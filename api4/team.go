@@ -21,36 +21,40 @@ const (
 )
 
 func (api *API) InitTeam() {
-	api.BaseRoutes.Teams.Handle("", api.ApiSessionRequired(createTeam)).Methods("POST")
-	api.BaseRoutes.Teams.Handle("", api.ApiSessionRequired(getAllTeams)).Methods("GET")
+	api.BaseRoutes.Teams.Handle("", api.ApiSessionRequiredWithScope(createTeam, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("POST")
+	api.BaseRoutes.Teams.Handle("", api.ApiSessionRequiredWithScope(getAllTeams, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
 	api.BaseRoutes.Teams.Handle("/{team_id:[A-Za-z0-9]+}/scheme", api.ApiSessionRequired(updateTeamScheme)).Methods("PUT")
-	api.BaseRoutes.Teams.Handle("/search", api.ApiSessionRequired(searchTeams)).Methods("POST")
-	api.BaseRoutes.TeamsForUser.Handle("", api.ApiSessionRequired(getTeamsForUser)).Methods("GET")
-	api.BaseRoutes.TeamsForUser.Handle("/unread", api.ApiSessionRequired(getTeamsUnreadForUser)).Methods("GET")
+	api.BaseRoutes.Teams.Handle("/search", api.ApiSessionRequiredWithScope(searchTeams, model.OAUTH_SCOPE_READ_TEAMS)).Methods("POST")
+	api.BaseRoutes.TeamsForUser.Handle("", api.ApiSessionRequiredWithScope(getTeamsForUser, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.TeamsForUser.Handle("/unread", api.ApiSessionRequiredWithScope(getTeamsUnreadForUser, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
 
-	api.BaseRoutes.Team.Handle("", api.ApiSessionRequired(getTeam)).Methods("GET")
-	api.BaseRoutes.Team.Handle("", api.ApiSessionRequired(updateTeam)).Methods("PUT")
+	api.BaseRoutes.Team.Handle("", api.ApiSessionRequiredWithScope(getTeam, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.Team.Handle("", api.ApiSessionRequiredWithScope(updateTeam, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("PUT")
 	api.BaseRoutes.Team.Handle("", api.ApiSessionRequired(deleteTeam)).Methods("DELETE")
-	api.BaseRoutes.Team.Handle("/patch", api.ApiSessionRequired(patchTeam)).Methods("PUT")
-	api.BaseRoutes.Team.Handle("/stats", api.ApiSessionRequired(getTeamStats)).Methods("GET")
-
-	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequiredTrustRequester(getTeamIcon)).Methods("GET")
-	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequired(setTeamIcon)).Methods("POST")
-	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequired(removeTeamIcon)).Methods("DELETE")
-
-	api.BaseRoutes.TeamMembers.Handle("", api.ApiSessionRequired(getTeamMembers)).Methods("GET")
-	api.BaseRoutes.TeamMembers.Handle("/ids", api.ApiSessionRequired(getTeamMembersByIds)).Methods("POST")
-	api.BaseRoutes.TeamMembersForUser.Handle("", api.ApiSessionRequired(getTeamMembersForUser)).Methods("GET")
-	api.BaseRoutes.TeamMembers.Handle("", api.ApiSessionRequired(addTeamMember)).Methods("POST")
-	api.BaseRoutes.Teams.Handle("/members/invite", api.ApiSessionRequired(addUserToTeamFromInvite)).Methods("POST")
-	api.BaseRoutes.TeamMembers.Handle("/batch", api.ApiSessionRequired(addTeamMembers)).Methods("POST")
-	api.BaseRoutes.TeamMember.Handle("", api.ApiSessionRequired(removeTeamMember)).Methods("DELETE")
-
-	api.BaseRoutes.TeamForUser.Handle("/unread", api.ApiSessionRequired(getTeamUnread)).Methods("GET")
-
-	api.BaseRoutes.TeamByName.Handle("", api.ApiSessionRequired(getTeamByName)).Methods("GET")
-	api.BaseRoutes.TeamMember.Handle("", api.ApiSessionRequired(getTeamMember)).Methods("GET")
-	api.BaseRoutes.TeamByName.Handle("/exists", api.ApiSessionRequired(teamExists)).Methods("GET")
+	api.BaseRoutes.Team.Handle("/patch", api.ApiSessionRequiredWithScope(patchTeam, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("PUT")
+	api.BaseRoutes.Team.Handle("/deletion", api.ApiSessionRequired(scheduleTeamDeletion)).Methods("POST")
+	api.BaseRoutes.Team.Handle("/deletion", api.ApiSessionRequired(cancelTeamDeletion)).Methods("DELETE")
+	api.BaseRoutes.Team.Handle("/stats", api.ApiSessionRequiredWithScope(getTeamStats, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.Team.Handle("/upload_policy", api.ApiSessionRequired(getTeamUploadPolicy)).Methods("GET")
+	api.BaseRoutes.Team.Handle("/upload_policy", api.ApiSessionRequired(updateTeamUploadPolicy)).Methods("PUT")
+
+	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequiredTrustRequesterWithScope(getTeamIcon, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequiredWithScope(setTeamIcon, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("POST")
+	api.BaseRoutes.Team.Handle("/image", api.ApiSessionRequiredWithScope(removeTeamIcon, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("DELETE")
+
+	api.BaseRoutes.TeamMembers.Handle("", api.ApiSessionRequiredWithScope(getTeamMembers, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.TeamMembers.Handle("/ids", api.ApiSessionRequiredWithScope(getTeamMembersByIds, model.OAUTH_SCOPE_READ_TEAMS)).Methods("POST")
+	api.BaseRoutes.TeamMembersForUser.Handle("", api.ApiSessionRequiredWithScope(getTeamMembersForUser, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.TeamMembers.Handle("", api.ApiSessionRequiredWithScope(addTeamMember, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("POST")
+	api.BaseRoutes.Teams.Handle("/members/invite", api.ApiSessionRequiredWithScope(addUserToTeamFromInvite, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("POST")
+	api.BaseRoutes.TeamMembers.Handle("/batch", api.ApiSessionRequiredWithScope(addTeamMembers, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("POST")
+	api.BaseRoutes.TeamMember.Handle("", api.ApiSessionRequiredWithScope(removeTeamMember, model.OAUTH_SCOPE_WRITE_TEAMS)).Methods("DELETE")
+
+	api.BaseRoutes.TeamForUser.Handle("/unread", api.ApiSessionRequiredWithScope(getTeamUnread, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+
+	api.BaseRoutes.TeamByName.Handle("", api.ApiSessionRequiredWithScope(getTeamByName, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.TeamMember.Handle("", api.ApiSessionRequiredWithScope(getTeamMember, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
+	api.BaseRoutes.TeamByName.Handle("/exists", api.ApiSessionRequiredWithScope(teamExists, model.OAUTH_SCOPE_READ_TEAMS)).Methods("GET")
 	api.BaseRoutes.TeamMember.Handle("/roles", api.ApiSessionRequired(updateTeamMemberRoles)).Methods("PUT")
 	api.BaseRoutes.TeamMember.Handle("/schemeRoles", api.ApiSessionRequired(updateTeamMemberSchemeRoles)).Methods("PUT")
 	api.BaseRoutes.Team.Handle("/import", api.ApiSessionRequired(importTeam)).Methods("POST")
@@ -70,6 +74,9 @@ func createTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Upload policies can only be set by system admins through their own endpoint
+	team.UploadPolicy = nil
+
 	rteam, err := c.App.CreateTeamWithUser(team, c.Session.UserId)
 	if err != nil {
 		c.Err = err
@@ -211,6 +218,57 @@ func deleteTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 	ReturnStatusOK(w)
 }
 
+func scheduleTeamDeletion(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, c.Params.TeamId, model.PERMISSION_MANAGE_TEAM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_TEAM)
+		return
+	}
+
+	if !*c.App.Config().ServiceSettings.EnableAPITeamDeletion {
+		c.Err = model.NewAppError("scheduleTeamDeletion", "api.team.schedule_team_deletion.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return
+	}
+
+	team, err := c.App.ScheduleTeamDeletion(c.Params.TeamId, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("team_id=" + team.Id)
+
+	c.App.SanitizeTeam(c.Session, team)
+	w.Write([]byte(team.ToJson()))
+}
+
+func cancelTeamDeletion(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, c.Params.TeamId, model.PERMISSION_MANAGE_TEAM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_TEAM)
+		return
+	}
+
+	team, err := c.App.CancelTeamDeletion(c.Params.TeamId, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("team_id=" + team.Id)
+
+	c.App.SanitizeTeam(c.Session, team)
+	w.Write([]byte(team.ToJson()))
+}
+
 func getTeamsForUser(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -916,3 +974,59 @@ func updateTeamScheme(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	ReturnStatusOK(w)
 }
+
+func getTeamUploadPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, c.Params.TeamId, model.PERMISSION_VIEW_TEAM) {
+		c.SetPermissionError(model.PERMISSION_VIEW_TEAM)
+		return
+	}
+
+	team, err := c.App.GetTeam(c.Params.TeamId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	policy := team.UploadPolicy
+	if policy == nil {
+		policy = &model.TeamUploadPolicy{}
+	}
+
+	w.Write([]byte(policy.ToJson()))
+}
+
+func updateTeamUploadPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	policy := model.TeamUploadPolicyFromJson(r.Body)
+	if policy == nil {
+		c.SetInvalidParam("upload_policy")
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	team, err := c.App.UpdateTeamUploadPolicy(c.Params.TeamId, policy)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("upload_policy=" + policy.ToJson())
+
+	if team.UploadPolicy == nil {
+		team.UploadPolicy = &model.TeamUploadPolicy{}
+	}
+	w.Write([]byte(team.UploadPolicy.ToJson()))
+}
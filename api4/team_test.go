@@ -555,6 +555,53 @@ func TestPermanentDeleteTeam(t *testing.T) {
 	}
 }
 
+func TestScheduleTeamDeletion(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	team := &model.Team{DisplayName: "DisplayName", Name: GenerateTestTeamName(), Email: th.GenerateTestEmail(), Type: model.TEAM_OPEN}
+	team, _ = Client.CreateTeam(team)
+
+	enableAPITeamDeletion := *th.App.Config().ServiceSettings.EnableAPITeamDeletion
+	defer func() {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableAPITeamDeletion = &enableAPITeamDeletion })
+	}()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableAPITeamDeletion = false })
+
+	_, resp := Client.ScheduleTeamDeletion(team.Id)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableAPITeamDeletion = true })
+
+	_, resp = Client.ScheduleTeamDeletion(th.BasicTeam.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.CancelTeamDeletion(team.Id)
+	CheckBadRequestStatus(t, resp)
+
+	scheduled, resp := Client.ScheduleTeamDeletion(team.Id)
+	CheckNoError(t, resp)
+	assert.True(t, scheduled.DeleteAt > 0)
+	assert.True(t, scheduled.ScheduledDeleteAt > scheduled.DeleteAt)
+
+	_, resp = Client.ScheduleTeamDeletion(team.Id)
+	CheckBadRequestStatus(t, resp)
+
+	restored, resp := Client.CancelTeamDeletion(team.Id)
+	CheckNoError(t, resp)
+	assert.Equal(t, int64(0), restored.DeleteAt)
+	assert.Equal(t, int64(0), restored.ScheduledDeleteAt)
+
+	_, resp = th.SystemAdminClient.ScheduleTeamDeletion(team.Id)
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.CancelTeamDeletion(team.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetAllTeams(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -2212,3 +2259,67 @@ func TestUpdateTeamScheme(t *testing.T) {
 	_, resp = th.SystemAdminClient.UpdateTeamScheme(team.Id, teamScheme.Id)
 	CheckUnauthorizedStatus(t, resp)
 }
+
+func TestTeamUploadPolicy(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	policy, resp := Client.GetTeamUploadPolicy(th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	require.True(t, policy.IsEmpty())
+
+	_, resp = Client.UpdateTeamUploadPolicy(th.BasicTeam.Id, &model.TeamUploadPolicy{BlockExecutables: true})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateTeamUploadPolicy(th.BasicTeam.Id, &model.TeamUploadPolicy{AllowedExtensions: []string{"tar.gz"}})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateTeamUploadPolicy(model.NewId(), &model.TeamUploadPolicy{BlockExecutables: true})
+	CheckNotFoundStatus(t, resp)
+
+	policy, resp = th.SystemAdminClient.UpdateTeamUploadPolicy(th.BasicTeam.Id, &model.TeamUploadPolicy{
+		AllowedExtensions: []string{".PNG", "txt"},
+		BlockExecutables:  true,
+	})
+	CheckNoError(t, resp)
+	require.Equal(t, []string{"png", "txt"}, policy.AllowedExtensions)
+
+	policy, resp = Client.GetTeamUploadPolicy(th.BasicTeam.Id)
+	CheckNoError(t, resp)
+	require.Equal(t, []string{"png", "txt"}, policy.AllowedExtensions)
+	require.True(t, policy.BlockExecutables)
+
+	data, err := readTestFile("test.png")
+	require.Nil(t, err)
+
+	_, resp = Client.UploadFile(data, th.BasicChannel.Id, "test.png")
+	CheckNoError(t, resp)
+
+	_, resp = Client.UploadFile([]byte("plain text"), th.BasicChannel.Id, "notes.txt")
+	CheckNoError(t, resp)
+
+	_, resp = Client.UploadFile(data, th.BasicChannel.Id, "test.txt")
+	CheckBadRequestStatus(t, resp)
+	require.Equal(t, model.ERROR_CODE_FILE_REJECTED, resp.Error.Code)
+
+	_, resp = Client.UploadFile([]byte("#!/bin/sh\n"), th.BasicChannel.Id, "notes.txt")
+	CheckBadRequestStatus(t, resp)
+	require.Equal(t, model.ERROR_CODE_FILE_REJECTED, resp.Error.Code)
+
+	_, resp = Client.UploadFile([]byte("plain text"), th.BasicChannel.Id, "notes.md")
+	CheckBadRequestStatus(t, resp)
+
+	// Direct messages aren't part of the team, so they aren't restricted
+	dm, resp := Client.CreateDirectChannel(th.BasicUser.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	_, resp = Client.UploadFile([]byte("plain text"), dm.Id, "notes.md")
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateTeamUploadPolicy(th.BasicTeam.Id, &model.TeamUploadPolicy{})
+	CheckNoError(t, resp)
+
+	_, resp = Client.UploadFile([]byte("plain text"), th.BasicChannel.Id, "notes.md")
+	CheckNoError(t, resp)
+}
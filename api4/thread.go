@@ -0,0 +1,109 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitThread() {
+	api.BaseRoutes.ThreadsForUser.Handle("", api.ApiSessionRequired(getThreadsForUser)).Methods("GET")
+	api.BaseRoutes.ThreadForUser.Handle("", api.ApiSessionRequired(getThreadForUser)).Methods("GET")
+	api.BaseRoutes.ThreadForUser.Handle("/following", api.ApiSessionRequired(followThread)).Methods("PUT")
+	api.BaseRoutes.ThreadForUser.Handle("/following", api.ApiSessionRequired(unfollowThread)).Methods("DELETE")
+	api.BaseRoutes.ThreadForUser.Handle("/read", api.ApiSessionRequired(markThreadAsRead)).Methods("PUT")
+}
+
+func getThreadsForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	threads, err := c.App.GetThreadsForUser(c.Params.UserId, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.ThreadsToJson(threads)))
+}
+
+// requireThreadPermissions checks that the session can act for the user and that the user can still read the thread.
+func requireThreadPermissions(c *Context) {
+	c.RequireUserId().RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+}
+
+func getThreadForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireThreadPermissions(c)
+	if c.Err != nil {
+		return
+	}
+
+	thread, err := c.App.GetThreadForUser(c.Params.UserId, c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(thread.ToJson()))
+}
+
+func followThread(c *Context, w http.ResponseWriter, r *http.Request) {
+	setThreadFollowing(c, w, true)
+}
+
+func unfollowThread(c *Context, w http.ResponseWriter, r *http.Request) {
+	setThreadFollowing(c, w, false)
+}
+
+func setThreadFollowing(c *Context, w http.ResponseWriter, following bool) {
+	requireThreadPermissions(c)
+	if c.Err != nil {
+		return
+	}
+
+	thread, err := c.App.SetThreadFollowing(c.Params.UserId, c.Params.PostId, following)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(thread.ToJson()))
+}
+
+func markThreadAsRead(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireThreadPermissions(c)
+	if c.Err != nil {
+		return
+	}
+
+	thread, err := c.App.MarkThreadAsRead(c.Params.UserId, c.Params.PostId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(thread.ToJson()))
+}
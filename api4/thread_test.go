@@ -0,0 +1,105 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestThreadFollowing(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	root := th.CreatePost()
+
+	threads, resp := Client.GetThreadsForUser(th.BasicUser.Id, 0, 60)
+	CheckNoError(t, resp)
+
+	if len(threads) != 0 {
+		t.Fatal("shouldn't follow a thread without replies")
+	}
+
+	th.LoginBasic2()
+	_, resp = Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, RootId: root.Id, Message: "reply"})
+	CheckNoError(t, resp)
+
+	thread, resp := Client.GetThreadForUser(th.BasicUser2.Id, root.Id)
+	CheckNoError(t, resp)
+
+	if !thread.Following || thread.UnreadReplies != 0 {
+		t.Fatal("should have followed the thread after replying to it")
+	}
+
+	th.LoginBasic()
+	threads, resp = Client.GetThreadsForUser(th.BasicUser.Id, 0, 60)
+	CheckNoError(t, resp)
+
+	if len(threads) != 1 || threads[0].PostId != root.Id || threads[0].UnreadReplies != 1 {
+		t.Fatal("should have followed the thread after someone replied to the root post")
+	}
+
+	thread, resp = Client.MarkThreadAsRead(th.BasicUser.Id, root.Id)
+	CheckNoError(t, resp)
+
+	if thread.UnreadReplies != 0 {
+		t.Fatal("should have read the thread")
+	}
+
+	thread, resp = Client.UnfollowThread(th.BasicUser.Id, root.Id)
+	CheckNoError(t, resp)
+
+	if thread.Following {
+		t.Fatal("should have stopped following the thread")
+	}
+
+	th.LoginBasic2()
+	_, resp = Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, RootId: root.Id, Message: "another reply"})
+	CheckNoError(t, resp)
+
+	th.LoginBasic()
+	thread, resp = Client.GetThreadForUser(th.BasicUser.Id, root.Id)
+	CheckNoError(t, resp)
+
+	if thread.Following {
+		t.Fatal("shouldn't have followed the thread again when someone else replied")
+	}
+
+	thread, resp = Client.FollowThread(th.BasicUser.Id, root.Id)
+	CheckNoError(t, resp)
+
+	if !thread.Following || thread.UnreadReplies != 1 {
+		t.Fatal("should have followed the thread again")
+	}
+
+	reply, resp := Client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, RootId: root.Id, Message: "own reply"})
+	CheckNoError(t, resp)
+
+	_, resp = Client.FollowThread(th.BasicUser.Id, reply.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.FollowThread(th.BasicUser.Id, model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.MarkThreadAsRead(th.BasicUser.Id, th.CreatePost().Id)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.FollowThread(th.BasicUser2.Id, root.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetThreadsForUser(th.BasicUser.Id, 0, 60)
+	CheckNoError(t, resp)
+
+	privateChannel := th.CreatePrivateChannel()
+	privatePost := th.CreatePostWithClient(Client, privateChannel)
+	th.LoginBasic2()
+	_, resp = Client.FollowThread(th.BasicUser2.Id, privatePost.Id)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetThreadsForUser(th.BasicUser.Id, 0, 60)
+	CheckUnauthorizedStatus(t, resp)
+}
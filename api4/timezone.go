@@ -0,0 +1,61 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitTimezone() {
+	api.BaseRoutes.User.Handle("/timezone", api.ApiSessionRequired(getUserTimezone)).Methods("GET")
+	api.BaseRoutes.User.Handle("/timezone", api.ApiSessionRequired(updateUserTimezone)).Methods("PUT")
+}
+
+func getUserTimezone(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	timezone, err := c.App.GetUserTimezone(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(timezone.ToJson()))
+}
+
+func updateUserTimezone(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	timezone := model.UserTimezoneFromJson(r.Body)
+	if timezone == nil {
+		c.SetInvalidParam("timezone")
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	updatedTimezone, err := c.App.UpdateUserTimezone(c.Params.UserId, timezone)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(updatedTimezone.ToJson()))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestUserTimezone(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	timezone, resp := Client.GetUserTimezone(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if !timezone.UseAutomaticTimezone {
+		t.Fatal("should default to the automatic timezone")
+	}
+
+	updated, resp := Client.UpdateUserTimezone(th.BasicUser.Id, &model.UserTimezone{ManualTimezone: "America/Toronto"})
+	CheckNoError(t, resp)
+
+	if updated.UseAutomaticTimezone || updated.ManualTimezone != "America/Toronto" {
+		t.Fatal("should have updated the timezone")
+	}
+
+	user, resp := Client.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	if user.GetPreferredTimezone() != "America/Toronto" {
+		t.Fatal("should have saved the timezone on the user")
+	}
+
+	_, resp = Client.UpdateUserTimezone(th.BasicUser.Id, &model.UserTimezone{ManualTimezone: "Mars/Olympus_Mons"})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetUserTimezone(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.UpdateUserTimezone(th.BasicUser2.Id, &model.UserTimezone{UseAutomaticTimezone: true})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetUserTimezone(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.DisplaySettings.EnableTimezone = false })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.DisplaySettings.EnableTimezone = true })
+
+	_, resp = Client.GetUserTimezone(th.BasicUser.Id)
+	CheckNotImplementedStatus(t, resp)
+}
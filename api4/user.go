@@ -19,18 +19,21 @@ import (
 
 func (api *API) InitUser() {
 	api.BaseRoutes.Users.Handle("", api.ApiHandler(createUser)).Methods("POST")
-	api.BaseRoutes.Users.Handle("", api.ApiSessionRequired(getUsers)).Methods("GET")
-	api.BaseRoutes.Users.Handle("/ids", api.ApiSessionRequired(getUsersByIds)).Methods("POST")
-	api.BaseRoutes.Users.Handle("/usernames", api.ApiSessionRequired(getUsersByNames)).Methods("POST")
-	api.BaseRoutes.Users.Handle("/search", api.ApiSessionRequired(searchUsers)).Methods("POST")
-	api.BaseRoutes.Users.Handle("/autocomplete", api.ApiSessionRequired(autocompleteUsers)).Methods("GET")
+	api.BaseRoutes.Users.Handle("", api.ApiSessionRequiredWithScope(getUsers, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
+	api.BaseRoutes.Users.Handle("/ids", api.ApiSessionRequiredWithScope(getUsersByIds, model.OAUTH_SCOPE_READ_USERS)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/usernames", api.ApiSessionRequiredWithScope(getUsersByNames, model.OAUTH_SCOPE_READ_USERS)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/search", api.ApiSessionRequiredWithScope(searchUsers, model.OAUTH_SCOPE_READ_USERS)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/autocomplete", api.ApiSessionRequiredWithScope(autocompleteUsers, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
 	api.BaseRoutes.Users.Handle("/stats", api.ApiSessionRequired(getTotalUsersStats)).Methods("GET")
-
-	api.BaseRoutes.User.Handle("", api.ApiSessionRequired(getUser)).Methods("GET")
-	api.BaseRoutes.User.Handle("/image", api.ApiSessionRequiredTrustRequester(getProfileImage)).Methods("GET")
-	api.BaseRoutes.User.Handle("/image", api.ApiSessionRequired(setProfileImage)).Methods("POST")
-	api.BaseRoutes.User.Handle("", api.ApiSessionRequired(updateUser)).Methods("PUT")
-	api.BaseRoutes.User.Handle("/patch", api.ApiSessionRequired(patchUser)).Methods("PUT")
+	api.BaseRoutes.Users.Handle("/directory", api.ApiSessionRequired(getUserDirectory)).Methods("GET")
+	api.BaseRoutes.Users.Handle("/external/{service:[A-Za-z0-9_-]+}/{external_id:[^/]+}", api.ApiSessionRequired(getUserByRemoteId)).Methods("GET")
+	api.BaseRoutes.Users.Handle("/inactive/preview", api.ApiSessionRequired(getInactiveUsersPreview)).Methods("GET")
+
+	api.BaseRoutes.User.Handle("", api.ApiSessionRequiredWithScope(getUser, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
+	api.BaseRoutes.User.Handle("/image", api.ApiSessionRequiredTrustRequesterWithScope(getProfileImage, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
+	api.BaseRoutes.User.Handle("/image", api.ApiSessionRequiredWithScope(setProfileImage, model.OAUTH_SCOPE_WRITE_USERS)).Methods("POST")
+	api.BaseRoutes.User.Handle("", api.ApiSessionRequiredWithScope(updateUser, model.OAUTH_SCOPE_WRITE_USERS)).Methods("PUT")
+	api.BaseRoutes.User.Handle("/patch", api.ApiSessionRequiredWithScope(patchUser, model.OAUTH_SCOPE_WRITE_USERS)).Methods("PUT")
 	api.BaseRoutes.User.Handle("", api.ApiSessionRequired(deleteUser)).Methods("DELETE")
 	api.BaseRoutes.User.Handle("/roles", api.ApiSessionRequired(updateUserRoles)).Methods("PUT")
 	api.BaseRoutes.User.Handle("/active", api.ApiSessionRequired(updateUserActive)).Methods("PUT")
@@ -50,8 +53,8 @@ func (api *API) InitUser() {
 	api.BaseRoutes.Users.Handle("/login/switch", api.ApiHandler(switchAccountType)).Methods("POST")
 	api.BaseRoutes.Users.Handle("/logout", api.ApiHandler(logout)).Methods("POST")
 
-	api.BaseRoutes.UserByUsername.Handle("", api.ApiSessionRequired(getUserByUsername)).Methods("GET")
-	api.BaseRoutes.UserByEmail.Handle("", api.ApiSessionRequired(getUserByEmail)).Methods("GET")
+	api.BaseRoutes.UserByUsername.Handle("", api.ApiSessionRequiredWithScope(getUserByUsername, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
+	api.BaseRoutes.UserByEmail.Handle("", api.ApiSessionRequiredWithScope(getUserByEmail, model.OAUTH_SCOPE_READ_USERS)).Methods("GET")
 
 	api.BaseRoutes.User.Handle("/sessions", api.ApiSessionRequired(getSessions)).Methods("GET")
 	api.BaseRoutes.User.Handle("/sessions/revoke", api.ApiSessionRequired(revokeSession)).Methods("POST")
@@ -59,6 +62,10 @@ func (api *API) InitUser() {
 	api.BaseRoutes.Users.Handle("/sessions/device", api.ApiSessionRequired(attachDeviceId)).Methods("PUT")
 	api.BaseRoutes.User.Handle("/audits", api.ApiSessionRequired(getUserAudits)).Methods("GET")
 
+	api.BaseRoutes.User.Handle("/webpush/subscriptions", api.ApiSessionRequired(createWebPushSubscription)).Methods("POST")
+	api.BaseRoutes.User.Handle("/webpush/subscriptions", api.ApiSessionRequired(getWebPushSubscriptions)).Methods("GET")
+	api.BaseRoutes.User.Handle("/webpush/subscriptions/{subscription_id:[A-Za-z0-9]+}", api.ApiSessionRequired(deleteWebPushSubscription)).Methods("DELETE")
+
 	api.BaseRoutes.User.Handle("/tokens", api.ApiSessionRequired(createUserAccessToken)).Methods("POST")
 	api.BaseRoutes.User.Handle("/tokens", api.ApiSessionRequired(getUserAccessTokensForUser)).Methods("GET")
 	api.BaseRoutes.Users.Handle("/tokens", api.ApiSessionRequired(getUserAccessTokens)).Methods("GET")
@@ -79,7 +86,13 @@ func createUser(c *Context, w http.ResponseWriter, r *http.Request) {
 	tokenId := r.URL.Query().Get("t")
 	inviteId := r.URL.Query().Get("iid")
 
-	// No permission check required
+	if user.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	// Bots can only be created through the bots API
+	user.IsBot = false
 
 	var ruser *model.User
 	var err *model.AppError
@@ -118,7 +131,7 @@ func getUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress)
+	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress) + "." + c.App.Config().PrivacySettings.GetUserFieldVisibilityEtag()
 
 	if c.HandleEtag(etag, "Get User", w, r) {
 		return
@@ -134,6 +147,35 @@ func getUser(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(user.ToJson()))
 }
 
+func getUserByRemoteId(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireService().RequireExternalId()
+	if c.Err != nil {
+		return
+	}
+
+	// No permission check required
+
+	user, err := c.App.GetUserByRemoteId(c.Params.Service, c.Params.ExternalId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress) + "." + c.App.Config().PrivacySettings.GetUserFieldVisibilityEtag()
+
+	if c.HandleEtag(etag, "Get User By Remote Id", w, r) {
+		return
+	}
+
+	if c.Session.UserId == user.Id {
+		user.Sanitize(map[string]bool{})
+	} else {
+		c.App.SanitizeProfile(user, c.IsSystemAdmin())
+	}
+	w.Header().Set(model.HEADER_ETAG_SERVER, etag)
+	w.Write([]byte(user.ToJson()))
+}
+
 func getUserByUsername(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUsername()
 	if c.Err != nil {
@@ -150,7 +192,7 @@ func getUserByUsername(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress)
+	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress) + "." + c.App.Config().PrivacySettings.GetUserFieldVisibilityEtag()
 
 	if c.HandleEtag(etag, "Get User", w, r) {
 		return
@@ -181,7 +223,7 @@ func getUserByEmail(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress)
+	etag := user.Etag(c.App.Config().PrivacySettings.ShowFullName, c.App.Config().PrivacySettings.ShowEmailAddress) + "." + c.App.Config().PrivacySettings.GetUserFieldVisibilityEtag()
 
 	if c.HandleEtag(etag, "Get User", w, r) {
 		return
@@ -211,21 +253,32 @@ func getProfileImage(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	user := users[0]
 	etag := strconv.FormatInt(user.LastPictureUpdate, 10)
-	if c.HandleEtag(etag, "Get Profile Image", w, r) {
+
+	// Responses that say the client's copy is still good need the same caching headers as the image itself. Users
+	// that have never uploaded a picture have a generated one, which has no time that it was last modified.
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%v, public", 24*60*60)) // 24 hrs
+	if user.LastPictureUpdate > 0 {
+		w.Header().Set(model.HEADER_LAST_MODIFIED, time.Unix(0, user.LastPictureUpdate*int64(time.Millisecond)).UTC().Format(http.TimeFormat))
+	}
+
+	if c.HandleEtag(etag, "Get Profile Image", w, r) || c.HandleLastModified(user.LastPictureUpdate, w, r) {
 		return
 	}
 
 	var img []byte
 	img, readFailed, err := c.App.GetProfileImage(user)
 	if err != nil {
+		w.Header().Del("Cache-Control")
+		w.Header().Del(model.HEADER_LAST_MODIFIED)
 		c.Err = err
 		return
 	}
 
 	if readFailed {
+		// The generated image that's returned instead shouldn't be kept in place of the real one for long
 		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%v, public", 5*60)) // 5 mins
+		w.Header().Del(model.HEADER_LAST_MODIFIED)
 	} else {
-		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%v, public", 24*60*60)) // 24 hrs
 		w.Header().Set(model.HEADER_ETAG_SERVER, etag)
 	}
 
@@ -409,6 +462,75 @@ func getUsers(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(model.UserListToJson(profiles)))
 }
 
+func getUserDirectory(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	query := r.URL.Query()
+
+	options := &model.UserDirectoryOptions{
+		Role:        query.Get("role"),
+		AuthService: query.Get("auth_service"),
+		TeamId:      query.Get("team_id"),
+	}
+
+	if lastActivityBefore := query.Get("last_activity_before"); lastActivityBefore != "" {
+		var err error
+		if options.LastActivityBefore, err = strconv.ParseInt(lastActivityBefore, 10, 64); err != nil {
+			c.SetInvalidUrlParam("last_activity_before")
+			return
+		}
+	}
+
+	sort, ok := model.UserDirectorySortFromString(query.Get("sort"))
+	if !ok {
+		c.SetInvalidUrlParam("sort")
+		return
+	}
+	options.Sort = sort
+
+	if err := options.IsValid(); err != nil {
+		c.Err = err
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"users.csv\"")
+
+		if err := c.App.ExportUserDirectoryCsv(options, w); err != nil {
+			// The response has already started so the error can only be logged.
+			mlog.Error(err.Error())
+		}
+		return
+	}
+
+	users, err := c.App.GetUserDirectoryPage(options, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.UserListToJson(users)))
+}
+
+func getInactiveUsersPreview(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	actions, err := c.App.GetInactiveUserActions(model.GetMillis())
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.InactiveUserActionListToJson(actions)))
+}
+
 func getUsersByIds(c *Context, w http.ResponseWriter, r *http.Request) {
 	userIds := model.ArrayFromJson(r.Body)
 
@@ -588,13 +710,13 @@ func updateUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if c.Session.IsOAuth {
-		ouser, err := c.App.GetUser(user.Id)
-		if err != nil {
-			c.Err = err
-			return
-		}
+	ouser, err := c.App.GetUser(user.Id)
+	if err != nil {
+		c.Err = err
+		return
+	}
 
+	if c.Session.IsOAuth {
 		if ouser.Email != user.Email {
 			c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 			c.Err.DetailedError += ", attempted email update by oauth app"
@@ -602,6 +724,8 @@ func updateUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	user.RemoteId = ouser.RemoteId
+
 	ruser, err := c.App.UpdateUserAsUser(user, c.IsSystemAdmin())
 	if err != nil {
 		c.Err = err
@@ -635,6 +759,11 @@ func patchUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if patch.RemoteId != nil && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
 	if c.Session.IsOAuth && patch.Email != nil {
 		if err != nil {
 			c.Err = err
@@ -1321,7 +1450,7 @@ func createUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, c.Params.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1390,7 +1519,7 @@ func getUserAccessTokensForUser(c *Context, w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, c.Params.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1421,7 +1550,7 @@ func getUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, accessToken.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, accessToken.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1450,7 +1579,7 @@ func revokeUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, accessToken.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, accessToken.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1487,7 +1616,7 @@ func disableUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, accessToken.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, accessToken.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1524,7 +1653,7 @@ func enableUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !c.App.SessionHasPermissionToUser(c.Session, accessToken.UserId) {
+	if !c.App.SessionHasPermissionToUserOrBot(c.Session, accessToken.UserId) {
 		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
 		return
 	}
@@ -1538,3 +1667,93 @@ func enableUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.LogAudit("success - token_id=" + accessToken.Id)
 	ReturnStatusOK(w)
 }
+
+func createWebPushSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	subscription := model.WebPushSubscriptionFromJson(r.Body)
+	if subscription == nil {
+		c.SetInvalidParam("subscription")
+		return
+	}
+
+	// Subscriptions are tied to the session that registers them so that they stop receiving notifications on logout.
+	if c.Params.UserId != c.Session.UserId {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	subscription.UserId = c.Session.UserId
+	subscription.SessionId = c.Session.Id
+
+	subscription, err := c.App.RegisterWebPushSubscription(subscription)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("subscription_id=" + subscription.Id)
+
+	subscription.Sanitize()
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(subscription.ToJson()))
+}
+
+func getWebPushSubscriptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	subscriptions, err := c.App.GetWebPushSubscriptionsForUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		subscription.Sanitize()
+	}
+
+	w.Write([]byte(model.WebPushSubscriptionListToJson(subscriptions)))
+}
+
+func deleteWebPushSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireSubscriptionId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	subscription, err := c.App.GetWebPushSubscription(c.Params.SubscriptionId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if subscription.UserId != c.Params.UserId {
+		c.Err = model.NewAppError("deleteWebPushSubscription", "api.user.delete_web_push_subscription.user_id.app_error", nil, "", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.App.DeleteWebPushSubscription(subscription.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("subscription_id=" + subscription.Id)
+	ReturnStatusOK(w)
+}
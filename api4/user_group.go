@@ -0,0 +1,202 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitUserGroup() {
+	api.BaseRoutes.UserGroups.Handle("", api.ApiSessionRequired(createUserGroup)).Methods("POST")
+	api.BaseRoutes.UserGroups.Handle("", api.ApiSessionRequired(getUserGroups)).Methods("GET")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}", api.ApiSessionRequired(getUserGroup)).Methods("GET")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}/patch", api.ApiSessionRequired(patchUserGroup)).Methods("PUT")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}", api.ApiSessionRequired(deleteUserGroup)).Methods("DELETE")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}/members", api.ApiSessionRequired(getUserGroupMembers)).Methods("GET")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}/members", api.ApiSessionRequired(addUserGroupMember)).Methods("POST")
+	api.BaseRoutes.UserGroups.Handle("/{group_id:[A-Za-z0-9]+}/members/{user_id:[A-Za-z0-9]+}", api.ApiSessionRequired(removeUserGroupMember)).Methods("DELETE")
+}
+
+// getManagedUserGroup returns the group from the URL if the session is allowed to change it. Groups can be changed by
+// the users who created them and by system admins.
+func getManagedUserGroup(c *Context) *model.UserGroup {
+	group, err := c.App.GetUserGroup(c.Params.GroupId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if group.CreatorId != c.Session.UserId && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return nil
+	}
+
+	return group
+}
+
+func createUserGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	group := model.UserGroupFromJson(r.Body)
+	if group == nil {
+		c.SetInvalidParam("group")
+		return
+	}
+
+	group.Id = ""
+	group.CreatorId = c.Session.UserId
+
+	rgroup, err := c.App.CreateUserGroup(group)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + rgroup.Id + " name=" + rgroup.Name)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rgroup.ToJson()))
+}
+
+func getUserGroups(c *Context, w http.ResponseWriter, r *http.Request) {
+	groups, err := c.App.GetUserGroups(c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.UserGroupListToJson(groups)))
+}
+
+func getUserGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	group, err := c.App.GetUserGroup(c.Params.GroupId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(group.ToJson()))
+}
+
+func patchUserGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	patch := model.UserGroupPatchFromJson(r.Body)
+	if patch == nil {
+		c.SetInvalidParam("group")
+		return
+	}
+
+	group := getManagedUserGroup(c)
+	if c.Err != nil {
+		return
+	}
+
+	rgroup, err := c.App.PatchUserGroup(group, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + rgroup.Id)
+	w.Write([]byte(rgroup.ToJson()))
+}
+
+func deleteUserGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	if getManagedUserGroup(c); c.Err != nil {
+		return
+	}
+
+	if err := c.App.DeleteUserGroup(c.Params.GroupId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId)
+	ReturnStatusOK(w)
+}
+
+func getUserGroupMembers(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	if _, err := c.App.GetUserGroup(c.Params.GroupId); err != nil {
+		c.Err = err
+		return
+	}
+
+	members, err := c.App.GetUserGroupMembers(c.Params.GroupId, c.Params.Page, c.Params.PerPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(model.UserGroupMembersToJson(members)))
+}
+
+func addUserGroupMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	member := model.UserGroupMemberFromJson(r.Body)
+	if member == nil || len(member.UserId) != 26 {
+		c.SetInvalidParam("user_id")
+		return
+	}
+
+	if getManagedUserGroup(c); c.Err != nil {
+		return
+	}
+
+	rmember, err := c.App.AddUserGroupMember(c.Params.GroupId, member.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId + " user_id=" + rmember.UserId)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rmember.ToJson()))
+}
+
+// removeUserGroupMember removes a user from a group. Users can always leave a group themselves.
+func removeUserGroupMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId().RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if c.Params.UserId != c.Session.UserId {
+		if getManagedUserGroup(c); c.Err != nil {
+			return
+		}
+	} else if _, err := c.App.GetUserGroup(c.Params.GroupId); err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := c.App.RemoveUserGroupMember(c.Params.GroupId, c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId + " user_id=" + c.Params.UserId)
+	ReturnStatusOK(w)
+}
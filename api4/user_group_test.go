@@ -0,0 +1,139 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestUserGroupManagement(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.CreateUserGroup(&model.UserGroup{Name: th.BasicUser2.Username})
+	CheckBadRequestStatus(t, resp)
+
+	group, resp := Client.CreateUserGroup(&model.UserGroup{Name: "oncall" + model.NewRandomString(6), DisplayName: "On Call", CreatorId: th.BasicUser2.Id})
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+
+	if group.CreatorId != th.BasicUser.Id {
+		t.Fatal("should have made the session user the creator of the group")
+	}
+
+	_, resp = Client.CreateUserGroup(&model.UserGroup{Name: group.Name})
+	CheckBadRequestStatus(t, resp)
+
+	groups, resp := Client.GetUserGroups(0, 60)
+	CheckNoError(t, resp)
+
+	found := false
+	for _, g := range groups {
+		if g.Id == group.Id {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("should have returned the group")
+	}
+
+	displayName := "Support"
+	patched, resp := Client.PatchUserGroup(group.Id, &model.UserGroupPatch{DisplayName: &displayName})
+	CheckNoError(t, resp)
+
+	if patched.DisplayName != displayName || patched.Name != group.Name {
+		t.Fatal("should have only changed the display name")
+	}
+
+	_, resp = Client.GetUserGroup(model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	th.LoginBasic2()
+
+	received, resp := Client.GetUserGroup(group.Id)
+	CheckNoError(t, resp)
+
+	if received.DisplayName != displayName {
+		t.Fatal("should have returned the patched group")
+	}
+
+	_, resp = Client.PatchUserGroup(group.Id, &model.UserGroupPatch{DisplayName: &displayName})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.DeleteUserGroup(group.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.PatchUserGroup(group.Id, &model.UserGroupPatch{DisplayName: &displayName})
+	CheckNoError(t, resp)
+
+	th.LoginBasic()
+
+	ok, resp := Client.DeleteUserGroup(group.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have deleted the group")
+	}
+
+	_, resp = Client.GetUserGroup(group.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestUserGroupMembers(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	group, resp := Client.CreateUserGroup(&model.UserGroup{Name: "designers" + model.NewRandomString(6)})
+	CheckNoError(t, resp)
+
+	member, resp := Client.AddUserGroupMember(group.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+
+	if member.GroupId != group.Id || member.UserId != th.BasicUser2.Id {
+		t.Fatal("should have added the user to the group")
+	}
+
+	_, resp = Client.AddUserGroupMember(group.Id, model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.AddUserGroupMember(group.Id, "junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.AddUserGroupMember(model.NewId(), th.BasicUser2.Id)
+	CheckNotFoundStatus(t, resp)
+
+	members, resp := Client.GetUserGroupMembers(group.Id, 0, 60)
+	CheckNoError(t, resp)
+
+	if len(members) != 1 || members[0].UserId != th.BasicUser2.Id {
+		t.Fatal("should have returned the member")
+	}
+
+	th.LoginBasic2()
+
+	_, resp = Client.AddUserGroupMember(group.Id, th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.RemoveUserGroupMember(group.Id, th.BasicUser.Id)
+	CheckForbiddenStatus(t, resp)
+
+	ok, resp := Client.RemoveUserGroupMember(group.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have been able to leave the group")
+	}
+
+	members, resp = Client.GetUserGroupMembers(group.Id, 0, 60)
+	CheckNoError(t, resp)
+
+	if len(members) != 0 {
+		t.Fatal("should have removed the member")
+	}
+}
@@ -4,6 +4,9 @@
 package api4
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
 	"net/http"
 	"strconv"
 	"testing"
@@ -401,6 +404,32 @@ func TestGetUser(t *testing.T) {
 		t.Fatal("last name should be blank")
 	}
 
+	// Check against data minimization rules
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.PrivacySettings.ShowEmailAddress = true
+		cfg.PrivacySettings.ShowFullName = true
+		*cfg.PrivacySettings.EnableUserDataMinimization = true
+		cfg.PrivacySettings.UserFieldVisibility = map[string]string{
+			model.USER_FIELD_EMAIL:     model.USER_FIELD_VISIBILITY_ADMINS,
+			model.USER_FIELD_FULL_NAME: model.USER_FIELD_VISIBILITY_ALL,
+		}
+	})
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.PrivacySettings.EnableUserDataMinimization = false })
+
+	ruser, resp = Client.GetUser(user.Id, "")
+	CheckNoError(t, resp)
+	assert.Equal(t, "", ruser.Email, "email should be blank")
+	assert.Equal(t, user.FirstName, ruser.FirstName, "first name should be visible")
+
+	ruser, resp = th.SystemAdminClient.GetUser(user.Id, "")
+	CheckNoError(t, resp)
+	assert.Equal(t, user.Email, ruser.Email, "email should be visible to admins")
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.PrivacySettings.ShowEmailAddress = false
+		cfg.PrivacySettings.ShowFullName = false
+	})
+
 	Client.Logout()
 	_, resp = Client.GetUser(user.Id, "")
 	CheckUnauthorizedStatus(t, resp)
@@ -486,6 +515,43 @@ func TestGetUserByUsername(t *testing.T) {
 	}
 }
 
+func TestGetUserByRemoteId(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	externalId := model.NewId()
+	remoteId := model.NewRemoteId("ldap", externalId)
+
+	_, resp := Client.PatchUser(th.BasicUser.Id, &model.UserPatch{RemoteId: &remoteId})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.PatchUser(th.BasicUser.Id, &model.UserPatch{RemoteId: &remoteId})
+	CheckNoError(t, resp)
+
+	ruser, resp := Client.GetUserByRemoteId("ldap", externalId, "")
+	CheckNoError(t, resp)
+	assert.Equal(t, th.BasicUser.Id, ruser.Id)
+
+	// full updates keep the remote id
+	ruser.Nickname = "remote"
+	ruser.RemoteId = nil
+	_, resp = Client.UpdateUser(ruser)
+	CheckNoError(t, resp)
+
+	ruser, resp = th.SystemAdminClient.GetUserByRemoteId("ldap", externalId, "")
+	CheckNoError(t, resp)
+	require.NotNil(t, ruser.RemoteId)
+	assert.Equal(t, remoteId, *ruser.RemoteId)
+
+	_, resp = Client.GetUserByRemoteId("ldap", model.NewId(), "")
+	CheckNotFoundStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetUserByRemoteId("ldap", externalId, "")
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestGetUserByEmail(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -905,6 +971,54 @@ func TestGetProfileImage(t *testing.T) {
 	}
 }
 
+func TestGetProfileImageConditional(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	Client := th.Client
+	user := th.BasicUser
+
+	data, err := readTestFile("test.png")
+	require.NoError(t, err)
+
+	_, resp := Client.SetProfileImage(user.Id, data)
+	CheckNoError(t, resp)
+	defer th.cleanupTestFile(&model.FileInfo{Path: "/users/" + user.Id + "/profile.png"})
+
+	get := func(headers map[string]string) *http.Response {
+		r, err := http.NewRequest("GET", Client.ApiUrl+"/users/"+user.Id+"/image", nil)
+		require.NoError(t, err)
+		r.Header.Set(model.HEADER_AUTH, Client.AuthType+" "+Client.AuthToken)
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+
+		response, err := Client.HttpClient.Do(r)
+		require.NoError(t, err)
+		response.Body.Close()
+		return response
+	}
+
+	response := get(nil)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	etag := response.Header.Get(model.HEADER_ETAG_SERVER)
+	lastModified := response.Header.Get(model.HEADER_LAST_MODIFIED)
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+
+	response = get(map[string]string{model.HEADER_ETAG_CLIENT: etag})
+	assert.Equal(t, http.StatusNotModified, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("Cache-Control"))
+
+	response = get(map[string]string{model.HEADER_IF_MODIFIED_SINCE: lastModified})
+	assert.Equal(t, http.StatusNotModified, response.StatusCode)
+
+	response = get(map[string]string{model.HEADER_IF_MODIFIED_SINCE: time.Unix(0, 0).UTC().Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusOK, response.StatusCode, "should return the image if it changed since")
+
+	response = get(map[string]string{model.HEADER_ETAG_CLIENT: "1", model.HEADER_IF_MODIFIED_SINCE: lastModified})
+	assert.Equal(t, http.StatusOK, response.StatusCode, "should prefer the etag")
+}
+
 func TestGetUsersByIds(t *testing.T) {
 	th := Setup().InitBasic()
 	defer th.TearDown()
@@ -988,6 +1102,140 @@ func TestGetTotalUsersStat(t *testing.T) {
 	}
 }
 
+func TestGetInactiveUsersPreview(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetInactiveUsersPreview()
+	CheckForbiddenStatus(t, resp)
+
+	actions, resp := th.SystemAdminClient.GetInactiveUsersPreview()
+	CheckNoError(t, resp)
+	for _, action := range actions {
+		assert.NotEqual(t, th.BasicUser.Id, action.UserId, "recently created users should not be inactive")
+	}
+}
+
+func TestGetUserDirectory(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	options := &model.UserDirectoryOptions{
+		TeamId: th.BasicTeam.Id,
+		Sort:   []model.UserDirectorySort{{Column: model.USER_DIRECTORY_SORT_USERNAME}},
+	}
+
+	_, resp := Client.GetUserDirectory(options, 0, 60)
+	CheckForbiddenStatus(t, resp)
+
+	users, resp := th.SystemAdminClient.GetUserDirectory(options, 0, 60)
+	CheckNoError(t, resp)
+	require.NotEmpty(t, users)
+	for i := 1; i < len(users); i++ {
+		assert.True(t, users[i-1].Username < users[i].Username)
+	}
+
+	options.Role = model.SYSTEM_ADMIN_ROLE_ID
+	users, resp = th.SystemAdminClient.GetUserDirectory(options, 0, 60)
+	CheckNoError(t, resp)
+	for _, user := range users {
+		assert.True(t, user.IsInRole(model.SYSTEM_ADMIN_ROLE_ID))
+	}
+
+	options.Sort = []model.UserDirectorySort{{Column: "password"}}
+	_, resp = th.SystemAdminClient.GetUserDirectory(options, 0, 60)
+	CheckBadRequestStatus(t, resp)
+
+	options.Role = ""
+	options.Sort = nil
+	data, resp := th.SystemAdminClient.ExportUserDirectory(options)
+	CheckNoError(t, resp)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.Nil(t, err)
+	require.True(t, len(records) > 1)
+	assert.Equal(t, model.UserDirectoryCsvHeader, records[0])
+
+	_, resp = Client.ExportUserDirectory(options)
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestWebPushSubscriptions(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	p256dh := make([]byte, model.WEB_PUSH_SUBSCRIPTION_P256DH_LENGTH)
+	p256dh[0] = 0x04
+
+	subscription := &model.WebPushSubscription{
+		Endpoint: "https://push.example.com/" + model.NewId(),
+		P256dh:   base64.RawURLEncoding.EncodeToString(p256dh),
+		Auth:     base64.RawURLEncoding.EncodeToString(make([]byte, model.WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH)),
+	}
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.EmailSettings.EnableWebPushNotifications = false })
+
+	_, resp := Client.CreateWebPushSubscription(th.BasicUser.Id, subscription)
+	CheckNotImplementedStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.EmailSettings.EnableWebPushNotifications = true })
+
+	_, resp = Client.CreateWebPushSubscription(th.BasicUser2.Id, subscription)
+	CheckForbiddenStatus(t, resp)
+
+	created, resp := Client.CreateWebPushSubscription(th.BasicUser.Id, subscription)
+	CheckNoError(t, resp)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, created.UserId)
+	assert.NotEmpty(t, created.SessionId)
+	assert.Empty(t, created.P256dh, "keys should be sanitized")
+	assert.Empty(t, created.Auth, "keys should be sanitized")
+
+	// registering the same endpoint again replaces the existing subscription
+	replaced, resp := Client.CreateWebPushSubscription(th.BasicUser.Id, subscription)
+	CheckNoError(t, resp)
+	assert.NotEqual(t, created.Id, replaced.Id)
+
+	subscriptions, resp := Client.GetWebPushSubscriptions(th.BasicUser.Id)
+	CheckNoError(t, resp)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, replaced.Id, subscriptions[0].Id)
+	assert.Empty(t, subscriptions[0].P256dh)
+
+	_, resp = Client.GetWebPushSubscriptions(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	subscriptions, resp = th.SystemAdminClient.GetWebPushSubscriptions(th.BasicUser.Id)
+	CheckNoError(t, resp)
+	require.Len(t, subscriptions, 1)
+
+	_, resp = th.SystemAdminClient.DeleteWebPushSubscription(th.SystemAdminUser.Id, replaced.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.DeleteWebPushSubscription(th.BasicUser.Id, "junk")
+	CheckBadRequestStatus(t, resp)
+
+	ok, resp := Client.DeleteWebPushSubscription(th.BasicUser.Id, replaced.Id)
+	CheckNoError(t, resp)
+	assert.True(t, ok)
+
+	subscriptions, resp = Client.GetWebPushSubscriptions(th.BasicUser.Id)
+	CheckNoError(t, resp)
+	assert.Empty(t, subscriptions)
+
+	// logging out removes the subscriptions of the session
+	created, resp = Client.CreateWebPushSubscription(th.BasicUser.Id, subscription)
+	CheckNoError(t, resp)
+
+	_, resp = Client.Logout()
+	CheckNoError(t, resp)
+
+	_, err := th.App.GetWebPushSubscription(created.Id)
+	assert.NotNil(t, err)
+}
+
 func TestUpdateUser(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -22,6 +22,8 @@ func (api *API) InitWebhook() {
 	api.BaseRoutes.OutgoingHook.Handle("", api.ApiSessionRequired(updateOutgoingHook)).Methods("PUT")
 	api.BaseRoutes.OutgoingHook.Handle("", api.ApiSessionRequired(deleteOutgoingHook)).Methods("DELETE")
 	api.BaseRoutes.OutgoingHook.Handle("/regen_token", api.ApiSessionRequired(regenOutgoingHookToken)).Methods("POST")
+	api.BaseRoutes.OutgoingHook.Handle("/regen_signing_secret", api.ApiSessionRequired(regenOutgoingHookSigningSecret)).Methods("POST")
+	api.BaseRoutes.OutgoingHook.Handle("/deliveries", api.ApiSessionRequired(getOutgoingHookDeliveries)).Methods("GET")
 }
 
 func createIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -50,6 +52,12 @@ func createIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Rate limits protect the server from misbehaving integrations, so only system admins can change them
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		hook.RateLimitPerMinute = 0
+		hook.RateLimitBurst = 0
+	}
+
 	incomingHook, err := c.App.CreateIncomingWebhookForChannel(c.Session.UserId, channel, hook)
 	if err != nil {
 		c.Err = err
@@ -115,6 +123,11 @@ func updateIncomingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !updatedHook.HasSameRateLimit(oldHook) && !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		updatedHook.RateLimitPerMinute = oldHook.RateLimitPerMinute
+		updatedHook.RateLimitBurst = oldHook.RateLimitBurst
+	}
+
 	incomingHook, err := c.App.UpdateIncomingWebhook(oldHook, updatedHook)
 	if err != nil {
 		c.Err = err
@@ -274,6 +287,11 @@ func updateOutgoingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !checkPrivateOutgoingHookPermission(c, toUpdateHook.ChannelId, oldHook.TeamId) {
+		c.LogAudit("fail - inappropriate permissions")
+		return
+	}
+
 	rhook, err := c.App.UpdateOutgoingWebhook(oldHook, toUpdateHook)
 	if err != nil {
 		c.Err = err
@@ -300,6 +318,11 @@ func createOutgoingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !checkPrivateOutgoingHookPermission(c, hook.ChannelId, hook.TeamId) {
+		c.LogAudit("fail - inappropriate permissions")
+		return
+	}
+
 	rhook, err := c.App.CreateOutgoingWebhook(hook)
 	if err != nil {
 		c.LogAudit("fail")
@@ -312,6 +335,27 @@ func createOutgoingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(rhook.ToJson()))
 }
 
+// checkPrivateOutgoingHookPermission checks that the session is allowed to scope an outgoing webhook to a channel
+// that isn't public, setting the error if it isn't.
+func checkPrivateOutgoingHookPermission(c *Context, channelId string, teamId string) bool {
+	if len(channelId) == 0 {
+		return true
+	}
+
+	// Channels that don't exist are reported when the hook is saved
+	channel, err := c.App.GetChannel(channelId)
+	if err != nil || channel.Type == model.CHANNEL_OPEN {
+		return true
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, teamId, model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS)
+		return false
+	}
+
+	return true
+}
+
 func getOutgoingHooks(c *Context, w http.ResponseWriter, r *http.Request) {
 	channelId := r.URL.Query().Get("channel_id")
 	teamId := r.URL.Query().Get("team_id")
@@ -413,6 +457,69 @@ func regenOutgoingHookToken(c *Context, w http.ResponseWriter, r *http.Request)
 	w.Write([]byte(rhook.ToJson()))
 }
 
+func regenOutgoingHookSigningSecret(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHookId()
+	if c.Err != nil {
+		return
+	}
+
+	hook, err := c.App.GetOutgoingWebhook(c.Params.HookId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("attempt")
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, hook.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	if c.Session.UserId != hook.CreatorId && !c.App.SessionHasPermissionToTeam(c.Session, hook.TeamId, model.PERMISSION_MANAGE_OTHERS_WEBHOOKS) {
+		c.LogAudit("fail - inappropriate permissions")
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_WEBHOOKS)
+		return
+	}
+
+	rhook, err := c.App.RegenOutgoingWebhookSigningSecret(hook)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("success")
+
+	w.Write([]byte(rhook.ToJson()))
+}
+
+func getOutgoingHookDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireHookId()
+	if c.Err != nil {
+		return
+	}
+
+	hook, err := c.App.GetOutgoingWebhook(c.Params.HookId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(c.Session, hook.TeamId, model.PERMISSION_MANAGE_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_WEBHOOKS)
+		return
+	}
+
+	if c.Session.UserId != hook.CreatorId && !c.App.SessionHasPermissionToTeam(c.Session, hook.TeamId, model.PERMISSION_MANAGE_OTHERS_WEBHOOKS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_WEBHOOKS)
+		return
+	}
+
+	deliveries := c.App.GetOutgoingWebhookDeliveries(hook.Id)
+
+	w.Write([]byte(model.OutgoingWebhookDeliveryListToJson(deliveries)))
+}
+
 func deleteOutgoingHook(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireHookId()
 	if c.Err != nil {
@@ -292,6 +292,45 @@ func TestCreateOutgoingWebhook(t *testing.T) {
 	CheckNotImplementedStatus(t, resp)
 }
 
+func TestCreateOutgoingWebhookForPrivateChannel(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	privateChannel := th.CreatePrivateChannel()
+	hook := &model.OutgoingWebhook{ChannelId: privateChannel.Id, TeamId: th.BasicTeam.Id, CallbackURLs: []string{"http://nowhere.com"}}
+
+	_, resp := Client.CreateOutgoingWebhook(hook)
+	CheckForbiddenStatus(t, resp)
+
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	rhook, resp := Client.CreateOutgoingWebhook(hook)
+	CheckNoError(t, resp)
+
+	if rhook.ChannelId != privateChannel.Id {
+		t.Fatal("channel ids didn't match")
+	}
+
+	// System admins have the permission, but still need to belong to the channel
+	_, resp = th.SystemAdminClient.CreateOutgoingWebhook(hook)
+	CheckForbiddenStatus(t, resp)
+
+	th.RemovePermissionFromRole(model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	rhook.DisplayName = "Updated"
+	_, resp = Client.UpdateOutgoingWebhook(rhook)
+	CheckForbiddenStatus(t, resp)
+}
+
 func TestGetOutgoingWebhooks(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -675,6 +714,71 @@ func TestRegenOutgoingHookToken(t *testing.T) {
 	CheckNotImplementedStatus(t, resp)
 }
 
+func TestRegenOutgoingHookSigningSecret(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	hook := &model.OutgoingWebhook{ChannelId: th.BasicChannel.Id, TeamId: th.BasicChannel.TeamId, CallbackURLs: []string{"http://nowhere.com"}}
+	rhook, resp := th.SystemAdminClient.CreateOutgoingWebhook(hook)
+	CheckNoError(t, resp)
+	if rhook.SigningSecret == "" {
+		t.Fatal("should have a signing secret")
+	}
+
+	_, resp = th.SystemAdminClient.RegenOutgoingHookSigningSecret("junk")
+	CheckBadRequestStatus(t, resp)
+
+	regenHook, resp := th.SystemAdminClient.RegenOutgoingHookSigningSecret(rhook.Id)
+	CheckNoError(t, resp)
+	if regenHook.SigningSecret == rhook.SigningSecret || regenHook.SigningSecret == "" {
+		t.Fatal("regen didn't work properly")
+	}
+	if regenHook.Token != rhook.Token {
+		t.Fatal("regen shouldn't change the token")
+	}
+
+	_, resp = Client.RegenOutgoingHookSigningSecret(rhook.Id)
+	CheckForbiddenStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = false })
+	_, resp = th.SystemAdminClient.RegenOutgoingHookSigningSecret(rhook.Id)
+	CheckNotImplementedStatus(t, resp)
+}
+
+func TestGetOutgoingHookDeliveries(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	hook := &model.OutgoingWebhook{ChannelId: th.BasicChannel.Id, TeamId: th.BasicChannel.TeamId, CallbackURLs: []string{"http://nowhere.com"}}
+	rhook, resp := th.SystemAdminClient.CreateOutgoingWebhook(hook)
+	CheckNoError(t, resp)
+
+	deliveries, resp := th.SystemAdminClient.GetOutgoingHookDeliveries(rhook.Id)
+	CheckNoError(t, resp)
+	if len(deliveries) != 0 {
+		t.Fatal("should have no deliveries")
+	}
+
+	_, resp = th.SystemAdminClient.GetOutgoingHookDeliveries("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetOutgoingHookDeliveries(model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.GetOutgoingHookDeliveries(rhook.Id)
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetOutgoingHookDeliveries(rhook.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestUpdateOutgoingHook(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer th.TearDown()
@@ -890,3 +994,37 @@ func TestDeleteOutgoingHook(t *testing.T) {
 		CheckForbiddenStatus(t, resp)
 	})
 }
+
+func TestIncomingWebhookRateLimitPermissions(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+	Client := th.Client
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableIncomingWebhooks = true })
+
+	defaultRolePermissions := th.SaveDefaultRolePermissions()
+	defer func() {
+		th.RestoreDefaultRolePermissions(defaultRolePermissions)
+	}()
+	th.AddPermissionToRole(model.PERMISSION_MANAGE_WEBHOOKS.Id, model.TEAM_USER_ROLE_ID)
+
+	hook, resp := Client.CreateIncomingWebhook(&model.IncomingWebhook{ChannelId: th.BasicChannel.Id, RateLimitPerMinute: 1000})
+	CheckNoError(t, resp)
+	assert.Equal(t, 0, hook.RateLimitPerMinute, "only system admins should be able to set rate limits")
+
+	hook.RateLimitPerMinute = 10
+	hook.RateLimitBurst = 2
+	hook, resp = th.SystemAdminClient.UpdateIncomingWebhook(hook)
+	CheckNoError(t, resp)
+	assert.Equal(t, 10, hook.RateLimitPerMinute)
+	assert.Equal(t, 2, hook.RateLimitBurst)
+
+	hook.RateLimitPerMinute = 0
+	hook.RateLimitBurst = 0
+	hook.DisplayName = "renamed"
+	hook, resp = Client.UpdateIncomingWebhook(hook)
+	CheckNoError(t, resp)
+	assert.Equal(t, "renamed", hook.DisplayName)
+	assert.Equal(t, 10, hook.RateLimitPerMinute, "only system admins should be able to change rate limits")
+	assert.Equal(t, 2, hook.RateLimitBurst, "only system admins should be able to change rate limits")
+}
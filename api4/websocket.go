@@ -6,17 +6,41 @@ package api4
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/websocket"
+	"github.com/mattermost/mattermost-server/app"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 )
 
 func (api *API) InitWebSocket() {
 	api.BaseRoutes.ApiRoot.Handle("/websocket", api.ApiHandlerTrustRequester(connectWebSocket)).Methods("GET")
+	api.BaseRoutes.ApiRoot.Handle("/websocket/connections", api.ApiSessionRequired(getWebSocketConnections)).Methods("GET")
 }
 
 func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
+	connectionId := r.URL.Query().Get(model.WEBSOCKET_CONNECTION_ID_PARAM)
+	if connectionId != "" && !model.IsValidId(connectionId) {
+		c.SetInvalidUrlParam(model.WEBSOCKET_CONNECTION_ID_PARAM)
+		return
+	}
+
+	var sequence int64
+	if connectionId != "" {
+		var err error
+		if sequence, err = strconv.ParseInt(r.URL.Query().Get(model.WEBSOCKET_SEQUENCE_NUMBER_PARAM), 10, 64); err != nil {
+			c.SetInvalidUrlParam(model.WEBSOCKET_SEQUENCE_NUMBER_PARAM)
+			return
+		}
+	}
+
+	// Apps that can only use some of the API mustn't be sent every event, so they can't connect to the websocket
+	c.OAuthScopeRequired(model.DEFAULT_SCOPE)
+	if c.Err != nil {
+		return
+	}
+
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  model.SOCKET_MAX_MESSAGE_SIZE_KB,
 		WriteBufferSize: model.SOCKET_MAX_MESSAGE_SIZE_KB,
@@ -30,7 +54,21 @@ func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(c.Session.UserId) > 0 {
+		if reason := c.App.CheckWebSocketConnectionQuota(c.Session.UserId); reason != "" {
+			mlog.Info(fmt.Sprintf("websocket connection rejected: user_id=%v reason=%v", c.Session.UserId, reason))
+			app.RejectWebSocketConnection(ws, reason)
+			return
+		}
+	}
+
 	wc := c.App.NewWebConn(ws, c.Session, c.T, "")
+	wc.IpAddress = c.IpAddress
+	wc.UserAgent = r.UserAgent()
+
+	if connectionId != "" {
+		wc.ResumeFrom(connectionId, sequence)
+	}
 
 	if len(c.Session.UserId) > 0 {
 		c.App.HubRegister(wc)
@@ -38,3 +76,18 @@ func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	wc.Pump()
 }
+
+func getWebSocketConnections(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	userId := r.URL.Query().Get("user_id")
+	if userId != "" && !model.IsValidId(userId) {
+		c.SetInvalidParam("user_id")
+		return
+	}
+
+	w.Write([]byte(model.WebSocketConnectionInfosToJson(c.App.GetWebSocketConnections(userId))))
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/store"
@@ -155,6 +157,104 @@ func TestWebSocketEvent(t *testing.T) {
 	}
 }
 
+func TestWebSocketEventFilter(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer WebSocketClient.Close()
+
+	WebSocketClient.Listen()
+
+	time.Sleep(300 * time.Millisecond)
+	if resp := <-WebSocketClient.ResponseChannel; resp.Status != model.STATUS_OK {
+		t.Fatal("should have responded OK to authentication challenge")
+	}
+
+	WebSocketClient.SubscribeEvents([]string{"junk"}, []string{"junk"})
+	if resp := <-WebSocketClient.ResponseChannel; resp.Status != model.STATUS_FAIL {
+		t.Fatal("should have failed to subscribe with an invalid channel id")
+	}
+
+	WebSocketClient.SubscribeEvents([]string{model.WEBSOCKET_EVENT_POSTED}, []string{th.BasicChannel.Id})
+	if resp := <-WebSocketClient.ResponseChannel; resp.Status != model.STATUS_OK {
+		t.Fatal("should have subscribed to events")
+	}
+
+	th.App.Publish(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", th.BasicChannel.Id, "", nil))
+	th.App.Publish(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", th.BasicChannel2.Id, "", nil))
+	th.App.Publish(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", th.BasicChannel.Id, "", nil))
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-WebSocketClient.EventChannel:
+			if event.Event == model.WEBSOCKET_EVENT_TYPING {
+				t.Fatal("should not have received an event that wasn't subscribed to")
+			}
+
+			if event.Event == model.WEBSOCKET_EVENT_POSTED {
+				if event.Broadcast.ChannelId != th.BasicChannel.Id {
+					t.Fatal("should not have received an event for a channel that wasn't subscribed to")
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("did not receive the subscribed event")
+		}
+	}
+}
+
+func TestWebSocketEphemeralPosts(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer WebSocketClient.Close()
+
+	WebSocketClient.Listen()
+
+	time.Sleep(300 * time.Millisecond)
+	if resp := <-WebSocketClient.ResponseChannel; resp.Status != model.STATUS_OK {
+		t.Fatal("should have responded OK to authentication challenge")
+	}
+
+	post := th.App.SendEphemeralPost(th.BasicUser.Id, &model.Post{ChannelId: th.BasicChannel.Id, Message: "working"})
+	th.App.UpdateEphemeralPost(th.BasicUser.Id, &model.Post{Id: post.Id, ChannelId: th.BasicChannel.Id, Message: "done"})
+	th.App.DeleteEphemeralPost(th.BasicUser.Id, post.Id)
+
+	expected := []string{model.WEBSOCKET_EVENT_EPHEMERAL_MESSAGE, model.WEBSOCKET_EVENT_POST_EDITED, model.WEBSOCKET_EVENT_POST_DELETED}
+
+	timeout := time.After(2 * time.Second)
+	for len(expected) > 0 {
+		select {
+		case event := <-WebSocketClient.EventChannel:
+			if event.Event != expected[0] {
+				continue
+			}
+
+			eventPost := model.PostFromJson(strings.NewReader(event.Data["post"].(string)))
+			if eventPost.Id != post.Id {
+				t.Fatal("should have sent the ephemeral post")
+			}
+
+			if event.Event == model.WEBSOCKET_EVENT_POST_EDITED && eventPost.Message != "done" {
+				t.Fatal("should have sent the updated post")
+			}
+
+			expected = expected[1:]
+		case <-timeout:
+			t.Fatal("did not receive " + expected[0])
+		}
+	}
+}
+
 func TestCreateDirectChannelWithSocket(t *testing.T) {
 	th := Setup().InitBasic()
 	defer th.TearDown()
@@ -458,3 +558,205 @@ func TestWebSocketStatuses(t *testing.T) {
 
 	WebSocketClient.Close()
 }
+
+func TestWebSocketConnectionQuota(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MaximumWebSocketConnectionsPerUser = 1 })
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MaximumWebSocketConnectionsPerUser = 0 })
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer WebSocketClient.Close()
+
+	time.Sleep(300 * time.Millisecond)
+
+	url := fmt.Sprintf("ws://localhost:%v", th.App.Srv.ListenAddr.Port) + model.API_URL_SUFFIX + "/websocket"
+	header := http.Header{model.HEADER_AUTH: []string{model.HEADER_BEARER + " " + th.Client.AuthToken}}
+
+	conn, _, dialErr := websocket.DefaultDialer.Dial(url, header)
+	if dialErr != nil {
+		t.Fatal(dialErr)
+	}
+	defer conn.Close()
+
+	_, _, readErr := conn.ReadMessage()
+	if closeErr, ok := readErr.(*websocket.CloseError); !ok {
+		t.Fatal("should have closed the connection over the quota", readErr)
+	} else if closeErr.Code != websocket.CloseTryAgainLater || closeErr.Text != model.WEBSOCKET_REJECT_USER_CONNECTION_LIMIT {
+		t.Fatal("should have given the reason for closing the connection", closeErr)
+	}
+
+	connections, resp := th.SystemAdminClient.GetWebSocketConnections(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if len(connections) != 1 || connections[0].UserId != th.BasicUser.Id || connections[0].ConnectAt == 0 {
+		t.Fatal("should have listed the user's connection")
+	}
+
+	_, resp = th.SystemAdminClient.GetWebSocketConnections("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.Client.GetWebSocketConnections("")
+	CheckForbiddenStatus(t, resp)
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.MaximumWebSocketConnectionsPerUser = 0
+		*cfg.ServiceSettings.MaximumWebSocketConnectionsPerServer = 1
+	})
+	defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MaximumWebSocketConnectionsPerServer = 0 })
+
+	conn2, _, dialErr := websocket.DefaultDialer.Dial(url, http.Header{model.HEADER_AUTH: []string{model.HEADER_BEARER + " " + th.SystemAdminClient.AuthToken}})
+	if dialErr != nil {
+		t.Fatal(dialErr)
+	}
+	defer conn2.Close()
+
+	_, _, readErr = conn2.ReadMessage()
+	if closeErr, ok := readErr.(*websocket.CloseError); !ok || closeErr.Text != model.WEBSOCKET_REJECT_SERVER_CONNECTION_LIMIT {
+		t.Fatal("should have closed the connection over the server quota", readErr)
+	}
+}
+
+func TestWebSocketUserTyping(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	require.Nil(t, err)
+	defer WebSocketClient.Close()
+	WebSocketClient.Listen()
+
+	client2 := th.CreateClient()
+	th.LoginBasic2WithClient(client2)
+	WebSocketClient2, err := model.NewWebSocketClient4(fmt.Sprintf("ws://localhost:%v", th.App.Srv.ListenAddr.Port), client2.AuthToken)
+	require.Nil(t, err)
+	defer WebSocketClient2.Close()
+	WebSocketClient2.Listen()
+
+	time.Sleep(300 * time.Millisecond)
+
+	countTypingEvents := func() int {
+		count := 0
+		timeout := time.After(500 * time.Millisecond)
+		for {
+			select {
+			case event := <-WebSocketClient.EventChannel:
+				if event.Event == model.WEBSOCKET_EVENT_TYPING && event.Data["user_id"] == th.BasicUser2.Id {
+					count++
+				}
+			case <-timeout:
+				return count
+			}
+		}
+	}
+
+	WebSocketClient2.UserTyping(th.BasicChannel.Id, "")
+	WebSocketClient2.UserTyping(th.BasicChannel.Id, "")
+	assert.Equal(t, 1, countTypingEvents(), "should drop typing events that repeat one that was just sent")
+
+	WebSocketClient2.UserTyping(th.BasicChannel.Id, th.BasicPost.Id)
+	assert.Equal(t, 1, countTypingEvents(), "should send typing events for each thread")
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.TeamSettings.MaxNotificationsPerChannel = 1 })
+	WebSocketClient2.UserTyping(th.BasicChannel.Id, model.NewId())
+	assert.Equal(t, 0, countTypingEvents(), "should drop typing events in large channels")
+}
+
+func TestWebSocketResume(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	require.Nil(t, err)
+	defer WebSocketClient.Close()
+	WebSocketClient.Listen()
+
+	resp := <-WebSocketClient.ResponseChannel
+	require.Equal(t, model.STATUS_OK, resp.Status)
+
+	waitForEvent := func(event string) *model.WebSocketEvent {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case evt := <-WebSocketClient.EventChannel:
+				if evt.Event == event {
+					return evt
+				}
+			case <-timeout:
+				require.Fail(t, "timed out waiting for event", event)
+				return nil
+			}
+		}
+	}
+
+	hello := waitForEvent(model.WEBSOCKET_EVENT_HELLO)
+	connectionId := hello.Data["connection_id"].(string)
+	require.NotEmpty(t, connectionId)
+	assert.Equal(t, connectionId, WebSocketClient.ConnectionId)
+
+	WebSocketClient.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	post := &model.Post{ChannelId: th.BasicChannel.Id, Message: "missed while reconnecting"}
+	_, resp2 := th.SystemAdminClient.CreatePost(post)
+	CheckNoError(t, resp2)
+
+	t.Run("should replay the missed events", func(t *testing.T) {
+		require.Nil(t, WebSocketClient.Resume())
+		WebSocketClient.Listen()
+
+		evt := waitForEvent(model.WEBSOCKET_EVENT_POSTED)
+		assert.Contains(t, evt.Data["post"], "missed while reconnecting")
+
+		hello := waitForEvent(model.WEBSOCKET_EVENT_HELLO)
+		assert.Equal(t, connectionId, hello.Data["connection_id"])
+		assert.True(t, hello.Sequence > evt.Sequence, "should continue the sequence of the previous connection")
+	})
+
+	t.Run("should start a new connection if the missed events aren't available", func(t *testing.T) {
+		WebSocketClient.Close()
+		time.Sleep(300 * time.Millisecond)
+
+		WebSocketClient.ServerSequence = -10
+		require.Nil(t, WebSocketClient.Resume())
+		WebSocketClient.Listen()
+
+		hello := waitForEvent(model.WEBSOCKET_EVENT_HELLO)
+		assert.NotEqual(t, connectionId, hello.Data["connection_id"])
+		assert.Equal(t, int64(0), hello.Sequence)
+	})
+}
+
+func TestWebSocketCloseOnShutdown(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer WebSocketClient.Close()
+
+	time.Sleep(300 * time.Millisecond)
+
+	th.App.StopServer()
+
+	WebSocketClient.Conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, readErr := WebSocketClient.Conn.ReadMessage()
+		if readErr == nil {
+			continue
+		}
+
+		if closeErr, ok := readErr.(*websocket.CloseError); !ok {
+			t.Fatal("should have closed the connection", readErr)
+		} else if closeErr.Code != websocket.CloseServiceRestart || closeErr.Text != model.WEBSOCKET_CLOSE_SERVER_RESTART {
+			t.Fatal("should have told the client to reconnect", closeErr)
+		}
+		break
+	}
+}
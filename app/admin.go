@@ -203,6 +203,10 @@ func (a *App) SaveConfig(cfg *model.Config, sendConfigChangeClusterMessage bool)
 	// start/restart email batching job if necessary
 	a.InitEmailBatching()
 
+	if sections := changedConfigSections(oldCfg, a.Config()); len(sections) > 0 {
+		a.PostSystemEvent(newConfigChangedSystemEvent(sections))
+	}
+
 	return nil
 }
 
@@ -15,10 +15,13 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	texttemplate "text/template"
 
+	"github.com/go-redis/redis"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/throttled/throttled"
+	"github.com/throttled/throttled/store/memstore"
 
 	"github.com/mattermost/mattermost-server/einterfaces"
 	ejobs "github.com/mattermost/mattermost-server/einterfaces/jobs"
@@ -34,6 +37,8 @@ import (
 
 const ADVANCED_PERMISSIONS_MIGRATION_KEY = "AdvancedPermissionsMigrationComplete"
 const EMOJIS_PERMISSIONS_MIGRATION_KEY = "EmojisPermissionsMigrationComplete"
+const PRIVATE_OUTGOING_WEBHOOKS_PERMISSIONS_MIGRATION_KEY = "PrivateOutgoingWebhooksPermissionsMigrationComplete"
+const BOTS_PERMISSIONS_MIGRATION_KEY = "BotsPermissionsMigrationComplete"
 
 type App struct {
 	goroutineCount      int32
@@ -64,6 +69,7 @@ type App struct {
 	Metrics          einterfaces.MetricsInterface
 	Mfa              einterfaces.MfaInterface
 	Saml             einterfaces.SamlInterface
+	Sms              einterfaces.SmsInterface
 
 	config                 atomic.Value
 	envConfig              map[string]interface{}
@@ -81,25 +87,71 @@ type App struct {
 
 	newStore func() store.Store
 
-	htmlTemplateWatcher     *utils.HTMLTemplateWatcher
-	sessionCache            *utils.Cache
-	configListenerId        string
-	licenseListenerId       string
-	logListenerId           string
-	clusterLeaderListenerId string
-	disableConfigWatch      bool
-	configWatcher           *utils.ConfigWatcher
-	asymmetricSigningKey    *ecdsa.PrivateKey
+	htmlTemplateWatcher      *utils.HTMLTemplateWatcher
+	sessionCache             utils.ObjectCache
+	redisCacheClient         *redis.Client
+	redisCachePrefix         string
+	configListenerId         string
+	licenseListenerId        string
+	logListenerId            string
+	emailTemplatesListenerId string
+	clusterLeaderListenerId  string
+	disableConfigWatch       bool
+	configWatcher            *utils.ConfigWatcher
+	asymmetricSigningKey     *ecdsa.PrivateKey
+	webPushVapidKey          *ecdsa.PrivateKey
 
 	pluginCommands     []*PluginCommand
 	pluginCommandsLock sync.RWMutex
 
+	pluginJobs     []*PluginScheduledJob
+	pluginJobsLock sync.RWMutex
+	pluginJobsTask *model.ScheduledTask
+
+	pluginHealthCheckTask *model.ScheduledTask
+
 	clientConfig        map[string]string
 	clientConfigHash    string
 	limitedClientConfig map[string]string
 	diagnosticId        string
 
 	phase2PermissionsMigrationComplete bool
+
+	performanceTimings sync.Map
+
+	channelBridgeHealth sync.Map
+	channelBridgeEchoes sync.Map
+
+	integrationTraffic      sync.Map
+	integrationTrafficLock  sync.Mutex // guards the count and start time of the integration traffic
+	integrationTrafficCount int
+	integrationTrafficSince int64
+
+	storeTracer            *storeTracer
+	storeTracingListenerId string
+
+	debugRecordingLock sync.Mutex
+	debugRecording     *model.DebugRecording
+
+	profileCaptureLock sync.Mutex
+	profileCaptureStop chan struct{} // closed to end the profile capture that's in progress early
+
+	incomingWebhookRateLimitLock  sync.Mutex
+	incomingWebhookRateLimitStore *memstore.MemStore
+	incomingWebhookRateLimiters   map[string]*throttled.GCRARateLimiter
+
+	linkPreviewFixtureServerLock sync.Mutex
+	linkPreviewFixtureServer     *linkPreviewFixtureServer
+
+	configImportLock sync.Mutex // ensures that the changes reported by an import are the ones that are applied
+
+	outgoingWebhookDeliveries sync.Map
+	integrationCircuits       sync.Map
+
+	typingEvents eventThrottle
+
+	webConnRegistry  webConnRegistry
+	webConnHandOvers sync.Map
 }
 
 var appCount = 0
@@ -124,6 +176,8 @@ func New(options ...Option) (outApp *App, outErr error) {
 		configListeners:  make(map[string]func(*model.Config, *model.Config)),
 		clientConfig:     make(map[string]string),
 		licenseListeners: map[string]func(){},
+
+		integrationTrafficSince: model.GetMillis(),
 	}
 	defer func() {
 		if outErr != nil {
@@ -206,13 +260,30 @@ func New(options ...Option) (outApp *App, outErr error) {
 		mlog.Error(fmt.Sprintf("Failed to parse server templates %v", err))
 	} else {
 		app.htmlTemplateWatcher = htmlTemplateWatcher
+		app.loadEmailTemplateOverrides()
+		app.emailTemplatesListenerId = app.AddConfigListener(func(_, _ *model.Config) {
+			app.loadEmailTemplateOverrides()
+		})
 	}
 
+	app.initRedisCaches()
+
 	app.Srv.Store = app.newStore()
+
+	app.storeTracer = newStoreTracer(app)
+	app.configureStoreTracing()
+	app.storeTracingListenerId = app.AddConfigListener(func(_, _ *model.Config) {
+		app.configureStoreTracing()
+	})
+
 	if err := app.ensureAsymmetricSigningKey(); err != nil {
 		return nil, errors.Wrapf(err, "unable to ensure asymmetric signing key")
 	}
 
+	if err := app.ensureWebPushVapidKey(); err != nil {
+		return nil, errors.Wrapf(err, "unable to ensure web push vapid key")
+	}
+
 	if err := app.ensureInstallationDate(); err != nil {
 		return nil, errors.Wrapf(err, "unable to ensure installation date")
 	}
@@ -267,11 +338,18 @@ func (a *App) Shutdown() {
 	a.HubStop()
 
 	a.ShutDownPlugins()
+	a.stopProfileCapture()
 	a.WaitForGoroutines()
 
+	a.stopLinkPreviewFixtureServer()
+
+	store.SetQueryTracer(nil)
+
 	if a.Srv.Store != nil {
 		a.Srv.Store.Close()
 	}
+
+	a.stopRedisCaches()
 	a.Srv = nil
 
 	if a.htmlTemplateWatcher != nil {
@@ -281,6 +359,8 @@ func (a *App) Shutdown() {
 	a.RemoveConfigListener(a.configListenerId)
 	a.RemoveLicenseListener(a.licenseListenerId)
 	a.RemoveConfigListener(a.logListenerId)
+	a.RemoveConfigListener(a.emailTemplatesListenerId)
+	a.RemoveConfigListener(a.storeTracingListenerId)
 	a.RemoveClusterLeaderChangedListener(a.clusterLeaderListenerId)
 	mlog.Info("Server stopped")
 
@@ -299,6 +379,14 @@ func RegisterClusterInterface(f func(*App) einterfaces.ClusterInterface) {
 	clusterInterface = f
 }
 
+var redisClusterInterface func(*App) einterfaces.ClusterInterface
+
+// RegisterRedisClusterInterface registers the cluster implementation that's used instead of any other when
+// ClusterSettings.RedisAddress is set, which doesn't require a license.
+func RegisterRedisClusterInterface(f func(*App) einterfaces.ClusterInterface) {
+	redisClusterInterface = f
+}
+
 var complianceInterface func(*App) einterfaces.ComplianceInterface
 
 func RegisterComplianceInterface(f func(*App) einterfaces.ComplianceInterface) {
@@ -353,6 +441,42 @@ func RegisterJobsMigrationsJobInterface(f func(*App) tjobs.MigrationsJobInterfac
 	jobsMigrationsInterface = f
 }
 
+var jobsEmailDigestInterface func(*App) tjobs.EmailDigestJobInterface
+
+func RegisterJobsEmailDigestJobInterface(f func(*App) tjobs.EmailDigestJobInterface) {
+	jobsEmailDigestInterface = f
+}
+
+var jobsInactiveUsersInterface func(*App) tjobs.InactiveUsersJobInterface
+
+func RegisterJobsInactiveUsersJobInterface(f func(*App) tjobs.InactiveUsersJobInterface) {
+	jobsInactiveUsersInterface = f
+}
+
+var jobsTeamDeletionInterface func(*App) tjobs.TeamDeletionJobInterface
+
+func RegisterJobsTeamDeletionJobInterface(f func(*App) tjobs.TeamDeletionJobInterface) {
+	jobsTeamDeletionInterface = f
+}
+
+var jobsLinkMetadataCleanupInterface func(*App) tjobs.LinkMetadataCleanupJobInterface
+
+func RegisterJobsLinkMetadataCleanupJobInterface(f func(*App) tjobs.LinkMetadataCleanupJobInterface) {
+	jobsLinkMetadataCleanupInterface = f
+}
+
+var jobsRemindersInterface func(*App) tjobs.RemindersJobInterface
+
+func RegisterJobsRemindersJobInterface(f func(*App) tjobs.RemindersJobInterface) {
+	jobsRemindersInterface = f
+}
+
+var jobsChannelSnoozesInterface func(*App) tjobs.ChannelSnoozesJobInterface
+
+func RegisterJobsChannelSnoozesJobInterface(f func(*App) tjobs.ChannelSnoozesJobInterface) {
+	jobsChannelSnoozesInterface = f
+}
+
 var ldapInterface func(*App) einterfaces.LdapInterface
 
 func RegisterLdapInterface(f func(*App) einterfaces.LdapInterface) {
@@ -383,6 +507,12 @@ func RegisterSamlInterface(f func(*App) einterfaces.SamlInterface) {
 	samlInterface = f
 }
 
+var smsInterface func(*App) einterfaces.SmsInterface
+
+func RegisterSmsInterface(f func(*App) einterfaces.SmsInterface) {
+	smsInterface = f
+}
+
 func (a *App) initEnterprise() {
 	if accountMigrationInterface != nil {
 		a.AccountMigration = accountMigrationInterface(a)
@@ -390,6 +520,9 @@ func (a *App) initEnterprise() {
 	if clusterInterface != nil {
 		a.Cluster = clusterInterface(a)
 	}
+	if redisClusterInterface != nil && *a.Config().ClusterSettings.RedisAddress != "" {
+		a.Cluster = redisClusterInterface(a)
+	}
 	if complianceInterface != nil {
 		a.Compliance = complianceInterface(a)
 	}
@@ -422,10 +555,16 @@ func (a *App) initEnterprise() {
 	if dataRetentionInterface != nil {
 		a.DataRetention = dataRetentionInterface(a)
 	}
+	if smsInterface != nil {
+		a.Sms = smsInterface(a)
+	}
 }
 
 func (a *App) initJobs() {
 	a.Jobs = jobs.NewJobServer(a, a.Srv.Store)
+	a.Jobs.OnJobFailed = func(job *model.Job, jobError *model.AppError) {
+		a.PostSystemEvent(newJobFailedSystemEvent(job, jobError))
+	}
 	if jobsDataRetentionJobInterface != nil {
 		a.Jobs.DataRetentionJob = jobsDataRetentionJobInterface(a)
 	}
@@ -444,6 +583,24 @@ func (a *App) initJobs() {
 	if jobsMigrationsInterface != nil {
 		a.Jobs.Migrations = jobsMigrationsInterface(a)
 	}
+	if jobsEmailDigestInterface != nil {
+		a.Jobs.EmailDigest = jobsEmailDigestInterface(a)
+	}
+	if jobsInactiveUsersInterface != nil {
+		a.Jobs.InactiveUsers = jobsInactiveUsersInterface(a)
+	}
+	if jobsTeamDeletionInterface != nil {
+		a.Jobs.TeamDeletion = jobsTeamDeletionInterface(a)
+	}
+	if jobsLinkMetadataCleanupInterface != nil {
+		a.Jobs.LinkMetadataCleanup = jobsLinkMetadataCleanupInterface(a)
+	}
+	if jobsRemindersInterface != nil {
+		a.Jobs.Reminders = jobsRemindersInterface(a)
+	}
+	if jobsChannelSnoozesInterface != nil {
+		a.Jobs.ChannelSnoozes = jobsChannelSnoozesInterface(a)
+	}
 	a.Jobs.Workers = a.Jobs.InitWorkers()
 	a.Jobs.Schedulers = a.Jobs.InitSchedulers()
 }
@@ -505,6 +662,14 @@ func (a *App) HTMLTemplates() *template.Template {
 	return nil
 }
 
+func (a *App) TextTemplates() *texttemplate.Template {
+	if a.htmlTemplateWatcher != nil {
+		return a.htmlTemplateWatcher.TextTemplates()
+	}
+
+	return nil
+}
+
 func (a *App) HTTPClient(trustURLs bool) *http.Client {
 	insecure := a.Config().ServiceSettings.EnableInsecureOutgoingConnections != nil && *a.Config().ServiceSettings.EnableInsecureOutgoingConnections
 
@@ -692,6 +857,87 @@ func (a *App) DoEmojisPermissionsMigration() {
 	}
 }
 
+// DoPrivateOutgoingWebhooksPermissionsMigration gives system admins the permission to create outgoing webhooks for
+// private channels on servers whose roles were migrated to the database before the permission existed.
+func (a *App) DoPrivateOutgoingWebhooksPermissionsMigration() {
+	// If the migration is already marked as completed, don't do it again.
+	if result := <-a.Srv.Store.System().GetByName(PRIVATE_OUTGOING_WEBHOOKS_PERMISSIONS_MIGRATION_KEY); result.Err == nil {
+		return
+	}
+
+	mlog.Info("Migrating private outgoing webhooks permissions to database.")
+
+	systemAdminRole, err := a.GetRoleByName(model.SYSTEM_ADMIN_ROLE_ID)
+	if err != nil {
+		mlog.Critical("Failed to migrate private outgoing webhooks permissions.")
+		mlog.Critical(err.Error())
+		return
+	}
+
+	if !utils.StringInSlice(model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS.Id, systemAdminRole.Permissions) {
+		systemAdminRole.Permissions = append(systemAdminRole.Permissions, model.PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS.Id)
+		if result := <-a.Srv.Store.Role().Save(systemAdminRole); result.Err != nil {
+			mlog.Critical("Failed to migrate private outgoing webhooks permissions.")
+			mlog.Critical(result.Err.Error())
+			return
+		}
+	}
+
+	system := model.System{
+		Name:  PRIVATE_OUTGOING_WEBHOOKS_PERMISSIONS_MIGRATION_KEY,
+		Value: "true",
+	}
+
+	if result := <-a.Srv.Store.System().Save(&system); result.Err != nil {
+		mlog.Critical("Failed to mark private outgoing webhooks permissions migration as completed.")
+		mlog.Critical(fmt.Sprint(result.Err))
+	}
+}
+
+// DoBotsPermissionsMigration gives system admins the permissions to manage bot accounts on servers whose roles were
+// migrated to the database before bot accounts existed.
+func (a *App) DoBotsPermissionsMigration() {
+	// If the migration is already marked as completed, don't do it again.
+	if result := <-a.Srv.Store.System().GetByName(BOTS_PERMISSIONS_MIGRATION_KEY); result.Err == nil {
+		return
+	}
+
+	mlog.Info("Migrating bots permissions to database.")
+
+	systemAdminRole, err := a.GetRoleByName(model.SYSTEM_ADMIN_ROLE_ID)
+	if err != nil {
+		mlog.Critical("Failed to migrate bots permissions.")
+		mlog.Critical(err.Error())
+		return
+	}
+
+	changed := false
+	for _, permission := range []*model.Permission{model.PERMISSION_CREATE_BOT, model.PERMISSION_MANAGE_BOTS, model.PERMISSION_MANAGE_OTHERS_BOTS} {
+		if !utils.StringInSlice(permission.Id, systemAdminRole.Permissions) {
+			systemAdminRole.Permissions = append(systemAdminRole.Permissions, permission.Id)
+			changed = true
+		}
+	}
+
+	if changed {
+		if result := <-a.Srv.Store.Role().Save(systemAdminRole); result.Err != nil {
+			mlog.Critical("Failed to migrate bots permissions.")
+			mlog.Critical(result.Err.Error())
+			return
+		}
+	}
+
+	system := model.System{
+		Name:  BOTS_PERMISSIONS_MIGRATION_KEY,
+		Value: "true",
+	}
+
+	if result := <-a.Srv.Store.System().Save(&system); result.Err != nil {
+		mlog.Critical("Failed to mark bots permissions migration as completed.")
+		mlog.Critical(fmt.Sprint(result.Err))
+	}
+}
+
 func (a *App) StartElasticsearch() {
 	a.Go(func() {
 		if err := a.Elasticsearch.Start(); err != nil {
@@ -108,6 +108,8 @@ func setupTestHelper(enterprise bool) *TestHelper {
 
 	th.App.DoAdvancedPermissionsMigration()
 	th.App.DoEmojisPermissionsMigration()
+	th.App.DoPrivateOutgoingWebhooksPermissionsMigration()
+	th.App.DoBotsPermissionsMigration()
 
 	th.App.Srv.Store.MarkSystemRanUnitTests()
 
@@ -4,6 +4,10 @@
 package app
 
 import (
+	"encoding/csv"
+	"io"
+	"net/http"
+
 	"github.com/mattermost/mattermost-server/model"
 )
 
@@ -22,3 +26,133 @@ func (a *App) GetAuditsPage(userId string, page int, perPage int) (model.Audits,
 		return result.Data.(model.Audits), nil
 	}
 }
+
+// SearchAudits returns the newest audits matching the options along with the cursor to pass to get the page after
+// them. The cursor is nil once there are no more audits.
+func (a *App) SearchAudits(options *model.AuditSearchOptions, perPage int) (model.Audits, *model.AuditCursor, *model.AppError) {
+	result := <-a.Srv.Store.Audit().Search(options, perPage)
+	if result.Err != nil {
+		return nil, nil, result.Err
+	}
+
+	audits := result.Data.(model.Audits)
+
+	var next *model.AuditCursor
+	if len(audits) == perPage && perPage > 0 {
+		last := audits[len(audits)-1]
+		next = &model.AuditCursor{CreateAt: last.CreateAt, Id: last.Id}
+	}
+
+	return audits, next, nil
+}
+
+// ExportAudits writes every audit matching the options to w in the given format, fetching and flushing them in
+// batches so that the whole audit log is never held in memory at once.
+func (a *App) ExportAudits(options *model.AuditSearchOptions, format string, w io.Writer) *model.AppError {
+	var exporter auditExporter
+	switch format {
+	case model.AUDIT_EXPORT_FORMAT_CSV:
+		exporter = &csvAuditExporter{writer: csv.NewWriter(w)}
+	case model.AUDIT_EXPORT_FORMAT_JSON:
+		exporter = &jsonAuditExporter{writer: w}
+	default:
+		return model.NewAppError("ExportAudits", "app.audit.export.format.app_error", nil, "format="+format, http.StatusBadRequest)
+	}
+
+	batchOptions := *options
+	if err := exporter.start(); err != nil {
+		return model.NewAppError("ExportAudits", "app.audit.export.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for {
+		audits, next, appErr := a.SearchAudits(&batchOptions, model.AUDIT_EXPORT_BATCH_SIZE)
+		if appErr != nil {
+			return appErr
+		}
+
+		for i := range audits {
+			if err := exporter.write(&audits[i]); err != nil {
+				return model.NewAppError("ExportAudits", "app.audit.export.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+		}
+
+		if err := exporter.flush(); err != nil {
+			return model.NewAppError("ExportAudits", "app.audit.export.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if next == nil {
+			break
+		}
+		batchOptions.Cursor = next
+	}
+
+	if err := exporter.finish(); err != nil {
+		return model.NewAppError("ExportAudits", "app.audit.export.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+type auditExporter interface {
+	start() error
+	write(audit *model.Audit) error
+	flush() error
+	finish() error
+}
+
+type csvAuditExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvAuditExporter) start() error {
+	return e.writer.Write(model.AuditCsvHeader)
+}
+
+func (e *csvAuditExporter) write(audit *model.Audit) error {
+	return e.writer.Write(audit.ToCsvRecord())
+}
+
+func (e *csvAuditExporter) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvAuditExporter) finish() error {
+	return e.flush()
+}
+
+// jsonAuditExporter writes the audits as a single JSON array, the same as a page returned by the audits endpoint.
+type jsonAuditExporter struct {
+	writer  io.Writer
+	written bool
+}
+
+func (e *jsonAuditExporter) start() error {
+	_, err := io.WriteString(e.writer, "[")
+	return err
+}
+
+func (e *jsonAuditExporter) write(audit *model.Audit) error {
+	if e.written {
+		if _, err := io.WriteString(e.writer, ","); err != nil {
+			return err
+		}
+	}
+	e.written = true
+
+	_, err := io.WriteString(e.writer, audit.ToJson())
+	return err
+}
+
+func (e *jsonAuditExporter) flush() error {
+	return nil
+}
+
+func (e *jsonAuditExporter) finish() error {
+	_, err := io.WriteString(e.writer, "]")
+	return err
+}
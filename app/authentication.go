@@ -74,6 +74,10 @@ func (a *App) checkUserPassword(user *model.User, password string) *model.AppErr
 			return result.Err
 		}
 
+		if user.FailedAttempts+1 == *a.Config().ServiceSettings.MaximumLoginAttempts {
+			a.PostSystemEvent(newSecuritySystemEvent(utils.T("app.system_event.account_locked.title"), user))
+		}
+
 		return model.NewAppError("checkUserPassword", "api.user.check_user_password.invalid.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
 	} else {
 		if result := <-a.Srv.Store.User().UpdateFailedPasswordAttempts(user.Id, 0); result.Err != nil {
@@ -131,6 +135,10 @@ func (a *App) CheckUserPreflightAuthenticationCriteria(user *model.User, mfaToke
 		return err
 	}
 
+	if err := checkUserNotBot(user); err != nil {
+		return err
+	}
+
 	if err := checkUserLoginAttempts(user, *a.Config().ServiceSettings.MaximumLoginAttempts); err != nil {
 		return err
 	}
@@ -179,6 +187,14 @@ func checkUserNotDisabled(user *model.User) *model.AppError {
 	return nil
 }
 
+// checkUserNotBot prevents bots from logging in since they can only authenticate with access tokens.
+func checkUserNotBot(user *model.User) *model.AppError {
+	if user.IsBot {
+		return model.NewAppError("Login", "api.user.login.bot_login_forbidden.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
+	}
+	return nil
+}
+
 func (a *App) authenticateUser(user *model.User, password, mfaToken string) (*model.User, *model.AppError) {
 	license := a.License()
 	ldapAvailable := *a.Config().LdapSettings.Enable && a.Ldap != nil && license != nil && *license.Features.LDAP
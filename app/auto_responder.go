@@ -4,10 +4,16 @@
 package app
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 )
 
+const AUTO_RESPONDER_SCHEDULE_BATCH_SIZE = 100
+
 func (a *App) SendAutoResponse(channel *model.Channel, receiver *model.User, rootId string) {
 	if receiver == nil || receiver.NotifyProps == nil {
 		return
@@ -67,3 +73,162 @@ func (a *App) DisableAutoResponder(userId string, asAdmin bool) *model.AppError
 
 	return nil
 }
+
+func (a *App) GetAutoResponderSchedule(userId string) (*model.AutoResponderSchedule, *model.AppError) {
+	if result := <-a.Srv.Store.AutoResponderSchedule().Get(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.AutoResponderSchedule), nil
+	}
+}
+
+// SetAutoResponderSchedule schedules a user's auto-responder, replacing any schedule that they already had. The
+// auto-responder sends the message that the user has already set up, and it's turned on straight away if the window
+// has already started.
+func (a *App) SetAutoResponderSchedule(schedule *model.AutoResponderSchedule) (*model.AutoResponderSchedule, *model.AppError) {
+	user, err := a.GetUser(schedule.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.NotifyProps["auto_responder_message"] == "" {
+		return nil, model.NewAppError("SetAutoResponderSchedule", "app.auto_responder.schedule.no_message.app_error", nil, "user_id="+schedule.UserId, http.StatusBadRequest)
+	}
+
+	now := model.GetMillis()
+	if schedule.IsEnded(now) {
+		return nil, model.NewAppError("SetAutoResponderSchedule", "app.auto_responder.schedule.ended.app_error", nil, "user_id="+schedule.UserId, http.StatusBadRequest)
+	}
+
+	var oldSchedule *model.AutoResponderSchedule
+	if result := <-a.Srv.Store.AutoResponderSchedule().Get(schedule.UserId); result.Err == nil {
+		oldSchedule = result.Data.(*model.AutoResponderSchedule)
+		schedule.CreateAt = oldSchedule.CreateAt
+	} else if result.Err.StatusCode != http.StatusNotFound {
+		return nil, result.Err
+	}
+
+	// The auto-responder is turned on now if the new window has already started, and turned off again if the old
+	// window had started but the new one hasn't
+	schedule.Active = schedule.IsStarted(now)
+	if schedule.Active {
+		if err := a.setScheduledAutoResponderActive(schedule.UserId, true, schedule.SetStatus); err != nil {
+			return nil, err
+		}
+	} else if oldSchedule != nil && oldSchedule.Active {
+		if err := a.setScheduledAutoResponderActive(schedule.UserId, false, oldSchedule.SetStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	if result := <-a.Srv.Store.AutoResponderSchedule().Save(schedule); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.AutoResponderSchedule), nil
+	}
+}
+
+// DeleteAutoResponderSchedule cancels a user's schedule, turning their auto-responder off if the window had started.
+func (a *App) DeleteAutoResponderSchedule(userId string) *model.AppError {
+	schedule, err := a.GetAutoResponderSchedule(userId)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Active {
+		if err := a.setScheduledAutoResponderActive(userId, false, schedule.SetStatus); err != nil {
+			return err
+		}
+	}
+
+	if result := <-a.Srv.Store.AutoResponderSchedule().Delete(userId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// ApplyAutoResponderSchedules turns auto-responders on for the windows that have started and off for the ones that
+// have ended. A schedule is removed once its window ends.
+func (a *App) ApplyAutoResponderSchedules() *model.AppError {
+	now := model.GetMillis()
+
+	for {
+		var schedules []*model.AutoResponderSchedule
+		if result := <-a.Srv.Store.AutoResponderSchedule().GetDue(now, AUTO_RESPONDER_SCHEDULE_BATCH_SIZE); result.Err != nil {
+			return result.Err
+		} else {
+			schedules = result.Data.([]*model.AutoResponderSchedule)
+		}
+
+		applied := 0
+		for _, schedule := range schedules {
+			if err := a.applyAutoResponderSchedule(schedule, now); err != nil {
+				mlog.Error(fmt.Sprintf("Failed to apply the auto responder schedule for user_id=%v, err=%v", schedule.UserId, err))
+				continue
+			}
+			applied++
+		}
+
+		// Stop rather than fetching the same schedules again when none of them could be applied
+		if len(schedules) < AUTO_RESPONDER_SCHEDULE_BATCH_SIZE || applied == 0 {
+			return nil
+		}
+	}
+}
+
+func (a *App) applyAutoResponderSchedule(schedule *model.AutoResponderSchedule, now int64) *model.AppError {
+	if schedule.IsEnded(now) {
+		if schedule.Active {
+			if err := a.setScheduledAutoResponderActive(schedule.UserId, false, schedule.SetStatus); err != nil {
+				return err
+			}
+		}
+
+		if result := <-a.Srv.Store.AutoResponderSchedule().Delete(schedule.UserId); result.Err != nil {
+			return result.Err
+		}
+
+		return nil
+	}
+
+	if err := a.setScheduledAutoResponderActive(schedule.UserId, true, schedule.SetStatus); err != nil {
+		return err
+	}
+
+	schedule.Active = true
+	if result := <-a.Srv.Store.AutoResponderSchedule().Save(schedule); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// setScheduledAutoResponderActive turns a user's auto-responder on or off, only changing their status to or from out
+// of office if the schedule asks for it.
+func (a *App) setScheduledAutoResponderActive(userId string, active bool, setStatus bool) *model.AppError {
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if (user.NotifyProps["auto_responder_active"] == "true") == active {
+		return nil
+	}
+
+	oldNotifyProps := model.CopyStringMap(user.NotifyProps)
+
+	patch := &model.UserPatch{NotifyProps: model.CopyStringMap(user.NotifyProps)}
+	patch.NotifyProps["auto_responder_active"] = strconv.FormatBool(active)
+
+	ruser, err := a.PatchUser(userId, patch, true)
+	if err != nil {
+		return err
+	}
+
+	if setStatus {
+		a.SetAutoResponderStatus(ruser, oldNotifyProps)
+	}
+
+	return nil
+}
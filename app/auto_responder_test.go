@@ -158,3 +158,92 @@ func TestSendAutoResponseFailure(t *testing.T) {
 		assert.False(t, autoResponderIsComment)
 	}
 }
+
+func TestAutoResponderSchedule(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	user := th.CreateUser()
+	defer th.App.PermanentDeleteUser(user)
+
+	th.App.SetStatusOnline(user.Id, true)
+
+	now := model.GetMillis()
+
+	_, err := th.App.SetAutoResponderSchedule(&model.AutoResponderSchedule{UserId: user.Id, StartAt: now + 60000, EndAt: now + 120000})
+	require.NotNil(t, err, "should require an auto responder message")
+
+	patch := &model.UserPatch{NotifyProps: model.CopyStringMap(user.NotifyProps)}
+	patch.NotifyProps["auto_responder_message"] = "Hello, I'm unavailable today."
+	_, err = th.App.PatchUser(user.Id, patch, true)
+	require.Nil(t, err)
+
+	schedule, err := th.App.SetAutoResponderSchedule(&model.AutoResponderSchedule{UserId: user.Id, StartAt: now + 60000, EndAt: now + 120000, SetStatus: true})
+	require.Nil(t, err)
+	assert.False(t, schedule.Active, "shouldn't turn the auto responder on before the window starts")
+
+	// Move the window so that it has started
+	schedule.StartAt = now - 60000
+	schedule.Active = false
+	scheduleStore := th.App.Srv.Store.AutoResponderSchedule()
+	require.Nil(t, (<-scheduleStore.Save(schedule)).Err)
+
+	require.Nil(t, th.App.ApplyAutoResponderSchedules())
+
+	user, err = th.App.GetUser(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "true", user.NotifyProps["auto_responder_active"])
+
+	status, err := th.App.GetStatus(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.STATUS_OUT_OF_OFFICE, status.Status)
+
+	// Move the window so that it has ended
+	schedule.EndAt = now - 1
+	schedule.StartAt = now - 60000
+	schedule.Active = true
+	require.Nil(t, (<-scheduleStore.Save(schedule)).Err)
+
+	require.Nil(t, th.App.ApplyAutoResponderSchedules())
+
+	user, err = th.App.GetUser(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "false", user.NotifyProps["auto_responder_active"])
+
+	status, err = th.App.GetStatus(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.STATUS_ONLINE, status.Status)
+
+	_, err = th.App.GetAutoResponderSchedule(user.Id)
+	assert.NotNil(t, err, "should remove the schedule once it ends")
+}
+
+func TestAutoResponderScheduleWithoutStatus(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	user := th.CreateUser()
+	defer th.App.PermanentDeleteUser(user)
+
+	th.App.SetStatusOnline(user.Id, true)
+
+	patch := &model.UserPatch{NotifyProps: model.CopyStringMap(user.NotifyProps)}
+	patch.NotifyProps["auto_responder_message"] = "Hello, I'm unavailable today."
+	_, err := th.App.PatchUser(user.Id, patch, true)
+	require.Nil(t, err)
+
+	now := model.GetMillis()
+	schedule, err := th.App.SetAutoResponderSchedule(&model.AutoResponderSchedule{UserId: user.Id, StartAt: now - 1000, EndAt: now + 60000})
+	require.Nil(t, err)
+	assert.True(t, schedule.Active, "should turn the auto responder on straight away once the window has started")
+
+	status, err := th.App.GetStatus(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.STATUS_ONLINE, status.Status, "shouldn't change the status unless asked to")
+
+	require.Nil(t, th.App.DeleteAutoResponderSchedule(user.Id))
+
+	user, err = th.App.GetUser(user.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "false", user.NotifyProps["auto_responder_active"])
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const BOTS_DISABLE_PAGE_SIZE = 100
+
+// CreateBot creates a bot along with the user that it posts as.
+func (a *App) CreateBot(bot *model.Bot) (*model.Bot, *model.AppError) {
+	user := bot.User()
+	user.Id = ""
+
+	var ruser *model.User
+	if result := <-a.Srv.Store.User().Save(user); result.Err != nil {
+		return nil, result.Err
+	} else {
+		ruser = result.Data.(*model.User)
+	}
+
+	bot.UserId = ruser.Id
+	bot.Username = ruser.Username
+
+	if result := <-a.Srv.Store.Bot().Save(bot); result.Err != nil {
+		// Don't leave behind a user that can never be used
+		if deleteResult := <-a.Srv.Store.User().PermanentDelete(ruser.Id); deleteResult.Err != nil {
+			mlog.Error(fmt.Sprintf("Failed to delete the user of a bot that couldn't be created err=%v", deleteResult.Err), mlog.String("user_id", ruser.Id))
+		}
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Bot), nil
+	}
+}
+
+func (a *App) GetBot(botUserId string, includeDeleted bool) (*model.Bot, *model.AppError) {
+	if result := <-a.Srv.Store.Bot().Get(botUserId, includeDeleted); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Bot), nil
+	}
+}
+
+func (a *App) GetBots(options *model.BotGetOptions) ([]*model.Bot, *model.AppError) {
+	if result := <-a.Srv.Store.Bot().GetAll(options); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.Bot), nil
+	}
+}
+
+// PatchBot updates a bot. Changes to its username and display name are made to its user as well.
+func (a *App) PatchBot(botUserId string, patch *model.BotPatch) (*model.Bot, *model.AppError) {
+	bot, err := a.GetBot(botUserId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.GetUser(botUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	bot.Patch(patch)
+	bot.UpdateUser(user)
+
+	// Validate the bot before its user is changed so that the two can't end up out of sync
+	bot.PreUpdate()
+	if err := bot.IsValid(); err != nil {
+		return nil, err
+	}
+
+	ruser, err := a.UpdateUser(user, false)
+	if err != nil {
+		return nil, err
+	}
+	bot.Username = ruser.Username
+
+	if result := <-a.Srv.Store.Bot().Update(bot); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Bot), nil
+	}
+}
+
+// UpdateBotActive enables or disables a bot. Its user is deactivated along with it so that its access tokens stop
+// working.
+func (a *App) UpdateBotActive(botUserId string, active bool) (*model.Bot, *model.AppError) {
+	user, err := a.GetUser(botUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsBot {
+		return nil, model.NewAppError("UpdateBotActive", "app.bot.not_bot.app_error", nil, "user_id="+botUserId, http.StatusNotFound)
+	}
+
+	if _, err := a.UpdateActive(user, active); err != nil {
+		return nil, err
+	}
+
+	return a.GetBot(botUserId, true)
+}
+
+// UpdateBotOwner assigns a bot to another user.
+func (a *App) UpdateBotOwner(botUserId, newOwnerId string) (*model.Bot, *model.AppError) {
+	bot, err := a.GetBot(botUserId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.GetUser(newOwnerId); err != nil {
+		return nil, err
+	}
+
+	bot.OwnerId = newOwnerId
+
+	if result := <-a.Srv.Store.Bot().Update(bot); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Bot), nil
+	}
+}
+
+// updateBotDeleteAt keeps a bot in sync with its user when the user is activated or deactivated.
+func (a *App) updateBotDeleteAt(botUserId string, deleteAt int64) *model.AppError {
+	bot, err := a.GetBot(botUserId, true)
+	if err != nil {
+		return err
+	}
+
+	if bot.DeleteAt == deleteAt {
+		return nil
+	}
+
+	bot.DeleteAt = deleteAt
+
+	if result := <-a.Srv.Store.Bot().Update(bot); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// disableUserBots disables the bots owned by a user who has been deactivated so that nobody is left in charge of them.
+func (a *App) disableUserBots(userId string) {
+	for page := 0; ; page++ {
+		bots, err := a.GetBots(&model.BotGetOptions{
+			OwnerId:        userId,
+			IncludeDeleted: true,
+			Page:           page,
+			PerPage:        BOTS_DISABLE_PAGE_SIZE,
+		})
+		if err != nil {
+			mlog.Error(fmt.Sprintf("Failed to get the bots of a deactivated user err=%v", err), mlog.String("user_id", userId))
+			return
+		}
+
+		for _, bot := range bots {
+			if bot.DeleteAt != 0 {
+				continue
+			}
+
+			if _, err := a.UpdateBotActive(bot.UserId, false); err != nil {
+				mlog.Error(fmt.Sprintf("Failed to disable the bot of a deactivated user err=%v", err), mlog.String("user_id", userId), mlog.String("bot_user_id", bot.UserId))
+			}
+		}
+
+		if len(bots) < BOTS_DISABLE_PAGE_SIZE {
+			return
+		}
+	}
+}
+
+// SessionHasPermissionToManageBot returns an error if the session can't manage a bot. Owners need permission to
+// manage their own bots, while everyone else needs permission to manage the bots of others.
+func (a *App) SessionHasPermissionToManageBot(session model.Session, botUserId string) *model.AppError {
+	bot, err := a.GetBot(botUserId, true)
+	if err != nil {
+		return err
+	}
+
+	permission := model.PERMISSION_MANAGE_OTHERS_BOTS
+	if bot.OwnerId == session.UserId {
+		permission = model.PERMISSION_MANAGE_BOTS
+	}
+
+	if !a.SessionHasPermissionTo(session, permission) {
+		return model.NewAppError("SessionHasPermissionToManageBot", "api.context.permissions.app_error", nil, "userId="+session.UserId+", permission="+permission.Id, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// SessionHasPermissionToUserOrBot returns true if the session can act as a user, either because it has permission to
+// edit the user or because the user is a bot that it manages.
+func (a *App) SessionHasPermissionToUserOrBot(session model.Session, userId string) bool {
+	if a.SessionHasPermissionToUser(session, userId) {
+		return true
+	}
+
+	return a.SessionHasPermissionToManageBot(session, userId) == nil
+}
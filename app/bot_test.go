@@ -0,0 +1,175 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestCreateBot(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{
+		Username:    "Bot" + model.NewId(),
+		DisplayName: "Test Bot",
+		Description: "Tests things",
+		OwnerId:     th.BasicUser.Id,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, th.BasicUser.Id, bot.OwnerId)
+	assert.NotZero(t, bot.CreateAt)
+
+	user, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	assert.True(t, user.IsBot)
+	assert.Equal(t, bot.Username, user.Username, "should lower case the username")
+	assert.Equal(t, "Test Bot", user.FirstName)
+
+	_, err = th.App.CreateBot(&model.Bot{Username: bot.Username, OwnerId: th.BasicUser.Id})
+	require.NotNil(t, err, "shouldn't allow the username of an existing user")
+
+	_, err = th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId()})
+	require.NotNil(t, err, "shouldn't create bots without owners")
+}
+
+func TestPatchBot(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	username := "renamed" + model.NewId()
+	displayName := "Renamed Bot"
+	bot, err = th.App.PatchBot(bot.UserId, &model.BotPatch{Username: &username, DisplayName: &displayName})
+	require.Nil(t, err)
+	assert.Equal(t, username, bot.Username)
+	assert.Equal(t, displayName, bot.DisplayName)
+
+	user, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	assert.Equal(t, username, user.Username)
+	assert.Equal(t, displayName, user.FirstName)
+	assert.True(t, user.IsBot, "should stay a bot")
+
+	_, err = th.App.PatchBot(bot.UserId, &model.BotPatch{Username: &th.BasicUser2.Username})
+	require.NotNil(t, err)
+
+	user, err = th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	assert.Equal(t, username, user.Username)
+}
+
+func TestCreateBotWithRenamedBotsName(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	username := "bot" + model.NewId()
+	bot, err := th.App.CreateBot(&model.Bot{Username: username, OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	renamed := "renamed" + model.NewId()
+	_, err = th.App.PatchBot(bot.UserId, &model.BotPatch{Username: &renamed})
+	require.Nil(t, err)
+
+	recreated, err := th.App.CreateBot(&model.Bot{Username: username, OwnerId: th.BasicUser.Id})
+	require.Nil(t, err, "the old name should be free once the bot is renamed")
+	assert.Equal(t, username, recreated.Username)
+}
+
+func TestUpdateBotActive(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	bot, err = th.App.UpdateBotActive(bot.UserId, false)
+	require.Nil(t, err)
+	assert.NotZero(t, bot.DeleteAt)
+
+	_, err = th.App.GetBot(bot.UserId, false)
+	require.NotNil(t, err)
+
+	bot, err = th.App.UpdateBotActive(bot.UserId, true)
+	require.Nil(t, err)
+	assert.Zero(t, bot.DeleteAt)
+
+	_, err = th.App.UpdateBotActive(th.BasicUser2.Id, false)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode, "shouldn't deactivate users that aren't bots")
+}
+
+func TestDisableBotsWhenOwnerIsDeactivated(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	owner := th.CreateUser()
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: owner.Id})
+	require.Nil(t, err)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.DisableBotsWhenOwnerIsDeactivated = false })
+
+	_, err = th.App.UpdateActive(owner, false)
+	require.Nil(t, err)
+
+	bot, err = th.App.GetBot(bot.UserId, true)
+	require.Nil(t, err)
+	assert.Zero(t, bot.DeleteAt, "should keep bots enabled when the setting is off")
+
+	owner, err = th.App.UpdateActive(owner, true)
+	require.Nil(t, err)
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.DisableBotsWhenOwnerIsDeactivated = true })
+
+	_, err = th.App.UpdateActive(owner, false)
+	require.Nil(t, err)
+
+	bot, err = th.App.GetBot(bot.UserId, true)
+	require.Nil(t, err)
+	assert.NotZero(t, bot.DeleteAt)
+
+	user, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	assert.NotZero(t, user.DeleteAt, "should deactivate the bot's user so that its tokens stop working")
+}
+
+func TestCreatePostFromBot(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	bot, err := th.App.CreateBot(&model.Bot{Username: "bot" + model.NewId(), OwnerId: th.BasicUser.Id})
+	require.Nil(t, err)
+
+	_, err = th.App.AddUserToTeam(th.BasicTeam.Id, bot.UserId, "")
+	require.Nil(t, err)
+	user, err := th.App.GetUser(bot.UserId)
+	require.Nil(t, err)
+	_, err = th.App.AddUserToChannel(user, th.BasicChannel)
+	require.Nil(t, err)
+
+	post, err := th.App.CreatePost(&model.Post{
+		UserId:    bot.UserId,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "from a bot",
+	}, th.BasicChannel, false)
+	require.Nil(t, err)
+	assert.Equal(t, "true", post.Props[model.POST_PROPS_FROM_BOT])
+
+	post, err = th.App.CreatePost(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "pretending to be a bot",
+		Props:     model.StringInterface{model.POST_PROPS_FROM_BOT: "true"},
+	}, th.BasicChannel, false)
+	require.Nil(t, err)
+	assert.Nil(t, post.Props[model.POST_PROPS_FROM_BOT], "shouldn't let users flag their own posts")
+}
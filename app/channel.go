@@ -75,7 +75,7 @@ func (a *App) JoinDefaultChannels(teamId string, user *model.User, shouldBeAdmin
 				UserId:      user.Id,
 				SchemeUser:  true,
 				SchemeAdmin: shouldBeAdmin,
-				NotifyProps: model.GetDefaultChannelNotifyProps(),
+				NotifyProps: a.getNewMemberChannelNotifyProps(channel.Id),
 			}
 
 			if cmResult := <-a.Srv.Store.Channel().SaveMember(cm); cmResult.Err != nil {
@@ -449,7 +449,11 @@ func (a *App) UpdateChannelPrivacy(oldChannel *model.Channel, user *model.User)
 
 		a.InvalidateCacheForChannel(channel)
 
+		// Once a channel is private, only its members may know that it exists
 		messageWs := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_CONVERTED, channel.TeamId, "", "", nil)
+		if channel.Type == model.CHANNEL_PRIVATE {
+			messageWs = model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_CONVERTED, "", channel.Id, "", nil)
+		}
 		messageWs.Add("channel_id", channel.Id)
 		a.Publish(messageWs)
 
@@ -649,6 +653,21 @@ func (a *App) UpdateChannelMemberNotifyProps(data map[string]string, channelId s
 		member.NotifyProps[model.PUSH_NOTIFY_PROP] = push
 	}
 
+	for _, prop := range model.NotifyScheduleProps {
+		if value, exists := data[prop]; exists {
+			member.NotifyProps[prop] = value
+		}
+	}
+
+	// an empty time stops snoozing the channel
+	if mutedUntil, exists := data[model.MUTED_UNTIL_NOTIFY_PROP]; exists {
+		if mutedUntil == "" {
+			delete(member.NotifyProps, model.MUTED_UNTIL_NOTIFY_PROP)
+		} else {
+			member.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP] = mutedUntil
+		}
+	}
+
 	if result := <-a.Srv.Store.Channel().UpdateMember(member); result.Err != nil {
 		return nil, result.Err
 	} else {
@@ -733,10 +752,29 @@ func (a *App) DeleteChannel(channel *model.Channel, userId string) *model.AppErr
 		}
 		a.InvalidateCacheForChannel(channel)
 
+		// Anyone on the team can see a public channel, but only the members of a private channel know that it exists
 		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_DELETED, channel.TeamId, "", "", nil)
+		if channel.Type == model.CHANNEL_PRIVATE {
+			message = model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_DELETED, "", channel.Id, "", nil)
+		}
 		message.Add("channel_id", channel.Id)
 		message.Add("delete_at", deleteAt)
 		a.Publish(message)
+
+		// Subscriptions are for a whole team, so they aren't told about private channels that their creators can't see
+		if channel.Type == model.CHANNEL_OPEN {
+			archivedChannel := channel.DeepCopy()
+			archivedChannel.DeleteAt = deleteAt
+
+			a.sendEventSubscriptionEvent(&model.EventSubscriptionPayload{
+				Event:     model.EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED,
+				Timestamp: deleteAt,
+				TeamId:    channel.TeamId,
+				ChannelId: channel.Id,
+				UserId:    userId,
+				Channel:   archivedChannel,
+			})
+		}
 	}
 
 	return nil
@@ -761,7 +799,7 @@ func (a *App) addUserToChannel(user *model.User, channel *model.Channel, teamMem
 	newMember := &model.ChannelMember{
 		ChannelId:   channel.Id,
 		UserId:      user.Id,
-		NotifyProps: model.GetDefaultChannelNotifyProps(),
+		NotifyProps: a.getNewMemberChannelNotifyProps(channel.Id),
 		SchemeUser:  true,
 	}
 	if result := <-a.Srv.Store.Channel().SaveMember(newMember); result.Err != nil {
@@ -844,6 +882,12 @@ func (a *App) AddChannelMember(userId string, channel *model.Channel, userReques
 		})
 	}
 
+	a.sendOutgoingWebhookEvent(&outgoingWebhookEvent{
+		Type:      model.OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL,
+		ChannelId: channel.Id,
+		UserId:    user.Id,
+	})
+
 	if userRequestorId == "" || userId == userRequestorId {
 		a.postJoinChannelMessage(user, channel)
 	} else {
@@ -1032,6 +1076,19 @@ func (a *App) GetChannelByName(channelName, teamId string, includeDeleted bool)
 	return result.Data.(*model.Channel), nil
 }
 
+func (a *App) GetChannelByRemoteId(service, externalId string) (*model.Channel, *model.AppError) {
+	remoteId := model.NewRemoteId(service, externalId)
+	if !model.IsValidRemoteId(&remoteId) {
+		return nil, model.NewAppError("GetChannelByRemoteId", "api.channel.get_channel_by_remote_id.invalid.app_error", nil, "remote_id="+remoteId, http.StatusBadRequest)
+	}
+
+	if result := <-a.Srv.Store.Channel().GetByRemoteId(remoteId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Channel), nil
+	}
+}
+
 func (a *App) GetChannelsByNames(channelNames []string, teamId string) ([]*model.Channel, *model.AppError) {
 	if result := <-a.Srv.Store.Channel().GetByNames(teamId, channelNames, true); result.Err != nil && result.Err.Id == "store.sql_channel.get_by_name.missing.app_error" {
 		result.Err.StatusCode = http.StatusNotFound
@@ -1138,6 +1195,14 @@ func (a *App) GetChannelMembersByIds(channelId string, userIds []string) (*model
 	}
 }
 
+func (a *App) GetChannelMembersForUserByChannelIds(userId string, channelIds []string) (*model.ChannelMembers, *model.AppError) {
+	if result := <-a.Srv.Store.Channel().GetMembersForUserByChannelIds(userId, channelIds); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ChannelMembers), nil
+	}
+}
+
 func (a *App) GetChannelMembersForUser(teamId string, userId string) (*model.ChannelMembers, *model.AppError) {
 	if result := <-a.Srv.Store.Channel().GetMembersForUser(teamId, userId); result.Err != nil {
 		return nil, result.Err
@@ -1204,6 +1269,12 @@ func (a *App) JoinChannel(channel *model.Channel, userId string) *model.AppError
 				})
 			}
 
+			a.sendOutgoingWebhookEvent(&outgoingWebhookEvent{
+				Type:      model.OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL,
+				ChannelId: channel.Id,
+				UserId:    user.Id,
+			})
+
 			if err := a.postJoinChannelMessage(user, channel); err != nil {
 				return err
 			}
@@ -1482,19 +1553,42 @@ func (a *App) SetActiveChannel(userId string, channelId string) *model.AppError
 }
 
 func (a *App) UpdateChannelLastViewedAt(channelIds []string, userId string) *model.AppError {
-	if result := <-a.Srv.Store.Channel().UpdateLastViewedAt(channelIds, userId); result.Err != nil {
+	result := <-a.Srv.Store.Channel().UpdateLastViewedAt(channelIds, userId)
+	if result.Err != nil {
 		return result.Err
 	}
 
-	if *a.Config().ServiceSettings.EnableChannelViewedMessages {
-		for _, channelId := range channelIds {
-			message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_VIEWED, "", "", userId, nil)
-			message.Add("channel_id", channelId)
-			a.Publish(message)
+	a.publishChannelsViewed(userId, channelIds, true)
+	a.publishReadReceipts(userId, result.Data.(map[string]int64))
+
+	return nil
+}
+
+// publishChannelsViewed lets the user's other clients know that the channels were read. If withMentionCount is set,
+// the user's new total unread mention count is sent along so that they can update their badges without recomputing
+// it. Otherwise, clients that need the count recompute it themselves.
+func (a *App) publishChannelsViewed(userId string, channelIds []string, withMentionCount bool) {
+	if !*a.Config().ServiceSettings.EnableChannelViewedMessages {
+		return
+	}
+
+	totalMentionCount := int64(-1)
+	if withMentionCount {
+		var err *model.AppError
+		if totalMentionCount, err = a.GetTotalUnreadMentions(userId); err != nil {
+			mlog.Error("Unable to get the total unread mention count", mlog.String("user_id", userId), mlog.Err(err))
+			totalMentionCount = -1
 		}
 	}
 
-	return nil
+	for _, channelId := range channelIds {
+		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_VIEWED, "", "", userId, nil)
+		message.Add("channel_id", channelId)
+		if totalMentionCount >= 0 {
+			message.Add("total_mention_count", totalMentionCount)
+		}
+		a.Publish(message)
+	}
 }
 
 func (a *App) AutocompleteChannels(teamId string, term string) (*model.ChannelList, *model.AppError) {
@@ -1540,7 +1634,7 @@ func (a *App) ViewChannel(view *model.ChannelView, userId string, clearPushNotif
 	if len(view.PrevChannelId) > 0 {
 		channelIds = append(channelIds, view.PrevChannelId)
 
-		if *a.Config().EmailSettings.SendPushNotifications && clearPushNotifications && len(view.ChannelId) > 0 {
+		if (*a.Config().EmailSettings.SendPushNotifications || *a.Config().EmailSettings.EnableWebPushNotifications) && clearPushNotifications && len(view.ChannelId) > 0 {
 			pchan = a.Srv.Store.User().GetUnreadCountForChannel(userId, view.ChannelId)
 		}
 	}
@@ -1551,13 +1645,12 @@ func (a *App) ViewChannel(view *model.ChannelView, userId string, clearPushNotif
 
 	uchan := a.Srv.Store.Channel().UpdateLastViewedAt(channelIds, userId)
 
+	clearPushNotification := false
 	if pchan != nil {
 		if result := <-pchan; result.Err != nil {
 			return nil, result.Err
 		} else {
-			if result.Data.(int64) > 0 {
-				a.ClearPushNotification(userId, view.ChannelId)
-			}
+			clearPushNotification = result.Data.(int64) > 0
 		}
 	}
 
@@ -1568,12 +1661,17 @@ func (a *App) ViewChannel(view *model.ChannelView, userId string, clearPushNotif
 		times = result.Data.(map[string]int64)
 	}
 
-	if *a.Config().ServiceSettings.EnableChannelViewedMessages && model.IsValidId(view.ChannelId) {
-		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_VIEWED, "", "", userId, nil)
-		message.Add("channel_id", view.ChannelId)
-		a.Publish(message)
+	// Wait until the channel has been marked as read so that the badge sent with the notification is accurate
+	if clearPushNotification {
+		a.ClearPushNotification(userId, view.ChannelId)
+	}
+
+	if model.IsValidId(view.ChannelId) {
+		a.publishChannelsViewed(userId, []string{view.ChannelId}, true)
 	}
 
+	a.publishReadReceipts(userId, times)
+
 	return times, nil
 }
 
@@ -1586,6 +1684,14 @@ func (a *App) PermanentDeleteChannel(channel *model.Channel) *model.AppError {
 		return result.Err
 	}
 
+	if result := <-a.Srv.Store.ChannelBridge().DeleteForChannel(channel.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-a.Srv.Store.ChannelNotifyDefaults().Delete(channel.Id); result.Err != nil {
+		return result.Err
+	}
+
 	if result := <-a.Srv.Store.Webhook().PermanentDeleteIncomingByChannel(channel.Id); result.Err != nil {
 		return result.Err
 	}
@@ -0,0 +1,488 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const CHANNEL_BRIDGE_PROP = "from_bridge"
+
+// channelBridgeHealth keeps the health of a bridge up to date as events pass through it.
+type channelBridgeHealth struct {
+	mutex  sync.Mutex
+	health model.ChannelBridgeHealth
+}
+
+func (h *channelBridgeHealth) recordEvent(incoming bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if incoming {
+		h.health.IncomingEvents++
+	} else {
+		h.health.OutgoingEvents++
+	}
+	h.health.LastEventAt = model.GetMillis()
+}
+
+func (h *channelBridgeHealth) recordError(message string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.health.FailedEvents++
+	h.health.LastError = message
+	h.health.LastErrorAt = model.GetMillis()
+}
+
+func (h *channelBridgeHealth) recordConflict() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.health.Conflicts++
+}
+
+func (h *channelBridgeHealth) snapshot() *model.ChannelBridgeHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	health := h.health
+	return &health
+}
+
+func (a *App) getChannelBridgeHealth(bridgeId string) *channelBridgeHealth {
+	health, _ := a.channelBridgeHealth.LoadOrStore(bridgeId, &channelBridgeHealth{health: model.ChannelBridgeHealth{BridgeId: bridgeId}})
+	return health.(*channelBridgeHealth)
+}
+
+// channelBridgeEchoKey identifies a change that was received from a bridge so that it isn't sent back to it.
+func channelBridgeEchoKey(bridgeId string, eventType string, postId string, reaction *model.Reaction) string {
+	key := strings.Join([]string{bridgeId, eventType, postId}, ":")
+	if reaction != nil {
+		key += ":" + reaction.UserId + ":" + reaction.EmojiName
+	}
+
+	return key
+}
+
+// isChannelBridgeEcho returns true, only once, if the change was received from the bridge.
+func (a *App) isChannelBridgeEcho(key string) bool {
+	if _, ok := a.channelBridgeEchoes.Load(key); !ok {
+		return false
+	}
+
+	a.channelBridgeEchoes.Delete(key)
+	return true
+}
+
+// shouldApplyChannelBridgeChange decides whether an edit or deletion made on the other side of a bridge is applied to
+// a post. The change conflicts with the post when the post was edited here after the change was made there.
+func shouldApplyChannelBridgeChange(policy string, bridgeId string, post *model.Post, timestamp int64) (apply bool, conflict bool) {
+	conflict = post.EditAt > timestamp
+
+	switch policy {
+	case model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS:
+		return true, conflict
+	case model.CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS:
+		// posts that were written here can only be changed here
+		if post.Props[CHANNEL_BRIDGE_PROP] != bridgeId {
+			return false, true
+		}
+
+		return !conflict, conflict
+	default:
+		return !conflict, conflict
+	}
+}
+
+func (a *App) CreateChannelBridge(bridge *model.ChannelBridge) (*model.ChannelBridge, *model.AppError) {
+	if _, err := a.GetChannel(bridge.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.ChannelBridge().Save(bridge); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ChannelBridge), nil
+	}
+}
+
+func (a *App) GetChannelBridge(bridgeId string) (*model.ChannelBridge, *model.AppError) {
+	if result := <-a.Srv.Store.ChannelBridge().Get(bridgeId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ChannelBridge), nil
+	}
+}
+
+func (a *App) GetAllChannelBridges() ([]*model.ChannelBridge, *model.AppError) {
+	if result := <-a.Srv.Store.ChannelBridge().GetAll(); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.ChannelBridge), nil
+	}
+}
+
+func (a *App) GetChannelBridgesForChannel(channelId string) ([]*model.ChannelBridge, *model.AppError) {
+	if result := <-a.Srv.Store.ChannelBridge().GetForChannel(channelId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.ChannelBridge), nil
+	}
+}
+
+// UpdateChannelBridge changes where a bridge sends its events and how it resolves conflicts. The channels it connects
+// and its token can't be changed.
+func (a *App) UpdateChannelBridge(oldBridge *model.ChannelBridge, updatedBridge *model.ChannelBridge) (*model.ChannelBridge, *model.AppError) {
+	bridge := &model.ChannelBridge{}
+	*bridge = *oldBridge
+
+	bridge.OutgoingURL = updatedBridge.OutgoingURL
+	bridge.ConflictPolicy = updatedBridge.ConflictPolicy
+
+	if result := <-a.Srv.Store.ChannelBridge().Update(bridge); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ChannelBridge), nil
+	}
+}
+
+func (a *App) DeleteChannelBridge(bridgeId string) *model.AppError {
+	if result := <-a.Srv.Store.ChannelBridge().Delete(bridgeId); result.Err != nil {
+		return result.Err
+	}
+
+	a.channelBridgeHealth.Delete(bridgeId)
+
+	return nil
+}
+
+// GetChannelBridgesHealth returns the health of the given bridges as seen by this server.
+func (a *App) GetChannelBridgesHealth(bridges []*model.ChannelBridge) []*model.ChannelBridgeHealth {
+	health := make([]*model.ChannelBridgeHealth, len(bridges))
+	for i, bridge := range bridges {
+		health[i] = a.getChannelBridgeHealth(bridge.Id).snapshot()
+	}
+
+	return health
+}
+
+// sendChannelBridgeEvents relays a change made in a channel to the services that the channel is bridged to, except
+// for the one that the change came from.
+func (a *App) sendChannelBridgeEvents(eventType string, post *model.Post, reaction *model.Reaction) {
+	if !*a.Config().ServiceSettings.EnableChannelBridges {
+		return
+	}
+
+	a.Go(func() {
+		bridges, err := a.GetChannelBridgesForChannel(post.ChannelId)
+		if err != nil {
+			mlog.Error("Unable to get the bridges for a channel", mlog.String("channel_id", post.ChannelId), mlog.Err(err))
+			return
+		}
+
+		for _, bridge := range bridges {
+			if a.isChannelBridgeEcho(channelBridgeEchoKey(bridge.Id, eventType, post.Id, reaction)) {
+				continue
+			}
+
+			// posts that already have a remote id on the bridged service were created by the bridge
+			if eventType == model.CHANNEL_BRIDGE_EVENT_POSTED && len(bridgeExternalId(bridge, post.RemoteId)) > 0 {
+				continue
+			}
+
+			if err := a.sendChannelBridgeEvent(bridge, a.buildChannelBridgeEvent(bridge, eventType, post, reaction), post); err != nil {
+				mlog.Warn("Unable to send an event to a channel bridge", mlog.String("bridge_id", bridge.Id), mlog.Err(err))
+				a.getChannelBridgeHealth(bridge.Id).recordError(err.Error())
+			}
+		}
+	})
+}
+
+func (a *App) buildChannelBridgeEvent(bridge *model.ChannelBridge, eventType string, post *model.Post, reaction *model.Reaction) *model.ChannelBridgeEvent {
+	event := &model.ChannelBridgeEvent{
+		Type:            eventType,
+		BridgeId:        bridge.Id,
+		RemoteChannelId: bridge.RemoteChannelId,
+		Timestamp:       model.GetMillis(),
+		PostId:          post.Id,
+		RootId:          post.RootId,
+		UserId:          post.UserId,
+		RemotePostId:    bridgeExternalId(bridge, post.RemoteId),
+	}
+
+	switch eventType {
+	case model.CHANNEL_BRIDGE_EVENT_POSTED:
+		event.Timestamp = post.CreateAt
+	case model.CHANNEL_BRIDGE_EVENT_POST_EDITED:
+		event.Timestamp = post.EditAt
+	case model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED, model.CHANNEL_BRIDGE_EVENT_REACTION_REMOVED:
+		event.UserId = reaction.UserId
+		event.EmojiName = reaction.EmojiName
+	}
+
+	if eventType == model.CHANNEL_BRIDGE_EVENT_POSTED || eventType == model.CHANNEL_BRIDGE_EVENT_POST_EDITED {
+		event.Message = post.Message
+
+		if len(post.FileIds) > 0 {
+			if infos, err := a.GetFileInfosForPost(post.Id, true); err != nil {
+				mlog.Warn("Unable to get the files of a bridged post", mlog.String("post_id", post.Id), mlog.Err(err))
+			} else {
+				for _, info := range infos {
+					file := &model.ChannelBridgeFile{Id: info.Id, Name: info.Name, MimeType: info.MimeType, Size: info.Size}
+					if a.Config().FileSettings.EnablePublicLink {
+						file.Url = a.GeneratePublicLink(a.GetSiteURL(), info)
+					}
+					event.Files = append(event.Files, file)
+				}
+			}
+		}
+	}
+
+	if len(post.RootId) > 0 {
+		if root, err := a.GetSinglePost(post.RootId); err == nil {
+			event.RemoteRootId = bridgeExternalId(bridge, root.RemoteId)
+		}
+	}
+
+	if user, err := a.GetUser(event.UserId); err == nil {
+		event.Username = user.Username
+		event.RemoteUserId = bridgeExternalId(bridge, user.RemoteId)
+	}
+
+	return event
+}
+
+// bridgeExternalId returns the id on the bridged service from a remote id, if it belongs to that service.
+func bridgeExternalId(bridge *model.ChannelBridge, remoteId *string) string {
+	if remoteId == nil {
+		return ""
+	}
+
+	if service, externalId, ok := model.ParseRemoteId(*remoteId); ok && service == bridge.Service {
+		return externalId
+	}
+
+	return ""
+}
+
+func (a *App) sendChannelBridgeEvent(bridge *model.ChannelBridge, event *model.ChannelBridgeEvent, post *model.Post) error {
+	req, err := http.NewRequest("POST", bridge.OutgoingURL, strings.NewReader(event.ToJson()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(model.HEADER_BRIDGE_TOKEN, bridge.Token)
+
+	resp, err := a.HTTPClient(false).Do(req)
+	if err != nil {
+		return err
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status=%v", resp.StatusCode)
+	}
+
+	a.getChannelBridgeHealth(bridge.Id).recordEvent(false)
+
+	// Remember which post was created on the other service so that changes to it can be matched in both directions
+	if event.Type == model.CHANNEL_BRIDGE_EVENT_POSTED && post.RemoteId == nil {
+		if response := model.ChannelBridgeEventResponseFromJson(resp.Body); response != nil && len(response.RemotePostId) > 0 {
+			mappedPost := &model.Post{}
+			*mappedPost = *post
+			mappedPost.RemoteId = model.NewString(model.NewRemoteId(bridge.Service, response.RemotePostId))
+			if result := <-a.Srv.Store.Post().Overwrite(mappedPost); result.Err != nil {
+				return result.Err
+			}
+		}
+	}
+
+	return nil
+}
+
+// HandleChannelBridgeEvent applies a change made on the other side of a bridge to its channel. Posts and users are
+// matched using their remote ids, and posts by users that don't have an account here are made by the bridge's creator
+// under the name they have on the other service.
+func (a *App) HandleChannelBridgeEvent(bridge *model.ChannelBridge, event *model.ChannelBridgeEvent) (*model.Post, *model.AppError) {
+	if err := event.IsValid(); err != nil {
+		return nil, err
+	}
+
+	health := a.getChannelBridgeHealth(bridge.Id)
+
+	post, err := a.handleChannelBridgeEvent(bridge, event)
+	if err != nil {
+		health.recordError(err.Error())
+		return nil, err
+	}
+
+	health.recordEvent(true)
+
+	return post, nil
+}
+
+func (a *App) handleChannelBridgeEvent(bridge *model.ChannelBridge, event *model.ChannelBridgeEvent) (*model.Post, *model.AppError) {
+	userId, overrideUsername := a.getChannelBridgeUser(bridge, event)
+
+	if event.Type == model.CHANNEL_BRIDGE_EVENT_POSTED {
+		return a.createChannelBridgePost(bridge, event, userId, overrideUsername)
+	}
+
+	post, err := a.GetPostByRemoteId(bridge.Service, event.RemotePostId)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.ChannelId != bridge.ChannelId {
+		return nil, model.NewAppError("HandleChannelBridgeEvent", "app.channel_bridge.handle_event.wrong_channel.app_error", nil, "bridge_id="+bridge.Id+", post_id="+post.Id, http.StatusBadRequest)
+	}
+
+	switch event.Type {
+	case model.CHANNEL_BRIDGE_EVENT_POST_EDITED, model.CHANNEL_BRIDGE_EVENT_POST_DELETED:
+		apply, conflict := shouldApplyChannelBridgeChange(bridge.ConflictPolicy, bridge.Id, post, event.Timestamp)
+		if conflict {
+			a.getChannelBridgeHealth(bridge.Id).recordConflict()
+		}
+
+		if !apply {
+			return post, nil
+		}
+
+		echoKey := channelBridgeEchoKey(bridge.Id, event.Type, post.Id, nil)
+		a.channelBridgeEchoes.Store(echoKey, true)
+
+		if event.Type == model.CHANNEL_BRIDGE_EVENT_POST_EDITED {
+			post.Message = event.Message
+			post, err = a.UpdatePost(post, true)
+		} else {
+			post, err = a.DeletePost(post.Id, userId)
+		}
+
+		if err != nil {
+			a.channelBridgeEchoes.Delete(echoKey)
+			return nil, err
+		}
+
+		return post, nil
+	default:
+		reaction := &model.Reaction{UserId: userId, PostId: post.Id, EmojiName: event.EmojiName}
+
+		echoKey := channelBridgeEchoKey(bridge.Id, event.Type, post.Id, reaction)
+		a.channelBridgeEchoes.Store(echoKey, true)
+
+		if event.Type == model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED {
+			_, err = a.SaveReactionForPost(reaction)
+		} else {
+			err = a.DeleteReactionForPost(reaction)
+		}
+
+		if err != nil {
+			a.channelBridgeEchoes.Delete(echoKey)
+			return nil, err
+		}
+
+		return post, nil
+	}
+}
+
+// getChannelBridgeUser returns who a change made on the other side of a bridge is made by here. That is the user who
+// was given the remote id of the user on the other service if they are a member of the channel, or otherwise the
+// creator of the bridge along with the username to show instead of theirs.
+func (a *App) getChannelBridgeUser(bridge *model.ChannelBridge, event *model.ChannelBridgeEvent) (string, string) {
+	if len(event.RemoteUserId) > 0 {
+		if user, err := a.GetUserByRemoteId(bridge.Service, event.RemoteUserId); err == nil {
+			if _, err := a.GetChannelMember(bridge.ChannelId, user.Id); err == nil {
+				return user.Id, ""
+			}
+		}
+	}
+
+	username := event.Username
+	if len(username) == 0 {
+		username = bridge.Service
+	}
+
+	return bridge.CreatorId, username
+}
+
+func (a *App) createChannelBridgePost(bridge *model.ChannelBridge, event *model.ChannelBridgeEvent, userId string, overrideUsername string) (*model.Post, *model.AppError) {
+	// The adapter may send the same post more than once if it didn't get a response the first time
+	if post, err := a.GetPostByRemoteId(bridge.Service, event.RemotePostId); err == nil {
+		return post, nil
+	}
+
+	channel, err := a.GetChannel(bridge.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		UserId:    userId,
+		Message:   event.Message,
+		RemoteId:  model.NewString(model.NewRemoteId(bridge.Service, event.RemotePostId)),
+	}
+	post.AddProp(CHANNEL_BRIDGE_PROP, bridge.Id)
+
+	if len(overrideUsername) > 0 {
+		post.AddProp("from_webhook", "true")
+		post.AddProp("override_username", overrideUsername)
+	}
+
+	if len(event.RemoteRootId) > 0 {
+		root, err := a.GetPostByRemoteId(bridge.Service, event.RemoteRootId)
+		if err != nil {
+			return nil, err
+		}
+
+		post.RootId = root.Id
+		post.ParentId = root.Id
+	}
+
+	for _, file := range event.Files {
+		info, err := a.uploadChannelBridgeFile(channel, userId, file)
+		if err != nil {
+			return nil, err
+		}
+
+		post.FileIds = append(post.FileIds, info.Id)
+	}
+
+	return a.CreatePost(post, channel, false)
+}
+
+func (a *App) uploadChannelBridgeFile(channel *model.Channel, userId string, file *model.ChannelBridgeFile) (*model.FileInfo, *model.AppError) {
+	resp, err := a.HTTPClient(false).Get(file.Url)
+	if err != nil {
+		return nil, model.NewAppError("uploadChannelBridgeFile", "app.channel_bridge.upload_file.download.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("uploadChannelBridgeFile", "app.channel_bridge.upload_file.download.app_error", nil, fmt.Sprintf("status=%v", resp.StatusCode), http.StatusBadRequest)
+	}
+
+	maxFileSize := *a.Config().FileSettings.MaxFileSize
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, model.NewAppError("uploadChannelBridgeFile", "app.channel_bridge.upload_file.download.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	if int64(len(data)) > maxFileSize {
+		return nil, model.NewAppError("uploadChannelBridgeFile", "app.channel_bridge.upload_file.too_large.app_error", map[string]interface{}{"Filename": file.Name}, "", http.StatusRequestEntityTooLarge)
+	}
+
+	return a.DoUploadFile(time.Now(), channel.TeamId, channel.Id, userId, file.Name, data)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestShouldApplyChannelBridgeChange(t *testing.T) {
+	bridgeId := model.NewId()
+
+	remotePost := &model.Post{EditAt: 1000, Props: model.StringInterface{CHANNEL_BRIDGE_PROP: bridgeId}}
+	localPost := &model.Post{EditAt: 1000}
+
+	for name, tc := range map[string]struct {
+		Policy    string
+		Post      *model.Post
+		Timestamp int64
+		Apply     bool
+		Conflict  bool
+	}{
+		"latest wins, newer change":              {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, remotePost, 2000, true, false},
+		"latest wins, older change":              {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, remotePost, 500, false, true},
+		"latest wins, local post":                {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, localPost, 2000, true, false},
+		"remote wins, older change":              {model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS, remotePost, 500, true, true},
+		"local wins, newer change to local post": {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS, localPost, 2000, false, true},
+		"local wins, newer change":               {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS, remotePost, 2000, true, false},
+		"local wins, older change":               {model.CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS, remotePost, 500, false, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			apply, conflict := shouldApplyChannelBridgeChange(tc.Policy, bridgeId, tc.Post, tc.Timestamp)
+			assert.Equal(t, tc.Apply, apply)
+			assert.Equal(t, tc.Conflict, conflict)
+		})
+	}
+}
+
+func TestChannelBridgeEchoes(t *testing.T) {
+	a := &App{}
+
+	reaction := &model.Reaction{UserId: model.NewId(), EmojiName: "smile"}
+	key := channelBridgeEchoKey("bridge", model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED, "post", reaction)
+	assert.NotEqual(t, key, channelBridgeEchoKey("bridge", model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED, "post", nil))
+	assert.NotEqual(t, key, channelBridgeEchoKey("other", model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED, "post", reaction))
+
+	assert.False(t, a.isChannelBridgeEcho(key))
+
+	a.channelBridgeEchoes.Store(key, true)
+	assert.True(t, a.isChannelBridgeEcho(key))
+	assert.False(t, a.isChannelBridgeEcho(key), "should only be an echo once")
+}
+
+func TestChannelBridgeHealth(t *testing.T) {
+	a := &App{}
+
+	health := a.getChannelBridgeHealth("bridge")
+	health.recordEvent(true)
+	health.recordEvent(false)
+	health.recordEvent(false)
+	health.recordConflict()
+	health.recordError("status=500")
+
+	snapshot := a.GetChannelBridgesHealth([]*model.ChannelBridge{{Id: "bridge"}, {Id: "unused"}})
+	assert.Equal(t, "bridge", snapshot[0].BridgeId)
+	assert.Equal(t, int64(1), snapshot[0].IncomingEvents)
+	assert.Equal(t, int64(2), snapshot[0].OutgoingEvents)
+	assert.Equal(t, int64(1), snapshot[0].Conflicts)
+	assert.Equal(t, int64(1), snapshot[0].FailedEvents)
+	assert.Equal(t, "status=500", snapshot[0].LastError)
+	assert.Equal(t, &model.ChannelBridgeHealth{BridgeId: "unused"}, snapshot[1])
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// GetChannelNotifyDefaults returns the notification settings that new members of a channel start with. Channels
+// without any defaults return an empty set of notify props.
+func (a *App) GetChannelNotifyDefaults(channelId string) (*model.ChannelNotifyDefaults, *model.AppError) {
+	result := <-a.Srv.Store.ChannelNotifyDefaults().Get(channelId)
+	if result.Err != nil {
+		if result.Err.StatusCode != http.StatusNotFound {
+			return nil, result.Err
+		}
+
+		return &model.ChannelNotifyDefaults{ChannelId: channelId, NotifyProps: model.StringMap{}}, nil
+	}
+
+	return result.Data.(*model.ChannelNotifyDefaults), nil
+}
+
+func (a *App) UpdateChannelNotifyDefaults(channel *model.Channel, notifyProps model.StringMap, userId string) (*model.ChannelNotifyDefaults, *model.AppError) {
+	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
+		return nil, model.NewAppError("UpdateChannelNotifyDefaults", "app.channel_notify_defaults.type.app_error", nil, "channel_id="+channel.Id, http.StatusBadRequest)
+	}
+
+	defaults := &model.ChannelNotifyDefaults{
+		ChannelId:   channel.Id,
+		NotifyProps: notifyProps,
+		UpdatedBy:   userId,
+	}
+
+	result := <-a.Srv.Store.ChannelNotifyDefaults().Save(defaults)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.(*model.ChannelNotifyDefaults), nil
+}
+
+func (a *App) DeleteChannelNotifyDefaults(channelId string) *model.AppError {
+	if result := <-a.Srv.Store.ChannelNotifyDefaults().Delete(channelId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// getNewMemberChannelNotifyProps returns the notify props that a user joining the channel should start with. Failing
+// to load the channel's defaults shouldn't stop the user from joining, so the global defaults are used instead.
+func (a *App) getNewMemberChannelNotifyProps(channelId string) model.StringMap {
+	defaults, err := a.GetChannelNotifyDefaults(channelId)
+	if err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to get the notification defaults for channel_id=%v, err=%v", channelId, err))
+		return model.GetDefaultChannelNotifyProps()
+	}
+
+	return defaults.ApplyTo(model.GetDefaultChannelNotifyProps())
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestChannelNotifyDefaultsAppliedOnJoin(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	channel := th.CreateChannel(th.BasicTeam)
+
+	defaults, err := th.App.GetChannelNotifyDefaults(channel.Id)
+	require.Nil(t, err)
+	assert.Empty(t, defaults.NotifyProps)
+
+	_, err = th.App.UpdateChannelNotifyDefaults(channel, model.StringMap{
+		model.DESKTOP_NOTIFY_PROP:     model.CHANNEL_NOTIFY_MENTION,
+		model.MARK_UNREAD_NOTIFY_PROP: model.CHANNEL_MARK_UNREAD_MENTION,
+	}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	member := th.AddUserToChannel(th.BasicUser2, channel)
+	assert.Equal(t, model.CHANNEL_NOTIFY_MENTION, member.NotifyProps[model.DESKTOP_NOTIFY_PROP])
+	assert.Equal(t, model.CHANNEL_MARK_UNREAD_MENTION, member.NotifyProps[model.MARK_UNREAD_NOTIFY_PROP])
+	assert.Equal(t, model.CHANNEL_NOTIFY_DEFAULT, member.NotifyProps[model.PUSH_NOTIFY_PROP])
+
+	member, err = th.App.UpdateChannelMemberNotifyProps(map[string]string{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_ALL}, channel.Id, th.BasicUser2.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.CHANNEL_NOTIFY_ALL, member.NotifyProps[model.DESKTOP_NOTIFY_PROP], "should let the member override the default")
+
+	_, err = th.App.UpdateChannelNotifyDefaults(channel, model.StringMap{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_NONE}, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	member, err = th.App.GetChannelMember(channel.Id, th.BasicUser2.Id)
+	require.Nil(t, err)
+	assert.Equal(t, model.CHANNEL_NOTIFY_ALL, member.NotifyProps[model.DESKTOP_NOTIFY_PROP], "shouldn't change existing members")
+
+	require.Nil(t, th.App.DeleteChannelNotifyDefaults(channel.Id))
+
+	defaults, err = th.App.GetChannelNotifyDefaults(channel.Id)
+	require.Nil(t, err)
+	assert.Empty(t, defaults.NotifyProps)
+}
+
+func TestUpdateChannelNotifyDefaultsDirectChannel(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	channel := th.CreateDmChannel(th.BasicUser2)
+
+	_, err := th.App.UpdateChannelNotifyDefaults(channel, model.StringMap{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_MENTION}, th.BasicUser.Id)
+	require.NotNil(t, err)
+}
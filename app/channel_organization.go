@@ -0,0 +1,93 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (a *App) checkChannelOrganizationEnabled() *model.AppError {
+	if !*a.Config().ServiceSettings.EnableChannelOrganization {
+		return model.NewAppError("checkChannelOrganizationEnabled", "app.channel_organization.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+// GetChannelOrganization returns how a user has organized their sidebar. Settings saved by older clients are moved
+// over to the typed preference the first time that they're read.
+func (a *App) GetChannelOrganization(userId string) (*model.ChannelOrganization, *model.AppError) {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return nil, err
+	}
+
+	preferences, err := a.GetPreferenceByCategoryForUser(userId, model.PREFERENCE_CATEGORY_SIDEBAR_SETTINGS)
+	if err != nil && err.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	var legacy *model.Preference
+	for i, preference := range preferences {
+		if preference.Name == model.PREFERENCE_NAME_CHANNEL_ORGANIZATION {
+			if organization := model.ChannelOrganizationFromJson(strings.NewReader(preference.Value)); organization != nil {
+				return organization, nil
+			}
+		} else if preference.Name == model.PREFERENCE_NAME_LEGACY_SIDEBAR_SETTINGS {
+			legacy = &preferences[i]
+		}
+	}
+
+	if legacy == nil {
+		return model.DefaultChannelOrganization(), nil
+	}
+
+	organization := model.ChannelOrganizationFromLegacyPreference(legacy.Value)
+	if err := a.saveChannelOrganization(userId, organization); err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to move the sidebar settings of user_id=%v to their channel organization, err=%v", userId, err))
+	}
+
+	return organization, nil
+}
+
+func (a *App) UpdateChannelOrganization(userId string, organization *model.ChannelOrganization) (*model.ChannelOrganization, *model.AppError) {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return nil, err
+	}
+
+	if err := organization.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := a.saveChannelOrganization(userId, organization); err != nil {
+		return nil, err
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_ORGANIZATION_CHANGED, "", "", userId, nil)
+	message.Add("channel_organization", organization.ToJson())
+	a.Publish(message)
+
+	return organization, nil
+}
+
+func (a *App) saveChannelOrganization(userId string, organization *model.ChannelOrganization) *model.AppError {
+	preferences := model.Preferences{
+		{
+			UserId:   userId,
+			Category: model.PREFERENCE_CATEGORY_SIDEBAR_SETTINGS,
+			Name:     model.PREFERENCE_NAME_CHANNEL_ORGANIZATION,
+			Value:    organization.ToJson(),
+		},
+	}
+
+	if result := <-a.Srv.Store.Preference().Save(&preferences); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
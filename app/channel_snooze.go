@@ -0,0 +1,58 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const CHANNEL_SNOOZE_BATCH_SIZE = 1000
+
+// ClearExpiredChannelSnoozes removes the snooze from every channel member whose snooze has run out, so that clients
+// show the channel as unmuted again.
+func (a *App) ClearExpiredChannelSnoozes() *model.AppError {
+	now := model.GetMillis()
+	expired := []model.ChannelMember{}
+
+	// Gather all of the expired members before updating any of them so that the pages don't shift underneath us
+	for offset := 0; ; offset += CHANNEL_SNOOZE_BATCH_SIZE {
+		result := <-a.Srv.Store.Channel().GetSnoozedMembers(offset, CHANNEL_SNOOZE_BATCH_SIZE)
+		if result.Err != nil {
+			return result.Err
+		}
+		members := *result.Data.(*model.ChannelMembers)
+
+		for _, member := range members {
+			if !model.IsChannelSnoozedAt(member.NotifyProps, now) {
+				expired = append(expired, member)
+			}
+		}
+
+		if len(members) < CHANNEL_SNOOZE_BATCH_SIZE {
+			break
+		}
+	}
+
+	for i := range expired {
+		member := &expired[i]
+		delete(member.NotifyProps, model.MUTED_UNTIL_NOTIFY_PROP)
+
+		if result := <-a.Srv.Store.Channel().UpdateMember(member); result.Err != nil {
+			mlog.Error(fmt.Sprintf("Failed to clear expired channel snooze err=%v", result.Err), mlog.String("channel_id", member.ChannelId), mlog.String("user_id", member.UserId))
+			continue
+		}
+
+		a.InvalidateCacheForUser(member.UserId)
+		a.InvalidateCacheForChannelMembersNotifyProps(member.ChannelId)
+
+		evt := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_MEMBER_UPDATED, "", "", member.UserId, nil)
+		evt.Add("channelMember", member.ToJson())
+		a.Publish(evt)
+	}
+
+	return nil
+}
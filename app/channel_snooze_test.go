@@ -0,0 +1,46 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestClearExpiredChannelSnoozes(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	channel2 := th.CreateChannel(th.BasicTeam)
+
+	expired := strconv.FormatInt(model.GetMillis()-1000, 10)
+	_, err := th.App.UpdateChannelMemberNotifyProps(map[string]string{model.MUTED_UNTIL_NOTIFY_PROP: expired}, th.BasicChannel.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	later := strconv.FormatInt(model.GetMillis()+2*60*60*1000, 10)
+	_, err = th.App.UpdateChannelMemberNotifyProps(map[string]string{model.MUTED_UNTIL_NOTIFY_PROP: later}, channel2.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	require.Nil(t, th.App.ClearExpiredChannelSnoozes())
+
+	member, err := th.App.GetChannelMember(th.BasicChannel.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+	_, ok := member.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP]
+	assert.False(t, ok, "should have cleared the expired snooze")
+
+	member, err = th.App.GetChannelMember(channel2.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, later, member.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP])
+
+	// Snoozing until an empty time stops snoozing
+	member, err = th.App.UpdateChannelMemberNotifyProps(map[string]string{model.MUTED_UNTIL_NOTIFY_PROP: ""}, channel2.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+	_, ok = member.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP]
+	assert.False(t, ok)
+}
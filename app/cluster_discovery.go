@@ -79,7 +79,10 @@ func (me *ClusterDiscoveryService) Stop() {
 }
 
 func (a *App) IsLeader() bool {
-	if a.License() != nil && *a.Config().ClusterSettings.Enable && a.Cluster != nil {
+	// Clustering through Redis doesn't need a license
+	clusterLicensed := a.License() != nil || *a.Config().ClusterSettings.RedisAddress != ""
+
+	if clusterLicensed && *a.Config().ClusterSettings.Enable && a.Cluster != nil {
 		return a.Cluster.IsLeader()
 	} else {
 		return true
@@ -21,6 +21,9 @@ func (a *App) RegisterAllClusterMessageHandlers() {
 	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_INVALIDATE_CACHE_FOR_CHANNEL, a.ClusterInvalidateCacheForChannelHandler)
 	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_INVALIDATE_CACHE_FOR_USER, a.ClusterInvalidateCacheForUserHandler)
 	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER, a.ClusterClearSessionCacheForUserHandler)
+	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_WEBSOCKET_CONNECTIONS, a.ClusterWebSocketConnectionsHandler)
+	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_WEBSOCKET_HAND_OVER_REQUEST, a.ClusterWebSocketHandOverRequestHandler)
+	a.Cluster.RegisterClusterMessageHandler(model.CLUSTER_EVENT_WEBSOCKET_HAND_OVER, a.ClusterWebSocketHandOverHandler)
 }
 
 func (a *App) ClusterPublishHandler(msg *model.ClusterMessage) {
@@ -62,9 +65,20 @@ func (a *App) ClusterInvalidateCacheForChannelHandler(msg *model.ClusterMessage)
 }
 
 func (a *App) ClusterInvalidateCacheForUserHandler(msg *model.ClusterMessage) {
+	// The server that sent the message has already invalidated the caches that are shared through Redis
+	if a.usesRedisCaches() {
+		a.invalidateWebConnCacheForUser(msg.Data)
+		return
+	}
+
 	a.InvalidateCacheForUserSkipClusterSend(msg.Data)
 }
 
 func (a *App) ClusterClearSessionCacheForUserHandler(msg *model.ClusterMessage) {
+	if a.usesRedisCaches() {
+		a.InvalidateWebConnSessionCacheForUser(msg.Data)
+		return
+	}
+
 	a.ClearSessionCacheForUserSkipClusterSend(msg.Data)
 }
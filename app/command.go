@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -52,9 +53,15 @@ func (a *App) CreateCommandPost(post *model.Post, teamId string, response *model
 
 	if response.ResponseType == model.COMMAND_RESPONSE_TYPE_IN_CHANNEL {
 		return a.CreatePostMissingChannel(post, true)
-	} else if (response.ResponseType == "" || response.ResponseType == model.COMMAND_RESPONSE_TYPE_EPHEMERAL) && (response.Text != "" || response.Attachments != nil) {
+	} else if response.IsEphemeral() && (response.Text != "" || response.Attachments != nil) {
 		post.ParentId = ""
-		a.SendEphemeralPost(post.UserId, post)
+
+		// Posts that already have an id replace an ephemeral response that was sent earlier
+		if post.Id != "" {
+			a.UpdateEphemeralPost(post.UserId, post)
+		} else {
+			a.SendEphemeralPost(post.UserId, post)
+		}
 	}
 
 	return post, nil
@@ -62,13 +69,19 @@ func (a *App) CreateCommandPost(post *model.Post, teamId string, response *model
 
 // previous ListCommands now ListAutocompleteCommands
 func (a *App) ListAutocompleteCommands(teamId string, T goi18n.TranslateFunc) ([]*model.Command, *model.AppError) {
+	return a.listAutocompleteCommands(teamId, T, true)
+}
+
+func (a *App) listAutocompleteCommands(teamId string, T goi18n.TranslateFunc, sanitize bool) ([]*model.Command, *model.AppError) {
 	commands := make([]*model.Command, 0, 32)
 	seen := make(map[string]bool)
 	for _, value := range commandProviders {
 		if cmd := value.GetCommand(a, T); cmd != nil {
 			cpy := *cmd
 			if cpy.AutoComplete && !seen[cpy.Id] {
-				cpy.Sanitize()
+				if sanitize {
+					cpy.Sanitize()
+				}
 				seen[cpy.Trigger] = true
 				commands = append(commands, &cpy)
 			}
@@ -78,6 +91,11 @@ func (a *App) ListAutocompleteCommands(teamId string, T goi18n.TranslateFunc) ([
 	for _, cmd := range a.PluginCommandsForTeam(teamId) {
 		if cmd.AutoComplete && !seen[cmd.Trigger] {
 			seen[cmd.Trigger] = true
+			if sanitize && cmd.AutocompleteData != nil {
+				cpy := *cmd
+				cpy.AutocompleteData = cpy.AutocompleteData.Sanitized()
+				cmd = &cpy
+			}
 			commands = append(commands, cmd)
 		}
 	}
@@ -89,7 +107,9 @@ func (a *App) ListAutocompleteCommands(teamId string, T goi18n.TranslateFunc) ([
 			teamCmds := result.Data.([]*model.Command)
 			for _, cmd := range teamCmds {
 				if cmd.AutoComplete && !seen[cmd.Id] {
-					cmd.Sanitize()
+					if sanitize {
+						cmd.Sanitize()
+					}
 					seen[cmd.Trigger] = true
 					commands = append(commands, cmd)
 				}
@@ -224,11 +244,11 @@ func (a *App) ExecuteCommand(args *model.CommandArgs) (*model.CommandResponse, *
 				p.Set("command", "/"+trigger)
 				p.Set("text", message)
 
-				if hook, err := a.CreateCommandWebhook(cmd.Id, args); err != nil {
-					return nil, model.NewAppError("command", "api.command.execute_command.failed.app_error", map[string]interface{}{"Trigger": trigger}, err.Error(), http.StatusInternalServerError)
-				} else {
-					p.Set("response_url", args.SiteURL+"/hooks/commands/"+hook.Id)
+				hook, appErr := a.CreateCommandWebhook(cmd.Id, args)
+				if appErr != nil {
+					return nil, model.NewAppError("command", "api.command.execute_command.failed.app_error", map[string]interface{}{"Trigger": trigger}, appErr.Error(), http.StatusInternalServerError)
 				}
+				p.Set("response_url", args.SiteURL+"/hooks/commands/"+hook.Id)
 
 				var req *http.Request
 				if cmd.Method == model.COMMAND_METHOD_GET {
@@ -244,20 +264,39 @@ func (a *App) ExecuteCommand(args *model.CommandArgs) (*model.CommandResponse, *
 					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 				}
 
-				if resp, err := a.HTTPClient(false).Do(req); err != nil {
+				start := time.Now()
+				recordDelivery := func(statusCode int, err string) {
+					a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_COMMAND, cmd.Id, cmd.URL, "application/x-www-form-urlencoded", []byte(p.Encode()), statusCode, time.Since(start), err)
+				}
+
+				// Commands whose URL keeps failing are turned down straight away instead of making the user wait
+				if !a.allowIntegrationRequest(cmd.URL) {
+					recordDelivery(0, errIntegrationCircuitOpen.Error())
+					return nil, model.NewAppError("command", "api.command.execute_command.unavailable.app_error", map[string]interface{}{"Trigger": trigger}, "", http.StatusServiceUnavailable)
+				}
+
+				resp, err := a.HTTPClient(false).Do(req)
+				a.recordIntegrationResponse(cmd.URL, resp, err)
+
+				if err != nil {
+					recordDelivery(0, err.Error())
 					return nil, model.NewAppError("command", "api.command.execute_command.failed.app_error", map[string]interface{}{"Trigger": trigger}, err.Error(), http.StatusInternalServerError)
 				} else {
 					if resp.StatusCode == http.StatusOK {
 						if response, err := model.CommandResponseFromHTTPBody(resp.Header.Get("Content-Type"), resp.Body); err != nil {
+							recordDelivery(resp.StatusCode, err.Error())
 							return nil, model.NewAppError("command", "api.command.execute_command.failed.app_error", map[string]interface{}{"Trigger": trigger}, err.Error(), http.StatusInternalServerError)
 						} else if response == nil {
+							recordDelivery(resp.StatusCode, "empty response")
 							return nil, model.NewAppError("command", "api.command.execute_command.failed_empty.app_error", map[string]interface{}{"Trigger": trigger}, "", http.StatusInternalServerError)
 						} else {
-							return a.HandleCommandResponse(cmd, args, response, false)
+							recordDelivery(resp.StatusCode, "")
+							return a.handleCommandResponse(cmd, args, response, false, hook)
 						}
 					} else {
 						defer resp.Body.Close()
 						body, _ := ioutil.ReadAll(resp.Body)
+						recordDelivery(resp.StatusCode, string(body))
 						return nil, model.NewAppError("command", "api.command.execute_command.failed_resp.app_error", map[string]interface{}{"Trigger": trigger, "Status": resp.Status}, string(body), http.StatusInternalServerError)
 					}
 				}
@@ -269,6 +308,13 @@ func (a *App) ExecuteCommand(args *model.CommandArgs) (*model.CommandResponse, *
 }
 
 func (a *App) HandleCommandResponse(command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.CommandResponse, *model.AppError) {
+	return a.handleCommandResponse(command, args, response, builtIn, nil)
+}
+
+// handleCommandResponse posts a response to a command. Ephemeral responses to custom commands are remembered by the
+// command's webhook so that later responses sent to it can replace or append to them, and dialogs in them are opened for
+// the user that ran the command.
+func (a *App) handleCommandResponse(command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool, hook *model.CommandWebhook) (*model.CommandResponse, *model.AppError) {
 	post := &model.Post{}
 	post.ChannelId = args.ChannelId
 	post.RootId = args.RootId
@@ -309,8 +355,26 @@ func (a *App) HandleCommandResponse(command *model.Command, args *model.CommandA
 	response.Text = a.ProcessSlackText(response.Text)
 	response.Attachments = a.ProcessSlackAttachments(response.Attachments)
 
-	if _, err := a.CreateCommandPost(post, args.TeamId, response); err != nil {
+	if hook != nil && hook.EphemeralPostId != "" && response.UpdatesOriginal() {
+		post.Id = hook.EphemeralPostId
+		if response.AppendOriginal && hook.EphemeralMessage != "" {
+			response.Text = hook.EphemeralMessage + "\n" + response.Text
+		}
+	}
+
+	if response.Dialog != nil && !builtIn {
+		if err := a.OpenInteractiveDialog(args.UserId, command.URL, response.Dialog); err != nil {
+			return nil, err
+		}
+	}
+
+	post, err := a.CreateCommandPost(post, args.TeamId, response)
+	if err != nil {
 		mlog.Error(err.Error())
+	} else if hook != nil && post.Type == model.POST_EPHEMERAL {
+		if result := <-a.Srv.Store.CommandWebhook().UpdateEphemeralPost(hook.Id, post.Id, post.Message); result.Err != nil {
+			mlog.Error("Unable to remember the ephemeral response to a command", mlog.String("command_webhook_id", hook.Id), mlog.Err(result.Err))
+		}
 	}
 
 	return response, nil
@@ -381,6 +445,14 @@ func (a *App) UpdateCommand(oldCmd, updatedCmd *model.Command) (*model.Command,
 	}
 }
 
+// PatchCommand changes the parts of a command that describe it to users.
+func (a *App) PatchCommand(oldCmd *model.Command, patch *model.CommandPatch) (*model.Command, *model.AppError) {
+	cmd := *oldCmd
+	cmd.Patch(patch)
+
+	return a.UpdateCommand(oldCmd, &cmd)
+}
+
 func (a *App) MoveCommand(team *model.Team, command *model.Command) *model.AppError {
 	command.TeamId = team.Id
 
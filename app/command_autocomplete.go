@@ -0,0 +1,149 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+// The most that's read from a command's server when fetching the items of a dynamic list
+const COMMAND_AUTOCOMPLETE_FETCH_MAX_SIZE = 1024 * 1024
+
+// GetCommandAutocompleteSuggestions returns the ways that a user could continue typing a slash command, based on the
+// autocomplete data that the command was registered with. Commands without autocomplete data have no suggestions past
+// their trigger.
+func (a *App) GetCommandAutocompleteSuggestions(userInput string, teamId string, channelId string, userId string, T goi18n.TranslateFunc) ([]*model.AutocompleteSuggestion, *model.AppError) {
+	if !strings.HasPrefix(userInput, "/") {
+		return nil, model.NewAppError("GetCommandAutocompleteSuggestions", "app.command.autocomplete_suggestions.invalid_input.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	commands, err := a.listAutocompleteCommands(teamId, T, false)
+	if err != nil {
+		return nil, err
+	}
+
+	spaceIndex := strings.Index(userInput, " ")
+	if spaceIndex == -1 {
+		trigger := strings.ToLower(userInput[1:])
+
+		suggestions := []*model.AutocompleteSuggestion{}
+		for _, cmd := range commands {
+			if strings.HasPrefix(cmd.Trigger, trigger) {
+				suggestions = append(suggestions, &model.AutocompleteSuggestion{
+					Complete:    "/" + cmd.Trigger,
+					Suggestion:  "/" + cmd.Trigger,
+					Hint:        cmd.AutoCompleteHint,
+					Description: cmd.AutoCompleteDesc,
+				})
+			}
+		}
+
+		return suggestions, nil
+	}
+
+	trigger := strings.ToLower(userInput[1:spaceIndex])
+	for _, cmd := range commands {
+		if cmd.Trigger != trigger || cmd.AutocompleteData == nil {
+			continue
+		}
+
+		fetch := func(arg *model.AutocompleteArg) []*model.AutocompleteListItem {
+			items, err := a.fetchCommandAutocompleteListItems(cmd, arg, userInput, teamId, channelId, userId)
+			if err != nil {
+				mlog.Warn(fmt.Sprintf("Unable to fetch autocomplete suggestions for command trigger=%v err=%v", trigger, err.Error()))
+			}
+			return items
+		}
+
+		return cmd.AutocompleteData.GetSuggestions(userInput[:spaceIndex+1], userInput[spaceIndex+1:], fetch), nil
+	}
+
+	return []*model.AutocompleteSuggestion{}, nil
+}
+
+// fetchCommandAutocompleteListItems requests the items of a dynamic list from the command's server, sending it the
+// same token that's sent when the command is run so that it can tell that the request came from this server. The items
+// for commands registered by plugins are requested from the plugin's ServeHTTP hook instead.
+func (a *App) fetchCommandAutocompleteListItems(cmd *model.Command, arg *model.AutocompleteArg, userInput string, teamId string, channelId string, userId string) ([]*model.AutocompleteListItem, error) {
+	query := url.Values{}
+	query.Set("user_input", userInput)
+	query.Set("team_id", teamId)
+	query.Set("channel_id", channelId)
+	query.Set("user_id", userId)
+
+	req, err := http.NewRequest(http.MethodGet, arg.FetchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL.RawQuery != "" {
+		req.URL.RawQuery += "&"
+	}
+	req.URL.RawQuery += query.Encode()
+
+	req.Header.Set("Accept", "application/json")
+
+	if cmd.PluginId != "" {
+		return a.fetchPluginCommandAutocompleteListItems(cmd.PluginId, req, userId)
+	}
+
+	if cmd.Token != "" {
+		req.Header.Set("Authorization", "Token "+cmd.Token)
+	}
+
+	resp, err := a.HTTPClient(false).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+
+	return readCommandAutocompleteListItems(resp.Body), nil
+}
+
+// fetchPluginCommandAutocompleteListItems passes the request for the items of a dynamic list to a plugin's ServeHTTP
+// hook as if it had been made by the user who's typing the command.
+func (a *App) fetchPluginCommandAutocompleteListItems(pluginId string, req *http.Request, userId string) ([]*model.AutocompleteListItem, error) {
+	if a.Plugins == nil {
+		return nil, fmt.Errorf("plugins are disabled")
+	}
+
+	req.Header.Set("Mattermost-User-Id", userId)
+
+	recorder := httptest.NewRecorder()
+	if err := a.Plugins.RunPluginHook(pluginId, func(hooks plugin.Hooks) {
+		hooks.ServeHTTP(&plugin.Context{}, recorder, req)
+	}); err != nil {
+		return nil, err
+	}
+
+	if recorder.Code != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %v", recorder.Code)
+	}
+
+	return readCommandAutocompleteListItems(recorder.Body), nil
+}
+
+func readCommandAutocompleteListItems(body io.Reader) []*model.AutocompleteListItem {
+	items := model.AutocompleteListItemsFromJson(io.LimitReader(body, COMMAND_AUTOCOMPLETE_FETCH_MAX_SIZE))
+	if len(items) > model.AUTOCOMPLETE_MAX_LIST_ITEMS {
+		items = items[:model.AUTOCOMPLETE_MAX_LIST_ITEMS]
+	}
+
+	return items
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+type RemindProvider struct {
+}
+
+const (
+	CMD_REMIND        = "remind"
+	CMD_REMIND_LIST   = "list"
+	CMD_REMIND_CANCEL = "cancel"
+
+	REMINDER_TIME_FORMAT = "Mon Jan 2 3:04 PM MST"
+)
+
+func init() {
+	RegisterCommandProvider(&RemindProvider{})
+}
+
+func (me *RemindProvider) GetTrigger() string {
+	return CMD_REMIND
+}
+
+func (me *RemindProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_REMIND,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_remind.desc"),
+		AutoCompleteHint: T("api.command_remind.hint"),
+		DisplayName:      T("api.command_remind.name"),
+	}
+}
+
+func (me *RemindProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
+	user, err := a.GetUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	location := getReminderLocation(user)
+
+	fields := strings.Fields(message)
+	if len(fields) > 0 {
+		switch strings.ToLower(fields[0]) {
+		case CMD_REMIND_LIST:
+			return me.listReminders(a, args, location)
+		case CMD_REMIND_CANCEL:
+			if len(fields) != 2 {
+				return &model.CommandResponse{Text: args.T("api.command_remind.usage"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+			}
+
+			return me.cancelReminder(a, args, fields[1])
+		}
+	}
+
+	request, ok := model.ParseReminderRequest(message, time.Now().In(location))
+	if !ok {
+		return &model.CommandResponse{Text: args.T("api.command_remind.usage"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	var channel *model.Channel
+	switch request.Target {
+	case model.REMINDER_TARGET_ME:
+		channel, err = a.GetReminderChannelForUser(args.UserId)
+	case model.REMINDER_TARGET_HERE:
+		channel, err = a.GetChannel(args.ChannelId)
+	default:
+		channel, err = a.GetChannelByName(request.Target, args.TeamId, false)
+	}
+
+	if err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.channel.error", map[string]interface{}{"Channel": request.Target}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	reminder, err := a.CreateReminder(&model.Reminder{
+		UserId:    args.UserId,
+		ChannelId: channel.Id,
+		Message:   request.Message,
+		RemindAt:  request.RemindAt.UnixNano() / int64(time.Millisecond),
+	})
+	if err != nil {
+		err.Translate(args.T)
+		return &model.CommandResponse{Text: err.Message, ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{Text: args.T("api.command_remind.success", map[string]interface{}{
+		"Time": formatReminderTime(reminder.RemindAt, location),
+		"Id":   reminder.Id,
+	}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
+
+func (me *RemindProvider) listReminders(a *App, args *model.CommandArgs, location *time.Location) *model.CommandResponse {
+	reminders, err := a.GetRemindersForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	if len(reminders) == 0 {
+		return &model.CommandResponse{Text: args.T("api.command_remind.list.empty"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(args.T("api.command_remind.list.title"))
+	for _, reminder := range reminders {
+		buf.WriteString("\n")
+		buf.WriteString(args.T("api.command_remind.list.item", map[string]interface{}{
+			"Time":    formatReminderTime(reminder.RemindAt, location),
+			"Message": reminder.Message,
+			"Id":      reminder.Id,
+		}))
+	}
+
+	return &model.CommandResponse{Text: buf.String(), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
+
+func (me *RemindProvider) cancelReminder(a *App, args *model.CommandArgs, reminderId string) *model.CommandResponse {
+	reminder, err := a.GetReminder(reminderId)
+	if err != nil || reminder.UserId != args.UserId {
+		return &model.CommandResponse{Text: args.T("api.command_remind.cancel.not_found"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	if err := a.CancelReminder(reminder.Id); err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{Text: args.T("api.command_remind.cancel.success", map[string]interface{}{"Message": reminder.Message}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
+
+// getReminderLocation returns the time zone that a user's reminders are read and displayed in, falling back to the
+// server's time zone if they haven't set one.
+func getReminderLocation(user *model.User) *time.Location {
+	if timezone := user.GetPreferredTimezone(); timezone != "" {
+		if location, err := time.LoadLocation(timezone); err == nil {
+			return location
+		}
+	}
+
+	return time.Local
+}
+
+func formatReminderTime(millis int64, location *time.Location) string {
+	return time.Unix(0, millis*int64(time.Millisecond)).In(location).Format(REMINDER_TIME_FORMAT)
+}
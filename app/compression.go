@@ -0,0 +1,52 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/NYTimes/gziphandler"
+	"github.com/gorilla/websocket"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// CompressionHandler gzips the responses of a handler for clients that accept it. Following the service settings,
+// responses smaller than CompressionMinimumSize are sent as they are, as are those with a Content-Type that isn't
+// one of CompressionContentTypes unless that's empty.
+func (a *App) CompressionHandler(h http.Handler) http.Handler {
+	settings := a.Config().ServiceSettings
+
+	wrapper, err := gziphandler.GzipHandlerWithOpts(
+		gziphandler.MinSize(*settings.CompressionMinimumSize),
+		gziphandler.ContentTypes(strings.Fields(*settings.CompressionContentTypes)),
+	)
+	if err != nil {
+		mlog.Error("Unable to set up response compression", mlog.Err(err))
+		return h
+	}
+
+	return wrapper(h)
+}
+
+// apiCompressionHandler compresses the responses to API requests, leaving everything else to the handlers for it.
+// Websocket connections are never compressed since they're hijacked from the HTTP server.
+func (a *App) apiCompressionHandler(h http.Handler) http.Handler {
+	subpath, _ := utils.GetSubpathFromConfig(a.Config())
+	apiPrefix := path.Join(subpath, "api") + "/"
+
+	compressed := a.CompressionHandler(h)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, apiPrefix) && !websocket.IsWebSocketUpgrade(r) {
+			compressed.ServeHTTP(w, r)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestCompressionHandler(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	serve := func(contentType string, size int) *httptest.ResponseRecorder {
+		handler := th.App.CompressionHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Write([]byte(strings.Repeat("a", size)))
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v4/system/ping", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.CompressionMinimumSize = 1000
+		*cfg.ServiceSettings.CompressionContentTypes = ""
+	})
+
+	assert.Equal(t, "gzip", serve("application/json", 2000).Header().Get("Content-Encoding"))
+	assert.Equal(t, "", serve("application/json", 500).Header().Get("Content-Encoding"), "should skip small responses")
+	assert.Equal(t, "gzip", serve("text/csv", 2000).Header().Get("Content-Encoding"))
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.CompressionContentTypes = "application/json text/html"
+	})
+
+	assert.Equal(t, "gzip", serve("application/json", 2000).Header().Get("Content-Encoding"))
+	assert.Equal(t, "", serve("text/csv", 2000).Header().Get("Content-Encoding"), "should skip other content types")
+}
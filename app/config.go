@@ -142,12 +142,42 @@ func (a *App) ensureAsymmetricSigningKey() error {
 		return nil
 	}
 
+	key, err := a.ensureSystemECDSAKey(model.SYSTEM_ASYMMETRIC_SIGNING_KEY)
+	if err != nil {
+		return err
+	}
+
+	a.asymmetricSigningKey = key
+	a.regenerateClientConfig()
+	return nil
+}
+
+// ensureWebPushVapidKey ensures that the key used to identify this server to web push services exists. It's kept
+// separate from the asymmetric signing key since browsers bind each subscription to the key it was created with.
+func (a *App) ensureWebPushVapidKey() error {
+	if a.webPushVapidKey != nil {
+		return nil
+	}
+
+	key, err := a.ensureSystemECDSAKey(model.SYSTEM_WEB_PUSH_VAPID_KEY)
+	if err != nil {
+		return err
+	}
+
+	a.webPushVapidKey = key
+	a.regenerateClientConfig()
+	return nil
+}
+
+// ensureSystemECDSAKey returns the ECDSA key stored in the Systems table under the given name, generating and
+// storing a new one if it doesn't exist yet.
+func (a *App) ensureSystemECDSAKey(name string) (*ecdsa.PrivateKey, error) {
 	var key *model.SystemAsymmetricSigningKey
 
-	result := <-a.Srv.Store.System().GetByName(model.SYSTEM_ASYMMETRIC_SIGNING_KEY)
+	result := <-a.Srv.Store.System().GetByName(name)
 	if result.Err == nil {
 		if err := json.Unmarshal([]byte(result.Data.(*model.System).Value), &key); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -155,7 +185,7 @@ func (a *App) ensureAsymmetricSigningKey() error {
 	if key == nil {
 		newECDSAKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		newKey := &model.SystemAsymmetricSigningKey{
 			ECDSAKey: &model.SystemECDSAKey{
@@ -166,11 +196,11 @@ func (a *App) ensureAsymmetricSigningKey() error {
 			},
 		}
 		system := &model.System{
-			Name: model.SYSTEM_ASYMMETRIC_SIGNING_KEY,
+			Name: name,
 		}
 		v, err := json.Marshal(newKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		system.Value = string(v)
 		if result = <-a.Srv.Store.System().Save(system); result.Err == nil {
@@ -182,11 +212,11 @@ func (a *App) ensureAsymmetricSigningKey() error {
 	// If we weren't able to save a new key above, another server must have beat us to it. Get the
 	// key from the database, and if that fails, error out.
 	if key == nil {
-		result := <-a.Srv.Store.System().GetByName(model.SYSTEM_ASYMMETRIC_SIGNING_KEY)
+		result := <-a.Srv.Store.System().GetByName(name)
 		if result.Err != nil {
-			return result.Err
+			return nil, result.Err
 		} else if err := json.Unmarshal([]byte(result.Data.(*model.System).Value), &key); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -195,18 +225,16 @@ func (a *App) ensureAsymmetricSigningKey() error {
 	case "P-256":
 		curve = elliptic.P256()
 	default:
-		return fmt.Errorf("unknown curve: " + key.ECDSAKey.Curve)
+		return nil, fmt.Errorf("unknown curve: " + key.ECDSAKey.Curve)
 	}
-	a.asymmetricSigningKey = &ecdsa.PrivateKey{
+	return &ecdsa.PrivateKey{
 		PublicKey: ecdsa.PublicKey{
 			Curve: curve,
 			X:     key.ECDSAKey.X,
 			Y:     key.ECDSAKey.Y,
 		},
 		D: key.ECDSAKey.D,
-	}
-	a.regenerateClientConfig()
-	return nil
+	}, nil
 }
 
 func (a *App) ensureInstallationDate() error {
@@ -238,6 +266,11 @@ func (a *App) AsymmetricSigningKey() *ecdsa.PrivateKey {
 	return a.asymmetricSigningKey
 }
 
+// WebPushVapidKey returns the private key used to sign requests to web push services.
+func (a *App) WebPushVapidKey() *ecdsa.PrivateKey {
+	return a.webPushVapidKey
+}
+
 func (a *App) regenerateClientConfig() {
 	a.clientConfig = utils.GenerateClientConfig(a.Config(), a.DiagnosticId(), a.License())
 	a.limitedClientConfig = utils.GenerateLimitedClientConfig(a.Config(), a.DiagnosticId(), a.License())
@@ -248,6 +281,10 @@ func (a *App) regenerateClientConfig() {
 		a.limitedClientConfig["AsymmetricSigningPublicKey"] = base64.StdEncoding.EncodeToString(der)
 	}
 
+	if key := a.WebPushVapidKey(); key != nil {
+		a.clientConfig["WebPushVapidPublicKey"] = utils.EncodeVapidPublicKey(&key.PublicKey)
+	}
+
 	clientConfigJSON, _ := json.Marshal(a.clientConfig)
 	a.clientConfigHash = fmt.Sprintf("%x", md5.Sum(clientConfigJSON))
 }
@@ -272,6 +309,17 @@ func (a *App) Desanitize(cfg *model.Config) {
 	if cfg.EmailSettings.SMTPPassword == model.FAKE_SETTING {
 		cfg.EmailSettings.SMTPPassword = actual.EmailSettings.SMTPPassword
 	}
+	for teamId, identity := range cfg.EmailSettings.TeamIdentities {
+		if identity.SMTPPassword != model.FAKE_SETTING {
+			continue
+		}
+
+		if actualIdentity, ok := actual.EmailSettings.TeamIdentities[teamId]; ok {
+			identity.SMTPPassword = actualIdentity.SMTPPassword
+		} else {
+			identity.SMTPPassword = ""
+		}
+	}
 
 	if cfg.GitLabSettings.Secret == model.FAKE_SETTING {
 		cfg.GitLabSettings.Secret = actual.GitLabSettings.Secret
@@ -288,6 +336,10 @@ func (a *App) Desanitize(cfg *model.Config) {
 		*cfg.ElasticsearchSettings.Password = *actual.ElasticsearchSettings.Password
 	}
 
+	if *cfg.ClusterSettings.RedisPassword == model.FAKE_SETTING {
+		*cfg.ClusterSettings.RedisPassword = *actual.ClusterSettings.RedisPassword
+	}
+
 	for i := range cfg.SqlSettings.DataSourceReplicas {
 		cfg.SqlSettings.DataSourceReplicas[i] = actual.SqlSettings.DataSourceReplicas[i]
 	}
@@ -295,6 +347,24 @@ func (a *App) Desanitize(cfg *model.Config) {
 	for i := range cfg.SqlSettings.DataSourceSearchReplicas {
 		cfg.SqlSettings.DataSourceSearchReplicas[i] = actual.SqlSettings.DataSourceSearchReplicas[i]
 	}
+
+	for _, provider := range cfg.IssueUnfurlSettings.Providers {
+		if provider.Token != model.FAKE_SETTING {
+			continue
+		}
+
+		provider.Token = ""
+		for _, actualProvider := range actual.IssueUnfurlSettings.Providers {
+			if actualProvider.Type == provider.Type && strings.EqualFold(actualProvider.Domain, provider.Domain) {
+				provider.Token = actualProvider.Token
+				break
+			}
+		}
+	}
+
+	if *cfg.SmsSettings.AuthToken == model.FAKE_SETTING {
+		*cfg.SmsSettings.AuthToken = *actual.SmsSettings.AuthToken
+	}
 }
 
 func (a *App) GetCookieDomain() string {
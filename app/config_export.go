@@ -0,0 +1,97 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// ExportConfig returns the sanitized config that the server is running with along with the settings that are set by
+// environment variables.
+func (a *App) ExportConfig() *model.ConfigExport {
+	return &model.ConfigExport{
+		Config:               a.GetConfig(),
+		EnvironmentOverrides: environmentOverrideSettings(a.GetEnvironmentConfig()),
+	}
+}
+
+// ImportConfig replaces the config with an imported one and returns the settings that changed. When dryRun is true,
+// the config is only validated and the changes that would be made are returned. Sanitized secrets in the imported
+// config are left unchanged, and the config is applied as a whole or not at all.
+func (a *App) ImportConfig(cfg *model.Config, dryRun bool) (*model.ConfigImportResult, *model.AppError) {
+	a.configImportLock.Lock()
+	defer a.configImportLock.Unlock()
+
+	// Plugin uploads can't be toggled through the API, and neither can the key that marketplace plugins are checked with
+	cfg.PluginSettings.EnableUploads = a.Config().PluginSettings.EnableUploads
+	cfg.PluginSettings.MarketplacePublicKey = a.Config().PluginSettings.MarketplacePublicKey
+
+	cfg.SetDefaults()
+	a.Desanitize(cfg)
+
+	if err := cfg.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if err := utils.ValidateLdapFilter(cfg, a.Ldap); err != nil {
+		return nil, err
+	}
+
+	if *a.Config().ClusterSettings.Enable && *a.Config().ClusterSettings.ReadOnlyConfig {
+		return nil, model.NewAppError("ImportConfig", "ent.cluster.save_config.error", nil, "", http.StatusForbidden)
+	}
+
+	overridden := map[string]bool{}
+	for _, setting := range environmentOverrideSettings(a.GetEnvironmentConfig()) {
+		overridden[setting] = true
+	}
+
+	result := &model.ConfigImportResult{
+		Changes: a.Config().Diff(cfg),
+		DryRun:  dryRun,
+	}
+
+	for _, change := range result.Changes {
+		change.EnvironmentOverride = overridden[change.Setting]
+		if change.RequiresRestart && !change.EnvironmentOverride {
+			result.RequiresRestart = true
+		}
+	}
+
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	if err := a.SaveConfig(cfg, true); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// environmentOverrideSettings returns the paths of the settings in a config from environment variables, such as
+// "ServiceSettings.SiteURL", in sorted order.
+func environmentOverrideSettings(envConfig map[string]interface{}) []string {
+	settings := []string{}
+
+	var addSettings func(prefix string, values map[string]interface{})
+	addSettings = func(prefix string, values map[string]interface{}) {
+		for name, value := range values {
+			if nested, ok := value.(map[string]interface{}); ok {
+				addSettings(prefix+name+".", nested)
+			} else {
+				settings = append(settings, prefix+name)
+			}
+		}
+	}
+	addSettings("", envConfig)
+
+	sort.Strings(settings)
+
+	return settings
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestEnvironmentOverrideSettings(t *testing.T) {
+	assert.Equal(t, []string{}, environmentOverrideSettings(map[string]interface{}{}))
+
+	assert.Equal(t, []string{
+		"MessageExportSettings.GlobalRelaySettings.SmtpUsername",
+		"ServiceSettings.ListenAddress",
+		"ServiceSettings.SiteURL",
+	}, environmentOverrideSettings(map[string]interface{}{
+		"ServiceSettings": map[string]interface{}{
+			"SiteURL":       "http://example.com",
+			"ListenAddress": ":8065",
+		},
+		"MessageExportSettings": map[string]interface{}{
+			"GlobalRelaySettings": map[string]interface{}{
+				"SmtpUsername": "user",
+			},
+		},
+	}))
+}
+
+func TestImportConfig(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	export := th.App.ExportConfig()
+	require.NotNil(t, export.Config)
+	assert.Equal(t, model.FAKE_SETTING, *export.Config.SqlSettings.DataSource, "should be sanitized")
+
+	result, err := th.App.ImportConfig(export.Config, false)
+	require.Nil(t, err)
+	assert.Empty(t, result.Changes, "shouldn't change anything when importing an exported config")
+
+	cfg := th.App.ExportConfig().Config
+	cfg.TeamSettings.SiteName = "Imported"
+	*cfg.ServiceSettings.ReadTimeout = *cfg.ServiceSettings.ReadTimeout + 1
+
+	result, err = th.App.ImportConfig(cfg, true)
+	require.Nil(t, err)
+	require.Len(t, result.Changes, 2)
+	assert.True(t, result.DryRun)
+	assert.True(t, result.RequiresRestart)
+	assert.NotEqual(t, "Imported", th.App.Config().TeamSettings.SiteName, "shouldn't apply a dry run")
+
+	cfg = th.App.ExportConfig().Config
+	cfg.TeamSettings.SiteName = "Imported"
+	*cfg.TeamSettings.MaxUsersPerTeam = -1
+
+	_, err = th.App.ImportConfig(cfg, false)
+	require.NotNil(t, err)
+	assert.NotEqual(t, "Imported", th.App.Config().TeamSettings.SiteName, "shouldn't apply part of an invalid config")
+
+	cfg = th.App.ExportConfig().Config
+	cfg.TeamSettings.SiteName = "Imported"
+
+	result, err = th.App.ImportConfig(cfg, false)
+	require.Nil(t, err)
+	require.Len(t, result.Changes, 1)
+	assert.False(t, result.RequiresRestart)
+	assert.Equal(t, "Imported", th.App.Config().TeamSettings.SiteName)
+	assert.NotEqual(t, model.FAKE_SETTING, *th.App.Config().SqlSettings.DataSource, "should keep sanitized secrets")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// SetCustomStatus sets the custom status that's shown next to a user's name. Clients are sent the updated user.
+func (a *App) SetCustomStatus(userId string, cs *model.CustomStatus) *model.AppError {
+	if cs == nil {
+		return model.NewAppError("SetCustomStatus", "model.custom_status.is_valid.empty.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := cs.IsValid(); err != nil {
+		return err
+	}
+
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return err
+	}
+
+	user.SetCustomStatus(cs)
+	if _, err := a.UpdateUser(user, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveCustomStatus removes a user's custom status, if they have one.
+func (a *App) RemoveCustomStatus(userId string) *model.AppError {
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := user.Props[model.USER_PROPS_KEY_CUSTOM_STATUS]; !ok {
+		return nil
+	}
+
+	user.ClearCustomStatus()
+	if _, err := a.UpdateUser(user, true); err != nil {
+		return err
+	}
+
+	return nil
+}
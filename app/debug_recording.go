@@ -0,0 +1,97 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// StartDebugRecording starts recording the requests that match the filter along with their responses, replacing any
+// recording that was already in progress. Only requests handled by this server are recorded.
+func (a *App) StartDebugRecording(filter *model.DebugRecordingFilter, userId string) (*model.DebugRecording, *model.AppError) {
+	if err := filter.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if filter.DurationSeconds == 0 {
+		filter.DurationSeconds = model.DEBUG_RECORDING_DEFAULT_DURATION_SECONDS
+	}
+
+	now := model.GetMillis()
+	recording := &model.DebugRecording{
+		DebugRecordingFilter: *filter,
+		StartedBy:            userId,
+		StartAt:              now,
+		ExpireAt:             now + int64(filter.DurationSeconds)*1000,
+		Requests:             []*model.DebugRecordedRequest{},
+	}
+
+	a.debugRecordingLock.Lock()
+	a.debugRecording = recording
+	a.debugRecordingLock.Unlock()
+
+	mlog.Info("Started debug recording", mlog.String("user_id", userId), mlog.String("path_prefixes", strings.Join(filter.PathPrefixes, ",")), mlog.String("integration_id", filter.IntegrationId))
+
+	return a.GetDebugRecording()
+}
+
+// StopDebugRecording stops and discards the current recording.
+func (a *App) StopDebugRecording() {
+	a.debugRecordingLock.Lock()
+	defer a.debugRecordingLock.Unlock()
+
+	a.debugRecording = nil
+}
+
+// GetDebugRecording returns a copy of the current recording, including one that has expired but hasn't been stopped
+// so that its requests can still be retrieved.
+func (a *App) GetDebugRecording() (*model.DebugRecording, *model.AppError) {
+	a.debugRecordingLock.Lock()
+	defer a.debugRecordingLock.Unlock()
+
+	if a.debugRecording == nil {
+		return nil, model.NewAppError("GetDebugRecording", "app.debug_recording.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	recording := *a.debugRecording
+	recording.Requests = make([]*model.DebugRecordedRequest, len(a.debugRecording.Requests))
+	copy(recording.Requests, a.debugRecording.Requests)
+
+	return &recording, nil
+}
+
+// ShouldRecordDebugRequest returns true if a request to the path made by the integration should be recorded.
+func (a *App) ShouldRecordDebugRequest(path string, integration *model.IntegrationSource) bool {
+	// Recording requests for the recording itself would include earlier requests in later ones
+	if strings.HasPrefix(path, model.API_URL_SUFFIX+"/debug/") {
+		return false
+	}
+
+	a.debugRecordingLock.Lock()
+	defer a.debugRecordingLock.Unlock()
+
+	return a.debugRecording != nil && model.GetMillis() < a.debugRecording.ExpireAt && a.debugRecording.Matches(path, integration)
+}
+
+// RecordDebugRequest adds a request to the current recording. Once a recording is full, further requests are only
+// counted so that memory use stays bounded.
+func (a *App) RecordDebugRequest(request *model.DebugRecordedRequest) {
+	a.debugRecordingLock.Lock()
+	defer a.debugRecordingLock.Unlock()
+
+	if a.debugRecording == nil || request.Timestamp >= a.debugRecording.ExpireAt {
+		return
+	}
+
+	if len(a.debugRecording.Requests) >= model.DEBUG_RECORDING_MAX_REQUESTS {
+		a.debugRecording.DroppedRequests++
+		return
+	}
+
+	a.debugRecording.Requests = append(a.debugRecording.Requests, request)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestDebugRecording(t *testing.T) {
+	a := &App{}
+
+	hook := &model.IntegrationSource{Type: model.INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: model.NewId()}
+
+	_, err := a.GetDebugRecording()
+	require.NotNil(t, err)
+	assert.False(t, a.ShouldRecordDebugRequest("/hooks/"+hook.Id, hook))
+
+	_, err = a.StartDebugRecording(&model.DebugRecordingFilter{}, model.NewId())
+	require.NotNil(t, err)
+
+	recording, err := a.StartDebugRecording(&model.DebugRecordingFilter{IntegrationId: hook.Id}, model.NewId())
+	require.Nil(t, err)
+	assert.Equal(t, recording.StartAt+model.DEBUG_RECORDING_DEFAULT_DURATION_SECONDS*1000, recording.ExpireAt)
+
+	assert.True(t, a.ShouldRecordDebugRequest("/hooks/"+hook.Id, hook))
+	assert.False(t, a.ShouldRecordDebugRequest("/api/v4/users/me", nil))
+	assert.False(t, a.ShouldRecordDebugRequest(model.API_URL_SUFFIX+"/debug/recording", hook))
+
+	for i := 0; i < model.DEBUG_RECORDING_MAX_REQUESTS+2; i++ {
+		a.RecordDebugRequest(&model.DebugRecordedRequest{Timestamp: model.GetMillis(), Path: "/hooks/" + hook.Id})
+	}
+
+	recording, err = a.GetDebugRecording()
+	require.Nil(t, err)
+	assert.Len(t, recording.Requests, model.DEBUG_RECORDING_MAX_REQUESTS)
+	assert.Equal(t, 2, recording.DroppedRequests)
+
+	t.Run("expired", func(t *testing.T) {
+		a.debugRecording.ExpireAt = model.GetMillis() - 1
+
+		assert.False(t, a.ShouldRecordDebugRequest("/hooks/"+hook.Id, hook))
+
+		a.RecordDebugRequest(&model.DebugRecordedRequest{Timestamp: model.GetMillis(), Path: "/hooks/" + hook.Id})
+		recording, err := a.GetDebugRecording()
+		require.Nil(t, err)
+		assert.Equal(t, 2, recording.DroppedRequests)
+	})
+
+	a.StopDebugRecording()
+	_, err = a.GetDebugRecording()
+	require.NotNil(t, err)
+}
@@ -45,17 +45,22 @@ const (
 	TRACK_CONFIG_ELASTICSEARCH      = "config_elasticsearch"
 	TRACK_CONFIG_PLUGIN             = "config_plugin"
 	TRACK_CONFIG_DATA_RETENTION     = "config_data_retention"
+	TRACK_CONFIG_INACTIVE_USER      = "config_inactive_user"
 	TRACK_CONFIG_MESSAGE_EXPORT     = "config_message_export"
 	TRACK_CONFIG_DISPLAY            = "config_display"
 	TRACK_CONFIG_TIMEZONE           = "config_timezone"
+	TRACK_CONFIG_ISSUE_UNFURL       = "config_issue_unfurl"
+	TRACK_CONFIG_SYSTEM_EVENTS      = "config_system_events"
+	TRACK_CONFIG_SMS                = "config_sms"
 	TRACK_PERMISSIONS_GENERAL       = "permissions_general"
 	TRACK_PERMISSIONS_SYSTEM_SCHEME = "permissions_system_scheme"
 	TRACK_PERMISSIONS_TEAM_SCHEMES  = "permissions_team_schemes"
 
-	TRACK_ACTIVITY = "activity"
-	TRACK_LICENSE  = "license"
-	TRACK_SERVER   = "server"
-	TRACK_PLUGINS  = "plugins"
+	TRACK_ACTIVITY    = "activity"
+	TRACK_LICENSE     = "license"
+	TRACK_SERVER      = "server"
+	TRACK_PLUGINS     = "plugins"
+	TRACK_PERFORMANCE = "performance"
 )
 
 var client *analytics.Client
@@ -69,6 +74,7 @@ func (a *App) SendDailyDiagnostics() {
 		a.trackPlugins()
 		a.trackServer()
 		a.trackPermissions()
+		a.trackPerformance()
 	}
 }
 
@@ -198,6 +204,9 @@ func (a *App) trackConfig() {
 	cfg := a.Config()
 	a.SendDiagnostic(TRACK_CONFIG_SERVICE, map[string]interface{}{
 		"web_server_mode":                             *cfg.ServiceSettings.WebserverMode,
+		"enable_api_compression":                      *cfg.ServiceSettings.EnableApiCompression,
+		"compression_minimum_size":                    *cfg.ServiceSettings.CompressionMinimumSize,
+		"isdefault_compression_content_types":         isDefault(*cfg.ServiceSettings.CompressionContentTypes, ""),
 		"enable_security_fix_alert":                   *cfg.ServiceSettings.EnableSecurityFixAlert,
 		"enable_insecure_outgoing_connections":        *cfg.ServiceSettings.EnableInsecureOutgoingConnections,
 		"enable_incoming_webhooks":                    cfg.ServiceSettings.EnableIncomingWebhooks,
@@ -207,6 +216,8 @@ func (a *App) trackConfig() {
 		"enable_post_username_override":               cfg.ServiceSettings.EnablePostUsernameOverride,
 		"enable_post_icon_override":                   cfg.ServiceSettings.EnablePostIconOverride,
 		"enable_user_access_tokens":                   *cfg.ServiceSettings.EnableUserAccessTokens,
+		"enable_bot_account_creation":                 *cfg.ServiceSettings.EnableBotAccountCreation,
+		"disable_bots_when_owner_is_deactivated":      *cfg.ServiceSettings.DisableBotsWhenOwnerIsDeactivated,
 		"enable_custom_emoji":                         *cfg.ServiceSettings.EnableCustomEmoji,
 		"enable_emoji_picker":                         *cfg.ServiceSettings.EnableEmojiPicker,
 		"enable_gif_picker":                           *cfg.ServiceSettings.EnableGifPicker,
@@ -244,6 +255,24 @@ func (a *App) trackConfig() {
 		"post_edit_time_limit":                                    *cfg.ServiceSettings.PostEditTimeLimit,
 		"enable_user_typing_messages":                             *cfg.ServiceSettings.EnableUserTypingMessages,
 		"enable_channel_viewed_messages":                          *cfg.ServiceSettings.EnableChannelViewedMessages,
+		"read_receipts":                                           *cfg.ServiceSettings.ReadReceipts,
+		"maximum_websocket_connections_per_user":                  *cfg.ServiceSettings.MaximumWebSocketConnectionsPerUser,
+		"maximum_websocket_connections_per_server":                *cfg.ServiceSettings.MaximumWebSocketConnectionsPerServer,
+		"websocket_send_queue_size":                               *cfg.ServiceSettings.WebSocketSendQueueSize,
+		"websocket_slow_client_timeout_seconds":                   *cfg.ServiceSettings.WebSocketSlowClientTimeoutSeconds,
+		"graceful_shutdown_timeout_seconds":                       *cfg.ServiceSettings.GracefulShutdownTimeoutSeconds,
+		"enable_channel_bridges":                                  *cfg.ServiceSettings.EnableChannelBridges,
+		"thread_auto_follow":                                      *cfg.ServiceSettings.ThreadAutoFollow,
+		"link_metadata_time_to_live_hours":                        *cfg.ServiceSettings.LinkMetadataTimeToLiveHours,
+		"link_metadata_max_cache_size":                            *cfg.ServiceSettings.LinkMetadataMaxCacheSize,
+		"link_metadata_refresh_min_accesses":                      *cfg.ServiceSettings.LinkMetadataRefreshMinAccesses,
+		"enable_link_preview_thumbnails":                          *cfg.ServiceSettings.EnableLinkPreviewThumbnails,
+		"link_preview_thumbnail_width":                            *cfg.ServiceSettings.LinkPreviewThumbnailWidth,
+		"link_preview_mode":                                       *cfg.ServiceSettings.LinkPreviewMode,
+		"isdefault_link_preview_fixtures_file":                    isDefault(*cfg.ServiceSettings.LinkPreviewFixturesFile, ""),
+		"incoming_webhook_rate_limit_per_minute":                  *cfg.ServiceSettings.IncomingWebhookRateLimitPerMinute,
+		"incoming_webhook_rate_limit_burst":                       *cfg.ServiceSettings.IncomingWebhookRateLimitBurst,
+		"outgoing_webhook_max_retries":                            *cfg.ServiceSettings.OutgoingWebhookMaxRetries,
 		"time_between_user_typing_updates_milliseconds":           *cfg.ServiceSettings.TimeBetweenUserTypingUpdatesMilliseconds,
 		"cluster_log_timeout_milliseconds":                        *cfg.ServiceSettings.ClusterLogTimeoutMilliseconds,
 		"enable_post_search":                                      *cfg.ServiceSettings.EnablePostSearch,
@@ -263,6 +292,7 @@ func (a *App) trackConfig() {
 		"experimental_limit_client_config":                        *cfg.ServiceSettings.ExperimentalLimitClientConfig,
 		"enable_email_invitations":                                *cfg.ServiceSettings.EnableEmailInvitations,
 		"experimental_channel_organization":                       *cfg.ServiceSettings.ExperimentalChannelOrganization,
+		"enable_channel_organization":                             *cfg.ServiceSettings.EnableChannelOrganization,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_TEAM, map[string]interface{}{
@@ -296,6 +326,7 @@ func (a *App) trackConfig() {
 		"experimental_town_square_is_read_only":     *cfg.TeamSettings.ExperimentalTownSquareIsReadOnly,
 		"experimental_primary_team":                 isDefault(*cfg.TeamSettings.ExperimentalPrimaryTeam, ""),
 		"experimental_default_channels":             len(cfg.TeamSettings.ExperimentalDefaultChannels),
+		"team_deletion_grace_period_days":           *cfg.TeamSettings.TeamDeletionGracePeriodDays,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_CLIENT_REQ, map[string]interface{}{
@@ -308,14 +339,16 @@ func (a *App) trackConfig() {
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_SQL, map[string]interface{}{
-		"driver_name":                    *cfg.SqlSettings.DriverName,
-		"trace":                          cfg.SqlSettings.Trace,
-		"max_idle_conns":                 *cfg.SqlSettings.MaxIdleConns,
-		"conn_max_lifetime_milliseconds": *cfg.SqlSettings.ConnMaxLifetimeMilliseconds,
-		"max_open_conns":                 *cfg.SqlSettings.MaxOpenConns,
-		"data_source_replicas":           len(cfg.SqlSettings.DataSourceReplicas),
-		"data_source_search_replicas":    len(cfg.SqlSettings.DataSourceSearchReplicas),
-		"query_timeout":                  *cfg.SqlSettings.QueryTimeout,
+		"driver_name":                           *cfg.SqlSettings.DriverName,
+		"trace":                                 cfg.SqlSettings.Trace,
+		"max_idle_conns":                        *cfg.SqlSettings.MaxIdleConns,
+		"conn_max_lifetime_milliseconds":        *cfg.SqlSettings.ConnMaxLifetimeMilliseconds,
+		"max_open_conns":                        *cfg.SqlSettings.MaxOpenConns,
+		"data_source_replicas":                  len(cfg.SqlSettings.DataSourceReplicas),
+		"data_source_search_replicas":           len(cfg.SqlSettings.DataSourceSearchReplicas),
+		"query_timeout":                         *cfg.SqlSettings.QueryTimeout,
+		"enable_post_events":                    *cfg.SqlSettings.EnablePostEvents,
+		"replica_health_check_interval_seconds": *cfg.SqlSettings.ReplicaHealthCheckIntervalSeconds,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_LOG, map[string]interface{}{
@@ -338,18 +371,23 @@ func (a *App) trackConfig() {
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_FILE, map[string]interface{}{
-		"enable_public_links":     cfg.FileSettings.EnablePublicLink,
-		"driver_name":             *cfg.FileSettings.DriverName,
-		"isdefault_directory":     isDefault(cfg.FileSettings.Directory, model.FILE_SETTINGS_DEFAULT_DIRECTORY),
-		"isabsolute_directory":    filepath.IsAbs(cfg.FileSettings.Directory),
-		"amazon_s3_ssl":           *cfg.FileSettings.AmazonS3SSL,
-		"amazon_s3_sse":           *cfg.FileSettings.AmazonS3SSE,
-		"amazon_s3_signv2":        *cfg.FileSettings.AmazonS3SignV2,
-		"amazon_s3_trace":         *cfg.FileSettings.AmazonS3Trace,
-		"max_file_size":           *cfg.FileSettings.MaxFileSize,
-		"enable_file_attachments": *cfg.FileSettings.EnableFileAttachments,
-		"enable_mobile_upload":    *cfg.FileSettings.EnableMobileUpload,
-		"enable_mobile_download":  *cfg.FileSettings.EnableMobileDownload,
+		"enable_public_links":              cfg.FileSettings.EnablePublicLink,
+		"driver_name":                      *cfg.FileSettings.DriverName,
+		"isdefault_directory":              isDefault(cfg.FileSettings.Directory, model.FILE_SETTINGS_DEFAULT_DIRECTORY),
+		"isabsolute_directory":             filepath.IsAbs(cfg.FileSettings.Directory),
+		"amazon_s3_ssl":                    *cfg.FileSettings.AmazonS3SSL,
+		"amazon_s3_sse":                    *cfg.FileSettings.AmazonS3SSE,
+		"amazon_s3_signv2":                 *cfg.FileSettings.AmazonS3SignV2,
+		"amazon_s3_trace":                  *cfg.FileSettings.AmazonS3Trace,
+		"max_file_size":                    *cfg.FileSettings.MaxFileSize,
+		"enable_file_attachments":          *cfg.FileSettings.EnableFileAttachments,
+		"enable_mobile_upload":             *cfg.FileSettings.EnableMobileUpload,
+		"enable_mobile_download":           *cfg.FileSettings.EnableMobileDownload,
+		"enable_image_optimization":        *cfg.FileSettings.EnableImageOptimization,
+		"image_optimization_minimum_size":  *cfg.FileSettings.ImageOptimizationMinimumSize,
+		"image_optimization_max_dimension": *cfg.FileSettings.ImageOptimizationMaxDimension,
+		"image_optimization_jpeg_quality":  *cfg.FileSettings.ImageOptimizationJpegQuality,
+		"preserve_original_images":         *cfg.FileSettings.PreserveOriginalImages,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_EMAIL, map[string]interface{}{
@@ -364,9 +402,11 @@ func (a *App) trackConfig() {
 		"connection_security":                  cfg.EmailSettings.ConnectionSecurity,
 		"send_push_notifications":              *cfg.EmailSettings.SendPushNotifications,
 		"push_notification_contents":           *cfg.EmailSettings.PushNotificationContents,
+		"enable_web_push_notifications":        *cfg.EmailSettings.EnableWebPushNotifications,
 		"enable_email_batching":                *cfg.EmailSettings.EnableEmailBatching,
 		"email_batching_buffer_size":           *cfg.EmailSettings.EmailBatchingBufferSize,
 		"email_batching_interval":              *cfg.EmailSettings.EmailBatchingInterval,
+		"enable_email_digests":                 *cfg.EmailSettings.EnableEmailDigests,
 		"enable_preview_mode_banner":           *cfg.EmailSettings.EnablePreviewModeBanner,
 		"isdefault_feedback_name":              isDefault(cfg.EmailSettings.FeedbackName, ""),
 		"isdefault_feedback_email":             isDefault(cfg.EmailSettings.FeedbackEmail, ""),
@@ -375,6 +415,7 @@ func (a *App) trackConfig() {
 		"isdefault_login_button_color":         isDefault(*cfg.EmailSettings.LoginButtonColor, ""),
 		"isdefault_login_button_border_color":  isDefault(*cfg.EmailSettings.LoginButtonBorderColor, ""),
 		"isdefault_login_button_text_color":    isDefault(*cfg.EmailSettings.LoginButtonTextColor, ""),
+		"isdefault_email_templates_directory":  isDefault(*cfg.EmailSettings.EmailTemplatesDirectory, ""),
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_EXTENSION, map[string]interface{}{
@@ -392,8 +433,9 @@ func (a *App) trackConfig() {
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_PRIVACY, map[string]interface{}{
-		"show_email_address": cfg.PrivacySettings.ShowEmailAddress,
-		"show_full_name":     cfg.PrivacySettings.ShowFullName,
+		"show_email_address":            cfg.PrivacySettings.ShowEmailAddress,
+		"show_full_name":                cfg.PrivacySettings.ShowFullName,
+		"enable_user_data_minimization": *cfg.PrivacySettings.EnableUserDataMinimization,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_THEME, map[string]interface{}{
@@ -478,11 +520,15 @@ func (a *App) trackConfig() {
 		"use_ip_address":          *cfg.ClusterSettings.UseIpAddress,
 		"use_experimental_gossip": *cfg.ClusterSettings.UseExperimentalGossip,
 		"read_only_config":        *cfg.ClusterSettings.ReadOnlyConfig,
+		"use_redis":               *cfg.ClusterSettings.RedisAddress != "",
+		"enable_redis_cache":      *cfg.ClusterSettings.EnableRedisCache,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_METRICS, map[string]interface{}{
-		"enable":             *cfg.MetricsSettings.Enable,
-		"block_profile_rate": *cfg.MetricsSettings.BlockProfileRate,
+		"enable":                            *cfg.MetricsSettings.Enable,
+		"block_profile_rate":                *cfg.MetricsSettings.BlockProfileRate,
+		"performance_timing_sample_percent": *cfg.MetricsSettings.PerformanceTimingSamplePercent,
+		"enable_store_tracing":              *cfg.MetricsSettings.EnableStoreTracing,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_NATIVEAPP, map[string]interface{}{
@@ -529,10 +575,15 @@ func (a *App) trackConfig() {
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_PLUGIN, map[string]interface{}{
-		"enable_jira":    pluginSetting(&cfg.PluginSettings, "jira", "enabled", false),
-		"enable_zoom":    pluginActivated(cfg.PluginSettings.PluginStates, "zoom"),
-		"enable":         *cfg.PluginSettings.Enable,
-		"enable_uploads": *cfg.PluginSettings.EnableUploads,
+		"enable_jira":              pluginSetting(&cfg.PluginSettings, "jira", "enabled", false),
+		"enable_zoom":              pluginActivated(cfg.PluginSettings.PluginStates, "zoom"),
+		"enable":                   *cfg.PluginSettings.Enable,
+		"enable_uploads":           *cfg.PluginSettings.EnableUploads,
+		"enable_marketplace":       *cfg.PluginSettings.EnableMarketplace,
+		"max_memory_mb":            *cfg.PluginSettings.MaxMemoryMB,
+		"max_api_calls_per_minute": *cfg.PluginSettings.MaxAPICallsPerMinute,
+		"max_kv_storage_mb":        *cfg.PluginSettings.MaxKVStorageMB,
+		"isdefault_limits":         len(cfg.PluginSettings.Limits) == 0,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_DATA_RETENTION, map[string]interface{}{
@@ -543,6 +594,17 @@ func (a *App) trackConfig() {
 		"deletion_job_start_time": *cfg.DataRetentionSettings.DeletionJobStartTime,
 	})
 
+	a.SendDiagnostic(TRACK_CONFIG_INACTIVE_USER, map[string]interface{}{
+		"enable_inactive_user_detection": *cfg.InactiveUserSettings.EnableInactiveUserDetection,
+		"inactive_days":                  *cfg.InactiveUserSettings.InactiveDays,
+		"notify_inactive_users":          *cfg.InactiveUserSettings.NotifyInactiveUsers,
+		"enable_auto_deactivation":       *cfg.InactiveUserSettings.EnableAutoDeactivation,
+		"deactivation_grace_period_days": *cfg.InactiveUserSettings.DeactivationGracePeriodDays,
+		"excluded_usernames_count":       len(cfg.InactiveUserSettings.ExcludedUsernames),
+		"excluded_roles_count":           len(cfg.InactiveUserSettings.ExcludedRoles),
+		"job_start_time":                 *cfg.InactiveUserSettings.JobStartTime,
+	})
+
 	a.SendDiagnostic(TRACK_CONFIG_MESSAGE_EXPORT, map[string]interface{}{
 		"enable_message_export":                 *cfg.MessageExportSettings.EnableExport,
 		"export_format":                         *cfg.MessageExportSettings.ExportFormat,
@@ -557,12 +619,39 @@ func (a *App) trackConfig() {
 
 	a.SendDiagnostic(TRACK_CONFIG_DISPLAY, map[string]interface{}{
 		"experimental_timezone":        *cfg.DisplaySettings.ExperimentalTimezone,
+		"enable_timezone":              *cfg.DisplaySettings.EnableTimezone,
 		"isdefault_custom_url_schemes": len(*cfg.DisplaySettings.CustomUrlSchemes) != 0,
 	})
 
 	a.SendDiagnostic(TRACK_CONFIG_TIMEZONE, map[string]interface{}{
 		"isdefault_supported_timezones_path": isDefault(*cfg.TimezoneSettings.SupportedTimezonesPath, model.TIMEZONE_SETTINGS_DEFAULT_SUPPORTED_TIMEZONES_PATH),
 	})
+
+	issueUnfurlProviders := map[string]int{}
+	for _, provider := range cfg.IssueUnfurlSettings.Providers {
+		if provider.Enable {
+			issueUnfurlProviders[provider.Type]++
+		}
+	}
+
+	a.SendDiagnostic(TRACK_CONFIG_ISSUE_UNFURL, map[string]interface{}{
+		"enable":         *cfg.IssueUnfurlSettings.Enable,
+		"jira_domains":   issueUnfurlProviders[model.ISSUE_UNFURL_PROVIDER_JIRA],
+		"github_domains": issueUnfurlProviders[model.ISSUE_UNFURL_PROVIDER_GITHUB],
+	})
+
+	a.SendDiagnostic(TRACK_CONFIG_SYSTEM_EVENTS, map[string]interface{}{
+		"enable":                    *cfg.SystemEventsSettings.Enable,
+		"enable_job_failures":       *cfg.SystemEventsSettings.EnableJobFailures,
+		"enable_config_changes":     *cfg.SystemEventsSettings.EnableConfigChanges,
+		"enable_security_events":    *cfg.SystemEventsSettings.EnableSecurityEvents,
+		"enable_user_deactivations": *cfg.SystemEventsSettings.EnableUserDeactivations,
+	})
+
+	a.SendDiagnostic(TRACK_CONFIG_SMS, map[string]interface{}{
+		"enable":            *cfg.SmsSettings.Enable,
+		"isdefault_api_url": isDefault(*cfg.SmsSettings.ApiURL, model.SMS_SETTINGS_DEFAULT_API_URL),
+	})
 }
 
 func (a *App) trackLicense() {
@@ -752,3 +841,24 @@ func (a *App) trackPermissions() {
 		}
 	}
 }
+
+// trackPerformance sends the sampled timings of hot code paths that were recorded since the last time diagnostics
+// were sent.
+func (a *App) trackPerformance() {
+	for stage, timing := range a.GetPerformanceTimings(true) {
+		if timing.Count == 0 {
+			continue
+		}
+
+		a.SendDiagnostic(TRACK_PERFORMANCE, map[string]interface{}{
+			"stage":   stage,
+			"count":   timing.Count,
+			"sum_ms":  timing.SumMs,
+			"p50_ms":  timing.Percentile(50),
+			"p95_ms":  timing.Percentile(95),
+			"p99_ms":  timing.Percentile(99),
+			"buckets": timing.Buckets,
+			"counts":  timing.Counts,
+		})
+	}
+}
@@ -99,6 +99,8 @@ func TestDiagnostics(t *testing.T) {
 	})
 
 	t.Run("SendDailyDiagnostics", func(t *testing.T) {
+		th.App.ObservePerformanceTiming(PERFORMANCE_TIMING_POST_CREATE, time.Millisecond)
+
 		th.App.SendDailyDiagnostics()
 
 		info := ""
@@ -140,7 +142,9 @@ func TestDiagnostics(t *testing.T) {
 			TRACK_ACTIVITY,
 			TRACK_SERVER,
 			TRACK_CONFIG_MESSAGE_EXPORT,
+			TRACK_CONFIG_INACTIVE_USER,
 			TRACK_PLUGINS,
+			TRACK_PERFORMANCE,
 		} {
 			if !strings.Contains(info, item) {
 				t.Fatal("Sent diagnostics missing item: " + item)
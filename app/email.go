@@ -5,6 +5,7 @@ package app
 
 import (
 	"fmt"
+	"net/mail"
 	"net/url"
 
 	"net/http"
@@ -59,7 +60,7 @@ func (a *App) SendChangeUsernameEmail(oldUsername, newUsername, email, locale, s
 		map[string]interface{}{"TeamDisplayName": a.Config().TeamSettings.SiteName, "NewUsername": newUsername})
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendChangeUsernameEmail", "api.user.send_email_change_username_and_forget.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -83,7 +84,7 @@ func (a *App) SendEmailChangeVerifyEmail(newUserEmail, locale, siteURL, token st
 	bodyPage.Props["VerifyUrl"] = link
 	bodyPage.Props["VerifyButton"] = T("api.templates.email_change_verify_body.button")
 
-	if err := a.SendMail(newUserEmail, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(newUserEmail, subject, bodyPage); err != nil {
 		return model.NewAppError("SendEmailChangeVerifyEmail", "api.user.send_email_change_verify_email_and_forget.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -104,7 +105,7 @@ func (a *App) SendEmailChangeEmail(oldEmail, newEmail, locale, siteURL string) *
 		map[string]interface{}{"TeamDisplayName": a.Config().TeamSettings.SiteName, "NewEmail": newEmail})
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(oldEmail, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(oldEmail, subject, bodyPage); err != nil {
 		return model.NewAppError("SendEmailChangeEmail", "api.user.send_email_change_email_and_forget.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -128,7 +129,7 @@ func (a *App) SendVerifyEmail(userEmail, locale, siteURL, token string) *model.A
 	bodyPage.Props["VerifyUrl"] = link
 	bodyPage.Props["Button"] = T("api.templates.verify_body.button")
 
-	if err := a.SendMail(userEmail, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(userEmail, subject, bodyPage); err != nil {
 		return model.NewAppError("SendVerifyEmail", "api.user.send_verify_email_and_forget.failed.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -148,7 +149,7 @@ func (a *App) SendSignInChangeEmail(email, method, locale, siteURL string) *mode
 		map[string]interface{}{"SiteName": a.ClientConfig()["SiteName"], "Method": method})
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendSignInChangeEmail", "api.user.send_sign_in_change_email_and_forget.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -187,7 +188,7 @@ func (a *App) SendWelcomeEmail(userId string, email string, verified bool, local
 		bodyPage.Props["VerifyUrl"] = link
 	}
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendWelcomeEmail", "api.user.send_welcome_email_and_forget.failed.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -208,7 +209,7 @@ func (a *App) SendPasswordChangeEmail(email, method, locale, siteURL string) *mo
 		map[string]interface{}{"TeamDisplayName": a.Config().TeamSettings.SiteName, "TeamURL": siteURL, "Method": method})
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendPasswordChangeEmail", "api.user.send_password_change_email_and_forget.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -228,7 +229,7 @@ func (a *App) SendUserAccessTokenAddedEmail(email, locale, siteURL string) *mode
 		map[string]interface{}{"SiteName": a.ClientConfig()["SiteName"], "SiteURL": siteURL})
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendUserAccessTokenAddedEmail", "api.user.send_user_access_token.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -252,7 +253,7 @@ func (a *App) SendPasswordResetEmail(email string, token *model.Token, locale, s
 	bodyPage.Props["ResetUrl"] = link
 	bodyPage.Props["Button"] = T("api.templates.reset_body.button")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return false, model.NewAppError("SendPasswordReset", "api.user.send_password_reset.send.app_error", nil, "err="+err.Message, http.StatusInternalServerError)
 	}
 
@@ -277,7 +278,7 @@ func (a *App) SendMfaChangeEmail(email string, activated bool, locale, siteURL s
 	}
 	bodyPage.Props["Warning"] = T("api.templates.email_warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendMfaChangeEmail", "api.user.send_mfa_change_email.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -343,7 +344,7 @@ func (a *App) SendInviteEmails(team *model.Team, senderName string, senderUserId
 				mlog.Info(fmt.Sprintf("sending invitation to %v %v", invite, bodyPage.Props["Link"]))
 			}
 
-			if err := a.SendMail(invite, subject, bodyPage.Render()); err != nil {
+			if err := a.SendTeamTemplateMail(team.Id, invite, subject, bodyPage); err != nil {
 				mlog.Error(fmt.Sprintf("Failed to send invite email successfully err=%v", err))
 			}
 		}
@@ -352,6 +353,7 @@ func (a *App) SendInviteEmails(team *model.Team, senderName string, senderUserId
 
 func (a *App) NewEmailTemplate(name, locale string) *utils.HTMLTemplate {
 	t := utils.NewHTMLTemplate(a.HTMLTemplates(), name)
+	t.TextTemplates = a.TextTemplates()
 
 	var localT i18n.TranslateFunc
 	if locale != "" {
@@ -373,6 +375,7 @@ func (a *App) NewEmailTemplate(name, locale string) *utils.HTMLTemplate {
 	t.Props["EmailInfo3"] = localT("api.templates.email_info3",
 		map[string]interface{}{"SiteName": a.Config().TeamSettings.SiteName})
 	t.Props["SupportEmail"] = *a.Config().SupportSettings.SupportEmail
+	t.Props["LogoPath"] = a.emailLogoPath()
 
 	return t
 }
@@ -393,7 +396,7 @@ func (a *App) SendDeactivateAccountEmail(email string, locale, siteURL string) *
 		map[string]interface{}{"SiteURL": siteURL})
 	bodyPage.Props["Warning"] = T("api.templates.deactivate_body.warning")
 
-	if err := a.SendMail(email, subject, bodyPage.Render()); err != nil {
+	if err := a.SendTemplateMail(email, subject, bodyPage); err != nil {
 		return model.NewAppError("SendDeactivateEmail", "api.user.send_deactivate_email_and_forget.failed.error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
@@ -401,6 +404,68 @@ func (a *App) SendDeactivateAccountEmail(email string, locale, siteURL string) *
 }
 
 func (a *App) SendMail(to, subject, htmlBody string) *model.AppError {
+	return a.sendMail(to, subject, htmlBody, "")
+}
+
+// SendTemplateMail renders an email template and sends it, using the template's plain text override if there's one.
+func (a *App) SendTemplateMail(to, subject string, page *utils.HTMLTemplate) *model.AppError {
+	return a.sendMail(to, subject, page.Render(), page.RenderText())
+}
+
+func (a *App) sendMail(to, subject, htmlBody, textBody string) *model.AppError {
+	cfg := a.Config()
+	from := mail.Address{Name: cfg.EmailSettings.FeedbackName, Address: cfg.EmailSettings.FeedbackEmail}
+
+	license := a.License()
+	return utils.SendMailUsingConfigAdvanced(to, to, from, subject, htmlBody, textBody, nil, nil, cfg, license != nil && *license.Features.Compliance)
+}
+
+// SendTeamMail sends an email on behalf of a team, using the team's outgoing email identity if one is configured
+// and the server's otherwise.
+func (a *App) SendTeamMail(teamId, to, subject, htmlBody string) *model.AppError {
+	return a.sendTeamMail(teamId, to, subject, htmlBody, "")
+}
+
+// SendTeamTemplateMail renders an email template and sends it on behalf of a team.
+func (a *App) SendTeamTemplateMail(teamId, to, subject string, page *utils.HTMLTemplate) *model.AppError {
+	return a.sendTeamMail(teamId, to, subject, page.Render(), page.RenderText())
+}
+
+func (a *App) sendTeamMail(teamId, to, subject, htmlBody, textBody string) *model.AppError {
+	identity, ok := a.Config().EmailSettings.TeamIdentities[teamId]
+	if !ok {
+		return a.sendMail(to, subject, htmlBody, textBody)
+	}
+
+	cfg := teamEmailConfig(a.Config(), identity)
+	from := mail.Address{Name: cfg.EmailSettings.FeedbackName, Address: cfg.EmailSettings.FeedbackEmail}
+
+	var mimeHeaders map[string]string
+	if len(identity.ReplyToAddress) > 0 {
+		mimeHeaders = map[string]string{"Reply-To": identity.ReplyToAddress}
+	}
+
 	license := a.License()
-	return utils.SendMailUsingConfig(to, subject, htmlBody, a.Config(), license != nil && *license.Features.Compliance)
+	return utils.SendMailUsingConfigAdvanced(to, to, from, subject, htmlBody, textBody, nil, mimeHeaders, cfg, license != nil && *license.Features.Compliance)
+}
+
+// teamEmailConfig returns a copy of the config with a team's email identity in place of the server's.
+func teamEmailConfig(cfg *model.Config, identity *model.TeamEmailIdentity) *model.Config {
+	teamCfg := *cfg
+
+	if len(identity.FeedbackName) > 0 {
+		teamCfg.EmailSettings.FeedbackName = identity.FeedbackName
+	}
+
+	if len(identity.FeedbackEmail) > 0 {
+		teamCfg.EmailSettings.FeedbackEmail = identity.FeedbackEmail
+	}
+
+	if len(identity.SMTPUsername) > 0 {
+		teamCfg.EmailSettings.EnableSMTPAuth = model.NewBool(true)
+		teamCfg.EmailSettings.SMTPUsername = identity.SMTPUsername
+		teamCfg.EmailSettings.SMTPPassword = identity.SMTPPassword
+	}
+
+	return &teamCfg
 }
@@ -24,7 +24,7 @@ const (
 )
 
 func (a *App) InitEmailBatching() {
-	if *a.Config().EmailSettings.EnableEmailBatching {
+	if *a.Config().EmailSettings.EnableEmailBatching && !*a.Config().EmailSettings.EnableEmailDigests {
 		if a.EmailBatching == nil {
 			a.EmailBatching = NewEmailBatchingJob(a, *a.Config().EmailSettings.EmailBatchingBufferSize)
 		}
@@ -207,19 +207,17 @@ func (a *App) sendBatchedEmailNotification(userId string, notifications []*batch
 	translateFunc := utils.GetUserTranslations(user.Locale)
 	displayNameFormat := *a.Config().TeamSettings.TeammateNameDisplay
 
-	var contents string
-	for _, notification := range notifications {
-		var sender *model.User
-		schan := a.Srv.Store.User().Get(notification.post.UserId)
-		if result := <-schan; result.Err != nil {
-			mlog.Warn("Unable to find sender of post for batched email notification")
-			continue
-		} else {
-			sender = result.Data.(*model.User)
-		}
+	emailNotificationContentsType := model.EMAIL_NOTIFICATION_CONTENTS_FULL
+	if license := a.License(); license != nil && *license.Features.EmailNotificationContents {
+		emailNotificationContentsType = *a.Config().EmailSettings.EmailNotificationContentsType
+	}
+
+	senders := make(map[string]*model.User)
 
+	var contents string
+	for _, thread := range groupBatchedNotificationsByThread(notifications) {
 		var channel *model.Channel
-		cchan := a.Srv.Store.Channel().Get(notification.post.ChannelId, true)
+		cchan := a.Srv.Store.Channel().Get(thread[0].post.ChannelId, true)
 		if result := <-cchan; result.Err != nil {
 			mlog.Warn("Unable to find channel of post for batched email notification")
 			continue
@@ -227,12 +225,27 @@ func (a *App) sendBatchedEmailNotification(userId string, notifications []*batch
 			channel = result.Data.(*model.Channel)
 		}
 
-		emailNotificationContentsType := model.EMAIL_NOTIFICATION_CONTENTS_FULL
-		if license := a.License(); license != nil && *license.Features.EmailNotificationContents {
-			emailNotificationContentsType = *a.Config().EmailSettings.EmailNotificationContentsType
+		threadNotifications := []*batchedNotification{}
+		for _, notification := range thread {
+			if _, ok := senders[notification.post.UserId]; !ok {
+				schan := a.Srv.Store.User().Get(notification.post.UserId)
+				if result := <-schan; result.Err != nil {
+					mlog.Warn("Unable to find sender of post for batched email notification")
+					continue
+				} else {
+					senders[notification.post.UserId] = result.Data.(*model.User)
+				}
+			}
+
+			threadNotifications = append(threadNotifications, notification)
 		}
 
-		contents += a.renderBatchedPost(notification, channel, sender, *a.Config().ServiceSettings.SiteURL, displayNameFormat, translateFunc, user.Locale, emailNotificationContentsType)
+		if len(threadNotifications) == 1 {
+			notification := threadNotifications[0]
+			contents += a.renderBatchedPost(notification, channel, senders[notification.post.UserId], *a.Config().ServiceSettings.SiteURL, displayNameFormat, translateFunc, user.Locale, emailNotificationContentsType)
+		} else if len(threadNotifications) > 1 {
+			contents += a.renderBatchedThread(threadNotifications, channel, senders, *a.Config().ServiceSettings.SiteURL, displayNameFormat, translateFunc, user.Locale, emailNotificationContentsType)
+		}
 	}
 
 	tm := time.Unix(notifications[0].post.CreateAt/1000, 0)
@@ -249,11 +262,52 @@ func (a *App) sendBatchedEmailNotification(userId string, notifications []*batch
 	body.Props["Posts"] = template.HTML(contents)
 	body.Props["BodyText"] = translateFunc("api.email_batching.send_batched_email_notification.body_text", len(notifications))
 
-	if err := a.SendMail(user.Email, subject, body.Render()); err != nil {
+	// batches that only cover one team are sent under that team's email identity
+	teamId := ""
+	if batchedNotificationsShareTeam(notifications) {
+		if result := <-a.Srv.Store.Team().GetByName(notifications[0].teamName); result.Err == nil {
+			teamId = result.Data.(*model.Team).Id
+		}
+	}
+
+	if err := a.SendTeamTemplateMail(teamId, user.Email, subject, body); err != nil {
 		mlog.Warn(fmt.Sprintf("Unable to send batched email notification err=%v", err), mlog.String("email", user.Email))
 	}
 }
 
+func batchedNotificationsShareTeam(notifications []*batchedNotification) bool {
+	for _, notification := range notifications {
+		if notification.teamName != notifications[0].teamName {
+			return false
+		}
+	}
+
+	return true
+}
+
+// groupBatchedNotificationsByThread groups notifications for posts in the same thread together so that a busy thread
+// only takes up one entry in the email. Threads are kept in the order that their first notification was queued.
+func groupBatchedNotificationsByThread(notifications []*batchedNotification) [][]*batchedNotification {
+	threads := [][]*batchedNotification{}
+	threadIndexes := make(map[string]int)
+
+	for _, notification := range notifications {
+		rootId := notification.post.RootId
+		if rootId == "" {
+			rootId = notification.post.Id
+		}
+
+		if index, ok := threadIndexes[rootId]; ok {
+			threads[index] = append(threads[index], notification)
+		} else {
+			threadIndexes[rootId] = len(threads)
+			threads = append(threads, []*batchedNotification{notification})
+		}
+	}
+
+	return threads
+}
+
 func (a *App) renderBatchedPost(notification *batchedNotification, channel *model.Channel, sender *model.User, siteURL string, displayNameFormat string, translateFunc i18n.TranslateFunc, userLocale string, emailNotificationContentsType string) string {
 	// don't include message contents if email notification contents type is set to generic
 	var template *utils.HTMLTemplate
@@ -267,11 +321,52 @@ func (a *App) renderBatchedPost(notification *batchedNotification, channel *mode
 	template.Props["PostMessage"] = a.GetMessageForNotification(notification.post, translateFunc)
 	template.Props["PostLink"] = siteURL + "/" + notification.teamName + "/pl/" + notification.post.Id
 	template.Props["SenderName"] = sender.GetDisplayName(displayNameFormat)
+	template.Props["Date"] = renderBatchedPostDate(notification.post, translateFunc)
+	template.Props["ChannelName"] = renderBatchedChannelName(channel, translateFunc, emailNotificationContentsType)
+
+	return template.Render()
+}
+
+type batchedThreadPostProps struct {
+	SenderName string
+	Date       string
+	Message    string
+}
+
+// renderBatchedThread renders several notifications for posts in the same thread as a single entry that links to the
+// most recent of them.
+func (a *App) renderBatchedThread(notifications []*batchedNotification, channel *model.Channel, senders map[string]*model.User, siteURL string, displayNameFormat string, translateFunc i18n.TranslateFunc, userLocale string, emailNotificationContentsType string) string {
+	posts := make([]*batchedThreadPostProps, 0, len(notifications))
+	for _, notification := range notifications {
+		postProps := &batchedThreadPostProps{
+			SenderName: senders[notification.post.UserId].GetDisplayName(displayNameFormat),
+			Date:       renderBatchedPostDate(notification.post, translateFunc),
+		}
+
+		// don't include message contents if email notification contents type is set to generic
+		if emailNotificationContentsType == model.EMAIL_NOTIFICATION_CONTENTS_FULL {
+			postProps.Message = a.GetMessageForNotification(notification.post, translateFunc)
+		}
 
-	tm := time.Unix(notification.post.CreateAt/1000, 0)
+		posts = append(posts, postProps)
+	}
+
+	last := notifications[len(notifications)-1]
+
+	template := a.NewEmailTemplate("post_batched_thread", userLocale)
+	template.Props["Button"] = translateFunc("api.email_batching.render_batched_thread.go_to_thread")
+	template.Props["PostLink"] = siteURL + "/" + last.teamName + "/pl/" + last.post.Id
+	template.Props["Posts"] = posts
+	template.Props["ChannelName"] = renderBatchedChannelName(channel, translateFunc, emailNotificationContentsType)
+
+	return template.Render()
+}
+
+func renderBatchedPostDate(post *model.Post, translateFunc i18n.TranslateFunc) string {
+	tm := time.Unix(post.CreateAt/1000, 0)
 	timezone, _ := tm.Zone()
 
-	template.Props["Date"] = translateFunc("api.email_batching.render_batched_post.date", map[string]interface{}{
+	return translateFunc("api.email_batching.render_batched_post.date", map[string]interface{}{
 		"Year":     tm.Year(),
 		"Month":    translateFunc(tm.Month().String()),
 		"Day":      tm.Day(),
@@ -279,19 +374,17 @@ func (a *App) renderBatchedPost(notification *batchedNotification, channel *mode
 		"Minute":   fmt.Sprintf("%02d", tm.Minute()),
 		"Timezone": timezone,
 	})
+}
 
+func renderBatchedChannelName(channel *model.Channel, translateFunc i18n.TranslateFunc, emailNotificationContentsType string) string {
 	if channel.Type == model.CHANNEL_DIRECT {
-		template.Props["ChannelName"] = translateFunc("api.email_batching.render_batched_post.direct_message")
+		return translateFunc("api.email_batching.render_batched_post.direct_message")
 	} else if channel.Type == model.CHANNEL_GROUP {
-		template.Props["ChannelName"] = translateFunc("api.email_batching.render_batched_post.group_message")
-	} else {
-		// don't include channel name if email notification contents type is set to generic
-		if emailNotificationContentsType == model.EMAIL_NOTIFICATION_CONTENTS_FULL {
-			template.Props["ChannelName"] = channel.DisplayName
-		} else {
-			template.Props["ChannelName"] = translateFunc("api.email_batching.render_batched_post.notification")
-		}
+		return translateFunc("api.email_batching.render_batched_post.group_message")
+	} else if emailNotificationContentsType == model.EMAIL_NOTIFICATION_CONTENTS_FULL {
+		return channel.DisplayName
 	}
 
-	return template.Render()
+	// don't include channel name if email notification contents type is set to generic
+	return translateFunc("api.email_batching.render_batched_post.notification")
 }
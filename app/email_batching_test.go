@@ -335,3 +335,74 @@ func TestRenderBatchedPostFull(t *testing.T) {
 		t.Fatal("Rendered email should contain post contents when email notification contents type is set to Full.")
 	}
 }
+
+func TestGroupBatchedNotificationsByThread(t *testing.T) {
+	root1 := &model.Post{Id: model.NewId()}
+	reply1 := &model.Post{Id: model.NewId(), RootId: root1.Id}
+	root2 := &model.Post{Id: model.NewId()}
+	reply2 := &model.Post{Id: model.NewId(), RootId: root1.Id}
+	reply3 := &model.Post{Id: model.NewId(), RootId: model.NewId()}
+
+	notifications := []*batchedNotification{
+		{post: reply1},
+		{post: root2},
+		{post: root1},
+		{post: reply3},
+		{post: reply2},
+	}
+
+	threads := groupBatchedNotificationsByThread(notifications)
+	if len(threads) != 3 {
+		t.Fatalf("should have grouped notifications into 3 threads, got %v", len(threads))
+	}
+
+	if len(threads[0]) != 3 || threads[0][0].post != reply1 || threads[0][1].post != root1 || threads[0][2].post != reply2 {
+		t.Fatal("should have grouped the root post and replies of the first thread in order")
+	}
+
+	if len(threads[1]) != 1 || threads[1][0].post != root2 {
+		t.Fatal("should have kept the second thread on its own")
+	}
+
+	if len(threads[2]) != 1 || threads[2][0].post != reply3 {
+		t.Fatal("should have kept the reply to a thread with no other notifications on its own")
+	}
+}
+
+/*
+ * Ensures that every post in a thread is included in a single entry of the notification email
+ */
+func TestRenderBatchedThread(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	sender1 := &model.User{Id: model.NewId(), Username: "sender1"}
+	sender2 := &model.User{Id: model.NewId(), Username: "sender2"}
+	senders := map[string]*model.User{sender1.Id: sender1, sender2.Id: sender2}
+
+	root := &model.Post{Id: model.NewId(), UserId: sender1.Id, Message: "This is the first message"}
+	reply := &model.Post{Id: model.NewId(), UserId: sender2.Id, RootId: root.Id, Message: "This is the second message"}
+	notifications := []*batchedNotification{
+		{post: root, teamName: "team"},
+		{post: reply, teamName: "team"},
+	}
+
+	channel := &model.Channel{DisplayName: "Some Test Channel"}
+
+	translateFunc := func(translationID string, args ...interface{}) string {
+		// mock translateFunc just returns the translation id - this is good enough for our purposes
+		return translationID
+	}
+
+	rendered := th.App.renderBatchedThread(notifications, channel, senders, "http://localhost:8065", model.SHOW_USERNAME, translateFunc, "en", model.EMAIL_NOTIFICATION_CONTENTS_FULL)
+	for _, expected := range []string{root.Message, reply.Message, "sender1", "sender2", "/team/pl/" + reply.Id} {
+		if !strings.Contains(rendered, expected) {
+			t.Fatalf("Rendered email should contain %v.", expected)
+		}
+	}
+
+	rendered = th.App.renderBatchedThread(notifications, channel, senders, "http://localhost:8065", model.SHOW_USERNAME, translateFunc, "en", model.EMAIL_NOTIFICATION_CONTENTS_GENERIC)
+	if strings.Contains(rendered, root.Message) || strings.Contains(rendered, reply.Message) {
+		t.Fatal("Rendered email should not contain post contents when email notification contents type is set to Generic.")
+	}
+}
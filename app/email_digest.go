@@ -0,0 +1,362 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+)
+
+type emailDigestThread struct {
+	RootId string
+	Posts  []*model.Post
+}
+
+type emailDigestChannel struct {
+	ChannelId string
+	TeamId    string
+	Threads   []*emailDigestThread
+}
+
+type emailDigestPostProps struct {
+	SenderName string
+	Date       string
+	Message    string
+}
+
+type emailDigestThreadProps struct {
+	Posts []*emailDigestPostProps
+	Link  string
+}
+
+func (a *App) AddNotificationEmailToDigest(user *model.User, post *model.Post, team *model.Team) *model.AppError {
+	if !*a.Config().EmailSettings.EnableEmailDigests {
+		return model.NewAppError("AddNotificationEmailToDigest", "api.email_digest.add_notification_email_to_digest.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	entry := &model.EmailDigestEntry{
+		UserId:    user.Id,
+		PostId:    post.Id,
+		ChannelId: post.ChannelId,
+		TeamId:    team.Id,
+	}
+
+	if result := <-a.Srv.Store.EmailDigest().Save(entry); result.Err != nil {
+		mlog.Error(fmt.Sprintf("Unable to queue email digest notification err=%v", result.Err), mlog.String("user_id", user.Id))
+		return result.Err
+	}
+
+	return nil
+}
+
+// getEmailDigestInterval returns how long, in seconds, the user wants notifications to be collected before a digest is sent.
+func (a *App) getEmailDigestInterval(userId string) int64 {
+	interval, _ := strconv.ParseInt(model.PREFERENCE_EMAIL_INTERVAL_BATCHING_SECONDS, 10, 64)
+
+	if result := <-a.Srv.Store.Preference().Get(userId, model.PREFERENCE_CATEGORY_NOTIFICATIONS, model.PREFERENCE_NAME_EMAIL_INTERVAL); result.Err == nil {
+		switch value := result.Data.(model.Preference).Value; value {
+		case model.PREFERENCE_EMAIL_INTERVAL_BATCHING_SECONDS, model.PREFERENCE_EMAIL_INTERVAL_HOUR_SECONDS, model.PREFERENCE_EMAIL_INTERVAL_DAY_SECONDS:
+			interval, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+
+	return interval
+}
+
+// SendPendingEmailDigests sends a digest to every user whose oldest queued notification has waited for at least
+// the interval they've chosen.
+func (a *App) SendPendingEmailDigests(now time.Time) *model.AppError {
+	var userIds []string
+	if result := <-a.Srv.Store.EmailDigest().GetUserIdsWithEntries(); result.Err != nil {
+		return result.Err
+	} else {
+		userIds = result.Data.([]string)
+	}
+
+	for _, userId := range userIds {
+		if err := a.sendPendingEmailDigest(userId, now); err != nil {
+			mlog.Error(fmt.Sprintf("Unable to send email digest err=%v", err), mlog.String("user_id", userId))
+		}
+	}
+
+	mlog.Debug(fmt.Sprintf("Email digests checked for %v user(s).", len(userIds)))
+
+	return nil
+}
+
+func (a *App) sendPendingEmailDigest(userId string, now time.Time) *model.AppError {
+	var entries []*model.EmailDigestEntry
+	if result := <-a.Srv.Store.EmailDigest().GetForUser(userId); result.Err != nil {
+		return result.Err
+	} else {
+		entries = result.Data.([]*model.EmailDigestEntry)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	interval := a.getEmailDigestInterval(userId)
+	if now.Sub(time.Unix(entries[0].CreateAt/1000, 0)) < time.Duration(interval)*time.Second {
+		return nil
+	}
+
+	// The digest waits for the user's quiet hours to end, including the emails that were held back during them
+	if result := <-a.Srv.Store.User().Get(userId); result.Err == nil && !DoesScheduleAllowNotification(result.Data.(*model.User), model.GetMillisForTime(now)) {
+		return nil
+	}
+
+	lastCreateAt := entries[len(entries)-1].CreateAt
+
+	if entries = a.filterUnreadEmailDigestEntries(userId, entries); len(entries) > 0 {
+		if err := a.sendEmailDigest(userId, entries); err != nil {
+			return err
+		}
+	}
+
+	if result := <-a.Srv.Store.EmailDigest().DeleteForUser(userId, lastCreateAt); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// filterUnreadEmailDigestEntries drops the entries for channels that the user has viewed since they were queued or
+// that the user has since left.
+func (a *App) filterUnreadEmailDigestEntries(userId string, entries []*model.EmailDigestEntry) []*model.EmailDigestEntry {
+	lastViewedAt := make(map[string]int64)
+	filtered := []*model.EmailDigestEntry{}
+
+	for _, entry := range entries {
+		viewedAt, ok := lastViewedAt[entry.ChannelId]
+		if !ok {
+			if result := <-a.Srv.Store.Channel().GetMember(entry.ChannelId, userId); result.Err != nil {
+				viewedAt = model.GetMillis()
+			} else {
+				viewedAt = result.Data.(*model.ChannelMember).LastViewedAt
+			}
+			lastViewedAt[entry.ChannelId] = viewedAt
+		}
+
+		if viewedAt < entry.CreateAt {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// groupEmailDigestPosts groups the posts of a digest by channel and then by thread, keeping each group in the order
+// that its first post was queued.
+func groupEmailDigestPosts(entries []*model.EmailDigestEntry, posts map[string]*model.Post) []*emailDigestChannel {
+	channels := []*emailDigestChannel{}
+	channelsById := make(map[string]*emailDigestChannel)
+	threadsById := make(map[string]*emailDigestThread)
+
+	for _, entry := range entries {
+		post, ok := posts[entry.PostId]
+		if !ok {
+			continue
+		}
+
+		channel, ok := channelsById[entry.ChannelId]
+		if !ok {
+			channel = &emailDigestChannel{ChannelId: entry.ChannelId, TeamId: entry.TeamId}
+			channelsById[entry.ChannelId] = channel
+			channels = append(channels, channel)
+		}
+
+		rootId := post.RootId
+		if rootId == "" {
+			rootId = post.Id
+		}
+
+		thread, ok := threadsById[rootId]
+		if !ok {
+			thread = &emailDigestThread{RootId: rootId}
+			threadsById[rootId] = thread
+			channel.Threads = append(channel.Threads, thread)
+		}
+
+		thread.Posts = append(thread.Posts, post)
+	}
+
+	return channels
+}
+
+func (a *App) sendEmailDigest(userId string, entries []*model.EmailDigestEntry) *model.AppError {
+	var user *model.User
+	if result := <-a.Srv.Store.User().Get(userId); result.Err != nil {
+		return result.Err
+	} else {
+		user = result.Data.(*model.User)
+	}
+
+	if user.DeleteAt != 0 {
+		return nil
+	}
+
+	postIds := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		postIds = append(postIds, entry.PostId)
+	}
+
+	posts := make(map[string]*model.Post)
+	if result := <-a.Srv.Store.Post().GetPostsByIds(postIds); result.Err != nil {
+		return result.Err
+	} else {
+		for _, post := range result.Data.([]*model.Post) {
+			posts[post.Id] = post
+		}
+	}
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	translateFunc := utils.GetUserTranslations(user.Locale)
+	siteURL := *a.Config().ServiceSettings.SiteURL
+
+	emailNotificationContentsType := model.EMAIL_NOTIFICATION_CONTENTS_FULL
+	if license := a.License(); license != nil && *license.Features.EmailNotificationContents {
+		emailNotificationContentsType = *a.Config().EmailSettings.EmailNotificationContentsType
+	}
+
+	var contents string
+	for _, channel := range groupEmailDigestPosts(entries, posts) {
+		contents += a.renderEmailDigestChannel(channel, user, siteURL, translateFunc, emailNotificationContentsType)
+	}
+
+	tm := time.Unix(model.GetMillis()/1000, 0)
+
+	subject := translateFunc("api.email_digest.send_email_digest.subject", len(posts), map[string]interface{}{
+		"SiteName": a.Config().TeamSettings.SiteName,
+		"Year":     tm.Year(),
+		"Month":    translateFunc(tm.Month().String()),
+		"Day":      tm.Day(),
+	})
+
+	body := a.NewEmailTemplate("post_batched_body", user.Locale)
+	body.Props["SiteURL"] = siteURL
+	body.Props["Posts"] = template.HTML(contents)
+	body.Props["BodyText"] = translateFunc("api.email_batching.send_batched_email_notification.body_text", len(posts))
+
+	// digests that only cover one team are sent under that team's email identity
+	teamId := entries[0].TeamId
+	for _, entry := range entries {
+		if entry.TeamId != teamId {
+			teamId = ""
+			break
+		}
+	}
+
+	if err := a.SendTeamTemplateMail(teamId, user.Email, subject, body); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *App) renderEmailDigestChannel(digestChannel *emailDigestChannel, user *model.User, siteURL string, translateFunc i18n.TranslateFunc, emailNotificationContentsType string) string {
+	var channel *model.Channel
+	if result := <-a.Srv.Store.Channel().Get(digestChannel.ChannelId, true); result.Err != nil {
+		mlog.Warn("Unable to find channel for email digest", mlog.String("channel_id", digestChannel.ChannelId))
+		return ""
+	} else {
+		channel = result.Data.(*model.Channel)
+	}
+
+	teamName := "select_team"
+	if digestChannel.TeamId != "" {
+		if result := <-a.Srv.Store.Team().Get(digestChannel.TeamId); result.Err == nil {
+			teamName = result.Data.(*model.Team).Name
+		}
+	}
+
+	displayNameFormat := *a.Config().TeamSettings.TeammateNameDisplay
+	senderNames := make(map[string]string)
+
+	threads := []*emailDigestThreadProps{}
+	for _, thread := range digestChannel.Threads {
+		threadProps := &emailDigestThreadProps{
+			Link: siteURL + "/" + teamName + "/pl/" + thread.Posts[len(thread.Posts)-1].Id,
+		}
+
+		for _, post := range thread.Posts {
+			senderName, ok := senderNames[post.UserId]
+			if !ok {
+				if result := <-a.Srv.Store.User().Get(post.UserId); result.Err != nil {
+					mlog.Warn("Unable to find sender of post for email digest", mlog.String("user_id", post.UserId))
+					continue
+				} else {
+					senderName = result.Data.(*model.User).GetDisplayName(displayNameFormat)
+				}
+				senderNames[post.UserId] = senderName
+			}
+
+			postProps := &emailDigestPostProps{
+				SenderName: senderName,
+				Date:       renderEmailDigestDate(post, user, translateFunc),
+			}
+
+			// don't include message contents if email notification contents type is set to generic
+			if emailNotificationContentsType == model.EMAIL_NOTIFICATION_CONTENTS_FULL {
+				postProps.Message = a.GetMessageForNotification(post, translateFunc)
+			}
+
+			threadProps.Posts = append(threadProps.Posts, postProps)
+		}
+
+		if len(threadProps.Posts) > 0 {
+			threads = append(threads, threadProps)
+		}
+	}
+
+	if len(threads) == 0 {
+		return ""
+	}
+
+	template := a.NewEmailTemplate("post_digest_channel", user.Locale)
+	template.Props["Threads"] = threads
+	template.Props["Button"] = translateFunc("api.email_digest.render_email_digest_channel.go_to_thread")
+
+	if channel.Type == model.CHANNEL_DIRECT {
+		template.Props["ChannelName"] = translateFunc("api.email_digest.render_email_digest_channel.direct_message")
+	} else if channel.Type == model.CHANNEL_GROUP {
+		template.Props["ChannelName"] = translateFunc("api.email_digest.render_email_digest_channel.group_message")
+	} else if emailNotificationContentsType == model.EMAIL_NOTIFICATION_CONTENTS_FULL {
+		template.Props["ChannelName"] = channel.DisplayName
+	} else {
+		// don't include channel name if email notification contents type is set to generic
+		template.Props["ChannelName"] = translateFunc("api.email_digest.render_email_digest_channel.channel")
+	}
+
+	return template.Render()
+}
+
+func renderEmailDigestDate(post *model.Post, user *model.User, translateFunc i18n.TranslateFunc) string {
+	tm := time.Unix(post.CreateAt/1000, 0)
+	if location, err := time.LoadLocation(user.GetPreferredTimezone()); err == nil {
+		tm = tm.In(location)
+	}
+	timezone, _ := tm.Zone()
+
+	return translateFunc("api.email_batching.render_batched_post.date", map[string]interface{}{
+		"Year":     tm.Year(),
+		"Month":    translateFunc(tm.Month().String()),
+		"Day":      tm.Day(),
+		"Hour":     tm.Hour(),
+		"Minute":   fmt.Sprintf("%02d", tm.Minute()),
+		"Timezone": timezone,
+	})
+}
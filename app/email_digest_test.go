@@ -0,0 +1,103 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestGroupEmailDigestPosts(t *testing.T) {
+	teamId := model.NewId()
+	channel1 := model.NewId()
+	channel2 := model.NewId()
+
+	root := &model.Post{Id: model.NewId(), ChannelId: channel1}
+	reply := &model.Post{Id: model.NewId(), ChannelId: channel1, RootId: root.Id}
+	other := &model.Post{Id: model.NewId(), ChannelId: channel1}
+	otherChannel := &model.Post{Id: model.NewId(), ChannelId: channel2}
+	replyWithoutRoot := &model.Post{Id: model.NewId(), ChannelId: channel2, RootId: model.NewId()}
+	deleted := &model.Post{Id: model.NewId(), ChannelId: channel2}
+
+	posts := map[string]*model.Post{}
+	entries := []*model.EmailDigestEntry{}
+	for _, post := range []*model.Post{root, otherChannel, other, reply, replyWithoutRoot, deleted} {
+		if post != deleted {
+			posts[post.Id] = post
+		}
+		entries = append(entries, &model.EmailDigestEntry{PostId: post.Id, ChannelId: post.ChannelId, TeamId: teamId})
+	}
+
+	channels := groupEmailDigestPosts(entries, posts)
+	require.Len(t, channels, 2)
+
+	assert.Equal(t, channel1, channels[0].ChannelId)
+	assert.Equal(t, teamId, channels[0].TeamId)
+	require.Len(t, channels[0].Threads, 2)
+	assert.Equal(t, root.Id, channels[0].Threads[0].RootId)
+	assert.Equal(t, []*model.Post{root, reply}, channels[0].Threads[0].Posts)
+	assert.Equal(t, []*model.Post{other}, channels[0].Threads[1].Posts)
+
+	assert.Equal(t, channel2, channels[1].ChannelId)
+	require.Len(t, channels[1].Threads, 2)
+	assert.Equal(t, []*model.Post{otherChannel}, channels[1].Threads[0].Posts)
+	assert.Equal(t, replyWithoutRoot.RootId, channels[1].Threads[1].RootId)
+}
+
+func TestNotificationEmailDuringQuietHours(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.EmailSettings.EnableEmailDigests = true
+	})
+
+	user := th.BasicUser2
+	user.NotifyProps[model.SCHEDULE_ENABLED_NOTIFY_PROP] = "true"
+	user.NotifyProps[model.SCHEDULE_DAYS_NOTIFY_PROP] = "sun,mon,tue,wed,thu,fri,sat"
+	user.NotifyProps[model.SCHEDULE_START_NOTIFY_PROP] = "00:00"
+	user.NotifyProps[model.SCHEDULE_END_NOTIFY_PROP] = "00:00"
+	user.NotifyProps[model.SCHEDULE_TIMEZONE_NOTIFY_PROP] = "UTC"
+	user, err := th.App.UpdateUser(user, false)
+	require.Nil(t, err)
+
+	post := th.CreatePost(th.BasicChannel)
+
+	t.Run("held back in the digest", func(t *testing.T) {
+		err := th.App.sendNotificationEmail(post, user, th.BasicChannel, th.BasicTeam, th.BasicChannel.DisplayName, th.BasicUser.Username, th.BasicUser)
+		require.Nil(t, err)
+
+		result := <-th.App.Srv.Store.EmailDigest().GetForUser(user.Id)
+		require.Nil(t, result.Err)
+		entries := result.Data.([]*model.EmailDigestEntry)
+		require.Len(t, entries, 1)
+		assert.Equal(t, post.Id, entries[0].PostId)
+
+		// The digest isn't sent until the quiet hours end
+		require.Nil(t, th.App.sendPendingEmailDigest(user.Id, time.Now().Add(48*time.Hour)))
+
+		result = <-th.App.Srv.Store.EmailDigest().GetForUser(user.Id)
+		require.Nil(t, result.Err)
+		assert.Len(t, result.Data.([]*model.EmailDigestEntry), 1)
+	})
+
+	t.Run("dropped without digests", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.EmailSettings.EnableEmailDigests = false
+		})
+
+		otherPost := th.CreatePost(th.BasicChannel)
+		err := th.App.sendNotificationEmail(otherPost, user, th.BasicChannel, th.BasicTeam, th.BasicChannel.DisplayName, th.BasicUser.Username, th.BasicUser)
+		require.Nil(t, err)
+
+		result := <-th.App.Srv.Store.EmailDigest().GetForUser(user.Id)
+		require.Nil(t, result.Err)
+		assert.Len(t, result.Data.([]*model.EmailDigestEntry), 1)
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	EMAIL_ASSETS_DIRECTORY = "assets"
+	EMAIL_LOGO_FILE        = "logo-email.png"
+)
+
+// EmailTemplatesDirectory returns the directory that email templates and branding assets are overridden from, or an
+// empty string if they aren't being overridden.
+func (a *App) EmailTemplatesDirectory() string {
+	directory := *a.Config().EmailSettings.EmailTemplatesDirectory
+	if directory == "" {
+		return ""
+	}
+
+	if found, ok := utils.FindDir(directory); ok {
+		return found
+	}
+
+	return ""
+}
+
+// EmailAssetsDirectory returns the directory that branding assets for emails are served from, or an empty string if
+// there isn't one.
+func (a *App) EmailAssetsDirectory() string {
+	if directory := a.EmailTemplatesDirectory(); directory != "" {
+		return filepath.Join(directory, EMAIL_ASSETS_DIRECTORY)
+	}
+
+	return ""
+}
+
+// loadEmailTemplateOverrides applies the configured email template overrides. Overrides that fail validation are
+// logged and ignored so that emails keep going out with the templates that were loaded before.
+func (a *App) loadEmailTemplateOverrides() {
+	if a.htmlTemplateWatcher == nil {
+		return
+	}
+
+	directory := a.EmailTemplatesDirectory()
+	if directory == "" && *a.Config().EmailSettings.EmailTemplatesDirectory != "" {
+		mlog.Error(fmt.Sprintf("Failed to find the email templates directory %v", *a.Config().EmailSettings.EmailTemplatesDirectory))
+	}
+
+	if err := a.htmlTemplateWatcher.SetOverridesDirectory(directory); err != nil {
+		mlog.Error(fmt.Sprintf("Failed to load the email templates from %v, err=%v", directory, err))
+	}
+}
+
+// emailLogoPath returns the path of the logo that emails link to, relative to the site URL.
+func (a *App) emailLogoPath() string {
+	if directory := a.EmailAssetsDirectory(); directory != "" {
+		if _, err := os.Stat(filepath.Join(directory, EMAIL_LOGO_FILE)); err == nil {
+			return "/static/email/" + EMAIL_LOGO_FILE
+		}
+	}
+
+	return "/static/images/" + EMAIL_LOGO_FILE
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestTeamEmailConfig(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.EmailSettings.FeedbackName = "Mattermost"
+	cfg.EmailSettings.FeedbackEmail = "feedback@example.com"
+	cfg.EmailSettings.SMTPUsername = "server"
+	cfg.EmailSettings.SMTPPassword = "serverpassword"
+
+	teamCfg := teamEmailConfig(cfg, &model.TeamEmailIdentity{FeedbackEmail: "notifications@acme.com"})
+	assert.Equal(t, "Mattermost", teamCfg.EmailSettings.FeedbackName)
+	assert.Equal(t, "notifications@acme.com", teamCfg.EmailSettings.FeedbackEmail)
+	assert.Equal(t, "server", teamCfg.EmailSettings.SMTPUsername)
+	assert.Equal(t, "serverpassword", teamCfg.EmailSettings.SMTPPassword)
+
+	teamCfg = teamEmailConfig(cfg, &model.TeamEmailIdentity{
+		FeedbackName:  "Acme",
+		FeedbackEmail: "notifications@acme.com",
+		SMTPUsername:  "acme",
+		SMTPPassword:  "acmepassword",
+	})
+	assert.Equal(t, "Acme", teamCfg.EmailSettings.FeedbackName)
+	assert.Equal(t, "acme", teamCfg.EmailSettings.SMTPUsername)
+	assert.Equal(t, "acmepassword", teamCfg.EmailSettings.SMTPPassword)
+	assert.True(t, *teamCfg.EmailSettings.EnableSMTPAuth)
+
+	assert.Equal(t, "Mattermost", cfg.EmailSettings.FeedbackName, "shouldn't change the server's config")
+	assert.Equal(t, "server", cfg.EmailSettings.SMTPUsername, "shouldn't change the server's config")
+	assert.False(t, *cfg.EmailSettings.EnableSMTPAuth, "shouldn't change the server's config")
+}
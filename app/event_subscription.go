@@ -0,0 +1,195 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (a *App) checkEventSubscriptionsEnabled(where string) *model.AppError {
+	if !a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return model.NewAppError(where, "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+// checkEventSubscriptionChannel checks that a subscription to posts is for a channel in the subscription's team.
+func (a *App) checkEventSubscriptionChannel(where string, subscription *model.EventSubscription) *model.AppError {
+	if len(subscription.ChannelId) == 0 {
+		return nil
+	}
+
+	channel, err := a.GetChannel(subscription.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	if channel.TeamId != subscription.TeamId {
+		return model.NewAppError(where, "api.event_subscription.channel_team_mismatch.app_error", nil, "channel_id="+channel.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (a *App) CreateEventSubscription(subscription *model.EventSubscription) (*model.EventSubscription, *model.AppError) {
+	if err := a.checkEventSubscriptionsEnabled("CreateEventSubscription"); err != nil {
+		return nil, err
+	}
+
+	if err := a.checkEventSubscriptionChannel("CreateEventSubscription", subscription); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.EventSubscription().Save(subscription); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.EventSubscription), nil
+	}
+}
+
+func (a *App) GetEventSubscription(subscriptionId string) (*model.EventSubscription, *model.AppError) {
+	if err := a.checkEventSubscriptionsEnabled("GetEventSubscription"); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.EventSubscription().Get(subscriptionId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.EventSubscription), nil
+	}
+}
+
+func (a *App) GetEventSubscriptionsForTeamPage(teamId string, page int, perPage int) ([]*model.EventSubscription, *model.AppError) {
+	if err := a.checkEventSubscriptionsEnabled("GetEventSubscriptionsForTeamPage"); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.EventSubscription().GetByTeam(teamId, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.EventSubscription), nil
+	}
+}
+
+func (a *App) PatchEventSubscription(oldSubscription *model.EventSubscription, patch *model.EventSubscriptionPatch) (*model.EventSubscription, *model.AppError) {
+	if err := a.checkEventSubscriptionsEnabled("PatchEventSubscription"); err != nil {
+		return nil, err
+	}
+
+	subscription := &model.EventSubscription{}
+	*subscription = *oldSubscription
+	subscription.Patch(patch)
+
+	if result := <-a.Srv.Store.EventSubscription().Update(subscription); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.EventSubscription), nil
+	}
+}
+
+// RegenEventSubscriptionSigningSecret replaces the secret that deliveries to a subscription are signed with.
+func (a *App) RegenEventSubscriptionSigningSecret(subscription *model.EventSubscription) (*model.EventSubscription, *model.AppError) {
+	if err := a.checkEventSubscriptionsEnabled("RegenEventSubscriptionSigningSecret"); err != nil {
+		return nil, err
+	}
+
+	subscription.SigningSecret = model.NewOutgoingWebhookSigningSecret()
+
+	if result := <-a.Srv.Store.EventSubscription().Update(subscription); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.EventSubscription), nil
+	}
+}
+
+func (a *App) DeleteEventSubscription(subscriptionId string) *model.AppError {
+	if err := a.checkEventSubscriptionsEnabled("DeleteEventSubscription"); err != nil {
+		return err
+	}
+
+	if result := <-a.Srv.Store.EventSubscription().Delete(subscriptionId, model.GetMillis()); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// sendEventSubscriptionEvent sends an event to the subscriptions for it in the background. The payload's channel is
+// only used to find subscriptions to posts.
+func (a *App) sendEventSubscriptionEvent(payload *model.EventSubscriptionPayload) {
+	if !a.Config().ServiceSettings.EnableOutgoingWebhooks || len(payload.TeamId) == 0 {
+		return
+	}
+
+	channelId := ""
+	if payload.Event == model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED {
+		channelId = payload.ChannelId
+	}
+
+	a.Go(func() {
+		result := <-a.Srv.Store.EventSubscription().GetActiveForEvent(payload.Event, payload.TeamId, channelId)
+		if result.Err != nil {
+			mlog.Error("Unable to get the subscriptions for an event", mlog.String("event", payload.Event), mlog.String("team_id", payload.TeamId), mlog.Err(result.Err))
+			return
+		}
+
+		for _, subscription := range result.Data.([]*model.EventSubscription) {
+			subscriptionPayload := *payload
+			subscriptionPayload.SubscriptionId = subscription.Id
+
+			a.deliverEventSubscriptionEvent(subscription, &subscriptionPayload)
+		}
+	})
+}
+
+// deliverEventSubscriptionEvent sends a signed payload to a subscription's callback URL and records whether it was
+// received. Deliveries aren't retried, but the subscription is deactivated once too many of them fail in a row.
+func (a *App) deliverEventSubscriptionEvent(subscription *model.EventSubscription, payload *model.EventSubscriptionPayload) {
+	url := subscription.CallbackURL
+	body := []byte(payload.ToJson())
+
+	if !a.allowIntegrationRequest(url) {
+		a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_EVENT_SUBSCRIPTION, subscription.Id, url, "application/json", body, 0, 0, errIntegrationCircuitOpen.Error())
+		return
+	}
+
+	timestamp := time.Now().Unix()
+
+	req, _ := http.NewRequest("POST", url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.HEADER_OUTGOING_HOOK_DELIVERY, model.NewId())
+	req.Header.Set(model.HEADER_OUTGOING_HOOK_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(model.HEADER_OUTGOING_HOOK_SIGNATURE, model.SignOutgoingWebhookPayload(subscription.SigningSecret, timestamp, body))
+
+	start := time.Now()
+	resp, err := a.HTTPClient(false).Do(req)
+	a.recordIntegrationResponse(url, resp, err)
+
+	succeeded := false
+	if err != nil {
+		a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_EVENT_SUBSCRIPTION, subscription.Id, url, "application/json", body, 0, time.Since(start), err.Error())
+	} else {
+		consumeAndClose(resp)
+		a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_EVENT_SUBSCRIPTION, subscription.Id, url, "application/json", body, resp.StatusCode, time.Since(start), "")
+		succeeded = resp.StatusCode < http.StatusBadRequest
+	}
+
+	if succeeded && subscription.Failures == 0 {
+		return
+	}
+
+	result := <-a.Srv.Store.EventSubscription().RecordDeliveryResult(subscription.Id, succeeded)
+	if result.Err != nil {
+		mlog.Error("Unable to record the result of an event subscription delivery", mlog.String("subscription_id", subscription.Id), mlog.Err(result.Err))
+	} else if result.Data.(bool) {
+		mlog.Warn("Deactivated an event subscription after too many failed deliveries", mlog.String("subscription_id", subscription.Id), mlog.String("url", url))
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"sync"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// eventThrottle limits how many events with the same key are published in each interval. It's local to this server,
+// so it only limits the events that are sent by it. The zero value is ready to use.
+type eventThrottle struct {
+	lock      sync.Mutex
+	windows   map[string]*eventThrottleWindow
+	lastSweep int64
+}
+
+type eventThrottleWindow struct {
+	start int64
+	count int
+}
+
+// allow returns true if fewer than max events with the given key have been allowed in the current interval, and counts
+// the event if so. Intervals are interval milliseconds long and start with the first event that's allowed after the
+// previous one ended.
+func (t *eventThrottle) allow(key string, max int, interval int64) bool {
+	now := model.GetMillis()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.windows == nil {
+		t.windows = make(map[string]*eventThrottleWindow)
+	}
+
+	// Intervals that have ended are forgotten every so often so that keys that aren't used again don't build up
+	if now-t.lastSweep >= interval {
+		for k, window := range t.windows {
+			if now-window.start >= interval {
+				delete(t.windows, k)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	window, ok := t.windows[key]
+	if !ok || now-window.start >= interval {
+		t.windows[key] = &eventThrottleWindow{start: now, count: 1}
+		return true
+	}
+
+	if window.count >= max {
+		return false
+	}
+
+	window.count++
+	return true
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventThrottle(t *testing.T) {
+	var throttle eventThrottle
+
+	assert.True(t, throttle.allow("a", 2, 100))
+	assert.True(t, throttle.allow("a", 2, 100))
+	assert.False(t, throttle.allow("a", 2, 100))
+	assert.True(t, throttle.allow("b", 2, 100), "should count keys separately")
+
+	time.Sleep(150 * time.Millisecond)
+
+	assert.True(t, throttle.allow("a", 2, 100), "should allow events again once the interval has passed")
+	assert.Len(t, throttle.windows, 1, "should forget intervals that have ended")
+}
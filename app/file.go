@@ -401,6 +401,10 @@ func (a *App) DoUploadFileExpectModification(now time.Time, rawTeamId string, ra
 	channelId := filepath.Base(rawChannelId)
 	userId := filepath.Base(rawUserId)
 
+	if err := a.CheckTeamUploadPolicy(channelId, filename, data); err != nil {
+		return nil, data, err
+	}
+
 	info, err := model.GetInfoForBytes(filename, data)
 	if err != nil {
 		err.StatusCode = http.StatusBadRequest
@@ -420,7 +424,6 @@ func (a *App) DoUploadFileExpectModification(now time.Time, rawTeamId string, ra
 	info.CreateAt = now.UnixNano() / int64(time.Millisecond)
 
 	pathPrefix := now.Format("20060102") + "/teams/" + teamId + "/channels/" + channelId + "/users/" + userId + "/" + info.Id + "/"
-	info.Path = pathPrefix + filename
 
 	if info.IsImage() {
 		// Check dimensions before loading the whole thing into memory later on
@@ -429,11 +432,32 @@ func (a *App) DoUploadFileExpectModification(now time.Time, rawTeamId string, ra
 			return nil, data, err
 		}
 
+		if optimizedData, optimizedFilename := optimizeImage(&a.Config().FileSettings, filename, data); optimizedData != nil {
+			if *a.Config().FileSettings.PreserveOriginalImages {
+				if _, err := a.WriteFile(bytes.NewReader(data), pathPrefix+"original/"+filename); err != nil {
+					return nil, data, err
+				}
+			}
+
+			optimizedInfo, _ := model.GetInfoForBytes(optimizedFilename, optimizedData)
+			info.Name = optimizedInfo.Name
+			info.Extension = optimizedInfo.Extension
+			info.MimeType = optimizedInfo.MimeType
+			info.Size = optimizedInfo.Size
+			info.Width = optimizedInfo.Width
+			info.Height = optimizedInfo.Height
+
+			filename = optimizedFilename
+			data = optimizedData
+		}
+
 		nameWithoutExtension := filename[:strings.LastIndex(filename, ".")]
 		info.PreviewPath = pathPrefix + nameWithoutExtension + "_preview.jpg"
 		info.ThumbnailPath = pathPrefix + nameWithoutExtension + "_thumb.jpg"
 	}
 
+	info.Path = pathPrefix + filename
+
 	if a.PluginsReady() {
 		var rejectionError *model.AppError
 		pluginContext := &plugin.Context{}
@@ -450,6 +474,7 @@ func (a *App) DoUploadFileExpectModification(now time.Time, rawTeamId string, ra
 			if newBytes.Len() != 0 {
 				data = newBytes.Bytes()
 				info.Size = int64(len(data))
+				refreshImageInfo(info, data)
 			}
 
 			return true
@@ -470,6 +495,20 @@ func (a *App) DoUploadFileExpectModification(now time.Time, rawTeamId string, ra
 	return info, data, nil
 }
 
+// refreshImageInfo updates the dimensions of an image after its contents have been replaced so that its preview and
+// thumbnail match the new image.
+func refreshImageInfo(info *model.FileInfo, data []byte) {
+	if !info.IsImage() {
+		return
+	}
+
+	if replacementInfo, _ := model.GetInfoForBytes(info.Name, data); replacementInfo != nil {
+		info.Width = replacementInfo.Width
+		info.Height = replacementInfo.Height
+		info.HasPreviewImage = replacementInfo.HasPreviewImage
+	}
+}
+
 func (a *App) HandleImages(previewPathList []string, thumbnailPathList []string, fileData [][]byte) {
 	wg := new(sync.WaitGroup)
 
@@ -612,6 +651,14 @@ func (a *App) GetFileInfo(fileId string) (*model.FileInfo, *model.AppError) {
 	}
 }
 
+func (a *App) GetFileInfosByIds(fileIds []string) ([]*model.FileInfo, *model.AppError) {
+	if result := <-a.Srv.Store.FileInfo().GetByIds(fileIds); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.FileInfo), nil
+	}
+}
+
 func (a *App) CopyFileInfos(userId string, fileIds []string) ([]string, *model.AppError) {
 	var newFileIds []string
 
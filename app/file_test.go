@@ -4,7 +4,10 @@
 package app
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"testing"
@@ -107,6 +110,28 @@ func TestDoUploadFile(t *testing.T) {
 	}
 }
 
+func TestRefreshImageInfo(t *testing.T) {
+	encode := func(width, height int) []byte {
+		var buf bytes.Buffer
+		require.Nil(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+		return buf.Bytes()
+	}
+
+	info, err := model.GetInfoForBytes("image.png", encode(10, 20))
+	require.Nil(t, err)
+
+	refreshImageInfo(info, encode(30, 40))
+	assert.Equal(t, 30, info.Width)
+	assert.Equal(t, 40, info.Height)
+	assert.True(t, info.HasPreviewImage)
+
+	info, err = model.GetInfoForBytes("file.txt", []byte("text"))
+	require.Nil(t, err)
+
+	refreshImageInfo(info, encode(30, 40))
+	assert.Equal(t, 0, info.Width, "should only refresh images")
+}
+
 func TestGetInfoForFilename(t *testing.T) {
 	th := Setup().InitBasic()
 	defer th.TearDown()
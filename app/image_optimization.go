@@ -0,0 +1,94 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// optimizeImage re-encodes an uploaded image so that it takes up less space, converting opaque PNGs such as screenshots
+// to JPEG and scaling down images that are larger than the maximum dimension. It returns the new image data and file
+// name, or nil if the image should be stored as it was uploaded.
+func optimizeImage(settings *model.FileSettings, filename string, data []byte) ([]byte, string) {
+	if !*settings.EnableImageOptimization {
+		return nil, ""
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, ""
+	}
+
+	maxDimension := *settings.ImageOptimizationMaxDimension
+	needsResize := maxDimension > 0 && (config.Width > maxDimension || config.Height > maxDimension)
+
+	if !needsResize && int64(len(data)) < *settings.ImageOptimizationMinimumSize {
+		return nil, ""
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to decode image for optimization err=%v", err))
+		return nil, ""
+	}
+
+	if format == "jpeg" {
+		// The EXIF data is lost when the image is encoded again, so it needs to be turned the right way up first
+		orientation, _ := getImageOrientation(bytes.NewReader(data))
+		img = makeImageUpright(img, orientation)
+	}
+
+	if needsResize {
+		img = imaging.Fit(img, maxDimension, maxDimension, imaging.Lanczos)
+	}
+
+	buf := new(bytes.Buffer)
+	if format == "jpeg" || isOpaqueImage(img) {
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: *settings.ImageOptimizationJpegQuality})
+		format = "jpeg"
+	} else {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(buf, img)
+	}
+
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to encode optimized image err=%v", err))
+		return nil, ""
+	}
+
+	// Keep the original if re-encoding it didn't help and it was already small enough
+	if !needsResize && buf.Len() >= len(data) {
+		return nil, ""
+	}
+
+	if format == "jpeg" {
+		extension := strings.ToLower(filepath.Ext(filename))
+		if extension != ".jpg" && extension != ".jpeg" {
+			filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".jpg"
+		}
+	}
+
+	return buf.Bytes(), filename
+}
+
+func isOpaqueImage(img image.Image) bool {
+	if opaque, ok := img.(interface {
+		Opaque() bool
+	}); ok {
+		return opaque.Opaque()
+	}
+
+	return false
+}
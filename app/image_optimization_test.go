@@ -0,0 +1,84 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func makeTestPNG(t *testing.T, width, height int, alpha uint8) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * y), G: uint8(x + y), B: uint8(x ^ y), A: alpha})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	require.Nil(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func TestOptimizeImage(t *testing.T) {
+	settings := &model.FileSettings{}
+	settings.SetDefaults()
+	settings.ImageOptimizationMinimumSize = model.NewInt64(0)
+	settings.ImageOptimizationMaxDimension = model.NewInt(200)
+
+	screenshot := makeTestPNG(t, 300, 150, 255)
+
+	t.Run("disabled", func(t *testing.T) {
+		data, _ := optimizeImage(settings, "screenshot.png", screenshot)
+		assert.Nil(t, data)
+	})
+
+	settings.EnableImageOptimization = model.NewBool(true)
+
+	t.Run("opaque png", func(t *testing.T) {
+		data, filename := optimizeImage(settings, "screenshot.png", screenshot)
+		require.NotNil(t, data)
+		assert.Equal(t, "screenshot.jpg", filename)
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.Nil(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, 200, config.Width)
+		assert.Equal(t, 100, config.Height)
+	})
+
+	t.Run("transparent png", func(t *testing.T) {
+		data, filename := optimizeImage(settings, "logo.png", makeTestPNG(t, 300, 150, 128))
+		require.NotNil(t, data)
+		assert.Equal(t, "logo.png", filename)
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.Nil(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 200, config.Width)
+	})
+
+	t.Run("smaller than the minimum size", func(t *testing.T) {
+		settings.ImageOptimizationMinimumSize = model.NewInt64(int64(len(screenshot)) + 1)
+		settings.ImageOptimizationMaxDimension = model.NewInt(0)
+
+		data, _ := optimizeImage(settings, "screenshot.png", screenshot)
+		assert.Nil(t, data)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		settings.ImageOptimizationMinimumSize = model.NewInt64(0)
+
+		data, _ := optimizeImage(settings, "file.png", []byte("not an image"))
+		assert.Nil(t, data)
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	INACTIVE_USER_BATCH_SIZE = 200
+)
+
+// GetInactiveUserActions returns what the inactive user job would do if it ran at the given time, without doing it.
+func (a *App) GetInactiveUserActions(now int64) ([]*model.InactiveUserAction, *model.AppError) {
+	_, actions, err := a.getInactiveUserActions(now)
+	return actions, err
+}
+
+func (a *App) getInactiveUserActions(now int64) ([]*model.User, []*model.InactiveUserAction, *model.AppError) {
+	settings := a.Config().InactiveUserSettings
+	lastActivityBefore := now - int64(*settings.InactiveDays)*model.INACTIVE_USER_DAY_MILLISECONDS
+
+	users := []*model.User{}
+	actions := []*model.InactiveUserAction{}
+
+	for offset := 0; ; offset += INACTIVE_USER_BATCH_SIZE {
+		var page []*model.User
+		if result := <-a.Srv.Store.User().GetInactive(lastActivityBefore, offset, INACTIVE_USER_BATCH_SIZE); result.Err != nil {
+			return nil, nil, result.Err
+		} else {
+			page = result.Data.([]*model.User)
+		}
+
+		for _, user := range page {
+			flaggedAt := a.getInactiveUserFlaggedAt(user.Id)
+
+			if action := settings.GetInactiveUserAction(user, flaggedAt, now); action != "" {
+				users = append(users, user)
+				actions = append(actions, &model.InactiveUserAction{
+					UserId:         user.Id,
+					Username:       user.Username,
+					Email:          user.Email,
+					LastActivityAt: user.LastActivityAt,
+					FlaggedAt:      flaggedAt,
+					Action:         action,
+				})
+			}
+		}
+
+		if len(page) < INACTIVE_USER_BATCH_SIZE {
+			break
+		}
+	}
+
+	return users, actions, nil
+}
+
+func (a *App) getInactiveUserFlaggedAt(userId string) int64 {
+	result := <-a.Srv.Store.Preference().Get(userId, model.PREFERENCE_CATEGORY_USER_LIFECYCLE, model.PREFERENCE_NAME_INACTIVE_FLAGGED_AT)
+	if result.Err != nil {
+		return 0
+	}
+
+	flaggedAt, err := strconv.ParseInt(result.Data.(model.Preference).Value, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return flaggedAt
+}
+
+// ApplyInactiveUserPolicy flags users who have been inactive for longer than the configured number of days, notifying
+// them if configured to, and deactivates flagged users whose grace period has run out.
+func (a *App) ApplyInactiveUserPolicy(now int64) *model.AppError {
+	users, actions, err := a.getInactiveUserActions(now)
+	if err != nil {
+		return err
+	}
+
+	for i, user := range users {
+		switch actions[i].Action {
+		case model.INACTIVE_USER_ACTION_FLAG:
+			if err := a.flagInactiveUser(user, now); err != nil {
+				mlog.Error(fmt.Sprintf("Unable to flag inactive user err=%v", err), mlog.String("user_id", user.Id))
+			}
+		case model.INACTIVE_USER_ACTION_DEACTIVATE:
+			if err := a.deactivateInactiveUser(user); err != nil {
+				mlog.Error(fmt.Sprintf("Unable to deactivate inactive user err=%v", err), mlog.String("user_id", user.Id))
+			}
+		}
+	}
+
+	mlog.Info(fmt.Sprintf("Inactive user policy applied to %v user(s).", len(users)))
+
+	return nil
+}
+
+func (a *App) flagInactiveUser(user *model.User, now int64) *model.AppError {
+	preference := model.Preference{
+		UserId:   user.Id,
+		Category: model.PREFERENCE_CATEGORY_USER_LIFECYCLE,
+		Name:     model.PREFERENCE_NAME_INACTIVE_FLAGGED_AT,
+		Value:    strconv.FormatInt(now, 10),
+	}
+
+	if result := <-a.Srv.Store.Preference().Save(&model.Preferences{preference}); result.Err != nil {
+		return result.Err
+	}
+
+	if *a.Config().InactiveUserSettings.NotifyInactiveUsers && a.Config().EmailSettings.SendEmailNotifications {
+		a.Go(func() {
+			if err := a.SendInactiveUserEmail(user, now); err != nil {
+				mlog.Error(err.Error())
+			}
+		})
+	}
+
+	return nil
+}
+
+func (a *App) deactivateInactiveUser(user *model.User) *model.AppError {
+	if _, err := a.UpdateActive(user, false); err != nil {
+		return err
+	}
+
+	// clear the flag so that the user gets a fresh grace period if they're reactivated
+	if result := <-a.Srv.Store.Preference().Delete(user.Id, model.PREFERENCE_CATEGORY_USER_LIFECYCLE, model.PREFERENCE_NAME_INACTIVE_FLAGGED_AT); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+func (a *App) SendInactiveUserEmail(user *model.User, flaggedAt int64) *model.AppError {
+	T := utils.GetUserTranslations(user.Locale)
+	settings := a.Config().InactiveUserSettings
+	siteURL := a.GetSiteURL()
+
+	rawUrl, _ := url.Parse(siteURL)
+
+	subject := T("api.templates.inactive_user_subject",
+		map[string]interface{}{"SiteName": a.ClientConfig()["SiteName"],
+			"ServerURL": rawUrl.Host})
+
+	bodyPage := a.NewEmailTemplate("inactive_user_body", user.Locale)
+	bodyPage.Props["SiteURL"] = siteURL
+	bodyPage.Props["Title"] = T("api.templates.inactive_user_body.title", map[string]interface{}{"ServerURL": rawUrl.Host})
+	bodyPage.Props["Info"] = T("api.templates.inactive_user_body.info", map[string]interface{}{"Days": *settings.InactiveDays})
+	bodyPage.Props["Button"] = T("api.templates.inactive_user_body.button")
+
+	if *settings.EnableAutoDeactivation {
+		deactivateAt := time.Unix(0, (flaggedAt+int64(*settings.DeactivationGracePeriodDays)*model.INACTIVE_USER_DAY_MILLISECONDS)*int64(time.Millisecond))
+		bodyPage.Props["Warning"] = T("api.templates.inactive_user_body.warning", map[string]interface{}{
+			"Year":  deactivateAt.Year(),
+			"Month": T(deactivateAt.Month().String()),
+			"Day":   deactivateAt.Day(),
+		})
+	}
+
+	if err := a.SendTemplateMail(user.Email, subject, bodyPage); err != nil {
+		return model.NewAppError("SendInactiveUserEmail", "api.user.send_inactive_user_email.failed.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
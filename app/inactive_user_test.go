@@ -0,0 +1,67 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func findInactiveUserAction(actions []*model.InactiveUserAction, userId string) *model.InactiveUserAction {
+	for _, action := range actions {
+		if action.UserId == userId {
+			return action
+		}
+	}
+
+	return nil
+}
+
+func TestApplyInactiveUserPolicy(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.InactiveUserSettings.InactiveDays = 30
+		*cfg.InactiveUserSettings.NotifyInactiveUsers = false
+		*cfg.InactiveUserSettings.EnableAutoDeactivation = true
+		*cfg.InactiveUserSettings.DeactivationGracePeriodDays = 7
+		cfg.InactiveUserSettings.ExcludedUsernames = []string{th.BasicUser2.Username}
+	})
+
+	day := int64(model.INACTIVE_USER_DAY_MILLISECONDS)
+	now := model.GetMillis() + 31*day
+
+	actions, err := th.App.GetInactiveUserActions(now)
+	require.Nil(t, err)
+	action := findInactiveUserAction(actions, th.BasicUser.Id)
+	require.NotNil(t, action)
+	assert.Equal(t, model.INACTIVE_USER_ACTION_FLAG, action.Action)
+	assert.Nil(t, findInactiveUserAction(actions, th.BasicUser2.Id), "excluded users should not be affected")
+
+	require.Nil(t, th.App.ApplyInactiveUserPolicy(now))
+	assert.Equal(t, now, th.App.getInactiveUserFlaggedAt(th.BasicUser.Id))
+
+	actions, err = th.App.GetInactiveUserActions(now + day)
+	require.Nil(t, err)
+	assert.Nil(t, findInactiveUserAction(actions, th.BasicUser.Id), "flagged users should wait out the grace period")
+
+	now += 7 * day
+	actions, err = th.App.GetInactiveUserActions(now)
+	require.Nil(t, err)
+	action = findInactiveUserAction(actions, th.BasicUser.Id)
+	require.NotNil(t, action)
+	assert.Equal(t, model.INACTIVE_USER_ACTION_DEACTIVATE, action.Action)
+
+	require.Nil(t, th.App.ApplyInactiveUserPolicy(now))
+
+	user, err := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.NotEqual(t, int64(0), user.DeleteAt)
+	assert.Equal(t, int64(0), th.App.getInactiveUserFlaggedAt(th.BasicUser.Id))
+}
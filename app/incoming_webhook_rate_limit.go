@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/throttled/throttled"
+	"github.com/throttled/throttled/store/memstore"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// The most incoming webhooks that rate limits are tracked for at once. The least recently used are forgotten first.
+const INCOMING_WEBHOOK_RATE_LIMIT_MEMSTORE_SIZE = 65536
+
+// getIncomingWebhookRateLimiter returns a rate limiter for the given quota. Hooks with the same quota share a limiter,
+// and they all share a store that tracks each hook separately by its id.
+func (a *App) getIncomingWebhookRateLimiter(perMinute int, burst int) (*throttled.GCRARateLimiter, error) {
+	a.incomingWebhookRateLimitLock.Lock()
+	defer a.incomingWebhookRateLimitLock.Unlock()
+
+	if a.incomingWebhookRateLimitStore == nil {
+		store, err := memstore.New(INCOMING_WEBHOOK_RATE_LIMIT_MEMSTORE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+
+		a.incomingWebhookRateLimitStore = store
+		a.incomingWebhookRateLimiters = make(map[string]*throttled.GCRARateLimiter)
+	}
+
+	key := fmt.Sprintf("%v:%v", perMinute, burst)
+	if rateLimiter, ok := a.incomingWebhookRateLimiters[key]; ok {
+		return rateLimiter, nil
+	}
+
+	quota := throttled.RateQuota{
+		MaxRate:  throttled.PerMin(perMinute),
+		MaxBurst: burst,
+	}
+
+	rateLimiter, err := throttled.NewGCRARateLimiter(a.incomingWebhookRateLimitStore, quota)
+	if err != nil {
+		return nil, err
+	}
+
+	a.incomingWebhookRateLimiters[key] = rateLimiter
+	return rateLimiter, nil
+}
+
+// CheckIncomingWebhookRateLimit counts a request to an incoming webhook, returning an error if the hook has made more
+// requests than it's allowed to. Requests are let through if the rate limiter isn't working so that a problem with it
+// doesn't break every integration.
+func (a *App) CheckIncomingWebhookRateLimit(hook *model.IncomingWebhook) *model.AppError {
+	perMinute, burst := hook.GetRateLimit(*a.Config().ServiceSettings.IncomingWebhookRateLimitPerMinute, *a.Config().ServiceSettings.IncomingWebhookRateLimitBurst)
+	if perMinute == 0 {
+		return nil
+	}
+
+	rateLimiter, err := a.getIncomingWebhookRateLimiter(perMinute, burst)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to rate limit incoming webhook err=%v", err.Error()), mlog.String("hook_id", hook.Id))
+		return nil
+	}
+
+	limited, result, err := rateLimiter.RateLimit(hook.Id, 1)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to rate limit incoming webhook err=%v", err.Error()), mlog.String("hook_id", hook.Id))
+		return nil
+	}
+
+	if limited {
+		retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+		return model.NewAppError("CheckIncomingWebhookRateLimit", "web.incoming_webhook.rate_limited.app_error", map[string]interface{}{"RetryAfter": retryAfter}, fmt.Sprintf("hook_id=%v retry_after=%v", hook.Id, retryAfter), http.StatusTooManyRequests)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+)
+
+// The number of requests in a row that have to fail before requests to an integration's URL are stopped
+const INTEGRATION_CIRCUIT_FAILURE_THRESHOLD = 5
+
+// How long requests to a failing integration's URL are stopped for before one is let through to check on it
+var integrationCircuitOpenDuration = 30 * time.Second
+
+var errIntegrationCircuitOpen = errors.New("requests to this URL are stopped for now because it keeps failing")
+
+// integrationCircuit is a circuit breaker for one of the URLs that the server sends requests to for integrations. The
+// circuit opens when requests keep failing so that posts and commands don't wait on an integration that's down. Once
+// it's been open for a while, a single request is let through as a probe, and the circuit closes again if it succeeds.
+type integrationCircuit struct {
+	mutex    sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (c *integrationCircuit) isOpen() bool {
+	return c.failures >= INTEGRATION_CIRCUIT_FAILURE_THRESHOLD
+}
+
+// allow returns true if a request can be sent, and marks it as the probe if the circuit is half open.
+func (c *integrationCircuit) allow(now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen() {
+		return true
+	}
+
+	if now.Sub(c.openedAt) < integrationCircuitOpenDuration || c.probing {
+		return false
+	}
+
+	c.probing = true
+	return true
+}
+
+// record counts the result of a request that was allowed, and returns true if the request opened the circuit.
+func (c *integrationCircuit) record(succeeded bool, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	wasOpen := c.isOpen()
+	c.probing = false
+
+	if succeeded {
+		c.failures = 0
+		return false
+	}
+
+	c.failures++
+	if c.isOpen() {
+		// A failed probe keeps the circuit open for another full period
+		c.openedAt = now
+	}
+
+	return !wasOpen && c.isOpen()
+}
+
+func (a *App) getIntegrationCircuit(url string) *integrationCircuit {
+	circuit, _ := a.integrationCircuits.LoadOrStore(url, &integrationCircuit{})
+	return circuit.(*integrationCircuit)
+}
+
+// allowIntegrationRequest returns false if requests to a URL keep failing and shouldn't be sent for now. Requests that
+// are allowed must have their result recorded with recordIntegrationResponse.
+func (a *App) allowIntegrationRequest(url string) bool {
+	return a.getIntegrationCircuit(url).allow(time.Now())
+}
+
+// recordIntegrationResponse records the outcome of a request to an integration's URL. Requests that couldn't be sent
+// or that got a server error count towards stopping requests to the URL.
+func (a *App) recordIntegrationResponse(url string, resp *http.Response, err error) {
+	succeeded := err == nil && resp.StatusCode < http.StatusInternalServerError
+
+	if opened := a.getIntegrationCircuit(url).record(succeeded, time.Now()); opened {
+		mlog.Warn("Stopped sending requests to an integration that keeps failing", mlog.String("url", url), mlog.Int("failures", INTEGRATION_CIRCUIT_FAILURE_THRESHOLD))
+	}
+}
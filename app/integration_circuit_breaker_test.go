@@ -0,0 +1,61 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegrationCircuit(t *testing.T) {
+	now := time.Now()
+
+	t.Run("opens after too many failures in a row", func(t *testing.T) {
+		circuit := &integrationCircuit{}
+
+		for i := 0; i < INTEGRATION_CIRCUIT_FAILURE_THRESHOLD-1; i++ {
+			assert.True(t, circuit.allow(now))
+			assert.False(t, circuit.record(false, now))
+		}
+
+		assert.True(t, circuit.allow(now))
+		assert.True(t, circuit.record(false, now), "should report that the circuit opened")
+		assert.False(t, circuit.allow(now))
+		assert.False(t, circuit.allow(now.Add(integrationCircuitOpenDuration/2)))
+	})
+
+	t.Run("a success resets the failures", func(t *testing.T) {
+		circuit := &integrationCircuit{}
+
+		for i := 0; i < INTEGRATION_CIRCUIT_FAILURE_THRESHOLD-1; i++ {
+			circuit.record(false, now)
+		}
+		circuit.record(true, now)
+		circuit.record(false, now)
+
+		assert.True(t, circuit.allow(now))
+	})
+
+	t.Run("lets a single probe through once it's been open for a while", func(t *testing.T) {
+		circuit := &integrationCircuit{}
+		for i := 0; i < INTEGRATION_CIRCUIT_FAILURE_THRESHOLD; i++ {
+			circuit.record(false, now)
+		}
+
+		later := now.Add(integrationCircuitOpenDuration)
+		assert.True(t, circuit.allow(later))
+		assert.False(t, circuit.allow(later), "should only allow one probe at a time")
+
+		assert.False(t, circuit.record(false, later), "shouldn't report a failed probe as opening the circuit")
+		assert.False(t, circuit.allow(later.Add(integrationCircuitOpenDuration/2)), "should stay open for another period after a failed probe")
+
+		evenLater := later.Add(integrationCircuitOpenDuration)
+		assert.True(t, circuit.allow(evenLater))
+		circuit.record(true, evenLater)
+		assert.True(t, circuit.allow(evenLater))
+		assert.True(t, circuit.allow(evenLater), "should close after a successful probe")
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// The most deliveries that are kept in the integration delivery log. Older ones are deleted periodically.
+const INTEGRATION_DELIVERY_LOG_SIZE = 10000
+
+// RecordIntegrationDelivery adds a request that was sent to or received from an integration to the delivery log. The
+// request's body is sanitized so that credentials aren't recorded, and it's saved in the background so that
+// integrations aren't slowed down by the log.
+func (a *App) RecordIntegrationDelivery(integrationType string, integrationId string, url string, contentType string, body []byte, statusCode int, elapsed time.Duration, err string) {
+	delivery := &model.IntegrationDelivery{
+		IntegrationType: integrationType,
+		IntegrationId:   integrationId,
+		URL:             url,
+		Request:         model.SanitizeDebugBody(contentType, body),
+		StatusCode:      statusCode,
+		Latency:         int64(elapsed / time.Millisecond),
+		Error:           err,
+	}
+
+	a.Go(func() {
+		if result := <-a.Srv.Store.IntegrationDelivery().Save(delivery); result.Err != nil {
+			mlog.Error("Unable to record an integration delivery", mlog.String("integration_type", integrationType), mlog.String("integration_id", integrationId), mlog.Err(result.Err))
+		}
+	})
+}
+
+// GetIntegrationDeliveries returns a page of the integration delivery log, newest first, optionally limited to a type
+// of integration or to a single integration.
+func (a *App) GetIntegrationDeliveries(integrationType string, integrationId string, page int, perPage int) ([]*model.IntegrationDelivery, *model.AppError) {
+	if integrationType != "" && !model.IsValidIntegrationDeliveryType(integrationType) {
+		return nil, model.NewAppError("GetIntegrationDeliveries", "app.integration_delivery.get_deliveries.integration_type.app_error", nil, "integration_type="+integrationType, http.StatusBadRequest)
+	}
+
+	result := <-a.Srv.Store.IntegrationDelivery().GetDeliveries(integrationType, integrationId, page*perPage, perPage)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.([]*model.IntegrationDelivery), nil
+}
+
+// TrimIntegrationDeliveries deletes the oldest deliveries from the integration delivery log so that it doesn't grow
+// past INTEGRATION_DELIVERY_LOG_SIZE.
+func (a *App) TrimIntegrationDeliveries() {
+	if result := <-a.Srv.Store.IntegrationDelivery().PermanentDeleteOverLimit(INTEGRATION_DELIVERY_LOG_SIZE); result.Err != nil {
+		mlog.Error("Unable to trim the integration delivery log", mlog.Err(result.Err))
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// The most integrations that traffic is kept for. User agents are chosen by clients, so this stops a misbehaving
+// client from growing the report without bound.
+const INTEGRATION_TRAFFIC_MAX_INTEGRATIONS = 1000
+
+type integrationTrafficCounter struct {
+	mutex          sync.Mutex
+	traffic        model.IntegrationTraffic
+	totalLatencyMs float64
+}
+
+func (c *integrationTrafficCounter) observe(elapsed time.Duration, failed bool) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.traffic.RequestCount++
+	if failed {
+		c.traffic.ErrorCount++
+	}
+
+	c.totalLatencyMs += ms
+	if ms > c.traffic.MaxLatencyMs {
+		c.traffic.MaxLatencyMs = ms
+	}
+
+	c.traffic.LastRequestAt = model.GetMillis()
+}
+
+func (c *integrationTrafficCounter) snapshot() *model.IntegrationTraffic {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	traffic := c.traffic
+	if traffic.RequestCount > 0 {
+		traffic.AverageLatencyMs = c.totalLatencyMs / float64(traffic.RequestCount)
+	}
+
+	return &traffic
+}
+
+// RecordIntegrationRequest records a request that was made by an integration, both in memory and in the metrics server
+// if there is one.
+func (a *App) RecordIntegrationRequest(source *model.IntegrationSource, elapsed time.Duration, failed bool) {
+	if a.Metrics != nil {
+		a.Metrics.IncrementIntegrationHttpRequest(source.Type)
+		a.Metrics.ObserveIntegrationHttpRequestDuration(source.Type, float64(elapsed)/float64(time.Second))
+
+		if failed {
+			a.Metrics.IncrementIntegrationHttpError(source.Type)
+		}
+	}
+
+	key := source.Key()
+
+	counter, ok := a.integrationTraffic.Load(key)
+	if !ok {
+		a.integrationTrafficLock.Lock()
+		if a.integrationTrafficCount >= INTEGRATION_TRAFFIC_MAX_INTEGRATIONS {
+			a.integrationTrafficLock.Unlock()
+			return
+		}
+
+		var loaded bool
+		counter, loaded = a.integrationTraffic.LoadOrStore(key, &integrationTrafficCounter{traffic: model.IntegrationTraffic{IntegrationSource: *source}})
+		if !loaded {
+			a.integrationTrafficCount++
+		}
+		a.integrationTrafficLock.Unlock()
+	}
+
+	counter.(*integrationTrafficCounter).observe(elapsed, failed)
+}
+
+// GetIntegrationTrafficReport returns the traffic that this server has received from each integration since it started
+// or since the report was last reset, busiest first.
+func (a *App) GetIntegrationTrafficReport() *model.IntegrationTrafficReport {
+	a.integrationTrafficLock.Lock()
+	since := a.integrationTrafficSince
+	a.integrationTrafficLock.Unlock()
+
+	report := &model.IntegrationTrafficReport{
+		Since:        since,
+		Integrations: []*model.IntegrationTraffic{},
+	}
+
+	a.integrationTraffic.Range(func(key, counter interface{}) bool {
+		report.Integrations = append(report.Integrations, counter.(*integrationTrafficCounter).snapshot())
+		return true
+	})
+
+	sort.Slice(report.Integrations, func(i, j int) bool {
+		if report.Integrations[i].RequestCount != report.Integrations[j].RequestCount {
+			return report.Integrations[i].RequestCount > report.Integrations[j].RequestCount
+		}
+		return report.Integrations[i].Key() < report.Integrations[j].Key()
+	})
+
+	return report
+}
+
+func (a *App) ResetIntegrationTrafficReport() {
+	a.integrationTrafficLock.Lock()
+	defer a.integrationTrafficLock.Unlock()
+
+	a.integrationTraffic.Range(func(key, counter interface{}) bool {
+		a.integrationTraffic.Delete(key)
+		return true
+	})
+
+	a.integrationTrafficCount = 0
+	a.integrationTrafficSince = model.GetMillis()
+}
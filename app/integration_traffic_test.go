@@ -0,0 +1,56 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestIntegrationTrafficReport(t *testing.T) {
+	a := &App{}
+
+	assert.Empty(t, a.GetIntegrationTrafficReport().Integrations)
+
+	hook := &model.IntegrationSource{Type: model.INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: model.NewId()}
+	bot := &model.IntegrationSource{Type: model.INTEGRATION_TYPE_BOT, Id: "curl", UserId: model.NewId()}
+
+	a.RecordIntegrationRequest(hook, 10*time.Millisecond, false)
+	a.RecordIntegrationRequest(hook, 30*time.Millisecond, true)
+	a.RecordIntegrationRequest(bot, 5*time.Millisecond, false)
+
+	report := a.GetIntegrationTrafficReport()
+	require.Len(t, report.Integrations, 2)
+
+	busiest := report.Integrations[0]
+	assert.Equal(t, *hook, busiest.IntegrationSource)
+	assert.Equal(t, int64(2), busiest.RequestCount)
+	assert.Equal(t, int64(1), busiest.ErrorCount)
+	assert.Equal(t, float64(20), busiest.AverageLatencyMs)
+	assert.Equal(t, float64(30), busiest.MaxLatencyMs)
+	assert.NotZero(t, busiest.LastRequestAt)
+
+	assert.Equal(t, *bot, report.Integrations[1].IntegrationSource)
+
+	a.ResetIntegrationTrafficReport()
+
+	report = a.GetIntegrationTrafficReport()
+	assert.Empty(t, report.Integrations)
+	assert.NotZero(t, report.Since)
+}
+
+func TestIntegrationTrafficReportLimit(t *testing.T) {
+	a := &App{}
+
+	for i := 0; i < INTEGRATION_TRAFFIC_MAX_INTEGRATIONS+10; i++ {
+		a.RecordIntegrationRequest(&model.IntegrationSource{Type: model.INTEGRATION_TYPE_BOT, Id: model.NewId()}, time.Millisecond, false)
+	}
+
+	assert.Len(t, a.GetIntegrationTrafficReport().Integrations, INTEGRATION_TRAFFIC_MAX_INTEGRATIONS)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// OpenInteractiveDialog shows a dialog to a user. The dialog is signed along with the URL that it's submitted to, so that
+// submissions can be checked against it without the server having to remember it.
+func (a *App) OpenInteractiveDialog(userId, url string, dialog *model.Dialog) *model.AppError {
+	if err := dialog.IsValid(); err != nil {
+		return err
+	}
+
+	signature, err := a.AsymmetricSigningKey().Sign(rand.Reader, hashInteractiveDialog(userId, url, dialog), crypto.SHA256)
+	if err != nil {
+		return model.NewAppError("OpenInteractiveDialog", "app.interactive_dialog.sign.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	request := &model.OpenDialogRequest{
+		URL:       url,
+		Dialog:    dialog,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_OPEN_DIALOG, "", "", userId, nil)
+	message.Add("dialog", request.ToJson())
+	a.Publish(message)
+
+	return nil
+}
+
+// SubmitInteractiveDialog passes a user's submission of a dialog on to the integration that opened it. Submissions that
+// don't match the dialog's elements are turned down without the integration being asked.
+func (a *App) SubmitInteractiveDialog(request *model.SubmitDialogRequest, T goi18n.TranslateFunc) (*model.SubmitDialogResponse, *model.AppError) {
+	if request.Dialog == nil || !a.verifyInteractiveDialog(request.UserId, request.URL, request.Dialog, request.Signature) {
+		return nil, model.NewAppError("SubmitInteractiveDialog", "app.interactive_dialog.submit.signature.app_error", nil, "", http.StatusForbidden)
+	}
+
+	dialog := request.Dialog
+
+	if !request.Cancelled {
+		if errors := dialog.ValidateSubmission(request.Submission); len(errors) > 0 {
+			response := &model.SubmitDialogResponse{Errors: make(map[string]string)}
+			for name, err := range errors {
+				err.Translate(T)
+				response.Errors[name] = err.Message
+			}
+			return response, nil
+		}
+	}
+
+	// The integration is told which dialog was submitted by its callback id and state rather than the dialog itself
+	integrationRequest := *request
+	integrationRequest.URL = ""
+	integrationRequest.CallbackId = dialog.CallbackId
+	integrationRequest.State = dialog.State
+	integrationRequest.Dialog = nil
+	integrationRequest.Signature = ""
+	if integrationRequest.Cancelled {
+		integrationRequest.Submission = nil
+	}
+
+	resp, err := a.doIntegrationRequest(request.URL, integrationRequest.ToJson())
+	if err != nil {
+		return nil, model.NewAppError("SubmitInteractiveDialog", "app.interactive_dialog.submit.integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("SubmitInteractiveDialog", "app.interactive_dialog.submit.integration.app_error", nil, fmt.Sprintf("status=%v", resp.StatusCode), http.StatusBadRequest)
+	}
+
+	// Integrations don't need to respond with anything when they accept a submission
+	var response model.SubmitDialogResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil && err != io.EOF {
+		return nil, model.NewAppError("SubmitInteractiveDialog", "app.interactive_dialog.submit.integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
+	}
+
+	return &response, nil
+}
+
+func (a *App) verifyInteractiveDialog(userId, url string, dialog *model.Dialog, signature string) bool {
+	der, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	var rs struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &rs); err != nil || len(rest) != 0 {
+		return false
+	}
+
+	key := a.AsymmetricSigningKey()
+	return ecdsa.Verify(&key.PublicKey, hashInteractiveDialog(userId, url, dialog), rs.R, rs.S)
+}
+
+func hashInteractiveDialog(userId, url string, dialog *model.Dialog) []byte {
+	b, _ := json.Marshal(struct {
+		UserId string        `json:"user_id"`
+		URL    string        `json:"url"`
+		Dialog *model.Dialog `json:"dialog"`
+	}{userId, url, dialog})
+
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// issueUnfurler turns links to a provider's issues into structured embeds using the provider's REST API.
+type issueUnfurler interface {
+	// apiURL returns the API endpoint describing the issue that the link points to, if it points to one.
+	apiURL(link *url.URL) (string, bool)
+	authorize(req *http.Request, provider *model.IssueUnfurlProvider)
+	embed(link *url.URL, body io.Reader) (*model.IssueEmbed, error)
+}
+
+var issueUnfurlers = map[string]issueUnfurler{
+	model.ISSUE_UNFURL_PROVIDER_JIRA:   jiraIssueUnfurler{},
+	model.ISSUE_UNFURL_PROVIDER_GITHUB: githubIssueUnfurler{},
+}
+
+type jiraIssueUnfurler struct{}
+
+var jiraIssuePath = regexp.MustCompile(`^/browse/([A-Z][A-Z0-9_]*-[0-9]+)/?$`)
+
+func (jiraIssueUnfurler) apiURL(link *url.URL) (string, bool) {
+	match := jiraIssuePath.FindStringSubmatch(link.Path)
+	if match == nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s://%s/rest/api/2/issue/%s?fields=summary,status,assignee,labels,issuetype", link.Scheme, link.Host, match[1]), true
+}
+
+func (jiraIssueUnfurler) authorize(req *http.Request, provider *model.IssueUnfurlProvider) {
+	if len(provider.Username) > 0 {
+		req.SetBasicAuth(provider.Username, provider.Token)
+	} else if len(provider.Token) > 0 {
+		req.Header.Set(model.HEADER_AUTH, model.HEADER_BEARER+" "+provider.Token)
+	}
+}
+
+func (jiraIssueUnfurler) embed(link *url.URL, body io.Reader) (*model.IssueEmbed, error) {
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  *struct {
+				Name string `json:"name"`
+			} `json:"status"`
+			Assignee *struct {
+				DisplayName string `json:"displayName"`
+			} `json:"assignee"`
+			Labels []string `json:"labels"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(body).Decode(&issue); err != nil {
+		return nil, err
+	}
+
+	embed := &model.IssueEmbed{
+		Provider: model.ISSUE_UNFURL_PROVIDER_JIRA,
+		Type:     model.ISSUE_EMBED_TYPE_ISSUE,
+		URL:      link.String(),
+		Key:      issue.Key,
+		Title:    issue.Fields.Summary,
+		Labels:   issue.Fields.Labels,
+	}
+	if issue.Fields.Status != nil {
+		embed.Status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Assignee != nil {
+		embed.Assignee = issue.Fields.Assignee.DisplayName
+	}
+	if embed.Labels == nil {
+		embed.Labels = []string{}
+	}
+
+	return embed, nil
+}
+
+type githubIssueUnfurler struct{}
+
+var githubIssuePath = regexp.MustCompile(`^/([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)/(issues|pull)/([0-9]+)/?$`)
+
+func (githubIssueUnfurler) apiURL(link *url.URL) (string, bool) {
+	match := githubIssuePath.FindStringSubmatch(link.Path)
+	if match == nil {
+		return "", false
+	}
+
+	// Pull requests are issues as far as the issues API is concerned, which gives us their labels and assignee
+	apiRoot := fmt.Sprintf("%s://%s/api/v3", link.Scheme, link.Host)
+	if strings.EqualFold(link.Host, "github.com") {
+		apiRoot = "https://api.github.com"
+	}
+
+	return fmt.Sprintf("%s/repos/%s/%s/issues/%s", apiRoot, match[1], match[2], match[4]), true
+}
+
+func (githubIssueUnfurler) authorize(req *http.Request, provider *model.IssueUnfurlProvider) {
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if len(provider.Token) > 0 {
+		req.Header.Set(model.HEADER_AUTH, model.HEADER_TOKEN+" "+provider.Token)
+	}
+}
+
+func (githubIssueUnfurler) embed(link *url.URL, body io.Reader) (*model.IssueEmbed, error) {
+	var issue struct {
+		Number   int    `json:"number"`
+		Title    string `json:"title"`
+		State    string `json:"state"`
+		Assignee *struct {
+			Login string `json:"login"`
+		} `json:"assignee"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		PullRequest *struct{} `json:"pull_request"`
+	}
+	if err := json.NewDecoder(body).Decode(&issue); err != nil {
+		return nil, err
+	}
+
+	match := githubIssuePath.FindStringSubmatch(link.Path)
+
+	embed := &model.IssueEmbed{
+		Provider: model.ISSUE_UNFURL_PROVIDER_GITHUB,
+		Type:     model.ISSUE_EMBED_TYPE_ISSUE,
+		URL:      link.String(),
+		Key:      fmt.Sprintf("%s/%s#%d", match[1], match[2], issue.Number),
+		Title:    issue.Title,
+		Status:   issue.State,
+		Labels:   []string{},
+	}
+	if issue.PullRequest != nil {
+		embed.Type = model.ISSUE_EMBED_TYPE_PULL_REQUEST
+	}
+	if issue.Assignee != nil {
+		embed.Assignee = issue.Assignee.Login
+	}
+	for _, label := range issue.Labels {
+		embed.Labels = append(embed.Labels, label.Name)
+	}
+
+	return embed, nil
+}
+
+// getIssueUnfurlProvider returns the enabled provider configured for the link's domain, if there is one.
+func (a *App) getIssueUnfurlProvider(link *url.URL) *model.IssueUnfurlProvider {
+	settings := a.Config().IssueUnfurlSettings
+	if !*settings.Enable {
+		return nil
+	}
+
+	for _, provider := range settings.Providers {
+		if provider.Enable && strings.EqualFold(provider.Domain, link.Host) {
+			return provider
+		}
+	}
+
+	return nil
+}
+
+// GetIssueEmbed returns a structured embed for a link to an issue or pull request on one of the configured issue
+// trackers. It returns nil if the link doesn't point to one or the issue couldn't be retrieved.
+func (a *App) GetIssueEmbed(requestURL string) *model.IssueEmbed {
+	link, err := url.Parse(requestURL)
+	if err != nil {
+		return nil
+	}
+
+	provider := a.getIssueUnfurlProvider(link)
+	if provider == nil {
+		return nil
+	}
+
+	unfurler, ok := issueUnfurlers[provider.Type]
+	if !ok {
+		return nil
+	}
+
+	apiURL, ok := unfurler.apiURL(link)
+	if !ok {
+		return nil
+	}
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("GetIssueEmbed failed to build request for url=%v with err=%v", requestURL, err.Error()))
+		return nil
+	}
+	unfurler.authorize(req, provider)
+
+	// The domains are configured by the system admin, so internal issue trackers are allowed
+	res, err := a.linkPreviewHTTPClient(true).Do(req)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("GetIssueEmbed request failed for url=%v with err=%v", requestURL, err.Error()))
+		return nil
+	}
+	defer consumeAndClose(res)
+
+	if res.StatusCode != http.StatusOK {
+		mlog.Warn(fmt.Sprintf("GetIssueEmbed request failed for url=%v with status=%v", requestURL, res.StatusCode))
+		return nil
+	}
+
+	embed, err := unfurler.embed(link, res.Body)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("GetIssueEmbed failed to parse response for url=%v with err=%v", requestURL, err.Error()))
+		return nil
+	}
+
+	return embed
+}
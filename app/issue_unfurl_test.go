@@ -0,0 +1,124 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestIssueUnfurlerAPIURL(t *testing.T) {
+	for name, tc := range map[string]struct {
+		Provider string
+		Link     string
+		APIURL   string
+	}{
+		"jira issue":              {model.ISSUE_UNFURL_PROVIDER_JIRA, "https://jira.example.com/browse/PROJ-123", "https://jira.example.com/rest/api/2/issue/PROJ-123?fields=summary,status,assignee,labels,issuetype"},
+		"jira server with a port": {model.ISSUE_UNFURL_PROVIDER_JIRA, "http://jira.example.com:8080/browse/PROJ-1/", "http://jira.example.com:8080/rest/api/2/issue/PROJ-1?fields=summary,status,assignee,labels,issuetype"},
+		"jira board":              {model.ISSUE_UNFURL_PROVIDER_JIRA, "https://jira.example.com/secure/RapidBoard.jspa", ""},
+		"github issue":            {model.ISSUE_UNFURL_PROVIDER_GITHUB, "https://github.com/mattermost/mattermost-server/issues/9000", "https://api.github.com/repos/mattermost/mattermost-server/issues/9000"},
+		"github pull request":     {model.ISSUE_UNFURL_PROVIDER_GITHUB, "https://github.com/mattermost/mattermost-server/pull/9001", "https://api.github.com/repos/mattermost/mattermost-server/issues/9001"},
+		"github enterprise":       {model.ISSUE_UNFURL_PROVIDER_GITHUB, "https://git.example.com/team/repo/issues/1", "https://git.example.com/api/v3/repos/team/repo/issues/1"},
+		"github repository":       {model.ISSUE_UNFURL_PROVIDER_GITHUB, "https://github.com/mattermost/mattermost-server", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			link, err := url.Parse(tc.Link)
+			require.Nil(t, err)
+
+			apiURL, ok := issueUnfurlers[tc.Provider].apiURL(link)
+			assert.Equal(t, tc.APIURL != "", ok)
+			assert.Equal(t, tc.APIURL, apiURL)
+		})
+	}
+}
+
+func TestJiraIssueUnfurlerEmbed(t *testing.T) {
+	link, _ := url.Parse("https://jira.example.com/browse/PROJ-123")
+
+	embed, err := jiraIssueUnfurler{}.embed(link, strings.NewReader(`{
+		"key": "PROJ-123",
+		"fields": {
+			"summary": "Crash on startup",
+			"status": {"name": "In Progress"},
+			"assignee": {"displayName": "Jane Doe"},
+			"labels": ["bug", "regression"]
+		}
+	}`))
+	require.Nil(t, err)
+	assert.Equal(t, &model.IssueEmbed{
+		Provider: model.ISSUE_UNFURL_PROVIDER_JIRA,
+		Type:     model.ISSUE_EMBED_TYPE_ISSUE,
+		URL:      "https://jira.example.com/browse/PROJ-123",
+		Key:      "PROJ-123",
+		Title:    "Crash on startup",
+		Status:   "In Progress",
+		Assignee: "Jane Doe",
+		Labels:   []string{"bug", "regression"},
+	}, embed)
+
+	embed, err = jiraIssueUnfurler{}.embed(link, strings.NewReader(`{"key": "PROJ-123", "fields": {"summary": "Unassigned", "assignee": null}}`))
+	require.Nil(t, err)
+	assert.Empty(t, embed.Assignee)
+	assert.Equal(t, []string{}, embed.Labels)
+
+	_, err = jiraIssueUnfurler{}.embed(link, strings.NewReader(`<html>`))
+	assert.NotNil(t, err)
+}
+
+func TestGithubIssueUnfurlerEmbed(t *testing.T) {
+	link, _ := url.Parse("https://github.com/mattermost/mattermost-server/pull/9001")
+
+	embed, err := githubIssueUnfurler{}.embed(link, strings.NewReader(`{
+		"number": 9001,
+		"title": "Add issue unfurling",
+		"state": "open",
+		"assignee": {"login": "octocat"},
+		"labels": [{"name": "2: Dev Review"}],
+		"pull_request": {"url": "https://api.github.com/repos/mattermost/mattermost-server/pulls/9001"}
+	}`))
+	require.Nil(t, err)
+	assert.Equal(t, &model.IssueEmbed{
+		Provider: model.ISSUE_UNFURL_PROVIDER_GITHUB,
+		Type:     model.ISSUE_EMBED_TYPE_PULL_REQUEST,
+		URL:      "https://github.com/mattermost/mattermost-server/pull/9001",
+		Key:      "mattermost/mattermost-server#9001",
+		Title:    "Add issue unfurling",
+		Status:   "open",
+		Assignee: "octocat",
+		Labels:   []string{"2: Dev Review"},
+	}, embed)
+}
+
+func TestGetIssueUnfurlProvider(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.IssueUnfurlSettings.Enable = true
+		cfg.IssueUnfurlSettings.Providers = []*model.IssueUnfurlProvider{
+			{Type: model.ISSUE_UNFURL_PROVIDER_JIRA, Domain: "jira.example.com", Enable: true},
+			{Type: model.ISSUE_UNFURL_PROVIDER_GITHUB, Domain: "github.com", Enable: false},
+		}
+	})
+
+	link, _ := url.Parse("https://JIRA.example.com/browse/PROJ-1")
+	provider := th.App.getIssueUnfurlProvider(link)
+	require.NotNil(t, provider)
+	assert.Equal(t, model.ISSUE_UNFURL_PROVIDER_JIRA, provider.Type)
+
+	link, _ = url.Parse("https://github.com/mattermost/mattermost-server/issues/1")
+	assert.Nil(t, th.App.getIssueUnfurlProvider(link), "should skip disabled domains")
+	assert.Nil(t, th.App.GetIssueEmbed(link.String()))
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.IssueUnfurlSettings.Enable = false })
+
+	link, _ = url.Parse("https://jira.example.com/browse/PROJ-1")
+	assert.Nil(t, th.App.getIssueUnfurlProvider(link))
+}
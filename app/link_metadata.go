@@ -0,0 +1,200 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dyatlov/go-opengraph/opengraph"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+const LINK_METADATA_CLEANUP_BATCH_SIZE = 100
+
+// LinkPreview is the metadata that clients use to render the preview of a link. Links to issues also carry the
+// issue, with the OpenGraph fields filled in as well for clients that don't know how to render issues.
+type LinkPreview struct {
+	*opengraph.OpenGraph
+	Issue *model.IssueEmbed `json:"issue,omitempty"`
+}
+
+func newIssueLinkPreview(issue *model.IssueEmbed) *LinkPreview {
+	og := opengraph.NewOpenGraph()
+	og.Type = "website"
+	og.URL = issue.URL
+	og.Title = issue.Key + ": " + issue.Title
+	og.Description = issue.Status
+	og.SiteName = issue.Provider
+
+	return &LinkPreview{og, issue}
+}
+
+func (p *LinkPreview) isEmpty() bool {
+	return p.Issue == nil && p.URL == "" && p.Title == "" && len(p.Images) == 0
+}
+
+func (a *App) linkMetadataExpiryTime(now int64) int64 {
+	return now - int64(*a.Config().ServiceSettings.LinkMetadataTimeToLiveHours)*int64(time.Hour/time.Millisecond)
+}
+
+// GetLinkPreview returns the preview for a link, using the stored metadata for the link unless it has expired.
+func (a *App) GetLinkPreview(requestURL string) *LinkPreview {
+	if preview := a.getStoredLinkPreview(requestURL, model.GetMillis()); preview != nil {
+		return preview
+	}
+
+	preview := a.fetchLinkPreview(requestURL)
+	a.saveLinkPreview(requestURL, preview, 0)
+
+	return preview
+}
+
+func (a *App) getStoredLinkPreview(requestURL string, now int64) *LinkPreview {
+	var metadata *model.LinkMetadata
+	if result := <-a.Srv.Store.LinkMetadata().Get(requestURL); result.Err != nil {
+		return nil
+	} else {
+		metadata = result.Data.(*model.LinkMetadata)
+	}
+
+	if metadata.CreateAt < a.linkMetadataExpiryTime(now) {
+		return nil
+	}
+
+	var preview *LinkPreview
+	if err := json.Unmarshal([]byte(metadata.Data), &preview); err != nil || preview == nil || preview.OpenGraph == nil {
+		return nil
+	}
+
+	// Issue previews shouldn't outlive unfurling being turned off for their domain
+	if preview.Issue != nil {
+		if link, err := url.Parse(requestURL); err != nil || a.getIssueUnfurlProvider(link) == nil {
+			return nil
+		}
+	}
+
+	if result := <-a.Srv.Store.LinkMetadata().Touch(metadata.Hash, now); result.Err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to record access to link metadata for url=%v err=%v", requestURL, result.Err))
+	}
+
+	return preview
+}
+
+func (a *App) fetchLinkPreview(requestURL string) *LinkPreview {
+	if issue := a.GetIssueEmbed(requestURL); issue != nil {
+		return newIssueLinkPreview(issue)
+	}
+
+	og := a.GetOpenGraphMetadata(requestURL)
+
+	if a.PluginsReady() {
+		pluginContext := &plugin.Context{}
+		a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+			if replacement := hooks.LinkMetadataWillBeSaved(pluginContext, requestURL, og); replacement != nil {
+				og = replacement
+			}
+			return true
+		}, plugin.LinkMetadataWillBeSavedId)
+
+		makeOpenGraphURLsAbsolute(og, requestURL)
+	}
+
+	a.addLinkPreviewThumbnails(og)
+
+	return &LinkPreview{OpenGraph: og}
+}
+
+// saveLinkPreview stores the preview for a link, returning whether it was stored. Previews of links that couldn't be
+// fetched aren't stored so that they're tried again the next time that they're posted.
+func (a *App) saveLinkPreview(requestURL string, preview *LinkPreview, lastAccessAt int64) bool {
+	if preview.isEmpty() {
+		return false
+	}
+
+	data, err := json.Marshal(preview)
+	if err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to encode link metadata for url=%v err=%v", requestURL, err.Error()))
+		return false
+	}
+
+	metadata := &model.LinkMetadata{
+		URL:          requestURL,
+		Data:         string(data),
+		LastAccessAt: lastAccessAt,
+	}
+
+	if result := <-a.Srv.Store.LinkMetadata().Save(metadata); result.Err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to save link metadata for url=%v err=%v", requestURL, result.Err))
+		return false
+	}
+
+	return true
+}
+
+// CleanupLinkMetadata removes stored link metadata that's older than the configured time to live, fetching links
+// that are still popular again instead of removing them. It then trims the stored metadata down to the configured
+// maximum size by removing the links that have gone the longest without being used.
+func (a *App) CleanupLinkMetadata() *model.AppError {
+	settings := a.Config().ServiceSettings
+	expireBefore := a.linkMetadataExpiryTime(model.GetMillis())
+
+	for {
+		var expired []*model.LinkMetadata
+		if result := <-a.Srv.Store.LinkMetadata().GetExpired(expireBefore, LINK_METADATA_CLEANUP_BATCH_SIZE); result.Err != nil {
+			return result.Err
+		} else {
+			expired = result.Data.([]*model.LinkMetadata)
+		}
+
+		for _, metadata := range expired {
+			// Links that have been used enough since they were last fetched are likely to be used again
+			if metadata.LastAccessAt >= expireBefore && metadata.AccessCount >= int64(*settings.LinkMetadataRefreshMinAccesses) {
+				if a.saveLinkPreview(metadata.URL, a.fetchLinkPreview(metadata.URL), metadata.LastAccessAt) {
+					continue
+				}
+			}
+
+			if result := <-a.Srv.Store.LinkMetadata().Delete(metadata.Hash); result.Err != nil {
+				return result.Err
+			}
+		}
+
+		if len(expired) < LINK_METADATA_CLEANUP_BATCH_SIZE {
+			break
+		}
+	}
+
+	var count int64
+	if result := <-a.Srv.Store.LinkMetadata().Count(); result.Err != nil {
+		return result.Err
+	} else {
+		count = result.Data.(int64)
+	}
+
+	if excess := count - int64(*settings.LinkMetadataMaxCacheSize); excess > 0 {
+		if result := <-a.Srv.Store.LinkMetadata().DeleteLeastRecentlyUsed(excess); result.Err != nil {
+			return result.Err
+		}
+	}
+
+	return nil
+}
+
+// PurgeLinkMetadataForDomain removes the stored metadata for every link to a domain so that those links are fetched
+// again the next time that they're posted, returning how many links were removed.
+func (a *App) PurgeLinkMetadataForDomain(domain string) (int64, *model.AppError) {
+	result := <-a.Srv.Store.LinkMetadata().DeleteForDomain(strings.ToLower(domain))
+	if result.Err != nil {
+		return 0, result.Err
+	}
+
+	return result.Data.(int64), nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const LINK_PREVIEW_FIXTURE_REQUEST_TIMEOUT = 5 * time.Second
+
+// linkPreviewFixtureServer is an HTTP server that's embedded in the app to answer requests for link previews with
+// fixtures when link previews are in offline mode. It only listens on the loopback interface.
+type linkPreviewFixtureServer struct {
+	listener net.Listener
+	server   *http.Server
+	url      string
+
+	lock          sync.RWMutex
+	file          string
+	fileFixtures  map[string]*model.LinkPreviewFixture
+	addedFixtures map[string]*model.LinkPreviewFixture
+}
+
+func newLinkPreviewFixtureServer() (*linkPreviewFixtureServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &linkPreviewFixtureServer{
+		listener:      listener,
+		url:           "http://" + listener.Addr().String(),
+		fileFixtures:  map[string]*model.LinkPreviewFixture{},
+		addedFixtures: map[string]*model.LinkPreviewFixture{},
+	}
+	s.server = &http.Server{Handler: s}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			mlog.Error(fmt.Sprintf("Link preview fixture server stopped unexpectedly err=%v", err.Error()))
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *linkPreviewFixtureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fixture := s.getFixture(r.URL.Query().Get("url"))
+	if fixture == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if fixture.ContentType != "" {
+		w.Header().Set("Content-Type", fixture.ContentType)
+	}
+	w.WriteHeader(fixture.GetStatusCode())
+	w.Write([]byte(fixture.Body))
+}
+
+func (s *linkPreviewFixtureServer) getFixture(requestURL string) *model.LinkPreviewFixture {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if fixture, ok := s.addedFixtures[requestURL]; ok {
+		return fixture
+	}
+
+	return s.fileFixtures[requestURL]
+}
+
+func (s *linkPreviewFixtureServer) addFixture(fixture *model.LinkPreviewFixture) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.addedFixtures[fixture.URL] = fixture
+}
+
+// loadFile replaces the fixtures that were loaded from a file with the ones in the given file. Fixtures that were
+// added individually are kept. The file is remembered even if it couldn't be loaded so that it isn't retried for
+// every request.
+func (s *linkPreviewFixtureServer) loadFile(file string) error {
+	fixtures, err := readLinkPreviewFixtures(file)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.file = file
+	s.fileFixtures = fixtures
+
+	return err
+}
+
+func readLinkPreviewFixtures(file string) (map[string]*model.LinkPreviewFixture, error) {
+	fixtures := map[string]*model.LinkPreviewFixture{}
+	if file == "" {
+		return fixtures, nil
+	}
+
+	path := utils.FindFile(file)
+	if path == "" {
+		return fixtures, errors.New("file not found")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fixtures, err
+	}
+	defer f.Close()
+
+	loaded, err := model.LinkPreviewFixturesFromJson(f)
+	if err != nil {
+		return fixtures, err
+	}
+
+	for _, fixture := range loaded {
+		if fixture == nil {
+			continue
+		}
+
+		if err := fixture.IsValid(); err != nil {
+			return map[string]*model.LinkPreviewFixture{}, err
+		}
+
+		// Bodies are read up front so that a missing file is reported when the fixtures are loaded
+		if fixture.BodyFile != "" {
+			data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(path), fixture.BodyFile))
+			if err != nil {
+				return map[string]*model.LinkPreviewFixture{}, err
+			}
+
+			resolved := *fixture
+			resolved.Body = string(data)
+			resolved.BodyFile = ""
+			fixture = &resolved
+		}
+
+		fixtures[fixture.URL] = fixture
+	}
+
+	return fixtures, nil
+}
+
+func (s *linkPreviewFixtureServer) loadedFile() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.file
+}
+
+func (s *linkPreviewFixtureServer) Close() {
+	s.server.Close()
+}
+
+// linkPreviewFixtureTransport sends every request to the fixture server instead of the host that it was meant for.
+type linkPreviewFixtureTransport struct {
+	serverURL string
+}
+
+func (t *linkPreviewFixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.serverURL == "" {
+		return nil, errors.New("link preview fixture server isn't running")
+	}
+
+	fixtureReq, err := http.NewRequest(req.Method, t.serverURL+"/?url="+url.QueryEscape(req.URL.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	fixtureReq = fixtureReq.WithContext(req.Context())
+
+	resp, err := http.DefaultTransport.RoundTrip(fixtureReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers shouldn't be able to tell that the response didn't come from the link's host
+	resp.Request = req
+
+	return resp, nil
+}
+
+// IsLinkPreviewOffline returns true if link previews are generated from fixtures instead of the internet.
+func (a *App) IsLinkPreviewOffline() bool {
+	return *a.Config().ServiceSettings.LinkPreviewMode == model.LINK_PREVIEW_MODE_OFFLINE
+}
+
+// linkPreviewHTTPClient returns the client used to fetch everything that goes into a link preview. In offline mode,
+// its requests are answered by the fixture server so that previews can be generated without access to the internet.
+func (a *App) linkPreviewHTTPClient(trustURLs bool) *http.Client {
+	if !a.IsLinkPreviewOffline() {
+		return a.HTTPClient(trustURLs)
+	}
+
+	server, err := a.getLinkPreviewFixtureServer()
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to start link preview fixture server err=%v", err.Error()))
+		return &http.Client{Transport: &linkPreviewFixtureTransport{}}
+	}
+
+	return &http.Client{
+		Transport: &linkPreviewFixtureTransport{serverURL: server.url},
+		Timeout:   LINK_PREVIEW_FIXTURE_REQUEST_TIMEOUT,
+	}
+}
+
+// getLinkPreviewFixtureServer returns the fixture server, starting it if it isn't running and loading the fixtures
+// file again if it's changed in the config.
+func (a *App) getLinkPreviewFixtureServer() (*linkPreviewFixtureServer, error) {
+	a.linkPreviewFixtureServerLock.Lock()
+	defer a.linkPreviewFixtureServerLock.Unlock()
+
+	if a.linkPreviewFixtureServer == nil {
+		server, err := newLinkPreviewFixtureServer()
+		if err != nil {
+			return nil, err
+		}
+		a.linkPreviewFixtureServer = server
+	}
+
+	file := *a.Config().ServiceSettings.LinkPreviewFixturesFile
+	if file != a.linkPreviewFixtureServer.loadedFile() {
+		if err := a.linkPreviewFixtureServer.loadFile(file); err != nil {
+			mlog.Error(fmt.Sprintf("Unable to load link preview fixtures from file=%v err=%v", file, err.Error()))
+		}
+	}
+
+	return a.linkPreviewFixtureServer, nil
+}
+
+// AddLinkPreviewFixture adds a fixture to those that link previews are generated from in offline mode, replacing any
+// fixture for the same URL.
+func (a *App) AddLinkPreviewFixture(fixture *model.LinkPreviewFixture) *model.AppError {
+	if err := fixture.IsValid(); err != nil {
+		return err
+	}
+
+	if fixture.BodyFile != "" {
+		return model.NewAppError("AddLinkPreviewFixture", "app.link_preview_fixture.body_file.app_error", nil, "url="+fixture.URL, http.StatusBadRequest)
+	}
+
+	server, err := a.getLinkPreviewFixtureServer()
+	if err != nil {
+		return model.NewAppError("AddLinkPreviewFixture", "app.link_preview_fixture.start.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	server.addFixture(fixture)
+
+	return nil
+}
+
+func (a *App) stopLinkPreviewFixtureServer() {
+	a.linkPreviewFixtureServerLock.Lock()
+	defer a.linkPreviewFixtureServerLock.Unlock()
+
+	if a.linkPreviewFixtureServer != nil {
+		a.linkPreviewFixtureServer.Close()
+		a.linkPreviewFixtureServer = nil
+	}
+}
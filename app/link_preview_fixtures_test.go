@@ -0,0 +1,108 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestLinkPreviewFixtureServer(t *testing.T) {
+	server, err := newLinkPreviewFixtureServer()
+	require.Nil(t, err)
+	defer server.Close()
+
+	require.Nil(t, server.loadFile("tests/link-preview-fixtures.json"))
+
+	client := &http.Client{Transport: &linkPreviewFixtureTransport{serverURL: server.url}}
+
+	resp, err := client.Get("https://www.example.com/article")
+	require.Nil(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "Fixture Article")
+	assert.Equal(t, "https://www.example.com/article", resp.Request.URL.String())
+
+	resp, err = client.Get("https://www.example.com/image.png")
+	require.Nil(t, err)
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "\x89PNG", string(body[:4]), "should serve bodies from files")
+
+	resp, err = client.Get("https://www.example.com/missing")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = client.Get("https://www.example.com/unknown")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "should never fetch links without fixtures")
+
+	server.addFixture(&model.LinkPreviewFixture{URL: "https://www.example.com/unknown", Body: "added"})
+	require.Nil(t, server.loadFile(""))
+
+	resp, err = client.Get("https://www.example.com/unknown")
+	require.Nil(t, err)
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "added", string(body), "should keep added fixtures when the file changes")
+
+	resp, err = client.Get("https://www.example.com/article")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.NotNil(t, server.loadFile("tests/missing-link-preview-fixtures.json"))
+}
+
+func TestGetLinkPreviewOffline(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableLinkPreviews = true
+		*cfg.ServiceSettings.EnableLinkPreviewThumbnails = true
+		*cfg.ServiceSettings.LinkPreviewMode = model.LINK_PREVIEW_MODE_OFFLINE
+		*cfg.ServiceSettings.LinkPreviewFixturesFile = "tests/link-preview-fixtures.json"
+		*cfg.IssueUnfurlSettings.Enable = true
+		cfg.IssueUnfurlSettings.Providers = []*model.IssueUnfurlProvider{
+			{Type: model.ISSUE_UNFURL_PROVIDER_GITHUB, Domain: "github.com", Enable: true},
+		}
+	})
+
+	og := th.App.GetOpenGraphMetadata("https://www.example.com/article")
+	assert.Equal(t, "Fixture Article", og.Title)
+
+	preview := th.App.fetchLinkPreview("https://www.example.com/article")
+	require.Len(t, preview.Images, 1)
+	assert.EqualValues(t, 300, preview.Images[0].Width, "should make thumbnails from fixtures")
+
+	embed := th.App.GetIssueEmbed("https://github.com/mattermost/mattermost-server/issues/9000")
+	require.NotNil(t, embed)
+	assert.Equal(t, "Fixture issue", embed.Title)
+	assert.Equal(t, "octocat", embed.Assignee)
+
+	og = th.App.GetOpenGraphMetadata("https://www.example.com/unknown")
+	assert.Equal(t, "", og.Title, "should never fetch links without fixtures")
+
+	require.Nil(t, th.App.AddLinkPreviewFixture(&model.LinkPreviewFixture{
+		URL:         "https://www.example.com/unknown",
+		ContentType: "text/html",
+		Body:        `<html><head><meta property="og:title" content="Added" /></head></html>`,
+	}))
+
+	og = th.App.GetOpenGraphMetadata("https://www.example.com/unknown")
+	assert.Equal(t, "Added", og.Title)
+
+	assert.NotNil(t, th.App.AddLinkPreviewFixture(&model.LinkPreviewFixture{URL: "https://www.example.com/file", BodyFile: "test.png"}))
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+
+	"github.com/disintegration/imaging"
+	"github.com/dyatlov/go-opengraph/opengraph"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	LINK_PREVIEW_THUMBNAILS_PATH = "link_previews/thumbnails/"
+
+	// Pages can list any number of images, but clients only ever show the first few
+	LINK_PREVIEW_MAX_THUMBNAILS = 3
+)
+
+var linkPreviewThumbnailNamePattern = regexp.MustCompile(`^[a-f0-9]{64}\.(jpg|png)$`)
+
+// IsValidLinkPreviewThumbnailName returns true if the name is one that could've been given to a generated thumbnail,
+// which ensures that it can't be used to read other files.
+func IsValidLinkPreviewThumbnailName(name string) bool {
+	return linkPreviewThumbnailNamePattern.MatchString(name)
+}
+
+// addLinkPreviewThumbnails replaces the images in a link preview with thumbnails that are generated and served by this
+// server so that clients don't need to download full-size images from other sites to show a small preview. Images that
+// can't be made into thumbnails are left as they were.
+func (a *App) addLinkPreviewThumbnails(og *opengraph.OpenGraph) {
+	if !*a.Config().ServiceSettings.EnableLinkPreviewThumbnails {
+		return
+	}
+
+	for i, image := range og.Images {
+		if i >= LINK_PREVIEW_MAX_THUMBNAILS {
+			break
+		}
+
+		imageURL := image.SecureURL
+		if imageURL == "" {
+			imageURL = image.URL
+		}
+		if imageURL == "" {
+			continue
+		}
+
+		name, width, height, err := a.generateLinkPreviewThumbnail(imageURL)
+		if err != nil {
+			mlog.Warn(fmt.Sprintf("Unable to generate link preview thumbnail for url=%v err=%v", imageURL, err.Error()))
+			continue
+		}
+
+		subpath, _ := utils.GetSubpathFromConfig(a.Config())
+
+		image.URL = ""
+		image.SecureURL = path.Join(subpath, model.API_URL_SUFFIX, "image/thumbnails", name)
+		image.Width = uint64(width)
+		image.Height = uint64(height)
+		if path.Ext(name) == ".jpg" {
+			image.Type = "image/jpeg"
+		} else {
+			image.Type = "image/png"
+		}
+	}
+}
+
+// generateLinkPreviewThumbnail downloads an image, scales it down to the configured width and stores it, returning the
+// name of the thumbnail and its size. Thumbnails are named after the image that they were made from so that an image
+// that's used by many pages is only stored once.
+func (a *App) generateLinkPreviewThumbnail(imageURL string) (string, int, int, error) {
+	cfg := a.Config()
+	thumbnailWidth := *cfg.ServiceSettings.LinkPreviewThumbnailWidth
+
+	resp, err := a.linkPreviewHTTPClient(false).Get(imageURL)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, 0, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+
+	maxSize := *cfg.FileSettings.MaxFileSize
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if int64(len(data)) > maxSize {
+		return "", 0, 0, fmt.Errorf("image is larger than %v bytes", maxSize)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	// Animated GIFs would lose their animation, so only still images are made into thumbnails
+	if format != "png" && format != "jpeg" {
+		return "", 0, 0, fmt.Errorf("unsupported image format %v", format)
+	}
+
+	if config.Width*config.Height > MaxImageSize {
+		return "", 0, 0, fmt.Errorf("image is larger than %v pixels", MaxImageSize)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if format == "jpeg" {
+		orientation, _ := getImageOrientation(bytes.NewReader(data))
+		img = makeImageUpright(img, orientation)
+	}
+
+	if img.Bounds().Dx() > thumbnailWidth {
+		img = imaging.Resize(img, thumbnailWidth, 0, imaging.Lanczos)
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v:%v", thumbnailWidth, imageURL)))
+	name := hex.EncodeToString(hash[:])
+
+	buf := new(bytes.Buffer)
+	if isOpaqueImage(img) {
+		name += ".jpg"
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		name += ".png"
+		err = png.Encode(buf, img)
+	}
+
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if _, err := a.WriteFile(buf, LINK_PREVIEW_THUMBNAILS_PATH+name); err != nil {
+		return "", 0, 0, err
+	}
+
+	return name, img.Bounds().Dx(), img.Bounds().Dy(), nil
+}
+
+// GetLinkPreviewThumbnail returns a thumbnail that was generated for the image in a link preview.
+func (a *App) GetLinkPreviewThumbnail(name string) ([]byte, *model.AppError) {
+	if !IsValidLinkPreviewThumbnailName(name) {
+		return nil, model.NewAppError("GetLinkPreviewThumbnail", "app.link_preview_thumbnail.get.app_error", nil, "name="+name, http.StatusNotFound)
+	}
+
+	data, err := a.ReadFile(LINK_PREVIEW_THUMBNAILS_PATH + name)
+	if err != nil {
+		return nil, model.NewAppError("GetLinkPreviewThumbnail", "app.link_preview_thumbnail.get.app_error", nil, err.Error(), http.StatusNotFound)
+	}
+
+	return data, nil
+}
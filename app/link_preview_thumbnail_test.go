@@ -0,0 +1,92 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/dyatlov/go-opengraph/opengraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestIsValidLinkPreviewThumbnailName(t *testing.T) {
+	name := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	assert.True(t, IsValidLinkPreviewThumbnailName(name+".jpg"))
+	assert.True(t, IsValidLinkPreviewThumbnailName(name+".png"))
+	assert.False(t, IsValidLinkPreviewThumbnailName(name+".gif"))
+	assert.False(t, IsValidLinkPreviewThumbnailName(name))
+	assert.False(t, IsValidLinkPreviewThumbnailName("../"+name+".jpg"))
+	assert.False(t, IsValidLinkPreviewThumbnailName(""))
+}
+
+func TestAddLinkPreviewThumbnails(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/image.png" {
+			http.NotFound(w, r)
+			return
+		}
+
+		img := image.NewRGBA(image.Rect(0, 0, 600, 400))
+		for x := 0; x < 600; x++ {
+			for y := 0; y < 400; y++ {
+				img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0, 255})
+			}
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		png.Encode(w, img)
+	}))
+	defer ts.Close()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+		*cfg.ServiceSettings.EnableLinkPreviewThumbnails = false
+	})
+
+	og := opengraph.NewOpenGraph()
+	og.Images = []*opengraph.Image{{URL: ts.URL + "/image.png"}, {URL: ts.URL + "/missing.png"}}
+
+	th.App.addLinkPreviewThumbnails(og)
+	assert.Equal(t, ts.URL+"/image.png", og.Images[0].URL, "shouldn't change images when thumbnails are disabled")
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableLinkPreviewThumbnails = true
+	})
+
+	th.App.addLinkPreviewThumbnails(og)
+
+	thumbnail := og.Images[0]
+	assert.Equal(t, "", thumbnail.URL)
+	assert.Equal(t, "/api/v4/image/thumbnails", path.Dir(thumbnail.SecureURL))
+	assert.Equal(t, "image/jpeg", thumbnail.Type)
+	assert.EqualValues(t, 300, thumbnail.Width)
+	assert.EqualValues(t, 200, thumbnail.Height)
+
+	assert.Equal(t, ts.URL+"/missing.png", og.Images[1].URL, "should leave images that couldn't be fetched")
+
+	data, err := th.App.GetLinkPreviewThumbnail(path.Base(thumbnail.SecureURL))
+	require.Nil(t, err)
+
+	config, format, decodeErr := image.DecodeConfig(bytes.NewReader(data))
+	require.Nil(t, decodeErr)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 300, config.Width)
+
+	_, err = th.App.GetLinkPreviewThumbnail("../../config.json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	MENTION_PATTERN_CACHE_SIZE    = 10000
+	MENTION_PATTERN_MATCH_TIMEOUT = 100 * time.Millisecond
+)
+
+var mentionPatternCache = utils.NewLru(MENTION_PATTERN_CACHE_SIZE)
+
+// MentionPattern is a compiled phrase or pattern mention key along with the users who use it.
+type MentionPattern struct {
+	Key     string
+	Regexp  *regexp.Regexp
+	UserIds []string
+}
+
+// compileMentionKey returns the compiled regular expression for a mention key, caching it since the same keys are
+// used for every post in a channel.
+func compileMentionKey(key string) *regexp.Regexp {
+	if cached, ok := mentionPatternCache.Get(key); ok {
+		return cached.(*regexp.Regexp)
+	}
+
+	re := model.CompileMentionKey(key)
+	if re != nil {
+		mentionPatternCache.Add(key, re)
+	}
+
+	return re
+}
+
+// Given a map of user IDs to profiles, returns the phrase and pattern mention keys used by the users in the channel.
+func (a *App) GetMentionPatternsInChannel(profiles map[string]*model.User) []*MentionPattern {
+	patterns := []*MentionPattern{}
+	patternsByKey := make(map[string]*MentionPattern)
+
+	for id, profile := range profiles {
+		keys := model.GetMentionPatterns(profile.NotifyProps)
+		for _, key := range strings.Split(profile.NotifyProps[model.MENTION_KEYS_NOTIFY_PROP], ",") {
+			if model.IsMentionKeyPhrase(key) {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			key = model.NormalizeMentionKey(key)
+
+			if pattern, ok := patternsByKey[key]; ok {
+				pattern.UserIds = append(pattern.UserIds, id)
+				continue
+			}
+
+			re := compileMentionKey(key)
+			if re == nil {
+				mlog.Warn("Ignoring invalid mention key", mlog.String("user_id", id))
+				continue
+			}
+
+			pattern := &MentionPattern{Key: key, Regexp: re, UserIds: []string{id}}
+			patternsByKey[key] = pattern
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// GetPatternMentions returns the users whose phrase or pattern mention keys match the text of the post. Matching
+// stops once the timeout has passed, in which case only the mentions found until then are returned.
+func GetPatternMentions(post *model.Post, patterns []*MentionPattern, timeout time.Duration) map[string]bool {
+	mentioned := make(map[string]bool)
+
+	if len(patterns) == 0 {
+		return mentioned
+	}
+
+	blocks := getMentionableText(post)
+	if len(blocks) == 0 {
+		return mentioned
+	}
+
+	results := make(chan []string, len(patterns))
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(results)
+
+		for _, pattern := range patterns {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			for _, block := range blocks {
+				if pattern.Regexp.MatchString(block) {
+					results <- pattern.UserIds
+					break
+				}
+			}
+		}
+	}()
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case userIds, ok := <-results:
+			if !ok {
+				return mentioned
+			}
+
+			for _, userId := range userIds {
+				mentioned[userId] = true
+			}
+		case <-deadline:
+			close(stop)
+			mlog.Warn("Timed out matching mention patterns", mlog.String("post_id", post.Id), mlog.Int("patterns", len(patterns)))
+			return mentioned
+		}
+	}
+}
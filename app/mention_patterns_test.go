@@ -0,0 +1,77 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestGetMentionPatternsInChannel(t *testing.T) {
+	a := &App{}
+
+	profiles := map[string]*model.User{
+		"user1": {Id: "user1", NotifyProps: model.StringMap{
+			model.MENTION_KEYS_NOTIFY_PROP:     "user1,Release Train",
+			model.MENTION_PATTERNS_NOTIFY_PROP: `/INC-\d{2,4}/`,
+		}},
+		"user2": {Id: "user2", NotifyProps: model.StringMap{
+			model.MENTION_KEYS_NOTIFY_PROP:     "user2,release train",
+			model.MENTION_PATTERNS_NOTIFY_PROP: "/[/",
+		}},
+		"user3": {Id: "user3", NotifyProps: model.StringMap{}},
+	}
+
+	patterns := a.GetMentionPatternsInChannel(profiles)
+	assert.Len(t, patterns, 2, "plain and invalid keys should be skipped")
+
+	userIds := map[string][]string{}
+	for _, pattern := range patterns {
+		userIds[pattern.Key] = append(userIds[pattern.Key], pattern.UserIds...)
+	}
+	assert.ElementsMatch(t, []string{"user1", "user2"}, userIds["release train"])
+	assert.ElementsMatch(t, []string{"user1"}, userIds[`/INC-\d{2,4}/`])
+}
+
+func TestGetPatternMentions(t *testing.T) {
+	patterns := []*MentionPattern{
+		{Key: "release train", Regexp: model.CompileMentionKey("release train"), UserIds: []string{"user1", "user2"}},
+		{Key: `/INC-\d+/`, Regexp: model.CompileMentionKey(`/INC-\d+/`), UserIds: []string{"user3"}},
+	}
+
+	for name, tc := range map[string]struct {
+		Message  string
+		Expected map[string]bool
+	}{
+		"none": {
+			Message:  "nothing to see here",
+			Expected: map[string]bool{},
+		},
+		"phrase": {
+			Message:  "The Release train leaves at noon",
+			Expected: map[string]bool{"user1": true, "user2": true},
+		},
+		"pattern": {
+			Message:  "Looking into inc-42 now",
+			Expected: map[string]bool{"user3": true},
+		},
+		"both": {
+			Message:  "INC-7 blocks the release train",
+			Expected: map[string]bool{"user1": true, "user2": true, "user3": true},
+		},
+		"code": {
+			Message:  "`INC-7` and\n```\nrelease train\n```",
+			Expected: map[string]bool{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			post := &model.Post{Message: tc.Message}
+			assert.Equal(t, tc.Expected, GetPatternMentions(post, patterns, time.Second))
+		})
+	}
+}
@@ -22,6 +22,8 @@ const (
 )
 
 func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *model.Channel, sender *model.User, parentPostList *model.PostList) ([]string, *model.AppError) {
+	defer a.StartPerformanceTimer(PERFORMANCE_TIMING_NOTIFICATION_FANOUT)()
+
 	// Do not send notifications in archived channels
 	if channel.DeleteAt > 0 {
 		return []string{}, nil
@@ -91,9 +93,16 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 
 	} else {
 		keywords := a.GetMentionKeywordsInChannel(profileMap, post.Type != model.POST_HEADER_CHANGE && post.Type != model.POST_PURPOSE_CHANGE)
+		if post.Type != model.POST_HEADER_CHANGE && post.Type != model.POST_PURPOSE_CHANGE {
+			a.addUserGroupMentionKeywords(channel.Id, profileMap, keywords)
+		}
 
 		m := GetExplicitMentions(post, keywords)
 
+		for userId := range GetPatternMentions(post, a.GetMentionPatternsInChannel(profileMap), MENTION_PATTERN_MATCH_TIMEOUT) {
+			m.MentionedUserIds[userId] = true
+		}
+
 		// Add an implicit mention when a user is added to a channel
 		// even if the user has set 'username mentions' to false in account settings.
 		if post.Type == model.POST_ADD_TO_CHANNEL {
@@ -124,6 +133,10 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 			}
 		}
 
+		if len(post.RootId) > 0 {
+			a.addThreadFollowerMentions(post.RootId, profileMap, mentionedUserIds, threadMentionedUserIds)
+		}
+
 		// prevent the user from mentioning themselves
 		if post.Props["from_webhook"] != "true" {
 			delete(mentionedUserIds, post.UserId)
@@ -198,8 +211,8 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 				}
 			}
 
-			// Remove the user as recipient when the user has muted the channel.
-			if channelMuted, ok := channelMemberNotifyPropsMap[id][model.MARK_UNREAD_NOTIFY_PROP]; ok {
+			// Remove the user as recipient when the user has muted the channel, unless the post is urgent.
+			if channelMuted, ok := channelMemberNotifyPropsMap[id][model.MARK_UNREAD_NOTIFY_PROP]; ok && !post.IsUrgent() {
 				if channelMuted == model.CHANNEL_MARK_UNREAD_MENTION {
 					mlog.Debug(fmt.Sprintf("Channel muted for user_id %v, channel_mute %v", id, channelMuted))
 					userAllowsEmails = false
@@ -226,12 +239,40 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 
 			autoResponderRelated := status.Status == model.STATUS_OUT_OF_OFFICE || post.Type == model.POST_AUTO_RESPONDER
 
+			// Don't email the user while the channel is muted on a schedule or snoozed, the same as when it's muted,
+			// unless the post is urgent. Emails during the user's quiet hours are held back by sendNotificationEmail.
+			if !post.IsUrgent() && !DoesChannelScheduleAllowNotification(profileMap[id], channelMemberNotifyPropsMap[id], model.GetMillis()) {
+				userAllowsEmails = false
+			}
+
 			if userAllowsEmails && status.Status != model.STATUS_ONLINE && profileMap[id].DeleteAt == 0 && !autoResponderRelated {
 				a.sendNotificationEmail(post, profileMap[id], channel, team, channelName, senderName, sender)
 			}
 		}
 	}
 
+	if *a.Config().SmsSettings.Enable && post.IsUrgent() {
+		for _, id := range mentionedUsersList {
+			// Only explicit mentions are urgent enough to text someone about
+			if profileMap[id] == nil || !mentionedUserIds[id] {
+				continue
+			}
+
+			var status *model.Status
+			var err *model.AppError
+			if status, err = a.GetStatus(id); err != nil {
+				status = &model.Status{UserId: id, Status: model.STATUS_OFFLINE, Manual: false, LastActivityAt: 0, ActiveChannel: ""}
+			}
+
+			if a.ShouldSendSmsNotification(profileMap[id], status, post) {
+				profile := profileMap[id]
+				a.Go(func() {
+					a.sendSmsNotification(post, profile, channelName, senderName)
+				})
+			}
+		}
+	}
+
 	T := utils.GetUserTranslations(sender.Locale)
 
 	// If the channel has more than 1K users then @here is disabled
@@ -280,12 +321,11 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 		}
 	}
 
-	sendPushNotifications := false
+	sendPushNotifications := *a.Config().EmailSettings.EnableWebPushNotifications
 	if *a.Config().EmailSettings.SendPushNotifications {
 		pushServer := *a.Config().EmailSettings.PushNotificationServer
 		if license := a.License(); pushServer == model.MHPNS && (license == nil || !*license.Features.MHPNS) {
 			mlog.Warn("api.post.send_notifications_and_forget.push_notification.mhpnsWarn FIXME: NOT FOUND IN TRANSLATIONS FILE")
-			sendPushNotifications = false
 		} else {
 			sendPushNotifications = true
 		}
@@ -386,6 +426,58 @@ func (a *App) SendNotifications(post *model.Post, team *model.Team, channel *mod
 	return mentionedUsersList, nil
 }
 
+// addThreadFollowerMentions notifies the users following a thread of a reply to it. Users who have stopped following
+// the thread aren't notified of the reply unless they were mentioned in it.
+func (a *App) addThreadFollowerMentions(rootId string, profileMap map[string]*model.User, mentionedUserIds map[string]bool, threadMentionedUserIds map[string]string) {
+	var threads []*model.Thread
+	if result := <-a.Srv.Store.ThreadMembership().GetThreadsForPost(rootId); result.Err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to get the members of thread_id=%v to notify them of a reply, err=%v", rootId, result.Err))
+		return
+	} else {
+		threads = result.Data.([]*model.Thread)
+	}
+
+	for _, thread := range threads {
+		if profileMap[thread.UserId] == nil {
+			continue
+		}
+
+		if thread.Following {
+			if _, ok := threadMentionedUserIds[thread.UserId]; !ok {
+				threadMentionedUserIds[thread.UserId] = THREAD_ANY
+			}
+
+			if _, ok := mentionedUserIds[thread.UserId]; !ok {
+				mentionedUserIds[thread.UserId] = false
+			}
+		} else if !mentionedUserIds[thread.UserId] {
+			delete(threadMentionedUserIds, thread.UserId)
+			delete(mentionedUserIds, thread.UserId)
+		}
+	}
+}
+
+// addUserGroupMentionKeywords adds a mention keyword for each user group so that mentioning @group notifies the
+// members of the group who are in the channel.
+func (a *App) addUserGroupMentionKeywords(channelId string, profileMap map[string]*model.User, keywords map[string][]string) {
+	var memberIds map[string][]string
+	if result := <-a.Srv.Store.UserGroup().GetMemberIdsInChannel(channelId); result.Err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to get the members of user groups in channel_id=%v, err=%v", channelId, result.Err))
+		return
+	} else {
+		memberIds = result.Data.(map[string][]string)
+	}
+
+	for name, userIds := range memberIds {
+		groupMention := "@" + name
+		for _, userId := range userIds {
+			if profileMap[userId] != nil {
+				keywords[groupMention] = append(keywords[groupMention], userId)
+			}
+		}
+	}
+}
+
 func (a *App) sendOutOfChannelMentions(sender *model.User, post *model.Post, users []*model.User) *model.AppError {
 	if len(users) == 0 {
 		return nil
@@ -551,13 +643,27 @@ func GetExplicitMentions(post *model.Post, keywords map[string][]string) *Explic
 		}
 	}
 
+	for _, text := range getMentionableText(post) {
+		processText(text)
+	}
+
+	return ret
+}
+
+// getMentionableText returns the blocks of plain text in the post's mention enabled fields, leaving out code and
+// other markdown that can't contain mentions.
+func getMentionableText(post *model.Post) []string {
+	blocks := []string{}
+
 	buf := ""
 	mentionsEnabledFields := GetMentionsEnabledFields(post)
 	for _, message := range mentionsEnabledFields {
 		markdown.Inspect(message, func(node interface{}) bool {
 			text, ok := node.(*markdown.Text)
 			if !ok {
-				processText(buf)
+				if buf != "" {
+					blocks = append(blocks, buf)
+				}
 				buf = ""
 				return true
 			}
@@ -565,9 +671,11 @@ func GetExplicitMentions(post *model.Post, keywords map[string][]string) *Explic
 			return false
 		})
 	}
-	processText(buf)
+	if buf != "" {
+		blocks = append(blocks, buf)
+	}
 
-	return ret
+	return blocks
 }
 
 // Given a post returns the values of the fields in which mentions are possible.
@@ -601,6 +709,11 @@ func (a *App) GetMentionKeywordsInChannel(profiles map[string]*model.User, lookF
 			// Add all the user's mention keys
 			splitKeys := strings.Split(profile.NotifyProps["mention_keys"], ",")
 			for _, k := range splitKeys {
+				// phrases are matched separately by GetPatternMentions
+				if model.IsMentionKeyPhrase(k) {
+					continue
+				}
+
 				// note that these are made lower case so that we can do a case insensitive check for them
 				key := strings.ToLower(k)
 				keywords[key] = append(keywords[key], id)
@@ -41,7 +41,19 @@ func (a *App) sendNotificationEmail(post *model.Post, user *model.User, channel
 			}
 		}
 	}
-	if *a.Config().EmailSettings.EnableEmailBatching {
+
+	// Emails during the user's quiet hours, even for urgent posts, are kept in their digest, which isn't sent until the
+	// quiet hours end. Without email digests, there's nowhere to keep them, so they're dropped.
+	if !DoesScheduleAllowNotification(user, model.GetMillis()) {
+		if !*a.Config().EmailSettings.EnableEmailDigests {
+			mlog.Debug("Skipped sending notification email during quiet hours", mlog.String("user_id", user.Id), mlog.String("post_id", post.Id))
+			return nil
+		}
+
+		return a.AddNotificationEmailToDigest(user, post, team)
+	}
+
+	if *a.Config().EmailSettings.EnableEmailDigests || *a.Config().EmailSettings.EnableEmailBatching {
 		var sendBatched bool
 		if result := <-a.Srv.Store.Preference().Get(user.Id, model.PREFERENCE_CATEGORY_NOTIFICATIONS, model.PREFERENCE_NAME_EMAIL_INTERVAL); result.Err != nil {
 			// if the call fails, assume that the interval has not been explicitly set and batch the notifications
@@ -52,7 +64,12 @@ func (a *App) sendNotificationEmail(post *model.Post, user *model.User, channel
 		}
 
 		if sendBatched {
-			if err := a.AddNotificationEmailToBatch(user, post, team); err == nil {
+			// email digests replace the in-memory batching when they're enabled
+			if *a.Config().EmailSettings.EnableEmailDigests {
+				if err := a.AddNotificationEmailToDigest(user, post, team); err == nil {
+					return nil
+				}
+			} else if err := a.AddNotificationEmailToBatch(user, post, team); err == nil {
 				return nil
 			}
 		}
@@ -88,7 +105,7 @@ func (a *App) sendNotificationEmail(post *model.Post, user *model.User, channel
 	var bodyText = a.getNotificationEmailBody(user, post, channel, channelName, senderName, team.Name, teamURL, emailNotificationContentsType, useMilitaryTime, translateFunc)
 
 	a.Go(func() {
-		if err := a.SendMail(user.Email, html.UnescapeString(subjectText), bodyText); err != nil {
+		if err := a.SendTeamMail(team.Id, user.Email, html.UnescapeString(subjectText), bodyText); err != nil {
 			mlog.Error(fmt.Sprint("api.post.send_notifications_and_forget.send.error FIXME: NOT FOUND IN TRANSLATIONS FILE", user.Email, err))
 		}
 	})
@@ -0,0 +1,192 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// notificationChannel is a channel that a user belongs to, along with the name of its team if it has one.
+type notificationChannel struct {
+	channel  *model.Channel
+	teamName string
+}
+
+// getNotificationChannelsForUser returns every channel that the user belongs to, including direct and group messages.
+func (a *App) getNotificationChannelsForUser(userId string) ([]*notificationChannel, *model.AppError) {
+	teams, err := a.GetTeamsForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	channels := []*notificationChannel{}
+
+	for _, team := range teams {
+		channelList, err := a.GetChannelsForUser(team.Id, userId, false)
+		if err != nil {
+			if err.Id == "store.sql_channel.get_channels.not_found.app_error" {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, channel := range *channelList {
+			if seen[channel.Id] {
+				continue
+			}
+			seen[channel.Id] = true
+
+			teamName := ""
+			if channel.TeamId == team.Id {
+				teamName = team.Name
+			}
+
+			channels = append(channels, &notificationChannel{channel: channel, teamName: teamName})
+		}
+	}
+
+	return channels, nil
+}
+
+// ExportNotificationPreferences returns every notification setting that a user has, so that they can be restored later
+// or applied to another user.
+func (a *App) ExportNotificationPreferences(userId string) (*model.NotificationPreferences, *model.AppError) {
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	allPreferences, err := a.GetPreferencesForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := model.Preferences{}
+	for _, preference := range allPreferences {
+		if preference.Category == model.PREFERENCE_CATEGORY_NOTIFICATIONS {
+			preferences = append(preferences, preference)
+		}
+	}
+
+	channels, err := a.getNotificationChannelsForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := a.GetChannelMembersForUser("", userId)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyProps := map[string]model.StringMap{}
+	for _, member := range *members {
+		notifyProps[member.ChannelId] = member.NotifyProps
+	}
+
+	exported := &model.NotificationPreferences{
+		UserId:      userId,
+		ExportAt:    model.GetMillis(),
+		NotifyProps: user.NotifyProps,
+		Preferences: preferences,
+		Channels:    []*model.ChannelNotificationPreferences{},
+	}
+
+	for _, channel := range channels {
+		props, ok := notifyProps[channel.channel.Id]
+		if !ok {
+			continue
+		}
+
+		exported.Channels = append(exported.Channels, &model.ChannelNotificationPreferences{
+			ChannelId:   channel.channel.Id,
+			TeamName:    channel.teamName,
+			ChannelName: channel.channel.Name,
+			NotifyProps: props,
+		})
+	}
+
+	return exported, nil
+}
+
+// ImportNotificationPreferences applies notification settings that were exported from this or another user. Channels
+// are matched by id first and then by team and channel name, and any that the user doesn't belong to are skipped.
+func (a *App) ImportNotificationPreferences(userId string, imported *model.NotificationPreferences) (*model.NotificationPreferencesImportResult, *model.AppError) {
+	if err := imported.IsValid(); err != nil {
+		return nil, err
+	}
+
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imported.NotifyProps) > 0 {
+		notifyProps := model.StringMap{}
+		for key, value := range user.NotifyProps {
+			notifyProps[key] = value
+		}
+		for key, value := range imported.NotifyProps {
+			notifyProps[key] = value
+		}
+
+		if _, err = a.UpdateUserNotifyProps(userId, notifyProps); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(imported.Preferences) > 0 {
+		preferences := model.Preferences{}
+		for _, preference := range imported.Preferences {
+			preference.UserId = userId
+			preferences = append(preferences, preference)
+		}
+
+		if err = a.UpdatePreferences(userId, preferences); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &model.NotificationPreferencesImportResult{
+		ChannelsSkipped: []*model.ChannelNotificationPreferences{},
+	}
+
+	if len(imported.Channels) == 0 {
+		return result, nil
+	}
+
+	channels, err := a.getNotificationChannelsForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	channelsById := map[string]*model.Channel{}
+	channelsByName := map[string]*model.Channel{}
+	for _, channel := range channels {
+		channelsById[channel.channel.Id] = channel.channel
+		if channel.teamName != "" {
+			channelsByName[channel.teamName+"/"+channel.channel.Name] = channel.channel
+		}
+	}
+
+	for _, importedChannel := range imported.Channels {
+		channel, ok := channelsById[importedChannel.ChannelId]
+		if !ok && importedChannel.TeamName != "" {
+			channel, ok = channelsByName[importedChannel.TeamName+"/"+importedChannel.ChannelName]
+		}
+
+		if !ok {
+			result.ChannelsSkipped = append(result.ChannelsSkipped, importedChannel)
+			continue
+		}
+
+		if _, err = a.UpdateChannelMemberNotifyProps(importedChannel.NotifyProps, channel.Id, userId); err != nil {
+			return nil, err
+		}
+
+		result.ChannelsUpdated++
+	}
+
+	return result, nil
+}
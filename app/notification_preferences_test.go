@@ -0,0 +1,85 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestExportImportNotificationPreferences(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	_, err := th.App.AddUserToChannel(th.BasicUser2, th.BasicChannel)
+	require.Nil(t, err)
+
+	notifyProps := th.BasicUser.NotifyProps
+	notifyProps[model.DESKTOP_NOTIFY_PROP] = model.USER_NOTIFY_NONE
+	_, err = th.App.UpdateUserNotifyProps(th.BasicUser.Id, notifyProps)
+	require.Nil(t, err)
+
+	err = th.App.UpdatePreferences(th.BasicUser.Id, model.Preferences{{
+		UserId:   th.BasicUser.Id,
+		Category: model.PREFERENCE_CATEGORY_NOTIFICATIONS,
+		Name:     model.PREFERENCE_NAME_EMAIL_INTERVAL,
+		Value:    "3600",
+	}})
+	require.Nil(t, err)
+
+	_, err = th.App.UpdateChannelMemberNotifyProps(map[string]string{model.PUSH_NOTIFY_PROP: model.CHANNEL_NOTIFY_NONE}, th.BasicChannel.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	exported, err := th.App.ExportNotificationPreferences(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, th.BasicUser.Id, exported.UserId)
+	assert.Equal(t, model.USER_NOTIFY_NONE, exported.NotifyProps[model.DESKTOP_NOTIFY_PROP])
+	require.Len(t, exported.Preferences, 1)
+	assert.Equal(t, "3600", exported.Preferences[0].Value)
+
+	var exportedChannel *model.ChannelNotificationPreferences
+	for _, channel := range exported.Channels {
+		if channel.ChannelId == th.BasicChannel.Id {
+			exportedChannel = channel
+		}
+	}
+	require.NotNil(t, exportedChannel)
+	assert.Equal(t, th.BasicTeam.Name, exportedChannel.TeamName)
+	assert.Equal(t, th.BasicChannel.Name, exportedChannel.ChannelName)
+	assert.Equal(t, model.CHANNEL_NOTIFY_NONE, exportedChannel.NotifyProps[model.PUSH_NOTIFY_PROP])
+
+	t.Run("apply to another user", func(t *testing.T) {
+		// Channels from another server only match by name
+		exportedChannel.ChannelId = ""
+		missing := &model.ChannelNotificationPreferences{TeamName: th.BasicTeam.Name, ChannelName: "missing", NotifyProps: model.StringMap{}}
+		exported.Channels = append(exported.Channels, missing)
+
+		result, err := th.App.ImportNotificationPreferences(th.BasicUser2.Id, exported)
+		require.Nil(t, err)
+		assert.Equal(t, []*model.ChannelNotificationPreferences{missing}, result.ChannelsSkipped)
+
+		user, err := th.App.GetUser(th.BasicUser2.Id)
+		require.Nil(t, err)
+		assert.Equal(t, model.USER_NOTIFY_NONE, user.NotifyProps[model.DESKTOP_NOTIFY_PROP])
+
+		preference, err := th.App.GetPreferenceByCategoryAndNameForUser(th.BasicUser2.Id, model.PREFERENCE_CATEGORY_NOTIFICATIONS, model.PREFERENCE_NAME_EMAIL_INTERVAL)
+		require.Nil(t, err)
+		assert.Equal(t, "3600", preference.Value)
+
+		member, err := th.App.GetChannelMember(th.BasicChannel.Id, th.BasicUser2.Id)
+		require.Nil(t, err)
+		assert.Equal(t, model.CHANNEL_NOTIFY_NONE, member.NotifyProps[model.PUSH_NOTIFY_PROP])
+	})
+
+	t.Run("invalid preferences", func(t *testing.T) {
+		_, err := th.App.ImportNotificationPreferences(th.BasicUser2.Id, &model.NotificationPreferences{
+			Preferences: model.Preferences{{Category: model.PREFERENCE_CATEGORY_THEME, Name: "", Value: "{}"}},
+		})
+		assert.NotNil(t, err)
+	})
+}
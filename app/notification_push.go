@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -19,21 +18,81 @@ func (a *App) sendPushNotification(post *model.Post, user *model.User, channel *
 	explicitMention, channelWideMention bool, replyToThreadType string) *model.AppError {
 	cfg := a.Config()
 	contentsConfig := *cfg.EmailSettings.PushNotificationContents
-	teammateNameConfig := *cfg.TeamSettings.TeammateNameDisplay
 	sessions, err := a.getMobileAppSessions(user.Id)
-	sentBySystem := senderName == utils.T("system.message.name")
 	if err != nil {
 		return err
 	}
 
-	msg := model.PushNotification{}
-	if badge := <-a.Srv.Store.User().GetUnreadCount(user.Id); badge.Err != nil {
+	var msg model.PushNotification
+	if contentsConfig == model.ID_LOADED_NOTIFICATION {
+		msg = a.buildIdLoadedPushNotificationMessage(post, user)
+	} else {
+		msg = a.buildFullPushNotificationMessage(contentsConfig, post, user, channel, channelName, sender, senderName, explicitMention, channelWideMention, replyToThreadType)
+	}
+
+	if badge, err := a.GetTotalUnreadMentions(user.Id); err != nil {
 		msg.Badge = 1
-		mlog.Error(fmt.Sprint("We could not get the unread message count for the user", user.Id, badge.Err), mlog.String("user_id", user.Id))
+		mlog.Error(fmt.Sprint("We could not get the unread message count for the user", user.Id, err), mlog.String("user_id", user.Id))
 	} else {
-		msg.Badge = int(badge.Data.(int64))
+		msg.Badge = int(badge)
 	}
 
+	if *cfg.EmailSettings.EnableWebPushNotifications {
+		a.sendWebPushNotifications(user.Id, msg)
+	}
+
+	if !a.isPushProxyEnabled() {
+		return nil
+	}
+
+	for _, session := range sessions {
+
+		if session.IsExpired() {
+			continue
+		}
+
+		tmpMessage := *model.PushNotificationFromJson(strings.NewReader(msg.ToJson()))
+		tmpMessage.SetDeviceIdAndPlatform(session.DeviceId)
+
+		mlog.Debug(fmt.Sprintf("Sending push notification to device %v for user %v with msg of '%v'", tmpMessage.DeviceId, user.Id, msg.Message), mlog.String("user_id", user.Id))
+
+		a.Go(func(session *model.Session) func() {
+			return func() {
+				a.sendToPushProxy(tmpMessage, session)
+			}
+		}(session))
+
+		if a.Metrics != nil {
+			a.Metrics.IncrementPostSentPush()
+		}
+	}
+
+	return nil
+}
+
+// buildIdLoadedPushNotificationMessage builds a notification that only identifies the post. The mobile apps replace
+// its placeholder message with the contents they fetch from the server, so the post's contents never reach the push
+// proxy or the services it forwards to.
+func (a *App) buildIdLoadedPushNotificationMessage(post *model.Post, user *model.User) model.PushNotification {
+	userLocale := utils.GetUserTranslations(user.Locale)
+
+	return model.PushNotification{
+		Category:   model.CATEGORY_CAN_REPLY,
+		Version:    model.PUSH_MESSAGE_V2,
+		Type:       model.PUSH_TYPE_MESSAGE,
+		PostId:     post.Id,
+		Message:    userLocale("api.push_notification.id_loaded.default_message"),
+		IsIdLoaded: true,
+	}
+}
+
+func (a *App) buildFullPushNotificationMessage(contentsConfig string, post *model.Post, user *model.User, channel *model.Channel, channelName string, sender *model.User, senderName string,
+	explicitMention, channelWideMention bool, replyToThreadType string) model.PushNotification {
+	cfg := a.Config()
+	teammateNameConfig := *cfg.TeamSettings.TeammateNameDisplay
+	sentBySystem := senderName == utils.T("system.message.name")
+
+	msg := model.PushNotification{}
 	msg.Category = model.CATEGORY_CAN_REPLY
 	msg.Version = model.PUSH_MESSAGE_V2
 	msg.Type = model.PUSH_TYPE_MESSAGE
@@ -75,39 +134,74 @@ func (a *App) sendPushNotification(post *model.Post, user *model.User, channel *
 	userLocale := utils.GetUserTranslations(user.Locale)
 	hasFiles := post.FileIds != nil && len(post.FileIds) > 0
 
-	msg.Message = a.getPushNotificationMessage(post.Message, explicitMention, channelWideMention, hasFiles, senderName, channelName, channel.Type, replyToThreadType, userLocale)
+	msg.Message = a.getPushNotificationMessage(contentsConfig, post.Message, explicitMention, channelWideMention, hasFiles, senderName, channelName, channel.Type, replyToThreadType, userLocale)
 
-	for _, session := range sessions {
+	return msg
+}
 
-		if session.IsExpired() {
-			continue
-		}
+// BuildFetchedPushNotificationMessage returns the full notification that the given user would have received for a
+// post. The mobile apps call it to load the contents of an ID-only notification, so the contents are always full
+// regardless of the configured push notification contents. The caller is expected to have checked that the user can
+// read the post.
+func (a *App) BuildFetchedPushNotificationMessage(postId string, userId string) (*model.PushNotification, *model.AppError) {
+	post, err := a.GetSinglePost(postId)
+	if err != nil {
+		return nil, err
+	}
 
-		tmpMessage := *model.PushNotificationFromJson(strings.NewReader(msg.ToJson()))
-		tmpMessage.SetDeviceIdAndPlatform(session.DeviceId)
+	channel, err := a.GetChannel(post.ChannelId)
+	if err != nil {
+		return nil, err
+	}
 
-		mlog.Debug(fmt.Sprintf("Sending push notification to device %v for user %v with msg of '%v'", tmpMessage.DeviceId, user.Id, msg.Message), mlog.String("user_id", user.Id))
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
 
-		a.Go(func(session *model.Session) func() {
-			return func() {
-				a.sendToPushProxy(tmpMessage, session)
-			}
-		}(session))
+	sender, err := a.GetUser(post.UserId)
+	if err != nil {
+		return nil, err
+	}
 
-		if a.Metrics != nil {
-			a.Metrics.IncrementPostSentPush()
+	senderName := sender.Username
+	if post.IsSystemMessage() {
+		senderName = utils.T("system.message.name")
+	} else if value, ok := post.Props["override_username"].(string); ok && post.Props["from_webhook"] == "true" && channel.Type != model.CHANNEL_DIRECT {
+		senderName = value
+	}
+
+	channelName := channel.DisplayName
+	if channel.Type == model.CHANNEL_GROUP {
+		result := <-a.Srv.Store.User().GetAllProfilesInChannel(channel.Id, true)
+		if result.Err != nil {
+			return nil, result.Err
 		}
+
+		userList := []*model.User{}
+		for _, u := range result.Data.(map[string]*model.User) {
+			userList = append(userList, u)
+		}
+		channelName = model.GetGroupDisplayNameFromUsers(userList, false)
 	}
 
-	return nil
+	explicitMention := channel.Type == model.CHANNEL_DIRECT
+	channelWideMention := false
+	if !explicitMention {
+		keywords := a.GetMentionKeywordsInChannel(map[string]*model.User{user.Id: user}, post.Type != model.POST_HEADER_CHANGE && post.Type != model.POST_PURPOSE_CHANGE)
+		mentions := GetExplicitMentions(post, keywords)
+		explicitMention = mentions.MentionedUserIds[user.Id]
+		channelWideMention = mentions.ChannelMentioned || mentions.HereMentioned || mentions.AllMentioned
+	}
+
+	msg := a.buildFullPushNotificationMessage(model.FULL_NOTIFICATION, post, user, channel, channelName, sender, senderName, explicitMention, channelWideMention, "")
+	return &msg, nil
 }
 
-func (a *App) getPushNotificationMessage(postMessage string, explicitMention, channelWideMention, hasFiles bool,
+func (a *App) getPushNotificationMessage(contentsConfig string, postMessage string, explicitMention, channelWideMention, hasFiles bool,
 	senderName, channelName, channelType, replyToThreadType string, userLocale i18n.TranslateFunc) string {
 	message := ""
 
-	contentsConfig := *a.Config().EmailSettings.PushNotificationContents
-
 	if contentsConfig == model.FULL_NOTIFICATION {
 		if channelType == model.CHANNEL_DIRECT {
 			message = model.ClearMentionTags(postMessage)
@@ -142,14 +236,18 @@ func (a *App) getPushNotificationMessage(postMessage string, explicitMention, ch
 	return message
 }
 
+// GetTotalUnreadMentions returns the count shown on a user's badge: their mentions across all of their teams plus
+// their unread direct messages. It reads from the master so that it includes posts and views that just happened.
+func (a *App) GetTotalUnreadMentions(userId string) (int64, *model.AppError) {
+	if result := <-a.Srv.Store.User().GetUnreadCountFromMaster(userId); result.Err != nil {
+		return 0, result.Err
+	} else {
+		return result.Data.(int64), nil
+	}
+}
+
 func (a *App) ClearPushNotification(userId string, channelId string) {
 	a.Go(func() {
-		// Sleep is to allow the read replicas a chance to fully sync
-		// the unread count for sending an accurate count.
-		// Delaying a little doesn't hurt anything and is cheaper than
-		// attempting to read from master.
-		time.Sleep(time.Second * 5)
-
 		sessions, err := a.getMobileAppSessions(userId)
 		if err != nil {
 			mlog.Error(err.Error())
@@ -160,15 +258,23 @@ func (a *App) ClearPushNotification(userId string, channelId string) {
 		msg.Type = model.PUSH_TYPE_CLEAR
 		msg.ChannelId = channelId
 		msg.ContentAvailable = 0
-		if badge := <-a.Srv.Store.User().GetUnreadCount(userId); badge.Err != nil {
+		if badge, err := a.GetTotalUnreadMentions(userId); err != nil {
 			msg.Badge = 0
-			mlog.Error(fmt.Sprint("We could not get the unread message count for the user", userId, badge.Err), mlog.String("user_id", userId))
+			mlog.Error(fmt.Sprint("We could not get the unread message count for the user", userId, err), mlog.String("user_id", userId))
 		} else {
-			msg.Badge = int(badge.Data.(int64))
+			msg.Badge = int(badge)
 		}
 
 		mlog.Debug(fmt.Sprintf("Clearing push notification to %v with channel_id %v", msg.DeviceId, msg.ChannelId))
 
+		if *a.Config().EmailSettings.EnableWebPushNotifications {
+			a.sendWebPushNotifications(userId, msg)
+		}
+
+		if !a.isPushProxyEnabled() {
+			return
+		}
+
 		for _, session := range sessions {
 			tmpMessage := *model.PushNotificationFromJson(strings.NewReader(msg.ToJson()))
 			tmpMessage.SetDeviceIdAndPlatform(session.DeviceId)
@@ -214,7 +320,8 @@ func (a *App) getMobileAppSessions(userId string) ([]*model.Session, *model.AppE
 
 func ShouldSendPushNotification(user *model.User, channelNotifyProps model.StringMap, wasMentioned bool, status *model.Status, post *model.Post) bool {
 	return DoesNotifyPropsAllowPushNotification(user, channelNotifyProps, post, wasMentioned) &&
-		DoesStatusAllowPushNotification(user.NotifyProps, status, post.ChannelId)
+		DoesStatusAllowPushNotification(user.NotifyProps, status, post.ChannelId) &&
+		DoesScheduleAllowNotification(user, model.GetMillis())
 }
 
 func DoesNotifyPropsAllowPushNotification(user *model.User, channelNotifyProps model.StringMap, post *model.Post, wasMentioned bool) bool {
@@ -222,8 +329,8 @@ func DoesNotifyPropsAllowPushNotification(user *model.User, channelNotifyProps m
 	userNotify := userNotifyProps[model.PUSH_NOTIFY_PROP]
 	channelNotify, ok := channelNotifyProps[model.PUSH_NOTIFY_PROP]
 
-	// If the channel is muted do not send push notifications
-	if channelMuted, ok := channelNotifyProps[model.MARK_UNREAD_NOTIFY_PROP]; ok {
+	// If the channel is muted do not send push notifications, unless the post is urgent
+	if channelMuted, ok := channelNotifyProps[model.MARK_UNREAD_NOTIFY_PROP]; ok && !post.IsUrgent() {
 		if channelMuted == model.CHANNEL_MARK_UNREAD_MENTION {
 			return false
 		}
@@ -233,6 +340,11 @@ func DoesNotifyPropsAllowPushNotification(user *model.User, channelNotifyProps m
 		return false
 	}
 
+	// If the channel is muted on a schedule that is currently in effect do not send push notifications
+	if !post.IsUrgent() && !DoesChannelScheduleAllowNotification(user, channelNotifyProps, model.GetMillis()) {
+		return false
+	}
+
 	if channelNotify == model.USER_NOTIFY_NONE {
 		return false
 	}
@@ -274,3 +386,16 @@ func DoesStatusAllowPushNotification(userNotifyProps model.StringMap, status *mo
 
 	return false
 }
+
+// DoesScheduleAllowNotification returns false while the user's notification schedule (quiet hours) is in effect.
+func DoesScheduleAllowNotification(user *model.User, millis int64) bool {
+	return !model.IsScheduleActive(user.NotifyProps, user.GetPreferredTimezone(), millis)
+}
+
+// DoesChannelScheduleAllowNotification returns false while the user has the channel muted on a schedule that covers
+// the given time, or has snoozed the channel until a later time. The schedule is evaluated in the user's timezone
+// unless it names one of its own.
+func DoesChannelScheduleAllowNotification(user *model.User, channelNotifyProps model.StringMap, millis int64) bool {
+	return !model.IsChannelSnoozedAt(channelNotifyProps, millis) &&
+		!model.IsScheduleActive(channelNotifyProps, user.GetPreferredTimezone(), millis)
+}
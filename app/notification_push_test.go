@@ -4,7 +4,9 @@
 package app
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/utils"
@@ -19,6 +21,7 @@ func TestDoesNotifyPropsAllowPushNotification(t *testing.T) {
 		withSystemPost       bool
 		wasMentioned         bool
 		isMuted              bool
+		isUrgent             bool
 		expected             bool
 	}{
 		{
@@ -318,6 +321,26 @@ func TestDoesNotifyPropsAllowPushNotification(t *testing.T) {
 			isMuted:              true,
 			expected:             false,
 		},
+		{
+			name:                 "When default is ALL, channel is MUTED and post is urgent",
+			userNotifySetting:    model.USER_NOTIFY_ALL,
+			channelNotifySetting: "",
+			withSystemPost:       false,
+			wasMentioned:         false,
+			isMuted:              true,
+			isUrgent:             true,
+			expected:             true,
+		},
+		{
+			name:                 "When channel is NONE, channel is MUTED and post is urgent",
+			userNotifySetting:    model.USER_NOTIFY_ALL,
+			channelNotifySetting: model.CHANNEL_NOTIFY_NONE,
+			withSystemPost:       false,
+			wasMentioned:         true,
+			isMuted:              true,
+			isUrgent:             true,
+			expected:             false,
+		},
 	}
 
 	for _, tc := range tt {
@@ -328,6 +351,9 @@ func TestDoesNotifyPropsAllowPushNotification(t *testing.T) {
 			if tc.withSystemPost {
 				post.Type = model.POST_JOIN_CHANNEL
 			}
+			if tc.isUrgent {
+				post.AddProp(model.POST_PROPS_PRIORITY, model.POST_PRIORITY_URGENT)
+			}
 
 			channelNotifyProps := make(map[string]string)
 			if tc.channelNotifySetting != "" {
@@ -876,11 +902,8 @@ func TestGetPushNotificationMessage(t *testing.T) {
 				pushNotificationContents = model.FULL_NOTIFICATION
 			}
 
-			th.App.UpdateConfig(func(cfg *model.Config) {
-				*cfg.EmailSettings.PushNotificationContents = pushNotificationContents
-			})
-
 			if actualMessage := th.App.getPushNotificationMessage(
+				pushNotificationContents,
 				tc.Message,
 				tc.explicitMention,
 				tc.channelWideMention,
@@ -896,3 +919,76 @@ func TestGetPushNotificationMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildIdLoadedPushNotificationMessage(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	post := &model.Post{Id: model.NewId(), ChannelId: th.BasicChannel.Id, UserId: th.BasicUser2.Id, Message: "secret"}
+
+	msg := th.App.buildIdLoadedPushNotificationMessage(post, th.BasicUser)
+
+	assert.True(t, msg.IsIdLoaded)
+	assert.Equal(t, post.Id, msg.PostId)
+	assert.Equal(t, "You've received a new message.", msg.Message)
+	assert.Empty(t, msg.ChannelId)
+	assert.Empty(t, msg.ChannelName)
+	assert.Empty(t, msg.SenderId)
+}
+
+func TestDoesScheduleAllowNotification(t *testing.T) {
+	// 2018-07-02 23:00 UTC is a Monday night.
+	night := time.Date(2018, 7, 2, 23, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	noon := time.Date(2018, 7, 2, 12, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+	user := &model.User{
+		NotifyProps: model.StringMap{
+			model.SCHEDULE_ENABLED_NOTIFY_PROP: "true",
+			model.SCHEDULE_DAYS_NOTIFY_PROP:    "mon,tue,wed,thu,fri",
+			model.SCHEDULE_START_NOTIFY_PROP:   "22:00",
+			model.SCHEDULE_END_NOTIFY_PROP:     "08:00",
+		},
+		Timezone: model.StringMap{
+			"useAutomaticTimezone": "false",
+			"manualTimezone":       "UTC",
+		},
+	}
+
+	assert.False(t, DoesScheduleAllowNotification(user, night))
+	assert.True(t, DoesScheduleAllowNotification(user, noon))
+
+	user.NotifyProps[model.SCHEDULE_ENABLED_NOTIFY_PROP] = "false"
+	assert.True(t, DoesScheduleAllowNotification(user, night))
+}
+
+func TestDoesChannelScheduleAllowNotification(t *testing.T) {
+	// 2018-07-02 is a Monday.
+	evening := time.Date(2018, 7, 2, 19, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	morning := time.Date(2018, 7, 3, 10, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+	user := &model.User{
+		Timezone: model.StringMap{
+			"useAutomaticTimezone": "true",
+			"automaticTimezone":    "UTC",
+		},
+	}
+
+	// Muted outside of working hours.
+	channelNotifyProps := model.StringMap{
+		model.SCHEDULE_ENABLED_NOTIFY_PROP: "true",
+		model.SCHEDULE_DAYS_NOTIFY_PROP:    "mon,tue,wed,thu,fri,sat,sun",
+		model.SCHEDULE_START_NOTIFY_PROP:   "17:00",
+		model.SCHEDULE_END_NOTIFY_PROP:     "09:00",
+	}
+
+	assert.False(t, DoesChannelScheduleAllowNotification(user, channelNotifyProps, evening))
+	assert.True(t, DoesChannelScheduleAllowNotification(user, channelNotifyProps, morning))
+	assert.True(t, DoesChannelScheduleAllowNotification(user, model.GetDefaultChannelNotifyProps(), evening))
+
+	// Snoozed for two hours from the evening.
+	snoozedNotifyProps := model.GetDefaultChannelNotifyProps()
+	snoozedNotifyProps[model.MUTED_UNTIL_NOTIFY_PROP] = strconv.FormatInt(evening+2*60*60*1000, 10)
+
+	assert.False(t, DoesChannelScheduleAllowNotification(user, snoozedNotifyProps, evening))
+	assert.True(t, DoesChannelScheduleAllowNotification(user, snoozedNotifyProps, morning))
+}
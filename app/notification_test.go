@@ -85,6 +85,43 @@ func TestSendNotifications(t *testing.T) {
 	assert.Len(t, mentions, 0)
 }
 
+func TestSendNotificationsUserGroupMention(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.AddUserToChannel(th.BasicUser2, th.BasicChannel)
+	outsider := th.CreateUser()
+
+	group, err := th.App.CreateUserGroup(&model.UserGroup{Name: "oncall" + model.NewRandomString(6), CreatorId: th.BasicUser.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, userId := range []string{th.BasicUser2.Id, outsider.Id} {
+		if _, err = th.App.AddUserGroupMember(group.Id, userId); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	post, err := th.App.CreatePostMissingChannel(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "paging @" + group.Name,
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mentions, err := th.App.SendNotifications(post, th.BasicTeam, th.BasicChannel, th.BasicUser, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !utils.StringInSlice(th.BasicUser2.Id, mentions) {
+		t.Fatal("group member in the channel should have been mentioned")
+	} else if utils.StringInSlice(outsider.Id, mentions) {
+		t.Fatal("group member outside of the channel shouldn't have been mentioned")
+	}
+}
+
 func TestGetExplicitMentions(t *testing.T) {
 	id1 := model.NewId()
 	id2 := model.NewId()
@@ -7,14 +7,20 @@ import (
 	"bytes"
 	b64 "encoding/base64"
 	"fmt"
+	"image"
+	"image/png"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/disintegration/imaging"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+
 	"github.com/mattermost/mattermost-server/einterfaces"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -63,6 +69,7 @@ func (a *App) UpdateOauthApp(oldApp, updatedApp *model.OAuthApp) (*model.OAuthAp
 	updatedApp.CreatorId = oldApp.CreatorId
 	updatedApp.CreateAt = oldApp.CreateAt
 	updatedApp.ClientSecret = oldApp.ClientSecret
+	updatedApp.LastIconUpdate = oldApp.LastIconUpdate
 
 	if result := <-a.Srv.Store.OAuth().UpdateApp(updatedApp); result.Err != nil {
 		return nil, result.Err
@@ -109,6 +116,24 @@ func (a *App) GetOAuthAppsByCreator(userId string, page, perPage int) ([]*model.
 	}
 }
 
+// GetOAuthScopes describes the scopes in a requested scope, or all of the scopes that apps can request if it's empty.
+func (a *App) GetOAuthScopes(scope string, T goi18n.TranslateFunc) []*model.OAuthScope {
+	names := model.OAuthScopes
+	if len(scope) > 0 {
+		names = model.ParseOAuthScope(scope)
+	}
+
+	scopes := make([]*model.OAuthScope, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, &model.OAuthScope{
+			Name:        name,
+			Description: T("api.oauth.scope." + strings.Replace(name, ":", "_", -1) + ".description"),
+		})
+	}
+
+	return scopes
+}
+
 func (a *App) GetOAuthImplicitRedirect(userId string, authRequest *model.AuthorizeRequest) (string, *model.AppError) {
 	session, err := a.GetOAuthAccessTokenForImplicitFlow(userId, authRequest)
 	if err != nil {
@@ -126,7 +151,7 @@ func (a *App) GetOAuthImplicitRedirect(userId string, authRequest *model.Authori
 }
 
 func (a *App) GetOAuthCodeRedirect(userId string, authRequest *model.AuthorizeRequest) (string, *model.AppError) {
-	authData := &model.AuthData{UserId: userId, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectUri, State: authRequest.State, Scope: authRequest.Scope}
+	authData := &model.AuthData{UserId: userId, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectUri, State: authRequest.State, Scope: authRequest.Scope, CodeChallenge: authRequest.CodeChallenge, CodeChallengeMethod: authRequest.CodeChallengeMethod}
 	authData.Code = model.NewId() + model.NewId()
 
 	if result := <-a.Srv.Store.OAuth().SaveAuthData(authData); result.Err != nil {
@@ -161,6 +186,10 @@ func (a *App) AllowOAuthAppAccessToUser(userId string, authRequest *model.Author
 
 	switch authRequest.ResponseType {
 	case model.AUTHCODE_RESPONSE_TYPE:
+		// Public apps can't prove who they are with their secret, so their codes have to be tied to a code verifier
+		if oauthApp.IsPublic && len(authRequest.CodeChallenge) == 0 {
+			return authRequest.RedirectUri + "?error=invalid_request&error_description=" + url.QueryEscape("code challenge required") + "&state=" + authRequest.State, nil
+		}
 		redirectURI, err = a.GetOAuthCodeRedirect(userId, authRequest)
 	case model.IMPLICIT_RESPONSE_TYPE:
 		redirectURI, err = a.GetOAuthImplicitRedirect(userId, authRequest)
@@ -205,7 +234,7 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(userId string, authRequest *mod
 		return nil, err
 	}
 
-	session, err := a.newSession(oauthApp.Name, user)
+	session, err := a.newSession(oauthApp.Name, user, authRequest.Scope)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +249,10 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(userId string, authRequest *mod
 	return session, nil
 }
 
-func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError) {
+// GetOAuthAccessTokenForCodeFlow exchanges an authorization code or a refresh token for an access token. Apps must give
+// their client secret unless they're public, and codes that were requested with a PKCE code challenge must be exchanged
+// with the matching code verifier.
+func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, code, secret, codeVerifier, refreshToken string) (*model.AccessResponse, *model.AppError) {
 	if !a.Config().ServiceSettings.EnableOAuthServiceProvider {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
@@ -232,7 +264,11 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 		oauthApp = result.Data.(*model.OAuthApp)
 	}
 
-	if oauthApp.ClientSecret != secret {
+	if len(secret) == 0 {
+		if !oauthApp.IsPublic {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.bad_client_secret.app_error", nil, "", http.StatusBadRequest)
+		}
+	} else if oauthApp.ClientSecret != secret {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusForbidden)
 	}
 
@@ -257,6 +293,12 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.redirect_uri.app_error", nil, "", http.StatusBadRequest)
 		}
 
+		if len(authData.CodeChallenge) > 0 || len(codeVerifier) > 0 || oauthApp.IsPublic {
+			if !authData.VerifyCodeVerifier(codeVerifier) {
+				return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.code_verifier.app_error", nil, "", http.StatusBadRequest)
+			}
+		}
+
 		if result := <-a.Srv.Store.User().Get(authData.UserId); result.Err != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
 		} else {
@@ -267,7 +309,9 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal.app_error", nil, "", http.StatusInternalServerError)
 		} else if result.Data != nil {
 			accessData := result.Data.(*model.AccessData)
-			if accessData.IsExpired() {
+			// Tokens are replaced when the app was authorized again with a different scope
+			if accessData.IsExpired() || accessData.Scope != authData.Scope {
+				accessData.Scope = authData.Scope
 				if access, err := a.newSessionUpdateToken(oauthApp.Name, accessData, user); err != nil {
 					return nil, err
 				} else {
@@ -280,12 +324,13 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 					TokenType:    model.ACCESS_TOKEN_TYPE,
 					RefreshToken: accessData.RefreshToken,
 					ExpiresIn:    int32((accessData.ExpiresAt - model.GetMillis()) / 1000),
+					Scope:        accessData.Scope,
 				}
 			}
 		} else {
 			// create a new session and return new access token
 			var session *model.Session
-			if result, err := a.newSession(oauthApp.Name, user); err != nil {
+			if result, err := a.newSession(oauthApp.Name, user, authData.Scope); err != nil {
 				return nil, err
 			} else {
 				session = result
@@ -303,6 +348,7 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 				TokenType:    model.ACCESS_TOKEN_TYPE,
 				RefreshToken: accessData.RefreshToken,
 				ExpiresIn:    int32(*a.Config().ServiceSettings.SessionLengthSSOInDays * 60 * 60 * 24),
+				Scope:        accessData.Scope,
 			}
 		}
 
@@ -331,7 +377,7 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectUri, c
 	return accessRsp, nil
 }
 
-func (a *App) newSession(appName string, user *model.User) (*model.Session, *model.AppError) {
+func (a *App) newSession(appName string, user *model.User, scope string) (*model.Session, *model.AppError) {
 	// set new token an session
 	session := &model.Session{UserId: user.Id, Roles: user.Roles, IsOAuth: true}
 	session.GenerateCSRF()
@@ -339,6 +385,7 @@ func (a *App) newSession(appName string, user *model.User) (*model.Session, *mod
 	session.AddProp(model.SESSION_PROP_PLATFORM, appName)
 	session.AddProp(model.SESSION_PROP_OS, "OAuth2")
 	session.AddProp(model.SESSION_PROP_BROWSER, "OAuth2")
+	session.AddProp(model.SESSION_PROP_OAUTH_SCOPE, scope)
 
 	if result := <-a.Srv.Store.Session().Save(session); result.Err != nil {
 		return nil, model.NewAppError("newSession", "api.oauth.get_access_token.internal_session.app_error", nil, "", http.StatusInternalServerError)
@@ -354,7 +401,7 @@ func (a *App) newSessionUpdateToken(appName string, accessData *model.AccessData
 	var session *model.Session
 	<-a.Srv.Store.Session().Remove(accessData.Token) //remove the previous session
 
-	if result, err := a.newSession(appName, user); err != nil {
+	if result, err := a.newSession(appName, user, accessData.Scope); err != nil {
 		return nil, err
 	} else {
 		session = result
@@ -372,6 +419,7 @@ func (a *App) newSessionUpdateToken(appName string, accessData *model.AccessData
 		RefreshToken: accessData.RefreshToken,
 		TokenType:    model.ACCESS_TOKEN_TYPE,
 		ExpiresIn:    int32(*a.Config().ServiceSettings.SessionLengthSSOInDays * 60 * 60 * 24),
+		Scope:        accessData.Scope,
 	}
 
 	return accessRsp, nil
@@ -470,6 +518,122 @@ func (a *App) RegenerateOAuthAppSecret(app *model.OAuthApp) (*model.OAuthApp, *m
 	return app, nil
 }
 
+func (a *App) PatchOAuthApp(oldApp *model.OAuthApp, patch *model.OAuthAppPatch) (*model.OAuthApp, *model.AppError) {
+	if !a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("PatchOAuthApp", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	app := &model.OAuthApp{}
+	*app = *oldApp
+	app.Patch(patch)
+
+	if result := <-a.Srv.Store.OAuth().UpdateApp(app); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([2]*model.OAuthApp)[0], nil
+	}
+}
+
+func getOAuthAppIconPath(appId string) string {
+	return "oauth_apps/" + appId + "/icon.png"
+}
+
+func (a *App) GetOAuthAppIcon(app *model.OAuthApp) ([]byte, *model.AppError) {
+	if len(*a.Config().FileSettings.DriverName) == 0 {
+		return nil, model.NewAppError("GetOAuthAppIcon", "api.oauth.get_oauth_app_icon.storage.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if app.LastIconUpdate == 0 {
+		return nil, model.NewAppError("GetOAuthAppIcon", "api.oauth.get_oauth_app_icon.not_found.app_error", nil, "app_id="+app.Id, http.StatusNotFound)
+	}
+
+	data, err := a.ReadFile(getOAuthAppIconPath(app.Id))
+	if err != nil {
+		return nil, model.NewAppError("GetOAuthAppIcon", "api.oauth.get_oauth_app_icon.not_found.app_error", nil, err.Error(), http.StatusNotFound)
+	}
+
+	return data, nil
+}
+
+func (a *App) SetOAuthAppIcon(app *model.OAuthApp, imageData *multipart.FileHeader) (*model.OAuthApp, *model.AppError) {
+	file, err := imageData.Open()
+	if err != nil {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.open.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	defer file.Close()
+	return a.SetOAuthAppIconFromFile(app, file)
+}
+
+// SetOAuthAppIconFromFile scales an uploaded image to the size of an icon and stores it as the app's icon.
+func (a *App) SetOAuthAppIconFromFile(app *model.OAuthApp, file multipart.File) (*model.OAuthApp, *model.AppError) {
+	if !a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if len(*a.Config().FileSettings.DriverName) == 0 {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.storage.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	// Decode image config first to check dimensions before loading the whole thing into memory later on
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.decode_config.app_error", nil, err.Error(), http.StatusBadRequest)
+	} else if config.Width*config.Height > model.MaxImageSize {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.too_large.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	file.Seek(0, 0)
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.decode.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	file.Seek(0, 0)
+
+	orientation, _ := getImageOrientation(file)
+	img = makeImageUpright(img, orientation)
+
+	iconWidthAndHeight := 128
+	img = imaging.Fill(img, iconWidthAndHeight, iconWidthAndHeight, imaging.Center, imaging.Lanczos)
+
+	buf := new(bytes.Buffer)
+	if err = png.Encode(buf, img); err != nil {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.encode.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if _, err := a.WriteFile(buf, getOAuthAppIconPath(app.Id)); err != nil {
+		return nil, model.NewAppError("SetOAuthAppIcon", "api.oauth.set_oauth_app_icon.write_file.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	updatedApp := &model.OAuthApp{}
+	*updatedApp = *app
+	updatedApp.LastIconUpdate = model.GetMillis()
+
+	if result := <-a.Srv.Store.OAuth().UpdateApp(updatedApp); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([2]*model.OAuthApp)[0], nil
+	}
+}
+
+// RemoveOAuthAppIcon stops using the uploaded icon for an app. The file is left in place like removed team icons are.
+func (a *App) RemoveOAuthAppIcon(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
+	if !a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("RemoveOAuthAppIcon", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	updatedApp := &model.OAuthApp{}
+	*updatedApp = *app
+	updatedApp.LastIconUpdate = 0
+
+	if result := <-a.Srv.Store.OAuth().UpdateApp(updatedApp); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([2]*model.OAuthApp)[0], nil
+	}
+}
+
 func (a *App) RevokeAccessToken(token string) *model.AppError {
 	session, _ := a.GetSession(token)
 	schan := a.Srv.Store.Session().Remove(token)
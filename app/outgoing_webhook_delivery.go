@@ -0,0 +1,192 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	OUTGOING_WEBHOOK_DELIVERY_HISTORY_SIZE = 50
+	OUTGOING_WEBHOOK_MAX_RETRY_DELAY       = time.Minute
+)
+
+// The delay before the first retry of a delivery, which doubles with each retry after that
+var outgoingWebhookRetryBaseDelay = time.Second
+
+// outgoingWebhookDeliveries keeps the most recent deliveries of a hook so that their status can be checked.
+type outgoingWebhookDeliveries struct {
+	mutex      sync.Mutex
+	deliveries []*model.OutgoingWebhookDelivery
+}
+
+func (d *outgoingWebhookDeliveries) add(delivery *model.OutgoingWebhookDelivery) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.deliveries = append(d.deliveries, delivery)
+	if len(d.deliveries) > OUTGOING_WEBHOOK_DELIVERY_HISTORY_SIZE {
+		d.deliveries = d.deliveries[len(d.deliveries)-OUTGOING_WEBHOOK_DELIVERY_HISTORY_SIZE:]
+	}
+}
+
+// update runs a function that changes deliveries while they can't be read.
+func (d *outgoingWebhookDeliveries) update(f func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	f()
+}
+
+// snapshot returns copies of the deliveries, newest first.
+func (d *outgoingWebhookDeliveries) snapshot() []*model.OutgoingWebhookDelivery {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	deliveries := make([]*model.OutgoingWebhookDelivery, 0, len(d.deliveries))
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		delivery := *d.deliveries[i]
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries
+}
+
+func (a *App) getOutgoingWebhookDeliveries(hookId string) *outgoingWebhookDeliveries {
+	deliveries, _ := a.outgoingWebhookDeliveries.LoadOrStore(hookId, &outgoingWebhookDeliveries{})
+	return deliveries.(*outgoingWebhookDeliveries)
+}
+
+// GetOutgoingWebhookDeliveries returns the most recent deliveries of a hook's payloads by this server, newest first.
+func (a *App) GetOutgoingWebhookDeliveries(hookId string) []*model.OutgoingWebhookDelivery {
+	return a.getOutgoingWebhookDeliveries(hookId).snapshot()
+}
+
+// getOutgoingWebhookRetryDelay returns how long to wait before retrying a delivery that has failed a number of times.
+func getOutgoingWebhookRetryDelay(failures int) time.Duration {
+	delay := outgoingWebhookRetryBaseDelay
+	for i := 1; i < failures && delay < OUTGOING_WEBHOOK_MAX_RETRY_DELAY; i++ {
+		delay *= 2
+	}
+
+	if delay > OUTGOING_WEBHOOK_MAX_RETRY_DELAY {
+		delay = OUTGOING_WEBHOOK_MAX_RETRY_DELAY
+	}
+
+	return delay
+}
+
+// shouldRetryOutgoingWebhook returns true if a request failed in a way that might not happen again, which is when it
+// timed out or when the server responded with an error of its own.
+func shouldRetryOutgoingWebhook(resp *http.Response, err error) bool {
+	if err != nil {
+		netErr, ok := err.(net.Error)
+		return ok && netErr.Timeout()
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// deliverOutgoingWebhook sends a payload to one of a hook's callback URLs, signing it if the hook has a secret. Requests
+// that time out or fail with a server error are retried with exponential backoff. The response to the last attempt is
+// returned. Every attempt is added to the integration delivery log. Nothing is sent while the URL's circuit is open.
+func (a *App) deliverOutgoingWebhook(hook *model.OutgoingWebhook, url string, contentType string, body []byte, postId string) (*http.Response, error) {
+	deliveries := a.getOutgoingWebhookDeliveries(hook.Id)
+
+	delivery := &model.OutgoingWebhookDelivery{
+		Id:       model.NewId(),
+		HookId:   hook.Id,
+		PostId:   postId,
+		URL:      url,
+		Status:   model.OUTGOING_HOOK_DELIVERY_STATUS_PENDING,
+		CreateAt: model.GetMillis(),
+	}
+	deliveries.add(delivery)
+
+	maxRetries := *a.Config().ServiceSettings.OutgoingWebhookMaxRetries
+
+	for attempt := 0; ; attempt++ {
+		req, _ := http.NewRequest("POST", url, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set(model.HEADER_OUTGOING_HOOK_DELIVERY, delivery.Id)
+
+		if hook.SigningSecret != "" {
+			timestamp := time.Now().Unix()
+			req.Header.Set(model.HEADER_OUTGOING_HOOK_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+			req.Header.Set(model.HEADER_OUTGOING_HOOK_SIGNATURE, model.SignOutgoingWebhookPayload(hook.SigningSecret, timestamp, body))
+		}
+
+		// Requests aren't sent to URLs that keep failing, and they aren't retried since the URL won't be tried again soon
+		var resp *http.Response
+		var err error
+		circuitOpen := !a.allowIntegrationRequest(url)
+
+		start := time.Now()
+		if circuitOpen {
+			err = errIntegrationCircuitOpen
+		} else {
+			resp, err = a.HTTPClient(false).Do(req)
+			a.recordIntegrationResponse(url, resp, err)
+		}
+
+		if err != nil {
+			a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_OUTGOING_WEBHOOK, hook.Id, url, contentType, body, 0, time.Since(start), err.Error())
+		} else {
+			a.RecordIntegrationDelivery(model.INTEGRATION_TYPE_OUTGOING_WEBHOOK, hook.Id, url, contentType, body, resp.StatusCode, time.Since(start), "")
+		}
+
+		retry := !circuitOpen && attempt < maxRetries && shouldRetryOutgoingWebhook(resp, err)
+
+		var delay time.Duration
+		if retry {
+			delay = getOutgoingWebhookRetryDelay(attempt + 1)
+		}
+
+		deliveries.update(func() {
+			delivery.Attempts = attempt + 1
+			delivery.LastAttemptAt = model.GetMillis()
+			delivery.NextAttemptAt = 0
+
+			if err != nil {
+				delivery.StatusCode = 0
+				delivery.Error = err.Error()
+			} else {
+				delivery.StatusCode = resp.StatusCode
+				delivery.Error = ""
+			}
+
+			if retry {
+				delivery.Status = model.OUTGOING_HOOK_DELIVERY_STATUS_RETRYING
+				delivery.NextAttemptAt = delivery.LastAttemptAt + int64(delay/time.Millisecond)
+			} else if err == nil && resp.StatusCode < http.StatusBadRequest {
+				delivery.Status = model.OUTGOING_HOOK_DELIVERY_STATUS_SUCCEEDED
+			} else {
+				delivery.Status = model.OUTGOING_HOOK_DELIVERY_STATUS_FAILED
+			}
+		})
+
+		if !retry {
+			return resp, err
+		}
+
+		if err != nil {
+			mlog.Warn(fmt.Sprintf("Outgoing webhook request timed out, retrying in %v, err=%v", delay, err.Error()), mlog.String("hook_id", hook.Id))
+		} else {
+			consumeAndClose(resp)
+			mlog.Warn(fmt.Sprintf("Outgoing webhook request failed, retrying in %v, status_code=%v", delay, resp.StatusCode), mlog.String("hook_id", hook.Id))
+		}
+
+		time.Sleep(delay)
+	}
+}
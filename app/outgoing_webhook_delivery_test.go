@@ -0,0 +1,189 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestGetOutgoingWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, outgoingWebhookRetryBaseDelay, getOutgoingWebhookRetryDelay(1))
+	assert.Equal(t, 2*outgoingWebhookRetryBaseDelay, getOutgoingWebhookRetryDelay(2))
+	assert.Equal(t, 8*outgoingWebhookRetryBaseDelay, getOutgoingWebhookRetryDelay(4))
+	assert.Equal(t, OUTGOING_WEBHOOK_MAX_RETRY_DELAY, getOutgoingWebhookRetryDelay(100))
+}
+
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
+
+func TestShouldRetryOutgoingWebhook(t *testing.T) {
+	assert.True(t, shouldRetryOutgoingWebhook(nil, testTimeoutError{}))
+	assert.False(t, shouldRetryOutgoingWebhook(nil, http.ErrUseLastResponse))
+	assert.True(t, shouldRetryOutgoingWebhook(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.False(t, shouldRetryOutgoingWebhook(&http.Response{StatusCode: http.StatusBadRequest}, nil))
+	assert.False(t, shouldRetryOutgoingWebhook(&http.Response{StatusCode: http.StatusOK}, nil))
+}
+
+func TestOutgoingWebhookDeliveriesHistory(t *testing.T) {
+	deliveries := &outgoingWebhookDeliveries{}
+
+	var ids []string
+	for i := 0; i < OUTGOING_WEBHOOK_DELIVERY_HISTORY_SIZE+5; i++ {
+		delivery := &model.OutgoingWebhookDelivery{Id: model.NewId()}
+		deliveries.add(delivery)
+		ids = append(ids, delivery.Id)
+	}
+
+	snapshot := deliveries.snapshot()
+	require.Len(t, snapshot, OUTGOING_WEBHOOK_DELIVERY_HISTORY_SIZE)
+	assert.Equal(t, ids[len(ids)-1], snapshot[0].Id, "should return the newest delivery first")
+	assert.Equal(t, ids[5], snapshot[len(snapshot)-1].Id, "should drop the oldest deliveries")
+}
+
+func TestDeliverOutgoingWebhook(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.ServiceSettings.EnableOutgoingWebhooks = true
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+		*cfg.ServiceSettings.OutgoingWebhookMaxRetries = 2
+	})
+
+	oldDelay := outgoingWebhookRetryBaseDelay
+	outgoingWebhookRetryBaseDelay = time.Millisecond
+	defer func() {
+		outgoingWebhookRetryBaseDelay = oldDelay
+	}()
+
+	hook, err := th.App.CreateOutgoingWebhook(&model.OutgoingWebhook{
+		ChannelId:    th.BasicChannel.Id,
+		TeamId:       th.BasicChannel.TeamId,
+		CreatorId:    th.BasicUser.Id,
+		CallbackURLs: []string{"http://localhost"},
+		TriggerWords: []string{"abc"},
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, hook.SigningSecret)
+
+	body := []byte("text=abc")
+
+	t.Run("retries server errors and signs every attempt", func(t *testing.T) {
+		var requests int32
+		var deliveryIds []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ := ioutil.ReadAll(r.Body)
+			timestamp, _ := strconv.ParseInt(r.Header.Get(model.HEADER_OUTGOING_HOOK_TIMESTAMP), 10, 64)
+			assert.Equal(t, model.SignOutgoingWebhookPayload(hook.SigningSecret, timestamp, received), r.Header.Get(model.HEADER_OUTGOING_HOOK_SIGNATURE))
+			deliveryIds = append(deliveryIds, r.Header.Get(model.HEADER_OUTGOING_HOOK_DELIVERY))
+
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{"text": "ok"}`))
+		}))
+		defer ts.Close()
+
+		resp, err := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id)
+		require.Nil(t, err)
+		consumeAndClose(resp)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+		require.Len(t, deliveryIds, 2)
+		assert.Equal(t, deliveryIds[0], deliveryIds[1], "should send the same delivery id with retries")
+
+		deliveries := th.App.GetOutgoingWebhookDeliveries(hook.Id)
+		require.NotEmpty(t, deliveries)
+		assert.Equal(t, deliveryIds[0], deliveries[0].Id)
+		assert.Equal(t, model.OUTGOING_HOOK_DELIVERY_STATUS_SUCCEEDED, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+		assert.Equal(t, th.BasicPost.Id, deliveries[0].PostId)
+	})
+
+	t.Run("gives up after the maximum number of retries", func(t *testing.T) {
+		var requests int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		resp, err := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id)
+		require.Nil(t, err)
+		consumeAndClose(resp)
+		assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+
+		deliveries := th.App.GetOutgoingWebhookDeliveries(hook.Id)
+		assert.Equal(t, model.OUTGOING_HOOK_DELIVERY_STATUS_FAILED, deliveries[0].Status)
+		assert.Equal(t, 3, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	})
+
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		var requests int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		resp, err := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id)
+		require.Nil(t, err)
+		consumeAndClose(resp)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+		assert.Equal(t, model.OUTGOING_HOOK_DELIVERY_STATUS_FAILED, th.App.GetOutgoingWebhookDeliveries(hook.Id)[0].Status)
+	})
+
+	t.Run("stops sending to a URL that keeps failing", func(t *testing.T) {
+		var requests int32
+		var healthy int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if atomic.LoadInt32(&healthy) == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer ts.Close()
+
+		for atomic.LoadInt32(&requests) < INTEGRATION_CIRCUIT_FAILURE_THRESHOLD {
+			if resp, _ := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id); resp != nil {
+				consumeAndClose(resp)
+			}
+		}
+
+		_, err := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id)
+		assert.Equal(t, errIntegrationCircuitOpen, err)
+		assert.EqualValues(t, INTEGRATION_CIRCUIT_FAILURE_THRESHOLD, atomic.LoadInt32(&requests), "shouldn't send requests while the circuit is open")
+		assert.Equal(t, 1, th.App.GetOutgoingWebhookDeliveries(hook.Id)[0].Attempts, "shouldn't retry while the circuit is open")
+
+		oldDuration := integrationCircuitOpenDuration
+		integrationCircuitOpenDuration = 0
+		defer func() {
+			integrationCircuitOpenDuration = oldDuration
+		}()
+		atomic.StoreInt32(&healthy, 1)
+
+		resp, err := th.App.deliverOutgoingWebhook(hook, ts.URL, "application/x-www-form-urlencoded", body, th.BasicPost.Id)
+		require.Nil(t, err)
+		consumeAndClose(resp)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, th.App.allowIntegrationRequest(ts.URL), "should close the circuit after a successful probe")
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// outgoingWebhookEvent is a change in a channel, other than a new post, that outgoing webhooks can subscribe to. The
+// user is the one that the event is about, which is the author for events about posts.
+type outgoingWebhookEvent struct {
+	Type      string
+	ChannelId string
+	UserId    string
+	Post      *model.Post
+	OldPost   *model.Post
+	Reaction  *model.Reaction
+}
+
+// sendOutgoingWebhookEvent triggers the outgoing webhooks in a channel that subscribe to an event.
+func (a *App) sendOutgoingWebhookEvent(event *outgoingWebhookEvent) {
+	if !a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return
+	}
+
+	a.Go(func() {
+		if err := a.handleOutgoingWebhookEvent(event); err != nil {
+			mlog.Error("Unable to trigger outgoing webhooks for an event", mlog.String("event", event.Type), mlog.String("channel_id", event.ChannelId), mlog.Err(err))
+		}
+	})
+}
+
+func (a *App) handleOutgoingWebhookEvent(event *outgoingWebhookEvent) *model.AppError {
+	channel, err := a.GetChannel(event.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	hooks, err := a.getOutgoingWebhooksForChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	var relevantHooks []*model.OutgoingWebhook
+	triggerWords := make(map[string]string)
+	for _, hook := range hooks {
+		if !hook.HasTriggerEvent(event.Type) || (hook.ChannelId != channel.Id && len(hook.ChannelId) != 0) {
+			continue
+		}
+
+		// Edits are filtered by trigger words like new posts are so that hooks can be triggered by editing commands
+		if event.Type == model.OUTGOING_HOOK_EVENT_POST_EDITED {
+			matched, triggerWord := matchOutgoingWebhookTriggerWord(hook, event.Post)
+			if !matched {
+				continue
+			}
+			triggerWords[hook.Id] = triggerWord
+		}
+
+		relevantHooks = append(relevantHooks, hook)
+	}
+
+	if len(relevantHooks) == 0 {
+		return nil
+	}
+
+	team := &model.Team{}
+	if len(channel.TeamId) > 0 {
+		if team, err = a.GetTeam(channel.TeamId); err != nil {
+			return err
+		}
+	}
+
+	user, err := a.GetUser(event.UserId)
+	if err != nil {
+		return err
+	}
+
+	// Responses can only be posted as comments to posts that still exist
+	var replyPost *model.Post
+	if event.Type != model.OUTGOING_HOOK_EVENT_POST_DELETED {
+		replyPost = event.Post
+	}
+
+	for _, hook := range relevantHooks {
+		payload := &model.OutgoingWebhookPayload{
+			Token:       hook.Token,
+			TeamId:      hook.TeamId,
+			TeamDomain:  team.Name,
+			ChannelId:   channel.Id,
+			ChannelName: channel.Name,
+			Timestamp:   model.GetMillis(),
+			UserId:      user.Id,
+			UserName:    user.Username,
+			TriggerWord: triggerWords[hook.Id],
+			Event:       event.Type,
+		}
+
+		if event.Post != nil {
+			payload.PostId = event.Post.Id
+			payload.Text = event.Post.Message
+			payload.FileIds = strings.Join(event.Post.FileIds, ",")
+		}
+
+		if event.OldPost != nil {
+			payload.OriginalText = event.OldPost.Message
+		}
+
+		if event.Reaction != nil {
+			payload.EmojiName = event.Reaction.EmojiName
+		}
+
+		a.TriggerWebhook(payload, hook, replyPost, channel)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestMatchOutgoingWebhookTriggerWord(t *testing.T) {
+	channelId := model.NewId()
+
+	hook := &model.OutgoingWebhook{TriggerWords: model.StringArray{"cats", "dog"}, TriggerWhen: TRIGGERWORDS_EXACT_MATCH}
+
+	matched, triggerWord := matchOutgoingWebhookTriggerWord(hook, &model.Post{ChannelId: channelId, Message: "cats are great"})
+	assert.True(t, matched)
+	assert.Equal(t, "cats", triggerWord)
+
+	matched, _ = matchOutgoingWebhookTriggerWord(hook, &model.Post{ChannelId: channelId, Message: "dogs are great"})
+	assert.False(t, matched)
+
+	hook.TriggerWhen = TRIGGERWORDS_STARTS_WITH
+	matched, triggerWord = matchOutgoingWebhookTriggerWord(hook, &model.Post{ChannelId: channelId, Message: "dogs are great"})
+	assert.True(t, matched)
+	assert.Equal(t, "dog", triggerWord)
+
+	hook.ChannelId = model.NewId()
+	matched, _ = matchOutgoingWebhookTriggerWord(hook, &model.Post{ChannelId: channelId, Message: "cats are great"})
+	assert.False(t, matched, "should not match posts in other channels")
+
+	hook.ChannelId = channelId
+	hook.TriggerWords = nil
+	matched, triggerWord = matchOutgoingWebhookTriggerWord(hook, &model.Post{ChannelId: channelId, Message: "anything"})
+	assert.True(t, matched, "should match every post in the channel without trigger words")
+	assert.Equal(t, "", triggerWord)
+}
+
+func TestOutgoingWebhookEvents(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.ServiceSettings.EnableOutgoingWebhooks = true
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+	})
+
+	payloads := make(chan *model.OutgoingWebhookPayload, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		payloads <- &model.OutgoingWebhookPayload{
+			Event:        r.Form.Get("event"),
+			ChannelId:    r.Form.Get("channel_id"),
+			UserId:       r.Form.Get("user_id"),
+			PostId:       r.Form.Get("post_id"),
+			Text:         r.Form.Get("text"),
+			OriginalText: r.Form.Get("original_text"),
+			EmojiName:    r.Form.Get("emoji_name"),
+			TriggerWord:  r.Form.Get("trigger_word"),
+		}
+	}))
+	defer ts.Close()
+
+	waitForPayload := func(t *testing.T) *model.OutgoingWebhookPayload {
+		select {
+		case payload := <-payloads:
+			return payload
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the hook to be triggered")
+			return nil
+		}
+	}
+
+	channel := th.CreateChannel(th.BasicTeam)
+
+	hook, err := th.App.CreateOutgoingWebhook(&model.OutgoingWebhook{
+		TeamId:        th.BasicTeam.Id,
+		CreatorId:     th.BasicUser.Id,
+		CallbackURLs:  []string{ts.URL},
+		TriggerEvents: model.StringArray{model.OUTGOING_HOOK_EVENT_REACTION_ADDED, model.OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL},
+	})
+	require.Nil(t, err, "should allow hooks for the whole team without trigger words if they aren't triggered by posts")
+
+	post := th.CreatePost(channel)
+	select {
+	case <-payloads:
+		t.Fatal("should not be triggered by posts")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	t.Run("reaction added", func(t *testing.T) {
+		_, err := th.App.SaveReactionForPost(&model.Reaction{UserId: th.BasicUser2.Id, PostId: post.Id, EmojiName: "smile"})
+		require.Nil(t, err)
+
+		payload := waitForPayload(t)
+		assert.Equal(t, model.OUTGOING_HOOK_EVENT_REACTION_ADDED, payload.Event)
+		assert.Equal(t, channel.Id, payload.ChannelId)
+		assert.Equal(t, th.BasicUser2.Id, payload.UserId)
+		assert.Equal(t, post.Id, payload.PostId)
+		assert.Equal(t, "smile", payload.EmojiName)
+	})
+
+	t.Run("user joined channel", func(t *testing.T) {
+		require.Nil(t, th.App.JoinChannel(channel, th.BasicUser2.Id))
+
+		payload := waitForPayload(t)
+		assert.Equal(t, model.OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL, payload.Event)
+		assert.Equal(t, th.BasicUser2.Id, payload.UserId)
+		assert.Equal(t, "", payload.PostId)
+	})
+
+	hook.ChannelId = channel.Id
+	hook.TriggerWords = model.StringArray{"edited"}
+	hook.TriggerEvents = model.StringArray{model.OUTGOING_HOOK_EVENT_POST_EDITED, model.OUTGOING_HOOK_EVENT_POST_DELETED}
+	_, err = th.App.UpdateOutgoingWebhook(hook, hook)
+	require.Nil(t, err)
+
+	t.Run("post edited", func(t *testing.T) {
+		originalMessage := post.Message
+
+		post.Message = "not a trigger word"
+		_, err := th.App.UpdatePost(post, false)
+		require.Nil(t, err)
+
+		post.Message = "edited message"
+		_, err = th.App.UpdatePost(post, false)
+		require.Nil(t, err)
+
+		payload := waitForPayload(t)
+		assert.Equal(t, model.OUTGOING_HOOK_EVENT_POST_EDITED, payload.Event)
+		assert.Equal(t, "edited message", payload.Text)
+		assert.Equal(t, "not a trigger word", payload.OriginalText, "should only be triggered by edits with trigger words")
+		assert.NotEqual(t, originalMessage, payload.OriginalText)
+		assert.Equal(t, "edited", payload.TriggerWord)
+	})
+
+	t.Run("post deleted", func(t *testing.T) {
+		_, err := th.App.DeletePost(post.Id, th.BasicUser.Id)
+		require.Nil(t, err)
+
+		payload := waitForPayload(t)
+		assert.Equal(t, model.OUTGOING_HOOK_EVENT_POST_DELETED, payload.Event)
+		assert.Equal(t, post.Id, payload.PostId)
+		assert.Equal(t, "edited message", payload.Text)
+	})
+}
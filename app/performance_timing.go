@@ -0,0 +1,67 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// The stages of hot code paths that are timed. Only these names and the durations are ever recorded so that the
+// timings can be shared without revealing anything about the content of the server.
+const (
+	PERFORMANCE_TIMING_POST_CREATE             = "post_create"
+	PERFORMANCE_TIMING_POST_CREATE_VALIDATION  = "post_create_validation"
+	PERFORMANCE_TIMING_POST_CREATE_MODERATION  = "post_create_moderation"
+	PERFORMANCE_TIMING_POST_CREATE_MENTIONS    = "post_create_mentions"
+	PERFORMANCE_TIMING_POST_CREATE_METADATA    = "post_create_metadata"
+	PERFORMANCE_TIMING_POST_CREATE_PERSISTENCE = "post_create_persistence"
+	PERFORMANCE_TIMING_POST_CREATE_FAN_OUT     = "post_create_fan_out"
+	PERFORMANCE_TIMING_NOTIFICATION_FANOUT     = "notification_fanout"
+	PERFORMANCE_TIMING_PREPARE_FOR_CLIENT      = "prepare_for_client"
+)
+
+var noopPerformanceTimer = func() {}
+
+// StartPerformanceTimer starts timing a stage of a hot code path and returns the function that stops it. Only the
+// configured percentage of calls are timed. The others get a timer that does nothing.
+func (a *App) StartPerformanceTimer(stage string) func() {
+	if percent := *a.Config().MetricsSettings.PerformanceTimingSamplePercent; percent <= 0 || rand.Intn(100) >= percent {
+		return noopPerformanceTimer
+	}
+
+	start := time.Now()
+
+	return func() {
+		a.ObservePerformanceTiming(stage, time.Since(start))
+	}
+}
+
+// ObservePerformanceTiming records how long a stage took, both in memory and in the metrics server if there is one.
+func (a *App) ObservePerformanceTiming(stage string, elapsed time.Duration) {
+	histogram, ok := a.performanceTimings.Load(stage)
+	if !ok {
+		histogram, _ = a.performanceTimings.LoadOrStore(stage, utils.NewTimingHistogram())
+	}
+	histogram.(*utils.TimingHistogram).Observe(elapsed)
+
+	if a.Metrics != nil {
+		a.Metrics.ObservePerformanceTiming(stage, float64(elapsed)/float64(time.Second))
+	}
+}
+
+// GetPerformanceTimings returns the timings recorded for each stage since the server started or since they were last
+// reset.
+func (a *App) GetPerformanceTimings(reset bool) map[string]*utils.TimingHistogramSnapshot {
+	timings := make(map[string]*utils.TimingHistogramSnapshot)
+
+	a.performanceTimings.Range(func(stage, histogram interface{}) bool {
+		timings[stage.(string)] = histogram.(*utils.TimingHistogram).Snapshot(reset)
+		return true
+	})
+
+	return timings
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPerformanceTimings(t *testing.T) {
+	a := &App{}
+
+	assert.Empty(t, a.GetPerformanceTimings(false))
+
+	a.ObservePerformanceTiming(PERFORMANCE_TIMING_POST_CREATE, 3*time.Millisecond)
+	a.ObservePerformanceTiming(PERFORMANCE_TIMING_POST_CREATE, 40*time.Millisecond)
+	a.ObservePerformanceTiming(PERFORMANCE_TIMING_NOTIFICATION_FANOUT, time.Millisecond)
+
+	timings := a.GetPerformanceTimings(true)
+	require.Len(t, timings, 2)
+	assert.Equal(t, int64(2), timings[PERFORMANCE_TIMING_POST_CREATE].Count)
+	assert.InDelta(t, 43, timings[PERFORMANCE_TIMING_POST_CREATE].SumMs, 0.001)
+	assert.Equal(t, int64(1), timings[PERFORMANCE_TIMING_NOTIFICATION_FANOUT].Count)
+
+	timings = a.GetPerformanceTimings(false)
+	assert.Equal(t, int64(0), timings[PERFORMANCE_TIMING_POST_CREATE].Count, "timings should be cleared after a reset")
+}
@@ -60,6 +60,8 @@ func (a *App) ResetPermissionsSystem() *model.AppError {
 	// Now that the permissions system has been reset, re-run the migration to reinitialise it.
 	a.DoAdvancedPermissionsMigration()
 	a.DoEmojisPermissionsMigration()
+	a.DoPrivateOutgoingWebhooksPermissionsMigration()
+	a.DoBotsPermissionsMigration()
 
 	return nil
 }
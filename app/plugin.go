@@ -41,6 +41,7 @@ func (a *App) SyncPluginsActiveState() {
 			// If it's not enabled we need to deactivate it
 			if !pluginEnabled {
 				deactivated := a.Plugins.Deactivate(pluginId)
+				a.UnschedulePluginJobs(pluginId)
 				if deactivated && plugin.Manifest.HasClient() {
 					message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_PLUGIN_DISABLED, "", "", "", nil)
 					message.Add("manifest", plugin.Manifest.ClientManifest())
@@ -116,6 +117,14 @@ func (a *App) InitPlugins(pluginDir, webappPluginDir string) {
 		a.Plugins = env
 	}
 
+	if subpath, err := utils.GetSubpathFromConfig(a.Config()); err == nil {
+		a.Plugins.SetWebappSubpath(subpath)
+	}
+	a.Plugins.SetMemoryLimit(a.getPluginMemoryLimit)
+
+	a.startPluginJobs()
+	a.pluginHealthCheckTask = model.CreateRecurringTask("Plugin Health Check", a.Plugins.CheckPluginHealth, plugin.HealthCheckInterval)
+
 	prepackagedPluginsDir, found := utils.FindDir("prepackaged_plugins")
 	if found {
 		if err := filepath.Walk(prepackagedPluginsDir, func(walkPath string, info os.FileInfo, err error) error {
@@ -155,6 +164,12 @@ func (a *App) ShutDownPlugins() {
 
 	mlog.Info("Shutting down plugins")
 
+	a.stopPluginJobs()
+
+	if a.pluginHealthCheckTask != nil {
+		a.pluginHealthCheckTask.Cancel()
+		a.pluginHealthCheckTask = nil
+	}
 	a.Plugins.Shutdown()
 
 	a.RemoveConfigListener(a.PluginConfigListenerId)
@@ -286,3 +301,44 @@ func (a *App) GetPlugins() (*model.PluginsResponse, *model.AppError) {
 
 	return resp, nil
 }
+
+// GetPluginSettingOptions asks an active plugin for the options of one of its dynamic dropdown settings.
+func (a *App) GetPluginSettingOptions(pluginId, key string) ([]*model.PluginOption, *model.AppError) {
+	if !a.PluginsReady() {
+		return nil, model.NewAppError("GetPluginSettingOptions", "app.plugin.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	var setting *model.PluginSetting
+	for _, bundle := range a.Plugins.Active() {
+		if bundle.Manifest == nil || bundle.Manifest.Id != pluginId || bundle.Manifest.SettingsSchema == nil {
+			continue
+		}
+
+		for _, s := range bundle.Manifest.SettingsSchema.Settings {
+			if strings.EqualFold(s.Key, key) {
+				setting = s
+				break
+			}
+		}
+	}
+
+	if setting == nil || !setting.Dynamic {
+		return nil, model.NewAppError("GetPluginSettingOptions", "app.plugin.setting_not_found.app_error", nil, "plugin_id="+pluginId+", key="+key, http.StatusNotFound)
+	}
+
+	hooks, err := a.Plugins.HooksForPlugin(pluginId)
+	if err != nil {
+		return nil, model.NewAppError("GetPluginSettingOptions", "app.plugin.setting_options.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	options, appErr := hooks.GetSettingOptions(&plugin.Context{}, setting.Key)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if options == nil {
+		options = []*model.PluginOption{}
+	}
+
+	return options, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -18,14 +19,24 @@ type PluginAPI struct {
 	app      *App
 	logger   *mlog.SugarLogger
 	manifest *model.Manifest
+
+	// The number of calls that the plugin has made over RPC during the current minute
+	apiCallsLock   sync.Mutex
+	apiCallsMinute int64
+	apiCalls       int
 }
 
 func NewPluginAPI(a *App, manifest *model.Manifest) *PluginAPI {
+	logger := a.Log.With(mlog.String("plugin_id", manifest.Id))
+	if a.Plugins != nil {
+		logger = logger.Tee(a.Plugins.LogBuffer(manifest.Id))
+	}
+
 	return &PluginAPI{
 		id:       manifest.Id,
 		manifest: manifest,
 		app:      a,
-		logger:   a.Log.With(mlog.String("plugin_id", manifest.Id)).Sugar(),
+		logger:   logger.Sugar(),
 	}
 }
 
@@ -65,6 +76,15 @@ func (api *PluginAPI) UnregisterCommand(teamId, trigger string) error {
 	return nil
 }
 
+func (api *PluginAPI) ScheduleJob(job *model.PluginJob) *model.AppError {
+	return api.app.SchedulePluginJob(api.id, job)
+}
+
+func (api *PluginAPI) UnscheduleJob(jobId string) *model.AppError {
+	api.app.UnschedulePluginJob(api.id, jobId)
+	return nil
+}
+
 func (api *PluginAPI) GetSession(sessionId string) (*model.Session, *model.AppError) {
 	session, err := api.app.GetSessionById(sessionId)
 
@@ -184,6 +204,15 @@ func (api *PluginAPI) UpdateUserStatus(userId, status string) (*model.Status, *m
 
 	return api.app.GetStatus(userId)
 }
+
+func (api *PluginAPI) UpdateUserCustomStatus(userId string, customStatus *model.CustomStatus) *model.AppError {
+	return api.app.SetCustomStatus(userId, customStatus)
+}
+
+func (api *PluginAPI) RemoveUserCustomStatus(userId string) *model.AppError {
+	return api.app.RemoveCustomStatus(userId)
+}
+
 func (api *PluginAPI) GetLDAPUserAttributes(userId string, attributes []string) (map[string]string, *model.AppError) {
 	if api.app.Ldap == nil {
 		return nil, model.NewAppError("GetLdapUserAttributes", "ent.ldap.disabled.app_error", nil, "", http.StatusNotImplemented)
@@ -229,6 +258,10 @@ func (api *PluginAPI) GetChannelByNameForTeamName(teamName, channelName string,
 	return api.app.GetChannelByNameForTeamName(channelName, teamName, includeDeleted)
 }
 
+func (api *PluginAPI) SearchChannels(teamId, term string) (*model.ChannelList, *model.AppError) {
+	return api.app.SearchChannels(teamId, term)
+}
+
 func (api *PluginAPI) GetDirectChannel(userId1, userId2 string) (*model.Channel, *model.AppError) {
 	return api.app.GetDirectChannel(userId1, userId2)
 }
@@ -270,6 +303,22 @@ func (api *PluginAPI) DeleteChannelMember(channelId, userId string) *model.AppEr
 	return api.app.LeaveChannel(channelId, userId)
 }
 
+func (api *PluginAPI) GetChannelSidebarCategories(userId, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	return api.app.GetSidebarCategories(userId, teamId)
+}
+
+func (api *PluginAPI) CreateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	return api.app.CreateSidebarCategory(userId, teamId, category)
+}
+
+func (api *PluginAPI) UpdateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	return api.app.UpdateSidebarCategory(userId, teamId, category)
+}
+
+func (api *PluginAPI) DeleteChannelSidebarCategory(userId, teamId, categoryId string) *model.AppError {
+	return api.app.DeleteSidebarCategory(userId, teamId, categoryId)
+}
+
 func (api *PluginAPI) CreatePost(post *model.Post) (*model.Post, *model.AppError) {
 	return api.app.CreatePostMissingChannel(post, true)
 }
@@ -290,6 +339,14 @@ func (api *PluginAPI) SendEphemeralPost(userId string, post *model.Post) *model.
 	return api.app.SendEphemeralPost(userId, post)
 }
 
+func (api *PluginAPI) UpdateEphemeralPost(userId string, post *model.Post) *model.Post {
+	return api.app.UpdateEphemeralPost(userId, post)
+}
+
+func (api *PluginAPI) DeleteEphemeralPost(userId, postId string) {
+	api.app.DeleteEphemeralPost(userId, postId)
+}
+
 func (api *PluginAPI) DeletePost(postId string) *model.AppError {
 	_, err := api.app.DeletePost(postId, api.id)
 	return err
@@ -299,6 +356,15 @@ func (api *PluginAPI) GetPost(postId string) (*model.Post, *model.AppError) {
 	return api.app.GetSinglePost(postId)
 }
 
+func (api *PluginAPI) SearchPosts(teamId, userId, terms string, isOrSearch bool) (*model.PostList, *model.AppError) {
+	results, err := api.app.SearchPostsInTeam(terms, userId, teamId, isOrSearch, false, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return results.PostList, nil
+}
+
 func (api *PluginAPI) UpdatePost(post *model.Post) (*model.Post, *model.AppError) {
 	return api.app.UpdatePost(post, false)
 }
@@ -316,9 +382,33 @@ func (api *PluginAPI) ReadFile(path string) ([]byte, *model.AppError) {
 }
 
 func (api *PluginAPI) KVSet(key string, value []byte) *model.AppError {
+	if err := api.app.checkPluginKVStorageLimit(api.id, key, value); err != nil {
+		return err
+	}
+
 	return api.app.SetPluginKey(api.id, key, value)
 }
 
+func (api *PluginAPI) KVSetWithExpiry(key string, value []byte, expireInSeconds int64) *model.AppError {
+	if err := api.app.checkPluginKVStorageLimit(api.id, key, value); err != nil {
+		return err
+	}
+
+	return api.app.SetPluginKeyWithExpiry(api.id, key, value, expireInSeconds)
+}
+
+func (api *PluginAPI) KVCompareAndSet(key string, oldValue, newValue []byte) (bool, *model.AppError) {
+	if err := api.app.checkPluginKVStorageLimit(api.id, key, newValue); err != nil {
+		return false, err
+	}
+
+	return api.app.CompareAndSetPluginKey(api.id, key, oldValue, newValue, 0)
+}
+
+func (api *PluginAPI) KVCompareAndDelete(key string, oldValue []byte) (bool, *model.AppError) {
+	return api.app.CompareAndDeletePluginKey(api.id, key, oldValue)
+}
+
 func (api *PluginAPI) KVGet(key string) ([]byte, *model.AppError) {
 	return api.app.GetPluginKey(api.id, key)
 }
@@ -175,3 +175,77 @@ func TestPluginAPILoadPluginConfigurationDefaults(t *testing.T) {
 	_, ret := hooks.MessageWillBePosted(nil, nil)
 	assert.Equal(t, "override35true", ret)
 }
+
+func TestPluginAPISearchChannels(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	api := th.SetupPluginAPI()
+
+	channels, err := api.SearchChannels(th.BasicTeam.Id, th.BasicChannel.Name)
+	require.Nil(t, err)
+	require.Len(t, *channels, 1)
+	assert.Equal(t, th.BasicChannel.Id, (*channels)[0].Id)
+
+	channels, err = api.SearchChannels(th.BasicTeam.Id, model.NewId())
+	require.Nil(t, err)
+	assert.Empty(t, *channels)
+}
+
+func TestPluginAPISearchPosts(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	api := th.SetupPluginAPI()
+
+	post, err := th.App.CreatePost(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "a post about kumquats",
+	}, th.BasicChannel, false)
+	require.Nil(t, err)
+
+	posts, err := api.SearchPosts(th.BasicTeam.Id, th.BasicUser.Id, "kumquats", false)
+	require.Nil(t, err)
+	require.Len(t, posts.Order, 1)
+	assert.Equal(t, post.Id, posts.Order[0])
+
+	posts, err = api.SearchPosts(th.BasicTeam.Id, th.BasicUser.Id, "kumquats persimmons", false)
+	require.Nil(t, err)
+	assert.Empty(t, posts.Order, "should match all of the terms")
+
+	posts, err = api.SearchPosts(th.BasicTeam.Id, th.BasicUser.Id, "kumquats persimmons", true)
+	require.Nil(t, err)
+	assert.Len(t, posts.Order, 1, "should match any of the terms")
+}
+
+func TestPluginAPIChannelSidebarCategories(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+	api := th.SetupPluginAPI()
+
+	category, err := api.CreateChannelSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, &model.SidebarCategory{
+		DisplayName: "Projects",
+		ChannelIds:  []string{th.BasicChannel.Id},
+	})
+	require.Nil(t, err)
+
+	categories, err := api.GetChannelSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	require.Nil(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, category.Id, categories[0].Id)
+
+	category.DisplayName = "Old Projects"
+	category.ChannelIds = nil
+	category, err = api.UpdateChannelSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, category)
+	require.Nil(t, err)
+	assert.Equal(t, "Old Projects", category.DisplayName)
+	assert.Empty(t, category.ChannelIds)
+
+	_, err = api.UpdateChannelSidebarCategory(th.BasicUser2.Id, th.BasicTeam.Id, category)
+	require.NotNil(t, err)
+
+	require.Nil(t, api.DeleteChannelSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, category.Id))
+
+	categories, err = api.GetChannelSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	require.Nil(t, err)
+	assert.Empty(t, categories)
+}
@@ -22,6 +22,16 @@ func (a *App) RegisterPluginCommand(pluginId string, command *model.Command) err
 		return fmt.Errorf("invalid command")
 	}
 
+	if command.AutocompleteData != nil {
+		if !strings.EqualFold(command.AutocompleteData.Trigger, command.Trigger) {
+			return fmt.Errorf("invalid command autocomplete data")
+		}
+
+		if err := command.AutocompleteData.IsValidForPlugin(); err != nil {
+			return err
+		}
+	}
+
 	command = &model.Command{
 		Trigger:          strings.ToLower(command.Trigger),
 		TeamId:           command.TeamId,
@@ -29,6 +39,9 @@ func (a *App) RegisterPluginCommand(pluginId string, command *model.Command) err
 		AutoCompleteDesc: command.AutoCompleteDesc,
 		AutoCompleteHint: command.AutoCompleteHint,
 		DisplayName:      command.DisplayName,
+		Description:      command.Description,
+		AutocompleteData: command.AutocompleteData,
+		PluginId:         pluginId,
 	}
 
 	a.pluginCommandsLock.Lock()
@@ -101,11 +114,13 @@ func (a *App) ExecutePluginCommand(args *model.CommandArgs) (*model.Command, *mo
 
 	for _, pc := range a.pluginCommands {
 		if (pc.Command.TeamId == "" || pc.Command.TeamId == args.TeamId) && pc.Command.Trigger == trigger {
-			pluginHooks, err := a.Plugins.HooksForPlugin(pc.PluginId)
-			if err != nil {
+			var response *model.CommandResponse
+			var appErr *model.AppError
+			if err := a.Plugins.RunPluginHook(pc.PluginId, func(hooks plugin.Hooks) {
+				response, appErr = hooks.ExecuteCommand(&plugin.Context{}, args)
+			}); err != nil {
 				return pc.Command, nil, model.NewAppError("ExecutePluginCommand", "model.plugin_command.error.app_error", nil, "err="+err.Error(), http.StatusInternalServerError)
 			}
-			response, appErr := pluginHooks.ExecuteCommand(&plugin.Context{}, args)
 			return pc.Command, response, appErr
 		}
 	}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+func TestPluginCommandAutocomplete(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"net/http"
+
+			"github.com/mattermost/mattermost-server/model"
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) OnActivate() error {
+			return p.API.RegisterCommand(&model.Command{
+				Trigger:      "projects",
+				AutoComplete: true,
+				AutocompleteData: &model.AutocompleteData{
+					Trigger: "projects",
+					Arguments: []*model.AutocompleteArg{
+						{Hint: "[project]", Type: model.AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST, FetchURL: "/autocomplete/projects"},
+					},
+				},
+			})
+		}
+
+		func (p *MyPlugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/autocomplete/projects" || r.Header.Get("Mattermost-User-Id") != r.URL.Query().Get("user_id") {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Write([]byte(model.AutocompleteListItemsToJson([]*model.AutocompleteListItem{
+				{Item: "server"},
+				{Item: "webapp"},
+			})))
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	commands := th.App.PluginCommandsForTeam(th.BasicTeam.Id)
+	require.Len(t, commands, 1)
+	assert.Equal(t, th.App.Plugins.Active()[0].Manifest.Id, commands[0].PluginId)
+
+	suggestions, err := th.App.GetCommandAutocompleteSuggestions("/projects w", th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Id, utils.T)
+	require.Nil(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "/projects webapp", suggestions[0].Complete)
+
+	registerErr := th.App.RegisterPluginCommand("otherplugin", &model.Command{
+		Trigger: "other",
+		AutocompleteData: &model.AutocompleteData{
+			Trigger: "other",
+			Arguments: []*model.AutocompleteArg{
+				{Type: model.AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST, FetchURL: "https://example.com/items"},
+			},
+		},
+	})
+	assert.NotNil(t, registerErr, "plugins should fetch dynamic lists from their own ServeHTTP hook")
+}
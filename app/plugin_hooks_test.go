@@ -791,3 +791,244 @@ func TestUserHasLoggedIn(t *testing.T) {
 		t.Errorf("Expected firstname overwrite, got default")
 	}
 }
+
+func TestHookLinkMetadataWillBeSaved(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableLinkPreviews = true
+		*cfg.ServiceSettings.LinkPreviewMode = model.LINK_PREVIEW_MODE_OFFLINE
+		*cfg.ServiceSettings.LinkPreviewFixturesFile = "tests/link-preview-fixtures.json"
+	})
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"strings"
+
+			"github.com/dyatlov/go-opengraph/opengraph"
+
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) LinkMetadataWillBeSaved(c *plugin.Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph {
+			if !strings.HasPrefix(requestURL, "https://wiki.example.com/") {
+				return nil
+			}
+
+			replacement := opengraph.NewOpenGraph()
+			replacement.Type = "website"
+			replacement.URL = requestURL
+			replacement.Title = "Internal wiki page"
+			replacement.Images = []*opengraph.Image{{URL: "/logo.png"}}
+			return replacement
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	preview := th.App.fetchLinkPreview("https://wiki.example.com/page")
+	assert.Equal(t, "Internal wiki page", preview.Title)
+	require.Len(t, preview.Images, 1)
+	assert.Equal(t, "https://wiki.example.com/logo.png", preview.Images[0].URL, "should make the plugin's URLs absolute")
+
+	preview = th.App.fetchLinkPreview("https://www.example.com/article")
+	assert.Equal(t, "Fixture Article", preview.Title, "should keep the metadata when the plugin returns nil")
+}
+
+func TestHookExecuteScheduledJob(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/model"
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) OnActivate() error {
+			if err := p.API.ScheduleJob(&model.PluginJob{Id: "once", RunAt: 1}); err != nil {
+				return err
+			}
+			if err := p.API.ScheduleJob(&model.PluginJob{Id: "recurring", Interval: 3600}); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		func (p *MyPlugin) ExecuteScheduledJob(c *plugin.Context, jobId string) {
+			p.API.KVSet(jobId, []byte("ran"))
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	pluginId := th.App.Plugins.Active()[0].Manifest.Id
+	require.Len(t, th.App.getPluginJobs(), 2)
+
+	th.App.runDuePluginJobs()
+
+	for _, jobId := range []string{"once", "recurring"} {
+		var value []byte
+		for i := 0; i < 50 && value == nil; i++ {
+			time.Sleep(100 * time.Millisecond)
+			value, _ = th.App.GetPluginKey(pluginId, jobId)
+		}
+		assert.Equal(t, []byte("ran"), value, "should have run "+jobId)
+	}
+
+	jobs := th.App.getPluginJobs()
+	require.Len(t, jobs, 1, "should forget a one-shot job once it's run")
+	assert.Equal(t, "recurring", jobs[0].Job.Id)
+
+	slot := jobs[0].Job.Slot(model.GetMillis())
+	claimed, err := th.App.claimPluginJobRun(pluginId, "recurring", slot)
+	require.Nil(t, err)
+	assert.False(t, claimed, "should only run a job once per interval")
+
+	claimed, err = th.App.claimPluginJobRun(pluginId, "recurring", slot-1)
+	require.Nil(t, err)
+	assert.False(t, claimed, "should not run an earlier run of a job")
+
+	claimed, err = th.App.claimPluginJobRun(pluginId, "recurring", slot+1)
+	require.Nil(t, err)
+	assert.True(t, claimed)
+
+	th.App.UnschedulePluginJobs(pluginId)
+	assert.Empty(t, th.App.getPluginJobs())
+}
+
+func TestHookReactionHasBeenAddedAndRemoved(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/model"
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) ReactionHasBeenAdded(c *plugin.Context, reaction *model.Reaction, post *model.Post) {
+			p.API.KVSet("added", []byte(reaction.EmojiName+":"+post.Message))
+		}
+
+		func (p *MyPlugin) ReactionHasBeenRemoved(c *plugin.Context, reaction *model.Reaction, post *model.Post) {
+			p.API.KVSet("removed", []byte(reaction.EmojiName+":"+post.Message))
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	pluginId := th.App.Plugins.Active()[0].Manifest.Id
+
+	waitForKey := func(key string) []byte {
+		var value []byte
+		for i := 0; i < 50 && value == nil; i++ {
+			time.Sleep(100 * time.Millisecond)
+			value, _ = th.App.GetPluginKey(pluginId, key)
+		}
+		return value
+	}
+
+	reaction := &model.Reaction{
+		UserId:    th.BasicUser.Id,
+		PostId:    th.BasicPost.Id,
+		EmojiName: "smile",
+	}
+
+	_, err := th.App.SaveReactionForPost(reaction)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("smile:"+th.BasicPost.Message), waitForKey("added"))
+
+	err = th.App.DeleteReactionForPost(reaction)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("smile:"+th.BasicPost.Message), waitForKey("removed"))
+}
+
+func TestHookUserStatusHasChanged(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/model"
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) UserStatusHasChanged(c *plugin.Context, status *model.Status) {
+			if status.Status != model.STATUS_DND {
+				return
+			}
+
+			if err := p.API.UpdateUserCustomStatus(status.UserId, &model.CustomStatus{Emoji: "no_bell", Text: "Focusing"}); err != nil {
+				p.API.KVSet("error", []byte(err.Error()))
+				return
+			}
+			p.API.KVSet("status", []byte(status.UserId+":"+status.Status))
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	pluginId := th.App.Plugins.Active()[0].Manifest.Id
+
+	th.App.SetStatusDoNotDisturb(th.BasicUser.Id)
+
+	var value []byte
+	for i := 0; i < 50 && value == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		value, _ = th.App.GetPluginKey(pluginId, "status")
+	}
+	assert.Equal(t, []byte(th.BasicUser.Id+":"+model.STATUS_DND), value)
+
+	user, err := th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	customStatus := user.GetCustomStatus()
+	require.NotNil(t, customStatus)
+	assert.Equal(t, "Focusing", customStatus.Text)
+
+	require.Nil(t, th.App.RemoveCustomStatus(th.BasicUser.Id))
+	user, err = th.App.GetUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Nil(t, user.GetCustomStatus())
+}
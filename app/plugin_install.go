@@ -16,12 +16,14 @@ import (
 	"github.com/mattermost/mattermost-server/utils"
 )
 
-// InstallPlugin unpacks and installs a plugin but does not enable or activate it.
+// InstallPlugin unpacks and installs a plugin but does not enable or activate it. If replace is set and the plugin is
+// already active, the new version is activated in its place without any downtime.
 func (a *App) InstallPlugin(pluginFile io.Reader, replace bool) (*model.Manifest, *model.AppError) {
-	return a.installPlugin(pluginFile, replace)
+	return a.installPlugin(pluginFile, replace, "")
 }
 
-func (a *App) installPlugin(pluginFile io.Reader, replace bool) (*model.Manifest, *model.AppError) {
+// installPlugin unpacks and installs a plugin. When expectedId is set, the plugin is only installed if it has that id.
+func (a *App) installPlugin(pluginFile io.Reader, replace bool, expectedId string) (*model.Manifest, *model.AppError) {
 	if a.Plugins == nil || !*a.Config().PluginSettings.Enable {
 		return nil, model.NewAppError("installPlugin", "app.plugin.disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
@@ -55,6 +57,10 @@ func (a *App) installPlugin(pluginFile io.Reader, replace bool) (*model.Manifest
 		return nil, model.NewAppError("installPlugin", "app.plugin.invalid_id.app_error", map[string]interface{}{"Min": plugin.MinIdLength, "Max": plugin.MaxIdLength, "Regex": plugin.ValidIdRegex}, "", http.StatusBadRequest)
 	}
 
+	if expectedId != "" && manifest.Id != expectedId {
+		return nil, model.NewAppError("installPlugin", "app.plugin.install_unexpected_id.app_error", nil, "expected_id="+expectedId+", id="+manifest.Id, http.StatusBadRequest)
+	}
+
 	bundles, err := a.Plugins.Available()
 	if err != nil {
 		return nil, model.NewAppError("installPlugin", "app.plugin.install.app_error", nil, err.Error(), http.StatusInternalServerError)
@@ -67,6 +73,10 @@ func (a *App) installPlugin(pluginFile io.Reader, replace bool) (*model.Manifest
 				return nil, model.NewAppError("installPlugin", "app.plugin.install_id.app_error", nil, "", http.StatusBadRequest)
 			}
 
+			if a.Plugins.IsActive(manifest.Id) {
+				return a.upgradePlugin(manifest.Id, tmpPluginDir)
+			}
+
 			if err := a.RemovePlugin(manifest.Id); err != nil {
 				return nil, model.NewAppError("installPlugin", "app.plugin.install_id_failed_remove.app_error", nil, "", http.StatusBadRequest)
 			}
@@ -86,6 +96,28 @@ func (a *App) installPlugin(pluginFile io.Reader, replace bool) (*model.Manifest
 	return manifest, nil
 }
 
+// upgradePlugin replaces an active plugin with the version in sourceDir without deactivating it, so that the old
+// version keeps handling requests until the new version has started.
+func (a *App) upgradePlugin(id string, sourceDir string) (*model.Manifest, *model.AppError) {
+	manifest, err := a.Plugins.Upgrade(id, sourceDir)
+	if err != nil {
+		return nil, model.NewAppError("upgradePlugin", "app.plugin.upgrade.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	// Clients load the new webapp bundle when they're told that the plugin was enabled with a different manifest
+	if manifest.HasClient() {
+		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_PLUGIN_ENABLED, "", "", "", nil)
+		message.Add("manifest", manifest.ClientManifest())
+		a.Publish(message)
+	}
+
+	if err := a.notifyPluginStatusesChanged(); err != nil {
+		mlog.Error("failed to notify plugin status changed", mlog.Err(err))
+	}
+
+	return manifest, nil
+}
+
 func (a *App) RemovePlugin(id string) *model.AppError {
 	return a.removePlugin(id)
 }
@@ -0,0 +1,187 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+// The prefix of the plugin key values that record when a plugin's jobs last ran. Keys set by plugins are hashed with
+// base64, which never contains a colon, so they can't collide with these.
+const PLUGIN_JOB_KEY_PREFIX = "job:"
+
+// How often the jobs that plugins have scheduled are checked for whether they're due
+var pluginJobCheckInterval = 15 * time.Second
+
+type PluginScheduledJob struct {
+	Job      *model.PluginJob
+	PluginId string
+}
+
+// SchedulePluginJob schedules a job for a plugin, replacing any job that the plugin already scheduled with the same id.
+// Jobs aren't persisted, so plugins should schedule them whenever they're activated.
+func (a *App) SchedulePluginJob(pluginId string, job *model.PluginJob) *model.AppError {
+	if err := job.IsValid(); err != nil {
+		return err
+	}
+
+	job = &model.PluginJob{
+		Id:       job.Id,
+		Interval: job.Interval,
+		RunAt:    job.RunAt,
+	}
+
+	a.pluginJobsLock.Lock()
+	defer a.pluginJobsLock.Unlock()
+
+	for _, pj := range a.pluginJobs {
+		if pj.PluginId == pluginId && pj.Job.Id == job.Id {
+			pj.Job = job
+			return nil
+		}
+	}
+
+	a.pluginJobs = append(a.pluginJobs, &PluginScheduledJob{
+		Job:      job,
+		PluginId: pluginId,
+	})
+	return nil
+}
+
+func (a *App) UnschedulePluginJob(pluginId, jobId string) {
+	a.pluginJobsLock.Lock()
+	defer a.pluginJobsLock.Unlock()
+
+	var remaining []*PluginScheduledJob
+	for _, pj := range a.pluginJobs {
+		if pj.PluginId != pluginId || pj.Job.Id != jobId {
+			remaining = append(remaining, pj)
+		}
+	}
+	a.pluginJobs = remaining
+}
+
+func (a *App) UnschedulePluginJobs(pluginId string) {
+	a.pluginJobsLock.Lock()
+	defer a.pluginJobsLock.Unlock()
+
+	var remaining []*PluginScheduledJob
+	for _, pj := range a.pluginJobs {
+		if pj.PluginId != pluginId {
+			remaining = append(remaining, pj)
+		}
+	}
+	a.pluginJobs = remaining
+}
+
+func (a *App) getPluginJobs() []*PluginScheduledJob {
+	a.pluginJobsLock.RLock()
+	defer a.pluginJobsLock.RUnlock()
+
+	jobs := make([]*PluginScheduledJob, len(a.pluginJobs))
+	copy(jobs, a.pluginJobs)
+	return jobs
+}
+
+// runDuePluginJobs runs the plugin jobs that are due on this server. Every server in a cluster checks the jobs that
+// were scheduled with it, but only the one that claims a job's current run invokes the plugin.
+func (a *App) runDuePluginJobs() {
+	if !a.PluginsReady() {
+		return
+	}
+
+	now := model.GetMillis()
+
+	for _, pj := range a.getPluginJobs() {
+		slot := pj.Job.Slot(now)
+		if slot < 0 {
+			continue
+		}
+
+		hooks, err := a.Plugins.HooksForPlugin(pj.PluginId)
+		if err != nil {
+			// The plugin isn't active anymore, and it'll schedule its jobs again if it's reactivated
+			a.UnschedulePluginJobs(pj.PluginId)
+			continue
+		}
+
+		claimed, appErr := a.claimPluginJobRun(pj.PluginId, pj.Job.Id, slot)
+		if appErr != nil {
+			mlog.Error("Unable to claim a plugin job", mlog.String("plugin_id", pj.PluginId), mlog.String("job_id", pj.Job.Id), mlog.Err(appErr))
+			continue
+		}
+
+		if !pj.Job.IsRecurring() {
+			a.UnschedulePluginJob(pj.PluginId, pj.Job.Id)
+		}
+
+		if !claimed {
+			continue
+		}
+
+		jobId := pj.Job.Id
+		a.Go(func() {
+			hooks.ExecuteScheduledJob(&plugin.Context{}, jobId)
+		})
+	}
+}
+
+// claimPluginJobRun records that a run of a plugin's job has started, and returns false if another server has already
+// started it. Runs are only claimed in order, so a server with a slow clock can't run a job again after a later run.
+func (a *App) claimPluginJobRun(pluginId, jobId string, slot int64) (bool, *model.AppError) {
+	key := PLUGIN_JOB_KEY_PREFIX + getKeyHash(jobId)
+
+	var oldValue []byte
+	if result := <-a.Srv.Store.Plugin().Get(pluginId, key); result.Err == nil {
+		oldValue = result.Data.(*model.PluginKeyValue).Value
+	} else if result.Err.StatusCode != http.StatusNotFound {
+		return false, result.Err
+	}
+
+	newValue := []byte(strconv.FormatInt(slot, 10))
+	if bytes.Equal(oldValue, newValue) {
+		return false, nil
+	}
+
+	if oldValue != nil {
+		if lastSlot, err := strconv.ParseInt(string(oldValue), 10, 64); err == nil && lastSlot > slot {
+			return false, nil
+		}
+	}
+
+	kv := &model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      key,
+		Value:    newValue,
+	}
+
+	result := <-a.Srv.Store.Plugin().CompareAndSet(kv, oldValue)
+	if result.Err != nil {
+		return false, result.Err
+	}
+
+	return result.Data.(bool), nil
+}
+
+func (a *App) startPluginJobs() {
+	a.pluginJobsTask = model.CreateRecurringTask("Plugin Jobs", a.runDuePluginJobs, pluginJobCheckInterval)
+}
+
+func (a *App) stopPluginJobs() {
+	if a.pluginJobsTask != nil {
+		a.pluginJobsTask.Cancel()
+		a.pluginJobsTask = nil
+	}
+
+	a.pluginJobsLock.Lock()
+	a.pluginJobs = nil
+	a.pluginJobsLock.Unlock()
+}
@@ -19,10 +19,17 @@ func getKeyHash(key string) string {
 }
 
 func (a *App) SetPluginKey(pluginId string, key string, value []byte) *model.AppError {
+	return a.SetPluginKeyWithExpiry(pluginId, key, value, 0)
+}
+
+// SetPluginKeyWithExpiry stores a value that's removed after the given number of seconds, or that's kept until it's
+// deleted if the number of seconds is 0.
+func (a *App) SetPluginKeyWithExpiry(pluginId string, key string, value []byte, expireInSeconds int64) *model.AppError {
 	kv := &model.PluginKeyValue{
 		PluginId: pluginId,
 		Key:      getKeyHash(key),
 		Value:    value,
+		ExpireAt: getPluginKeyExpireAt(expireInSeconds),
 	}
 
 	result := <-a.Srv.Store.Plugin().SaveOrUpdate(kv)
@@ -34,6 +41,26 @@ func (a *App) SetPluginKey(pluginId string, key string, value []byte) *model.App
 	return result.Err
 }
 
+// CompareAndSetPluginKey stores a value only if the current value is oldValue, or if there isn't a value when oldValue
+// is nil, returning whether it was stored.
+func (a *App) CompareAndSetPluginKey(pluginId string, key string, oldValue, newValue []byte, expireInSeconds int64) (bool, *model.AppError) {
+	kv := &model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      getKeyHash(key),
+		Value:    newValue,
+		ExpireAt: getPluginKeyExpireAt(expireInSeconds),
+	}
+
+	result := <-a.Srv.Store.Plugin().CompareAndSet(kv, oldValue)
+
+	if result.Err != nil {
+		mlog.Error(result.Err.Error())
+		return false, result.Err
+	}
+
+	return result.Data.(bool), nil
+}
+
 func (a *App) GetPluginKey(pluginId string, key string) ([]byte, *model.AppError) {
 	result := <-a.Srv.Store.Plugin().Get(pluginId, getKeyHash(key))
 
@@ -59,3 +86,31 @@ func (a *App) DeletePluginKey(pluginId string, key string) *model.AppError {
 
 	return result.Err
 }
+
+// CompareAndDeletePluginKey deletes a value only if it's oldValue, returning whether it was deleted.
+func (a *App) CompareAndDeletePluginKey(pluginId string, key string, oldValue []byte) (bool, *model.AppError) {
+	result := <-a.Srv.Store.Plugin().CompareAndDelete(pluginId, getKeyHash(key), oldValue)
+
+	if result.Err != nil {
+		mlog.Error(result.Err.Error())
+		return false, result.Err
+	}
+
+	return result.Data.(bool), nil
+}
+
+// DeleteExpiredPluginKeys removes the values that plugins stored which have expired. Expired values are never returned,
+// so this only frees up space.
+func (a *App) DeleteExpiredPluginKeys() {
+	if result := <-a.Srv.Store.Plugin().DeleteAllExpired(); result.Err != nil {
+		mlog.Error("Unable to delete expired plugin key values", mlog.Err(result.Err))
+	}
+}
+
+func getPluginKeyExpireAt(expireInSeconds int64) int64 {
+	if expireInSeconds <= 0 {
+		return 0
+	}
+
+	return model.GetMillis() + expireInSeconds*1000
+}
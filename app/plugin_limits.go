@@ -0,0 +1,76 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// getPluginMemoryLimit returns the number of bytes of memory that a plugin's process can use, or 0 if it isn't
+// limited. The plugin environment stops plugins that use more than this.
+func (a *App) getPluginMemoryLimit(pluginId string) int64 {
+	return int64(*a.Config().PluginSettings.GetLimits(pluginId).MaxMemoryMB) * 1024 * 1024
+}
+
+// AllowAPICall counts a call that the plugin has made to the API over RPC, and returns false if the plugin has made
+// more calls during the current minute than its limit allows.
+func (api *PluginAPI) AllowAPICall() bool {
+	limit := *api.app.Config().PluginSettings.GetLimits(api.id).MaxAPICallsPerMinute
+	if limit <= 0 {
+		return true
+	}
+
+	minute := model.GetMillis() / (60 * 1000)
+
+	api.apiCallsLock.Lock()
+	defer api.apiCallsLock.Unlock()
+
+	if minute != api.apiCallsMinute {
+		api.apiCallsMinute = minute
+		api.apiCalls = 0
+	}
+
+	api.apiCalls++
+
+	if api.apiCalls == limit+1 {
+		api.logger.Warn("Plugin exceeded its API call limit", mlog.Int("limit", limit))
+	}
+
+	return api.apiCalls <= limit
+}
+
+// checkPluginKVStorageLimit returns an error if storing a value would take the keys and values that a plugin has
+// stored over its limit. Values that replace existing ones only count the difference in size.
+//
+// The size is read before the value is written and not in the same transaction, so values that a plugin sets at the
+// same time are each checked against the size from before any of them, and together they can take the plugin over its
+// limit. The limit is meant to stop plugins from filling the database by mistake rather than to be exact.
+func (a *App) checkPluginKVStorageLimit(pluginId, key string, value []byte) *model.AppError {
+	limit := int64(*a.Config().PluginSettings.GetLimits(pluginId).MaxKVStorageMB) * 1024 * 1024
+	if limit <= 0 {
+		return nil
+	}
+
+	result := <-a.Srv.Store.Plugin().GetTotalSize(pluginId)
+	if result.Err != nil {
+		return result.Err
+	}
+	size := result.Data.(int64)
+
+	hashedKey := getKeyHash(key)
+	if result := <-a.Srv.Store.Plugin().Get(pluginId, hashedKey); result.Err == nil {
+		size -= int64(len(hashedKey) + len(result.Data.(*model.PluginKeyValue).Value))
+	} else if result.Err.StatusCode != http.StatusNotFound {
+		return result.Err
+	}
+
+	if size+int64(len(hashedKey)+len(value)) > limit {
+		return model.NewAppError("checkPluginKVStorageLimit", "app.plugin.kv_storage_limit.app_error", nil, "plugin_id="+pluginId, http.StatusRequestEntityTooLarge)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestPluginAPIAllowAPICall(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+	api := th.SetupPluginAPI()
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, api.AllowAPICall())
+	}
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.PluginSettings.Limits["pluginid"] = &model.PluginLimits{MaxAPICallsPerMinute: model.NewInt(3)}
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, api.AllowAPICall())
+	}
+	assert.False(t, api.AllowAPICall())
+
+	otherApi := NewPluginAPI(th.App, &model.Manifest{Id: "otherpluginid"})
+	assert.True(t, otherApi.AllowAPICall())
+
+	// The calls are counted again once the minute is over
+	api.apiCallsMinute--
+	assert.True(t, api.AllowAPICall())
+}
+
+func TestPluginAPIKVStorageLimit(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+	api := th.SetupPluginAPI()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.PluginSettings.Limits["pluginid"] = &model.PluginLimits{MaxKVStorageMB: model.NewInt(1)}
+	})
+
+	// 25 of these fit in 1MB along with their keys, but 26 don't
+	value := bytes.Repeat([]byte("a"), 40*1024)
+
+	for i := 0; i < 25; i++ {
+		key := fmt.Sprintf("key%v", i)
+		require.Nil(t, api.KVSet(key, value))
+		defer api.KVDelete(key)
+	}
+
+	// Replacing a value only counts the difference in size
+	require.Nil(t, api.KVSet("key0", value))
+
+	err := api.KVSet("key25", value)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.StatusCode)
+
+	_, err = api.KVCompareAndSet("key25", nil, value)
+	require.NotNil(t, err)
+
+	require.Nil(t, api.KVSetWithExpiry("key25", []byte("small"), 60))
+	defer api.KVDelete("key25")
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.PluginSettings.Limits["pluginid"].MaxKVStorageMB = model.NewInt(0)
+	})
+
+	require.Nil(t, api.KVSet("key26", value))
+	defer api.KVDelete("key26")
+}
+
+func TestPluginAPICallLimitOverRPC(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.PluginSettings.MaxAPICallsPerMinute = 1
+	})
+
+	SetAppEnvironmentWithPlugins(t, []string{
+		`
+		package main
+
+		import (
+			"fmt"
+
+			"github.com/mattermost/mattermost-server/plugin"
+			"github.com/mattermost/mattermost-server/model"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) MessageWillBePosted(c *plugin.Context, post *model.Post) (*model.Post, string) {
+			user, _ := p.API.GetUser(post.UserId)
+			post.Message = fmt.Sprintf("%v_%v", post.Message, user != nil)
+			return post, ""
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+		`,
+	}, th.App, th.App.NewPluginAPI)
+
+	post, err := th.App.CreatePost(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "message",
+	}, th.BasicChannel, false)
+	require.Nil(t, err)
+	assert.Equal(t, "message_true", post.Message)
+
+	post, err = th.App.CreatePost(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "message",
+	}, th.BasicChannel, false)
+	require.Nil(t, err)
+	assert.Equal(t, "message_false", post.Message)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+func (a *App) getPluginLogBuffer(pluginId string) (*plugin.LogBuffer, *model.AppError) {
+	if !a.PluginsReady() {
+		return nil, model.NewAppError("getPluginLogBuffer", "app.plugin.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	bundles, err := a.Plugins.Available()
+	if err != nil {
+		return nil, model.NewAppError("getPluginLogBuffer", "app.plugin.get_plugins.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for _, bundle := range bundles {
+		if bundle.Manifest != nil && bundle.Manifest.Id == pluginId {
+			return a.Plugins.LogBuffer(pluginId), nil
+		}
+	}
+
+	return nil, model.NewAppError("getPluginLogBuffer", "app.plugin.not_installed.app_error", nil, "plugin_id="+pluginId, http.StatusNotFound)
+}
+
+// GetPluginLogs returns the most recent entries from a plugin's log on this server, from oldest to newest. Each entry
+// is a JSON object.
+func (a *App) GetPluginLogs(pluginId string) ([]string, *model.AppError) {
+	buffer, err := a.getPluginLogBuffer(pluginId)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Entries(), nil
+}
+
+// FollowPluginLogs returns the most recent entries from a plugin's log on this server along with a channel that
+// receives each entry that's logged after them. The returned function must be called to stop following the log.
+func (a *App) FollowPluginLogs(pluginId string) ([]string, <-chan string, func(), *model.AppError) {
+	buffer, err := a.getPluginLogBuffer(pluginId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	entries, newEntries, unsubscribe := buffer.Subscribe()
+	return entries, newEntries, unsubscribe, nil
+}
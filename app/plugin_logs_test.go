@@ -0,0 +1,65 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginLogBuffer(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	SetAppEnvironmentWithPlugins(t,
+		[]string{
+			`
+		package main
+
+		import (
+			"fmt"
+			"os"
+
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) OnActivate() error {
+			p.API.LogWarn("logged through the api", "key", "value")
+			fmt.Fprintln(os.Stderr, "written to stderr")
+			return nil
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`}, th.App, th.App.NewPluginAPI)
+
+	pluginId := th.App.Plugins.Active()[0].Manifest.Id
+	buffer := th.App.Plugins.LogBuffer(pluginId)
+
+	var logs string
+	for i := 0; i < 50 && !strings.Contains(logs, "written to stderr"); i++ {
+		logs = strings.Join(buffer.Entries(), "\n")
+		if !strings.Contains(logs, "written to stderr") {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	assert.Contains(t, logs, `"msg":"logged through the api"`)
+	assert.Contains(t, logs, `"key":"value"`)
+	assert.Contains(t, logs, "written to stderr")
+
+	_, err := th.App.GetPluginLogs("notinstalled")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+const (
+	// The name of the catalog in a marketplace that's a directory
+	MARKETPLACE_CATALOG_FILE = "marketplace.json"
+
+	MARKETPLACE_CATALOG_MAX_SIZE = 1024 * 1024
+	MARKETPLACE_BUNDLE_MAX_SIZE  = 50 * 1024 * 1024
+)
+
+func (a *App) checkMarketplaceEnabled(where string) *model.AppError {
+	if !a.PluginsReady() {
+		return model.NewAppError(where, "app.plugin.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if !*a.Config().PluginSettings.EnableMarketplace {
+		return model.NewAppError(where, "app.plugin.marketplace.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+func isRemoteMarketplace(marketplaceURL string) bool {
+	return strings.HasPrefix(marketplaceURL, "http://") || strings.HasPrefix(marketplaceURL, "https://")
+}
+
+// readMarketplaceFile reads a file from the marketplace, which is either a URL or a path relative to the marketplace's
+// directory. Files that are larger than maxSize are rejected rather than truncated.
+func (a *App) readMarketplaceFile(location string, maxSize int64) ([]byte, error) {
+	var reader io.ReadCloser
+
+	if isRemoteMarketplace(location) {
+		resp, err := a.HTTPClient(true).Get(location)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			consumeAndClose(resp)
+			return nil, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+		}
+
+		reader = resp.Body
+	} else {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+
+		reader = file
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file is larger than %v bytes", maxSize)
+	}
+
+	return data, nil
+}
+
+// getMarketplaceBundleLocation resolves where a plugin's bundle is downloaded from. Relative URLs are relative to the
+// catalog, and bundles in a marketplace that's a directory can't be outside of it.
+func getMarketplaceBundleLocation(marketplaceURL, downloadURL string) string {
+	if isRemoteMarketplace(downloadURL) {
+		return downloadURL
+	}
+
+	if isRemoteMarketplace(marketplaceURL) {
+		base, err := url.Parse(marketplaceURL)
+		if err != nil {
+			return downloadURL
+		}
+
+		ref, err := url.Parse(downloadURL)
+		if err != nil {
+			return downloadURL
+		}
+
+		return base.ResolveReference(ref).String()
+	}
+
+	return filepath.Join(marketplaceURL, filepath.FromSlash(path.Clean("/"+downloadURL)))
+}
+
+func (a *App) getMarketplaceCatalog() ([]*model.MarketplacePlugin, *model.AppError) {
+	marketplaceURL := *a.Config().PluginSettings.MarketplaceURL
+
+	catalogLocation := marketplaceURL
+	if !isRemoteMarketplace(marketplaceURL) {
+		catalogLocation = filepath.Join(marketplaceURL, MARKETPLACE_CATALOG_FILE)
+	}
+
+	data, err := a.readMarketplaceFile(catalogLocation, MARKETPLACE_CATALOG_MAX_SIZE)
+	if err != nil {
+		return nil, model.NewAppError("getMarketplaceCatalog", "app.plugin.marketplace.catalog.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	catalog := model.MarketplacePluginListFromJson(bytes.NewReader(data))
+	if catalog == nil {
+		return nil, model.NewAppError("getMarketplaceCatalog", "app.plugin.marketplace.catalog.app_error", nil, "unable to parse the catalog", http.StatusInternalServerError)
+	}
+
+	var plugins []*model.MarketplacePlugin
+	for _, p := range catalog {
+		if p == nil || p.IsValid() != nil || !plugin.IsValidId(p.Id) {
+			continue
+		}
+
+		p.InstalledVersion = ""
+		plugins = append(plugins, p)
+	}
+
+	return plugins, nil
+}
+
+// GetMarketplacePlugins returns the plugins in the marketplace's catalog along with the versions of them that are
+// installed.
+func (a *App) GetMarketplacePlugins() ([]*model.MarketplacePlugin, *model.AppError) {
+	if err := a.checkMarketplaceEnabled("GetMarketplacePlugins"); err != nil {
+		return nil, err
+	}
+
+	plugins, err := a.getMarketplaceCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	bundles, bundlesErr := a.Plugins.Available()
+	if bundlesErr != nil {
+		return nil, model.NewAppError("GetMarketplacePlugins", "app.plugin.get_plugins.app_error", nil, bundlesErr.Error(), http.StatusInternalServerError)
+	}
+
+	installedVersions := make(map[string]string, len(bundles))
+	for _, bundle := range bundles {
+		if bundle.Manifest != nil {
+			installedVersions[bundle.Manifest.Id] = bundle.Manifest.Version
+		}
+	}
+
+	for _, p := range plugins {
+		p.InstalledVersion = installedVersions[p.Id]
+	}
+
+	return plugins, nil
+}
+
+// InstallMarketplacePlugin downloads a plugin from the marketplace and installs it, replacing the version that's
+// installed. The bundle must be signed with the marketplace's key and must contain the plugin that the catalog lists.
+func (a *App) InstallMarketplacePlugin(pluginId string) (*model.Manifest, *model.AppError) {
+	if err := a.checkMarketplaceEnabled("InstallMarketplacePlugin"); err != nil {
+		return nil, err
+	}
+
+	plugins, err := a.getMarketplaceCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	var marketplacePlugin *model.MarketplacePlugin
+	for _, p := range plugins {
+		if p.Id == pluginId {
+			marketplacePlugin = p
+			break
+		}
+	}
+
+	if marketplacePlugin == nil {
+		return nil, model.NewAppError("InstallMarketplacePlugin", "app.plugin.marketplace.not_found.app_error", nil, "plugin_id="+pluginId, http.StatusNotFound)
+	}
+
+	location := getMarketplaceBundleLocation(*a.Config().PluginSettings.MarketplaceURL, marketplacePlugin.DownloadURL)
+
+	bundle, readErr := a.readMarketplaceFile(location, MARKETPLACE_BUNDLE_MAX_SIZE)
+	if readErr != nil {
+		return nil, model.NewAppError("InstallMarketplacePlugin", "app.plugin.marketplace.download.app_error", nil, readErr.Error(), http.StatusInternalServerError)
+	}
+
+	if !verifyMarketplaceSignature(*a.Config().PluginSettings.MarketplacePublicKey, marketplacePlugin.Signature, bundle) {
+		return nil, model.NewAppError("InstallMarketplacePlugin", "app.plugin.marketplace.signature.app_error", nil, "plugin_id="+pluginId, http.StatusBadRequest)
+	}
+
+	return a.installPlugin(bytes.NewReader(bundle), true, pluginId)
+}
+
+// verifyMarketplaceSignature returns true if a bundle was signed with the private half of an ed25519 key. Both the
+// key and the signature are base64 encoded.
+func verifyMarketplaceSignature(publicKey, signature string, bundle []byte) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+
+	return ed25519.Verify(ed25519.PublicKey(key), bundle, sig)
+}
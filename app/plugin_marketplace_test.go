@@ -0,0 +1,42 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestGetMarketplaceBundleLocation(t *testing.T) {
+	assert.Equal(t, "https://cdn.example.com/plugin.tar.gz", getMarketplaceBundleLocation("https://marketplace.example.com/catalog.json", "https://cdn.example.com/plugin.tar.gz"))
+	assert.Equal(t, "https://marketplace.example.com/bundles/plugin.tar.gz", getMarketplaceBundleLocation("https://marketplace.example.com/catalog.json", "bundles/plugin.tar.gz"))
+	assert.Equal(t, "https://cdn.example.com/plugin.tar.gz", getMarketplaceBundleLocation("/opt/marketplace", "https://cdn.example.com/plugin.tar.gz"))
+
+	assert.Equal(t, filepath.Join("/opt/marketplace", "bundles", "plugin.tar.gz"), getMarketplaceBundleLocation("/opt/marketplace", "bundles/plugin.tar.gz"))
+	assert.Equal(t, filepath.Join("/opt/marketplace", "passwd"), getMarketplaceBundleLocation("/opt/marketplace", "../../etc/../passwd"), "should not leave the marketplace's directory")
+}
+
+func TestVerifyMarketplaceSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	bundle := []byte("bundle")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, bundle))
+	key := base64.StdEncoding.EncodeToString(publicKey)
+
+	assert.True(t, verifyMarketplaceSignature(key, signature, bundle))
+	assert.False(t, verifyMarketplaceSignature(key, signature, []byte("tampered")))
+	assert.False(t, verifyMarketplaceSignature(base64.StdEncoding.EncodeToString(otherPublicKey), signature, bundle))
+	assert.False(t, verifyMarketplaceSignature(key, "junk", bundle))
+	assert.False(t, verifyMarketplaceSignature("", signature, bundle))
+}
@@ -28,14 +28,13 @@ func (a *App) ServePluginRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	params := mux.Vars(r)
-	hooks, err := a.Plugins.HooksForPlugin(params["plugin_id"])
-	if err != nil {
+	if err := a.Plugins.RunPluginHook(params["plugin_id"], func(hooks plugin.Hooks) {
+		a.servePluginRequest(w, r, hooks.ServeHTTP)
+	}); err != nil {
 		a.Log.Error("Access to route for non-existent plugin", mlog.String("missing_plugin_id", params["plugin_id"]), mlog.Err(err))
 		http.NotFound(w, r)
 		return
 	}
-
-	a.servePluginRequest(w, r, hooks.ServeHTTP)
 }
 
 func (a *App) servePluginRequest(w http.ResponseWriter, r *http.Request, handler func(*plugin.Context, http.ResponseWriter, *http.Request)) {
@@ -171,3 +171,50 @@ func TestGetPluginStatuses(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, pluginStatuses)
 }
+
+func TestGetPluginSettingOptions(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	setupPluginApiTest(t,
+		`
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/model"
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) GetSettingOptions(c *plugin.Context, key string) ([]*model.PluginOption, *model.AppError) {
+			return []*model.PluginOption{{DisplayName: "Option for " + key, Value: key}}, nil
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`,
+		`{"id": "testsettingoptions", "backend": {"executable": "backend.exe"}, "settings_schema": {"settings": [
+			{"key": "Project", "type": "dropdown", "dynamic": true},
+			{"key": "Static", "type": "dropdown", "options": [{"display_name": "Static", "value": "static"}]}
+		]}}`, "testsettingoptions", th.App)
+
+	options, err := th.App.GetPluginSettingOptions("testsettingoptions", "project")
+	require.Nil(t, err)
+	require.Len(t, options, 1)
+	assert.Equal(t, "Option for Project", options[0].DisplayName)
+	assert.Equal(t, "Project", options[0].Value)
+
+	_, err = th.App.GetPluginSettingOptions("testsettingoptions", "Static")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+
+	_, err = th.App.GetPluginSettingOptions("testsettingoptions", "junk")
+	require.NotNil(t, err)
+
+	_, err = th.App.GetPluginSettingOptions("junk", "Project")
+	require.NotNil(t, err)
+}
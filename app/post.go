@@ -85,11 +85,9 @@ func (a *App) CreatePostAsUser(post *model.Post) (*model.Post, *model.AppError)
 				mlog.Error(fmt.Sprintf("Encountered error updating last viewed, channel_id=%s, user_id=%s, err=%v", post.ChannelId, post.UserId, result.Err))
 			}
 
-			if *a.Config().ServiceSettings.EnableChannelViewedMessages {
-				message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_VIEWED, "", "", post.UserId, nil)
-				message.Add("channel_id", post.ChannelId)
-				a.Publish(message)
-			}
+			// Posting doesn't usually change the poster's mentions, so the mention count is left out rather than
+			// querying the master for it on every post
+			a.publishChannelsViewed(post.UserId, []string{post.ChannelId}, false)
 		}
 
 		return rp, nil
@@ -109,127 +107,21 @@ func (a *App) CreatePostMissingChannel(post *model.Post, triggerWebhooks bool) (
 	return a.CreatePost(post, channel, triggerWebhooks)
 }
 
+// CreatePost runs the post through each stage of the post create pipeline, returning the post as it was saved.
 func (a *App) CreatePost(post *model.Post, channel *model.Channel, triggerWebhooks bool) (*model.Post, *model.AppError) {
-	post.SanitizeProps()
-
-	var pchan store.StoreChannel
-	if len(post.RootId) > 0 {
-		pchan = a.Srv.Store.Post().Get(post.RootId)
-	}
-
-	uchan := a.Srv.Store.User().Get(post.UserId)
-	var user *model.User
-	if result := <-uchan; result.Err != nil {
-		return nil, result.Err
-	} else {
-		user = result.Data.(*model.User)
-	}
+	defer a.StartPerformanceTimer(PERFORMANCE_TIMING_POST_CREATE)()
 
-	if a.License() != nil && *a.Config().TeamSettings.ExperimentalTownSquareIsReadOnly &&
-		!post.IsSystemMessage() &&
-		channel.Name == model.DEFAULT_CHANNEL &&
-		!a.RolesGrantPermission(user.GetRoles(), model.PERMISSION_MANAGE_SYSTEM.Id) {
-		return nil, model.NewAppError("createPost", "api.post.create_post.town_square_read_only", nil, "", http.StatusForbidden)
-	}
-
-	// Verify the parent/child relationships are correct
-	var parentPostList *model.PostList
-	if pchan != nil {
-		if presult := <-pchan; presult.Err != nil {
-			return nil, model.NewAppError("createPost", "api.post.create_post.root_id.app_error", nil, "", http.StatusBadRequest)
-		} else {
-			parentPostList = presult.Data.(*model.PostList)
-			if len(parentPostList.Posts) == 0 || !parentPostList.IsChannelId(post.ChannelId) {
-				return nil, model.NewAppError("createPost", "api.post.create_post.channel_root_id.app_error", nil, "", http.StatusInternalServerError)
-			}
-
-			if post.ParentId == "" {
-				post.ParentId = post.RootId
-			}
-
-			if post.RootId != post.ParentId {
-				parent := parentPostList.Posts[post.ParentId]
-				if parent == nil {
-					return nil, model.NewAppError("createPost", "api.post.create_post.parent_id.app_error", nil, "", http.StatusInternalServerError)
-				}
-			}
-		}
+	c := &PostCreateContext{
+		Post:            post,
+		Channel:         channel,
+		TriggerWebhooks: triggerWebhooks,
 	}
 
-	post.Hashtags, _ = model.ParseHashtags(post.Message)
-
-	if err := a.FillInPostProps(post, channel); err != nil {
+	if err := postCreateMiddleware.run(a, c); err != nil {
 		return nil, err
 	}
 
-	if a.PluginsReady() {
-		var rejectionError *model.AppError
-		pluginContext := &plugin.Context{}
-		a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
-			replacementPost, rejectionReason := hooks.MessageWillBePosted(pluginContext, post)
-			if rejectionReason != "" {
-				rejectionError = model.NewAppError("createPost", "Post rejected by plugin. "+rejectionReason, nil, "", http.StatusBadRequest)
-				return false
-			}
-			if replacementPost != nil {
-				post = replacementPost
-			}
-
-			return true
-		}, plugin.MessageWillBePostedId)
-		if rejectionError != nil {
-			return nil, rejectionError
-		}
-	}
-
-	var rpost *model.Post
-	if result := <-a.Srv.Store.Post().Save(post); result.Err != nil {
-		return nil, result.Err
-	} else {
-		rpost = result.Data.(*model.Post)
-	}
-
-	if a.PluginsReady() {
-		a.Go(func() {
-			pluginContext := &plugin.Context{}
-			a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
-				hooks.MessageHasBeenPosted(pluginContext, rpost)
-				return true
-			}, plugin.MessageHasBeenPostedId)
-		})
-	}
-
-	esInterface := a.Elasticsearch
-	if esInterface != nil && *a.Config().ElasticsearchSettings.EnableIndexing {
-		a.Go(func() {
-			esInterface.IndexPost(rpost, channel.TeamId)
-		})
-	}
-
-	if a.Metrics != nil {
-		a.Metrics.IncrementPostCreate()
-	}
-
-	if len(post.FileIds) > 0 {
-		// There's a rare bug where the client sends up duplicate FileIds so protect against that
-		post.FileIds = utils.RemoveDuplicatesFromStringArray(post.FileIds)
-
-		for _, fileId := range post.FileIds {
-			if result := <-a.Srv.Store.FileInfo().AttachToPost(fileId, post.Id); result.Err != nil {
-				mlog.Error(fmt.Sprintf("Encountered error attaching files to post, post_id=%s, user_id=%s, file_ids=%v, err=%v", post.Id, post.FileIds, post.UserId, result.Err), mlog.String("post_id", post.Id))
-			}
-		}
-
-		if a.Metrics != nil {
-			a.Metrics.IncrementPostFileAttachment(len(post.FileIds))
-		}
-	}
-
-	if err := a.handlePostEvents(rpost, user, channel, triggerWebhooks, parentPostList); err != nil {
-		return nil, err
-	}
-
-	return rpost, nil
+	return c.Post, nil
 }
 
 // FillInPostProps should be invoked before saving posts to fill in properties such as
@@ -351,6 +243,40 @@ func (a *App) SendEphemeralPost(userId string, post *model.Post) *model.Post {
 	return post
 }
 
+// UpdateEphemeralPost replaces an ephemeral post that was sent to a user with the same id.
+func (a *App) UpdateEphemeralPost(userId string, post *model.Post) *model.Post {
+	post.Type = model.POST_EPHEMERAL
+
+	post.UpdateAt = model.GetMillis()
+	if post.CreateAt == 0 {
+		post.CreateAt = post.UpdateAt
+	}
+	if post.Props == nil {
+		post.Props = model.StringInterface{}
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POST_EDITED, "", post.ChannelId, userId, nil)
+	message.Add("post", a.PostWithProxyAddedToImageURLs(post).ToJson())
+	a.Publish(message)
+
+	return post
+}
+
+// DeleteEphemeralPost removes an ephemeral post that was sent to a user.
+func (a *App) DeleteEphemeralPost(userId, postId string) {
+	post := &model.Post{
+		Id:       postId,
+		UserId:   userId,
+		Type:     model.POST_EPHEMERAL,
+		DeleteAt: model.GetMillis(),
+	}
+	post.UpdateAt = post.DeleteAt
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POST_DELETED, "", "", userId, nil)
+	message.Add("post", post.ToJson())
+	a.Publish(message)
+}
+
 func (a *App) UpdatePost(post *model.Post, safeUpdate bool) (*model.Post, *model.AppError) {
 	post.SanitizeProps()
 
@@ -397,6 +323,7 @@ func (a *App) UpdatePost(post *model.Post, safeUpdate bool) (*model.Post, *model
 		newPost.HasReactions = post.HasReactions
 		newPost.FileIds = post.FileIds
 		newPost.Props = post.Props
+		newPost.RemoteId = post.RemoteId
 	}
 
 	if err := a.FillInPostProps(post, nil); err != nil {
@@ -443,6 +370,18 @@ func (a *App) UpdatePost(post *model.Post, safeUpdate bool) (*model.Post, *model
 
 		a.sendUpdatedPostEvent(rpost)
 
+		a.sendChannelBridgeEvents(model.CHANNEL_BRIDGE_EVENT_POST_EDITED, rpost, nil)
+
+		if rpost.Message != oldPost.Message {
+			a.sendOutgoingWebhookEvent(&outgoingWebhookEvent{
+				Type:      model.OUTGOING_HOOK_EVENT_POST_EDITED,
+				ChannelId: rpost.ChannelId,
+				UserId:    rpost.UserId,
+				Post:      rpost,
+				OldPost:   oldPost,
+			})
+		}
+
 		a.InvalidateCacheForChannelPosts(rpost.ChannelId)
 
 		return rpost, nil
@@ -507,6 +446,19 @@ func (a *App) GetSinglePost(postId string) (*model.Post, *model.AppError) {
 	}
 }
 
+func (a *App) GetPostByRemoteId(service, externalId string) (*model.Post, *model.AppError) {
+	remoteId := model.NewRemoteId(service, externalId)
+	if !model.IsValidRemoteId(&remoteId) {
+		return nil, model.NewAppError("GetPostByRemoteId", "api.post.get_post_by_remote_id.invalid.app_error", nil, "remote_id="+remoteId, http.StatusBadRequest)
+	}
+
+	if result := <-a.Srv.Store.Post().GetByRemoteId(remoteId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Post), nil
+	}
+}
+
 func (a *App) GetPostThread(postId string) (*model.PostList, *model.AppError) {
 	if result := <-a.Srv.Store.Post().Get(postId); result.Err != nil {
 		return nil, result.Err
@@ -580,6 +532,27 @@ func (a *App) GetPostsAfterPost(channelId, postId string, page, perPage int) (*m
 	}
 }
 
+// GetPostsByCursor returns a page of the posts that are older or newer than the cursor, with the list's cursors set
+// to get the pages on either side of it.
+func (a *App) GetPostsByCursor(channelId string, cursor *model.PostCursor, before bool, perPage int) (*model.PostList, *model.AppError) {
+	result := <-a.Srv.Store.Post().GetPostsByCursor(channelId, cursor, before, perPage)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	list := result.Data.(*model.PostList)
+
+	// Going back stops at the start of the channel, but going forward always leaves the cursor's post behind
+	list.SetCursors(!before || len(list.Order) == perPage)
+
+	if !before && len(list.Order) == 0 {
+		// Nothing newer has been posted yet, so the same cursor is used to check again
+		list.AfterCursor = cursor.String()
+	}
+
+	return list, nil
+}
+
 func (a *App) GetPostsAroundPost(postId, channelId string, offset, limit int, before bool) (*model.PostList, *model.AppError) {
 	var pchan store.StoreChannel
 	if before {
@@ -617,6 +590,15 @@ func (a *App) DeletePost(postId, deleteByID string) (*model.Post, *model.AppErro
 			a.DeleteFlaggedPosts(post.Id)
 		})
 
+		a.sendChannelBridgeEvents(model.CHANNEL_BRIDGE_EVENT_POST_DELETED, post, nil)
+
+		a.sendOutgoingWebhookEvent(&outgoingWebhookEvent{
+			Type:      model.OUTGOING_HOOK_EVENT_POST_DELETED,
+			ChannelId: post.ChannelId,
+			UserId:    post.UserId,
+			Post:      post,
+		})
+
 		esInterface := a.Elasticsearch
 		if esInterface != nil && *a.Config().ElasticsearchSettings.EnableIndexing {
 			a.Go(func() {
@@ -777,7 +759,7 @@ func (a *App) GetFileInfosForPost(postId string, readFromMaster bool) ([]*model.
 func (a *App) GetOpenGraphMetadata(requestURL string) *opengraph.OpenGraph {
 	og := opengraph.NewOpenGraph()
 
-	res, err := a.HTTPClient(false).Get(requestURL)
+	res, err := a.linkPreviewHTTPClient(false).Get(requestURL)
 	if err != nil {
 		mlog.Error(fmt.Sprintf("GetOpenGraphMetadata request failed for url=%v with err=%v", requestURL, err.Error()))
 		return og
@@ -880,22 +862,7 @@ func (a *App) DoPostAction(postId, actionId, userId, selectedOption string) *mod
 		request.Context["selected_option"] = selectedOption
 	}
 
-	req, _ := http.NewRequest("POST", action.Integration.URL, strings.NewReader(request.ToJson()))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// Allow access to plugin routes for action buttons
-	var httpClient *http.Client
-	url, _ := url.Parse(action.Integration.URL)
-	siteURL, _ := url.Parse(*a.Config().ServiceSettings.SiteURL)
-	subpath, _ := utils.GetSubpathFromConfig(a.Config())
-	if (url.Hostname() == "localhost" || url.Hostname() == "127.0.0.1" || url.Hostname() == siteURL.Hostname()) && strings.HasPrefix(url.Path, path.Join(subpath, "plugins")) {
-		httpClient = a.HTTPClient(true)
-	} else {
-		httpClient = a.HTTPClient(false)
-	}
-
-	resp, err := httpClient.Do(req)
+	resp, err := a.doIntegrationRequest(action.Integration.URL, request.ToJson())
 	if err != nil {
 		return model.NewAppError("DoPostAction", "api.post.do_action.action_integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
 	}
@@ -910,10 +877,15 @@ func (a *App) DoPostAction(postId, actionId, userId, selectedOption string) *mod
 		return model.NewAppError("DoPostAction", "api.post.do_action.action_integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
 	}
 
+	if response.ClearOptionsCache {
+		clearPostActionOptionsCache(action.Integration.URL)
+	}
+
 	retainedProps := []string{"override_username", "override_icon_url"}
 
 	if response.Update != nil {
 		response.Update.Id = postId
+		response.Update.RemoteId = post.RemoteId
 		response.Update.AddProp("from_webhook", "true")
 		for _, prop := range retainedProps {
 			if value, ok := post.Props[prop]; ok {
@@ -950,14 +922,67 @@ func (a *App) DoPostAction(postId, actionId, userId, selectedOption string) *mod
 	return nil
 }
 
+// doIntegrationRequest sends a JSON request to the integration behind a post action or dialog.
+func (a *App) doIntegrationRequest(rawURL string, body string) (*http.Response, error) {
+	req, err := http.NewRequest("POST", rawURL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	// Allow access to plugin routes for action buttons and dialogs
+	var httpClient *http.Client
+	url, _ := url.Parse(rawURL)
+	siteURL, _ := url.Parse(*a.Config().ServiceSettings.SiteURL)
+	subpath, _ := utils.GetSubpathFromConfig(a.Config())
+	if (url.Hostname() == "localhost" || url.Hostname() == "127.0.0.1" || url.Hostname() == siteURL.Hostname()) && strings.HasPrefix(url.Path, path.Join(subpath, "plugins")) {
+		httpClient = a.HTTPClient(true)
+	} else {
+		httpClient = a.HTTPClient(false)
+	}
+
+	return httpClient.Do(req)
+}
+
 func (a *App) PostListWithProxyAddedToImageURLs(list *model.PostList) *model.PostList {
+	defer a.StartPerformanceTimer(PERFORMANCE_TIMING_PREPARE_FOR_CLIENT)()
+
 	if f := a.ImageProxyAdder(); f != nil {
 		return list.WithRewrittenImageURLs(f)
 	}
 	return list
 }
 
+// PostListWithFileInfos returns a copy of the list with the file infos for its posts filled in. They're loaded for all
+// of the posts in a single query so that clients don't need to request them a post at a time.
+func (a *App) PostListWithFileInfos(list *model.PostList) (*model.PostList, *model.AppError) {
+	var postIds []string
+	for _, post := range list.Posts {
+		if len(post.FileIds) > 0 {
+			postIds = append(postIds, post.Id)
+		}
+	}
+
+	withInfos := *list
+	withInfos.FileInfos = nil
+
+	if len(postIds) == 0 {
+		return &withInfos, nil
+	}
+
+	result := <-a.Srv.Store.FileInfo().GetForPosts(postIds)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	withInfos.AddFileInfos(result.Data.([]*model.FileInfo))
+	return &withInfos, nil
+}
+
 func (a *App) PostWithProxyAddedToImageURLs(post *model.Post) *model.Post {
+	defer a.StartPerformanceTimer(PERFORMANCE_TIMING_PREPARE_FOR_CLIENT)()
+
 	if f := a.ImageProxyAdder(); f != nil {
 		return post.WithRewrittenImageURLs(f)
 	}
@@ -0,0 +1,86 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// getAcknowledgeablePost returns the post if its sender asked for acknowledgement and its channel hasn't been archived.
+func (a *App) getAcknowledgeablePost(postId string) (*model.Post, *model.AppError) {
+	post, err := a.GetSinglePost(postId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !post.IsAckRequested() {
+		return nil, model.NewAppError("getAcknowledgeablePost", "api.post.acknowledge.not_requested.app_error", nil, "post_id="+postId, http.StatusBadRequest)
+	}
+
+	channel, err := a.GetChannel(post.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel.DeleteAt > 0 {
+		return nil, model.NewAppError("getAcknowledgeablePost", "api.post.acknowledge.archived_channel.app_error", nil, "post_id="+postId, http.StatusForbidden)
+	}
+
+	return post, nil
+}
+
+func (a *App) AcknowledgePost(userId, postId string) (*model.PostAcknowledgement, *model.AppError) {
+	post, err := a.getAcknowledgeablePost(postId)
+	if err != nil {
+		return nil, err
+	}
+
+	var acknowledgement *model.PostAcknowledgement
+	if result := <-a.Srv.Store.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: userId, PostId: postId}); result.Err != nil {
+		return nil, result.Err
+	} else {
+		acknowledgement = result.Data.(*model.PostAcknowledgement)
+	}
+
+	a.Go(func() {
+		a.sendAcknowledgementEvent(model.WEBSOCKET_EVENT_ACKNOWLEDGEMENT_ADDED, acknowledgement, post)
+	})
+
+	return acknowledgement, nil
+}
+
+func (a *App) UnacknowledgePost(userId, postId string) *model.AppError {
+	post, err := a.getAcknowledgeablePost(postId)
+	if err != nil {
+		return err
+	}
+
+	if result := <-a.Srv.Store.PostAcknowledgement().Delete(userId, postId); result.Err != nil {
+		return result.Err
+	}
+
+	acknowledgement := &model.PostAcknowledgement{UserId: userId, PostId: postId}
+
+	a.Go(func() {
+		a.sendAcknowledgementEvent(model.WEBSOCKET_EVENT_ACKNOWLEDGEMENT_REMOVED, acknowledgement, post)
+	})
+
+	return nil
+}
+
+func (a *App) GetAcknowledgementsForPost(postId string) ([]*model.PostAcknowledgement, *model.AppError) {
+	result := <-a.Srv.Store.PostAcknowledgement().GetForPost(postId)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return result.Data.([]*model.PostAcknowledgement), nil
+}
+
+func (a *App) sendAcknowledgementEvent(event string, acknowledgement *model.PostAcknowledgement, post *model.Post) {
+	message := model.NewWebSocketEvent(event, "", post.ChannelId, "", nil)
+	message.Add("acknowledgement", acknowledgement.ToJson())
+	a.Publish(message)
+}
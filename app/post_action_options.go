@@ -0,0 +1,94 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	POST_ACTION_OPTIONS_CACHE_SIZE = 10000
+	POST_ACTION_OPTIONS_CACHE_SEC  = 30
+)
+
+// postActionOptionsCache holds the options returned by integrations for dynamic menus so that they aren't requested
+// again every time that a menu is rendered.
+var postActionOptionsCache = utils.NewLru(POST_ACTION_OPTIONS_CACHE_SIZE)
+
+// getPostActionOptionsCacheKey returns the key of the options for a menu. Menus with the same URL and context get the
+// same options from their integration, so they share an entry even when they're in different posts.
+func getPostActionOptionsCacheKey(integration *model.PostActionIntegration) string {
+	context, _ := json.Marshal(integration.Context)
+	return integration.URL + "\n" + string(context)
+}
+
+// clearPostActionOptionsCache discards the cached options of every menu that uses an integration URL.
+func clearPostActionOptionsCache(url string) {
+	prefix := url + "\n"
+	for _, key := range postActionOptionsCache.Keys() {
+		if strings.HasPrefix(key.(string), prefix) {
+			postActionOptionsCache.Remove(key)
+		}
+	}
+}
+
+// GetPostActionOptions returns the options of a dynamic menu, which are requested from its integration unless they
+// were requested recently.
+func (a *App) GetPostActionOptions(postId, actionId, userId string) ([]*model.PostActionOptions, *model.AppError) {
+	pchan := a.Srv.Store.Post().GetSingle(postId)
+
+	var post *model.Post
+	if result := <-pchan; result.Err != nil {
+		return nil, result.Err
+	} else {
+		post = result.Data.(*model.Post)
+	}
+
+	action := post.GetAction(actionId)
+	if action == nil || action.Integration == nil || action.DataSource != model.POST_ACTION_DATA_SOURCE_DYNAMIC {
+		return nil, model.NewAppError("GetPostActionOptions", "api.post.get_action_options.action_id.app_error", nil, fmt.Sprintf("action=%v", action), http.StatusNotFound)
+	}
+
+	key := getPostActionOptionsCacheKey(action.Integration)
+	if cached, ok := postActionOptionsCache.Get(key); ok {
+		return cached.([]*model.PostActionOptions), nil
+	}
+
+	request := &model.PostActionIntegrationRequest{
+		UserId:     userId,
+		PostId:     postId,
+		Type:       model.POST_ACTION_REQUEST_TYPE_OPTIONS,
+		DataSource: action.DataSource,
+		Context:    action.Integration.Context,
+	}
+
+	resp, err := a.doIntegrationRequest(action.Integration.URL, request.ToJson())
+	if err != nil {
+		return nil, model.NewAppError("GetPostActionOptions", "api.post.get_action_options.action_integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
+	}
+	defer consumeAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("GetPostActionOptions", "api.post.get_action_options.action_integration.app_error", nil, fmt.Sprintf("status=%v", resp.StatusCode), http.StatusBadRequest)
+	}
+
+	var response model.PostActionOptionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, model.NewAppError("GetPostActionOptions", "api.post.get_action_options.action_integration.app_error", nil, "err="+err.Error(), http.StatusBadRequest)
+	}
+
+	if response.Options == nil {
+		response.Options = []*model.PostActionOptions{}
+	}
+
+	postActionOptionsCache.AddWithExpiresInSecs(key, response.Options, POST_ACTION_OPTIONS_CACHE_SEC)
+
+	return response.Options, nil
+}
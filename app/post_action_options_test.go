@@ -0,0 +1,121 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestPostActionOptionsCacheKey(t *testing.T) {
+	key := getPostActionOptionsCacheKey(&model.PostActionIntegration{URL: "http://example.com", Context: model.StringInterface{"a": 1, "b": "2"}})
+	assert.Equal(t, key, getPostActionOptionsCacheKey(&model.PostActionIntegration{URL: "http://example.com", Context: model.StringInterface{"b": "2", "a": 1}}))
+	assert.NotEqual(t, key, getPostActionOptionsCacheKey(&model.PostActionIntegration{URL: "http://example.com", Context: model.StringInterface{"a": 2, "b": "2"}}))
+	assert.NotEqual(t, key, getPostActionOptionsCacheKey(&model.PostActionIntegration{URL: "http://example.com/other", Context: model.StringInterface{"a": 1, "b": "2"}}))
+
+	otherKey := getPostActionOptionsCacheKey(&model.PostActionIntegration{URL: "http://example.com/other"})
+	postActionOptionsCache.Add(key, []*model.PostActionOptions{})
+	postActionOptionsCache.Add(otherKey, []*model.PostActionOptions{})
+
+	clearPostActionOptionsCache("http://example.com")
+
+	_, ok := postActionOptionsCache.Get(key)
+	assert.False(t, ok)
+	_, ok = postActionOptionsCache.Get(otherKey)
+	assert.True(t, ok, "should only clear options for the same URL")
+
+	postActionOptionsCache.Purge()
+}
+
+func TestGetPostActionOptions(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+	})
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request model.PostActionIntegrationRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.NoError(t, err)
+
+		if request.Type == model.POST_ACTION_REQUEST_TYPE_OPTIONS {
+			count := atomic.AddInt32(&requests, 1)
+			assert.Equal(t, th.BasicUser.Id, request.UserId)
+			assert.Equal(t, model.POST_ACTION_DATA_SOURCE_DYNAMIC, request.DataSource)
+			assert.Equal(t, "foo", request.Context["s"])
+			fmt.Fprintf(w, `{"options": [{"text": "Option %v", "value": "option%v"}]}`, count, count)
+		} else {
+			fmt.Fprintf(w, `{"clear_options_cache": true}`)
+		}
+	}))
+	defer ts.Close()
+
+	post, err := th.App.CreatePostAsUser(&model.Post{
+		Message:       "Interactive post",
+		ChannelId:     th.BasicChannel.Id,
+		PendingPostId: model.NewId() + ":" + fmt.Sprint(model.GetMillis()),
+		UserId:        th.BasicUser.Id,
+		Props: model.StringInterface{
+			"attachments": []*model.SlackAttachment{
+				{
+					Text: "hello",
+					Actions: []*model.PostAction{
+						{
+							Integration: &model.PostActionIntegration{
+								Context: model.StringInterface{"s": "foo"},
+								URL:     ts.URL,
+							},
+							Name:       "dynamic",
+							Type:       model.POST_ACTION_TYPE_SELECT,
+							DataSource: model.POST_ACTION_DATA_SOURCE_DYNAMIC,
+						},
+						{
+							Integration: &model.PostActionIntegration{URL: ts.URL},
+							Name:        "button",
+							Type:        model.POST_ACTION_TYPE_BUTTON,
+						},
+					},
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	defer postActionOptionsCache.Purge()
+
+	attachments := post.Attachments()
+	dynamicId := attachments[0].Actions[0].Id
+	buttonId := attachments[0].Actions[1].Id
+
+	options, err := th.App.GetPostActionOptions(post.Id, dynamicId, th.BasicUser.Id)
+	require.Nil(t, err)
+	require.Len(t, options, 1)
+	assert.Equal(t, "option1", options[0].Value)
+
+	options, err = th.App.GetPostActionOptions(post.Id, dynamicId, th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "option1", options[0].Value, "should use cached options")
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	require.Nil(t, th.App.DoPostAction(post.Id, buttonId, th.BasicUser.Id, ""))
+
+	options, err = th.App.GetPostActionOptions(post.Id, dynamicId, th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "option2", options[0].Value, "should request options again after the integration clears them")
+
+	_, err = th.App.GetPostActionOptions(post.Id, buttonId, th.BasicUser.Id)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
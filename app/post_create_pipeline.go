@@ -0,0 +1,347 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+	"github.com/mattermost/mattermost-server/store"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// The stages that a post goes through when it's created, in the order that they run. Mentions and metadata are filled
+// in before moderation so that plugins are given posts with their hashtags and channel mentions.
+const (
+	POST_CREATE_STAGE_VALIDATION  = "validation"
+	POST_CREATE_STAGE_MENTIONS    = "mentions"
+	POST_CREATE_STAGE_METADATA    = "metadata"
+	POST_CREATE_STAGE_MODERATION  = "moderation"
+	POST_CREATE_STAGE_PERSISTENCE = "persistence"
+	POST_CREATE_STAGE_FAN_OUT     = "fan_out"
+)
+
+var postCreateStages = []struct {
+	name   string
+	timing string
+}{
+	{POST_CREATE_STAGE_VALIDATION, PERFORMANCE_TIMING_POST_CREATE_VALIDATION},
+	{POST_CREATE_STAGE_MENTIONS, PERFORMANCE_TIMING_POST_CREATE_MENTIONS},
+	{POST_CREATE_STAGE_METADATA, PERFORMANCE_TIMING_POST_CREATE_METADATA},
+	{POST_CREATE_STAGE_MODERATION, PERFORMANCE_TIMING_POST_CREATE_MODERATION},
+	{POST_CREATE_STAGE_PERSISTENCE, PERFORMANCE_TIMING_POST_CREATE_PERSISTENCE},
+	{POST_CREATE_STAGE_FAN_OUT, PERFORMANCE_TIMING_POST_CREATE_FAN_OUT},
+}
+
+// PostCreateContext is a post that's being created along with what the earlier middleware learned about it.
+type PostCreateContext struct {
+	// Post is the post being created. Middleware may replace it before it's saved. Once it's been saved, it's the
+	// post as it was stored.
+	Post            *model.Post
+	Channel         *model.Channel
+	TriggerWebhooks bool
+
+	// User is the author of the post, loaded during validation.
+	User *model.User
+
+	// ParentPostList is the thread that the post replies to, loaded during validation if the post is a reply.
+	ParentPostList *model.PostList
+
+	// Halted is set by middleware that has taken over the post, such as one that holds it back to be sent later.
+	// No further middleware runs and the post is returned to the caller as it is.
+	Halted bool
+}
+
+// PostCreateMiddleware is a step in creating a post. Returning an error stops the post from being created, except
+// during fan-out where the post has already been saved and the error is only returned to the caller.
+type PostCreateMiddleware func(a *App, c *PostCreateContext) *model.AppError
+
+type postCreateStep struct {
+	name       string
+	middleware PostCreateMiddleware
+}
+
+// postCreatePipeline is the middleware for each stage of creating a post, in the order that it runs.
+type postCreatePipeline map[string][]postCreateStep
+
+func (p postCreatePipeline) register(stage, name string, middleware PostCreateMiddleware) {
+	for _, known := range postCreateStages {
+		if known.name == stage {
+			p[stage] = append(p[stage], postCreateStep{name, middleware})
+			return
+		}
+	}
+
+	panic(fmt.Sprintf("unknown post create stage %v for middleware %v", stage, name))
+}
+
+func (p postCreatePipeline) run(a *App, c *PostCreateContext) *model.AppError {
+	for _, stage := range postCreateStages {
+		stopTimer := a.StartPerformanceTimer(stage.timing)
+
+		for _, step := range p[stage.name] {
+			if err := step.middleware(a, c); err != nil {
+				stopTimer()
+				return err
+			}
+
+			if c.Halted {
+				mlog.Debug(fmt.Sprintf("Post creation halted by middleware %v during %v", step.name, stage.name), mlog.String("post_id", c.Post.Id))
+				stopTimer()
+				return nil
+			}
+		}
+
+		stopTimer()
+	}
+
+	return nil
+}
+
+var postCreateMiddleware = postCreatePipeline{}
+
+// RegisterPostCreateMiddleware adds middleware to the end of a stage of creating posts. The middleware is shared by
+// every App, so it should be registered from an init function. Plugins hook into the moderation and fan-out stages
+// through MessageWillBePosted and MessageHasBeenPosted instead.
+func RegisterPostCreateMiddleware(stage, name string, middleware PostCreateMiddleware) {
+	postCreateMiddleware.register(stage, name, middleware)
+}
+
+func init() {
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_VALIDATION, "sanitize_props", sanitizePostProps)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_VALIDATION, "author_and_thread", loadPostAuthorAndThread)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_VALIDATION, "town_square_read_only", checkTownSquareReadOnly)
+
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_MENTIONS, "channel_mentions", fillInPostChannelMentions)
+
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_METADATA, "hashtags", parsePostHashtags)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_METADATA, "bot_flag", flagBotPost)
+
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_MODERATION, "plugins", runMessageWillBePostedHooks)
+
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_PERSISTENCE, "save", savePost)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_PERSISTENCE, "file_attachments", attachFilesToPost)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_PERSISTENCE, "thread_memberships", updateThreadMemberships)
+
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "plugins", runMessageHasBeenPostedHooks)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "search_index", indexPost)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "post_events", sendPostEvents)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "thread_updates", sendThreadUpdates)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "channel_bridges", sendPostToChannelBridges)
+	RegisterPostCreateMiddleware(POST_CREATE_STAGE_FAN_OUT, "event_subscriptions", sendPostToEventSubscriptions)
+}
+
+func sanitizePostProps(a *App, c *PostCreateContext) *model.AppError {
+	c.Post.SanitizeProps()
+	return nil
+}
+
+func loadPostAuthorAndThread(a *App, c *PostCreateContext) *model.AppError {
+	var pchan store.StoreChannel
+	if len(c.Post.RootId) > 0 {
+		pchan = a.Srv.Store.Post().Get(c.Post.RootId)
+	}
+
+	if result := <-a.Srv.Store.User().Get(c.Post.UserId); result.Err != nil {
+		return result.Err
+	} else {
+		c.User = result.Data.(*model.User)
+	}
+
+	// Verify the parent/child relationships are correct
+	if pchan != nil {
+		if presult := <-pchan; presult.Err != nil {
+			return model.NewAppError("createPost", "api.post.create_post.root_id.app_error", nil, "", http.StatusBadRequest)
+		} else {
+			c.ParentPostList = presult.Data.(*model.PostList)
+			if len(c.ParentPostList.Posts) == 0 || !c.ParentPostList.IsChannelId(c.Post.ChannelId) {
+				return model.NewAppError("createPost", "api.post.create_post.channel_root_id.app_error", nil, "", http.StatusInternalServerError)
+			}
+
+			if c.Post.ParentId == "" {
+				c.Post.ParentId = c.Post.RootId
+			}
+
+			if c.Post.RootId != c.Post.ParentId {
+				parent := c.ParentPostList.Posts[c.Post.ParentId]
+				if parent == nil {
+					return model.NewAppError("createPost", "api.post.create_post.parent_id.app_error", nil, "", http.StatusInternalServerError)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkTownSquareReadOnly(a *App, c *PostCreateContext) *model.AppError {
+	if a.License() != nil && *a.Config().TeamSettings.ExperimentalTownSquareIsReadOnly &&
+		!c.Post.IsSystemMessage() &&
+		c.Channel.Name == model.DEFAULT_CHANNEL &&
+		!a.RolesGrantPermission(c.User.GetRoles(), model.PERMISSION_MANAGE_SYSTEM.Id) {
+		return model.NewAppError("createPost", "api.post.create_post.town_square_read_only", nil, "", http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// runMessageWillBePostedHooks lets plugins reject or replace the post. Like before posts were created by middleware,
+// a replacement post is saved as it is, without its hashtags or channel mentions being filled in again.
+func runMessageWillBePostedHooks(a *App, c *PostCreateContext) *model.AppError {
+	if !a.PluginsReady() {
+		return nil
+	}
+
+	var rejectionError *model.AppError
+	pluginContext := &plugin.Context{}
+	a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+		replacementPost, rejectionReason := hooks.MessageWillBePosted(pluginContext, c.Post)
+		if rejectionReason != "" {
+			rejectionError = model.NewAppError("createPost", "Post rejected by plugin. "+rejectionReason, nil, "", http.StatusBadRequest)
+			return false
+		}
+		if replacementPost != nil {
+			c.Post = replacementPost
+		}
+
+		return true
+	}, plugin.MessageWillBePostedId)
+
+	return rejectionError
+}
+
+func fillInPostChannelMentions(a *App, c *PostCreateContext) *model.AppError {
+	return a.FillInPostProps(c.Post, c.Channel)
+}
+
+func parsePostHashtags(a *App, c *PostCreateContext) *model.AppError {
+	c.Post.Hashtags, _ = model.ParseHashtags(c.Post.Message)
+	return nil
+}
+
+// flagBotPost marks posts made by bots so that clients can identify them. The prop is set by the server alone so that
+// other users can't make their posts look like they came from bots.
+func flagBotPost(a *App, c *PostCreateContext) *model.AppError {
+	if c.User.IsBot {
+		c.Post.AddProp(model.POST_PROPS_FROM_BOT, "true")
+	} else if c.Post.Props != nil {
+		delete(c.Post.Props, model.POST_PROPS_FROM_BOT)
+	}
+
+	return nil
+}
+
+func savePost(a *App, c *PostCreateContext) *model.AppError {
+	if result := <-a.Srv.Store.Post().Save(c.Post); result.Err != nil {
+		return result.Err
+	} else {
+		c.Post = result.Data.(*model.Post)
+	}
+
+	if a.Metrics != nil {
+		a.Metrics.IncrementPostCreate()
+	}
+
+	return nil
+}
+
+func attachFilesToPost(a *App, c *PostCreateContext) *model.AppError {
+	post := c.Post
+	if len(post.FileIds) == 0 {
+		return nil
+	}
+
+	// There's a rare bug where the client sends up duplicate FileIds so protect against that
+	post.FileIds = utils.RemoveDuplicatesFromStringArray(post.FileIds)
+
+	for _, fileId := range post.FileIds {
+		if result := <-a.Srv.Store.FileInfo().AttachToPost(fileId, post.Id); result.Err != nil {
+			mlog.Error(fmt.Sprintf("Encountered error attaching files to post, post_id=%s, user_id=%s, file_ids=%v, err=%v", post.Id, post.FileIds, post.UserId, result.Err), mlog.String("post_id", post.Id))
+		}
+	}
+
+	if a.Metrics != nil {
+		a.Metrics.IncrementPostFileAttachment(len(post.FileIds))
+	}
+
+	return nil
+}
+
+// updateThreadMemberships runs before fan-out so that users who start following the thread are notified of the reply.
+func updateThreadMemberships(a *App, c *PostCreateContext) *model.AppError {
+	if len(c.Post.RootId) == 0 || c.ParentPostList == nil {
+		return nil
+	}
+
+	// The reply has already been saved, so failing to update the thread shouldn't fail the post
+	if err := a.updateThreadForReply(c.Post, c.User, c.ParentPostList); err != nil {
+		mlog.Error(fmt.Sprintf("Failed to update the thread memberships for post_id=%v, err=%v", c.Post.Id, err.Error()), mlog.String("post_id", c.Post.Id))
+	}
+
+	return nil
+}
+
+func runMessageHasBeenPostedHooks(a *App, c *PostCreateContext) *model.AppError {
+	if !a.PluginsReady() {
+		return nil
+	}
+
+	post := c.Post
+	a.Go(func() {
+		pluginContext := &plugin.Context{}
+		a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+			hooks.MessageHasBeenPosted(pluginContext, post)
+			return true
+		}, plugin.MessageHasBeenPostedId)
+	})
+
+	return nil
+}
+
+func indexPost(a *App, c *PostCreateContext) *model.AppError {
+	esInterface := a.Elasticsearch
+	if esInterface != nil && *a.Config().ElasticsearchSettings.EnableIndexing {
+		post, teamId := c.Post, c.Channel.TeamId
+		a.Go(func() {
+			esInterface.IndexPost(post, teamId)
+		})
+	}
+
+	return nil
+}
+
+func sendPostEvents(a *App, c *PostCreateContext) *model.AppError {
+	return a.handlePostEvents(c.Post, c.User, c.Channel, c.TriggerWebhooks, c.ParentPostList)
+}
+
+func sendThreadUpdates(a *App, c *PostCreateContext) *model.AppError {
+	if len(c.Post.RootId) > 0 {
+		post := c.Post
+		a.Go(func() {
+			a.sendThreadUpdatedEvents(post)
+		})
+	}
+
+	return nil
+}
+
+func sendPostToChannelBridges(a *App, c *PostCreateContext) *model.AppError {
+	a.sendChannelBridgeEvents(model.CHANNEL_BRIDGE_EVENT_POSTED, c.Post, nil)
+	return nil
+}
+
+func sendPostToEventSubscriptions(a *App, c *PostCreateContext) *model.AppError {
+	a.sendEventSubscriptionEvent(&model.EventSubscriptionPayload{
+		Event:     model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+		Timestamp: c.Post.CreateAt,
+		TeamId:    c.Channel.TeamId,
+		ChannelId: c.Channel.Id,
+		UserId:    c.Post.UserId,
+		Post:      c.Post,
+	})
+	return nil
+}
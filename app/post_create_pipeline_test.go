@@ -0,0 +1,106 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestPostCreatePipelineRegister(t *testing.T) {
+	pipeline := postCreatePipeline{}
+	noop := func(a *App, c *PostCreateContext) *model.AppError { return nil }
+
+	pipeline.register(POST_CREATE_STAGE_METADATA, "first", noop)
+	pipeline.register(POST_CREATE_STAGE_METADATA, "second", noop)
+	require.Len(t, pipeline[POST_CREATE_STAGE_METADATA], 2)
+	assert.Equal(t, "first", pipeline[POST_CREATE_STAGE_METADATA][0].name)
+	assert.Equal(t, "second", pipeline[POST_CREATE_STAGE_METADATA][1].name)
+
+	assert.Panics(t, func() { pipeline.register("unknown", "third", noop) })
+}
+
+func TestPostCreatePipelineRun(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	var ran []string
+	step := func(name string, err *model.AppError, halt bool) PostCreateMiddleware {
+		return func(a *App, c *PostCreateContext) *model.AppError {
+			ran = append(ran, name)
+			c.Halted = halt
+			return err
+		}
+	}
+
+	t.Run("runs the stages in order", func(t *testing.T) {
+		ran = nil
+		pipeline := postCreatePipeline{}
+		pipeline.register(POST_CREATE_STAGE_FAN_OUT, "fan_out", step("fan_out", nil, false))
+		pipeline.register(POST_CREATE_STAGE_VALIDATION, "validation", step("validation", nil, false))
+		pipeline.register(POST_CREATE_STAGE_PERSISTENCE, "persistence", step("persistence", nil, false))
+		pipeline.register(POST_CREATE_STAGE_MODERATION, "moderation", step("moderation", nil, false))
+
+		assert.Nil(t, pipeline.run(th.App, &PostCreateContext{Post: &model.Post{}}))
+		assert.Equal(t, []string{"validation", "moderation", "persistence", "fan_out"}, ran)
+	})
+
+	t.Run("stops at an error", func(t *testing.T) {
+		ran = nil
+		pipeline := postCreatePipeline{}
+		pipeline.register(POST_CREATE_STAGE_VALIDATION, "validation", step("validation", nil, false))
+		pipeline.register(POST_CREATE_STAGE_MODERATION, "moderation", step("moderation", model.NewAppError("test", "rejected", nil, "", http.StatusBadRequest), false))
+		pipeline.register(POST_CREATE_STAGE_PERSISTENCE, "persistence", step("persistence", nil, false))
+
+		err := pipeline.run(th.App, &PostCreateContext{Post: &model.Post{}})
+		require.NotNil(t, err)
+		assert.Equal(t, "rejected", err.Id)
+		assert.Equal(t, []string{"validation", "moderation"}, ran)
+	})
+
+	t.Run("stops when halted", func(t *testing.T) {
+		ran = nil
+		pipeline := postCreatePipeline{}
+		pipeline.register(POST_CREATE_STAGE_VALIDATION, "schedule", step("schedule", nil, true))
+		pipeline.register(POST_CREATE_STAGE_VALIDATION, "validation", step("validation", nil, false))
+		pipeline.register(POST_CREATE_STAGE_PERSISTENCE, "persistence", step("persistence", nil, false))
+
+		c := &PostCreateContext{Post: &model.Post{}}
+		assert.Nil(t, pipeline.run(th.App, c))
+		assert.True(t, c.Halted)
+		assert.Equal(t, []string{"schedule"}, ran)
+	})
+}
+
+func TestPostCreatePipelineFillsInPostBeforeModeration(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	pipeline := postCreatePipeline{
+		POST_CREATE_STAGE_MENTIONS: postCreateMiddleware[POST_CREATE_STAGE_MENTIONS],
+		POST_CREATE_STAGE_METADATA: postCreateMiddleware[POST_CREATE_STAGE_METADATA],
+	}
+
+	var moderated *model.Post
+	pipeline.register(POST_CREATE_STAGE_MODERATION, "record", func(a *App, c *PostCreateContext) *model.AppError {
+		moderated = c.Post
+		return nil
+	})
+
+	c := &PostCreateContext{
+		Post:    &model.Post{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id, Message: "#release in ~" + th.BasicChannel.Name},
+		Channel: th.BasicChannel,
+		User:    th.BasicUser,
+	}
+	require.Nil(t, pipeline.run(th.App, c))
+
+	require.NotNil(t, moderated)
+	assert.Equal(t, "#release", moderated.Hashtags, "plugins should be given the post's hashtags")
+	assert.NotNil(t, moderated.Props["channel_mentions"], "plugins should be given the post's channel mentions")
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// Events are only read once they're this old, since a change that was given an earlier Seq may still be committing
+// and a client that had already moved its cursor past it would never see it.
+const POST_EVENTS_SETTLE_MILLISECONDS = 2000
+
+// GetPostEventsForChannel returns a channel's post events after the given cursor. When afterSeq is negative, no
+// events are returned, only the cursor to start following the channel from.
+func (a *App) GetPostEventsForChannel(channelId string, afterSeq int64, limit int) (*model.PostEventList, *model.AppError) {
+	if !*a.Config().SqlSettings.EnablePostEvents {
+		return nil, model.NewAppError("GetPostEventsForChannel", "app.post_event.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	createdBefore := model.GetMillis() - POST_EVENTS_SETTLE_MILLISECONDS
+
+	if afterSeq < 0 {
+		result := <-a.Srv.Store.PostEvent().GetLatestSeq(channelId, createdBefore)
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return &model.PostEventList{Events: []*model.PostEvent{}, Cursor: result.Data.(int64)}, nil
+	}
+
+	list := &model.PostEventList{Cursor: afterSeq}
+	if result := <-a.Srv.Store.PostEvent().GetForChannel(channelId, afterSeq, createdBefore, limit); result.Err != nil {
+		return nil, result.Err
+	} else {
+		list.Events = result.Data.([]*model.PostEvent)
+	}
+
+	if len(list.Events) > 0 {
+		list.Cursor = list.Events[len(list.Events)-1].Seq
+	}
+
+	return list, nil
+}
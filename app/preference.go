@@ -46,6 +46,10 @@ func (a *App) UpdatePreferences(userId string, preferences model.Preferences) *m
 			return model.NewAppError("savePreferences", "api.preference.update_preferences.set.app_error", nil,
 				"userId="+userId+", preference.UserId="+preference.UserId, http.StatusForbidden)
 		}
+
+		if preference.IsServerManaged() {
+			return model.NewAppError("savePreferences", "api.preference.server_managed.app_error", nil, "category="+preference.Category, http.StatusForbidden)
+		}
 	}
 
 	if result := <-a.Srv.Store.Preference().Save(&preferences); result.Err != nil {
@@ -67,6 +71,10 @@ func (a *App) DeletePreferences(userId string, preferences model.Preferences) *m
 				"userId="+userId+", preference.UserId="+preference.UserId, http.StatusForbidden)
 			return err
 		}
+
+		if preference.IsServerManaged() {
+			return model.NewAppError("deletePreferences", "api.preference.server_managed.app_error", nil, "category="+preference.Category, http.StatusForbidden)
+		}
 	}
 
 	for _, preference := range preferences {
@@ -0,0 +1,203 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// StartProfileCapture starts capturing profiles of this server in the background, returning the job that tracks the
+// capture. Once the job succeeds, the profiles can be downloaded from the file store. Only one capture can run on a
+// server at a time since Go can only profile the CPU once at a time.
+func (a *App) StartProfileCapture(request *model.ProfileCaptureRequest, userId string) (*model.Job, *model.AppError) {
+	if err := request.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if len(*a.Config().FileSettings.DriverName) == 0 {
+		return nil, model.NewAppError("StartProfileCapture", "app.profile_capture.storage.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	profiles := request.Profiles
+	if len(profiles) == 0 {
+		profiles = model.ProfileCaptures
+	}
+
+	duration := request.DurationSeconds
+	if duration == 0 {
+		duration = model.PROFILE_CAPTURE_DEFAULT_DURATION_SECONDS
+	}
+
+	a.profileCaptureLock.Lock()
+	defer a.profileCaptureLock.Unlock()
+
+	if a.profileCaptureStop != nil {
+		return nil, model.NewAppError("StartProfileCapture", "app.profile_capture.in_progress.app_error", nil, "", http.StatusConflict)
+	}
+
+	now := model.GetMillis()
+	job := &model.Job{
+		Id:             model.NewId(),
+		Type:           model.JOB_TYPE_PROFILE_CAPTURE,
+		CreateAt:       now,
+		StartAt:        now,
+		LastActivityAt: now,
+		Status:         model.JOB_STATUS_IN_PROGRESS,
+		Data: map[string]string{
+			model.PROFILE_CAPTURE_DATA_PROFILES:         strings.Join(profiles, ","),
+			model.PROFILE_CAPTURE_DATA_DURATION_SECONDS: strconv.Itoa(duration),
+			model.PROFILE_CAPTURE_DATA_NODE_ID:          a.GetClusterId(),
+			model.PROFILE_CAPTURE_DATA_STARTED_BY:       userId,
+		},
+	}
+
+	// The job is created already in progress so that the job servers leave it alone, since the profiles have to be
+	// captured by the server that was asked for them
+	if result := <-a.Srv.Store.Job().Save(job); result.Err != nil {
+		return nil, result.Err
+	}
+
+	stop := make(chan struct{})
+	a.profileCaptureStop = stop
+
+	// The capture updates its own copy of the job so that it doesn't race with the caller
+	captureJob := *job
+	captureJob.Data = model.CopyStringMap(job.Data)
+
+	a.Go(func() {
+		a.captureProfiles(&captureJob, time.Duration(duration)*time.Second, stop)
+
+		a.profileCaptureLock.Lock()
+		a.profileCaptureStop = nil
+		a.profileCaptureLock.Unlock()
+	})
+
+	mlog.Info("Started capturing profiles", mlog.String("job_id", job.Id), mlog.String("profiles", job.Data[model.PROFILE_CAPTURE_DATA_PROFILES]), mlog.Int("duration_seconds", duration))
+
+	return job, nil
+}
+
+// stopProfileCapture ends the CPU profile of a capture that's in progress early so that the server can shut down
+// without waiting for it. The capture still finishes with the profiles that it has.
+func (a *App) stopProfileCapture() {
+	a.profileCaptureLock.Lock()
+	defer a.profileCaptureLock.Unlock()
+
+	if a.profileCaptureStop == nil {
+		return
+	}
+
+	select {
+	case <-a.profileCaptureStop:
+	default:
+		close(a.profileCaptureStop)
+	}
+}
+
+func (a *App) captureProfiles(job *model.Job, duration time.Duration, stop <-chan struct{}) {
+	captured := map[string]*bytes.Buffer{}
+	profiles := job.CapturedProfiles()
+
+	for _, profile := range profiles {
+		captured[profile] = &bytes.Buffer{}
+	}
+
+	if buf, ok := captured[model.PROFILE_CAPTURE_CPU]; ok {
+		if err := pprof.StartCPUProfile(buf); err != nil {
+			a.failProfileCapture(job, model.NewAppError("captureProfiles", "app.profile_capture.cpu.app_error", nil, err.Error(), http.StatusInternalServerError))
+			return
+		}
+	}
+
+	select {
+	case <-time.After(duration):
+	case <-stop:
+	}
+
+	if _, ok := captured[model.PROFILE_CAPTURE_CPU]; ok {
+		pprof.StopCPUProfile()
+	}
+
+	if buf, ok := captured[model.PROFILE_CAPTURE_HEAP]; ok {
+		// Collect garbage first so that the profile only shows memory that's still in use
+		runtime.GC()
+
+		if err := pprof.Lookup("heap").WriteTo(buf, 0); err != nil {
+			a.failProfileCapture(job, model.NewAppError("captureProfiles", "app.profile_capture.write.app_error", nil, err.Error(), http.StatusInternalServerError))
+			return
+		}
+	}
+
+	if buf, ok := captured[model.PROFILE_CAPTURE_GOROUTINE]; ok {
+		if err := pprof.Lookup("goroutine").WriteTo(buf, 0); err != nil {
+			a.failProfileCapture(job, model.NewAppError("captureProfiles", "app.profile_capture.write.app_error", nil, err.Error(), http.StatusInternalServerError))
+			return
+		}
+	}
+
+	for _, profile := range profiles {
+		if _, err := a.WriteFile(captured[profile], model.ProfileCapturePath(job.Id, profile)); err != nil {
+			a.failProfileCapture(job, err)
+			return
+		}
+	}
+
+	if err := a.Jobs.SetJobSuccess(job); err != nil {
+		mlog.Error("Unable to mark the profile capture as finished", mlog.String("job_id", job.Id), mlog.Err(err))
+		return
+	}
+
+	mlog.Info("Finished capturing profiles", mlog.String("job_id", job.Id))
+}
+
+func (a *App) failProfileCapture(job *model.Job, err *model.AppError) {
+	mlog.Error("Unable to capture profiles", mlog.String("job_id", job.Id), mlog.Err(err))
+
+	if err := a.Jobs.SetJobError(job, err); err != nil {
+		mlog.Error("Unable to mark the profile capture as failed", mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+}
+
+// GetProfileCaptures returns the profile captures that have been started by any server, newest first.
+func (a *App) GetProfileCaptures(page, perPage int) ([]*model.Job, *model.AppError) {
+	return a.GetJobsByTypePage(model.JOB_TYPE_PROFILE_CAPTURE, page, perPage)
+}
+
+// GetCapturedProfile returns one of the profiles from a capture that has finished, in the format read by pprof.
+func (a *App) GetCapturedProfile(jobId string, profile string) ([]byte, *model.AppError) {
+	job, err := a.GetJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Type != model.JOB_TYPE_PROFILE_CAPTURE {
+		return nil, model.NewAppError("GetCapturedProfile", "app.profile_capture.not_found.app_error", nil, "job_id="+jobId, http.StatusNotFound)
+	}
+
+	found := false
+	for _, captured := range job.CapturedProfiles() {
+		if captured == profile {
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, model.NewAppError("GetCapturedProfile", "app.profile_capture.not_found.app_error", nil, "job_id="+jobId+" profile="+profile, http.StatusNotFound)
+	}
+
+	if job.Status != model.JOB_STATUS_SUCCESS {
+		return nil, model.NewAppError("GetCapturedProfile", "app.profile_capture.not_finished.app_error", nil, "job_id="+jobId+" status="+job.Status, http.StatusBadRequest)
+	}
+
+	return a.ReadFile(model.ProfileCapturePath(jobId, profile))
+}
@@ -83,7 +83,12 @@ func (rl *RateLimiter) RateLimitWriter(key string, w http.ResponseWriter) bool {
 
 	if limited {
 		mlog.Error(fmt.Sprintf("Denied due to throttling settings code=429 key=%v", key))
-		http.Error(w, "limit exceeded", 429)
+
+		// Respond with an AppError like the rest of the API so that clients can tell from its code why they were denied
+		appErr := model.NewAppError("RateLimitWriter", "app.rate_limit.limited.app_error", nil, "", http.StatusTooManyRequests)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(appErr.StatusCode)
+		w.Write([]byte(appErr.ToJson()))
 	}
 
 	return limited
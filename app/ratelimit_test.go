@@ -80,3 +80,21 @@ func TestGenerateKey(t *testing.T) {
 		require.Equal(t, tc.expectedKey, key, "Wrong key on test "+strconv.Itoa(testnum))
 	}
 }
+
+func TestRateLimitWriter(t *testing.T) {
+	settings := genRateLimitSettings(false, false, "")
+	settings.MaxBurst = model.NewInt(0)
+	rateLimiter, err := NewRateLimiter(settings)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	require.False(t, rateLimiter.RateLimitWriter("key", w))
+
+	w = httptest.NewRecorder()
+	require.True(t, rateLimiter.RateLimitWriter("key", w))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	appErr := model.AppErrorFromJson(w.Body)
+	require.Equal(t, "app.rate_limit.limited.app_error", appErr.Id)
+	require.Equal(t, model.ERROR_CODE_RATE_LIMITED, appErr.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
 )
 
 func (a *App) SaveReactionForPost(reaction *model.Reaction) (*model.Reaction, *model.AppError) {
@@ -46,6 +47,26 @@ func (a *App) SaveReactionForPost(reaction *model.Reaction) (*model.Reaction, *m
 		a.sendReactionEvent(model.WEBSOCKET_EVENT_REACTION_ADDED, reaction, post, true)
 	})
 
+	a.sendChannelBridgeEvents(model.CHANNEL_BRIDGE_EVENT_REACTION_ADDED, post, reaction)
+
+	a.sendOutgoingWebhookEvent(&outgoingWebhookEvent{
+		Type:      model.OUTGOING_HOOK_EVENT_REACTION_ADDED,
+		ChannelId: channel.Id,
+		UserId:    reaction.UserId,
+		Post:      post,
+		Reaction:  reaction,
+	})
+
+	if a.PluginsReady() {
+		a.Go(func() {
+			pluginContext := &plugin.Context{}
+			a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+				hooks.ReactionHasBeenAdded(pluginContext, reaction, post)
+				return true
+			}, plugin.ReactionHasBeenAddedId)
+		})
+	}
+
 	return reaction, nil
 }
 
@@ -96,6 +117,18 @@ func (a *App) DeleteReactionForPost(reaction *model.Reaction) *model.AppError {
 		a.sendReactionEvent(model.WEBSOCKET_EVENT_REACTION_REMOVED, reaction, post, hasReactions)
 	})
 
+	a.sendChannelBridgeEvents(model.CHANNEL_BRIDGE_EVENT_REACTION_REMOVED, post, reaction)
+
+	if a.PluginsReady() {
+		a.Go(func() {
+			pluginContext := &plugin.Context{}
+			a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+				hooks.ReactionHasBeenRemoved(pluginContext, reaction, post)
+				return true
+			}, plugin.ReactionHasBeenRemovedId)
+		})
+	}
+
 	return nil
 }
 
@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// AreReadReceiptsEnabled returns true if members of the channel can see how far the other members have read it.
+func (a *App) AreReadReceiptsEnabled(channel *model.Channel) bool {
+	switch *a.Config().ServiceSettings.ReadReceipts {
+	case model.READ_RECEIPTS_ALL_CHANNELS:
+		return true
+	case model.READ_RECEIPTS_CHANNEL_OPT_IN:
+		return channel.ReadReceipts
+	default:
+		return false
+	}
+}
+
+// GetReadReceiptsForChannel returns how far each member of the channel has read it.
+func (a *App) GetReadReceiptsForChannel(channel *model.Channel) ([]*model.ReadReceipt, *model.AppError) {
+	if !a.AreReadReceiptsEnabled(channel) {
+		return nil, model.NewAppError("GetReadReceiptsForChannel", "app.read_receipt.disabled.app_error", nil, "channel_id="+channel.Id, http.StatusNotImplemented)
+	}
+
+	result := <-a.Srv.Store.Channel().GetReadReceipts(channel.Id, 0)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.([]*model.ReadReceipt), nil
+}
+
+// GetReadReceiptsForPost returns the read receipts of the members who have seen the post, not including its author.
+func (a *App) GetReadReceiptsForPost(post *model.Post, channel *model.Channel) ([]*model.ReadReceipt, *model.AppError) {
+	if !a.AreReadReceiptsEnabled(channel) {
+		return nil, model.NewAppError("GetReadReceiptsForPost", "app.read_receipt.disabled.app_error", nil, "channel_id="+channel.Id, http.StatusNotImplemented)
+	}
+
+	result := <-a.Srv.Store.Channel().GetReadReceipts(channel.Id, post.CreateAt)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	receipts := []*model.ReadReceipt{}
+	for _, receipt := range result.Data.([]*model.ReadReceipt) {
+		if receipt.UserId != post.UserId {
+			receipts = append(receipts, receipt)
+		}
+	}
+
+	return receipts, nil
+}
+
+// publishReadReceipts lets the members of each channel that has read receipts enabled know how far the user has now
+// read it. The given times are those returned when the channels were marked as viewed.
+func (a *App) publishReadReceipts(userId string, times map[string]int64) {
+	if *a.Config().ServiceSettings.ReadReceipts == model.READ_RECEIPTS_DISABLED {
+		return
+	}
+
+	for channelId, lastViewedAt := range times {
+		channel, err := a.GetChannel(channelId)
+		if err != nil {
+			mlog.Error("Unable to get the channel to publish a read receipt", mlog.String("channel_id", channelId), mlog.Err(err))
+			continue
+		}
+
+		if !a.AreReadReceiptsEnabled(channel) {
+			continue
+		}
+
+		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_READ_RECEIPT, "", channelId, "", nil)
+		message.Add("user_id", userId)
+		message.Add("last_viewed_at", lastViewedAt)
+		a.Publish(message)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestAreReadReceiptsEnabled(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	optedIn := &model.Channel{ReadReceipts: true}
+	optedOut := &model.Channel{}
+
+	assert.False(t, th.App.AreReadReceiptsEnabled(optedIn))
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceipts = model.READ_RECEIPTS_CHANNEL_OPT_IN })
+	assert.True(t, th.App.AreReadReceiptsEnabled(optedIn))
+	assert.False(t, th.App.AreReadReceiptsEnabled(optedOut))
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceipts = model.READ_RECEIPTS_ALL_CHANNELS })
+	assert.True(t, th.App.AreReadReceiptsEnabled(optedOut))
+}
+
+func TestGetReadReceiptsForPost(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.ReadReceipts = model.READ_RECEIPTS_ALL_CHANNELS })
+
+	post := th.CreatePost(th.BasicChannel)
+
+	receipts, err := th.App.GetReadReceiptsForPost(post, th.BasicChannel)
+	assert.Nil(t, err)
+	assert.Len(t, receipts, 0)
+
+	_, err = th.App.ViewChannel(&model.ChannelView{ChannelId: th.BasicChannel.Id}, th.BasicUser2.Id, false)
+	assert.Nil(t, err)
+
+	receipts, err = th.App.GetReadReceiptsForPost(post, th.BasicChannel)
+	assert.Nil(t, err)
+	if assert.Len(t, receipts, 1) {
+		assert.Equal(t, th.BasicUser2.Id, receipts[0].UserId)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store/sqlstore"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// initRedisCaches moves the session, profile and channel member caches into Redis when
+// ClusterSettings.EnableRedisCache is on, so that every server in the cluster shares them instead of each keeping its
+// own copy that has to be invalidated through cluster messages.
+func (a *App) initRedisCaches() {
+	settings := a.Config().ClusterSettings
+	if !*settings.EnableRedisCache {
+		return
+	}
+
+	a.redisCacheClient = redis.NewClient(&redis.Options{
+		Addr:     *settings.RedisAddress,
+		Password: *settings.RedisPassword,
+		DB:       *settings.RedisDatabase,
+	})
+
+	if err := a.redisCacheClient.Ping().Err(); err != nil {
+		// Until Redis can be reached, every lookup misses and falls through to the database
+		mlog.Error("Unable to reach Redis for caching", mlog.String("address", *settings.RedisAddress), mlog.Err(err))
+	}
+
+	a.redisCachePrefix = fmt.Sprintf("mattermost:cache:%v:", *settings.ClusterName)
+
+	a.sessionCache = utils.NewRedisCache(a.redisCacheClient, a.redisCachePrefix+"sessions:", "Session", &model.Session{}, 0, "")
+	sqlstore.UseRedisCaches(a.redisCacheClient, a.redisCachePrefix)
+
+	mlog.Info("Sharing caches through Redis", mlog.String("address", *settings.RedisAddress))
+}
+
+func (a *App) stopRedisCaches() {
+	if a.redisCacheClient == nil {
+		return
+	}
+
+	sqlstore.UseRedisCaches(nil, "")
+	a.redisCacheClient.Close()
+	a.redisCacheClient = nil
+	a.redisCachePrefix = ""
+}
+
+// usesRedisCaches is true when the session, profile and channel member caches are shared by the servers in the
+// cluster, so they don't need to tell each other to invalidate them.
+func (a *App) usesRedisCaches() bool {
+	return a.redisCacheClient != nil
+}
+
+// The session cache is keyed by token, so the tokens of each user's cached sessions are also kept in a set so that
+// they can be cleared without going through the whole cache.
+func (a *App) redisSessionTokensKey(userId string) string {
+	return a.redisCachePrefix + "session_tokens:" + userId
+}
+
+// addSessionToRedisIndex records that a session was cached for its user. The set expires with the last session added
+// to it, so it never outlives the sessions it lists.
+func (a *App) addSessionToRedisIndex(session *model.Session, expireInSecs int64) {
+	key := a.redisSessionTokensKey(session.UserId)
+
+	pipe := a.redisCacheClient.TxPipeline()
+	pipe.SAdd(key, session.Token)
+	pipe.Expire(key, time.Duration(expireInSecs)*time.Second)
+	if _, err := pipe.Exec(); err != nil {
+		mlog.Debug("Unable to index a session in the Redis cache", mlog.String("user_id", session.UserId), mlog.Err(err))
+	}
+}
+
+// clearRedisSessionCacheForUser removes the user's cached sessions using the set of their tokens.
+func (a *App) clearRedisSessionCacheForUser(userId string) {
+	key := a.redisSessionTokensKey(userId)
+
+	tokens, err := a.redisCacheClient.SMembers(key).Result()
+	if err != nil {
+		mlog.Warn("Unable to list the cached sessions of a user", mlog.String("user_id", userId), mlog.Err(err))
+		return
+	}
+
+	for _, token := range tokens {
+		a.sessionCache.Remove(token)
+		if a.Metrics != nil {
+			a.Metrics.IncrementMemCacheInvalidationCounterSession()
+		}
+	}
+
+	if err := a.redisCacheClient.Del(key).Err(); err != nil {
+		mlog.Warn("Unable to remove the cached sessions of a user", mlog.String("user_id", userId), mlog.Err(err))
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	REMINDERS_DELIVERY_BATCH_SIZE = 1000
+)
+
+// CreateReminder schedules a message to be posted to a channel on behalf of the user who set the reminder.
+func (a *App) CreateReminder(reminder *model.Reminder) (*model.Reminder, *model.AppError) {
+	if !a.HasPermissionToChannel(reminder.UserId, reminder.ChannelId, model.PERMISSION_CREATE_POST) {
+		return nil, model.NewAppError("CreateReminder", "app.reminder.create.permissions.app_error", nil, "channel_id="+reminder.ChannelId, http.StatusForbidden)
+	}
+
+	reminders, err := a.GetRemindersForUser(reminder.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reminders) >= model.REMINDER_MAX_PER_USER {
+		return nil, model.NewAppError("CreateReminder", "app.reminder.create.too_many.app_error", map[string]interface{}{"Max": model.REMINDER_MAX_PER_USER}, "user_id="+reminder.UserId, http.StatusBadRequest)
+	}
+
+	result := <-a.Srv.Store.Reminder().Save(reminder)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.(*model.Reminder), nil
+}
+
+func (a *App) GetReminder(reminderId string) (*model.Reminder, *model.AppError) {
+	result := <-a.Srv.Store.Reminder().Get(reminderId)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.(*model.Reminder), nil
+}
+
+// GetRemindersForUser returns the reminders that a user has set which haven't been delivered yet, soonest first.
+func (a *App) GetRemindersForUser(userId string) ([]*model.Reminder, *model.AppError) {
+	result := <-a.Srv.Store.Reminder().GetForUser(userId)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.([]*model.Reminder), nil
+}
+
+func (a *App) CancelReminder(reminderId string) *model.AppError {
+	if result := <-a.Srv.Store.Reminder().Delete(reminderId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// GetReminderChannelForUser returns the channel that reminders a user sets for themselves are posted to, which is their
+// direct message channel with themselves.
+func (a *App) GetReminderChannelForUser(userId string) (*model.Channel, *model.AppError) {
+	return a.CreateDirectChannel(userId, userId)
+}
+
+// DeliverDueReminders posts every reminder that has come due. Reminders that fail to be delivered because of a server
+// error are left in place to be retried the next time that this runs.
+func (a *App) DeliverDueReminders() *model.AppError {
+	result := <-a.Srv.Store.Reminder().GetDue(model.GetMillis(), REMINDERS_DELIVERY_BATCH_SIZE)
+	if result.Err != nil {
+		return result.Err
+	}
+
+	for _, reminder := range result.Data.([]*model.Reminder) {
+		if err := a.deliverReminder(reminder); err != nil {
+			mlog.Error("Failed to deliver reminder", mlog.String("reminder_id", reminder.Id), mlog.String("error", err.Error()))
+
+			if err.StatusCode >= http.StatusInternalServerError {
+				continue
+			}
+		}
+
+		if err := a.CancelReminder(reminder.Id); err != nil {
+			mlog.Error("Failed to delete delivered reminder", mlog.String("reminder_id", reminder.Id), mlog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func (a *App) deliverReminder(reminder *model.Reminder) *model.AppError {
+	user, err := a.GetUser(reminder.UserId)
+	if err != nil {
+		return err
+	}
+
+	channel, err := a.GetChannel(reminder.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	// The user may have left the channel or been deactivated since setting the reminder
+	if user.DeleteAt != 0 || channel.DeleteAt != 0 || !a.HasPermissionToChannel(user.Id, channel.Id, model.PERMISSION_CREATE_POST) {
+		return model.NewAppError("deliverReminder", "app.reminder.deliver.permissions.app_error", nil, "channel_id="+channel.Id, http.StatusForbidden)
+	}
+
+	T := utils.GetUserTranslations(user.Locale)
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		UserId:    user.Id,
+		Message:   T("app.reminder.deliver.message", map[string]interface{}{"Message": reminder.Message}),
+	}
+	post.AddProp(model.POST_PROPS_REMINDER_ID, reminder.Id)
+
+	// Users aren't normally notified of their own posts, so mark reminders that are posted to a user's channel with
+	// themselves as being sent by a bot
+	if channel.Type == model.CHANNEL_DIRECT && channel.Name == model.GetDMNameFromIds(user.Id, user.Id) {
+		post.AddProp("from_webhook", "true")
+	}
+
+	if _, err := a.CreatePost(post, channel, false); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestRemindCommand(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	cmd := &RemindProvider{}
+	args := &model.CommandArgs{
+		T:         i18n.IdentityTfunc(),
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		TeamId:    th.BasicTeam.Id,
+	}
+
+	resp := cmd.DoCommand(th.App, args, "me to call Bob")
+	assert.Equal(t, "api.command_remind.usage", resp.Text)
+
+	resp = cmd.DoCommand(th.App, args, "~missing-channel to call Bob in 5 minutes")
+	assert.Equal(t, "api.command_remind.channel.error", resp.Text)
+
+	resp = cmd.DoCommand(th.App, args, "me to call Bob in 5 minutes")
+	assert.Equal(t, "api.command_remind.success", resp.Text)
+
+	resp = cmd.DoCommand(th.App, args, "~"+th.BasicChannel.Name+" stand up at 9:30am tomorrow")
+	assert.Equal(t, "api.command_remind.success", resp.Text)
+
+	reminders, err := th.App.GetRemindersForUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	require.Len(t, reminders, 2)
+	assert.Equal(t, "call Bob", reminders[0].Message)
+	assert.Equal(t, th.BasicChannel.Id, reminders[1].ChannelId)
+
+	selfChannel, err := th.App.GetReminderChannelForUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, selfChannel.Id, reminders[0].ChannelId)
+
+	resp = cmd.DoCommand(th.App, args, "list")
+	assert.Contains(t, resp.Text, "api.command_remind.list.title")
+
+	resp = cmd.DoCommand(th.App, &model.CommandArgs{T: i18n.IdentityTfunc(), UserId: th.BasicUser2.Id}, "cancel "+reminders[0].Id)
+	assert.Equal(t, "api.command_remind.cancel.not_found", resp.Text, "shouldn't cancel another user's reminder")
+
+	resp = cmd.DoCommand(th.App, args, "cancel "+reminders[0].Id)
+	assert.Equal(t, "api.command_remind.cancel.success", resp.Text)
+
+	reminders, err = th.App.GetRemindersForUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Len(t, reminders, 1)
+}
+
+func TestDeliverDueReminders(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	selfChannel, err := th.App.GetReminderChannelForUser(th.BasicUser.Id)
+	require.Nil(t, err)
+
+	due, err := th.App.CreateReminder(&model.Reminder{
+		UserId:    th.BasicUser.Id,
+		ChannelId: selfChannel.Id,
+		Message:   "call Bob",
+		RemindAt:  model.GetMillis() + 10,
+	})
+	require.Nil(t, err)
+
+	notDue, err := th.App.CreateReminder(&model.Reminder{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "stand up",
+		RemindAt:  model.GetMillis() + 60*60*1000,
+	})
+	require.Nil(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+
+	require.Nil(t, th.App.DeliverDueReminders())
+
+	reminders, err := th.App.GetRemindersForUser(th.BasicUser.Id)
+	require.Nil(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, notDue.Id, reminders[0].Id)
+
+	posts, err := th.App.GetPosts(selfChannel.Id, 0, 1)
+	require.Nil(t, err)
+	require.Len(t, posts.Order, 1)
+
+	post := posts.Posts[posts.Order[0]]
+	assert.Equal(t, th.BasicUser.Id, post.UserId)
+	assert.Equal(t, due.Id, post.Props[model.POST_PROPS_REMINDER_ID])
+	assert.Equal(t, "true", post.Props["from_webhook"])
+}
+
+func TestCreateReminderPermissions(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	channel := th.CreatePrivateChannel(th.BasicTeam)
+
+	_, err := th.App.CreateReminder(&model.Reminder{
+		UserId:    th.BasicUser2.Id,
+		ChannelId: channel.Id,
+		Message:   "call Bob",
+		RemindAt:  model.GetMillis() + 60000,
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, "app.reminder.create.permissions.app_error", err.Id)
+}
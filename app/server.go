@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/handlers"
@@ -63,8 +64,6 @@ func (rl *RecoveryLogger) Println(i ...interface{}) {
 	mlog.Error(fmt.Sprint(i))
 }
 
-const TIME_TO_WAIT_FOR_CONNECTIONS_TO_CLOSE_ON_SERVER_SHUTDOWN = time.Second
-
 // golang.org/x/crypto/acme/autocert/autocert.go
 func handleHTTPRedirect(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" && r.Method != "HEAD" {
@@ -110,6 +109,10 @@ func (a *App) StartServer() error {
 		handler = corsWrapper.Handler(handler)
 	}
 
+	if *a.Config().ServiceSettings.EnableApiCompression {
+		handler = a.apiCompressionHandler(handler)
+	}
+
 	if *a.Config().RateLimitSettings.Enable {
 		mlog.Info("RateLimiter is enabled")
 
@@ -225,10 +228,33 @@ func (a *App) StartServer() error {
 	return nil
 }
 
+// StopServer stops accepting connections and waits for the requests that are in progress to finish, for up to
+// ServiceSettings.GracefulShutdownTimeoutSeconds, before closing the connections that are left. It can be called
+// before the rest of the app is shut down so that servers can be restarted one at a time without clients seeing errors.
 func (a *App) StopServer() {
 	if a.Srv.Server != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), TIME_TO_WAIT_FOR_CONNECTIONS_TO_CLOSE_ON_SERVER_SHUTDOWN)
+		timeout := time.Duration(*a.Config().ServiceSettings.GracefulShutdownTimeoutSeconds) * time.Second
+		mlog.Info("Draining connections", mlog.Int("timeout_seconds", *a.Config().ServiceSettings.GracefulShutdownTimeoutSeconds))
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
+
+		// Websocket connections are hijacked, so the HTTP server doesn't wait for them. Their clients are told to
+		// reconnect as soon as the listener is closed so that they move to another server while the requests that are
+		// in progress finish.
+		hubs := a.Hubs
+		listening := a.Srv.didFinishListen != nil
+		webConnsClosed := make(chan struct{})
+		var closeWebConns sync.Once
+		a.Srv.Server.RegisterOnShutdown(func() {
+			closeWebConns.Do(func() {
+				for _, hub := range hubs {
+					hub.CloseForRestart()
+				}
+				close(webConnsClosed)
+			})
+		})
+
 		didShutdown := false
 		for a.Srv.didFinishListen != nil && !didShutdown {
 			if err := a.Srv.Server.Shutdown(ctx); err != nil {
@@ -242,6 +268,11 @@ func (a *App) StopServer() {
 			}
 			timer.Stop()
 		}
+
+		if listening {
+			<-webConnsClosed
+		}
+
 		a.Srv.Server.Close()
 		a.Srv.Server = nil
 	}
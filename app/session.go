@@ -138,6 +138,12 @@ func (a *App) ClearSessionCacheForUser(userId string) {
 }
 
 func (a *App) ClearSessionCacheForUserSkipClusterSend(userId string) {
+	if a.usesRedisCaches() {
+		a.clearRedisSessionCacheForUser(userId)
+		a.InvalidateWebConnSessionCacheForUser(userId)
+		return
+	}
+
 	keys := a.sessionCache.Keys()
 
 	for _, key := range keys {
@@ -156,7 +162,12 @@ func (a *App) ClearSessionCacheForUserSkipClusterSend(userId string) {
 }
 
 func (a *App) AddSessionToCache(session *model.Session) {
-	a.sessionCache.AddWithExpiresInSecs(session.Token, session, int64(*a.Config().ServiceSettings.SessionCacheInMinutes*60))
+	expireInSecs := int64(*a.Config().ServiceSettings.SessionCacheInMinutes * 60)
+	a.sessionCache.AddWithExpiresInSecs(session.Token, session, expireInSecs)
+
+	if a.usesRedisCaches() {
+		a.addSessionToRedisIndex(session, expireInSecs)
+	}
 }
 
 func (a *App) SessionCacheLength() int {
@@ -211,6 +222,10 @@ func (a *App) RevokeSession(session *model.Session) *model.AppError {
 		}
 	}
 
+	if result := <-a.Srv.Store.WebPushSubscription().DeleteBySessionId(session.Id); result.Err != nil {
+		mlog.Error(fmt.Sprintf("Unable to remove web push subscriptions for session err=%v", result.Err), mlog.String("user_id", session.UserId))
+	}
+
 	a.RevokeWebrtcToken(session.Id)
 	a.ClearSessionCacheForUser(session.UserId)
 
@@ -259,8 +274,8 @@ func (a *App) CreateUserAccessToken(token *model.UserAccessToken) (*model.UserAc
 
 	if result := <-uchan; result.Err != nil {
 		mlog.Error(result.Err.Error())
-	} else {
-		user := result.Data.(*model.User)
+	} else if user := result.Data.(*model.User); !user.IsBot {
+		// Bots don't have real email addresses
 		if err := a.SendUserAccessTokenAddedEmail(user.Email, user.Locale, a.GetSiteURL()); err != nil {
 			mlog.Error(err.Error())
 		}
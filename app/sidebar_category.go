@@ -0,0 +1,171 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// GetSidebarCategories returns the categories that a user has made for their sidebar in a team, in the order that
+// they're shown.
+func (a *App) GetSidebarCategories(userId, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return nil, err
+	}
+
+	result := <-a.Srv.Store.SidebarCategory().GetForTeam(userId, teamId)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.([]*model.SidebarCategory), nil
+}
+
+// getSidebarCategoryForUser returns one of a user's categories, and returns a not found error if the category is in
+// another team or belongs to someone else.
+func (a *App) getSidebarCategoryForUser(userId, teamId, categoryId string) (*model.SidebarCategory, *model.AppError) {
+	result := <-a.Srv.Store.SidebarCategory().Get(categoryId)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	category := result.Data.(*model.SidebarCategory)
+	if category.UserId != userId || category.TeamId != teamId {
+		return nil, model.NewAppError("getSidebarCategoryForUser", "app.sidebar_category.not_found.app_error", nil, "category_id="+categoryId, http.StatusNotFound)
+	}
+
+	return category, nil
+}
+
+// checkSidebarCategoryChannels makes sure that a user only puts channels that they're a member of in their categories.
+// Direct and group messages can be put in a category in any team.
+func (a *App) checkSidebarCategoryChannels(userId, teamId string, channelIds []string) *model.AppError {
+	if len(channelIds) == 0 {
+		return nil
+	}
+
+	members, err := a.GetChannelMembersForUser(teamId, userId)
+	if err != nil {
+		return err
+	}
+
+	isMember := make(map[string]bool, len(*members))
+	for _, member := range *members {
+		isMember[member.ChannelId] = true
+	}
+
+	for _, channelId := range channelIds {
+		if !isMember[channelId] {
+			return model.NewAppError("checkSidebarCategoryChannels", "app.sidebar_category.channel_not_member.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+		}
+
+		channel, err := a.GetChannel(channelId)
+		if err != nil {
+			return err
+		}
+
+		if channel.TeamId != "" && channel.TeamId != teamId {
+			return model.NewAppError("checkSidebarCategoryChannels", "app.sidebar_category.channel_not_member.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) CreateSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return nil, err
+	}
+
+	categories, err := a.GetSidebarCategories(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categories) >= model.SIDEBAR_CATEGORY_MAX_PER_TEAM {
+		return nil, model.NewAppError("CreateSidebarCategory", "app.sidebar_category.too_many.app_error", map[string]interface{}{"Max": model.SIDEBAR_CATEGORY_MAX_PER_TEAM}, "user_id="+userId+", team_id="+teamId, http.StatusBadRequest)
+	}
+
+	if err := a.checkSidebarCategoryChannels(userId, teamId, category.ChannelIds); err != nil {
+		return nil, err
+	}
+
+	category = &model.SidebarCategory{
+		UserId:      userId,
+		TeamId:      teamId,
+		DisplayName: category.DisplayName,
+		SortOrder:   category.SortOrder,
+		ChannelIds:  category.ChannelIds,
+	}
+
+	result := <-a.Srv.Store.SidebarCategory().Save(category)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	category = result.Data.(*model.SidebarCategory)
+	a.publishSidebarCategoryEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_CREATED, category)
+
+	return category, nil
+}
+
+// UpdateSidebarCategory replaces the name, position and channels of one of a user's categories.
+func (a *App) UpdateSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return nil, err
+	}
+
+	oldCategory, err := a.getSidebarCategoryForUser(userId, teamId, category.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.checkSidebarCategoryChannels(userId, teamId, category.ChannelIds); err != nil {
+		return nil, err
+	}
+
+	updatedCategory := oldCategory
+	updatedCategory.DisplayName = category.DisplayName
+	updatedCategory.SortOrder = category.SortOrder
+	updatedCategory.ChannelIds = category.ChannelIds
+
+	result := <-a.Srv.Store.SidebarCategory().Update(updatedCategory)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	updatedCategory = result.Data.(*model.SidebarCategory)
+	a.publishSidebarCategoryEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_UPDATED, updatedCategory)
+
+	return updatedCategory, nil
+}
+
+func (a *App) DeleteSidebarCategory(userId, teamId, categoryId string) *model.AppError {
+	if err := a.checkChannelOrganizationEnabled(); err != nil {
+		return err
+	}
+
+	category, err := a.getSidebarCategoryForUser(userId, teamId, categoryId)
+	if err != nil {
+		return err
+	}
+
+	if result := <-a.Srv.Store.SidebarCategory().Delete(category.Id); result.Err != nil {
+		return result.Err
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_DELETED, teamId, "", userId, nil)
+	message.Add("category_id", category.Id)
+	a.Publish(message)
+
+	return nil
+}
+
+func (a *App) publishSidebarCategoryEvent(event string, category *model.SidebarCategory) {
+	message := model.NewWebSocketEvent(event, category.TeamId, "", category.UserId, nil)
+	message.Add("category", category.ToJson())
+	a.Publish(message)
+}
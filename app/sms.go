@@ -0,0 +1,177 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// SendSms sends a text message to a phone number through the registered SMS gateway, or through the Twilio-compatible
+// API in the config if there isn't one.
+func (a *App) SendSms(phoneNumber string, message string) *model.AppError {
+	if !*a.Config().SmsSettings.Enable {
+		return model.NewAppError("SendSms", "app.sms.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if runes := []rune(message); len(runes) > model.SMS_MESSAGE_MAX_RUNES {
+		message = string(runes[:model.SMS_MESSAGE_MAX_RUNES-1]) + "…"
+	}
+
+	if a.Sms != nil {
+		return a.Sms.SendSms(phoneNumber, message)
+	}
+
+	return a.sendTwilioSms(phoneNumber, message)
+}
+
+func (a *App) sendTwilioSms(phoneNumber string, message string) *model.AppError {
+	settings := a.Config().SmsSettings
+
+	form := url.Values{}
+	form.Set("To", phoneNumber)
+	form.Set("From", *settings.FromNumber)
+	form.Set("Body", message)
+
+	apiURL := strings.TrimRight(*settings.ApiURL, "/") + "/2010-04-01/Accounts/" + url.PathEscape(*settings.AccountSid) + "/Messages.json"
+
+	req, err := http.NewRequest(http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return model.NewAppError("sendTwilioSms", "app.sms.send.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(*settings.AccountSid, *settings.AuthToken)
+
+	resp, err := a.HTTPClient(true).Do(req)
+	if err != nil {
+		return model.NewAppError("sendTwilioSms", "app.sms.send.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return model.NewAppError("sendTwilioSms", "app.sms.send.app_error", nil, fmt.Sprintf("status_code=%v", resp.StatusCode), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// SendPhoneVerificationCode texts a code to a phone number which the user then enters to prove that the number is
+// theirs. The number isn't stored on the user until it's verified.
+func (a *App) SendPhoneVerificationCode(userId string, phoneNumber string) *model.AppError {
+	phoneNumber = model.NormalizePhoneNumber(phoneNumber)
+	if !model.IsValidPhoneNumber(phoneNumber) {
+		return model.NewAppError("SendPhoneVerificationCode", "app.sms.phone_number.invalid.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return err
+	}
+
+	code := model.NewPhoneVerificationCode()
+	token := &model.Token{
+		Token:    model.GetPhoneVerificationToken(userId, code),
+		CreateAt: model.GetMillis(),
+		Type:     model.TOKEN_TYPE_PHONE_VERIFICATION,
+		Extra:    (&model.PhoneVerification{UserId: userId, PhoneNumber: phoneNumber}).ToJson(),
+	}
+
+	if result := <-a.Srv.Store.Token().Save(token); result.Err != nil {
+		return result.Err
+	}
+
+	T := utils.GetUserTranslations(user.Locale)
+	return a.SendSms(phoneNumber, T("app.sms.verification_code", map[string]interface{}{"Code": code, "SiteName": a.ClientConfig()["SiteName"]}))
+}
+
+// VerifyPhoneNumber checks the code that the user was sent and stores the phone number that it was sent to.
+func (a *App) VerifyPhoneNumber(userId string, code string) (*model.User, *model.AppError) {
+	result := <-a.Srv.Store.Token().GetByToken(model.GetPhoneVerificationToken(userId, code))
+	if result.Err != nil {
+		return nil, model.NewAppError("VerifyPhoneNumber", "app.sms.verify.bad_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	token := result.Data.(*model.Token)
+	verification := model.PhoneVerificationFromJson(token.Extra)
+	if token.Type != model.TOKEN_TYPE_PHONE_VERIFICATION || verification == nil || verification.UserId != userId {
+		return nil, model.NewAppError("VerifyPhoneNumber", "app.sms.verify.bad_code.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := a.DeleteToken(token); err != nil {
+		mlog.Error(err.Error())
+	}
+
+	if model.GetMillis()-token.CreateAt >= model.PHONE_VERIFICATION_EXPIRY_TIME {
+		return nil, model.NewAppError("VerifyPhoneNumber", "app.sms.verify.expired.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return a.setPhoneNumber(userId, verification.PhoneNumber)
+}
+
+// RemovePhoneNumber removes the user's verified phone number so that they stop receiving text messages.
+func (a *App) RemovePhoneNumber(userId string) (*model.User, *model.AppError) {
+	return a.setPhoneNumber(userId, "")
+}
+
+func (a *App) setPhoneNumber(userId string, phoneNumber string) (*model.User, *model.AppError) {
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MakeNonNil()
+	if phoneNumber == "" {
+		delete(user.Props, model.USER_PROP_PHONE_NUMBER)
+	} else {
+		user.Props[model.USER_PROP_PHONE_NUMBER] = phoneNumber
+	}
+
+	result := <-a.Srv.Store.User().Update(user, true)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	ruser := result.Data.([2]*model.User)[0]
+
+	a.InvalidateCacheForUser(userId)
+	a.sendUpdatedUserEvent(*ruser)
+
+	return ruser, nil
+}
+
+// ShouldSendSmsNotification returns true if an urgent mention should be sent to the user by text message because
+// they're offline and have asked to receive them. Quiet hours still apply.
+func (a *App) ShouldSendSmsNotification(user *model.User, status *model.Status, post *model.Post) bool {
+	return *a.Config().SmsSettings.Enable &&
+		post.IsUrgent() &&
+		user.DeleteAt == 0 &&
+		user.NotifyProps[model.SMS_NOTIFY_PROP] == "true" &&
+		user.GetPhoneNumber() != "" &&
+		status.Status == model.STATUS_OFFLINE &&
+		DoesScheduleAllowNotification(user, model.GetMillis())
+}
+
+func (a *App) sendSmsNotification(post *model.Post, user *model.User, channelName string, senderName string) {
+	T := utils.GetUserTranslations(user.Locale)
+
+	message := T("app.sms.notification", map[string]interface{}{
+		"SenderName":  senderName,
+		"ChannelName": channelName,
+		"Message":     model.ClearMentionTags(post.Message),
+	})
+
+	if err := a.SendSms(user.GetPhoneNumber(), message); err != nil {
+		mlog.Error(fmt.Sprintf("Unable to send SMS notification err=%v", err), mlog.String("user_id", user.Id), mlog.String("post_id", post.Id))
+	}
+}
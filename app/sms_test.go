@@ -0,0 +1,135 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+type testSmsInterface struct {
+	phoneNumbers []string
+	messages     []string
+}
+
+func (s *testSmsInterface) SendSms(phoneNumber string, message string) *model.AppError {
+	s.phoneNumbers = append(s.phoneNumbers, phoneNumber)
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestSendTwilioSms(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	var status int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", username)
+		assert.Equal(t, "secret", password)
+
+		assert.Equal(t, "+15555550123", r.FormValue("To"))
+		assert.Equal(t, "+15555550100", r.FormValue("From"))
+		assert.Equal(t, "hello", r.FormValue("Body"))
+
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.AllowedUntrustedInternalConnections = "localhost 127.0.0.1"
+		*cfg.SmsSettings.Enable = true
+		*cfg.SmsSettings.ApiURL = ts.URL
+		*cfg.SmsSettings.AccountSid = "AC123"
+		*cfg.SmsSettings.AuthToken = "secret"
+		*cfg.SmsSettings.FromNumber = "+15555550100"
+	})
+
+	status = http.StatusCreated
+	assert.Nil(t, th.App.SendSms("+15555550123", "hello"))
+
+	status = http.StatusUnauthorized
+	assert.NotNil(t, th.App.SendSms("+15555550123", "hello"))
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SmsSettings.Enable = false
+	})
+	assert.NotNil(t, th.App.SendSms("+15555550123", "hello"))
+}
+
+func TestVerifyPhoneNumber(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	sms := &testSmsInterface{}
+	th.App.Sms = sms
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SmsSettings.Enable = true
+	})
+
+	err := th.App.SendPhoneVerificationCode(th.BasicUser.Id, "not a number")
+	assert.NotNil(t, err)
+
+	err = th.App.SendPhoneVerificationCode(th.BasicUser.Id, "+1 (555) 555-0123")
+	require.Nil(t, err)
+	require.Len(t, sms.messages, 1)
+	assert.Equal(t, "+15555550123", sms.phoneNumbers[0])
+
+	code := regexp.MustCompile(`[0-9]{6}`).FindString(sms.messages[0])
+	require.NotEmpty(t, code)
+
+	_, err = th.App.VerifyPhoneNumber(th.BasicUser2.Id, code)
+	assert.NotNil(t, err, "shouldn't be able to use another user's code")
+
+	user, err := th.App.VerifyPhoneNumber(th.BasicUser.Id, code)
+	require.Nil(t, err)
+	assert.Equal(t, "+15555550123", user.GetPhoneNumber())
+
+	_, err = th.App.VerifyPhoneNumber(th.BasicUser.Id, code)
+	assert.NotNil(t, err, "shouldn't be able to use a code twice")
+
+	user, err = th.App.RemovePhoneNumber(th.BasicUser.Id)
+	require.Nil(t, err)
+	assert.Equal(t, "", user.GetPhoneNumber())
+}
+
+func TestShouldSendSmsNotification(t *testing.T) {
+	th := Setup()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SmsSettings.Enable = true
+	})
+
+	user := &model.User{
+		Id:          model.NewId(),
+		NotifyProps: map[string]string{model.SMS_NOTIFY_PROP: "true"},
+		Props:       map[string]string{model.USER_PROP_PHONE_NUMBER: "+15555550123"},
+	}
+	offline := &model.Status{UserId: user.Id, Status: model.STATUS_OFFLINE}
+	online := &model.Status{UserId: user.Id, Status: model.STATUS_ONLINE}
+	urgent := &model.Post{Message: "help", Props: model.StringInterface{model.POST_PROPS_PRIORITY: model.POST_PRIORITY_URGENT}}
+	normal := &model.Post{Message: "hi"}
+
+	assert.True(t, th.App.ShouldSendSmsNotification(user, offline, urgent))
+	assert.False(t, th.App.ShouldSendSmsNotification(user, online, urgent))
+	assert.False(t, th.App.ShouldSendSmsNotification(user, offline, normal))
+
+	user.NotifyProps[model.SMS_NOTIFY_PROP] = "false"
+	assert.False(t, th.App.ShouldSendSmsNotification(user, offline, urgent))
+	user.NotifyProps[model.SMS_NOTIFY_PROP] = "true"
+
+	delete(user.Props, model.USER_PROP_PHONE_NUMBER)
+	assert.False(t, th.App.ShouldSendSmsNotification(user, offline, urgent))
+}
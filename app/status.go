@@ -8,6 +8,7 @@ import (
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
 	"github.com/mattermost/mattermost-server/store"
 	"github.com/mattermost/mattermost-server/utils"
 )
@@ -239,6 +240,17 @@ func (a *App) BroadcastStatus(status *model.Status) {
 	event.Add("status", status.Status)
 	event.Add("user_id", status.UserId)
 	a.Publish(event)
+
+	if a.PluginsReady() {
+		statusCopy := *status
+		a.Go(func() {
+			pluginContext := &plugin.Context{}
+			a.Plugins.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+				hooks.UserStatusHasChanged(pluginContext, &statusCopy)
+				return true
+			}, plugin.UserStatusHasChangedId)
+		})
+	}
 }
 
 func (a *App) SetStatusOffline(userId string, manual bool) {
@@ -305,13 +317,21 @@ func (a *App) SetStatusDoNotDisturb(userId string) {
 	a.SaveAndBroadcastStatus(status)
 }
 
+// SaveAndBroadcastStatus saves a user's status and sends it to clients. Since clients are only sent the status itself,
+// it isn't sent again if the user already had it.
 func (a *App) SaveAndBroadcastStatus(status *model.Status) {
+	oldStatus := GetStatusFromCache(status.UserId)
+
 	a.AddStatusCache(status)
 
 	if result := <-a.Srv.Store.Status().SaveOrUpdate(status); result.Err != nil {
 		mlog.Error(fmt.Sprintf("Failed to save status for user_id=%v, err=%v", status.UserId, result.Err))
 	}
 
+	if oldStatus != nil && oldStatus.Status == status.Status {
+		return
+	}
+
 	a.BroadcastStatus(status)
 }
 
@@ -363,3 +383,22 @@ func (a *App) GetStatus(userId string) (*model.Status, *model.AppError) {
 func (a *App) IsUserAway(lastActivityAt int64) bool {
 	return model.GetMillis()-lastActivityAt >= *a.Config().TeamSettings.UserStatusAwayTimeout*1000
 }
+
+// SanitizeStatuses returns copies of statuses with the last activity of other users removed if it's hidden from the
+// user who requested them.
+func (a *App) SanitizeStatuses(statuses []*model.Status, userId string, asAdmin bool) []*model.Status {
+	if asAdmin || a.Config().PrivacySettings.IsUserFieldVisible(model.USER_FIELD_LAST_ACTIVITY) {
+		return statuses
+	}
+
+	sanitized := make([]*model.Status, len(statuses))
+	for i, status := range statuses {
+		cpy := *status
+		if cpy.UserId != userId {
+			cpy.LastActivityAt = 0
+		}
+		sanitized[i] = &cpy
+	}
+
+	return sanitized
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type storeMethodCounter struct {
+	mutex sync.Mutex
+	stats model.StoreMethodStats
+}
+
+func (c *storeMethodCounter) observe(elapsed time.Duration, rows int, failed bool) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.CallCount++
+	if failed {
+		c.stats.ErrorCount++
+	}
+
+	c.stats.RowCount += int64(rows)
+	c.stats.TotalDurationMs += ms
+	if ms > c.stats.MaxMs {
+		c.stats.MaxMs = ms
+	}
+}
+
+func (c *storeMethodCounter) snapshot() *model.StoreMethodStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	if stats.CallCount > 0 {
+		stats.AverageMs = stats.TotalDurationMs / float64(stats.CallCount)
+	}
+
+	return &stats
+}
+
+// storeTracer records the calls made to each store method while MetricsSettings.EnableStoreTracing is on.
+type storeTracer struct {
+	app *App
+
+	methods sync.Map
+
+	sinceLock sync.Mutex
+	since     int64
+}
+
+func newStoreTracer(a *App) *storeTracer {
+	return &storeTracer{
+		app:   a,
+		since: model.GetMillis(),
+	}
+}
+
+func (t *storeTracer) TraceQuery(method string, elapsed time.Duration, rows int, failed bool) {
+	if metrics := t.app.Metrics; metrics != nil {
+		metrics.ObserveStoreMethodDuration(method, float64(elapsed)/float64(time.Second))
+		metrics.AddStoreMethodRowCount(method, float64(rows))
+
+		if failed {
+			metrics.IncrementStoreMethodError(method)
+		}
+	}
+
+	counter, ok := t.methods.Load(method)
+	if !ok {
+		counter, _ = t.methods.LoadOrStore(method, &storeMethodCounter{stats: model.StoreMethodStats{Method: method}})
+	}
+
+	counter.(*storeMethodCounter).observe(elapsed, rows, failed)
+}
+
+// configureStoreTracing starts or stops tracing the store to match the configuration.
+func (a *App) configureStoreTracing() {
+	if *a.Config().MetricsSettings.EnableStoreTracing {
+		store.SetQueryTracer(a.storeTracer)
+	} else {
+		store.SetQueryTracer(nil)
+	}
+}
+
+// GetStoreTracingReport returns the calls that this server has made to each store method since it started or since the
+// report was last reset, with the methods that took the longest in total first.
+func (a *App) GetStoreTracingReport() *model.StoreTracingReport {
+	a.storeTracer.sinceLock.Lock()
+	since := a.storeTracer.since
+	a.storeTracer.sinceLock.Unlock()
+
+	report := &model.StoreTracingReport{
+		Enabled: *a.Config().MetricsSettings.EnableStoreTracing,
+		Since:   since,
+		Methods: []*model.StoreMethodStats{},
+	}
+
+	a.storeTracer.methods.Range(func(method, counter interface{}) bool {
+		report.Methods = append(report.Methods, counter.(*storeMethodCounter).snapshot())
+		return true
+	})
+
+	sort.Slice(report.Methods, func(i, j int) bool {
+		if report.Methods[i].TotalDurationMs != report.Methods[j].TotalDurationMs {
+			return report.Methods[i].TotalDurationMs > report.Methods[j].TotalDurationMs
+		}
+		return report.Methods[i].Method < report.Methods[j].Method
+	})
+
+	return report
+}
+
+func (a *App) ResetStoreTracingReport() {
+	a.storeTracer.sinceLock.Lock()
+	defer a.storeTracer.sinceLock.Unlock()
+
+	a.storeTracer.methods.Range(func(method, counter interface{}) bool {
+		a.storeTracer.methods.Delete(method)
+		return true
+	})
+
+	a.storeTracer.since = model.GetMillis()
+}
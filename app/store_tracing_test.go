@@ -0,0 +1,51 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func getTracedTestUsers(failed bool) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if failed {
+			result.Err = model.NewAppError("getTracedTestUsers", "app.test.app_error", nil, "", 500)
+			return
+		}
+
+		result.Data = []*model.User{{Id: model.NewId()}, {Id: model.NewId()}}
+	})
+}
+
+func TestStoreTracer(t *testing.T) {
+	tracer := newStoreTracer(&App{})
+
+	store.SetQueryTracer(tracer)
+	defer store.SetQueryTracer(nil)
+
+	<-getTracedTestUsers(false)
+	<-getTracedTestUsers(false)
+	<-getTracedTestUsers(true)
+
+	counter, ok := tracer.methods.Load("app.getTracedTestUsers")
+	require.True(t, ok, "should name the method that called Do")
+
+	stats := counter.(*storeMethodCounter).snapshot()
+	assert.Equal(t, int64(3), stats.CallCount)
+	assert.Equal(t, int64(1), stats.ErrorCount)
+	assert.Equal(t, int64(4), stats.RowCount)
+	assert.True(t, stats.MaxMs >= stats.AverageMs)
+
+	store.SetQueryTracer(nil)
+	<-getTracedTestUsers(false)
+
+	counter, _ = tracer.methods.Load("app.getTracedTestUsers")
+	assert.Equal(t, int64(3), counter.(*storeMethodCounter).snapshot().CallCount, "should stop tracing")
+}
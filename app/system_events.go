@@ -0,0 +1,146 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// PostSystemEvent posts the event into the system events channel in the background if events of its type are
+// enabled, so that administrators see problems without having to watch the logs.
+func (a *App) PostSystemEvent(event *model.SystemEvent) {
+	settings := a.Config().SystemEventsSettings
+	if !event.IsEnabled(&settings) {
+		return
+	}
+
+	channelId := *settings.ChannelId
+
+	a.Go(func() {
+		if err := a.postSystemEvent(event, channelId); err != nil {
+			mlog.Error(fmt.Sprintf("Failed to post system event type=%v err=%v", event.Type, err.Error()))
+		}
+	})
+}
+
+func (a *App) postSystemEvent(event *model.SystemEvent, channelId string) *model.AppError {
+	channel, err := a.GetChannel(channelId)
+	if err != nil {
+		return err
+	}
+
+	if channel.DeleteAt > 0 {
+		return model.NewAppError("postSystemEvent", "app.system_event.archived_channel.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+	}
+
+	bot, err := a.getSystemEventsBot()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.CreatePost(event.ToPost(bot.Id, channel.Id), channel, false)
+	return err
+}
+
+// getSystemEventsBot returns the user that system events are posted as, creating it the first time that it's needed.
+func (a *App) getSystemEventsBot() (*model.User, *model.AppError) {
+	if result := <-a.Srv.Store.System().GetByName(model.SYSTEM_EVENTS_BOT_USER_ID); result.Err == nil {
+		if user, err := a.GetUser(result.Data.(*model.System).Value); err == nil {
+			return user, nil
+		}
+	}
+
+	// Nobody knows the password, so the bot can only post through the server
+	username := model.SYSTEM_EVENTS_BOT_USERNAME
+	if result := <-a.Srv.Store.User().GetByUsername(username); result.Err == nil {
+		username = username + "-" + model.NewId()[:8]
+	}
+
+	bot := &model.User{
+		Username:      username,
+		Email:         username + "@localhost",
+		Password:      model.NewId(),
+		Nickname:      utils.T("app.system_event.bot.nickname"),
+		EmailVerified: true,
+	}
+
+	var ruser *model.User
+	if result := <-a.Srv.Store.User().Save(bot); result.Err != nil {
+		return nil, result.Err
+	} else {
+		ruser = result.Data.(*model.User)
+	}
+
+	if result := <-a.Srv.Store.System().SaveOrUpdate(&model.System{Name: model.SYSTEM_EVENTS_BOT_USER_ID, Value: ruser.Id}); result.Err != nil {
+		return nil, result.Err
+	}
+
+	return ruser, nil
+}
+
+func newJobFailedSystemEvent(job *model.Job, jobError *model.AppError) *model.SystemEvent {
+	event := &model.SystemEvent{
+		Type:  model.SYSTEM_EVENT_TYPE_JOB_FAILED,
+		Title: utils.T("app.system_event.job_failed.title", map[string]interface{}{"Type": job.Type}),
+	}
+	event.AddField(utils.T("app.system_event.field.job_id"), job.Id)
+	if jobError != nil {
+		event.AddField(utils.T("app.system_event.field.error"), jobError.Message)
+	}
+
+	return event
+}
+
+func newConfigChangedSystemEvent(sections []string) *model.SystemEvent {
+	event := &model.SystemEvent{
+		Type:  model.SYSTEM_EVENT_TYPE_CONFIG_CHANGED,
+		Title: utils.T("app.system_event.config_changed.title"),
+	}
+	event.AddField(utils.T("app.system_event.field.sections"), strings.Join(sections, ", "))
+
+	return event
+}
+
+func newSecuritySystemEvent(title string, user *model.User) *model.SystemEvent {
+	event := &model.SystemEvent{
+		Type:  model.SYSTEM_EVENT_TYPE_SECURITY,
+		Title: title,
+	}
+	event.AddField(utils.T("app.system_event.field.user"), "@"+user.Username)
+
+	return event
+}
+
+func newUserDeactivatedSystemEvent(user *model.User) *model.SystemEvent {
+	event := &model.SystemEvent{
+		Type:  model.SYSTEM_EVENT_TYPE_USER_DEACTIVATED,
+		Title: utils.T("app.system_event.user_deactivated.title"),
+	}
+	event.AddField(utils.T("app.system_event.field.user"), "@"+user.Username)
+
+	return event
+}
+
+// changedConfigSections returns the names of the sections of the config that differ. Only the names are reported so
+// that secrets in the config never end up in a post.
+func changedConfigSections(oldCfg, newCfg *model.Config) []string {
+	var sections []string
+
+	oldValue := reflect.ValueOf(*oldCfg)
+	newValue := reflect.ValueOf(*newCfg)
+	for i := 0; i < oldValue.NumField(); i++ {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			sections = append(sections, oldValue.Type().Field(i).Name)
+		}
+	}
+
+	return sections
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestChangedConfigSections(t *testing.T) {
+	oldCfg := &model.Config{}
+	oldCfg.SetDefaults()
+
+	newCfg := oldCfg.Clone()
+	assert.Empty(t, changedConfigSections(oldCfg, newCfg))
+
+	newCfg.EmailSettings.SMTPPassword = "secret"
+	newCfg.TeamSettings.SiteName = "Changed"
+	assert.Equal(t, []string{"TeamSettings", "EmailSettings"}, changedConfigSections(oldCfg, newCfg))
+}
+
+func TestPostSystemEvent(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.SystemEventsSettings.Enable = true
+		*cfg.SystemEventsSettings.ChannelId = th.BasicChannel.Id
+	})
+
+	event := newUserDeactivatedSystemEvent(th.BasicUser2)
+	assert.Nil(t, th.App.postSystemEvent(event, th.BasicChannel.Id))
+	assert.Nil(t, th.App.postSystemEvent(event, th.BasicChannel.Id))
+
+	bot, err := th.App.GetUserByUsername(model.SYSTEM_EVENTS_BOT_USERNAME)
+	assert.Nil(t, err)
+
+	posts, err := th.App.GetPosts(th.BasicChannel.Id, 0, 2)
+	assert.Nil(t, err)
+	for _, post := range posts.Posts {
+		assert.Equal(t, bot.Id, post.UserId, "should reuse the same bot")
+		assert.Equal(t, model.SYSTEM_EVENT_TYPE_USER_DEACTIVATED, post.Props[model.POST_PROPS_SYSTEM_EVENT_TYPE])
+	}
+}
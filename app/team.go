@@ -499,6 +499,13 @@ func (a *App) JoinUserToTeam(team *model.Team, user *model.User, userRequestorId
 	message.Add("user_id", user.Id)
 	a.Publish(message)
 
+	a.sendEventSubscriptionEvent(&model.EventSubscriptionPayload{
+		Event:     model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED,
+		Timestamp: model.GetMillis(),
+		TeamId:    team.Id,
+		UserId:    user.Id,
+	})
+
 	return nil
 }
 
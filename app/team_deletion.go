@@ -0,0 +1,457 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	TEAM_DELETION_DAY_MILLISECONDS = 24 * 60 * 60 * 1000
+	TEAM_EXPORT_DIRECTORY          = "exports/teams/"
+	TEAM_EXPORT_BATCH_SIZE         = 200
+)
+
+// ScheduleTeamDeletion archives the team and schedules it to be permanently deleted, along with all of its content,
+// once the configured grace period has passed.
+func (a *App) ScheduleTeamDeletion(teamId string, requesterId string) (*model.Team, *model.AppError) {
+	team, err := a.GetTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	if team.ScheduledDeleteAt != 0 {
+		return nil, model.NewAppError("ScheduleTeamDeletion", "api.team.schedule_team_deletion.already_scheduled.app_error", nil, "team_id="+team.Id, http.StatusBadRequest)
+	}
+
+	// Remember when a team that was already archived was archived so that cancelling the deletion leaves it archived.
+	now := model.GetMillis()
+	team.ArchivedAt = team.DeleteAt
+	if team.DeleteAt == 0 {
+		team.DeleteAt = now
+	}
+	team.ScheduledDeleteAt = now + int64(*a.Config().TeamSettings.TeamDeletionGracePeriodDays)*TEAM_DELETION_DAY_MILLISECONDS
+
+	if result := <-a.Srv.Store.Team().Update(team); result.Err != nil {
+		return nil, result.Err
+	}
+
+	a.sendTeamEvent(team, model.WEBSOCKET_EVENT_DELETE_TEAM)
+
+	a.Go(func() {
+		a.sendTeamDeletionEmails(team, "scheduled", requesterId)
+	})
+
+	return team, nil
+}
+
+// CancelTeamDeletion restores a team that is scheduled for deletion. A team that was already archived before its
+// deletion was scheduled stays archived.
+func (a *App) CancelTeamDeletion(teamId string, requesterId string) (*model.Team, *model.AppError) {
+	team, err := a.GetTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	if team.ScheduledDeleteAt == 0 {
+		return nil, model.NewAppError("CancelTeamDeletion", "api.team.cancel_team_deletion.not_scheduled.app_error", nil, "team_id="+team.Id, http.StatusBadRequest)
+	}
+
+	team.DeleteAt = team.ArchivedAt
+	team.ScheduledDeleteAt = 0
+	team.ArchivedAt = 0
+
+	if result := <-a.Srv.Store.Team().Update(team); result.Err != nil {
+		return nil, result.Err
+	}
+
+	a.sendTeamEvent(team, model.WEBSOCKET_EVENT_UPDATE_TEAM)
+
+	a.Go(func() {
+		a.sendTeamDeletionEmails(team, "cancelled", requesterId)
+	})
+
+	return team, nil
+}
+
+// DeleteScheduledTeams exports and then permanently deletes every team whose deletion grace period has ended.
+func (a *App) DeleteScheduledTeams(now time.Time) *model.AppError {
+	var teams []*model.Team
+	if result := <-a.Srv.Store.Team().GetScheduledForDeletion(now.UnixNano() / int64(time.Millisecond)); result.Err != nil {
+		return result.Err
+	} else {
+		teams = result.Data.([]*model.Team)
+	}
+
+	for _, team := range teams {
+		if err := a.deleteScheduledTeam(team); err != nil {
+			mlog.Error(fmt.Sprintf("Unable to delete scheduled team err=%v", err), mlog.String("team_id", team.Id))
+		}
+	}
+
+	return nil
+}
+
+func (a *App) deleteScheduledTeam(team *model.Team) *model.AppError {
+	// Team admins are looked up before the deletion removes their membership.
+	admins, err := a.getTeamAdmins(team.Id)
+	if err != nil {
+		return err
+	}
+
+	path, err := a.ExportTeam(team)
+	if err != nil {
+		// Leave the team alone so that the export is retried on the next run rather than losing its content.
+		return err
+	}
+
+	mlog.Info(fmt.Sprintf("Exported team %v to %v before deleting it", team.Id, path), mlog.String("team_id", team.Id))
+
+	if err := a.PermanentDeleteTeam(team); err != nil {
+		return err
+	}
+
+	a.sendTeamDeletionEmailsToUsers(team, admins, "deleted", "")
+
+	return nil
+}
+
+func (a *App) getTeamAdmins(teamId string) ([]*model.User, *model.AppError) {
+	userIds := []string{}
+
+	for offset := 0; ; offset += TEAM_EXPORT_BATCH_SIZE {
+		members, err := a.GetTeamMembers(teamId, offset, TEAM_EXPORT_BATCH_SIZE)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			if member.SchemeAdmin || model.IsInRole(member.Roles, model.TEAM_ADMIN_ROLE_ID) {
+				userIds = append(userIds, member.UserId)
+			}
+		}
+
+		if len(members) < TEAM_EXPORT_BATCH_SIZE {
+			break
+		}
+	}
+
+	if len(userIds) == 0 {
+		return []*model.User{}, nil
+	}
+
+	if result := <-a.Srv.Store.User().GetProfileByIds(userIds, true); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.User), nil
+	}
+}
+
+func (a *App) sendTeamDeletionEmails(team *model.Team, event string, requesterId string) {
+	admins, err := a.getTeamAdmins(team.Id)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to get team admins to notify about team deletion err=%v", err), mlog.String("team_id", team.Id))
+		return
+	}
+
+	username := ""
+	if requester, err := a.GetUser(requesterId); err == nil {
+		username = requester.Username
+	}
+
+	a.sendTeamDeletionEmailsToUsers(team, admins, event, username)
+}
+
+// sendTeamDeletionEmailsToUsers notifies the given users that the team was scheduled for deletion, that the
+// deletion was cancelled or that the team was deleted, depending on the event.
+func (a *App) sendTeamDeletionEmailsToUsers(team *model.Team, users []*model.User, event string, username string) {
+	if !a.Config().EmailSettings.SendEmailNotifications {
+		return
+	}
+
+	siteURL := a.GetSiteURL()
+	rawUrl, _ := url.Parse(siteURL)
+	deleteAt := time.Unix(0, team.ScheduledDeleteAt*int64(time.Millisecond))
+
+	for _, user := range users {
+		if user.DeleteAt != 0 {
+			continue
+		}
+
+		T := utils.GetUserTranslations(user.Locale)
+		props := map[string]interface{}{
+			"SiteName":        a.ClientConfig()["SiteName"],
+			"ServerURL":       rawUrl.Host,
+			"TeamDisplayName": team.DisplayName,
+			"Username":        username,
+			"Year":            deleteAt.Year(),
+			"Month":           T(deleteAt.Month().String()),
+			"Day":             deleteAt.Day(),
+		}
+
+		bodyPage := a.NewEmailTemplate("team_deletion_body", user.Locale)
+		bodyPage.Props["SiteURL"] = siteURL
+		bodyPage.Props["Title"] = T("api.templates.team_deletion_"+event+"_body.title", props)
+		bodyPage.Props["Info"] = T("api.templates.team_deletion_"+event+"_body.info", props)
+		bodyPage.Props["Button"] = T("api.templates.team_deletion_body.button", props)
+
+		if err := a.SendTeamTemplateMail(team.Id, user.Email, T("api.templates.team_deletion_"+event+"_subject", props), bodyPage); err != nil {
+			mlog.Error(fmt.Sprintf("Unable to send team deletion email err=%v", err), mlog.String("user_id", user.Id))
+		}
+	}
+}
+
+// ExportTeam writes the team's channels, members and posts to the file store using the bulk import format, so that
+// the content can be imported again, and returns the path of the export.
+func (a *App) ExportTeam(team *model.Team) (string, *model.AppError) {
+	path := fmt.Sprintf("%v%v/%v.jsonl", TEAM_EXPORT_DIRECTORY, team.Id, model.GetMillis())
+
+	reader, writer := io.Pipe()
+	errs := make(chan *model.AppError, 1)
+
+	go func() {
+		err := a.writeTeamExport(team, json.NewEncoder(writer))
+		if err != nil {
+			writer.CloseWithError(err)
+		} else {
+			writer.Close()
+		}
+		errs <- err
+	}()
+
+	_, writeErr := a.WriteFile(reader, path)
+	reader.Close()
+
+	if err := <-errs; err != nil {
+		return "", err
+	}
+
+	if writeErr != nil {
+		return "", writeErr
+	}
+
+	return path, nil
+}
+
+func (a *App) writeTeamExport(team *model.Team, encoder *json.Encoder) *model.AppError {
+	encode := func(line *LineImportData) *model.AppError {
+		if err := encoder.Encode(line); err != nil {
+			return model.NewAppError("ExportTeam", "app.team.export.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		return nil
+	}
+
+	version := 1
+	if err := encode(&LineImportData{Type: "version", Version: &version}); err != nil {
+		return err
+	}
+
+	if err := encode(buildTeamExportLine(team)); err != nil {
+		return err
+	}
+
+	var channels []*model.Channel
+	if result := <-a.Srv.Store.Channel().GetTeamChannels(team.Id); result.Err != nil {
+		if result.Err.Id != "store.sql_channel.get_channels.not_found.app_error" {
+			return result.Err
+		}
+	} else {
+		channels = *result.Data.(*model.ChannelList)
+	}
+
+	for _, channel := range channels {
+		if err := encode(buildChannelExportLine(team, channel)); err != nil {
+			return err
+		}
+	}
+
+	usernames := make(map[string]string)
+
+	for offset := 0; ; offset += TEAM_EXPORT_BATCH_SIZE {
+		members, err := a.GetTeamMembers(team.Id, offset, TEAM_EXPORT_BATCH_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			user, err := a.GetUser(member.UserId)
+			if err != nil {
+				mlog.Warn("Unable to find team member for export", mlog.String("user_id", member.UserId))
+				continue
+			}
+			usernames[user.Id] = user.Username
+
+			if err := encode(buildUserExportLine(team, user, member)); err != nil {
+				return err
+			}
+		}
+
+		if len(members) < TEAM_EXPORT_BATCH_SIZE {
+			break
+		}
+	}
+
+	for _, channel := range channels {
+		if err := a.writeChannelPostsExport(team, channel, usernames, encode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *App) writeChannelPostsExport(team *model.Team, channel *model.Channel, usernames map[string]string, encode func(*LineImportData) *model.AppError) *model.AppError {
+	for offset := 0; ; offset += TEAM_EXPORT_BATCH_SIZE {
+		var roots []*model.Post
+		if result := <-a.Srv.Store.Post().GetRootPostsForExport(channel.Id, offset, TEAM_EXPORT_BATCH_SIZE); result.Err != nil {
+			return result.Err
+		} else {
+			roots = result.Data.([]*model.Post)
+		}
+
+		for _, root := range roots {
+			var thread *model.PostList
+			if result := <-a.Srv.Store.Post().Get(root.Id); result.Err != nil {
+				return result.Err
+			} else {
+				thread = result.Data.(*model.PostList)
+			}
+
+			for _, post := range thread.Posts {
+				if _, ok := usernames[post.UserId]; !ok {
+					// Posts by users who have since left the team still need their author.
+					if user, err := a.GetUser(post.UserId); err == nil {
+						usernames[user.Id] = user.Username
+					} else {
+						usernames[post.UserId] = ""
+					}
+				}
+			}
+
+			if line := buildPostExportLine(team, channel, root, thread, usernames); line != nil {
+				if err := encode(line); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(roots) < TEAM_EXPORT_BATCH_SIZE {
+			break
+		}
+	}
+
+	return nil
+}
+
+func buildTeamExportLine(team *model.Team) *LineImportData {
+	allowOpenInvite := team.AllowOpenInvite
+
+	return &LineImportData{
+		Type: "team",
+		Team: &TeamImportData{
+			Name:            &team.Name,
+			DisplayName:     &team.DisplayName,
+			Type:            &team.Type,
+			Description:     &team.Description,
+			AllowOpenInvite: &allowOpenInvite,
+		},
+	}
+}
+
+func buildChannelExportLine(team *model.Team, channel *model.Channel) *LineImportData {
+	return &LineImportData{
+		Type: "channel",
+		Channel: &ChannelImportData{
+			Team:        &team.Name,
+			Name:        &channel.Name,
+			DisplayName: &channel.DisplayName,
+			Type:        &channel.Type,
+			Header:      &channel.Header,
+			Purpose:     &channel.Purpose,
+		},
+	}
+}
+
+func buildUserExportLine(team *model.Team, user *model.User, member *model.TeamMember) *LineImportData {
+	roles := member.Roles
+
+	return &LineImportData{
+		Type: "user",
+		User: &UserImportData{
+			Username:  &user.Username,
+			Email:     &user.Email,
+			Nickname:  &user.Nickname,
+			FirstName: &user.FirstName,
+			LastName:  &user.LastName,
+			Position:  &user.Position,
+			Teams: &[]UserTeamImportData{
+				{
+					Name:  &team.Name,
+					Roles: &roles,
+				},
+			},
+		},
+	}
+}
+
+// buildPostExportLine returns the line for a root post with its replies nested under it, skipping any post whose
+// author can't be found since the import format identifies authors by username.
+func buildPostExportLine(team *model.Team, channel *model.Channel, root *model.Post, thread *model.PostList, usernames map[string]string) *LineImportData {
+	rootUsername := usernames[root.UserId]
+	if rootUsername == "" {
+		return nil
+	}
+
+	replies := []*model.Post{}
+	for _, post := range thread.Posts {
+		if post.Id != root.Id && post.RootId == root.Id {
+			replies = append(replies, post)
+		}
+	}
+
+	sort.Slice(replies, func(i, j int) bool {
+		return replies[i].CreateAt < replies[j].CreateAt
+	})
+
+	replyLines := []ReplyImportData{}
+	for _, reply := range replies {
+		username := usernames[reply.UserId]
+		if username == "" {
+			continue
+		}
+
+		replyLines = append(replyLines, ReplyImportData{
+			User:     model.NewString(username),
+			Message:  model.NewString(reply.Message),
+			CreateAt: model.NewInt64(reply.CreateAt),
+		})
+	}
+
+	line := &LineImportData{
+		Type: "post",
+		Post: &PostImportData{
+			Team:     &team.Name,
+			Channel:  &channel.Name,
+			User:     model.NewString(rootUsername),
+			Message:  model.NewString(root.Message),
+			CreateAt: model.NewInt64(root.CreateAt),
+		},
+	}
+
+	if len(replyLines) > 0 {
+		line.Post.Replies = &replyLines
+	}
+
+	return line
+}
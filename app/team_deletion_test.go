@@ -0,0 +1,140 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestBuildPostExportLine(t *testing.T) {
+	team := &model.Team{Name: "team"}
+	channel := &model.Channel{Name: "channel"}
+
+	root := &model.Post{Id: model.NewId(), UserId: "u1", Message: "root", CreateAt: 1000}
+	reply1 := &model.Post{Id: model.NewId(), UserId: "u2", RootId: root.Id, Message: "first", CreateAt: 2000}
+	reply2 := &model.Post{Id: model.NewId(), UserId: "u1", RootId: root.Id, Message: "second", CreateAt: 3000}
+	orphan := &model.Post{Id: model.NewId(), UserId: "gone", RootId: root.Id, Message: "orphan", CreateAt: 2500}
+
+	thread := model.NewPostList()
+	thread.AddPost(root)
+	thread.AddPost(reply2)
+	thread.AddPost(orphan)
+	thread.AddPost(reply1)
+
+	usernames := map[string]string{"u1": "alice", "u2": "bob", "gone": ""}
+
+	line := buildPostExportLine(team, channel, root, thread, usernames)
+	require.NotNil(t, line)
+	assert.Equal(t, "post", line.Type)
+	assert.Equal(t, "team", *line.Post.Team)
+	assert.Equal(t, "channel", *line.Post.Channel)
+	assert.Equal(t, "alice", *line.Post.User)
+	assert.Equal(t, "root", *line.Post.Message)
+
+	require.NotNil(t, line.Post.Replies)
+	replies := *line.Post.Replies
+	require.Len(t, replies, 2, "replies without a known author should be skipped")
+	assert.Equal(t, "bob", *replies[0].User)
+	assert.Equal(t, "first", *replies[0].Message)
+	assert.Equal(t, "alice", *replies[1].User)
+	assert.Equal(t, "second", *replies[1].Message)
+
+	root.UserId = "gone"
+	assert.Nil(t, buildPostExportLine(team, channel, root, thread, usernames), "posts without a known author should be skipped")
+}
+
+func TestExportTeam(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.CreatePost(th.BasicChannel)
+
+	path, err := th.App.ExportTeam(th.BasicTeam)
+	require.Nil(t, err)
+	defer th.App.RemoveFile(path)
+
+	data, err := th.App.ReadFile(path)
+	require.Nil(t, err)
+
+	types := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var line LineImportData
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+		types[line.Type]++
+	}
+
+	assert.Equal(t, 1, types["version"])
+	assert.Equal(t, 1, types["team"])
+	assert.True(t, types["channel"] > 0)
+	assert.True(t, types["user"] > 0)
+	assert.True(t, types["post"] > 0)
+}
+
+func TestDeleteScheduledTeams(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	team := th.CreateTeam()
+	th.LinkUserToTeam(th.BasicUser, team)
+
+	team, err := th.App.ScheduleTeamDeletion(team.Id, th.BasicUser.Id)
+	require.Nil(t, err)
+
+	require.Nil(t, th.App.DeleteScheduledTeams(time.Now()))
+	_, err = th.App.GetTeam(team.Id)
+	require.Nil(t, err, "the team should be kept until the grace period ends")
+
+	deleteAt := time.Unix(0, (team.ScheduledDeleteAt+1)*int64(time.Millisecond))
+	require.Nil(t, th.App.DeleteScheduledTeams(deleteAt))
+	_, err = th.App.GetTeam(team.Id)
+	assert.NotNil(t, err, "the team should be deleted once the grace period ends")
+
+	if backend, err := th.App.FileBackend(); err == nil {
+		backend.RemoveDirectory(TEAM_EXPORT_DIRECTORY + team.Id)
+	}
+}
+
+func TestCancelTeamDeletion(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	t.Run("active team is restored", func(t *testing.T) {
+		team := th.CreateTeam()
+
+		_, err := th.App.ScheduleTeamDeletion(team.Id, th.BasicUser.Id)
+		require.Nil(t, err)
+
+		team, err = th.App.CancelTeamDeletion(team.Id, th.BasicUser.Id)
+		require.Nil(t, err)
+		assert.Equal(t, int64(0), team.DeleteAt)
+		assert.Equal(t, int64(0), team.ScheduledDeleteAt)
+	})
+
+	t.Run("archived team stays archived", func(t *testing.T) {
+		team := th.CreateTeam()
+		require.Nil(t, th.App.SoftDeleteTeam(team.Id))
+
+		archived, err := th.App.GetTeam(team.Id)
+		require.Nil(t, err)
+		require.NotEqual(t, int64(0), archived.DeleteAt)
+
+		_, err = th.App.ScheduleTeamDeletion(team.Id, th.BasicUser.Id)
+		require.Nil(t, err)
+
+		team, err = th.App.CancelTeamDeletion(team.Id, th.BasicUser.Id)
+		require.Nil(t, err)
+		assert.Equal(t, archived.DeleteAt, team.DeleteAt)
+		assert.Equal(t, int64(0), team.ScheduledDeleteAt)
+	})
+}
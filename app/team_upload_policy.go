@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// UpdateTeamUploadPolicy replaces the policy that restricts the files that can be uploaded to a team. An empty policy
+// removes the restrictions.
+func (a *App) UpdateTeamUploadPolicy(teamId string, policy *model.TeamUploadPolicy) (*model.Team, *model.AppError) {
+	team, err := a.GetTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.PreSave()
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if policy.IsEmpty() {
+		team.UploadPolicy = nil
+	} else {
+		team.UploadPolicy = policy
+	}
+
+	team, err = a.updateTeamUnsanitized(team)
+	if err != nil {
+		return nil, err
+	}
+
+	a.sendTeamEvent(team, model.WEBSOCKET_EVENT_UPDATE_TEAM)
+
+	return team, nil
+}
+
+// CheckTeamUploadPolicy returns an error if the upload policy of the channel's team doesn't allow the file. Files sent
+// in direct and group messages aren't restricted since those channels aren't part of a team.
+func (a *App) CheckTeamUploadPolicy(channelId string, filename string, data []byte) *model.AppError {
+	channel, err := a.GetChannel(channelId)
+	if err != nil {
+		// Files for channels that don't exist can't be posted anyway
+		if err.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if channel.TeamId == "" {
+		return nil
+	}
+
+	team, err := a.GetTeam(channel.TeamId)
+	if err != nil {
+		return err
+	}
+
+	if team.UploadPolicy == nil {
+		return nil
+	}
+
+	return team.UploadPolicy.CheckFile(filename, data)
+}
+
+// IsUploadPolicyError returns true if an error was returned because an upload policy rejected a file.
+func IsUploadPolicyError(err *model.AppError) bool {
+	return strings.HasPrefix(err.Id, "model.team_upload_policy.check_file.")
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// autoFollowsThreads returns whether a user should start following the threads that they take part in. Users who
+// don't want to hear about replies at all are left to follow threads themselves.
+func (a *App) autoFollowsThreads(user *model.User) bool {
+	return *a.Config().ServiceSettings.ThreadAutoFollow && user.NotifyProps[model.COMMENTS_NOTIFY_PROP] != model.COMMENTS_NOTIFY_NEVER
+}
+
+// updateThreadForReply bumps a thread for its members when a reply is posted to it. The replier starts following
+// the thread again even if they'd stopped, while the author of the root post only starts following it the first
+// time that someone replies.
+func (a *App) updateThreadForReply(reply *model.Post, replier *model.User, parentPostList *model.PostList) *model.AppError {
+	if result := <-a.Srv.Store.ThreadMembership().UpdateLastUpdated(reply.RootId, reply.CreateAt); result.Err != nil {
+		return result.Err
+	}
+
+	if a.autoFollowsThreads(replier) {
+		membership := &model.ThreadMembership{
+			PostId:      reply.RootId,
+			UserId:      replier.Id,
+			Following:   true,
+			LastViewed:  reply.CreateAt,
+			LastUpdated: reply.CreateAt,
+		}
+
+		if result := <-a.Srv.Store.ThreadMembership().Save(membership); result.Err != nil {
+			return result.Err
+		}
+	}
+
+	rootPost := parentPostList.Posts[reply.RootId]
+	if rootPost == nil || rootPost.UserId == replier.Id {
+		return nil
+	}
+
+	if result := <-a.Srv.Store.ThreadMembership().Get(rootPost.UserId, rootPost.Id); result.Err == nil {
+		return nil
+	} else if result.Err.StatusCode != http.StatusNotFound {
+		return result.Err
+	}
+
+	var rootAuthor *model.User
+	if result := <-a.Srv.Store.User().Get(rootPost.UserId); result.Err != nil {
+		return result.Err
+	} else {
+		rootAuthor = result.Data.(*model.User)
+	}
+
+	if !a.autoFollowsThreads(rootAuthor) {
+		return nil
+	}
+
+	membership := &model.ThreadMembership{
+		PostId:      rootPost.Id,
+		UserId:      rootAuthor.Id,
+		Following:   true,
+		LastViewed:  rootPost.CreateAt,
+		LastUpdated: reply.CreateAt,
+	}
+
+	if result := <-a.Srv.Store.ThreadMembership().Save(membership); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// sendThreadUpdatedEvents tells everyone following a thread, other than the replier, how many of its replies they
+// haven't seen.
+func (a *App) sendThreadUpdatedEvents(reply *model.Post) {
+	var threads []*model.Thread
+	if result := <-a.Srv.Store.ThreadMembership().GetThreadsForPost(reply.RootId); result.Err != nil {
+		mlog.Warn(fmt.Sprintf("Failed to get the members of thread_id=%v to notify them of a reply, err=%v", reply.RootId, result.Err))
+		return
+	} else {
+		threads = result.Data.([]*model.Thread)
+	}
+
+	for _, thread := range threads {
+		if thread.Following && thread.UserId != reply.UserId {
+			a.sendThreadEvent(model.WEBSOCKET_EVENT_THREAD_UPDATED, thread)
+		}
+	}
+}
+
+func (a *App) sendThreadEvent(event string, thread *model.Thread) {
+	message := model.NewWebSocketEvent(event, "", "", thread.UserId, nil)
+	message.Add("thread", thread.ToJson())
+	a.Publish(message)
+}
+
+// getThreadRootPost returns the root post of a thread, making sure that the thread is identified by its root post
+// rather than one of its replies.
+func (a *App) getThreadRootPost(postId string) (*model.Post, *model.AppError) {
+	post, err := a.GetSinglePost(postId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(post.RootId) > 0 {
+		return nil, model.NewAppError("getThreadRootPost", "app.thread.not_root.app_error", nil, "post_id="+postId, http.StatusBadRequest)
+	}
+
+	return post, nil
+}
+
+// GetThreadForUser returns a thread as seen by one of its members.
+func (a *App) GetThreadForUser(userId, postId string) (*model.Thread, *model.AppError) {
+	var threads []*model.Thread
+	if result := <-a.Srv.Store.ThreadMembership().GetThreadsForPost(postId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		threads = result.Data.([]*model.Thread)
+	}
+
+	for _, thread := range threads {
+		if thread.UserId == userId {
+			return thread, nil
+		}
+	}
+
+	return nil, model.NewAppError("GetThreadForUser", "app.thread.not_member.app_error", nil, "user_id="+userId+", post_id="+postId, http.StatusNotFound)
+}
+
+// GetThreadsForUser returns a page of the threads that a user follows, most recently updated first.
+func (a *App) GetThreadsForUser(userId string, page, perPage int) ([]*model.Thread, *model.AppError) {
+	if result := <-a.Srv.Store.ThreadMembership().GetThreadsForUser(userId, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.Thread), nil
+	}
+}
+
+// updateThreadMembership changes a user's membership of a thread. Users who aren't members of the thread are only
+// made members if join is set.
+func (a *App) updateThreadMembership(userId, postId string, event string, join bool, update func(membership *model.ThreadMembership)) (*model.Thread, *model.AppError) {
+	rootPost, err := a.getThreadRootPost(postId)
+	if err != nil {
+		return nil, err
+	}
+
+	var membership *model.ThreadMembership
+	if result := <-a.Srv.Store.ThreadMembership().Get(userId, rootPost.Id); result.Err == nil {
+		membership = result.Data.(*model.ThreadMembership)
+	} else if result.Err.StatusCode == http.StatusNotFound && join {
+		membership = &model.ThreadMembership{PostId: rootPost.Id, UserId: userId, LastUpdated: rootPost.UpdateAt}
+	} else if result.Err.StatusCode == http.StatusNotFound {
+		return nil, model.NewAppError("updateThreadMembership", "app.thread.not_member.app_error", nil, "user_id="+userId+", post_id="+postId, http.StatusNotFound)
+	} else {
+		return nil, result.Err
+	}
+
+	update(membership)
+
+	if result := <-a.Srv.Store.ThreadMembership().Save(membership); result.Err != nil {
+		return nil, result.Err
+	}
+
+	thread, err := a.GetThreadForUser(userId, rootPost.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	a.sendThreadEvent(event, thread)
+
+	return thread, nil
+}
+
+func (a *App) SetThreadFollowing(userId, postId string, following bool) (*model.Thread, *model.AppError) {
+	return a.updateThreadMembership(userId, postId, model.WEBSOCKET_EVENT_THREAD_FOLLOW_CHANGED, true, func(membership *model.ThreadMembership) {
+		membership.Following = following
+	})
+}
+
+// MarkThreadAsRead records that a member of a thread has seen every reply that's been posted to it so far. Reading a
+// thread doesn't make a user a member of it, since members who aren't following a thread aren't notified of replies.
+func (a *App) MarkThreadAsRead(userId, postId string) (*model.Thread, *model.AppError) {
+	return a.updateThreadMembership(userId, postId, model.WEBSOCKET_EVENT_THREAD_READ_CHANGED, false, func(membership *model.ThreadMembership) {
+		membership.LastViewed = model.GetMillis()
+	})
+}
@@ -4,6 +4,8 @@
 package app
 
 import (
+	"net/http"
+
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/utils"
 )
@@ -26,3 +28,63 @@ func (a *App) LoadTimezones() {
 
 	a.timezones.Store(timezoneCfg)
 }
+
+func (a *App) checkTimezoneEnabled() *model.AppError {
+	if !*a.Config().DisplaySettings.EnableTimezone {
+		return model.NewAppError("checkTimezoneEnabled", "app.timezone.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+func (a *App) GetUserTimezone(userId string) (*model.UserTimezone, *model.AppError) {
+	if err := a.checkTimezoneEnabled(); err != nil {
+		return nil, err
+	}
+
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.UserTimezoneFromMap(user.Timezone), nil
+}
+
+// UpdateUserTimezone changes a user's timezone and lets their other sessions know about it. Everyone else finds out
+// through the usual user updated event.
+func (a *App) UpdateUserTimezone(userId string, timezone *model.UserTimezone) (*model.UserTimezone, *model.AppError) {
+	if err := a.checkTimezoneEnabled(); err != nil {
+		return nil, err
+	}
+
+	supported := a.Timezones()
+	if len(supported) == 0 {
+		supported = model.DefaultSupportedTimezones
+	}
+
+	if err := timezone.IsValid(supported); err != nil {
+		return nil, err
+	}
+
+	user, err := a.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Timezone = timezone.ToMap()
+
+	updatedUser, err := a.UpdateUser(user, false)
+	if err != nil {
+		return nil, err
+	}
+
+	a.sendUpdatedUserEvent(*updatedUser)
+
+	updatedTimezone := model.UserTimezoneFromMap(updatedUser.Timezone)
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TIMEZONE_CHANGED, "", "", userId, nil)
+	message.Add("timezone", updatedTimezone.ToJson())
+	a.Publish(message)
+
+	return updatedTimezone, nil
+}
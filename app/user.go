@@ -362,6 +362,19 @@ func (a *App) GetUserByUsername(username string) (*model.User, *model.AppError)
 	}
 }
 
+func (a *App) GetUserByRemoteId(service, externalId string) (*model.User, *model.AppError) {
+	remoteId := model.NewRemoteId(service, externalId)
+	if !model.IsValidRemoteId(&remoteId) {
+		return nil, model.NewAppError("GetUserByRemoteId", "api.user.get_user_by_remote_id.invalid.app_error", nil, "remote_id="+remoteId, http.StatusBadRequest)
+	}
+
+	if result := <-a.Srv.Store.User().GetByRemoteId(remoteId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.User), nil
+	}
+}
+
 func (a *App) GetUserByEmail(email string) (*model.User, *model.AppError) {
 
 	if result := <-a.Srv.Store.User().GetByEmail(email); result.Err != nil && result.Err.Id == "store.sql_user.missing_account.const" {
@@ -417,7 +430,7 @@ func (a *App) GetUsersPage(page int, perPage int, asAdmin bool) ([]*model.User,
 }
 
 func (a *App) GetUsersEtag() string {
-	return fmt.Sprintf("%v.%v.%v", (<-a.Srv.Store.User().GetEtagForAllProfiles()).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress)
+	return fmt.Sprintf("%v.%v.%v.%v", (<-a.Srv.Store.User().GetEtagForAllProfiles()).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress, a.Config().PrivacySettings.GetUserFieldVisibilityEtag())
 }
 
 func (a *App) GetUsersInTeam(teamId string, offset int, limit int) ([]*model.User, *model.AppError) {
@@ -471,11 +484,11 @@ func (a *App) GetUsersNotInTeamPage(teamId string, page int, perPage int, asAdmi
 }
 
 func (a *App) GetUsersInTeamEtag(teamId string) string {
-	return fmt.Sprintf("%v.%v.%v", (<-a.Srv.Store.User().GetEtagForProfiles(teamId)).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress)
+	return fmt.Sprintf("%v.%v.%v.%v", (<-a.Srv.Store.User().GetEtagForProfiles(teamId)).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress, a.Config().PrivacySettings.GetUserFieldVisibilityEtag())
 }
 
 func (a *App) GetUsersNotInTeamEtag(teamId string) string {
-	return fmt.Sprintf("%v.%v.%v", (<-a.Srv.Store.User().GetEtagForProfilesNotInTeam(teamId)).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress)
+	return fmt.Sprintf("%v.%v.%v.%v", (<-a.Srv.Store.User().GetEtagForProfilesNotInTeam(teamId)).Data.(string), a.Config().PrivacySettings.ShowFullName, a.Config().PrivacySettings.ShowEmailAddress, a.Config().PrivacySettings.GetUserFieldVisibilityEtag())
 }
 
 func (a *App) GetUsersInChannel(channelId string, offset int, limit int) ([]*model.User, *model.AppError) {
@@ -893,6 +906,15 @@ func (a *App) UpdateActive(user *model.User, active bool) (*model.User, *model.A
 
 		if !active {
 			a.SetStatusOffline(ruser.Id, false)
+			a.PostSystemEvent(newUserDeactivatedSystemEvent(ruser))
+		}
+
+		if ruser.IsBot {
+			if err := a.updateBotDeleteAt(ruser.Id, ruser.DeleteAt); err != nil {
+				return nil, err
+			}
+		} else if !active && *a.Config().ServiceSettings.DisableBotsWhenOwnerIsDeactivated {
+			a.disableUserBots(ruser.Id)
 		}
 
 		teamsForUser, err := a.GetTeamsForUser(user.Id)
@@ -920,8 +942,9 @@ func (a *App) UpdateActive(user *model.User, active bool) (*model.User, *model.A
 func (a *App) SanitizeProfile(user *model.User, asAdmin bool) {
 	options := a.Config().GetSanitizeOptions()
 	if asAdmin {
-		options["email"] = true
-		options["fullname"] = true
+		for _, field := range model.MINIMIZABLE_USER_FIELDS {
+			options[field] = true
+		}
 		options["authservice"] = true
 	}
 	user.SanitizeProfile(options)
@@ -1225,6 +1248,8 @@ func (a *App) UpdateUserRoles(userId string, newRoles string, sendWebSocketEvent
 		return nil, err
 	}
 
+	wasSystemAdmin := user.IsInRole(model.SYSTEM_ADMIN_ROLE_ID)
+
 	user.Roles = newRoles
 	uchan := a.Srv.Store.User().Update(user, true)
 	schan := a.Srv.Store.Session().UpdateRoles(user.Id, newRoles)
@@ -1243,6 +1268,10 @@ func (a *App) UpdateUserRoles(userId string, newRoles string, sendWebSocketEvent
 
 	a.ClearSessionCacheForUser(user.Id)
 
+	if !wasSystemAdmin && ruser.IsInRole(model.SYSTEM_ADMIN_ROLE_ID) {
+		a.PostSystemEvent(newSecuritySystemEvent(utils.T("app.system_event.system_admin_granted.title"), ruser))
+	}
+
 	if sendWebSocketEvent {
 		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_USER_ROLE_UPDATED, "", "", user.Id, nil)
 		message.Add("user_id", user.Id)
@@ -1342,6 +1371,12 @@ func (a *App) PermanentDeleteUser(user *model.User) *model.AppError {
 		return result.Err
 	}
 
+	if user.IsBot {
+		if result := <-a.Srv.Store.Bot().PermanentDelete(user.Id); result.Err != nil {
+			return result.Err
+		}
+	}
+
 	if result := <-a.Srv.Store.Audit().PermanentDeleteByUser(user.Id); result.Err != nil {
 		return result.Err
 	}
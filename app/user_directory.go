@@ -0,0 +1,65 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (a *App) GetUserDirectory(options *model.UserDirectoryOptions, offset, limit int) ([]*model.User, *model.AppError) {
+	if result := <-a.Srv.Store.User().GetDirectory(options, offset, limit); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.User), nil
+	}
+}
+
+func (a *App) GetUserDirectoryPage(options *model.UserDirectoryOptions, page, perPage int) ([]*model.User, *model.AppError) {
+	users, err := a.GetUserDirectory(options, page*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.sanitizeProfiles(users, true), nil
+}
+
+// ExportUserDirectoryCsv writes every user matching the options to w as CSV, fetching and flushing
+// the rows in batches so that large directories are never held in memory at once.
+func (a *App) ExportUserDirectoryCsv(options *model.UserDirectoryOptions, w io.Writer) *model.AppError {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(model.UserDirectoryCsvHeader); err != nil {
+		return model.NewAppError("ExportUserDirectoryCsv", "app.user_directory.export_csv.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for offset := 0; ; offset += model.USER_DIRECTORY_CSV_BATCH_SIZE {
+		users, appErr := a.GetUserDirectory(options, offset, model.USER_DIRECTORY_CSV_BATCH_SIZE)
+		if appErr != nil {
+			return appErr
+		}
+
+		for _, user := range a.sanitizeProfiles(users, true) {
+			if err := writer.Write(user.ToCsvRecord()); err != nil {
+				return model.NewAppError("ExportUserDirectoryCsv", "app.user_directory.export_csv.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return model.NewAppError("ExportUserDirectoryCsv", "app.user_directory.export_csv.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if len(users) < model.USER_DIRECTORY_CSV_BATCH_SIZE {
+			return nil
+		}
+	}
+}
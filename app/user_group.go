@@ -0,0 +1,101 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// checkUserGroupName makes sure that a group doesn't take the name of a user, since both are mentioned as @name.
+func (a *App) checkUserGroupName(name string) *model.AppError {
+	if result := <-a.Srv.Store.User().GetByUsername(strings.ToLower(name)); result.Err == nil {
+		return model.NewAppError("checkUserGroupName", "app.user_group.name_taken.app_error", nil, "name="+name, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (a *App) CreateUserGroup(group *model.UserGroup) (*model.UserGroup, *model.AppError) {
+	if err := a.checkUserGroupName(group.Name); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.UserGroup().Save(group); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.UserGroup), nil
+	}
+}
+
+func (a *App) GetUserGroup(groupId string) (*model.UserGroup, *model.AppError) {
+	if result := <-a.Srv.Store.UserGroup().Get(groupId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.UserGroup), nil
+	}
+}
+
+func (a *App) GetUserGroups(page, perPage int) ([]*model.UserGroup, *model.AppError) {
+	if result := <-a.Srv.Store.UserGroup().GetAll(page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.UserGroup), nil
+	}
+}
+
+func (a *App) PatchUserGroup(group *model.UserGroup, patch *model.UserGroupPatch) (*model.UserGroup, *model.AppError) {
+	oldName := group.Name
+	group.Patch(patch)
+
+	if strings.ToLower(group.Name) != oldName {
+		if err := a.checkUserGroupName(group.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if result := <-a.Srv.Store.UserGroup().Update(group); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.UserGroup), nil
+	}
+}
+
+func (a *App) DeleteUserGroup(groupId string) *model.AppError {
+	if result := <-a.Srv.Store.UserGroup().Delete(groupId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+func (a *App) AddUserGroupMember(groupId, userId string) (*model.UserGroupMember, *model.AppError) {
+	if _, err := a.GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	if result := <-a.Srv.Store.UserGroup().SaveMember(&model.UserGroupMember{GroupId: groupId, UserId: userId}); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.UserGroupMember), nil
+	}
+}
+
+func (a *App) RemoveUserGroupMember(groupId, userId string) *model.AppError {
+	if result := <-a.Srv.Store.UserGroup().DeleteMember(groupId, userId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+func (a *App) GetUserGroupMembers(groupId string, page, perPage int) ([]*model.UserGroupMember, *model.AppError) {
+	if result := <-a.Srv.Store.UserGroup().GetMembers(groupId, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.UserGroupMember), nil
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// The most typing events that are sent for a channel in each interval of TimeBetweenUserTypingUpdatesMilliseconds
+const USER_TYPING_MAX_EVENTS_PER_CHANNEL = 10
+
+// PublishUserTyping tells the other members of a channel that a user is typing in it. To keep typing from flooding
+// websockets on busy servers, the event is dropped if:
+//   - the channel has more members than TeamSettings.MaxNotificationsPerChannel,
+//   - the user was already reported as typing in the same channel and thread within the last
+//     ServiceSettings.TimeBetweenUserTypingUpdatesMilliseconds, or
+//   - USER_TYPING_MAX_EVENTS_PER_CHANNEL events have already been sent for the channel in that time.
+func (a *App) PublishUserTyping(userId, channelId, parentId string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableUserTypingMessages {
+		return nil
+	}
+
+	memberCount, err := a.GetChannelMemberCount(channelId)
+	if err != nil {
+		return err
+	}
+
+	if memberCount > *a.Config().TeamSettings.MaxNotificationsPerChannel {
+		return nil
+	}
+
+	interval := *a.Config().ServiceSettings.TimeBetweenUserTypingUpdatesMilliseconds
+	if !a.typingEvents.allow("user:"+channelId+":"+parentId+":"+userId, 1, interval) {
+		return nil
+	}
+
+	if !a.typingEvents.allow("channel:"+channelId, USER_TYPING_MAX_EVENTS_PER_CHANNEL, interval) {
+		return nil
+	}
+
+	omitUsers := make(map[string]bool, 1)
+	omitUsers[userId] = true
+
+	event := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", channelId, "", omitUsers)
+	event.Add("parent_id", parentId)
+	event.Add("user_id", userId)
+	a.Publish(event)
+
+	return nil
+}
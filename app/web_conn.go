@@ -16,32 +16,60 @@ import (
 )
 
 const (
-	SEND_QUEUE_SIZE           = 256
-	SEND_SLOW_WARN            = (SEND_QUEUE_SIZE * 50) / 100
-	SEND_DEADLOCK_WARN        = (SEND_QUEUE_SIZE * 95) / 100
-	WRITE_WAIT                = 30 * time.Second
-	PONG_WAIT                 = 100 * time.Second
-	PING_PERIOD               = (PONG_WAIT * 6) / 10
-	AUTH_TIMEOUT              = 5 * time.Second
-	WEBCONN_MEMBER_CACHE_TIME = 1000 * 60 * 30 // 30 minutes
+	SEND_QUEUE_SIZE            = 256 // used when ServiceSettings.WebSocketSendQueueSize isn't set
+	SEND_SLOW_WARN_PERCENT     = 50  // how full the send queue is when a connection is considered slow
+	SEND_DEADLOCK_WARN_PERCENT = 95
+	WRITE_WAIT                 = 30 * time.Second
+	PONG_WAIT                  = 100 * time.Second
+	PING_PERIOD                = (PONG_WAIT * 6) / 10
+	AUTH_TIMEOUT               = 5 * time.Second
+	WEBCONN_MEMBER_CACHE_TIME  = 1000 * 60 * 30 // 30 minutes
+)
+
+const (
+	// The number of events that are kept for each connection so that they can be sent again to clients that missed them
+	WEBCONN_REPLAY_BUFFER_SIZE = 128
+
+	// How long the events for a lost connection are kept while waiting for the client to reconnect
+	WEBCONN_RECONNECT_WINDOW = 2 * time.Minute
 )
 
 type WebConn struct {
 	sessionExpiresAt          int64 // This should stay at the top for 64-bit alignment of 64-bit words accessed atomically
 	App                       *App
+	Id                        string
 	WebSocket                 *websocket.Conn
 	Send                      chan model.WebSocketMessage
 	sessionToken              atomic.Value
 	session                   atomic.Value
 	LastUserActivityAt        int64
 	UserId                    string
+	ConnectAt                 int64
+	IpAddress                 string
+	UserAgent                 string
 	T                         goi18n.TranslateFunc
 	Locale                    string
 	AllChannelMembers         map[string]string
 	LastAllChannelMembersTime int64
-	Sequence                  int64
+	Sequence                  int64 // The sequence number of the next event, which is only used by the hub
+	eventFilter               atomic.Value
 	endWritePump              chan struct{}
 	pumpFinished              chan struct{}
+
+	// The events that were most recently sent on the connection, so that they can be sent again if the client
+	// reconnects after missing some of them. Like Sequence, these are only used by the hub.
+	replayBuffer       *webConnReplayBuffer
+	disconnectedAt     int64
+	resumeConnectionId string
+	resumeSequence     int64
+
+	// The state of the connection being resumed when it was open to another server, and whether this connection has
+	// been replaced by one that resumed it
+	handOver  *webConnHandOver
+	takenOver bool
+
+	// When the connection's send queue became slow, or zero if it isn't, which is only used by the hub
+	slowSince int64
 }
 
 func (a *App) NewWebConn(ws *websocket.Conn, session model.Session, t goi18n.TranslateFunc, locale string) *WebConn {
@@ -52,16 +80,24 @@ func (a *App) NewWebConn(ws *websocket.Conn, session model.Session, t goi18n.Tra
 		})
 	}
 
+	sendQueueSize := SEND_QUEUE_SIZE
+	if size := a.Config().ServiceSettings.WebSocketSendQueueSize; size != nil && *size > 0 {
+		sendQueueSize = *size
+	}
+
 	wc := &WebConn{
 		App:                a,
-		Send:               make(chan model.WebSocketMessage, SEND_QUEUE_SIZE),
+		Id:                 model.NewId(),
+		Send:               make(chan model.WebSocketMessage, sendQueueSize),
 		WebSocket:          ws,
 		LastUserActivityAt: model.GetMillis(),
 		UserId:             session.UserId,
+		ConnectAt:          model.GetMillis(),
 		T:                  t,
 		Locale:             locale,
 		endWritePump:       make(chan struct{}, 2),
 		pumpFinished:       make(chan struct{}, 1),
+		replayBuffer:       newWebConnReplayBuffer(WEBCONN_REPLAY_BUFFER_SIZE),
 	}
 
 	wc.SetSession(&session)
@@ -77,6 +113,56 @@ func (wc *WebConn) Close() {
 	<-wc.pumpFinished
 }
 
+// ResumeFrom asks for the connection to take the place of one that was lost, sending the events after the given
+// sequence number that the client missed. If those events aren't available any more, the connection starts over with
+// a new id, and the client is expected to fetch whatever it missed. It must be called before the connection is
+// registered with a hub.
+func (wc *WebConn) ResumeFrom(connectionId string, sequence int64) {
+	wc.resumeConnectionId = connectionId
+	wc.resumeSequence = sequence
+}
+
+// RejectWebSocketConnection closes a websocket before it's registered with a hub, telling the client why it was closed.
+// The close frame uses the "try again later" code so that clients know that they may reconnect once some of the other
+// connections close.
+func RejectWebSocketConnection(ws *websocket.Conn, reason string) {
+	closeWebSocket(ws, websocket.CloseTryAgainLater, reason)
+}
+
+// closeWebSocket sends a close frame with the given code and reason before closing a websocket.
+func closeWebSocket(ws *websocket.Conn, code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	if err := ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(WRITE_WAIT)); err != nil {
+		mlog.Debug(fmt.Sprintf("websocket.close: failed to send close message: %v", err.Error()))
+	}
+
+	ws.Close()
+}
+
+// Info describes the connection for administrators.
+func (wc *WebConn) Info() *model.WebSocketConnectionInfo {
+	info := &model.WebSocketConnectionInfo{
+		Id:             wc.Id,
+		ClusterId:      wc.App.GetClusterId(),
+		UserId:         wc.UserId,
+		UserAgent:      wc.UserAgent,
+		IpAddress:      wc.IpAddress,
+		ConnectAt:      wc.ConnectAt,
+		LastActivityAt: wc.LastUserActivityAt,
+		SendQueueDepth: len(wc.Send),
+	}
+
+	if session := wc.GetSession(); session != nil {
+		info.SessionId = session.Id
+		info.DeviceId = session.DeviceId
+		info.Platform = session.Props[model.SESSION_PROP_PLATFORM]
+		info.Os = session.Props[model.SESSION_PROP_OS]
+		info.Browser = session.Props[model.SESSION_PROP_BROWSER]
+	}
+
+	return info
+}
+
 func (c *WebConn) GetSessionExpiresAt() int64 {
 	return atomic.LoadInt64(&c.sessionExpiresAt)
 }
@@ -105,6 +191,20 @@ func (c *WebConn) SetSession(v *model.Session) {
 	c.session.Store(v)
 }
 
+// GetEventFilter returns the filter that the connection asked for, or nil if it gets every event.
+func (c *WebConn) GetEventFilter() *model.WebSocketEventFilter {
+	filter, _ := c.eventFilter.Load().(*model.WebSocketEventFilter)
+	return filter
+}
+
+func (c *WebConn) SetEventFilter(v *model.WebSocketEventFilter) {
+	if v != nil && v.IsEmpty() {
+		v = nil
+	}
+
+	c.eventFilter.Store(v)
+}
+
 func (c *WebConn) Pump() {
 	ch := make(chan struct{}, 1)
 	go func() {
@@ -172,55 +272,34 @@ func (c *WebConn) writePump() {
 
 			evt, evtOk := msg.(*model.WebSocketEvent)
 
-			skipSend := false
-			if len(c.Send) >= SEND_SLOW_WARN {
-				// When the pump starts to get slow we'll drop non-critical messages
-				if msg.EventType() == model.WEBSOCKET_EVENT_TYPING ||
-					msg.EventType() == model.WEBSOCKET_EVENT_STATUS_CHANGE ||
-					msg.EventType() == model.WEBSOCKET_EVENT_CHANNEL_VIEWED {
-					mlog.Info(fmt.Sprintf("websocket.slow: dropping message userId=%v type=%v channelId=%v", c.UserId, msg.EventType(), evt.Broadcast.ChannelId))
-					skipSend = true
-				}
-			}
+			// Events were given their sequence numbers by the hub, which has already shed any low priority events
+			// that a slow connection can't keep up with, so every queued event is sent to keep the sequence whole
+			msgBytes := []byte(msg.ToJson())
 
-			if !skipSend {
-				var msgBytes []byte
+			if c.isSendQueueAbove(SEND_DEADLOCK_WARN_PERCENT) {
 				if evtOk {
-					cpyEvt := &model.WebSocketEvent{}
-					*cpyEvt = *evt
-					cpyEvt.Sequence = c.Sequence
-					msgBytes = []byte(cpyEvt.ToJson())
-					c.Sequence++
+					mlog.Error(fmt.Sprintf("websocket.full: message userId=%v type=%v channelId=%v size=%v", c.UserId, msg.EventType(), evt.Broadcast.ChannelId, len(msg.ToJson())))
 				} else {
-					msgBytes = []byte(msg.ToJson())
-				}
-
-				if len(c.Send) >= SEND_DEADLOCK_WARN {
-					if evtOk {
-						mlog.Error(fmt.Sprintf("websocket.full: message userId=%v type=%v channelId=%v size=%v", c.UserId, msg.EventType(), evt.Broadcast.ChannelId, len(msg.ToJson())))
-					} else {
-						mlog.Error(fmt.Sprintf("websocket.full: message userId=%v type=%v size=%v", c.UserId, msg.EventType(), len(msg.ToJson())))
-					}
+					mlog.Error(fmt.Sprintf("websocket.full: message userId=%v type=%v size=%v", c.UserId, msg.EventType(), len(msg.ToJson())))
 				}
+			}
 
-				c.WebSocket.SetWriteDeadline(time.Now().Add(WRITE_WAIT))
-				if err := c.WebSocket.WriteMessage(websocket.TextMessage, msgBytes); err != nil {
-					// browsers will appear as CloseNoStatusReceived
-					if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
-						mlog.Debug(fmt.Sprintf("websocket.send: client side closed socket userId=%v", c.UserId))
-					} else {
-						mlog.Debug(fmt.Sprintf("websocket.send: closing websocket for userId=%v, error=%v", c.UserId, err.Error()))
-					}
-
-					return
+			c.WebSocket.SetWriteDeadline(time.Now().Add(WRITE_WAIT))
+			if err := c.WebSocket.WriteMessage(websocket.TextMessage, msgBytes); err != nil {
+				// browsers will appear as CloseNoStatusReceived
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+					mlog.Debug(fmt.Sprintf("websocket.send: client side closed socket userId=%v", c.UserId))
+				} else {
+					mlog.Debug(fmt.Sprintf("websocket.send: closing websocket for userId=%v, error=%v", c.UserId, err.Error()))
 				}
 
-				if c.App.Metrics != nil {
-					c.App.Go(func() {
-						c.App.Metrics.IncrementWebSocketBroadcast(msg.EventType())
-					})
-				}
+				return
+			}
 
+			if c.App.Metrics != nil {
+				c.App.Go(func() {
+					c.App.Metrics.IncrementWebSocketBroadcast(msg.EventType())
+				})
 			}
 		case <-ticker.C:
 			c.WebSocket.SetWriteDeadline(time.Now().Add(WRITE_WAIT))
@@ -276,10 +355,71 @@ func (webCon *WebConn) IsAuthenticated() bool {
 	return true
 }
 
-func (webCon *WebConn) SendHello() {
+func (webCon *WebConn) helloEvent() *model.WebSocketEvent {
 	msg := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_HELLO, "", "", webCon.UserId, nil)
 	msg.Add("server_version", fmt.Sprintf("%v.%v.%v.%v", model.CurrentVersion, model.BuildNumber, webCon.App.ClientConfigHash(), webCon.App.License() != nil))
-	webCon.Send <- msg
+	msg.Add("connection_id", webCon.Id)
+	return msg
+}
+
+// queueEvent gives a copy of an event the connection's next sequence number and keeps it so that it can be replayed.
+// The copy is also queued to be sent unless the client has disconnected. It returns false without doing either if
+// the queue is full. It must only be called by the hub.
+func (webCon *WebConn) queueEvent(msg *model.WebSocketEvent) bool {
+	evt := &model.WebSocketEvent{}
+	*evt = *msg
+	evt.Sequence = webCon.Sequence
+
+	if webCon.disconnectedAt == 0 {
+		select {
+		case webCon.Send <- evt:
+		default:
+			return false
+		}
+
+		if !webCon.isSendQueueAbove(SEND_SLOW_WARN_PERCENT) {
+			webCon.slowSince = 0
+		} else if webCon.slowSince == 0 {
+			webCon.slowSince = model.GetMillis()
+		}
+	}
+
+	webCon.replayBuffer.Add(evt)
+	webCon.Sequence++
+
+	return true
+}
+
+// isSendQueueAbove returns true if the connection's send queue is at least the given percentage full.
+func (webCon *WebConn) isSendQueueAbove(percent int) bool {
+	return len(webCon.Send)*100 >= cap(webCon.Send)*percent
+}
+
+// isStuck returns true if the connection's send queue has stayed slow for at least the given number of seconds, so
+// the client isn't keeping up with its events. It must only be called by the hub.
+func (webCon *WebConn) isStuck(timeoutSeconds int) bool {
+	return webCon.slowSince != 0 && model.GetMillis()-webCon.slowSince >= int64(timeoutSeconds)*1000
+}
+
+// missedEvents returns the events that were sent after the given sequence number, or false if some of them are no
+// longer available. It must only be called by the hub.
+func (webCon *WebConn) missedEvents(sequence int64) ([]*model.WebSocketEvent, bool) {
+	return missedWebSocketEvents(webCon.replayBuffer.Events(), webCon.Sequence, sequence)
+}
+
+// missedWebSocketEvents returns the events that were sent after the given sequence number from those that were most
+// recently sent on a connection, where next is the sequence number of the connection's next event.
+func missedWebSocketEvents(events []*model.WebSocketEvent, next int64, sequence int64) ([]*model.WebSocketEvent, bool) {
+	if sequence >= next {
+		return nil, false
+	}
+
+	first := next - int64(len(events))
+	if sequence+1 < first {
+		return nil, false
+	}
+
+	return events[sequence+1-first:], true
 }
 
 func (webCon *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
@@ -288,6 +428,11 @@ func (webCon *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		return false
 	}
 
+	// Check the connection's filter first since it's cheaper than the checks that need the session or channel members
+	if filter := webCon.GetEventFilter(); filter != nil && !filter.Matches(msg) {
+		return false
+	}
+
 	// If the event contains sanitized data, only send to users that don't have permission to
 	// see sensitive data. Prevents admin clients from receiving events with bad data
 	var hasReadPrivateDataPermission *bool
@@ -326,7 +471,8 @@ func (webCon *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		}
 	}
 
-	// Only report events to users who are in the channel for the event
+	// Only report events to users who are in the channel for the event. Archived channels still count so that their
+	// members are told when they are archived.
 	if len(msg.Broadcast.ChannelId) > 0 {
 		if model.GetMillis()-webCon.LastAllChannelMembersTime > WEBCONN_MEMBER_CACHE_TIME {
 			webCon.AllChannelMembers = nil
@@ -334,7 +480,7 @@ func (webCon *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		}
 
 		if webCon.AllChannelMembers == nil {
-			if result := <-webCon.App.Srv.Store.Channel().GetAllChannelMembersForUser(webCon.UserId, true, false); result.Err != nil {
+			if result := <-webCon.App.Srv.Store.Channel().GetAllChannelMembersForUser(webCon.UserId, true, true); result.Err != nil {
 				mlog.Error("webhub.shouldSendEvent: " + result.Err.Error())
 				return false
 			} else {
@@ -0,0 +1,311 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	// How often each server tells the others about all of the websocket connections that are open to it
+	WEBCONN_REGISTRY_SYNC_INTERVAL = time.Minute
+
+	// How long the connections of a server that hasn't been heard from are kept
+	WEBCONN_REGISTRY_NODE_EXPIRY = 3 * WEBCONN_REGISTRY_SYNC_INTERVAL
+
+	// How long a server waits for another one to hand over a connection that a client is resuming
+	WEBCONN_HAND_OVER_TIMEOUT = 2 * time.Second
+)
+
+// webConnRegistryEntry describes a user's connections to a server. Resumable has the ids of the connections that were
+// lost recently enough that the client can still resume them.
+type webConnRegistryEntry struct {
+	Connections []*model.WebSocketConnectionInfo `json:"connections"`
+	Resumable   []string                         `json:"resumable,omitempty"`
+}
+
+// webConnRegistryUpdate is sent to the other servers in the cluster when a user's connections change, and
+// periodically with the entries of every user so that servers that missed an update or joined later catch up. Updates
+// are ordered by their sequence numbers, since they aren't always received in the order that they were sent.
+type webConnRegistryUpdate struct {
+	ClusterId string                           `json:"cluster_id"`
+	Sequence  int64                            `json:"sequence"`
+	Full      bool                             `json:"full,omitempty"`
+	Users     map[string]*webConnRegistryEntry `json:"users"`
+}
+
+// webConnHandOver carries the state of a connection from the server that it was open to over to the one that a
+// client resumed it on.
+type webConnHandOver struct {
+	ConnectionId string                  `json:"connection_id"`
+	UserId       string                  `json:"user_id"`
+	RequestedBy  string                  `json:"requested_by"`
+	Sequence     int64                   `json:"sequence"`
+	Events       []*model.WebSocketEvent `json:"events,omitempty"`
+}
+
+type webConnRegistryUser struct {
+	sequence int64
+	entry    *webConnRegistryEntry
+}
+
+type webConnRegistryNode struct {
+	lastSeenAt int64
+	users      map[string]*webConnRegistryUser
+}
+
+// webConnRegistry keeps track of the websocket connections that are open to the other servers in the cluster, so
+// that a client can connect to any of them. The zero value is ready to use.
+type webConnRegistry struct {
+	lock     sync.RWMutex
+	nodes    map[string]*webConnRegistryNode
+	sequence int64
+}
+
+// nextSequence returns the sequence number for an update from this server. Sequence numbers start from the current
+// time so that they keep increasing if the server restarts with the same cluster id.
+func (r *webConnRegistry) nextSequence() int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.sequence == 0 {
+		r.sequence = model.GetMillis() * 1000
+	}
+	r.sequence++
+
+	return r.sequence
+}
+
+// Apply records an update from another server, ignoring the entries that are older than the ones that it has.
+func (r *webConnRegistry) Apply(update *webConnRegistryUpdate) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.nodes == nil {
+		r.nodes = make(map[string]*webConnRegistryNode)
+	}
+
+	node := r.nodes[update.ClusterId]
+	if node == nil {
+		node = &webConnRegistryNode{users: make(map[string]*webConnRegistryUser)}
+		r.nodes[update.ClusterId] = node
+	}
+	node.lastSeenAt = model.GetMillis()
+
+	if update.Full {
+		for userId, user := range node.users {
+			if _, ok := update.Users[userId]; !ok && user.sequence < update.Sequence {
+				delete(node.users, userId)
+			}
+		}
+	}
+
+	for userId, entry := range update.Users {
+		if user := node.users[userId]; user != nil && user.sequence > update.Sequence {
+			continue
+		}
+
+		node.users[userId] = &webConnRegistryUser{sequence: update.Sequence, entry: entry}
+	}
+}
+
+// Expire forgets the connections of the servers that haven't been heard from recently.
+func (r *webConnRegistry) Expire() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	expireBefore := model.GetMillis() - int64(WEBCONN_REGISTRY_NODE_EXPIRY/time.Millisecond)
+	for clusterId, node := range r.nodes {
+		if node.lastSeenAt < expireBefore {
+			delete(r.nodes, clusterId)
+		}
+	}
+}
+
+// ForUser describes the connections that are open to the other servers, or only those of a user if userId isn't
+// empty.
+func (r *webConnRegistry) ForUser(userId string) []*model.WebSocketConnectionInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	infos := []*model.WebSocketConnectionInfo{}
+	for _, node := range r.nodes {
+		if userId != "" {
+			if user := node.users[userId]; user != nil {
+				infos = append(infos, user.entry.Connections...)
+			}
+			continue
+		}
+
+		for _, user := range node.users {
+			infos = append(infos, user.entry.Connections...)
+		}
+	}
+
+	return infos
+}
+
+// HasConnection returns true if a connection is open to another server or can still be resumed there.
+func (r *webConnRegistry) HasConnection(userId, connectionId string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, node := range r.nodes {
+		user := node.users[userId]
+		if user == nil {
+			continue
+		}
+
+		for _, info := range user.entry.Connections {
+			if info.Id == connectionId {
+				return true
+			}
+		}
+
+		for _, id := range user.entry.Resumable {
+			if id == connectionId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (a *App) sendWebConnRegistryUpdate(update *webConnRegistryUpdate) {
+	if a.Cluster == nil {
+		return
+	}
+
+	update.ClusterId = a.GetClusterId()
+
+	b, err := json.Marshal(update)
+	if err != nil {
+		mlog.Error("Unable to encode the websocket connections for the cluster", mlog.Err(err))
+		return
+	}
+
+	a.Cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.CLUSTER_EVENT_WEBSOCKET_CONNECTIONS,
+		SendType: model.CLUSTER_SEND_RELIABLE,
+		Data:     string(b),
+	})
+}
+
+// syncWebConnRegistry sends the connections of every user to the other servers and forgets those of servers that
+// have left the cluster.
+func (a *App) syncWebConnRegistry() {
+	if a.Cluster == nil {
+		return
+	}
+
+	// The sequence number is taken first so that any update that's sent while the entries are collected wins
+	update := &webConnRegistryUpdate{
+		Sequence: a.webConnRegistry.nextSequence(),
+		Full:     true,
+		Users:    make(map[string]*webConnRegistryEntry),
+	}
+
+	for _, hub := range a.Hubs {
+		for userId, entry := range hub.RegistryEntries() {
+			update.Users[userId] = entry
+		}
+	}
+
+	a.sendWebConnRegistryUpdate(update)
+	a.webConnRegistry.Expire()
+}
+
+// requestWebConnHandOver asks the other servers for the state of a connection that a client is resuming, returning
+// nil if none of them hand it over in time.
+func (a *App) requestWebConnHandOver(userId, connectionId string) *webConnHandOver {
+	request, err := json.Marshal(&webConnHandOver{
+		ConnectionId: connectionId,
+		UserId:       userId,
+		RequestedBy:  a.GetClusterId(),
+	})
+	if err != nil {
+		return nil
+	}
+
+	waiter := make(chan *webConnHandOver, 1)
+	a.webConnHandOvers.Store(connectionId, waiter)
+	defer a.webConnHandOvers.Delete(connectionId)
+
+	a.Cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.CLUSTER_EVENT_WEBSOCKET_HAND_OVER_REQUEST,
+		SendType: model.CLUSTER_SEND_RELIABLE,
+		Data:     string(request),
+	})
+
+	select {
+	case handOver := <-waiter:
+		return handOver
+	case <-time.After(WEBCONN_HAND_OVER_TIMEOUT):
+		mlog.Debug("Timed out waiting for another server to hand over a websocket connection", mlog.String("user_id", userId), mlog.String("connection_id", connectionId))
+		return nil
+	}
+}
+
+func (a *App) ClusterWebSocketConnectionsHandler(msg *model.ClusterMessage) {
+	var update *webConnRegistryUpdate
+	if err := json.NewDecoder(strings.NewReader(msg.Data)).Decode(&update); err != nil || update == nil {
+		return
+	}
+
+	a.webConnRegistry.Apply(update)
+}
+
+func (a *App) ClusterWebSocketHandOverRequestHandler(msg *model.ClusterMessage) {
+	var request *webConnHandOver
+	if err := json.NewDecoder(strings.NewReader(msg.Data)).Decode(&request); err != nil || request == nil {
+		return
+	}
+
+	hub := a.GetHubForUserId(request.UserId)
+	if hub == nil {
+		return
+	}
+
+	handOver := hub.TakeOver(request.UserId, request.ConnectionId)
+	if handOver == nil {
+		return
+	}
+	handOver.RequestedBy = request.RequestedBy
+
+	b, err := json.Marshal(handOver)
+	if err != nil {
+		return
+	}
+
+	a.Cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.CLUSTER_EVENT_WEBSOCKET_HAND_OVER,
+		SendType: model.CLUSTER_SEND_RELIABLE,
+		Data:     string(b),
+	})
+}
+
+func (a *App) ClusterWebSocketHandOverHandler(msg *model.ClusterMessage) {
+	var handOver *webConnHandOver
+	if err := json.NewDecoder(strings.NewReader(msg.Data)).Decode(&handOver); err != nil || handOver == nil {
+		return
+	}
+
+	if handOver.RequestedBy != a.GetClusterId() {
+		return
+	}
+
+	if waiter, ok := a.webConnHandOvers.Load(handOver.ConnectionId); ok {
+		select {
+		case waiter.(chan *webConnHandOver) <- handOver:
+		default:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestWebConnRegistry(t *testing.T) {
+	var r webConnRegistry
+
+	entry := func(ids ...string) *webConnRegistryEntry {
+		e := &webConnRegistryEntry{}
+		for _, id := range ids {
+			e.Connections = append(e.Connections, &model.WebSocketConnectionInfo{Id: id, UserId: "user1"})
+		}
+		return e
+	}
+
+	r.Apply(&webConnRegistryUpdate{ClusterId: "node1", Sequence: 2, Users: map[string]*webConnRegistryEntry{"user1": entry("conn1", "conn2")}})
+	assert.Len(t, r.ForUser("user1"), 2)
+	assert.True(t, r.HasConnection("user1", "conn2"))
+
+	t.Run("older updates are ignored", func(t *testing.T) {
+		r.Apply(&webConnRegistryUpdate{ClusterId: "node1", Sequence: 1, Users: map[string]*webConnRegistryEntry{"user1": entry()}})
+		assert.Len(t, r.ForUser("user1"), 2)
+	})
+
+	t.Run("resumable connections", func(t *testing.T) {
+		e := entry("conn1")
+		e.Resumable = []string{"conn2"}
+		r.Apply(&webConnRegistryUpdate{ClusterId: "node1", Sequence: 3, Users: map[string]*webConnRegistryEntry{"user1": e}})
+
+		assert.Len(t, r.ForUser("user1"), 1)
+		assert.True(t, r.HasConnection("user1", "conn2"))
+		assert.False(t, r.HasConnection("user1", "conn3"))
+		assert.False(t, r.HasConnection("user2", "conn1"))
+	})
+
+	t.Run("full updates replace older entries", func(t *testing.T) {
+		r.Apply(&webConnRegistryUpdate{ClusterId: "node2", Sequence: 1, Users: map[string]*webConnRegistryEntry{"user1": entry("conn4")}})
+		assert.Len(t, r.ForUser(""), 2)
+
+		r.Apply(&webConnRegistryUpdate{ClusterId: "node1", Sequence: 4, Full: true})
+		assert.Len(t, r.ForUser("user1"), 1)
+		assert.Equal(t, "conn4", r.ForUser("user1")[0].Id)
+	})
+
+	assert.NotEqual(t, r.nextSequence(), r.nextSequence())
+}
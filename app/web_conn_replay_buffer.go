@@ -0,0 +1,48 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// webConnReplayBuffer keeps the most recent events that were sent on a connection, oldest first, so that they can be
+// sent again to a client that missed them. It isn't safe for concurrent use, since it's only used by the hub.
+type webConnReplayBuffer struct {
+	events []*model.WebSocketEvent
+	next   int
+	full   bool
+}
+
+func newWebConnReplayBuffer(size int) *webConnReplayBuffer {
+	return &webConnReplayBuffer{
+		events: make([]*model.WebSocketEvent, size),
+	}
+}
+
+// Add adds an event to the buffer, replacing the oldest one if the buffer is full.
+func (b *webConnReplayBuffer) Add(evt *model.WebSocketEvent) {
+	if b == nil || len(b.events) == 0 {
+		return
+	}
+
+	b.events[b.next] = evt
+	b.next = (b.next + 1) % len(b.events)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Events returns the events in the buffer from oldest to newest.
+func (b *webConnReplayBuffer) Events() []*model.WebSocketEvent {
+	if b == nil {
+		return nil
+	}
+
+	if !b.full {
+		return append([]*model.WebSocketEvent{}, b.events[:b.next]...)
+	}
+
+	return append(append([]*model.WebSocketEvent{}, b.events[b.next:]...), b.events[:b.next]...)
+}
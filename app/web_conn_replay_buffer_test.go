@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestWebConnMissedEvents(t *testing.T) {
+	wc := &WebConn{
+		Send:         make(chan model.WebSocketMessage, 10),
+		replayBuffer: newWebConnReplayBuffer(3),
+	}
+
+	for i := 0; i < 5; i++ {
+		require.True(t, wc.queueEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", "", "", nil)))
+	}
+	assert.Equal(t, int64(5), wc.Sequence)
+	assert.Len(t, wc.Send, 5)
+
+	sequences := func(events []*model.WebSocketEvent) []int64 {
+		result := []int64{}
+		for _, evt := range events {
+			result = append(result, evt.Sequence)
+		}
+		return result
+	}
+
+	events, ok := wc.missedEvents(2)
+	require.True(t, ok)
+	assert.Equal(t, []int64{3, 4}, sequences(events))
+
+	events, ok = wc.missedEvents(1)
+	require.True(t, ok)
+	assert.Equal(t, []int64{2, 3, 4}, sequences(events))
+
+	events, ok = wc.missedEvents(4)
+	require.True(t, ok)
+	assert.Empty(t, events)
+
+	_, ok = wc.missedEvents(0)
+	assert.False(t, ok, "should no longer have the event after the sequence number")
+
+	_, ok = wc.missedEvents(5)
+	assert.False(t, ok, "should never have sent an event with the sequence number")
+
+	wc.disconnectedAt = model.GetMillis()
+	require.True(t, wc.queueEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", "", "", nil)))
+	assert.Len(t, wc.Send, 5, "should only keep events for replay once disconnected")
+
+	events, ok = wc.missedEvents(4)
+	require.True(t, ok)
+	assert.Equal(t, []int64{5}, sequences(events))
+}
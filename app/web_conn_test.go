@@ -79,4 +79,47 @@ func TestWebConnShouldSendEvent(t *testing.T) {
 		assert.Equal(t, c.User2Expected, basicUser2Wc.ShouldSendEvent(event), c.Description)
 		assert.Equal(t, c.AdminExpected, adminUserWc.ShouldSendEvent(event), c.Description)
 	}
+
+	t.Run("should only send events that match the connection's filter", func(t *testing.T) {
+		basicUserWc.SetEventFilter(model.NewWebSocketEventFilter([]string{model.WEBSOCKET_EVENT_POSTED}, []string{th.BasicChannel.Id}))
+		defer basicUserWc.SetEventFilter(nil)
+
+		assert.True(t, basicUserWc.ShouldSendEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", th.BasicChannel.Id, "", nil)))
+		assert.False(t, basicUserWc.ShouldSendEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", th.BasicChannel.Id, "", nil)))
+		assert.False(t, basicUserWc.ShouldSendEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", model.NewId(), "", nil)))
+		assert.True(t, basicUser2Wc.ShouldSendEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", th.BasicChannel.Id, "", nil)))
+	})
+
+	t.Run("an empty filter should send every event", func(t *testing.T) {
+		basicUserWc.SetEventFilter(model.NewWebSocketEventFilter(nil, nil))
+
+		assert.Nil(t, basicUserWc.GetEventFilter())
+		assert.True(t, basicUserWc.ShouldSendEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", th.BasicChannel.Id, "", nil)))
+	})
+}
+
+func TestWebConnIsStuck(t *testing.T) {
+	wc := &WebConn{
+		Send: make(chan model.WebSocketMessage, 4),
+	}
+
+	queue := func() {
+		require.True(t, wc.queueEvent(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", "", "", nil)))
+	}
+
+	queue()
+	assert.Zero(t, wc.slowSince, "shouldn't be slow while the queue is mostly empty")
+
+	queue()
+	assert.NotZero(t, wc.slowSince, "should be slow once the queue is half full")
+	assert.False(t, wc.isStuck(30))
+
+	wc.slowSince -= 30 * 1000
+	assert.True(t, wc.isStuck(30), "should be stuck once the queue has been slow for too long")
+
+	<-wc.Send
+	<-wc.Send
+	queue()
+	assert.Zero(t, wc.slowSince, "shouldn't be slow once the client catches up")
+	assert.False(t, wc.isStuck(30))
 }
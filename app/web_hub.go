@@ -8,55 +8,114 @@ import (
 	"hash/fnv"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/gorilla/websocket"
+
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 )
 
 const (
-	BROADCAST_QUEUE_SIZE = 4096
-	DEADLOCK_TICKER      = 15 * time.Second                  // check every 15 seconds
-	DEADLOCK_WARN        = (BROADCAST_QUEUE_SIZE * 99) / 100 // number of buffered messages before printing stack trace
+	BROADCAST_QUEUE_SIZE     = 4096
+	BROADCAST_LOW_QUEUE_SIZE = 1024
+	DEADLOCK_TICKER          = 15 * time.Second                  // check every 15 seconds
+	DEADLOCK_WARN            = (BROADCAST_QUEUE_SIZE * 99) / 100 // number of buffered messages before printing stack trace
+
+	HUB_CHANNEL_LOAD_BATCH_SIZE = 10        // how many users' channels a hub loads at once
+	HUB_CHANNEL_LOAD_RETRY_TIME = 1000 * 30 // how long a hub waits before loading a user's channels again after failing
+)
+
+// Events are broadcast through the hub in lanes. Critical events are always sent before any others and are never
+// dropped, while low priority events like typing and presence are dropped rather than held up when the hub or a
+// connection falls behind.
+const (
+	HUB_LANE_CRITICAL = "critical"
+	HUB_LANE_NORMAL   = "normal"
+	HUB_LANE_LOW      = "low"
 )
 
+func hubLaneForEvent(event string) string {
+	switch event {
+	case model.WEBSOCKET_EVENT_POSTED, model.WEBSOCKET_EVENT_POST_EDITED, model.WEBSOCKET_EVENT_POST_DELETED:
+		return HUB_LANE_CRITICAL
+	case model.WEBSOCKET_EVENT_TYPING, model.WEBSOCKET_EVENT_STATUS_CHANGE, model.WEBSOCKET_EVENT_READ_RECEIPT:
+		return HUB_LANE_LOW
+	default:
+		return HUB_LANE_NORMAL
+	}
+}
+
 type WebConnActivityMessage struct {
 	UserId       string
 	SessionToken string
 	ActivityAt   int64
 }
 
+// hubConnectionsQuery asks a hub to describe its connections, or only those of a user if UserId is set.
+type hubConnectionsQuery struct {
+	UserId string
+	Result chan []*model.WebSocketConnectionInfo
+}
+
+// hubConnectionRequest asks a hub about one of a user's connections, either to find out whether it has the
+// connection or to take it over for a client that's resuming it on another server.
+type hubConnectionRequest struct {
+	UserId       string
+	ConnectionId string
+	Found        chan bool
+	Result       chan *webConnHandOver
+}
+
 type Hub struct {
 	// connectionCount should be kept first.
 	// See https://github.com/mattermost/mattermost-server/pull/7281
-	connectionCount int64
-	app             *App
-	connectionIndex int
-	register        chan *WebConn
-	unregister      chan *WebConn
-	broadcast       chan *model.WebSocketEvent
-	stop            chan struct{}
-	didStop         chan struct{}
-	invalidateUser  chan string
-	activity        chan *WebConnActivityMessage
-	ExplicitStop    bool
-	goroutineId     int
+	connectionCount   int64
+	shedCount         int64
+	app               *App
+	connectionIndex   int
+	register          chan *WebConn
+	unregister        chan *WebConn
+	broadcastCritical chan *model.WebSocketEvent
+	broadcast         chan *model.WebSocketEvent
+	broadcastLow      chan *model.WebSocketEvent
+	stop              chan struct{}
+	didStop           chan struct{}
+	invalidateUser    chan string
+	activity          chan *WebConnActivityMessage
+	connections       chan *hubConnectionsQuery
+	findConnection    chan *hubConnectionRequest
+	takeOver          chan *hubConnectionRequest
+	registryEntries   chan chan map[string]*webConnRegistryEntry
+	closeForRestart   chan struct{}
+	channelsLoaded    chan *hubChannelLoad
+	ExplicitStop      bool
+	goroutineId       int
 }
 
 func (a *App) NewWebHub() *Hub {
 	return &Hub{
-		app:            a,
-		register:       make(chan *WebConn, 1),
-		unregister:     make(chan *WebConn, 1),
-		broadcast:      make(chan *model.WebSocketEvent, BROADCAST_QUEUE_SIZE),
-		stop:           make(chan struct{}),
-		didStop:        make(chan struct{}),
-		invalidateUser: make(chan string),
-		activity:       make(chan *WebConnActivityMessage),
-		ExplicitStop:   false,
+		app:               a,
+		register:          make(chan *WebConn, 1),
+		unregister:        make(chan *WebConn, 1),
+		broadcastCritical: make(chan *model.WebSocketEvent, BROADCAST_QUEUE_SIZE),
+		broadcast:         make(chan *model.WebSocketEvent, BROADCAST_QUEUE_SIZE),
+		broadcastLow:      make(chan *model.WebSocketEvent, BROADCAST_LOW_QUEUE_SIZE),
+		stop:              make(chan struct{}),
+		didStop:           make(chan struct{}),
+		invalidateUser:    make(chan string),
+		activity:          make(chan *WebConnActivityMessage),
+		connections:       make(chan *hubConnectionsQuery),
+		findConnection:    make(chan *hubConnectionRequest),
+		takeOver:          make(chan *hubConnectionRequest),
+		registryEntries:   make(chan chan map[string]*webConnRegistryEntry),
+		closeForRestart:   make(chan struct{}),
+		channelsLoaded:    make(chan *hubChannelLoad),
+		ExplicitStop:      false,
 	}
 }
 
@@ -69,6 +128,48 @@ func (a *App) TotalWebsocketConnections() int {
 	return int(count)
 }
 
+// GetWebSocketConnections describes the websocket connections that are open to any server in the cluster, or only
+// those of a user if userId isn't empty, in the order that they were opened.
+func (a *App) GetWebSocketConnections(userId string) []*model.WebSocketConnectionInfo {
+	hubs := a.Hubs
+	if userId != "" {
+		hubs = []*Hub{a.GetHubForUserId(userId)}
+	}
+
+	infos := []*model.WebSocketConnectionInfo{}
+	for _, hub := range hubs {
+		if hub != nil {
+			infos = append(infos, hub.Connections(userId)...)
+		}
+	}
+
+	infos = append(infos, a.webConnRegistry.ForUser(userId)...)
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ConnectAt < infos[j].ConnectAt
+	})
+
+	return infos
+}
+
+// CheckWebSocketConnectionQuota returns the reason that a user isn't allowed to open another websocket connection,
+// or an empty string if they are.
+func (a *App) CheckWebSocketConnectionQuota(userId string) string {
+	settings := a.Config().ServiceSettings
+
+	if max := *settings.MaximumWebSocketConnectionsPerServer; max > 0 && a.TotalWebsocketConnections() >= max {
+		return model.WEBSOCKET_REJECT_SERVER_CONNECTION_LIMIT
+	}
+
+	if max := *settings.MaximumWebSocketConnectionsPerUser; max > 0 {
+		if len(a.GetWebSocketConnections(userId)) >= max {
+			return model.WEBSOCKET_REJECT_USER_CONNECTION_LIMIT
+		}
+	}
+
+	return ""
+}
+
 func (a *App) HubStart() {
 	// Total number of hubs is twice the number of CPUs.
 	numberOfHubs := runtime.NumCPU() * 2
@@ -85,17 +186,22 @@ func (a *App) HubStart() {
 
 	go func() {
 		ticker := time.NewTicker(DEADLOCK_TICKER)
+		syncTicker := time.NewTicker(WEBCONN_REGISTRY_SYNC_INTERVAL)
 
 		defer func() {
 			ticker.Stop()
+			syncTicker.Stop()
 		}()
 
 		for {
 			select {
 			case <-ticker.C:
+				a.reportHubLaneQueueDepths()
+				a.reportWebConnSendQueueDepths()
+
 				for _, hub := range a.Hubs {
-					if len(hub.broadcast) >= DEADLOCK_WARN {
-						mlog.Error(fmt.Sprintf("Hub processing might be deadlock on hub %v goroutine %v with %v events in the buffer", hub.connectionIndex, hub.goroutineId, len(hub.broadcast)))
+					if len(hub.broadcast) >= DEADLOCK_WARN || len(hub.broadcastCritical) >= DEADLOCK_WARN {
+						mlog.Error(fmt.Sprintf("Hub processing might be deadlock on hub %v goroutine %v with %v events in the buffer", hub.connectionIndex, hub.goroutineId, len(hub.broadcast)+len(hub.broadcastCritical)))
 						buf := make([]byte, 1<<16)
 						runtime.Stack(buf, true)
 						output := fmt.Sprintf("%s", buf)
@@ -109,6 +215,9 @@ func (a *App) HubStart() {
 					}
 				}
 
+			case <-syncTicker.C:
+				a.syncWebConnRegistry()
+
 			case <-a.HubsStopCheckingForDeadlock:
 				return
 			}
@@ -116,6 +225,37 @@ func (a *App) HubStart() {
 	}()
 }
 
+// reportHubLaneQueueDepths records how many events are waiting in each lane across all of the hubs.
+func (a *App) reportHubLaneQueueDepths() {
+	if a.Metrics == nil {
+		return
+	}
+
+	depths := make(map[string]int)
+	for _, hub := range a.Hubs {
+		for lane, depth := range hub.LaneQueueDepths() {
+			depths[lane] += depth
+		}
+	}
+
+	for lane, depth := range depths {
+		a.Metrics.SetWebsocketHubLaneQueueDepth(lane, float64(depth))
+	}
+}
+
+// reportWebConnSendQueueDepths records how many events are waiting to be sent on each connection.
+func (a *App) reportWebConnSendQueueDepths() {
+	if a.Metrics == nil {
+		return
+	}
+
+	for _, hub := range a.Hubs {
+		for _, info := range hub.Connections("") {
+			a.Metrics.ObserveWebsocketSendQueueDepth(float64(info.SendQueueDepth))
+		}
+	}
+}
+
 func (a *App) HubStop() {
 	mlog.Info("stopping websocket hub connections")
 
@@ -130,6 +270,12 @@ func (a *App) HubStop() {
 	}
 
 	a.Hubs = []*Hub{}
+
+	// Let the other servers know that none of the connections are open to this one anymore
+	a.sendWebConnRegistryUpdate(&webConnRegistryUpdate{
+		Sequence: a.webConnRegistry.nextSequence(),
+		Full:     true,
+	})
 }
 
 func (a *App) GetHubForUserId(userId string) *Hub {
@@ -145,9 +291,18 @@ func (a *App) GetHubForUserId(userId string) *Hub {
 
 func (a *App) HubRegister(webConn *WebConn) {
 	hub := a.GetHubForUserId(webConn.UserId)
-	if hub != nil {
-		hub.Register(webConn)
+	if hub == nil {
+		return
+	}
+
+	// A client can resume a connection that was open to another server in the cluster, which hands over its state
+	if webConn.resumeConnectionId != "" && a.Cluster != nil && !hub.HasConnection(webConn.UserId, webConn.resumeConnectionId) {
+		if a.webConnRegistry.HasConnection(webConn.UserId, webConn.resumeConnectionId) {
+			webConn.handOver = a.requestWebConnHandOver(webConn.UserId, webConn.resumeConnectionId)
+		}
 	}
+
+	hub.Register(webConn)
 }
 
 func (a *App) HubUnregister(webConn *WebConn) {
@@ -233,7 +388,7 @@ func (a *App) InvalidateCacheForChannelSkipClusterSend(channelId string) {
 func (a *App) InvalidateCacheForChannelMembers(channelId string) {
 	a.InvalidateCacheForChannelMembersSkipClusterSend(channelId)
 
-	if a.Cluster != nil {
+	if a.Cluster != nil && !a.usesRedisCaches() {
 		msg := &model.ClusterMessage{
 			Event:    model.CLUSTER_EVENT_INVALIDATE_CACHE_FOR_CHANNEL_MEMBERS,
 			SendType: model.CLUSTER_SEND_BEST_EFFORT,
@@ -251,7 +406,7 @@ func (a *App) InvalidateCacheForChannelMembersSkipClusterSend(channelId string)
 func (a *App) InvalidateCacheForChannelMembersNotifyProps(channelId string) {
 	a.InvalidateCacheForChannelMembersNotifyPropsSkipClusterSend(channelId)
 
-	if a.Cluster != nil {
+	if a.Cluster != nil && !a.usesRedisCaches() {
 		msg := &model.ClusterMessage{
 			Event:    model.CLUSTER_EVENT_INVALIDATE_CACHE_FOR_CHANNEL_MEMBERS_NOTIFY_PROPS,
 			SendType: model.CLUSTER_SEND_BEST_EFFORT,
@@ -308,6 +463,11 @@ func (a *App) InvalidateCacheForUserSkipClusterSend(userId string) {
 	a.Srv.Store.User().InvalidateProfilesInChannelCacheByUser(userId)
 	a.Srv.Store.User().InvalidatProfileCacheForUser(userId)
 
+	a.invalidateWebConnCacheForUser(userId)
+}
+
+// invalidateWebConnCacheForUser makes this server's websocket connections for a user reload what they know about them.
+func (a *App) invalidateWebConnCacheForUser(userId string) {
 	hub := a.GetHubForUserId(userId)
 	if hub != nil {
 		hub.InvalidateUser(userId)
@@ -347,10 +507,6 @@ func (a *App) UpdateWebConnUserActivity(session model.Session, activityAt int64)
 
 func (h *Hub) Register(webConn *WebConn) {
 	h.register <- webConn
-
-	if webConn.IsAuthenticated() {
-		webConn.SendHello()
-	}
 }
 
 func (h *Hub) Unregister(webConn *WebConn) {
@@ -360,12 +516,47 @@ func (h *Hub) Unregister(webConn *WebConn) {
 	}
 }
 
+// Broadcast queues an event in its lane. Low priority events are dropped instead of waiting when their lane is full.
 func (h *Hub) Broadcast(message *model.WebSocketEvent) {
-	if h != nil && h.broadcast != nil && message != nil {
+	if h == nil || h.broadcast == nil || message == nil {
+		return
+	}
+
+	switch hubLaneForEvent(message.Event) {
+	case HUB_LANE_CRITICAL:
+		h.broadcastCritical <- message
+	case HUB_LANE_LOW:
+		select {
+		case h.broadcastLow <- message:
+		default:
+			h.recordShed(message.Event)
+		}
+	default:
 		h.broadcast <- message
 	}
 }
 
+func (h *Hub) recordShed(eventType string) {
+	atomic.AddInt64(&h.shedCount, 1)
+
+	if metrics := h.app.Metrics; metrics != nil {
+		metrics.IncrementWebsocketEventShed(eventType)
+	}
+}
+
+// ShedCount returns the number of low priority events that have been dropped by the hub or its connections.
+func (h *Hub) ShedCount() int64 {
+	return atomic.LoadInt64(&h.shedCount)
+}
+
+func (h *Hub) LaneQueueDepths() map[string]int {
+	return map[string]int{
+		HUB_LANE_CRITICAL: len(h.broadcastCritical),
+		HUB_LANE_NORMAL:   len(h.broadcast),
+		HUB_LANE_LOW:      len(h.broadcastLow),
+	}
+}
+
 func (h *Hub) InvalidateUser(userId string) {
 	h.invalidateUser <- userId
 }
@@ -374,6 +565,68 @@ func (h *Hub) UpdateActivity(userId, sessionToken string, activityAt int64) {
 	h.activity <- &WebConnActivityMessage{UserId: userId, SessionToken: sessionToken, ActivityAt: activityAt}
 }
 
+// Connections describes the hub's connections, or only those of a user if userId isn't empty.
+func (h *Hub) Connections(userId string) []*model.WebSocketConnectionInfo {
+	query := &hubConnectionsQuery{UserId: userId, Result: make(chan []*model.WebSocketConnectionInfo, 1)}
+
+	select {
+	case h.connections <- query:
+	case <-h.stop:
+		return nil
+	}
+
+	return <-query.Result
+}
+
+// HasConnection returns true if the hub has one of a user's connections, whether it's open or can still be resumed.
+func (h *Hub) HasConnection(userId, connectionId string) bool {
+	request := &hubConnectionRequest{UserId: userId, ConnectionId: connectionId, Found: make(chan bool, 1)}
+
+	select {
+	case h.findConnection <- request:
+	case <-h.stop:
+		return false
+	}
+
+	return <-request.Found
+}
+
+// TakeOver closes one of a user's connections so that a client can resume it on another server, returning its state
+// or nil if the hub doesn't have it.
+func (h *Hub) TakeOver(userId, connectionId string) *webConnHandOver {
+	request := &hubConnectionRequest{UserId: userId, ConnectionId: connectionId, Result: make(chan *webConnHandOver, 1)}
+
+	select {
+	case h.takeOver <- request:
+	case <-h.stop:
+		return nil
+	}
+
+	return <-request.Result
+}
+
+// RegistryEntries describes the connections of every user that has any, including those that can still be resumed.
+func (h *Hub) RegistryEntries() map[string]*webConnRegistryEntry {
+	result := make(chan map[string]*webConnRegistryEntry, 1)
+
+	select {
+	case h.registryEntries <- result:
+	case <-h.stop:
+		return nil
+	}
+
+	return <-result
+}
+
+// CloseForRestart tells the clients of the hub's connections that the server is restarting so that they reconnect,
+// which should be to another server in the cluster.
+func (h *Hub) CloseForRestart() {
+	select {
+	case h.closeForRestart <- struct{}{}:
+	case <-h.stop:
+	}
+}
+
 func getGoroutineId() int {
 	var buf [64]byte
 	n := runtime.Stack(buf[:], false)
@@ -401,21 +654,239 @@ func (h *Hub) Start() {
 
 		connections := newHubConnectionIndex()
 
+		// Connections that were lost are kept for a while so that the events that are sent while the client is
+		// reconnecting can be replayed to it
+		disconnected := newHubConnectionIndex()
+		expireTicker := time.NewTicker(WEBCONN_RECONNECT_WINDOW / 2)
+		defer expireTicker.Stop()
+
+		channels := newHubChannelIndex()
+
+		// loadChannels starts loading the channels that the index needs, a few users at a time, without holding up the
+		// hub. A user's channels are taken from one of their connections instead when it has already loaded them.
+		loadChannels := func() {
+			now := model.GetMillis()
+			staleBefore := now - WEBCONN_MEMBER_CACHE_TIME
+
+			for _, load := range channels.StartLoads(now, staleBefore, HUB_CHANNEL_LOAD_BATCH_SIZE) {
+				for _, webCon := range append(connections.ForUser(load.UserId), disconnected.ForUser(load.UserId)...) {
+					if webCon.AllChannelMembers != nil && webCon.LastAllChannelMembersTime >= staleBefore && webCon.LastAllChannelMembersTime > load.LoadedAt {
+						load.Channels = webCon.AllChannelMembers
+						load.LoadedAt = webCon.LastAllChannelMembersTime
+					}
+				}
+				if load.Channels != nil {
+					channels.Loaded(load, 0)
+					continue
+				}
+
+				load := load
+				h.app.Go(func() {
+					if result := <-h.app.Srv.Store.Channel().GetAllChannelMembersForUser(load.UserId, true, true); result.Err != nil {
+						load.Err = result.Err
+					} else {
+						load.Channels = result.Data.(map[string]string)
+					}
+					load.LoadedAt = model.GetMillis()
+
+					select {
+					case h.channelsLoaded <- load:
+					case <-h.stop:
+					}
+				})
+			}
+		}
+
+		// untrack stops tracking the channels of a user once all of their connections are gone
+		untrack := func(userId string) {
+			if len(connections.ForUser(userId)) == 0 && len(disconnected.ForUser(userId)) == 0 {
+				channels.RemoveUser(userId)
+			}
+		}
+
+		broadcast := func(msg *model.WebSocketEvent) {
+			candidates := connections.All()
+			disconnectedCandidates := disconnected.All()
+			if msg.Broadcast.UserId != "" {
+				candidates = connections.ForUser(msg.Broadcast.UserId)
+				disconnectedCandidates = disconnected.ForUser(msg.Broadcast.UserId)
+			} else if msg.Broadcast.ChannelId != "" {
+				// Only the channel's members can receive its events, so there's no need to look at anyone else
+				candidates = nil
+				disconnectedCandidates = nil
+				for _, userId := range channels.UsersInChannel(msg.Broadcast.ChannelId) {
+					candidates = append(candidates, connections.ForUser(userId)...)
+					disconnectedCandidates = append(disconnectedCandidates, disconnected.ForUser(userId)...)
+				}
+			}
+			msg.PrecomputeJSON()
+			lowPriority := hubLaneForEvent(msg.Event) == HUB_LANE_LOW
+			slowClientTimeout := *h.app.Config().ServiceSettings.WebSocketSlowClientTimeoutSeconds
+			for _, webCon := range candidates {
+				if webCon.ShouldSendEvent(msg) {
+					if lowPriority && webCon.isSendQueueAbove(SEND_SLOW_WARN_PERCENT) {
+						// Low priority events are shed before they're given a sequence number once the connection
+						// starts to fall behind, so the client doesn't see a gap where they would have been
+						h.recordShed(msg.Event)
+						continue
+					}
+
+					if !webCon.queueEvent(msg) {
+						if lowPriority {
+							h.recordShed(msg.Event)
+							continue
+						}
+
+						mlog.Error(fmt.Sprintf("webhub.broadcast: cannot send, closing websocket for userId=%v", webCon.UserId))
+						close(webCon.Send)
+						connections.Remove(webCon)
+					} else if slowClientTimeout > 0 && webCon.isStuck(slowClientTimeout) {
+						// The client can resume the connection when it reconnects, since its events are still kept
+						mlog.Warn(fmt.Sprintf("webhub.broadcast: client is too slow, closing websocket for userId=%v", webCon.UserId))
+						close(webCon.Send)
+						connections.Remove(webCon)
+
+						if metrics := h.app.Metrics; metrics != nil {
+							metrics.IncrementWebsocketSlowClientDisconnect()
+						}
+					}
+				}
+			}
+			for _, webCon := range disconnectedCandidates {
+				if webCon.ShouldSendEvent(msg) {
+					webCon.queueEvent(msg)
+				}
+			}
+		}
+
+		// takeOver removes a connection that's being resumed by a new one, possibly on another server, and returns
+		// its state, or nil if the hub doesn't have it
+		takeOver := func(userId, connectionId string) *webConnHandOver {
+			previous := findWebConn(connections.ForUser(userId), connectionId)
+			if previous != nil {
+				// The client reconnected before the server noticed that the previous connection was lost
+				connections.Remove(previous)
+				atomic.StoreInt64(&h.connectionCount, int64(len(connections.All())))
+				previous.WebSocket.Close()
+			} else if previous = findWebConn(disconnected.ForUser(userId), connectionId); previous != nil {
+				disconnected.Remove(previous)
+			} else {
+				return nil
+			}
+			untrack(userId)
+
+			// The previous connection can't be resumed again once it's been replaced
+			previous.takenOver = true
+
+			return &webConnHandOver{
+				ConnectionId: previous.Id,
+				UserId:       userId,
+				Sequence:     previous.Sequence,
+				Events:       previous.replayBuffer.Events(),
+			}
+		}
+
+		// resume lets a connection continue from the one that it's replacing, sending it the events that the client
+		// missed
+		resume := func(webCon *WebConn, handOver *webConnHandOver) {
+			missed, ok := missedWebSocketEvents(handOver.Events, handOver.Sequence, webCon.resumeSequence)
+			if !ok {
+				mlog.Debug(fmt.Sprintf("webhub.resume: missed events are no longer available, starting a new connection for userId=%v", webCon.UserId))
+				return
+			}
+
+			webCon.Id = handOver.ConnectionId
+			webCon.Sequence = handOver.Sequence
+			webCon.replayBuffer = newWebConnReplayBuffer(WEBCONN_REPLAY_BUFFER_SIZE)
+			for _, evt := range handOver.Events {
+				webCon.replayBuffer.Add(evt)
+			}
+
+			for _, evt := range missed {
+				select {
+				case webCon.Send <- evt:
+				default:
+					mlog.Error(fmt.Sprintf("webhub.resume: cannot send missed event, userId=%v type=%v", webCon.UserId, evt.Event))
+				}
+			}
+		}
+
+		registryEntry := func(userId string) *webConnRegistryEntry {
+			entry := &webConnRegistryEntry{Connections: webConnInfos(connections.ForUser(userId))}
+			for _, webCon := range disconnected.ForUser(userId) {
+				entry.Resumable = append(entry.Resumable, webCon.Id)
+			}
+			return entry
+		}
+
+		// connectionsChanged tells the other servers in the cluster about a user's connections to this one
+		connectionsChanged := func(userId string) {
+			if h.app.Cluster == nil {
+				return
+			}
+
+			update := &webConnRegistryUpdate{
+				Sequence: h.app.webConnRegistry.nextSequence(),
+				Users:    map[string]*webConnRegistryEntry{userId: registryEntry(userId)},
+			}
+
+			h.app.Go(func() {
+				h.app.sendWebConnRegistryUpdate(update)
+			})
+		}
+
 		for {
+			// Critical events jump ahead of everything else that's waiting
+			select {
+			case msg := <-h.broadcastCritical:
+				broadcast(msg)
+				continue
+			default:
+			}
+
 			select {
 			case webCon := <-h.register:
+				handOver := webCon.handOver
+				if handOver == nil && webCon.resumeConnectionId != "" {
+					handOver = takeOver(webCon.UserId, webCon.resumeConnectionId)
+				}
+				if handOver != nil {
+					resume(webCon, handOver)
+				}
+
 				connections.Add(webCon)
 				atomic.StoreInt64(&h.connectionCount, int64(len(connections.All())))
+				channels.AddUser(webCon.UserId)
+				loadChannels()
+
+				if webCon.IsAuthenticated() {
+					webCon.queueEvent(webCon.helloEvent())
+				}
+
+				connectionsChanged(webCon.UserId)
 			case webCon := <-h.unregister:
 				connections.Remove(webCon)
 				atomic.StoreInt64(&h.connectionCount, int64(len(connections.All())))
 
-				if len(webCon.UserId) == 0 {
+				// A connection that was taken over has been replaced by another one, so the user is still connected
+				if len(webCon.UserId) == 0 || webCon.takenOver {
 					continue
 				}
 
+				if !disconnected.Has(webCon) {
+					webCon.disconnectedAt = model.GetMillis()
+					disconnected.Add(webCon)
+				}
+
+				connectionsChanged(webCon.UserId)
+
 				conns := connections.ForUser(webCon.UserId)
 				if len(conns) == 0 {
+					if len(h.app.webConnRegistry.ForUser(webCon.UserId)) > 0 {
+						// The user is still connected to another server in the cluster
+						continue
+					}
+
 					h.app.Go(func() {
 						h.app.SetStatusOffline(webCon.UserId, false)
 					})
@@ -433,32 +904,84 @@ func (h *Hub) Start() {
 					}
 				}
 			case userId := <-h.invalidateUser:
+				channels.InvalidateUser(userId)
 				for _, webCon := range connections.ForUser(userId) {
 					webCon.InvalidateCache()
 				}
+				for _, webCon := range disconnected.ForUser(userId) {
+					webCon.InvalidateCache()
+				}
+				loadChannels()
+			case <-expireTicker.C:
+				expireBefore := model.GetMillis() - int64(WEBCONN_RECONNECT_WINDOW/time.Millisecond)
+
+				var expired []*WebConn
+				for _, webCon := range disconnected.All() {
+					if webCon.disconnectedAt < expireBefore {
+						expired = append(expired, webCon)
+					}
+				}
+				for _, webCon := range expired {
+					disconnected.Remove(webCon)
+					untrack(webCon.UserId)
+					connectionsChanged(webCon.UserId)
+				}
+
+				loadChannels()
+			case load := <-h.channelsLoaded:
+				if load.Err != nil {
+					mlog.Error("webhub.channelIndex: " + load.Err.Error())
+				}
+				channels.Loaded(load, model.GetMillis()+HUB_CHANNEL_LOAD_RETRY_TIME)
+				loadChannels()
+			case request := <-h.takeOver:
+				handOver := takeOver(request.UserId, request.ConnectionId)
+				if handOver != nil {
+					connectionsChanged(request.UserId)
+				}
+				request.Result <- handOver
+			case <-h.closeForRestart:
+				// The connections are unregistered as usual once their clients close them
+				for _, webCon := range connections.All() {
+					ws := webCon.WebSocket
+					h.app.Go(func() {
+						closeWebSocket(ws, websocket.CloseServiceRestart, model.WEBSOCKET_CLOSE_SERVER_RESTART)
+					})
+				}
+			case result := <-h.registryEntries:
+				entries := make(map[string]*webConnRegistryEntry)
+				for _, webCon := range connections.All() {
+					entries[webCon.UserId] = nil
+				}
+				for _, webCon := range disconnected.All() {
+					entries[webCon.UserId] = nil
+				}
+				for userId := range entries {
+					entries[userId] = registryEntry(userId)
+				}
+				result <- entries
+			case query := <-h.findConnection:
+				query.Found <- findWebConn(connections.ForUser(query.UserId), query.ConnectionId) != nil ||
+					findWebConn(disconnected.ForUser(query.UserId), query.ConnectionId) != nil
 			case activity := <-h.activity:
 				for _, webCon := range connections.ForUser(activity.UserId) {
 					if webCon.GetSessionToken() == activity.SessionToken {
 						webCon.LastUserActivityAt = activity.ActivityAt
 					}
 				}
-			case msg := <-h.broadcast:
+			case query := <-h.connections:
 				candidates := connections.All()
-				if msg.Broadcast.UserId != "" {
-					candidates = connections.ForUser(msg.Broadcast.UserId)
-				}
-				msg.PrecomputeJSON()
-				for _, webCon := range candidates {
-					if webCon.ShouldSendEvent(msg) {
-						select {
-						case webCon.Send <- msg:
-						default:
-							mlog.Error(fmt.Sprintf("webhub.broadcast: cannot send, closing websocket for userId=%v", webCon.UserId))
-							close(webCon.Send)
-							connections.Remove(webCon)
-						}
-					}
+				if query.UserId != "" {
+					candidates = connections.ForUser(query.UserId)
 				}
+
+				query.Result <- webConnInfos(candidates)
+			case msg := <-h.broadcastCritical:
+				broadcast(msg)
+			case msg := <-h.broadcast:
+				broadcast(msg)
+			case msg := <-h.broadcastLow:
+				broadcast(msg)
 			case <-h.stop:
 				userIds := make(map[string]bool)
 
@@ -468,7 +991,10 @@ func (h *Hub) Start() {
 				}
 
 				for userId := range userIds {
-					h.app.SetStatusOffline(userId, false)
+					// Users that are still connected to other servers in the cluster stay online
+					if len(h.app.webConnRegistry.ForUser(userId)) == 0 {
+						h.app.SetStatusOffline(userId, false)
+					}
 				}
 
 				h.ExplicitStop = true
@@ -501,6 +1027,25 @@ func (h *Hub) Start() {
 	go doRecoverableStart()
 }
 
+func findWebConn(webConns []*WebConn, id string) *WebConn {
+	for _, webConn := range webConns {
+		if webConn.Id == id {
+			return webConn
+		}
+	}
+
+	return nil
+}
+
+func webConnInfos(webConns []*WebConn) []*model.WebSocketConnectionInfo {
+	infos := make([]*model.WebSocketConnectionInfo, 0, len(webConns))
+	for _, webConn := range webConns {
+		infos = append(infos, webConn.Info())
+	}
+
+	return infos
+}
+
 type hubConnectionIndexIndexes struct {
 	connections         int
 	connectionsByUserId int
@@ -550,6 +1095,11 @@ func (i *hubConnectionIndex) Remove(wc *WebConn) {
 	delete(i.connectionIndexes, wc)
 }
 
+func (i *hubConnectionIndex) Has(wc *WebConn) bool {
+	_, ok := i.connectionIndexes[wc]
+	return ok
+}
+
 func (i *hubConnectionIndex) ForUser(id string) []*WebConn {
 	return i.connectionsByUserId[id]
 }
@@ -557,3 +1107,167 @@ func (i *hubConnectionIndex) ForUser(id string) []*WebConn {
 func (i *hubConnectionIndex) All() []*WebConn {
 	return i.connections
 }
+
+// hubChannelIndex tracks which channels the users connected to a hub are members of, so that events for a channel are
+// only checked against the connections of its members instead of every connection to the hub. The hub loads a user's
+// channels in the background when they connect and again after they're invalidated or become stale. Until then, the
+// user is treated as a member of every channel.
+type hubChannelIndex struct {
+	channelsByUserId map[string]map[string]string
+	usersByChannelId map[string]map[string]bool
+	loadedAt         map[string]int64
+	unloaded         map[string]bool
+	loading          map[string]int64
+	retryAt          map[string]int64
+	lastLoadId       int64
+}
+
+// hubChannelLoad is the loading of a user's channels that was started by a hubChannelIndex.
+type hubChannelLoad struct {
+	Index    *hubChannelIndex
+	LoadId   int64
+	UserId   string
+	Channels map[string]string
+	LoadedAt int64
+	Err      *model.AppError
+}
+
+func newHubChannelIndex() *hubChannelIndex {
+	return &hubChannelIndex{
+		channelsByUserId: make(map[string]map[string]string),
+		usersByChannelId: make(map[string]map[string]bool),
+		loadedAt:         make(map[string]int64),
+		unloaded:         make(map[string]bool),
+		loading:          make(map[string]int64),
+		retryAt:          make(map[string]int64),
+	}
+}
+
+// AddUser starts tracking the channels of a user that has connected to the hub.
+func (i *hubChannelIndex) AddUser(userId string) {
+	if userId == "" {
+		return
+	}
+
+	if _, ok := i.channelsByUserId[userId]; !ok {
+		i.unloaded[userId] = true
+	}
+}
+
+// RemoveUser stops tracking the channels of a user that no longer has any connections to the hub.
+func (i *hubChannelIndex) RemoveUser(userId string) {
+	i.forget(userId)
+	delete(i.unloaded, userId)
+	delete(i.loading, userId)
+	delete(i.retryAt, userId)
+}
+
+// InvalidateUser makes the user's channels be loaded again. Any load that's already in progress is ignored, since it
+// may have missed the change.
+func (i *hubChannelIndex) InvalidateUser(userId string) {
+	if _, ok := i.channelsByUserId[userId]; !ok && !i.unloaded[userId] {
+		return
+	}
+
+	i.forget(userId)
+	i.unloaded[userId] = true
+	delete(i.loading, userId)
+	delete(i.retryAt, userId)
+}
+
+// StartLoads returns the loads that should be started so that no more than max are in progress at once. Users whose
+// channels are unknown come first, followed by those whose channels were loaded before staleBefore, who keep their
+// channels until they're loaded again. Users whose last load failed are skipped until it's time to retry them.
+func (i *hubChannelIndex) StartLoads(now, staleBefore int64, max int) []*hubChannelLoad {
+	var loads []*hubChannelLoad
+
+	start := func(userId string) bool {
+		if len(i.loading) >= max {
+			return false
+		}
+
+		if _, ok := i.loading[userId]; !ok {
+			i.lastLoadId++
+			i.loading[userId] = i.lastLoadId
+			loads = append(loads, &hubChannelLoad{Index: i, LoadId: i.lastLoadId, UserId: userId})
+		}
+		return true
+	}
+
+	for userId := range i.unloaded {
+		if i.retryAt[userId] > now {
+			continue
+		}
+		if !start(userId) {
+			return loads
+		}
+	}
+
+	for userId, loadedAt := range i.loadedAt {
+		if loadedAt >= staleBefore || i.retryAt[userId] > now {
+			continue
+		}
+		if !start(userId) {
+			return loads
+		}
+	}
+
+	return loads
+}
+
+// Loaded records the result of a load that was started by the index, unless the load was since superseded. If the
+// load failed, the user's channels won't be loaded again until retryAt.
+func (i *hubChannelIndex) Loaded(load *hubChannelLoad, retryAt int64) {
+	if load.Index != i || i.loading[load.UserId] != load.LoadId {
+		return
+	}
+
+	delete(i.loading, load.UserId)
+
+	if load.Err != nil {
+		i.retryAt[load.UserId] = retryAt
+		return
+	}
+
+	i.forget(load.UserId)
+	delete(i.unloaded, load.UserId)
+	delete(i.retryAt, load.UserId)
+
+	i.channelsByUserId[load.UserId] = load.Channels
+	i.loadedAt[load.UserId] = load.LoadedAt
+
+	for channelId := range load.Channels {
+		if i.usersByChannelId[channelId] == nil {
+			i.usersByChannelId[channelId] = make(map[string]bool)
+		}
+		i.usersByChannelId[channelId][load.UserId] = true
+	}
+}
+
+// UsersInChannel returns the tracked users that are members of the channel. Users whose channels aren't known yet are
+// included as well, since it isn't known whether they're members.
+func (i *hubChannelIndex) UsersInChannel(channelId string) []string {
+	var userIds []string
+
+	for userId := range i.unloaded {
+		userIds = append(userIds, userId)
+	}
+
+	for userId := range i.usersByChannelId[channelId] {
+		userIds = append(userIds, userId)
+	}
+
+	return userIds
+}
+
+func (i *hubChannelIndex) forget(userId string) {
+	for channelId := range i.channelsByUserId[userId] {
+		delete(i.usersByChannelId[channelId], userId)
+		if len(i.usersByChannelId[channelId]) == 0 {
+			delete(i.usersByChannelId, channelId)
+		}
+	}
+
+	delete(i.channelsByUserId, userId)
+	delete(i.loadedAt, userId)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	goi18n "github.com/nicksnyder/go-i18n/i18n"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-server/model"
@@ -60,3 +61,140 @@ func TestHubStopWithMultipleConnections(t *testing.T) {
 	defer wc2.Close()
 	defer wc3.Close()
 }
+
+func TestHubLaneForEvent(t *testing.T) {
+	assert.Equal(t, HUB_LANE_CRITICAL, hubLaneForEvent(model.WEBSOCKET_EVENT_POSTED))
+	assert.Equal(t, HUB_LANE_CRITICAL, hubLaneForEvent(model.WEBSOCKET_EVENT_POST_EDITED))
+	assert.Equal(t, HUB_LANE_LOW, hubLaneForEvent(model.WEBSOCKET_EVENT_TYPING))
+	assert.Equal(t, HUB_LANE_LOW, hubLaneForEvent(model.WEBSOCKET_EVENT_STATUS_CHANGE))
+	assert.Equal(t, HUB_LANE_NORMAL, hubLaneForEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED))
+	assert.Equal(t, HUB_LANE_NORMAL, hubLaneForEvent(model.WEBSOCKET_EVENT_CHANNEL_VIEWED))
+}
+
+func TestHubBroadcastLanes(t *testing.T) {
+	a := &App{}
+	hub := a.NewWebHub()
+
+	for i := 0; i < BROADCAST_LOW_QUEUE_SIZE+10; i++ {
+		hub.Broadcast(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", model.NewId(), "", nil))
+	}
+	hub.Broadcast(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_POSTED, "", model.NewId(), "", nil))
+	hub.Broadcast(model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED, "", model.NewId(), "", nil))
+
+	assert.Equal(t, int64(10), hub.ShedCount(), "should drop typing events once their lane is full")
+	assert.Equal(t, map[string]int{
+		HUB_LANE_CRITICAL: 1,
+		HUB_LANE_NORMAL:   1,
+		HUB_LANE_LOW:      BROADCAST_LOW_QUEUE_SIZE,
+	}, hub.LaneQueueDepths())
+}
+
+func TestHubChannelIndex(t *testing.T) {
+	user1 := model.NewId()
+	user2 := model.NewId()
+	channel1 := model.NewId()
+	channel2 := model.NewId()
+
+	memberships := map[string]map[string]string{
+		user1: {channel1: "channel_user", channel2: "channel_user"},
+		user2: {channel2: "channel_user"},
+	}
+	failing := ""
+
+	index := newHubChannelIndex()
+
+	// finish completes the loads the way that the hub does
+	finish := func(loads []*hubChannelLoad, now int64) {
+		for _, load := range loads {
+			if load.UserId == failing {
+				load.Err = model.NewAppError("test", "test", nil, "", 500)
+			} else {
+				load.Channels = memberships[load.UserId]
+			}
+			load.LoadedAt = now
+			index.Loaded(load, now+HUB_CHANNEL_LOAD_RETRY_TIME)
+		}
+	}
+
+	index.AddUser(user1)
+	index.AddUser(user2)
+	index.AddUser("")
+
+	assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel1), "should include users whose channels aren't known yet")
+
+	loads := index.StartLoads(1000, 0, 10)
+	assert.Len(t, loads, 2)
+	assert.Empty(t, index.StartLoads(1000, 0, 10), "shouldn't start loads that are already in progress")
+	finish(loads, 1000)
+
+	assert.Equal(t, []string{user1}, index.UsersInChannel(channel1))
+	assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel2))
+	assert.Empty(t, index.UsersInChannel(model.NewId()))
+	assert.Empty(t, index.StartLoads(1000, 0, 10), "should only load each user's channels once")
+
+	t.Run("invalidated users are loaded again", func(t *testing.T) {
+		memberships[user2] = map[string]string{channel1: "channel_user"}
+		index.InvalidateUser(user2)
+
+		loads := index.StartLoads(2000, 0, 10)
+		require.Len(t, loads, 1)
+		assert.Equal(t, user2, loads[0].UserId)
+		finish(loads, 2000)
+
+		assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel1))
+		assert.Equal(t, []string{user1}, index.UsersInChannel(channel2))
+	})
+
+	t.Run("loads that were in progress when a user was invalidated are ignored", func(t *testing.T) {
+		loads := index.StartLoads(3000, 2001, 10)
+		require.Len(t, loads, 2)
+
+		index.InvalidateUser(user2)
+		finish(loads, 3000)
+
+		assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel2), "should still include user2 until it's loaded again")
+
+		finish(index.StartLoads(3000, 0, 10), 3000)
+		assert.Equal(t, []string{user1}, index.UsersInChannel(channel2))
+	})
+
+	t.Run("stale users are loaded again a few at a time", func(t *testing.T) {
+		loads := index.StartLoads(4000, 3001, 1)
+		require.Len(t, loads, 1)
+		assert.Empty(t, index.StartLoads(4000, 3001, 1))
+
+		assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel1), "should keep stale users' channels until they're loaded again")
+
+		finish(loads, 4000)
+		loads = index.StartLoads(4000, 3001, 1)
+		require.Len(t, loads, 1)
+		finish(loads, 4000)
+
+		assert.Empty(t, index.StartLoads(4000, 3001, 1))
+	})
+
+	t.Run("users that can't be loaded are always included and retried later", func(t *testing.T) {
+		failing = user2
+		index.InvalidateUser(user2)
+		finish(index.StartLoads(5000, 0, 10), 5000)
+
+		assert.ElementsMatch(t, []string{user1, user2}, index.UsersInChannel(channel2))
+		assert.Empty(t, index.StartLoads(5000, 0, 10), "shouldn't retry before the retry time")
+
+		failing = ""
+		loads := index.StartLoads(5000+HUB_CHANNEL_LOAD_RETRY_TIME, 0, 10)
+		require.Len(t, loads, 1)
+		finish(loads, 5000+HUB_CHANNEL_LOAD_RETRY_TIME)
+
+		assert.Equal(t, []string{user1}, index.UsersInChannel(channel2))
+	})
+
+	t.Run("removed users are no longer tracked", func(t *testing.T) {
+		index.RemoveUser(user1)
+		index.RemoveUser(user2)
+
+		assert.Empty(t, index.UsersInChannel(channel1))
+		assert.Empty(t, index.UsersInChannel(channel2))
+		assert.Empty(t, index.StartLoads(10000, 10000, 10))
+	})
+}
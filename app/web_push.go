@@ -0,0 +1,162 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+const (
+	WEB_PUSH_TTL_SECONDS       = 24 * 60 * 60
+	WEB_PUSH_VAPID_EXPIRY_TIME = 12 * time.Hour
+)
+
+func (a *App) RegisterWebPushSubscription(subscription *model.WebPushSubscription) (*model.WebPushSubscription, *model.AppError) {
+	if !*a.Config().EmailSettings.EnableWebPushNotifications {
+		return nil, model.NewAppError("RegisterWebPushSubscription", "api.web_push.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	// A browser only ever has one subscription per endpoint, so an existing one is either stale or was created
+	// by a session that has since been replaced.
+	if result := <-a.Srv.Store.WebPushSubscription().DeleteByEndpoint(subscription.Endpoint); result.Err != nil {
+		return nil, result.Err
+	}
+
+	subscription.Id = ""
+
+	if result := <-a.Srv.Store.WebPushSubscription().Save(subscription); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.WebPushSubscription), nil
+	}
+}
+
+func (a *App) GetWebPushSubscription(subscriptionId string) (*model.WebPushSubscription, *model.AppError) {
+	if result := <-a.Srv.Store.WebPushSubscription().Get(subscriptionId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.WebPushSubscription), nil
+	}
+}
+
+func (a *App) GetWebPushSubscriptionsForUser(userId string) ([]*model.WebPushSubscription, *model.AppError) {
+	if result := <-a.Srv.Store.WebPushSubscription().GetForUser(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.WebPushSubscription), nil
+	}
+}
+
+func (a *App) DeleteWebPushSubscription(subscriptionId string) *model.AppError {
+	if result := <-a.Srv.Store.WebPushSubscription().Delete(subscriptionId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// isPushProxyEnabled returns whether notifications for mobile devices can be sent through the configured push proxy.
+func (a *App) isPushProxyEnabled() bool {
+	if !*a.Config().EmailSettings.SendPushNotifications {
+		return false
+	}
+
+	pushServer := *a.Config().EmailSettings.PushNotificationServer
+	if license := a.License(); pushServer == model.MHPNS && (license == nil || !*license.Features.MHPNS) {
+		return false
+	}
+
+	return true
+}
+
+func (a *App) sendWebPushNotifications(userId string, msg model.PushNotification) {
+	subscriptions, err := a.GetWebPushSubscriptionsForUser(userId)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to get web push subscriptions err=%v", err), mlog.String("user_id", userId))
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		mlog.Debug(fmt.Sprintf("Sending web push notification to subscription %v for user %v", subscription.Id, userId), mlog.String("user_id", userId))
+
+		a.Go(func(subscription *model.WebPushSubscription) func() {
+			return func() {
+				a.sendToWebPushEndpoint(msg, subscription)
+			}
+		}(subscription))
+
+		if msg.Type == model.PUSH_TYPE_MESSAGE && a.Metrics != nil {
+			a.Metrics.IncrementPostSentPush()
+		}
+	}
+}
+
+func (a *App) sendToWebPushEndpoint(msg model.PushNotification, subscription *model.WebPushSubscription) {
+	msg.ServerId = a.DiagnosticId()
+
+	key := a.WebPushVapidKey()
+	if key == nil {
+		mlog.Error("Unable to send web push notification without a VAPID key", mlog.String("user_id", subscription.UserId))
+		return
+	}
+
+	p256dh, _ := base64.RawURLEncoding.DecodeString(subscription.P256dh)
+	auth, _ := base64.RawURLEncoding.DecodeString(subscription.Auth)
+
+	body, err := utils.EncryptWebPushPayload([]byte(msg.ToJson()), p256dh, auth)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to encrypt web push notification for SubscriptionId=%v err=%v", subscription.Id, err.Error()), mlog.String("user_id", subscription.UserId))
+		return
+	}
+
+	authorization, err := utils.CreateVapidAuthorization(subscription.Endpoint, *a.Config().ServiceSettings.SiteURL, key, time.Now().Add(WEB_PUSH_VAPID_EXPIRY_TIME))
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to sign web push notification for SubscriptionId=%v err=%v", subscription.Id, err.Error()), mlog.String("user_id", subscription.UserId))
+		return
+	}
+
+	request, err := http.NewRequest("POST", subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Unable to create web push request for SubscriptionId=%v err=%v", subscription.Id, err.Error()), mlog.String("user_id", subscription.UserId))
+		return
+	}
+
+	urgency := "normal"
+	if msg.Type == model.PUSH_TYPE_MESSAGE {
+		urgency = "high"
+	}
+
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", strconv.Itoa(WEB_PUSH_TTL_SECONDS))
+	request.Header.Set("Urgency", urgency)
+
+	// The endpoint is provided by the client, so it can't be trusted and mustn't be allowed to point inside the server's network.
+	resp, err := a.HTTPClient(false).Do(request)
+	if err != nil {
+		mlog.Error(fmt.Sprintf("Web push reported as error for UserId=%v SubscriptionId=%v message=%v", subscription.UserId, subscription.Id, err.Error()), mlog.String("user_id", subscription.UserId))
+		return
+	}
+	consumeAndClose(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		mlog.Info(fmt.Sprintf("Web push subscription was reported as expired for UserId=%v SubscriptionId=%v removing it", subscription.UserId, subscription.Id), mlog.String("user_id", subscription.UserId))
+		if err := a.DeleteWebPushSubscription(subscription.Id); err != nil {
+			mlog.Error(fmt.Sprintf("Unable to remove web push subscription err=%v", err), mlog.String("user_id", subscription.UserId))
+		}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		mlog.Error(fmt.Sprintf("Web push reported as error for UserId=%v SubscriptionId=%v status=%v", subscription.UserId, subscription.Id, resp.StatusCode), mlog.String("user_id", subscription.UserId))
+	}
+}
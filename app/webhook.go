@@ -4,8 +4,8 @@
 package app
 
 import (
+	"bytes"
 	"fmt"
-	"io"
 	"net/http"
 	"regexp"
 	"strings"
@@ -27,45 +27,21 @@ func (a *App) handleWebhookEvents(post *model.Post, team *model.Team, channel *m
 		return nil
 	}
 
-	if channel.Type != model.CHANNEL_OPEN {
-		return nil
-	}
-
-	hchan := a.Srv.Store.Webhook().GetOutgoingByTeam(team.Id, -1, -1)
-	result := <-hchan
-	if result.Err != nil {
-		return result.Err
-	}
-
-	hooks := result.Data.([]*model.OutgoingWebhook)
-	if len(hooks) == 0 {
-		return nil
-	}
-
-	var firstWord, triggerWord string
-
-	splitWords := strings.Fields(post.Message)
-	if len(splitWords) > 0 {
-		firstWord = splitWords[0]
+	hooks, err := a.getOutgoingWebhooksForChannel(channel)
+	if err != nil {
+		return err
 	}
 
-	relevantHooks := []*model.OutgoingWebhook{}
 	for _, hook := range hooks {
-		if hook.ChannelId == post.ChannelId || len(hook.ChannelId) == 0 {
-			if hook.ChannelId == post.ChannelId && len(hook.TriggerWords) == 0 {
-				relevantHooks = append(relevantHooks, hook)
-				triggerWord = ""
-			} else if hook.TriggerWhen == TRIGGERWORDS_EXACT_MATCH && hook.TriggerWordExactMatch(firstWord) {
-				relevantHooks = append(relevantHooks, hook)
-				triggerWord = hook.GetTriggerWord(firstWord, true)
-			} else if hook.TriggerWhen == TRIGGERWORDS_STARTS_WITH && hook.TriggerWordStartsWith(firstWord) {
-				relevantHooks = append(relevantHooks, hook)
-				triggerWord = hook.GetTriggerWord(firstWord, false)
-			}
+		if !hook.HasTriggerEvent(model.OUTGOING_HOOK_EVENT_POSTED) {
+			continue
+		}
+
+		matched, triggerWord := matchOutgoingWebhookTriggerWord(hook, post)
+		if !matched {
+			continue
 		}
-	}
 
-	for _, hook := range relevantHooks {
 		payload := &model.OutgoingWebhookPayload{
 			Token:       hook.Token,
 			TeamId:      hook.TeamId,
@@ -79,6 +55,7 @@ func (a *App) handleWebhookEvents(post *model.Post, team *model.Team, channel *m
 			Text:        post.Message,
 			TriggerWord: triggerWord,
 			FileIds:     strings.Join(post.FileIds, ","),
+			Event:       model.OUTGOING_HOOK_EVENT_POSTED,
 		}
 		a.Go(func(hook *model.OutgoingWebhook) func() {
 			return func() {
@@ -90,24 +67,103 @@ func (a *App) handleWebhookEvents(post *model.Post, team *model.Team, channel *m
 	return nil
 }
 
+// matchOutgoingWebhookTriggerWord returns true if a post should trigger a hook, along with the trigger word that it
+// starts with. Hooks that are scoped to a channel without any trigger words are triggered by every post in it.
+func matchOutgoingWebhookTriggerWord(hook *model.OutgoingWebhook, post *model.Post) (bool, string) {
+	if hook.ChannelId != post.ChannelId && len(hook.ChannelId) != 0 {
+		return false, ""
+	}
+
+	if hook.ChannelId == post.ChannelId && len(hook.TriggerWords) == 0 {
+		return true, ""
+	}
+
+	var firstWord string
+	if splitWords := strings.Fields(post.Message); len(splitWords) > 0 {
+		firstWord = splitWords[0]
+	}
+
+	if hook.TriggerWhen == TRIGGERWORDS_EXACT_MATCH && hook.TriggerWordExactMatch(firstWord) {
+		return true, hook.GetTriggerWord(firstWord, true)
+	} else if hook.TriggerWhen == TRIGGERWORDS_STARTS_WITH && hook.TriggerWordStartsWith(firstWord) {
+		return true, hook.GetTriggerWord(firstWord, false)
+	}
+
+	return false, ""
+}
+
+// getOutgoingWebhooksForChannel returns the hooks that can be triggered by what happens in a channel. Hooks in public
+// channels may be scoped to the whole team, so the ones that are scoped to other channels still need to be filtered out.
+func (a *App) getOutgoingWebhooksForChannel(channel *model.Channel) ([]*model.OutgoingWebhook, *model.AppError) {
+	if channel.Type != model.CHANNEL_OPEN {
+		return a.getPrivateOutgoingWebhooks(channel)
+	}
+
+	result := <-a.Srv.Store.Webhook().GetOutgoingByTeam(channel.TeamId, -1, -1)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return result.Data.([]*model.OutgoingWebhook), nil
+}
+
+// getPrivateOutgoingWebhooks returns the outgoing webhooks that are triggered by posts in a private channel, direct
+// message or group message. Unlike in public channels, hooks must be scoped to the channel, and they stop being
+// triggered once their creator leaves it so that they can't be used to read channels that they no longer belong to.
+func (a *App) getPrivateOutgoingWebhooks(channel *model.Channel) ([]*model.OutgoingWebhook, *model.AppError) {
+	result := <-a.Srv.Store.Webhook().GetOutgoingByChannel(channel.Id, -1, -1)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	var hooks []*model.OutgoingWebhook
+	for _, hook := range result.Data.([]*model.OutgoingWebhook) {
+		if result := <-a.Srv.Store.Channel().GetMember(channel.Id, hook.CreatorId); result.Err != nil {
+			continue
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, nil
+}
+
+// checkOutgoingWebhookChannel checks that an outgoing webhook can be scoped to a channel. Hooks can only be added to
+// private channels, direct messages and group messages that their creator belongs to.
+func (a *App) checkOutgoingWebhookChannel(where string, channel *model.Channel, teamId string, creatorId string) *model.AppError {
+	switch channel.Type {
+	case model.CHANNEL_OPEN, model.CHANNEL_PRIVATE:
+		if channel.TeamId != teamId {
+			return model.NewAppError(where, "api.webhook.create_outgoing.permissions.app_error", nil, "", http.StatusForbidden)
+		}
+	}
+
+	if channel.Type != model.CHANNEL_OPEN {
+		if result := <-a.Srv.Store.Channel().GetMember(channel.Id, creatorId); result.Err != nil {
+			return model.NewAppError(where, "api.webhook.create_outgoing.not_member.app_error", nil, "", http.StatusForbidden)
+		}
+	}
+
+	return nil
+}
+
+// TriggerWebhook sends a payload to a hook's callback URLs and posts their responses in the channel. The post is nil for
+// events that aren't about a post that can still be replied to, in which case responses are never posted as comments.
 func (a *App) TriggerWebhook(payload *model.OutgoingWebhookPayload, hook *model.OutgoingWebhook, post *model.Post, channel *model.Channel) {
-	var body io.Reader
+	var body []byte
 	var contentType string
 	if hook.ContentType == "application/json" {
-		body = strings.NewReader(payload.ToJSON())
+		body = []byte(payload.ToJSON())
 		contentType = "application/json"
 	} else {
-		body = strings.NewReader(payload.ToFormValues())
+		body = []byte(payload.ToFormValues())
 		contentType = "application/x-www-form-urlencoded"
 	}
 
 	for _, url := range hook.CallbackURLs {
 		a.Go(func(url string) func() {
 			return func() {
-				req, _ := http.NewRequest("POST", url, body)
-				req.Header.Set("Content-Type", contentType)
-				req.Header.Set("Accept", "application/json")
-				if resp, err := a.HTTPClient(false).Do(req); err != nil {
+				if resp, err := a.deliverOutgoingWebhook(hook, url, contentType, body, payload.PostId); err != nil {
 					mlog.Error(fmt.Sprintf("Event POST failed, err=%s", err.Error()))
 				} else {
 					defer consumeAndClose(resp)
@@ -116,7 +172,7 @@ func (a *App) TriggerWebhook(payload *model.OutgoingWebhookPayload, hook *model.
 
 					if webhookResp != nil && (webhookResp.Text != nil || len(webhookResp.Attachments) > 0) {
 						postRootId := ""
-						if webhookResp.ResponseType == model.OUTGOING_HOOK_RESPONSE_TYPE_COMMENT {
+						if webhookResp.ResponseType == model.OUTGOING_HOOK_RESPONSE_TYPE_COMMENT && post != nil {
 							postRootId = post.Id
 						}
 						if len(webhookResp.Props) == 0 {
@@ -231,6 +287,27 @@ func SplitWebhookPost(post *model.Post, maxPostSize int) ([]*model.Post, *model.
 }
 
 func (a *App) CreateWebhookPost(userId string, channel *model.Channel, text, overrideUsername, overrideIconUrl string, props model.StringInterface, postType string, postRootId string) (*model.Post, *model.AppError) {
+	splits, err := a.buildWebhookPosts(userId, channel, text, overrideUsername, overrideIconUrl, props, postType, postRootId)
+	if err != nil {
+		return nil, err
+	}
+
+	if metrics := a.Metrics; metrics != nil {
+		metrics.IncrementWebhookPost()
+	}
+
+	for _, split := range splits {
+		if _, err := a.CreatePostMissingChannel(split, false); err != nil {
+			return nil, model.NewAppError("CreateWebhookPost", "api.post.create_webhook_post.creating.app_error", nil, "err="+err.Message, http.StatusInternalServerError)
+		}
+	}
+
+	return splits[0], nil
+}
+
+// buildWebhookPosts returns the posts that a webhook's message is posted as, which is more than one if the message is
+// too long for a single post.
+func (a *App) buildWebhookPosts(userId string, channel *model.Channel, text, overrideUsername, overrideIconUrl string, props model.StringInterface, postType string, postRootId string) ([]*model.Post, *model.AppError) {
 	// parse links into Markdown format
 	linkWithTextRegex := regexp.MustCompile(`<([^\n<\|>]+)\|([^\n>]+)>`)
 	text = linkWithTextRegex.ReplaceAllString(text, "[${2}](${1})")
@@ -243,10 +320,6 @@ func (a *App) CreateWebhookPost(userId string, channel *model.Channel, text, ove
 		return nil, err
 	}
 
-	if metrics := a.Metrics; metrics != nil {
-		metrics.IncrementWebhookPost()
-	}
-
 	if a.Config().ServiceSettings.EnablePostUsernameOverride {
 		if len(overrideUsername) != 0 {
 			post.AddProp("override_username", overrideUsername)
@@ -273,18 +346,7 @@ func (a *App) CreateWebhookPost(userId string, channel *model.Channel, text, ove
 		}
 	}
 
-	splits, err := SplitWebhookPost(post, a.MaxPostSize())
-	if err != nil {
-		return nil, err
-	}
-
-	for _, split := range splits {
-		if _, err := a.CreatePostMissingChannel(split, false); err != nil {
-			return nil, model.NewAppError("CreateWebhookPost", "api.post.create_webhook_post.creating.app_error", nil, "err="+err.Message, http.StatusInternalServerError)
-		}
-	}
-
-	return splits[0], nil
+	return SplitWebhookPost(post, a.MaxPostSize())
 }
 
 func (a *App) CreateIncomingWebhookForChannel(creatorId string, channel *model.Channel, hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.AppError) {
@@ -409,14 +471,10 @@ func (a *App) CreateOutgoingWebhook(hook *model.OutgoingWebhook) (*model.Outgoin
 			channel = result.Data.(*model.Channel)
 		}
 
-		if channel.Type != model.CHANNEL_OPEN {
-			return nil, model.NewAppError("CreateOutgoingWebhook", "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusForbidden)
-		}
-
-		if channel.Type != model.CHANNEL_OPEN || channel.TeamId != hook.TeamId {
-			return nil, model.NewAppError("CreateOutgoingWebhook", "api.webhook.create_outgoing.permissions.app_error", nil, "", http.StatusForbidden)
+		if err := a.checkOutgoingWebhookChannel("CreateOutgoingWebhook", channel, hook.TeamId, hook.CreatorId); err != nil {
+			return nil, err
 		}
-	} else if len(hook.TriggerWords) == 0 {
+	} else if len(hook.TriggerWords) == 0 && hook.HasTriggerEvent(model.OUTGOING_HOOK_EVENT_POSTED) {
 		return nil, model.NewAppError("CreateOutgoingWebhook", "api.webhook.create_outgoing.triggers.app_error", nil, "", http.StatusBadRequest)
 	}
 
@@ -453,14 +511,10 @@ func (a *App) UpdateOutgoingWebhook(oldHook, updatedHook *model.OutgoingWebhook)
 			return nil, err
 		}
 
-		if channel.Type != model.CHANNEL_OPEN {
-			return nil, model.NewAppError("UpdateOutgoingWebhook", "api.webhook.create_outgoing.not_open.app_error", nil, "", http.StatusForbidden)
-		}
-
-		if channel.TeamId != oldHook.TeamId {
-			return nil, model.NewAppError("UpdateOutgoingWebhook", "api.webhook.create_outgoing.permissions.app_error", nil, "", http.StatusForbidden)
+		if err := a.checkOutgoingWebhookChannel("UpdateOutgoingWebhook", channel, oldHook.TeamId, oldHook.CreatorId); err != nil {
+			return nil, err
 		}
-	} else if len(updatedHook.TriggerWords) == 0 {
+	} else if len(updatedHook.TriggerWords) == 0 && updatedHook.HasTriggerEvent(model.OUTGOING_HOOK_EVENT_POSTED) {
 		return nil, model.NewAppError("UpdateOutgoingWebhook", "api.webhook.create_outgoing.triggers.app_error", nil, "", http.StatusInternalServerError)
 	}
 
@@ -484,6 +538,7 @@ func (a *App) UpdateOutgoingWebhook(oldHook, updatedHook *model.OutgoingWebhook)
 	updatedHook.CreateAt = oldHook.CreateAt
 	updatedHook.DeleteAt = oldHook.DeleteAt
 	updatedHook.TeamId = oldHook.TeamId
+	updatedHook.SigningSecret = oldHook.SigningSecret
 	updatedHook.UpdateAt = model.GetMillis()
 
 	if result = <-a.Srv.Store.Webhook().UpdateOutgoing(updatedHook); result.Err != nil {
@@ -567,20 +622,52 @@ func (a *App) RegenOutgoingWebhookToken(hook *model.OutgoingWebhook) (*model.Out
 	}
 }
 
-func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookRequest) *model.AppError {
+// RegenOutgoingWebhookSigningSecret replaces the secret that a hook's requests are signed with.
+func (a *App) RegenOutgoingWebhookSigningSecret(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.AppError) {
+	if !a.Config().ServiceSettings.EnableOutgoingWebhooks {
+		return nil, model.NewAppError("RegenOutgoingWebhookSigningSecret", "api.outgoing_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	hook.SigningSecret = model.NewOutgoingWebhookSigningSecret()
+
+	if result := <-a.Srv.Store.Webhook().UpdateOutgoing(hook); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.OutgoingWebhook), nil
+	}
+}
+
+// DecodeIncomingWebhookRequest decodes the JSON payload of a request to an incoming webhook. Payloads for hooks with a
+// payload template can be in any format since they're rendered by the template, and all others must be webhook
+// requests. Hooks that can't be found are left for HandleIncomingWebhook to report.
+func (a *App) DecodeIncomingWebhookRequest(hookId string, data []byte) (*model.IncomingWebhookRequest, *model.AppError) {
+	if hook, err := a.GetIncomingWebhook(hookId); err == nil && hook.PayloadTemplate != "" {
+		return hook.RenderPayloadTemplate(data)
+	}
+
+	return model.IncomingWebhookRequestFromJson(bytes.NewReader(data))
+}
+
+// HandleIncomingWebhook creates a post from a request to an incoming webhook, or changes a post that the hook created
+// before if the request has a post id. The post that was created, updated or deleted is returned.
+func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookRequest) (*model.Post, *model.AppError) {
 	if !a.Config().ServiceSettings.EnableIncomingWebhooks {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
 
 	hchan := a.Srv.Store.Webhook().GetIncoming(hookId, true)
 
 	if req == nil {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.parse.app_error", nil, "", http.StatusBadRequest)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.parse.app_error", nil, "", http.StatusBadRequest)
 	}
 
 	text := req.Text
-	if len(text) == 0 && req.Attachments == nil {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.text.app_error", nil, "", http.StatusBadRequest)
+	if len(text) == 0 && req.Attachments == nil && !req.Delete {
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.text.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if req.Delete && len(req.PostId) == 0 {
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.delete.app_error", nil, "", http.StatusBadRequest)
 	}
 
 	channelName := req.ChannelName
@@ -588,11 +675,15 @@ func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookReq
 
 	var hook *model.IncomingWebhook
 	if result := <-hchan; result.Err != nil {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.invalid.app_error", nil, "err="+result.Err.Message, http.StatusBadRequest)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.invalid.app_error", nil, "err="+result.Err.Message, http.StatusBadRequest)
 	} else {
 		hook = result.Data.(*model.IncomingWebhook)
 	}
 
+	if err := a.CheckIncomingWebhookRateLimit(hook); err != nil {
+		return nil, err
+	}
+
 	uchan := a.Srv.Store.User().Get(hook.UserId)
 
 	if len(req.Props) == 0 {
@@ -600,6 +691,7 @@ func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookReq
 	}
 
 	req.Props["webhook_display_name"] = hook.DisplayName
+	req.Props[model.POST_PROPS_WEBHOOK_ID] = hook.Id
 
 	text = a.ProcessSlackText(text)
 	req.Attachments = a.ProcessSlackAttachments(req.Attachments)
@@ -609,16 +701,30 @@ func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookReq
 		webhookType = model.POST_SLACK_ATTACHMENT
 	}
 
+	overrideUsername := hook.Username
+	if req.Username != "" {
+		overrideUsername = req.Username
+	}
+
+	overrideIconUrl := hook.IconURL
+	if req.IconURL != "" {
+		overrideIconUrl = req.IconURL
+	}
+
+	if len(req.PostId) > 0 {
+		return a.changeIncomingWebhookPost(hook, req, text, overrideUsername, overrideIconUrl, webhookType)
+	}
+
 	var channel *model.Channel
 	var cchan store.StoreChannel
 
 	if len(channelName) != 0 {
 		if channelName[0] == '@' {
 			if result := <-a.Srv.Store.User().GetByUsername(channelName[1:]); result.Err != nil {
-				return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.user.app_error", nil, "err="+result.Err.Message, http.StatusBadRequest)
+				return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.user.app_error", nil, "err="+result.Err.Message, http.StatusBadRequest)
 			} else {
 				if ch, err := a.GetDirectChannel(hook.UserId, result.Data.(*model.User).Id); err != nil {
-					return err
+					return nil, err
 				} else {
 					channel = ch
 				}
@@ -635,44 +741,70 @@ func (a *App) HandleIncomingWebhook(hookId string, req *model.IncomingWebhookReq
 	if channel == nil {
 		result := <-cchan
 		if result.Err != nil {
-			return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.channel.app_error", nil, "err="+result.Err.Message, result.Err.StatusCode)
+			return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.channel.app_error", nil, "err="+result.Err.Message, result.Err.StatusCode)
 		} else {
 			channel = result.Data.(*model.Channel)
 		}
 	}
 
 	if hook.ChannelLocked && hook.ChannelId != channel.Id {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.channel_locked.app_error", nil, "", http.StatusForbidden)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.channel_locked.app_error", nil, "", http.StatusForbidden)
 	}
 
 	var user *model.User
 	if result := <-uchan; result.Err != nil {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.user.app_error", nil, "err="+result.Err.Message, http.StatusForbidden)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.user.app_error", nil, "err="+result.Err.Message, http.StatusForbidden)
 	} else {
 		user = result.Data.(*model.User)
 	}
 
 	if a.License() != nil && *a.Config().TeamSettings.ExperimentalTownSquareIsReadOnly &&
 		channel.Name == model.DEFAULT_CHANNEL && !a.RolesGrantPermission(user.GetRoles(), model.PERMISSION_MANAGE_SYSTEM.Id) {
-		return model.NewAppError("HandleIncomingWebhook", "api.post.create_post.town_square_read_only", nil, "", http.StatusForbidden)
+		return nil, model.NewAppError("HandleIncomingWebhook", "api.post.create_post.town_square_read_only", nil, "", http.StatusForbidden)
 	}
 
 	if channel.Type != model.CHANNEL_OPEN && !a.HasPermissionToChannel(hook.UserId, channel.Id, model.PERMISSION_READ_CHANNEL) {
-		return model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.permissions.app_error", nil, "", http.StatusForbidden)
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.permissions.app_error", nil, "", http.StatusForbidden)
 	}
 
-	overrideUsername := hook.Username
-	if req.Username != "" {
-		overrideUsername = req.Username
+	return a.CreateWebhookPost(hook.UserId, channel, text, overrideUsername, overrideIconUrl, req.Props, webhookType, "")
+}
+
+// changeIncomingWebhookPost updates or deletes a post that an incoming webhook created. Posts created by other hooks
+// or users can't be found this way.
+func (a *App) changeIncomingWebhookPost(hook *model.IncomingWebhook, req *model.IncomingWebhookRequest, text, overrideUsername, overrideIconUrl, postType string) (*model.Post, *model.AppError) {
+	post, err := a.GetSinglePost(req.PostId)
+	if err != nil || post.UserId != hook.UserId || post.Props[model.POST_PROPS_WEBHOOK_ID] != hook.Id {
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.post.app_error", nil, "post_id="+req.PostId, http.StatusNotFound)
 	}
 
-	overrideIconUrl := hook.IconURL
-	if req.IconURL != "" {
-		overrideIconUrl = req.IconURL
+	channel, err := a.GetChannel(post.ChannelId)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := a.CreateWebhookPost(hook.UserId, channel, text, overrideUsername, overrideIconUrl, req.Props, webhookType, "")
-	return err
+	if channel.Type != model.CHANNEL_OPEN && !a.HasPermissionToChannel(hook.UserId, channel.Id, model.PERMISSION_READ_CHANNEL) {
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.permissions.app_error", nil, "", http.StatusForbidden)
+	}
+
+	if req.Delete {
+		return a.DeletePost(post.Id, hook.UserId)
+	}
+
+	splits, err := a.buildWebhookPosts(hook.UserId, channel, text, overrideUsername, overrideIconUrl, req.Props, postType, post.RootId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Updates can't add posts to split long messages into, so they have to fit into the one being updated
+	if len(splits) > 1 {
+		return nil, model.NewAppError("HandleIncomingWebhook", "web.incoming_webhook.update_too_long.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	post.Message = splits[0].Message
+	post.Props = splits[0].Props
+
+	return a.UpdatePost(post, false)
 }
 
 func (a *App) CreateCommandWebhook(commandId string, args *model.CommandArgs) (*model.CommandWebhook, *model.AppError) {
@@ -718,10 +850,16 @@ func (a *App) HandleCommandWebhook(hookId string, response *model.CommandRespons
 		ParentId:  hook.ParentId,
 	}
 
-	if result := <-a.Srv.Store.CommandWebhook().TryUse(hook.Id, 5); result.Err != nil {
+	// Updates to the last ephemeral response don't count towards the webhook's limit since they don't add to the
+	// responses that the user sees, which lets long running commands report their progress as often as they need to
+	if hook.EphemeralPostId != "" && response.UpdatesOriginal() {
+		if response.AppendOriginal && utf8.RuneCountInString(hook.EphemeralMessage)+utf8.RuneCountInString(response.Text) >= model.POST_MESSAGE_MAX_RUNES_V2 {
+			return model.NewAppError("HandleCommandWebhook", "web.command_webhook.append_too_long.app_error", nil, "", http.StatusBadRequest)
+		}
+	} else if result := <-a.Srv.Store.CommandWebhook().TryUse(hook.Id, 5); result.Err != nil {
 		return model.NewAppError("HandleCommandWebhook", "web.command_webhook.invalid.app_error", nil, "err="+result.Err.Message, result.Err.StatusCode)
 	}
 
-	_, err := a.HandleCommandResponse(cmd, args, response, false)
+	_, err := a.handleCommandResponse(cmd, args, response, false, hook)
 	return err
 }
@@ -651,3 +651,60 @@ func TestTriggerOutGoingWebhookWithUsernameAndIconURL(t *testing.T) {
 	}
 
 }
+
+func TestPrivateOutgoingWebhooks(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableOutgoingWebhooks = true })
+
+	privateChannel := th.CreatePrivateChannel(th.BasicTeam)
+
+	hook := &model.OutgoingWebhook{
+		ChannelId:    privateChannel.Id,
+		TeamId:       th.BasicTeam.Id,
+		CallbackURLs: []string{"http://nowhere.com"},
+		CreatorId:    th.BasicUser2.Id,
+	}
+
+	_, err := th.App.CreateOutgoingWebhook(hook)
+	require.NotNil(t, err, "shouldn't be able to add a hook to a private channel that the creator isn't in")
+
+	hook.CreatorId = th.BasicUser.Id
+	privateHook, err := th.App.CreateOutgoingWebhook(hook)
+	require.Nil(t, err)
+
+	// Hooks that aren't scoped to a channel are only triggered in public channels
+	_, err = th.App.CreateOutgoingWebhook(&model.OutgoingWebhook{
+		TeamId:       th.BasicTeam.Id,
+		CallbackURLs: []string{"http://nowhere.com"},
+		CreatorId:    th.BasicUser.Id,
+		TriggerWords: []string{"cats"},
+	})
+	require.Nil(t, err)
+
+	hooks, err := th.App.getPrivateOutgoingWebhooks(privateChannel)
+	require.Nil(t, err)
+	require.Len(t, hooks, 1)
+	assert.Equal(t, privateHook.Id, hooks[0].Id)
+
+	groupChannel := th.CreateGroupChannel(th.BasicUser2, th.CreateUser())
+	_, err = th.App.CreateOutgoingWebhook(&model.OutgoingWebhook{
+		ChannelId:    groupChannel.Id,
+		TeamId:       th.BasicTeam.Id,
+		CallbackURLs: []string{"http://nowhere.com"},
+		CreatorId:    th.BasicUser.Id,
+	})
+	require.Nil(t, err)
+
+	hooks, err = th.App.getPrivateOutgoingWebhooks(groupChannel)
+	require.Nil(t, err)
+	assert.Len(t, hooks, 1)
+
+	result := <-th.App.Srv.Store.Channel().RemoveMember(privateChannel.Id, th.BasicUser.Id)
+	require.Nil(t, result.Err)
+
+	hooks, err = th.App.getPrivateOutgoingWebhooks(privateChannel)
+	require.Nil(t, err)
+	assert.Len(t, hooks, 0, "hooks should stop being triggered once their creator leaves the channel")
+}
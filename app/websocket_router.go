@@ -51,9 +51,15 @@ func (wr *WebSocketRouter) ServeWebSocket(conn *WebConn, r *model.WebSocketReque
 
 		session, err := wr.app.GetSession(token)
 
-		if err != nil {
+		if err != nil || !session.HasOAuthScope(model.DEFAULT_SCOPE) {
 			conn.WebSocket.Close()
 		} else {
+			if reason := wr.app.CheckWebSocketConnectionQuota(session.UserId); reason != "" {
+				mlog.Info(fmt.Sprintf("websocket connection rejected: user_id=%v reason=%v", session.UserId, reason))
+				RejectWebSocketConnection(conn.WebSocket, reason)
+				return
+			}
+
 			wr.app.Go(func() {
 				wr.app.SetStatusOnline(session.UserId, false)
 				wr.app.UpdateLastActivityAtIfNeeded(*session)
@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package channelsnoozes
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type ChannelSnoozesJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsChannelSnoozesJobInterface(func(a *app.App) tjobs.ChannelSnoozesJobInterface {
+		return &ChannelSnoozesJobInterfaceImpl{a}
+	})
+}
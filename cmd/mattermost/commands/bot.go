@@ -0,0 +1,243 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost-server/app"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const BOT_LIST_PAGE_SIZE = 200
+
+var BotCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Management of bots",
+}
+
+var BotCreateCmd = &cobra.Command{
+	Use:     "create [username]",
+	Short:   "Create a bot",
+	Long:    "Create a bot that's owned by a user. Bots can only authenticate with access tokens.",
+	Example: `  bot create mybot --owner user@example.com --display-name "My Bot" --description "Posts build results"`,
+	RunE:    botCreateCmdF,
+}
+
+var BotListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List bots",
+	Long:    "List the bots on the system.",
+	Example: "  bot list --all",
+	RunE:    botListCmdF,
+}
+
+var BotDisableCmd = &cobra.Command{
+	Use:     "disable [bots]",
+	Short:   "Disable bots",
+	Long:    "Disable bots. Their access tokens stop working until they're enabled again.",
+	Example: "  bot disable mybot",
+	RunE:    botDisableCmdF,
+}
+
+var BotEnableCmd = &cobra.Command{
+	Use:     "enable [bots]",
+	Short:   "Enable bots",
+	Long:    "Enable bots that were disabled.",
+	Example: "  bot enable mybot",
+	RunE:    botEnableCmdF,
+}
+
+var BotAssignCmd = &cobra.Command{
+	Use:     "assign [bot] [user]",
+	Short:   "Assign a bot to another owner",
+	Long:    "Make a user the owner of a bot.",
+	Example: "  bot assign mybot user@example.com",
+	RunE:    botAssignCmdF,
+}
+
+func init() {
+	BotCreateCmd.Flags().String("owner", "", "The user who owns the bot. Required.")
+	BotCreateCmd.Flags().String("display-name", "", "The bot's display name.")
+	BotCreateCmd.Flags().String("description", "", "A description of what the bot does.")
+
+	BotListCmd.Flags().Bool("all", false, "Include disabled bots.")
+	BotListCmd.Flags().Bool("orphaned", false, "Only list bots whose owners have been deactivated.")
+
+	BotCmd.AddCommand(
+		BotCreateCmd,
+		BotListCmd,
+		BotDisableCmd,
+		BotEnableCmd,
+		BotAssignCmd,
+	)
+	RootCmd.AddCommand(BotCmd)
+}
+
+func getBotFromBotArg(a *app.App, botArg string) *model.Bot {
+	user := getUserFromUserArg(a, botArg)
+	if user == nil || !user.IsBot {
+		return nil
+	}
+
+	bot, err := a.GetBot(user.Id, true)
+	if err != nil {
+		return nil
+	}
+
+	return bot
+}
+
+func printBot(bot *model.Bot, owner *model.User) {
+	ownerName := bot.OwnerId
+	if owner != nil {
+		ownerName = owner.Username
+	}
+
+	status := ""
+	if bot.DeleteAt != 0 {
+		status = " (disabled)"
+	}
+
+	CommandPrettyPrintln(fmt.Sprintf("%v: %v (owner: %v)%v", bot.UserId, bot.Username, ownerName, status))
+}
+
+func botCreateCmdF(command *cobra.Command, args []string) error {
+	a, err := InitDBCommandContextCobra(command)
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	if len(args) != 1 {
+		return errors.New("Expected one argument. See help text for details.")
+	}
+
+	ownerArg, _ := command.Flags().GetString("owner")
+	if ownerArg == "" {
+		return errors.New("Owner is required.")
+	}
+
+	owner := getUserFromUserArg(a, ownerArg)
+	if owner == nil {
+		return errors.New("Unable to find user '" + ownerArg + "'")
+	}
+
+	displayName, _ := command.Flags().GetString("display-name")
+	description, _ := command.Flags().GetString("description")
+
+	bot, appErr := a.CreateBot(&model.Bot{
+		Username:    args[0],
+		DisplayName: displayName,
+		Description: description,
+		OwnerId:     owner.Id,
+	})
+	if appErr != nil {
+		return errors.New("Unable to create bot. Error: " + appErr.Error())
+	}
+
+	printBot(bot, owner)
+
+	return nil
+}
+
+func botListCmdF(command *cobra.Command, args []string) error {
+	a, err := InitDBCommandContextCobra(command)
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	all, _ := command.Flags().GetBool("all")
+	orphaned, _ := command.Flags().GetBool("orphaned")
+
+	for page := 0; ; page++ {
+		bots, appErr := a.GetBots(&model.BotGetOptions{
+			IncludeDeleted: all,
+			OnlyOrphaned:   orphaned,
+			Page:           page,
+			PerPage:        BOT_LIST_PAGE_SIZE,
+		})
+		if appErr != nil {
+			return errors.New("Unable to list bots. Error: " + appErr.Error())
+		}
+
+		for _, bot := range bots {
+			owner, _ := a.GetUser(bot.OwnerId)
+			printBot(bot, owner)
+		}
+
+		if len(bots) < BOT_LIST_PAGE_SIZE {
+			return nil
+		}
+	}
+}
+
+func botDisableCmdF(command *cobra.Command, args []string) error {
+	return updateBotsActive(command, args, false)
+}
+
+func botEnableCmdF(command *cobra.Command, args []string) error {
+	return updateBotsActive(command, args, true)
+}
+
+func updateBotsActive(command *cobra.Command, args []string, active bool) error {
+	a, err := InitDBCommandContextCobra(command)
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one bot.")
+	}
+
+	for _, botArg := range args {
+		bot := getBotFromBotArg(a, botArg)
+		if bot == nil {
+			CommandPrintErrorln("Unable to find bot '" + botArg + "'")
+			continue
+		}
+
+		if _, appErr := a.UpdateBotActive(bot.UserId, active); appErr != nil {
+			CommandPrintErrorln("Unable to update bot '" + botArg + "'. Error: " + appErr.Error())
+		}
+	}
+
+	return nil
+}
+
+func botAssignCmdF(command *cobra.Command, args []string) error {
+	a, err := InitDBCommandContextCobra(command)
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	if len(args) != 2 {
+		return errors.New("Expected two arguments. See help text for details.")
+	}
+
+	bot := getBotFromBotArg(a, args[0])
+	if bot == nil {
+		return errors.New("Unable to find bot '" + args[0] + "'")
+	}
+
+	owner := getUserFromUserArg(a, args[1])
+	if owner == nil {
+		return errors.New("Unable to find user '" + args[1] + "'")
+	}
+
+	bot, appErr := a.UpdateBotOwner(bot.UserId, owner.Id)
+	if appErr != nil {
+		return errors.New("Unable to assign bot. Error: " + appErr.Error())
+	}
+
+	printBot(bot, owner)
+
+	return nil
+}
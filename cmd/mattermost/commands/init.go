@@ -26,6 +26,8 @@ func InitDBCommandContextCobra(command *cobra.Command) (*app.App, error) {
 
 	a.DoAdvancedPermissionsMigration()
 	a.DoEmojisPermissionsMigration()
+	a.DoPrivateOutgoingWebhooksPermissionsMigration()
+	a.DoBotsPermissionsMigration()
 
 	return a, nil
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var LinkMetadataCmd = &cobra.Command{
+	Use:   "linkmetadata",
+	Short: "Management of stored link previews",
+}
+
+var LinkMetadataPurgeCmd = &cobra.Command{
+	Use:     "purge [domains]",
+	Short:   "Purge link previews for domains",
+	Long:    "Remove the stored previews of every link to the given domains so that they're fetched again the next time that they're posted.",
+	Example: "  linkmetadata purge example.com docs.example.com",
+	RunE:    linkMetadataPurgeCmdF,
+}
+
+func init() {
+	LinkMetadataCmd.AddCommand(
+		LinkMetadataPurgeCmd,
+	)
+	RootCmd.AddCommand(LinkMetadataCmd)
+}
+
+func linkMetadataPurgeCmdF(command *cobra.Command, args []string) error {
+	a, err := InitDBCommandContextCobra(command)
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one domain.")
+	}
+
+	for _, domain := range args {
+		count, err := a.PurgeLinkMetadataForDomain(domain)
+		if err != nil {
+			return errors.New("Unable to purge link previews for '" + domain + "'. Error: " + err.Error())
+		}
+
+		CommandPrettyPrintln(fmt.Sprintf("Purged %d link previews for %s", count, domain))
+	}
+
+	return nil
+}
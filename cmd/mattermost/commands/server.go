@@ -97,6 +97,8 @@ func runServer(configFileLocation string, disableConfigWatch bool, usedPlatform
 
 	a.DoAdvancedPermissionsMigration()
 	a.DoEmojisPermissionsMigration()
+	a.DoPrivateOutgoingWebhooksPermissionsMigration()
+	a.DoBotsPermissionsMigration()
 
 	a.InitPlugins(*a.Config().PluginSettings.Directory, *a.Config().PluginSettings.ClientDirectory)
 	a.AddConfigListener(func(prevCfg, cfg *model.Config) {
@@ -161,6 +163,15 @@ func runServer(configFileLocation string, disableConfigWatch bool, usedPlatform
 	a.Go(func() {
 		runCommandWebhookCleanupJob(a)
 	})
+	a.Go(func() {
+		runIntegrationDeliveryCleanupJob(a)
+	})
+	a.Go(func() {
+		runPluginKeyValueCleanupJob(a)
+	})
+	a.Go(func() {
+		runAutoResponderScheduleJob(a)
+	})
 
 	if complianceI := a.Compliance; complianceI != nil {
 		complianceI.StartComplianceDailyJob()
@@ -179,13 +190,13 @@ func runServer(configFileLocation string, disableConfigWatch bool, usedPlatform
 		a.StartElasticsearch()
 	}
 
-	if *a.Config().JobSettings.RunJobs {
+	runJobs := *a.Config().JobSettings.RunJobs
+	if runJobs {
 		a.Jobs.StartWorkers()
-		defer a.Jobs.StopWorkers()
 	}
-	if *a.Config().JobSettings.RunScheduler {
+	runScheduler := *a.Config().JobSettings.RunScheduler
+	if runScheduler {
 		a.Jobs.StartSchedulers()
-		defer a.Jobs.StopSchedulers()
 	}
 
 	notifyReady()
@@ -195,6 +206,17 @@ func runServer(configFileLocation string, disableConfigWatch bool, usedPlatform
 	signal.Notify(interruptChan, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	<-interruptChan
 
+	// Finish the requests that are in progress while the rest of the cluster can still be told about their changes,
+	// and then let the jobs that are running record how they ended before the workers stop
+	a.StopServer()
+
+	if runScheduler {
+		a.Jobs.StopSchedulers()
+	}
+	if runJobs {
+		a.Jobs.StopWorkers()
+	}
+
 	if a.Cluster != nil {
 		a.Cluster.StopInterNodeCommunication()
 	}
@@ -234,6 +256,20 @@ func runCommandWebhookCleanupJob(a *app.App) {
 	}, time.Hour*1)
 }
 
+func runIntegrationDeliveryCleanupJob(a *app.App) {
+	a.TrimIntegrationDeliveries()
+	model.CreateRecurringTask("Integration Delivery Cleanup", func() {
+		a.TrimIntegrationDeliveries()
+	}, time.Minute*10)
+}
+
+func runPluginKeyValueCleanupJob(a *app.App) {
+	a.DeleteExpiredPluginKeys()
+	model.CreateRecurringTask("Plugin Key Value Cleanup", func() {
+		a.DeleteExpiredPluginKeys()
+	}, time.Hour*1)
+}
+
 func runSessionCleanupJob(a *app.App) {
 	doSessionCleanup(a)
 	model.CreateRecurringTask("Session Cleanup", func() {
@@ -241,6 +277,13 @@ func runSessionCleanupJob(a *app.App) {
 	}, time.Hour*24)
 }
 
+func runAutoResponderScheduleJob(a *app.App) {
+	doAutoResponderSchedules(a)
+	model.CreateRecurringTask("Auto Responder Schedules", func() {
+		doAutoResponderSchedules(a)
+	}, time.Minute*1)
+}
+
 func resetStatuses(a *app.App) {
 	if result := <-a.Srv.Store.Status().ResetAll(); result.Err != nil {
 		mlog.Error(fmt.Sprint("mattermost.reset_status.error FIXME: NOT FOUND IN TRANSLATIONS FILE", result.Err.Error()))
@@ -294,6 +337,12 @@ func doCommandWebhookCleanup(a *app.App) {
 	a.Srv.Store.CommandWebhook().Cleanup()
 }
 
+func doAutoResponderSchedules(a *app.App) {
+	if err := a.ApplyAutoResponderSchedules(); err != nil {
+		mlog.Error("Failed to apply auto responder schedules", mlog.Err(err))
+	}
+}
+
 func doSessionCleanup(a *app.App) {
 	a.Srv.Store.Session().Cleanup(model.GetMillis(), SESSIONS_CLEANUP_BATCH_SIZE)
 }
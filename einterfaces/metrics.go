@@ -18,6 +18,10 @@ type MetricsInterface interface {
 	IncrementHttpError()
 	ObserveHttpRequestDuration(elapsed float64)
 
+	IncrementIntegrationHttpRequest(integrationType string)
+	IncrementIntegrationHttpError(integrationType string)
+	ObserveIntegrationHttpRequestDuration(integrationType string, elapsed float64)
+
 	IncrementClusterRequest()
 	ObserveClusterRequestDuration(elapsed float64)
 	IncrementClusterEventType(eventType string)
@@ -37,10 +41,20 @@ type MetricsInterface interface {
 
 	IncrementWebsocketEvent(eventType string)
 	IncrementWebSocketBroadcast(eventType string)
+	IncrementWebsocketEventShed(eventType string)
+	SetWebsocketHubLaneQueueDepth(lane string, depth float64)
+	ObserveWebsocketSendQueueDepth(depth float64)
+	IncrementWebsocketSlowClientDisconnect()
 
 	AddMemCacheHitCounter(cacheName string, amount float64)
 	AddMemCacheMissCounter(cacheName string, amount float64)
 
 	IncrementPostsSearchCounter()
 	ObservePostsSearchDuration(elapsed float64)
+
+	ObservePerformanceTiming(stage string, elapsed float64)
+
+	ObserveStoreMethodDuration(method string, elapsed float64)
+	AddStoreMethodRowCount(method string, rows float64)
+	IncrementStoreMethodError(method string)
 }
@@ -0,0 +1,14 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package einterfaces
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// SmsInterface delivers text messages through an SMS gateway. When one isn't registered, messages are sent through
+// the Twilio-compatible API configured in SmsSettings.
+type SmsInterface interface {
+	SendSms(phoneNumber string, message string) *model.AppError
+}
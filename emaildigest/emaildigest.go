@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package emaildigest
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type EmailDigestJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsEmailDigestJobInterface(func(a *app.App) tjobs.EmailDigestJobInterface {
+		return &EmailDigestJobInterfaceImpl{a}
+	})
+}
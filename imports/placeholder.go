@@ -6,5 +6,12 @@ package imports
 // This is a placeholder so this package can be imported in Team Edition when it will be otherwise empty
 
 import (
+	_ "github.com/mattermost/mattermost-server/channelsnoozes"
+	_ "github.com/mattermost/mattermost-server/emaildigest"
+	_ "github.com/mattermost/mattermost-server/inactiveusers"
+	_ "github.com/mattermost/mattermost-server/linkmetadata"
 	_ "github.com/mattermost/mattermost-server/migrations"
+	_ "github.com/mattermost/mattermost-server/rediscluster"
+	_ "github.com/mattermost/mattermost-server/reminders"
+	_ "github.com/mattermost/mattermost-server/teamdeletion"
 )
@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package inactiveusers
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type InactiveUsersJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsInactiveUsersJobInterface(func(a *app.App) tjobs.InactiveUsersJobInterface {
+		return &InactiveUsersJobInterfaceImpl{a}
+	})
+}
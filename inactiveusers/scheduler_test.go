@@ -0,0 +1,35 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package inactiveusers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestNextScheduleTime(t *testing.T) {
+	scheduler := &Scheduler{}
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	*cfg.InactiveUserSettings.JobStartTime = "04:00"
+
+	now := time.Date(2018, 6, 1, 2, 30, 0, 0, time.Local)
+	nextTime := scheduler.NextScheduleTime(cfg, now, false, nil)
+	require.NotNil(t, nextTime)
+	assert.Equal(t, time.Date(2018, 6, 1, 4, 0, 0, 0, time.Local), *nextTime)
+
+	now = time.Date(2018, 6, 1, 4, 0, 0, 0, time.Local)
+	nextTime = scheduler.NextScheduleTime(cfg, now, false, nil)
+	require.NotNil(t, nextTime)
+	assert.Equal(t, time.Date(2018, 6, 2, 4, 0, 0, 0, time.Local), *nextTime)
+
+	*cfg.InactiveUserSettings.JobStartTime = "junk"
+	assert.Nil(t, scheduler.NextScheduleTime(cfg, now, false, nil))
+}
@@ -0,0 +1,11 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package interfaces
+
+import "github.com/mattermost/mattermost-server/model"
+
+type EmailDigestJobInterface interface {
+	MakeWorker() model.Worker
+	MakeScheduler() model.Scheduler
+}
@@ -74,6 +74,9 @@ func (srv *JobServer) SetJobSuccess(job *model.Job) *model.AppError {
 func (srv *JobServer) SetJobError(job *model.Job, jobError *model.AppError) *model.AppError {
 	if jobError == nil {
 		result := <-srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_ERROR)
+		if result.Err == nil {
+			srv.notifyJobFailed(job, nil)
+		}
 		return result.Err
 	}
 
@@ -98,9 +101,17 @@ func (srv *JobServer) SetJobError(job *model.Job, jobError *model.AppError) *mod
 		}
 	}
 
+	srv.notifyJobFailed(job, jobError)
+
 	return nil
 }
 
+func (srv *JobServer) notifyJobFailed(job *model.Job, jobError *model.AppError) {
+	if srv.OnJobFailed != nil {
+		srv.OnJobFailed(job, jobError)
+	}
+}
+
 func (srv *JobServer) SetJobCanceled(job *model.Job) *model.AppError {
 	result := <-srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_CANCELED)
 	return result.Err
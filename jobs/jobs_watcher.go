@@ -114,6 +114,48 @@ func (watcher *Watcher) PollAndNotify() {
 					default:
 					}
 				}
+			} else if job.Type == model.JOB_TYPE_EMAIL_DIGEST {
+				if watcher.workers.EmailDigest != nil {
+					select {
+					case watcher.workers.EmailDigest.JobChannel() <- *job:
+					default:
+					}
+				}
+			} else if job.Type == model.JOB_TYPE_INACTIVE_USERS {
+				if watcher.workers.InactiveUsers != nil {
+					select {
+					case watcher.workers.InactiveUsers.JobChannel() <- *job:
+					default:
+					}
+				}
+			} else if job.Type == model.JOB_TYPE_TEAM_DELETION {
+				if watcher.workers.TeamDeletion != nil {
+					select {
+					case watcher.workers.TeamDeletion.JobChannel() <- *job:
+					default:
+					}
+				}
+			} else if job.Type == model.JOB_TYPE_LINK_METADATA_CLEANUP {
+				if watcher.workers.LinkMetadataCleanup != nil {
+					select {
+					case watcher.workers.LinkMetadataCleanup.JobChannel() <- *job:
+					default:
+					}
+				}
+			} else if job.Type == model.JOB_TYPE_REMINDERS {
+				if watcher.workers.Reminders != nil {
+					select {
+					case watcher.workers.Reminders.JobChannel() <- *job:
+					default:
+					}
+				}
+			} else if job.Type == model.JOB_TYPE_CHANNEL_SNOOZES {
+				if watcher.workers.ChannelSnoozes != nil {
+					select {
+					case watcher.workers.ChannelSnoozes.JobChannel() <- *job:
+					default:
+					}
+				}
 			}
 		}
 	}
@@ -56,6 +56,30 @@ func (srv *JobServer) InitSchedulers() *Schedulers {
 		schedulers.schedulers = append(schedulers.schedulers, migrationsInterface.MakeScheduler())
 	}
 
+	if emailDigestInterface := srv.EmailDigest; emailDigestInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, emailDigestInterface.MakeScheduler())
+	}
+
+	if inactiveUsersInterface := srv.InactiveUsers; inactiveUsersInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, inactiveUsersInterface.MakeScheduler())
+	}
+
+	if teamDeletionInterface := srv.TeamDeletion; teamDeletionInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, teamDeletionInterface.MakeScheduler())
+	}
+
+	if linkMetadataCleanupInterface := srv.LinkMetadataCleanup; linkMetadataCleanupInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, linkMetadataCleanupInterface.MakeScheduler())
+	}
+
+	if remindersInterface := srv.Reminders; remindersInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, remindersInterface.MakeScheduler())
+	}
+
+	if channelSnoozesInterface := srv.ChannelSnoozes; channelSnoozesInterface != nil {
+		schedulers.schedulers = append(schedulers.schedulers, channelSnoozesInterface.MakeScheduler())
+	}
+
 	schedulers.nextRunTimes = make([]*time.Time, len(schedulers.schedulers))
 	return schedulers
 }
@@ -36,6 +36,15 @@ type JobServer struct {
 	ElasticsearchIndexer    ejobs.ElasticsearchIndexerInterface
 	LdapSync                ejobs.LdapSyncInterface
 	Migrations              tjobs.MigrationsJobInterface
+	EmailDigest             tjobs.EmailDigestJobInterface
+	InactiveUsers           tjobs.InactiveUsersJobInterface
+	TeamDeletion            tjobs.TeamDeletionJobInterface
+	LinkMetadataCleanup     tjobs.LinkMetadataCleanupJobInterface
+	Reminders               tjobs.RemindersJobInterface
+	ChannelSnoozes          tjobs.ChannelSnoozesJobInterface
+
+	// OnJobFailed is called, if it's set, with each job that's marked as having failed.
+	OnJobFailed func(job *model.Job, jobError *model.AppError)
 }
 
 func NewJobServer(configService ConfigService, store store.Store) *JobServer {
@@ -21,6 +21,12 @@ type Workers struct {
 	ElasticsearchAggregation model.Worker
 	LdapSync                 model.Worker
 	Migrations               model.Worker
+	EmailDigest              model.Worker
+	InactiveUsers            model.Worker
+	TeamDeletion             model.Worker
+	LinkMetadataCleanup      model.Worker
+	Reminders                model.Worker
+	ChannelSnoozes           model.Worker
 
 	listenerId string
 }
@@ -55,6 +61,30 @@ func (srv *JobServer) InitWorkers() *Workers {
 		workers.Migrations = migrationsInterface.MakeWorker()
 	}
 
+	if emailDigestInterface := srv.EmailDigest; emailDigestInterface != nil {
+		workers.EmailDigest = emailDigestInterface.MakeWorker()
+	}
+
+	if inactiveUsersInterface := srv.InactiveUsers; inactiveUsersInterface != nil {
+		workers.InactiveUsers = inactiveUsersInterface.MakeWorker()
+	}
+
+	if teamDeletionInterface := srv.TeamDeletion; teamDeletionInterface != nil {
+		workers.TeamDeletion = teamDeletionInterface.MakeWorker()
+	}
+
+	if linkMetadataCleanupInterface := srv.LinkMetadataCleanup; linkMetadataCleanupInterface != nil {
+		workers.LinkMetadataCleanup = linkMetadataCleanupInterface.MakeWorker()
+	}
+
+	if remindersInterface := srv.Reminders; remindersInterface != nil {
+		workers.Reminders = remindersInterface.MakeWorker()
+	}
+
+	if channelSnoozesInterface := srv.ChannelSnoozes; channelSnoozesInterface != nil {
+		workers.ChannelSnoozes = channelSnoozesInterface.MakeWorker()
+	}
+
 	return workers
 }
 
@@ -86,6 +116,30 @@ func (workers *Workers) Start() *Workers {
 			go workers.Migrations.Run()
 		}
 
+		if workers.EmailDigest != nil && *workers.ConfigService.Config().EmailSettings.EnableEmailDigests {
+			go workers.EmailDigest.Run()
+		}
+
+		if workers.InactiveUsers != nil && *workers.ConfigService.Config().InactiveUserSettings.EnableInactiveUserDetection {
+			go workers.InactiveUsers.Run()
+		}
+
+		if workers.TeamDeletion != nil {
+			go workers.TeamDeletion.Run()
+		}
+
+		if workers.LinkMetadataCleanup != nil {
+			go workers.LinkMetadataCleanup.Run()
+		}
+
+		if workers.Reminders != nil {
+			go workers.Reminders.Run()
+		}
+
+		if workers.ChannelSnoozes != nil {
+			go workers.ChannelSnoozes.Run()
+		}
+
 		go workers.Watcher.Start()
 	})
 
@@ -136,6 +190,22 @@ func (workers *Workers) handleConfigChange(oldConfig *model.Config, newConfig *m
 			workers.LdapSync.Stop()
 		}
 	}
+
+	if workers.EmailDigest != nil {
+		if !*oldConfig.EmailSettings.EnableEmailDigests && *newConfig.EmailSettings.EnableEmailDigests {
+			go workers.EmailDigest.Run()
+		} else if *oldConfig.EmailSettings.EnableEmailDigests && !*newConfig.EmailSettings.EnableEmailDigests {
+			workers.EmailDigest.Stop()
+		}
+	}
+
+	if workers.InactiveUsers != nil {
+		if !*oldConfig.InactiveUserSettings.EnableInactiveUserDetection && *newConfig.InactiveUserSettings.EnableInactiveUserDetection {
+			go workers.InactiveUsers.Run()
+		} else if *oldConfig.InactiveUserSettings.EnableInactiveUserDetection && !*newConfig.InactiveUserSettings.EnableInactiveUserDetection {
+			workers.InactiveUsers.Stop()
+		}
+	}
 }
 
 func (workers *Workers) Stop() *Workers {
@@ -167,6 +237,30 @@ func (workers *Workers) Stop() *Workers {
 		workers.Migrations.Stop()
 	}
 
+	if workers.EmailDigest != nil && *workers.ConfigService.Config().EmailSettings.EnableEmailDigests {
+		workers.EmailDigest.Stop()
+	}
+
+	if workers.InactiveUsers != nil && *workers.ConfigService.Config().InactiveUserSettings.EnableInactiveUserDetection {
+		workers.InactiveUsers.Stop()
+	}
+
+	if workers.TeamDeletion != nil {
+		workers.TeamDeletion.Stop()
+	}
+
+	if workers.LinkMetadataCleanup != nil {
+		workers.LinkMetadataCleanup.Stop()
+	}
+
+	if workers.Reminders != nil {
+		workers.Reminders.Stop()
+	}
+
+	if workers.ChannelSnoozes != nil {
+		workers.ChannelSnoozes.Stop()
+	}
+
 	mlog.Info("Stopped workers")
 
 	return workers
@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package linkmetadata
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type LinkMetadataCleanupJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsLinkMetadataCleanupJobInterface(func(a *app.App) tjobs.LinkMetadataCleanupJobInterface {
+		return &LinkMetadataCleanupJobInterfaceImpl{a}
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package linkmetadata
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-server/app"
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	LINK_METADATA_CLEANUP_SCHEDULE_INTERVAL = 24 * time.Hour
+)
+
+type Scheduler struct {
+	App *app.App
+}
+
+func (m *LinkMetadataCleanupJobInterfaceImpl) MakeScheduler() model.Scheduler {
+	return &Scheduler{m.App}
+}
+
+func (scheduler *Scheduler) Name() string {
+	return "LinkMetadataCleanupScheduler"
+}
+
+func (scheduler *Scheduler) JobType() string {
+	return model.JOB_TYPE_LINK_METADATA_CLEANUP
+}
+
+func (scheduler *Scheduler) Enabled(cfg *model.Config) bool {
+	return true
+}
+
+func (scheduler *Scheduler) NextScheduleTime(cfg *model.Config, now time.Time, pendingJobs bool, lastSuccessfulJob *model.Job) *time.Time {
+	nextTime := time.Now().Add(LINK_METADATA_CLEANUP_SCHEDULE_INTERVAL)
+	return &nextTime
+}
+
+func (scheduler *Scheduler) ScheduleJob(cfg *model.Config, pendingJobs bool, lastSuccessfulJob *model.Job) (*model.Job, *model.AppError) {
+	mlog.Debug("Scheduling Job", mlog.String("scheduler", scheduler.Name()))
+
+	// Don't queue up another run while the previous one is still waiting to be picked up.
+	if pendingJobs {
+		return nil, nil
+	}
+
+	if job, err := scheduler.App.Jobs.CreateJob(model.JOB_TYPE_LINK_METADATA_CLEANUP, map[string]string{}); err != nil {
+		return nil, err
+	} else {
+		return job, nil
+	}
+}
@@ -137,6 +137,17 @@ func (l *Logger) StdLogWriter() io.Writer {
 	return &loggerWriter{f}
 }
 
+// Tee returns a logger that also writes every entry to w as a line of JSON, regardless of the logger's levels.
+func (l *Logger) Tee(w io.Writer) *Logger {
+	core := zapcore.NewCore(makeEncoder(true), zapcore.AddSync(w), zapcore.DebugLevel)
+
+	newlogger := *l
+	newlogger.zap = newlogger.zap.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, core)
+	}))
+	return &newlogger
+}
+
 func (l *Logger) WithCallerSkip(skip int) *Logger {
 	newlogger := *l
 	newlogger.zap = newlogger.zap.WithOptions(zap.AddCallerSkip(skip))
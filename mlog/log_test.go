@@ -0,0 +1,35 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package mlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	logger := NewLogger(&LoggerConfiguration{
+		EnableConsole: false,
+		EnableFile:    false,
+	})
+
+	var buffer bytes.Buffer
+	teeLogger := logger.With(String("plugin_id", "foo")).Tee(&buffer)
+
+	teeLogger.Debug("debug message", Int("count", 3))
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "debug message", entry["msg"])
+	assert.Equal(t, float64(3), entry["count"])
+
+	buffer.Reset()
+	logger.Info("not teed")
+	assert.Empty(t, buffer.String())
+}
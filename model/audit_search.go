@@ -0,0 +1,148 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	AUDIT_SEARCH_MAX_PER_PAGE = 1000
+	AUDIT_EXPORT_BATCH_SIZE   = 500
+
+	AUDIT_EXPORT_FORMAT_CSV  = "csv"
+	AUDIT_EXPORT_FORMAT_JSON = "json"
+
+	// Audits are recorded with the path of the API request as their action, so the type of object that an audit
+	// targets is the first segment of that path after the API root, e.g. "users" for "/api/v4/users/login".
+	AUDIT_TARGET_TYPE_PREFIX = API_URL_SUFFIX + "/"
+
+	AUDIT_ACTION_MAX_LENGTH = 512
+)
+
+var validAuditTargetType = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// AuditCsvHeader lists the columns written when exporting audits as CSV.
+var AuditCsvHeader = []string{"id", "create_at", "user_id", "action", "target_type", "extra_info", "ip_address", "session_id"}
+
+// AuditCursor identifies the last audit of a page. Audits are returned newest first, so the next page holds the
+// audits that sort after it.
+type AuditCursor struct {
+	CreateAt int64
+	Id       string
+}
+
+func (c *AuditCursor) String() string {
+	return strconv.FormatInt(c.CreateAt, 10) + ":" + c.Id
+}
+
+// AuditCursorFromString parses a cursor returned by AuditCursor.String.
+func AuditCursorFromString(value string) (*AuditCursor, bool) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || !IsValidId(parts[1]) {
+		return nil, false
+	}
+
+	createAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || createAt < 0 {
+		return nil, false
+	}
+
+	return &AuditCursor{CreateAt: createAt, Id: parts[1]}, true
+}
+
+// AuditSearchOptions filters the audits returned by the audits endpoint. Since and Until are inclusive bounds on
+// CreateAt and are ignored when zero.
+type AuditSearchOptions struct {
+	UserId     string
+	Action     string
+	TargetType string
+	Since      int64
+	Until      int64
+	Cursor     *AuditCursor
+}
+
+func (o *AuditSearchOptions) IsValid() *AppError {
+	if o.UserId != "" && !IsValidId(o.UserId) {
+		return NewAppError("AuditSearchOptions.IsValid", "model.audit_search.is_valid.user_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if len(o.Action) > AUDIT_ACTION_MAX_LENGTH {
+		return NewAppError("AuditSearchOptions.IsValid", "model.audit_search.is_valid.action.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.TargetType != "" && !validAuditTargetType.MatchString(o.TargetType) {
+		return NewAppError("AuditSearchOptions.IsValid", "model.audit_search.is_valid.target_type.app_error", nil, "target_type="+o.TargetType, http.StatusBadRequest)
+	}
+
+	if o.Since < 0 || o.Until < 0 || (o.Until > 0 && o.Since > o.Until) {
+		return NewAppError("AuditSearchOptions.IsValid", "model.audit_search.is_valid.date_range.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// ToQueryString encodes the options as the query parameters accepted by the audits endpoint.
+func (o *AuditSearchOptions) ToQueryString() string {
+	query := url.Values{}
+
+	if o.UserId != "" {
+		query.Set("user_id", o.UserId)
+	}
+
+	if o.Action != "" {
+		query.Set("action", o.Action)
+	}
+
+	if o.TargetType != "" {
+		query.Set("target_type", o.TargetType)
+	}
+
+	if o.Since > 0 {
+		query.Set("since", strconv.FormatInt(o.Since, 10))
+	}
+
+	if o.Until > 0 {
+		query.Set("until", strconv.FormatInt(o.Until, 10))
+	}
+
+	if o.Cursor != nil {
+		query.Set("cursor", o.Cursor.String())
+	}
+
+	return query.Encode()
+}
+
+// TargetType returns the type of object that the audit's action was performed on, or an empty string if the
+// action isn't an API request.
+func (o *Audit) TargetType() string {
+	if !strings.HasPrefix(o.Action, AUDIT_TARGET_TYPE_PREFIX) {
+		return ""
+	}
+
+	targetType := strings.TrimPrefix(o.Action, AUDIT_TARGET_TYPE_PREFIX)
+	if index := strings.Index(targetType, "/"); index != -1 {
+		targetType = targetType[:index]
+	}
+
+	return targetType
+}
+
+// ToCsvRecord returns the audit's fields in the order given by AuditCsvHeader.
+func (o *Audit) ToCsvRecord() []string {
+	return []string{
+		o.Id,
+		strconv.FormatInt(o.CreateAt, 10),
+		o.UserId,
+		o.Action,
+		o.TargetType(),
+		o.ExtraInfo,
+		o.IpAddress,
+		o.SessionId,
+	}
+}
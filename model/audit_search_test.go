@@ -0,0 +1,54 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditCursor(t *testing.T) {
+	cursor := &AuditCursor{CreateAt: 1234, Id: NewId()}
+
+	parsed, ok := AuditCursorFromString(cursor.String())
+	require.True(t, ok)
+	assert.Equal(t, cursor, parsed)
+
+	for _, value := range []string{"", "1234", "abc:" + NewId(), "-1:" + NewId(), "1234:junk"} {
+		_, ok := AuditCursorFromString(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestAuditSearchOptionsIsValid(t *testing.T) {
+	assert.Nil(t, (&AuditSearchOptions{}).IsValid())
+	assert.Nil(t, (&AuditSearchOptions{UserId: NewId(), Action: "/api/v4/users/login", TargetType: "oauth_apps", Since: 1, Until: 2}).IsValid())
+
+	assert.NotNil(t, (&AuditSearchOptions{UserId: "junk"}).IsValid())
+	assert.NotNil(t, (&AuditSearchOptions{TargetType: "users%"}).IsValid())
+	assert.NotNil(t, (&AuditSearchOptions{Since: 2, Until: 1}).IsValid())
+	assert.NotNil(t, (&AuditSearchOptions{Since: -1}).IsValid())
+}
+
+func TestAuditSearchOptionsToQueryString(t *testing.T) {
+	options := &AuditSearchOptions{UserId: NewId(), Action: "/api/v4/users/login", Since: 5, Cursor: &AuditCursor{CreateAt: 10, Id: NewId()}}
+
+	query, err := url.ParseQuery(options.ToQueryString())
+	require.Nil(t, err)
+	assert.Equal(t, options.UserId, query.Get("user_id"))
+	assert.Equal(t, options.Action, query.Get("action"))
+	assert.Equal(t, "5", query.Get("since"))
+	assert.Equal(t, options.Cursor.String(), query.Get("cursor"))
+	assert.Empty(t, query.Get("until"))
+	assert.Empty(t, query.Get("target_type"))
+}
+
+func TestAuditTargetType(t *testing.T) {
+	assert.Equal(t, "users", (&Audit{Action: "/api/v4/users/login"}).TargetType())
+	assert.Equal(t, "audits", (&Audit{Action: "/api/v4/audits"}).TargetType())
+	assert.Equal(t, "", (&Audit{Action: "cli"}).TargetType())
+}
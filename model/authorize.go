@@ -4,9 +4,13 @@
 package model
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net/http"
+	"regexp"
 )
 
 const (
@@ -14,8 +18,18 @@ const (
 	AUTHCODE_RESPONSE_TYPE = "code"
 	IMPLICIT_RESPONSE_TYPE = "token"
 	DEFAULT_SCOPE          = "user"
+
+	// Methods that clients can use to derive a PKCE code challenge from its verifier, as described in RFC 7636
+	PKCE_METHOD_PLAIN = "plain"
+	PKCE_METHOD_S256  = "S256"
+
+	PKCE_CODE_MIN_LENGTH = 43
+	PKCE_CODE_MAX_LENGTH = 128
 )
 
+// Code verifiers and challenges may only use unreserved URL characters
+var validPKCECode = regexp.MustCompile(`^[A-Za-z0-9\-._~]+$`)
+
 type AuthData struct {
 	ClientId    string `json:"client_id"`
 	UserId      string `json:"user_id"`
@@ -25,6 +39,9 @@ type AuthData struct {
 	RedirectUri string `json:"redirect_uri"`
 	State       string `json:"state"`
 	Scope       string `json:"scope"`
+
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
 }
 
 type AuthorizeRequest struct {
@@ -33,6 +50,9 @@ type AuthorizeRequest struct {
 	RedirectUri  string `json:"redirect_uri"`
 	Scope        string `json:"scope"`
 	State        string `json:"state"`
+
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
 }
 
 // IsValid validates the AuthData and returns an error if it isn't configured
@@ -71,6 +91,11 @@ func (ad *AuthData) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
 	}
 
+	if err := isValidCodeChallenge(ad.CodeChallenge, ad.CodeChallengeMethod); err != nil {
+		err.DetailedError = "client_id=" + ad.ClientId
+		return err
+	}
+
 	return nil
 }
 
@@ -98,9 +123,39 @@ func (ar *AuthorizeRequest) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
 	}
 
+	if err := isValidCodeChallenge(ar.CodeChallenge, ar.CodeChallengeMethod); err != nil {
+		err.DetailedError = "client_id=" + ar.ClientId
+		return err
+	}
+
+	return nil
+}
+
+func isValidCodeChallenge(challenge, method string) *AppError {
+	if len(challenge) == 0 {
+		if len(method) != 0 {
+			return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "", http.StatusBadRequest)
+		}
+		return nil
+	}
+
+	if !IsValidPKCECode(challenge) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// Clients that don't give a method use the plain one
+	if method != "" && method != PKCE_METHOD_PLAIN && method != PKCE_METHOD_S256 {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge_method.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
+// IsValidPKCECode returns true if s can be used as a PKCE code verifier or code challenge.
+func IsValidPKCECode(s string) bool {
+	return len(s) >= PKCE_CODE_MIN_LENGTH && len(s) <= PKCE_CODE_MAX_LENGTH && validPKCECode.MatchString(s)
+}
+
 func (ad *AuthData) PreSave() {
 	if ad.ExpiresIn == 0 {
 		ad.ExpiresIn = AUTHCODE_EXPIRE_TIME
@@ -113,6 +168,10 @@ func (ad *AuthData) PreSave() {
 	if len(ad.Scope) == 0 {
 		ad.Scope = DEFAULT_SCOPE
 	}
+
+	if len(ad.CodeChallenge) > 0 && len(ad.CodeChallengeMethod) == 0 {
+		ad.CodeChallengeMethod = PKCE_METHOD_PLAIN
+	}
 }
 
 func (ad *AuthData) ToJson() string {
@@ -140,3 +199,19 @@ func AuthorizeRequestFromJson(data io.Reader) *AuthorizeRequest {
 func (ad *AuthData) IsExpired() bool {
 	return GetMillis() > ad.CreateAt+int64(ad.ExpiresIn*1000)
 }
+
+// VerifyCodeVerifier returns true if a PKCE code verifier matches the code challenge that the authorization code was
+// requested with.
+func (ad *AuthData) VerifyCodeVerifier(verifier string) bool {
+	if len(ad.CodeChallenge) == 0 || !IsValidPKCECode(verifier) {
+		return false
+	}
+
+	challenge := verifier
+	if ad.CodeChallengeMethod == PKCE_METHOD_S256 {
+		hash := sha256.Sum256([]byte(verifier))
+		challenge = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(ad.CodeChallenge)) == 1
+}
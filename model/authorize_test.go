@@ -133,3 +133,68 @@ func TestAuthIsValid(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAuthIsValidCodeChallenge(t *testing.T) {
+	ad := AuthData{
+		ClientId:    NewId(),
+		UserId:      NewId(),
+		Code:        NewId(),
+		ExpiresIn:   AUTHCODE_EXPIRE_TIME,
+		CreateAt:    GetMillis(),
+		RedirectUri: "http://example.com",
+	}
+	require.Nil(t, ad.IsValid())
+
+	ad.CodeChallenge = NewRandomString(43)
+	require.Nil(t, ad.IsValid(), "should allow a challenge without a method")
+
+	ad.CodeChallengeMethod = PKCE_METHOD_S256
+	require.Nil(t, ad.IsValid())
+
+	ad.CodeChallengeMethod = "junk"
+	require.NotNil(t, ad.IsValid())
+
+	ad.CodeChallengeMethod = PKCE_METHOD_PLAIN
+	ad.CodeChallenge = NewRandomString(42)
+	require.NotNil(t, ad.IsValid(), "should fail a challenge that's too short")
+
+	ad.CodeChallenge = NewRandomString(129)
+	require.NotNil(t, ad.IsValid(), "should fail a challenge that's too long")
+
+	ad.CodeChallenge = NewRandomString(42) + "+"
+	require.NotNil(t, ad.IsValid(), "should fail a challenge with reserved characters")
+
+	ad.CodeChallenge = ""
+	require.NotNil(t, ad.IsValid(), "should fail a method without a challenge")
+
+	ar := AuthorizeRequest{
+		ResponseType:        AUTHCODE_RESPONSE_TYPE,
+		ClientId:            NewId(),
+		RedirectUri:         "http://example.com",
+		CodeChallenge:       NewRandomString(43),
+		CodeChallengeMethod: "junk",
+	}
+	require.NotNil(t, ar.IsValid())
+
+	ar.CodeChallengeMethod = PKCE_METHOD_S256
+	require.Nil(t, ar.IsValid())
+}
+
+func TestAuthVerifyCodeVerifier(t *testing.T) {
+	// The example from RFC 7636
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	ad := AuthData{CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallengeMethod: PKCE_METHOD_S256}
+	require.True(t, ad.VerifyCodeVerifier(verifier))
+	require.False(t, ad.VerifyCodeVerifier(verifier+"a"))
+	require.False(t, ad.VerifyCodeVerifier(""))
+
+	ad = AuthData{CodeChallenge: verifier}
+	ad.PreSave()
+	require.Equal(t, PKCE_METHOD_PLAIN, ad.CodeChallengeMethod)
+	require.True(t, ad.VerifyCodeVerifier(verifier))
+	require.False(t, ad.VerifyCodeVerifier("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"))
+
+	ad = AuthData{}
+	require.False(t, ad.VerifyCodeVerifier(verifier), "should fail when the code wasn't requested with a challenge")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// AutoResponderSchedule turns a user's auto-responder on at StartAt and off again at EndAt. Active records whether
+// the server has turned it on for the window yet.
+type AutoResponderSchedule struct {
+	UserId    string `json:"user_id"`
+	StartAt   int64  `json:"start_at"`
+	EndAt     int64  `json:"end_at"`
+	SetStatus bool   `json:"set_status"`
+	Active    bool   `json:"active"`
+	CreateAt  int64  `json:"create_at"`
+	UpdateAt  int64  `json:"update_at"`
+}
+
+func (o *AutoResponderSchedule) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func AutoResponderScheduleFromJson(data io.Reader) *AutoResponderSchedule {
+	var o *AutoResponderSchedule
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *AutoResponderSchedule) PreSave() {
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	o.UpdateAt = GetMillis()
+}
+
+// IsStarted returns true if the window has started by the given time.
+func (o *AutoResponderSchedule) IsStarted(now int64) bool {
+	return o.StartAt <= now
+}
+
+// IsEnded returns true if the window has ended by the given time.
+func (o *AutoResponderSchedule) IsEnded(now int64) bool {
+	return o.EndAt <= now
+}
+
+func (o *AutoResponderSchedule) IsValid() *AppError {
+	if len(o.UserId) != 26 {
+		return NewAppError("AutoResponderSchedule.IsValid", "model.auto_responder_schedule.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.StartAt <= 0 {
+		return NewAppError("AutoResponderSchedule.IsValid", "model.auto_responder_schedule.is_valid.start_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.EndAt <= o.StartAt {
+		return NewAppError("AutoResponderSchedule.IsValid", "model.auto_responder_schedule.is_valid.end_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("AutoResponderSchedule.IsValid", "model.auto_responder_schedule.is_valid.create_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("AutoResponderSchedule.IsValid", "model.auto_responder_schedule.is_valid.update_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	return nil
+}
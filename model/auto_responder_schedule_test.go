@@ -0,0 +1,42 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoResponderScheduleIsValid(t *testing.T) {
+	schedule := &AutoResponderSchedule{UserId: NewId(), StartAt: 1000, EndAt: 2000}
+	require.NotNil(t, schedule.IsValid(), "should require the create time")
+
+	schedule.PreSave()
+	require.Nil(t, schedule.IsValid())
+
+	schedule.EndAt = schedule.StartAt
+	assert.NotNil(t, schedule.IsValid(), "should end after it starts")
+
+	schedule.EndAt = 2000
+	schedule.StartAt = 0
+	assert.NotNil(t, schedule.IsValid())
+
+	schedule.StartAt = 1000
+	schedule.UserId = "junk"
+	assert.NotNil(t, schedule.IsValid())
+}
+
+func TestAutoResponderScheduleWindow(t *testing.T) {
+	schedule := &AutoResponderSchedule{StartAt: 1000, EndAt: 2000}
+
+	assert.False(t, schedule.IsStarted(999))
+	assert.True(t, schedule.IsStarted(1000))
+	assert.False(t, schedule.IsEnded(1999))
+	assert.True(t, schedule.IsEnded(2000))
+
+	assert.Equal(t, schedule, AutoResponderScheduleFromJson(strings.NewReader(schedule.ToJson())))
+}
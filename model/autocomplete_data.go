@@ -0,0 +1,345 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	AUTOCOMPLETE_ARG_TYPE_TEXT         = "text"
+	AUTOCOMPLETE_ARG_TYPE_STATIC_LIST  = "static_list"
+	AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST = "dynamic_list"
+
+	AUTOCOMPLETE_DATA_MAX_DEPTH = 5
+	AUTOCOMPLETE_MAX_LIST_ITEMS = 100
+)
+
+// AutocompleteData describes the arguments that a slash command or one of its subcommands accepts so that the server
+// can suggest them to users as they type. A command can have either subcommands or arguments, but not both.
+type AutocompleteData struct {
+	Trigger     string              `json:"trigger"`
+	Hint        string              `json:"hint"`
+	HelpText    string              `json:"help_text"`
+	Arguments   []*AutocompleteArg  `json:"arguments,omitempty"`
+	SubCommands []*AutocompleteData `json:"sub_commands,omitempty"`
+}
+
+// AutocompleteArg describes an argument to a command. Arguments without a name are positional and are filled in the
+// order that they're listed, while named arguments are flags that are given as "--name value".
+type AutocompleteArg struct {
+	Name     string                  `json:"name,omitempty"`
+	Hint     string                  `json:"hint"`
+	HelpText string                  `json:"help_text"`
+	Type     string                  `json:"type"`
+	Required bool                    `json:"required"`
+	Items    []*AutocompleteListItem `json:"items,omitempty"`
+
+	// FetchURL is requested by the server to get the items for a dynamic list. It's never sent to clients. For
+	// commands registered by plugins, it's a path that's passed to the plugin's ServeHTTP hook instead.
+	FetchURL string `json:"fetch_url,omitempty"`
+}
+
+type AutocompleteListItem struct {
+	Item     string `json:"item"`
+	Hint     string `json:"hint"`
+	HelpText string `json:"help_text"`
+}
+
+// AutocompleteSuggestion is a way to continue what a user has typed. Complete is the full command line that results
+// from choosing it.
+type AutocompleteSuggestion struct {
+	Complete    string `json:"complete"`
+	Suggestion  string `json:"suggestion"`
+	Hint        string `json:"hint"`
+	Description string `json:"description"`
+}
+
+// AutocompleteFetchFunc returns the items of a dynamic list argument.
+type AutocompleteFetchFunc func(arg *AutocompleteArg) []*AutocompleteListItem
+
+func (o *AutocompleteData) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func AutocompleteDataFromJson(data io.Reader) *AutocompleteData {
+	var o *AutocompleteData
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func AutocompleteListItemsToJson(items []*AutocompleteListItem) string {
+	b, _ := json.Marshal(items)
+	return string(b)
+}
+
+func AutocompleteListItemsFromJson(data io.Reader) []*AutocompleteListItem {
+	var items []*AutocompleteListItem
+	json.NewDecoder(data).Decode(&items)
+	return items
+}
+
+func AutocompleteSuggestionsToJson(suggestions []*AutocompleteSuggestion) string {
+	b, _ := json.Marshal(suggestions)
+	return string(b)
+}
+
+func AutocompleteSuggestionsFromJson(data io.Reader) []*AutocompleteSuggestion {
+	var suggestions []*AutocompleteSuggestion
+	json.NewDecoder(data).Decode(&suggestions)
+	return suggestions
+}
+
+func (o *AutocompleteData) IsValid() *AppError {
+	return o.isValid(1, false)
+}
+
+// IsValidForPlugin is like IsValid, but requires the FetchURL of dynamic lists to be a path handled by the plugin.
+func (o *AutocompleteData) IsValidForPlugin() *AppError {
+	return o.isValid(1, true)
+}
+
+func (o *AutocompleteData) isValid(depth int, forPlugin bool) *AppError {
+	if depth > AUTOCOMPLETE_DATA_MAX_DEPTH {
+		return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.depth.app_error", map[string]interface{}{"Max": AUTOCOMPLETE_DATA_MAX_DEPTH}, "", http.StatusBadRequest)
+	}
+
+	if o.Trigger == "" || strings.Contains(o.Trigger, " ") {
+		return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.trigger.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+	}
+
+	if len(o.Arguments) > 0 && len(o.SubCommands) > 0 {
+		return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.arguments_and_sub_commands.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+	}
+
+	names := make(map[string]bool)
+	for _, arg := range o.Arguments {
+		if arg == nil {
+			return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.argument.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+		}
+
+		if arg.Name != "" {
+			if names[arg.Name] || strings.HasPrefix(arg.Name, "-") || strings.Contains(arg.Name, " ") {
+				return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.argument_name.app_error", nil, "name="+arg.Name, http.StatusBadRequest)
+			}
+			names[arg.Name] = true
+		}
+
+		switch arg.Type {
+		case AUTOCOMPLETE_ARG_TYPE_TEXT:
+		case AUTOCOMPLETE_ARG_TYPE_STATIC_LIST:
+			if len(arg.Items) == 0 || len(arg.Items) > AUTOCOMPLETE_MAX_LIST_ITEMS {
+				return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.items.app_error", map[string]interface{}{"Max": AUTOCOMPLETE_MAX_LIST_ITEMS}, "trigger="+o.Trigger, http.StatusBadRequest)
+			}
+		case AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST:
+			if forPlugin {
+				if len(arg.FetchURL) > 1024 || !strings.HasPrefix(arg.FetchURL, "/") || strings.HasPrefix(arg.FetchURL, "//") {
+					return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.fetch_url.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+				}
+			} else if len(arg.FetchURL) > 1024 || !IsValidHttpUrl(arg.FetchURL) {
+				return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.fetch_url.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+			}
+		default:
+			return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.type.app_error", nil, "type="+arg.Type, http.StatusBadRequest)
+		}
+	}
+
+	triggers := make(map[string]bool)
+	for _, subCommand := range o.SubCommands {
+		if subCommand == nil {
+			return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.sub_command.app_error", nil, "trigger="+o.Trigger, http.StatusBadRequest)
+		}
+
+		if triggers[subCommand.Trigger] {
+			return NewAppError("AutocompleteData.IsValid", "model.autocomplete_data.is_valid.sub_command.app_error", nil, "trigger="+subCommand.Trigger, http.StatusBadRequest)
+		}
+		triggers[subCommand.Trigger] = true
+
+		if err := subCommand.isValid(depth+1, forPlugin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sanitized returns a copy of the data without the URLs of dynamic lists so that it can be sent to clients.
+func (o *AutocompleteData) Sanitized() *AutocompleteData {
+	if o == nil {
+		return nil
+	}
+
+	sanitized := *o
+
+	if o.Arguments != nil {
+		sanitized.Arguments = make([]*AutocompleteArg, len(o.Arguments))
+		for i, arg := range o.Arguments {
+			sanitizedArg := *arg
+			sanitizedArg.FetchURL = ""
+			sanitized.Arguments[i] = &sanitizedArg
+		}
+	}
+
+	if o.SubCommands != nil {
+		sanitized.SubCommands = make([]*AutocompleteData, len(o.SubCommands))
+		for i, subCommand := range o.SubCommands {
+			sanitized.SubCommands[i] = subCommand.Sanitized()
+		}
+	}
+
+	return &sanitized
+}
+
+// GetSuggestions returns the ways that a user could continue typing a command. The command line is split into what
+// comes before the text that the command's data describes, such as "/trigger ", and the text after it.
+func (o *AutocompleteData) GetSuggestions(before string, text string, fetch AutocompleteFetchFunc) []*AutocompleteSuggestion {
+	// Everything up to the last space has been typed, and the word after it is still being typed
+	words := strings.Split(text, " ")
+	partial := words[len(words)-1]
+	before += text[:len(text)-len(partial)]
+
+	var typed []string
+	for _, word := range words[:len(words)-1] {
+		if word != "" {
+			typed = append(typed, word)
+		}
+	}
+
+	data := o
+	for len(data.SubCommands) > 0 && len(typed) > 0 {
+		data = data.getSubCommand(typed[0])
+		if data == nil {
+			return []*AutocompleteSuggestion{}
+		}
+		typed = typed[1:]
+	}
+
+	if len(data.SubCommands) > 0 {
+		suggestions := []*AutocompleteSuggestion{}
+		for _, subCommand := range data.SubCommands {
+			if strings.HasPrefix(strings.ToLower(subCommand.Trigger), strings.ToLower(partial)) {
+				suggestions = append(suggestions, &AutocompleteSuggestion{
+					Complete:    before + subCommand.Trigger,
+					Suggestion:  subCommand.Trigger,
+					Hint:        subCommand.Hint,
+					Description: subCommand.HelpText,
+				})
+			}
+		}
+		return suggestions
+	}
+
+	return data.getArgumentSuggestions(before, typed, partial, fetch)
+}
+
+func (o *AutocompleteData) getSubCommand(trigger string) *AutocompleteData {
+	for _, subCommand := range o.SubCommands {
+		if strings.EqualFold(subCommand.Trigger, trigger) {
+			return subCommand
+		}
+	}
+
+	return nil
+}
+
+func (o *AutocompleteData) getNamedArgument(name string) *AutocompleteArg {
+	for _, arg := range o.Arguments {
+		if arg.Name != "" && arg.Name == name {
+			return arg
+		}
+	}
+
+	return nil
+}
+
+func (o *AutocompleteData) getArgumentSuggestions(before string, typed []string, partial string, fetch AutocompleteFetchFunc) []*AutocompleteSuggestion {
+	var positional []*AutocompleteArg
+	for _, arg := range o.Arguments {
+		if arg.Name == "" {
+			positional = append(positional, arg)
+		}
+	}
+
+	used := make(map[string]bool)
+	position := 0
+	var flag *AutocompleteArg
+	for _, word := range typed {
+		if flag != nil {
+			// This word is the value of the flag before it
+			flag = nil
+		} else if strings.HasPrefix(word, "--") {
+			if flag = o.getNamedArgument(strings.TrimPrefix(word, "--")); flag != nil {
+				used[flag.Name] = true
+			}
+		} else {
+			position++
+		}
+	}
+
+	if flag != nil {
+		return getArgumentValueSuggestions(flag, before, partial, fetch)
+	}
+
+	if position < len(positional) && !strings.HasPrefix(partial, "-") {
+		return getArgumentValueSuggestions(positional[position], before, partial, fetch)
+	}
+
+	suggestions := []*AutocompleteSuggestion{}
+	for _, arg := range o.Arguments {
+		if arg.Name != "" && !used[arg.Name] && strings.HasPrefix("--"+arg.Name, partial) {
+			suggestions = append(suggestions, &AutocompleteSuggestion{
+				Complete:    before + "--" + arg.Name,
+				Suggestion:  "--" + arg.Name,
+				Hint:        arg.Hint,
+				Description: arg.HelpText,
+			})
+		}
+	}
+
+	return suggestions
+}
+
+func getArgumentValueSuggestions(arg *AutocompleteArg, before string, partial string, fetch AutocompleteFetchFunc) []*AutocompleteSuggestion {
+	var items []*AutocompleteListItem
+	switch arg.Type {
+	case AUTOCOMPLETE_ARG_TYPE_STATIC_LIST:
+		items = arg.Items
+	case AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST:
+		if fetch != nil {
+			items = fetch(arg)
+		}
+	default:
+		// Free text can't be suggested, but the hint still tells the user what to type
+		return []*AutocompleteSuggestion{{
+			Complete:    before + partial,
+			Suggestion:  partial,
+			Hint:        arg.Hint,
+			Description: arg.HelpText,
+		}}
+	}
+
+	suggestions := []*AutocompleteSuggestion{}
+	for _, item := range items {
+		if item == nil || !strings.HasPrefix(strings.ToLower(item.Item), strings.ToLower(partial)) {
+			continue
+		}
+
+		suggestions = append(suggestions, &AutocompleteSuggestion{
+			Complete:    before + item.Item,
+			Suggestion:  item.Item,
+			Hint:        item.Hint,
+			Description: item.HelpText,
+		})
+
+		if len(suggestions) >= AUTOCOMPLETE_MAX_LIST_ITEMS {
+			break
+		}
+	}
+
+	return suggestions
+}
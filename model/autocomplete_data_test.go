@@ -0,0 +1,166 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAutocompleteData() *AutocompleteData {
+	return &AutocompleteData{
+		Trigger: "jira",
+		SubCommands: []*AutocompleteData{
+			{
+				Trigger:  "create",
+				HelpText: "Create an issue",
+				Arguments: []*AutocompleteArg{
+					{Hint: "[project]", Type: AUTOCOMPLETE_ARG_TYPE_DYNAMIC_LIST, FetchURL: "https://example.com/projects"},
+					{Hint: "[summary]", HelpText: "What the issue is about", Type: AUTOCOMPLETE_ARG_TYPE_TEXT},
+					{Name: "priority", Type: AUTOCOMPLETE_ARG_TYPE_STATIC_LIST, Items: []*AutocompleteListItem{
+						{Item: "high"},
+						{Item: "low"},
+					}},
+					{Name: "assignee", Type: AUTOCOMPLETE_ARG_TYPE_TEXT},
+				},
+			},
+			{Trigger: "connect", HelpText: "Connect your account"},
+		},
+	}
+}
+
+func TestAutocompleteDataIsValid(t *testing.T) {
+	assert.Nil(t, newTestAutocompleteData().IsValid())
+
+	data := newTestAutocompleteData()
+	data.Trigger = "ji ra"
+	assert.NotNil(t, data.IsValid())
+
+	data = newTestAutocompleteData()
+	data.Arguments = []*AutocompleteArg{{Type: AUTOCOMPLETE_ARG_TYPE_TEXT}}
+	assert.NotNil(t, data.IsValid(), "should not have both arguments and subcommands")
+
+	data = newTestAutocompleteData()
+	data.SubCommands[0].Arguments[0].FetchURL = "ftp://example.com"
+	assert.NotNil(t, data.IsValid())
+
+	data = newTestAutocompleteData()
+	data.SubCommands[0].Arguments[2].Items = nil
+	assert.NotNil(t, data.IsValid())
+
+	data = newTestAutocompleteData()
+	data.SubCommands[0].Arguments[3].Name = "priority"
+	assert.NotNil(t, data.IsValid())
+
+	data = newTestAutocompleteData()
+	data.SubCommands[0].Arguments[3].Type = "date"
+	assert.NotNil(t, data.IsValid())
+
+	data = newTestAutocompleteData()
+	data.SubCommands[1].Trigger = "create"
+	assert.NotNil(t, data.IsValid())
+
+	data = &AutocompleteData{Trigger: "a"}
+	leaf := data
+	for i := 0; i < AUTOCOMPLETE_DATA_MAX_DEPTH; i++ {
+		leaf.SubCommands = []*AutocompleteData{{Trigger: "a"}}
+		leaf = leaf.SubCommands[0]
+	}
+	assert.NotNil(t, data.IsValid())
+}
+
+func TestAutocompleteDataIsValidForPlugin(t *testing.T) {
+	data := newTestAutocompleteData()
+	assert.NotNil(t, data.IsValidForPlugin(), "should require a path instead of a url")
+
+	data.SubCommands[0].Arguments[0].FetchURL = "/projects"
+	assert.Nil(t, data.IsValidForPlugin())
+	assert.NotNil(t, data.IsValid())
+
+	data.SubCommands[0].Arguments[0].FetchURL = "//example.com/projects"
+	assert.NotNil(t, data.IsValidForPlugin())
+
+	data.SubCommands[0].Arguments[0].FetchURL = "projects"
+	assert.NotNil(t, data.IsValidForPlugin())
+}
+
+func TestAutocompleteDataSanitized(t *testing.T) {
+	data := newTestAutocompleteData()
+	sanitized := data.Sanitized()
+
+	assert.Equal(t, "", sanitized.SubCommands[0].Arguments[0].FetchURL)
+	assert.Equal(t, "https://example.com/projects", data.SubCommands[0].Arguments[0].FetchURL, "should not modify the original")
+
+	assert.Nil(t, (*AutocompleteData)(nil).Sanitized())
+}
+
+func TestAutocompleteDataGetSuggestions(t *testing.T) {
+	data := newTestAutocompleteData()
+
+	fetched := 0
+	fetch := func(arg *AutocompleteArg) []*AutocompleteListItem {
+		fetched++
+		return []*AutocompleteListItem{{Item: "MM", HelpText: "Mattermost"}, {Item: "DOCS"}}
+	}
+
+	suggestions := func(text string) []string {
+		var completes []string
+		for _, suggestion := range data.GetSuggestions("/jira ", text, fetch) {
+			completes = append(completes, suggestion.Complete)
+		}
+		return completes
+	}
+
+	assert.Equal(t, []string{"/jira create", "/jira connect"}, suggestions(""))
+	assert.Equal(t, []string{"/jira create", "/jira connect"}, suggestions("c"))
+	assert.Equal(t, []string{"/jira connect"}, suggestions("con"))
+	assert.Empty(t, suggestions("delete "))
+
+	assert.Equal(t, []string{"/jira create MM", "/jira create DOCS"}, suggestions("create "))
+	assert.Equal(t, []string{"/jira create MM"}, suggestions("create m"))
+	assert.Equal(t, 2, fetched)
+
+	hints := data.GetSuggestions("/jira ", "create MM Fix", fetch)
+	require.Len(t, hints, 1)
+	assert.Equal(t, "/jira create MM Fix", hints[0].Complete)
+	assert.Equal(t, "[summary]", hints[0].Hint)
+
+	assert.Equal(t, []string{"/jira create MM Fix --priority", "/jira create MM Fix --assignee"}, suggestions("create MM Fix "))
+	assert.Equal(t, []string{"/jira create MM Fix --priority"}, suggestions("create MM Fix --p"))
+	assert.Equal(t, []string{"/jira create MM Fix --priority high", "/jira create MM Fix --priority low"}, suggestions("create MM Fix --priority "))
+	assert.Equal(t, []string{"/jira create MM Fix --priority low --assignee"}, suggestions("create MM Fix --priority low "))
+	assert.Equal(t, []string{"/jira create  MM"}, suggestions("create  M"), "should ignore repeated spaces")
+}
+
+func TestCommandIsValidAutocompleteData(t *testing.T) {
+	o := Command{
+		Id:        NewId(),
+		Token:     NewId(),
+		CreateAt:  GetMillis(),
+		UpdateAt:  GetMillis(),
+		CreatorId: NewId(),
+		TeamId:    NewId(),
+		Trigger:   "jira",
+		URL:       "http://example.com",
+		Method:    COMMAND_METHOD_GET,
+	}
+	require.Nil(t, o.IsValid())
+
+	o.AutocompleteData = newTestAutocompleteData()
+	assert.Nil(t, o.IsValid())
+
+	o.Trigger = "other"
+	assert.NotNil(t, o.IsValid(), "should require the triggers to match")
+
+	o.Trigger = "jira"
+	o.AutocompleteData.HelpText = strings.Repeat("a", COMMAND_AUTOCOMPLETE_DATA_MAX_SIZE)
+	assert.NotNil(t, o.IsValid())
+
+	o.AutocompleteData = newTestAutocompleteData()
+	o.Sanitize()
+	assert.Equal(t, "", o.AutocompleteData.SubCommands[0].Arguments[0].FetchURL)
+}
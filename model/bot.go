@@ -0,0 +1,161 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	BOT_DISPLAY_NAME_MAX_RUNES = USER_FIRST_NAME_MAX_RUNES
+	BOT_DESCRIPTION_MAX_RUNES  = 1024
+)
+
+// Bot is a special type of user that's owned by another user and that can only authenticate with access tokens. Each
+// bot has a user with the same id that stores its username and display name.
+type Bot struct {
+	UserId      string `json:"user_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	OwnerId     string `json:"owner_id"`
+	CreateAt    int64  `json:"create_at"`
+	UpdateAt    int64  `json:"update_at"`
+	DeleteAt    int64  `json:"delete_at"`
+}
+
+type BotPatch struct {
+	Username    *string `json:"username"`
+	DisplayName *string `json:"display_name"`
+	Description *string `json:"description"`
+}
+
+// BotGetOptions filters the bots that are returned when they're listed.
+type BotGetOptions struct {
+	OwnerId        string
+	IncludeDeleted bool
+	OnlyOrphaned   bool
+	Page           int
+	PerPage        int
+}
+
+func (b *Bot) IsValid() *AppError {
+	if len(b.UserId) != 26 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidUsername(b.Username) {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.username.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(b.DisplayName) > BOT_DISPLAY_NAME_MAX_RUNES {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.display_name.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(b.Description) > BOT_DESCRIPTION_MAX_RUNES {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.description.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	if len(b.OwnerId) != 26 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.owner_id.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	if b.CreateAt == 0 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.create_at.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	if b.UpdateAt == 0 {
+		return NewAppError("Bot.IsValid", "model.bot.is_valid.update_at.app_error", nil, "user_id="+b.UserId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (b *Bot) PreSave() {
+	b.Username = NormalizeUsername(strings.TrimSpace(b.Username))
+
+	b.CreateAt = GetMillis()
+	b.UpdateAt = b.CreateAt
+	b.DeleteAt = 0
+}
+
+func (b *Bot) PreUpdate() {
+	b.Username = NormalizeUsername(strings.TrimSpace(b.Username))
+
+	b.UpdateAt = GetMillis()
+}
+
+func (b *Bot) Patch(patch *BotPatch) {
+	if patch.Username != nil {
+		b.Username = *patch.Username
+	}
+
+	if patch.DisplayName != nil {
+		b.DisplayName = *patch.DisplayName
+	}
+
+	if patch.Description != nil {
+		b.Description = *patch.Description
+	}
+}
+
+// User returns the user that's created along with the bot. Nobody knows its password, so it can only be used through
+// access tokens. Its email is made from a new id rather than the username so that it doesn't have to change when the
+// bot is renamed, which would leave the old email to clash with a new bot that takes the old name.
+func (b *Bot) User() *User {
+	return &User{
+		Id:            b.UserId,
+		Username:      b.Username,
+		Email:         fmt.Sprintf("%v@localhost", NewId()),
+		Password:      NewId(),
+		FirstName:     b.DisplayName,
+		Roles:         SYSTEM_USER_ROLE_ID,
+		EmailVerified: true,
+		IsBot:         true,
+	}
+}
+
+// UpdateUser copies the bot's username and display name to its user.
+func (b *Bot) UpdateUser(user *User) {
+	user.Username = b.Username
+	user.FirstName = b.DisplayName
+}
+
+func (b *Bot) ToJson() string {
+	j, _ := json.Marshal(b)
+	return string(j)
+}
+
+func BotFromJson(data io.Reader) *Bot {
+	var b *Bot
+	json.NewDecoder(data).Decode(&b)
+	return b
+}
+
+func (p *BotPatch) ToJson() string {
+	j, _ := json.Marshal(p)
+	return string(j)
+}
+
+func BotPatchFromJson(data io.Reader) *BotPatch {
+	var p *BotPatch
+	json.NewDecoder(data).Decode(&p)
+	return p
+}
+
+func BotListToJson(bots []*Bot) string {
+	j, _ := json.Marshal(bots)
+	return string(j)
+}
+
+func BotListFromJson(data io.Reader) []*Bot {
+	var bots []*Bot
+	json.NewDecoder(data).Decode(&bots)
+	return bots
+}
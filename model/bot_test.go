@@ -0,0 +1,79 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotIsValid(t *testing.T) {
+	bot := &Bot{
+		UserId:   NewId(),
+		Username: "  MyBot ",
+		OwnerId:  NewId(),
+	}
+	bot.PreSave()
+	assert.Equal(t, "mybot", bot.Username)
+	require.Nil(t, bot.IsValid())
+
+	for name, modify := range map[string]func(b *Bot){
+		"user id":      func(b *Bot) { b.UserId = "junk" },
+		"username":     func(b *Bot) { b.Username = "my bot" },
+		"display name": func(b *Bot) { b.DisplayName = strings.Repeat("a", BOT_DISPLAY_NAME_MAX_RUNES+1) },
+		"description":  func(b *Bot) { b.Description = strings.Repeat("a", BOT_DESCRIPTION_MAX_RUNES+1) },
+		"owner id":     func(b *Bot) { b.OwnerId = "" },
+		"create at":    func(b *Bot) { b.CreateAt = 0 },
+		"update at":    func(b *Bot) { b.UpdateAt = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := *bot
+			modify(&invalid)
+			assert.NotNil(t, invalid.IsValid())
+		})
+	}
+}
+
+func TestBotPatch(t *testing.T) {
+	bot := &Bot{Username: "mybot", DisplayName: "My Bot", Description: "Does things"}
+
+	bot.Patch(&BotPatch{DisplayName: NewString("Renamed"), Description: NewString("")})
+	assert.Equal(t, "mybot", bot.Username)
+	assert.Equal(t, "Renamed", bot.DisplayName)
+	assert.Equal(t, "", bot.Description)
+
+	user := &User{Username: "old", FirstName: "Old"}
+	bot.UpdateUser(user)
+	assert.Equal(t, "mybot", user.Username)
+	assert.Equal(t, "Renamed", user.FirstName)
+}
+
+func TestBotUser(t *testing.T) {
+	bot := &Bot{UserId: NewId(), Username: "mybot", DisplayName: "My Bot"}
+
+	user := bot.User()
+	assert.Equal(t, bot.UserId, user.Id)
+	assert.Equal(t, "mybot", user.Username)
+	assert.Equal(t, "My Bot", user.FirstName)
+	assert.True(t, strings.HasSuffix(user.Email, "@localhost"))
+	assert.NotEqual(t, "mybot@localhost", user.Email, "the email shouldn't depend on the username")
+	assert.NotEqual(t, user.Email, bot.User().Email)
+	assert.Equal(t, SYSTEM_USER_ROLE_ID, user.Roles)
+	assert.True(t, user.IsBot)
+	assert.NotEmpty(t, user.Password)
+}
+
+func TestBotJson(t *testing.T) {
+	bot := &Bot{UserId: NewId(), Username: "mybot", OwnerId: NewId(), CreateAt: 1, UpdateAt: 2}
+	assert.Equal(t, bot, BotFromJson(strings.NewReader(bot.ToJson())))
+
+	bots := []*Bot{bot}
+	assert.Equal(t, bots, BotListFromJson(strings.NewReader(BotListToJson(bots))))
+
+	patch := &BotPatch{Username: NewString("renamed")}
+	assert.Equal(t, patch, BotPatchFromJson(strings.NewReader(patch.ToJson())))
+}
@@ -47,14 +47,18 @@ type Channel struct {
 	ExtraUpdateAt int64                  `json:"extra_update_at"`
 	CreatorId     string                 `json:"creator_id"`
 	SchemeId      *string                `json:"scheme_id"`
+	RemoteId      *string                `json:"remote_id,omitempty"`
+	ReadReceipts  bool                   `json:"read_receipts"`
 	Props         map[string]interface{} `json:"props" db:"-"`
 }
 
 type ChannelPatch struct {
-	DisplayName *string `json:"display_name"`
-	Name        *string `json:"name"`
-	Header      *string `json:"header"`
-	Purpose     *string `json:"purpose"`
+	DisplayName  *string `json:"display_name"`
+	Name         *string `json:"name"`
+	Header       *string `json:"header"`
+	Purpose      *string `json:"purpose"`
+	RemoteId     *string `json:"remote_id"`
+	ReadReceipts *bool   `json:"read_receipts"`
 }
 
 func (o *Channel) DeepCopy() *Channel {
@@ -125,6 +129,10 @@ func (o *Channel) IsValid() *AppError {
 		return NewAppError("Channel.IsValid", "model.channel.is_valid.creator_id.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if !IsValidRemoteId(o.RemoteId) {
+		return NewAppError("Channel.IsValid", "model.channel.is_valid.remote_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -162,6 +170,14 @@ func (o *Channel) Patch(patch *ChannelPatch) {
 	if patch.Purpose != nil {
 		o.Purpose = *patch.Purpose
 	}
+
+	if patch.RemoteId != nil {
+		o.RemoteId = patchRemoteId(patch.RemoteId)
+	}
+
+	if patch.ReadReceipts != nil {
+		o.ReadReceipts = *patch.ReadReceipts
+	}
 }
 
 func (o *Channel) MakeNonNil() {
@@ -0,0 +1,271 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS = "latest_wins"
+	CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS  = "local_wins"
+	CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS = "remote_wins"
+
+	CHANNEL_BRIDGE_EVENT_POSTED           = "posted"
+	CHANNEL_BRIDGE_EVENT_POST_EDITED      = "post_edited"
+	CHANNEL_BRIDGE_EVENT_POST_DELETED     = "post_deleted"
+	CHANNEL_BRIDGE_EVENT_REACTION_ADDED   = "reaction_added"
+	CHANNEL_BRIDGE_EVENT_REACTION_REMOVED = "reaction_removed"
+
+	CHANNEL_BRIDGE_REMOTE_CHANNEL_ID_MAX_LENGTH = 128
+	CHANNEL_BRIDGE_OUTGOING_URL_MAX_LENGTH      = 1024
+	CHANNEL_BRIDGE_MAX_FILES                    = 5
+
+	HEADER_BRIDGE_TOKEN = "X-Bridge-Token"
+)
+
+// ChannelBridge pairs a channel with a channel on another chat service, such as Slack or Microsoft Teams. Changes
+// made in the channel are sent to the adapter at OutgoingURL, which relays them to the other service, and the adapter
+// sends changes made on the other service back as ChannelBridgeEvents authenticated by Token. Posts and users on both
+// sides are matched using their remote ids.
+type ChannelBridge struct {
+	Id              string `json:"id"`
+	ChannelId       string `json:"channel_id"`
+	Service         string `json:"service"`
+	RemoteChannelId string `json:"remote_channel_id"`
+	OutgoingURL     string `json:"outgoing_url"`
+	Token           string `json:"token"`
+	ConflictPolicy  string `json:"conflict_policy"`
+	CreatorId       string `json:"creator_id"`
+	CreateAt        int64  `json:"create_at"`
+	UpdateAt        int64  `json:"update_at"`
+}
+
+// ChannelBridgeFile is a file attached to a bridged post. Incoming files are downloaded from Url, while outgoing files
+// only have a Url when public links are enabled.
+type ChannelBridgeFile struct {
+	Id       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Url      string `json:"url,omitempty"`
+}
+
+// ChannelBridgeEvent is a change to a bridged post, sent in both directions. Timestamp is when the change was made,
+// and is used to resolve conflicts between changes made on both sides.
+type ChannelBridgeEvent struct {
+	Type            string               `json:"type"`
+	BridgeId        string               `json:"bridge_id,omitempty"`
+	RemoteChannelId string               `json:"remote_channel_id,omitempty"`
+	Timestamp       int64                `json:"timestamp"`
+	PostId          string               `json:"post_id,omitempty"`
+	RemotePostId    string               `json:"remote_post_id,omitempty"`
+	RootId          string               `json:"root_id,omitempty"`
+	RemoteRootId    string               `json:"remote_root_id,omitempty"`
+	UserId          string               `json:"user_id,omitempty"`
+	RemoteUserId    string               `json:"remote_user_id,omitempty"`
+	Username        string               `json:"username,omitempty"`
+	Message         string               `json:"message,omitempty"`
+	EmojiName       string               `json:"emoji_name,omitempty"`
+	Files           []*ChannelBridgeFile `json:"files,omitempty"`
+}
+
+// ChannelBridgeEventResponse is what the adapter replies with when it has relayed a new post, so that later edits,
+// deletions and reactions can be matched to the post on the other service.
+type ChannelBridgeEventResponse struct {
+	RemotePostId string `json:"remote_post_id"`
+}
+
+// ChannelBridgeHealth summarizes the traffic through a bridge since this server started.
+type ChannelBridgeHealth struct {
+	BridgeId       string `json:"bridge_id"`
+	IncomingEvents int64  `json:"incoming_events"`
+	OutgoingEvents int64  `json:"outgoing_events"`
+	FailedEvents   int64  `json:"failed_events"`
+	Conflicts      int64  `json:"conflicts"`
+	LastEventAt    int64  `json:"last_event_at"`
+	LastError      string `json:"last_error,omitempty"`
+	LastErrorAt    int64  `json:"last_error_at,omitempty"`
+}
+
+func (o *ChannelBridge) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ChannelBridgeFromJson(data io.Reader) *ChannelBridge {
+	var o *ChannelBridge
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func ChannelBridgeListToJson(l []*ChannelBridge) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func ChannelBridgeListFromJson(data io.Reader) []*ChannelBridge {
+	var o []*ChannelBridge
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *ChannelBridgeEvent) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ChannelBridgeEventFromJson(data io.Reader) *ChannelBridgeEvent {
+	var o *ChannelBridgeEvent
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func ChannelBridgeEventResponseFromJson(data io.Reader) *ChannelBridgeEventResponse {
+	var o *ChannelBridgeEventResponse
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *ChannelBridgeHealth) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ChannelBridgeHealthFromJson(data io.Reader) *ChannelBridgeHealth {
+	var o *ChannelBridgeHealth
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func ChannelBridgeHealthListToJson(l []*ChannelBridgeHealth) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func ChannelBridgeHealthListFromJson(data io.Reader) []*ChannelBridgeHealth {
+	var o []*ChannelBridgeHealth
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func IsValidChannelBridgeConflictPolicy(policy string) bool {
+	switch policy {
+	case CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, CHANNEL_BRIDGE_CONFLICT_POLICY_LOCAL_WINS, CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS:
+		return true
+	}
+
+	return false
+}
+
+func (o *ChannelBridge) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.Token == "" {
+		o.Token = NewId()
+	}
+
+	if o.ConflictPolicy == "" {
+		o.ConflictPolicy = CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS
+	}
+
+	o.Service = strings.ToLower(o.Service)
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+}
+
+func (o *ChannelBridge) PreUpdate() {
+	o.Service = strings.ToLower(o.Service)
+	o.UpdateAt = GetMillis()
+}
+
+func (o *ChannelBridge) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.ChannelId) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidRemoteIdService(o.Service) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.service.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.RemoteChannelId) == 0 || len(o.RemoteChannelId) > CHANNEL_BRIDGE_REMOTE_CHANNEL_ID_MAX_LENGTH {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.remote_channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.OutgoingURL) > CHANNEL_BRIDGE_OUTGOING_URL_MAX_LENGTH || !IsValidHttpUrl(o.OutgoingURL) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.outgoing_url.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.Token) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.token.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidChannelBridgeConflictPolicy(o.ConflictPolicy) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.conflict_policy.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.CreatorId) {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.creator_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("ChannelBridge.IsValid", "model.channel_bridge.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Sanitize removes the token used by the adapter to authenticate with the server.
+func (o *ChannelBridge) Sanitize() {
+	o.Token = ""
+}
+
+func (o *ChannelBridgeEvent) IsValid() *AppError {
+	switch o.Type {
+	case CHANNEL_BRIDGE_EVENT_POSTED, CHANNEL_BRIDGE_EVENT_POST_EDITED, CHANNEL_BRIDGE_EVENT_POST_DELETED:
+	case CHANNEL_BRIDGE_EVENT_REACTION_ADDED, CHANNEL_BRIDGE_EVENT_REACTION_REMOVED:
+		if len(o.EmojiName) == 0 || len(o.EmojiName) > EMOJI_NAME_MAX_LENGTH {
+			return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.emoji_name.app_error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.type.app_error", nil, "type="+o.Type, http.StatusBadRequest)
+	}
+
+	if len(o.RemotePostId) == 0 || len(o.RemotePostId) > REMOTE_ID_MAX_LENGTH {
+		return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.remote_post_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.RemoteRootId) > REMOTE_ID_MAX_LENGTH || len(o.RemoteUserId) > REMOTE_ID_MAX_LENGTH {
+		return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.remote_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.Timestamp <= 0 {
+		return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.timestamp.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Files) > CHANNEL_BRIDGE_MAX_FILES {
+		return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.files.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, file := range o.Files {
+		if len(file.Name) == 0 || !IsValidHttpUrl(file.Url) {
+			return NewAppError("ChannelBridgeEvent.IsValid", "model.channel_bridge_event.is_valid.files.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
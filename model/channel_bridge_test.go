@@ -0,0 +1,98 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelBridgeJson(t *testing.T) {
+	o := &ChannelBridge{Id: NewId(), ChannelId: NewId(), Service: "slack", RemoteChannelId: "C123"}
+	ro := ChannelBridgeFromJson(strings.NewReader(o.ToJson()))
+	assert.Equal(t, o, ro)
+
+	l := []*ChannelBridge{o}
+	assert.Equal(t, l, ChannelBridgeListFromJson(strings.NewReader(ChannelBridgeListToJson(l))))
+}
+
+func TestChannelBridgeIsValid(t *testing.T) {
+	o := &ChannelBridge{
+		ChannelId:       NewId(),
+		Service:         "MSTeams",
+		RemoteChannelId: "19:abc@thread.skype",
+		OutgoingURL:     "https://bridge.example.com/events",
+		CreatorId:       NewId(),
+	}
+	o.PreSave()
+
+	assert.Equal(t, "msteams", o.Service)
+	assert.Equal(t, CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, o.ConflictPolicy)
+	assert.Nil(t, o.IsValid())
+
+	o.Service = "ms teams"
+	assert.NotNil(t, o.IsValid())
+	o.Service = "msteams"
+
+	o.RemoteChannelId = ""
+	assert.NotNil(t, o.IsValid())
+	o.RemoteChannelId = strings.Repeat("a", CHANNEL_BRIDGE_REMOTE_CHANNEL_ID_MAX_LENGTH+1)
+	assert.NotNil(t, o.IsValid())
+	o.RemoteChannelId = "C123"
+
+	o.OutgoingURL = "ftp://bridge.example.com"
+	assert.NotNil(t, o.IsValid())
+	o.OutgoingURL = "http://localhost:8080/events"
+
+	o.ConflictPolicy = "first_wins"
+	assert.NotNil(t, o.IsValid())
+	o.ConflictPolicy = CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS
+
+	o.Token = ""
+	assert.NotNil(t, o.IsValid())
+	o.Token = NewId()
+
+	assert.Nil(t, o.IsValid())
+
+	o.Sanitize()
+	assert.Empty(t, o.Token)
+}
+
+func TestChannelBridgeEventIsValid(t *testing.T) {
+	o := &ChannelBridgeEvent{
+		Type:         CHANNEL_BRIDGE_EVENT_POSTED,
+		Timestamp:    GetMillis(),
+		RemotePostId: "1536101234.000100",
+		Message:      "hello",
+	}
+	assert.Nil(t, o.IsValid())
+
+	o.Type = "typing"
+	assert.NotNil(t, o.IsValid())
+
+	o.Type = CHANNEL_BRIDGE_EVENT_REACTION_ADDED
+	assert.NotNil(t, o.IsValid(), "reactions need an emoji")
+	o.EmojiName = "thumbsup"
+	assert.Nil(t, o.IsValid())
+
+	o.RemotePostId = ""
+	assert.NotNil(t, o.IsValid())
+	o.RemotePostId = "1536101234.000100"
+
+	o.Timestamp = 0
+	assert.NotNil(t, o.IsValid())
+	o.Timestamp = GetMillis()
+
+	o.Files = []*ChannelBridgeFile{{Name: "a.png"}}
+	assert.NotNil(t, o.IsValid(), "files need a url")
+	o.Files[0].Url = "https://files.example.com/a.png"
+	assert.Nil(t, o.IsValid())
+
+	for len(o.Files) <= CHANNEL_BRIDGE_MAX_FILES {
+		o.Files = append(o.Files, o.Files[0])
+	}
+	assert.NotNil(t, o.IsValid())
+}
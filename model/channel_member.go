@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -17,6 +18,12 @@ const (
 	CHANNEL_NOTIFY_NONE         = "none"
 	CHANNEL_MARK_UNREAD_ALL     = "all"
 	CHANNEL_MARK_UNREAD_MENTION = "mention"
+
+	// The time in milliseconds until which notifications for the channel are snoozed
+	MUTED_UNTIL_NOTIFY_PROP = "muted_until"
+
+	// The most channels that a user's memberships can be asked for at once
+	CHANNEL_MEMBERS_BY_CHANNEL_IDS_MAX = 1000
 )
 
 type ChannelUnread struct {
@@ -51,6 +58,21 @@ func (o *ChannelMembers) ToJson() string {
 	}
 }
 
+// Etag changes whenever a member is added, removed or updated, including when a member views the channel.
+func (o *ChannelMembers) Etag() string {
+	id := "0"
+	var t int64 = 0
+
+	for _, v := range *o {
+		if v.LastUpdateAt > t {
+			t = v.LastUpdateAt
+			id = v.ChannelId + v.UserId
+		}
+	}
+
+	return Etag(id, t, len(*o))
+}
+
 func (o *ChannelUnread) ToJson() string {
 	b, _ := json.Marshal(o)
 	return string(b)
@@ -111,6 +133,16 @@ func (o *ChannelMember) IsValid() *AppError {
 		}
 	}
 
+	if !IsValidNotifyScheduleProps(o.NotifyProps) {
+		return NewAppError("ChannelMember.IsValid", "model.channel_member.is_valid.notify_schedule.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if mutedUntil, ok := o.NotifyProps[MUTED_UNTIL_NOTIFY_PROP]; ok {
+		if millis, err := strconv.ParseInt(mutedUntil, 10, 64); err != nil || millis < 0 {
+			return NewAppError("ChannelMember.IsValid", "model.channel_member.is_valid.muted_until.app_error", nil, "muted_until="+mutedUntil, http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
@@ -137,6 +169,21 @@ func IsChannelMarkUnreadLevelValid(markUnreadLevel string) bool {
 	return markUnreadLevel == CHANNEL_MARK_UNREAD_ALL || markUnreadLevel == CHANNEL_MARK_UNREAD_MENTION
 }
 
+// GetChannelMutedUntil returns the time in milliseconds until which the channel is snoozed, or 0 if it isn't.
+func GetChannelMutedUntil(channelNotifyProps StringMap) int64 {
+	millis, err := strconv.ParseInt(channelNotifyProps[MUTED_UNTIL_NOTIFY_PROP], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return millis
+}
+
+// IsChannelSnoozedAt returns true if the channel is snoozed at the given time in milliseconds.
+func IsChannelSnoozedAt(channelNotifyProps StringMap, millis int64) bool {
+	return millis < GetChannelMutedUntil(channelNotifyProps)
+}
+
 func IsSendEmailValid(sendEmail string) bool {
 	return sendEmail == CHANNEL_NOTIFY_DEFAULT || sendEmail == "true" || sendEmail == "false"
 }
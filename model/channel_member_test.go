@@ -64,12 +64,76 @@ func TestChannelMemberIsValid(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	o.NotifyProps[SCHEDULE_START_NOTIFY_PROP] = "25:00"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.NotifyProps[SCHEDULE_START_NOTIFY_PROP] = "17:00"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.NotifyProps[MUTED_UNTIL_NOTIFY_PROP] = "tomorrow"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.NotifyProps[MUTED_UNTIL_NOTIFY_PROP] = "1530000000000"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
 	o.Roles = ""
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
 }
 
+func TestChannelMembersEtag(t *testing.T) {
+	members := ChannelMembers{
+		{ChannelId: NewId(), UserId: NewId(), LastUpdateAt: 1000},
+		{ChannelId: NewId(), UserId: NewId(), LastUpdateAt: 2000},
+	}
+
+	etag := members.Etag()
+	if etag != members.Etag() {
+		t.Fatal("etag should be stable")
+	}
+
+	members[0].LastUpdateAt = 3000
+	if members.Etag() == etag {
+		t.Fatal("etag should change when a member is updated")
+	}
+
+	etag = members.Etag()
+	members = members[:1]
+	if members.Etag() == etag {
+		t.Fatal("etag should change when a member is removed")
+	}
+}
+
+func TestIsChannelSnoozedAt(t *testing.T) {
+	props := GetDefaultChannelNotifyProps()
+	if IsChannelSnoozedAt(props, 1000) {
+		t.Fatal("should not be snoozed without a time")
+	}
+
+	props[MUTED_UNTIL_NOTIFY_PROP] = "2000"
+	if !IsChannelSnoozedAt(props, 1000) {
+		t.Fatal("should be snoozed before the time")
+	}
+
+	if IsChannelSnoozedAt(props, 2000) {
+		t.Fatal("should not be snoozed once the time has passed")
+	}
+
+	props[MUTED_UNTIL_NOTIFY_PROP] = "junk"
+	if IsChannelSnoozedAt(props, 1000) {
+		t.Fatal("should ignore an invalid time")
+	}
+}
+
 func TestChannelUnreadJson(t *testing.T) {
 	o := ChannelUnread{ChannelId: NewId(), TeamId: NewId(), MsgCount: 5, MentionCount: 3}
 	json := o.ToJson()
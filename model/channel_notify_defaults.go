@@ -0,0 +1,84 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// ChannelNotifyDefaults holds the notification settings that a channel's admins have chosen for new members of the
+// channel. They're copied into a member's notify props when the member joins, after which the member can change them.
+type ChannelNotifyDefaults struct {
+	ChannelId   string    `json:"channel_id"`
+	NotifyProps StringMap `json:"notify_props"`
+	UpdateAt    int64     `json:"update_at"`
+	UpdatedBy   string    `json:"updated_by"`
+}
+
+func (o *ChannelNotifyDefaults) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ChannelNotifyDefaultsFromJson(data io.Reader) *ChannelNotifyDefaults {
+	var o *ChannelNotifyDefaults
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *ChannelNotifyDefaults) PreSave() {
+	if o.NotifyProps == nil {
+		o.NotifyProps = StringMap{}
+	}
+
+	o.UpdateAt = GetMillis()
+}
+
+func (o *ChannelNotifyDefaults) IsValid() *AppError {
+	if len(o.ChannelId) != 26 {
+		return NewAppError("ChannelNotifyDefaults.IsValid", "model.channel_notify_defaults.is_valid.channel_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.UpdatedBy) != 26 {
+		return NewAppError("ChannelNotifyDefaults.IsValid", "model.channel_notify_defaults.is_valid.updated_by.app_error", nil, "channel_id="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("ChannelNotifyDefaults.IsValid", "model.channel_notify_defaults.is_valid.update_at.app_error", nil, "channel_id="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	for key, value := range o.NotifyProps {
+		var valid bool
+		switch key {
+		case DESKTOP_NOTIFY_PROP, PUSH_NOTIFY_PROP:
+			valid = IsChannelNotifyLevelValid(value)
+		case MARK_UNREAD_NOTIFY_PROP:
+			valid = IsChannelMarkUnreadLevelValid(value)
+		case EMAIL_NOTIFY_PROP:
+			valid = IsSendEmailValid(value)
+		}
+
+		if !valid {
+			return NewAppError("ChannelNotifyDefaults.IsValid", "model.channel_notify_defaults.is_valid.notify_props.app_error", nil, "channel_id="+o.ChannelId+", "+key+"="+value, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// ApplyTo returns a copy of the given member notify props with the channel's defaults applied over them.
+func (o *ChannelNotifyDefaults) ApplyTo(props StringMap) StringMap {
+	applied := StringMap{}
+	for key, value := range props {
+		applied[key] = value
+	}
+
+	for key, value := range o.NotifyProps {
+		applied[key] = value
+	}
+
+	return applied
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelNotifyDefaultsIsValid(t *testing.T) {
+	defaults := &ChannelNotifyDefaults{ChannelId: NewId(), UpdatedBy: NewId()}
+	require.NotNil(t, defaults.IsValid(), "should require the update time")
+
+	defaults.PreSave()
+	require.Nil(t, defaults.IsValid())
+
+	defaults.NotifyProps = StringMap{DESKTOP_NOTIFY_PROP: CHANNEL_NOTIFY_MENTION, PUSH_NOTIFY_PROP: CHANNEL_NOTIFY_NONE, MARK_UNREAD_NOTIFY_PROP: CHANNEL_MARK_UNREAD_MENTION, EMAIL_NOTIFY_PROP: "false"}
+	require.Nil(t, defaults.IsValid())
+
+	defaults.NotifyProps[DESKTOP_NOTIFY_PROP] = "sometimes"
+	assert.NotNil(t, defaults.IsValid())
+
+	defaults.NotifyProps = StringMap{MARK_UNREAD_NOTIFY_PROP: CHANNEL_NOTIFY_NONE}
+	assert.NotNil(t, defaults.IsValid())
+
+	defaults.NotifyProps = StringMap{"ignore_channel_mentions": "on"}
+	assert.NotNil(t, defaults.IsValid(), "should only allow the props that have defaults")
+
+	defaults.NotifyProps = nil
+	defaults.ChannelId = "junk"
+	assert.NotNil(t, defaults.IsValid())
+
+	defaults.ChannelId = NewId()
+	defaults.UpdatedBy = ""
+	assert.NotNil(t, defaults.IsValid())
+}
+
+func TestChannelNotifyDefaultsApplyTo(t *testing.T) {
+	defaults := &ChannelNotifyDefaults{NotifyProps: StringMap{DESKTOP_NOTIFY_PROP: CHANNEL_NOTIFY_MENTION, MARK_UNREAD_NOTIFY_PROP: CHANNEL_MARK_UNREAD_MENTION}}
+
+	props := GetDefaultChannelNotifyProps()
+	applied := defaults.ApplyTo(props)
+
+	assert.Equal(t, CHANNEL_NOTIFY_MENTION, applied[DESKTOP_NOTIFY_PROP])
+	assert.Equal(t, CHANNEL_MARK_UNREAD_MENTION, applied[MARK_UNREAD_NOTIFY_PROP])
+	assert.Equal(t, CHANNEL_NOTIFY_DEFAULT, applied[PUSH_NOTIFY_PROP])
+	assert.Equal(t, CHANNEL_NOTIFY_DEFAULT, props[DESKTOP_NOTIFY_PROP], "shouldn't change the given props")
+}
+
+func TestChannelNotifyDefaultsJson(t *testing.T) {
+	defaults := &ChannelNotifyDefaults{ChannelId: NewId(), NotifyProps: StringMap{DESKTOP_NOTIFY_PROP: CHANNEL_NOTIFY_MENTION}, UpdateAt: 1, UpdatedBy: NewId()}
+
+	assert.Equal(t, defaults, ChannelNotifyDefaultsFromJson(strings.NewReader(defaults.ToJson())))
+}
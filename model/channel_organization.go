@@ -0,0 +1,90 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	PREFERENCE_CATEGORY_SIDEBAR_SETTINGS    = "sidebar_settings"
+	PREFERENCE_NAME_CHANNEL_ORGANIZATION    = "channel_organization"
+	PREFERENCE_NAME_LEGACY_SIDEBAR_SETTINGS = ""
+
+	CHANNEL_ORGANIZATION_GROUPING_BY_TYPE = "by_type"
+	CHANNEL_ORGANIZATION_GROUPING_NONE    = "none"
+	CHANNEL_ORGANIZATION_SORTING_ALPHA    = "alpha"
+	CHANNEL_ORGANIZATION_SORTING_RECENT   = "recent"
+)
+
+// ChannelOrganization is how a user has chosen to organize the channels in their sidebar.
+type ChannelOrganization struct {
+	Grouping       string `json:"grouping"`
+	Sorting        string `json:"sorting"`
+	UnreadsAtTop   bool   `json:"unreads_at_top"`
+	FavoritesAtTop bool   `json:"favorites_at_top"`
+}
+
+func DefaultChannelOrganization() *ChannelOrganization {
+	return &ChannelOrganization{
+		Grouping:       CHANNEL_ORGANIZATION_GROUPING_BY_TYPE,
+		Sorting:        CHANNEL_ORGANIZATION_SORTING_ALPHA,
+		UnreadsAtTop:   true,
+		FavoritesAtTop: true,
+	}
+}
+
+func (o *ChannelOrganization) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ChannelOrganizationFromJson(data io.Reader) *ChannelOrganization {
+	var o *ChannelOrganization
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// ChannelOrganizationFromLegacyPreference reads the sidebar settings that older clients saved as a preference with
+// every value stored as a string. Any missing or unknown values are left as the defaults.
+func ChannelOrganizationFromLegacyPreference(value string) *ChannelOrganization {
+	organization := DefaultChannelOrganization()
+
+	var legacy map[string]string
+	if err := json.Unmarshal([]byte(value), &legacy); err != nil {
+		return organization
+	}
+
+	if grouping := legacy["grouping"]; grouping == CHANNEL_ORGANIZATION_GROUPING_BY_TYPE || grouping == CHANNEL_ORGANIZATION_GROUPING_NONE {
+		organization.Grouping = grouping
+	}
+
+	if sorting := legacy["sorting"]; sorting == CHANNEL_ORGANIZATION_SORTING_ALPHA || sorting == CHANNEL_ORGANIZATION_SORTING_RECENT {
+		organization.Sorting = sorting
+	}
+
+	if unreadsAtTop, ok := legacy["unreads_at_top"]; ok {
+		organization.UnreadsAtTop = unreadsAtTop == "true"
+	}
+
+	if favoritesAtTop, ok := legacy["favorite_at_top"]; ok {
+		organization.FavoritesAtTop = favoritesAtTop == "true"
+	}
+
+	return organization
+}
+
+func (o *ChannelOrganization) IsValid() *AppError {
+	if o.Grouping != CHANNEL_ORGANIZATION_GROUPING_BY_TYPE && o.Grouping != CHANNEL_ORGANIZATION_GROUPING_NONE {
+		return NewAppError("ChannelOrganization.IsValid", "model.channel_organization.is_valid.grouping.app_error", nil, "grouping="+o.Grouping, http.StatusBadRequest)
+	}
+
+	if o.Sorting != CHANNEL_ORGANIZATION_SORTING_ALPHA && o.Sorting != CHANNEL_ORGANIZATION_SORTING_RECENT {
+		return NewAppError("ChannelOrganization.IsValid", "model.channel_organization.is_valid.sorting.app_error", nil, "sorting="+o.Sorting, http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelOrganizationIsValid(t *testing.T) {
+	organization := DefaultChannelOrganization()
+	assert.Nil(t, organization.IsValid())
+
+	organization.Grouping = "by_colour"
+	assert.NotNil(t, organization.IsValid())
+
+	organization.Grouping = CHANNEL_ORGANIZATION_GROUPING_NONE
+	organization.Sorting = "random"
+	assert.NotNil(t, organization.IsValid())
+
+	organization.Sorting = CHANNEL_ORGANIZATION_SORTING_RECENT
+	assert.Nil(t, organization.IsValid())
+	assert.Equal(t, organization, ChannelOrganizationFromJson(strings.NewReader(organization.ToJson())))
+}
+
+func TestChannelOrganizationFromLegacyPreference(t *testing.T) {
+	organization := ChannelOrganizationFromLegacyPreference(`{"grouping":"none","sorting":"recent","unreads_at_top":"false","favorite_at_top":"true"}`)
+	assert.Equal(t, &ChannelOrganization{
+		Grouping:       CHANNEL_ORGANIZATION_GROUPING_NONE,
+		Sorting:        CHANNEL_ORGANIZATION_SORTING_RECENT,
+		UnreadsAtTop:   false,
+		FavoritesAtTop: true,
+	}, organization)
+
+	organization = ChannelOrganizationFromLegacyPreference(`{"grouping":"junk"}`)
+	assert.Equal(t, DefaultChannelOrganization(), organization, "should ignore unknown values")
+
+	organization = ChannelOrganizationFromLegacyPreference("not json")
+	assert.Equal(t, DefaultChannelOrganization(), organization)
+}
@@ -58,6 +58,14 @@ func TestChannelPatch(t *testing.T) {
 	if *p.Purpose != o.Purpose {
 		t.Fatal("do not match")
 	}
+
+	o.Patch(&ChannelPatch{ReadReceipts: NewBool(true)})
+	if !o.ReadReceipts {
+		t.Fatal("should have enabled read receipts")
+	}
+	if *p.Purpose != o.Purpose {
+		t.Fatal("should only have patched read receipts")
+	}
 }
 
 func TestChannelIsValid(t *testing.T) {
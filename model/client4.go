@@ -22,6 +22,8 @@ const (
 	HEADER_CLUSTER_ID         = "X-Cluster-ID"
 	HEADER_ETAG_SERVER        = "ETag"
 	HEADER_ETAG_CLIENT        = "If-None-Match"
+	HEADER_LAST_MODIFIED      = "Last-Modified"
+	HEADER_IF_MODIFIED_SINCE  = "If-Modified-Since"
 	HEADER_FORWARDED          = "X-Forwarded-For"
 	HEADER_REAL_IP            = "X-Real-IP"
 	HEADER_FORWARDED_PROTO    = "X-Forwarded-Proto"
@@ -30,6 +32,7 @@ const (
 	HEADER_AUTH               = "Authorization"
 	HEADER_REQUESTED_WITH     = "X-Requested-With"
 	HEADER_REQUESTED_WITH_XML = "XMLHttpRequest"
+	HEADER_NEXT_CURSOR        = "X-Next-Cursor"
 	STATUS                    = "status"
 	STATUS_OK                 = "OK"
 	STATUS_FAIL               = "FAIL"
@@ -39,6 +42,7 @@ const (
 
 	API_URL_SUFFIX_V1 = "/api/v1"
 	API_URL_SUFFIX_V4 = "/api/v4"
+	API_URL_SUFFIX_V5 = "/api/v5"
 	API_URL_SUFFIX    = API_URL_SUFFIX_V4
 )
 
@@ -217,6 +221,10 @@ func (c *Client4) GetPostsEphemeralRoute() string {
 	return fmt.Sprintf("/posts/ephemeral")
 }
 
+func (c *Client4) GetRemoteIdRoute(service, externalId string) string {
+	return fmt.Sprintf("/external/%v/%v", service, url.PathEscape(externalId))
+}
+
 func (c *Client4) GetConfigRoute() string {
 	return fmt.Sprintf("/config")
 }
@@ -229,6 +237,14 @@ func (c *Client4) GetPostRoute(postId string) string {
 	return fmt.Sprintf(c.GetPostsRoute()+"/%v", postId)
 }
 
+func (c *Client4) GetThreadsForUserRoute(userId string) string {
+	return fmt.Sprintf(c.GetUserRoute(userId) + "/threads")
+}
+
+func (c *Client4) GetThreadForUserRoute(userId, postId string) string {
+	return fmt.Sprintf(c.GetThreadsForUserRoute(userId)+"/%v", postId)
+}
+
 func (c *Client4) GetFilesRoute() string {
 	return fmt.Sprintf("/files")
 }
@@ -377,6 +393,50 @@ func (c *Client4) GetSchemeRoute(id string) string {
 	return c.GetSchemesRoute() + fmt.Sprintf("/%v", id)
 }
 
+func (c *Client4) GetChannelBridgesRoute() string {
+	return fmt.Sprintf("/bridges")
+}
+
+func (c *Client4) GetChannelBridgeRoute(bridgeId string) string {
+	return c.GetChannelBridgesRoute() + fmt.Sprintf("/%v", bridgeId)
+}
+
+func (c *Client4) GetUserGroupsRoute() string {
+	return fmt.Sprintf("/groups")
+}
+
+func (c *Client4) GetUserGroupRoute(groupId string) string {
+	return c.GetUserGroupsRoute() + fmt.Sprintf("/%v", groupId)
+}
+
+func (c *Client4) GetRemindersRoute() string {
+	return fmt.Sprintf("/reminders")
+}
+
+func (c *Client4) GetReminderRoute(reminderId string) string {
+	return c.GetRemindersRoute() + fmt.Sprintf("/%v", reminderId)
+}
+
+func (c *Client4) GetSidebarCategoriesRoute(userId, teamId string) string {
+	return c.GetUserRoute(userId) + c.GetTeamRoute(teamId) + "/channels/categories"
+}
+
+func (c *Client4) GetBotsRoute() string {
+	return fmt.Sprintf("/bots")
+}
+
+func (c *Client4) GetBotRoute(botUserId string) string {
+	return c.GetBotsRoute() + fmt.Sprintf("/%v", botUserId)
+}
+
+func (c *Client4) GetEventSubscriptionsRoute() string {
+	return fmt.Sprintf("/subscriptions")
+}
+
+func (c *Client4) GetEventSubscriptionRoute(subscriptionId string) string {
+	return c.GetEventSubscriptionsRoute() + fmt.Sprintf("/%v", subscriptionId)
+}
+
 func (c *Client4) GetAnalyticsRoute() string {
 	return fmt.Sprintf("/analytics")
 }
@@ -682,6 +742,16 @@ func (c *Client4) GetUserByEmail(email, etag string) (*User, *Response) {
 	}
 }
 
+// GetUserByRemoteId returns the user that was given the external id of the provided service.
+func (c *Client4) GetUserByRemoteId(service, externalId, etag string) (*User, *Response) {
+	if r, err := c.DoApiGet(c.GetUsersRoute()+c.GetRemoteIdRoute(service, externalId), etag); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // AutocompleteUsersInTeam returns the users on a team based on search term.
 func (c *Client4) AutocompleteUsersInTeam(teamId string, username string, etag string) (*UserAutocomplete, *Response) {
 	query := fmt.Sprintf("?in_team=%v&name=%v", teamId, username)
@@ -741,6 +811,52 @@ func (c *Client4) GetUsers(page int, perPage int, etag string) ([]*User, *Respon
 	}
 }
 
+// GetUserDirectory returns a page of users matching the directory options. Must be a system administrator.
+func (c *Client4) GetUserDirectory(options *UserDirectoryOptions, page int, perPage int) ([]*User, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if encoded := options.ToQueryString(); encoded != "" {
+		query += "&" + encoded
+	}
+
+	if r, err := c.DoApiGet(c.GetUsersRoute()+"/directory"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetInactiveUsersPreview returns the users that the inactive user job would flag or deactivate if it ran now.
+// Must be a system administrator.
+func (c *Client4) GetInactiveUsersPreview() ([]*InactiveUserAction, *Response) {
+	if r, err := c.DoApiGet(c.GetUsersRoute()+"/inactive/preview", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return InactiveUserActionListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ExportUserDirectory returns every user matching the directory options as CSV. Must be a system administrator.
+func (c *Client4) ExportUserDirectory(options *UserDirectoryOptions) ([]byte, *Response) {
+	query := "?format=csv"
+	if encoded := options.ToQueryString(); encoded != "" {
+		query += "&" + encoded
+	}
+
+	if r, err := c.DoApiGet(c.GetUsersRoute()+"/directory"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+
+		if data, err := ioutil.ReadAll(r.Body); err != nil {
+			return nil, BuildErrorResponse(r, NewAppError("ExportUserDirectory", "model.client.read_file.app_error", nil, err.Error(), r.StatusCode))
+		} else {
+			return data, BuildResponse(r)
+		}
+	}
+}
+
 // GetUsersInTeam returns a page of users on a team. Page counting starts at 0.
 func (c *Client4) GetUsersInTeam(teamId string, page int, perPage int, etag string) ([]*User, *Response) {
 	query := fmt.Sprintf("?in_team=%v&page=%v&per_page=%v", teamId, page, perPage)
@@ -1045,6 +1161,36 @@ func (c *Client4) AttachDeviceId(deviceId string) (bool, *Response) {
 	}
 }
 
+// CreateWebPushSubscription registers a browser's push subscription for the current session of the given user.
+func (c *Client4) CreateWebPushSubscription(userId string, subscription *WebPushSubscription) (*WebPushSubscription, *Response) {
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+"/webpush/subscriptions", subscription.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return WebPushSubscriptionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetWebPushSubscriptions returns the web push subscriptions of a user's active sessions.
+func (c *Client4) GetWebPushSubscriptions(userId string) ([]*WebPushSubscription, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/webpush/subscriptions", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return WebPushSubscriptionListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DeleteWebPushSubscription removes one of a user's web push subscriptions.
+func (c *Client4) DeleteWebPushSubscription(userId, subscriptionId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId) + "/webpush/subscriptions/" + subscriptionId); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
 // GetTeamsUnreadForUser will return an array with TeamUnread objects that contain the amount
 // of unread messages and mentions the current user has for the teams it belongs to.
 // An optional team ID can be set to exclude that team from the results. Must be authenticated.
@@ -1382,6 +1528,27 @@ func (c *Client4) PermanentDeleteTeam(teamId string) (bool, *Response) {
 	}
 }
 
+// ScheduleTeamDeletion archives the team and schedules it to be exported and permanently deleted once the
+// deletion grace period has passed.
+func (c *Client4) ScheduleTeamDeletion(teamId string) (*Team, *Response) {
+	if r, err := c.DoApiPost(c.GetTeamRoute(teamId)+"/deletion", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return TeamFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// CancelTeamDeletion restores a team that is scheduled for deletion.
+func (c *Client4) CancelTeamDeletion(teamId string) (*Team, *Response) {
+	if r, err := c.DoApiDelete(c.GetTeamRoute(teamId) + "/deletion"); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return TeamFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetTeamMembers returns team members based on the provided team id string.
 func (c *Client4) GetTeamMembers(teamId string, page int, perPage int, etag string) ([]*TeamMember, *Response) {
 	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
@@ -1484,6 +1651,28 @@ func (c *Client4) GetTeamStats(teamId, etag string) (*TeamStats, *Response) {
 	}
 }
 
+// GetTeamUploadPolicy returns the policy that restricts the files that can be uploaded to a team.
+// Must be authenticated.
+func (c *Client4) GetTeamUploadPolicy(teamId string) (*TeamUploadPolicy, *Response) {
+	if r, err := c.DoApiGet(c.GetTeamRoute(teamId)+"/upload_policy", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return TeamUploadPolicyFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateTeamUploadPolicy replaces the policy that restricts the files that can be uploaded to a team.
+// Must have manage_system permission.
+func (c *Client4) UpdateTeamUploadPolicy(teamId string, policy *TeamUploadPolicy) (*TeamUploadPolicy, *Response) {
+	if r, err := c.DoApiPut(c.GetTeamRoute(teamId)+"/upload_policy", policy.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return TeamUploadPolicyFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetTotalUsersStats returns a total system user stats.
 // Must be authenticated.
 func (c *Client4) GetTotalUsersStats(etag string) (*UsersStats, *Response) {
@@ -1703,6 +1892,16 @@ func (c *Client4) GetChannel(channelId, etag string) (*Channel, *Response) {
 	}
 }
 
+// GetChannelByRemoteId returns the channel that was given the external id of the provided service.
+func (c *Client4) GetChannelByRemoteId(service, externalId, etag string) (*Channel, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelsRoute()+c.GetRemoteIdRoute(service, externalId), etag); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetChannelStats returns statistics for a channel.
 func (c *Client4) GetChannelStats(channelId string, etag string) (*ChannelStats, *Response) {
 	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/stats", etag); err != nil {
@@ -1845,6 +2044,17 @@ func (c *Client4) GetChannelMembersByIds(channelId string, userIds []string) (*C
 	}
 }
 
+// GetChannelMembersForUserByChannelIds gets a user's memberships in the given channels, leaving out the channels
+// that the user isn't a member of.
+func (c *Client4) GetChannelMembersForUserByChannelIds(userId string, channelIds []string) (*ChannelMembers, *Response) {
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+"/channel_members/ids", ArrayToJson(channelIds)); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelMembersFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetChannelMember gets a channel member.
 func (c *Client4) GetChannelMember(channelId, userId, etag string) (*ChannelMember, *Response) {
 	if r, err := c.DoApiGet(c.GetChannelMemberRoute(channelId, userId), etag); err != nil {
@@ -2023,6 +2233,87 @@ func (c *Client4) UnpinPost(postId string) (bool, *Response) {
 	}
 }
 
+// AcknowledgePost records that a user has seen a post whose sender asked for acknowledgement.
+func (c *Client4) AcknowledgePost(userId, postId string) (*PostAcknowledgement, *Response) {
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+c.GetPostRoute(postId)+"/ack", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostAcknowledgementFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UnacknowledgePost removes a user's acknowledgement of a post.
+func (c *Client4) UnacknowledgePost(userId, postId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId) + c.GetPostRoute(postId) + "/ack"); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetThreadsForUser gets a page of the threads that a user follows, most recently updated first.
+func (c *Client4) GetThreadsForUser(userId string, page, perPage int) ([]*Thread, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetThreadsForUserRoute(userId)+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ThreadsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetThreadForUser gets a thread as seen by one of its members.
+func (c *Client4) GetThreadForUser(userId, postId string) (*Thread, *Response) {
+	if r, err := c.DoApiGet(c.GetThreadForUserRoute(userId, postId), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ThreadFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// FollowThread makes a user follow the thread started by a post.
+func (c *Client4) FollowThread(userId, postId string) (*Thread, *Response) {
+	if r, err := c.DoApiPut(c.GetThreadForUserRoute(userId, postId)+"/following", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ThreadFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UnfollowThread stops a user from following the thread started by a post.
+func (c *Client4) UnfollowThread(userId, postId string) (*Thread, *Response) {
+	if r, err := c.DoApiDelete(c.GetThreadForUserRoute(userId, postId) + "/following"); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ThreadFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// MarkThreadAsRead records that a user has seen every reply in a thread that they're a member of.
+func (c *Client4) MarkThreadAsRead(userId, postId string) (*Thread, *Response) {
+	if r, err := c.DoApiPut(c.GetThreadForUserRoute(userId, postId)+"/read", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ThreadFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetPostAcknowledgements gets the acknowledgements of a post in the order that they were made.
+func (c *Client4) GetPostAcknowledgements(postId string) ([]*PostAcknowledgement, *Response) {
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/acknowledgements", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostAcknowledgementsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetPost gets a single post.
 func (c *Client4) GetPost(postId string, etag string) (*Post, *Response) {
 	if r, err := c.DoApiGet(c.GetPostRoute(postId), etag); err != nil {
@@ -2033,6 +2324,27 @@ func (c *Client4) GetPost(postId string, etag string) (*Post, *Response) {
 	}
 }
 
+// GetPushNotificationForPost gets the full contents of the push notification that the current user was sent for a
+// post. It's used to load the contents of notifications sent when PushNotificationContents is set to "id_loaded".
+func (c *Client4) GetPushNotificationForPost(postId string) (*PushNotification, *Response) {
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/push_notification", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PushNotificationFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetPostByRemoteId gets the post that was given the external id of the provided service.
+func (c *Client4) GetPostByRemoteId(service, externalId, etag string) (*Post, *Response) {
+	if r, err := c.DoApiGet(c.GetPostsRoute()+c.GetRemoteIdRoute(service, externalId), etag); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // DeletePost deletes a post from the provided post id string.
 func (c *Client4) DeletePost(postId string) (bool, *Response) {
 	if r, err := c.DoApiDelete(c.GetPostRoute(postId)); err != nil {
@@ -2116,6 +2428,22 @@ func (c *Client4) GetPostsSince(channelId string, time int64) (*PostList, *Respo
 	}
 }
 
+// GetPostEventsForChannel gets the changes made to posts in a channel after the given cursor. Pass a negative cursor
+// to only get the cursor to start following the channel from.
+func (c *Client4) GetPostEventsForChannel(channelId string, after int64, limit int) (*PostEventList, *Response) {
+	query := fmt.Sprintf("?limit=%v", limit)
+	if after >= 0 {
+		query += fmt.Sprintf("&after=%v", after)
+	}
+
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/posts/events"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostEventListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetPostsAfter gets a page of posts that were posted after the post provided.
 func (c *Client4) GetPostsAfter(channelId, postId string, page, perPage int, etag string) (*PostList, *Response) {
 	query := fmt.Sprintf("?page=%v&per_page=%v&after=%v", page, perPage, postId)
@@ -2138,6 +2466,30 @@ func (c *Client4) GetPostsBefore(channelId, postId string, page, perPage int, et
 	}
 }
 
+// GetPostsBeforeCursor gets the posts that are older than the cursor. The returned list holds the cursors to use to
+// get the posts on either side of it.
+func (c *Client4) GetPostsBeforeCursor(channelId string, cursor string, perPage int) (*PostList, *Response) {
+	query := fmt.Sprintf("?per_page=%v&before_cursor=%v", perPage, url.QueryEscape(cursor))
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/posts"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetPostsAfterCursor gets the posts that are newer than the cursor. The returned list holds the cursors to use to
+// get the posts on either side of it.
+func (c *Client4) GetPostsAfterCursor(channelId string, cursor string, perPage int) (*PostList, *Response) {
+	query := fmt.Sprintf("?per_page=%v&after_cursor=%v", perPage, url.QueryEscape(cursor))
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/posts"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // SearchPosts returns any posts with matching terms string.
 func (c *Client4) SearchPosts(teamId string, terms string, isOrSearch bool) (*PostList, *Response) {
 	params := SearchParameter{
@@ -2189,6 +2541,27 @@ func (c *Client4) DoPostAction(postId, actionId string) (bool, *Response) {
 	}
 }
 
+// GetPostActionOptions gets the options of a menu that loads them from its integration.
+func (c *Client4) GetPostActionOptions(postId, actionId string) ([]*PostActionOptions, *Response) {
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/actions/"+actionId+"/options", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PostActionOptionsListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// SubmitInteractiveDialog submits a dialog that was opened for the user, returning the errors for any of its fields
+// that were rejected.
+func (c *Client4) SubmitInteractiveDialog(request SubmitDialogRequest) (*SubmitDialogResponse, *Response) {
+	if r, err := c.DoApiPost("/actions/dialogs/submit", request.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return SubmitDialogResponseFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // File Section
 
 // UploadFile will upload a file to a channel using a multipart request, to be later attached to a post.
@@ -2343,6 +2716,17 @@ func (c *Client4) GetFileInfosForPost(postId string, etag string) ([]*FileInfo,
 	}
 }
 
+// GetFileInfosByIds gets the file infos for several files at once. Files that the user can't see are left out.
+func (c *Client4) GetFileInfosByIds(fileIds []string) ([]*FileInfo, *Response) {
+	query := fmt.Sprintf("?ids=%v", url.QueryEscape(strings.Join(fileIds, ",")))
+	if r, err := c.DoApiGet(c.GetFilesRoute()+"/infos"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return FileInfosFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // General/System Section
 
 // GetPing will return ok if the running goRoutines are below the threshold and unhealthy for above.
@@ -2421,6 +2805,29 @@ func (c *Client4) GetEnvironmentConfig() (map[string]interface{}, *Response) {
 	}
 }
 
+// ExportConfig will retrieve the sanitized server configuration along with the settings that
+// are set through environment variables.
+func (c *Client4) ExportConfig() (*ConfigExport, *Response) {
+	if r, err := c.DoApiGet(c.GetConfigRoute()+"/export", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ConfigExportFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ImportConfig will replace the server configuration with an exported one and return the
+// settings that changed. When dryRun is true, the configuration is only validated and the
+// settings that would change are returned.
+func (c *Client4) ImportConfig(export *ConfigExport, dryRun bool) (*ConfigImportResult, *Response) {
+	if r, err := c.DoApiPost(c.GetConfigRoute()+fmt.Sprintf("/import?dry_run=%v", dryRun), export.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ConfigImportResultFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetOldClientLicense will retrieve the parts of the server license needed by the
 // client, formatted in the old format.
 func (c *Client4) GetOldClientLicense(etag string) (map[string]string, *Response) {
@@ -2522,29 +2929,97 @@ func (c *Client4) GetAnalyticsOld(name, teamId string) (AnalyticsRows, *Response
 	}
 }
 
-// Webhooks Section
-
-// CreateIncomingWebhook creates an incoming webhook for a channel.
-func (c *Client4) CreateIncomingWebhook(hook *IncomingWebhook) (*IncomingWebhook, *Response) {
-	if r, err := c.DoApiPost(c.GetIncomingWebhooksRoute(), hook.ToJson()); err != nil {
+// GetIntegrationTraffic returns the traffic that the server handling the request has received from each integration.
+// Must be a system administrator.
+func (c *Client4) GetIntegrationTraffic() (*IntegrationTrafficReport, *Response) {
+	if r, err := c.DoApiGet(c.GetSystemRoute()+"/integration_traffic", ""); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return IncomingWebhookFromJson(r.Body), BuildResponse(r)
+		return IntegrationTrafficReportFromJson(r.Body), BuildResponse(r)
 	}
 }
 
-// UpdateIncomingWebhook updates an incoming webhook for a channel.
-func (c *Client4) UpdateIncomingWebhook(hook *IncomingWebhook) (*IncomingWebhook, *Response) {
-	if r, err := c.DoApiPut(c.GetIncomingWebhookRoute(hook.Id), hook.ToJson()); err != nil {
-		return nil, BuildErrorResponse(r, err)
+// ResetIntegrationTraffic clears the integration traffic report of the server handling the request. Must be a system
+// administrator.
+func (c *Client4) ResetIntegrationTraffic() (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetSystemRoute() + "/integration_traffic"); err != nil {
+		return false, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return IncomingWebhookFromJson(r.Body), BuildResponse(r)
+		return CheckStatusOK(r), BuildResponse(r)
 	}
 }
 
-// GetIncomingWebhooks returns a page of incoming webhooks on the system. Page counting starts at 0.
+// GetStoreTracing returns the calls that the server handling the request has made to each store method while
+// MetricsSettings.EnableStoreTracing was on. Must be a system administrator.
+func (c *Client4) GetStoreTracing() (*StoreTracingReport, *Response) {
+	if r, err := c.DoApiGet(c.GetSystemRoute()+"/store_tracing", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return StoreTracingReportFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ResetStoreTracing clears the store tracing report of the server handling the request. Must be a system
+// administrator.
+func (c *Client4) ResetStoreTracing() (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetSystemRoute() + "/store_tracing"); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetIntegrationDeliveries returns a page of the requests that were recently sent to or received from integrations,
+// newest first. The type and id of an integration can be given to only get its deliveries. Must be a system
+// administrator.
+func (c *Client4) GetIntegrationDeliveries(integrationType string, integrationId string, page int, perPage int) ([]*IntegrationDelivery, *Response) {
+	query := fmt.Sprintf("?integration_type=%v&integration_id=%v&page=%v&per_page=%v", url.QueryEscape(integrationType), url.QueryEscape(integrationId), page, perPage)
+	if r, err := c.DoApiGet(c.GetSystemRoute()+"/integration_deliveries"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return IntegrationDeliveryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetErrorCatalog returns the codes that errors returned by the API can have, along with hints for how to resolve them
+// in the user's language.
+func (c *Client4) GetErrorCatalog() ([]*ErrorCatalogEntry, *Response) {
+	if r, err := c.DoApiGet(c.GetSystemRoute()+"/errors", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ErrorCatalogFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Webhooks Section
+
+// CreateIncomingWebhook creates an incoming webhook for a channel.
+func (c *Client4) CreateIncomingWebhook(hook *IncomingWebhook) (*IncomingWebhook, *Response) {
+	if r, err := c.DoApiPost(c.GetIncomingWebhooksRoute(), hook.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return IncomingWebhookFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateIncomingWebhook updates an incoming webhook for a channel.
+func (c *Client4) UpdateIncomingWebhook(hook *IncomingWebhook) (*IncomingWebhook, *Response) {
+	if r, err := c.DoApiPut(c.GetIncomingWebhookRoute(hook.Id), hook.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return IncomingWebhookFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetIncomingWebhooks returns a page of incoming webhooks on the system. Page counting starts at 0.
 func (c *Client4) GetIncomingWebhooks(page int, perPage int, etag string) ([]*IncomingWebhook, *Response) {
 	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
 	if r, err := c.DoApiGet(c.GetIncomingWebhooksRoute()+query, etag); err != nil {
@@ -2659,6 +3134,26 @@ func (c *Client4) RegenOutgoingHookToken(hookId string) (*OutgoingWebhook, *Resp
 	}
 }
 
+// RegenOutgoingHookSigningSecret replaces the secret that the outgoing webhook's requests are signed with.
+func (c *Client4) RegenOutgoingHookSigningSecret(hookId string) (*OutgoingWebhook, *Response) {
+	if r, err := c.DoApiPost(c.GetOutgoingWebhookRoute(hookId)+"/regen_signing_secret", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return OutgoingWebhookFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetOutgoingHookDeliveries returns the recent deliveries of the outgoing webhook, newest first.
+func (c *Client4) GetOutgoingHookDeliveries(hookId string) ([]*OutgoingWebhookDelivery, *Response) {
+	if r, err := c.DoApiGet(c.GetOutgoingWebhookRoute(hookId)+"/deliveries", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return OutgoingWebhookDeliveryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // DeleteOutgoingWebhook delete the outgoing webhook on the system requested by Hook Id.
 func (c *Client4) DeleteOutgoingWebhook(hookId string) (bool, *Response) {
 	if r, err := c.DoApiDelete(c.GetOutgoingWebhookRoute(hookId)); err != nil {
@@ -2669,6 +3164,67 @@ func (c *Client4) DeleteOutgoingWebhook(hookId string) (bool, *Response) {
 	}
 }
 
+// Event Subscriptions Section
+
+// CreateEventSubscription registers a callback URL that's sent an event whenever it happens.
+func (c *Client4) CreateEventSubscription(subscription *EventSubscription) (*EventSubscription, *Response) {
+	if r, err := c.DoApiPost(c.GetEventSubscriptionsRoute(), subscription.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return EventSubscriptionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetEventSubscriptionsForTeam returns a page of the event subscriptions in a team.
+func (c *Client4) GetEventSubscriptionsForTeam(teamId string, page int, perPage int) ([]*EventSubscription, *Response) {
+	query := fmt.Sprintf("?team_id=%v&page=%v&per_page=%v", teamId, page, perPage)
+	if r, err := c.DoApiGet(c.GetEventSubscriptionsRoute()+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return EventSubscriptionListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) GetEventSubscription(subscriptionId string) (*EventSubscription, *Response) {
+	if r, err := c.DoApiGet(c.GetEventSubscriptionRoute(subscriptionId), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return EventSubscriptionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// PatchEventSubscription changes a subscription's callback URL or description, or activates or deactivates it.
+func (c *Client4) PatchEventSubscription(subscriptionId string, patch *EventSubscriptionPatch) (*EventSubscription, *Response) {
+	if r, err := c.DoApiPut(c.GetEventSubscriptionRoute(subscriptionId)+"/patch", patch.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return EventSubscriptionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RegenEventSubscriptionSigningSecret replaces the secret that deliveries to the subscription are signed with.
+func (c *Client4) RegenEventSubscriptionSigningSecret(subscriptionId string) (*EventSubscription, *Response) {
+	if r, err := c.DoApiPost(c.GetEventSubscriptionRoute(subscriptionId)+"/regen_signing_secret", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return EventSubscriptionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) DeleteEventSubscription(subscriptionId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetEventSubscriptionRoute(subscriptionId)); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
 // Preferences Section
 
 // GetPreferences returns the user's preferences.
@@ -2934,6 +3490,45 @@ func (c *Client4) GetAudits(page int, perPage int, etag string) (Audits, *Respon
 	}
 }
 
+// SearchAudits returns the newest audits matching the options, along with the cursor to use in the options to get
+// the audits after them. The cursor is nil when there are no more audits. Must be a system administrator.
+func (c *Client4) SearchAudits(options *AuditSearchOptions, perPage int) (Audits, *AuditCursor, *Response) {
+	query := fmt.Sprintf("?per_page=%v", perPage)
+	if encoded := options.ToQueryString(); encoded != "" {
+		query += "&" + encoded
+	}
+
+	if r, err := c.DoApiGet("/audits"+query, ""); err != nil {
+		return nil, nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+
+		next, _ := AuditCursorFromString(r.Header.Get(HEADER_NEXT_CURSOR))
+		return AuditsFromJson(r.Body), next, BuildResponse(r)
+	}
+}
+
+// ExportAudits returns every audit matching the options in the given format, either "csv" or "json". Must be a
+// system administrator.
+func (c *Client4) ExportAudits(options *AuditSearchOptions, format string) ([]byte, *Response) {
+	query := "?format=" + url.QueryEscape(format)
+	if encoded := options.ToQueryString(); encoded != "" {
+		query += "&" + encoded
+	}
+
+	if r, err := c.DoApiGet("/audits"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+
+		if data, err := ioutil.ReadAll(r.Body); err != nil {
+			return nil, BuildErrorResponse(r, NewAppError("ExportAudits", "model.client.read_file.app_error", nil, err.Error(), r.StatusCode))
+		} else {
+			return data, BuildResponse(r)
+		}
+	}
+}
+
 // Brand Section
 
 // GetBrandImage retrieves the previously uploaded brand image.
@@ -3066,6 +3661,21 @@ func (c *Client4) GetOAuthAppInfo(appId string) (*OAuthApp, *Response) {
 	}
 }
 
+// GetOAuthScopes describes the scopes that OAuth 2.0 apps can request, or only the ones in scope if it isn't empty.
+func (c *Client4) GetOAuthScopes(scope string) ([]*OAuthScope, *Response) {
+	query := ""
+	if len(scope) > 0 {
+		query = "?scope=" + url.QueryEscape(scope)
+	}
+
+	if r, err := c.DoApiGet("/oauth/scopes"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return OAuthScopeListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // DeleteOAuthApp deletes a registered OAuth 2.0 client application.
 func (c *Client4) DeleteOAuthApp(appId string) (bool, *Response) {
 	if r, err := c.DoApiDelete(c.GetOAuthAppRoute(appId)); err != nil {
@@ -3086,6 +3696,77 @@ func (c *Client4) RegenerateOAuthAppSecret(appId string) (*OAuthApp, *Response)
 	}
 }
 
+// PatchOAuthApp changes how a registered OAuth 2.0 client application is described and where it can redirect users to.
+func (c *Client4) PatchOAuthApp(appId string, patch *OAuthAppPatch) (*OAuthApp, *Response) {
+	if r, err := c.DoApiPut(c.GetOAuthAppRoute(appId)+"/patch", patch.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return OAuthAppFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// SetOAuthAppIcon uploads an icon for a registered OAuth 2.0 client application.
+func (c *Client4) SetOAuthAppIcon(appId string, data []byte) (*OAuthApp, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if part, err := writer.CreateFormFile("image", "icon.png"); err != nil {
+		return nil, &Response{Error: NewAppError("SetOAuthAppIcon", "model.client.set_oauth_app_icon.no_file.app_error", nil, err.Error(), http.StatusBadRequest)}
+	} else if _, err = io.Copy(part, bytes.NewBuffer(data)); err != nil {
+		return nil, &Response{Error: NewAppError("SetOAuthAppIcon", "model.client.set_oauth_app_icon.no_file.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, &Response{Error: NewAppError("SetOAuthAppIcon", "model.client.set_oauth_app_icon.writer.app_error", nil, err.Error(), http.StatusBadRequest)}
+	}
+
+	rq, _ := http.NewRequest("POST", c.ApiUrl+c.GetOAuthAppRoute(appId)+"/icon", bytes.NewReader(body.Bytes()))
+	rq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if len(c.AuthToken) > 0 {
+		rq.Header.Set(HEADER_AUTH, c.AuthType+" "+c.AuthToken)
+	}
+
+	if rp, err := c.HttpClient.Do(rq); err != nil || rp == nil {
+		// set to http.StatusForbidden(403)
+		return nil, &Response{StatusCode: http.StatusForbidden, Error: NewAppError(c.GetOAuthAppRoute(appId)+"/icon", "model.client.connecting.app_error", nil, err.Error(), 403)}
+	} else {
+		defer closeBody(rp)
+
+		if rp.StatusCode >= 300 {
+			return nil, BuildErrorResponse(rp, AppErrorFromJson(rp.Body))
+		} else {
+			return OAuthAppFromJson(rp.Body), BuildResponse(rp)
+		}
+	}
+}
+
+// GetOAuthAppIcon gets the icon that was uploaded for a registered OAuth 2.0 client application.
+func (c *Client4) GetOAuthAppIcon(appId, etag string) ([]byte, *Response) {
+	if r, err := c.DoApiGet(c.GetOAuthAppRoute(appId)+"/icon", etag); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+
+		if data, err := ioutil.ReadAll(r.Body); err != nil {
+			return nil, BuildErrorResponse(r, NewAppError("GetOAuthAppIcon", "model.client.get_oauth_app_icon.app_error", nil, err.Error(), r.StatusCode))
+		} else {
+			return data, BuildResponse(r)
+		}
+	}
+}
+
+// RemoveOAuthAppIcon stops using the icon that was uploaded for a registered OAuth 2.0 client application.
+func (c *Client4) RemoveOAuthAppIcon(appId string) (*OAuthApp, *Response) {
+	if r, err := c.DoApiDelete(c.GetOAuthAppRoute(appId) + "/icon"); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return OAuthAppFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetAuthorizedOAuthAppsForUser gets a page of OAuth 2.0 client applications the user has authorized to use access their account.
 func (c *Client4) GetAuthorizedOAuthAppsForUser(userId string, page, perPage int) ([]*OAuthApp, *Response) {
 	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
@@ -3196,6 +3877,16 @@ func (c *Client4) UpdateCommand(cmd *Command) (*Command, *Response) {
 	}
 }
 
+// PatchCommand partially updates how a command is described to users, such as its icon and autocomplete hints.
+func (c *Client4) PatchCommand(commandId string, patch *CommandPatch) (*Command, *Response) {
+	if r, err := c.DoApiPut(c.GetCommandRoute(commandId)+"/patch", patch.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CommandFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // DeleteCommand deletes a command based on the provided command id string
 func (c *Client4) DeleteCommand(commandId string) (bool, *Response) {
 	if r, err := c.DoApiDelete(c.GetCommandRoute(commandId)); err != nil {
@@ -3261,6 +3952,17 @@ func (c *Client4) ListAutocompleteCommands(teamId string) ([]*Command, *Response
 	}
 }
 
+// ListCommandAutocompleteSuggestions returns the ways that a user could continue typing a command in a channel.
+func (c *Client4) ListCommandAutocompleteSuggestions(userInput string, teamId string, channelId string) ([]*AutocompleteSuggestion, *Response) {
+	query := fmt.Sprintf("?user_input=%v&channel_id=%v", url.QueryEscape(userInput), channelId)
+	if r, err := c.DoApiGet(c.GetTeamRoute(teamId)+"/commands/autocomplete_suggestions"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return AutocompleteSuggestionsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // RegenCommandToken will create a new token if the user have the right permissions.
 func (c *Client4) RegenCommandToken(commandId string) (string, *Response) {
 	if r, err := c.DoApiPut(c.GetCommandRoute(commandId)+"/regen_token", ""); err != nil {
@@ -3657,71 +4359,57 @@ func (c *Client4) GetChannelsForScheme(schemeId string, page int, perPage int) (
 	}
 }
 
-// Plugin Section
-
-// UploadPlugin takes an io.Reader stream pointing to the contents of a .tar.gz plugin.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) UploadPlugin(file io.Reader) (*Manifest, *Response) {
-	body := new(bytes.Buffer)
-	writer := multipart.NewWriter(body)
-
-	if part, err := writer.CreateFormFile("plugin", "plugin.tar.gz"); err != nil {
-		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
-	} else if _, err = io.Copy(part, file); err != nil {
-		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
-	}
+// Channel Bridges Section
 
-	if err := writer.Close(); err != nil {
-		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
+// CreateChannelBridge bridges a channel to a channel on another service. The returned bridge includes the token that
+// its adapter uses to send events.
+func (c *Client4) CreateChannelBridge(bridge *ChannelBridge) (*ChannelBridge, *Response) {
+	if r, err := c.DoApiPost(c.GetChannelBridgesRoute(), bridge.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelBridgeFromJson(r.Body), BuildResponse(r)
 	}
+}
 
-	rq, _ := http.NewRequest("POST", c.ApiUrl+c.GetPluginsRoute(), body)
-	rq.Header.Set("Content-Type", writer.FormDataContentType())
-
-	if len(c.AuthToken) > 0 {
-		rq.Header.Set(HEADER_AUTH, c.AuthType+" "+c.AuthToken)
+// GetChannelBridges returns all of the channel bridges, or only the ones for a channel if channelId isn't empty.
+func (c *Client4) GetChannelBridges(channelId string) ([]*ChannelBridge, *Response) {
+	query := ""
+	if len(channelId) > 0 {
+		query = "?channel_id=" + channelId
 	}
 
-	if rp, err := c.HttpClient.Do(rq); err != nil || rp == nil {
-		return nil, BuildErrorResponse(rp, NewAppError("UploadPlugin", "model.client.connecting.app_error", nil, err.Error(), 0))
+	if r, err := c.DoApiGet(c.GetChannelBridgesRoute()+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	} else {
-		defer closeBody(rp)
-
-		if rp.StatusCode >= 300 {
-			return nil, BuildErrorResponse(rp, AppErrorFromJson(rp.Body))
-		} else {
-			return ManifestFromJson(rp.Body), BuildResponse(rp)
-		}
+		defer closeBody(r)
+		return ChannelBridgeListFromJson(r.Body), BuildResponse(r)
 	}
 }
 
-// GetPlugins will return a list of plugin manifests for currently active plugins.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) GetPlugins() (*PluginsResponse, *Response) {
-	if r, err := c.DoApiGet(c.GetPluginsRoute(), ""); err != nil {
+// GetChannelBridge returns a channel bridge along with its token.
+func (c *Client4) GetChannelBridge(bridgeId string) (*ChannelBridge, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelBridgeRoute(bridgeId), ""); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return PluginsResponseFromJson(r.Body), BuildResponse(r)
+		return ChannelBridgeFromJson(r.Body), BuildResponse(r)
 	}
 }
 
-// GetPluginStatuses will return the plugins installed on any server in the cluster, for reporting
-// to the administrator via the system console.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) GetPluginStatuses() (PluginStatuses, *Response) {
-	if r, err := c.DoApiGet(c.GetPluginsRoute(), "/statuses"); err != nil {
+// UpdateChannelBridge changes the outgoing URL and conflict policy of a channel bridge.
+func (c *Client4) UpdateChannelBridge(bridge *ChannelBridge) (*ChannelBridge, *Response) {
+	if r, err := c.DoApiPut(c.GetChannelBridgeRoute(bridge.Id), bridge.ToJson()); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return PluginStatusesFromJson(r.Body), BuildResponse(r)
+		return ChannelBridgeFromJson(r.Body), BuildResponse(r)
 	}
 }
 
-// RemovePlugin will deactivate and delete a plugin.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) RemovePlugin(id string) (bool, *Response) {
-	if r, err := c.DoApiDelete(c.GetPluginRoute(id)); err != nil {
+// DeleteChannelBridge stops bridging a channel.
+func (c *Client4) DeleteChannelBridge(bridgeId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetChannelBridgeRoute(bridgeId)); err != nil {
 		return false, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
@@ -3729,25 +4417,151 @@ func (c *Client4) RemovePlugin(id string) (bool, *Response) {
 	}
 }
 
-// GetWebappPlugins will return a list of plugins that the webapp should download.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) GetWebappPlugins() ([]*Manifest, *Response) {
-	if r, err := c.DoApiGet(c.GetPluginsRoute()+"/webapp", ""); err != nil {
+// GetChannelBridgesHealth returns the health of every channel bridge.
+func (c *Client4) GetChannelBridgesHealth() ([]*ChannelBridgeHealth, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelBridgesRoute()+"/health", ""); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return ManifestListFromJson(r.Body), BuildResponse(r)
+		return ChannelBridgeHealthListFromJson(r.Body), BuildResponse(r)
 	}
 }
 
-// ActivatePlugin will activate an plugin installed.
-// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
-func (c *Client4) EnablePlugin(id string) (bool, *Response) {
-	if r, err := c.DoApiPost(c.GetPluginRoute(id)+"/enable", ""); err != nil {
-		return false, BuildErrorResponse(r, err)
+// GetChannelBridgeHealth returns the health of a channel bridge.
+func (c *Client4) GetChannelBridgeHealth(bridgeId string) (*ChannelBridgeHealth, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelBridgeRoute(bridgeId)+"/health", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	} else {
 		defer closeBody(r)
-		return CheckStatusOK(r), BuildResponse(r)
+		return ChannelBridgeHealthFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// SendChannelBridgeEvent sends a change made on the other side of a bridge, authenticated by the bridge's token, and
+// returns the post that was changed.
+func (c *Client4) SendChannelBridgeEvent(bridgeId string, token string, event *ChannelBridgeEvent) (*Post, *Response) {
+	rq, _ := http.NewRequest(http.MethodPost, c.ApiUrl+c.GetChannelBridgeRoute(bridgeId)+"/events", strings.NewReader(event.ToJson()))
+	rq.Header.Set(HEADER_BRIDGE_TOKEN, token)
+
+	if rp, err := c.HttpClient.Do(rq); err != nil || rp == nil {
+		return nil, &Response{Error: NewAppError("SendChannelBridgeEvent", "model.client.connecting.app_error", nil, err.Error(), 0)}
+	} else if rp.StatusCode >= 300 {
+		defer closeBody(rp)
+		return nil, BuildErrorResponse(rp, AppErrorFromJson(rp.Body))
+	} else {
+		defer closeBody(rp)
+		return PostFromJson(rp.Body), BuildResponse(rp)
+	}
+}
+
+// Plugin Section
+
+// UploadPlugin takes an io.Reader stream pointing to the contents of a .tar.gz plugin.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) UploadPlugin(file io.Reader) (*Manifest, *Response) {
+	return c.uploadPlugin(file, false)
+}
+
+// UploadPluginForced will upload a plugin, replacing the installed version of it if there is one. A plugin that's
+// active is upgraded to the new version without being deactivated.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) UploadPluginForced(file io.Reader) (*Manifest, *Response) {
+	return c.uploadPlugin(file, true)
+}
+
+func (c *Client4) uploadPlugin(file io.Reader, force bool) (*Manifest, *Response) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	if force {
+		if err := writer.WriteField("force", "true"); err != nil {
+			return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
+		}
+	}
+
+	if part, err := writer.CreateFormFile("plugin", "plugin.tar.gz"); err != nil {
+		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
+	} else if _, err = io.Copy(part, file); err != nil {
+		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, &Response{Error: NewAppError("UploadPlugin", "model.client.writer.app_error", nil, err.Error(), 0)}
+	}
+
+	rq, _ := http.NewRequest("POST", c.ApiUrl+c.GetPluginsRoute(), body)
+	rq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if len(c.AuthToken) > 0 {
+		rq.Header.Set(HEADER_AUTH, c.AuthType+" "+c.AuthToken)
+	}
+
+	if rp, err := c.HttpClient.Do(rq); err != nil || rp == nil {
+		return nil, BuildErrorResponse(rp, NewAppError("UploadPlugin", "model.client.connecting.app_error", nil, err.Error(), 0))
+	} else {
+		defer closeBody(rp)
+
+		if rp.StatusCode >= 300 {
+			return nil, BuildErrorResponse(rp, AppErrorFromJson(rp.Body))
+		} else {
+			return ManifestFromJson(rp.Body), BuildResponse(rp)
+		}
+	}
+}
+
+// GetPlugins will return a list of plugin manifests for currently active plugins.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) GetPlugins() (*PluginsResponse, *Response) {
+	if r, err := c.DoApiGet(c.GetPluginsRoute(), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PluginsResponseFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetPluginStatuses will return the plugins installed on any server in the cluster, for reporting
+// to the administrator via the system console.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) GetPluginStatuses() (PluginStatuses, *Response) {
+	if r, err := c.DoApiGet(c.GetPluginsRoute(), "/statuses"); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PluginStatusesFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RemovePlugin will deactivate and delete a plugin.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) RemovePlugin(id string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetPluginRoute(id)); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetWebappPlugins will return a list of plugins that the webapp should download.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) GetWebappPlugins() ([]*Manifest, *Response) {
+	if r, err := c.DoApiGet(c.GetPluginsRoute()+"/webapp", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ManifestListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ActivatePlugin will activate an plugin installed.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) EnablePlugin(id string) (bool, *Response) {
+	if r, err := c.DoApiPost(c.GetPluginRoute(id)+"/enable", ""); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
 	}
 }
 
@@ -3762,6 +4576,52 @@ func (c *Client4) DisablePlugin(id string) (bool, *Response) {
 	}
 }
 
+// GetPluginSettingOptions will return the options that a plugin provides for one of its dynamic dropdown settings.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) GetPluginSettingOptions(id, key string) ([]*PluginOption, *Response) {
+	query := "?key=" + url.QueryEscape(key)
+	if r, err := c.DoApiGet(c.GetPluginRoute(id)+"/settings/options"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PluginOptionListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetPluginLogs returns the most recent entries from a plugin's log on the server that handles the request. Clients
+// that want to receive new entries as they're logged can connect a websocket to the same route with follow=true.
+func (c *Client4) GetPluginLogs(id string) ([]PluginLogEntry, *Response) {
+	if r, err := c.DoApiGet(c.GetPluginRoute(id)+"/logs", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return PluginLogEntryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetMarketplacePlugins will return the plugins that can be installed from the marketplace.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) GetMarketplacePlugins() ([]*MarketplacePlugin, *Response) {
+	if r, err := c.DoApiGet(c.GetPluginsRoute()+"/marketplace", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return MarketplacePluginListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// InstallMarketplacePlugin will download a plugin from the marketplace and install it, replacing the installed version.
+// WARNING: PLUGINS ARE STILL EXPERIMENTAL. THIS FUNCTION IS SUBJECT TO CHANGE.
+func (c *Client4) InstallMarketplacePlugin(id string) (*Manifest, *Response) {
+	request := &MarketplaceInstallRequest{Id: id}
+	if r, err := c.DoApiPost(c.GetPluginsRoute()+"/marketplace", request.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ManifestFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // UpdateChannelScheme will update a channel's scheme.
 func (c *Client4) UpdateChannelScheme(channelId, schemeId string) (bool, *Response) {
 	sip := &SchemeIDPatch{SchemeID: &schemeId}
@@ -3794,3 +4654,513 @@ func (c *Client4) GetRedirectLocation(urlParam, etag string) (string, *Response)
 		return MapFromJson(r.Body)["location"], BuildResponse(r)
 	}
 }
+
+// User Group Section
+
+// CreateUserGroup creates a group that can be mentioned as @name.
+func (c *Client4) CreateUserGroup(group *UserGroup) (*UserGroup, *Response) {
+	if r, err := c.DoApiPost(c.GetUserGroupsRoute(), group.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetUserGroups returns a page of groups sorted by name.
+func (c *Client4) GetUserGroups(page int, perPage int) ([]*UserGroup, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetUserGroupsRoute()+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) GetUserGroup(groupId string) (*UserGroup, *Response) {
+	if r, err := c.DoApiGet(c.GetUserGroupRoute(groupId), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// PatchUserGroup partially updates a group. Any missing fields in the patch are left unchanged.
+func (c *Client4) PatchUserGroup(groupId string, patch *UserGroupPatch) (*UserGroup, *Response) {
+	if r, err := c.DoApiPut(c.GetUserGroupRoute(groupId)+"/patch", patch.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) DeleteUserGroup(groupId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserGroupRoute(groupId)); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetUserGroupMembers returns a page of the members of a group in the order that they were added.
+func (c *Client4) GetUserGroupMembers(groupId string, page int, perPage int) ([]*UserGroupMember, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetUserGroupRoute(groupId)+"/members"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupMembersFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) AddUserGroupMember(groupId, userId string) (*UserGroupMember, *Response) {
+	member := &UserGroupMember{GroupId: groupId, UserId: userId}
+	if r, err := c.DoApiPost(c.GetUserGroupRoute(groupId)+"/members", member.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserGroupMemberFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) RemoveUserGroupMember(groupId, userId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserGroupRoute(groupId) + "/members/" + userId); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Timezone Section
+
+// GetUserTimezone returns the timezone that a user has chosen or that was detected for them.
+func (c *Client4) GetUserTimezone(userId string) (*UserTimezone, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/timezone", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserTimezoneFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) UpdateUserTimezone(userId string, timezone *UserTimezone) (*UserTimezone, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/timezone", timezone.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserTimezoneFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Channel Organization Section
+
+// GetChannelOrganization returns how a user has chosen to organize the channels in their sidebar.
+func (c *Client4) GetChannelOrganization(userId string) (*ChannelOrganization, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/channel_organization", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelOrganizationFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) UpdateChannelOrganization(userId string, organization *ChannelOrganization) (*ChannelOrganization, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/channel_organization", organization.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelOrganizationFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Sidebar Categories Section
+
+// GetSidebarCategoriesForTeamForUser returns the categories that a user has made for their sidebar in a team.
+func (c *Client4) GetSidebarCategoriesForTeamForUser(userId, teamId string) ([]*SidebarCategory, *Response) {
+	if r, err := c.DoApiGet(c.GetSidebarCategoriesRoute(userId, teamId), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) CreateSidebarCategoryForTeamForUser(userId, teamId string, category *SidebarCategory) (*SidebarCategory, *Response) {
+	if r, err := c.DoApiPost(c.GetSidebarCategoriesRoute(userId, teamId), category.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) UpdateSidebarCategoryForTeamForUser(userId, teamId string, category *SidebarCategory) (*SidebarCategory, *Response) {
+	if r, err := c.DoApiPut(c.GetSidebarCategoriesRoute(userId, teamId)+"/"+category.Id, category.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) DeleteSidebarCategoryForTeamForUser(userId, teamId, categoryId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetSidebarCategoriesRoute(userId, teamId) + "/" + categoryId); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Auto Responder Section
+
+func (c *Client4) GetAutoResponderSchedule(userId string) (*AutoResponderSchedule, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/auto_responder/schedule", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return AutoResponderScheduleFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// SetAutoResponderSchedule schedules a user's auto-responder to be turned on and off again, replacing any schedule
+// that they already had.
+func (c *Client4) SetAutoResponderSchedule(userId string, schedule *AutoResponderSchedule) (*AutoResponderSchedule, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/auto_responder/schedule", schedule.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return AutoResponderScheduleFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) DeleteAutoResponderSchedule(userId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId) + "/auto_responder/schedule"); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Channel Notify Defaults Section
+
+func (c *Client4) GetChannelNotifyDefaults(channelId string) (*ChannelNotifyDefaults, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/notify_defaults", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelNotifyDefaultsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateChannelNotifyDefaults sets the notify props that new members of a channel start with.
+func (c *Client4) UpdateChannelNotifyDefaults(channelId string, notifyProps StringMap) (*ChannelNotifyDefaults, *Response) {
+	defaults := &ChannelNotifyDefaults{ChannelId: channelId, NotifyProps: notifyProps}
+	if r, err := c.DoApiPut(c.GetChannelRoute(channelId)+"/notify_defaults", defaults.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ChannelNotifyDefaultsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+func (c *Client4) DeleteChannelNotifyDefaults(channelId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetChannelRoute(channelId) + "/notify_defaults"); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Read Receipts Section
+
+// GetReadReceiptsForChannel returns how far each member of a channel has read it.
+func (c *Client4) GetReadReceiptsForChannel(channelId string) ([]*ReadReceipt, *Response) {
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/read_receipts", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ReadReceiptsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetReadReceiptsForPost returns the read receipts of the members who have seen a post.
+func (c *Client4) GetReadReceiptsForPost(postId string) ([]*ReadReceipt, *Response) {
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/read_receipts", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return ReadReceiptsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Reminders Section
+
+// GetRemindersForUser returns the reminders that a user has set which haven't been delivered yet.
+func (c *Client4) GetRemindersForUser(userId string) ([]*Reminder, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/reminders", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return RemindersFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// CancelReminder deletes a reminder before it's delivered.
+func (c *Client4) CancelReminder(reminderId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetReminderRoute(reminderId)); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Bots Section
+
+// CreateBot creates a bot owned by the current user.
+func (c *Client4) CreateBot(bot *Bot) (*Bot, *Response) {
+	if r, err := c.DoApiPost(c.GetBotsRoute(), bot.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// PatchBot partially updates a bot. Any missing fields are not updated.
+func (c *Client4) PatchBot(botUserId string, patch *BotPatch) (*Bot, *Response) {
+	if r, err := c.DoApiPut(c.GetBotRoute(botUserId), patch.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBot gets a bot that hasn't been disabled.
+func (c *Client4) GetBot(botUserId string) (*Bot, *Response) {
+	if r, err := c.DoApiGet(c.GetBotRoute(botUserId), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBotIncludeDeleted gets a bot whether or not it's been disabled.
+func (c *Client4) GetBotIncludeDeleted(botUserId string) (*Bot, *Response) {
+	if r, err := c.DoApiGet(c.GetBotRoute(botUserId)+"?include_deleted=true", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBots gets a page of the bots that the current user can manage.
+func (c *Client4) GetBots(page, perPage int, includeDeleted, onlyOrphaned bool) ([]*Bot, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v&include_deleted=%v&only_orphaned=%v", page, perPage, includeDeleted, onlyOrphaned)
+	if r, err := c.DoApiGet(c.GetBotsRoute()+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DisableBot disables a bot along with its user and access tokens.
+func (c *Client4) DisableBot(botUserId string) (*Bot, *Response) {
+	if r, err := c.DoApiPost(c.GetBotRoute(botUserId)+"/disable", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// EnableBot enables a bot that was disabled.
+func (c *Client4) EnableBot(botUserId string) (*Bot, *Response) {
+	if r, err := c.DoApiPost(c.GetBotRoute(botUserId)+"/enable", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// AssignBot gives a bot to another owner.
+func (c *Client4) AssignBot(botUserId, newOwnerId string) (*Bot, *Response) {
+	if r, err := c.DoApiPost(c.GetBotRoute(botUserId)+"/assign/"+newOwnerId, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return BotFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Notification Preferences Section
+
+// ExportNotificationPreferences returns every notification setting that a user has.
+func (c *Client4) ExportNotificationPreferences(userId string) (*NotificationPreferences, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/notification_preferences", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return NotificationPreferencesFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ImportNotificationPreferences applies notification settings that were exported from this or another user, possibly on
+// another server.
+func (c *Client4) ImportNotificationPreferences(userId string, preferences *NotificationPreferences) (*NotificationPreferencesImportResult, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/notification_preferences", preferences.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return NotificationPreferencesImportResultFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// SMS Section
+
+// SendPhoneVerificationCode texts a verification code to a phone number so that the user can receive notifications
+// there once it's verified.
+func (c *Client4) SendPhoneVerificationCode(userId string, phoneNumber string) (bool, *Response) {
+	requestBody := map[string]string{"phone_number": phoneNumber}
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+"/phone", MapToJson(requestBody)); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// VerifyPhoneNumber stores the phone number that a verification code was sent to on the user.
+func (c *Client4) VerifyPhoneNumber(userId string, code string) (*User, *Response) {
+	requestBody := map[string]string{"code": code}
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+"/phone/verify", MapToJson(requestBody)); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RemovePhoneNumber removes a user's verified phone number.
+func (c *Client4) RemovePhoneNumber(userId string) (*User, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId) + "/phone"); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// Debug Recording Section
+
+// StartDebugRecording starts recording the requests that match the filter, and their responses, on the server that
+// handles the request. Any recording that was already in progress is discarded. Must be a system administrator.
+func (c *Client4) StartDebugRecording(filter *DebugRecordingFilter) (*DebugRecording, *Response) {
+	if r, err := c.DoApiPost("/debug/recording", filter.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return DebugRecordingFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetDebugRecording returns the requests that have been recorded so far. Must be a system administrator.
+func (c *Client4) GetDebugRecording() (*DebugRecording, *Response) {
+	if r, err := c.DoApiGet("/debug/recording", ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return DebugRecordingFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// StopDebugRecording stops and discards the current recording. Must be a system administrator.
+func (c *Client4) StopDebugRecording() (bool, *Response) {
+	if r, err := c.DoApiDelete("/debug/recording"); err != nil {
+		return false, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// StartProfileCapture starts capturing profiles of the server that handles the request. The returned job succeeds
+// once the profiles can be downloaded. Must be a system administrator.
+func (c *Client4) StartProfileCapture(request *ProfileCaptureRequest) (*Job, *Response) {
+	if r, err := c.DoApiPost("/debug/profiles", request.ToJson()); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return JobFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetProfileCaptures returns a page of the profile captures, newest first. Must be a system administrator.
+func (c *Client4) GetProfileCaptures(page int, perPage int) ([]*Job, *Response) {
+	if r, err := c.DoApiGet(fmt.Sprintf("/debug/profiles?page=%v&per_page=%v", page, perPage), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return JobsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DownloadCapturedProfile returns one of the profiles from a finished profile capture, which can be read with
+// `go tool pprof`. Must be a system administrator.
+func (c *Client4) DownloadCapturedProfile(jobId string, profile string) ([]byte, *Response) {
+	if r, err := c.DoApiGet("/debug/profiles/"+jobId+"/"+profile, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+
+		if data, err := ioutil.ReadAll(r.Body); err != nil {
+			return nil, BuildErrorResponse(r, NewAppError("DownloadCapturedProfile", "model.client.read_file.app_error", nil, err.Error(), r.StatusCode))
+		} else {
+			return data, BuildResponse(r)
+		}
+	}
+}
+
+// WebSocket Section
+
+// GetWebSocketConnections returns the websocket connections that are open to the server that handles the request, or
+// only those of a user if userId isn't empty. Must be a system administrator.
+func (c *Client4) GetWebSocketConnections(userId string) ([]*WebSocketConnectionInfo, *Response) {
+	query := ""
+	if userId != "" {
+		query = "?user_id=" + userId
+	}
+
+	if r, err := c.DoApiGet("/websocket/connections"+query, ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return WebSocketConnectionInfosFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GraphQL Section
+
+// GraphQL runs a query against the GraphQL endpoint. Errors in resolving parts of the query are returned in the
+// response along with whatever could be resolved, so the response's Errors should be checked as well.
+func (c *Client4) GraphQL(request *GraphQLRequest) (*GraphQLResponse, *Response) {
+	if r, err := c.DoApiRequest(http.MethodPost, c.Url+API_URL_SUFFIX_V5+"/graphql", request.ToJson(), ""); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	} else {
+		defer closeBody(r)
+		return GraphQLResponseFromJson(r.Body), BuildResponse(r)
+	}
+}
@@ -23,6 +23,9 @@ const (
 	CLUSTER_EVENT_CLEAR_SESSION_CACHE_FOR_USER                      = "clear_session_user"
 	CLUSTER_EVENT_INVALIDATE_CACHE_FOR_ROLES                        = "inv_roles"
 	CLUSTER_EVENT_INVALIDATE_CACHE_FOR_SCHEMES                      = "inv_schemes"
+	CLUSTER_EVENT_WEBSOCKET_CONNECTIONS                             = "websocket_connections"
+	CLUSTER_EVENT_WEBSOCKET_HAND_OVER_REQUEST                       = "websocket_hand_over_request"
+	CLUSTER_EVENT_WEBSOCKET_HAND_OVER                               = "websocket_hand_over"
 
 	CLUSTER_SEND_BEST_EFFORT = "best_effort"
 	CLUSTER_SEND_RELIABLE    = "reliable"
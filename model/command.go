@@ -15,6 +15,8 @@ const (
 	COMMAND_METHOD_GET  = "G"
 	MIN_TRIGGER_LENGTH  = 1
 	MAX_TRIGGER_LENGTH  = 128
+
+	COMMAND_AUTOCOMPLETE_DATA_MAX_SIZE = 65535
 )
 
 type Command struct {
@@ -35,6 +37,24 @@ type Command struct {
 	DisplayName      string `json:"display_name"`
 	Description      string `json:"description"`
 	URL              string `json:"url"`
+
+	// AutocompleteData describes the command's arguments so that they can be suggested as users type them.
+	AutocompleteData *AutocompleteData `json:"autocomplete_data,omitempty"`
+
+	// PluginId is set for commands that are registered by plugins, which aren't stored in the database.
+	PluginId string `json:"plugin_id,omitempty" db:"-"`
+}
+
+// CommandPatch changes how a command is described to users without changing what it does, so that integrations can
+// keep their commands' descriptions in sync with the versions of them that are deployed.
+type CommandPatch struct {
+	DisplayName      *string           `json:"display_name"`
+	Description      *string           `json:"description"`
+	IconURL          *string           `json:"icon_url"`
+	AutoComplete     *bool             `json:"auto_complete"`
+	AutoCompleteDesc *string           `json:"auto_complete_desc"`
+	AutoCompleteHint *string           `json:"auto_complete_hint"`
+	AutocompleteData *AutocompleteData `json:"autocomplete_data"`
 }
 
 func (o *Command) ToJson() string {
@@ -48,6 +68,17 @@ func CommandFromJson(data io.Reader) *Command {
 	return o
 }
 
+func (o *CommandPatch) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func CommandPatchFromJson(data io.Reader) *CommandPatch {
+	var o *CommandPatch
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
 func CommandListToJson(l []*Command) string {
 	b, _ := json.Marshal(l)
 	return string(b)
@@ -109,6 +140,32 @@ func (o *Command) IsValid() *AppError {
 		return NewAppError("Command.IsValid", "model.command.is_valid.description.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if len(o.IconURL) > 1024 {
+		return NewAppError("Command.IsValid", "model.command.is_valid.icon_url.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.AutoCompleteDesc) > 1024 {
+		return NewAppError("Command.IsValid", "model.command.is_valid.auto_complete_desc.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.AutoCompleteHint) > 1024 {
+		return NewAppError("Command.IsValid", "model.command.is_valid.auto_complete_hint.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.AutocompleteData != nil {
+		if !strings.EqualFold(o.AutocompleteData.Trigger, o.Trigger) {
+			return NewAppError("Command.IsValid", "model.command.is_valid.autocomplete_data_trigger.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if err := o.AutocompleteData.IsValid(); err != nil {
+			return err
+		}
+
+		if len(o.AutocompleteData.ToJson()) > COMMAND_AUTOCOMPLETE_DATA_MAX_SIZE {
+			return NewAppError("Command.IsValid", "model.command.is_valid.autocomplete_data_size.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
@@ -129,6 +186,36 @@ func (o *Command) PreUpdate() {
 	o.UpdateAt = GetMillis()
 }
 
+func (o *Command) Patch(patch *CommandPatch) {
+	if patch.DisplayName != nil {
+		o.DisplayName = *patch.DisplayName
+	}
+
+	if patch.Description != nil {
+		o.Description = *patch.Description
+	}
+
+	if patch.IconURL != nil {
+		o.IconURL = *patch.IconURL
+	}
+
+	if patch.AutoComplete != nil {
+		o.AutoComplete = *patch.AutoComplete
+	}
+
+	if patch.AutoCompleteDesc != nil {
+		o.AutoCompleteDesc = *patch.AutoCompleteDesc
+	}
+
+	if patch.AutoCompleteHint != nil {
+		o.AutoCompleteHint = *patch.AutoCompleteHint
+	}
+
+	if patch.AutocompleteData != nil {
+		o.AutocompleteData = patch.AutocompleteData
+	}
+}
+
 func (o *Command) Sanitize() {
 	o.Token = ""
 	o.CreatorId = ""
@@ -136,4 +223,5 @@ func (o *Command) Sanitize() {
 	o.URL = ""
 	o.Username = ""
 	o.IconURL = ""
+	o.AutocompleteData = o.AutocompleteData.Sanitized()
 }
@@ -26,6 +26,25 @@ type CommandResponse struct {
 	Props        StringInterface    `json:"props"`
 	GotoLocation string             `json:"goto_location"`
 	Attachments  []*SlackAttachment `json:"attachments"`
+
+	// Ephemeral responses sent to a command's response_url can replace or add to the text of the last ephemeral
+	// response to the command instead of being shown separately, so that long running commands can show their progress
+	ReplaceOriginal bool `json:"replace_original"`
+	AppendOriginal  bool `json:"append_original"`
+
+	// A dialog in the response to a custom command is shown to the user that ran the command, and what they enter is
+	// submitted to the command's URL
+	Dialog *Dialog `json:"dialog,omitempty"`
+}
+
+// IsEphemeral returns true if the response is only shown to the user that ran the command.
+func (o *CommandResponse) IsEphemeral() bool {
+	return o.ResponseType == "" || o.ResponseType == COMMAND_RESPONSE_TYPE_EPHEMERAL
+}
+
+// UpdatesOriginal returns true if the response changes the last ephemeral response to the command.
+func (o *CommandResponse) UpdatesOriginal() bool {
+	return o.IsEphemeral() && (o.ReplaceOriginal || o.AppendOriginal)
 }
 
 func (o *CommandResponse) ToJson() string {
@@ -27,6 +27,20 @@ func TestCommandResponseFromHTTPBody(t *testing.T) {
 	}
 }
 
+func TestCommandResponseUpdatesOriginal(t *testing.T) {
+	for _, test := range []struct {
+		Response *CommandResponse
+		Expected bool
+	}{
+		{&CommandResponse{}, false},
+		{&CommandResponse{ReplaceOriginal: true}, true},
+		{&CommandResponse{ResponseType: COMMAND_RESPONSE_TYPE_EPHEMERAL, AppendOriginal: true}, true},
+		{&CommandResponse{ResponseType: COMMAND_RESPONSE_TYPE_IN_CHANNEL, ReplaceOriginal: true}, false},
+	} {
+		assert.Equal(t, test.Expected, test.Response.UpdatesOriginal())
+	}
+}
+
 func TestCommandResponseFromPlainText(t *testing.T) {
 	response := CommandResponseFromPlainText("foo")
 	assert.Equal(t, "foo", response.Text)
@@ -161,6 +161,56 @@ func TestCommandIsValid(t *testing.T) {
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
+
+	o.IconURL = strings.Repeat("1", 1025)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.IconURL = strings.Repeat("1", 1024)
+	o.AutoCompleteDesc = strings.Repeat("1", 1025)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.AutoCompleteDesc = strings.Repeat("1", 1024)
+	o.AutoCompleteHint = strings.Repeat("1", 1025)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.AutoCompleteHint = strings.Repeat("1", 1024)
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommandPatch(t *testing.T) {
+	o := Command{
+		Trigger:          "deploy",
+		DisplayName:      "Deploy",
+		Description:      "Deploys things",
+		IconURL:          "https://example.com/old.png",
+		AutoCompleteHint: "[service]",
+	}
+
+	description := "Deploys services"
+	iconURL := "https://example.com/new.png"
+	autoComplete := true
+	o.Patch(&CommandPatch{
+		Description:      &description,
+		IconURL:          &iconURL,
+		AutoComplete:     &autoComplete,
+		AutocompleteData: &AutocompleteData{Trigger: "deploy", HelpText: "Deploys a service"},
+	})
+
+	if o.Description != description || o.IconURL != iconURL || !o.AutoComplete || o.AutocompleteData == nil {
+		t.Fatal("should have patched the command")
+	}
+
+	if o.Trigger != "deploy" || o.DisplayName != "Deploy" || o.AutoCompleteHint != "[service]" {
+		t.Fatal("shouldn't have changed fields that weren't patched")
+	}
 }
 
 func TestCommandPreSave(t *testing.T) {
@@ -16,6 +16,10 @@ type CommandWebhook struct {
 	RootId    string
 	ParentId  string
 	UseCount  int
+
+	// The last ephemeral response that was sent through the webhook, which later responses can replace or append to
+	EphemeralPostId  string
+	EphemeralMessage string
 }
 
 const (
@@ -61,5 +65,9 @@ func (o *CommandWebhook) IsValid() *AppError {
 		return NewAppError("CommandWebhook.IsValid", "model.command_hook.parent_id.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if len(o.EphemeralPostId) != 0 && len(o.EphemeralPostId) != 26 {
+		return NewAppError("CommandWebhook.IsValid", "model.command_hook.ephemeral_post_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
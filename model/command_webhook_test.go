@@ -40,6 +40,8 @@ func TestCommandWebhookIsValid(t *testing.T) {
 		{func() { h.RootId = NewId() }, ""},
 		{func() { h.ParentId = "asd" }, "model.command_hook.parent_id.app_error"},
 		{func() { h.ParentId = NewId() }, ""},
+		{func() { h.EphemeralPostId = "asd" }, "model.command_hook.ephemeral_post_id.app_error"},
+		{func() { h.EphemeralPostId = NewId() }, ""},
 	} {
 		tmp := h
 		test.Transform()
@@ -4,6 +4,7 @@
 package model
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"math"
@@ -14,6 +15,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -43,6 +46,7 @@ const (
 	GENERIC_NO_CHANNEL_NOTIFICATION = "generic_no_channel"
 	GENERIC_NOTIFICATION            = "generic"
 	FULL_NOTIFICATION               = "full"
+	ID_LOADED_NOTIFICATION          = "id_loaded"
 
 	DIRECT_MESSAGE_ANY  = "any"
 	DIRECT_MESSAGE_TEAM = "team"
@@ -74,6 +78,10 @@ const (
 	GROUP_UNREAD_CHANNELS_DEFAULT_ON  = "default_on"
 	GROUP_UNREAD_CHANNELS_DEFAULT_OFF = "default_off"
 
+	READ_RECEIPTS_DISABLED       = "disabled"
+	READ_RECEIPTS_ALL_CHANNELS   = "all_channels"
+	READ_RECEIPTS_CHANNEL_OPT_IN = "channel_opt_in"
+
 	EMAIL_BATCHING_BUFFER_SIZE = 256
 	EMAIL_BATCHING_INTERVAL    = 30
 
@@ -93,17 +101,34 @@ const (
 	SERVICE_SETTINGS_DEFAULT_GFYCAT_API_KEY     = "2_KtH_W5"
 	SERVICE_SETTINGS_DEFAULT_GFYCAT_API_SECRET  = "3wLVZPiswc3DnaiaFoLkDvB4X0IV6CpMkj4tf2inJRsBY6-FnkT08zGmppWFgeof"
 
+	SERVICE_SETTINGS_DEFAULT_LINK_METADATA_TIME_TO_LIVE_HOURS   = 168
+	SERVICE_SETTINGS_DEFAULT_LINK_METADATA_MAX_CACHE_SIZE       = 50000
+	SERVICE_SETTINGS_DEFAULT_LINK_METADATA_REFRESH_MIN_ACCESSES = 10
+	SERVICE_SETTINGS_DEFAULT_LINK_PREVIEW_THUMBNAIL_WIDTH       = 300
+	SERVICE_SETTINGS_DEFAULT_INCOMING_WEBHOOK_RATE_LIMIT_BURST  = 10
+	SERVICE_SETTINGS_DEFAULT_OUTGOING_WEBHOOK_MAX_RETRIES       = 3
+	SERVICE_SETTINGS_MAX_OUTGOING_WEBHOOK_MAX_RETRIES           = 10
+
+	LINK_PREVIEW_MODE_ONLINE  = "online"
+	LINK_PREVIEW_MODE_OFFLINE = "offline"
+
 	TEAM_SETTINGS_DEFAULT_MAX_USERS_PER_TEAM       = 50
 	TEAM_SETTINGS_DEFAULT_CUSTOM_BRAND_TEXT        = ""
 	TEAM_SETTINGS_DEFAULT_CUSTOM_DESCRIPTION_TEXT  = ""
 	TEAM_SETTINGS_DEFAULT_USER_STATUS_AWAY_TIMEOUT = 300
+	TEAM_SETTINGS_DEFAULT_DELETION_GRACE_PERIOD    = 7
 
 	SQL_SETTINGS_DEFAULT_DATA_SOURCE = "mmuser:mostest@tcp(dockerhost:3306)/mattermost_test?charset=utf8mb4,utf8&readTimeout=30s&writeTimeout=30s"
 
-	FILE_SETTINGS_DEFAULT_DIRECTORY = "./data/"
+	FILE_SETTINGS_DEFAULT_DIRECTORY                        = "./data/"
+	FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_MINIMUM_SIZE  = 1048576 // 1 MB
+	FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_MAX_DIMENSION = 4096
+	FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_JPEG_QUALITY  = 85
 
 	EMAIL_SETTINGS_DEFAULT_FEEDBACK_ORGANIZATION = ""
 
+	SMS_SETTINGS_DEFAULT_API_URL = "https://api.twilio.com"
+
 	SUPPORT_SETTINGS_DEFAULT_TERMS_OF_SERVICE_LINK = "https://about.mattermost.com/default-terms/"
 	SUPPORT_SETTINGS_DEFAULT_PRIVACY_POLICY_LINK   = "https://about.mattermost.com/default-privacy-policy/"
 	SUPPORT_SETTINGS_DEFAULT_ABOUT_LINK            = "https://about.mattermost.com/default-about/"
@@ -138,6 +163,8 @@ const (
 
 	ANALYTICS_SETTINGS_DEFAULT_MAX_USERS_FOR_STATISTICS = 2500
 
+	METRICS_SETTINGS_DEFAULT_PERFORMANCE_TIMING_SAMPLE_PERCENT = 10
+
 	ANNOUNCEMENT_SETTINGS_DEFAULT_BANNER_COLOR      = "#f2a93b"
 	ANNOUNCEMENT_SETTINGS_DEFAULT_BANNER_TEXT_COLOR = "#333333"
 
@@ -159,6 +186,10 @@ const (
 	DATA_RETENTION_SETTINGS_DEFAULT_FILE_RETENTION_DAYS     = 365
 	DATA_RETENTION_SETTINGS_DEFAULT_DELETION_JOB_START_TIME = "02:00"
 
+	INACTIVE_USER_SETTINGS_DEFAULT_INACTIVE_DAYS                  = 90
+	INACTIVE_USER_SETTINGS_DEFAULT_DEACTIVATION_GRACE_PERIOD_DAYS = 14
+	INACTIVE_USER_SETTINGS_DEFAULT_JOB_START_TIME                 = "04:00"
+
 	PLUGIN_SETTINGS_DEFAULT_DIRECTORY        = "./plugins"
 	PLUGIN_SETTINGS_DEFAULT_CLIENT_DIRECTORY = "./client/plugins"
 
@@ -192,12 +223,22 @@ type ServiceSettings struct {
 	GoogleDeveloperKey                                string
 	EnableOAuthServiceProvider                        bool
 	EnableIncomingWebhooks                            bool
+	IncomingWebhookRateLimitPerMinute                 *int
+	IncomingWebhookRateLimitBurst                     *int
 	EnableOutgoingWebhooks                            bool
+	OutgoingWebhookMaxRetries                         *int
 	EnableCommands                                    *bool
 	EnableOnlyAdminIntegrations                       *bool
 	EnablePostUsernameOverride                        bool
 	EnablePostIconOverride                            bool
 	EnableLinkPreviews                                *bool
+	LinkMetadataTimeToLiveHours                       *int
+	LinkMetadataMaxCacheSize                          *int
+	LinkMetadataRefreshMinAccesses                    *int
+	EnableLinkPreviewThumbnails                       *bool
+	LinkPreviewThumbnailWidth                         *int
+	LinkPreviewMode                                   *string
+	LinkPreviewFixturesFile                           *string
 	EnableTesting                                     bool
 	EnableDeveloper                                   *bool
 	EnableSecurityFixAlert                            *bool
@@ -206,6 +247,8 @@ type ServiceSettings struct {
 	EnableMultifactorAuthentication                   *bool
 	EnforceMultifactorAuthentication                  *bool
 	EnableUserAccessTokens                            *bool
+	EnableBotAccountCreation                          *bool
+	DisableBotsWhenOwnerIsDeactivated                 *bool
 	AllowCorsFrom                                     *string
 	CorsExposedHeaders                                *string
 	CorsAllowCredentials                              *bool
@@ -219,6 +262,9 @@ type ServiceSettings struct {
 	WebsocketSecurePort                               *int
 	WebsocketPort                                     *int
 	WebserverMode                                     *string
+	EnableApiCompression                              *bool
+	CompressionMinimumSize                            *int
+	CompressionContentTypes                           *string
 	EnableCustomEmoji                                 *bool
 	EnableEmojiPicker                                 *bool
 	EnableGifPicker                                   *bool
@@ -232,6 +278,14 @@ type ServiceSettings struct {
 	EnablePostSearch                                  *bool
 	EnableUserTypingMessages                          *bool
 	EnableChannelViewedMessages                       *bool
+	ReadReceipts                                      *string
+	MaximumWebSocketConnectionsPerUser                *int
+	MaximumWebSocketConnectionsPerServer              *int
+	WebSocketSendQueueSize                            *int
+	WebSocketSlowClientTimeoutSeconds                 *int
+	GracefulShutdownTimeoutSeconds                    *int
+	EnableChannelBridges                              *bool
+	ThreadAutoFollow                                  *bool
 	EnableUserStatuses                                *bool
 	ExperimentalEnableAuthenticationTransfer          *bool
 	ClusterLogTimeoutMilliseconds                     *int
@@ -240,7 +294,8 @@ type ServiceSettings struct {
 	EnableTutorial                                    *bool
 	ExperimentalEnableDefaultChannelLeaveJoinMessages *bool
 	ExperimentalGroupUnreadChannels                   *string
-	ExperimentalChannelOrganization                   *bool
+	ExperimentalChannelOrganization                   *bool // Deprecated: replaced by EnableChannelOrganization
+	EnableChannelOrganization                         *bool
 	ImageProxyType                                    *string
 	ImageProxyURL                                     *string
 	ImageProxyOptions                                 *string
@@ -280,6 +335,46 @@ func (s *ServiceSettings) SetDefaults() {
 		s.EnableLinkPreviews = NewBool(false)
 	}
 
+	if s.LinkMetadataTimeToLiveHours == nil {
+		s.LinkMetadataTimeToLiveHours = NewInt(SERVICE_SETTINGS_DEFAULT_LINK_METADATA_TIME_TO_LIVE_HOURS)
+	}
+
+	if s.LinkMetadataMaxCacheSize == nil {
+		s.LinkMetadataMaxCacheSize = NewInt(SERVICE_SETTINGS_DEFAULT_LINK_METADATA_MAX_CACHE_SIZE)
+	}
+
+	if s.LinkMetadataRefreshMinAccesses == nil {
+		s.LinkMetadataRefreshMinAccesses = NewInt(SERVICE_SETTINGS_DEFAULT_LINK_METADATA_REFRESH_MIN_ACCESSES)
+	}
+
+	if s.EnableLinkPreviewThumbnails == nil {
+		s.EnableLinkPreviewThumbnails = NewBool(false)
+	}
+
+	if s.IncomingWebhookRateLimitPerMinute == nil {
+		s.IncomingWebhookRateLimitPerMinute = NewInt(0)
+	}
+
+	if s.IncomingWebhookRateLimitBurst == nil {
+		s.IncomingWebhookRateLimitBurst = NewInt(SERVICE_SETTINGS_DEFAULT_INCOMING_WEBHOOK_RATE_LIMIT_BURST)
+	}
+
+	if s.OutgoingWebhookMaxRetries == nil {
+		s.OutgoingWebhookMaxRetries = NewInt(SERVICE_SETTINGS_DEFAULT_OUTGOING_WEBHOOK_MAX_RETRIES)
+	}
+
+	if s.LinkPreviewThumbnailWidth == nil {
+		s.LinkPreviewThumbnailWidth = NewInt(SERVICE_SETTINGS_DEFAULT_LINK_PREVIEW_THUMBNAIL_WIDTH)
+	}
+
+	if s.LinkPreviewMode == nil {
+		s.LinkPreviewMode = NewString(LINK_PREVIEW_MODE_ONLINE)
+	}
+
+	if s.LinkPreviewFixturesFile == nil {
+		s.LinkPreviewFixturesFile = NewString("")
+	}
+
 	if s.EnableDeveloper == nil {
 		s.EnableDeveloper = NewBool(false)
 	}
@@ -308,6 +403,14 @@ func (s *ServiceSettings) SetDefaults() {
 		s.EnableUserAccessTokens = NewBool(false)
 	}
 
+	if s.EnableBotAccountCreation == nil {
+		s.EnableBotAccountCreation = NewBool(false)
+	}
+
+	if s.DisableBotsWhenOwnerIsDeactivated == nil {
+		s.DisableBotsWhenOwnerIsDeactivated = NewBool(true)
+	}
+
 	if s.GoroutineHealthThreshold == nil {
 		s.GoroutineHealthThreshold = NewInt(-1)
 	}
@@ -364,6 +467,38 @@ func (s *ServiceSettings) SetDefaults() {
 		s.EnableChannelViewedMessages = NewBool(true)
 	}
 
+	if s.ReadReceipts == nil {
+		s.ReadReceipts = NewString(READ_RECEIPTS_DISABLED)
+	}
+
+	if s.MaximumWebSocketConnectionsPerUser == nil {
+		s.MaximumWebSocketConnectionsPerUser = NewInt(0)
+	}
+
+	if s.MaximumWebSocketConnectionsPerServer == nil {
+		s.MaximumWebSocketConnectionsPerServer = NewInt(0)
+	}
+
+	if s.WebSocketSendQueueSize == nil {
+		s.WebSocketSendQueueSize = NewInt(256)
+	}
+
+	if s.WebSocketSlowClientTimeoutSeconds == nil {
+		s.WebSocketSlowClientTimeoutSeconds = NewInt(30)
+	}
+
+	if s.GracefulShutdownTimeoutSeconds == nil {
+		s.GracefulShutdownTimeoutSeconds = NewInt(30)
+	}
+
+	if s.EnableChannelBridges == nil {
+		s.EnableChannelBridges = NewBool(false)
+	}
+
+	if s.ThreadAutoFollow == nil {
+		s.ThreadAutoFollow = NewBool(true)
+	}
+
 	if s.EnableUserStatuses == nil {
 		s.EnableUserStatuses = NewBool(true)
 	}
@@ -442,6 +577,18 @@ func (s *ServiceSettings) SetDefaults() {
 		*s.WebserverMode = "gzip"
 	}
 
+	if s.EnableApiCompression == nil {
+		s.EnableApiCompression = NewBool(false)
+	}
+
+	if s.CompressionMinimumSize == nil {
+		s.CompressionMinimumSize = NewInt(1400)
+	}
+
+	if s.CompressionContentTypes == nil {
+		s.CompressionContentTypes = NewString("")
+	}
+
 	if s.EnableCustomEmoji == nil {
 		s.EnableCustomEmoji = NewBool(false)
 	}
@@ -503,6 +650,10 @@ func (s *ServiceSettings) SetDefaults() {
 		s.ExperimentalChannelOrganization = NewBool(experimentalUnreadEnabled)
 	}
 
+	if s.EnableChannelOrganization == nil {
+		s.EnableChannelOrganization = NewBool(true)
+	}
+
 	if s.ImageProxyType == nil {
 		s.ImageProxyType = NewString("")
 	}
@@ -540,6 +691,13 @@ type ClusterSettings struct {
 	MaxIdleConns                *int
 	MaxIdleConnsPerHost         *int
 	IdleConnTimeoutMilliseconds *int
+	// When RedisAddress is set, the servers in the cluster talk to each other through Redis. Unless ReadOnlyConfig is
+	// enabled, that includes the whole configuration whenever it's changed, with its database, SMTP and other
+	// credentials, so the Redis server must be private to the cluster and protected by RedisPassword.
+	RedisAddress     *string
+	RedisPassword    *string
+	RedisDatabase    *int
+	EnableRedisCache *bool
 }
 
 func (s *ClusterSettings) SetDefaults() {
@@ -586,12 +744,30 @@ func (s *ClusterSettings) SetDefaults() {
 	if s.IdleConnTimeoutMilliseconds == nil {
 		s.IdleConnTimeoutMilliseconds = NewInt(90000)
 	}
+
+	if s.RedisAddress == nil {
+		s.RedisAddress = NewString("")
+	}
+
+	if s.RedisPassword == nil {
+		s.RedisPassword = NewString("")
+	}
+
+	if s.RedisDatabase == nil {
+		s.RedisDatabase = NewInt(0)
+	}
+
+	if s.EnableRedisCache == nil {
+		s.EnableRedisCache = NewBool(false)
+	}
 }
 
 type MetricsSettings struct {
-	Enable           *bool
-	BlockProfileRate *int
-	ListenAddress    *string
+	Enable                         *bool
+	BlockProfileRate               *int
+	ListenAddress                  *string
+	PerformanceTimingSamplePercent *int
+	EnableStoreTracing             *bool
 }
 
 func (s *MetricsSettings) SetDefaults() {
@@ -606,6 +782,22 @@ func (s *MetricsSettings) SetDefaults() {
 	if s.BlockProfileRate == nil {
 		s.BlockProfileRate = NewInt(0)
 	}
+
+	if s.PerformanceTimingSamplePercent == nil {
+		s.PerformanceTimingSamplePercent = NewInt(METRICS_SETTINGS_DEFAULT_PERFORMANCE_TIMING_SAMPLE_PERCENT)
+	}
+
+	if s.EnableStoreTracing == nil {
+		s.EnableStoreTracing = NewBool(false)
+	}
+}
+
+func (s *MetricsSettings) isValid() *AppError {
+	if *s.PerformanceTimingSamplePercent < 0 || *s.PerformanceTimingSamplePercent > 100 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.metrics_performance_timing_sample_percent.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
 }
 
 type ExperimentalSettings struct {
@@ -654,6 +846,11 @@ type SqlSettings struct {
 	Trace                       bool
 	AtRestEncryptKey            string
 	QueryTimeout                *int
+	EnablePostEvents            *bool
+
+	// How often the replicas are checked, so that reads go to the master instead of replicas that are unavailable
+	// until they recover. Replicas aren't checked if this is zero.
+	ReplicaHealthCheckIntervalSeconds *int
 }
 
 func (s *SqlSettings) SetDefaults() {
@@ -684,6 +881,14 @@ func (s *SqlSettings) SetDefaults() {
 	if s.QueryTimeout == nil {
 		s.QueryTimeout = NewInt(30)
 	}
+
+	if s.EnablePostEvents == nil {
+		s.EnablePostEvents = NewBool(false)
+	}
+
+	if s.ReplicaHealthCheckIntervalSeconds == nil {
+		s.ReplicaHealthCheckIntervalSeconds = NewInt(10)
+	}
 }
 
 type LogSettings struct {
@@ -761,6 +966,12 @@ type FileSettings struct {
 	AmazonS3SignV2          *bool
 	AmazonS3SSE             *bool
 	AmazonS3Trace           *bool
+
+	EnableImageOptimization       *bool
+	ImageOptimizationMinimumSize  *int64
+	ImageOptimizationMaxDimension *int
+	ImageOptimizationJpegQuality  *int
+	PreserveOriginalImages        *bool
 }
 
 func (s *FileSettings) SetDefaults() {
@@ -818,6 +1029,36 @@ func (s *FileSettings) SetDefaults() {
 	if s.Directory == "" {
 		s.Directory = FILE_SETTINGS_DEFAULT_DIRECTORY
 	}
+
+	if s.EnableImageOptimization == nil {
+		s.EnableImageOptimization = NewBool(false)
+	}
+
+	if s.ImageOptimizationMinimumSize == nil {
+		s.ImageOptimizationMinimumSize = NewInt64(FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_MINIMUM_SIZE)
+	}
+
+	if s.ImageOptimizationMaxDimension == nil {
+		s.ImageOptimizationMaxDimension = NewInt(FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_MAX_DIMENSION)
+	}
+
+	if s.ImageOptimizationJpegQuality == nil {
+		s.ImageOptimizationJpegQuality = NewInt(FILE_SETTINGS_DEFAULT_IMAGE_OPTIMIZATION_JPEG_QUALITY)
+	}
+
+	if s.PreserveOriginalImages == nil {
+		s.PreserveOriginalImages = NewBool(true)
+	}
+}
+
+// TeamEmailIdentity overrides the server's outgoing email identity for email sent on behalf of a team. Empty fields
+// fall back to the server's EmailSettings.
+type TeamEmailIdentity struct {
+	FeedbackName   string
+	FeedbackEmail  string
+	ReplyToAddress string
+	SMTPUsername   string
+	SMTPPassword   string
 }
 
 type EmailSettings struct {
@@ -840,15 +1081,19 @@ type EmailSettings struct {
 	SendPushNotifications             *bool
 	PushNotificationServer            *string
 	PushNotificationContents          *string
+	EnableWebPushNotifications        *bool
 	EnableEmailBatching               *bool
 	EmailBatchingBufferSize           *int
 	EmailBatchingInterval             *int
+	EnableEmailDigests                *bool
 	EnablePreviewModeBanner           *bool
 	SkipServerCertificateVerification *bool
 	EmailNotificationContentsType     *string
 	LoginButtonColor                  *string
 	LoginButtonBorderColor            *string
 	LoginButtonTextColor              *string
+	EmailTemplatesDirectory           *string
+	TeamIdentities                    map[string]*TeamEmailIdentity
 }
 
 func (s *EmailSettings) SetDefaults() {
@@ -880,6 +1125,10 @@ func (s *EmailSettings) SetDefaults() {
 		s.PushNotificationContents = NewString(GENERIC_NOTIFICATION)
 	}
 
+	if s.EnableWebPushNotifications == nil {
+		s.EnableWebPushNotifications = NewBool(false)
+	}
+
 	if s.FeedbackOrganization == nil {
 		s.FeedbackOrganization = NewString(EMAIL_SETTINGS_DEFAULT_FEEDBACK_ORGANIZATION)
 	}
@@ -896,6 +1145,10 @@ func (s *EmailSettings) SetDefaults() {
 		s.EmailBatchingInterval = NewInt(EMAIL_BATCHING_INTERVAL)
 	}
 
+	if s.EnableEmailDigests == nil {
+		s.EnableEmailDigests = NewBool(false)
+	}
+
 	if s.EnablePreviewModeBanner == nil {
 		s.EnablePreviewModeBanner = NewBool(true)
 	}
@@ -932,6 +1185,14 @@ func (s *EmailSettings) SetDefaults() {
 	if s.LoginButtonTextColor == nil {
 		s.LoginButtonTextColor = NewString("#2389D7")
 	}
+
+	if s.EmailTemplatesDirectory == nil {
+		s.EmailTemplatesDirectory = NewString("")
+	}
+
+	if s.TeamIdentities == nil {
+		s.TeamIdentities = make(map[string]*TeamEmailIdentity)
+	}
 }
 
 type ExtensionSettings struct {
@@ -988,6 +1249,69 @@ func (s *RateLimitSettings) SetDefaults() {
 type PrivacySettings struct {
 	ShowEmailAddress bool
 	ShowFullName     bool
+
+	// When data minimization is enabled, user fields are only returned to those allowed to see them by the visibility
+	// rules, which map each field to who can see it. Fields without a rule are visible to everyone.
+	EnableUserDataMinimization *bool
+	UserFieldVisibility        map[string]string
+}
+
+func (s *PrivacySettings) SetDefaults() {
+	if s.EnableUserDataMinimization == nil {
+		s.EnableUserDataMinimization = NewBool(false)
+	}
+
+	if s.UserFieldVisibility == nil {
+		s.UserFieldVisibility = map[string]string{
+			USER_FIELD_EMAIL:         USER_FIELD_VISIBILITY_ADMINS,
+			USER_FIELD_FULL_NAME:     USER_FIELD_VISIBILITY_ADMINS,
+			USER_FIELD_LAST_ACTIVITY: USER_FIELD_VISIBILITY_ADMINS,
+		}
+	}
+}
+
+func (s *PrivacySettings) isValid() *AppError {
+	for field, visibility := range s.UserFieldVisibility {
+		if !IsMinimizableUserField(field) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.privacy.user_field.app_error", map[string]interface{}{"Field": field}, "", http.StatusBadRequest)
+		}
+
+		if visibility != USER_FIELD_VISIBILITY_ALL && visibility != USER_FIELD_VISIBILITY_ADMINS {
+			return NewAppError("Config.IsValid", "model.config.is_valid.privacy.user_field_visibility.app_error", map[string]interface{}{"Field": field}, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// IsUserFieldVisible returns true if a field of other users' profiles can be seen by users who aren't admins.
+func (s *PrivacySettings) IsUserFieldVisible(field string) bool {
+	switch field {
+	case USER_FIELD_EMAIL:
+		if !s.ShowEmailAddress {
+			return false
+		}
+	case USER_FIELD_FULL_NAME:
+		if !s.ShowFullName {
+			return false
+		}
+	}
+
+	return !*s.EnableUserDataMinimization || s.UserFieldVisibility[field] != USER_FIELD_VISIBILITY_ADMINS
+}
+
+// GetUserFieldVisibilityEtag returns a value that changes whenever the fields that users can see change.
+func (s *PrivacySettings) GetUserFieldVisibilityEtag() string {
+	etag := ""
+	for _, field := range MINIMIZABLE_USER_FIELDS {
+		if s.IsUserFieldVisible(field) {
+			etag += "1"
+		} else {
+			etag += "0"
+		}
+	}
+
+	return etag
 }
 
 type SupportSettings struct {
@@ -1132,6 +1456,7 @@ type TeamSettings struct {
 	ExperimentalTownSquareIsReadOnly    *bool
 	ExperimentalPrimaryTeam             *string
 	ExperimentalDefaultChannels         []string
+	TeamDeletionGracePeriodDays         *int
 }
 
 func (s *TeamSettings) SetDefaults() {
@@ -1248,6 +1573,10 @@ func (s *TeamSettings) SetDefaults() {
 		s.ExperimentalDefaultChannels = []string{}
 	}
 
+	if s.TeamDeletionGracePeriodDays == nil {
+		s.TeamDeletionGracePeriodDays = NewInt(TEAM_SETTINGS_DEFAULT_DELETION_GRACE_PERIOD)
+	}
+
 	if s.EnableTeamCreation == nil {
 		s.EnableTeamCreation = NewBool(true)
 	}
@@ -1764,6 +2093,51 @@ func (s *DataRetentionSettings) SetDefaults() {
 	}
 }
 
+type InactiveUserSettings struct {
+	EnableInactiveUserDetection *bool
+	InactiveDays                *int
+	NotifyInactiveUsers         *bool
+	EnableAutoDeactivation      *bool
+	DeactivationGracePeriodDays *int
+	ExcludedUsernames           []string
+	ExcludedRoles               []string
+	JobStartTime                *string
+}
+
+func (s *InactiveUserSettings) SetDefaults() {
+	if s.EnableInactiveUserDetection == nil {
+		s.EnableInactiveUserDetection = NewBool(false)
+	}
+
+	if s.InactiveDays == nil {
+		s.InactiveDays = NewInt(INACTIVE_USER_SETTINGS_DEFAULT_INACTIVE_DAYS)
+	}
+
+	if s.NotifyInactiveUsers == nil {
+		s.NotifyInactiveUsers = NewBool(true)
+	}
+
+	if s.EnableAutoDeactivation == nil {
+		s.EnableAutoDeactivation = NewBool(false)
+	}
+
+	if s.DeactivationGracePeriodDays == nil {
+		s.DeactivationGracePeriodDays = NewInt(INACTIVE_USER_SETTINGS_DEFAULT_DEACTIVATION_GRACE_PERIOD_DAYS)
+	}
+
+	if s.ExcludedUsernames == nil {
+		s.ExcludedUsernames = []string{}
+	}
+
+	if s.ExcludedRoles == nil {
+		s.ExcludedRoles = []string{SYSTEM_ADMIN_ROLE_ID}
+	}
+
+	if s.JobStartTime == nil {
+		s.JobStartTime = NewString(INACTIVE_USER_SETTINGS_DEFAULT_JOB_START_TIME)
+	}
+}
+
 type JobSettings struct {
 	RunJobs      *bool
 	RunScheduler *bool
@@ -1783,6 +2157,30 @@ type PluginState struct {
 	Enable bool
 }
 
+// PluginLimits are the resources that a plugin can use. Limits of 0 are unlimited, and limits that are nil in
+// PluginSettings.Limits fall back to the limits in PluginSettings.
+type PluginLimits struct {
+	MaxMemoryMB          *int
+	MaxAPICallsPerMinute *int
+	MaxKVStorageMB       *int
+}
+
+func (l *PluginLimits) isValid() *AppError {
+	if l.MaxMemoryMB != nil && *l.MaxMemoryMB < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.plugin.max_memory_mb.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if l.MaxAPICallsPerMinute != nil && *l.MaxAPICallsPerMinute < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.plugin.max_api_calls_per_minute.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if l.MaxKVStorageMB != nil && *l.MaxKVStorageMB < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.plugin.max_kv_storage_mb.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 type PluginSettings struct {
 	Enable          *bool
 	EnableUploads   *bool
@@ -1790,6 +2188,19 @@ type PluginSettings struct {
 	ClientDirectory *string
 	Plugins         map[string]map[string]interface{}
 	PluginStates    map[string]*PluginState
+
+	// The marketplace lists the plugins in a catalog that's either fetched from MarketplaceURL or read from a
+	// marketplace.json file when MarketplaceURL is a directory. Plugins are only installed from it when their bundle
+	// is signed by the ed25519 key in MarketplacePublicKey.
+	EnableMarketplace    *bool
+	MarketplaceURL       *string
+	MarketplacePublicKey *string
+
+	// The resources that each plugin can use, unless they're overridden for a plugin by its entry in Limits
+	MaxMemoryMB          *int
+	MaxAPICallsPerMinute *int
+	MaxKVStorageMB       *int
+	Limits               map[string]*PluginLimits
 }
 
 func (s *PluginSettings) SetDefaults() {
@@ -1824,6 +2235,90 @@ func (s *PluginSettings) SetDefaults() {
 	if s.PluginStates == nil {
 		s.PluginStates = make(map[string]*PluginState)
 	}
+
+	if s.EnableMarketplace == nil {
+		s.EnableMarketplace = NewBool(false)
+	}
+
+	if s.MarketplaceURL == nil {
+		s.MarketplaceURL = NewString("")
+	}
+
+	if s.MarketplacePublicKey == nil {
+		s.MarketplacePublicKey = NewString("")
+	}
+
+	if s.MaxMemoryMB == nil {
+		s.MaxMemoryMB = NewInt(0)
+	}
+
+	if s.MaxAPICallsPerMinute == nil {
+		s.MaxAPICallsPerMinute = NewInt(0)
+	}
+
+	if s.MaxKVStorageMB == nil {
+		s.MaxKVStorageMB = NewInt(0)
+	}
+
+	if s.Limits == nil {
+		s.Limits = make(map[string]*PluginLimits)
+	}
+}
+
+// GetLimits returns the resource limits of a plugin, with any limits that aren't overridden for it taken from the
+// limits for every plugin.
+func (s *PluginSettings) GetLimits(pluginId string) *PluginLimits {
+	limits := &PluginLimits{
+		MaxMemoryMB:          s.MaxMemoryMB,
+		MaxAPICallsPerMinute: s.MaxAPICallsPerMinute,
+		MaxKVStorageMB:       s.MaxKVStorageMB,
+	}
+
+	if override := s.Limits[pluginId]; override != nil {
+		if override.MaxMemoryMB != nil {
+			limits.MaxMemoryMB = override.MaxMemoryMB
+		}
+
+		if override.MaxAPICallsPerMinute != nil {
+			limits.MaxAPICallsPerMinute = override.MaxAPICallsPerMinute
+		}
+
+		if override.MaxKVStorageMB != nil {
+			limits.MaxKVStorageMB = override.MaxKVStorageMB
+		}
+	}
+
+	return limits
+}
+
+func (s *PluginSettings) isValid() *AppError {
+	if err := (&PluginLimits{MaxMemoryMB: s.MaxMemoryMB, MaxAPICallsPerMinute: s.MaxAPICallsPerMinute, MaxKVStorageMB: s.MaxKVStorageMB}).isValid(); err != nil {
+		return err
+	}
+
+	for _, limits := range s.Limits {
+		if limits == nil {
+			continue
+		}
+
+		if err := limits.isValid(); err != nil {
+			return err
+		}
+	}
+
+	if !*s.EnableMarketplace {
+		return nil
+	}
+
+	if len(*s.MarketplaceURL) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.plugin.marketplace_url.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if key, err := base64.StdEncoding.DecodeString(*s.MarketplacePublicKey); err != nil || len(key) != ed25519.PublicKeySize {
+		return NewAppError("Config.IsValid", "model.config.is_valid.plugin.marketplace_public_key.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
 }
 
 type GlobalRelayMessageExportSettings struct {
@@ -1896,7 +2391,8 @@ func (s *MessageExportSettings) SetDefaults() {
 
 type DisplaySettings struct {
 	CustomUrlSchemes     *[]string
-	ExperimentalTimezone *bool
+	ExperimentalTimezone *bool // Deprecated: replaced by EnableTimezone
+	EnableTimezone       *bool
 }
 
 func (s *DisplaySettings) SetDefaults() {
@@ -1908,6 +2404,10 @@ func (s *DisplaySettings) SetDefaults() {
 	if s.ExperimentalTimezone == nil {
 		s.ExperimentalTimezone = NewBool(false)
 	}
+
+	if s.EnableTimezone == nil {
+		s.EnableTimezone = NewBool(true)
+	}
 }
 
 type TimezoneSettings struct {
@@ -1920,6 +2420,94 @@ func (s *TimezoneSettings) SetDefaults() {
 	}
 }
 
+type IssueUnfurlProvider struct {
+	Type     string
+	Domain   string
+	Enable   bool
+	Username string
+	Token    string
+}
+
+type IssueUnfurlSettings struct {
+	Enable    *bool
+	Providers []*IssueUnfurlProvider
+}
+
+func (s *IssueUnfurlSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.Providers == nil {
+		s.Providers = []*IssueUnfurlProvider{}
+	}
+}
+
+type SystemEventsSettings struct {
+	Enable                  *bool
+	ChannelId               *string
+	EnableJobFailures       *bool
+	EnableConfigChanges     *bool
+	EnableSecurityEvents    *bool
+	EnableUserDeactivations *bool
+}
+
+func (s *SystemEventsSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.ChannelId == nil {
+		s.ChannelId = NewString("")
+	}
+
+	if s.EnableJobFailures == nil {
+		s.EnableJobFailures = NewBool(true)
+	}
+
+	if s.EnableConfigChanges == nil {
+		s.EnableConfigChanges = NewBool(true)
+	}
+
+	if s.EnableSecurityEvents == nil {
+		s.EnableSecurityEvents = NewBool(true)
+	}
+
+	if s.EnableUserDeactivations == nil {
+		s.EnableUserDeactivations = NewBool(true)
+	}
+}
+
+type SmsSettings struct {
+	Enable     *bool
+	ApiURL     *string
+	AccountSid *string
+	AuthToken  *string
+	FromNumber *string
+}
+
+func (s *SmsSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewBool(false)
+	}
+
+	if s.ApiURL == nil {
+		s.ApiURL = NewString(SMS_SETTINGS_DEFAULT_API_URL)
+	}
+
+	if s.AccountSid == nil {
+		s.AccountSid = NewString("")
+	}
+
+	if s.AuthToken == nil {
+		s.AuthToken = NewString("")
+	}
+
+	if s.FromNumber == nil {
+		s.FromNumber = NewString("")
+	}
+}
+
 type ConfigFunc func() *Config
 
 type Config struct {
@@ -1952,11 +2540,15 @@ type Config struct {
 	WebrtcSettings        WebrtcSettings
 	ElasticsearchSettings ElasticsearchSettings
 	DataRetentionSettings DataRetentionSettings
+	InactiveUserSettings  InactiveUserSettings
 	MessageExportSettings MessageExportSettings
 	JobSettings           JobSettings
 	PluginSettings        PluginSettings
 	DisplaySettings       DisplaySettings
 	TimezoneSettings      TimezoneSettings
+	IssueUnfurlSettings   IssueUnfurlSettings
+	SystemEventsSettings  SystemEventsSettings
+	SmsSettings           SmsSettings
 }
 
 func (o *Config) Clone() *Config {
@@ -2022,6 +2614,7 @@ func (o *Config) SetDefaults() {
 	o.ElasticsearchSettings.SetDefaults()
 	o.NativeAppSettings.SetDefaults()
 	o.DataRetentionSettings.SetDefaults()
+	o.InactiveUserSettings.SetDefaults()
 	o.RateLimitSettings.SetDefaults()
 	o.LogSettings.SetDefaults()
 	o.JobSettings.SetDefaults()
@@ -2030,6 +2623,10 @@ func (o *Config) SetDefaults() {
 	o.TimezoneSettings.SetDefaults()
 	o.DisplaySettings.SetDefaults()
 	o.ExtensionSettings.SetDefaults()
+	o.IssueUnfurlSettings.SetDefaults()
+	o.SystemEventsSettings.SetDefaults()
+	o.SmsSettings.SetDefaults()
+	o.PrivacySettings.SetDefaults()
 }
 
 func (o *Config) IsValid() *AppError {
@@ -2037,10 +2634,22 @@ func (o *Config) IsValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.site_url_email_batching.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if len(*o.ServiceSettings.SiteURL) == 0 && *o.EmailSettings.EnableEmailDigests {
+		return NewAppError("Config.IsValid", "model.config.is_valid.site_url_email_digests.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(*o.ServiceSettings.SiteURL) == 0 && *o.EmailSettings.EnableWebPushNotifications {
+		return NewAppError("Config.IsValid", "model.config.is_valid.site_url_web_push_notifications.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	if *o.ClusterSettings.Enable && *o.EmailSettings.EnableEmailBatching {
 		return NewAppError("Config.IsValid", "model.config.is_valid.cluster_email_batching.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *o.ClusterSettings.EnableRedisCache && len(*o.ClusterSettings.RedisAddress) == 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.cluster_redis_cache.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	if len(*o.ServiceSettings.SiteURL) == 0 && *o.ServiceSettings.AllowCookiesForSubdomains {
 		return NewAppError("Config.IsValid", "model.config.is_valid.allow_cookies_for_subdomains.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -2093,6 +2702,10 @@ func (o *Config) IsValid() *AppError {
 		return err
 	}
 
+	if err := o.InactiveUserSettings.isValid(); err != nil {
+		return err
+	}
+
 	if err := o.LocalizationSettings.isValid(); err != nil {
 		return err
 	}
@@ -2105,6 +2718,30 @@ func (o *Config) IsValid() *AppError {
 		return err
 	}
 
+	if err := o.MetricsSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.IssueUnfurlSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.SystemEventsSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.SmsSettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.PrivacySettings.isValid(); err != nil {
+		return err
+	}
+
+	if err := o.PluginSettings.isValid(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2133,6 +2770,10 @@ func (ts *TeamSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.sitename_length.app_error", map[string]interface{}{"MaxLength": SITENAME_MAX_LENGTH}, "", http.StatusBadRequest)
 	}
 
+	if *ts.TeamDeletionGracePeriodDays <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.team_deletion_grace_period_days.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -2165,6 +2806,10 @@ func (ss *SqlSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.sql_max_conn.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *ss.ReplicaHealthCheckIntervalSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sql_replica_health_check_interval.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -2181,6 +2826,18 @@ func (fs *FileSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.file_salt.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *fs.ImageOptimizationMinimumSize < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.image_optimization_minimum_size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *fs.ImageOptimizationMaxDimension < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.image_optimization_max_dimension.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *fs.ImageOptimizationJpegQuality < 1 || *fs.ImageOptimizationJpegQuality > 100 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.image_optimization_jpeg_quality.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -2205,6 +2862,16 @@ func (es *EmailSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.email_notification_contents_type.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	for teamId, identity := range es.TeamIdentities {
+		if len(teamId) != 26 || identity == nil {
+			return NewAppError("Config.IsValid", "model.config.is_valid.email_team_identity.app_error", map[string]interface{}{"TeamId": teamId}, "", http.StatusBadRequest)
+		}
+
+		if (len(identity.FeedbackEmail) > 0 && !IsValidEmail(identity.FeedbackEmail)) || (len(identity.ReplyToAddress) > 0 && !IsValidEmail(identity.ReplyToAddress)) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.email_team_identity_address.app_error", map[string]interface{}{"TeamId": teamId}, "", http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
@@ -2358,6 +3025,34 @@ func (ss *ServiceSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.login_attempts.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *ss.LinkMetadataTimeToLiveHours <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.link_metadata_time_to_live.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.LinkMetadataMaxCacheSize <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.link_metadata_max_cache_size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.LinkPreviewThumbnailWidth <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.link_preview_thumbnail_width.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.LinkPreviewMode != LINK_PREVIEW_MODE_ONLINE && *ss.LinkPreviewMode != LINK_PREVIEW_MODE_OFFLINE {
+		return NewAppError("Config.IsValid", "model.config.is_valid.link_preview_mode.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.IncomingWebhookRateLimitPerMinute < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.incoming_webhook_rate_limit_per_minute.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.IncomingWebhookRateLimitBurst < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.incoming_webhook_rate_limit_burst.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.OutgoingWebhookMaxRetries < 0 || *ss.OutgoingWebhookMaxRetries > SERVICE_SETTINGS_MAX_OUTGOING_WEBHOOK_MAX_RETRIES {
+		return NewAppError("Config.IsValid", "model.config.is_valid.outgoing_webhook_max_retries.app_error", map[string]interface{}{"Max": SERVICE_SETTINGS_MAX_OUTGOING_WEBHOOK_MAX_RETRIES}, "", http.StatusBadRequest)
+	}
+
 	if len(*ss.SiteURL) != 0 {
 		if _, err := url.ParseRequestURI(*ss.SiteURL); err != nil {
 			return NewAppError("Config.IsValid", "model.config.is_valid.site_url.app_error", nil, "", http.StatusBadRequest)
@@ -2388,6 +3083,36 @@ func (ss *ServiceSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.group_unread_channels.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if *ss.ReadReceipts != READ_RECEIPTS_DISABLED &&
+		*ss.ReadReceipts != READ_RECEIPTS_ALL_CHANNELS &&
+		*ss.ReadReceipts != READ_RECEIPTS_CHANNEL_OPT_IN {
+		return NewAppError("Config.IsValid", "model.config.is_valid.read_receipts.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.MaximumWebSocketConnectionsPerUser < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.max_websocket_connections_per_user.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.MaximumWebSocketConnectionsPerServer < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.max_websocket_connections_per_server.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.CompressionMinimumSize < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.compression_minimum_size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.WebSocketSendQueueSize < 1 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.websocket_send_queue_size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.WebSocketSlowClientTimeoutSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.websocket_slow_client_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ss.GracefulShutdownTimeoutSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.graceful_shutdown_timeout.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	switch *ss.ImageProxyType {
 	case "":
 	case "atmos/camo":
@@ -2451,6 +3176,28 @@ func (drs *DataRetentionSettings) isValid() *AppError {
 	return nil
 }
 
+func (ius *InactiveUserSettings) isValid() *AppError {
+	if *ius.InactiveDays <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.inactive_user.inactive_days_too_low.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *ius.DeactivationGracePeriodDays < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.inactive_user.deactivation_grace_period_days.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, role := range ius.ExcludedRoles {
+		if !IsValidRoleName(role) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.inactive_user.excluded_roles.app_error", nil, "role="+role, http.StatusBadRequest)
+		}
+	}
+
+	if _, err := time.Parse("15:04", *ius.JobStartTime); err != nil {
+		return NewAppError("Config.IsValid", "model.config.is_valid.inactive_user.job_start_time.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 func (ls *LocalizationSettings) isValid() *AppError {
 	if len(*ls.AvailableLocales) > 0 {
 		if !strings.Contains(*ls.AvailableLocales, *ls.DefaultClientLocale) {
@@ -2517,10 +3264,49 @@ func (ds *DisplaySettings) isValid() *AppError {
 	return nil
 }
 
+func (s *IssueUnfurlSettings) isValid() *AppError {
+	for _, provider := range s.Providers {
+		if provider == nil || (provider.Type != ISSUE_UNFURL_PROVIDER_JIRA && provider.Type != ISSUE_UNFURL_PROVIDER_GITHUB) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.issue_unfurl.type.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if len(provider.Domain) == 0 || strings.ContainsAny(provider.Domain, "/ ") {
+			return NewAppError("Config.IsValid", "model.config.is_valid.issue_unfurl.domain.app_error", map[string]interface{}{"Domain": provider.Domain}, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func (s *SystemEventsSettings) isValid() *AppError {
+	if *s.Enable && len(*s.ChannelId) != 26 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.system_events.channel_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (s *SmsSettings) isValid() *AppError {
+	if !*s.Enable {
+		return nil
+	}
+
+	if !IsValidHttpUrl(*s.ApiURL) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sms.api_url.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidPhoneNumber(*s.FromNumber) {
+		return NewAppError("Config.IsValid", "model.config.is_valid.sms.from_number.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 func (o *Config) GetSanitizeOptions() map[string]bool {
 	options := map[string]bool{}
-	options["fullname"] = o.PrivacySettings.ShowFullName
-	options["email"] = o.PrivacySettings.ShowEmailAddress
+	for _, field := range MINIMIZABLE_USER_FIELDS {
+		options[field] = o.PrivacySettings.IsUserFieldVisible(field)
+	}
 
 	return options
 }
@@ -2540,6 +3326,12 @@ func (o *Config) Sanitize() {
 		o.EmailSettings.SMTPPassword = FAKE_SETTING
 	}
 
+	for _, identity := range o.EmailSettings.TeamIdentities {
+		if len(identity.SMTPPassword) > 0 {
+			identity.SMTPPassword = FAKE_SETTING
+		}
+	}
+
 	if len(o.GitLabSettings.Secret) > 0 {
 		o.GitLabSettings.Secret = FAKE_SETTING
 	}
@@ -2556,4 +3348,18 @@ func (o *Config) Sanitize() {
 	}
 
 	*o.ElasticsearchSettings.Password = FAKE_SETTING
+
+	if len(*o.ClusterSettings.RedisPassword) > 0 {
+		*o.ClusterSettings.RedisPassword = FAKE_SETTING
+	}
+
+	for _, provider := range o.IssueUnfurlSettings.Providers {
+		if len(provider.Token) > 0 {
+			provider.Token = FAKE_SETTING
+		}
+	}
+
+	if len(*o.SmsSettings.AuthToken) > 0 {
+		*o.SmsSettings.AuthToken = FAKE_SETTING
+	}
 }
@@ -0,0 +1,173 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Settings that are only read when the server starts, so changes to them don't take effect until it's restarted. Whole
+// sections are given by their name alone.
+var configSettingsRequiringRestart = map[string]bool{
+	"ServiceSettings.ListenAddress":                   true,
+	"ServiceSettings.ConnectionSecurity":              true,
+	"ServiceSettings.TLSCertFile":                     true,
+	"ServiceSettings.TLSKeyFile":                      true,
+	"ServiceSettings.UseLetsEncrypt":                  true,
+	"ServiceSettings.LetsEncryptCertificateCacheFile": true,
+	"ServiceSettings.Forward80To443":                  true,
+	"ServiceSettings.ReadTimeout":                     true,
+	"ServiceSettings.WriteTimeout":                    true,
+	"ServiceSettings.WebserverMode":                   true,
+	"ServiceSettings.EnableApiCompression":            true,
+	"ServiceSettings.CompressionMinimumSize":          true,
+	"ServiceSettings.CompressionContentTypes":         true,
+	"ServiceSettings.AllowCorsFrom":                   true,
+	"ServiceSettings.CorsExposedHeaders":              true,
+	"ServiceSettings.CorsAllowCredentials":            true,
+	"ServiceSettings.CorsDebug":                       true,
+	"ServiceSettings.SessionCacheInMinutes":           true,
+	"SqlSettings":                                     true,
+	"ClusterSettings":                                 true,
+	"MetricsSettings.ListenAddress":                   true,
+	"PluginSettings.Directory":                        true,
+	"PluginSettings.ClientDirectory":                  true,
+}
+
+// ConfigDiff describes a setting that differs between two configs. Settings are named by their path, such as
+// "ServiceSettings.SiteURL", and secrets are given in their sanitized form.
+type ConfigDiff struct {
+	Setting             string      `json:"setting"`
+	OldValue            interface{} `json:"old_value"`
+	NewValue            interface{} `json:"new_value"`
+	RequiresRestart     bool        `json:"requires_restart"`
+	EnvironmentOverride bool        `json:"environment_override"`
+}
+
+// ConfigExport is the effective config of the server along with the settings that are set by environment variables.
+// Changes to those settings are saved but have no effect while the variables are set.
+type ConfigExport struct {
+	Config               *Config  `json:"config"`
+	EnvironmentOverrides []string `json:"environment_overrides"`
+}
+
+// ConfigImportResult describes the changes that importing a config makes, or would make when it's a dry run.
+type ConfigImportResult struct {
+	Changes         []*ConfigDiff `json:"changes"`
+	RequiresRestart bool          `json:"requires_restart"`
+	DryRun          bool          `json:"dry_run"`
+}
+
+func (o *ConfigExport) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ConfigExportFromJson(data io.Reader) *ConfigExport {
+	var o *ConfigExport
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *ConfigImportResult) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ConfigImportResultFromJson(data io.Reader) *ConfigImportResult {
+	var o *ConfigImportResult
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// ConfigSettingRequiresRestart returns true if the server has to be restarted for a change to a setting to take effect.
+func ConfigSettingRequiresRestart(setting string) bool {
+	if configSettingsRequiringRestart[setting] {
+		return true
+	}
+
+	section := strings.SplitN(setting, ".", 2)[0]
+	return configSettingsRequiringRestart[section]
+}
+
+// Diff returns the settings that differ between the config and another one, in the order that they're declared.
+// Settings that hold secrets are compared by their actual values, but only their sanitized values are returned so
+// that a diff can be shown to administrators without revealing them.
+func (o *Config) Diff(other *Config) []*ConfigDiff {
+	var settings []string
+	diffConfigValues("", reflect.ValueOf(*o), reflect.ValueOf(*other), &settings)
+
+	sanitizedOld := o.Clone()
+	sanitizedOld.Sanitize()
+	sanitizedNew := other.Clone()
+	sanitizedNew.Sanitize()
+
+	diffs := make([]*ConfigDiff, 0, len(settings))
+	for _, setting := range settings {
+		diffs = append(diffs, &ConfigDiff{
+			Setting:         setting,
+			OldValue:        getConfigValue(reflect.ValueOf(*sanitizedOld), setting),
+			NewValue:        getConfigValue(reflect.ValueOf(*sanitizedNew), setting),
+			RequiresRestart: ConfigSettingRequiresRestart(setting),
+		})
+	}
+
+	return diffs
+}
+
+// diffConfigValues adds the paths of the settings that differ between two values of the same type to settings.
+// Structs are compared field by field, while any other value is compared as a whole.
+func diffConfigValues(path string, oldValue, newValue reflect.Value, settings *[]string) {
+	if oldValue.Kind() == reflect.Ptr && oldValue.Type().Elem().Kind() == reflect.Struct && !oldValue.IsNil() && !newValue.IsNil() {
+		oldValue = oldValue.Elem()
+		newValue = newValue.Elem()
+	}
+
+	if oldValue.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			*settings = append(*settings, path)
+		}
+		return
+	}
+
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		diffConfigValues(fieldPath, oldValue.Field(i), newValue.Field(i), settings)
+	}
+}
+
+// getConfigValue returns the value of the setting at a path, or nil if there isn't one.
+func getConfigValue(value reflect.Value, path string) interface{} {
+	for _, name := range strings.Split(path, ".") {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil
+			}
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return nil
+		}
+
+		value = value.FieldByName(name)
+		if !value.IsValid() {
+			return nil
+		}
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDiff(t *testing.T) {
+	old := &Config{}
+	old.SetDefaults()
+
+	assert.Empty(t, old.Diff(old.Clone()))
+
+	updated := old.Clone()
+	updated.TeamSettings.SiteName = "Changed"
+	*updated.ServiceSettings.ListenAddress = ":8888"
+	updated.EmailSettings.SMTPPassword = "secret"
+	updated.SqlSettings.DataSourceReplicas = []string{"replica"}
+
+	diffs := old.Diff(updated)
+	require.Len(t, diffs, 4)
+
+	assert.Equal(t, "ServiceSettings.ListenAddress", diffs[0].Setting, "should be in the order that settings are declared")
+	assert.Equal(t, ":8065", *diffs[0].OldValue.(*string))
+	assert.Equal(t, ":8888", *diffs[0].NewValue.(*string))
+	assert.True(t, diffs[0].RequiresRestart)
+
+	assert.Equal(t, "TeamSettings.SiteName", diffs[1].Setting)
+	assert.False(t, diffs[1].RequiresRestart)
+
+	assert.Equal(t, "SqlSettings.DataSourceReplicas", diffs[2].Setting)
+	assert.Equal(t, []string{FAKE_SETTING}, diffs[2].NewValue, "shouldn't reveal secrets")
+	assert.True(t, diffs[2].RequiresRestart, "should require a restart for settings in sections that do")
+
+	assert.Equal(t, "EmailSettings.SMTPPassword", diffs[3].Setting)
+	assert.Equal(t, "", diffs[3].OldValue)
+	assert.Equal(t, FAKE_SETTING, diffs[3].NewValue)
+}
+
+func TestConfigDiffNestedSettings(t *testing.T) {
+	old := &Config{}
+	old.SetDefaults()
+
+	updated := old.Clone()
+	*updated.MessageExportSettings.GlobalRelaySettings.SmtpUsername = "changed"
+
+	diffs := old.Diff(updated)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, "MessageExportSettings.GlobalRelaySettings.SmtpUsername", diffs[0].Setting)
+
+	updated.MessageExportSettings.GlobalRelaySettings = nil
+
+	diffs = old.Diff(updated)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, "MessageExportSettings.GlobalRelaySettings", diffs[0].Setting)
+	assert.Nil(t, diffs[0].NewValue.(*GlobalRelayMessageExportSettings))
+}
+
+func TestConfigSettingRequiresRestart(t *testing.T) {
+	assert.True(t, ConfigSettingRequiresRestart("ServiceSettings.ListenAddress"))
+	assert.True(t, ConfigSettingRequiresRestart("SqlSettings.DataSource"))
+	assert.False(t, ConfigSettingRequiresRestart("ServiceSettings.SiteURL"))
+	assert.False(t, ConfigSettingRequiresRestart("MetricsSettings.Enable"))
+}
+
+func TestConfigImportResultJson(t *testing.T) {
+	result := &ConfigImportResult{
+		Changes:         []*ConfigDiff{{Setting: "TeamSettings.SiteName", OldValue: "Old", NewValue: "New"}},
+		RequiresRestart: true,
+		DryRun:          true,
+	}
+
+	decoded := ConfigImportResultFromJson(strings.NewReader(result.ToJson()))
+	assert.Equal(t, result, decoded)
+
+	assert.Nil(t, ConfigExportFromJson(strings.NewReader("junk")))
+}
@@ -561,3 +561,140 @@ func TestListenAddressIsValidated(t *testing.T) {
 	}
 
 }
+
+func TestIssueUnfurlSettingsIsValid(t *testing.T) {
+	s := &IssueUnfurlSettings{}
+	s.SetDefaults()
+	require.Nil(t, s.isValid())
+
+	s.Providers = []*IssueUnfurlProvider{
+		{Type: ISSUE_UNFURL_PROVIDER_JIRA, Domain: "jira.example.com:8080"},
+		{Type: ISSUE_UNFURL_PROVIDER_GITHUB, Domain: "github.com"},
+	}
+	require.Nil(t, s.isValid())
+
+	s.Providers[0].Type = "gitlab"
+	require.NotNil(t, s.isValid())
+	s.Providers[0].Type = ISSUE_UNFURL_PROVIDER_JIRA
+
+	s.Providers[1].Domain = "https://github.com/"
+	require.NotNil(t, s.isValid())
+	s.Providers[1].Domain = ""
+	require.NotNil(t, s.isValid())
+}
+
+func TestEmailSettingsIsValidTeamIdentities(t *testing.T) {
+	es := &EmailSettings{ConnectionSecurity: CONN_SECURITY_NONE}
+	es.SetDefaults()
+	require.Nil(t, es.isValid())
+
+	teamId := NewId()
+	es.TeamIdentities[teamId] = &TeamEmailIdentity{FeedbackEmail: "notifications@acme.com", ReplyToAddress: "support@acme.com"}
+	require.Nil(t, es.isValid())
+
+	es.TeamIdentities[teamId].ReplyToAddress = "support"
+	require.NotNil(t, es.isValid())
+	es.TeamIdentities[teamId].ReplyToAddress = ""
+
+	es.TeamIdentities["acme"] = &TeamEmailIdentity{}
+	require.NotNil(t, es.isValid())
+}
+
+func TestSystemEventsSettingsIsValid(t *testing.T) {
+	s := &SystemEventsSettings{}
+	s.SetDefaults()
+	require.Nil(t, s.isValid())
+
+	*s.Enable = true
+	require.NotNil(t, s.isValid())
+
+	*s.ChannelId = NewId()
+	require.Nil(t, s.isValid())
+}
+
+func TestPrivacySettingsUserFieldVisibility(t *testing.T) {
+	s := &PrivacySettings{ShowEmailAddress: true, ShowFullName: true}
+	s.SetDefaults()
+	require.Nil(t, s.isValid())
+
+	// The visibility rules only apply once data minimization is enabled
+	for _, field := range MINIMIZABLE_USER_FIELDS {
+		assert.True(t, s.IsUserFieldVisible(field), field)
+	}
+
+	*s.EnableUserDataMinimization = true
+	assert.False(t, s.IsUserFieldVisible(USER_FIELD_EMAIL))
+	assert.False(t, s.IsUserFieldVisible(USER_FIELD_FULL_NAME))
+	assert.False(t, s.IsUserFieldVisible(USER_FIELD_LAST_ACTIVITY))
+	assert.True(t, s.IsUserFieldVisible(USER_FIELD_NICKNAME))
+	assert.True(t, s.IsUserFieldVisible(USER_FIELD_POSITION))
+
+	s.UserFieldVisibility[USER_FIELD_EMAIL] = USER_FIELD_VISIBILITY_ALL
+	assert.True(t, s.IsUserFieldVisible(USER_FIELD_EMAIL))
+
+	// The existing settings still hide fields regardless of the rules
+	s.ShowEmailAddress = false
+	assert.False(t, s.IsUserFieldVisible(USER_FIELD_EMAIL))
+
+	etag := s.GetUserFieldVisibilityEtag()
+	s.UserFieldVisibility[USER_FIELD_POSITION] = USER_FIELD_VISIBILITY_ADMINS
+	assert.NotEqual(t, etag, s.GetUserFieldVisibilityEtag())
+
+	s.UserFieldVisibility[USER_FIELD_POSITION] = "everyone"
+	require.NotNil(t, s.isValid())
+	delete(s.UserFieldVisibility, USER_FIELD_POSITION)
+
+	s.UserFieldVisibility["password"] = USER_FIELD_VISIBILITY_ALL
+	require.NotNil(t, s.isValid())
+}
+
+func TestConfigGetSanitizeOptions(t *testing.T) {
+	c := &Config{}
+	c.SetDefaults()
+	c.PrivacySettings.ShowEmailAddress = true
+	c.PrivacySettings.ShowFullName = false
+	*c.PrivacySettings.EnableUserDataMinimization = true
+	c.PrivacySettings.UserFieldVisibility = map[string]string{USER_FIELD_NICKNAME: USER_FIELD_VISIBILITY_ADMINS}
+
+	options := c.GetSanitizeOptions()
+	assert.True(t, options[USER_FIELD_EMAIL])
+	assert.False(t, options[USER_FIELD_FULL_NAME])
+	assert.False(t, options[USER_FIELD_NICKNAME])
+	assert.True(t, options[USER_FIELD_POSITION])
+	assert.True(t, options[USER_FIELD_LAST_ACTIVITY])
+}
+
+func TestPluginSettingsGetLimits(t *testing.T) {
+	s := &PluginSettings{}
+	s.SetDefaults()
+	*s.MaxMemoryMB = 256
+	*s.MaxAPICallsPerMinute = 1000
+
+	limits := s.GetLimits("plugin")
+	assert.Equal(t, 256, *limits.MaxMemoryMB)
+	assert.Equal(t, 1000, *limits.MaxAPICallsPerMinute)
+	assert.Equal(t, 0, *limits.MaxKVStorageMB)
+
+	s.Limits["plugin"] = &PluginLimits{
+		MaxMemoryMB:    NewInt(0),
+		MaxKVStorageMB: NewInt(10),
+	}
+
+	limits = s.GetLimits("plugin")
+	assert.Equal(t, 0, *limits.MaxMemoryMB)
+	assert.Equal(t, 1000, *limits.MaxAPICallsPerMinute)
+	assert.Equal(t, 10, *limits.MaxKVStorageMB)
+
+	limits = s.GetLimits("otherplugin")
+	assert.Equal(t, 256, *limits.MaxMemoryMB)
+	assert.Equal(t, 0, *limits.MaxKVStorageMB)
+
+	require.Nil(t, s.isValid())
+
+	s.Limits["plugin"].MaxAPICallsPerMinute = NewInt(-1)
+	require.NotNil(t, s.isValid())
+
+	delete(s.Limits, "plugin")
+	*s.MaxKVStorageMB = -1
+	require.NotNil(t, s.isValid())
+}
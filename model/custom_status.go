@@ -0,0 +1,88 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	USER_PROPS_KEY_CUSTOM_STATUS = "customStatus"
+
+	CUSTOM_STATUS_TEXT_MAX_RUNES  = 100
+	CUSTOM_STATUS_EMOJI_MAX_RUNES = 64
+)
+
+// CustomStatus is a short message that a user shows next to their name, such as "In a meeting". It's kept in the
+// user's props so that it's sent to clients along with the rest of their profile.
+type CustomStatus struct {
+	Emoji     string `json:"emoji"`
+	Text      string `json:"text"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+func (cs *CustomStatus) ToJson() string {
+	b, _ := json.Marshal(cs)
+	return string(b)
+}
+
+func CustomStatusFromJson(data io.Reader) *CustomStatus {
+	var cs *CustomStatus
+	json.NewDecoder(data).Decode(&cs)
+	return cs
+}
+
+func (cs *CustomStatus) IsValid() *AppError {
+	if cs.Emoji == "" && cs.Text == "" {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.empty.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(cs.Text) > CUSTOM_STATUS_TEXT_MAX_RUNES {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.text.app_error", map[string]interface{}{"Max": CUSTOM_STATUS_TEXT_MAX_RUNES}, "", http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(cs.Emoji) > CUSTOM_STATUS_EMOJI_MAX_RUNES {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.emoji.app_error", nil, "emoji="+cs.Emoji, http.StatusBadRequest)
+	}
+
+	if cs.ExpiresAt < 0 {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.expires_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsExpired returns true if the custom status had an expiry time that has passed.
+func (cs *CustomStatus) IsExpired() bool {
+	return cs.ExpiresAt > 0 && cs.ExpiresAt <= GetMillis()
+}
+
+// GetCustomStatus returns the user's custom status, or nil if they don't have one or if it has expired.
+func (u *User) GetCustomStatus() *CustomStatus {
+	data, ok := u.Props[USER_PROPS_KEY_CUSTOM_STATUS]
+	if !ok || data == "" {
+		return nil
+	}
+
+	var cs *CustomStatus
+	if err := json.Unmarshal([]byte(data), &cs); err != nil || cs == nil || cs.IsExpired() {
+		return nil
+	}
+
+	return cs
+}
+
+func (u *User) SetCustomStatus(cs *CustomStatus) {
+	if u.Props == nil {
+		u.Props = StringMap{}
+	}
+	u.Props[USER_PROPS_KEY_CUSTOM_STATUS] = cs.ToJson()
+}
+
+func (u *User) ClearCustomStatus() {
+	delete(u.Props, USER_PROPS_KEY_CUSTOM_STATUS)
+}
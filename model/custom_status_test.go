@@ -0,0 +1,40 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomStatusIsValid(t *testing.T) {
+	assert.Nil(t, (&CustomStatus{Emoji: "calendar", Text: "In a meeting"}).IsValid())
+	assert.Nil(t, (&CustomStatus{Text: "In a meeting"}).IsValid())
+	assert.Nil(t, (&CustomStatus{Emoji: "calendar"}).IsValid())
+
+	assert.NotNil(t, (&CustomStatus{}).IsValid())
+	assert.NotNil(t, (&CustomStatus{Text: strings.Repeat("a", CUSTOM_STATUS_TEXT_MAX_RUNES+1)}).IsValid())
+	assert.NotNil(t, (&CustomStatus{Emoji: strings.Repeat("a", CUSTOM_STATUS_EMOJI_MAX_RUNES+1)}).IsValid())
+	assert.NotNil(t, (&CustomStatus{Text: "In a meeting", ExpiresAt: -1}).IsValid())
+}
+
+func TestUserCustomStatus(t *testing.T) {
+	user := &User{}
+	assert.Nil(t, user.GetCustomStatus())
+
+	user.SetCustomStatus(&CustomStatus{Emoji: "calendar", Text: "In a meeting"})
+	cs := user.GetCustomStatus()
+	require.NotNil(t, cs)
+	assert.Equal(t, "In a meeting", cs.Text)
+
+	user.SetCustomStatus(&CustomStatus{Text: "In a meeting", ExpiresAt: GetMillis() - 1000})
+	assert.Nil(t, user.GetCustomStatus(), "should not return an expired status")
+
+	user.ClearCustomStatus()
+	assert.Nil(t, user.GetCustomStatus())
+	assert.NotContains(t, user.Props, USER_PROPS_KEY_CUSTOM_STATUS)
+}
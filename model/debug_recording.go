@@ -0,0 +1,222 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	DEBUG_RECORDING_DEFAULT_DURATION_SECONDS = 10 * 60
+	DEBUG_RECORDING_MAX_DURATION_SECONDS     = 60 * 60
+	DEBUG_RECORDING_MAX_REQUESTS             = 500
+	DEBUG_RECORDING_MAX_BODY_SIZE            = 16 * 1024
+
+	DEBUG_RECORDING_REDACTED = "[redacted]"
+)
+
+// Matches string fields in JSON that couldn't be parsed, such as when it was cut off at the maximum body size
+var debugRecordingSensitiveJsonField = regexp.MustCompile(`(?i)("[^"]*(?:password|token|secret)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"?`)
+
+// Headers that carry credentials are never recorded
+var debugRecordingSensitiveHeaders = []string{
+	HEADER_AUTH,
+	HEADER_TOKEN,
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Query parameters and body fields whose names contain any of these are never recorded
+var debugRecordingSensitiveNames = []string{
+	"password",
+	"token",
+	"secret",
+}
+
+// DebugRecordingFilter chooses which requests are recorded. Requests are recorded if their path starts with any of
+// the path prefixes or if they were made by the integration with the given id, such as a webhook or user access token.
+type DebugRecordingFilter struct {
+	PathPrefixes    []string `json:"path_prefixes"`
+	IntegrationId   string   `json:"integration_id"`
+	DurationSeconds int      `json:"duration_seconds"`
+}
+
+// DebugRecording holds the requests that a server has recorded to help with debugging integrations and clients.
+// Recordings are kept in memory by the server that handled the requests and are lost when it restarts.
+type DebugRecording struct {
+	DebugRecordingFilter
+	StartedBy       string                  `json:"started_by"`
+	StartAt         int64                   `json:"start_at"`
+	ExpireAt        int64                   `json:"expire_at"`
+	DroppedRequests int                     `json:"dropped_requests"`
+	Requests        []*DebugRecordedRequest `json:"requests"`
+}
+
+// DebugRecordedRequest is a request and the response that was sent for it, with any credentials removed.
+type DebugRecordedRequest struct {
+	RequestId         string             `json:"request_id"`
+	Timestamp         int64              `json:"timestamp"`
+	ElapsedMs         float64            `json:"elapsed_ms"`
+	Method            string             `json:"method"`
+	Path              string             `json:"path"`
+	Query             string             `json:"query,omitempty"`
+	UserId            string             `json:"user_id,omitempty"`
+	Integration       *IntegrationSource `json:"integration,omitempty"`
+	RequestHeaders    map[string]string  `json:"request_headers"`
+	RequestBody       string             `json:"request_body,omitempty"`
+	RequestTruncated  bool               `json:"request_truncated,omitempty"`
+	StatusCode        int                `json:"status_code"`
+	ResponseHeaders   map[string]string  `json:"response_headers"`
+	ResponseBody      string             `json:"response_body,omitempty"`
+	ResponseTruncated bool               `json:"response_truncated,omitempty"`
+}
+
+func (o *DebugRecordingFilter) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func DebugRecordingFilterFromJson(data io.Reader) *DebugRecordingFilter {
+	var o *DebugRecordingFilter
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *DebugRecordingFilter) IsValid() *AppError {
+	if len(o.PathPrefixes) == 0 && o.IntegrationId == "" {
+		return NewAppError("DebugRecordingFilter.IsValid", "model.debug_recording.is_valid.filter.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, prefix := range o.PathPrefixes {
+		if !strings.HasPrefix(prefix, "/") {
+			return NewAppError("DebugRecordingFilter.IsValid", "model.debug_recording.is_valid.path_prefix.app_error", nil, "path_prefix="+prefix, http.StatusBadRequest)
+		}
+	}
+
+	if o.DurationSeconds < 0 || o.DurationSeconds > DEBUG_RECORDING_MAX_DURATION_SECONDS {
+		return NewAppError("DebugRecordingFilter.IsValid", "model.debug_recording.is_valid.duration.app_error", map[string]interface{}{"Max": DEBUG_RECORDING_MAX_DURATION_SECONDS}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Matches returns true if a request to the path made by the integration should be recorded.
+func (o *DebugRecordingFilter) Matches(path string, integration *IntegrationSource) bool {
+	if o.IntegrationId != "" && integration != nil && integration.Id == o.IntegrationId {
+		return true
+	}
+
+	for _, prefix := range o.PathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *DebugRecording) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func DebugRecordingFromJson(data io.Reader) *DebugRecording {
+	var o *DebugRecording
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func isDebugRecordingSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range debugRecordingSensitiveNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SanitizeDebugHeaders flattens headers for recording, redacting those that carry credentials.
+func SanitizeDebugHeaders(header http.Header) map[string]string {
+	sanitized := make(map[string]string, len(header))
+	for name, values := range header {
+		sanitized[name] = strings.Join(values, ", ")
+	}
+
+	for _, name := range debugRecordingSensitiveHeaders {
+		if _, ok := sanitized[http.CanonicalHeaderKey(name)]; ok {
+			sanitized[http.CanonicalHeaderKey(name)] = DEBUG_RECORDING_REDACTED
+		}
+	}
+
+	return sanitized
+}
+
+// SanitizeDebugQuery redacts the values of query parameters that look like they carry credentials.
+func SanitizeDebugQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+
+	sanitizeDebugValues(values)
+	return values.Encode()
+}
+
+func sanitizeDebugValues(values url.Values) {
+	for name := range values {
+		if isDebugRecordingSensitiveName(name) {
+			values[name] = []string{DEBUG_RECORDING_REDACTED}
+		}
+	}
+}
+
+// SanitizeDebugBody redacts fields that look like they carry credentials from JSON and form encoded bodies. Bodies in
+// other formats are recorded as they are.
+func SanitizeDebugBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			sanitizeDebugValues(values)
+			return values.Encode()
+		}
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		if b, err := json.Marshal(sanitizeDebugJson(data)); err == nil {
+			return string(b)
+		}
+	}
+
+	return debugRecordingSensitiveJsonField.ReplaceAllString(string(body), `${1}"`+DEBUG_RECORDING_REDACTED+`"`)
+}
+
+func sanitizeDebugJson(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for name, field := range value {
+			if isDebugRecordingSensitiveName(name) {
+				value[name] = DEBUG_RECORDING_REDACTED
+			} else {
+				value[name] = sanitizeDebugJson(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = sanitizeDebugJson(item)
+		}
+	}
+
+	return data
+}
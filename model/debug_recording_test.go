@@ -0,0 +1,76 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugRecordingFilterIsValid(t *testing.T) {
+	assert.NotNil(t, (&DebugRecordingFilter{}).IsValid())
+	assert.NotNil(t, (&DebugRecordingFilter{PathPrefixes: []string{"api/v4/posts"}}).IsValid())
+	assert.NotNil(t, (&DebugRecordingFilter{IntegrationId: NewId(), DurationSeconds: -1}).IsValid())
+	assert.NotNil(t, (&DebugRecordingFilter{IntegrationId: NewId(), DurationSeconds: DEBUG_RECORDING_MAX_DURATION_SECONDS + 1}).IsValid())
+
+	assert.Nil(t, (&DebugRecordingFilter{PathPrefixes: []string{"/api/v4/posts"}}).IsValid())
+	assert.Nil(t, (&DebugRecordingFilter{IntegrationId: NewId(), DurationSeconds: 60}).IsValid())
+}
+
+func TestDebugRecordingFilterMatches(t *testing.T) {
+	hookId := NewId()
+	filter := &DebugRecordingFilter{PathPrefixes: []string{"/api/v4/posts"}, IntegrationId: hookId}
+
+	assert.True(t, filter.Matches("/api/v4/posts/"+NewId(), nil))
+	assert.True(t, filter.Matches("/hooks/"+hookId, &IntegrationSource{Type: INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: hookId}))
+	assert.False(t, filter.Matches("/hooks/"+NewId(), &IntegrationSource{Type: INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: NewId()}))
+	assert.False(t, filter.Matches("/api/v4/users/me", nil))
+}
+
+func TestSanitizeDebugHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abc")
+	header.Set(HEADER_TOKEN, "abc")
+	header.Add("Accept", "application/json")
+	header.Add("Accept", "text/plain")
+
+	sanitized := SanitizeDebugHeaders(header)
+	assert.Equal(t, DEBUG_RECORDING_REDACTED, sanitized["Authorization"])
+	assert.Equal(t, DEBUG_RECORDING_REDACTED, sanitized["Token"])
+	assert.Equal(t, "application/json, text/plain", sanitized["Accept"])
+}
+
+func TestSanitizeDebugQuery(t *testing.T) {
+	query := SanitizeDebugQuery("page=1&access_token=abc")
+	assert.Contains(t, query, "page=1")
+	assert.NotContains(t, query, "abc")
+}
+
+func TestSanitizeDebugBody(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		body := SanitizeDebugBody("application/json", []byte(`{"login_id":"user","password":"hunter2","props":[{"client_secret":"abc"}]}`))
+		assert.Contains(t, body, `"login_id":"user"`)
+		assert.NotContains(t, body, "hunter2")
+		assert.NotContains(t, body, "abc")
+	})
+
+	t.Run("truncated json", func(t *testing.T) {
+		body := SanitizeDebugBody("application/json", []byte(`{"login_id":"user","Password":"hunter2","text":"`+strings.Repeat("a", 10)))
+		assert.Contains(t, body, `"login_id":"user"`)
+		assert.NotContains(t, body, "hunter2")
+	})
+
+	t.Run("form", func(t *testing.T) {
+		body := SanitizeDebugBody("application/x-www-form-urlencoded", []byte("text=hello&token=abc"))
+		assert.Contains(t, body, "text=hello")
+		assert.NotContains(t, body, "abc")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "", SanitizeDebugBody("application/json", nil))
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+// EmailDigestEntry is a post notification that has been queued to be sent to a user as part of an email digest.
+// TeamId is left empty for direct and group messages sent to users who don't belong to any team.
+type EmailDigestEntry struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	PostId    string `json:"post_id"`
+	ChannelId string `json:"channel_id"`
+	TeamId    string `json:"team_id"`
+	CreateAt  int64  `json:"create_at"`
+}
+
+func (o *EmailDigestEntry) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.PostId) != 26 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.post_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.ChannelId) != 26 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 0 && len(o.TeamId) != 26 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("EmailDigestEntry.IsValid", "model.email_digest_entry.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *EmailDigestEntry) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailDigestEntryIsValid(t *testing.T) {
+	entry := &EmailDigestEntry{}
+	assert.NotNil(t, entry.IsValid())
+
+	entry.PreSave()
+	assert.NotNil(t, entry.IsValid())
+
+	entry.UserId = NewId()
+	entry.PostId = NewId()
+	entry.ChannelId = NewId()
+	assert.Nil(t, entry.IsValid())
+
+	entry.TeamId = "junk"
+	assert.NotNil(t, entry.IsValid())
+
+	entry.TeamId = NewId()
+	assert.Nil(t, entry.IsValid())
+
+	entry.CreateAt = 0
+	assert.NotNil(t, entry.IsValid())
+}
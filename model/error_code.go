@@ -0,0 +1,170 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+// Error codes are stable, machine-readable descriptions of why a request failed. Unlike the ids of AppErrors, which
+// change whenever the code that returns them is reorganized, clients can rely on these to decide how to handle errors.
+const (
+	ERROR_CODE_BAD_REQUEST         = "bad_request"
+	ERROR_CODE_INVALID_PARAM       = "invalid_param"
+	ERROR_CODE_UNAUTHORIZED        = "unauthorized"
+	ERROR_CODE_SESSION_EXPIRED     = "session_expired"
+	ERROR_CODE_MFA_REQUIRED        = "mfa_required"
+	ERROR_CODE_FORBIDDEN           = "forbidden"
+	ERROR_CODE_PERMISSION_DENIED   = "permission_denied"
+	ERROR_CODE_NOT_FOUND           = "not_found"
+	ERROR_CODE_ALREADY_EXISTS      = "already_exists"
+	ERROR_CODE_CONFLICT            = "conflict"
+	ERROR_CODE_TOO_LARGE           = "too_large"
+	ERROR_CODE_RATE_LIMITED        = "rate_limited"
+	ERROR_CODE_FEATURE_DISABLED    = "feature_disabled"
+	ERROR_CODE_LICENSE_REQUIRED    = "license_required"
+	ERROR_CODE_NOT_IMPLEMENTED     = "not_implemented"
+	ERROR_CODE_INTERNAL_ERROR      = "internal_error"
+	ERROR_CODE_SERVICE_UNAVAILABLE = "service_unavailable"
+	ERROR_CODE_FILE_REJECTED       = "file_rejected"
+)
+
+// ErrorCatalogEntry describes an error code so that it can be documented for clients and integrations.
+type ErrorCatalogEntry struct {
+	Code       string `json:"code"`
+	StatusCode int    `json:"status_code"`
+	Hint       string `json:"hint"`
+}
+
+// ErrorCatalog lists every error code along with the status code that it's usually returned with. The hints are
+// translation ids until the catalog is localized.
+var ErrorCatalog = []*ErrorCatalogEntry{
+	{ERROR_CODE_BAD_REQUEST, http.StatusBadRequest, "model.error_code.bad_request.hint"},
+	{ERROR_CODE_INVALID_PARAM, http.StatusBadRequest, "model.error_code.invalid_param.hint"},
+	{ERROR_CODE_UNAUTHORIZED, http.StatusUnauthorized, "model.error_code.unauthorized.hint"},
+	{ERROR_CODE_SESSION_EXPIRED, http.StatusUnauthorized, "model.error_code.session_expired.hint"},
+	{ERROR_CODE_MFA_REQUIRED, http.StatusUnauthorized, "model.error_code.mfa_required.hint"},
+	{ERROR_CODE_FORBIDDEN, http.StatusForbidden, "model.error_code.forbidden.hint"},
+	{ERROR_CODE_PERMISSION_DENIED, http.StatusForbidden, "model.error_code.permission_denied.hint"},
+	{ERROR_CODE_NOT_FOUND, http.StatusNotFound, "model.error_code.not_found.hint"},
+	{ERROR_CODE_ALREADY_EXISTS, http.StatusBadRequest, "model.error_code.already_exists.hint"},
+	{ERROR_CODE_CONFLICT, http.StatusConflict, "model.error_code.conflict.hint"},
+	{ERROR_CODE_TOO_LARGE, http.StatusRequestEntityTooLarge, "model.error_code.too_large.hint"},
+	{ERROR_CODE_RATE_LIMITED, http.StatusTooManyRequests, "model.error_code.rate_limited.hint"},
+	{ERROR_CODE_FEATURE_DISABLED, http.StatusNotImplemented, "model.error_code.feature_disabled.hint"},
+	{ERROR_CODE_LICENSE_REQUIRED, http.StatusNotImplemented, "model.error_code.license_required.hint"},
+	{ERROR_CODE_NOT_IMPLEMENTED, http.StatusNotImplemented, "model.error_code.not_implemented.hint"},
+	{ERROR_CODE_INTERNAL_ERROR, http.StatusInternalServerError, "model.error_code.internal_error.hint"},
+	{ERROR_CODE_SERVICE_UNAVAILABLE, http.StatusServiceUnavailable, "model.error_code.service_unavailable.hint"},
+	{ERROR_CODE_FILE_REJECTED, http.StatusBadRequest, "model.error_code.file_rejected.hint"},
+}
+
+// errorCodesById maps the ids of errors that are returned throughout the API to their codes.
+var errorCodesById = map[string]string{
+	"api.context.invalid_param.app_error":      ERROR_CODE_INVALID_PARAM,
+	"api.context.invalid_url_param.app_error":  ERROR_CODE_INVALID_PARAM,
+	"api.context.invalid_body_param.app_error": ERROR_CODE_INVALID_PARAM,
+	"api.context.session_expired.app_error":    ERROR_CODE_SESSION_EXPIRED,
+	"api.context.invalid_token.error":          ERROR_CODE_SESSION_EXPIRED,
+	"api.context.token_provided.app_error":     ERROR_CODE_UNAUTHORIZED,
+	"api.context.mfa_required.app_error":       ERROR_CODE_MFA_REQUIRED,
+	"api.context.permissions.app_error":        ERROR_CODE_PERMISSION_DENIED,
+	"model.utils.decode_json.app_error":        ERROR_CODE_BAD_REQUEST,
+
+	"model.team_upload_policy.check_file.extension_not_allowed.app_error": ERROR_CODE_FILE_REJECTED,
+	"model.team_upload_policy.check_file.extension_blocked.app_error":     ERROR_CODE_FILE_REJECTED,
+	"model.team_upload_policy.check_file.content_blocked.app_error":       ERROR_CODE_FILE_REJECTED,
+	"model.team_upload_policy.check_file.content_mismatch.app_error":      ERROR_CODE_FILE_REJECTED,
+	"model.team_upload_policy.check_file.executable.app_error":            ERROR_CODE_FILE_REJECTED,
+}
+
+// errorCodesByIdPart maps the names that are used consistently at the end of error ids for the same kind of error to
+// their codes, such as "store.sql_channel.save_channel.exists.app_error". Some are also used as suffixes, such as in
+// "api.post.link_preview_disabled.app_error".
+var errorCodesByIdPart = []struct {
+	part        string
+	code        string
+	matchSuffix bool
+}{
+	{"license", ERROR_CODE_LICENSE_REQUIRED, false},
+	{"disabled", ERROR_CODE_FEATURE_DISABLED, true},
+	{"too_large", ERROR_CODE_TOO_LARGE, false},
+	{"not_found", ERROR_CODE_NOT_FOUND, true},
+	{"exists", ERROR_CODE_ALREADY_EXISTS, false},
+	{"duplicate", ERROR_CODE_ALREADY_EXISTS, false},
+}
+
+var errorCodesByStatusCode = map[int]string{
+	http.StatusBadRequest:            ERROR_CODE_BAD_REQUEST,
+	http.StatusUnauthorized:          ERROR_CODE_UNAUTHORIZED,
+	http.StatusForbidden:             ERROR_CODE_FORBIDDEN,
+	http.StatusNotFound:              ERROR_CODE_NOT_FOUND,
+	http.StatusConflict:              ERROR_CODE_CONFLICT,
+	http.StatusRequestEntityTooLarge: ERROR_CODE_TOO_LARGE,
+	http.StatusTooManyRequests:       ERROR_CODE_RATE_LIMITED,
+	http.StatusNotImplemented:        ERROR_CODE_NOT_IMPLEMENTED,
+	http.StatusServiceUnavailable:    ERROR_CODE_SERVICE_UNAVAILABLE,
+}
+
+// GetErrorCode returns the code for an error, looking first at its id and then at its status code for errors that
+// aren't listed individually.
+func GetErrorCode(id string, statusCode int) string {
+	if code, ok := errorCodesById[id]; ok {
+		return code
+	}
+
+	if parts := strings.Split(id, "."); len(parts) >= 2 {
+		part := parts[len(parts)-2]
+		for _, rule := range errorCodesByIdPart {
+			if part == rule.part || (rule.matchSuffix && strings.HasSuffix(part, "_"+rule.part)) {
+				return rule.code
+			}
+		}
+	}
+
+	if code, ok := errorCodesByStatusCode[statusCode]; ok {
+		return code
+	}
+
+	if statusCode >= 400 && statusCode < 500 {
+		return ERROR_CODE_BAD_REQUEST
+	}
+
+	return ERROR_CODE_INTERNAL_ERROR
+}
+
+// GetErrorCodeHintId returns the translation id of the hint for an error code.
+func GetErrorCodeHintId(code string) string {
+	return "model.error_code." + code + ".hint"
+}
+
+// GetLocalizedErrorCatalog returns a copy of the error catalog with its hints translated.
+func GetLocalizedErrorCatalog(T goi18n.TranslateFunc) []*ErrorCatalogEntry {
+	catalog := make([]*ErrorCatalogEntry, len(ErrorCatalog))
+	for i, entry := range ErrorCatalog {
+		catalog[i] = &ErrorCatalogEntry{
+			Code:       entry.Code,
+			StatusCode: entry.StatusCode,
+			Hint:       T(entry.Hint),
+		}
+	}
+
+	return catalog
+}
+
+func ErrorCatalogToJson(catalog []*ErrorCatalogEntry) string {
+	b, _ := json.Marshal(catalog)
+	return string(b)
+}
+
+func ErrorCatalogFromJson(data io.Reader) []*ErrorCatalogEntry {
+	var catalog []*ErrorCatalogEntry
+	json.NewDecoder(data).Decode(&catalog)
+	return catalog
+}
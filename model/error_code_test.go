@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorCode(t *testing.T) {
+	for _, testCase := range []struct {
+		Id         string
+		StatusCode int
+		Expected   string
+	}{
+		{"api.context.invalid_url_param.app_error", http.StatusBadRequest, ERROR_CODE_INVALID_PARAM},
+		{"api.context.permissions.app_error", http.StatusForbidden, ERROR_CODE_PERMISSION_DENIED},
+		{"api.context.session_expired.app_error", http.StatusUnauthorized, ERROR_CODE_SESSION_EXPIRED},
+		{"api.roles.patch_roles.license.error", http.StatusNotImplemented, ERROR_CODE_LICENSE_REQUIRED},
+		{"api.license.add_license.invalid.app_error", http.StatusBadRequest, ERROR_CODE_BAD_REQUEST},
+		{"api.post.link_preview_disabled.app_error", http.StatusNotImplemented, ERROR_CODE_FEATURE_DISABLED},
+		{"app.sms.disabled.app_error", http.StatusNotImplemented, ERROR_CODE_FEATURE_DISABLED},
+		{"store.sql_channel.save_channel.exists.app_error", http.StatusBadRequest, ERROR_CODE_ALREADY_EXISTS},
+		{"api.emoji.create.duplicate.app_error", http.StatusBadRequest, ERROR_CODE_ALREADY_EXISTS},
+		{"api.file.file_exists.s3.app_error", http.StatusInternalServerError, ERROR_CODE_INTERNAL_ERROR},
+		{"api.file.upload_file.too_large.app_error", http.StatusRequestEntityTooLarge, ERROR_CODE_TOO_LARGE},
+		{"store.sql_user.missing_account.const", http.StatusNotFound, ERROR_CODE_NOT_FOUND},
+		{"api.user.update_password.failed.app_error", http.StatusBadRequest, ERROR_CODE_BAD_REQUEST},
+		{"api.something.conflict.app_error", http.StatusConflict, ERROR_CODE_CONFLICT},
+		{"api.something.invalid.app_error", http.StatusUnprocessableEntity, ERROR_CODE_BAD_REQUEST},
+		{"app.rate_limit.limited.app_error", http.StatusTooManyRequests, ERROR_CODE_RATE_LIMITED},
+		{"api.something.failed.app_error", http.StatusInternalServerError, ERROR_CODE_INTERNAL_ERROR},
+		{"", 0, ERROR_CODE_INTERNAL_ERROR},
+	} {
+		t.Run(testCase.Id, func(t *testing.T) {
+			assert.Equal(t, testCase.Expected, GetErrorCode(testCase.Id, testCase.StatusCode))
+		})
+	}
+}
+
+func TestErrorCatalog(t *testing.T) {
+	codes := map[string]bool{}
+	for _, entry := range ErrorCatalog {
+		assert.False(t, codes[entry.Code], "codes should only be listed once")
+		codes[entry.Code] = true
+
+		assert.Equal(t, GetErrorCodeHintId(entry.Code), entry.Hint)
+	}
+
+	// Every code that can be returned should be documented
+	for _, code := range errorCodesById {
+		assert.True(t, codes[code], code)
+	}
+	for _, rule := range errorCodesByIdPart {
+		assert.True(t, codes[rule.code], rule.code)
+	}
+	for _, code := range errorCodesByStatusCode {
+		assert.True(t, codes[code], code)
+	}
+}
+
+func TestAppErrorCode(t *testing.T) {
+	err := NewAppError("Test", "api.context.invalid_param.app_error", nil, "", http.StatusBadRequest)
+	err.Translate(nil)
+
+	assert.Equal(t, ERROR_CODE_INVALID_PARAM, err.Code)
+	assert.Equal(t, "", err.Hint)
+
+	err.Translate(func(translationId string, args ...interface{}) string {
+		return "translated " + translationId
+	})
+
+	assert.Equal(t, ERROR_CODE_INVALID_PARAM, err.Code)
+	assert.Equal(t, "translated model.error_code.invalid_param.hint", err.Hint)
+
+	decoded := AppErrorFromJson(strings.NewReader(err.ToJson()))
+	assert.Equal(t, ERROR_CODE_INVALID_PARAM, decoded.Code)
+	assert.Equal(t, err.Hint, decoded.Hint)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	EVENT_SUBSCRIPTION_EVENT_POST_CREATED     = "post_created"
+	EVENT_SUBSCRIPTION_EVENT_USER_ADDED       = "user_added"
+	EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED = "channel_archived"
+
+	EVENT_SUBSCRIPTION_CALLBACK_URL_MAX_LENGTH = 1024
+	EVENT_SUBSCRIPTION_DESCRIPTION_MAX_LENGTH  = 500
+
+	// Subscriptions are deactivated once this many deliveries to them have failed in a row
+	EVENT_SUBSCRIPTION_MAX_FAILURES = 10
+)
+
+// EventSubscription registers a callback URL that's sent an EventSubscriptionPayload whenever an event happens on the
+// server. Subscriptions are scoped to a team, and subscriptions to posts are scoped to one of its channels. Payloads are
+// signed with the subscription's secret like outgoing webhooks are. Failures counts the deliveries that have failed in a
+// row, and the subscription stops being active when there are too many of them.
+type EventSubscription struct {
+	Id            string `json:"id"`
+	CreateAt      int64  `json:"create_at"`
+	UpdateAt      int64  `json:"update_at"`
+	DeleteAt      int64  `json:"delete_at"`
+	CreatorId     string `json:"creator_id"`
+	TeamId        string `json:"team_id"`
+	ChannelId     string `json:"channel_id"`
+	EventType     string `json:"event_type"`
+	CallbackURL   string `json:"callback_url"`
+	Description   string `json:"description"`
+	SigningSecret string `json:"signing_secret"`
+	Active        bool   `json:"active"`
+	Failures      int    `json:"failures"`
+}
+
+type EventSubscriptionPatch struct {
+	CallbackURL *string `json:"callback_url"`
+	Description *string `json:"description"`
+	Active      *bool   `json:"active"`
+}
+
+// EventSubscriptionPayload is what's sent to a subscription's callback URL. The fields that are set depend on the event:
+// new posts send the post, added users send the user's id, and archived channels send the channel.
+type EventSubscriptionPayload struct {
+	SubscriptionId string   `json:"subscription_id"`
+	Event          string   `json:"event"`
+	Timestamp      int64    `json:"timestamp"`
+	TeamId         string   `json:"team_id"`
+	ChannelId      string   `json:"channel_id,omitempty"`
+	UserId         string   `json:"user_id,omitempty"`
+	Post           *Post    `json:"post,omitempty"`
+	Channel        *Channel `json:"channel,omitempty"`
+}
+
+func IsValidEventSubscriptionEvent(event string) bool {
+	switch event {
+	case EVENT_SUBSCRIPTION_EVENT_POST_CREATED, EVENT_SUBSCRIPTION_EVENT_USER_ADDED, EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED:
+		return true
+	}
+
+	return false
+}
+
+func (o *EventSubscription) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func EventSubscriptionFromJson(data io.Reader) *EventSubscription {
+	var o *EventSubscription
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func EventSubscriptionListToJson(l []*EventSubscription) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func EventSubscriptionListFromJson(data io.Reader) []*EventSubscription {
+	var o []*EventSubscription
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *EventSubscriptionPatch) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func EventSubscriptionPatchFromJson(data io.Reader) *EventSubscriptionPatch {
+	var o *EventSubscriptionPatch
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *EventSubscriptionPayload) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func EventSubscriptionPayloadFromJson(data io.Reader) *EventSubscriptionPayload {
+	var o *EventSubscriptionPayload
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *EventSubscription) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.CreatorId) != 26 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.creator_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidEventSubscriptionEvent(o.EventType) {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.event_type.app_error", nil, "id="+o.Id+", event_type="+o.EventType, http.StatusBadRequest)
+	}
+
+	// Posts are only sent for a single channel, while the other events are about the whole team
+	if o.EventType == EVENT_SUBSCRIPTION_EVENT_POST_CREATED {
+		if len(o.ChannelId) != 26 {
+			return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	} else if len(o.ChannelId) != 0 {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.CallbackURL) > EVENT_SUBSCRIPTION_CALLBACK_URL_MAX_LENGTH || !IsValidHttpUrl(o.CallbackURL) {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.callback_url.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Description) > EVENT_SUBSCRIPTION_DESCRIPTION_MAX_LENGTH {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.description.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.SigningSecret) == 0 || len(o.SigningSecret) > OUTGOING_HOOK_SIGNING_SECRET_MAX_LENGTH {
+		return NewAppError("EventSubscription.IsValid", "model.event_subscription.is_valid.signing_secret.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// PreSave prepares a new subscription, which is active until deliveries to it start failing.
+func (o *EventSubscription) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.SigningSecret == "" {
+		o.SigningSecret = NewOutgoingWebhookSigningSecret()
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+	o.DeleteAt = 0
+	o.Active = true
+	o.Failures = 0
+}
+
+func (o *EventSubscription) PreUpdate() {
+	o.UpdateAt = GetMillis()
+}
+
+// Patch applies a patch to a subscription. Reactivating a subscription clears its failures so that it gets a full
+// number of deliveries before it's deactivated again.
+func (o *EventSubscription) Patch(patch *EventSubscriptionPatch) {
+	if patch.CallbackURL != nil {
+		o.CallbackURL = *patch.CallbackURL
+	}
+
+	if patch.Description != nil {
+		o.Description = *patch.Description
+	}
+
+	if patch.Active != nil {
+		if *patch.Active && !o.Active {
+			o.Failures = 0
+		}
+		o.Active = *patch.Active
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSubscriptionJson(t *testing.T) {
+	subscription := &EventSubscription{Id: NewId(), EventType: EVENT_SUBSCRIPTION_EVENT_USER_ADDED}
+	assert.Equal(t, subscription, EventSubscriptionFromJson(strings.NewReader(subscription.ToJson())))
+
+	payload := &EventSubscriptionPayload{SubscriptionId: NewId(), Event: EVENT_SUBSCRIPTION_EVENT_POST_CREATED, Post: &Post{Id: NewId()}}
+	assert.Equal(t, payload, EventSubscriptionPayloadFromJson(strings.NewReader(payload.ToJson())))
+}
+
+func TestEventSubscriptionIsValid(t *testing.T) {
+	subscription := &EventSubscription{
+		CreatorId:   NewId(),
+		TeamId:      NewId(),
+		EventType:   EVENT_SUBSCRIPTION_EVENT_USER_ADDED,
+		CallbackURL: "https://example.com/events",
+	}
+	subscription.PreSave()
+	require.Nil(t, subscription.IsValid())
+	assert.True(t, subscription.Active)
+	assert.NotEmpty(t, subscription.SigningSecret)
+
+	subscription.EventType = "junk"
+	assert.NotNil(t, subscription.IsValid())
+
+	subscription.EventType = EVENT_SUBSCRIPTION_EVENT_POST_CREATED
+	assert.NotNil(t, subscription.IsValid(), "should require a channel for posts")
+
+	subscription.ChannelId = NewId()
+	assert.Nil(t, subscription.IsValid())
+
+	subscription.EventType = EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED
+	assert.NotNil(t, subscription.IsValid(), "shouldn't allow a channel for other events")
+
+	subscription.ChannelId = ""
+	subscription.CallbackURL = "ftp://example.com"
+	assert.NotNil(t, subscription.IsValid())
+
+	subscription.CallbackURL = "https://example.com/events"
+	subscription.Description = strings.Repeat("a", EVENT_SUBSCRIPTION_DESCRIPTION_MAX_LENGTH+1)
+	assert.NotNil(t, subscription.IsValid())
+}
+
+func TestEventSubscriptionPatch(t *testing.T) {
+	subscription := &EventSubscription{
+		CallbackURL: "https://example.com/events",
+		Description: "events",
+		Active:      false,
+		Failures:    EVENT_SUBSCRIPTION_MAX_FAILURES,
+	}
+
+	description := "new description"
+	subscription.Patch(&EventSubscriptionPatch{Description: &description})
+	assert.Equal(t, "new description", subscription.Description)
+	assert.Equal(t, "https://example.com/events", subscription.CallbackURL)
+	assert.False(t, subscription.Active)
+
+	active := true
+	subscription.Patch(&EventSubscriptionPatch{Active: &active})
+	assert.True(t, subscription.Active)
+	assert.Equal(t, 0, subscription.Failures, "should clear the failures when reactivated")
+}
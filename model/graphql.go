@@ -0,0 +1,48 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// GraphQLRequest is a query sent to the GraphQL endpoint.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLError is an error that happened while resolving part of a query. Extensions holds the id and status code of
+// the AppError that caused it, if any.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLResponse is the result of a query. Data is shaped like the query, and it can hold a partial result even when
+// there are errors.
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []*GraphQLError `json:"errors,omitempty"`
+}
+
+func (o *GraphQLRequest) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func GraphQLRequestFromJson(data io.Reader) *GraphQLRequest {
+	var o *GraphQLRequest
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func GraphQLResponseFromJson(data io.Reader) *GraphQLResponse {
+	var o *GraphQLResponse
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
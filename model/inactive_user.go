@@ -0,0 +1,78 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	INACTIVE_USER_ACTION_FLAG       = "flag"
+	INACTIVE_USER_ACTION_DEACTIVATE = "deactivate"
+
+	INACTIVE_USER_DAY_MILLISECONDS = 24 * 60 * 60 * 1000
+)
+
+// InactiveUserAction describes what the inactive user job will do to a user on its next run.
+type InactiveUserAction struct {
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	LastActivityAt int64  `json:"last_activity_at"`
+	FlaggedAt      int64  `json:"flagged_at"`
+	Action         string `json:"action"`
+}
+
+func InactiveUserActionListToJson(l []*InactiveUserAction) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func InactiveUserActionListFromJson(data io.Reader) []*InactiveUserAction {
+	var o []*InactiveUserAction
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// IsExcluded returns true if the user is never flagged or deactivated by the inactive user job.
+func (s *InactiveUserSettings) IsExcluded(user *User) bool {
+	for _, username := range s.ExcludedUsernames {
+		if user.Username == username {
+			return true
+		}
+	}
+
+	for _, role := range s.ExcludedRoles {
+		if user.IsInRole(role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetInactiveUserAction returns the action to take for a user whose last activity and creation are known, given the
+// time at which they were flagged as inactive or 0 if they never were. An empty string means nothing should be done.
+func (s *InactiveUserSettings) GetInactiveUserAction(user *User, flaggedAt int64, now int64) string {
+	if user.DeleteAt != 0 || s.IsExcluded(user) {
+		return ""
+	}
+
+	cutoff := now - int64(*s.InactiveDays)*INACTIVE_USER_DAY_MILLISECONDS
+	if user.LastActivityAt >= cutoff || user.CreateAt >= cutoff {
+		return ""
+	}
+
+	// a flag set before the user's last activity belongs to an earlier period of inactivity
+	if flaggedAt == 0 || flaggedAt < user.LastActivityAt {
+		return INACTIVE_USER_ACTION_FLAG
+	}
+
+	if *s.EnableAutoDeactivation && now-flaggedAt >= int64(*s.DeactivationGracePeriodDays)*INACTIVE_USER_DAY_MILLISECONDS {
+		return INACTIVE_USER_ACTION_DEACTIVATE
+	}
+
+	return ""
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInactiveUserActionListJson(t *testing.T) {
+	actions := []*InactiveUserAction{{UserId: NewId(), Username: "user", Action: INACTIVE_USER_ACTION_FLAG}}
+
+	json := InactiveUserActionListToJson(actions)
+	assert.Equal(t, actions, InactiveUserActionListFromJson(strings.NewReader(json)))
+}
+
+func TestInactiveUserSettingsIsExcluded(t *testing.T) {
+	s := InactiveUserSettings{}
+	s.SetDefaults()
+	s.ExcludedUsernames = []string{"service"}
+
+	assert.True(t, s.IsExcluded(&User{Username: "service", Roles: SYSTEM_USER_ROLE_ID}))
+	assert.True(t, s.IsExcluded(&User{Username: "admin", Roles: SYSTEM_USER_ROLE_ID + " " + SYSTEM_ADMIN_ROLE_ID}))
+	assert.False(t, s.IsExcluded(&User{Username: "user", Roles: SYSTEM_USER_ROLE_ID}))
+}
+
+func TestInactiveUserSettingsGetInactiveUserAction(t *testing.T) {
+	day := int64(INACTIVE_USER_DAY_MILLISECONDS)
+	now := 1000 * day
+
+	s := InactiveUserSettings{}
+	s.SetDefaults()
+	*s.InactiveDays = 30
+	*s.DeactivationGracePeriodDays = 7
+
+	user := &User{Username: "user", Roles: SYSTEM_USER_ROLE_ID, CreateAt: now - 100*day, LastActivityAt: now - 10*day}
+	assert.Equal(t, "", s.GetInactiveUserAction(user, 0, now), "active user")
+
+	user.LastActivityAt = now - 40*day
+	assert.Equal(t, INACTIVE_USER_ACTION_FLAG, s.GetInactiveUserAction(user, 0, now))
+	assert.Equal(t, INACTIVE_USER_ACTION_FLAG, s.GetInactiveUserAction(user, now-50*day, now), "stale flag")
+	assert.Equal(t, "", s.GetInactiveUserAction(user, now-10*day, now), "auto deactivation disabled")
+
+	*s.EnableAutoDeactivation = true
+	assert.Equal(t, INACTIVE_USER_ACTION_DEACTIVATE, s.GetInactiveUserAction(user, now-10*day, now))
+	assert.Equal(t, "", s.GetInactiveUserAction(user, now-5*day, now), "within grace period")
+
+	user.LastActivityAt = 0
+	user.CreateAt = now - 10*day
+	assert.Equal(t, "", s.GetInactiveUserAction(user, 0, now), "recently created user")
+
+	user.CreateAt = now - 100*day
+	assert.Equal(t, INACTIVE_USER_ACTION_FLAG, s.GetInactiveUserAction(user, 0, now), "never active user")
+
+	user.DeleteAt = now
+	assert.Equal(t, "", s.GetInactiveUserAction(user, 0, now), "deactivated user")
+
+	user.DeleteAt = 0
+	s.ExcludedUsernames = []string{"user"}
+	assert.Equal(t, "", s.GetInactiveUserAction(user, 0, now), "excluded user")
+}
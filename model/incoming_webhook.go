@@ -28,6 +28,13 @@ type IncomingWebhook struct {
 	Username      string `json:"username"`
 	IconURL       string `json:"icon_url"`
 	ChannelLocked bool   `json:"channel_locked"`
+
+	// Rate limits that override the server's defaults for this hook. A value of 0 uses the server's default.
+	RateLimitPerMinute int `json:"rate_limit_per_minute"`
+	RateLimitBurst     int `json:"rate_limit_burst"`
+
+	// A template that turns payloads in other formats into posts. See RenderPayloadTemplate.
+	PayloadTemplate string `json:"payload_template"`
 }
 
 type IncomingWebhookRequest struct {
@@ -38,6 +45,26 @@ type IncomingWebhookRequest struct {
 	Props       StringInterface    `json:"props"`
 	Attachments []*SlackAttachment `json:"attachments"`
 	Type        string             `json:"type"`
+
+	// PostId is set to update or delete a post that the hook created before instead of creating a new one
+	PostId string `json:"post_id"`
+	Delete bool   `json:"delete"`
+}
+
+// IncomingWebhookResponse is returned to clients that accept JSON so that they can change the post later.
+type IncomingWebhookResponse struct {
+	PostId string `json:"post_id"`
+}
+
+func (o *IncomingWebhookResponse) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IncomingWebhookResponseFromJson(data io.Reader) *IncomingWebhookResponse {
+	var o *IncomingWebhookResponse
+	json.NewDecoder(data).Decode(&o)
+	return o
 }
 
 func (o *IncomingWebhook) ToJson() string {
@@ -105,9 +132,42 @@ func (o *IncomingWebhook) IsValid() *AppError {
 		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.icon_url.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if o.RateLimitPerMinute < 0 || o.RateLimitBurst < 0 {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.rate_limit.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.PayloadTemplate) > INCOMING_WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.payload_template.length.app_error", map[string]interface{}{"Max": INCOMING_WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH}, "", http.StatusBadRequest)
+	}
+
+	if _, err := parseIncomingWebhookPayloadTemplate(o.PayloadTemplate); err != nil {
+		return NewAppError("IncomingWebhook.IsValid", "model.incoming_hook.payload_template.parse.app_error", map[string]interface{}{"Error": err.Error()}, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
+// GetRateLimit returns the number of requests per minute and the burst that the hook is limited to, falling back to
+// the server's defaults for any that the hook doesn't set. A rate of 0 requests per minute means there's no limit.
+func (o *IncomingWebhook) GetRateLimit(defaultPerMinute int, defaultBurst int) (int, int) {
+	perMinute := defaultPerMinute
+	if o.RateLimitPerMinute > 0 {
+		perMinute = o.RateLimitPerMinute
+	}
+
+	burst := defaultBurst
+	if o.RateLimitBurst > 0 {
+		burst = o.RateLimitBurst
+	}
+
+	return perMinute, burst
+}
+
+// HasSameRateLimit returns true if both hooks override the server's rate limits in the same way.
+func (o *IncomingWebhook) HasSameRateLimit(other *IncomingWebhook) bool {
+	return o.RateLimitPerMinute == other.RateLimitPerMinute && o.RateLimitBurst == other.RateLimitBurst
+}
+
 func (o *IncomingWebhook) PreSave() {
 	if o.Id == "" {
 		o.Id = NewId()
@@ -0,0 +1,66 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+const INCOMING_WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH = 4000
+
+// Functions that payload templates can use in addition to the ones built into Go templates
+var incomingWebhookTemplateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		b, err := json.Marshal(value)
+		return string(b), err
+	},
+	"join": func(values []interface{}, separator string) string {
+		strs := make([]string, len(values))
+		for i, value := range values {
+			strs[i] = fmt.Sprint(value)
+		}
+		return strings.Join(strs, separator)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"default": func(fallback interface{}, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+func parseIncomingWebhookPayloadTemplate(text string) (*template.Template, error) {
+	return template.New("payload").Funcs(incomingWebhookTemplateFuncs).Parse(text)
+}
+
+// RenderPayloadTemplate turns a JSON payload that was sent to the hook into a webhook request by rendering the hook's
+// payload template with it. The template is a Go template that's given the decoded payload, and it renders the text
+// of the post, so services that can't be configured to send Mattermost's format can post to the hook directly.
+func (o *IncomingWebhook) RenderPayloadTemplate(data []byte) (*IncomingWebhookRequest, *AppError) {
+	tmpl, err := parseIncomingWebhookPayloadTemplate(o.PayloadTemplate)
+	if err != nil {
+		return nil, NewAppError("RenderPayloadTemplate", "model.incoming_hook.payload_template.app_error", nil, "id="+o.Id+", err="+err.Error(), http.StatusInternalServerError)
+	}
+
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, NewAppError("RenderPayloadTemplate", "model.incoming_hook.parse_data.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, payload); err != nil {
+		return nil, NewAppError("RenderPayloadTemplate", "model.incoming_hook.render_payload_template.app_error", map[string]interface{}{"Error": err.Error()}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return &IncomingWebhookRequest{Text: strings.TrimSpace(text.String())}, nil
+}
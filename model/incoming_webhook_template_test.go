@@ -0,0 +1,82 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomingWebhookRenderPayloadTemplate(t *testing.T) {
+	alertmanagerPayload := []byte(`{
+		"status": "firing",
+		"commonLabels": {"alertname": "HighLatency", "severity": "page"},
+		"alerts": [
+			{"labels": {"instance": "app-1"}, "startsAt": "2018-08-01T10:00:00Z"},
+			{"labels": {"instance": "app-2"}, "startsAt": "2018-08-01T10:01:00Z"}
+		],
+		"groupKey": 1234567890
+	}`)
+
+	for _, test := range []struct {
+		Name     string
+		Template string
+		Payload  []byte
+		Expected string
+		Error    string
+	}{
+		{
+			Name:     "fields",
+			Template: "**{{ upper .status }}**: {{ .commonLabels.alertname }}",
+			Payload:  alertmanagerPayload,
+			Expected: "**FIRING**: HighLatency",
+		},
+		{
+			Name:     "lists",
+			Template: "{{ range .alerts }}\n- {{ .labels.instance }} since {{ .startsAt }}{{ end }}",
+			Payload:  alertmanagerPayload,
+			Expected: "- app-1 since 2018-08-01T10:00:00Z\n- app-2 since 2018-08-01T10:01:00Z",
+		},
+		{
+			Name:     "numbers are kept as they were sent",
+			Template: "{{ .groupKey }}",
+			Payload:  alertmanagerPayload,
+			Expected: "1234567890",
+		},
+		{
+			Name:     "helpers",
+			Template: `{{ default "unknown" .commonLabels.team }} {{ json .commonLabels }} {{ join .tags ", " }}`,
+			Payload:  []byte(`{"commonLabels": {"severity": "page"}, "tags": ["a", 1, true]}`),
+			Expected: `unknown {"severity":"page"} a, 1, true`,
+		},
+		{
+			Name:     "invalid payload",
+			Template: "{{ .text }}",
+			Payload:  []byte(`{"text": `),
+			Error:    "model.incoming_hook.parse_data.app_error",
+		},
+		{
+			Name:     "failed rendering",
+			Template: `{{ join .text ", " }}`,
+			Payload:  []byte(`{"text": "not a list"}`),
+			Error:    "model.incoming_hook.render_payload_template.app_error",
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			hook := &IncomingWebhook{Id: NewId(), PayloadTemplate: test.Template}
+
+			request, err := hook.RenderPayloadTemplate(test.Payload)
+			if test.Error != "" {
+				require.NotNil(t, err)
+				assert.Equal(t, test.Error, err.Id)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.Expected, request.Text)
+		})
+	}
+}
@@ -109,6 +109,58 @@ func TestIncomingWebhookIsValid(t *testing.T) {
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
+
+	o.RateLimitPerMinute = -1
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.RateLimitPerMinute = 60
+	o.RateLimitBurst = -1
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.RateLimitBurst = 5
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.PayloadTemplate = "{{ .text"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.PayloadTemplate = strings.Repeat("1", INCOMING_WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.PayloadTemplate = "{{ .text }}"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIncomingWebhookGetRateLimit(t *testing.T) {
+	o := IncomingWebhook{}
+
+	perMinute, burst := o.GetRateLimit(0, 10)
+	if perMinute != 0 || burst != 10 {
+		t.Fatal("should use the server's defaults")
+	}
+
+	o.RateLimitPerMinute = 30
+	perMinute, burst = o.GetRateLimit(60, 10)
+	if perMinute != 30 || burst != 10 {
+		t.Fatal("should override the server's rate but not its burst")
+	}
+
+	o.RateLimitBurst = 2
+	perMinute, burst = o.GetRateLimit(60, 10)
+	if perMinute != 30 || burst != 2 {
+		t.Fatal("should override the server's rate and burst")
+	}
 }
 
 func TestIncomingWebhookPreSave(t *testing.T) {
@@ -206,3 +258,12 @@ func TestIncomingWebhookNullArrayItems(t *testing.T) {
 		t.Fatalf("expected one field")
 	}
 }
+
+func TestIncomingWebhookResponseJson(t *testing.T) {
+	o := IncomingWebhookResponse{PostId: NewId()}
+
+	ro := IncomingWebhookResponseFromJson(strings.NewReader(o.ToJson()))
+	if ro.PostId != o.PostId {
+		t.Fatal("post ids do not match")
+	}
+}
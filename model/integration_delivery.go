@@ -0,0 +1,104 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	INTEGRATION_DELIVERY_URL_MAX_LENGTH     = 1024
+	INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH = 4096
+	INTEGRATION_DELIVERY_ERROR_MAX_LENGTH   = 1024
+)
+
+// IntegrationDelivery records a request that was sent to or received from an integration so that admins can find
+// out why an integration isn't working. The request is truncated, and the secrets in it are removed. The status code
+// is the one that was returned to incoming webhooks or by the integration for outgoing requests, and it's 0 if an
+// outgoing request didn't get a response.
+type IntegrationDelivery struct {
+	Id              string `json:"id"`
+	IntegrationType string `json:"integration_type"`
+	IntegrationId   string `json:"integration_id"`
+	URL             string `json:"url"`
+	Request         string `json:"request"`
+	StatusCode      int    `json:"status_code"`
+	Latency         int64  `json:"latency"`
+	Error           string `json:"error,omitempty"`
+	CreateAt        int64  `json:"create_at"`
+}
+
+func (o *IntegrationDelivery) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IntegrationDeliveryListToJson(l []*IntegrationDelivery) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func IntegrationDeliveryListFromJson(data io.Reader) []*IntegrationDelivery {
+	var o []*IntegrationDelivery
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// IsValidIntegrationDeliveryType returns true if deliveries are recorded for a type of integration.
+func IsValidIntegrationDeliveryType(integrationType string) bool {
+	switch integrationType {
+	case INTEGRATION_TYPE_INCOMING_WEBHOOK, INTEGRATION_TYPE_OUTGOING_WEBHOOK, INTEGRATION_TYPE_COMMAND, INTEGRATION_TYPE_EVENT_SUBSCRIPTION:
+		return true
+	}
+
+	return false
+}
+
+// PreSave truncates the fields that can be longer than they're stored, since a delivery is still worth recording when
+// the request was too large to keep all of it.
+func (o *IntegrationDelivery) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	o.URL = truncateRunes(o.URL, INTEGRATION_DELIVERY_URL_MAX_LENGTH)
+	o.Request = truncateRunes(o.Request, INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH)
+	o.Error = truncateRunes(o.Error, INTEGRATION_DELIVERY_ERROR_MAX_LENGTH)
+}
+
+func (o *IntegrationDelivery) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("IntegrationDelivery.IsValid", "model.integration_delivery.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidIntegrationDeliveryType(o.IntegrationType) {
+		return NewAppError("IntegrationDelivery.IsValid", "model.integration_delivery.is_valid.integration_type.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.IntegrationId) != 26 {
+		return NewAppError("IntegrationDelivery.IsValid", "model.integration_delivery.is_valid.integration_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("IntegrationDelivery.IsValid", "model.integration_delivery.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// truncateRunes shortens a string to at most max runes without splitting any of them.
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationDeliveryPreSave(t *testing.T) {
+	delivery := &IntegrationDelivery{
+		IntegrationType: INTEGRATION_TYPE_COMMAND,
+		IntegrationId:   NewId(),
+		URL:             "https://example.com/" + strings.Repeat("a", INTEGRATION_DELIVERY_URL_MAX_LENGTH),
+		Request:         strings.Repeat("é", INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH+1),
+		Error:           strings.Repeat("e", INTEGRATION_DELIVERY_ERROR_MAX_LENGTH+1),
+	}
+	delivery.PreSave()
+
+	assert.Len(t, delivery.Id, 26)
+	assert.NotZero(t, delivery.CreateAt)
+	assert.Len(t, delivery.URL, INTEGRATION_DELIVERY_URL_MAX_LENGTH)
+	assert.Equal(t, strings.Repeat("é", INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH), delivery.Request, "should truncate without splitting characters")
+	assert.Len(t, delivery.Error, INTEGRATION_DELIVERY_ERROR_MAX_LENGTH)
+	require.Nil(t, delivery.IsValid())
+}
+
+func TestIntegrationDeliveryIsValid(t *testing.T) {
+	for _, test := range []struct {
+		Transform     func(*IntegrationDelivery)
+		ExpectedError string
+	}{
+		{func(o *IntegrationDelivery) {}, ""},
+		{func(o *IntegrationDelivery) { o.Id = "junk" }, "model.integration_delivery.is_valid.id.app_error"},
+		{func(o *IntegrationDelivery) { o.IntegrationType = INTEGRATION_TYPE_BOT }, "model.integration_delivery.is_valid.integration_type.app_error"},
+		{func(o *IntegrationDelivery) { o.IntegrationId = "" }, "model.integration_delivery.is_valid.integration_id.app_error"},
+		{func(o *IntegrationDelivery) { o.CreateAt = 0 }, "model.integration_delivery.is_valid.create_at.app_error"},
+	} {
+		delivery := &IntegrationDelivery{IntegrationType: INTEGRATION_TYPE_INCOMING_WEBHOOK, IntegrationId: NewId()}
+		delivery.PreSave()
+		test.Transform(delivery)
+
+		if err := delivery.IsValid(); test.ExpectedError == "" {
+			assert.Nil(t, err)
+		} else if assert.NotNil(t, err) {
+			assert.Equal(t, test.ExpectedError, err.Id)
+		}
+	}
+}
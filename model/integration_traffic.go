@@ -0,0 +1,121 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const (
+	INTEGRATION_TYPE_USER_ACCESS_TOKEN  = "user_access_token"
+	INTEGRATION_TYPE_OAUTH_APP          = "oauth_app"
+	INTEGRATION_TYPE_INCOMING_WEBHOOK   = "incoming_webhook"
+	INTEGRATION_TYPE_COMMAND_WEBHOOK    = "command_webhook"
+	INTEGRATION_TYPE_BOT                = "bot"
+	INTEGRATION_TYPE_OUTGOING_WEBHOOK   = "outgoing_webhook"
+	INTEGRATION_TYPE_COMMAND            = "command"
+	INTEGRATION_TYPE_EVENT_SUBSCRIPTION = "event_subscription"
+
+	INTEGRATION_UNKNOWN_USER_AGENT = "unknown"
+)
+
+// IntegrationSource identifies the integration that made a request. Id is the id of the user access token or webhook,
+// the name of the OAuth app, or the product name from the user agent of a bot.
+type IntegrationSource struct {
+	Type   string `json:"type"`
+	Id     string `json:"id"`
+	UserId string `json:"user_id,omitempty"`
+}
+
+// IntegrationTraffic is the traffic that the server has received from an integration.
+type IntegrationTraffic struct {
+	IntegrationSource
+	RequestCount     int64   `json:"request_count"`
+	ErrorCount       int64   `json:"error_count"`
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+	MaxLatencyMs     float64 `json:"max_latency_ms"`
+	LastRequestAt    int64   `json:"last_request_at"`
+}
+
+// IntegrationTrafficReport is the traffic that a server has received from each integration since the time given by
+// Since, busiest first.
+type IntegrationTrafficReport struct {
+	Since        int64                 `json:"since"`
+	Integrations []*IntegrationTraffic `json:"integrations"`
+}
+
+func (o *IntegrationTrafficReport) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IntegrationTrafficReportFromJson(data io.Reader) *IntegrationTrafficReport {
+	var o *IntegrationTrafficReport
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// Key uniquely identifies the source among all integrations.
+func (o *IntegrationSource) Key() string {
+	return o.Type + ":" + o.Id + ":" + o.UserId
+}
+
+// GetIntegrationSource works out which integration, if any, made a request from its session, path and user agent.
+// Requests made by people through the webapp or the mobile and desktop apps return nil.
+func GetIntegrationSource(session *Session, path string, userAgent string) *IntegrationSource {
+	if strings.HasPrefix(path, "/hooks/commands/") {
+		return &IntegrationSource{Type: INTEGRATION_TYPE_COMMAND_WEBHOOK, Id: strings.TrimPrefix(path, "/hooks/commands/")}
+	} else if strings.HasPrefix(path, "/hooks/") {
+		return &IntegrationSource{Type: INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: strings.TrimPrefix(path, "/hooks/")}
+	}
+
+	if session == nil || session.UserId == "" {
+		return nil
+	}
+
+	if session.Props[SESSION_PROP_TYPE] == SESSION_TYPE_USER_ACCESS_TOKEN {
+		return &IntegrationSource{Type: INTEGRATION_TYPE_USER_ACCESS_TOKEN, Id: session.Props[SESSION_PROP_USER_ACCESS_TOKEN_ID], UserId: session.UserId}
+	}
+
+	if session.IsOAuth {
+		return &IntegrationSource{Type: INTEGRATION_TYPE_OAUTH_APP, Id: session.Props[SESSION_PROP_PLATFORM], UserId: session.UserId}
+	}
+
+	if session.IsMobileApp() {
+		return nil
+	}
+
+	if product := getBotUserAgentProduct(userAgent); product != "" {
+		return &IntegrationSource{Type: INTEGRATION_TYPE_BOT, Id: product, UserId: session.UserId}
+	}
+
+	return nil
+}
+
+// getBotUserAgentProduct returns the lowercase product name from a user agent that doesn't belong to a browser, such as
+// "python-requests" from "python-requests/2.19.1", or an empty string for browsers.
+func getBotUserAgentProduct(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" {
+		return INTEGRATION_UNKNOWN_USER_AGENT
+	}
+
+	// Every browser, including the desktop app, claims to be Mozilla
+	if strings.HasPrefix(userAgent, "Mozilla/") {
+		return ""
+	}
+
+	product := strings.ToLower(strings.Fields(userAgent)[0])
+	if index := strings.Index(product, "/"); index != -1 {
+		product = product[:index]
+	}
+
+	if product == "" {
+		return INTEGRATION_UNKNOWN_USER_AGENT
+	}
+
+	return product
+}
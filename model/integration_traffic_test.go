@@ -0,0 +1,55 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIntegrationSource(t *testing.T) {
+	userId := NewId()
+	browser := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/67.0.3396.99 Safari/537.36"
+
+	hookId := NewId()
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_INCOMING_WEBHOOK, Id: hookId}, GetIntegrationSource(&Session{}, "/hooks/"+hookId, "curl/7.58.0"))
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_COMMAND_WEBHOOK, Id: hookId}, GetIntegrationSource(nil, "/hooks/commands/"+hookId, ""))
+
+	tokenId := NewId()
+	session := &Session{UserId: userId, Props: StringMap{SESSION_PROP_TYPE: SESSION_TYPE_USER_ACCESS_TOKEN, SESSION_PROP_USER_ACCESS_TOKEN_ID: tokenId}}
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_USER_ACCESS_TOKEN, Id: tokenId, UserId: userId}, GetIntegrationSource(session, "/api/v4/posts", browser))
+
+	session = &Session{UserId: userId, IsOAuth: true, Props: StringMap{SESSION_PROP_PLATFORM: "Jira"}}
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_OAUTH_APP, Id: "Jira", UserId: userId}, GetIntegrationSource(session, "/api/v4/posts", browser))
+
+	session = &Session{UserId: userId, Props: StringMap{}}
+	assert.Nil(t, GetIntegrationSource(session, "/api/v4/posts", browser))
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_BOT, Id: "python-requests", UserId: userId}, GetIntegrationSource(session, "/api/v4/posts", "python-requests/2.19.1"))
+	assert.Equal(t, &IntegrationSource{Type: INTEGRATION_TYPE_BOT, Id: INTEGRATION_UNKNOWN_USER_AGENT, UserId: userId}, GetIntegrationSource(session, "/api/v4/posts", ""))
+
+	session.DeviceId = "android:" + NewId()
+	assert.Nil(t, GetIntegrationSource(session, "/api/v4/posts", "okhttp/3.10.0"), "should ignore the mobile apps")
+
+	assert.Nil(t, GetIntegrationSource(&Session{}, "/api/v4/users/login", "curl/7.58.0"), "should ignore requests without a session")
+}
+
+func TestIntegrationTrafficReportJson(t *testing.T) {
+	report := &IntegrationTrafficReport{
+		Since: GetMillis(),
+		Integrations: []*IntegrationTraffic{{
+			IntegrationSource: IntegrationSource{Type: INTEGRATION_TYPE_BOT, Id: "curl", UserId: NewId()},
+			RequestCount:      10,
+			ErrorCount:        1,
+			AverageLatencyMs:  12.5,
+			MaxLatencyMs:      40,
+			LastRequestAt:     GetMillis(),
+		}},
+	}
+
+	json := report.ToJson()
+	assert.Contains(t, json, `"type":"bot"`)
+	assert.Equal(t, report, IntegrationTrafficReportFromJson(strings.NewReader(json)))
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	DIALOG_ELEMENT_TYPE_TEXT        = "text"
+	DIALOG_ELEMENT_TYPE_TEXTAREA    = "textarea"
+	DIALOG_ELEMENT_TYPE_SELECT      = "select"
+	DIALOG_ELEMENT_TYPE_MULTISELECT = "multiselect"
+	DIALOG_ELEMENT_TYPE_DATE        = "date"
+	DIALOG_ELEMENT_TYPE_DATETIME    = "datetime"
+	DIALOG_ELEMENT_TYPE_HEADER      = "header"
+
+	DIALOG_TEXT_SUBTYPE_TEXT     = "text"
+	DIALOG_TEXT_SUBTYPE_EMAIL    = "email"
+	DIALOG_TEXT_SUBTYPE_NUMBER   = "number"
+	DIALOG_TEXT_SUBTYPE_PASSWORD = "password"
+	DIALOG_TEXT_SUBTYPE_TEL      = "tel"
+	DIALOG_TEXT_SUBTYPE_URL      = "url"
+
+	DIALOG_DATA_SOURCE_USERS    = "users"
+	DIALOG_DATA_SOURCE_CHANNELS = "channels"
+
+	DIALOG_DATE_FORMAT = "2006-01-02"
+
+	DIALOG_MAX_ELEMENTS        = 20
+	DIALOG_MAX_OPTIONS         = 100
+	DIALOG_NAME_MAX_LENGTH     = 300
+	DIALOG_TEXT_MAX_LENGTH     = 150
+	DIALOG_TEXTAREA_MAX_LENGTH = 3000
+)
+
+// Dialog is a form that an integration asks a client to show to a user. The values that the user enters are sent back
+// to the integration as a submission, which is checked against the dialog's elements first.
+type Dialog struct {
+	CallbackId       string          `json:"callback_id"`
+	Title            string          `json:"title"`
+	IntroductionText string          `json:"introduction_text"`
+	IconURL          string          `json:"icon_url"`
+	Elements         []DialogElement `json:"elements"`
+	SubmitLabel      string          `json:"submit_label"`
+	State            string          `json:"state"`
+}
+
+// DialogElement is a field of a dialog. Section headers only group the fields after them, so they have no value.
+// Select elements list their options or take them from a data source, and multi-selects accept any number of them.
+// Dates are submitted as YYYY-MM-DD and date-times in RFC 3339 format.
+type DialogElement struct {
+	DisplayName string               `json:"display_name"`
+	Name        string               `json:"name"`
+	Type        string               `json:"type"`
+	SubType     string               `json:"subtype"`
+	Default     string               `json:"default"`
+	Placeholder string               `json:"placeholder"`
+	HelpText    string               `json:"help_text"`
+	Optional    bool                 `json:"optional"`
+	MinLength   int                  `json:"min_length"`
+	MaxLength   int                  `json:"max_length"`
+	DataSource  string               `json:"data_source"`
+	Options     []*PostActionOptions `json:"options"`
+}
+
+// OpenDialogRequest is sent to a user's clients to show them a dialog. The signature covers the dialog, the URL that
+// it's submitted to and the user, so that the server can trust them when they're sent back with a submission.
+type OpenDialogRequest struct {
+	URL       string  `json:"url"`
+	Dialog    *Dialog `json:"dialog"`
+	Signature string  `json:"signature"`
+}
+
+// SubmitDialogRequest is sent to an integration when a user submits or cancels one of its dialogs. Clients send the
+// signed dialog back along with the submission, and it's removed before the request is passed on to the integration.
+type SubmitDialogRequest struct {
+	Type       string                 `json:"type"`
+	URL        string                 `json:"url,omitempty"`
+	CallbackId string                 `json:"callback_id"`
+	State      string                 `json:"state"`
+	UserId     string                 `json:"user_id"`
+	ChannelId  string                 `json:"channel_id"`
+	TeamId     string                 `json:"team_id"`
+	Submission map[string]interface{} `json:"submission"`
+	Cancelled  bool                   `json:"cancelled"`
+	Dialog     *Dialog                `json:"dialog,omitempty"`
+	Signature  string                 `json:"signature,omitempty"`
+}
+
+// SubmitDialogResponse carries the errors for each field of a submission that was rejected.
+type SubmitDialogResponse struct {
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func (o *Dialog) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func DialogFromJson(data io.Reader) *Dialog {
+	var o *Dialog
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *OpenDialogRequest) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func OpenDialogRequestFromJson(data io.Reader) *OpenDialogRequest {
+	var o *OpenDialogRequest
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *SubmitDialogRequest) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func SubmitDialogRequestFromJson(data io.Reader) *SubmitDialogRequest {
+	var o *SubmitDialogRequest
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *SubmitDialogResponse) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func SubmitDialogResponseFromJson(data io.Reader) *SubmitDialogResponse {
+	var o *SubmitDialogResponse
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *Dialog) IsValid() *AppError {
+	if o.Title == "" {
+		return NewAppError("Dialog.IsValid", "model.dialog.is_valid.title.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Elements) > DIALOG_MAX_ELEMENTS {
+		return NewAppError("Dialog.IsValid", "model.dialog.is_valid.elements.app_error", map[string]interface{}{"Max": DIALOG_MAX_ELEMENTS}, "", http.StatusBadRequest)
+	}
+
+	names := make(map[string]bool)
+	for i := range o.Elements {
+		element := &o.Elements[i]
+		if err := element.IsValid(); err != nil {
+			return err
+		}
+
+		if element.Type == DIALOG_ELEMENT_TYPE_HEADER {
+			continue
+		}
+
+		if names[element.Name] {
+			return NewAppError("Dialog.IsValid", "model.dialog.is_valid.duplicate_name.app_error", map[string]interface{}{"Name": element.Name}, "", http.StatusBadRequest)
+		}
+		names[element.Name] = true
+	}
+
+	return nil
+}
+
+func (o *DialogElement) IsValid() *AppError {
+	if o.DisplayName == "" {
+		return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.display_name.app_error", nil, "name="+o.Name, http.StatusBadRequest)
+	}
+
+	if o.Type == DIALOG_ELEMENT_TYPE_HEADER {
+		return nil
+	}
+
+	if o.Name == "" || len(o.Name) > DIALOG_NAME_MAX_LENGTH {
+		return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.name.app_error", nil, "name="+o.Name, http.StatusBadRequest)
+	}
+
+	if o.MinLength < 0 || o.MaxLength < 0 || (o.MaxLength > 0 && o.MinLength > o.MaxLength) {
+		return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.length.app_error", nil, "name="+o.Name, http.StatusBadRequest)
+	}
+
+	switch o.Type {
+	case DIALOG_ELEMENT_TYPE_TEXT:
+		switch o.SubType {
+		case "", DIALOG_TEXT_SUBTYPE_TEXT, DIALOG_TEXT_SUBTYPE_EMAIL, DIALOG_TEXT_SUBTYPE_NUMBER, DIALOG_TEXT_SUBTYPE_PASSWORD, DIALOG_TEXT_SUBTYPE_TEL, DIALOG_TEXT_SUBTYPE_URL:
+		default:
+			return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.subtype.app_error", nil, "name="+o.Name+", subtype="+o.SubType, http.StatusBadRequest)
+		}
+	case DIALOG_ELEMENT_TYPE_TEXTAREA:
+	case DIALOG_ELEMENT_TYPE_SELECT, DIALOG_ELEMENT_TYPE_MULTISELECT:
+		if o.DataSource != "" {
+			if o.DataSource != DIALOG_DATA_SOURCE_USERS && o.DataSource != DIALOG_DATA_SOURCE_CHANNELS {
+				return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.data_source.app_error", nil, "name="+o.Name+", data_source="+o.DataSource, http.StatusBadRequest)
+			}
+		} else if len(o.Options) == 0 || len(o.Options) > DIALOG_MAX_OPTIONS {
+			return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.options.app_error", map[string]interface{}{"Max": DIALOG_MAX_OPTIONS}, "name="+o.Name, http.StatusBadRequest)
+		}
+	case DIALOG_ELEMENT_TYPE_DATE, DIALOG_ELEMENT_TYPE_DATETIME:
+		if o.Default != "" && !o.isValidTime(o.Default) {
+			return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.default.app_error", nil, "name="+o.Name, http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("DialogElement.IsValid", "model.dialog_element.is_valid.type.app_error", nil, "name="+o.Name+", type="+o.Type, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// ValidateSubmission checks the values that a user submitted against the dialog's elements, returning the errors for
+// each field that was rejected. Values for fields that aren't in the dialog are rejected too.
+func (o *Dialog) ValidateSubmission(submission map[string]interface{}) map[string]*AppError {
+	errors := make(map[string]*AppError)
+
+	elements := make(map[string]*DialogElement)
+	for i := range o.Elements {
+		element := &o.Elements[i]
+		if element.Type == DIALOG_ELEMENT_TYPE_HEADER {
+			continue
+		}
+
+		elements[element.Name] = element
+		if err := element.ValidateValue(submission[element.Name]); err != nil {
+			errors[element.Name] = err
+		}
+	}
+
+	for name := range submission {
+		if _, ok := elements[name]; !ok {
+			errors[name] = NewAppError("Dialog.ValidateSubmission", "model.dialog.submission.unknown_field.app_error", nil, "name="+name, http.StatusBadRequest)
+		}
+	}
+
+	return errors
+}
+
+// ValidateValue checks a submitted value for the element. Missing values are only accepted for optional elements.
+func (o *DialogElement) ValidateValue(value interface{}) *AppError {
+	if o.Type == DIALOG_ELEMENT_TYPE_MULTISELECT {
+		return o.validateValues(value)
+	}
+
+	if value == nil {
+		return o.validateEmpty()
+	}
+
+	// Numbers are submitted as JSON numbers by some clients and as strings by others
+	if f, ok := value.(float64); ok && o.Type == DIALOG_ELEMENT_TYPE_TEXT && o.SubType == DIALOG_TEXT_SUBTYPE_NUMBER {
+		value = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return o.newValueError("model.dialog_element.submission.type.app_error")
+	}
+
+	if s == "" {
+		return o.validateEmpty()
+	}
+
+	switch o.Type {
+	case DIALOG_ELEMENT_TYPE_TEXT, DIALOG_ELEMENT_TYPE_TEXTAREA:
+		return o.validateText(s)
+	case DIALOG_ELEMENT_TYPE_SELECT:
+		if !o.hasOption(s) {
+			return o.newValueError("model.dialog_element.submission.option.app_error")
+		}
+	case DIALOG_ELEMENT_TYPE_DATE, DIALOG_ELEMENT_TYPE_DATETIME:
+		if !o.isValidTime(s) {
+			return o.newValueError("model.dialog_element.submission." + o.Type + ".app_error")
+		}
+	}
+
+	return nil
+}
+
+func (o *DialogElement) validateValues(value interface{}) *AppError {
+	var values []string
+	switch v := value.(type) {
+	case nil:
+	case []string:
+		values = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return o.newValueError("model.dialog_element.submission.type.app_error")
+			}
+			values = append(values, s)
+		}
+	default:
+		return o.newValueError("model.dialog_element.submission.type.app_error")
+	}
+
+	if len(values) == 0 {
+		return o.validateEmpty()
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range values {
+		if seen[s] || !o.hasOption(s) {
+			return o.newValueError("model.dialog_element.submission.option.app_error")
+		}
+		seen[s] = true
+	}
+
+	return nil
+}
+
+func (o *DialogElement) validateEmpty() *AppError {
+	if o.Optional {
+		return nil
+	}
+
+	return o.newValueError("model.dialog_element.submission.required.app_error")
+}
+
+func (o *DialogElement) validateText(s string) *AppError {
+	maxLength := o.MaxLength
+	if maxLength == 0 {
+		maxLength = DIALOG_TEXT_MAX_LENGTH
+		if o.Type == DIALOG_ELEMENT_TYPE_TEXTAREA {
+			maxLength = DIALOG_TEXTAREA_MAX_LENGTH
+		}
+	}
+
+	if length := utf8.RuneCountInString(s); length < o.MinLength || length > maxLength {
+		return NewAppError("DialogElement.ValidateValue", "model.dialog_element.submission.length.app_error", map[string]interface{}{"Name": o.DisplayName, "Min": o.MinLength, "Max": maxLength}, "name="+o.Name, http.StatusBadRequest)
+	}
+
+	if o.Type != DIALOG_ELEMENT_TYPE_TEXT {
+		return nil
+	}
+
+	switch o.SubType {
+	case DIALOG_TEXT_SUBTYPE_EMAIL:
+		if !IsValidEmail(s) {
+			return o.newValueError("model.dialog_element.submission.email.app_error")
+		}
+	case DIALOG_TEXT_SUBTYPE_NUMBER:
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return o.newValueError("model.dialog_element.submission.number.app_error")
+		}
+	case DIALOG_TEXT_SUBTYPE_URL:
+		if !IsValidHttpUrl(s) {
+			return o.newValueError("model.dialog_element.submission.url.app_error")
+		}
+	}
+
+	return nil
+}
+
+// hasOption returns true if the value is one of the element's options. Values of elements whose options come from a
+// data source are ids, which are checked when they're used instead.
+func (o *DialogElement) hasOption(value string) bool {
+	if o.DataSource != "" {
+		return IsValidId(value)
+	}
+
+	for _, option := range o.Options {
+		if option != nil && option.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *DialogElement) isValidTime(value string) bool {
+	var err error
+	if o.Type == DIALOG_ELEMENT_TYPE_DATE {
+		_, err = time.Parse(DIALOG_DATE_FORMAT, value)
+	} else {
+		_, err = time.Parse(time.RFC3339, value)
+	}
+
+	return err == nil
+}
+
+func (o *DialogElement) newValueError(id string) *AppError {
+	return NewAppError("DialogElement.ValidateValue", id, map[string]interface{}{"Name": o.DisplayName}, "name="+o.Name, http.StatusBadRequest)
+}
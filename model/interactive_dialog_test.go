@@ -0,0 +1,112 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestDialog() *Dialog {
+	return &Dialog{
+		CallbackId: "callback",
+		Title:      "Title",
+		Elements: []DialogElement{
+			{DisplayName: "Details", Type: DIALOG_ELEMENT_TYPE_HEADER},
+			{DisplayName: "Name", Name: "name", Type: DIALOG_ELEMENT_TYPE_TEXT, MinLength: 2, MaxLength: 10},
+			{DisplayName: "Email", Name: "email", Type: DIALOG_ELEMENT_TYPE_TEXT, SubType: DIALOG_TEXT_SUBTYPE_EMAIL, Optional: true},
+			{DisplayName: "Count", Name: "count", Type: DIALOG_ELEMENT_TYPE_TEXT, SubType: DIALOG_TEXT_SUBTYPE_NUMBER, Optional: true},
+			{DisplayName: "Schedule", Type: DIALOG_ELEMENT_TYPE_HEADER},
+			{DisplayName: "Day", Name: "day", Type: DIALOG_ELEMENT_TYPE_DATE},
+			{DisplayName: "Reminder", Name: "reminder", Type: DIALOG_ELEMENT_TYPE_DATETIME, Optional: true},
+			{DisplayName: "Labels", Name: "labels", Type: DIALOG_ELEMENT_TYPE_MULTISELECT, Options: []*PostActionOptions{
+				{Text: "Bug", Value: "bug"},
+				{Text: "Feature", Value: "feature"},
+			}},
+			{DisplayName: "Assignee", Name: "assignee", Type: DIALOG_ELEMENT_TYPE_SELECT, DataSource: DIALOG_DATA_SOURCE_USERS, Optional: true},
+		},
+	}
+}
+
+func TestDialogIsValid(t *testing.T) {
+	dialog := getTestDialog()
+	require.Nil(t, dialog.IsValid())
+
+	dialog.Elements = append(dialog.Elements, DialogElement{DisplayName: "Name", Name: "name", Type: DIALOG_ELEMENT_TYPE_TEXTAREA})
+	require.NotNil(t, dialog.IsValid(), "should reject duplicate names")
+
+	for name, element := range map[string]DialogElement{
+		"missing display name": {Name: "a", Type: DIALOG_ELEMENT_TYPE_TEXT},
+		"missing name":         {DisplayName: "A", Type: DIALOG_ELEMENT_TYPE_TEXT},
+		"bad type":             {DisplayName: "A", Name: "a", Type: "radio"},
+		"bad subtype":          {DisplayName: "A", Name: "a", Type: DIALOG_ELEMENT_TYPE_TEXT, SubType: "color"},
+		"bad lengths":          {DisplayName: "A", Name: "a", Type: DIALOG_ELEMENT_TYPE_TEXT, MinLength: 5, MaxLength: 2},
+		"no options":           {DisplayName: "A", Name: "a", Type: DIALOG_ELEMENT_TYPE_MULTISELECT},
+		"bad data source":      {DisplayName: "A", Name: "a", Type: DIALOG_ELEMENT_TYPE_SELECT, DataSource: "teams"},
+		"bad default date":     {DisplayName: "A", Name: "a", Type: DIALOG_ELEMENT_TYPE_DATE, Default: "tomorrow"},
+	} {
+		assert.NotNil(t, element.IsValid(), name)
+	}
+
+	dialog = getTestDialog()
+	dialog.Title = ""
+	require.NotNil(t, dialog.IsValid())
+}
+
+func TestDialogValidateSubmission(t *testing.T) {
+	dialog := getTestDialog()
+
+	errors := dialog.ValidateSubmission(map[string]interface{}{
+		"name":     "Jane",
+		"count":    float64(3),
+		"day":      "2018-10-01",
+		"reminder": "2018-10-01T09:30:00Z",
+		"labels":   []interface{}{"bug", "feature"},
+		"assignee": NewId(),
+	})
+	assert.Empty(t, errors)
+
+	errors = dialog.ValidateSubmission(map[string]interface{}{
+		"name":     "J",
+		"email":    "jane",
+		"count":    "three",
+		"day":      "2018-10-01T09:30:00Z",
+		"reminder": "2018-10-01",
+		"labels":   []interface{}{"bug", "bug"},
+		"assignee": "jane",
+		"unknown":  "value",
+	})
+	for _, name := range []string{"name", "email", "count", "day", "reminder", "labels", "assignee", "unknown"} {
+		assert.NotNil(t, errors[name], name)
+	}
+
+	errors = dialog.ValidateSubmission(map[string]interface{}{
+		"name":   strings.Repeat("a", 11),
+		"labels": []interface{}{"question"},
+	})
+	assert.Equal(t, "model.dialog_element.submission.length.app_error", errors["name"].Id)
+	assert.Equal(t, "model.dialog_element.submission.required.app_error", errors["day"].Id)
+	assert.Equal(t, "model.dialog_element.submission.option.app_error", errors["labels"].Id)
+	assert.Nil(t, errors["email"], "should accept missing optional values")
+
+	errors = dialog.ValidateSubmission(map[string]interface{}{"name": "Jane", "day": "2018-10-01", "labels": []interface{}{}})
+	assert.Equal(t, "model.dialog_element.submission.required.app_error", errors["labels"].Id)
+
+	errors = dialog.ValidateSubmission(map[string]interface{}{"name": "Jane", "day": "2018-10-01", "labels": "bug"})
+	assert.Equal(t, "model.dialog_element.submission.type.app_error", errors["labels"].Id)
+}
+
+func TestDialogJson(t *testing.T) {
+	dialog := getTestDialog()
+	assert.Equal(t, dialog, DialogFromJson(strings.NewReader(dialog.ToJson())))
+
+	openRequest := &OpenDialogRequest{URL: "https://example.com/dialog", Dialog: dialog, Signature: "signature"}
+	assert.Equal(t, openRequest, OpenDialogRequestFromJson(strings.NewReader(openRequest.ToJson())))
+
+	request := &SubmitDialogRequest{CallbackId: "callback", Submission: map[string]interface{}{"labels": []interface{}{"bug"}}}
+	assert.Equal(t, request, SubmitDialogRequestFromJson(strings.NewReader(request.ToJson())))
+}
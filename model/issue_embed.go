@@ -0,0 +1,40 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	ISSUE_UNFURL_PROVIDER_JIRA   = "jira"
+	ISSUE_UNFURL_PROVIDER_GITHUB = "github"
+
+	ISSUE_EMBED_TYPE_ISSUE        = "issue"
+	ISSUE_EMBED_TYPE_PULL_REQUEST = "pull_request"
+)
+
+// IssueEmbed is the structured preview of a link to an issue or pull request on an issue tracker.
+type IssueEmbed struct {
+	Provider string   `json:"provider"`
+	Type     string   `json:"type"`
+	URL      string   `json:"url"`
+	Key      string   `json:"key"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Assignee string   `json:"assignee,omitempty"`
+	Labels   []string `json:"labels"`
+}
+
+func (o *IssueEmbed) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func IssueEmbedFromJson(data io.Reader) *IssueEmbed {
+	var o *IssueEmbed
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
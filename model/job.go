@@ -17,6 +17,13 @@ const (
 	JOB_TYPE_ELASTICSEARCH_POST_AGGREGATION = "elasticsearch_post_aggregation"
 	JOB_TYPE_LDAP_SYNC                      = "ldap_sync"
 	JOB_TYPE_MIGRATIONS                     = "migrations"
+	JOB_TYPE_EMAIL_DIGEST                   = "email_digest"
+	JOB_TYPE_INACTIVE_USERS                 = "inactive_users"
+	JOB_TYPE_TEAM_DELETION                  = "team_deletion"
+	JOB_TYPE_LINK_METADATA_CLEANUP          = "link_metadata_cleanup"
+	JOB_TYPE_REMINDERS                      = "reminders"
+	JOB_TYPE_CHANNEL_SNOOZES                = "channel_snoozes"
+	JOB_TYPE_PROFILE_CAPTURE                = "profile_capture"
 
 	JOB_STATUS_PENDING          = "pending"
 	JOB_STATUS_IN_PROGRESS      = "in_progress"
@@ -54,6 +61,13 @@ func (j *Job) IsValid() *AppError {
 	case JOB_TYPE_LDAP_SYNC:
 	case JOB_TYPE_MESSAGE_EXPORT:
 	case JOB_TYPE_MIGRATIONS:
+	case JOB_TYPE_EMAIL_DIGEST:
+	case JOB_TYPE_INACTIVE_USERS:
+	case JOB_TYPE_TEAM_DELETION:
+	case JOB_TYPE_LINK_METADATA_CLEANUP:
+	case JOB_TYPE_REMINDERS:
+	case JOB_TYPE_CHANNEL_SNOOZES:
+	case JOB_TYPE_PROFILE_CAPTURE:
 	default:
 		return NewAppError("Job.IsValid", "model.job.is_valid.type.app_error", nil, "id="+j.Id, http.StatusBadRequest)
 	}
@@ -0,0 +1,85 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	LINK_METADATA_URL_MAX_LENGTH    = 2048
+	LINK_METADATA_DOMAIN_MAX_LENGTH = 128
+	LINK_METADATA_DATA_MAX_LENGTH   = 65535
+)
+
+// LinkMetadata is the stored metadata for a link, used to render its preview without fetching the link each time
+// it's posted.
+type LinkMetadata struct {
+	Hash         int64
+	URL          string
+	Domain       string
+	Data         string
+	CreateAt     int64
+	LastAccessAt int64
+	AccessCount  int64
+}
+
+// GenerateLinkMetadataHash returns the key that the metadata for a URL is stored under.
+func GenerateLinkMetadataHash(requestURL string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(requestURL))
+
+	return int64(hash.Sum64())
+}
+
+// LinkMetadataDomain returns the domain that the metadata for a URL is stored under, or an empty string if the URL
+// can't be parsed.
+func LinkMetadataDomain(requestURL string) string {
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+func (o *LinkMetadata) PreSave() {
+	o.Hash = GenerateLinkMetadataHash(o.URL)
+	o.Domain = LinkMetadataDomain(o.URL)
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	if o.LastAccessAt == 0 {
+		o.LastAccessAt = o.CreateAt
+	}
+}
+
+func (o *LinkMetadata) IsValid() *AppError {
+	if len(o.URL) == 0 || len(o.URL) > LINK_METADATA_URL_MAX_LENGTH {
+		return NewAppError("LinkMetadata.IsValid", "model.link_metadata.is_valid.url.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.Hash != GenerateLinkMetadataHash(o.URL) {
+		return NewAppError("LinkMetadata.IsValid", "model.link_metadata.is_valid.hash.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	if len(o.Domain) > LINK_METADATA_DOMAIN_MAX_LENGTH {
+		return NewAppError("LinkMetadata.IsValid", "model.link_metadata.is_valid.domain.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	if len(o.Data) > LINK_METADATA_DATA_MAX_LENGTH {
+		return NewAppError("LinkMetadata.IsValid", "model.link_metadata.is_valid.data.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("LinkMetadata.IsValid", "model.link_metadata.is_valid.create_at.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	return nil
+}
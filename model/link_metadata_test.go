@@ -0,0 +1,60 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkMetadataDomain(t *testing.T) {
+	assert.Equal(t, "example.com", LinkMetadataDomain("https://Example.COM:8443/page?query=1"))
+	assert.Equal(t, "", LinkMetadataDomain("/relative/path"))
+	assert.Equal(t, "", LinkMetadataDomain("%zz"))
+}
+
+func TestLinkMetadataPreSave(t *testing.T) {
+	metadata := LinkMetadata{URL: "https://www.example.com/article"}
+	metadata.PreSave()
+
+	assert.Equal(t, GenerateLinkMetadataHash("https://www.example.com/article"), metadata.Hash)
+	assert.NotEqual(t, GenerateLinkMetadataHash("https://www.example.com/other"), metadata.Hash)
+	assert.Equal(t, "www.example.com", metadata.Domain)
+	assert.NotZero(t, metadata.CreateAt)
+	assert.Equal(t, metadata.CreateAt, metadata.LastAccessAt)
+
+	metadata = LinkMetadata{URL: "https://www.example.com/article", CreateAt: 2000, LastAccessAt: 1000}
+	metadata.PreSave()
+
+	assert.Equal(t, int64(2000), metadata.CreateAt)
+	assert.Equal(t, int64(1000), metadata.LastAccessAt, "should keep the last access time of refreshed links")
+}
+
+func TestLinkMetadataIsValid(t *testing.T) {
+	metadata := LinkMetadata{URL: "https://www.example.com/article", Data: "{}"}
+	metadata.PreSave()
+	assert.Nil(t, metadata.IsValid())
+
+	metadata.Hash++
+	assert.NotNil(t, metadata.IsValid())
+	metadata.PreSave()
+
+	metadata.Data = strings.Repeat("a", LINK_METADATA_DATA_MAX_LENGTH+1)
+	assert.NotNil(t, metadata.IsValid())
+	metadata.Data = "{}"
+
+	metadata.CreateAt = 0
+	assert.NotNil(t, metadata.IsValid())
+	metadata.PreSave()
+
+	metadata = LinkMetadata{URL: "https://www.example.com/" + strings.Repeat("a", LINK_METADATA_URL_MAX_LENGTH)}
+	metadata.PreSave()
+	assert.NotNil(t, metadata.IsValid())
+
+	metadata = LinkMetadata{}
+	metadata.PreSave()
+	assert.NotNil(t, metadata.IsValid())
+}
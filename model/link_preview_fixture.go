@@ -0,0 +1,57 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// LinkPreviewFixture is a canned response to a request for a link preview. When link previews are in offline mode,
+// responses are served from fixtures instead of being fetched from the internet. The body is either given inline or
+// read from a file, relative to the file that the fixture was loaded from, for binary content such as images.
+type LinkPreviewFixture struct {
+	URL         string `json:"url"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+	BodyFile    string `json:"body_file"`
+}
+
+func LinkPreviewFixturesToJson(fixtures []*LinkPreviewFixture) string {
+	b, _ := json.Marshal(fixtures)
+	return string(b)
+}
+
+func LinkPreviewFixturesFromJson(data io.Reader) ([]*LinkPreviewFixture, error) {
+	var fixtures []*LinkPreviewFixture
+	err := json.NewDecoder(data).Decode(&fixtures)
+	return fixtures, err
+}
+
+func (o *LinkPreviewFixture) IsValid() *AppError {
+	if !IsValidHttpUrl(o.URL) {
+		return NewAppError("LinkPreviewFixture.IsValid", "model.link_preview_fixture.is_valid.url.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	if o.StatusCode != 0 && (o.StatusCode < 100 || o.StatusCode > 599) {
+		return NewAppError("LinkPreviewFixture.IsValid", "model.link_preview_fixture.is_valid.status_code.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	if o.Body != "" && o.BodyFile != "" {
+		return NewAppError("LinkPreviewFixture.IsValid", "model.link_preview_fixture.is_valid.body.app_error", nil, "url="+o.URL, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// GetStatusCode returns the status code to respond with, which is 200 unless another is given.
+func (o *LinkPreviewFixture) GetStatusCode() int {
+	if o.StatusCode == 0 {
+		return http.StatusOK
+	}
+
+	return o.StatusCode
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkPreviewFixtureIsValid(t *testing.T) {
+	fixture := &LinkPreviewFixture{URL: "https://www.example.com", Body: "body"}
+	require.Nil(t, fixture.IsValid())
+	assert.Equal(t, http.StatusOK, fixture.GetStatusCode())
+
+	fixture.StatusCode = http.StatusNotFound
+	require.Nil(t, fixture.IsValid())
+	assert.Equal(t, http.StatusNotFound, fixture.GetStatusCode())
+
+	fixture.StatusCode = 1000
+	require.NotNil(t, fixture.IsValid())
+	fixture.StatusCode = 0
+
+	fixture.BodyFile = "body.html"
+	require.NotNil(t, fixture.IsValid())
+	fixture.BodyFile = ""
+
+	fixture.URL = "www.example.com"
+	require.NotNil(t, fixture.IsValid())
+}
+
+func TestLinkPreviewFixturesJson(t *testing.T) {
+	fixtures := []*LinkPreviewFixture{{URL: "https://www.example.com", ContentType: "text/html", Body: "<html></html>"}}
+
+	decoded, err := LinkPreviewFixturesFromJson(strings.NewReader(LinkPreviewFixturesToJson(fixtures)))
+	require.Nil(t, err)
+	assert.Equal(t, fixtures, decoded)
+
+	_, err = LinkPreviewFixturesFromJson(strings.NewReader("{"))
+	assert.NotNil(t, err)
+}
@@ -35,7 +35,7 @@ type PluginSetting struct {
 	// "bool" will result in a boolean true or false setting.
 	//
 	// "dropdown" will result in a string setting that allows the user to select from a list of
-	// pre-defined options.
+	// pre-defined options, or from options that the plugin provides if Dynamic is set.
 	//
 	// "generated" will result in a string setting that is set to a random, cryptographically secure
 	// string.
@@ -63,6 +63,11 @@ type PluginSetting struct {
 	// For "radio" or "dropdown" settings, this is the list of pre-defined options that the user can choose
 	// from.
 	Options []*PluginOption `json:"options,omitempty" yaml:"options,omitempty"`
+
+	// For "dropdown" settings, setting this fetches the options from the plugin's GetSettingOptions hook
+	// whenever the setting is displayed, rather than using Options. This allows settings such as choosing
+	// a channel or a project in another system.
+	Dynamic bool `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
 }
 
 type PluginSettingsSchema struct {
@@ -179,6 +184,17 @@ func ManifestListFromJson(data io.Reader) []*Manifest {
 	return manifests
 }
 
+func PluginOptionListToJson(o []*PluginOption) string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func PluginOptionListFromJson(data io.Reader) []*PluginOption {
+	var options []*PluginOption
+	json.NewDecoder(data).Decode(&options)
+	return options
+}
+
 func (m *Manifest) HasClient() bool {
 	return m.Webapp != nil
 }
@@ -94,6 +94,11 @@ func TestManifestUnmarshal(t *testing.T) {
 					},
 					Default: "thedefault",
 				},
+				&PluginSetting{
+					Key:     "thedynamicsetting",
+					Type:    "dropdown",
+					Dynamic: true,
+				},
 			},
 		},
 	}
@@ -123,6 +128,9 @@ settings_schema:
               - display_name: theoptiondisplayname
                 value: thevalue
           default: thedefault
+        - key: thedynamicsetting
+          type: dropdown
+          dynamic: true
 `), &yamlResult))
 	assert.Equal(t, expected, yamlResult)
 
@@ -158,6 +166,11 @@ settings_schema:
 					}
 				],
 				"default": "thedefault"
+			},
+			{
+				"key": "thedynamicsetting",
+				"type": "dropdown",
+				"dynamic": true
 			}
 		]
     }
@@ -0,0 +1,67 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// MarketplacePlugin is a plugin that's listed in the marketplace's catalog. DownloadURL is where its bundle is
+// downloaded from, or the path of the bundle relative to the catalog when the marketplace is a directory. Signature is
+// the base64 encoded ed25519 signature of the bundle. InstalledVersion is set by the server to the version of the
+// plugin that's installed, if any.
+type MarketplacePlugin struct {
+	Id               string `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Version          string `json:"version"`
+	HomepageURL      string `json:"homepage_url,omitempty"`
+	DownloadURL      string `json:"download_url"`
+	Signature        string `json:"signature"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+}
+
+type MarketplaceInstallRequest struct {
+	Id string `json:"id"`
+}
+
+func (p *MarketplacePlugin) IsValid() *AppError {
+	if len(p.Id) == 0 {
+		return NewAppError("MarketplacePlugin.IsValid", "model.marketplace_plugin.is_valid.id.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if len(p.DownloadURL) == 0 {
+		return NewAppError("MarketplacePlugin.IsValid", "model.marketplace_plugin.is_valid.download_url.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if len(p.Signature) == 0 {
+		return NewAppError("MarketplacePlugin.IsValid", "model.marketplace_plugin.is_valid.signature.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func MarketplacePluginListToJson(l []*MarketplacePlugin) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func MarketplacePluginListFromJson(data io.Reader) []*MarketplacePlugin {
+	var o []*MarketplacePlugin
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (r *MarketplaceInstallRequest) ToJson() string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+func MarketplaceInstallRequestFromJson(data io.Reader) *MarketplaceInstallRequest {
+	var o *MarketplaceInstallRequest
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	MENTION_KEY_PATTERN_DELIMITER  = "/"
+	MENTION_PATTERNS_DELIMITER     = "\n"
+	MENTION_KEY_PATTERN_MAX_LENGTH = 64
+	MENTION_KEY_PHRASE_MAX_WORDS   = 8
+)
+
+// IsMentionKeyPattern returns true if the mention key is a regular expression, written between slashes like /INC-\d+/.
+func IsMentionKeyPattern(key string) bool {
+	return len(key) > 2 && strings.HasPrefix(key, MENTION_KEY_PATTERN_DELIMITER) && strings.HasSuffix(key, MENTION_KEY_PATTERN_DELIMITER)
+}
+
+// IsMentionKeyPhrase returns true if the mention key is made up of more than one word.
+func IsMentionKeyPhrase(key string) bool {
+	return !IsMentionKeyPattern(key) && len(strings.Fields(key)) > 1
+}
+
+// CompileMentionKey returns a case insensitive regular expression that matches the given pattern or phrase mention key.
+// Phrases only match whole words and any amount of whitespace between them. Nil is returned for plain keys and for
+// keys that aren't valid.
+func CompileMentionKey(key string) *regexp.Regexp {
+	var expr string
+
+	if IsMentionKeyPattern(key) {
+		pattern := key[1 : len(key)-1]
+		if len(pattern) > MENTION_KEY_PATTERN_MAX_LENGTH {
+			return nil
+		}
+
+		expr = "(?i)" + pattern
+	} else if IsMentionKeyPhrase(key) {
+		words := strings.Fields(key)
+		if len(words) > MENTION_KEY_PHRASE_MAX_WORDS {
+			return nil
+		}
+
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+
+		expr = `(?i)(?:^|[^\pL\pN_@])` + strings.Join(words, `\s+`) + `(?:$|[^\pL\pN_])`
+	} else {
+		return nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+
+	// patterns that match an empty string would mention the user in every post
+	if re.MatchString("") {
+		return nil
+	}
+
+	return re
+}
+
+// IsValidMentionKeys checks that every phrase in the user's mention keys and every one of their mention patterns can be
+// compiled.
+func IsValidMentionKeys(props StringMap) bool {
+	if keys, ok := props[MENTION_KEYS_NOTIFY_PROP]; ok {
+		for _, key := range strings.Split(keys, ",") {
+			if IsMentionKeyPhrase(key) && CompileMentionKey(key) == nil {
+				return false
+			}
+		}
+	}
+
+	for _, pattern := range GetMentionPatterns(props) {
+		if !IsMentionKeyPattern(pattern) || CompileMentionKey(pattern) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetMentionPatterns returns the user's mention patterns. They're kept apart from the comma separated mention keys, one
+// per line, since regular expressions can contain commas.
+func GetMentionPatterns(props StringMap) []string {
+	patterns := []string{}
+
+	for _, pattern := range strings.Split(props[MENTION_PATTERNS_NOTIFY_PROP], MENTION_PATTERNS_DELIMITER) {
+		if len(pattern) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// NormalizeMentionKey lower cases plain keys and phrases so that they can be compared case insensitively. Patterns
+// keep their case since it changes the meaning of escapes like \D and \S.
+func NormalizeMentionKey(key string) string {
+	if IsMentionKeyPattern(key) {
+		return key
+	}
+
+	return strings.ToLower(key)
+}
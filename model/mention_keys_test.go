@@ -0,0 +1,76 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentionKeyTypes(t *testing.T) {
+	assert.True(t, IsMentionKeyPattern(`/INC-\d+/`))
+	assert.False(t, IsMentionKeyPattern("//"))
+	assert.False(t, IsMentionKeyPattern("/word"))
+	assert.False(t, IsMentionKeyPattern("word"))
+
+	assert.True(t, IsMentionKeyPhrase("release train"))
+	assert.False(t, IsMentionKeyPhrase("release"))
+	assert.False(t, IsMentionKeyPhrase("/release train/"))
+}
+
+func TestCompileMentionKey(t *testing.T) {
+	assert.Nil(t, CompileMentionKey("word"))
+	assert.Nil(t, CompileMentionKey("/(/"))
+	assert.Nil(t, CompileMentionKey("/a*/"), "patterns matching empty strings should be rejected")
+	assert.Nil(t, CompileMentionKey("/"+strings.Repeat("a", MENTION_KEY_PATTERN_MAX_LENGTH+1)+"/"))
+	assert.Nil(t, CompileMentionKey(strings.Repeat("word ", MENTION_KEY_PHRASE_MAX_WORDS+1)))
+
+	pattern := CompileMentionKey(`/INC-\d+/`)
+	if assert.NotNil(t, pattern) {
+		assert.True(t, pattern.MatchString("see inc-1234 for details"))
+		assert.False(t, pattern.MatchString("see INC-abc for details"))
+	}
+
+	phrase := CompileMentionKey("release train")
+	if assert.NotNil(t, phrase) {
+		assert.True(t, phrase.MatchString("Release  Train is leaving"))
+		assert.True(t, phrase.MatchString("the release train."))
+		assert.False(t, phrase.MatchString("prerelease train"))
+		assert.False(t, phrase.MatchString("release trains"))
+		assert.False(t, phrase.MatchString("release"))
+	}
+
+	assert.NotNil(t, CompileMentionKey("a.b c"))
+	assert.False(t, CompileMentionKey("a.b c").MatchString("axb c"), "phrases should be matched literally")
+}
+
+func TestIsValidMentionKeys(t *testing.T) {
+	assert.True(t, IsValidMentionKeys(StringMap{}))
+	assert.True(t, IsValidMentionKeys(StringMap{MENTION_KEYS_NOTIFY_PROP: "user,@user,release train"}))
+	assert.True(t, IsValidMentionKeys(StringMap{MENTION_PATTERNS_NOTIFY_PROP: "/INC-\\d{2,4}/\n/[a-z]+-bot/"}))
+	assert.False(t, IsValidMentionKeys(StringMap{MENTION_KEYS_NOTIFY_PROP: strings.Repeat("word ", MENTION_KEY_PHRASE_MAX_WORDS+1)}))
+	assert.False(t, IsValidMentionKeys(StringMap{MENTION_PATTERNS_NOTIFY_PROP: "/INC-\\d+/\n/[/"}))
+	assert.False(t, IsValidMentionKeys(StringMap{MENTION_PATTERNS_NOTIFY_PROP: "release train"}), "patterns should be written between slashes")
+}
+
+func TestGetMentionPatterns(t *testing.T) {
+	assert.Empty(t, GetMentionPatterns(StringMap{}))
+
+	patterns := GetMentionPatterns(StringMap{MENTION_PATTERNS_NOTIFY_PROP: "/INC-\\d{2,4}/\n\n/[a-z]+-bot/"})
+	assert.Equal(t, []string{`/INC-\d{2,4}/`, `/[a-z]+-bot/`}, patterns)
+
+	pattern := CompileMentionKey(patterns[0])
+	if assert.NotNil(t, pattern) {
+		assert.True(t, pattern.MatchString("see INC-123 for details"))
+		assert.False(t, pattern.MatchString("see INC-1 for details"))
+	}
+}
+
+func TestNormalizeMentionKey(t *testing.T) {
+	assert.Equal(t, "word", NormalizeMentionKey("Word"))
+	assert.Equal(t, "release train", NormalizeMentionKey("Release Train"))
+	assert.Equal(t, `/INC-\D+/`, NormalizeMentionKey(`/INC-\D+/`))
+}
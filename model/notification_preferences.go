@@ -0,0 +1,74 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// NotificationPreferences is a copy of every notification setting that a user has, which can be applied to the same
+// user to restore them or to another user, possibly on another server.
+type NotificationPreferences struct {
+	UserId      string                            `json:"user_id"`
+	ExportAt    int64                             `json:"export_at"`
+	NotifyProps StringMap                         `json:"notify_props"`
+	Preferences Preferences                       `json:"preferences"`
+	Channels    []*ChannelNotificationPreferences `json:"channels"`
+}
+
+// ChannelNotificationPreferences are a user's notification settings for one channel. The team and channel names are
+// used to find the channel when the settings are applied on another server, where channels have different ids.
+type ChannelNotificationPreferences struct {
+	ChannelId   string    `json:"channel_id"`
+	TeamName    string    `json:"team_name,omitempty"`
+	ChannelName string    `json:"channel_name,omitempty"`
+	NotifyProps StringMap `json:"notify_props"`
+}
+
+// NotificationPreferencesImportResult describes how the notification settings were applied to a user. Channels that
+// the user isn't a member of are skipped.
+type NotificationPreferencesImportResult struct {
+	ChannelsUpdated int                               `json:"channels_updated"`
+	ChannelsSkipped []*ChannelNotificationPreferences `json:"channels_skipped"`
+}
+
+func (o *NotificationPreferences) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func NotificationPreferencesFromJson(data io.Reader) *NotificationPreferences {
+	var o *NotificationPreferences
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *NotificationPreferencesImportResult) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func NotificationPreferencesImportResultFromJson(data io.Reader) *NotificationPreferencesImportResult {
+	var o *NotificationPreferencesImportResult
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *NotificationPreferences) IsValid() *AppError {
+	for _, preference := range o.Preferences {
+		if preference.Category != PREFERENCE_CATEGORY_NOTIFICATIONS {
+			return NewAppError("NotificationPreferences.IsValid", "model.notification_preferences.is_valid.category.app_error", nil, "category="+preference.Category, http.StatusBadRequest)
+		}
+	}
+
+	for _, channel := range o.Channels {
+		if !IsValidId(channel.ChannelId) && (channel.TeamName == "" || channel.ChannelName == "") {
+			return NewAppError("NotificationPreferences.IsValid", "model.notification_preferences.is_valid.channel.app_error", nil, "channel_id="+channel.ChannelId, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferencesJson(t *testing.T) {
+	preferences := &NotificationPreferences{
+		UserId:      NewId(),
+		ExportAt:    GetMillis(),
+		NotifyProps: StringMap{DESKTOP_NOTIFY_PROP: USER_NOTIFY_MENTION},
+		Preferences: Preferences{{UserId: NewId(), Category: PREFERENCE_CATEGORY_NOTIFICATIONS, Name: PREFERENCE_NAME_EMAIL_INTERVAL, Value: "900"}},
+		Channels: []*ChannelNotificationPreferences{{
+			ChannelId:   NewId(),
+			TeamName:    "team",
+			ChannelName: "town-square",
+			NotifyProps: StringMap{PUSH_NOTIFY_PROP: CHANNEL_NOTIFY_NONE},
+		}},
+	}
+
+	assert.Equal(t, preferences, NotificationPreferencesFromJson(strings.NewReader(preferences.ToJson())))
+
+	result := &NotificationPreferencesImportResult{ChannelsUpdated: 1, ChannelsSkipped: preferences.Channels}
+	assert.Equal(t, result, NotificationPreferencesImportResultFromJson(strings.NewReader(result.ToJson())))
+}
+
+func TestNotificationPreferencesIsValid(t *testing.T) {
+	preferences := &NotificationPreferences{
+		Preferences: Preferences{{Category: PREFERENCE_CATEGORY_NOTIFICATIONS, Name: PREFERENCE_NAME_EMAIL_INTERVAL, Value: "900"}},
+		Channels: []*ChannelNotificationPreferences{
+			{ChannelId: NewId()},
+			{TeamName: "team", ChannelName: "town-square"},
+		},
+	}
+	assert.Nil(t, preferences.IsValid())
+
+	preferences.Preferences = append(preferences.Preferences, Preference{Category: PREFERENCE_CATEGORY_THEME, Name: "", Value: "{}"})
+	assert.NotNil(t, preferences.IsValid(), "should only apply notification preferences")
+
+	preferences.Preferences = preferences.Preferences[:1]
+	preferences.Channels = append(preferences.Channels, &ChannelNotificationPreferences{TeamName: "team"})
+	assert.NotNil(t, preferences.IsValid(), "should need a way to find the channel")
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SCHEDULE_ENABLED_NOTIFY_PROP  = "schedule_enabled"
+	SCHEDULE_DAYS_NOTIFY_PROP     = "schedule_days"
+	SCHEDULE_START_NOTIFY_PROP    = "schedule_start"
+	SCHEDULE_END_NOTIFY_PROP      = "schedule_end"
+	SCHEDULE_TIMEZONE_NOTIFY_PROP = "schedule_timezone"
+)
+
+// NotifyScheduleProps lists the notify props that make up a notification schedule.
+var NotifyScheduleProps = []string{
+	SCHEDULE_ENABLED_NOTIFY_PROP,
+	SCHEDULE_DAYS_NOTIFY_PROP,
+	SCHEDULE_START_NOTIFY_PROP,
+	SCHEDULE_END_NOTIFY_PROP,
+	SCHEDULE_TIMEZONE_NOTIFY_PROP,
+}
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// NotifySchedule describes a recurring window of time during which notifications are held back.
+// The window starts at Start minutes after midnight on each of Days and ends at End minutes after
+// midnight, wrapping into the following day when End is not after Start.
+type NotifySchedule struct {
+	Days     map[time.Weekday]bool
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// NotifyScheduleFromProps builds the schedule stored in a set of notify props. It returns nil when
+// the schedule is disabled or cannot be parsed. The defaultTimezone is used when the props do not
+// name a timezone of their own.
+func NotifyScheduleFromProps(props StringMap, defaultTimezone string) *NotifySchedule {
+	if props[SCHEDULE_ENABLED_NOTIFY_PROP] != "true" {
+		return nil
+	}
+
+	days, ok := parseScheduleDays(props[SCHEDULE_DAYS_NOTIFY_PROP])
+	if !ok {
+		return nil
+	}
+
+	start, ok := parseScheduleTime(props[SCHEDULE_START_NOTIFY_PROP])
+	if !ok {
+		return nil
+	}
+
+	end, ok := parseScheduleTime(props[SCHEDULE_END_NOTIFY_PROP])
+	if !ok {
+		return nil
+	}
+
+	timezone := props[SCHEDULE_TIMEZONE_NOTIFY_PROP]
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &NotifySchedule{
+		Days:     days,
+		Start:    start,
+		End:      end,
+		Location: location,
+	}
+}
+
+// IsActiveAt returns true if the given time falls within the schedule.
+func (s *NotifySchedule) IsActiveAt(t time.Time) bool {
+	local := t.In(s.Location)
+	minutes := local.Hour()*60 + local.Minute()
+
+	if s.Start < s.End {
+		return s.Days[local.Weekday()] && minutes >= s.Start && minutes < s.End
+	}
+
+	// The window wraps past midnight, so the early morning part belongs to the previous day's schedule.
+	if minutes >= s.Start {
+		return s.Days[local.Weekday()]
+	}
+
+	if minutes < s.End {
+		return s.Days[local.AddDate(0, 0, -1).Weekday()]
+	}
+
+	return false
+}
+
+// IsScheduleActive returns true if the notify props contain an enabled schedule that covers the
+// given time in milliseconds.
+func IsScheduleActive(props StringMap, defaultTimezone string, millis int64) bool {
+	schedule := NotifyScheduleFromProps(props, defaultTimezone)
+	if schedule == nil {
+		return false
+	}
+
+	return schedule.IsActiveAt(time.Unix(0, millis*int64(time.Millisecond)))
+}
+
+// IsValidNotifyScheduleProps checks any schedule values present in the notify props.
+func IsValidNotifyScheduleProps(props StringMap) bool {
+	if enabled, ok := props[SCHEDULE_ENABLED_NOTIFY_PROP]; ok && enabled != "true" && enabled != "false" {
+		return false
+	}
+
+	if days, ok := props[SCHEDULE_DAYS_NOTIFY_PROP]; ok {
+		if _, valid := parseScheduleDays(days); !valid {
+			return false
+		}
+	}
+
+	if start, ok := props[SCHEDULE_START_NOTIFY_PROP]; ok {
+		if _, valid := parseScheduleTime(start); !valid {
+			return false
+		}
+	}
+
+	if end, ok := props[SCHEDULE_END_NOTIFY_PROP]; ok {
+		if _, valid := parseScheduleTime(end); !valid {
+			return false
+		}
+	}
+
+	if timezone, ok := props[SCHEDULE_TIMEZONE_NOTIFY_PROP]; ok && timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseScheduleDays(value string) (map[time.Weekday]bool, bool) {
+	days := make(map[time.Weekday]bool)
+	if value == "" {
+		return days, true
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		day, ok := scheduleWeekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, false
+		}
+		days[day] = true
+	}
+
+	return days, true
+}
+
+// parseScheduleTime converts a time formatted as HH:MM into minutes after midnight.
+func parseScheduleTime(value string) (int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, false
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, false
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+
+	return hours*60 + minutes, true
+}
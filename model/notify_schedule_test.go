@@ -0,0 +1,102 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyScheduleFromProps(t *testing.T) {
+	assert.Nil(t, NotifyScheduleFromProps(StringMap{}, "UTC"))
+	assert.Nil(t, NotifyScheduleFromProps(StringMap{SCHEDULE_ENABLED_NOTIFY_PROP: "false"}, "UTC"))
+	assert.Nil(t, NotifyScheduleFromProps(StringMap{
+		SCHEDULE_ENABLED_NOTIFY_PROP: "true",
+		SCHEDULE_DAYS_NOTIFY_PROP:    "mon",
+		SCHEDULE_START_NOTIFY_PROP:   "9:00",
+		SCHEDULE_END_NOTIFY_PROP:     "17:00",
+	}, "UTC"))
+
+	schedule := NotifyScheduleFromProps(StringMap{
+		SCHEDULE_ENABLED_NOTIFY_PROP: "true",
+		SCHEDULE_DAYS_NOTIFY_PROP:    "mon,Fri",
+		SCHEDULE_START_NOTIFY_PROP:   "09:30",
+		SCHEDULE_END_NOTIFY_PROP:     "17:00",
+	}, "America/New_York")
+	if assert.NotNil(t, schedule) {
+		assert.Equal(t, map[time.Weekday]bool{time.Monday: true, time.Friday: true}, schedule.Days)
+		assert.Equal(t, 9*60+30, schedule.Start)
+		assert.Equal(t, 17*60, schedule.End)
+		assert.Equal(t, "America/New_York", schedule.Location.String())
+	}
+
+	schedule = NotifyScheduleFromProps(StringMap{
+		SCHEDULE_ENABLED_NOTIFY_PROP:  "true",
+		SCHEDULE_DAYS_NOTIFY_PROP:     "mon",
+		SCHEDULE_START_NOTIFY_PROP:    "09:30",
+		SCHEDULE_END_NOTIFY_PROP:      "17:00",
+		SCHEDULE_TIMEZONE_NOTIFY_PROP: "Europe/Berlin",
+	}, "America/New_York")
+	if assert.NotNil(t, schedule) {
+		assert.Equal(t, "Europe/Berlin", schedule.Location.String())
+	}
+}
+
+func TestNotifyScheduleIsActiveAt(t *testing.T) {
+	// 2018-07-02 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2018, 7, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	daytime := &NotifySchedule{
+		Days:     map[time.Weekday]bool{time.Monday: true},
+		Start:    9 * 60,
+		End:      17 * 60,
+		Location: time.UTC,
+	}
+	assert.False(t, daytime.IsActiveAt(at(2, 8, 59)))
+	assert.True(t, daytime.IsActiveAt(at(2, 9, 0)))
+	assert.True(t, daytime.IsActiveAt(at(2, 16, 59)))
+	assert.False(t, daytime.IsActiveAt(at(2, 17, 0)))
+	assert.False(t, daytime.IsActiveAt(at(3, 12, 0)))
+
+	overnight := &NotifySchedule{
+		Days:     map[time.Weekday]bool{time.Monday: true},
+		Start:    22 * 60,
+		End:      8 * 60,
+		Location: time.UTC,
+	}
+	assert.False(t, overnight.IsActiveAt(at(2, 7, 0)))
+	assert.True(t, overnight.IsActiveAt(at(2, 23, 0)))
+	assert.True(t, overnight.IsActiveAt(at(3, 7, 59)))
+	assert.False(t, overnight.IsActiveAt(at(3, 8, 0)))
+	assert.False(t, overnight.IsActiveAt(at(3, 23, 0)))
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	overnight.Location = newYork
+	assert.True(t, overnight.IsActiveAt(at(3, 3, 0)))
+	assert.False(t, overnight.IsActiveAt(at(2, 23, 0)))
+}
+
+func TestIsValidNotifyScheduleProps(t *testing.T) {
+	assert.True(t, IsValidNotifyScheduleProps(StringMap{}))
+	assert.True(t, IsValidNotifyScheduleProps(StringMap{
+		SCHEDULE_ENABLED_NOTIFY_PROP:  "true",
+		SCHEDULE_DAYS_NOTIFY_PROP:     "mon,tue",
+		SCHEDULE_START_NOTIFY_PROP:    "22:00",
+		SCHEDULE_END_NOTIFY_PROP:      "07:00",
+		SCHEDULE_TIMEZONE_NOTIFY_PROP: "UTC",
+	}))
+	assert.True(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_DAYS_NOTIFY_PROP: ""}))
+	assert.False(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_ENABLED_NOTIFY_PROP: "yes"}))
+	assert.False(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_DAYS_NOTIFY_PROP: "monday"}))
+	assert.False(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_START_NOTIFY_PROP: "24:00"}))
+	assert.False(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_END_NOTIFY_PROP: "07:60"}))
+	assert.False(t, IsValidNotifyScheduleProps(StringMap{SCHEDULE_TIMEZONE_NOTIFY_PROP: "Nowhere/Special"}))
+}
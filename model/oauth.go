@@ -32,6 +32,23 @@ type OAuthApp struct {
 	CallbackUrls StringArray `json:"callback_urls"`
 	Homepage     string      `json:"homepage"`
 	IsTrusted    bool        `json:"is_trusted"`
+
+	// Public apps, such as mobile and single page apps, can't keep their client secret private, so they exchange
+	// authorization codes for access tokens with a PKCE code verifier instead.
+	IsPublic bool `json:"is_public"`
+
+	// LastIconUpdate is when an icon was last uploaded for the app, or 0 if it doesn't have one. Apps without an
+	// uploaded icon can still link to one with IconURL.
+	LastIconUpdate int64 `json:"last_icon_update,omitempty"`
+}
+
+// OAuthAppPatch changes how an app is described to users and where they can be sent back to after authorizing it.
+type OAuthAppPatch struct {
+	Name         *string      `json:"name"`
+	Description  *string      `json:"description"`
+	IconURL      *string      `json:"icon_url"`
+	CallbackUrls *StringArray `json:"callback_urls"`
+	Homepage     *string      `json:"homepage"`
 }
 
 // IsValid validates the app and returns an error if it isn't configured
@@ -134,6 +151,39 @@ func (a *OAuthApp) IsValidRedirectURL(url string) bool {
 	return false
 }
 
+func (a *OAuthApp) Patch(patch *OAuthAppPatch) {
+	if patch.Name != nil {
+		a.Name = *patch.Name
+	}
+
+	if patch.Description != nil {
+		a.Description = *patch.Description
+	}
+
+	if patch.IconURL != nil {
+		a.IconURL = *patch.IconURL
+	}
+
+	if patch.CallbackUrls != nil {
+		a.CallbackUrls = *patch.CallbackUrls
+	}
+
+	if patch.Homepage != nil {
+		a.Homepage = *patch.Homepage
+	}
+}
+
+func (o *OAuthAppPatch) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func OAuthAppPatchFromJson(data io.Reader) *OAuthAppPatch {
+	var o *OAuthAppPatch
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
 func OAuthAppFromJson(data io.Reader) *OAuthApp {
 	var app *OAuthApp
 	json.NewDecoder(data).Decode(&app)
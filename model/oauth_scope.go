@@ -0,0 +1,99 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const (
+	OAUTH_SCOPE_READ_USERS     = "read:users"
+	OAUTH_SCOPE_WRITE_USERS    = "write:users"
+	OAUTH_SCOPE_READ_TEAMS     = "read:teams"
+	OAUTH_SCOPE_WRITE_TEAMS    = "write:teams"
+	OAUTH_SCOPE_READ_CHANNELS  = "read:channels"
+	OAUTH_SCOPE_WRITE_CHANNELS = "write:channels"
+	OAUTH_SCOPE_READ_POSTS     = "read:posts"
+	OAUTH_SCOPE_WRITE_POSTS    = "write:posts"
+	OAUTH_SCOPE_READ_FILES     = "read:files"
+	OAUTH_SCOPE_WRITE_FILES    = "write:files"
+
+	OAUTH_SCOPE_READ_PREFIX  = "read:"
+	OAUTH_SCOPE_WRITE_PREFIX = "write:"
+)
+
+// OAuthScopes are the scopes that apps can request, in the order that they're listed when users are asked to allow
+// them. The default scope, which grants full access to the API, can also be requested.
+var OAuthScopes = []string{
+	OAUTH_SCOPE_READ_USERS,
+	OAUTH_SCOPE_WRITE_USERS,
+	OAUTH_SCOPE_READ_TEAMS,
+	OAUTH_SCOPE_WRITE_TEAMS,
+	OAUTH_SCOPE_READ_CHANNELS,
+	OAUTH_SCOPE_WRITE_CHANNELS,
+	OAUTH_SCOPE_READ_POSTS,
+	OAUTH_SCOPE_WRITE_POSTS,
+	OAUTH_SCOPE_READ_FILES,
+	OAUTH_SCOPE_WRITE_FILES,
+}
+
+// OAuthScope describes a scope so that users can decide whether to allow an app to have it.
+type OAuthScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func OAuthScopeListToJson(l []*OAuthScope) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func OAuthScopeListFromJson(data io.Reader) []*OAuthScope {
+	var o []*OAuthScope
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// ParseOAuthScope splits a space separated scope, as given in OAuth requests, into the scopes that it's made up of.
+// Names that aren't scopes are ignored since apps could request anything before scopes were supported, so a scope
+// without any known ones is the default scope.
+func ParseOAuthScope(scope string) []string {
+	var scopes []string
+	for _, s := range strings.Fields(scope) {
+		if (s == DEFAULT_SCOPE || containsString(OAuthScopes, s)) && !containsString(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return []string{DEFAULT_SCOPE}
+	}
+
+	return scopes
+}
+
+// OAuthScopeIncludes returns true if a scope grants everything that another one does. Apps that were given the default
+// scope have full access, and write access to a resource includes read access to it.
+func OAuthScopeIncludes(granted string, requested string) bool {
+	grantedScopes := ParseOAuthScope(granted)
+	if containsString(grantedScopes, DEFAULT_SCOPE) {
+		return true
+	}
+
+	for _, s := range ParseOAuthScope(requested) {
+		if containsString(grantedScopes, s) {
+			continue
+		}
+
+		if strings.HasPrefix(s, OAUTH_SCOPE_READ_PREFIX) && containsString(grantedScopes, OAUTH_SCOPE_WRITE_PREFIX+strings.TrimPrefix(s, OAUTH_SCOPE_READ_PREFIX)) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
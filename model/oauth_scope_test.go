@@ -0,0 +1,49 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOAuthScope(t *testing.T) {
+	assert.Equal(t, []string{DEFAULT_SCOPE}, ParseOAuthScope(""))
+	assert.Equal(t, []string{DEFAULT_SCOPE}, ParseOAuthScope("all"), "should ignore unknown scopes")
+	assert.Equal(t, []string{OAUTH_SCOPE_READ_POSTS, OAUTH_SCOPE_WRITE_USERS}, ParseOAuthScope(" read:posts  junk write:users read:posts"))
+}
+
+func TestOAuthScopeIncludes(t *testing.T) {
+	for name, tc := range map[string]struct {
+		Granted   string
+		Requested string
+		Expected  bool
+	}{
+		"default scope grants everything":        {"user", "write:posts read:files", true},
+		"legacy scope grants everything":         {"", "write:channels", true},
+		"same scope":                             {"read:posts", "read:posts", true},
+		"write includes read":                    {"write:posts", "read:posts", true},
+		"read doesn't include write":             {"read:posts", "write:posts", false},
+		"other resource":                         {"write:posts", "read:channels", false},
+		"some of the requested scopes":           {"read:posts", "read:posts read:users", false},
+		"all of the requested scopes":            {"read:users write:posts", "read:posts read:users", true},
+		"default scope needs the default scope":  {"read:posts write:posts", "user", false},
+		"unknown requested scope is the default": {"read:posts", "all", false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, OAuthScopeIncludes(tc.Granted, tc.Requested))
+		})
+	}
+}
+
+func TestOAuthScopeListJson(t *testing.T) {
+	scopes := []*OAuthScope{{Name: OAUTH_SCOPE_READ_POSTS, Description: "Read posts"}}
+
+	decoded := OAuthScopeListFromJson(strings.NewReader(OAuthScopeListToJson(scopes)))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, scopes[0], decoded[0])
+}
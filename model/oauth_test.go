@@ -51,6 +51,31 @@ func TestOAuthAppPreUpdate(t *testing.T) {
 	a1.PreUpdate()
 }
 
+func TestOAuthAppPatch(t *testing.T) {
+	app := &OAuthApp{
+		Id:           NewId(),
+		Name:         "TestOAuthApp",
+		Description:  "description",
+		CallbackUrls: []string{"https://nowhere.com"},
+		Homepage:     "https://nowhere.com",
+		IconURL:      "https://nowhere.com/icon_image.png",
+		ClientSecret: NewId(),
+	}
+
+	patch := &OAuthAppPatch{
+		Description:  NewString("new description"),
+		CallbackUrls: &StringArray{"https://nowhere.com/callback", "https://elsewhere.com/callback"},
+	}
+
+	app.Patch(OAuthAppPatchFromJson(strings.NewReader(patch.ToJson())))
+
+	require.Equal(t, "TestOAuthApp", app.Name)
+	require.Equal(t, "new description", app.Description)
+	require.Equal(t, StringArray{"https://nowhere.com/callback", "https://elsewhere.com/callback"}, app.CallbackUrls)
+	require.Equal(t, "https://nowhere.com", app.Homepage)
+	require.Equal(t, "https://nowhere.com/icon_image.png", app.IconURL)
+}
+
 func TestOAuthAppIsValid(t *testing.T) {
 	app := OAuthApp{}
 
@@ -4,6 +4,9 @@
 package model
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,6 +33,13 @@ type OutgoingWebhook struct {
 	ContentType  string      `json:"content_type"`
 	Username     string      `json:"username"`
 	IconURL      string      `json:"icon_url"`
+
+	// TriggerEvents are the events that trigger the hook. Hooks without any are only triggered by posts.
+	TriggerEvents StringArray `json:"trigger_events"`
+
+	// SigningSecret is used to sign requests to the callback URLs so that they can tell that the requests came from
+	// this server. Hooks created before requests were signed don't have one until it's regenerated.
+	SigningSecret string `json:"signing_secret"`
 }
 
 type OutgoingWebhookPayload struct {
@@ -45,6 +55,13 @@ type OutgoingWebhookPayload struct {
 	Text        string `json:"text"`
 	TriggerWord string `json:"trigger_word"`
 	FileIds     string `json:"file_ids"`
+
+	// Event is the type of event that triggered the hook. For posts and edits, the post and its author are sent. For
+	// deleted posts, the text is the message that was deleted. For reactions, the user is the one who reacted to the
+	// post and the emoji name is set. For users joining a channel, the user is the one who joined and no post is sent.
+	Event        string `json:"event"`
+	OriginalText string `json:"original_text,omitempty"`
+	EmojiName    string `json:"emoji_name,omitempty"`
 }
 
 type OutgoingWebhookResponse struct {
@@ -57,7 +74,36 @@ type OutgoingWebhookResponse struct {
 	ResponseType string             `json:"response_type"`
 }
 
-const OUTGOING_HOOK_RESPONSE_TYPE_COMMENT = "comment"
+const (
+	OUTGOING_HOOK_RESPONSE_TYPE_COMMENT = "comment"
+
+	OUTGOING_HOOK_SIGNING_SECRET_MAX_LENGTH = 128
+
+	// Headers that are sent with signed requests. Every attempt to deliver a payload has the same delivery id so that
+	// receivers can ignore retries of payloads that they've already handled.
+	HEADER_OUTGOING_HOOK_SIGNATURE = "X-Mattermost-Signature"
+	HEADER_OUTGOING_HOOK_TIMESTAMP = "X-Mattermost-Timestamp"
+	HEADER_OUTGOING_HOOK_DELIVERY  = "X-Mattermost-Delivery"
+
+	OUTGOING_HOOK_SIGNATURE_VERSION = "v1"
+
+	// Events that outgoing webhooks can subscribe to
+	OUTGOING_HOOK_EVENT_POSTED              = "posted"
+	OUTGOING_HOOK_EVENT_POST_EDITED         = "post_edited"
+	OUTGOING_HOOK_EVENT_POST_DELETED        = "post_deleted"
+	OUTGOING_HOOK_EVENT_REACTION_ADDED      = "reaction_added"
+	OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL = "user_joined_channel"
+)
+
+func IsValidOutgoingHookEvent(event string) bool {
+	switch event {
+	case OUTGOING_HOOK_EVENT_POSTED, OUTGOING_HOOK_EVENT_POST_EDITED, OUTGOING_HOOK_EVENT_POST_DELETED,
+		OUTGOING_HOOK_EVENT_REACTION_ADDED, OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL:
+		return true
+	}
+
+	return false
+}
 
 func (o *OutgoingWebhookPayload) ToJSON() string {
 	b, _ := json.Marshal(o)
@@ -78,6 +124,15 @@ func (o *OutgoingWebhookPayload) ToFormValues() string {
 	v.Set("text", o.Text)
 	v.Set("trigger_word", o.TriggerWord)
 	v.Set("file_ids", o.FileIds)
+	v.Set("event", o.Event)
+
+	if o.OriginalText != "" {
+		v.Set("original_text", o.OriginalText)
+	}
+
+	if o.EmojiName != "" {
+		v.Set("emoji_name", o.EmojiName)
+	}
 
 	return v.Encode()
 }
@@ -157,6 +212,16 @@ func (o *OutgoingWebhook) IsValid() *AppError {
 		}
 	}
 
+	if len(ArrayToJson(o.TriggerEvents)) > 1024 {
+		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.trigger_events.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, event := range o.TriggerEvents {
+		if !IsValidOutgoingHookEvent(event) {
+			return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.trigger_events.app_error", nil, "event="+event, http.StatusBadRequest)
+		}
+	}
+
 	if len(o.CallbackURLs) == 0 || len(fmt.Sprintf("%s", o.CallbackURLs)) > 1024 {
 		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.callback.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -191,6 +256,10 @@ func (o *OutgoingWebhook) IsValid() *AppError {
 		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.icon_url.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if len(o.SigningSecret) > OUTGOING_HOOK_SIGNING_SECRET_MAX_LENGTH {
+		return NewAppError("OutgoingWebhook.IsValid", "model.outgoing_hook.is_valid.signing_secret.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -203,6 +272,10 @@ func (o *OutgoingWebhook) PreSave() {
 		o.Token = NewId()
 	}
 
+	if o.SigningSecret == "" {
+		o.SigningSecret = NewOutgoingWebhookSigningSecret()
+	}
+
 	o.CreateAt = GetMillis()
 	o.UpdateAt = o.CreateAt
 }
@@ -262,3 +335,34 @@ func (o *OutgoingWebhook) GetTriggerWord(word string, isExactMatch bool) (trigge
 
 	return triggerWord
 }
+
+// HasTriggerEvent returns true if the hook is triggered by a type of event. Hooks that don't subscribe to any events
+// are triggered by posts, which is how they worked before they could subscribe to others.
+func (o *OutgoingWebhook) HasTriggerEvent(event string) bool {
+	if len(o.TriggerEvents) == 0 {
+		return event == OUTGOING_HOOK_EVENT_POSTED
+	}
+
+	for _, triggerEvent := range o.TriggerEvents {
+		if triggerEvent == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewOutgoingWebhookSigningSecret() string {
+	return NewId() + NewId()
+}
+
+// SignOutgoingWebhookPayload returns the signature of a request to an outgoing webhook's callback URL, which is an HMAC
+// of the timestamp of the request and its body. Receivers should compute it themselves and compare it to the one in the
+// signature header.
+func SignOutgoingWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return OUTGOING_HOOK_SIGNATURE_VERSION + "=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	OUTGOING_HOOK_DELIVERY_STATUS_PENDING   = "pending"
+	OUTGOING_HOOK_DELIVERY_STATUS_RETRYING  = "retrying"
+	OUTGOING_HOOK_DELIVERY_STATUS_SUCCEEDED = "succeeded"
+	OUTGOING_HOOK_DELIVERY_STATUS_FAILED    = "failed"
+)
+
+// OutgoingWebhookDelivery is the status of sending a payload to one of an outgoing webhook's callback URLs. Requests
+// that time out or fail with a server error are retried, so a delivery may take several attempts.
+type OutgoingWebhookDelivery struct {
+	Id            string `json:"id"`
+	HookId        string `json:"hook_id"`
+	PostId        string `json:"post_id"`
+	URL           string `json:"url"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	StatusCode    int    `json:"status_code,omitempty"`
+	Error         string `json:"error,omitempty"`
+	CreateAt      int64  `json:"create_at"`
+	LastAttemptAt int64  `json:"last_attempt_at,omitempty"`
+	NextAttemptAt int64  `json:"next_attempt_at,omitempty"`
+}
+
+func OutgoingWebhookDeliveryListToJson(l []*OutgoingWebhookDelivery) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func OutgoingWebhookDeliveryListFromJson(data io.Reader) []*OutgoingWebhookDelivery {
+	var o []*OutgoingWebhookDelivery
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
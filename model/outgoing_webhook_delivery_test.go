@@ -0,0 +1,30 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutgoingWebhookDeliveryListJson(t *testing.T) {
+	deliveries := []*OutgoingWebhookDelivery{
+		{
+			Id:         NewId(),
+			HookId:     NewId(),
+			URL:        "http://example.com",
+			Status:     OUTGOING_HOOK_DELIVERY_STATUS_SUCCEEDED,
+			Attempts:   2,
+			StatusCode: 200,
+			CreateAt:   GetMillis(),
+		},
+	}
+
+	decoded := OutgoingWebhookDeliveryListFromJson(strings.NewReader(OutgoingWebhookDeliveryListToJson(deliveries)))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, deliveries[0], decoded[0])
+}
@@ -141,6 +141,16 @@ func TestOutgoingWebhookIsValid(t *testing.T) {
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
+
+	o.TriggerEvents = StringArray{OUTGOING_HOOK_EVENT_POSTED, OUTGOING_HOOK_EVENT_POST_DELETED, "junk"}
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.TriggerEvents = StringArray{OUTGOING_HOOK_EVENT_POSTED, OUTGOING_HOOK_EVENT_POST_DELETED}
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestOutgoingWebhookPayloadToFormValues(t *testing.T) {
@@ -157,6 +167,7 @@ func TestOutgoingWebhookPayloadToFormValues(t *testing.T) {
 		Text:        "Text",
 		TriggerWord: "TriggerWord",
 		FileIds:     "FileIds",
+		Event:       OUTGOING_HOOK_EVENT_POSTED,
 	}
 	v := url.Values{}
 	v.Set("token", "Token")
@@ -171,11 +182,40 @@ func TestOutgoingWebhookPayloadToFormValues(t *testing.T) {
 	v.Set("text", "Text")
 	v.Set("trigger_word", "TriggerWord")
 	v.Set("file_ids", "FileIds")
+	v.Set("event", "posted")
+	if got, want := p.ToFormValues(), v.Encode(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got %+v, wanted %+v", got, want)
+	}
+
+	p.Event = OUTGOING_HOOK_EVENT_POST_EDITED
+	p.OriginalText = "OriginalText"
+	p.EmojiName = "smile"
+	v.Set("event", "post_edited")
+	v.Set("original_text", "OriginalText")
+	v.Set("emoji_name", "smile")
 	if got, want := p.ToFormValues(), v.Encode(); !reflect.DeepEqual(got, want) {
 		t.Fatalf("Got %+v, wanted %+v", got, want)
 	}
 }
 
+func TestOutgoingWebhookHasTriggerEvent(t *testing.T) {
+	o := OutgoingWebhook{}
+	if !o.HasTriggerEvent(OUTGOING_HOOK_EVENT_POSTED) {
+		t.Fatal("hooks without events should be triggered by posts")
+	}
+	if o.HasTriggerEvent(OUTGOING_HOOK_EVENT_REACTION_ADDED) {
+		t.Fatal("hooks without events should only be triggered by posts")
+	}
+
+	o.TriggerEvents = StringArray{OUTGOING_HOOK_EVENT_REACTION_ADDED, OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL}
+	if o.HasTriggerEvent(OUTGOING_HOOK_EVENT_POSTED) {
+		t.Fatal("should not be triggered by posts")
+	}
+	if !o.HasTriggerEvent(OUTGOING_HOOK_EVENT_USER_JOINED_CHANNEL) {
+		t.Fatal("should be triggered by users joining")
+	}
+}
+
 func TestOutgoingWebhookPreSave(t *testing.T) {
 	o := OutgoingWebhook{}
 	o.PreSave()
@@ -208,3 +248,33 @@ func TestOutgoingWebhookResponseJson(t *testing.T) {
 		t.Fatal("Text does not match")
 	}
 }
+
+func TestOutgoingWebhookSigningSecret(t *testing.T) {
+	o := OutgoingWebhook{}
+	o.PreSave()
+	if o.SigningSecret == "" {
+		t.Fatal("should have generated a signing secret")
+	}
+
+	o.SigningSecret = strings.Repeat("a", OUTGOING_HOOK_SIGNING_SECRET_MAX_LENGTH+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+}
+
+func TestSignOutgoingWebhookPayload(t *testing.T) {
+	// Computed with: printf '1500000000.text=hello' | openssl dgst -sha256 -hmac secret
+	expected := "v1=" + "936128199debac81bce4a060ade143bca434926b75c664b9793be80e44133db2"
+
+	if signature := SignOutgoingWebhookPayload("secret", 1500000000, []byte("text=hello")); signature != expected {
+		t.Fatalf("wrong signature %v", signature)
+	}
+
+	if SignOutgoingWebhookPayload("other", 1500000000, []byte("text=hello")) == expected {
+		t.Fatal("signature should depend on the secret")
+	}
+
+	if SignOutgoingWebhookPayload("secret", 1500000001, []byte("text=hello")) == expected {
+		t.Fatal("signature should depend on the timestamp")
+	}
+}
@@ -48,6 +48,7 @@ var PERMISSION_UPLOAD_FILE *Permission
 var PERMISSION_GET_PUBLIC_LINK *Permission
 var PERMISSION_MANAGE_WEBHOOKS *Permission
 var PERMISSION_MANAGE_OTHERS_WEBHOOKS *Permission
+var PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS *Permission
 var PERMISSION_MANAGE_OAUTH *Permission
 var PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH *Permission
 var PERMISSION_MANAGE_EMOJIS *Permission
@@ -69,6 +70,9 @@ var PERMISSION_MANAGE_JOBS *Permission
 var PERMISSION_CREATE_USER_ACCESS_TOKEN *Permission
 var PERMISSION_READ_USER_ACCESS_TOKEN *Permission
 var PERMISSION_REVOKE_USER_ACCESS_TOKEN *Permission
+var PERMISSION_CREATE_BOT *Permission
+var PERMISSION_MANAGE_BOTS *Permission
+var PERMISSION_MANAGE_OTHERS_BOTS *Permission
 
 // General permission that encompasses all system admin functions
 // in the future this could be broken up to allow access to some
@@ -276,6 +280,12 @@ func initializePermissions() {
 		"authentication.permissions.manage_others_webhooks.description",
 		PERMISSION_SCOPE_TEAM,
 	}
+	PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS = &Permission{
+		"manage_private_outgoing_webhooks",
+		"authentication.permissions.manage_private_outgoing_webhooks.name",
+		"authentication.permissions.manage_private_outgoing_webhooks.description",
+		PERMISSION_SCOPE_TEAM,
+	}
 	PERMISSION_MANAGE_OAUTH = &Permission{
 		"manage_oauth",
 		"authentication.permissions.manage_oauth.name",
@@ -402,6 +412,24 @@ func initializePermissions() {
 		"authentication.permisssions.manage_jobs.description",
 		PERMISSION_SCOPE_SYSTEM,
 	}
+	PERMISSION_CREATE_BOT = &Permission{
+		"create_bot",
+		"authentication.permissions.create_bot.name",
+		"authentication.permissions.create_bot.description",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+	PERMISSION_MANAGE_BOTS = &Permission{
+		"manage_bots",
+		"authentication.permissions.manage_bots.name",
+		"authentication.permissions.manage_bots.description",
+		PERMISSION_SCOPE_SYSTEM,
+	}
+	PERMISSION_MANAGE_OTHERS_BOTS = &Permission{
+		"manage_others_bots",
+		"authentication.permissions.manage_others_bots.name",
+		"authentication.permissions.manage_others_bots.description",
+		PERMISSION_SCOPE_SYSTEM,
+	}
 
 	ALL_PERMISSIONS = []*Permission{
 		PERMISSION_INVITE_USER,
@@ -436,6 +464,7 @@ func initializePermissions() {
 		PERMISSION_GET_PUBLIC_LINK,
 		PERMISSION_MANAGE_WEBHOOKS,
 		PERMISSION_MANAGE_OTHERS_WEBHOOKS,
+		PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS,
 		PERMISSION_MANAGE_OAUTH,
 		PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH,
 		PERMISSION_MANAGE_EMOJIS,
@@ -457,6 +486,9 @@ func initializePermissions() {
 		PERMISSION_CREATE_USER_ACCESS_TOKEN,
 		PERMISSION_READ_USER_ACCESS_TOKEN,
 		PERMISSION_REVOKE_USER_ACCESS_TOKEN,
+		PERMISSION_CREATE_BOT,
+		PERMISSION_MANAGE_BOTS,
+		PERMISSION_MANAGE_OTHERS_BOTS,
 		PERMISSION_MANAGE_SYSTEM,
 	}
 }
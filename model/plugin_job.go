@@ -0,0 +1,60 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const (
+	PLUGIN_JOB_ID_MAX_LENGTH = 64
+
+	// Recurring plugin jobs can't run more often than this many seconds apart
+	PLUGIN_JOB_MIN_INTERVAL = 60
+)
+
+// PluginJob is a job that a plugin schedules with the server. A job with an Interval runs every Interval seconds, and a
+// job without one runs once at RunAt, which is in milliseconds. Jobs only run on one server in a cluster each time
+// they're due, and they're run by invoking the plugin's ExecuteScheduledJob hook with the job's Id.
+type PluginJob struct {
+	Id       string `json:"id"`
+	Interval int64  `json:"interval"`
+	RunAt    int64  `json:"run_at"`
+}
+
+func (o *PluginJob) IsValid() *AppError {
+	if len(o.Id) == 0 || len(o.Id) > PLUGIN_JOB_ID_MAX_LENGTH {
+		return NewAppError("PluginJob.IsValid", "model.plugin_job.is_valid.id.app_error", map[string]interface{}{"Max": PLUGIN_JOB_ID_MAX_LENGTH}, "", http.StatusBadRequest)
+	}
+
+	if o.Interval < 0 || (o.Interval > 0 && o.Interval < PLUGIN_JOB_MIN_INTERVAL) {
+		return NewAppError("PluginJob.IsValid", "model.plugin_job.is_valid.interval.app_error", map[string]interface{}{"Min": PLUGIN_JOB_MIN_INTERVAL}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Interval == 0 && o.RunAt <= 0 {
+		return NewAppError("PluginJob.IsValid", "model.plugin_job.is_valid.run_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsRecurring returns true if the job runs every Interval seconds rather than once.
+func (o *PluginJob) IsRecurring() bool {
+	return o.Interval > 0
+}
+
+// Slot identifies the run of the job that's due at the given time in milliseconds, so that servers can agree on which
+// of them runs it. Recurring jobs have a slot for every interval, and one-shot jobs have a single slot once RunAt has
+// passed. It returns -1 if a one-shot job isn't due yet.
+func (o *PluginJob) Slot(now int64) int64 {
+	if o.IsRecurring() {
+		return now / (o.Interval * 1000)
+	}
+
+	if now < o.RunAt {
+		return -1
+	}
+
+	return o.RunAt
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginJobIsValid(t *testing.T) {
+	require.Nil(t, (&PluginJob{Id: "job", Interval: PLUGIN_JOB_MIN_INTERVAL}).IsValid())
+	require.Nil(t, (&PluginJob{Id: "job", RunAt: GetMillis()}).IsValid())
+
+	require.NotNil(t, (&PluginJob{Interval: PLUGIN_JOB_MIN_INTERVAL}).IsValid())
+	require.NotNil(t, (&PluginJob{Id: strings.Repeat("a", PLUGIN_JOB_ID_MAX_LENGTH+1), Interval: PLUGIN_JOB_MIN_INTERVAL}).IsValid())
+	require.NotNil(t, (&PluginJob{Id: "job", Interval: PLUGIN_JOB_MIN_INTERVAL - 1}).IsValid())
+	require.NotNil(t, (&PluginJob{Id: "job", Interval: -1}).IsValid())
+	require.NotNil(t, (&PluginJob{Id: "job"}).IsValid())
+}
+
+func TestPluginJobSlot(t *testing.T) {
+	recurring := &PluginJob{Id: "job", Interval: 60}
+	assert.Equal(t, int64(0), recurring.Slot(59999))
+	assert.Equal(t, int64(1), recurring.Slot(60000))
+	assert.Equal(t, recurring.Slot(60000), recurring.Slot(119999))
+
+	oneShot := &PluginJob{Id: "job", RunAt: 1000}
+	assert.Equal(t, int64(-1), oneShot.Slot(999))
+	assert.Equal(t, int64(1000), oneShot.Slot(1000))
+	assert.Equal(t, int64(1000), oneShot.Slot(5000))
+}
@@ -13,10 +13,13 @@ const (
 	KEY_VALUE_KEY_MAX_RUNES       = 50
 )
 
+// PluginKeyValue is a value that a plugin has stored. ExpireAt is when the value stops being returned, or 0 if it's
+// kept until it's deleted.
 type PluginKeyValue struct {
 	PluginId string `json:"plugin_id"`
 	Key      string `json:"key" db:"PKey"`
 	Value    []byte `json:"value" db:"PValue"`
+	ExpireAt int64  `json:"expire_at"`
 }
 
 func (kv *PluginKeyValue) IsValid() *AppError {
@@ -28,5 +31,14 @@ func (kv *PluginKeyValue) IsValid() *AppError {
 		return NewAppError("PluginKeyValue.IsValid", "model.plugin_key_value.is_valid.key.app_error", map[string]interface{}{"Max": KEY_VALUE_KEY_MAX_RUNES, "Min": 0}, "key="+kv.Key, http.StatusBadRequest)
 	}
 
+	if kv.ExpireAt < 0 {
+		return NewAppError("PluginKeyValue.IsValid", "model.plugin_key_value.is_valid.expire_at.app_error", nil, "key="+kv.Key, http.StatusBadRequest)
+	}
+
 	return nil
 }
+
+// IsExpired returns true if the value has expired at the given time.
+func (kv *PluginKeyValue) IsExpired(now int64) bool {
+	return kv.ExpireAt != 0 && kv.ExpireAt <= now
+}
@@ -19,4 +19,17 @@ func TestPluginKeyIsValid(t *testing.T) {
 	kv.PluginId = "someid"
 	kv.Key = ""
 	assert.NotNil(t, kv.IsValid())
+
+	kv.Key = "somekey"
+	kv.ExpireAt = -1
+	assert.NotNil(t, kv.IsValid())
+}
+
+func TestPluginKeyIsExpired(t *testing.T) {
+	kv := PluginKeyValue{PluginId: "someid", Key: "somekey", Value: []byte("somevalue")}
+	assert.False(t, kv.IsExpired(GetMillis()), "values without an expiry time should never expire")
+
+	kv.ExpireAt = 1000
+	assert.False(t, kv.IsExpired(999))
+	assert.True(t, kv.IsExpired(1000))
 }
@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// PluginLogEntry is an entry from a plugin's log. Entries always have a "level", a "ts" in seconds and a "msg", along
+// with any fields that were logged with the message.
+type PluginLogEntry map[string]interface{}
+
+func PluginLogEntryListFromJson(data io.Reader) []PluginLogEntry {
+	var o []PluginLogEntry
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
@@ -13,7 +13,7 @@ const (
 	PluginStateStarting            = 1 // unused by server
 	PluginStateRunning             = 2
 	PluginStateFailedToStart       = 3
-	PluginStateFailedToStayRunning = 4 // unused by server
+	PluginStateFailedToStayRunning = 4
 	PluginStateStopping            = 5 // unused by server
 )
 
@@ -26,6 +26,11 @@ type PluginStatus struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Version     string `json:"version"`
+
+	// The number of times the server has restarted the plugin after it stopped responding, and the reason it
+	// last stopped responding
+	Restarts int    `json:"restarts"`
+	Error    string `json:"error,omitempty"`
 }
 
 type PluginStatuses []*PluginStatus
@@ -50,8 +50,20 @@ const (
 	PROPS_ADD_CHANNEL_MEMBER    = "add_channel_member"
 	POST_PROPS_ADDED_USER_ID    = "addedUserId"
 	POST_PROPS_DELETE_BY        = "deleteBy"
+	POST_PROPS_PRIORITY         = "priority"
+	POST_PROPS_REQUESTED_ACK    = "requested_ack"
+	POST_PROPS_FROM_BOT         = "from_bot"
+	POST_PROPS_WEBHOOK_ID       = "webhook_id"
+	POST_PRIORITY_IMPORTANT     = "important"
+	POST_PRIORITY_URGENT        = "urgent"
 	POST_ACTION_TYPE_BUTTON     = "button"
 	POST_ACTION_TYPE_SELECT     = "select"
+
+	// Menus with this data source load their options from their integration when they're opened
+	POST_ACTION_DATA_SOURCE_DYNAMIC = "dynamic"
+
+	// The type of the request sent to an integration to get the options of a dynamic menu
+	POST_ACTION_REQUEST_TYPE_OPTIONS = "options"
 )
 
 type Post struct {
@@ -81,6 +93,7 @@ type Post struct {
 	FileIds       StringArray     `json:"file_ids,omitempty"`
 	PendingPostId string          `json:"pending_post_id" db:"-"`
 	HasReactions  bool            `json:"has_reactions,omitempty"`
+	RemoteId      *string         `json:"remote_id,omitempty"`
 }
 
 type PostEphemeral struct {
@@ -94,6 +107,7 @@ type PostPatch struct {
 	Props        *StringInterface `json:"props"`
 	FileIds      *StringArray     `json:"file_ids"`
 	HasReactions *bool            `json:"has_reactions"`
+	RemoteId     *string          `json:"remote_id"`
 }
 
 type SearchParameter struct {
@@ -150,6 +164,14 @@ type PostActionIntegrationRequest struct {
 type PostActionIntegrationResponse struct {
 	Update        *Post  `json:"update"`
 	EphemeralText string `json:"ephemeral_text"`
+
+	// ClearOptionsCache discards the cached options of the dynamic menus that use the integration's URL so that
+	// changes made by the action are shown the next time that they're opened.
+	ClearOptionsCache bool `json:"clear_options_cache,omitempty"`
+}
+
+type PostActionOptionsResponse struct {
+	Options []*PostActionOptions `json:"options"`
 }
 
 func (o *Post) ToJson() string {
@@ -260,6 +282,27 @@ func (o *Post) IsValid(maxPostSize int) *AppError {
 		return NewAppError("Post.IsValid", "model.post.is_valid.props.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
 
+	if !IsValidRemoteId(o.RemoteId) {
+		return NewAppError("Post.IsValid", "model.post.is_valid.remote_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if priority, ok := o.Props[POST_PROPS_PRIORITY]; ok {
+		if priority != POST_PRIORITY_IMPORTANT && priority != POST_PRIORITY_URGENT {
+			return NewAppError("Post.IsValid", "model.post.is_valid.priority.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	}
+
+	if requestedAck, ok := o.Props[POST_PROPS_REQUESTED_ACK]; ok {
+		if _, ok := requestedAck.(bool); !ok {
+			return NewAppError("Post.IsValid", "model.post.is_valid.requested_ack.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	}
+
+	// Priority and acknowledgements apply to the start of a conversation, not to each reply in it
+	if len(o.RootId) > 0 && (o.GetPriority() != "" || o.IsAckRequested()) {
+		return NewAppError("Post.IsValid", "model.post.is_valid.priority_reply.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -323,6 +366,22 @@ func (o *Post) IsSystemMessage() bool {
 	return len(o.Type) >= len(POST_SYSTEM_MESSAGE_PREFIX) && o.Type[:len(POST_SYSTEM_MESSAGE_PREFIX)] == POST_SYSTEM_MESSAGE_PREFIX
 }
 
+// GetPriority returns the priority that the post was sent with, or an empty string if it was sent without one.
+func (o *Post) GetPriority() string {
+	priority, _ := o.Props[POST_PROPS_PRIORITY].(string)
+	return priority
+}
+
+func (o *Post) IsUrgent() bool {
+	return o.GetPriority() == POST_PRIORITY_URGENT
+}
+
+// IsAckRequested returns whether the sender asked the recipients of the post to acknowledge that they've seen it.
+func (o *Post) IsAckRequested() bool {
+	requestedAck, _ := o.Props[POST_PROPS_REQUESTED_ACK].(bool)
+	return requestedAck
+}
+
 func (p *Post) Patch(patch *PostPatch) {
 	if patch.IsPinned != nil {
 		p.IsPinned = *patch.IsPinned
@@ -343,6 +402,10 @@ func (p *Post) Patch(patch *PostPatch) {
 	if patch.HasReactions != nil {
 		p.HasReactions = *patch.HasReactions
 	}
+
+	if patch.RemoteId != nil {
+		p.RemoteId = patchRemoteId(patch.RemoteId)
+	}
 }
 
 func (o *PostPatch) ToJson() string {
@@ -503,6 +566,17 @@ func DoPostActionRequestFromJson(data io.Reader) *DoPostActionRequest {
 	return o
 }
 
+func PostActionOptionsListToJson(options []*PostActionOptions) string {
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+func PostActionOptionsListFromJson(data io.Reader) []*PostActionOptions {
+	var o []*PostActionOptions
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
 // RewriteImageURLs takes a message and returns a copy that has all of the image URLs replaced
 // according to the function f. For each image URL, f will be invoked, and the resulting markdown
 // will contain the URL returned by that invocation instead.
@@ -0,0 +1,61 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// PostAcknowledgement records that a user has seen a post whose sender asked for acknowledgement.
+type PostAcknowledgement struct {
+	UserId         string `json:"user_id"`
+	PostId         string `json:"post_id"`
+	AcknowledgedAt int64  `json:"acknowledged_at"`
+}
+
+func (o *PostAcknowledgement) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func PostAcknowledgementFromJson(data io.Reader) *PostAcknowledgement {
+	var o *PostAcknowledgement
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func PostAcknowledgementsToJson(o []*PostAcknowledgement) string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func PostAcknowledgementsFromJson(data io.Reader) []*PostAcknowledgement {
+	var o []*PostAcknowledgement
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *PostAcknowledgement) PreSave() {
+	if o.AcknowledgedAt == 0 {
+		o.AcknowledgedAt = GetMillis()
+	}
+}
+
+func (o *PostAcknowledgement) IsValid() *AppError {
+	if len(o.UserId) != 26 {
+		return NewAppError("PostAcknowledgement.IsValid", "model.post_acknowledgement.is_valid.user_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if len(o.PostId) != 26 {
+		return NewAppError("PostAcknowledgement.IsValid", "model.post_acknowledgement.is_valid.post_id.app_error", nil, "post_id="+o.PostId, http.StatusBadRequest)
+	}
+
+	if o.AcknowledgedAt == 0 {
+		return NewAppError("PostAcknowledgement.IsValid", "model.post_acknowledgement.is_valid.acknowledged_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostAcknowledgementJson(t *testing.T) {
+	acknowledgement := &PostAcknowledgement{UserId: NewId(), PostId: NewId(), AcknowledgedAt: GetMillis()}
+
+	assert.Equal(t, acknowledgement, PostAcknowledgementFromJson(strings.NewReader(acknowledgement.ToJson())))
+	assert.Equal(t, []*PostAcknowledgement{acknowledgement}, PostAcknowledgementsFromJson(strings.NewReader(PostAcknowledgementsToJson([]*PostAcknowledgement{acknowledgement}))))
+	assert.Nil(t, PostAcknowledgementFromJson(strings.NewReader("junk")))
+}
+
+func TestPostAcknowledgementIsValid(t *testing.T) {
+	acknowledgement := PostAcknowledgement{UserId: NewId(), PostId: NewId()}
+	assert.NotNil(t, acknowledgement.IsValid())
+
+	acknowledgement.PreSave()
+	assert.Nil(t, acknowledgement.IsValid())
+
+	acknowledgement.UserId = "junk"
+	assert.NotNil(t, acknowledgement.IsValid())
+	acknowledgement.UserId = NewId()
+
+	acknowledgement.PostId = ""
+	assert.NotNil(t, acknowledgement.IsValid())
+}
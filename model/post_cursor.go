@@ -0,0 +1,39 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PostCursor identifies a post in a channel's history by its position. Unlike a page number, a cursor keeps pointing
+// at the same place in the channel as new posts are made and old ones are deleted.
+type PostCursor struct {
+	CreateAt int64
+	Id       string
+}
+
+func NewPostCursor(post *Post) *PostCursor {
+	return &PostCursor{CreateAt: post.CreateAt, Id: post.Id}
+}
+
+func (c *PostCursor) String() string {
+	return strconv.FormatInt(c.CreateAt, 10) + ":" + c.Id
+}
+
+// PostCursorFromString parses a cursor returned by PostCursor.String.
+func PostCursorFromString(value string) (*PostCursor, bool) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || !IsValidId(parts[1]) {
+		return nil, false
+	}
+
+	createAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || createAt < 0 {
+		return nil, false
+	}
+
+	return &PostCursor{CreateAt: createAt, Id: parts[1]}, true
+}
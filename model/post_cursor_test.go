@@ -0,0 +1,24 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostCursor(t *testing.T) {
+	cursor := NewPostCursor(&Post{Id: NewId(), CreateAt: 1234})
+
+	parsed, ok := PostCursorFromString(cursor.String())
+	require.True(t, ok)
+	assert.Equal(t, cursor, parsed)
+
+	for _, value := range []string{"", "1234", "abc:" + NewId(), "-1:" + NewId(), "1234:junk"} {
+		_, ok := PostCursorFromString(value)
+		assert.False(t, ok, value)
+	}
+}
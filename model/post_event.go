@@ -0,0 +1,77 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	POST_EVENT_TYPE_CREATED          = "created"
+	POST_EVENT_TYPE_EDITED           = "edited"
+	POST_EVENT_TYPE_DELETED          = "deleted"
+	POST_EVENT_TYPE_REACTION_ADDED   = "reaction_added"
+	POST_EVENT_TYPE_REACTION_REMOVED = "reaction_removed"
+
+	POST_EVENT_DATA_MAX_LENGTH = 65535
+
+	POST_EVENTS_DEFAULT_LIMIT = 100
+	POST_EVENTS_MAX_LIMIT     = 1000
+)
+
+// PostEvent is an entry in the append-only log of changes made to the posts in a channel. Seq only ever increases,
+// so the Seq of the last event that was read can be used as a cursor to continue reading the log later.
+type PostEvent struct {
+	Seq       int64  `json:"seq"`
+	ChannelId string `json:"channel_id"`
+	PostId    string `json:"post_id"`
+	Type      string `json:"type"`
+
+	// Data is the post or reaction as it was after the change. It's empty if it was too large to store, in which
+	// case the post needs to be fetched instead.
+	Data     string `json:"data"`
+	CreateAt int64  `json:"create_at"`
+}
+
+// PostEventList is a page of a channel's post events along with the cursor to read the next page from.
+type PostEventList struct {
+	Events []*PostEvent `json:"events"`
+	Cursor int64        `json:"cursor"`
+}
+
+func NewPostEvent(eventType string, post *Post) *PostEvent {
+	return newPostEvent(eventType, post.ChannelId, post.Id, post.ToJson())
+}
+
+// NewReactionPostEvent returns the event for a reaction being added or removed. Reactions don't know which channel
+// they're in, so the channel is filled in from the post when the event is saved.
+func NewReactionPostEvent(eventType string, reaction *Reaction) *PostEvent {
+	return newPostEvent(eventType, "", reaction.PostId, reaction.ToJson())
+}
+
+func newPostEvent(eventType, channelId, postId, data string) *PostEvent {
+	if len(data) > POST_EVENT_DATA_MAX_LENGTH {
+		data = ""
+	}
+
+	return &PostEvent{
+		ChannelId: channelId,
+		PostId:    postId,
+		Type:      eventType,
+		Data:      data,
+		CreateAt:  GetMillis(),
+	}
+}
+
+func (o *PostEventList) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func PostEventListFromJson(data io.Reader) *PostEventList {
+	var o *PostEventList
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
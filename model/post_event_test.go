@@ -0,0 +1,46 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPostEvent(t *testing.T) {
+	post := &Post{Id: NewId(), ChannelId: NewId(), Message: "hello"}
+
+	event := NewPostEvent(POST_EVENT_TYPE_CREATED, post)
+	assert.Equal(t, post.ChannelId, event.ChannelId)
+	assert.Equal(t, post.Id, event.PostId)
+	assert.Equal(t, post.Id, PostFromJson(strings.NewReader(event.Data)).Id)
+	assert.NotZero(t, event.CreateAt)
+
+	post.Message = strings.Repeat("a", POST_EVENT_DATA_MAX_LENGTH)
+	event = NewPostEvent(POST_EVENT_TYPE_EDITED, post)
+	assert.Empty(t, event.Data, "should leave out data that's too large to store")
+}
+
+func TestNewReactionPostEvent(t *testing.T) {
+	reaction := &Reaction{UserId: NewId(), PostId: NewId(), EmojiName: "smile"}
+
+	event := NewReactionPostEvent(POST_EVENT_TYPE_REACTION_ADDED, reaction)
+	assert.Empty(t, event.ChannelId)
+	assert.Equal(t, reaction.PostId, event.PostId)
+	assert.Equal(t, "smile", ReactionFromJson(strings.NewReader(event.Data)).EmojiName)
+}
+
+func TestPostEventListJson(t *testing.T) {
+	list := &PostEventList{
+		Events: []*PostEvent{{Seq: 5, PostId: NewId(), Type: POST_EVENT_TYPE_DELETED}},
+		Cursor: 5,
+	}
+
+	decoded := PostEventListFromJson(strings.NewReader(list.ToJson()))
+	require.NotNil(t, decoded)
+	assert.Equal(t, list, decoded)
+}
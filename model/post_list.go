@@ -12,6 +12,15 @@ import (
 type PostList struct {
 	Order []string         `json:"order"`
 	Posts map[string]*Post `json:"posts"`
+
+	// FileInfos holds the file infos for the posts in the list, keyed by post id. It's only filled in when the list is
+	// being returned to a client.
+	FileInfos map[string][]*FileInfo `json:"file_infos,omitempty"`
+
+	// BeforeCursor and AfterCursor are passed back to get the posts that are older or newer than the ones in the list.
+	// BeforeCursor is empty once the start of the channel has been reached.
+	BeforeCursor string `json:"before_cursor,omitempty"`
+	AfterCursor  string `json:"after_cursor,omitempty"`
 }
 
 func NewPostList() *PostList {
@@ -21,6 +30,25 @@ func NewPostList() *PostList {
 	}
 }
 
+// SetCursors points the list's cursors at its oldest and newest posts. hasOlder is false when the list starts at the
+// beginning of the channel.
+func (o *PostList) SetCursors(hasOlder bool) {
+	o.BeforeCursor = ""
+	o.AfterCursor = ""
+
+	if len(o.Order) == 0 {
+		return
+	}
+
+	if oldest, ok := o.Posts[o.Order[len(o.Order)-1]]; ok && hasOlder {
+		o.BeforeCursor = NewPostCursor(oldest).String()
+	}
+
+	if newest, ok := o.Posts[o.Order[0]]; ok {
+		o.AfterCursor = NewPostCursor(newest).String()
+	}
+}
+
 func (o *PostList) WithRewrittenImageURLs(f func(string) string) *PostList {
 	copy := *o
 	copy.Posts = make(map[string]*Post)
@@ -90,6 +118,23 @@ func (o *PostList) Extend(other *PostList) {
 			o.AddOrder(postId)
 		}
 	}
+
+	for postId, infos := range other.FileInfos {
+		if _, ok := o.FileInfos[postId]; !ok {
+			o.AddFileInfos(infos)
+		}
+	}
+}
+
+// AddFileInfos adds file infos to the list, grouping them by the posts that they're attached to.
+func (o *PostList) AddFileInfos(infos []*FileInfo) {
+	if o.FileInfos == nil {
+		o.FileInfos = make(map[string][]*FileInfo)
+	}
+
+	for _, info := range infos {
+		o.FileInfos[info.PostId] = append(o.FileInfos[info.PostId], info)
+	}
 }
 
 func (o *PostList) SortByCreateAt() {
@@ -71,6 +71,34 @@ func TestPostListExtend(t *testing.T) {
 	}
 }
 
+func TestPostListFileInfos(t *testing.T) {
+	l1 := PostList{}
+
+	p1 := &Post{Id: NewId(), Message: NewId()}
+	l1.AddPost(p1)
+	l1.AddOrder(p1.Id)
+	l1.AddFileInfos([]*FileInfo{{Id: NewId(), PostId: p1.Id}, {Id: NewId(), PostId: p1.Id}})
+
+	assert.Len(t, l1.FileInfos[p1.Id], 2)
+
+	l2 := PostList{}
+
+	p2 := &Post{Id: NewId(), Message: NewId()}
+	l2.AddPost(p2)
+	l2.AddOrder(p2.Id)
+	l2.AddFileInfos([]*FileInfo{{Id: NewId(), PostId: p2.Id}})
+
+	l2.Extend(&l1)
+	l2.Extend(&l1)
+
+	assert.Len(t, l2.FileInfos[p1.Id], 2)
+	assert.Len(t, l2.FileInfos[p2.Id], 1)
+	assert.Len(t, l1.FileInfos, 1, "extending l2 changed l1")
+
+	assert.Equal(t, l2.FileInfos, PostListFromJson(strings.NewReader(l2.ToJson())).FileInfos)
+	assert.NotContains(t, (&PostList{}).ToJson(), "file_infos")
+}
+
 func TestPostListSortByCreateAt(t *testing.T) {
 	pl := PostList{}
 	p1 := &Post{Id: NewId(), Message: NewId(), CreateAt: 2}
@@ -90,3 +118,28 @@ func TestPostListSortByCreateAt(t *testing.T) {
 	assert.EqualValues(t, pl.Order[1], p1.Id)
 	assert.EqualValues(t, pl.Order[2], p2.Id)
 }
+
+func TestPostListSetCursors(t *testing.T) {
+	newest := &Post{Id: NewId(), CreateAt: 2}
+	oldest := &Post{Id: NewId(), CreateAt: 1}
+
+	pl := NewPostList()
+	pl.AddPost(newest)
+	pl.AddOrder(newest.Id)
+	pl.AddPost(oldest)
+	pl.AddOrder(oldest.Id)
+
+	pl.SetCursors(true)
+	assert.Equal(t, NewPostCursor(oldest).String(), pl.BeforeCursor)
+	assert.Equal(t, NewPostCursor(newest).String(), pl.AfterCursor)
+
+	pl.SetCursors(false)
+	assert.Empty(t, pl.BeforeCursor)
+	assert.Equal(t, NewPostCursor(newest).String(), pl.AfterCursor)
+
+	empty := NewPostList()
+	empty.SetCursors(true)
+	assert.Empty(t, empty.BeforeCursor)
+	assert.Empty(t, empty.AfterCursor)
+	assert.NotContains(t, empty.ToJson(), "cursor")
+}
@@ -145,6 +145,42 @@ func TestPostPreSave(t *testing.T) {
 	o.Etag()
 }
 
+func TestPostPriority(t *testing.T) {
+	o := Post{Id: NewId(), CreateAt: GetMillis(), UpdateAt: GetMillis(), UserId: NewId(), ChannelId: NewId()}
+	assert.Equal(t, "", o.GetPriority())
+	assert.False(t, o.IsUrgent())
+	assert.False(t, o.IsAckRequested())
+
+	o.AddProp(POST_PROPS_PRIORITY, POST_PRIORITY_URGENT)
+	o.AddProp(POST_PROPS_REQUESTED_ACK, true)
+	assert.Nil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2))
+	assert.True(t, o.IsUrgent())
+	assert.True(t, o.IsAckRequested())
+
+	o.AddProp(POST_PROPS_PRIORITY, POST_PRIORITY_IMPORTANT)
+	assert.Nil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2))
+	assert.Equal(t, POST_PRIORITY_IMPORTANT, o.GetPriority())
+	assert.False(t, o.IsUrgent())
+
+	o.AddProp(POST_PROPS_PRIORITY, "critical")
+	assert.NotNil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2))
+	o.AddProp(POST_PROPS_PRIORITY, POST_PRIORITY_URGENT)
+
+	o.AddProp(POST_PROPS_REQUESTED_ACK, "true")
+	assert.NotNil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2))
+	assert.False(t, o.IsAckRequested())
+	o.AddProp(POST_PROPS_REQUESTED_ACK, true)
+
+	o.RootId = NewId()
+	assert.NotNil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2), "replies shouldn't have a priority")
+
+	delete(o.Props, POST_PROPS_PRIORITY)
+	assert.NotNil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2), "replies shouldn't request acknowledgement")
+
+	o.AddProp(POST_PROPS_REQUESTED_ACK, false)
+	assert.Nil(t, o.IsValid(POST_MESSAGE_MAX_RUNES_V2))
+}
+
 func TestPostIsSystemMessage(t *testing.T) {
 	post1 := Post{Message: "test_1"}
 	post1.PreSave()
@@ -37,6 +37,11 @@ const (
 
 	PREFERENCE_EMAIL_INTERVAL_NO_BATCHING_SECONDS = "30"  // the "immediate" setting is actually 30s
 	PREFERENCE_EMAIL_INTERVAL_BATCHING_SECONDS    = "900" // fifteen minutes is 900 seconds
+	PREFERENCE_EMAIL_INTERVAL_HOUR_SECONDS        = "3600"
+	PREFERENCE_EMAIL_INTERVAL_DAY_SECONDS         = "86400"
+
+	PREFERENCE_CATEGORY_USER_LIFECYCLE  = "user_lifecycle"
+	PREFERENCE_NAME_INACTIVE_FLAGGED_AT = "inactive_flagged_at"
 )
 
 type Preference struct {
@@ -57,6 +62,12 @@ func PreferenceFromJson(data io.Reader) *Preference {
 	return o
 }
 
+// IsServerManaged returns true if the preference is kept by the server for its own bookkeeping, such as when the user
+// was flagged as inactive, so users must not be allowed to change it.
+func (o *Preference) IsServerManaged() bool {
+	return o.Category == PREFERENCE_CATEGORY_USER_LIFECYCLE
+}
+
 func (o *Preference) IsValid() *AppError {
 	if len(o.UserId) != 26 {
 		return NewAppError("Preference.IsValid", "model.preference.is_valid.id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
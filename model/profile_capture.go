@@ -0,0 +1,95 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	PROFILE_CAPTURE_CPU       = "cpu"
+	PROFILE_CAPTURE_HEAP      = "heap"
+	PROFILE_CAPTURE_GOROUTINE = "goroutine"
+
+	PROFILE_CAPTURE_DEFAULT_DURATION_SECONDS = 30
+	PROFILE_CAPTURE_MAX_DURATION_SECONDS     = 5 * 60
+
+	// Captured profiles are kept in the file store under this directory, in a directory named after the capture's job
+	PROFILE_CAPTURE_DIRECTORY = "profiles/"
+
+	// The keys of the data of a profile capture's job
+	PROFILE_CAPTURE_DATA_PROFILES         = "profiles"
+	PROFILE_CAPTURE_DATA_DURATION_SECONDS = "duration_seconds"
+	PROFILE_CAPTURE_DATA_NODE_ID          = "node_id"
+	PROFILE_CAPTURE_DATA_STARTED_BY       = "started_by"
+)
+
+// ProfileCaptures are all of the profiles that can be captured, in the order that they're captured.
+var ProfileCaptures = []string{
+	PROFILE_CAPTURE_CPU,
+	PROFILE_CAPTURE_HEAP,
+	PROFILE_CAPTURE_GOROUTINE,
+}
+
+// ProfileCaptureRequest chooses which profiles are captured. The CPU profile covers the whole duration, while the
+// others are taken once it's over. Every profile is captured if none are chosen.
+type ProfileCaptureRequest struct {
+	Profiles        []string `json:"profiles"`
+	DurationSeconds int      `json:"duration_seconds"`
+}
+
+func (o *ProfileCaptureRequest) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ProfileCaptureRequestFromJson(data io.Reader) *ProfileCaptureRequest {
+	var o *ProfileCaptureRequest
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *ProfileCaptureRequest) IsValid() *AppError {
+	seen := map[string]bool{}
+	for _, profile := range o.Profiles {
+		if !IsValidProfileCapture(profile) || seen[profile] {
+			return NewAppError("ProfileCaptureRequest.IsValid", "model.profile_capture.is_valid.profile.app_error", nil, "profile="+profile, http.StatusBadRequest)
+		}
+		seen[profile] = true
+	}
+
+	if o.DurationSeconds < 0 || o.DurationSeconds > PROFILE_CAPTURE_MAX_DURATION_SECONDS {
+		return NewAppError("ProfileCaptureRequest.IsValid", "model.profile_capture.is_valid.duration.app_error", map[string]interface{}{"Max": PROFILE_CAPTURE_MAX_DURATION_SECONDS}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsValidProfileCapture returns true if the profile is one that can be captured.
+func IsValidProfileCapture(profile string) bool {
+	for _, valid := range ProfileCaptures {
+		if profile == valid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProfileCapturePath returns where a captured profile is kept in the file store.
+func ProfileCapturePath(jobId string, profile string) string {
+	return PROFILE_CAPTURE_DIRECTORY + jobId + "/" + profile + ".pprof"
+}
+
+// CapturedProfiles returns the profiles that were captured by a profile capture's job.
+func (j *Job) CapturedProfiles() []string {
+	if j.Data[PROFILE_CAPTURE_DATA_PROFILES] == "" {
+		return nil
+	}
+
+	return strings.Split(j.Data[PROFILE_CAPTURE_DATA_PROFILES], ",")
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileCaptureRequestIsValid(t *testing.T) {
+	assert.Nil(t, (&ProfileCaptureRequest{}).IsValid())
+	assert.Nil(t, (&ProfileCaptureRequest{Profiles: []string{PROFILE_CAPTURE_HEAP, PROFILE_CAPTURE_CPU}, DurationSeconds: 10}).IsValid())
+	assert.Nil(t, (&ProfileCaptureRequest{DurationSeconds: PROFILE_CAPTURE_MAX_DURATION_SECONDS}).IsValid())
+
+	assert.NotNil(t, (&ProfileCaptureRequest{Profiles: []string{"block"}}).IsValid())
+	assert.NotNil(t, (&ProfileCaptureRequest{Profiles: []string{PROFILE_CAPTURE_CPU, PROFILE_CAPTURE_CPU}}).IsValid())
+	assert.NotNil(t, (&ProfileCaptureRequest{DurationSeconds: -1}).IsValid())
+	assert.NotNil(t, (&ProfileCaptureRequest{DurationSeconds: PROFILE_CAPTURE_MAX_DURATION_SECONDS + 1}).IsValid())
+}
+
+func TestJobCapturedProfiles(t *testing.T) {
+	job := &Job{Type: JOB_TYPE_PROFILE_CAPTURE, Data: map[string]string{}}
+	assert.Empty(t, job.CapturedProfiles())
+
+	job.Data[PROFILE_CAPTURE_DATA_PROFILES] = "cpu,goroutine"
+	assert.Equal(t, []string{PROFILE_CAPTURE_CPU, PROFILE_CAPTURE_GOROUTINE}, job.CapturedProfiles())
+
+	assert.Equal(t, "profiles/abc/heap.pprof", ProfileCapturePath("abc", PROFILE_CAPTURE_HEAP))
+}
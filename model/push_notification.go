@@ -46,6 +46,7 @@ type PushNotification struct {
 	OverrideIconUrl  string `json:"override_icon_url"`
 	FromWebhook      string `json:"from_webhook"`
 	Version          string `json:"version"`
+	IsIdLoaded       bool   `json:"is_id_loaded"`
 }
 
 func (me *PushNotification) ToJson() string {
@@ -0,0 +1,36 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ReadReceipt records how far a member has read a channel. The member has seen every post in the channel that was
+// created at or before LastViewedAt.
+type ReadReceipt struct {
+	ChannelId    string `json:"channel_id"`
+	UserId       string `json:"user_id"`
+	LastViewedAt int64  `json:"last_viewed_at"`
+}
+
+// HasSeen returns true if the member had read the channel up to the given post.
+func (o *ReadReceipt) HasSeen(post *Post) bool {
+	return o.LastViewedAt >= post.CreateAt
+}
+
+func ReadReceiptsToJson(o []*ReadReceipt) string {
+	if b, err := json.Marshal(o); err != nil {
+		return "[]"
+	} else {
+		return string(b)
+	}
+}
+
+func ReadReceiptsFromJson(data io.Reader) []*ReadReceipt {
+	var o []*ReadReceipt
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadReceiptHasSeen(t *testing.T) {
+	receipt := &ReadReceipt{ChannelId: NewId(), UserId: NewId(), LastViewedAt: 1000}
+
+	assert.True(t, receipt.HasSeen(&Post{CreateAt: 999}))
+	assert.True(t, receipt.HasSeen(&Post{CreateAt: 1000}))
+	assert.False(t, receipt.HasSeen(&Post{CreateAt: 1001}))
+}
+
+func TestReadReceiptsJson(t *testing.T) {
+	receipts := []*ReadReceipt{{ChannelId: NewId(), UserId: NewId(), LastViewedAt: 1000}}
+
+	assert.Equal(t, receipts, ReadReceiptsFromJson(strings.NewReader(ReadReceiptsToJson(receipts))))
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	REMINDER_MESSAGE_MAX_RUNES = 4000
+	REMINDER_MAX_PER_USER      = 100
+
+	// The hour of the day that reminders set for "tomorrow" without a time are delivered at.
+	REMINDER_DEFAULT_HOUR = 9
+
+	REMINDER_TARGET_ME   = "me"
+	REMINDER_TARGET_HERE = "here"
+
+	POST_PROPS_REMINDER_ID = "reminder_id"
+)
+
+// Reminder is a message that will be posted to a channel at a later time on behalf of the user who set it. Reminders
+// that users set for themselves are posted to their direct message channel with themselves.
+type Reminder struct {
+	Id        string `json:"id"`
+	CreateAt  int64  `json:"create_at"`
+	UserId    string `json:"user_id"`
+	ChannelId string `json:"channel_id"`
+	Message   string `json:"message"`
+	RemindAt  int64  `json:"remind_at"`
+}
+
+func (o *Reminder) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ReminderFromJson(data io.Reader) *Reminder {
+	var o *Reminder
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func RemindersToJson(o []*Reminder) string {
+	if b, err := json.Marshal(o); err != nil {
+		return "[]"
+	} else {
+		return string(b)
+	}
+}
+
+func RemindersFromJson(data io.Reader) []*Reminder {
+	var o []*Reminder
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *Reminder) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.CreateAt = GetMillis()
+}
+
+func (o *Reminder) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.ChannelId) {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Message == "" || utf8.RuneCountInString(o.Message) > REMINDER_MESSAGE_MAX_RUNES {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.message.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.RemindAt <= o.CreateAt {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.remind_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// ReminderRequest is a reminder as it was written in a /remind command.
+type ReminderRequest struct {
+	// Target is "me", "here" for the channel that the command was used in, or the name of a channel.
+	Target   string
+	Message  string
+	RemindAt time.Time
+}
+
+var reminderClockTime = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+
+var reminderUnits = map[string]time.Duration{
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+}
+
+// ParseReminderRequest parses the text of a /remind command, e.g. "me to call Bob in 2 hours", "~town-square stand up
+// at 9:30am tomorrow" or "here deploy tomorrow". Times of day are read in now's location and refer to the next time
+// that the clock reaches them.
+func ParseReminderRequest(text string, now time.Time) (*ReminderRequest, bool) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return nil, false
+	}
+
+	request := &ReminderRequest{}
+
+	switch target := strings.ToLower(fields[0]); {
+	case target == REMINDER_TARGET_ME, target == REMINDER_TARGET_HERE:
+		request.Target = target
+	case strings.HasPrefix(target, "~") && len(target) > 1:
+		request.Target = target[1:]
+	default:
+		return nil, false
+	}
+
+	fields = fields[1:]
+	if strings.ToLower(fields[0]) == "to" {
+		fields = fields[1:]
+	}
+
+	remindAt, remaining, ok := parseReminderTime(fields, now)
+	if !ok || len(remaining) == 0 {
+		return nil, false
+	}
+
+	request.Message = strings.Join(remaining, " ")
+	request.RemindAt = remindAt
+
+	return request, true
+}
+
+// parseReminderTime reads the time at the end of a reminder and returns it along with the fields before it.
+func parseReminderTime(fields []string, now time.Time) (time.Time, []string, bool) {
+	n := len(fields)
+	lower := func(i int) string {
+		return strings.ToLower(fields[i])
+	}
+
+	// "in 5 minutes"
+	if n >= 3 && lower(n-3) == "in" {
+		if unit, ok := reminderUnits[lower(n-1)]; ok {
+			if amount, err := strconv.Atoi(fields[n-2]); err == nil && amount > 0 {
+				return now.Add(time.Duration(amount) * unit), fields[:n-3], true
+			}
+		}
+		return time.Time{}, nil, false
+	}
+
+	tomorrow := false
+	if lower(n-1) == "tomorrow" {
+		tomorrow = true
+		n--
+	}
+
+	// "at 3pm", "at 15:30" or "at 9:30am tomorrow"
+	if n >= 2 && lower(n-2) == "at" {
+		hour, minute, ok := parseReminderClockTime(lower(n - 1))
+		if !ok {
+			return time.Time{}, nil, false
+		}
+
+		remindAt := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if tomorrow || !remindAt.After(now) {
+			remindAt = remindAt.AddDate(0, 0, 1)
+		}
+
+		return remindAt, fields[:n-2], true
+	}
+
+	// "tomorrow"
+	if tomorrow {
+		remindAt := time.Date(now.Year(), now.Month(), now.Day()+1, REMINDER_DEFAULT_HOUR, 0, 0, 0, now.Location())
+		return remindAt, fields[:n], true
+	}
+
+	return time.Time{}, nil, false
+}
+
+func parseReminderClockTime(value string) (int, int, bool) {
+	match := reminderClockTime.FindStringSubmatch(value)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+
+		hour = hour % 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if match[2] == "" || hour > 23 {
+			return 0, 0, false
+		}
+	}
+
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	return hour, minute, true
+}
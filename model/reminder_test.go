@@ -0,0 +1,113 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderIsValid(t *testing.T) {
+	reminder := &Reminder{UserId: NewId(), ChannelId: NewId(), Message: "call Bob"}
+	reminder.PreSave()
+	reminder.RemindAt = reminder.CreateAt + 60000
+	require.Nil(t, reminder.IsValid())
+
+	reminder.RemindAt = reminder.CreateAt
+	assert.NotNil(t, reminder.IsValid(), "should be in the future")
+
+	reminder.RemindAt = reminder.CreateAt + 60000
+	reminder.Message = ""
+	assert.NotNil(t, reminder.IsValid())
+
+	reminder.Message = strings.Repeat("a", REMINDER_MESSAGE_MAX_RUNES+1)
+	assert.NotNil(t, reminder.IsValid())
+
+	reminder.Message = "call Bob"
+	reminder.ChannelId = "junk"
+	assert.NotNil(t, reminder.IsValid())
+}
+
+func TestRemindersJson(t *testing.T) {
+	reminders := []*Reminder{{Id: NewId(), UserId: NewId(), ChannelId: NewId(), Message: "call Bob", CreateAt: 1, RemindAt: 2}}
+
+	assert.Equal(t, reminders, RemindersFromJson(strings.NewReader(RemindersToJson(reminders))))
+	assert.Equal(t, reminders[0], ReminderFromJson(strings.NewReader(reminders[0].ToJson())))
+}
+
+func TestParseReminderRequest(t *testing.T) {
+	location := time.FixedZone("test", -5*60*60)
+	now := time.Date(2018, 7, 2, 14, 0, 0, 0, location)
+
+	for text, expected := range map[string]*ReminderRequest{
+		"me to call Bob in 2 hours": {
+			Target:   REMINDER_TARGET_ME,
+			Message:  "call Bob",
+			RemindAt: now.Add(2 * time.Hour),
+		},
+		"Me stretch in 1 min": {
+			Target:   REMINDER_TARGET_ME,
+			Message:  "stretch",
+			RemindAt: now.Add(time.Minute),
+		},
+		"~town-square stand up at 9:30am tomorrow": {
+			Target:   "town-square",
+			Message:  "stand up",
+			RemindAt: time.Date(2018, 7, 3, 9, 30, 0, 0, location),
+		},
+		"here deploy at 3pm": {
+			Target:   REMINDER_TARGET_HERE,
+			Message:  "deploy",
+			RemindAt: time.Date(2018, 7, 2, 15, 0, 0, 0, location),
+		},
+		"here deploy at 13:15": {
+			Target:   REMINDER_TARGET_HERE,
+			Message:  "deploy",
+			RemindAt: time.Date(2018, 7, 3, 13, 15, 0, 0, location),
+		},
+		"here lunch at 12am": {
+			Target:   REMINDER_TARGET_HERE,
+			Message:  "lunch",
+			RemindAt: time.Date(2018, 7, 3, 0, 0, 0, 0, location),
+		},
+		"me to water the plants tomorrow": {
+			Target:   REMINDER_TARGET_ME,
+			Message:  "water the plants",
+			RemindAt: time.Date(2018, 7, 3, REMINDER_DEFAULT_HOUR, 0, 0, 0, location),
+		},
+		"me to review the release in 1 week": {
+			Target:   REMINDER_TARGET_ME,
+			Message:  "review the release",
+			RemindAt: now.AddDate(0, 0, 7),
+		},
+	} {
+		t.Run(text, func(t *testing.T) {
+			request, ok := ParseReminderRequest(text, now)
+			require.True(t, ok)
+			assert.Equal(t, expected.Target, request.Target)
+			assert.Equal(t, expected.Message, request.Message)
+			assert.True(t, expected.RemindAt.Equal(request.RemindAt), "expected %v, got %v", expected.RemindAt, request.RemindAt)
+		})
+	}
+
+	for _, text := range []string{
+		"",
+		"me in 5 minutes",
+		"me to call Bob",
+		"bob to call me in 5 minutes",
+		"me to call Bob in -5 minutes",
+		"me to call Bob in 5 fortnights",
+		"me to call Bob at 25:00",
+		"me to call Bob at 13pm",
+		"me to call Bob at 9",
+		"~ to call Bob tomorrow",
+	} {
+		_, ok := ParseReminderRequest(text, now)
+		assert.False(t, ok, text)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	REMOTE_ID_SEPARATOR          = ":"
+	REMOTE_ID_MAX_LENGTH         = 128
+	REMOTE_ID_SERVICE_MAX_LENGTH = 32
+)
+
+var validRemoteIdService = regexp.MustCompile(`^[a-z0-9_\-]+$`)
+
+// NewRemoteId returns the remote id that maps an object to its counterpart in an external system, such as a message
+// bridged from Slack. Remote ids are stored as the name of the service and the id in that service separated by a colon.
+func NewRemoteId(service string, externalId string) string {
+	return strings.ToLower(service) + REMOTE_ID_SEPARATOR + externalId
+}
+
+// ParseRemoteId splits a remote id into the name of the service and the id in that service.
+func ParseRemoteId(remoteId string) (string, string, bool) {
+	parts := strings.SplitN(remoteId, REMOTE_ID_SEPARATOR, 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// IsValidRemoteIdService returns true if the name of the external service is made up of lower case letters, numbers,
+// dashes and underscores.
+func IsValidRemoteIdService(service string) bool {
+	return len(service) <= REMOTE_ID_SERVICE_MAX_LENGTH && validRemoteIdService.MatchString(service)
+}
+
+// IsValidRemoteId returns true if a remote id is either unset or names a valid service and a non-empty external id.
+func IsValidRemoteId(remoteId *string) bool {
+	if remoteId == nil {
+		return true
+	}
+
+	if len(*remoteId) > REMOTE_ID_MAX_LENGTH {
+		return false
+	}
+
+	service, externalId, ok := ParseRemoteId(*remoteId)
+
+	return ok && IsValidRemoteIdService(service) && len(externalId) > 0
+}
+
+// patchRemoteId applies a remote id from a patch, where an empty string clears it.
+func patchRemoteId(remoteId *string) *string {
+	if *remoteId == "" {
+		return nil
+	}
+
+	return NewString(*remoteId)
+}
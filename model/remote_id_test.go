@@ -0,0 +1,38 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteId(t *testing.T) {
+	remoteId := NewRemoteId("Slack", "C024BE91L:1355517523.000005")
+	assert.Equal(t, "slack:C024BE91L:1355517523.000005", remoteId)
+
+	service, externalId, ok := ParseRemoteId(remoteId)
+	assert.True(t, ok)
+	assert.Equal(t, "slack", service)
+	assert.Equal(t, "C024BE91L:1355517523.000005", externalId)
+
+	_, _, ok = ParseRemoteId("slack")
+	assert.False(t, ok)
+}
+
+func TestIsValidRemoteId(t *testing.T) {
+	assert.True(t, IsValidRemoteId(nil))
+	assert.True(t, IsValidRemoteId(NewString("jira:PROJ-123")))
+	assert.True(t, IsValidRemoteId(NewString("ms-teams:19:abc@thread.skype")))
+
+	assert.False(t, IsValidRemoteId(NewString("")))
+	assert.False(t, IsValidRemoteId(NewString("jira")))
+	assert.False(t, IsValidRemoteId(NewString("jira:")))
+	assert.False(t, IsValidRemoteId(NewString(":PROJ-123")))
+	assert.False(t, IsValidRemoteId(NewString("Jira:PROJ-123")))
+	assert.False(t, IsValidRemoteId(NewString(strings.Repeat("a", REMOTE_ID_SERVICE_MAX_LENGTH+1)+":1")))
+	assert.False(t, IsValidRemoteId(NewString("jira:"+strings.Repeat("1", REMOTE_ID_MAX_LENGTH))))
+}
@@ -331,6 +331,7 @@ func MakeDefaultRoles() map[string]*Role {
 							PERMISSION_CREATE_PRIVATE_CHANNEL.Id,
 							PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH.Id,
 							PERMISSION_MANAGE_OTHERS_WEBHOOKS.Id,
+							PERMISSION_MANAGE_PRIVATE_OUTGOING_WEBHOOKS.Id,
 							PERMISSION_EDIT_OTHER_USERS.Id,
 							PERMISSION_MANAGE_OAUTH.Id,
 							PERMISSION_INVITE_USER.Id,
@@ -340,6 +341,9 @@ func MakeDefaultRoles() map[string]*Role {
 							PERMISSION_ADD_USER_TO_TEAM.Id,
 							PERMISSION_LIST_USERS_WITHOUT_TEAM.Id,
 							PERMISSION_MANAGE_JOBS.Id,
+							PERMISSION_CREATE_BOT.Id,
+							PERMISSION_MANAGE_BOTS.Id,
+							PERMISSION_MANAGE_OTHERS_BOTS.Id,
 							PERMISSION_CREATE_POST_PUBLIC.Id,
 							PERMISSION_CREATE_POST_EPHEMERAL.Id,
 							PERMISSION_CREATE_USER_ACCESS_TOKEN.Id,
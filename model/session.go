@@ -18,6 +18,7 @@ const (
 	SESSION_PROP_BROWSER              = "browser"
 	SESSION_PROP_TYPE                 = "type"
 	SESSION_PROP_USER_ACCESS_TOKEN_ID = "user_access_token_id"
+	SESSION_PROP_OAUTH_SCOPE          = "oauth_scope"
 	SESSION_TYPE_USER_ACCESS_TOKEN    = "UserAccessToken"
 	SESSION_ACTIVITY_TIMEOUT          = 1000 * 60 * 5 // 5 minutes
 	SESSION_USER_ACCESS_TOKEN_EXPIRY  = 100 * 365     // 100 years
@@ -117,6 +118,16 @@ func (me *Session) AddProp(key string, value string) {
 	me.Props[key] = value
 }
 
+// HasOAuthScope returns true if the session can be used for requests that need a scope. Only sessions of OAuth access
+// tokens are limited, and tokens that were granted before apps could request scopes have full access.
+func (me *Session) HasOAuthScope(scope string) bool {
+	if !me.IsOAuth {
+		return true
+	}
+
+	return OAuthScopeIncludes(me.Props[SESSION_PROP_OAUTH_SCOPE], scope)
+}
+
 func (me *Session) GetTeamByTeamId(teamId string) *TeamMember {
 	for _, team := range me.TeamMembers {
 		if team.TeamId == teamId {
@@ -78,3 +78,23 @@ func TestSessionCSRF(t *testing.T) {
 }
 
 
+
+func TestSessionHasOAuthScope(t *testing.T) {
+	session := Session{}
+	if !session.HasOAuthScope(OAUTH_SCOPE_WRITE_POSTS) {
+		t.Fatal("sessions that aren't for OAuth apps should have every scope")
+	}
+
+	session.IsOAuth = true
+	if !session.HasOAuthScope(OAUTH_SCOPE_WRITE_POSTS) {
+		t.Fatal("sessions from before scopes were supported should have every scope")
+	}
+
+	session.AddProp(SESSION_PROP_OAUTH_SCOPE, OAUTH_SCOPE_READ_POSTS)
+	if !session.HasOAuthScope(OAUTH_SCOPE_READ_POSTS) {
+		t.Fatal("should have the granted scope")
+	}
+	if session.HasOAuthScope(OAUTH_SCOPE_WRITE_POSTS) {
+		t.Fatal("should not have scopes that weren't granted")
+	}
+}
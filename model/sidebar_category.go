@@ -0,0 +1,116 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES = 64
+	SIDEBAR_CATEGORY_MAX_CHANNELS           = 100
+
+	// Users can't have more categories than this in each team
+	SIDEBAR_CATEGORY_MAX_PER_TEAM = 50
+)
+
+// SidebarCategory is a section of a user's channel sidebar in a team. Categories are shown in order of SortOrder, and
+// list the channels in them in the order of ChannelIds. They're part of how a user organizes their channels, so
+// they're only available when channel organization is enabled.
+type SidebarCategory struct {
+	Id          string      `json:"id"`
+	CreateAt    int64       `json:"create_at"`
+	UpdateAt    int64       `json:"update_at"`
+	UserId      string      `json:"user_id"`
+	TeamId      string      `json:"team_id"`
+	DisplayName string      `json:"display_name"`
+	SortOrder   int64       `json:"sort_order"`
+	ChannelIds  StringArray `json:"channel_ids"`
+}
+
+func (o *SidebarCategory) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func SidebarCategoryFromJson(data io.Reader) *SidebarCategory {
+	var o *SidebarCategory
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func SidebarCategoryListToJson(l []*SidebarCategory) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func SidebarCategoryListFromJson(data io.Reader) []*SidebarCategory {
+	var o []*SidebarCategory
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *SidebarCategory) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.DisplayName == "" || utf8.RuneCountInString(o.DisplayName) > SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.display_name.app_error", map[string]interface{}{"Max": SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.ChannelIds) > SIDEBAR_CATEGORY_MAX_CHANNELS {
+		return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.too_many_channels.app_error", map[string]interface{}{"Max": SIDEBAR_CATEGORY_MAX_CHANNELS}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	seen := make(map[string]bool, len(o.ChannelIds))
+	for _, channelId := range o.ChannelIds {
+		if len(channelId) != 26 || seen[channelId] {
+			return NewAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.channel_ids.app_error", nil, "id="+o.Id+", channel_id="+channelId, http.StatusBadRequest)
+		}
+		seen[channelId] = true
+	}
+
+	return nil
+}
+
+func (o *SidebarCategory) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.ChannelIds == nil {
+		o.ChannelIds = StringArray{}
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+}
+
+func (o *SidebarCategory) PreUpdate() {
+	if o.ChannelIds == nil {
+		o.ChannelIds = StringArray{}
+	}
+
+	o.UpdateAt = GetMillis()
+}
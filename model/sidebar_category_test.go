@@ -0,0 +1,60 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSidebarCategoryJson(t *testing.T) {
+	category := &SidebarCategory{Id: NewId(), DisplayName: "Support Tickets", ChannelIds: StringArray{NewId()}}
+	assert.Equal(t, category, SidebarCategoryFromJson(strings.NewReader(category.ToJson())))
+
+	categories := []*SidebarCategory{category}
+	assert.Equal(t, categories, SidebarCategoryListFromJson(strings.NewReader(SidebarCategoryListToJson(categories))))
+}
+
+func TestSidebarCategoryIsValid(t *testing.T) {
+	category := &SidebarCategory{
+		UserId:      NewId(),
+		TeamId:      NewId(),
+		DisplayName: "Support Tickets",
+	}
+	category.PreSave()
+	require.Nil(t, category.IsValid())
+	assert.NotNil(t, category.ChannelIds)
+
+	category.DisplayName = ""
+	assert.NotNil(t, category.IsValid())
+
+	category.DisplayName = strings.Repeat("a", SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES+1)
+	assert.NotNil(t, category.IsValid())
+
+	category.DisplayName = strings.Repeat("あ", SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES)
+	assert.Nil(t, category.IsValid())
+
+	channelId := NewId()
+	category.ChannelIds = StringArray{channelId, NewId()}
+	assert.Nil(t, category.IsValid())
+
+	category.ChannelIds = StringArray{channelId, channelId}
+	assert.NotNil(t, category.IsValid(), "shouldn't allow a channel twice")
+
+	category.ChannelIds = StringArray{"junk"}
+	assert.NotNil(t, category.IsValid())
+
+	category.ChannelIds = make(StringArray, SIDEBAR_CATEGORY_MAX_CHANNELS+1)
+	for i := range category.ChannelIds {
+		category.ChannelIds[i] = NewId()
+	}
+	assert.NotNil(t, category.IsValid())
+
+	category.ChannelIds = nil
+	category.TeamId = "junk"
+	assert.NotNil(t, category.IsValid())
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Users opt in to receiving urgent mentions by SMS with this notify prop
+	SMS_NOTIFY_PROP = "sms"
+
+	// The user's phone number is only stored once it has been verified
+	USER_PROP_PHONE_NUMBER = "phone_number"
+
+	TOKEN_TYPE_PHONE_VERIFICATION = "phone_verification"
+
+	PHONE_VERIFICATION_CODE_LENGTH = 6
+	PHONE_VERIFICATION_EXPIRY_TIME = 1000 * 60 * 10 // 10 minutes
+
+	SMS_MESSAGE_MAX_RUNES = 160
+)
+
+// Phone numbers are stored in E.164 format, such as +15555550123
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// PhoneVerification is stored with the token used to verify a user's phone number.
+type PhoneVerification struct {
+	UserId      string `json:"user_id"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+func (o *PhoneVerification) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func PhoneVerificationFromJson(data string) *PhoneVerification {
+	var o *PhoneVerification
+	json.Unmarshal([]byte(data), &o)
+	return o
+}
+
+// NormalizePhoneNumber removes the spaces and punctuation that people commonly type in phone numbers.
+func NormalizePhoneNumber(phoneNumber string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, phoneNumber)
+}
+
+func IsValidPhoneNumber(phoneNumber string) bool {
+	return phoneNumberPattern.MatchString(phoneNumber)
+}
+
+// NewPhoneVerificationCode returns a random numeric code to be sent to a phone number that's being verified.
+func NewPhoneVerificationCode() string {
+	max := big.NewInt(1)
+	for i := 0; i < PHONE_VERIFICATION_CODE_LENGTH; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		panic(err)
+	}
+
+	return fmt.Sprintf("%0*d", PHONE_VERIFICATION_CODE_LENGTH, n)
+}
+
+// GetPhoneVerificationToken returns the token under which a user's pending phone verification is stored. The code
+// is part of the token so that it can be looked up from the code that the user enters.
+func GetPhoneVerificationToken(userId string, code string) string {
+	hash := sha256.Sum256([]byte(userId + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetPhoneNumber returns the user's verified phone number, or an empty string if they don't have one.
+func (u *User) GetPhoneNumber() string {
+	return u.Props[USER_PROP_PHONE_NUMBER]
+}
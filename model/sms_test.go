@@ -0,0 +1,51 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoneNumber(t *testing.T) {
+	assert.Equal(t, "+15555550123", NormalizePhoneNumber("+1 (555) 555-0123"))
+	assert.Equal(t, "+445555550123", NormalizePhoneNumber("+44.5555.550123"))
+
+	assert.True(t, IsValidPhoneNumber("+15555550123"))
+	assert.False(t, IsValidPhoneNumber("15555550123"))
+	assert.False(t, IsValidPhoneNumber("+05555550123"))
+	assert.False(t, IsValidPhoneNumber("+1555"))
+	assert.False(t, IsValidPhoneNumber("+1555555012345678"))
+	assert.False(t, IsValidPhoneNumber("+1 555 555 0123"))
+}
+
+func TestNewPhoneVerificationCode(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9]{6}$`)
+	for i := 0; i < 100; i++ {
+		assert.Regexp(t, pattern, NewPhoneVerificationCode())
+	}
+}
+
+func TestGetPhoneVerificationToken(t *testing.T) {
+	userId := NewId()
+
+	token := GetPhoneVerificationToken(userId, "123456")
+	assert.Len(t, token, TOKEN_SIZE)
+	assert.Equal(t, token, GetPhoneVerificationToken(userId, "123456"))
+	assert.NotEqual(t, token, GetPhoneVerificationToken(userId, "123457"))
+	assert.NotEqual(t, token, GetPhoneVerificationToken(NewId(), "123456"))
+}
+
+func TestUserClearNonProfileFieldsPhoneNumber(t *testing.T) {
+	props := StringMap{USER_PROP_PHONE_NUMBER: "+15555550123", "other": "value"}
+	user := &User{Props: props}
+
+	user.ClearNonProfileFields()
+
+	assert.Equal(t, "", user.GetPhoneNumber())
+	assert.Equal(t, "value", user.Props["other"])
+	assert.Equal(t, "+15555550123", props[USER_PROP_PHONE_NUMBER], "shouldn't modify the original props")
+}
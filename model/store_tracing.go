@@ -0,0 +1,40 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// StoreMethodStats is how often a store method was called, how long it took and how many rows it returned.
+type StoreMethodStats struct {
+	Method          string  `json:"method"`
+	CallCount       int64   `json:"call_count"`
+	ErrorCount      int64   `json:"error_count"`
+	RowCount        int64   `json:"row_count"`
+	TotalDurationMs float64 `json:"total_duration_ms"`
+	AverageMs       float64 `json:"average_ms"`
+	MaxMs           float64 `json:"max_ms"`
+}
+
+// StoreTracingReport is the calls that a server has made to each store method since the time given by Since, with the
+// methods that took the longest in total first. Enabled is false when MetricsSettings.EnableStoreTracing is off, in
+// which case nothing new is being recorded.
+type StoreTracingReport struct {
+	Enabled bool                `json:"enabled"`
+	Since   int64               `json:"since"`
+	Methods []*StoreMethodStats `json:"methods"`
+}
+
+func (o *StoreTracingReport) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func StoreTracingReportFromJson(data io.Reader) *StoreTracingReport {
+	var o *StoreTracingReport
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
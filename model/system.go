@@ -17,6 +17,8 @@ const (
 	SYSTEM_LAST_COMPLIANCE_TIME   = "LastComplianceTime"
 	SYSTEM_ASYMMETRIC_SIGNING_KEY = "AsymmetricSigningKey"
 	SYSTEM_INSTALLATION_DATE_KEY  = "InstallationDate"
+	SYSTEM_WEB_PUSH_VAPID_KEY     = "WebPushVapidKey"
+	SYSTEM_EVENTS_BOT_USER_ID     = "SystemEventsBotUserId"
 )
 
 type System struct {
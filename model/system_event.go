@@ -0,0 +1,72 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	SYSTEM_EVENT_TYPE_JOB_FAILED       = "job_failed"
+	SYSTEM_EVENT_TYPE_CONFIG_CHANGED   = "config_changed"
+	SYSTEM_EVENT_TYPE_SECURITY         = "security"
+	SYSTEM_EVENT_TYPE_USER_DEACTIVATED = "user_deactivated"
+
+	SYSTEM_EVENTS_BOT_USERNAME = "system-events"
+
+	POST_PROPS_SYSTEM_EVENT_TYPE = "system_event_type"
+)
+
+// SystemEvent is something that happened on the server that administrators should know about, posted to the
+// configured system events channel.
+type SystemEvent struct {
+	Type   string
+	Title  string
+	Fields []*SlackAttachmentField
+}
+
+func (o *SystemEvent) AddField(title string, value interface{}) {
+	o.Fields = append(o.Fields, &SlackAttachmentField{Title: title, Value: value, Short: true})
+}
+
+// IsEnabled returns whether events of this type should be posted with the given settings.
+func (o *SystemEvent) IsEnabled(settings *SystemEventsSettings) bool {
+	if !*settings.Enable {
+		return false
+	}
+
+	switch o.Type {
+	case SYSTEM_EVENT_TYPE_JOB_FAILED:
+		return *settings.EnableJobFailures
+	case SYSTEM_EVENT_TYPE_CONFIG_CHANGED:
+		return *settings.EnableConfigChanges
+	case SYSTEM_EVENT_TYPE_SECURITY:
+		return *settings.EnableSecurityEvents
+	case SYSTEM_EVENT_TYPE_USER_DEACTIVATED:
+		return *settings.EnableUserDeactivations
+	}
+
+	return false
+}
+
+// ToPost returns the post describing the event, with the event's details laid out as the fields of an attachment.
+func (o *SystemEvent) ToPost(userId, channelId string) *Post {
+	color := "#2389D7"
+	if o.Type == SYSTEM_EVENT_TYPE_JOB_FAILED || o.Type == SYSTEM_EVENT_TYPE_SECURITY {
+		color = "#DB3D3D"
+	}
+
+	post := &Post{
+		UserId:    userId,
+		ChannelId: channelId,
+		Type:      POST_SLACK_ATTACHMENT,
+	}
+	post.AddProp(POST_PROPS_SYSTEM_EVENT_TYPE, o.Type)
+	post.AddProp("attachments", []*SlackAttachment{
+		{
+			Fallback: o.Title,
+			Color:    color,
+			Title:    o.Title,
+			Fields:   o.Fields,
+		},
+	})
+
+	return post
+}
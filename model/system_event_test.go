@@ -0,0 +1,49 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemEventIsEnabled(t *testing.T) {
+	settings := &SystemEventsSettings{}
+	settings.SetDefaults()
+
+	event := &SystemEvent{Type: SYSTEM_EVENT_TYPE_JOB_FAILED}
+	assert.False(t, event.IsEnabled(settings))
+
+	*settings.Enable = true
+	assert.True(t, event.IsEnabled(settings))
+
+	*settings.EnableJobFailures = false
+	assert.False(t, event.IsEnabled(settings))
+	assert.True(t, (&SystemEvent{Type: SYSTEM_EVENT_TYPE_CONFIG_CHANGED}).IsEnabled(settings))
+	assert.True(t, (&SystemEvent{Type: SYSTEM_EVENT_TYPE_SECURITY}).IsEnabled(settings))
+	assert.True(t, (&SystemEvent{Type: SYSTEM_EVENT_TYPE_USER_DEACTIVATED}).IsEnabled(settings))
+	assert.False(t, (&SystemEvent{Type: "unknown"}).IsEnabled(settings))
+}
+
+func TestSystemEventToPost(t *testing.T) {
+	event := &SystemEvent{Type: SYSTEM_EVENT_TYPE_SECURITY, Title: "An account was locked"}
+	event.AddField("User", "@someone")
+
+	userId := NewId()
+	channelId := NewId()
+	post := event.ToPost(userId, channelId)
+
+	assert.Equal(t, userId, post.UserId)
+	assert.Equal(t, channelId, post.ChannelId)
+	assert.Equal(t, POST_SLACK_ATTACHMENT, post.Type)
+	assert.Equal(t, SYSTEM_EVENT_TYPE_SECURITY, post.Props[POST_PROPS_SYSTEM_EVENT_TYPE])
+
+	attachments := post.Attachments()
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "An account was locked", attachments[0].Title)
+	require.Len(t, attachments[0].Fields, 1)
+	assert.Equal(t, "@someone", attachments[0].Fields[0].Value)
+}
@@ -26,21 +26,24 @@ const (
 )
 
 type Team struct {
-	Id                 string  `json:"id"`
-	CreateAt           int64   `json:"create_at"`
-	UpdateAt           int64   `json:"update_at"`
-	DeleteAt           int64   `json:"delete_at"`
-	DisplayName        string  `json:"display_name"`
-	Name               string  `json:"name"`
-	Description        string  `json:"description"`
-	Email              string  `json:"email"`
-	Type               string  `json:"type"`
-	CompanyName        string  `json:"company_name"`
-	AllowedDomains     string  `json:"allowed_domains"`
-	InviteId           string  `json:"invite_id"`
-	AllowOpenInvite    bool    `json:"allow_open_invite"`
-	LastTeamIconUpdate int64   `json:"last_team_icon_update,omitempty"`
-	SchemeId           *string `json:"scheme_id"`
+	Id                 string            `json:"id"`
+	CreateAt           int64             `json:"create_at"`
+	UpdateAt           int64             `json:"update_at"`
+	DeleteAt           int64             `json:"delete_at"`
+	ScheduledDeleteAt  int64             `json:"scheduled_delete_at"`
+	ArchivedAt         int64             `json:"archived_at"`
+	DisplayName        string            `json:"display_name"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description"`
+	Email              string            `json:"email"`
+	Type               string            `json:"type"`
+	CompanyName        string            `json:"company_name"`
+	AllowedDomains     string            `json:"allowed_domains"`
+	InviteId           string            `json:"invite_id"`
+	AllowOpenInvite    bool              `json:"allow_open_invite"`
+	LastTeamIconUpdate int64             `json:"last_team_icon_update,omitempty"`
+	SchemeId           *string           `json:"scheme_id"`
+	UploadPolicy       *TeamUploadPolicy `json:"upload_policy,omitempty"`
 }
 
 type TeamPatch struct {
@@ -166,6 +169,12 @@ func (o *Team) IsValid() *AppError {
 		return NewAppError("Team.IsValid", "model.team.is_valid.domains.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
 
+	if o.UploadPolicy != nil {
+		if err := o.UploadPolicy.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
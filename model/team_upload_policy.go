@@ -0,0 +1,209 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	TEAM_UPLOAD_POLICY_MAX_EXTENSIONS       = 100
+	TEAM_UPLOAD_POLICY_EXTENSION_MAX_LENGTH = 32
+	TEAM_UPLOAD_POLICY_MAX_SIZE             = 4000
+
+	// Reasons that an upload was rejected, which are given with the error so that clients can explain them
+	UPLOAD_REJECTED_EXTENSION_NOT_ALLOWED = "extension_not_allowed"
+	UPLOAD_REJECTED_EXTENSION_BLOCKED     = "extension_blocked"
+	UPLOAD_REJECTED_CONTENT_BLOCKED       = "content_blocked"
+	UPLOAD_REJECTED_CONTENT_MISMATCH      = "content_mismatch"
+	UPLOAD_REJECTED_EXECUTABLE            = "executable"
+)
+
+// Extensions of files that run as programs or scripts when they're opened on common operating systems
+var executableFileExtensions = map[string]bool{
+	"app": true, "apk": true, "bat": true, "cmd": true, "com": true, "cpl": true, "dll": true, "dmg": true,
+	"exe": true, "hta": true, "jar": true, "msi": true, "pif": true, "ps1": true, "scr": true, "sh": true,
+	"vb": true, "vbe": true, "vbs": true, "wsf": true,
+}
+
+// Leading bytes of executable formats, which are checked so that executables can't get through by being renamed
+var executableFileSignatures = [][]byte{
+	[]byte("\x7fELF"),          // Linux executables and libraries
+	[]byte("\xfe\xed\xfa\xce"), // Mach-O 32-bit
+	[]byte("\xfe\xed\xfa\xcf"), // Mach-O 64-bit
+	[]byte("\xce\xfa\xed\xfe"), // Mach-O 32-bit, little endian
+	[]byte("\xcf\xfa\xed\xfe"), // Mach-O 64-bit, little endian
+	[]byte("\xca\xfe\xba\xbe"), // Mach-O universal binaries and Java classes
+	[]byte("#!"),               // Scripts
+	[]byte("dex\n"),            // Android executables
+}
+
+// TeamUploadPolicy restricts the files that can be uploaded to a team's channels. If any extensions are allowed, files
+// must have one of them. Files are checked by their content as well as their name so that they can't be renamed to get
+// around the policy.
+type TeamUploadPolicy struct {
+	AllowedExtensions []string `json:"allowed_extensions"`
+	BlockedExtensions []string `json:"blocked_extensions"`
+	BlockExecutables  bool     `json:"block_executables"`
+}
+
+func (o *TeamUploadPolicy) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func TeamUploadPolicyFromJson(data io.Reader) *TeamUploadPolicy {
+	var o *TeamUploadPolicy
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+// NormalizeFileExtension returns an extension in the form that upload policies use, which is lower case without a
+// leading dot.
+func NormalizeFileExtension(extension string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+}
+
+func (o *TeamUploadPolicy) PreSave() {
+	for i, extension := range o.AllowedExtensions {
+		o.AllowedExtensions[i] = NormalizeFileExtension(extension)
+	}
+
+	for i, extension := range o.BlockedExtensions {
+		o.BlockedExtensions[i] = NormalizeFileExtension(extension)
+	}
+}
+
+func (o *TeamUploadPolicy) IsValid() *AppError {
+	if len(o.AllowedExtensions) > TEAM_UPLOAD_POLICY_MAX_EXTENSIONS || len(o.BlockedExtensions) > TEAM_UPLOAD_POLICY_MAX_EXTENSIONS {
+		return NewAppError("TeamUploadPolicy.IsValid", "model.team_upload_policy.is_valid.extensions.app_error", map[string]interface{}{"Max": TEAM_UPLOAD_POLICY_MAX_EXTENSIONS}, "", http.StatusBadRequest)
+	}
+
+	for _, extension := range append(append([]string{}, o.AllowedExtensions...), o.BlockedExtensions...) {
+		if extension == "" || len(extension) > TEAM_UPLOAD_POLICY_EXTENSION_MAX_LENGTH || extension != NormalizeFileExtension(extension) || !IsValidAlphaNumHyphenUnderscore(extension, false) {
+			return NewAppError("TeamUploadPolicy.IsValid", "model.team_upload_policy.is_valid.extension.app_error", nil, "extension="+extension, http.StatusBadRequest)
+		}
+	}
+
+	if len(o.ToJson()) > TEAM_UPLOAD_POLICY_MAX_SIZE {
+		return NewAppError("TeamUploadPolicy.IsValid", "model.team_upload_policy.is_valid.size.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsEmpty returns true if the policy allows every file.
+func (o *TeamUploadPolicy) IsEmpty() bool {
+	return len(o.AllowedExtensions) == 0 && len(o.BlockedExtensions) == 0 && !o.BlockExecutables
+}
+
+// IsExecutableContent returns true if data starts like a program or script.
+func IsExecutableContent(data []byte) bool {
+	if isWindowsExecutable(data) {
+		return true
+	}
+
+	for _, signature := range executableFileSignatures {
+		if bytes.HasPrefix(data, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWindowsExecutable returns true if data is a DOS or Windows executable. Text can easily start with their "MZ"
+// signature, so the header that it points to is checked as well when there's one.
+func isWindowsExecutable(data []byte) bool {
+	if !bytes.HasPrefix(data, []byte("MZ")) || len(data) < 0x40 {
+		return false
+	}
+
+	offset := int(binary.LittleEndian.Uint32(data[0x3c:0x40]))
+	if offset > 0 && offset+4 <= len(data) {
+		return bytes.Equal(data[offset:offset+4], []byte("PE\x00\x00"))
+	}
+
+	return false
+}
+
+// GetContentExtensions returns the extensions of the type that a file's content looks like, as sniffed from its first
+// bytes. Nothing is returned for generic types, such as plain text or zip archives, which are used by many formats.
+func GetContentExtensions(data []byte) (string, []string) {
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+
+	switch mimeType {
+	case "", "application/octet-stream", "text/plain", "application/zip", "application/x-gzip":
+		return mimeType, nil
+	}
+
+	extensions, _ := mime.ExtensionsByType(mimeType)
+	for i, extension := range extensions {
+		extensions[i] = NormalizeFileExtension(extension)
+	}
+
+	return mimeType, extensions
+}
+
+// CheckFile returns an error if the policy doesn't allow a file to be uploaded. The reason that the file was rejected
+// is part of the error's id, and its details include the file's name, extension and sniffed type.
+func (o *TeamUploadPolicy) CheckFile(name string, data []byte) *AppError {
+	extension := NormalizeFileExtension(filepath.Ext(name))
+	mimeType, contentExtensions := GetContentExtensions(data)
+
+	reject := func(reason string) *AppError {
+		params := map[string]interface{}{
+			"Filename":  name,
+			"Extension": extension,
+			"MimeType":  mimeType,
+		}
+		details := fmt.Sprintf("reason=%v, filename=%v, extension=%v, mime_type=%v", reason, name, extension, mimeType)
+		return NewAppError("TeamUploadPolicy.CheckFile", "model.team_upload_policy.check_file."+reason+".app_error", params, details, http.StatusBadRequest)
+	}
+
+	if o.BlockExecutables && (executableFileExtensions[extension] || IsExecutableContent(data)) {
+		return reject(UPLOAD_REJECTED_EXECUTABLE)
+	}
+
+	if containsString(o.BlockedExtensions, extension) {
+		return reject(UPLOAD_REJECTED_EXTENSION_BLOCKED)
+	}
+
+	for _, contentExtension := range contentExtensions {
+		if containsString(o.BlockedExtensions, contentExtension) {
+			return reject(UPLOAD_REJECTED_CONTENT_BLOCKED)
+		}
+	}
+
+	if len(o.AllowedExtensions) > 0 {
+		if !containsString(o.AllowedExtensions, extension) {
+			return reject(UPLOAD_REJECTED_EXTENSION_NOT_ALLOWED)
+		}
+
+		// Content that's recognized must be of a type that's allowed as well, and it must match the file's extension
+		if len(contentExtensions) > 0 && !containsString(contentExtensions, extension) {
+			return reject(UPLOAD_REJECTED_CONTENT_MISMATCH)
+		}
+	}
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
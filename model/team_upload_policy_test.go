@@ -0,0 +1,97 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	testPdfData = []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	testElfData = []byte("\x7fELF\x02\x01\x01\x00")
+)
+
+func getTestWindowsExecutable() []byte {
+	data := make([]byte, 0x80)
+	copy(data, "MZ")
+	binary.LittleEndian.PutUint32(data[0x3c:], 0x40)
+	copy(data[0x40:], "PE\x00\x00")
+	return data
+}
+
+func TestTeamUploadPolicyIsValid(t *testing.T) {
+	policy := &TeamUploadPolicy{AllowedExtensions: []string{".PNG", " pdf"}, BlockedExtensions: []string{"EXE"}}
+	require.NotNil(t, policy.IsValid(), "should require normalized extensions")
+
+	policy.PreSave()
+	require.Nil(t, policy.IsValid())
+	assert.Equal(t, []string{"png", "pdf"}, policy.AllowedExtensions)
+	assert.Equal(t, []string{"exe"}, policy.BlockedExtensions)
+	assert.False(t, policy.IsEmpty())
+	assert.True(t, (&TeamUploadPolicy{}).IsEmpty())
+
+	policy.BlockedExtensions = []string{"tar.gz"}
+	require.NotNil(t, policy.IsValid())
+
+	policy.BlockedExtensions = []string{""}
+	require.NotNil(t, policy.IsValid())
+
+	policy.BlockedExtensions = make([]string, TEAM_UPLOAD_POLICY_MAX_EXTENSIONS+1)
+	for i := range policy.BlockedExtensions {
+		policy.BlockedExtensions[i] = "ext"
+	}
+	require.NotNil(t, policy.IsValid())
+}
+
+func TestIsExecutableContent(t *testing.T) {
+	assert.True(t, IsExecutableContent(testElfData))
+	assert.True(t, IsExecutableContent(getTestWindowsExecutable()))
+	assert.True(t, IsExecutableContent([]byte("#!/bin/sh\nrm -rf /\n")))
+	assert.False(t, IsExecutableContent([]byte("MZ is how this text starts, but it's long enough to have a header offset")))
+	assert.False(t, IsExecutableContent(testPngData))
+	assert.False(t, IsExecutableContent([]byte("hello")))
+}
+
+func TestTeamUploadPolicyCheckFile(t *testing.T) {
+	for name, tc := range map[string]struct {
+		Policy   TeamUploadPolicy
+		Filename string
+		Data     []byte
+		Reason   string
+	}{
+		"empty policy":                {TeamUploadPolicy{}, "program.exe", getTestWindowsExecutable(), ""},
+		"executable extension":        {TeamUploadPolicy{BlockExecutables: true}, "program.EXE", []byte("text"), UPLOAD_REJECTED_EXECUTABLE},
+		"renamed executable":          {TeamUploadPolicy{BlockExecutables: true}, "notes.txt", testElfData, UPLOAD_REJECTED_EXECUTABLE},
+		"renamed windows executable":  {TeamUploadPolicy{BlockExecutables: true}, "image.png", getTestWindowsExecutable(), UPLOAD_REJECTED_EXECUTABLE},
+		"script":                      {TeamUploadPolicy{BlockExecutables: true}, "notes", []byte("#!/usr/bin/env python\n"), UPLOAD_REJECTED_EXECUTABLE},
+		"allowed non-executable":      {TeamUploadPolicy{BlockExecutables: true}, "image.png", testPngData, ""},
+		"blocked extension":           {TeamUploadPolicy{BlockedExtensions: []string{"pdf"}}, "report.PDF", testPdfData, UPLOAD_REJECTED_EXTENSION_BLOCKED},
+		"renamed blocked content":     {TeamUploadPolicy{BlockedExtensions: []string{"pdf"}}, "report.txt", testPdfData, UPLOAD_REJECTED_CONTENT_BLOCKED},
+		"not blocked":                 {TeamUploadPolicy{BlockedExtensions: []string{"pdf"}}, "image.png", testPngData, ""},
+		"allowed extension":           {TeamUploadPolicy{AllowedExtensions: []string{"png", "txt"}}, "image.png", testPngData, ""},
+		"allowed text":                {TeamUploadPolicy{AllowedExtensions: []string{"png", "txt"}}, "notes.txt", []byte("plain text"), ""},
+		"extension not allowed":       {TeamUploadPolicy{AllowedExtensions: []string{"png", "txt"}}, "report.pdf", testPdfData, UPLOAD_REJECTED_EXTENSION_NOT_ALLOWED},
+		"missing extension":           {TeamUploadPolicy{AllowedExtensions: []string{"png", "txt"}}, "report", testPdfData, UPLOAD_REJECTED_EXTENSION_NOT_ALLOWED},
+		"content doesn't match":       {TeamUploadPolicy{AllowedExtensions: []string{"png", "txt"}}, "report.png", testPdfData, UPLOAD_REJECTED_CONTENT_MISMATCH},
+		"generic content is accepted": {TeamUploadPolicy{AllowedExtensions: []string{"docx"}}, "document.docx", []byte("PK\x03\x04"), ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.Policy.CheckFile(tc.Filename, tc.Data)
+			if tc.Reason == "" {
+				assert.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+				assert.Equal(t, "model.team_upload_policy.check_file."+tc.Reason+".app_error", err.Id)
+				assert.Equal(t, ERROR_CODE_FILE_REJECTED, GetErrorCode(err.Id, err.StatusCode))
+				assert.True(t, strings.Contains(err.DetailedError, "reason="+tc.Reason))
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// ThreadMembership is a user's relationship with a thread, identified by its root post. Users who stop following a
+// thread keep their membership so that they aren't automatically followed again when someone else replies.
+type ThreadMembership struct {
+	PostId      string `json:"post_id"`
+	UserId      string `json:"user_id"`
+	Following   bool   `json:"following"`
+	LastViewed  int64  `json:"last_viewed"`
+	LastUpdated int64  `json:"last_updated"`
+}
+
+// Thread is a thread as seen by one of its members.
+type Thread struct {
+	PostId        string `json:"post_id"`
+	UserId        string `json:"user_id"`
+	Following     bool   `json:"following"`
+	LastViewed    int64  `json:"last_viewed"`
+	LastUpdated   int64  `json:"last_updated"`
+	UnreadReplies int64  `json:"unread_replies"`
+}
+
+func (o *ThreadMembership) PreSave() {
+	if o.LastUpdated == 0 {
+		o.LastUpdated = GetMillis()
+	}
+}
+
+func (o *ThreadMembership) IsValid() *AppError {
+	if len(o.PostId) != 26 {
+		return NewAppError("ThreadMembership.IsValid", "model.thread_membership.is_valid.post_id.app_error", nil, "post_id="+o.PostId, http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("ThreadMembership.IsValid", "model.thread_membership.is_valid.user_id.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.LastUpdated == 0 {
+		return NewAppError("ThreadMembership.IsValid", "model.thread_membership.is_valid.last_updated.app_error", nil, "post_id="+o.PostId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *Thread) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ThreadFromJson(data io.Reader) *Thread {
+	var o *Thread
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func ThreadsToJson(o []*Thread) string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func ThreadsFromJson(data io.Reader) []*Thread {
+	var o []*Thread
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadMembershipIsValid(t *testing.T) {
+	membership := &ThreadMembership{PostId: NewId(), UserId: NewId()}
+	require.NotNil(t, membership.IsValid(), "should require the last updated time")
+
+	membership.PreSave()
+	require.Nil(t, membership.IsValid())
+
+	membership.PostId = "junk"
+	assert.NotNil(t, membership.IsValid())
+
+	membership.PostId = NewId()
+	membership.UserId = ""
+	assert.NotNil(t, membership.IsValid())
+}
+
+func TestThreadJson(t *testing.T) {
+	thread := &Thread{PostId: NewId(), UserId: NewId(), Following: true, LastViewed: 1, LastUpdated: 2, UnreadReplies: 3}
+
+	assert.Equal(t, thread, ThreadFromJson(strings.NewReader(thread.ToJson())))
+
+	threads := ThreadsFromJson(strings.NewReader(ThreadsToJson([]*Thread{thread})))
+	require.Len(t, threads, 1)
+	assert.Equal(t, thread, threads[0])
+}
@@ -6,10 +6,21 @@ package model
 import (
 	"encoding/json"
 	"io"
+	"net/http"
 )
 
 type SupportedTimezones []string
 
+func (timezones SupportedTimezones) Contains(timezone string) bool {
+	for _, supported := range timezones {
+		if supported == timezone {
+			return true
+		}
+	}
+
+	return false
+}
+
 func TimezonesToJson(timezoneList []string) string {
 	b, _ := json.Marshal(timezoneList)
 	return string(b)
@@ -30,6 +41,60 @@ func DefaultUserTimezone() map[string]string {
 	return defaultTimezone
 }
 
+// UserTimezone is the typed form of the timezone that's stored on a user as a map of strings.
+type UserTimezone struct {
+	UseAutomaticTimezone bool   `json:"use_automatic_timezone"`
+	AutomaticTimezone    string `json:"automatic_timezone"`
+	ManualTimezone       string `json:"manual_timezone"`
+}
+
+func (tz *UserTimezone) ToJson() string {
+	b, _ := json.Marshal(tz)
+	return string(b)
+}
+
+func UserTimezoneFromJson(data io.Reader) *UserTimezone {
+	var tz *UserTimezone
+	json.NewDecoder(data).Decode(&tz)
+	return tz
+}
+
+func UserTimezoneFromMap(m StringMap) *UserTimezone {
+	if m == nil {
+		m = DefaultUserTimezone()
+	}
+
+	return &UserTimezone{
+		UseAutomaticTimezone: m["useAutomaticTimezone"] == "true",
+		AutomaticTimezone:    m["automaticTimezone"],
+		ManualTimezone:       m["manualTimezone"],
+	}
+}
+
+func (tz *UserTimezone) ToMap() StringMap {
+	useAutomaticTimezone := "false"
+	if tz.UseAutomaticTimezone {
+		useAutomaticTimezone = "true"
+	}
+
+	return StringMap{
+		"useAutomaticTimezone": useAutomaticTimezone,
+		"automaticTimezone":    tz.AutomaticTimezone,
+		"manualTimezone":       tz.ManualTimezone,
+	}
+}
+
+// IsValid checks that the timezones are either empty or one of the supported timezones.
+func (tz *UserTimezone) IsValid(supported SupportedTimezones) *AppError {
+	for _, timezone := range []string{tz.AutomaticTimezone, tz.ManualTimezone} {
+		if len(timezone) > 0 && !supported.Contains(timezone) {
+			return NewAppError("UserTimezone.IsValid", "model.user_timezone.is_valid.timezone.app_error", nil, "timezone="+timezone, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
 var DefaultSupportedTimezones = []string{
 	"Africa/Abidjan",
 	"Africa/Accra",
@@ -0,0 +1,34 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTimezoneMap(t *testing.T) {
+	timezone := UserTimezoneFromMap(nil)
+	assert.Equal(t, &UserTimezone{UseAutomaticTimezone: true}, timezone)
+
+	timezone = &UserTimezone{UseAutomaticTimezone: false, AutomaticTimezone: "America/Toronto", ManualTimezone: "Europe/Paris"}
+	assert.Equal(t, StringMap{
+		"useAutomaticTimezone": "false",
+		"automaticTimezone":    "America/Toronto",
+		"manualTimezone":       "Europe/Paris",
+	}, timezone.ToMap())
+	assert.Equal(t, timezone, UserTimezoneFromMap(timezone.ToMap()))
+	assert.Equal(t, timezone, UserTimezoneFromJson(strings.NewReader(timezone.ToJson())))
+}
+
+func TestUserTimezoneIsValid(t *testing.T) {
+	supported := SupportedTimezones{"America/Toronto", "Europe/Paris"}
+
+	assert.Nil(t, (&UserTimezone{UseAutomaticTimezone: true}).IsValid(supported))
+	assert.Nil(t, (&UserTimezone{AutomaticTimezone: "America/Toronto", ManualTimezone: "Europe/Paris"}).IsValid(supported))
+	assert.NotNil(t, (&UserTimezone{ManualTimezone: "Mars/Olympus_Mons"}).IsValid(supported))
+	assert.NotNil(t, (&UserTimezone{AutomaticTimezone: "Mars/Olympus_Mons"}).IsValid(supported))
+}
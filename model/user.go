@@ -29,6 +29,7 @@ const (
 	CHANNEL_MENTIONS_NOTIFY_PROP = "channel"
 	COMMENTS_NOTIFY_PROP         = "comments"
 	MENTION_KEYS_NOTIFY_PROP     = "mention_keys"
+	MENTION_PATTERNS_NOTIFY_PROP = "mention_patterns"
 	COMMENTS_NOTIFY_NEVER        = "never"
 	COMMENTS_NOTIFY_ROOT         = "root"
 	COMMENTS_NOTIFY_ANY          = "any"
@@ -47,6 +48,37 @@ const (
 	USER_PASSWORD_MAX_LENGTH  = 72
 )
 
+// Fields of users that can be hidden from other users who aren't admins. These are also the names of the options used
+// to sanitize users.
+const (
+	USER_FIELD_EMAIL         = "email"
+	USER_FIELD_FULL_NAME     = "fullname"
+	USER_FIELD_NICKNAME      = "nickname"
+	USER_FIELD_POSITION      = "position"
+	USER_FIELD_LAST_ACTIVITY = "lastactivity"
+
+	USER_FIELD_VISIBILITY_ALL    = "all"
+	USER_FIELD_VISIBILITY_ADMINS = "admins"
+)
+
+var MINIMIZABLE_USER_FIELDS = []string{
+	USER_FIELD_EMAIL,
+	USER_FIELD_FULL_NAME,
+	USER_FIELD_NICKNAME,
+	USER_FIELD_POSITION,
+	USER_FIELD_LAST_ACTIVITY,
+}
+
+func IsMinimizableUserField(field string) bool {
+	for _, minimizable := range MINIMIZABLE_USER_FIELDS {
+		if field == minimizable {
+			return true
+		}
+	}
+
+	return false
+}
+
 type User struct {
 	Id                 string    `json:"id"`
 	CreateAt           int64     `json:"create_at,omitempty"`
@@ -73,6 +105,8 @@ type User struct {
 	Timezone           StringMap `json:"timezone"`
 	MfaActive          bool      `json:"mfa_active,omitempty"`
 	MfaSecret          string    `json:"mfa_secret,omitempty"`
+	RemoteId           *string   `json:"remote_id,omitempty"`
+	IsBot              bool      `json:"is_bot,omitempty"`
 	LastActivityAt     int64     `db:"-" json:"last_activity_at,omitempty"`
 }
 
@@ -87,6 +121,7 @@ type UserPatch struct {
 	NotifyProps StringMap `json:"notify_props,omitempty"`
 	Locale      *string   `json:"locale"`
 	Timezone    StringMap `json:"timezone"`
+	RemoteId    *string   `json:"remote_id"`
 }
 
 type UserAuth struct {
@@ -168,6 +203,18 @@ func (u *User) IsValid() *AppError {
 		return InvalidUserError("password_limit", u.Id)
 	}
 
+	if !IsValidNotifyScheduleProps(u.NotifyProps) {
+		return InvalidUserError("notify_schedule", u.Id)
+	}
+
+	if !IsValidMentionKeys(u.NotifyProps) {
+		return InvalidUserError("mention_keys", u.Id)
+	}
+
+	if !IsValidRemoteId(u.RemoteId) {
+		return InvalidUserError("remote_id", u.Id)
+	}
+
 	return nil
 }
 
@@ -258,6 +305,11 @@ func (u *User) PreUpdate() {
 		}
 		u.NotifyProps["mention_keys"] = strings.Join(goodKeys, ",")
 	}
+
+	if _, ok := u.NotifyProps[MENTION_PATTERNS_NOTIFY_PROP]; ok {
+		// Remove any blank mention patterns
+		u.NotifyProps[MENTION_PATTERNS_NOTIFY_PROP] = strings.Join(GetMentionPatterns(u.NotifyProps), MENTION_PATTERNS_DELIMITER)
+	}
 }
 
 func (u *User) SetDefaultNotifications() {
@@ -328,6 +380,10 @@ func (u *User) Patch(patch *UserPatch) {
 	if patch.Timezone != nil {
 		u.Timezone = patch.Timezone
 	}
+
+	if patch.RemoteId != nil {
+		u.RemoteId = patchRemoteId(patch.RemoteId)
+	}
 }
 
 // ToJson convert a User to a json string
@@ -357,13 +413,22 @@ func (u *User) Sanitize(options map[string]bool) {
 	u.AuthData = NewString("")
 	u.MfaSecret = ""
 
-	if len(options) != 0 && !options["email"] {
+	if len(options) != 0 && !options[USER_FIELD_EMAIL] {
 		u.Email = ""
 	}
-	if len(options) != 0 && !options["fullname"] {
+	if len(options) != 0 && !options[USER_FIELD_FULL_NAME] {
 		u.FirstName = ""
 		u.LastName = ""
 	}
+	if len(options) != 0 && !options[USER_FIELD_NICKNAME] {
+		u.Nickname = ""
+	}
+	if len(options) != 0 && !options[USER_FIELD_POSITION] {
+		u.Position = ""
+	}
+	if len(options) != 0 && !options[USER_FIELD_LAST_ACTIVITY] {
+		u.LastActivityAt = 0
+	}
 	if len(options) != 0 && !options["passwordupdate"] {
 		u.LastPasswordUpdate = 0
 	}
@@ -381,6 +446,13 @@ func (u *User) ClearNonProfileFields() {
 	u.NotifyProps = StringMap{}
 	u.LastPasswordUpdate = 0
 	u.FailedAttempts = 0
+
+	// Copy the props rather than changing them in place since they may be shared with a cached user
+	if _, ok := u.Props[USER_PROP_PHONE_NUMBER]; ok {
+		props := CopyStringMap(u.Props)
+		delete(props, USER_PROP_PHONE_NUMBER)
+		u.Props = props
+	}
 }
 
 func (u *User) SanitizeProfile(options map[string]bool) {
@@ -0,0 +1,163 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	USER_DIRECTORY_SORT_USERNAME         = "username"
+	USER_DIRECTORY_SORT_EMAIL            = "email"
+	USER_DIRECTORY_SORT_FIRST_NAME       = "first_name"
+	USER_DIRECTORY_SORT_LAST_NAME        = "last_name"
+	USER_DIRECTORY_SORT_AUTH_SERVICE     = "auth_service"
+	USER_DIRECTORY_SORT_CREATE_AT        = "create_at"
+	USER_DIRECTORY_SORT_LAST_ACTIVITY_AT = "last_activity_at"
+
+	USER_DIRECTORY_MAX_SORT_COLUMNS = 3
+	USER_DIRECTORY_CSV_BATCH_SIZE   = 200
+)
+
+var userDirectorySortColumns = map[string]bool{
+	USER_DIRECTORY_SORT_USERNAME:         true,
+	USER_DIRECTORY_SORT_EMAIL:            true,
+	USER_DIRECTORY_SORT_FIRST_NAME:       true,
+	USER_DIRECTORY_SORT_LAST_NAME:        true,
+	USER_DIRECTORY_SORT_AUTH_SERVICE:     true,
+	USER_DIRECTORY_SORT_CREATE_AT:        true,
+	USER_DIRECTORY_SORT_LAST_ACTIVITY_AT: true,
+}
+
+// UserDirectoryCsvHeader lists the columns written when exporting the user directory as CSV.
+var UserDirectoryCsvHeader = []string{"id", "username", "email", "first_name", "last_name", "nickname", "roles", "auth_service", "create_at", "last_activity_at", "delete_at"}
+
+type UserDirectorySort struct {
+	Column     string
+	Descending bool
+}
+
+// UserDirectoryOptions filters and orders the users returned by the admin user directory.
+type UserDirectoryOptions struct {
+	Role               string
+	AuthService        string
+	LastActivityBefore int64
+	TeamId             string
+	Sort               []UserDirectorySort
+}
+
+// UserDirectorySortFromString parses a comma separated list of columns, each optionally prefixed
+// with a "-" to sort in descending order, e.g. "last_name,-create_at".
+func UserDirectorySortFromString(value string) ([]UserDirectorySort, bool) {
+	sort := []UserDirectorySort{}
+	if value == "" {
+		return sort, true
+	}
+
+	seen := make(map[string]bool)
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		descending := strings.HasPrefix(column, "-")
+		column = strings.TrimPrefix(column, "-")
+
+		if !userDirectorySortColumns[column] || seen[column] {
+			return nil, false
+		}
+		seen[column] = true
+
+		sort = append(sort, UserDirectorySort{Column: column, Descending: descending})
+	}
+
+	if len(sort) > USER_DIRECTORY_MAX_SORT_COLUMNS {
+		return nil, false
+	}
+
+	return sort, true
+}
+
+func (o *UserDirectoryOptions) SortString() string {
+	columns := make([]string, 0, len(o.Sort))
+	for _, s := range o.Sort {
+		if s.Descending {
+			columns = append(columns, "-"+s.Column)
+		} else {
+			columns = append(columns, s.Column)
+		}
+	}
+
+	return strings.Join(columns, ",")
+}
+
+func (o *UserDirectoryOptions) IsValid() *AppError {
+	if o.Role != "" && !IsValidRoleName(o.Role) {
+		return NewAppError("UserDirectoryOptions.IsValid", "model.user_directory.is_valid.role.app_error", nil, "role="+o.Role, http.StatusBadRequest)
+	}
+
+	if o.TeamId != "" && !IsValidId(o.TeamId) {
+		return NewAppError("UserDirectoryOptions.IsValid", "model.user_directory.is_valid.team_id.app_error", nil, "team_id="+o.TeamId, http.StatusBadRequest)
+	}
+
+	if o.LastActivityBefore < 0 {
+		return NewAppError("UserDirectoryOptions.IsValid", "model.user_directory.is_valid.last_activity_before.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Sort) > USER_DIRECTORY_MAX_SORT_COLUMNS {
+		return NewAppError("UserDirectoryOptions.IsValid", "model.user_directory.is_valid.sort.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, s := range o.Sort {
+		if !userDirectorySortColumns[s.Column] {
+			return NewAppError("UserDirectoryOptions.IsValid", "model.user_directory.is_valid.sort.app_error", nil, "column="+s.Column, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// ToQueryString encodes the options as the query parameters accepted by the user directory endpoint.
+func (o *UserDirectoryOptions) ToQueryString() string {
+	query := url.Values{}
+
+	if o.Role != "" {
+		query.Set("role", o.Role)
+	}
+
+	if o.AuthService != "" {
+		query.Set("auth_service", o.AuthService)
+	}
+
+	if o.LastActivityBefore > 0 {
+		query.Set("last_activity_before", strconv.FormatInt(o.LastActivityBefore, 10))
+	}
+
+	if o.TeamId != "" {
+		query.Set("team_id", o.TeamId)
+	}
+
+	if len(o.Sort) > 0 {
+		query.Set("sort", o.SortString())
+	}
+
+	return query.Encode()
+}
+
+// ToCsvRecord returns the user's fields in the order given by UserDirectoryCsvHeader.
+func (u *User) ToCsvRecord() []string {
+	return []string{
+		u.Id,
+		u.Username,
+		u.Email,
+		u.FirstName,
+		u.LastName,
+		u.Nickname,
+		u.Roles,
+		u.AuthService,
+		strconv.FormatInt(u.CreateAt, 10),
+		strconv.FormatInt(u.LastActivityAt, 10),
+		strconv.FormatInt(u.DeleteAt, 10),
+	}
+}
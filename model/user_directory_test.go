@@ -0,0 +1,77 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDirectorySortFromString(t *testing.T) {
+	sort, ok := UserDirectorySortFromString("")
+	require.True(t, ok)
+	assert.Empty(t, sort)
+
+	sort, ok = UserDirectorySortFromString("last_name, -create_at")
+	require.True(t, ok)
+	assert.Equal(t, []UserDirectorySort{
+		{Column: USER_DIRECTORY_SORT_LAST_NAME},
+		{Column: USER_DIRECTORY_SORT_CREATE_AT, Descending: true},
+	}, sort)
+
+	_, ok = UserDirectorySortFromString("password")
+	assert.False(t, ok)
+
+	_, ok = UserDirectorySortFromString("username,-username")
+	assert.False(t, ok)
+
+	_, ok = UserDirectorySortFromString("username,email,first_name,last_name")
+	assert.False(t, ok)
+}
+
+func TestUserDirectoryOptionsIsValid(t *testing.T) {
+	options := &UserDirectoryOptions{}
+	assert.Nil(t, options.IsValid())
+
+	options.Role = SYSTEM_ADMIN_ROLE_ID
+	options.TeamId = NewId()
+	options.Sort = []UserDirectorySort{{Column: USER_DIRECTORY_SORT_USERNAME}}
+	assert.Nil(t, options.IsValid())
+
+	options.TeamId = "junk"
+	assert.NotNil(t, options.IsValid())
+	options.TeamId = ""
+
+	options.LastActivityBefore = -1
+	assert.NotNil(t, options.IsValid())
+	options.LastActivityBefore = 0
+
+	options.Sort = []UserDirectorySort{{Column: "password"}}
+	assert.NotNil(t, options.IsValid())
+}
+
+func TestUserDirectoryOptionsToQueryString(t *testing.T) {
+	options := &UserDirectoryOptions{
+		Role:               SYSTEM_ADMIN_ROLE_ID,
+		LastActivityBefore: 1234,
+		Sort: []UserDirectorySort{
+			{Column: USER_DIRECTORY_SORT_USERNAME},
+			{Column: USER_DIRECTORY_SORT_CREATE_AT, Descending: true},
+		},
+	}
+
+	assert.Equal(t, "last_activity_before=1234&role=system_admin&sort=username%2C-create_at", options.ToQueryString())
+	assert.Equal(t, "", (&UserDirectoryOptions{}).ToQueryString())
+}
+
+func TestUserToCsvRecord(t *testing.T) {
+	user := &User{Id: NewId(), Username: "jdoe", Email: "jdoe@example.com", CreateAt: 1, LastActivityAt: 2}
+	record := user.ToCsvRecord()
+	require.Len(t, record, len(UserDirectoryCsvHeader))
+	assert.Equal(t, "jdoe", record[1])
+	assert.Equal(t, "1", record[8])
+	assert.Equal(t, "2", record[9])
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	USER_GROUP_DISPLAY_NAME_MAX_RUNES = 64
+	USER_GROUP_DESCRIPTION_MAX_RUNES  = 1024
+)
+
+// UserGroup is a set of users that can be mentioned all at once as @Name, such as @oncall or @designers. Only the
+// members of the group who are in the channel are notified.
+type UserGroup struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	CreatorId   string `json:"creator_id"`
+	CreateAt    int64  `json:"create_at"`
+	UpdateAt    int64  `json:"update_at"`
+}
+
+type UserGroupPatch struct {
+	Name        *string `json:"name"`
+	DisplayName *string `json:"display_name"`
+	Description *string `json:"description"`
+}
+
+type UserGroupMember struct {
+	GroupId  string `json:"group_id"`
+	UserId   string `json:"user_id"`
+	CreateAt int64  `json:"create_at"`
+}
+
+func (o *UserGroup) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func UserGroupFromJson(data io.Reader) *UserGroup {
+	var o *UserGroup
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func UserGroupListToJson(l []*UserGroup) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func UserGroupListFromJson(data io.Reader) []*UserGroup {
+	var o []*UserGroup
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *UserGroupPatch) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func UserGroupPatchFromJson(data io.Reader) *UserGroupPatch {
+	var o *UserGroupPatch
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *UserGroupMember) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func UserGroupMemberFromJson(data io.Reader) *UserGroupMember {
+	var o *UserGroupMember
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func UserGroupMembersToJson(l []*UserGroupMember) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func UserGroupMembersFromJson(data io.Reader) []*UserGroupMember {
+	var o []*UserGroupMember
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *UserGroup) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.Name = strings.ToLower(o.Name)
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+}
+
+func (o *UserGroup) PreUpdate() {
+	o.Name = strings.ToLower(o.Name)
+	o.UpdateAt = GetMillis()
+}
+
+func (o *UserGroup) Patch(patch *UserGroupPatch) {
+	if patch.Name != nil {
+		o.Name = *patch.Name
+	}
+
+	if patch.DisplayName != nil {
+		o.DisplayName = *patch.DisplayName
+	}
+
+	if patch.Description != nil {
+		o.Description = *patch.Description
+	}
+}
+
+// IsValid checks the group. Groups are mentioned the same way as users, so their names follow the same rules as
+// usernames and can't be one of the special mentions like @channel or @here.
+func (o *UserGroup) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidUsername(o.Name) || o.Name == "here" {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.DisplayName) > USER_GROUP_DISPLAY_NAME_MAX_RUNES {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.display_name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Description) > USER_GROUP_DESCRIPTION_MAX_RUNES {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.description.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.CreatorId) != 26 {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.creator_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("UserGroup.IsValid", "model.user_group.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *UserGroupMember) PreSave() {
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+}
+
+func (o *UserGroupMember) IsValid() *AppError {
+	if len(o.GroupId) != 26 {
+		return NewAppError("UserGroupMember.IsValid", "model.user_group_member.is_valid.group_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("UserGroupMember.IsValid", "model.user_group_member.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("UserGroupMember.IsValid", "model.user_group_member.is_valid.create_at.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserGroupIsValid(t *testing.T) {
+	group := &UserGroup{Name: "OnCall", CreatorId: NewId()}
+	require.NotNil(t, group.IsValid(), "should require an id")
+
+	group.PreSave()
+	require.Nil(t, group.IsValid())
+	assert.Equal(t, "oncall", group.Name)
+
+	group.Name = "here"
+	assert.NotNil(t, group.IsValid(), "shouldn't allow special mentions")
+
+	group.Name = "all"
+	assert.NotNil(t, group.IsValid(), "shouldn't allow special mentions")
+
+	group.Name = "not valid"
+	assert.NotNil(t, group.IsValid())
+
+	group.Name = "designers"
+	group.DisplayName = strings.Repeat("a", USER_GROUP_DISPLAY_NAME_MAX_RUNES+1)
+	assert.NotNil(t, group.IsValid())
+
+	group.DisplayName = ""
+	group.Description = strings.Repeat("a", USER_GROUP_DESCRIPTION_MAX_RUNES+1)
+	assert.NotNil(t, group.IsValid())
+
+	group.Description = ""
+	group.CreatorId = ""
+	assert.NotNil(t, group.IsValid())
+}
+
+func TestUserGroupPatch(t *testing.T) {
+	group := &UserGroup{Name: "oncall", DisplayName: "On Call", Description: "description"}
+
+	displayName := "Support"
+	group.Patch(&UserGroupPatch{DisplayName: &displayName})
+
+	assert.Equal(t, "oncall", group.Name)
+	assert.Equal(t, "Support", group.DisplayName)
+	assert.Equal(t, "description", group.Description)
+}
+
+func TestUserGroupMemberIsValid(t *testing.T) {
+	member := &UserGroupMember{GroupId: NewId(), UserId: NewId()}
+	require.NotNil(t, member.IsValid(), "should require the create time")
+
+	member.PreSave()
+	require.Nil(t, member.IsValid())
+
+	member.GroupId = "junk"
+	assert.NotNil(t, member.IsValid())
+
+	member.GroupId = NewId()
+	member.UserId = ""
+	assert.NotNil(t, member.IsValid())
+}
+
+func TestUserGroupJson(t *testing.T) {
+	group := &UserGroup{Id: NewId(), Name: "oncall", CreatorId: NewId(), CreateAt: 1, UpdateAt: 2}
+
+	assert.Equal(t, group, UserGroupFromJson(strings.NewReader(group.ToJson())))
+
+	groups := UserGroupListFromJson(strings.NewReader(UserGroupListToJson([]*UserGroup{group})))
+	require.Len(t, groups, 1)
+	assert.Equal(t, group, groups[0])
+
+	member := &UserGroupMember{GroupId: group.Id, UserId: NewId(), CreateAt: 1}
+	members := UserGroupMembersFromJson(strings.NewReader(UserGroupMembersToJson([]*UserGroupMember{member})))
+	require.Len(t, members, 1)
+	assert.Equal(t, member, members[0])
+}
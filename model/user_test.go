@@ -192,6 +192,33 @@ func HasExpectedUserIsValidError(err *AppError, fieldName string, userId string)
 		(userId == "" || err.DetailedError == "user_id="+userId)
 }
 
+func TestUserSanitize(t *testing.T) {
+	user := &User{
+		Id:             NewId(),
+		Email:          "test@example.com",
+		FirstName:      "first",
+		LastName:       "last",
+		Nickname:       "nickname",
+		Position:       "position",
+		LastActivityAt: GetMillis(),
+	}
+
+	user.Sanitize(map[string]bool{
+		USER_FIELD_EMAIL:         true,
+		USER_FIELD_FULL_NAME:     false,
+		USER_FIELD_NICKNAME:      true,
+		USER_FIELD_POSITION:      false,
+		USER_FIELD_LAST_ACTIVITY: false,
+	})
+
+	assert.Equal(t, "test@example.com", user.Email)
+	assert.Equal(t, "", user.FirstName)
+	assert.Equal(t, "", user.LastName)
+	assert.Equal(t, "nickname", user.Nickname)
+	assert.Equal(t, "", user.Position)
+	assert.Equal(t, int64(0), user.LastActivityAt)
+}
+
 func TestUserGetFullName(t *testing.T) {
 	user := User{}
 
@@ -50,6 +50,8 @@ type AppError struct {
 	StatusCode    int    `json:"status_code,omitempty"` // The http status code
 	Where         string `json:"-"`                     // The function where it happened in the form of Struct.Func
 	IsOAuth       bool   `json:"is_oauth,omitempty"`    // Whether the error is OAuth specific
+	Code          string `json:"code,omitempty"`        // A stable code that clients can use to tell what kind of error it is
+	Hint          string `json:"hint,omitempty"`        // What the user can do to resolve the error
 	params        map[string]interface{}
 }
 
@@ -58,8 +60,11 @@ func (er *AppError) Error() string {
 }
 
 func (er *AppError) Translate(T goi18n.TranslateFunc) {
+	er.Code = GetErrorCode(er.Id, er.StatusCode)
+
 	if T == nil {
 		er.Message = er.Id
+		er.Hint = ""
 		return
 	}
 
@@ -68,6 +73,8 @@ func (er *AppError) Translate(T goi18n.TranslateFunc) {
 	} else {
 		er.Message = T(er.Id, er.params)
 	}
+
+	er.Hint = T(GetErrorCodeHintId(er.Code))
 }
 
 func (er *AppError) SystemMessage(T goi18n.TranslateFunc) string {
@@ -0,0 +1,103 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	WEB_PUSH_SUBSCRIPTION_ENDPOINT_MAX_LENGTH = 1024
+	WEB_PUSH_SUBSCRIPTION_P256DH_LENGTH       = 65
+	WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH         = 16
+)
+
+// WebPushSubscription is a browser's push subscription, registered for the session that created it so that the
+// browser stops receiving notifications once that session ends. P256dh and Auth are the base64url encoded keys
+// from the browser's PushSubscription.
+type WebPushSubscription struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	SessionId string `json:"session_id"`
+	Endpoint  string `json:"endpoint"`
+	P256dh    string `json:"p256dh"`
+	Auth      string `json:"auth"`
+	CreateAt  int64  `json:"create_at"`
+}
+
+func (o *WebPushSubscription) ToJson() string {
+	b, _ := json.Marshal(o)
+	return string(b)
+}
+
+func WebPushSubscriptionFromJson(data io.Reader) *WebPushSubscription {
+	var o *WebPushSubscription
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func WebPushSubscriptionListToJson(l []*WebPushSubscription) string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func WebPushSubscriptionListFromJson(data io.Reader) []*WebPushSubscription {
+	var o []*WebPushSubscription
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
+
+func (o *WebPushSubscription) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.CreateAt = GetMillis()
+}
+
+func (o *WebPushSubscription) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.SessionId) != 26 {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.session_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Endpoint) == 0 || len(o.Endpoint) > WEB_PUSH_SUBSCRIPTION_ENDPOINT_MAX_LENGTH {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.endpoint.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if endpointUrl, err := url.Parse(o.Endpoint); err != nil || endpointUrl.Scheme != "https" || endpointUrl.Host == "" {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.endpoint.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if key, err := base64.RawURLEncoding.DecodeString(o.P256dh); err != nil || len(key) != WEB_PUSH_SUBSCRIPTION_P256DH_LENGTH || key[0] != 0x04 {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.p256dh.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if auth, err := base64.RawURLEncoding.DecodeString(o.Auth); err != nil || len(auth) != WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.auth.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("WebPushSubscription.IsValid", "model.web_push_subscription.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Sanitize removes the keys needed to encrypt messages for the subscription.
+func (o *WebPushSubscription) Sanitize() {
+	o.P256dh = ""
+	o.Auth = ""
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebPushSubscriptionJson(t *testing.T) {
+	o := &WebPushSubscription{Id: NewId(), UserId: NewId(), Endpoint: "https://push.example.com/abc"}
+	ro := WebPushSubscriptionFromJson(strings.NewReader(o.ToJson()))
+	assert.Equal(t, o, ro)
+
+	l := []*WebPushSubscription{o}
+	assert.Equal(t, l, WebPushSubscriptionListFromJson(strings.NewReader(WebPushSubscriptionListToJson(l))))
+}
+
+func TestWebPushSubscriptionIsValid(t *testing.T) {
+	p256dh := make([]byte, WEB_PUSH_SUBSCRIPTION_P256DH_LENGTH)
+	p256dh[0] = 0x04
+
+	o := &WebPushSubscription{
+		UserId:    NewId(),
+		SessionId: NewId(),
+		Endpoint:  "https://push.example.com/abc",
+		P256dh:    base64.RawURLEncoding.EncodeToString(p256dh),
+		Auth:      base64.RawURLEncoding.EncodeToString(make([]byte, WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH)),
+	}
+	assert.NotNil(t, o.IsValid())
+
+	o.PreSave()
+	assert.Nil(t, o.IsValid())
+
+	o.Endpoint = "http://push.example.com/abc"
+	assert.NotNil(t, o.IsValid(), "endpoint must use https")
+
+	o.Endpoint = "https://push.example.com/" + strings.Repeat("a", WEB_PUSH_SUBSCRIPTION_ENDPOINT_MAX_LENGTH)
+	assert.NotNil(t, o.IsValid())
+
+	o.Endpoint = "https://push.example.com/abc"
+	o.P256dh = base64.RawURLEncoding.EncodeToString(p256dh[1:])
+	assert.NotNil(t, o.IsValid())
+
+	o.P256dh = base64.RawURLEncoding.EncodeToString(p256dh)
+	o.Auth = "junk!"
+	assert.NotNil(t, o.IsValid())
+
+	o.Auth = base64.RawURLEncoding.EncodeToString(make([]byte, WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH))
+	o.SessionId = ""
+	assert.NotNil(t, o.IsValid())
+}
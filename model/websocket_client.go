@@ -6,6 +6,8 @@ package model
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,6 +29,8 @@ type WebSocketClient struct {
 	EventChannel       chan *WebSocketEvent
 	ResponseChannel    chan *WebSocketResponse
 	ListenError        *AppError
+	ConnectionId       string // The id of the connection from the server's hello event, used to resume it
+	ServerSequence     int64  // The sequence number of the last event received from the server, used to resume the connection
 	pingTimeoutTimer   *time.Timer
 }
 
@@ -55,6 +59,8 @@ func NewWebSocketClientWithDialer(dialer *websocket.Dialer, url, authToken strin
 		make(chan *WebSocketEvent, 100),
 		make(chan *WebSocketResponse, 100),
 		nil,
+		"",
+		0,
 		nil,
 	}
 
@@ -82,8 +88,30 @@ func (wsc *WebSocketClient) Connect() *AppError {
 }
 
 func (wsc *WebSocketClient) ConnectWithDialer(dialer *websocket.Dialer) *AppError {
+	return wsc.connect(dialer, wsc.ConnectUrl)
+}
+
+// Resume reconnects after the connection was lost, asking the server to send the events that were missed. The events
+// were only sent if the ConnectionId from the hello event that follows them is unchanged.
+func (wsc *WebSocketClient) Resume() *AppError {
+	return wsc.ResumeWithDialer(websocket.DefaultDialer)
+}
+
+func (wsc *WebSocketClient) ResumeWithDialer(dialer *websocket.Dialer) *AppError {
+	if wsc.ConnectionId == "" {
+		return wsc.ConnectWithDialer(dialer)
+	}
+
+	query := url.Values{}
+	query.Set(WEBSOCKET_CONNECTION_ID_PARAM, wsc.ConnectionId)
+	query.Set(WEBSOCKET_SEQUENCE_NUMBER_PARAM, strconv.FormatInt(wsc.ServerSequence, 10))
+
+	return wsc.connect(dialer, wsc.ConnectUrl+"?"+query.Encode())
+}
+
+func (wsc *WebSocketClient) connect(dialer *websocket.Dialer, connectUrl string) *AppError {
 	var err error
-	wsc.Conn, _, err = dialer.Dial(wsc.ConnectUrl, nil)
+	wsc.Conn, _, err = dialer.Dial(connectUrl, nil)
 	if err != nil {
 		return NewAppError("Connect", "model.websocket_client.connect_fail.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
@@ -123,6 +151,13 @@ func (wsc *WebSocketClient) Listen() {
 
 			var event WebSocketEvent
 			if err := json.Unmarshal(rawMsg, &event); err == nil && event.IsValid() {
+				wsc.ServerSequence = event.Sequence
+				if event.Event == WEBSOCKET_EVENT_HELLO {
+					if connectionId, ok := event.Data["connection_id"].(string); ok {
+						wsc.ConnectionId = connectionId
+					}
+				}
+
 				wsc.EventChannel <- &event
 				continue
 			}
@@ -173,6 +208,16 @@ func (wsc *WebSocketClient) GetStatusesByIds(userIds []string) {
 	wsc.SendMessage("get_statuses_by_ids", data)
 }
 
+// SubscribeEvents asks for only the events of the given types and for the given channels to be sent on this connection.
+// Leaving both empty goes back to getting every event.
+func (wsc *WebSocketClient) SubscribeEvents(events []string, channelIds []string) {
+	data := map[string]interface{}{
+		"events":      events,
+		"channel_ids": channelIds,
+	}
+	wsc.SendMessage("subscribe_events", data)
+}
+
 func (wsc *WebSocketClient) configurePingHandling() {
 	wsc.Conn.SetPingHandler(wsc.pingHandler)
 	wsc.pingTimeoutTimer = time.NewTimer(time.Second * (60 + PING_TIMEOUT_BUFFER_SECONDS))
@@ -0,0 +1,60 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// The reasons that are given in the close frame when a websocket connection is rejected for going over a quota.
+const (
+	WEBSOCKET_REJECT_USER_CONNECTION_LIMIT   = "user_connection_limit"
+	WEBSOCKET_REJECT_SERVER_CONNECTION_LIMIT = "server_connection_limit"
+)
+
+// The reason that's given with the "service restart" close code when a server closes its websocket connections because
+// it's shutting down. Clients should reconnect, resuming their connections, which will usually reach another server.
+const WEBSOCKET_CLOSE_SERVER_RESTART = "server_restart"
+
+// The query parameters that a client connects with to resume a connection that it lost, giving the id from the hello
+// event of that connection and the sequence number of the last event that it received on it. If the server still has
+// the events that were missed, it sends them before the hello event and keeps the same connection id. Otherwise, the
+// hello event has a new connection id, and the client should fetch whatever it may have missed.
+const (
+	WEBSOCKET_CONNECTION_ID_PARAM   = "connection_id"
+	WEBSOCKET_SEQUENCE_NUMBER_PARAM = "sequence_number"
+)
+
+// WebSocketConnectionInfo describes an open websocket connection. ClusterId is the id of the server in the cluster
+// that the connection is open to.
+type WebSocketConnectionInfo struct {
+	Id             string `json:"id"`
+	ClusterId      string `json:"cluster_id,omitempty"`
+	UserId         string `json:"user_id"`
+	SessionId      string `json:"session_id"`
+	DeviceId       string `json:"device_id,omitempty"`
+	Platform       string `json:"platform,omitempty"`
+	Os             string `json:"os,omitempty"`
+	Browser        string `json:"browser,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	IpAddress      string `json:"ip_address,omitempty"`
+	ConnectAt      int64  `json:"connect_at"`
+	LastActivityAt int64  `json:"last_activity_at"`
+	SendQueueDepth int    `json:"send_queue_depth"`
+}
+
+func WebSocketConnectionInfosToJson(o []*WebSocketConnectionInfo) string {
+	if b, err := json.Marshal(o); err != nil {
+		return "[]"
+	} else {
+		return string(b)
+	}
+}
+
+func WebSocketConnectionInfosFromJson(data io.Reader) []*WebSocketConnectionInfo {
+	var o []*WebSocketConnectionInfo
+	json.NewDecoder(data).Decode(&o)
+	return o
+}
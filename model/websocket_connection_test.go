@@ -0,0 +1,25 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocketConnectionInfosJson(t *testing.T) {
+	infos := []*WebSocketConnectionInfo{{
+		Id:        NewId(),
+		UserId:    NewId(),
+		SessionId: NewId(),
+		Platform:  "Linux",
+		UserAgent: "Go-http-client/1.1",
+		ConnectAt: GetMillis(),
+	}}
+
+	assert.Equal(t, infos, WebSocketConnectionInfosFromJson(strings.NewReader(WebSocketConnectionInfosToJson(infos))))
+	assert.Equal(t, "[]", WebSocketConnectionInfosToJson([]*WebSocketConnectionInfo{}))
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const (
+	WEBSOCKET_EVENT_FILTER_MAX_EVENTS   = 100
+	WEBSOCKET_EVENT_FILTER_MAX_CHANNELS = 1000
+)
+
+// WebSocketEventFilter narrows down the events that are sent to a websocket connection so that integrations such as
+// bots don't have to receive and discard every event that their user can see. When Events is set, only events of those
+// types are sent. When ChannelIds is set, events for other channels aren't sent, but events that aren't for a channel
+// still are. The filter never widens what's sent, so a connection still only gets events for channels it's a member of.
+type WebSocketEventFilter struct {
+	Events     []string `json:"events"`
+	ChannelIds []string `json:"channel_ids"`
+
+	events     map[string]bool
+	channelIds map[string]bool
+}
+
+func NewWebSocketEventFilter(events []string, channelIds []string) *WebSocketEventFilter {
+	filter := &WebSocketEventFilter{
+		Events:     events,
+		ChannelIds: channelIds,
+		events:     make(map[string]bool, len(events)),
+		channelIds: make(map[string]bool, len(channelIds)),
+	}
+
+	for _, event := range events {
+		filter.events[event] = true
+	}
+
+	for _, channelId := range channelIds {
+		filter.channelIds[channelId] = true
+	}
+
+	return filter
+}
+
+// WebSocketEventFilterFromInterface reads a filter from the data of a websocket request.
+func WebSocketEventFilterFromInterface(data map[string]interface{}) *WebSocketEventFilter {
+	return NewWebSocketEventFilter(ArrayFromInterface(data["events"]), ArrayFromInterface(data["channel_ids"]))
+}
+
+func (f *WebSocketEventFilter) IsValid() *AppError {
+	if len(f.Events) > WEBSOCKET_EVENT_FILTER_MAX_EVENTS {
+		return NewAppError("WebSocketEventFilter.IsValid", "model.websocket_event_filter.is_valid.events.app_error", map[string]interface{}{"Max": WEBSOCKET_EVENT_FILTER_MAX_EVENTS}, "", http.StatusBadRequest)
+	}
+
+	for _, event := range f.Events {
+		if len(event) == 0 {
+			return NewAppError("WebSocketEventFilter.IsValid", "model.websocket_event_filter.is_valid.event.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if len(f.ChannelIds) > WEBSOCKET_EVENT_FILTER_MAX_CHANNELS {
+		return NewAppError("WebSocketEventFilter.IsValid", "model.websocket_event_filter.is_valid.channel_ids.app_error", map[string]interface{}{"Max": WEBSOCKET_EVENT_FILTER_MAX_CHANNELS}, "", http.StatusBadRequest)
+	}
+
+	for _, channelId := range f.ChannelIds {
+		if !IsValidId(channelId) {
+			return NewAppError("WebSocketEventFilter.IsValid", "model.websocket_event_filter.is_valid.channel_id.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// IsEmpty returns true if the filter lets every event through.
+func (f *WebSocketEventFilter) IsEmpty() bool {
+	return len(f.Events) == 0 && len(f.ChannelIds) == 0
+}
+
+// Matches returns true if an event should be sent to a connection that uses the filter.
+func (f *WebSocketEventFilter) Matches(event *WebSocketEvent) bool {
+	if len(f.events) > 0 && !f.events[event.Event] {
+		return false
+	}
+
+	if len(f.channelIds) > 0 && event.Broadcast != nil && len(event.Broadcast.ChannelId) > 0 && !f.channelIds[event.Broadcast.ChannelId] {
+		return false
+	}
+
+	return true
+}
+
+func (f *WebSocketEventFilter) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"events":      f.Events,
+		"channel_ids": f.ChannelIds,
+	}
+}
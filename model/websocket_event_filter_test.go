@@ -0,0 +1,77 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketEventFilterIsValid(t *testing.T) {
+	require.Nil(t, NewWebSocketEventFilter(nil, nil).IsValid())
+	require.Nil(t, NewWebSocketEventFilter([]string{WEBSOCKET_EVENT_POSTED}, []string{NewId()}).IsValid())
+
+	require.NotNil(t, NewWebSocketEventFilter([]string{""}, nil).IsValid())
+	require.NotNil(t, NewWebSocketEventFilter(nil, []string{"junk"}).IsValid())
+
+	tooManyChannels := make([]string, WEBSOCKET_EVENT_FILTER_MAX_CHANNELS+1)
+	for i := range tooManyChannels {
+		tooManyChannels[i] = NewId()
+	}
+	require.NotNil(t, NewWebSocketEventFilter(nil, tooManyChannels).IsValid())
+}
+
+func TestWebSocketEventFilterFromInterface(t *testing.T) {
+	channelId := NewId()
+
+	filter := WebSocketEventFilterFromInterface(map[string]interface{}{
+		"events":      []interface{}{WEBSOCKET_EVENT_POSTED},
+		"channel_ids": []interface{}{channelId},
+	})
+
+	assert.Equal(t, []string{WEBSOCKET_EVENT_POSTED}, filter.Events)
+	assert.Equal(t, []string{channelId}, filter.ChannelIds)
+	assert.False(t, filter.IsEmpty())
+
+	assert.True(t, WebSocketEventFilterFromInterface(nil).IsEmpty())
+}
+
+func TestWebSocketEventFilterMatches(t *testing.T) {
+	channelId := NewId()
+	otherChannelId := NewId()
+
+	posted := NewWebSocketEvent(WEBSOCKET_EVENT_POSTED, "", channelId, "", nil)
+	postedElsewhere := NewWebSocketEvent(WEBSOCKET_EVENT_POSTED, "", otherChannelId, "", nil)
+	typing := NewWebSocketEvent(WEBSOCKET_EVENT_TYPING, "", channelId, "", nil)
+	userUpdated := NewWebSocketEvent(WEBSOCKET_EVENT_USER_UPDATED, "", "", "", nil)
+
+	t.Run("events", func(t *testing.T) {
+		filter := NewWebSocketEventFilter([]string{WEBSOCKET_EVENT_POSTED}, nil)
+
+		assert.True(t, filter.Matches(posted))
+		assert.True(t, filter.Matches(postedElsewhere))
+		assert.False(t, filter.Matches(typing))
+		assert.False(t, filter.Matches(userUpdated))
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		filter := NewWebSocketEventFilter(nil, []string{channelId})
+
+		assert.True(t, filter.Matches(posted))
+		assert.False(t, filter.Matches(postedElsewhere))
+		assert.True(t, filter.Matches(typing))
+		assert.True(t, filter.Matches(userUpdated), "events that aren't for a channel should still be sent")
+	})
+
+	t.Run("events and channels", func(t *testing.T) {
+		filter := NewWebSocketEventFilter([]string{WEBSOCKET_EVENT_POSTED}, []string{channelId})
+
+		assert.True(t, filter.Matches(posted))
+		assert.False(t, filter.Matches(postedElsewhere))
+		assert.False(t, filter.Matches(typing))
+		assert.False(t, filter.Matches(userUpdated))
+	})
+}
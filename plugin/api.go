@@ -20,11 +20,26 @@ type API interface {
 
 	// RegisterCommand registers a custom slash command. When the command is triggered, your plugin
 	// can fulfill it via the ExecuteCommand hook.
+	//
+	// The command's AutocompleteData describes its subcommands and arguments so that they can be
+	// suggested to users as they type. The FetchURL of a dynamic list argument is a path, such as
+	// "/autocomplete/projects", that's requested from your plugin's ServeHTTP hook with a GET request
+	// on behalf of the user who's typing. The response should be a JSON list of
+	// model.AutocompleteListItem.
 	RegisterCommand(command *model.Command) error
 
 	// UnregisterCommand unregisters a command previously registered via RegisterCommand.
 	UnregisterCommand(teamId, trigger string) error
 
+	// ScheduleJob schedules a job that's run once or every job.Interval seconds. When the job is due, it's run on one
+	// server in the cluster via the ExecuteScheduledJob hook. Jobs are forgotten when the plugin is deactivated, so
+	// they should be scheduled in OnActivate. Scheduling a job again with the same id replaces it, but a one-shot job
+	// isn't run again for the same job.RunAt.
+	ScheduleJob(job *model.PluginJob) *model.AppError
+
+	// UnscheduleJob stops running a job previously scheduled via ScheduleJob.
+	UnscheduleJob(jobId string) *model.AppError
+
 	// GetSession returns the session object for the Session ID
 	GetSession(sessionId string) (*model.Session, *model.AppError)
 
@@ -62,6 +77,13 @@ type API interface {
 	// The status parameter can be: "online", "away", "dnd", or "offline".
 	UpdateUserStatus(userId, status string) (*model.Status, *model.AppError)
 
+	// UpdateUserCustomStatus will set a user's custom status, which is shown next to their name along with an emoji.
+	// A custom status with ExpiresAt set is removed once that time has passed.
+	UpdateUserCustomStatus(userId string, customStatus *model.CustomStatus) *model.AppError
+
+	// RemoveUserCustomStatus will remove a user's custom status.
+	RemoveUserCustomStatus(userId string) *model.AppError
+
 	// GetLDAPUserAttributes will return LDAP attributes for a user.
 	// The attributes parameter should be a list of attributes to pull.
 	// Returns a map with attribute names as keys and the user's attributes as values.
@@ -122,6 +144,9 @@ type API interface {
 	// GetChannelByNameForTeamName gets a channel by its name, given a team name.
 	GetChannelByNameForTeamName(teamName, channelName string, includeDeleted bool) (*model.Channel, *model.AppError)
 
+	// SearchChannels gets the public channels in a team whose name, display name or purpose match a search term.
+	SearchChannels(teamId, term string) (*model.ChannelList, *model.AppError)
+
 	// GetDirectChannel gets a direct message channel.
 	GetDirectChannel(userId1, userId2 string) (*model.Channel, *model.AppError)
 
@@ -146,6 +171,19 @@ type API interface {
 	// DeleteChannelMember deletes a channel membership for a user.
 	DeleteChannelMember(channelId, userId string) *model.AppError
 
+	// GetChannelSidebarCategories gets the categories that a user has made for their sidebar in a team.
+	GetChannelSidebarCategories(userId, teamId string) ([]*model.SidebarCategory, *model.AppError)
+
+	// CreateChannelSidebarCategory creates a category in a user's sidebar in a team. The channels in the category
+	// must be ones that the user is a member of.
+	CreateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError)
+
+	// UpdateChannelSidebarCategory updates the name, position and channels of a category in a user's sidebar.
+	UpdateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError)
+
+	// DeleteChannelSidebarCategory deletes a category from a user's sidebar.
+	DeleteChannelSidebarCategory(userId, teamId, categoryId string) *model.AppError
+
 	// CreatePost creates a post.
 	CreatePost(post *model.Post) (*model.Post, *model.AppError)
 
@@ -161,12 +199,23 @@ type API interface {
 	// SendEphemeralPost creates an ephemeral post.
 	SendEphemeralPost(userId string, post *model.Post) *model.Post
 
+	// UpdateEphemeralPost replaces an ephemeral post that was sent to a user with SendEphemeralPost. The
+	// post must have the same id as the one that was sent.
+	UpdateEphemeralPost(userId string, post *model.Post) *model.Post
+
+	// DeleteEphemeralPost removes an ephemeral post that was sent to a user with SendEphemeralPost.
+	DeleteEphemeralPost(userId, postId string)
+
 	// DeletePost deletes a post.
 	DeletePost(postId string) *model.AppError
 
 	// GetPost gets a post.
 	GetPost(postId string) (*model.Post, *model.AppError)
 
+	// SearchPosts searches the posts in a team that a user can see. The terms use the same syntax as the search
+	// box, including "from:" and "in:", and isOrSearch matches posts with any of the terms rather than all of them.
+	SearchPosts(teamId, userId, terms string, isOrSearch bool) (*model.PostList, *model.AppError)
+
 	// UpdatePost updates a post.
 	UpdatePost(post *model.Post) (*model.Post, *model.AppError)
 
@@ -193,6 +242,19 @@ type API interface {
 	// KVDelete will remove a key-value pair. Returns nil for non-existent keys.
 	KVDelete(key string) *model.AppError
 
+	// KVSetWithExpiry will store a key-value pair, unique per plugin, that's removed after the given number of
+	// seconds. A value of 0 keeps the pair until it's deleted.
+	KVSetWithExpiry(key string, value []byte, expireInSeconds int64) *model.AppError
+
+	// KVCompareAndSet will store a key-value pair only if the current value is oldValue, returning whether it was
+	// stored. A nil oldValue only stores the pair if the key doesn't have a value. The check is atomic across every
+	// server in a cluster, so it can be used for locks and idempotency keys.
+	KVCompareAndSet(key string, oldValue, newValue []byte) (bool, *model.AppError)
+
+	// KVCompareAndDelete will remove a key-value pair only if the current value is oldValue, returning whether it was
+	// removed.
+	KVCompareAndDelete(key string, oldValue []byte) (bool, *model.AppError)
+
 	// PublishWebSocketEvent sends an event to WebSocket connections.
 	// event is the type and will be prepended with "custom_<pluginid>_"
 	// payload is the data sent with the event. Interface values must be primitive Go types or mattermost-server/model types
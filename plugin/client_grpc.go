@@ -0,0 +1,350 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin/pluginproto"
+)
+
+// Plugins that aren't written in Go can talk to the server over gRPC instead of net/rpc. The protocol is described in
+// pluginproto/plugin.proto, and arguments and return values are passed as JSON so that plugins don't need to know
+// about every type in the model package.
+
+type hooksGRPCClient struct {
+	client      pluginproto.HooksClient
+	log         *mlog.Logger
+	broker      *plugin.GRPCBroker
+	apiImpl     API
+	implemented [TotalHooksId]bool
+}
+
+type apiGRPCServer struct {
+	impl API
+}
+
+func (p *hooksPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	return fmt.Errorf("plugins written in Go are served over net/rpc")
+}
+
+func (p *hooksPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &hooksGRPCClient{client: pluginproto.NewHooksClient(c), log: p.log, broker: b, apiImpl: p.apiImpl}, nil
+}
+
+var _ plugin.GRPCPlugin = &hooksPlugin{}
+var _ Hooks = &hooksGRPCClient{}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// decodeGRPCReturns decodes the JSON array of return values from a hook into the fields of returns, which must be a
+// pointer to a struct with one field for each return value.
+func decodeGRPCReturns(data []byte, returns interface{}) error {
+	var values []json.RawMessage
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+	}
+
+	fields := reflect.ValueOf(returns).Elem()
+	if len(values) != fields.NumField() {
+		return fmt.Errorf("expected %v return values but got %v", fields.NumField(), len(values))
+	}
+
+	for i, value := range values {
+		field := fields.Field(i)
+
+		if field.Type() == errorType {
+			var message *string
+			if err := json.Unmarshal(value, &message); err != nil {
+				return err
+			}
+			if message != nil {
+				field.Set(reflect.ValueOf(errors.New(*message)))
+			}
+			continue
+		}
+
+		if err := json.Unmarshal(value, field.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *hooksGRPCClient) call(name string, args []interface{}, returns interface{}) error {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	response, err := g.client.Call(context.Background(), &pluginproto.CallRequest{
+		Method: name,
+		Args:   encodedArgs,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeGRPCReturns(response.Returns, returns)
+}
+
+//
+// Below are special cases for hooks that can not be auto generated
+//
+
+func (g *hooksGRPCClient) Implemented() ([]string, error) {
+	response, err := g.client.Implemented(context.Background(), &pluginproto.ImplementedRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hookName := range response.Hooks {
+		if hookId, ok := hookNameToId[hookName]; ok {
+			g.implemented[hookId] = true
+		}
+	}
+	return response.Hooks, nil
+}
+
+func (g *hooksGRPCClient) OnActivate() error {
+	brokerId := g.broker.NextId()
+	go g.broker.AcceptAndServe(brokerId, func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		pluginproto.RegisterAPIServer(server, &apiGRPCServer{impl: g.apiImpl})
+		return server
+	})
+
+	response, err := g.client.OnActivate(context.Background(), &pluginproto.OnActivateRequest{
+		ApiBrokerId: brokerId,
+	})
+	if err != nil {
+		g.log.Error("gRPC call to OnActivate plugin failed.", mlog.Err(err))
+		return err
+	}
+
+	returns := &Z_OnActivateReturns{}
+	if err := decodeGRPCReturns(response.Returns, returns); err != nil {
+		return err
+	}
+	return returns.A
+}
+
+type grpcHTTPRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Header     http.Header `json:"header"`
+	Host       string      `json:"host"`
+	RemoteAddr string      `json:"remote_addr"`
+	Body       []byte      `json:"body"`
+}
+
+type grpcHTTPResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+type serveHTTPGRPCReturns struct {
+	A *grpcHTTPResponse
+}
+
+// ServeHTTP passes the whole request to the plugin and writes the whole response that it returns, so unlike plugins
+// that use net/rpc, plugins that use gRPC can't stream responses.
+func (g *hooksGRPCClient) ServeHTTP(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !g.implemented[ServeHTTPId] {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			g.log.Error("Plugin failed to ServeHTTP, couldn't read the request body", mlog.Err(err))
+			http.Error(w, "500 internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	request := &grpcHTTPRequest{
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Header:     r.Header,
+		Host:       r.Host,
+		RemoteAddr: r.RemoteAddr,
+		Body:       body,
+	}
+
+	returns := &serveHTTPGRPCReturns{}
+	if err := g.call("ServeHTTP", []interface{}{c, request}, returns); err != nil {
+		g.log.Error("Plugin failed to ServeHTTP, gRPC call failed", mlog.Err(err))
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	} else if returns.A == nil {
+		g.log.Error("Plugin failed to ServeHTTP, it didn't return a response")
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	for name, values := range returns.A.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	statusCode := returns.A.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	w.Write(returns.A.Body)
+}
+
+type fileWillBeUploadedGRPCReturns struct {
+	A *model.FileInfo
+	B string
+	C []byte
+}
+
+func (g *hooksGRPCClient) FileWillBeUploaded(c *Context, info *model.FileInfo, file io.Reader, output io.Writer) (*model.FileInfo, string) {
+	if !g.implemented[FileWillBeUploadedId] {
+		return info, ""
+	}
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		g.log.Error("Plugin failed to read the uploaded file.", mlog.Err(err))
+		return info, ""
+	}
+
+	returns := &fileWillBeUploadedGRPCReturns{}
+	if err := g.call("FileWillBeUploaded", []interface{}{c, info, data}, returns); err != nil {
+		g.log.Error("gRPC call FileWillBeUploaded to plugin failed.", mlog.Err(err))
+	}
+
+	if returns.C != nil {
+		if _, err := output.Write(returns.C); err != nil {
+			g.log.Error("Error writing replacement file.", mlog.Err(err))
+		}
+	}
+	return returns.A, returns.B
+}
+
+var apiType = reflect.TypeOf((*API)(nil)).Elem()
+
+// Call invokes an API method by name. The arguments are decoded based on the method's signature in the API
+// interface, and the final parameter of variadic methods can be given any number of arguments.
+func (s *apiGRPCServer) Call(ctx context.Context, request *pluginproto.CallRequest) (*pluginproto.CallResponse, error) {
+	if err := checkAPICallLimit(s.impl, request.Method); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	if request.Method == "LoadPluginConfiguration" {
+		return s.loadPluginConfiguration()
+	}
+
+	apiMethod, ok := apiType.MethodByName(request.Method)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "API %v doesn't exist.", request.Method)
+	}
+
+	method := reflect.ValueOf(s.impl).MethodByName(request.Method)
+	if !method.IsValid() {
+		return nil, status.Errorf(codes.Unimplemented, "API %v called but not implemented.", request.Method)
+	}
+
+	args, err := decodeGRPCArgs(request.Args, apiMethod.Type)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "API %v called with invalid arguments: %v", request.Method, err.Error())
+	}
+
+	returns, err := encodeGRPCReturns(method.Call(args), apiMethod.Type)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pluginproto.CallResponse{Returns: returns}, nil
+}
+
+func (s *apiGRPCServer) loadPluginConfiguration() (*pluginproto.CallResponse, error) {
+	var config interface{}
+	returns := []interface{}{nil, nil}
+	if err := s.impl.LoadPluginConfiguration(&config); err != nil {
+		returns[1] = err.Error()
+	} else {
+		returns[0] = config
+	}
+
+	b, err := json.Marshal(returns)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pluginproto.CallResponse{Returns: b}, nil
+}
+
+func decodeGRPCArgs(data []byte, methodType reflect.Type) ([]reflect.Value, error) {
+	var values []json.RawMessage
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	numIn := methodType.NumIn()
+	if len(values) != numIn && !(methodType.IsVariadic() && len(values) >= numIn-1) {
+		return nil, fmt.Errorf("expected %v arguments but got %v", numIn, len(values))
+	}
+
+	args := make([]reflect.Value, len(values))
+	for i, value := range values {
+		var argType reflect.Type
+		if methodType.IsVariadic() && i >= numIn-1 {
+			argType = methodType.In(numIn - 1).Elem()
+		} else {
+			argType = methodType.In(i)
+		}
+
+		arg := reflect.New(argType)
+		if err := json.Unmarshal(value, arg.Interface()); err != nil {
+			return nil, err
+		}
+		args[i] = arg.Elem()
+	}
+
+	return args, nil
+}
+
+func encodeGRPCReturns(values []reflect.Value, methodType reflect.Type) ([]byte, error) {
+	returns := make([]interface{}, len(values))
+	for i, value := range values {
+		if methodType.Out(i) == errorType {
+			if !value.IsNil() {
+				returns[i] = value.Interface().(error).Error()
+			}
+			continue
+		}
+
+		returns[i] = value.Interface()
+	}
+
+	return json.Marshal(returns)
+}
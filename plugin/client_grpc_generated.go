@@ -0,0 +1,213 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make pluginapi"
+// DO NOT EDIT
+
+package plugin
+
+import (
+	"github.com/dyatlov/go-opengraph/opengraph"
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (g *hooksGRPCClient) OnDeactivate() error {
+	_returns := &Z_OnDeactivateReturns{}
+	if g.implemented[OnDeactivateId] {
+		if err := g.call("OnDeactivate", []interface{}{}, _returns); err != nil {
+			g.log.Error("gRPC call OnDeactivate to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A
+}
+
+func (g *hooksGRPCClient) OnConfigurationChange() error {
+	_returns := &Z_OnConfigurationChangeReturns{}
+	if g.implemented[OnConfigurationChangeId] {
+		if err := g.call("OnConfigurationChange", []interface{}{}, _returns); err != nil {
+			g.log.Error("gRPC call OnConfigurationChange to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A
+}
+
+func (g *hooksGRPCClient) ExecuteCommand(c *Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
+	_returns := &Z_ExecuteCommandReturns{}
+	if g.implemented[ExecuteCommandId] {
+		if err := g.call("ExecuteCommand", []interface{}{c, args}, _returns); err != nil {
+			g.log.Error("gRPC call ExecuteCommand to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (g *hooksGRPCClient) MessageWillBePosted(c *Context, post *model.Post) (*model.Post, string) {
+	_returns := &Z_MessageWillBePostedReturns{}
+	if g.implemented[MessageWillBePostedId] {
+		if err := g.call("MessageWillBePosted", []interface{}{c, post}, _returns); err != nil {
+			g.log.Error("gRPC call MessageWillBePosted to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (g *hooksGRPCClient) MessageWillBeUpdated(c *Context, newPost, oldPost *model.Post) (*model.Post, string) {
+	_returns := &Z_MessageWillBeUpdatedReturns{}
+	if g.implemented[MessageWillBeUpdatedId] {
+		if err := g.call("MessageWillBeUpdated", []interface{}{c, newPost, oldPost}, _returns); err != nil {
+			g.log.Error("gRPC call MessageWillBeUpdated to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (g *hooksGRPCClient) MessageHasBeenPosted(c *Context, post *model.Post) {
+	_returns := &Z_MessageHasBeenPostedReturns{}
+	if g.implemented[MessageHasBeenPostedId] {
+		if err := g.call("MessageHasBeenPosted", []interface{}{c, post}, _returns); err != nil {
+			g.log.Error("gRPC call MessageHasBeenPosted to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) MessageHasBeenUpdated(c *Context, newPost, oldPost *model.Post) {
+	_returns := &Z_MessageHasBeenUpdatedReturns{}
+	if g.implemented[MessageHasBeenUpdatedId] {
+		if err := g.call("MessageHasBeenUpdated", []interface{}{c, newPost, oldPost}, _returns); err != nil {
+			g.log.Error("gRPC call MessageHasBeenUpdated to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) ChannelHasBeenCreated(c *Context, channel *model.Channel) {
+	_returns := &Z_ChannelHasBeenCreatedReturns{}
+	if g.implemented[ChannelHasBeenCreatedId] {
+		if err := g.call("ChannelHasBeenCreated", []interface{}{c, channel}, _returns); err != nil {
+			g.log.Error("gRPC call ChannelHasBeenCreated to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserHasJoinedChannel(c *Context, channelMember *model.ChannelMember, actor *model.User) {
+	_returns := &Z_UserHasJoinedChannelReturns{}
+	if g.implemented[UserHasJoinedChannelId] {
+		if err := g.call("UserHasJoinedChannel", []interface{}{c, channelMember, actor}, _returns); err != nil {
+			g.log.Error("gRPC call UserHasJoinedChannel to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserHasLeftChannel(c *Context, channelMember *model.ChannelMember, actor *model.User) {
+	_returns := &Z_UserHasLeftChannelReturns{}
+	if g.implemented[UserHasLeftChannelId] {
+		if err := g.call("UserHasLeftChannel", []interface{}{c, channelMember, actor}, _returns); err != nil {
+			g.log.Error("gRPC call UserHasLeftChannel to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserHasJoinedTeam(c *Context, teamMember *model.TeamMember, actor *model.User) {
+	_returns := &Z_UserHasJoinedTeamReturns{}
+	if g.implemented[UserHasJoinedTeamId] {
+		if err := g.call("UserHasJoinedTeam", []interface{}{c, teamMember, actor}, _returns); err != nil {
+			g.log.Error("gRPC call UserHasJoinedTeam to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserHasLeftTeam(c *Context, teamMember *model.TeamMember, actor *model.User) {
+	_returns := &Z_UserHasLeftTeamReturns{}
+	if g.implemented[UserHasLeftTeamId] {
+		if err := g.call("UserHasLeftTeam", []interface{}{c, teamMember, actor}, _returns); err != nil {
+			g.log.Error("gRPC call UserHasLeftTeam to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserWillLogIn(c *Context, user *model.User) string {
+	_returns := &Z_UserWillLogInReturns{}
+	if g.implemented[UserWillLogInId] {
+		if err := g.call("UserWillLogIn", []interface{}{c, user}, _returns); err != nil {
+			g.log.Error("gRPC call UserWillLogIn to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A
+}
+
+func (g *hooksGRPCClient) UserHasLoggedIn(c *Context, user *model.User) {
+	_returns := &Z_UserHasLoggedInReturns{}
+	if g.implemented[UserHasLoggedInId] {
+		if err := g.call("UserHasLoggedIn", []interface{}{c, user}, _returns); err != nil {
+			g.log.Error("gRPC call UserHasLoggedIn to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) LinkMetadataWillBeSaved(c *Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph {
+	_returns := &Z_LinkMetadataWillBeSavedReturns{}
+	if g.implemented[LinkMetadataWillBeSavedId] {
+		if err := g.call("LinkMetadataWillBeSaved", []interface{}{c, requestURL, og}, _returns); err != nil {
+			g.log.Error("gRPC call LinkMetadataWillBeSaved to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A
+}
+
+func (g *hooksGRPCClient) ExecuteScheduledJob(c *Context, jobId string) {
+	_returns := &Z_ExecuteScheduledJobReturns{}
+	if g.implemented[ExecuteScheduledJobId] {
+		if err := g.call("ExecuteScheduledJob", []interface{}{c, jobId}, _returns); err != nil {
+			g.log.Error("gRPC call ExecuteScheduledJob to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) GetSettingOptions(c *Context, key string) ([]*model.PluginOption, *model.AppError) {
+	_returns := &Z_GetSettingOptionsReturns{}
+	if g.implemented[GetSettingOptionsId] {
+		if err := g.call("GetSettingOptions", []interface{}{c, key}, _returns); err != nil {
+			g.log.Error("gRPC call GetSettingOptions to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (g *hooksGRPCClient) ReactionHasBeenAdded(c *Context, reaction *model.Reaction, post *model.Post) {
+	_returns := &Z_ReactionHasBeenAddedReturns{}
+	if g.implemented[ReactionHasBeenAddedId] {
+		if err := g.call("ReactionHasBeenAdded", []interface{}{c, reaction, post}, _returns); err != nil {
+			g.log.Error("gRPC call ReactionHasBeenAdded to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) ReactionHasBeenRemoved(c *Context, reaction *model.Reaction, post *model.Post) {
+	_returns := &Z_ReactionHasBeenRemovedReturns{}
+	if g.implemented[ReactionHasBeenRemovedId] {
+		if err := g.call("ReactionHasBeenRemoved", []interface{}{c, reaction, post}, _returns); err != nil {
+			g.log.Error("gRPC call ReactionHasBeenRemoved to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (g *hooksGRPCClient) UserStatusHasChanged(c *Context, status *model.Status) {
+	_returns := &Z_UserStatusHasChangedReturns{}
+	if g.implemented[UserStatusHasChangedId] {
+		if err := g.call("UserStatusHasChanged", []interface{}{c, status}, _returns); err != nil {
+			g.log.Error("gRPC call UserStatusHasChanged to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin/pluginproto"
+)
+
+type grpcTestAPI struct {
+	API
+	logged    []string
+	user      *model.User
+	allowCall bool
+}
+
+func (api *grpcTestAPI) LogInfo(msg string, keyValuePairs ...interface{}) {
+	api.logged = append(api.logged, msg)
+}
+
+func (api *grpcTestAPI) GetUser(userId string) (*model.User, *model.AppError) {
+	if userId != api.user.Id {
+		return nil, model.NewAppError("GetUser", "app.user.get.app_error", nil, "", http.StatusNotFound)
+	}
+	return api.user, nil
+}
+
+func (api *grpcTestAPI) AllowAPICall() bool {
+	return api.allowCall
+}
+
+// The plugin is written in Go so that it can be compiled by the test, but it only uses the protocol in
+// pluginproto/plugin.proto like a plugin written in another language would.
+const grpcTestPlugin = `
+	package main
+
+	import (
+		"context"
+		"encoding/json"
+		"fmt"
+
+		"github.com/hashicorp/go-plugin"
+		"google.golang.org/grpc"
+
+		"github.com/mattermost/mattermost-server/plugin/pluginproto"
+	)
+
+	type hooksServer struct {
+		broker *plugin.GRPCBroker
+		api    pluginproto.APIClient
+	}
+
+	func (s *hooksServer) Implemented(ctx context.Context, req *pluginproto.ImplementedRequest) (*pluginproto.ImplementedResponse, error) {
+		return &pluginproto.ImplementedResponse{Hooks: []string{"OnActivate", "MessageWillBePosted", "ServeHTTP"}}, nil
+	}
+
+	func (s *hooksServer) OnActivate(ctx context.Context, req *pluginproto.OnActivateRequest) (*pluginproto.CallResponse, error) {
+		conn, err := s.broker.Dial(req.ApiBrokerId)
+		if err != nil {
+			return nil, err
+		}
+		s.api = pluginproto.NewAPIClient(conn)
+
+		if _, err := s.api.Call(ctx, &pluginproto.CallRequest{Method: "LogInfo", Args: []byte(` + "`" + `["activated", "key", "value"]` + "`" + `)}); err != nil {
+			return nil, err
+		}
+		return &pluginproto.CallResponse{Returns: []byte("[null]")}, nil
+	}
+
+	func (s *hooksServer) Call(ctx context.Context, req *pluginproto.CallRequest) (*pluginproto.CallResponse, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(req.Args, &args); err != nil {
+			return nil, err
+		}
+
+		switch req.Method {
+		case "MessageWillBePosted":
+			var post map[string]interface{}
+			json.Unmarshal(args[1], &post)
+
+			userArgs, _ := json.Marshal([]interface{}{post["user_id"]})
+			response, err := s.api.Call(ctx, &pluginproto.CallRequest{Method: "GetUser", Args: userArgs})
+			if err != nil {
+				return nil, err
+			}
+
+			var user map[string]interface{}
+			var appErr interface{}
+			json.Unmarshal(response.Returns, &[]interface{}{&user, &appErr})
+
+			post["message"] = fmt.Sprintf("%v from %v", post["message"], user["username"])
+			b, _ := json.Marshal([]interface{}{post, ""})
+			return &pluginproto.CallResponse{Returns: b}, nil
+		case "ServeHTTP":
+			var request struct {
+				URL  string ` + "`" + `json:"url"` + "`" + `
+				Body []byte ` + "`" + `json:"body"` + "`" + `
+			}
+			json.Unmarshal(args[1], &request)
+
+			b, _ := json.Marshal([]interface{}{map[string]interface{}{
+				"status_code": 201,
+				"header":      map[string][]string{"X-Url": {request.URL}},
+				"body":        append([]byte("echo "), request.Body...),
+			}})
+			return &pluginproto.CallResponse{Returns: b}, nil
+		}
+
+		return nil, fmt.Errorf("hook %v isn't implemented", req.Method)
+	}
+
+	type hooksPlugin struct {
+		plugin.NetRPCUnsupportedPlugin
+	}
+
+	func (p *hooksPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+		pluginproto.RegisterHooksServer(s, &hooksServer{broker: broker})
+		return nil
+	}
+
+	func (p *hooksPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+		return nil, nil
+	}
+
+	func main() {
+		plugin.Serve(&plugin.ServeConfig{
+			HandshakeConfig: plugin.HandshakeConfig{
+				ProtocolVersion:  1,
+				MagicCookieKey:   "MATTERMOST_PLUGIN",
+				MagicCookieValue: "Securely message teams, anywhere.",
+			},
+			Plugins:    map[string]plugin.Plugin{"hooks": &hooksPlugin{}},
+			GRPCServer: plugin.DefaultGRPCServer,
+		})
+	}
+`
+
+func TestGRPCPlugin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	compileGo(t, grpcTestPlugin, filepath.Join(dir, "backend.exe"))
+	ioutil.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id": "foo", "backend": {"executable": "backend.exe"}}`), 0600)
+
+	api := &grpcTestAPI{
+		user:      &model.User{Id: model.NewId(), Username: "alice"},
+		allowCall: true,
+	}
+
+	bundle := model.BundleInfoForPath(dir)
+	log := mlog.NewLogger(&mlog.LoggerConfiguration{
+		EnableConsole: true,
+		ConsoleJson:   true,
+		ConsoleLevel:  "error",
+		EnableFile:    false,
+	})
+	supervisor, err := newSupervisor(bundle, log, api)
+	require.NoError(t, err)
+	defer supervisor.Shutdown()
+
+	assert.Equal(t, []string{"activated"}, api.logged)
+	assert.True(t, supervisor.Implements(MessageWillBePostedId))
+	assert.False(t, supervisor.Implements(MessageHasBeenPostedId))
+	assert.NoError(t, supervisor.PerformHealthCheck())
+
+	post, rejection := supervisor.Hooks().MessageWillBePosted(&Context{}, &model.Post{UserId: api.user.Id, Message: "hello"})
+	assert.Empty(t, rejection)
+	require.NotNil(t, post)
+	assert.Equal(t, "hello from alice", post.Message)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/path?query=1", strings.NewReader("body"))
+	supervisor.Hooks().ServeHTTP(&Context{}, w, r)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/path?query=1", w.Header().Get("X-Url"))
+	assert.Equal(t, "echo body", w.Body.String())
+}
+
+func TestAPIGRPCServer(t *testing.T) {
+	api := &grpcTestAPI{
+		user:      &model.User{Id: model.NewId(), Username: "alice"},
+		allowCall: true,
+	}
+	server := &apiGRPCServer{impl: api}
+
+	response, err := server.Call(context.Background(), &pluginproto.CallRequest{Method: "GetUser", Args: []byte(`["` + api.user.Id + `"]`)})
+	require.NoError(t, err)
+	assert.Contains(t, string(response.Returns), `"username":"alice"`)
+	assert.True(t, strings.HasSuffix(string(response.Returns), ",null]"))
+
+	response, err = server.Call(context.Background(), &pluginproto.CallRequest{Method: "GetUser", Args: []byte(`["` + model.NewId() + `"]`)})
+	require.NoError(t, err)
+	assert.Contains(t, string(response.Returns), `"status_code":404`)
+
+	_, err = server.Call(context.Background(), &pluginproto.CallRequest{Method: "LogInfo", Args: []byte(`["message", "key", 1, "other", "value"]`)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"message"}, api.logged)
+
+	_, err = server.Call(context.Background(), &pluginproto.CallRequest{Method: "GetUser", Args: []byte(`[]`)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.Call(context.Background(), &pluginproto.CallRequest{Method: "AllowAPICall"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	api.allowCall = false
+	_, err = server.Call(context.Background(), &pluginproto.CallRequest{Method: "GetUser", Args: []byte(`["` + api.user.Id + `"]`)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -62,6 +62,23 @@ type apiRPCServer struct {
 	impl API
 }
 
+// APICallLimiter is implemented by API implementations that limit how often a plugin can call them. Calls that a
+// plugin makes after exceeding its limit fail without reaching the implementation.
+type APICallLimiter interface {
+	AllowAPICall() bool
+}
+
+func (s *apiRPCServer) checkAPICallLimit(name string) error {
+	return checkAPICallLimit(s.impl, name)
+}
+
+func checkAPICallLimit(impl API, name string) error {
+	if limiter, ok := impl.(APICallLimiter); ok && !limiter.AllowAPICall() {
+		return fmt.Errorf("API %v called but the plugin has exceeded its API call limit.", name)
+	}
+	return nil
+}
+
 // Registering some types used by MM for encoding/gob used by rpc
 func init() {
 	gob.Register([]*model.SlackAttachment{})
@@ -207,6 +224,10 @@ func (g *apiRPCClient) LoadPluginConfiguration(dest interface{}) error {
 }
 
 func (s *apiRPCServer) LoadPluginConfiguration(args *Z_LoadPluginConfigurationArgsArgs, returns *Z_LoadPluginConfigurationArgsReturns) error {
+	if err := s.checkAPICallLimit("LoadPluginConfiguration"); err != nil {
+		return err
+	}
+
 	var config interface{}
 	if hook, ok := s.impl.(interface {
 		LoadPluginConfiguration(dest interface{}) error
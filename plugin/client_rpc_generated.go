@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/dyatlov/go-opengraph/opengraph"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 )
@@ -501,6 +502,216 @@ func (s *hooksRPCServer) UserHasLoggedIn(args *Z_UserHasLoggedInArgs, returns *Z
 	return nil
 }
 
+func init() {
+	hookNameToId["LinkMetadataWillBeSaved"] = LinkMetadataWillBeSavedId
+}
+
+type Z_LinkMetadataWillBeSavedArgs struct {
+	A *Context
+	B string
+	C *opengraph.OpenGraph
+}
+
+type Z_LinkMetadataWillBeSavedReturns struct {
+	A *opengraph.OpenGraph
+}
+
+func (g *hooksRPCClient) LinkMetadataWillBeSaved(c *Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph {
+	_args := &Z_LinkMetadataWillBeSavedArgs{c, requestURL, og}
+	_returns := &Z_LinkMetadataWillBeSavedReturns{}
+	if g.implemented[LinkMetadataWillBeSavedId] {
+		if err := g.client.Call("Plugin.LinkMetadataWillBeSaved", _args, _returns); err != nil {
+			g.log.Error("RPC call LinkMetadataWillBeSaved to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A
+}
+
+func (s *hooksRPCServer) LinkMetadataWillBeSaved(args *Z_LinkMetadataWillBeSavedArgs, returns *Z_LinkMetadataWillBeSavedReturns) error {
+	if hook, ok := s.impl.(interface {
+		LinkMetadataWillBeSaved(c *Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph
+	}); ok {
+		returns.A = hook.LinkMetadataWillBeSaved(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("Hook LinkMetadataWillBeSaved called but not implemented.")
+	}
+	return nil
+}
+
+func init() {
+	hookNameToId["ExecuteScheduledJob"] = ExecuteScheduledJobId
+}
+
+type Z_ExecuteScheduledJobArgs struct {
+	A *Context
+	B string
+}
+
+type Z_ExecuteScheduledJobReturns struct {
+}
+
+func (g *hooksRPCClient) ExecuteScheduledJob(c *Context, jobId string) {
+	_args := &Z_ExecuteScheduledJobArgs{c, jobId}
+	_returns := &Z_ExecuteScheduledJobReturns{}
+	if g.implemented[ExecuteScheduledJobId] {
+		if err := g.client.Call("Plugin.ExecuteScheduledJob", _args, _returns); err != nil {
+			g.log.Error("RPC call ExecuteScheduledJob to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (s *hooksRPCServer) ExecuteScheduledJob(args *Z_ExecuteScheduledJobArgs, returns *Z_ExecuteScheduledJobReturns) error {
+	if hook, ok := s.impl.(interface {
+		ExecuteScheduledJob(c *Context, jobId string)
+	}); ok {
+		hook.ExecuteScheduledJob(args.A, args.B)
+	} else {
+		return fmt.Errorf("Hook ExecuteScheduledJob called but not implemented.")
+	}
+	return nil
+}
+
+func init() {
+	hookNameToId["GetSettingOptions"] = GetSettingOptionsId
+}
+
+type Z_GetSettingOptionsArgs struct {
+	A *Context
+	B string
+}
+
+type Z_GetSettingOptionsReturns struct {
+	A []*model.PluginOption
+	B *model.AppError
+}
+
+func (g *hooksRPCClient) GetSettingOptions(c *Context, key string) ([]*model.PluginOption, *model.AppError) {
+	_args := &Z_GetSettingOptionsArgs{c, key}
+	_returns := &Z_GetSettingOptionsReturns{}
+	if g.implemented[GetSettingOptionsId] {
+		if err := g.client.Call("Plugin.GetSettingOptions", _args, _returns); err != nil {
+			g.log.Error("RPC call GetSettingOptions to plugin failed.", mlog.Err(err))
+		}
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *hooksRPCServer) GetSettingOptions(args *Z_GetSettingOptionsArgs, returns *Z_GetSettingOptionsReturns) error {
+	if hook, ok := s.impl.(interface {
+		GetSettingOptions(c *Context, key string) ([]*model.PluginOption, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.GetSettingOptions(args.A, args.B)
+	} else {
+		return fmt.Errorf("Hook GetSettingOptions called but not implemented.")
+	}
+	return nil
+}
+
+func init() {
+	hookNameToId["ReactionHasBeenAdded"] = ReactionHasBeenAddedId
+}
+
+type Z_ReactionHasBeenAddedArgs struct {
+	A *Context
+	B *model.Reaction
+	C *model.Post
+}
+
+type Z_ReactionHasBeenAddedReturns struct {
+}
+
+func (g *hooksRPCClient) ReactionHasBeenAdded(c *Context, reaction *model.Reaction, post *model.Post) {
+	_args := &Z_ReactionHasBeenAddedArgs{c, reaction, post}
+	_returns := &Z_ReactionHasBeenAddedReturns{}
+	if g.implemented[ReactionHasBeenAddedId] {
+		if err := g.client.Call("Plugin.ReactionHasBeenAdded", _args, _returns); err != nil {
+			g.log.Error("RPC call ReactionHasBeenAdded to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (s *hooksRPCServer) ReactionHasBeenAdded(args *Z_ReactionHasBeenAddedArgs, returns *Z_ReactionHasBeenAddedReturns) error {
+	if hook, ok := s.impl.(interface {
+		ReactionHasBeenAdded(c *Context, reaction *model.Reaction, post *model.Post)
+	}); ok {
+		hook.ReactionHasBeenAdded(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("Hook ReactionHasBeenAdded called but not implemented.")
+	}
+	return nil
+}
+
+func init() {
+	hookNameToId["ReactionHasBeenRemoved"] = ReactionHasBeenRemovedId
+}
+
+type Z_ReactionHasBeenRemovedArgs struct {
+	A *Context
+	B *model.Reaction
+	C *model.Post
+}
+
+type Z_ReactionHasBeenRemovedReturns struct {
+}
+
+func (g *hooksRPCClient) ReactionHasBeenRemoved(c *Context, reaction *model.Reaction, post *model.Post) {
+	_args := &Z_ReactionHasBeenRemovedArgs{c, reaction, post}
+	_returns := &Z_ReactionHasBeenRemovedReturns{}
+	if g.implemented[ReactionHasBeenRemovedId] {
+		if err := g.client.Call("Plugin.ReactionHasBeenRemoved", _args, _returns); err != nil {
+			g.log.Error("RPC call ReactionHasBeenRemoved to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (s *hooksRPCServer) ReactionHasBeenRemoved(args *Z_ReactionHasBeenRemovedArgs, returns *Z_ReactionHasBeenRemovedReturns) error {
+	if hook, ok := s.impl.(interface {
+		ReactionHasBeenRemoved(c *Context, reaction *model.Reaction, post *model.Post)
+	}); ok {
+		hook.ReactionHasBeenRemoved(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("Hook ReactionHasBeenRemoved called but not implemented.")
+	}
+	return nil
+}
+
+func init() {
+	hookNameToId["UserStatusHasChanged"] = UserStatusHasChangedId
+}
+
+type Z_UserStatusHasChangedArgs struct {
+	A *Context
+	B *model.Status
+}
+
+type Z_UserStatusHasChangedReturns struct {
+}
+
+func (g *hooksRPCClient) UserStatusHasChanged(c *Context, status *model.Status) {
+	_args := &Z_UserStatusHasChangedArgs{c, status}
+	_returns := &Z_UserStatusHasChangedReturns{}
+	if g.implemented[UserStatusHasChangedId] {
+		if err := g.client.Call("Plugin.UserStatusHasChanged", _args, _returns); err != nil {
+			g.log.Error("RPC call UserStatusHasChanged to plugin failed.", mlog.Err(err))
+		}
+	}
+
+}
+
+func (s *hooksRPCServer) UserStatusHasChanged(args *Z_UserStatusHasChangedArgs, returns *Z_UserStatusHasChangedReturns) error {
+	if hook, ok := s.impl.(interface {
+		UserStatusHasChanged(c *Context, status *model.Status)
+	}); ok {
+		hook.UserStatusHasChanged(args.A, args.B)
+	} else {
+		return fmt.Errorf("Hook UserStatusHasChanged called but not implemented.")
+	}
+	return nil
+}
+
 type Z_RegisterCommandArgs struct {
 	A *model.Command
 }
@@ -519,6 +730,9 @@ func (g *apiRPCClient) RegisterCommand(command *model.Command) error {
 }
 
 func (s *apiRPCServer) RegisterCommand(args *Z_RegisterCommandArgs, returns *Z_RegisterCommandReturns) error {
+	if err := s.checkAPICallLimit("RegisterCommand"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		RegisterCommand(command *model.Command) error
 	}); ok {
@@ -548,6 +762,9 @@ func (g *apiRPCClient) UnregisterCommand(teamId, trigger string) error {
 }
 
 func (s *apiRPCServer) UnregisterCommand(args *Z_UnregisterCommandArgs, returns *Z_UnregisterCommandReturns) error {
+	if err := s.checkAPICallLimit("UnregisterCommand"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UnregisterCommand(teamId, trigger string) error
 	}); ok {
@@ -558,6 +775,68 @@ func (s *apiRPCServer) UnregisterCommand(args *Z_UnregisterCommandArgs, returns
 	return nil
 }
 
+type Z_ScheduleJobArgs struct {
+	A *model.PluginJob
+}
+
+type Z_ScheduleJobReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) ScheduleJob(job *model.PluginJob) *model.AppError {
+	_args := &Z_ScheduleJobArgs{job}
+	_returns := &Z_ScheduleJobReturns{}
+	if err := g.client.Call("Plugin.ScheduleJob", _args, _returns); err != nil {
+		log.Printf("RPC call to ScheduleJob API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) ScheduleJob(args *Z_ScheduleJobArgs, returns *Z_ScheduleJobReturns) error {
+	if err := s.checkAPICallLimit("ScheduleJob"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		ScheduleJob(job *model.PluginJob) *model.AppError
+	}); ok {
+		returns.A = hook.ScheduleJob(args.A)
+	} else {
+		return fmt.Errorf("API ScheduleJob called but not implemented.")
+	}
+	return nil
+}
+
+type Z_UnscheduleJobArgs struct {
+	A string
+}
+
+type Z_UnscheduleJobReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) UnscheduleJob(jobId string) *model.AppError {
+	_args := &Z_UnscheduleJobArgs{jobId}
+	_returns := &Z_UnscheduleJobReturns{}
+	if err := g.client.Call("Plugin.UnscheduleJob", _args, _returns); err != nil {
+		log.Printf("RPC call to UnscheduleJob API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) UnscheduleJob(args *Z_UnscheduleJobArgs, returns *Z_UnscheduleJobReturns) error {
+	if err := s.checkAPICallLimit("UnscheduleJob"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		UnscheduleJob(jobId string) *model.AppError
+	}); ok {
+		returns.A = hook.UnscheduleJob(args.A)
+	} else {
+		return fmt.Errorf("API UnscheduleJob called but not implemented.")
+	}
+	return nil
+}
+
 type Z_GetSessionArgs struct {
 	A string
 }
@@ -577,6 +856,9 @@ func (g *apiRPCClient) GetSession(sessionId string) (*model.Session, *model.AppE
 }
 
 func (s *apiRPCServer) GetSession(args *Z_GetSessionArgs, returns *Z_GetSessionReturns) error {
+	if err := s.checkAPICallLimit("GetSession"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetSession(sessionId string) (*model.Session, *model.AppError)
 	}); ok {
@@ -604,6 +886,9 @@ func (g *apiRPCClient) GetConfig() *model.Config {
 }
 
 func (s *apiRPCServer) GetConfig(args *Z_GetConfigArgs, returns *Z_GetConfigReturns) error {
+	if err := s.checkAPICallLimit("GetConfig"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetConfig() *model.Config
 	}); ok {
@@ -632,6 +917,9 @@ func (g *apiRPCClient) SaveConfig(config *model.Config) *model.AppError {
 }
 
 func (s *apiRPCServer) SaveConfig(args *Z_SaveConfigArgs, returns *Z_SaveConfigReturns) error {
+	if err := s.checkAPICallLimit("SaveConfig"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		SaveConfig(config *model.Config) *model.AppError
 	}); ok {
@@ -661,6 +949,9 @@ func (g *apiRPCClient) CreateUser(user *model.User) (*model.User, *model.AppErro
 }
 
 func (s *apiRPCServer) CreateUser(args *Z_CreateUserArgs, returns *Z_CreateUserReturns) error {
+	if err := s.checkAPICallLimit("CreateUser"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreateUser(user *model.User) (*model.User, *model.AppError)
 	}); ok {
@@ -689,6 +980,9 @@ func (g *apiRPCClient) DeleteUser(userId string) *model.AppError {
 }
 
 func (s *apiRPCServer) DeleteUser(args *Z_DeleteUserArgs, returns *Z_DeleteUserReturns) error {
+	if err := s.checkAPICallLimit("DeleteUser"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeleteUser(userId string) *model.AppError
 	}); ok {
@@ -718,6 +1012,9 @@ func (g *apiRPCClient) GetUser(userId string) (*model.User, *model.AppError) {
 }
 
 func (s *apiRPCServer) GetUser(args *Z_GetUserArgs, returns *Z_GetUserReturns) error {
+	if err := s.checkAPICallLimit("GetUser"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetUser(userId string) (*model.User, *model.AppError)
 	}); ok {
@@ -747,6 +1044,9 @@ func (g *apiRPCClient) GetUserByEmail(email string) (*model.User, *model.AppErro
 }
 
 func (s *apiRPCServer) GetUserByEmail(args *Z_GetUserByEmailArgs, returns *Z_GetUserByEmailReturns) error {
+	if err := s.checkAPICallLimit("GetUserByEmail"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetUserByEmail(email string) (*model.User, *model.AppError)
 	}); ok {
@@ -776,6 +1076,9 @@ func (g *apiRPCClient) GetUserByUsername(name string) (*model.User, *model.AppEr
 }
 
 func (s *apiRPCServer) GetUserByUsername(args *Z_GetUserByUsernameArgs, returns *Z_GetUserByUsernameReturns) error {
+	if err := s.checkAPICallLimit("GetUserByUsername"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetUserByUsername(name string) (*model.User, *model.AppError)
 	}); ok {
@@ -805,6 +1108,9 @@ func (g *apiRPCClient) UpdateUser(user *model.User) (*model.User, *model.AppErro
 }
 
 func (s *apiRPCServer) UpdateUser(args *Z_UpdateUserArgs, returns *Z_UpdateUserReturns) error {
+	if err := s.checkAPICallLimit("UpdateUser"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateUser(user *model.User) (*model.User, *model.AppError)
 	}); ok {
@@ -834,6 +1140,9 @@ func (g *apiRPCClient) GetUserStatus(userId string) (*model.Status, *model.AppEr
 }
 
 func (s *apiRPCServer) GetUserStatus(args *Z_GetUserStatusArgs, returns *Z_GetUserStatusReturns) error {
+	if err := s.checkAPICallLimit("GetUserStatus"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetUserStatus(userId string) (*model.Status, *model.AppError)
 	}); ok {
@@ -863,6 +1172,9 @@ func (g *apiRPCClient) GetUserStatusesByIds(userIds []string) ([]*model.Status,
 }
 
 func (s *apiRPCServer) GetUserStatusesByIds(args *Z_GetUserStatusesByIdsArgs, returns *Z_GetUserStatusesByIdsReturns) error {
+	if err := s.checkAPICallLimit("GetUserStatusesByIds"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetUserStatusesByIds(userIds []string) ([]*model.Status, *model.AppError)
 	}); ok {
@@ -893,6 +1205,9 @@ func (g *apiRPCClient) UpdateUserStatus(userId, status string) (*model.Status, *
 }
 
 func (s *apiRPCServer) UpdateUserStatus(args *Z_UpdateUserStatusArgs, returns *Z_UpdateUserStatusReturns) error {
+	if err := s.checkAPICallLimit("UpdateUserStatus"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateUserStatus(userId, status string) (*model.Status, *model.AppError)
 	}); ok {
@@ -903,6 +1218,69 @@ func (s *apiRPCServer) UpdateUserStatus(args *Z_UpdateUserStatusArgs, returns *Z
 	return nil
 }
 
+type Z_UpdateUserCustomStatusArgs struct {
+	A string
+	B *model.CustomStatus
+}
+
+type Z_UpdateUserCustomStatusReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) UpdateUserCustomStatus(userId string, customStatus *model.CustomStatus) *model.AppError {
+	_args := &Z_UpdateUserCustomStatusArgs{userId, customStatus}
+	_returns := &Z_UpdateUserCustomStatusReturns{}
+	if err := g.client.Call("Plugin.UpdateUserCustomStatus", _args, _returns); err != nil {
+		log.Printf("RPC call to UpdateUserCustomStatus API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) UpdateUserCustomStatus(args *Z_UpdateUserCustomStatusArgs, returns *Z_UpdateUserCustomStatusReturns) error {
+	if err := s.checkAPICallLimit("UpdateUserCustomStatus"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		UpdateUserCustomStatus(userId string, customStatus *model.CustomStatus) *model.AppError
+	}); ok {
+		returns.A = hook.UpdateUserCustomStatus(args.A, args.B)
+	} else {
+		return fmt.Errorf("API UpdateUserCustomStatus called but not implemented.")
+	}
+	return nil
+}
+
+type Z_RemoveUserCustomStatusArgs struct {
+	A string
+}
+
+type Z_RemoveUserCustomStatusReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) RemoveUserCustomStatus(userId string) *model.AppError {
+	_args := &Z_RemoveUserCustomStatusArgs{userId}
+	_returns := &Z_RemoveUserCustomStatusReturns{}
+	if err := g.client.Call("Plugin.RemoveUserCustomStatus", _args, _returns); err != nil {
+		log.Printf("RPC call to RemoveUserCustomStatus API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) RemoveUserCustomStatus(args *Z_RemoveUserCustomStatusArgs, returns *Z_RemoveUserCustomStatusReturns) error {
+	if err := s.checkAPICallLimit("RemoveUserCustomStatus"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		RemoveUserCustomStatus(userId string) *model.AppError
+	}); ok {
+		returns.A = hook.RemoveUserCustomStatus(args.A)
+	} else {
+		return fmt.Errorf("API RemoveUserCustomStatus called but not implemented.")
+	}
+	return nil
+}
+
 type Z_GetLDAPUserAttributesArgs struct {
 	A string
 	B []string
@@ -923,6 +1301,9 @@ func (g *apiRPCClient) GetLDAPUserAttributes(userId string, attributes []string)
 }
 
 func (s *apiRPCServer) GetLDAPUserAttributes(args *Z_GetLDAPUserAttributesArgs, returns *Z_GetLDAPUserAttributesReturns) error {
+	if err := s.checkAPICallLimit("GetLDAPUserAttributes"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetLDAPUserAttributes(userId string, attributes []string) (map[string]string, *model.AppError)
 	}); ok {
@@ -952,6 +1333,9 @@ func (g *apiRPCClient) CreateTeam(team *model.Team) (*model.Team, *model.AppErro
 }
 
 func (s *apiRPCServer) CreateTeam(args *Z_CreateTeamArgs, returns *Z_CreateTeamReturns) error {
+	if err := s.checkAPICallLimit("CreateTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreateTeam(team *model.Team) (*model.Team, *model.AppError)
 	}); ok {
@@ -980,6 +1364,9 @@ func (g *apiRPCClient) DeleteTeam(teamId string) *model.AppError {
 }
 
 func (s *apiRPCServer) DeleteTeam(args *Z_DeleteTeamArgs, returns *Z_DeleteTeamReturns) error {
+	if err := s.checkAPICallLimit("DeleteTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeleteTeam(teamId string) *model.AppError
 	}); ok {
@@ -1008,6 +1395,9 @@ func (g *apiRPCClient) GetTeams() ([]*model.Team, *model.AppError) {
 }
 
 func (s *apiRPCServer) GetTeams(args *Z_GetTeamsArgs, returns *Z_GetTeamsReturns) error {
+	if err := s.checkAPICallLimit("GetTeams"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetTeams() ([]*model.Team, *model.AppError)
 	}); ok {
@@ -1037,6 +1427,9 @@ func (g *apiRPCClient) GetTeam(teamId string) (*model.Team, *model.AppError) {
 }
 
 func (s *apiRPCServer) GetTeam(args *Z_GetTeamArgs, returns *Z_GetTeamReturns) error {
+	if err := s.checkAPICallLimit("GetTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetTeam(teamId string) (*model.Team, *model.AppError)
 	}); ok {
@@ -1066,6 +1459,9 @@ func (g *apiRPCClient) GetTeamByName(name string) (*model.Team, *model.AppError)
 }
 
 func (s *apiRPCServer) GetTeamByName(args *Z_GetTeamByNameArgs, returns *Z_GetTeamByNameReturns) error {
+	if err := s.checkAPICallLimit("GetTeamByName"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetTeamByName(name string) (*model.Team, *model.AppError)
 	}); ok {
@@ -1095,6 +1491,9 @@ func (g *apiRPCClient) UpdateTeam(team *model.Team) (*model.Team, *model.AppErro
 }
 
 func (s *apiRPCServer) UpdateTeam(args *Z_UpdateTeamArgs, returns *Z_UpdateTeamReturns) error {
+	if err := s.checkAPICallLimit("UpdateTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateTeam(team *model.Team) (*model.Team, *model.AppError)
 	}); ok {
@@ -1125,6 +1524,9 @@ func (g *apiRPCClient) CreateTeamMember(teamId, userId string) (*model.TeamMembe
 }
 
 func (s *apiRPCServer) CreateTeamMember(args *Z_CreateTeamMemberArgs, returns *Z_CreateTeamMemberReturns) error {
+	if err := s.checkAPICallLimit("CreateTeamMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreateTeamMember(teamId, userId string) (*model.TeamMember, *model.AppError)
 	}); ok {
@@ -1156,6 +1558,9 @@ func (g *apiRPCClient) CreateTeamMembers(teamId string, userIds []string, reques
 }
 
 func (s *apiRPCServer) CreateTeamMembers(args *Z_CreateTeamMembersArgs, returns *Z_CreateTeamMembersReturns) error {
+	if err := s.checkAPICallLimit("CreateTeamMembers"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreateTeamMembers(teamId string, userIds []string, requestorId string) ([]*model.TeamMember, *model.AppError)
 	}); ok {
@@ -1186,6 +1591,9 @@ func (g *apiRPCClient) DeleteTeamMember(teamId, userId, requestorId string) *mod
 }
 
 func (s *apiRPCServer) DeleteTeamMember(args *Z_DeleteTeamMemberArgs, returns *Z_DeleteTeamMemberReturns) error {
+	if err := s.checkAPICallLimit("DeleteTeamMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeleteTeamMember(teamId, userId, requestorId string) *model.AppError
 	}); ok {
@@ -1217,6 +1625,9 @@ func (g *apiRPCClient) GetTeamMembers(teamId string, offset, limit int) ([]*mode
 }
 
 func (s *apiRPCServer) GetTeamMembers(args *Z_GetTeamMembersArgs, returns *Z_GetTeamMembersReturns) error {
+	if err := s.checkAPICallLimit("GetTeamMembers"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetTeamMembers(teamId string, offset, limit int) ([]*model.TeamMember, *model.AppError)
 	}); ok {
@@ -1247,6 +1658,9 @@ func (g *apiRPCClient) GetTeamMember(teamId, userId string) (*model.TeamMember,
 }
 
 func (s *apiRPCServer) GetTeamMember(args *Z_GetTeamMemberArgs, returns *Z_GetTeamMemberReturns) error {
+	if err := s.checkAPICallLimit("GetTeamMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetTeamMember(teamId, userId string) (*model.TeamMember, *model.AppError)
 	}); ok {
@@ -1278,6 +1692,9 @@ func (g *apiRPCClient) UpdateTeamMemberRoles(teamId, userId, newRoles string) (*
 }
 
 func (s *apiRPCServer) UpdateTeamMemberRoles(args *Z_UpdateTeamMemberRolesArgs, returns *Z_UpdateTeamMemberRolesReturns) error {
+	if err := s.checkAPICallLimit("UpdateTeamMemberRoles"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateTeamMemberRoles(teamId, userId, newRoles string) (*model.TeamMember, *model.AppError)
 	}); ok {
@@ -1307,6 +1724,9 @@ func (g *apiRPCClient) CreateChannel(channel *model.Channel) (*model.Channel, *m
 }
 
 func (s *apiRPCServer) CreateChannel(args *Z_CreateChannelArgs, returns *Z_CreateChannelReturns) error {
+	if err := s.checkAPICallLimit("CreateChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreateChannel(channel *model.Channel) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1335,6 +1755,9 @@ func (g *apiRPCClient) DeleteChannel(channelId string) *model.AppError {
 }
 
 func (s *apiRPCServer) DeleteChannel(args *Z_DeleteChannelArgs, returns *Z_DeleteChannelReturns) error {
+	if err := s.checkAPICallLimit("DeleteChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeleteChannel(channelId string) *model.AppError
 	}); ok {
@@ -1366,6 +1789,9 @@ func (g *apiRPCClient) GetPublicChannelsForTeam(teamId string, offset, limit int
 }
 
 func (s *apiRPCServer) GetPublicChannelsForTeam(args *Z_GetPublicChannelsForTeamArgs, returns *Z_GetPublicChannelsForTeamReturns) error {
+	if err := s.checkAPICallLimit("GetPublicChannelsForTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetPublicChannelsForTeam(teamId string, offset, limit int) (*model.ChannelList, *model.AppError)
 	}); ok {
@@ -1395,6 +1821,9 @@ func (g *apiRPCClient) GetChannel(channelId string) (*model.Channel, *model.AppE
 }
 
 func (s *apiRPCServer) GetChannel(args *Z_GetChannelArgs, returns *Z_GetChannelReturns) error {
+	if err := s.checkAPICallLimit("GetChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetChannel(channelId string) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1426,6 +1855,9 @@ func (g *apiRPCClient) GetChannelByName(teamId, name string, includeDeleted bool
 }
 
 func (s *apiRPCServer) GetChannelByName(args *Z_GetChannelByNameArgs, returns *Z_GetChannelByNameReturns) error {
+	if err := s.checkAPICallLimit("GetChannelByName"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetChannelByName(teamId, name string, includeDeleted bool) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1457,6 +1889,9 @@ func (g *apiRPCClient) GetChannelByNameForTeamName(teamName, channelName string,
 }
 
 func (s *apiRPCServer) GetChannelByNameForTeamName(args *Z_GetChannelByNameForTeamNameArgs, returns *Z_GetChannelByNameForTeamNameReturns) error {
+	if err := s.checkAPICallLimit("GetChannelByNameForTeamName"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetChannelByNameForTeamName(teamName, channelName string, includeDeleted bool) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1467,6 +1902,39 @@ func (s *apiRPCServer) GetChannelByNameForTeamName(args *Z_GetChannelByNameForTe
 	return nil
 }
 
+type Z_SearchChannelsArgs struct {
+	A string
+	B string
+}
+
+type Z_SearchChannelsReturns struct {
+	A *model.ChannelList
+	B *model.AppError
+}
+
+func (g *apiRPCClient) SearchChannels(teamId, term string) (*model.ChannelList, *model.AppError) {
+	_args := &Z_SearchChannelsArgs{teamId, term}
+	_returns := &Z_SearchChannelsReturns{}
+	if err := g.client.Call("Plugin.SearchChannels", _args, _returns); err != nil {
+		log.Printf("RPC call to SearchChannels API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) SearchChannels(args *Z_SearchChannelsArgs, returns *Z_SearchChannelsReturns) error {
+	if err := s.checkAPICallLimit("SearchChannels"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		SearchChannels(teamId, term string) (*model.ChannelList, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.SearchChannels(args.A, args.B)
+	} else {
+		return fmt.Errorf("API SearchChannels called but not implemented.")
+	}
+	return nil
+}
+
 type Z_GetDirectChannelArgs struct {
 	A string
 	B string
@@ -1487,6 +1955,9 @@ func (g *apiRPCClient) GetDirectChannel(userId1, userId2 string) (*model.Channel
 }
 
 func (s *apiRPCServer) GetDirectChannel(args *Z_GetDirectChannelArgs, returns *Z_GetDirectChannelReturns) error {
+	if err := s.checkAPICallLimit("GetDirectChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetDirectChannel(userId1, userId2 string) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1516,6 +1987,9 @@ func (g *apiRPCClient) GetGroupChannel(userIds []string) (*model.Channel, *model
 }
 
 func (s *apiRPCServer) GetGroupChannel(args *Z_GetGroupChannelArgs, returns *Z_GetGroupChannelReturns) error {
+	if err := s.checkAPICallLimit("GetGroupChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetGroupChannel(userIds []string) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1545,6 +2019,9 @@ func (g *apiRPCClient) UpdateChannel(channel *model.Channel) (*model.Channel, *m
 }
 
 func (s *apiRPCServer) UpdateChannel(args *Z_UpdateChannelArgs, returns *Z_UpdateChannelReturns) error {
+	if err := s.checkAPICallLimit("UpdateChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateChannel(channel *model.Channel) (*model.Channel, *model.AppError)
 	}); ok {
@@ -1575,6 +2052,9 @@ func (g *apiRPCClient) AddChannelMember(channelId, userId string) (*model.Channe
 }
 
 func (s *apiRPCServer) AddChannelMember(args *Z_AddChannelMemberArgs, returns *Z_AddChannelMemberReturns) error {
+	if err := s.checkAPICallLimit("AddChannelMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		AddChannelMember(channelId, userId string) (*model.ChannelMember, *model.AppError)
 	}); ok {
@@ -1605,6 +2085,9 @@ func (g *apiRPCClient) GetChannelMember(channelId, userId string) (*model.Channe
 }
 
 func (s *apiRPCServer) GetChannelMember(args *Z_GetChannelMemberArgs, returns *Z_GetChannelMemberReturns) error {
+	if err := s.checkAPICallLimit("GetChannelMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetChannelMember(channelId, userId string) (*model.ChannelMember, *model.AppError)
 	}); ok {
@@ -1636,6 +2119,9 @@ func (g *apiRPCClient) UpdateChannelMemberRoles(channelId, userId, newRoles stri
 }
 
 func (s *apiRPCServer) UpdateChannelMemberRoles(args *Z_UpdateChannelMemberRolesArgs, returns *Z_UpdateChannelMemberRolesReturns) error {
+	if err := s.checkAPICallLimit("UpdateChannelMemberRoles"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateChannelMemberRoles(channelId, userId, newRoles string) (*model.ChannelMember, *model.AppError)
 	}); ok {
@@ -1667,6 +2153,9 @@ func (g *apiRPCClient) UpdateChannelMemberNotifications(channelId, userId string
 }
 
 func (s *apiRPCServer) UpdateChannelMemberNotifications(args *Z_UpdateChannelMemberNotificationsArgs, returns *Z_UpdateChannelMemberNotificationsReturns) error {
+	if err := s.checkAPICallLimit("UpdateChannelMemberNotifications"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdateChannelMemberNotifications(channelId, userId string, notifications map[string]string) (*model.ChannelMember, *model.AppError)
 	}); ok {
@@ -1696,6 +2185,9 @@ func (g *apiRPCClient) DeleteChannelMember(channelId, userId string) *model.AppE
 }
 
 func (s *apiRPCServer) DeleteChannelMember(args *Z_DeleteChannelMemberArgs, returns *Z_DeleteChannelMemberReturns) error {
+	if err := s.checkAPICallLimit("DeleteChannelMember"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeleteChannelMember(channelId, userId string) *model.AppError
 	}); ok {
@@ -1706,6 +2198,140 @@ func (s *apiRPCServer) DeleteChannelMember(args *Z_DeleteChannelMemberArgs, retu
 	return nil
 }
 
+type Z_GetChannelSidebarCategoriesArgs struct {
+	A string
+	B string
+}
+
+type Z_GetChannelSidebarCategoriesReturns struct {
+	A []*model.SidebarCategory
+	B *model.AppError
+}
+
+func (g *apiRPCClient) GetChannelSidebarCategories(userId, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	_args := &Z_GetChannelSidebarCategoriesArgs{userId, teamId}
+	_returns := &Z_GetChannelSidebarCategoriesReturns{}
+	if err := g.client.Call("Plugin.GetChannelSidebarCategories", _args, _returns); err != nil {
+		log.Printf("RPC call to GetChannelSidebarCategories API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) GetChannelSidebarCategories(args *Z_GetChannelSidebarCategoriesArgs, returns *Z_GetChannelSidebarCategoriesReturns) error {
+	if err := s.checkAPICallLimit("GetChannelSidebarCategories"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		GetChannelSidebarCategories(userId, teamId string) ([]*model.SidebarCategory, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.GetChannelSidebarCategories(args.A, args.B)
+	} else {
+		return fmt.Errorf("API GetChannelSidebarCategories called but not implemented.")
+	}
+	return nil
+}
+
+type Z_CreateChannelSidebarCategoryArgs struct {
+	A string
+	B string
+	C *model.SidebarCategory
+}
+
+type Z_CreateChannelSidebarCategoryReturns struct {
+	A *model.SidebarCategory
+	B *model.AppError
+}
+
+func (g *apiRPCClient) CreateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	_args := &Z_CreateChannelSidebarCategoryArgs{userId, teamId, category}
+	_returns := &Z_CreateChannelSidebarCategoryReturns{}
+	if err := g.client.Call("Plugin.CreateChannelSidebarCategory", _args, _returns); err != nil {
+		log.Printf("RPC call to CreateChannelSidebarCategory API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) CreateChannelSidebarCategory(args *Z_CreateChannelSidebarCategoryArgs, returns *Z_CreateChannelSidebarCategoryReturns) error {
+	if err := s.checkAPICallLimit("CreateChannelSidebarCategory"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		CreateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.CreateChannelSidebarCategory(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("API CreateChannelSidebarCategory called but not implemented.")
+	}
+	return nil
+}
+
+type Z_UpdateChannelSidebarCategoryArgs struct {
+	A string
+	B string
+	C *model.SidebarCategory
+}
+
+type Z_UpdateChannelSidebarCategoryReturns struct {
+	A *model.SidebarCategory
+	B *model.AppError
+}
+
+func (g *apiRPCClient) UpdateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	_args := &Z_UpdateChannelSidebarCategoryArgs{userId, teamId, category}
+	_returns := &Z_UpdateChannelSidebarCategoryReturns{}
+	if err := g.client.Call("Plugin.UpdateChannelSidebarCategory", _args, _returns); err != nil {
+		log.Printf("RPC call to UpdateChannelSidebarCategory API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) UpdateChannelSidebarCategory(args *Z_UpdateChannelSidebarCategoryArgs, returns *Z_UpdateChannelSidebarCategoryReturns) error {
+	if err := s.checkAPICallLimit("UpdateChannelSidebarCategory"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		UpdateChannelSidebarCategory(userId, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.UpdateChannelSidebarCategory(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("API UpdateChannelSidebarCategory called but not implemented.")
+	}
+	return nil
+}
+
+type Z_DeleteChannelSidebarCategoryArgs struct {
+	A string
+	B string
+	C string
+}
+
+type Z_DeleteChannelSidebarCategoryReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) DeleteChannelSidebarCategory(userId, teamId, categoryId string) *model.AppError {
+	_args := &Z_DeleteChannelSidebarCategoryArgs{userId, teamId, categoryId}
+	_returns := &Z_DeleteChannelSidebarCategoryReturns{}
+	if err := g.client.Call("Plugin.DeleteChannelSidebarCategory", _args, _returns); err != nil {
+		log.Printf("RPC call to DeleteChannelSidebarCategory API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) DeleteChannelSidebarCategory(args *Z_DeleteChannelSidebarCategoryArgs, returns *Z_DeleteChannelSidebarCategoryReturns) error {
+	if err := s.checkAPICallLimit("DeleteChannelSidebarCategory"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		DeleteChannelSidebarCategory(userId, teamId, categoryId string) *model.AppError
+	}); ok {
+		returns.A = hook.DeleteChannelSidebarCategory(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("API DeleteChannelSidebarCategory called but not implemented.")
+	}
+	return nil
+}
+
 type Z_CreatePostArgs struct {
 	A *model.Post
 }
@@ -1725,6 +2351,9 @@ func (g *apiRPCClient) CreatePost(post *model.Post) (*model.Post, *model.AppErro
 }
 
 func (s *apiRPCServer) CreatePost(args *Z_CreatePostArgs, returns *Z_CreatePostReturns) error {
+	if err := s.checkAPICallLimit("CreatePost"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CreatePost(post *model.Post) (*model.Post, *model.AppError)
 	}); ok {
@@ -1754,6 +2383,9 @@ func (g *apiRPCClient) AddReaction(reaction *model.Reaction) (*model.Reaction, *
 }
 
 func (s *apiRPCServer) AddReaction(args *Z_AddReactionArgs, returns *Z_AddReactionReturns) error {
+	if err := s.checkAPICallLimit("AddReaction"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		AddReaction(reaction *model.Reaction) (*model.Reaction, *model.AppError)
 	}); ok {
@@ -1782,6 +2414,9 @@ func (g *apiRPCClient) RemoveReaction(reaction *model.Reaction) *model.AppError
 }
 
 func (s *apiRPCServer) RemoveReaction(args *Z_RemoveReactionArgs, returns *Z_RemoveReactionReturns) error {
+	if err := s.checkAPICallLimit("RemoveReaction"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		RemoveReaction(reaction *model.Reaction) *model.AppError
 	}); ok {
@@ -1811,6 +2446,9 @@ func (g *apiRPCClient) GetReactions(postId string) ([]*model.Reaction, *model.Ap
 }
 
 func (s *apiRPCServer) GetReactions(args *Z_GetReactionsArgs, returns *Z_GetReactionsReturns) error {
+	if err := s.checkAPICallLimit("GetReactions"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetReactions(postId string) ([]*model.Reaction, *model.AppError)
 	}); ok {
@@ -1840,6 +2478,9 @@ func (g *apiRPCClient) SendEphemeralPost(userId string, post *model.Post) *model
 }
 
 func (s *apiRPCServer) SendEphemeralPost(args *Z_SendEphemeralPostArgs, returns *Z_SendEphemeralPostReturns) error {
+	if err := s.checkAPICallLimit("SendEphemeralPost"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		SendEphemeralPost(userId string, post *model.Post) *model.Post
 	}); ok {
@@ -1850,6 +2491,69 @@ func (s *apiRPCServer) SendEphemeralPost(args *Z_SendEphemeralPostArgs, returns
 	return nil
 }
 
+type Z_UpdateEphemeralPostArgs struct {
+	A string
+	B *model.Post
+}
+
+type Z_UpdateEphemeralPostReturns struct {
+	A *model.Post
+}
+
+func (g *apiRPCClient) UpdateEphemeralPost(userId string, post *model.Post) *model.Post {
+	_args := &Z_UpdateEphemeralPostArgs{userId, post}
+	_returns := &Z_UpdateEphemeralPostReturns{}
+	if err := g.client.Call("Plugin.UpdateEphemeralPost", _args, _returns); err != nil {
+		log.Printf("RPC call to UpdateEphemeralPost API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) UpdateEphemeralPost(args *Z_UpdateEphemeralPostArgs, returns *Z_UpdateEphemeralPostReturns) error {
+	if err := s.checkAPICallLimit("UpdateEphemeralPost"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		UpdateEphemeralPost(userId string, post *model.Post) *model.Post
+	}); ok {
+		returns.A = hook.UpdateEphemeralPost(args.A, args.B)
+	} else {
+		return fmt.Errorf("API UpdateEphemeralPost called but not implemented.")
+	}
+	return nil
+}
+
+type Z_DeleteEphemeralPostArgs struct {
+	A string
+	B string
+}
+
+type Z_DeleteEphemeralPostReturns struct {
+}
+
+func (g *apiRPCClient) DeleteEphemeralPost(userId, postId string) {
+	_args := &Z_DeleteEphemeralPostArgs{userId, postId}
+	_returns := &Z_DeleteEphemeralPostReturns{}
+	if err := g.client.Call("Plugin.DeleteEphemeralPost", _args, _returns); err != nil {
+		log.Printf("RPC call to DeleteEphemeralPost API failed: %s", err.Error())
+	}
+
+}
+
+func (s *apiRPCServer) DeleteEphemeralPost(args *Z_DeleteEphemeralPostArgs, returns *Z_DeleteEphemeralPostReturns) error {
+	if err := s.checkAPICallLimit("DeleteEphemeralPost"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		DeleteEphemeralPost(userId, postId string)
+	}); ok {
+		hook.DeleteEphemeralPost(args.A, args.B)
+	} else {
+		return fmt.Errorf("API DeleteEphemeralPost called but not implemented.")
+	}
+	return nil
+}
+
 type Z_DeletePostArgs struct {
 	A string
 }
@@ -1868,6 +2572,9 @@ func (g *apiRPCClient) DeletePost(postId string) *model.AppError {
 }
 
 func (s *apiRPCServer) DeletePost(args *Z_DeletePostArgs, returns *Z_DeletePostReturns) error {
+	if err := s.checkAPICallLimit("DeletePost"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		DeletePost(postId string) *model.AppError
 	}); ok {
@@ -1897,6 +2604,9 @@ func (g *apiRPCClient) GetPost(postId string) (*model.Post, *model.AppError) {
 }
 
 func (s *apiRPCServer) GetPost(args *Z_GetPostArgs, returns *Z_GetPostReturns) error {
+	if err := s.checkAPICallLimit("GetPost"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetPost(postId string) (*model.Post, *model.AppError)
 	}); ok {
@@ -1907,6 +2617,41 @@ func (s *apiRPCServer) GetPost(args *Z_GetPostArgs, returns *Z_GetPostReturns) e
 	return nil
 }
 
+type Z_SearchPostsArgs struct {
+	A string
+	B string
+	C string
+	D bool
+}
+
+type Z_SearchPostsReturns struct {
+	A *model.PostList
+	B *model.AppError
+}
+
+func (g *apiRPCClient) SearchPosts(teamId, userId, terms string, isOrSearch bool) (*model.PostList, *model.AppError) {
+	_args := &Z_SearchPostsArgs{teamId, userId, terms, isOrSearch}
+	_returns := &Z_SearchPostsReturns{}
+	if err := g.client.Call("Plugin.SearchPosts", _args, _returns); err != nil {
+		log.Printf("RPC call to SearchPosts API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) SearchPosts(args *Z_SearchPostsArgs, returns *Z_SearchPostsReturns) error {
+	if err := s.checkAPICallLimit("SearchPosts"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		SearchPosts(teamId, userId, terms string, isOrSearch bool) (*model.PostList, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.SearchPosts(args.A, args.B, args.C, args.D)
+	} else {
+		return fmt.Errorf("API SearchPosts called but not implemented.")
+	}
+	return nil
+}
+
 type Z_UpdatePostArgs struct {
 	A *model.Post
 }
@@ -1926,6 +2671,9 @@ func (g *apiRPCClient) UpdatePost(post *model.Post) (*model.Post, *model.AppErro
 }
 
 func (s *apiRPCServer) UpdatePost(args *Z_UpdatePostArgs, returns *Z_UpdatePostReturns) error {
+	if err := s.checkAPICallLimit("UpdatePost"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		UpdatePost(post *model.Post) (*model.Post, *model.AppError)
 	}); ok {
@@ -1956,6 +2704,9 @@ func (g *apiRPCClient) CopyFileInfos(userId string, fileIds []string) ([]string,
 }
 
 func (s *apiRPCServer) CopyFileInfos(args *Z_CopyFileInfosArgs, returns *Z_CopyFileInfosReturns) error {
+	if err := s.checkAPICallLimit("CopyFileInfos"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		CopyFileInfos(userId string, fileIds []string) ([]string, *model.AppError)
 	}); ok {
@@ -1985,6 +2736,9 @@ func (g *apiRPCClient) GetFileInfo(fileId string) (*model.FileInfo, *model.AppEr
 }
 
 func (s *apiRPCServer) GetFileInfo(args *Z_GetFileInfoArgs, returns *Z_GetFileInfoReturns) error {
+	if err := s.checkAPICallLimit("GetFileInfo"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		GetFileInfo(fileId string) (*model.FileInfo, *model.AppError)
 	}); ok {
@@ -2014,6 +2768,9 @@ func (g *apiRPCClient) ReadFile(path string) ([]byte, *model.AppError) {
 }
 
 func (s *apiRPCServer) ReadFile(args *Z_ReadFileArgs, returns *Z_ReadFileReturns) error {
+	if err := s.checkAPICallLimit("ReadFile"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		ReadFile(path string) ([]byte, *model.AppError)
 	}); ok {
@@ -2043,6 +2800,9 @@ func (g *apiRPCClient) KVSet(key string, value []byte) *model.AppError {
 }
 
 func (s *apiRPCServer) KVSet(args *Z_KVSetArgs, returns *Z_KVSetReturns) error {
+	if err := s.checkAPICallLimit("KVSet"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		KVSet(key string, value []byte) *model.AppError
 	}); ok {
@@ -2072,6 +2832,9 @@ func (g *apiRPCClient) KVGet(key string) ([]byte, *model.AppError) {
 }
 
 func (s *apiRPCServer) KVGet(args *Z_KVGetArgs, returns *Z_KVGetReturns) error {
+	if err := s.checkAPICallLimit("KVGet"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		KVGet(key string) ([]byte, *model.AppError)
 	}); ok {
@@ -2100,6 +2863,9 @@ func (g *apiRPCClient) KVDelete(key string) *model.AppError {
 }
 
 func (s *apiRPCServer) KVDelete(args *Z_KVDeleteArgs, returns *Z_KVDeleteReturns) error {
+	if err := s.checkAPICallLimit("KVDelete"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		KVDelete(key string) *model.AppError
 	}); ok {
@@ -2110,6 +2876,106 @@ func (s *apiRPCServer) KVDelete(args *Z_KVDeleteArgs, returns *Z_KVDeleteReturns
 	return nil
 }
 
+type Z_KVSetWithExpiryArgs struct {
+	A string
+	B []byte
+	C int64
+}
+
+type Z_KVSetWithExpiryReturns struct {
+	A *model.AppError
+}
+
+func (g *apiRPCClient) KVSetWithExpiry(key string, value []byte, expireInSeconds int64) *model.AppError {
+	_args := &Z_KVSetWithExpiryArgs{key, value, expireInSeconds}
+	_returns := &Z_KVSetWithExpiryReturns{}
+	if err := g.client.Call("Plugin.KVSetWithExpiry", _args, _returns); err != nil {
+		log.Printf("RPC call to KVSetWithExpiry API failed: %s", err.Error())
+	}
+	return _returns.A
+}
+
+func (s *apiRPCServer) KVSetWithExpiry(args *Z_KVSetWithExpiryArgs, returns *Z_KVSetWithExpiryReturns) error {
+	if err := s.checkAPICallLimit("KVSetWithExpiry"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		KVSetWithExpiry(key string, value []byte, expireInSeconds int64) *model.AppError
+	}); ok {
+		returns.A = hook.KVSetWithExpiry(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("API KVSetWithExpiry called but not implemented.")
+	}
+	return nil
+}
+
+type Z_KVCompareAndSetArgs struct {
+	A string
+	B []byte
+	C []byte
+}
+
+type Z_KVCompareAndSetReturns struct {
+	A bool
+	B *model.AppError
+}
+
+func (g *apiRPCClient) KVCompareAndSet(key string, oldValue, newValue []byte) (bool, *model.AppError) {
+	_args := &Z_KVCompareAndSetArgs{key, oldValue, newValue}
+	_returns := &Z_KVCompareAndSetReturns{}
+	if err := g.client.Call("Plugin.KVCompareAndSet", _args, _returns); err != nil {
+		log.Printf("RPC call to KVCompareAndSet API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) KVCompareAndSet(args *Z_KVCompareAndSetArgs, returns *Z_KVCompareAndSetReturns) error {
+	if err := s.checkAPICallLimit("KVCompareAndSet"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		KVCompareAndSet(key string, oldValue, newValue []byte) (bool, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.KVCompareAndSet(args.A, args.B, args.C)
+	} else {
+		return fmt.Errorf("API KVCompareAndSet called but not implemented.")
+	}
+	return nil
+}
+
+type Z_KVCompareAndDeleteArgs struct {
+	A string
+	B []byte
+}
+
+type Z_KVCompareAndDeleteReturns struct {
+	A bool
+	B *model.AppError
+}
+
+func (g *apiRPCClient) KVCompareAndDelete(key string, oldValue []byte) (bool, *model.AppError) {
+	_args := &Z_KVCompareAndDeleteArgs{key, oldValue}
+	_returns := &Z_KVCompareAndDeleteReturns{}
+	if err := g.client.Call("Plugin.KVCompareAndDelete", _args, _returns); err != nil {
+		log.Printf("RPC call to KVCompareAndDelete API failed: %s", err.Error())
+	}
+	return _returns.A, _returns.B
+}
+
+func (s *apiRPCServer) KVCompareAndDelete(args *Z_KVCompareAndDeleteArgs, returns *Z_KVCompareAndDeleteReturns) error {
+	if err := s.checkAPICallLimit("KVCompareAndDelete"); err != nil {
+		return err
+	}
+	if hook, ok := s.impl.(interface {
+		KVCompareAndDelete(key string, oldValue []byte) (bool, *model.AppError)
+	}); ok {
+		returns.A, returns.B = hook.KVCompareAndDelete(args.A, args.B)
+	} else {
+		return fmt.Errorf("API KVCompareAndDelete called but not implemented.")
+	}
+	return nil
+}
+
 type Z_PublishWebSocketEventArgs struct {
 	A string
 	B map[string]interface{}
@@ -2129,6 +2995,9 @@ func (g *apiRPCClient) PublishWebSocketEvent(event string, payload map[string]in
 }
 
 func (s *apiRPCServer) PublishWebSocketEvent(args *Z_PublishWebSocketEventArgs, returns *Z_PublishWebSocketEventReturns) error {
+	if err := s.checkAPICallLimit("PublishWebSocketEvent"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		PublishWebSocketEvent(event string, payload map[string]interface{}, broadcast *model.WebsocketBroadcast)
 	}); ok {
@@ -2158,6 +3027,9 @@ func (g *apiRPCClient) HasPermissionTo(userId string, permission *model.Permissi
 }
 
 func (s *apiRPCServer) HasPermissionTo(args *Z_HasPermissionToArgs, returns *Z_HasPermissionToReturns) error {
+	if err := s.checkAPICallLimit("HasPermissionTo"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		HasPermissionTo(userId string, permission *model.Permission) bool
 	}); ok {
@@ -2188,6 +3060,9 @@ func (g *apiRPCClient) HasPermissionToTeam(userId, teamId string, permission *mo
 }
 
 func (s *apiRPCServer) HasPermissionToTeam(args *Z_HasPermissionToTeamArgs, returns *Z_HasPermissionToTeamReturns) error {
+	if err := s.checkAPICallLimit("HasPermissionToTeam"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		HasPermissionToTeam(userId, teamId string, permission *model.Permission) bool
 	}); ok {
@@ -2218,6 +3093,9 @@ func (g *apiRPCClient) HasPermissionToChannel(userId, channelId string, permissi
 }
 
 func (s *apiRPCServer) HasPermissionToChannel(args *Z_HasPermissionToChannelArgs, returns *Z_HasPermissionToChannelReturns) error {
+	if err := s.checkAPICallLimit("HasPermissionToChannel"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		HasPermissionToChannel(userId, channelId string, permission *model.Permission) bool
 	}); ok {
@@ -2246,6 +3124,9 @@ func (g *apiRPCClient) LogDebug(msg string, keyValuePairs ...interface{}) {
 }
 
 func (s *apiRPCServer) LogDebug(args *Z_LogDebugArgs, returns *Z_LogDebugReturns) error {
+	if err := s.checkAPICallLimit("LogDebug"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		LogDebug(msg string, keyValuePairs ...interface{})
 	}); ok {
@@ -2274,6 +3155,9 @@ func (g *apiRPCClient) LogInfo(msg string, keyValuePairs ...interface{}) {
 }
 
 func (s *apiRPCServer) LogInfo(args *Z_LogInfoArgs, returns *Z_LogInfoReturns) error {
+	if err := s.checkAPICallLimit("LogInfo"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		LogInfo(msg string, keyValuePairs ...interface{})
 	}); ok {
@@ -2302,6 +3186,9 @@ func (g *apiRPCClient) LogError(msg string, keyValuePairs ...interface{}) {
 }
 
 func (s *apiRPCServer) LogError(args *Z_LogErrorArgs, returns *Z_LogErrorReturns) error {
+	if err := s.checkAPICallLimit("LogError"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		LogError(msg string, keyValuePairs ...interface{})
 	}); ok {
@@ -2330,6 +3217,9 @@ func (g *apiRPCClient) LogWarn(msg string, keyValuePairs ...interface{}) {
 }
 
 func (s *apiRPCServer) LogWarn(args *Z_LogWarnArgs, returns *Z_LogWarnReturns) error {
+	if err := s.checkAPICallLimit("LogWarn"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		LogWarn(msg string, keyValuePairs ...interface{})
 	}); ok {
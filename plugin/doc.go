@@ -4,6 +4,9 @@
 // The plugin package is used by Mattermost server plugins written in go. It also enables the
 // Mattermost server to manage and interact with the running plugin environment.
 //
+// Plugins written in other languages can instead implement the gRPC protocol described in
+// pluginproto/plugin.proto, which the server uses when a plugin announces gRPC during its handshake.
+//
 // Note that this package exports a large number of types prefixed with Z_. These are public only
 // to allow their use with Hashicorp's go-plugin (and net/rpc). Do not use these directly.
 package plugin
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -28,6 +29,12 @@ type activePlugin struct {
 	BundleInfo *model.BundleInfo
 	State      int
 
+	// The number of times the plugin has been restarted after failing a health check, the error it failed
+	// with, and when it can next be restarted
+	Restarts    int
+	Error       string
+	NextRestart time.Time
+
 	supervisor *supervisor
 }
 
@@ -41,6 +48,9 @@ type Environment struct {
 	newAPIImpl      apiImplCreatorFunc
 	pluginDir       string
 	webappPluginDir string
+	webappSubpath   string
+	memoryLimit     func(pluginId string) int64
+	logBuffers      sync.Map
 }
 
 func NewEnvironment(newAPIImpl apiImplCreatorFunc, pluginDir string, webappPluginDir string, logger *mlog.Logger) (*Environment, error) {
@@ -52,6 +62,34 @@ func NewEnvironment(newAPIImpl apiImplCreatorFunc, pluginDir string, webappPlugi
 	}, nil
 }
 
+// SetWebappSubpath sets the subpath that the server is hosted at, which is rewritten into the webapp bundles of
+// plugins that are activated after it's set.
+func (env *Environment) SetWebappSubpath(subpath string) {
+	env.webappSubpath = subpath
+}
+
+// SetMemoryLimit sets a function that returns the number of bytes of memory that a plugin's process can use, or 0 if
+// it isn't limited. Plugins that use more than their limit are stopped by CheckPluginHealth.
+func (env *Environment) SetMemoryLimit(memoryLimit func(pluginId string) int64) {
+	env.memoryLimit = memoryLimit
+}
+
+// LogBuffer returns the buffer that keeps the most recent entries from a plugin's log. Buffers are kept when plugins
+// are deactivated, so that the logs of a plugin that failed can still be read.
+func (env *Environment) LogBuffer(id string) *LogBuffer {
+	if buffer, ok := env.logBuffers.Load(id); ok {
+		return buffer.(*LogBuffer)
+	}
+
+	buffer, _ := env.logBuffers.LoadOrStore(id, NewLogBuffer(LOG_BUFFER_SIZE))
+	return buffer.(*LogBuffer)
+}
+
+// pluginLogger returns a logger for messages from or about a plugin that are also kept in its log buffer.
+func (env *Environment) pluginLogger(id string) *mlog.Logger {
+	return env.logger.Tee(env.LogBuffer(id))
+}
+
 // Performs a full scan of the given path.
 //
 // This function will return info for all subdirectories that appear to be plugins (i.e. all
@@ -113,47 +151,59 @@ func (env *Environment) Statuses() (model.PluginStatuses, error) {
 			continue
 		}
 
-		pluginState := model.PluginStateNotRunning
-		if plugin, ok := env.activePlugins.Load(plugin.Manifest.Id); ok {
-			pluginState = plugin.(activePlugin).State
-		}
-
 		status := &model.PluginStatus{
 			PluginId:    plugin.Manifest.Id,
 			PluginPath:  filepath.Dir(plugin.ManifestPath),
-			State:       pluginState,
+			State:       model.PluginStateNotRunning,
 			Name:        plugin.Manifest.Name,
 			Description: plugin.Manifest.Description,
 			Version:     plugin.Manifest.Version,
 		}
 
+		if p, ok := env.activePlugins.Load(plugin.Manifest.Id); ok {
+			activePlugin := p.(activePlugin)
+			status.State = activePlugin.State
+			status.Restarts = activePlugin.Restarts
+			status.Error = activePlugin.Error
+		}
+
 		pluginStatuses = append(pluginStatuses, status)
 	}
 
 	return pluginStatuses, nil
 }
 
-func (env *Environment) Activate(id string) (manifest *model.Manifest, activated bool, reterr error) {
-	// Check if we are already active
-	if _, ok := env.activePlugins.Load(id); ok {
-		return nil, false, nil
-	}
-
+// findBundle returns the installed plugin with the given id.
+func (env *Environment) findBundle(id string) (*model.BundleInfo, error) {
 	plugins, err := env.Available()
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	var pluginInfo *model.BundleInfo
 	for _, p := range plugins {
 		if p.Manifest != nil && p.Manifest.Id == id {
 			if pluginInfo != nil {
-				return nil, false, fmt.Errorf("multiple plugins found: %v", id)
+				return nil, fmt.Errorf("multiple plugins found: %v", id)
 			}
 			pluginInfo = p
 		}
 	}
 	if pluginInfo == nil {
-		return nil, false, fmt.Errorf("plugin not found: %v", id)
+		return nil, fmt.Errorf("plugin not found: %v", id)
+	}
+
+	return pluginInfo, nil
+}
+
+func (env *Environment) Activate(id string) (manifest *model.Manifest, activated bool, reterr error) {
+	// Check if we are already active
+	if _, ok := env.activePlugins.Load(id); ok {
+		return nil, false, nil
+	}
+
+	pluginInfo, err := env.findBundle(id)
+	if err != nil {
+		return nil, false, err
 	}
 
 	activePlugin := activePlugin{BundleInfo: pluginInfo}
@@ -162,47 +212,24 @@ func (env *Environment) Activate(id string) (manifest *model.Manifest, activated
 			activePlugin.State = model.PluginStateRunning
 		} else {
 			activePlugin.State = model.PluginStateFailedToStart
+			activePlugin.Error = reterr.Error()
 		}
 		env.activePlugins.Store(pluginInfo.Manifest.Id, activePlugin)
 	}()
 
 	if pluginInfo.Manifest.Webapp != nil {
-		bundlePath := filepath.Clean(pluginInfo.Manifest.Webapp.BundlePath)
-		if bundlePath == "" || bundlePath[0] == '.' {
-			return nil, false, fmt.Errorf("invalid webapp bundle path")
-		}
-		bundlePath = filepath.Join(env.pluginDir, id, bundlePath)
 		destinationPath := filepath.Join(env.webappPluginDir, id)
-
 		if err := os.RemoveAll(destinationPath); err != nil {
 			return nil, false, errors.Wrapf(err, "unable to remove old webapp bundle directory: %v", destinationPath)
 		}
 
-		if err := utils.CopyDir(filepath.Dir(bundlePath), destinationPath); err != nil {
-			return nil, false, errors.Wrapf(err, "unable to copy webapp bundle directory: %v", id)
-		}
-
-		sourceBundleFilepath := filepath.Join(destinationPath, filepath.Base(bundlePath))
-
-		sourceBundleFileContents, err := ioutil.ReadFile(sourceBundleFilepath)
-		if err != nil {
-			return nil, false, errors.Wrapf(err, "unable to read webapp bundle: %v", id)
-		}
-
-		hash := fnv.New64a()
-		hash.Write(sourceBundleFileContents)
-		pluginInfo.Manifest.Webapp.BundleHash = hash.Sum([]byte{})
-
-		if err := os.Rename(
-			sourceBundleFilepath,
-			filepath.Join(destinationPath, fmt.Sprintf("%s_%x_bundle.js", id, pluginInfo.Manifest.Webapp.BundleHash)),
-		); err != nil {
-			return nil, false, errors.Wrapf(err, "unable to rename webapp bundle: %v", id)
+		if _, err := env.unpackWebappBundle(pluginInfo); err != nil {
+			return nil, false, err
 		}
 	}
 
 	if pluginInfo.Manifest.HasServer() {
-		supervisor, err := newSupervisor(pluginInfo, env.logger, env.newAPIImpl(pluginInfo.Manifest))
+		supervisor, err := newSupervisor(pluginInfo, env.pluginLogger(id), env.newAPIImpl(pluginInfo.Manifest))
 		if err != nil {
 			return nil, false, errors.Wrapf(err, "unable to start plugin: %v", id)
 		}
@@ -212,6 +239,80 @@ func (env *Environment) Activate(id string) (manifest *model.Manifest, activated
 	return pluginInfo.Manifest, true, nil
 }
 
+// unpackWebappBundle copies a plugin's webapp bundle to where it's served from and names the bundle after its hash.
+// The files are prepared in a staging directory first and then moved into place, so that a bundle that's already
+// being served keeps working until it's replaced. It returns the names of the files that were moved into place.
+func (env *Environment) unpackWebappBundle(pluginInfo *model.BundleInfo) ([]string, error) {
+	id := pluginInfo.Manifest.Id
+
+	bundlePath := filepath.Clean(pluginInfo.Manifest.Webapp.BundlePath)
+	if bundlePath == "" || bundlePath[0] == '.' {
+		return nil, fmt.Errorf("invalid webapp bundle path")
+	}
+	bundlePath = filepath.Join(pluginInfo.Path, bundlePath)
+	destinationPath := filepath.Join(env.webappPluginDir, id)
+	stagingPath := filepath.Join(env.webappPluginDir, "."+id+".staging")
+
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return nil, errors.Wrapf(err, "unable to remove old webapp bundle directory: %v", stagingPath)
+	}
+	defer os.RemoveAll(stagingPath)
+
+	if err := utils.CopyDir(filepath.Dir(bundlePath), stagingPath); err != nil {
+		return nil, errors.Wrapf(err, "unable to copy webapp bundle directory: %v", id)
+	}
+
+	sourceBundleFilepath := filepath.Join(stagingPath, filepath.Base(bundlePath))
+
+	sourceBundleFileContents, err := ioutil.ReadFile(sourceBundleFilepath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read webapp bundle: %v", id)
+	}
+
+	// The bundle is hashed after it's rewritten so that its URL changes along with the subpath
+	sourceBundleFileContents = utils.RewritePluginAssetsSubpath(sourceBundleFileContents, env.webappSubpath)
+
+	hash := fnv.New64a()
+	hash.Write(sourceBundleFileContents)
+	pluginInfo.Manifest.Webapp.BundleHash = hash.Sum([]byte{})
+
+	if err := ioutil.WriteFile(
+		filepath.Join(stagingPath, fmt.Sprintf("%s_%x_bundle.js", id, pluginInfo.Manifest.Webapp.BundleHash)),
+		sourceBundleFileContents,
+		0644,
+	); err != nil {
+		return nil, errors.Wrapf(err, "unable to write webapp bundle: %v", id)
+	}
+
+	if err := os.Remove(sourceBundleFilepath); err != nil {
+		return nil, errors.Wrapf(err, "unable to remove original webapp bundle: %v", id)
+	}
+
+	files, err := ioutil.ReadDir(stagingPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read webapp bundle directory: %v", id)
+	}
+
+	if err := os.MkdirAll(destinationPath, 0744); err != nil {
+		return nil, errors.Wrapf(err, "unable to create webapp bundle directory: %v", destinationPath)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := os.RemoveAll(filepath.Join(destinationPath, file.Name())); err != nil {
+			return nil, errors.Wrapf(err, "unable to replace webapp bundle file: %v", file.Name())
+		}
+
+		if err := os.Rename(filepath.Join(stagingPath, file.Name()), filepath.Join(destinationPath, file.Name())); err != nil {
+			return nil, errors.Wrapf(err, "unable to move webapp bundle file: %v", file.Name())
+		}
+
+		names = append(names, file.Name())
+	}
+
+	return names, nil
+}
+
 // Deactivates the plugin with the given id.
 func (env *Environment) Deactivate(id string) bool {
 	p, ok := env.activePlugins.Load(id)
@@ -224,7 +325,7 @@ func (env *Environment) Deactivate(id string) bool {
 	activePlugin := p.(activePlugin)
 	if activePlugin.supervisor != nil {
 		if err := activePlugin.supervisor.Hooks().OnDeactivate(); err != nil {
-			env.logger.Error("Plugin OnDeactivate() error", mlog.String("plugin_id", activePlugin.BundleInfo.Manifest.Id), mlog.Err(err))
+			env.pluginLogger(id).Error("Plugin OnDeactivate() error", mlog.String("plugin_id", activePlugin.BundleInfo.Manifest.Id), mlog.Err(err))
 		}
 		activePlugin.supervisor.Shutdown()
 	}
@@ -252,7 +353,8 @@ func (env *Environment) Shutdown() {
 
 // HooksForPlugin returns the hooks API for the plugin with the given id.
 //
-// Consider using RunMultiPluginHook instead.
+// Consider using RunPluginHook or RunMultiPluginHook instead, since hooks that are invoked through the returned API
+// aren't waited for when the plugin is upgraded.
 func (env *Environment) HooksForPlugin(id string) (Hooks, error) {
 	if p, ok := env.activePlugins.Load(id); ok {
 		activePlugin := p.(activePlugin)
@@ -275,10 +377,26 @@ func (env *Environment) RunMultiPluginHook(hookRunnerFunc multiPluginHookRunnerF
 		if activePlugin.supervisor == nil || !activePlugin.supervisor.Implements(hookId) {
 			return true
 		}
-		if !hookRunnerFunc(activePlugin.supervisor.Hooks()) {
-			return false
-		}
 
-		return true
+		activePlugin.supervisor.beginHook()
+		defer activePlugin.supervisor.endHook()
+
+		return hookRunnerFunc(activePlugin.supervisor.Hooks())
 	})
 }
+
+// RunPluginHook invokes hookRunnerFunc with the hooks API for the plugin with the given id. If the plugin is upgraded
+// while hookRunnerFunc is running, the old version of the plugin isn't stopped until it returns.
+func (env *Environment) RunPluginHook(id string, hookRunnerFunc func(hooks Hooks)) error {
+	p, ok := env.activePlugins.Load(id)
+	if !ok || p.(activePlugin).supervisor == nil {
+		return fmt.Errorf("plugin not found: %v", id)
+	}
+
+	supervisor := p.(activePlugin).supervisor
+	supervisor.beginHook()
+	defer supervisor.endHook()
+
+	hookRunnerFunc(supervisor.Hooks())
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	// How often the server should call CheckPluginHealth
+	HealthCheckInterval = 30 * time.Second
+
+	// Plugins that keep failing are left stopped after being restarted this many times
+	HealthCheckMaxRestarts = 3
+)
+
+var (
+	healthCheckPingTimeout = 10 * time.Second
+
+	// The delay before the first restart of a plugin that failed, which doubles with every restart after it
+	healthCheckRestartBackoff = 30 * time.Second
+)
+
+func healthCheckRestartDelay(restarts int) time.Duration {
+	return healthCheckRestartBackoff << uint(restarts)
+}
+
+// CheckPluginHealth checks that the processes of active plugins are still running, responding and within their
+// memory limits. Plugins that crashed, hung or used too much memory are stopped and marked as failed to stay running,
+// and then restarted with an increasing delay until they've been restarted HealthCheckMaxRestarts times. Reactivating a
+// plugin resets its restarts.
+func (env *Environment) CheckPluginHealth() {
+	now := time.Now()
+
+	env.activePlugins.Range(func(key, value interface{}) bool {
+		id := key.(string)
+		activePlugin := value.(activePlugin)
+
+		switch activePlugin.State {
+		case model.PluginStateRunning:
+			if activePlugin.supervisor == nil {
+				return true
+			}
+
+			if err := activePlugin.supervisor.PerformHealthCheck(); err != nil {
+				env.pluginLogger(id).Error("Plugin failed a health check", mlog.String("plugin_id", id), mlog.Err(err))
+				env.stopFailedPlugin(id, activePlugin, err, now)
+			} else if err := env.checkMemoryLimit(id, activePlugin.supervisor); err != nil {
+				env.pluginLogger(id).Error("Plugin exceeded its memory limit", mlog.String("plugin_id", id), mlog.Err(err))
+				env.stopFailedPlugin(id, activePlugin, err, now)
+			}
+		case model.PluginStateFailedToStayRunning:
+			if activePlugin.Restarts < HealthCheckMaxRestarts && !now.Before(activePlugin.NextRestart) {
+				env.restartFailedPlugin(id, activePlugin, now)
+			}
+		}
+
+		return true
+	})
+}
+
+// checkMemoryLimit returns an error if a plugin's process is using more memory than its limit. Memory usage that
+// can't be read isn't treated as a failure, since it's only available on some systems.
+func (env *Environment) checkMemoryLimit(id string, sup *supervisor) error {
+	if env.memoryLimit == nil {
+		return nil
+	}
+
+	limit := env.memoryLimit(id)
+	if limit <= 0 {
+		return nil
+	}
+
+	usage, err := sup.MemoryUsage()
+	if err != nil {
+		return nil
+	}
+
+	if usage > limit {
+		return fmt.Errorf("plugin process is using %v bytes of memory, which is more than its limit of %v bytes", usage, limit)
+	}
+
+	return nil
+}
+
+func (env *Environment) stopFailedPlugin(id string, activePlugin activePlugin, err error, now time.Time) {
+	if activePlugin.supervisor != nil {
+		activePlugin.supervisor.Shutdown()
+		activePlugin.supervisor = nil
+	}
+
+	activePlugin.State = model.PluginStateFailedToStayRunning
+	activePlugin.Error = err.Error()
+	activePlugin.NextRestart = now.Add(healthCheckRestartDelay(activePlugin.Restarts))
+	env.activePlugins.Store(id, activePlugin)
+
+	if activePlugin.Restarts >= HealthCheckMaxRestarts {
+		env.pluginLogger(id).Error("Plugin has been restarted too many times and won't be restarted again", mlog.String("plugin_id", id), mlog.Int("restarts", activePlugin.Restarts))
+	}
+}
+
+func (env *Environment) restartFailedPlugin(id string, failedPlugin activePlugin, now time.Time) {
+	env.pluginLogger(id).Info("Restarting plugin", mlog.String("plugin_id", id), mlog.Int("restarts", failedPlugin.Restarts))
+
+	env.activePlugins.Delete(id)
+	_, _, err := env.Activate(id)
+
+	p, ok := env.activePlugins.Load(id)
+	if !ok {
+		return
+	}
+
+	restartedPlugin := p.(activePlugin)
+	restartedPlugin.Restarts = failedPlugin.Restarts + 1
+
+	if err != nil {
+		env.pluginLogger(id).Error("Unable to restart plugin", mlog.String("plugin_id", id), mlog.Err(err))
+		env.stopFailedPlugin(id, restartedPlugin, err, now)
+		return
+	}
+
+	env.activePlugins.Store(id, restartedPlugin)
+}
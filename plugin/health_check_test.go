@@ -0,0 +1,198 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPluginHealth(t *testing.T) {
+	pluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(pluginDir)
+
+	webappPluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(webappPluginDir)
+
+	compileGo(t, `
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`, filepath.Join(pluginDir, "testhealth", "backend.exe"))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pluginDir, "testhealth", "plugin.json"), []byte(`{"id": "testhealth", "backend": {"executable": "backend.exe"}}`), 0600))
+
+	log := mlog.NewLogger(&mlog.LoggerConfiguration{
+		EnableConsole: true,
+		ConsoleJson:   true,
+		ConsoleLevel:  "error",
+		EnableFile:    false,
+	})
+
+	env, err := NewEnvironment(func(*model.Manifest) API { return nil }, pluginDir, webappPluginDir, log)
+	require.NoError(t, err)
+	defer env.Shutdown()
+
+	_, activated, err := env.Activate("testhealth")
+	require.NoError(t, err)
+	require.True(t, activated)
+
+	getStatus := func() *model.PluginStatus {
+		statuses, err := env.Statuses()
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		return statuses[0]
+	}
+
+	env.CheckPluginHealth()
+	assert.Equal(t, model.PluginStateRunning, getStatus().State)
+
+	// Crash the plugin
+	p, _ := env.activePlugins.Load("testhealth")
+	p.(activePlugin).supervisor.client.Kill()
+
+	env.CheckPluginHealth()
+	status := getStatus()
+	assert.Equal(t, model.PluginStateFailedToStayRunning, status.State)
+	assert.NotEmpty(t, status.Error)
+	_, err = env.HooksForPlugin("testhealth")
+	assert.Error(t, err, "should not use the hooks of a plugin that failed")
+
+	// The plugin isn't restarted until its backoff has passed
+	env.CheckPluginHealth()
+	assert.Equal(t, model.PluginStateFailedToStayRunning, getStatus().State)
+
+	oldBackoff := healthCheckRestartBackoff
+	healthCheckRestartBackoff = 0
+	defer func() {
+		healthCheckRestartBackoff = oldBackoff
+	}()
+
+	p, _ = env.activePlugins.Load("testhealth")
+	failedPlugin := p.(activePlugin)
+	failedPlugin.NextRestart = time.Now()
+	env.activePlugins.Store("testhealth", failedPlugin)
+
+	env.CheckPluginHealth()
+	status = getStatus()
+	assert.Equal(t, model.PluginStateRunning, status.State)
+	assert.Equal(t, 1, status.Restarts)
+	_, err = env.HooksForPlugin("testhealth")
+	assert.NoError(t, err)
+
+	// Plugins aren't restarted forever
+	for i := 1; i < HealthCheckMaxRestarts+1; i++ {
+		p, _ := env.activePlugins.Load("testhealth")
+		p.(activePlugin).supervisor.client.Kill()
+		env.CheckPluginHealth()
+		env.CheckPluginHealth()
+	}
+
+	status = getStatus()
+	assert.Equal(t, model.PluginStateFailedToStayRunning, status.State)
+	assert.Equal(t, HealthCheckMaxRestarts, status.Restarts)
+
+	env.CheckPluginHealth()
+	assert.Equal(t, model.PluginStateFailedToStayRunning, getStatus().State)
+}
+
+func TestCheckPluginHealthMemoryLimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory usage is only read on linux")
+	}
+
+	pluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(pluginDir)
+
+	webappPluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(webappPluginDir)
+
+	compileGo(t, `
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`, filepath.Join(pluginDir, "testmemory", "backend.exe"))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pluginDir, "testmemory", "plugin.json"), []byte(`{"id": "testmemory", "backend": {"executable": "backend.exe"}}`), 0600))
+
+	log := mlog.NewLogger(&mlog.LoggerConfiguration{
+		EnableConsole: true,
+		ConsoleJson:   true,
+		ConsoleLevel:  "error",
+		EnableFile:    false,
+	})
+
+	env, err := NewEnvironment(func(*model.Manifest) API { return nil }, pluginDir, webappPluginDir, log)
+	require.NoError(t, err)
+	defer env.Shutdown()
+
+	var limit int64
+	env.SetMemoryLimit(func(pluginId string) int64 {
+		assert.Equal(t, "testmemory", pluginId)
+		return limit
+	})
+
+	_, activated, err := env.Activate("testmemory")
+	require.NoError(t, err)
+	require.True(t, activated)
+
+	p, _ := env.activePlugins.Load("testmemory")
+	usage, err := p.(activePlugin).supervisor.MemoryUsage()
+	require.NoError(t, err)
+	assert.True(t, usage > 0)
+
+	limit = 1024 * 1024 * 1024
+	env.CheckPluginHealth()
+
+	statuses, err := env.Statuses()
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, model.PluginStateRunning, statuses[0].State)
+
+	limit = 1024
+	env.CheckPluginHealth()
+
+	statuses, err = env.Statuses()
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, model.PluginStateFailedToStayRunning, statuses[0].State)
+	assert.Contains(t, statuses[0].Error, "memory")
+}
+
+func TestHealthCheckRestartDelay(t *testing.T) {
+	assert.Equal(t, healthCheckRestartBackoff, healthCheckRestartDelay(0))
+	assert.Equal(t, 4*healthCheckRestartBackoff, healthCheckRestartDelay(2))
+	assert.Equal(t, 2*healthCheckRestartBackoff, healthCheckRestartDelay(1))
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/dyatlov/go-opengraph/opengraph"
+
 	"github.com/mattermost/mattermost-server/model"
 )
 
@@ -15,24 +17,30 @@ import (
 // Feel free to add more, but do not change existing assignments. Follow the naming convention of
 // <HookName>Id as the autogenerated glue code depends on that.
 const (
-	OnActivateId            = 0
-	OnDeactivateId          = 1
-	ServeHTTPId             = 2
-	OnConfigurationChangeId = 3
-	ExecuteCommandId        = 4
-	MessageWillBePostedId   = 5
-	MessageWillBeUpdatedId  = 6
-	MessageHasBeenPostedId  = 7
-	MessageHasBeenUpdatedId = 8
-	UserHasJoinedChannelId  = 9
-	UserHasLeftChannelId    = 10
-	UserHasJoinedTeamId     = 11
-	UserHasLeftTeamId       = 12
-	ChannelHasBeenCreatedId = 13
-	FileWillBeUploadedId    = 14
-	UserWillLogInId         = 15
-	UserHasLoggedInId       = 16
-	TotalHooksId            = iota
+	OnActivateId              = 0
+	OnDeactivateId            = 1
+	ServeHTTPId               = 2
+	OnConfigurationChangeId   = 3
+	ExecuteCommandId          = 4
+	MessageWillBePostedId     = 5
+	MessageWillBeUpdatedId    = 6
+	MessageHasBeenPostedId    = 7
+	MessageHasBeenUpdatedId   = 8
+	UserHasJoinedChannelId    = 9
+	UserHasLeftChannelId      = 10
+	UserHasJoinedTeamId       = 11
+	UserHasLeftTeamId         = 12
+	ChannelHasBeenCreatedId   = 13
+	FileWillBeUploadedId      = 14
+	UserWillLogInId           = 15
+	UserHasLoggedInId         = 16
+	LinkMetadataWillBeSavedId = 17
+	ExecuteScheduledJobId     = 18
+	GetSettingOptionsId       = 19
+	ReactionHasBeenAddedId    = 20
+	ReactionHasBeenRemovedId  = 21
+	UserStatusHasChangedId    = 22
+	TotalHooksId              = iota
 )
 
 // Hooks describes the methods a plugin may implement to automatically receive the corresponding
@@ -53,6 +61,10 @@ type Hooks interface {
 	// OnDeactivate is invoked when the plugin is deactivated. This is the plugin's last chance to
 	// use the API, and the plugin will be terminated shortly after this invocation. The plugin
 	// will stop receiving hooks just prior to this method being called.
+	//
+	// It isn't invoked when the plugin is upgraded while it's active. Instead, the old version stops
+	// receiving hooks once the new version's OnActivate has returned, and is terminated once the
+	// hooks that it's already handling have returned.
 	OnDeactivate() error
 
 	// OnConfigurationChange is invoked when configuration changes may have been made.
@@ -139,6 +151,48 @@ type Hooks interface {
 	// To allow the file without modification, do not write to the output and return a nil *model.FileInfo and an empty string.
 	//
 	// Note that this method will be called for files uploaded by plugins, including the plugin that uploaded the post.
-	// FileInfo.Size will be automatically set properly if you modify the file.
+	// FileInfo.Size, and the dimensions of images, will be automatically set properly if you modify the file.
 	FileWillBeUploaded(c *Context, info *model.FileInfo, file io.Reader, output io.Writer) (*model.FileInfo, string)
+
+	// LinkMetadataWillBeSaved is invoked after the server has fetched and parsed the OpenGraph metadata of a link that
+	// was posted, but before it's saved and used for the link's preview. Plugins can use it to supply metadata for
+	// links that the server can't read, such as pages that require a login.
+	//
+	// To replace the metadata, return a non-nil *opengraph.OpenGraph. Its images are thumbnailed and made absolute
+	// like the server's own metadata.
+	// To keep the metadata, return nil.
+	//
+	// The metadata is empty if the server couldn't fetch the link.
+	LinkMetadataWillBeSaved(c *Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph
+
+	// ExecuteScheduledJob is invoked when a job that the plugin scheduled via the API's ScheduleJob is due. It's only
+	// invoked on one server in a cluster for each run of the job.
+	ExecuteScheduledJob(c *Context, jobId string)
+
+	// GetSettingOptions is invoked when a System Admin views one of the plugin's "dropdown" settings
+	// that has Dynamic set. The key is the setting's key in the manifest.
+	//
+	// Return the options that the setting can be set to, or an error to show instead of them.
+	GetSettingOptions(c *Context, key string) ([]*model.PluginOption, *model.AppError)
+
+	// ReactionHasBeenAdded is invoked after a reaction has been committed to the database, along with the post that
+	// it was added to.
+	//
+	// Note that this method will be called for reactions added by plugins, including the plugin that added the
+	// reaction.
+	ReactionHasBeenAdded(c *Context, reaction *model.Reaction, post *model.Post)
+
+	// ReactionHasBeenRemoved is invoked after a reaction has been removed from the database, along with the post that
+	// it was removed from.
+	//
+	// Note that this method will be called for reactions removed by plugins, including the plugin that removed the
+	// reaction.
+	ReactionHasBeenRemoved(c *Context, reaction *model.Reaction, post *model.Post)
+
+	// UserStatusHasChanged is invoked after a user's status has been set, either by the user or automatically as
+	// they connect, go idle and disconnect.
+	//
+	// Note that this method will be called for statuses set by plugins, including the plugin that set the status,
+	// and that it may be called when a status is set to the value that it already had.
+	UserStatusHasChanged(c *Context, status *model.Status)
 }
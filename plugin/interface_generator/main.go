@@ -264,6 +264,9 @@ func (g *apiRPCClient) {{.Name}}{{funcStyle .Params}} {{funcStyle .Return}} {
 }
 
 func (s *apiRPCServer) {{.Name}}(args *{{.Name | obscure}}Args, returns *{{.Name | obscure}}Returns) error {
+	if err := s.checkAPICallLimit("{{.Name}}"); err != nil {
+		return err
+	}
 	if hook, ok := s.impl.(interface {
 		{{.Name}}{{funcStyle .Params}} {{funcStyle .Return}}
 	}); ok {
@@ -276,6 +279,28 @@ func (s *apiRPCServer) {{.Name}}(args *{{.Name | obscure}}Args, returns *{{.Name
 {{end}}
 `
 
+var grpcHooksTemplate = `// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make pluginapi"
+// DO NOT EDIT
+
+package plugin
+
+{{range .HooksMethods}}
+
+func (g *hooksGRPCClient) {{.Name}}{{funcStyle .Params}} {{funcStyle .Return}} {
+	_returns := &{{.Name | obscure}}Returns{}
+	if g.implemented[{{.Name}}Id] {
+		if err := g.call("{{.Name}}", []interface{}{ {{valuesOnly .Params}} }, _returns); err != nil {
+			g.log.Error("gRPC call {{.Name}} to plugin failed.", mlog.Err(err))
+		}
+	}
+	{{ if .Return }} return {{destruct "_returns." .Return}} {{ end }}
+}
+{{end}}
+`
+
 type MethodParams struct {
 	Name   string
 	Params *ast.FieldList
@@ -300,11 +325,6 @@ func generateGlue(info *PluginInterfaceInfo) {
 		},
 	}
 
-	hooksTemplate, err := template.New("hooks").Funcs(templateFunctions).Parse(hooksTemplate)
-	if err != nil {
-		panic(err)
-	}
-
 	templateParams := HooksTemplateParams{}
 	for _, hook := range info.Hooks {
 		templateParams.HooksMethods = append(templateParams.HooksMethods, MethodParams{
@@ -320,8 +340,19 @@ func generateGlue(info *PluginInterfaceInfo) {
 			Return: api.Results,
 		})
 	}
+
+	executeTemplate("hooks", hooksTemplate, templateFunctions, &templateParams, "client_rpc_generated.go")
+	executeTemplate("grpcHooks", grpcHooksTemplate, templateFunctions, &templateParams, "client_grpc_generated.go")
+}
+
+func executeTemplate(name, text string, templateFunctions map[string]interface{}, templateParams *HooksTemplateParams, filename string) {
+	tmpl, err := template.New(name).Funcs(templateFunctions).Parse(text)
+	if err != nil {
+		panic(err)
+	}
+
 	templateResult := &bytes.Buffer{}
-	hooksTemplate.Execute(templateResult, &templateParams)
+	tmpl.Execute(templateResult, templateParams)
 
 	importsBuffer := &bytes.Buffer{}
 	cmd := exec.Command("goimports")
@@ -332,7 +363,7 @@ func generateGlue(info *PluginInterfaceInfo) {
 		panic(err)
 	}
 
-	if err := ioutil.WriteFile(filepath.Join(getPluginPackageDir(), "client_rpc_generated.go"), importsBuffer.Bytes(), 0664); err != nil {
+	if err := ioutil.WriteFile(filepath.Join(getPluginPackageDir(), filename), importsBuffer.Bytes(), 0664); err != nil {
 		panic(err)
 	}
 }
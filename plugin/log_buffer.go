@@ -0,0 +1,89 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"sync"
+)
+
+// The number of entries that are kept for each plugin
+const LOG_BUFFER_SIZE = 1000
+
+// New entries are dropped for subscribers that have this many entries waiting to be read
+const logBufferSubscriberBacklog = 100
+
+// LogBuffer keeps the most recent entries from a plugin's log, including the output of its process and the messages
+// that it logs through the API, so that they can be viewed without access to the server's log. Each entry is a line
+// of JSON written by mlog.
+type LogBuffer struct {
+	lock        sync.Mutex
+	entries     []string
+	next        int
+	full        bool
+	subscribers map[chan string]bool
+}
+
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{
+		entries:     make([]string, size),
+		subscribers: make(map[chan string]bool),
+	}
+}
+
+// Write adds an entry to the buffer. It's called by mlog once for each entry.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	entry := string(bytes.TrimRight(p, "\n"))
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Entries returns the entries in the buffer from oldest to newest.
+func (b *LogBuffer) Entries() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.entriesLocked()
+}
+
+func (b *LogBuffer) entriesLocked() []string {
+	if !b.full {
+		return append([]string{}, b.entries[:b.next]...)
+	}
+
+	return append(append([]string{}, b.entries[b.next:]...), b.entries[:b.next]...)
+}
+
+// Subscribe returns the entries in the buffer along with a channel that receives each entry that's added after them.
+// Entries are dropped if the subscriber falls too far behind. The returned function must be called to unsubscribe.
+func (b *LogBuffer) Subscribe() ([]string, <-chan string, func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscriber := make(chan string, logBufferSubscriberBacklog)
+	b.subscribers[subscriber] = true
+
+	return b.entriesLocked(), subscriber, func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+
+		delete(b.subscribers, subscriber)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBuffer(t *testing.T) {
+	buffer := NewLogBuffer(3)
+	assert.Empty(t, buffer.Entries())
+
+	buffer.Write([]byte("{\"msg\":\"1\"}\n"))
+	buffer.Write([]byte("{\"msg\":\"2\"}\n"))
+	assert.Equal(t, []string{`{"msg":"1"}`, `{"msg":"2"}`}, buffer.Entries())
+
+	buffer.Write([]byte("{\"msg\":\"3\"}\n"))
+	buffer.Write([]byte("{\"msg\":\"4\"}\n"))
+	assert.Equal(t, []string{`{"msg":"2"}`, `{"msg":"3"}`, `{"msg":"4"}`}, buffer.Entries())
+
+	entries, newEntries, unsubscribe := buffer.Subscribe()
+	assert.Equal(t, []string{`{"msg":"2"}`, `{"msg":"3"}`, `{"msg":"4"}`}, entries)
+
+	buffer.Write([]byte("{\"msg\":\"5\"}\n"))
+	assert.Equal(t, `{"msg":"5"}`, <-newEntries)
+
+	unsubscribe()
+	buffer.Write([]byte("{\"msg\":\"6\"}\n"))
+	assert.Empty(t, newEntries)
+}
+
+func TestLogBufferSubscriberBacklog(t *testing.T) {
+	buffer := NewLogBuffer(LOG_BUFFER_SIZE)
+
+	_, newEntries, unsubscribe := buffer.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < logBufferSubscriberBacklog+10; i++ {
+		buffer.Write([]byte("{}\n"))
+	}
+
+	assert.Len(t, newEntries, logBufferSubscriberBacklog)
+	assert.Len(t, buffer.Entries(), logBufferSubscriberBacklog+10)
+}
@@ -0,0 +1,6 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package pluginproto contains the Go types for the gRPC protocol in plugin.proto, which is used by server plugins
+// written in languages other than Go. plugin.pb.go is generated from plugin.proto by `make pluginproto`.
+package pluginproto
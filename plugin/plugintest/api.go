@@ -112,6 +112,31 @@ func (_m *API) CreateChannel(channel *model.Channel) (*model.Channel, *model.App
 	return r0, r1
 }
 
+// CreateChannelSidebarCategory provides a mock function with given fields: userId, teamId, category
+func (_m *API) CreateChannelSidebarCategory(userId string, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	ret := _m.Called(userId, teamId, category)
+
+	var r0 *model.SidebarCategory
+	if rf, ok := ret.Get(0).(func(string, string, *model.SidebarCategory) *model.SidebarCategory); ok {
+		r0 = rf(userId, teamId, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SidebarCategory)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, string, *model.SidebarCategory) *model.AppError); ok {
+		r1 = rf(userId, teamId, category)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // CreatePost provides a mock function with given fields: post
 func (_m *API) CreatePost(post *model.Post) (*model.Post, *model.AppError) {
 	ret := _m.Called(post)
@@ -269,6 +294,27 @@ func (_m *API) DeleteChannelMember(channelId string, userId string) *model.AppEr
 	return r0
 }
 
+// DeleteChannelSidebarCategory provides a mock function with given fields: userId, teamId, categoryId
+func (_m *API) DeleteChannelSidebarCategory(userId string, teamId string, categoryId string) *model.AppError {
+	ret := _m.Called(userId, teamId, categoryId)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string) *model.AppError); ok {
+		r0 = rf(userId, teamId, categoryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
+// DeleteEphemeralPost provides a mock function with given fields: userId, postId
+func (_m *API) DeleteEphemeralPost(userId string, postId string) {
+	_m.Called(userId, postId)
+}
+
 // DeletePost provides a mock function with given fields: postId
 func (_m *API) DeletePost(postId string) *model.AppError {
 	ret := _m.Called(postId)
@@ -433,6 +479,31 @@ func (_m *API) GetChannelMember(channelId string, userId string) (*model.Channel
 	return r0, r1
 }
 
+// GetChannelSidebarCategories provides a mock function with given fields: userId, teamId
+func (_m *API) GetChannelSidebarCategories(userId string, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	ret := _m.Called(userId, teamId)
+
+	var r0 []*model.SidebarCategory
+	if rf, ok := ret.Get(0).(func(string, string) []*model.SidebarCategory); ok {
+		r0 = rf(userId, teamId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SidebarCategory)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, string) *model.AppError); ok {
+		r1 = rf(userId, teamId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetConfig provides a mock function with given fields:
 func (_m *API) GetConfig() *model.Config {
 	ret := _m.Called()
@@ -941,6 +1012,52 @@ func (_m *API) HasPermissionToTeam(userId string, teamId string, permission *mod
 	return r0
 }
 
+// KVCompareAndDelete provides a mock function with given fields: key, oldValue
+func (_m *API) KVCompareAndDelete(key string, oldValue []byte) (bool, *model.AppError) {
+	ret := _m.Called(key, oldValue)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, []byte) bool); ok {
+		r0 = rf(key, oldValue)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, []byte) *model.AppError); ok {
+		r1 = rf(key, oldValue)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// KVCompareAndSet provides a mock function with given fields: key, oldValue, newValue
+func (_m *API) KVCompareAndSet(key string, oldValue []byte, newValue []byte) (bool, *model.AppError) {
+	ret := _m.Called(key, oldValue, newValue)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, []byte, []byte) bool); ok {
+		r0 = rf(key, oldValue, newValue)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, []byte, []byte) *model.AppError); ok {
+		r1 = rf(key, oldValue, newValue)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // KVDelete provides a mock function with given fields: key
 func (_m *API) KVDelete(key string) *model.AppError {
 	ret := _m.Called(key)
@@ -998,6 +1115,22 @@ func (_m *API) KVSet(key string, value []byte) *model.AppError {
 	return r0
 }
 
+// KVSetWithExpiry provides a mock function with given fields: key, value, expireInSeconds
+func (_m *API) KVSetWithExpiry(key string, value []byte, expireInSeconds int64) *model.AppError {
+	ret := _m.Called(key, value, expireInSeconds)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(string, []byte, int64) *model.AppError); ok {
+		r0 = rf(key, value, expireInSeconds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
 // LoadPluginConfiguration provides a mock function with given fields: dest
 func (_m *API) LoadPluginConfiguration(dest interface{}) error {
 	ret := _m.Called(dest)
@@ -1104,6 +1237,22 @@ func (_m *API) RemoveReaction(reaction *model.Reaction) *model.AppError {
 	return r0
 }
 
+// RemoveUserCustomStatus provides a mock function with given fields: userId
+func (_m *API) RemoveUserCustomStatus(userId string) *model.AppError {
+	ret := _m.Called(userId)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(string) *model.AppError); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
 // SaveConfig provides a mock function with given fields: config
 func (_m *API) SaveConfig(config *model.Config) *model.AppError {
 	ret := _m.Called(config)
@@ -1120,6 +1269,72 @@ func (_m *API) SaveConfig(config *model.Config) *model.AppError {
 	return r0
 }
 
+// ScheduleJob provides a mock function with given fields: job
+func (_m *API) ScheduleJob(job *model.PluginJob) *model.AppError {
+	ret := _m.Called(job)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(*model.PluginJob) *model.AppError); ok {
+		r0 = rf(job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
+// SearchChannels provides a mock function with given fields: teamId, term
+func (_m *API) SearchChannels(teamId string, term string) (*model.ChannelList, *model.AppError) {
+	ret := _m.Called(teamId, term)
+
+	var r0 *model.ChannelList
+	if rf, ok := ret.Get(0).(func(string, string) *model.ChannelList); ok {
+		r0 = rf(teamId, term)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ChannelList)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, string) *model.AppError); ok {
+		r1 = rf(teamId, term)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SearchPosts provides a mock function with given fields: teamId, userId, terms, isOrSearch
+func (_m *API) SearchPosts(teamId string, userId string, terms string, isOrSearch bool) (*model.PostList, *model.AppError) {
+	ret := _m.Called(teamId, userId, terms, isOrSearch)
+
+	var r0 *model.PostList
+	if rf, ok := ret.Get(0).(func(string, string, string, bool) *model.PostList); ok {
+		r0 = rf(teamId, userId, terms, isOrSearch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostList)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, string, string, bool) *model.AppError); ok {
+		r1 = rf(teamId, userId, terms, isOrSearch)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // SendEphemeralPost provides a mock function with given fields: userId, post
 func (_m *API) SendEphemeralPost(userId string, post *model.Post) *model.Post {
 	ret := _m.Called(userId, post)
@@ -1150,6 +1365,22 @@ func (_m *API) UnregisterCommand(teamId string, trigger string) error {
 	return r0
 }
 
+// UnscheduleJob provides a mock function with given fields: jobId
+func (_m *API) UnscheduleJob(jobId string) *model.AppError {
+	ret := _m.Called(jobId)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(string) *model.AppError); ok {
+		r0 = rf(jobId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateChannel provides a mock function with given fields: channel
 func (_m *API) UpdateChannel(channel *model.Channel) (*model.Channel, *model.AppError) {
 	ret := _m.Called(channel)
@@ -1225,6 +1456,47 @@ func (_m *API) UpdateChannelMemberRoles(channelId string, userId string, newRole
 	return r0, r1
 }
 
+// UpdateChannelSidebarCategory provides a mock function with given fields: userId, teamId, category
+func (_m *API) UpdateChannelSidebarCategory(userId string, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	ret := _m.Called(userId, teamId, category)
+
+	var r0 *model.SidebarCategory
+	if rf, ok := ret.Get(0).(func(string, string, *model.SidebarCategory) *model.SidebarCategory); ok {
+		r0 = rf(userId, teamId, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SidebarCategory)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string, string, *model.SidebarCategory) *model.AppError); ok {
+		r1 = rf(userId, teamId, category)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateEphemeralPost provides a mock function with given fields: userId, post
+func (_m *API) UpdateEphemeralPost(userId string, post *model.Post) *model.Post {
+	ret := _m.Called(userId, post)
+
+	var r0 *model.Post
+	if rf, ok := ret.Get(0).(func(string, *model.Post) *model.Post); ok {
+		r0 = rf(userId, post)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	return r0
+}
+
 // UpdatePost provides a mock function with given fields: post
 func (_m *API) UpdatePost(post *model.Post) (*model.Post, *model.AppError) {
 	ret := _m.Called(post)
@@ -1325,6 +1597,22 @@ func (_m *API) UpdateUser(user *model.User) (*model.User, *model.AppError) {
 	return r0, r1
 }
 
+// UpdateUserCustomStatus provides a mock function with given fields: userId, customStatus
+func (_m *API) UpdateUserCustomStatus(userId string, customStatus *model.CustomStatus) *model.AppError {
+	ret := _m.Called(userId, customStatus)
+
+	var r0 *model.AppError
+	if rf, ok := ret.Get(0).(func(string, *model.CustomStatus) *model.AppError); ok {
+		r0 = rf(userId, customStatus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AppError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateUserStatus provides a mock function with given fields: userId, status
 func (_m *API) UpdateUserStatus(userId string, status string) (*model.Status, *model.AppError) {
 	ret := _m.Called(userId, status)
@@ -8,6 +8,7 @@ import http "net/http"
 import io "io"
 import mock "github.com/stretchr/testify/mock"
 import model "github.com/mattermost/mattermost-server/model"
+import opengraph "github.com/dyatlov/go-opengraph/opengraph"
 import plugin "github.com/mattermost/mattermost-server/plugin"
 
 // Hooks is an autogenerated mock type for the Hooks type
@@ -45,6 +46,11 @@ func (_m *Hooks) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 	return r0, r1
 }
 
+// ExecuteScheduledJob provides a mock function with given fields: c, jobId
+func (_m *Hooks) ExecuteScheduledJob(c *plugin.Context, jobId string) {
+	_m.Called(c, jobId)
+}
+
 // FileWillBeUploaded provides a mock function with given fields: c, info, file, output
 func (_m *Hooks) FileWillBeUploaded(c *plugin.Context, info *model.FileInfo, file io.Reader, output io.Writer) (*model.FileInfo, string) {
 	ret := _m.Called(c, info, file, output)
@@ -68,6 +74,31 @@ func (_m *Hooks) FileWillBeUploaded(c *plugin.Context, info *model.FileInfo, fil
 	return r0, r1
 }
 
+// GetSettingOptions provides a mock function with given fields: c, key
+func (_m *Hooks) GetSettingOptions(c *plugin.Context, key string) ([]*model.PluginOption, *model.AppError) {
+	ret := _m.Called(c, key)
+
+	var r0 []*model.PluginOption
+	if rf, ok := ret.Get(0).(func(*plugin.Context, string) []*model.PluginOption); ok {
+		r0 = rf(c, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PluginOption)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(*plugin.Context, string) *model.AppError); ok {
+		r1 = rf(c, key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // Implemented provides a mock function with given fields:
 func (_m *Hooks) Implemented() ([]string, error) {
 	ret := _m.Called()
@@ -91,6 +122,22 @@ func (_m *Hooks) Implemented() ([]string, error) {
 	return r0, r1
 }
 
+// LinkMetadataWillBeSaved provides a mock function with given fields: c, requestURL, og
+func (_m *Hooks) LinkMetadataWillBeSaved(c *plugin.Context, requestURL string, og *opengraph.OpenGraph) *opengraph.OpenGraph {
+	ret := _m.Called(c, requestURL, og)
+
+	var r0 *opengraph.OpenGraph
+	if rf, ok := ret.Get(0).(func(*plugin.Context, string, *opengraph.OpenGraph) *opengraph.OpenGraph); ok {
+		r0 = rf(c, requestURL, og)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*opengraph.OpenGraph)
+		}
+	}
+
+	return r0
+}
+
 // MessageHasBeenPosted provides a mock function with given fields: c, post
 func (_m *Hooks) MessageHasBeenPosted(c *plugin.Context, post *model.Post) {
 	_m.Called(c, post)
@@ -189,6 +236,16 @@ func (_m *Hooks) OnDeactivate() error {
 	return r0
 }
 
+// ReactionHasBeenAdded provides a mock function with given fields: c, reaction, post
+func (_m *Hooks) ReactionHasBeenAdded(c *plugin.Context, reaction *model.Reaction, post *model.Post) {
+	_m.Called(c, reaction, post)
+}
+
+// ReactionHasBeenRemoved provides a mock function with given fields: c, reaction, post
+func (_m *Hooks) ReactionHasBeenRemoved(c *plugin.Context, reaction *model.Reaction, post *model.Post) {
+	_m.Called(c, reaction, post)
+}
+
 // ServeHTTP provides a mock function with given fields: c, w, r
 func (_m *Hooks) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Request) {
 	_m.Called(c, w, r)
@@ -219,6 +276,11 @@ func (_m *Hooks) UserHasLoggedIn(c *plugin.Context, user *model.User) {
 	_m.Called(c, user)
 }
 
+// UserStatusHasChanged provides a mock function with given fields: c, status
+func (_m *Hooks) UserStatusHasChanged(c *plugin.Context, status *model.Status) {
+	_m.Called(c, status)
+}
+
 // UserWillLogIn provides a mock function with given fields: c, user
 func (_m *Hooks) UserWillLogIn(c *plugin.Context, user *model.User) string {
 	ret := _m.Called(c, user)
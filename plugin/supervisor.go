@@ -5,21 +5,30 @@ package plugin
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
+	"github.com/pkg/errors"
 )
 
 type supervisor struct {
 	client      *plugin.Client
+	rpcClient   plugin.ClientProtocol
 	hooks       Hooks
 	implemented [TotalHooksId]bool
+
+	// The number of hooks that are running in the plugin through RunPluginHook or RunMultiPluginHook
+	inFlight int32
 }
 
 func newSupervisor(pluginInfo *model.BundleInfo, parentLogger *mlog.Logger, apiImpl API) (retSupervisor *supervisor, retErr error) {
@@ -61,12 +70,17 @@ func newSupervisor(pluginInfo *model.BundleInfo, parentLogger *mlog.Logger, apiI
 		SyncStderr:      wrappedLogger.With(mlog.String("source", "plugin_stderr")).StdLogWriter(),
 		Logger:          hclogAdaptedLogger,
 		StartTimeout:    time.Second * 3,
+		AllowedProtocols: []plugin.Protocol{
+			plugin.ProtocolNetRPC,
+			plugin.ProtocolGRPC,
+		},
 	})
 
 	rpcClient, err := supervisor.client.Client()
 	if err != nil {
 		return nil, err
 	}
+	supervisor.rpcClient = rpcClient
 
 	raw, err := rpcClient.Dispense("hooks")
 	if err != nil {
@@ -99,6 +113,75 @@ func (sup *supervisor) Shutdown() {
 	}
 }
 
+// PerformHealthCheck returns an error if the plugin's process has exited or doesn't respond to a ping in time.
+func (sup *supervisor) PerformHealthCheck() error {
+	if sup.client.Exited() {
+		return fmt.Errorf("plugin process exited")
+	}
+
+	pinged := make(chan error, 1)
+	go func() {
+		pinged <- sup.rpcClient.Ping()
+	}()
+
+	select {
+	case err := <-pinged:
+		if err != nil {
+			return errors.Wrap(err, "plugin didn't respond to a ping")
+		}
+	case <-time.After(healthCheckPingTimeout):
+		return fmt.Errorf("plugin didn't respond to a ping within %v", healthCheckPingTimeout)
+	}
+
+	return nil
+}
+
+// MemoryUsage returns the number of bytes of resident memory used by the plugin's process. It's read from /proc, so
+// it returns an error on systems other than Linux.
+func (sup *supervisor) MemoryUsage() (int64, error) {
+	pid := sup.client.ReattachConfig().Pid
+
+	statm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to read the plugin process's memory usage")
+	}
+
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unable to parse the plugin process's memory usage")
+	}
+
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to parse the plugin process's memory usage")
+	}
+
+	return pages * int64(os.Getpagesize()), nil
+}
+
+// beginHook and endHook are called around hooks that need to finish before the plugin is stopped by an upgrade.
+func (sup *supervisor) beginHook() {
+	atomic.AddInt32(&sup.inFlight, 1)
+}
+
+func (sup *supervisor) endHook() {
+	atomic.AddInt32(&sup.inFlight, -1)
+}
+
+// drain waits until no hooks are running in the plugin, or until the timeout has passed. It returns false if hooks
+// were still running.
+func (sup *supervisor) drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt32(&sup.inFlight) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(drainPollInterval)
+	}
+
+	return true
+}
+
 func (sup *supervisor) Hooks() Hooks {
 	return sup.hooks
 }
@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+	"github.com/pkg/errors"
+)
+
+var (
+	// The longest that the old version of an upgraded plugin is kept running for hooks that haven't returned yet
+	UpgradeDrainTimeout = 30 * time.Second
+
+	drainPollInterval = 10 * time.Millisecond
+)
+
+// Upgrade replaces an active plugin with the version in sourceDir without deactivating it first, so that it keeps
+// handling hooks and serving its webapp bundle while the new version starts.
+//
+// Once the new version has been activated, hooks are sent to it instead and its webapp bundle is served in place of
+// the old one. The old version is then stopped as soon as the hooks that were running in it through RunPluginHook or
+// RunMultiPluginHook have returned, or after UpgradeDrainTimeout. Since the plugin stays active throughout, the old
+// version isn't sent OnDeactivate, so that it doesn't undo things that the new version has set up, such as commands.
+//
+// If the new version can't be started, the old version is put back and keeps running.
+func (env *Environment) Upgrade(id string, sourceDir string) (*model.Manifest, error) {
+	p, ok := env.activePlugins.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("plugin not active: %v", id)
+	}
+	oldPlugin := p.(activePlugin)
+
+	// The old version's files are moved aside rather than removed, since its process is still running from them
+	pluginPath := filepath.Join(env.pluginDir, id)
+	previousPath := filepath.Join(env.pluginDir, "."+id+".previous")
+
+	if err := os.RemoveAll(previousPath); err != nil {
+		return nil, errors.Wrapf(err, "unable to remove previous plugin directory: %v", previousPath)
+	}
+
+	if err := os.Rename(pluginPath, previousPath); err != nil {
+		return nil, errors.Wrapf(err, "unable to move plugin directory: %v", id)
+	}
+
+	restore := func() {
+		if err := os.RemoveAll(pluginPath); err != nil {
+			env.pluginLogger(id).Error("Unable to remove the new version of a plugin that failed to upgrade", mlog.String("plugin_id", id), mlog.Err(err))
+		}
+		if err := os.Rename(previousPath, pluginPath); err != nil {
+			env.pluginLogger(id).Error("Unable to restore the old version of a plugin that failed to upgrade", mlog.String("plugin_id", id), mlog.Err(err))
+		}
+	}
+
+	if err := utils.CopyDir(sourceDir, pluginPath); err != nil {
+		restore()
+		return nil, errors.Wrapf(err, "unable to copy plugin directory: %v", id)
+	}
+
+	newPlugin, webappFiles, err := env.startUpgradedPlugin(id)
+	if err != nil {
+		restore()
+		return nil, err
+	}
+
+	env.activePlugins.Store(id, newPlugin)
+
+	if oldPlugin.supervisor != nil {
+		if !oldPlugin.supervisor.drain(UpgradeDrainTimeout) {
+			env.pluginLogger(id).Warn("Stopping the old version of an upgraded plugin before its hooks returned", mlog.String("plugin_id", id), mlog.String("timeout", UpgradeDrainTimeout.String()))
+		}
+
+		oldPlugin.supervisor.Shutdown()
+	}
+
+	env.removeStaleWebappFiles(id, webappFiles)
+
+	if err := os.RemoveAll(previousPath); err != nil {
+		env.pluginLogger(id).Warn("Unable to remove the old version of an upgraded plugin", mlog.String("plugin_id", id), mlog.Err(err))
+	}
+
+	return newPlugin.BundleInfo.Manifest, nil
+}
+
+// startUpgradedPlugin unpacks the webapp bundle and starts the server of a plugin that's replacing one that's still
+// active. It returns the names of the webapp files that belong to the new version.
+func (env *Environment) startUpgradedPlugin(id string) (activePlugin, []string, error) {
+	pluginInfo, err := env.findBundle(id)
+	if err != nil {
+		return activePlugin{}, nil, err
+	}
+
+	newPlugin := activePlugin{BundleInfo: pluginInfo, State: model.PluginStateRunning}
+
+	if pluginInfo.Manifest.HasServer() {
+		supervisor, err := newSupervisor(pluginInfo, env.pluginLogger(id), env.newAPIImpl(pluginInfo.Manifest))
+		if err != nil {
+			return activePlugin{}, nil, errors.Wrapf(err, "unable to start plugin: %v", id)
+		}
+		newPlugin.supervisor = supervisor
+	}
+
+	var webappFiles []string
+	if pluginInfo.Manifest.Webapp != nil {
+		if webappFiles, err = env.unpackWebappBundle(pluginInfo); err != nil {
+			if newPlugin.supervisor != nil {
+				newPlugin.supervisor.Shutdown()
+			}
+			return activePlugin{}, nil, err
+		}
+	}
+
+	return newPlugin, webappFiles, nil
+}
+
+// removeStaleWebappFiles removes the files that were left in a plugin's webapp directory by an old version of it.
+func (env *Environment) removeStaleWebappFiles(id string, webappFiles []string) {
+	keep := make(map[string]bool, len(webappFiles))
+	for _, name := range webappFiles {
+		keep[name] = true
+	}
+
+	destinationPath := filepath.Join(env.webappPluginDir, id)
+
+	files, err := ioutil.ReadDir(destinationPath)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if keep[file.Name()] {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(destinationPath, file.Name())); err != nil {
+			env.pluginLogger(id).Warn("Unable to remove an old webapp file of an upgraded plugin", mlog.String("plugin_id", id), mlog.String("file", file.Name()), mlog.Err(err))
+		}
+	}
+}
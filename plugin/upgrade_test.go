@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func writeUpgradeTestPlugin(t *testing.T, dir string, version string) {
+	compileGo(t, fmt.Sprintf(`
+		package main
+
+		import (
+			"net/http"
+			"time"
+
+			"github.com/mattermost/mattermost-server/plugin"
+		)
+
+		type MyPlugin struct {
+			plugin.MattermostPlugin
+		}
+
+		func (p *MyPlugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				time.Sleep(time.Second)
+			}
+			w.Write([]byte("%s"))
+		}
+
+		func main() {
+			plugin.ClientMain(&MyPlugin{})
+		}
+	`, version), filepath.Join(dir, "backend.exe"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "webapp"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "webapp", "main.js"), []byte("// "+version), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id": "testupgrade", "version": "`+version+`", "backend": {"executable": "backend.exe"}, "webapp": {"bundle_path": "webapp/main.js"}}`), 0600))
+}
+
+func serveUpgradeTestPlugin(t *testing.T, env *Environment, path string) string {
+	w := httptest.NewRecorder()
+	err := env.RunPluginHook("testupgrade", func(hooks Hooks) {
+		hooks.ServeHTTP(&Context{}, w, httptest.NewRequest(http.MethodGet, path, nil))
+	})
+	require.NoError(t, err)
+	return w.Body.String()
+}
+
+func TestUpgrade(t *testing.T) {
+	pluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(pluginDir)
+
+	webappPluginDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(webappPluginDir)
+
+	sourceDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(sourceDir)
+
+	writeUpgradeTestPlugin(t, filepath.Join(pluginDir, "testupgrade"), "1.0.0")
+	writeUpgradeTestPlugin(t, sourceDir, "2.0.0")
+
+	log := mlog.NewLogger(&mlog.LoggerConfiguration{
+		EnableConsole: true,
+		ConsoleJson:   true,
+		ConsoleLevel:  "error",
+		EnableFile:    false,
+	})
+
+	env, err := NewEnvironment(func(*model.Manifest) API { return nil }, pluginDir, webappPluginDir, log)
+	require.NoError(t, err)
+	defer env.Shutdown()
+
+	oldManifest, activated, err := env.Activate("testupgrade")
+	require.NoError(t, err)
+	require.True(t, activated)
+	oldBundle := fmt.Sprintf("testupgrade_%x_bundle.js", oldManifest.Webapp.BundleHash)
+
+	t.Run("in-flight hooks finish in the old version", func(t *testing.T) {
+		slowResponse := make(chan string)
+		go func() {
+			slowResponse <- serveUpgradeTestPlugin(t, env, "/slow")
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		manifest, err := env.Upgrade("testupgrade", sourceDir)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", manifest.Version)
+
+		assert.Equal(t, "1.0.0", <-slowResponse)
+		assert.Equal(t, "2.0.0", serveUpgradeTestPlugin(t, env, "/"))
+
+		require.Len(t, env.Active(), 1)
+		assert.Equal(t, "2.0.0", env.Active()[0].Manifest.Version)
+
+		newBundle := fmt.Sprintf("testupgrade_%x_bundle.js", manifest.Webapp.BundleHash)
+		assert.FileExists(t, filepath.Join(webappPluginDir, "testupgrade", newBundle))
+		_, err = os.Stat(filepath.Join(webappPluginDir, "testupgrade", oldBundle))
+		assert.True(t, os.IsNotExist(err), "should remove the old webapp bundle")
+
+		files, err := ioutil.ReadDir(pluginDir)
+		require.NoError(t, err)
+		require.Len(t, files, 1, "should remove the old version")
+	})
+
+	t.Run("the old version keeps running if the new one fails to start", func(t *testing.T) {
+		brokenDir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		defer os.RemoveAll(brokenDir)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(brokenDir, "backend.exe"), []byte("not an executable"), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(brokenDir, "plugin.json"), []byte(`{"id": "testupgrade", "version": "3.0.0", "backend": {"executable": "backend.exe"}}`), 0600))
+
+		_, err = env.Upgrade("testupgrade", brokenDir)
+		assert.Error(t, err)
+
+		assert.Equal(t, "2.0.0", serveUpgradeTestPlugin(t, env, "/"))
+
+		bundles, err := env.Available()
+		require.NoError(t, err)
+		require.Len(t, bundles, 1)
+		assert.Equal(t, "2.0.0", bundles[0].Manifest.Version)
+	})
+
+	_, err = env.Upgrade("notactive", sourceDir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rediscluster
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/mattermost/mattermost-server/app"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// server is what the cluster needs from the app that it belongs to.
+type server interface {
+	Config() *model.Config
+	SaveConfig(cfg *model.Config, sendConfigChangeClusterMessage bool) *model.AppError
+	GetLogsSkipSend(page, perPage int) ([]string, *model.AppError)
+	GetPluginStatuses() (model.PluginStatuses, *model.AppError)
+	TotalWebsocketConnections() int
+	TotalReadDbConnections() int
+	TotalMasterDbConnections() int
+	InvokeClusterLeaderChangedListeners()
+}
+
+type appServer struct {
+	*app.App
+}
+
+func (s appServer) TotalReadDbConnections() int {
+	return s.Srv.Store.TotalReadDbConnections()
+}
+
+func (s appServer) TotalMasterDbConnections() int {
+	return s.Srv.Store.TotalMasterDbConnections()
+}
+
+// backend is what the cluster needs from Redis, so that the servers can be tested against each other without it.
+type backend interface {
+	Ping() error
+
+	// Subscribe returns the payloads that are published to the channel until the returned function is called.
+	Subscribe(channel string) (<-chan string, func() error)
+	Publish(channel string, payload []byte) error
+
+	HSet(key, field string, value []byte) error
+	HGetAll(key string) (map[string]string, error)
+	HDel(key, field string) error
+
+	// AcquireLock takes the lock for the holder if nobody has it, or keeps it for the holder if it already has it,
+	// returning whether the holder has the lock until it expires.
+	AcquireLock(key, holder string, expiry time.Duration) (bool, error)
+	ReleaseLock(key, holder string) error
+
+	// Push adds a value to the end of a list that's removed once it expires.
+	Push(key string, value []byte, expiry time.Duration) error
+
+	// Pop waits for a value at the start of a list, returning false if there isn't one before the timeout.
+	Pop(key string, timeout time.Duration) (string, bool, error)
+	Del(key string) error
+
+	Close() error
+}
+
+// A lock is only renewed by its holder, so that it can't be taken over while it's being renewed.
+var renewLockScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("pexpire", KEYS[1], ARGV[2])
+	else
+		return 0
+	end
+`)
+
+var releaseLockScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("del", KEYS[1])
+	else
+		return 0
+	end
+`)
+
+type redisBackend struct {
+	client *redis.Client
+}
+
+func newRedisBackend(settings *model.ClusterSettings) backend {
+	return &redisBackend{
+		client: redis.NewClient(&redis.Options{
+			Addr:     *settings.RedisAddress,
+			Password: *settings.RedisPassword,
+			DB:       *settings.RedisDatabase,
+		}),
+	}
+}
+
+func (b *redisBackend) Ping() error {
+	return b.client.Ping().Err()
+}
+
+func (b *redisBackend) Subscribe(channel string) (<-chan string, func() error) {
+	pubsub := b.client.Subscribe(channel)
+
+	payloads := make(chan string)
+	go func() {
+		defer close(payloads)
+
+		for msg := range pubsub.Channel() {
+			payloads <- msg.Payload
+		}
+	}()
+
+	return payloads, pubsub.Close
+}
+
+func (b *redisBackend) Publish(channel string, payload []byte) error {
+	return b.client.Publish(channel, payload).Err()
+}
+
+func (b *redisBackend) HSet(key, field string, value []byte) error {
+	return b.client.HSet(key, field, value).Err()
+}
+
+func (b *redisBackend) HGetAll(key string) (map[string]string, error) {
+	return b.client.HGetAll(key).Result()
+}
+
+func (b *redisBackend) HDel(key, field string) error {
+	return b.client.HDel(key, field).Err()
+}
+
+func (b *redisBackend) AcquireLock(key, holder string, expiry time.Duration) (bool, error) {
+	acquired, err := b.client.SetNX(key, holder, expiry).Result()
+	if err != nil || acquired {
+		return acquired, err
+	}
+
+	renewed, err := renewLockScript.Run(b.client, []string{key}, holder, strconv.FormatInt(int64(expiry/time.Millisecond), 10)).Int64()
+	return renewed == 1, err
+}
+
+func (b *redisBackend) ReleaseLock(key, holder string) error {
+	return releaseLockScript.Run(b.client, []string{key}, holder).Err()
+}
+
+func (b *redisBackend) Push(key string, value []byte, expiry time.Duration) error {
+	pipe := b.client.TxPipeline()
+	pipe.RPush(key, value)
+	pipe.Expire(key, expiry)
+	_, err := pipe.Exec()
+	return err
+}
+
+func (b *redisBackend) Pop(key string, timeout time.Duration) (string, bool, error) {
+	// Redis waits forever when given a timeout of less than a second
+	if timeout < time.Second {
+		timeout = time.Second
+	}
+
+	result, err := b.client.BLPop(timeout, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	return result[1], true, nil
+}
+
+func (b *redisBackend) Del(key string) error {
+	return b.client.Del(key).Err()
+}
+
+func (b *redisBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rediscluster
+
+import (
+	"encoding/json"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// Each server keeps its entry in the nodes hash up to date, and entries that haven't been updated recently are
+// treated as servers that have left the cluster.
+type node struct {
+	Info       *model.ClusterInfo `json:"info"`
+	LastSeenAt int64              `json:"last_seen_at"`
+}
+
+func (c *RedisCluster) GetMyClusterInfo() *model.ClusterInfo {
+	settings := c.server.Config().ClusterSettings
+
+	hostname := *settings.OverrideHostname
+	if hostname == "" {
+		if hn, err := os.Hostname(); err == nil {
+			hostname = hn
+		}
+	}
+
+	return &model.ClusterInfo{
+		Id:         c.id,
+		Version:    model.CurrentVersion,
+		ConfigHash: utils.HashSha256(c.server.Config().ToJson()),
+		IpAddress:  model.GetServerIpAddress(),
+		Hostname:   hostname,
+	}
+}
+
+// GetClusterInfos describes the servers in the cluster, including this one.
+func (c *RedisCluster) GetClusterInfos() []*model.ClusterInfo {
+	nodes, err := c.nodes()
+	if err != nil {
+		mlog.Error("Unable to get the servers in the cluster", mlog.Err(err))
+		return []*model.ClusterInfo{c.GetMyClusterInfo()}
+	}
+
+	infos := make([]*model.ClusterInfo, 0, len(nodes))
+	for _, n := range nodes {
+		infos = append(infos, n.Info)
+	}
+
+	return infos
+}
+
+// nodes returns the servers that are in the cluster, removing the entries of those that have left.
+func (c *RedisCluster) nodes() ([]*node, error) {
+	if atomic.LoadInt32(&c.started) == 0 {
+		return []*node{{Info: c.GetMyClusterInfo(), LastSeenAt: model.GetMillis()}}, nil
+	}
+
+	entries, err := c.backend.HGetAll(c.key("nodes"))
+	if err != nil {
+		return nil, err
+	}
+
+	expireBefore := model.GetMillis() - int64(REDIS_CLUSTER_NODE_EXPIRY/time.Millisecond)
+
+	nodes := make([]*node, 0, len(entries))
+	for id, entry := range entries {
+		var n node
+		if err := json.Unmarshal([]byte(entry), &n); err != nil || n.Info == nil || n.LastSeenAt < expireBefore {
+			c.backend.HDel(c.key("nodes"), id)
+			continue
+		}
+
+		nodes = append(nodes, &n)
+	}
+
+	return nodes, nil
+}
+
+func (c *RedisCluster) heartbeat() {
+	defer c.stopped.Done()
+
+	ticker := time.NewTicker(REDIS_CLUSTER_HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		c.updateNode()
+		c.updateLeadership()
+
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *RedisCluster) updateNode() {
+	b, err := json.Marshal(&node{Info: c.GetMyClusterInfo(), LastSeenAt: model.GetMillis()})
+	if err != nil {
+		return
+	}
+
+	if err := c.backend.HSet(c.key("nodes"), c.id, b); err != nil {
+		mlog.Error("Unable to update this server's entry in the cluster", mlog.Err(err))
+	}
+}
+
+func (c *RedisCluster) updateLeadership() {
+	leader, err := c.backend.AcquireLock(c.key("leader"), c.id, REDIS_CLUSTER_LEADER_EXPIRY)
+	if err != nil {
+		// Without Redis, this server can't tell whether another one has taken over
+		mlog.Error("Unable to update the cluster leader", mlog.Err(err))
+		leader = false
+	}
+
+	c.setLeader(leader)
+}
+
+func (c *RedisCluster) releaseLeadership() {
+	if !c.IsLeader() {
+		return
+	}
+
+	if err := c.backend.ReleaseLock(c.key("leader"), c.id); err != nil {
+		mlog.Error("Unable to step down as the cluster leader", mlog.Err(err))
+	}
+
+	c.setLeader(false)
+}
+
+func (c *RedisCluster) setLeader(leader bool) {
+	var value int32
+	if leader {
+		value = 1
+	}
+
+	if atomic.SwapInt32(&c.leader, value) != value {
+		mlog.Info("The cluster leader changed", mlog.Bool("is_leader", leader))
+		c.server.InvokeClusterLeaderChangedListeners()
+	}
+}
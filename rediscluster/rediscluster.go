@@ -0,0 +1,267 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+// Package rediscluster lets the servers in a cluster talk to each other through Redis pub/sub, so that cache
+// invalidations and websocket events reach every server without a licensed clustering implementation. It's used
+// instead of any other clustering implementation when ClusterSettings.RedisAddress is set.
+package rediscluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost-server/app"
+	"github.com/mattermost/mattermost-server/einterfaces"
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+const (
+	REDIS_CLUSTER_SEND_QUEUE_SIZE     = 4096
+	REDIS_CLUSTER_HEARTBEAT_INTERVAL  = 5 * time.Second
+	REDIS_CLUSTER_NODE_EXPIRY         = 3 * REDIS_CLUSTER_HEARTBEAT_INTERVAL
+	REDIS_CLUSTER_LEADER_EXPIRY       = 3 * REDIS_CLUSTER_HEARTBEAT_INTERVAL
+	REDIS_CLUSTER_RESPONSE_KEY_EXPIRY = time.Minute
+)
+
+// The kinds of envelopes that are sent between the servers
+const (
+	envelopeMessage       = "message"
+	envelopeConfigChanged = "config_changed"
+	envelopeRequest       = "request"
+)
+
+// envelope wraps everything that's published to the cluster's channel so that each server can ignore what it sent.
+type envelope struct {
+	Kind    string                `json:"kind"`
+	From    string                `json:"from"`
+	Message *model.ClusterMessage `json:"message,omitempty"`
+	Config  *model.Config         `json:"config,omitempty"`
+	Request *request              `json:"request,omitempty"`
+}
+
+type RedisCluster struct {
+	server      server
+	newBackend  func(settings *model.ClusterSettings) backend
+	id          string
+	backend     backend
+	unsubscribe func() error
+
+	handlersLock sync.RWMutex
+	handlers     map[string]einterfaces.ClusterMessageHandler
+
+	started   int32
+	leader    int32
+	sendQueue chan *model.ClusterMessage
+	stop      chan struct{}
+	stopped   sync.WaitGroup
+}
+
+func init() {
+	app.RegisterRedisClusterInterface(func(a *app.App) einterfaces.ClusterInterface {
+		return NewRedisCluster(a)
+	})
+}
+
+func NewRedisCluster(a *app.App) *RedisCluster {
+	return newRedisCluster(appServer{a}, newRedisBackend)
+}
+
+func newRedisCluster(s server, newBackend func(settings *model.ClusterSettings) backend) *RedisCluster {
+	return &RedisCluster{
+		server:     s,
+		newBackend: newBackend,
+		id:         model.NewId(),
+		handlers:   make(map[string]einterfaces.ClusterMessageHandler),
+		sendQueue:  make(chan *model.ClusterMessage, REDIS_CLUSTER_SEND_QUEUE_SIZE),
+	}
+}
+
+// key returns the name of a Redis key or channel that belongs to the cluster, so that more than one cluster can
+// share a Redis server.
+func (c *RedisCluster) key(name string) string {
+	return fmt.Sprintf("mattermost:cluster:%v:%v", *c.server.Config().ClusterSettings.ClusterName, name)
+}
+
+func (c *RedisCluster) StartInterNodeCommunication() {
+	settings := c.server.Config().ClusterSettings
+	if !*settings.Enable {
+		return
+	}
+
+	c.backend = c.newBackend(&settings)
+
+	if err := c.backend.Ping(); err != nil {
+		// The client keeps trying to connect, so the cluster starts working once Redis is reachable
+		mlog.Error("Unable to reach Redis for clustering", mlog.String("address", *settings.RedisAddress), mlog.Err(err))
+	}
+
+	var messages <-chan string
+	messages, c.unsubscribe = c.backend.Subscribe(c.key("messages"))
+	c.stop = make(chan struct{})
+
+	c.stopped.Add(3)
+	go c.receive(messages)
+	go c.send()
+	go c.heartbeat()
+
+	atomic.StoreInt32(&c.started, 1)
+
+	mlog.Info("Started clustering through Redis", mlog.String("address", *settings.RedisAddress), mlog.String("cluster_id", c.id))
+}
+
+func (c *RedisCluster) StopInterNodeCommunication() {
+	if !atomic.CompareAndSwapInt32(&c.started, 1, 0) {
+		return
+	}
+
+	close(c.stop)
+	c.unsubscribe()
+	c.stopped.Wait()
+
+	c.backend.HDel(c.key("nodes"), c.id)
+	c.releaseLeadership()
+	c.backend.Close()
+
+	mlog.Info("Stopped clustering through Redis")
+}
+
+func (c *RedisCluster) RegisterClusterMessageHandler(event string, crm einterfaces.ClusterMessageHandler) {
+	c.handlersLock.Lock()
+	defer c.handlersLock.Unlock()
+
+	c.handlers[event] = crm
+}
+
+func (c *RedisCluster) GetClusterId() string {
+	return c.id
+}
+
+func (c *RedisCluster) IsLeader() bool {
+	return atomic.LoadInt32(&c.leader) == 1
+}
+
+// SendClusterMessage publishes a message to the other servers. Messages are published in the order that they're sent,
+// except for those that wait for all to send, which are published right away. Best effort messages are dropped if
+// too many are waiting to be published.
+func (c *RedisCluster) SendClusterMessage(msg *model.ClusterMessage) {
+	if atomic.LoadInt32(&c.started) == 0 {
+		return
+	}
+
+	if msg.WaitForAllToSend {
+		c.publish(&envelope{Kind: envelopeMessage, Message: msg})
+		return
+	}
+
+	if msg.SendType == model.CLUSTER_SEND_RELIABLE {
+		select {
+		case c.sendQueue <- msg:
+		case <-c.stop:
+		}
+		return
+	}
+
+	select {
+	case c.sendQueue <- msg:
+	default:
+		mlog.Warn("Dropping cluster message since too many are waiting to be sent", mlog.String("event", msg.Event))
+	}
+}
+
+// NotifyMsg handles an envelope that was received from another server.
+func (c *RedisCluster) NotifyMsg(buf []byte) {
+	var e envelope
+	if err := json.Unmarshal(buf, &e); err != nil {
+		mlog.Error("Unable to decode a cluster message", mlog.Err(err))
+		return
+	}
+
+	if e.From == c.id {
+		return
+	}
+
+	switch e.Kind {
+	case envelopeMessage:
+		if e.Message == nil {
+			return
+		}
+
+		c.handlersLock.RLock()
+		handler := c.handlers[e.Message.Event]
+		c.handlersLock.RUnlock()
+
+		if handler != nil {
+			handler(e.Message)
+		}
+	case envelopeConfigChanged:
+		if e.Config == nil {
+			return
+		}
+
+		if err := c.server.SaveConfig(e.Config, false); err != nil {
+			mlog.Error("Unable to save the configuration that was changed on another server", mlog.String("from", e.From), mlog.Err(err))
+		}
+	case envelopeRequest:
+		if e.Request != nil {
+			go c.respond(e.Request)
+		}
+	}
+}
+
+// ConfigChanged sends the new configuration to the other servers when it can be changed through the System Console.
+// Otherwise, each server reads its own configuration. The configuration is sent as it is, with its credentials, since
+// the other servers need them too.
+func (c *RedisCluster) ConfigChanged(previousConfig *model.Config, newConfig *model.Config, sendToOtherServer bool) *model.AppError {
+	if !sendToOtherServer || atomic.LoadInt32(&c.started) == 0 || *newConfig.ClusterSettings.ReadOnlyConfig {
+		return nil
+	}
+
+	if err := c.publish(&envelope{Kind: envelopeConfigChanged, Config: newConfig}); err != nil {
+		return model.NewAppError("ConfigChanged", "ent.cluster.redis.publish.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (c *RedisCluster) publish(e *envelope) error {
+	e.From = c.id
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := c.backend.Publish(c.key("messages"), b); err != nil {
+		mlog.Error("Unable to publish a cluster message", mlog.String("kind", e.Kind), mlog.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *RedisCluster) send() {
+	defer c.stopped.Done()
+
+	for {
+		select {
+		case msg := <-c.sendQueue:
+			c.publish(&envelope{Kind: envelopeMessage, Message: msg})
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// receive handles the envelopes that are published to the cluster's channel until the server unsubscribes from it.
+func (c *RedisCluster) receive(messages <-chan string) {
+	defer c.stopped.Done()
+
+	for msg := range messages {
+		c.NotifyMsg([]byte(msg))
+	}
+}
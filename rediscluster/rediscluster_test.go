@@ -0,0 +1,414 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rediscluster
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// fakeRedis keeps everything in memory so that several servers can share it as though it were Redis.
+type fakeRedis struct {
+	mutex       sync.Mutex
+	subscribers map[string][]chan string
+	published   int
+	hashes      map[string]map[string]string
+	locks       map[string]string
+	lists       map[string][]string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		subscribers: make(map[string][]chan string),
+		hashes:      make(map[string]map[string]string),
+		locks:       make(map[string]string),
+		lists:       make(map[string][]string),
+	}
+}
+
+func (r *fakeRedis) Ping() error {
+	return nil
+}
+
+func (r *fakeRedis) Subscribe(channel string) (<-chan string, func() error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	payloads := make(chan string, 100)
+	r.subscribers[channel] = append(r.subscribers[channel], payloads)
+
+	return payloads, func() error {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		subscribers := r.subscribers[channel]
+		for i, subscriber := range subscribers {
+			if subscriber == payloads {
+				r.subscribers[channel] = append(subscribers[:i], subscribers[i+1:]...)
+				close(payloads)
+				break
+			}
+		}
+		return nil
+	}
+}
+
+func (r *fakeRedis) Publish(channel string, payload []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.published++
+	for _, subscriber := range r.subscribers[channel] {
+		subscriber <- string(payload)
+	}
+	return nil
+}
+
+func (r *fakeRedis) Published() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.published
+}
+
+func (r *fakeRedis) HSet(key, field string, value []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.hashes[key] == nil {
+		r.hashes[key] = make(map[string]string)
+	}
+	r.hashes[key][field] = string(value)
+	return nil
+}
+
+func (r *fakeRedis) HGetAll(key string) (map[string]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := make(map[string]string)
+	for field, value := range r.hashes[key] {
+		entries[field] = value
+	}
+	return entries, nil
+}
+
+func (r *fakeRedis) HDel(key, field string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.hashes[key], field)
+	return nil
+}
+
+func (r *fakeRedis) AcquireLock(key, holder string, expiry time.Duration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if current, ok := r.locks[key]; ok && current != holder {
+		return false, nil
+	}
+	r.locks[key] = holder
+	return true, nil
+}
+
+func (r *fakeRedis) ReleaseLock(key, holder string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.locks[key] == holder {
+		delete(r.locks, key)
+	}
+	return nil
+}
+
+func (r *fakeRedis) Push(key string, value []byte, expiry time.Duration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lists[key] = append(r.lists[key], string(value))
+	return nil
+}
+
+func (r *fakeRedis) Pop(key string, timeout time.Duration) (string, bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		r.mutex.Lock()
+		if list := r.lists[key]; len(list) > 0 {
+			r.lists[key] = list[1:]
+			r.mutex.Unlock()
+			return list[0], true, nil
+		}
+		r.mutex.Unlock()
+
+		if time.Now().After(deadline) {
+			return "", false, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (r *fakeRedis) Del(key string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.lists, key)
+	return nil
+}
+
+func (r *fakeRedis) Close() error {
+	return nil
+}
+
+type fakeServer struct {
+	config        *model.Config
+	savedConfigs  chan *model.Config
+	leaderChanges chan bool
+}
+
+func newFakeServer(clusterName string) *fakeServer {
+	config := &model.Config{}
+	config.SetDefaults()
+	*config.ClusterSettings.Enable = true
+	*config.ClusterSettings.ClusterName = clusterName
+	*config.ServiceSettings.ClusterLogTimeoutMilliseconds = 1000
+
+	return &fakeServer{
+		config:        config,
+		savedConfigs:  make(chan *model.Config, 10),
+		leaderChanges: make(chan bool, 10),
+	}
+}
+
+func (s *fakeServer) Config() *model.Config {
+	return s.config
+}
+
+func (s *fakeServer) SaveConfig(cfg *model.Config, sendConfigChangeClusterMessage bool) *model.AppError {
+	s.savedConfigs <- cfg
+	return nil
+}
+
+func (s *fakeServer) GetLogsSkipSend(page, perPage int) ([]string, *model.AppError) {
+	return []string{"log line"}, nil
+}
+
+func (s *fakeServer) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	return model.PluginStatuses{}, nil
+}
+
+func (s *fakeServer) TotalWebsocketConnections() int {
+	return 3
+}
+
+func (s *fakeServer) TotalReadDbConnections() int {
+	return 2
+}
+
+func (s *fakeServer) TotalMasterDbConnections() int {
+	return 1
+}
+
+func (s *fakeServer) InvokeClusterLeaderChangedListeners() {
+	s.leaderChanges <- true
+}
+
+func startFakeCluster(t *testing.T, redis *fakeRedis, clusterName string) (*RedisCluster, *fakeServer) {
+	s := newFakeServer(clusterName)
+	c := newRedisCluster(s, func(*model.ClusterSettings) backend {
+		return redis
+	})
+	c.StartInterNodeCommunication()
+
+	return c, s
+}
+
+// waitFor fails the test if the condition isn't met within a second.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); !condition(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestRedisClusterMessages(t *testing.T) {
+	redis := newFakeRedis()
+
+	c1, _ := startFakeCluster(t, redis, "cluster")
+	defer c1.StopInterNodeCommunication()
+	c2, _ := startFakeCluster(t, redis, "cluster")
+	defer c2.StopInterNodeCommunication()
+	other, _ := startFakeCluster(t, redis, "other")
+	defer other.StopInterNodeCommunication()
+
+	handle := func(c *RedisCluster) chan *model.ClusterMessage {
+		received := make(chan *model.ClusterMessage, 10)
+		c.RegisterClusterMessageHandler("test", func(msg *model.ClusterMessage) {
+			received <- msg
+		})
+		return received
+	}
+	received1 := handle(c1)
+	received2 := handle(c2)
+	receivedOther := handle(other)
+
+	c1.SendClusterMessage(&model.ClusterMessage{Event: "test", SendType: model.CLUSTER_SEND_RELIABLE, Data: "data"})
+
+	select {
+	case msg := <-received2:
+		assert.Equal(t, "data", msg.Data)
+	case <-time.After(time.Second):
+		t.Fatal("should send messages to the other servers in the cluster")
+	}
+
+	// The other cluster's envelope would have been delivered alongside the first one
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, received1, "shouldn't handle the messages that the server sent itself")
+	assert.Empty(t, receivedOther, "shouldn't send messages to servers in other clusters")
+
+	t.Run("envelopes that can't be handled are ignored", func(t *testing.T) {
+		notify := func(e *envelope) {
+			b, err := json.Marshal(e)
+			require.Nil(t, err)
+			c2.NotifyMsg(b)
+		}
+
+		c2.NotifyMsg([]byte("{"))
+		notify(&envelope{Kind: envelopeMessage, From: c1.id})
+		notify(&envelope{Kind: envelopeMessage, From: c1.id, Message: &model.ClusterMessage{Event: "unknown"}})
+		notify(&envelope{Kind: "unknown", From: c1.id, Message: &model.ClusterMessage{Event: "test"}})
+		notify(&envelope{Kind: envelopeMessage, From: c2.id, Message: &model.ClusterMessage{Event: "test"}})
+
+		assert.Empty(t, received2)
+
+		notify(&envelope{Kind: envelopeMessage, From: c1.id, Message: &model.ClusterMessage{Event: "test"}})
+		assert.Len(t, received2, 1)
+	})
+}
+
+func TestRedisClusterLeader(t *testing.T) {
+	redis := newFakeRedis()
+
+	c1, s1 := startFakeCluster(t, redis, "cluster")
+	waitFor(t, c1.IsLeader)
+	assert.Len(t, s1.leaderChanges, 1, "should tell the app when this server becomes the leader")
+
+	c2, s2 := startFakeCluster(t, redis, "cluster")
+	defer c2.StopInterNodeCommunication()
+
+	c2.updateLeadership()
+	assert.False(t, c2.IsLeader(), "should only have one leader")
+	assert.Empty(t, s2.leaderChanges)
+
+	c1.updateLeadership()
+	assert.True(t, c1.IsLeader(), "should keep the leadership while it's renewed")
+	assert.Len(t, s1.leaderChanges, 1)
+
+	c1.StopInterNodeCommunication()
+	assert.False(t, c1.IsLeader(), "should step down when stopping")
+
+	c2.updateLeadership()
+	assert.True(t, c2.IsLeader(), "should take over once the leader steps down")
+	assert.Len(t, s2.leaderChanges, 1)
+}
+
+func TestRedisClusterRequests(t *testing.T) {
+	redis := newFakeRedis()
+
+	c1, _ := startFakeCluster(t, redis, "cluster")
+	defer c1.StopInterNodeCommunication()
+
+	t.Run("a server on its own doesn't wait for responses", func(t *testing.T) {
+		waitFor(t, func() bool { return len(c1.GetClusterInfos()) == 1 })
+
+		lines, err := c1.GetLogs(0, 10)
+		require.Nil(t, err)
+		assert.Empty(t, lines)
+	})
+
+	c2, _ := startFakeCluster(t, redis, "cluster")
+	defer c2.StopInterNodeCommunication()
+
+	waitFor(t, func() bool { return len(c1.GetClusterInfos()) == 2 })
+
+	t.Run("logs", func(t *testing.T) {
+		lines, err := c1.GetLogs(0, 10)
+		require.Nil(t, err)
+		assert.Equal(t, append(c2.logsHeader(), "log line"), lines)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		stats, err := c1.GetClusterStats()
+		require.Nil(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, &model.ClusterStats{
+			Id:                        c2.id,
+			TotalWebsocketConnections: 3,
+			TotalReadDbConnections:    2,
+			TotalMasterDbConnections:  1,
+		}, stats[0])
+	})
+
+	t.Run("servers that have left aren't waited for", func(t *testing.T) {
+		c2.StopInterNodeCommunication()
+		waitFor(t, func() bool { return len(c1.GetClusterInfos()) == 1 })
+
+		stats, err := c1.GetClusterStats()
+		require.Nil(t, err)
+		assert.Empty(t, stats)
+	})
+}
+
+func TestRedisClusterConfigChanged(t *testing.T) {
+	redis := newFakeRedis()
+
+	c1, s1 := startFakeCluster(t, redis, "cluster")
+	defer c1.StopInterNodeCommunication()
+	c2, s2 := startFakeCluster(t, redis, "cluster")
+	defer c2.StopInterNodeCommunication()
+
+	newConfig := s1.Config().Clone()
+	*newConfig.ServiceSettings.SiteURL = "http://example.com"
+
+	t.Run("read only config isn't sent", func(t *testing.T) {
+		published := redis.Published()
+
+		require.Nil(t, c1.ConfigChanged(s1.Config(), newConfig, true))
+		assert.Equal(t, published, redis.Published())
+	})
+
+	*newConfig.ClusterSettings.ReadOnlyConfig = false
+
+	t.Run("config isn't sent when it came from another server", func(t *testing.T) {
+		published := redis.Published()
+
+		require.Nil(t, c1.ConfigChanged(s1.Config(), newConfig, false))
+		assert.Equal(t, published, redis.Published())
+	})
+
+	t.Run("config is saved by the other servers", func(t *testing.T) {
+		require.Nil(t, c1.ConfigChanged(s1.Config(), newConfig, true))
+
+		select {
+		case saved := <-s2.savedConfigs:
+			assert.Equal(t, "http://example.com", *saved.ServiceSettings.SiteURL)
+		case <-time.After(time.Second):
+			t.Fatal("should save the config on the other servers")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, s1.savedConfigs, "shouldn't save the config again on the server that changed it")
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rediscluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost-server/mlog"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// The kinds of requests that a server can make of the others in the cluster
+const (
+	requestLogs           = "logs"
+	requestStats          = "stats"
+	requestPluginStatuses = "plugin_statuses"
+)
+
+// A request is published to every server, and each of the others pushes its response onto the list named by
+// ReplyTo, where the requesting server waits for them.
+type request struct {
+	Kind    string `json:"kind"`
+	ReplyTo string `json:"reply_to"`
+	Page    int    `json:"page,omitempty"`
+	PerPage int    `json:"per_page,omitempty"`
+}
+
+func (c *RedisCluster) GetLogs(page, perPage int) ([]string, *model.AppError) {
+	responses, err := c.request(&request{Kind: requestLogs, Page: page, PerPage: perPage})
+	if err != nil {
+		return nil, model.NewAppError("GetLogs", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	lines := []string{}
+	for _, response := range responses {
+		var nodeLines []string
+		if err := json.Unmarshal([]byte(response), &nodeLines); err != nil {
+			return nil, model.NewAppError("GetLogs", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		lines = append(lines, nodeLines...)
+	}
+
+	return lines, nil
+}
+
+func (c *RedisCluster) GetClusterStats() ([]*model.ClusterStats, *model.AppError) {
+	responses, err := c.request(&request{Kind: requestStats})
+	if err != nil {
+		return nil, model.NewAppError("GetClusterStats", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	stats := make([]*model.ClusterStats, 0, len(responses))
+	for _, response := range responses {
+		var nodeStats *model.ClusterStats
+		if err := json.Unmarshal([]byte(response), &nodeStats); err != nil {
+			return nil, model.NewAppError("GetClusterStats", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		stats = append(stats, nodeStats)
+	}
+
+	return stats, nil
+}
+
+func (c *RedisCluster) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	responses, err := c.request(&request{Kind: requestPluginStatuses})
+	if err != nil {
+		return nil, model.NewAppError("GetPluginStatuses", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	statuses := model.PluginStatuses{}
+	for _, response := range responses {
+		var nodeStatuses model.PluginStatuses
+		if err := json.Unmarshal([]byte(response), &nodeStatuses); err != nil {
+			return nil, model.NewAppError("GetPluginStatuses", "ent.cluster.redis.request.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		statuses = append(statuses, nodeStatuses...)
+	}
+
+	return statuses, nil
+}
+
+// request asks the other servers in the cluster for something, returning their responses. Servers that don't respond
+// within ServiceSettings.ClusterLogTimeoutMilliseconds are left out.
+func (c *RedisCluster) request(r *request) ([]string, error) {
+	if atomic.LoadInt32(&c.started) == 0 {
+		return nil, nil
+	}
+
+	nodes, err := c.nodes()
+	if err != nil {
+		return nil, err
+	}
+
+	others := 0
+	for _, n := range nodes {
+		if n.Info.Id != c.id {
+			others++
+		}
+	}
+
+	if others == 0 {
+		return nil, nil
+	}
+
+	r.ReplyTo = c.key("responses:" + model.NewId())
+	defer c.backend.Del(r.ReplyTo)
+
+	if err := c.publish(&envelope{Kind: envelopeRequest, Request: r}); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(time.Duration(*c.server.Config().ServiceSettings.ClusterLogTimeoutMilliseconds) * time.Millisecond)
+
+	responses := []string{}
+	for len(responses) < others {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		response, ok, err := c.backend.Pop(r.ReplyTo, remaining)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+
+		responses = append(responses, response)
+	}
+
+	if len(responses) < others {
+		mlog.Warn("Some servers in the cluster didn't respond in time", mlog.String("request", r.Kind), mlog.Int("expected", others), mlog.Int("received", len(responses)))
+	}
+
+	return responses, nil
+}
+
+// respond handles a request from another server.
+func (c *RedisCluster) respond(r *request) {
+	var response interface{}
+
+	switch r.Kind {
+	case requestLogs:
+		lines, err := c.server.GetLogsSkipSend(r.Page, r.PerPage)
+		if err != nil {
+			mlog.Error("Unable to get the logs for another server in the cluster", mlog.Err(err))
+			return
+		}
+
+		response = append(c.logsHeader(), lines...)
+	case requestStats:
+		response = &model.ClusterStats{
+			Id:                        c.id,
+			TotalWebsocketConnections: c.server.TotalWebsocketConnections(),
+			TotalReadDbConnections:    c.server.TotalReadDbConnections(),
+			TotalMasterDbConnections:  c.server.TotalMasterDbConnections(),
+		}
+	case requestPluginStatuses:
+		statuses, err := c.server.GetPluginStatuses()
+		if err != nil {
+			mlog.Error("Unable to get the plugin statuses for another server in the cluster", mlog.Err(err))
+			return
+		}
+
+		response = statuses
+	default:
+		return
+	}
+
+	b, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	if err := c.backend.Push(r.ReplyTo, b, REDIS_CLUSTER_RESPONSE_KEY_EXPIRY); err != nil {
+		mlog.Error("Unable to respond to another server in the cluster", mlog.String("request", r.Kind), mlog.Err(err))
+	}
+}
+
+// logsHeader separates this server's logs from those of the other servers, the same way that App.GetLogs does.
+func (c *RedisCluster) logsHeader() []string {
+	separator := "-----------------------------------------------------------------------------------------------------------"
+	return []string{separator, separator, c.GetMyClusterInfo().Hostname, separator, separator}
+}
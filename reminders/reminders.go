@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package reminders
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type RemindersJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsRemindersJobInterface(func(a *app.App) tjobs.RemindersJobInterface {
+		return &RemindersJobInterfaceImpl{a}
+	})
+}
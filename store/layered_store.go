@@ -165,6 +165,66 @@ func (s *LayeredStore) Plugin() PluginStore {
 	return s.DatabaseLayer.Plugin()
 }
 
+func (s *LayeredStore) EmailDigest() EmailDigestStore {
+	return s.DatabaseLayer.EmailDigest()
+}
+
+func (s *LayeredStore) WebPushSubscription() WebPushSubscriptionStore {
+	return s.DatabaseLayer.WebPushSubscription()
+}
+
+func (s *LayeredStore) ChannelBridge() ChannelBridgeStore {
+	return s.DatabaseLayer.ChannelBridge()
+}
+
+func (s *LayeredStore) LinkMetadata() LinkMetadataStore {
+	return s.DatabaseLayer.LinkMetadata()
+}
+
+func (s *LayeredStore) PostAcknowledgement() PostAcknowledgementStore {
+	return s.DatabaseLayer.PostAcknowledgement()
+}
+
+func (s *LayeredStore) PostEvent() PostEventStore {
+	return s.DatabaseLayer.PostEvent()
+}
+
+func (s *LayeredStore) ThreadMembership() ThreadMembershipStore {
+	return s.DatabaseLayer.ThreadMembership()
+}
+
+func (s *LayeredStore) UserGroup() UserGroupStore {
+	return s.DatabaseLayer.UserGroup()
+}
+
+func (s *LayeredStore) AutoResponderSchedule() AutoResponderScheduleStore {
+	return s.DatabaseLayer.AutoResponderSchedule()
+}
+
+func (s *LayeredStore) ChannelNotifyDefaults() ChannelNotifyDefaultsStore {
+	return s.DatabaseLayer.ChannelNotifyDefaults()
+}
+
+func (s *LayeredStore) Reminder() ReminderStore {
+	return s.DatabaseLayer.Reminder()
+}
+
+func (s *LayeredStore) Bot() BotStore {
+	return s.DatabaseLayer.Bot()
+}
+
+func (s *LayeredStore) IntegrationDelivery() IntegrationDeliveryStore {
+	return s.DatabaseLayer.IntegrationDelivery()
+}
+
+func (s *LayeredStore) EventSubscription() EventSubscriptionStore {
+	return s.DatabaseLayer.EventSubscription()
+}
+
+func (s *LayeredStore) SidebarCategory() SidebarCategoryStore {
+	return s.DatabaseLayer.SidebarCategory()
+}
+
 func (s *LayeredStore) Role() RoleStore {
 	return s.RoleStore
 }
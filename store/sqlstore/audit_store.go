@@ -5,6 +5,7 @@ package sqlstore
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/store"
@@ -32,6 +33,8 @@ func NewSqlAuditStore(sqlStore SqlStore) store.AuditStore {
 
 func (s SqlAuditStore) CreateIndexesIfNotExists() {
 	s.CreateIndexIfNotExists("idx_audits_user_id", "Audits", "UserId")
+	s.CreateIndexIfNotExists("idx_audits_create_at", "Audits", "CreateAt")
+	s.CreateCompositeIndexIfNotExists("idx_audits_user_id_create_at", "Audits", []string{"UserId", "CreateAt"})
 }
 
 func (s SqlAuditStore) Save(audit *model.Audit) store.StoreChannel {
@@ -70,6 +73,69 @@ func (s SqlAuditStore) Get(user_id string, offset int, limit int) store.StoreCha
 	})
 }
 
+// escapeAuditLikeTerm escapes the wildcards in a term that will be matched using LIKE ... ESCAPE '!'.
+func escapeAuditLikeTerm(term string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(term)
+}
+
+// Search returns the newest audits matching the options, starting after the options' cursor when one is given.
+func (s SqlAuditStore) Search(options *model.AuditSearchOptions, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if limit > model.AUDIT_SEARCH_MAX_PER_PAGE {
+			result.Err = model.NewAppError("SqlAuditStore.Search", "store.sql_audit.get.limit.app_error", nil, "", http.StatusBadRequest)
+			return
+		}
+
+		parameters := map[string]interface{}{"Limit": limit}
+		conditions := []string{}
+
+		if options.UserId != "" {
+			conditions = append(conditions, "UserId = :UserId")
+			parameters["UserId"] = options.UserId
+		}
+
+		if options.Action != "" {
+			conditions = append(conditions, "Action LIKE :Action ESCAPE '!'")
+			parameters["Action"] = escapeAuditLikeTerm(options.Action) + "%"
+		}
+
+		if options.TargetType != "" {
+			conditions = append(conditions, "(Action = :TargetType OR Action LIKE :TargetTypePrefix ESCAPE '!')")
+			parameters["TargetType"] = model.AUDIT_TARGET_TYPE_PREFIX + options.TargetType
+			parameters["TargetTypePrefix"] = escapeAuditLikeTerm(model.AUDIT_TARGET_TYPE_PREFIX+options.TargetType) + "/%"
+		}
+
+		if options.Since > 0 {
+			conditions = append(conditions, "CreateAt >= :Since")
+			parameters["Since"] = options.Since
+		}
+
+		if options.Until > 0 {
+			conditions = append(conditions, "CreateAt <= :Until")
+			parameters["Until"] = options.Until
+		}
+
+		if options.Cursor != nil {
+			conditions = append(conditions, "(CreateAt < :CursorCreateAt OR (CreateAt = :CursorCreateAt AND Id < :CursorId))")
+			parameters["CursorCreateAt"] = options.Cursor.CreateAt
+			parameters["CursorId"] = options.Cursor.Id
+		}
+
+		where := ""
+		if len(conditions) > 0 {
+			where = "WHERE " + strings.Join(conditions, " AND ")
+		}
+
+		audits := model.Audits{}
+		if _, err := s.GetReplica().Select(&audits, "SELECT * FROM Audits "+where+" ORDER BY CreateAt DESC, Id DESC LIMIT :Limit", parameters); err != nil {
+			result.Err = model.NewAppError("SqlAuditStore.Search", "store.sql_audit.search.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = audits
+	})
+}
+
 func (s SqlAuditStore) PermanentDeleteByUser(userId string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		if _, err := s.GetMaster().Exec("DELETE FROM Audits WHERE UserId = :userId",
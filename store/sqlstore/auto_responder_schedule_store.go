@@ -0,0 +1,98 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlAutoResponderScheduleStore struct {
+	SqlStore
+}
+
+func NewSqlAutoResponderScheduleStore(sqlStore SqlStore) store.AutoResponderScheduleStore {
+	s := &SqlAutoResponderScheduleStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.AutoResponderSchedule{}, "AutoResponderSchedules").SetKeys(false, "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlAutoResponderScheduleStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_auto_responder_schedules_start_at", "AutoResponderSchedules", "StartAt")
+	s.CreateIndexIfNotExists("idx_auto_responder_schedules_end_at", "AutoResponderSchedules", "EndAt")
+}
+
+// Save stores a user's schedule, replacing any schedule that they already had.
+func (s SqlAutoResponderScheduleStore) Save(schedule *model.AutoResponderSchedule) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		schedule.PreSave()
+		if result.Err = schedule.IsValid(); result.Err != nil {
+			return
+		}
+
+		count, err := s.GetMaster().Update(schedule)
+		if err == nil && count == 0 {
+			err = s.GetMaster().Insert(schedule)
+
+			// MySQL doesn't count rows that were left unchanged as updated
+			if err != nil && IsUniqueConstraintError(err, []string{"PRIMARY", "autoresponderschedules_pkey"}) {
+				_, err = s.GetMaster().Update(schedule)
+			}
+		}
+
+		if err != nil {
+			result.Err = model.NewAppError("SqlAutoResponderScheduleStore.Save", "store.sql_auto_responder_schedule.save.app_error", nil, "user_id="+schedule.UserId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = schedule
+		}
+	})
+}
+
+func (s SqlAutoResponderScheduleStore) Get(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var schedule model.AutoResponderSchedule
+		if err := s.GetMaster().SelectOne(&schedule, "SELECT * FROM AutoResponderSchedules WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlAutoResponderScheduleStore.Get", "store.sql_auto_responder_schedule.get.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &schedule
+		}
+	})
+}
+
+func (s SqlAutoResponderScheduleStore) Delete(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM AutoResponderSchedules WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlAutoResponderScheduleStore.Delete", "store.sql_auto_responder_schedule.delete.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// GetDue returns the schedules that need to be acted on, which are the ones whose window has started without the
+// auto-responder being turned on yet and the ones whose window has ended.
+func (s SqlAutoResponderScheduleStore) GetDue(now int64, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := `SELECT * FROM AutoResponderSchedules
+			WHERE (StartAt <= :Now AND Active = :NotActive) OR EndAt <= :Now
+			ORDER BY StartAt ASC
+			LIMIT :Limit`
+
+		var schedules []*model.AutoResponderSchedule
+		if _, err := s.GetMaster().Select(&schedules, query, map[string]interface{}{"Now": now, "NotActive": false, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlAutoResponderScheduleStore.GetDue", "store.sql_auto_responder_schedule.get_due.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = schedules
+		}
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// bot is the row that's stored for a model.Bot. The bot's username and display name are stored on its user, so they're
+// joined in from the Users table when bots are loaded.
+type bot struct {
+	UserId      string
+	Description string
+	OwnerId     string
+	CreateAt    int64
+	UpdateAt    int64
+	DeleteAt    int64
+}
+
+func botFromModel(b *model.Bot) *bot {
+	return &bot{
+		UserId:      b.UserId,
+		Description: b.Description,
+		OwnerId:     b.OwnerId,
+		CreateAt:    b.CreateAt,
+		UpdateAt:    b.UpdateAt,
+		DeleteAt:    b.DeleteAt,
+	}
+}
+
+const botColumns = `
+	b.UserId,
+	u.Username,
+	u.FirstName AS DisplayName,
+	b.Description,
+	b.OwnerId,
+	b.CreateAt,
+	b.UpdateAt,
+	b.DeleteAt`
+
+type SqlBotStore struct {
+	SqlStore
+}
+
+func NewSqlBotStore(sqlStore SqlStore) store.BotStore {
+	s := &SqlBotStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(bot{}, "Bots").SetKeys(false, "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("Description").SetMaxSize(model.BOT_DESCRIPTION_MAX_RUNES * 4)
+		table.ColMap("OwnerId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlBotStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_bots_owner_id", "Bots", "OwnerId")
+}
+
+func (s SqlBotStore) Save(b *model.Bot) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		b.PreSave()
+		if result.Err = b.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(botFromModel(b)); err != nil {
+			result.Err = model.NewAppError("SqlBotStore.Save", "store.sql_bot.save.app_error", nil, "user_id="+b.UserId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = b
+		}
+	})
+}
+
+func (s SqlBotStore) Get(userId string, includeDeleted bool) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := "SELECT" + botColumns + " FROM Bots b INNER JOIN Users u ON u.Id = b.UserId WHERE b.UserId = :UserId"
+		if !includeDeleted {
+			query += " AND b.DeleteAt = 0"
+		}
+
+		var b model.Bot
+		if err := s.GetReplica().SelectOne(&b, query, map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlBotStore.Get", "store.sql_bot.get.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &b
+		}
+	})
+}
+
+// GetAll returns a page of bots ordered by when they were created. Orphaned bots are those whose owners have been
+// deactivated or deleted.
+func (s SqlBotStore) GetAll(options *model.BotGetOptions) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var conditions []string
+		params := map[string]interface{}{
+			"Limit":  options.PerPage,
+			"Offset": options.Page * options.PerPage,
+		}
+
+		if options.OwnerId != "" {
+			conditions = append(conditions, "b.OwnerId = :OwnerId")
+			params["OwnerId"] = options.OwnerId
+		}
+
+		if !options.IncludeDeleted {
+			conditions = append(conditions, "b.DeleteAt = 0")
+		}
+
+		if options.OnlyOrphaned {
+			conditions = append(conditions, "(o.Id IS NULL OR o.DeleteAt != 0)")
+		}
+
+		where := ""
+		if len(conditions) > 0 {
+			where = "WHERE " + strings.Join(conditions, " AND ")
+		}
+
+		query := fmt.Sprintf(`
+			SELECT%v
+			FROM
+				Bots b
+				INNER JOIN Users u ON u.Id = b.UserId
+				LEFT JOIN Users o ON o.Id = b.OwnerId
+			%v
+			ORDER BY b.CreateAt ASC, u.Username ASC
+			LIMIT :Limit OFFSET :Offset`, botColumns, where)
+
+		var bots []*model.Bot
+		if _, err := s.GetReplica().Select(&bots, query, params); err != nil {
+			result.Err = model.NewAppError("SqlBotStore.GetAll", "store.sql_bot.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = bots
+		}
+	})
+}
+
+// Update saves the bot's description, owner and whether it's active. Its username and display name are saved by
+// updating its user.
+func (s SqlBotStore) Update(b *model.Bot) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		b.PreUpdate()
+		if result.Err = b.IsValid(); result.Err != nil {
+			return
+		}
+
+		if count, err := s.GetMaster().Update(botFromModel(b)); err != nil {
+			result.Err = model.NewAppError("SqlBotStore.Update", "store.sql_bot.update.app_error", nil, "user_id="+b.UserId+", "+err.Error(), http.StatusInternalServerError)
+		} else if count != 1 {
+			result.Err = model.NewAppError("SqlBotStore.Update", "store.sql_bot.update.app_error", nil, "user_id="+b.UserId, http.StatusNotFound)
+		} else {
+			result.Data = b
+		}
+	})
+}
+
+func (s SqlBotStore) PermanentDelete(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM Bots WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlBotStore.PermanentDelete", "store.sql_bot.permanent_delete.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = userId
+		}
+	})
+}
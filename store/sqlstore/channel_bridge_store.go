@@ -0,0 +1,141 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlChannelBridgeStore struct {
+	SqlStore
+}
+
+func NewSqlChannelBridgeStore(sqlStore SqlStore) store.ChannelBridgeStore {
+	s := &SqlChannelBridgeStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.ChannelBridge{}, "ChannelBridges").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("Service").SetMaxSize(model.REMOTE_ID_SERVICE_MAX_LENGTH)
+		table.ColMap("RemoteChannelId").SetMaxSize(model.CHANNEL_BRIDGE_REMOTE_CHANNEL_ID_MAX_LENGTH)
+		table.ColMap("OutgoingURL").SetMaxSize(model.CHANNEL_BRIDGE_OUTGOING_URL_MAX_LENGTH)
+		table.ColMap("Token").SetMaxSize(26)
+		table.ColMap("ConflictPolicy").SetMaxSize(32)
+		table.ColMap("CreatorId").SetMaxSize(26)
+		table.SetUniqueTogether("Service", "RemoteChannelId")
+	}
+
+	return s
+}
+
+func (s SqlChannelBridgeStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_channel_bridges_channel_id", "ChannelBridges", "ChannelId")
+}
+
+func (s SqlChannelBridgeStore) Save(bridge *model.ChannelBridge) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(bridge.Id) > 0 {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.Save", "store.sql_channel_bridge.save.existing.app_error", nil, "id="+bridge.Id, http.StatusBadRequest)
+			return
+		}
+
+		bridge.PreSave()
+		if result.Err = bridge.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(bridge); err != nil {
+			if IsUniqueConstraintError(err, []string{"Service", "channelbridges_service_remotechannelid_key"}) {
+				result.Err = model.NewAppError("SqlChannelBridgeStore.Save", "store.sql_channel_bridge.save.remote_channel_exists.app_error", nil, "id="+bridge.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlChannelBridgeStore.Save", "store.sql_channel_bridge.save.app_error", nil, "id="+bridge.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
+		} else {
+			result.Data = bridge
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var bridge model.ChannelBridge
+
+		if err := s.GetReplica().SelectOne(&bridge, "SELECT * FROM ChannelBridges WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.Get", "store.sql_channel_bridge.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &bridge
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) GetAll() store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var bridges []*model.ChannelBridge
+
+		if _, err := s.GetReplica().Select(&bridges, "SELECT * FROM ChannelBridges ORDER BY CreateAt ASC"); err != nil {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.GetAll", "store.sql_channel_bridge.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = bridges
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) GetForChannel(channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var bridges []*model.ChannelBridge
+
+		if _, err := s.GetReplica().Select(&bridges, "SELECT * FROM ChannelBridges WHERE ChannelId = :ChannelId ORDER BY CreateAt ASC", map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.GetForChannel", "store.sql_channel_bridge.get_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = bridges
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) Update(bridge *model.ChannelBridge) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		bridge.PreUpdate()
+		if result.Err = bridge.IsValid(); result.Err != nil {
+			return
+		}
+
+		if _, err := s.GetMaster().Update(bridge); err != nil {
+			if IsUniqueConstraintError(err, []string{"Service", "channelbridges_service_remotechannelid_key"}) {
+				result.Err = model.NewAppError("SqlChannelBridgeStore.Update", "store.sql_channel_bridge.save.remote_channel_exists.app_error", nil, "id="+bridge.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlChannelBridgeStore.Update", "store.sql_channel_bridge.update.app_error", nil, "id="+bridge.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
+		} else {
+			result.Data = bridge
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) Delete(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM ChannelBridges WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.Delete", "store.sql_channel_bridge.delete.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = id
+		}
+	})
+}
+
+func (s SqlChannelBridgeStore) DeleteForChannel(channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM ChannelBridges WHERE ChannelId = :ChannelId", map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewAppError("SqlChannelBridgeStore.DeleteForChannel", "store.sql_channel_bridge.delete_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = channelId
+		}
+	})
+}
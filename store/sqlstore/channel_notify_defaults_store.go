@@ -0,0 +1,80 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlChannelNotifyDefaultsStore struct {
+	SqlStore
+}
+
+func NewSqlChannelNotifyDefaultsStore(sqlStore SqlStore) store.ChannelNotifyDefaultsStore {
+	s := &SqlChannelNotifyDefaultsStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.ChannelNotifyDefaults{}, "ChannelNotifyDefaults").SetKeys(false, "ChannelId")
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("NotifyProps").SetMaxSize(2000)
+		table.ColMap("UpdatedBy").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlChannelNotifyDefaultsStore) CreateIndexesIfNotExists() {
+}
+
+// Save stores a channel's notification defaults, replacing any defaults that it already had.
+func (s SqlChannelNotifyDefaultsStore) Save(defaults *model.ChannelNotifyDefaults) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		defaults.PreSave()
+		if result.Err = defaults.IsValid(); result.Err != nil {
+			return
+		}
+
+		count, err := s.GetMaster().Update(defaults)
+		if err == nil && count == 0 {
+			err = s.GetMaster().Insert(defaults)
+
+			// MySQL doesn't count rows that were left unchanged as updated
+			if err != nil && IsUniqueConstraintError(err, []string{"PRIMARY", "channelnotifydefaults_pkey"}) {
+				_, err = s.GetMaster().Update(defaults)
+			}
+		}
+
+		if err != nil {
+			result.Err = model.NewAppError("SqlChannelNotifyDefaultsStore.Save", "store.sql_channel_notify_defaults.save.app_error", nil, "channel_id="+defaults.ChannelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = defaults
+		}
+	})
+}
+
+func (s SqlChannelNotifyDefaultsStore) Get(channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var defaults model.ChannelNotifyDefaults
+		if err := s.GetReplica().SelectOne(&defaults, "SELECT * FROM ChannelNotifyDefaults WHERE ChannelId = :ChannelId", map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewAppError("SqlChannelNotifyDefaultsStore.Get", "store.sql_channel_notify_defaults.get.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &defaults
+		}
+	})
+}
+
+func (s SqlChannelNotifyDefaultsStore) Delete(channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM ChannelNotifyDefaults WHERE ChannelId = :ChannelId", map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewAppError("SqlChannelNotifyDefaultsStore.Delete", "store.sql_channel_notify_defaults.delete.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
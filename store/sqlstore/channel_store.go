@@ -232,9 +232,9 @@ func (db allChannelMembers) ToMapStringString() map[string]string {
 	return result
 }
 
-var channelMemberCountsCache = utils.NewLru(CHANNEL_MEMBERS_COUNTS_CACHE_SIZE)
-var allChannelMembersForUserCache = utils.NewLru(ALL_CHANNEL_MEMBERS_FOR_USER_CACHE_SIZE)
-var allChannelMembersNotifyPropsForChannelCache = utils.NewLru(ALL_CHANNEL_MEMBERS_NOTIFY_PROPS_FOR_CHANNEL_CACHE_SIZE)
+var channelMemberCountsCache utils.ObjectCache = utils.NewLru(CHANNEL_MEMBERS_COUNTS_CACHE_SIZE)
+var allChannelMembersForUserCache utils.ObjectCache = utils.NewLru(ALL_CHANNEL_MEMBERS_FOR_USER_CACHE_SIZE)
+var allChannelMembersNotifyPropsForChannelCache utils.ObjectCache = utils.NewLru(ALL_CHANNEL_MEMBERS_NOTIFY_PROPS_FOR_CHANNEL_CACHE_SIZE)
 var channelCache = utils.NewLru(model.CHANNEL_CACHE_SIZE)
 var channelByNameCache = utils.NewLru(model.CHANNEL_CACHE_SIZE)
 
@@ -272,6 +272,7 @@ func NewSqlChannelStore(sqlStore SqlStore, metrics einterfaces.MetricsInterface)
 		table.ColMap("Purpose").SetMaxSize(250)
 		table.ColMap("CreatorId").SetMaxSize(26)
 		table.ColMap("SchemeId").SetMaxSize(26)
+		table.ColMap("RemoteId").SetMaxSize(model.REMOTE_ID_MAX_LENGTH)
 
 		tablem := db.AddTableWithName(channelMember{}, "ChannelMembers").SetKeys(false, "ChannelId", "UserId")
 		tablem.ColMap("ChannelId").SetMaxSize(26)
@@ -289,6 +290,7 @@ func (s SqlChannelStore) CreateIndexesIfNotExists() {
 	s.CreateIndexIfNotExists("idx_channels_update_at", "Channels", "UpdateAt")
 	s.CreateIndexIfNotExists("idx_channels_create_at", "Channels", "CreateAt")
 	s.CreateIndexIfNotExists("idx_channels_delete_at", "Channels", "DeleteAt")
+	s.CreateUniqueIndexIfNotExists("idx_channels_remote_id_unique", "Channels", "RemoteId")
 
 	if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
 		s.CreateIndexIfNotExists("idx_channels_name_lower", "Channels", "lower(Name)")
@@ -449,6 +451,8 @@ func (s SqlChannelStore) saveChannelT(transaction *gorp.Transaction, channel *mo
 				result.Err = model.NewAppError("SqlChannelStore.Save", store.CHANNEL_EXISTS_ERROR, nil, "id="+channel.Id+", "+err.Error(), http.StatusBadRequest)
 				result.Data = &dupChannel
 			}
+		} else if IsUniqueConstraintError(err, []string{"RemoteId", "idx_channels_remote_id_unique"}) {
+			result.Err = model.NewAppError("SqlChannelStore.Save", "store.sql_channel.save_channel.remote_id_exists.app_error", nil, "id="+channel.Id+", "+err.Error(), http.StatusBadRequest)
 		} else {
 			result.Err = model.NewAppError("SqlChannelStore.Save", "store.sql_channel.save_channel.save.app_error", nil, "id="+channel.Id+", "+err.Error(), http.StatusInternalServerError)
 		}
@@ -479,6 +483,10 @@ func (s SqlChannelStore) Update(channel *model.Channel) store.StoreChannel {
 				result.Err = model.NewAppError("SqlChannelStore.Update", "store.sql_channel.update.exists.app_error", nil, "id="+channel.Id+", "+err.Error(), http.StatusBadRequest)
 				return
 			}
+			if IsUniqueConstraintError(err, []string{"RemoteId", "idx_channels_remote_id_unique"}) {
+				result.Err = model.NewAppError("SqlChannelStore.Update", "store.sql_channel.update.remote_id_exists.app_error", nil, "id="+channel.Id+", "+err.Error(), http.StatusBadRequest)
+				return
+			}
 			result.Err = model.NewAppError("SqlChannelStore.Update", "store.sql_channel.update.updating.app_error", nil, "id="+channel.Id+", "+err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -875,6 +883,23 @@ func (s SqlChannelStore) GetByNames(teamId string, names []string, allowFromCach
 	})
 }
 
+func (s SqlChannelStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		channel := model.Channel{}
+
+		if err := s.GetReplica().SelectOne(&channel, "SELECT * FROM Channels WHERE RemoteId = :RemoteId", map[string]interface{}{"RemoteId": remoteId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlChannelStore.GetByRemoteId", store.MISSING_CHANNEL_ERROR, nil, "remote_id="+remoteId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewAppError("SqlChannelStore.GetByRemoteId", "store.sql_channel.get_by_remote_id.app_error", nil, "remote_id="+remoteId+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result.Data = &channel
+	})
+}
+
 func (s SqlChannelStore) GetByNameIncludeDeleted(teamId string, name string, allowFromCache bool) store.StoreChannel {
 	return s.getByName(teamId, name, true, allowFromCache)
 }
@@ -1436,6 +1461,50 @@ func (s SqlChannelStore) UpdateLastViewedAt(channelIds []string, userId string)
 	})
 }
 
+// GetReadReceipts returns how far each member of the channel has read it, leaving out the members who haven't read
+// it up to the given time.
+func (s SqlChannelStore) GetReadReceipts(channelId string, since int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		receipts := []*model.ReadReceipt{}
+
+		if _, err := s.GetReplica().Select(&receipts, `
+			SELECT
+				ChannelId, UserId, LastViewedAt
+			FROM
+				ChannelMembers
+			WHERE
+				ChannelId = :ChannelId
+				AND LastViewedAt >= :Since
+			ORDER BY
+				LastViewedAt DESC, UserId`, map[string]interface{}{"ChannelId": channelId, "Since": since}); err != nil {
+			result.Err = model.NewAppError("SqlChannelStore.GetReadReceipts", "store.sql_channel.get_read_receipts.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = receipts
+	})
+}
+
+// GetSnoozedMembers returns the channel members that have snoozed their channel, whether or not the time that it was
+// snoozed until has passed.
+func (s SqlChannelStore) GetSnoozedMembers(offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var dbMembers channelMemberWithSchemeRolesList
+		if _, err := s.GetReplica().Select(&dbMembers, CHANNEL_MEMBERS_WITH_SCHEME_SELECT_QUERY+`
+			WHERE
+				ChannelMembers.NotifyProps LIKE :Pattern
+			ORDER BY
+				ChannelMembers.ChannelId, ChannelMembers.UserId
+			LIMIT :Limit
+			OFFSET :Offset`, map[string]interface{}{"Pattern": "%\"" + model.MUTED_UNTIL_NOTIFY_PROP + "\":%", "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlChannelStore.GetSnoozedMembers", "store.sql_channel.get_snoozed_members.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = dbMembers.ToModel()
+	})
+}
+
 func (s SqlChannelStore) IncrementMentionCount(channelId string, userId string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		_, err := s.GetMaster().Exec(
@@ -1497,7 +1566,7 @@ func (s SqlChannelStore) AnalyticsTypeCount(teamId string, channelType string) s
 			query += " AND TeamId = :TeamId"
 		}
 
-		v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId, "ChannelType": channelType})
+		v, err := s.GetReplicaFor("Channel.AnalyticsTypeCount").SelectInt(query, map[string]interface{}{"TeamId": teamId, "ChannelType": channelType})
 		if err != nil {
 			result.Err = model.NewAppError("SqlChannelStore.AnalyticsTypeCount", "store.sql_channel.analytics_type_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 			return
@@ -1515,7 +1584,7 @@ func (s SqlChannelStore) AnalyticsDeletedTypeCount(teamId string, channelType st
 			query += " AND TeamId = :TeamId"
 		}
 
-		v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId, "ChannelType": channelType})
+		v, err := s.GetReplicaFor("Channel.AnalyticsDeletedTypeCount").SelectInt(query, map[string]interface{}{"TeamId": teamId, "ChannelType": channelType})
 		if err != nil {
 			result.Err = model.NewAppError("SqlChannelStore.AnalyticsDeletedTypeCount", "store.sql_channel.analytics_deleted_type_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 			return
@@ -1561,7 +1630,7 @@ func (s SqlChannelStore) AutocompleteInTeam(teamId string, term string, includeD
 		var channels model.ChannelList
 
 		if likeClause, likeTerm := s.buildLIKEClause(term); likeClause == "" {
-			if _, err := s.GetReplica().Select(&channels, fmt.Sprintf(queryFormat, ""), map[string]interface{}{"TeamId": teamId}); err != nil {
+			if _, err := s.GetReplicaFor("Channel.AutocompleteInTeam").Select(&channels, fmt.Sprintf(queryFormat, ""), map[string]interface{}{"TeamId": teamId}); err != nil {
 				result.Err = model.NewAppError("SqlChannelStore.AutocompleteInTeam", "store.sql_channel.search.app_error", nil, "term="+term+", "+", "+err.Error(), http.StatusInternalServerError)
 			}
 		} else {
@@ -1572,7 +1641,7 @@ func (s SqlChannelStore) AutocompleteInTeam(teamId string, term string, includeD
 			fulltextQuery := fmt.Sprintf(queryFormat, "AND "+fulltextClause)
 			query := fmt.Sprintf("(%v) UNION (%v) LIMIT 50", likeQuery, fulltextQuery)
 
-			if _, err := s.GetReplica().Select(&channels, query, map[string]interface{}{"TeamId": teamId, "LikeTerm": likeTerm, "FulltextTerm": fulltextTerm}); err != nil {
+			if _, err := s.GetReplicaFor("Channel.AutocompleteInTeam").Select(&channels, query, map[string]interface{}{"TeamId": teamId, "LikeTerm": likeTerm, "FulltextTerm": fulltextTerm}); err != nil {
 				result.Err = model.NewAppError("SqlChannelStore.AutocompleteInTeam", "store.sql_channel.search.app_error", nil, "term="+term+", "+", "+err.Error(), http.StatusInternalServerError)
 			}
 		}
@@ -1761,6 +1830,32 @@ func (s SqlChannelStore) GetMembersByIds(channelId string, userIds []string) sto
 	})
 }
 
+func (s SqlChannelStore) GetMembersForUserByChannelIds(userId string, channelIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var dbMembers channelMemberWithSchemeRolesList
+		props := make(map[string]interface{})
+		idQuery := ""
+
+		for index, channelId := range channelIds {
+			if len(idQuery) > 0 {
+				idQuery += ", "
+			}
+
+			props["channelId"+strconv.Itoa(index)] = channelId
+			idQuery += ":channelId" + strconv.Itoa(index)
+		}
+
+		props["UserId"] = userId
+
+		if _, err := s.GetReplica().Select(&dbMembers, CHANNEL_MEMBERS_WITH_SCHEME_SELECT_QUERY+"WHERE ChannelMembers.UserId = :UserId AND ChannelMembers.ChannelId IN ("+idQuery+")", props); err != nil {
+			result.Err = model.NewAppError("SqlChannelStore.GetMembersForUserByChannelIds", "store.sql_channel.get_members_for_user_by_channel_ids.app_error", nil, "userId="+userId+" "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = dbMembers.ToModel()
+	})
+}
+
 func (s SqlChannelStore) GetChannelsByScheme(schemeId string, offset int, limit int) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		var channels model.ChannelList
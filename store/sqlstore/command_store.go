@@ -32,6 +32,7 @@ func NewSqlCommandStore(sqlStore SqlStore) store.CommandStore {
 		tableo.ColMap("AutoCompleteHint").SetMaxSize(1024)
 		tableo.ColMap("DisplayName").SetMaxSize(64)
 		tableo.ColMap("Description").SetMaxSize(128)
+		tableo.ColMap("AutocompleteData").SetMaxSize(model.COMMAND_AUTOCOMPLETE_DATA_MAX_SIZE)
 	}
 
 	return s
@@ -164,7 +165,7 @@ func (s SqlCommandStore) AnalyticsCommandCount(teamId string) store.StoreChannel
 			query += " AND TeamId = :TeamId"
 		}
 
-		if c, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
+		if c, err := s.GetReplicaFor("Command.AnalyticsCommandCount").SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
 			result.Err = model.NewAppError("SqlCommandStore.AnalyticsCommandCount", "store.sql_command.analytics_command_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = c
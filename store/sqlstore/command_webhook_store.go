@@ -27,6 +27,8 @@ func NewSqlCommandWebhookStore(sqlStore SqlStore) store.CommandWebhookStore {
 		tablec.ColMap("ChannelId").SetMaxSize(26)
 		tablec.ColMap("RootId").SetMaxSize(26)
 		tablec.ColMap("ParentId").SetMaxSize(26)
+		tablec.ColMap("EphemeralPostId").SetMaxSize(26)
+		tablec.ColMap("EphemeralMessage").SetMaxSize(model.POST_MESSAGE_MAX_BYTES_V2)
 	}
 
 	return s
@@ -84,6 +86,16 @@ func (s SqlCommandWebhookStore) TryUse(id string, limit int) store.StoreChannel
 	})
 }
 
+func (s SqlCommandWebhookStore) UpdateEphemeralPost(id string, postId string, message string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("UPDATE CommandWebhooks SET EphemeralPostId = :EphemeralPostId, EphemeralMessage = :EphemeralMessage WHERE Id = :Id", map[string]interface{}{"Id": id, "EphemeralPostId": postId, "EphemeralMessage": message}); err != nil {
+			result.Err = model.NewAppError("SqlCommandWebhookStore.UpdateEphemeralPost", "store.sql_command_webhooks.update_ephemeral_post.app_error", nil, "id="+id+", err="+err.Error(), http.StatusInternalServerError)
+		}
+
+		result.Data = id
+	})
+}
+
 func (s SqlCommandWebhookStore) Cleanup() {
 	mlog.Debug("Cleaning up command webhook store.")
 	exptime := model.GetMillis() - model.COMMAND_WEBHOOK_LIFETIME
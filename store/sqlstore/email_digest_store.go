@@ -0,0 +1,89 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlEmailDigestStore struct {
+	SqlStore
+}
+
+func NewSqlEmailDigestStore(sqlStore SqlStore) store.EmailDigestStore {
+	s := &SqlEmailDigestStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.EmailDigestEntry{}, "EmailDigestEntries").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("PostId").SetMaxSize(26)
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("TeamId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlEmailDigestStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_email_digest_entries_user_id", "EmailDigestEntries", "UserId")
+	s.CreateIndexIfNotExists("idx_email_digest_entries_create_at", "EmailDigestEntries", "CreateAt")
+}
+
+func (s SqlEmailDigestStore) Save(entry *model.EmailDigestEntry) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(entry.Id) > 0 {
+			result.Err = model.NewAppError("SqlEmailDigestStore.Save", "store.sql_email_digest.save.existing.app_error", nil, "id="+entry.Id, http.StatusBadRequest)
+			return
+		}
+
+		entry.PreSave()
+		if result.Err = entry.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(entry); err != nil {
+			result.Err = model.NewAppError("SqlEmailDigestStore.Save", "store.sql_email_digest.save.app_error", nil, "id="+entry.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = entry
+		}
+	})
+}
+
+func (s SqlEmailDigestStore) GetUserIdsWithEntries() store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var userIds []string
+
+		if _, err := s.GetReplica().Select(&userIds, "SELECT DISTINCT UserId FROM EmailDigestEntries"); err != nil {
+			result.Err = model.NewAppError("SqlEmailDigestStore.GetUserIdsWithEntries", "store.sql_email_digest.get_user_ids.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = userIds
+		}
+	})
+}
+
+func (s SqlEmailDigestStore) GetForUser(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var entries []*model.EmailDigestEntry
+
+		if _, err := s.GetReplica().Select(&entries, "SELECT * FROM EmailDigestEntries WHERE UserId = :UserId ORDER BY CreateAt ASC", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlEmailDigestStore.GetForUser", "store.sql_email_digest.get_for_user.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = entries
+		}
+	})
+}
+
+func (s SqlEmailDigestStore) DeleteForUser(userId string, before int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM EmailDigestEntries WHERE UserId = :UserId AND CreateAt <= :Before", map[string]interface{}{"UserId": userId, "Before": before}); err != nil {
+			result.Err = model.NewAppError("SqlEmailDigestStore.DeleteForUser", "store.sql_email_digest.delete_for_user.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = userId
+		}
+	})
+}
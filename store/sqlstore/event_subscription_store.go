@@ -0,0 +1,162 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlEventSubscriptionStore struct {
+	SqlStore
+}
+
+func NewSqlEventSubscriptionStore(sqlStore SqlStore) store.EventSubscriptionStore {
+	s := &SqlEventSubscriptionStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.EventSubscription{}, "EventSubscriptions").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("CreatorId").SetMaxSize(26)
+		table.ColMap("TeamId").SetMaxSize(26)
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("EventType").SetMaxSize(32)
+		table.ColMap("CallbackURL").SetMaxSize(model.EVENT_SUBSCRIPTION_CALLBACK_URL_MAX_LENGTH)
+		table.ColMap("Description").SetMaxSize(model.EVENT_SUBSCRIPTION_DESCRIPTION_MAX_LENGTH)
+		table.ColMap("SigningSecret").SetMaxSize(model.OUTGOING_HOOK_SIGNING_SECRET_MAX_LENGTH)
+	}
+
+	return s
+}
+
+func (s SqlEventSubscriptionStore) CreateIndexesIfNotExists() {
+	s.CreateCompositeIndexIfNotExists("idx_event_subscriptions_team_id_event_type", "EventSubscriptions", []string{"TeamId", "EventType"})
+	s.CreateIndexIfNotExists("idx_event_subscriptions_channel_id", "EventSubscriptions", "ChannelId")
+}
+
+func (s SqlEventSubscriptionStore) Save(subscription *model.EventSubscription) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(subscription.Id) > 0 {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.Save", "store.sql_event_subscription.save.existing.app_error", nil, "id="+subscription.Id, http.StatusBadRequest)
+			return
+		}
+
+		subscription.PreSave()
+		if result.Err = subscription.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(subscription); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.Save", "store.sql_event_subscription.save.app_error", nil, "id="+subscription.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = subscription
+		}
+	})
+}
+
+func (s SqlEventSubscriptionStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var subscription model.EventSubscription
+
+		if err := s.GetReplica().SelectOne(&subscription, "SELECT * FROM EventSubscriptions WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": id}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlEventSubscriptionStore.Get", "store.sql_event_subscription.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+			} else {
+				result.Err = model.NewAppError("SqlEventSubscriptionStore.Get", "store.sql_event_subscription.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result.Data = &subscription
+	})
+}
+
+func (s SqlEventSubscriptionStore) GetByTeam(teamId string, offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		subscriptions := []*model.EventSubscription{}
+
+		if _, err := s.GetReplica().Select(&subscriptions, "SELECT * FROM EventSubscriptions WHERE TeamId = :TeamId AND DeleteAt = 0 ORDER BY CreateAt, Id LIMIT :Limit OFFSET :Offset", map[string]interface{}{"TeamId": teamId, "Offset": offset, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.GetByTeam", "store.sql_event_subscription.get_by_team.app_error", nil, "team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = subscriptions
+	})
+}
+
+// GetActiveForEvent returns the active subscriptions that an event in a team should be sent to. The channel is only
+// used to match subscriptions to events in channels, and should be empty for other events.
+func (s SqlEventSubscriptionStore) GetActiveForEvent(eventType string, teamId string, channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		subscriptions := []*model.EventSubscription{}
+
+		query := `SELECT * FROM EventSubscriptions
+			WHERE TeamId = :TeamId AND EventType = :EventType AND ChannelId = :ChannelId AND Active = :Active AND DeleteAt = 0`
+
+		if _, err := s.GetReplica().Select(&subscriptions, query, map[string]interface{}{"TeamId": teamId, "EventType": eventType, "ChannelId": channelId, "Active": true}); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.GetActiveForEvent", "store.sql_event_subscription.get_active_for_event.app_error", nil, "event_type="+eventType+", team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = subscriptions
+	})
+}
+
+func (s SqlEventSubscriptionStore) Update(subscription *model.EventSubscription) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		subscription.PreUpdate()
+		if result.Err = subscription.IsValid(); result.Err != nil {
+			return
+		}
+
+		if _, err := s.GetMaster().Update(subscription); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.Update", "store.sql_event_subscription.update.app_error", nil, "id="+subscription.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = subscription
+		}
+	})
+}
+
+func (s SqlEventSubscriptionStore) Delete(id string, time int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("UPDATE EventSubscriptions SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt WHERE Id = :Id", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.Delete", "store.sql_event_subscription.delete.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// RecordDeliveryResult counts a failed delivery to a subscription, or resets the count after a successful one. The
+// subscription is deactivated when too many deliveries have failed in a row, and the result is true if this delivery
+// deactivated it. The count is updated in the database so that deliveries that fail at the same time are all counted.
+func (s SqlEventSubscriptionStore) RecordDeliveryResult(id string, succeeded bool) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		result.Data = false
+
+		if succeeded {
+			if _, err := s.GetMaster().Exec("UPDATE EventSubscriptions SET Failures = 0 WHERE Id = :Id AND Failures > 0", map[string]interface{}{"Id": id}); err != nil {
+				result.Err = model.NewAppError("SqlEventSubscriptionStore.RecordDeliveryResult", "store.sql_event_subscription.record_delivery_result.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if _, err := s.GetMaster().Exec("UPDATE EventSubscriptions SET Failures = Failures + 1 WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.RecordDeliveryResult", "store.sql_event_subscription.record_delivery_result.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sqlResult, err := s.GetMaster().Exec(`UPDATE EventSubscriptions SET Active = :Inactive, UpdateAt = :UpdateAt
+			WHERE Id = :Id AND Active = :Active AND Failures >= :MaxFailures`,
+			map[string]interface{}{"Id": id, "Active": true, "Inactive": false, "UpdateAt": model.GetMillis(), "MaxFailures": model.EVENT_SUBSCRIPTION_MAX_FAILURES})
+		if err != nil {
+			result.Err = model.NewAppError("SqlEventSubscriptionStore.RecordDeliveryResult", "store.sql_event_subscription.record_delivery_result.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, _ := sqlResult.RowsAffected()
+		result.Data = rowsAffected == 1
+	})
+}
@@ -6,6 +6,7 @@ package sqlstore
 import (
 	"database/sql"
 	"net/http"
+	"strconv"
 
 	"github.com/mattermost/mattermost-server/einterfaces"
 	"github.com/mattermost/mattermost-server/model"
@@ -177,6 +178,64 @@ func (fs SqlFileInfoStore) GetForPost(postId string, readFromMaster bool, allowF
 	})
 }
 
+func (fs SqlFileInfoStore) GetByIds(fileIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		infos := []*model.FileInfo{}
+		if len(fileIds) == 0 {
+			result.Data = infos
+			return
+		}
+
+		props := make(map[string]interface{})
+		idQuery := ""
+
+		for index, fileId := range fileIds {
+			if len(idQuery) > 0 {
+				idQuery += ", "
+			}
+
+			props["fileId"+strconv.Itoa(index)] = fileId
+			idQuery += ":fileId" + strconv.Itoa(index)
+		}
+
+		if _, err := fs.GetReplica().Select(&infos, "SELECT * FROM FileInfo WHERE Id IN ("+idQuery+") AND DeleteAt = 0 ORDER BY CreateAt", props); err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.GetByIds", "store.sql_file_info.get_by_ids.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = infos
+		}
+	})
+}
+
+// GetForPosts returns the file infos for all of the given posts in a single query, which saves loading them one post
+// at a time when displaying a list of posts.
+func (fs SqlFileInfoStore) GetForPosts(postIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		infos := []*model.FileInfo{}
+		if len(postIds) == 0 {
+			result.Data = infos
+			return
+		}
+
+		props := make(map[string]interface{})
+		idQuery := ""
+
+		for index, postId := range postIds {
+			if len(idQuery) > 0 {
+				idQuery += ", "
+			}
+
+			props["postId"+strconv.Itoa(index)] = postId
+			idQuery += ":postId" + strconv.Itoa(index)
+		}
+
+		if _, err := fs.GetReplica().Select(&infos, "SELECT * FROM FileInfo WHERE PostId IN ("+idQuery+") AND DeleteAt = 0 ORDER BY CreateAt", props); err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.GetForPosts", "store.sql_file_info.get_for_posts.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = infos
+		}
+	})
+}
+
 func (fs SqlFileInfoStore) GetForUser(userId string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		var infos []*model.FileInfo
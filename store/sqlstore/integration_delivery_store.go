@@ -0,0 +1,100 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlIntegrationDeliveryStore struct {
+	SqlStore
+}
+
+func NewSqlIntegrationDeliveryStore(sqlStore SqlStore) store.IntegrationDeliveryStore {
+	s := &SqlIntegrationDeliveryStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.IntegrationDelivery{}, "IntegrationDeliveries").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("IntegrationType").SetMaxSize(32)
+		table.ColMap("IntegrationId").SetMaxSize(26)
+		table.ColMap("URL").SetMaxSize(model.INTEGRATION_DELIVERY_URL_MAX_LENGTH)
+		table.ColMap("Request").SetMaxSize(model.INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH)
+		table.ColMap("Error").SetMaxSize(model.INTEGRATION_DELIVERY_ERROR_MAX_LENGTH)
+	}
+
+	return s
+}
+
+func (s SqlIntegrationDeliveryStore) CreateIndexesIfNotExists() {
+	s.CreateCompositeIndexIfNotExists("idx_integration_deliveries_integration_id_create_at", "IntegrationDeliveries", []string{"IntegrationId", "CreateAt"})
+	s.CreateIndexIfNotExists("idx_integration_deliveries_create_at", "IntegrationDeliveries", "CreateAt")
+}
+
+func (s SqlIntegrationDeliveryStore) Save(delivery *model.IntegrationDelivery) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		delivery.PreSave()
+		if result.Err = delivery.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(delivery); err != nil {
+			result.Err = model.NewAppError("SqlIntegrationDeliveryStore.Save", "store.sql_integration_delivery.save.app_error", nil, "id="+delivery.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = delivery
+		}
+	})
+}
+
+// GetDeliveries returns a page of the recorded deliveries, newest first. The deliveries can be limited to those of a
+// type of integration or of a single integration by passing their type or id, which are ignored when they're empty.
+func (s SqlIntegrationDeliveryStore) GetDeliveries(integrationType string, integrationId string, offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := "SELECT * FROM IntegrationDeliveries WHERE 1 = 1"
+		if integrationType != "" {
+			query += " AND IntegrationType = :IntegrationType"
+		}
+		if integrationId != "" {
+			query += " AND IntegrationId = :IntegrationId"
+		}
+		query += " ORDER BY CreateAt DESC, Id LIMIT :Limit OFFSET :Offset"
+
+		deliveries := []*model.IntegrationDelivery{}
+		if _, err := s.GetReplica().Select(&deliveries, query, map[string]interface{}{"IntegrationType": integrationType, "IntegrationId": integrationId, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlIntegrationDeliveryStore.GetDeliveries", "store.sql_integration_delivery.get_deliveries.app_error", nil, "integration_type="+integrationType+", integration_id="+integrationId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = deliveries
+		}
+	})
+}
+
+// PermanentDeleteOverLimit deletes the oldest deliveries so that at most limit of them are kept.
+func (s SqlIntegrationDeliveryStore) PermanentDeleteOverLimit(limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		oldestKept, err := s.GetMaster().SelectInt("SELECT CreateAt FROM IntegrationDeliveries ORDER BY CreateAt DESC LIMIT 1 OFFSET :Offset", map[string]interface{}{"Offset": limit - 1})
+		if err != nil && err != sql.ErrNoRows {
+			result.Err = model.NewAppError("SqlIntegrationDeliveryStore.PermanentDeleteOverLimit", "store.sql_integration_delivery.permanent_delete_over_limit.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// There aren't more deliveries than the limit
+		if oldestKept == 0 {
+			result.Data = int64(0)
+			return
+		}
+
+		sqlResult, err := s.GetMaster().Exec("DELETE FROM IntegrationDeliveries WHERE CreateAt < :CreateAt", map[string]interface{}{"CreateAt": oldestKept})
+		if err != nil {
+			result.Err = model.NewAppError("SqlIntegrationDeliveryStore.PermanentDeleteOverLimit", "store.sql_integration_delivery.permanent_delete_over_limit.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, _ := sqlResult.RowsAffected()
+		result.Data = rowsAffected
+	})
+}
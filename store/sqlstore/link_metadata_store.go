@@ -0,0 +1,153 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlLinkMetadataStore struct {
+	SqlStore
+}
+
+func NewSqlLinkMetadataStore(sqlStore SqlStore) store.LinkMetadataStore {
+	s := &SqlLinkMetadataStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.LinkMetadata{}, "LinkMetadata").SetKeys(false, "Hash")
+		table.ColMap("URL").SetMaxSize(model.LINK_METADATA_URL_MAX_LENGTH)
+		table.ColMap("Domain").SetMaxSize(model.LINK_METADATA_DOMAIN_MAX_LENGTH)
+		table.ColMap("Data").SetMaxSize(model.LINK_METADATA_DATA_MAX_LENGTH)
+	}
+
+	return s
+}
+
+func (s SqlLinkMetadataStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_link_metadata_domain", "LinkMetadata", "Domain")
+	s.CreateIndexIfNotExists("idx_link_metadata_create_at", "LinkMetadata", "CreateAt")
+	s.CreateIndexIfNotExists("idx_link_metadata_last_access_at", "LinkMetadata", "LastAccessAt")
+}
+
+// Save stores the metadata for a link, replacing whatever was previously stored for it.
+func (s SqlLinkMetadataStore) Save(metadata *model.LinkMetadata) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		metadata.PreSave()
+		if result.Err = metadata.IsValid(); result.Err != nil {
+			return
+		}
+
+		count, err := s.GetMaster().Update(metadata)
+		if err == nil && count == 0 {
+			err = s.GetMaster().Insert(metadata)
+
+			// Another request stored the same link in the meantime
+			if err != nil && IsUniqueConstraintError(err, []string{"PRIMARY", "linkmetadata_pkey"}) {
+				err = nil
+			}
+		}
+
+		if err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.Save", "store.sql_link_metadata.save.app_error", nil, "url="+metadata.URL+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = metadata
+		}
+	})
+}
+
+func (s SqlLinkMetadataStore) Get(url string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var metadata model.LinkMetadata
+
+		// Different links could hash to the same value, so make sure that we've found the right one
+		if err := s.GetReplica().SelectOne(&metadata, "SELECT * FROM LinkMetadata WHERE Hash = :Hash AND URL = :URL", map[string]interface{}{"Hash": model.GenerateLinkMetadataHash(url), "URL": url}); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.Get", "store.sql_link_metadata.get.app_error", nil, "url="+url+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &metadata
+		}
+	})
+}
+
+// Touch records that the stored metadata for a link was used.
+func (s SqlLinkMetadataStore) Touch(hash int64, accessAt int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("UPDATE LinkMetadata SET LastAccessAt = :LastAccessAt, AccessCount = AccessCount + 1 WHERE Hash = :Hash", map[string]interface{}{"Hash": hash, "LastAccessAt": accessAt}); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.Touch", "store.sql_link_metadata.touch.app_error", nil, "hash="+strconv.FormatInt(hash, 10)+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// GetExpired returns metadata that was fetched before the given time, oldest first.
+func (s SqlLinkMetadataStore) GetExpired(createdBefore int64, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var metadata []*model.LinkMetadata
+
+		// This is used to page through expired metadata as it's removed, so it can't wait for replicas to catch up
+		if _, err := s.GetMaster().Select(&metadata, "SELECT * FROM LinkMetadata WHERE CreateAt < :CreateAt ORDER BY CreateAt ASC LIMIT :Limit", map[string]interface{}{"CreateAt": createdBefore, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.GetExpired", "store.sql_link_metadata.get_expired.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = metadata
+		}
+	})
+}
+
+func (s SqlLinkMetadataStore) Delete(hash int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM LinkMetadata WHERE Hash = :Hash", map[string]interface{}{"Hash": hash}); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.Delete", "store.sql_link_metadata.delete.app_error", nil, "hash="+strconv.FormatInt(hash, 10)+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// DeleteForDomain deletes the metadata for every link to the given domain, returning how many links were deleted.
+func (s SqlLinkMetadataStore) DeleteForDomain(domain string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		sqlResult, err := s.GetMaster().Exec("DELETE FROM LinkMetadata WHERE Domain = :Domain", map[string]interface{}{"Domain": domain})
+		if err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.DeleteForDomain", "store.sql_link_metadata.delete_for_domain.app_error", nil, "domain="+domain+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if rowsAffected, err := sqlResult.RowsAffected(); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.DeleteForDomain", "store.sql_link_metadata.delete_for_domain.app_error", nil, "domain="+domain+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = rowsAffected
+		}
+	})
+}
+
+func (s SqlLinkMetadataStore) Count() store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if count, err := s.GetReplica().SelectInt("SELECT COUNT(*) FROM LinkMetadata"); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.Count", "store.sql_link_metadata.count.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = count
+		}
+	})
+}
+
+// DeleteLeastRecentlyUsed deletes the metadata for the given number of links that have gone the longest without
+// being used.
+func (s SqlLinkMetadataStore) DeleteLeastRecentlyUsed(count int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		// MySQL doesn't allow LIMIT in an IN subquery, but it does allow it in a derived table
+		query := `DELETE FROM LinkMetadata WHERE Hash IN (
+			SELECT Hash FROM (
+				SELECT Hash FROM LinkMetadata ORDER BY LastAccessAt ASC LIMIT :Limit
+			) AS LeastRecentlyUsed
+		)`
+
+		if _, err := s.GetMaster().Exec(query, map[string]interface{}{"Limit": count}); err != nil {
+			result.Err = model.NewAppError("SqlLinkMetadataStore.DeleteLeastRecentlyUsed", "store.sql_link_metadata.delete_least_recently_used.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -37,6 +37,8 @@ func NewSqlOAuthStore(sqlStore SqlStore) store.OAuthStore {
 		tableAuth.ColMap("RedirectUri").SetMaxSize(256)
 		tableAuth.ColMap("State").SetMaxSize(1024)
 		tableAuth.ColMap("Scope").SetMaxSize(128)
+		tableAuth.ColMap("CodeChallenge").SetMaxSize(128)
+		tableAuth.ColMap("CodeChallengeMethod").SetMaxSize(16)
 
 		tableAccess := db.AddTableWithName(model.AccessData{}, "OAuthAccessData").SetKeys(false, "Token")
 		tableAccess.ColMap("ClientId").SetMaxSize(26)
@@ -257,8 +259,8 @@ func (as SqlOAuthStore) UpdateAccessData(accessData *model.AccessData) store.Sto
 			return
 		}
 
-		if _, err := as.GetMaster().Exec("UPDATE OAuthAccessData SET Token = :Token, ExpiresAt = :ExpiresAt, RefreshToken = :RefreshToken WHERE ClientId = :ClientId AND UserID = :UserId",
-			map[string]interface{}{"Token": accessData.Token, "ExpiresAt": accessData.ExpiresAt, "RefreshToken": accessData.RefreshToken, "ClientId": accessData.ClientId, "UserId": accessData.UserId}); err != nil {
+		if _, err := as.GetMaster().Exec("UPDATE OAuthAccessData SET Token = :Token, ExpiresAt = :ExpiresAt, RefreshToken = :RefreshToken, Scope = :Scope WHERE ClientId = :ClientId AND UserID = :UserId",
+			map[string]interface{}{"Token": accessData.Token, "ExpiresAt": accessData.ExpiresAt, "RefreshToken": accessData.RefreshToken, "Scope": accessData.Scope, "ClientId": accessData.ClientId, "UserId": accessData.UserId}); err != nil {
 			result.Err = model.NewAppError("SqlOAuthStore.Update", "store.sql_oauth.update_access_data.app_error", nil,
 				"clientId="+accessData.ClientId+",userId="+accessData.UserId+", "+err.Error(), http.StatusInternalServerError)
 		} else {
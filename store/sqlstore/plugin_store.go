@@ -4,6 +4,7 @@
 package sqlstore
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -30,6 +31,7 @@ func NewSqlPluginStore(sqlStore SqlStore) store.PluginStore {
 }
 
 func (ps SqlPluginStore) CreateIndexesIfNotExists() {
+	ps.CreateIndexIfNotExists("idx_plugin_key_value_store_expire_at", "PluginKeyValueStore", "ExpireAt")
 }
 
 func (ps SqlPluginStore) SaveOrUpdate(kv *model.PluginKeyValue) store.StoreChannel {
@@ -57,7 +59,7 @@ func (ps SqlPluginStore) SaveOrUpdate(kv *model.PluginKeyValue) store.StoreChann
 				}
 			}
 		} else if ps.DriverName() == model.DATABASE_DRIVER_MYSQL {
-			if _, err := ps.GetMaster().Exec("INSERT INTO PluginKeyValueStore (PluginId, PKey, PValue) VALUES(:PluginId, :Key, :Value) ON DUPLICATE KEY UPDATE PValue = :Value", map[string]interface{}{"PluginId": kv.PluginId, "Key": kv.Key, "Value": kv.Value}); err != nil {
+			if _, err := ps.GetMaster().Exec("INSERT INTO PluginKeyValueStore (PluginId, PKey, PValue, ExpireAt) VALUES(:PluginId, :Key, :Value, :ExpireAt) ON DUPLICATE KEY UPDATE PValue = :Value, ExpireAt = :ExpireAt", map[string]interface{}{"PluginId": kv.PluginId, "Key": kv.Key, "Value": kv.Value, "ExpireAt": kv.ExpireAt}); err != nil {
 				result.Err = model.NewAppError("SqlPluginStore.SaveOrUpdate", "store.sql_plugin_store.save.app_error", nil, err.Error(), http.StatusInternalServerError)
 				return
 			}
@@ -67,11 +69,71 @@ func (ps SqlPluginStore) SaveOrUpdate(kv *model.PluginKeyValue) store.StoreChann
 	})
 }
 
+// CompareAndSet sets a value only if the current value is oldValue, returning whether it was set. A nil oldValue only
+// sets the value if there isn't one, which includes a value that has expired. Since the check happens in the database,
+// it's safe to use for locks that are shared by every server in a cluster.
+func (ps SqlPluginStore) CompareAndSet(kv *model.PluginKeyValue, oldValue []byte) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if result.Err = kv.IsValid(); result.Err != nil {
+			return
+		}
+
+		params := map[string]interface{}{
+			"PluginId": kv.PluginId,
+			"Key":      kv.Key,
+			"Value":    kv.Value,
+			"ExpireAt": kv.ExpireAt,
+			"OldValue": oldValue,
+			"Now":      model.GetMillis(),
+		}
+
+		if oldValue == nil {
+			if err := ps.GetMaster().Insert(kv); err == nil {
+				result.Data = true
+				return
+			} else if !IsUniqueConstraintError(err, []string{"PRIMARY", "PluginId", "Key", "PKey"}) {
+				result.Err = model.NewAppError("SqlPluginStore.CompareAndSet", "store.sql_plugin_store.compare_and_set.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", kv.PluginId, kv.Key, err.Error()), http.StatusInternalServerError)
+				return
+			}
+
+			// There's already a value, but it can be replaced if it has expired
+			sqlResult, err := ps.GetMaster().Exec("UPDATE PluginKeyValueStore SET PValue = :Value, ExpireAt = :ExpireAt WHERE PluginId = :PluginId AND PKey = :Key AND ExpireAt != 0 AND ExpireAt <= :Now", params)
+			if err != nil {
+				result.Err = model.NewAppError("SqlPluginStore.CompareAndSet", "store.sql_plugin_store.compare_and_set.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", kv.PluginId, kv.Key, err.Error()), http.StatusInternalServerError)
+				return
+			}
+
+			rowsAffected, _ := sqlResult.RowsAffected()
+			result.Data = rowsAffected == 1
+			return
+		}
+
+		sqlResult, err := ps.GetMaster().Exec("UPDATE PluginKeyValueStore SET PValue = :Value, ExpireAt = :ExpireAt WHERE PluginId = :PluginId AND PKey = :Key AND PValue = :OldValue AND (ExpireAt = 0 OR ExpireAt > :Now)", params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlPluginStore.CompareAndSet", "store.sql_plugin_store.compare_and_set.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", kv.PluginId, kv.Key, err.Error()), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, _ := sqlResult.RowsAffected()
+		if rowsAffected == 0 && bytes.Equal(oldValue, kv.Value) {
+			// MySQL doesn't count rows that the update didn't change, so check whether the value matched
+			count, err := ps.GetMaster().SelectInt("SELECT COUNT(*) FROM PluginKeyValueStore WHERE PluginId = :PluginId AND PKey = :Key AND PValue = :OldValue AND (ExpireAt = 0 OR ExpireAt > :Now)", params)
+			if err != nil {
+				result.Err = model.NewAppError("SqlPluginStore.CompareAndSet", "store.sql_plugin_store.compare_and_set.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", kv.PluginId, kv.Key, err.Error()), http.StatusInternalServerError)
+				return
+			}
+			rowsAffected = count
+		}
+
+		result.Data = rowsAffected == 1
+	})
+}
+
 func (ps SqlPluginStore) Get(pluginId, key string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		var kv *model.PluginKeyValue
 
-		if err := ps.GetReplica().SelectOne(&kv, "SELECT * FROM PluginKeyValueStore WHERE PluginId = :PluginId AND PKey = :Key", map[string]interface{}{"PluginId": pluginId, "Key": key}); err != nil {
+		if err := ps.GetReplica().SelectOne(&kv, "SELECT * FROM PluginKeyValueStore WHERE PluginId = :PluginId AND PKey = :Key AND (ExpireAt = 0 OR ExpireAt > :Now)", map[string]interface{}{"PluginId": pluginId, "Key": key, "Now": model.GetMillis()}); err != nil {
 			if err == sql.ErrNoRows {
 				result.Err = model.NewAppError("SqlPluginStore.Get", "store.sql_plugin_store.get.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", pluginId, key, err.Error()), http.StatusNotFound)
 			} else {
@@ -92,3 +154,45 @@ func (ps SqlPluginStore) Delete(pluginId, key string) store.StoreChannel {
 		}
 	})
 }
+
+// CompareAndDelete deletes a value only if it's oldValue, returning whether it was deleted.
+func (ps SqlPluginStore) CompareAndDelete(pluginId, key string, oldValue []byte) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		sqlResult, err := ps.GetMaster().Exec("DELETE FROM PluginKeyValueStore WHERE PluginId = :PluginId AND PKey = :Key AND PValue = :OldValue AND (ExpireAt = 0 OR ExpireAt > :Now)", map[string]interface{}{"PluginId": pluginId, "Key": key, "OldValue": oldValue, "Now": model.GetMillis()})
+		if err != nil {
+			result.Err = model.NewAppError("SqlPluginStore.CompareAndDelete", "store.sql_plugin_store.compare_and_delete.app_error", nil, fmt.Sprintf("plugin_id=%v, key=%v, err=%v", pluginId, key, err.Error()), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, _ := sqlResult.RowsAffected()
+		result.Data = rowsAffected == 1
+	})
+}
+
+// DeleteAllExpired removes the values that have expired, returning how many were removed.
+func (ps SqlPluginStore) DeleteAllExpired() store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		sqlResult, err := ps.GetMaster().Exec("DELETE FROM PluginKeyValueStore WHERE ExpireAt != 0 AND ExpireAt <= :Now", map[string]interface{}{"Now": model.GetMillis()})
+		if err != nil {
+			result.Err = model.NewAppError("SqlPluginStore.DeleteAllExpired", "store.sql_plugin_store.delete_all_expired.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, _ := sqlResult.RowsAffected()
+		result.Data = rowsAffected
+	})
+}
+
+// GetTotalSize returns the number of bytes used by the keys and values that a plugin has stored and that haven't
+// expired.
+func (ps SqlPluginStore) GetTotalSize(pluginId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		size, err := ps.GetReplica().SelectInt("SELECT COALESCE(SUM(LENGTH(PKey) + LENGTH(PValue)), 0) FROM PluginKeyValueStore WHERE PluginId = :PluginId AND (ExpireAt = 0 OR ExpireAt > :Now)", map[string]interface{}{"PluginId": pluginId, "Now": model.GetMillis()})
+		if err != nil {
+			result.Err = model.NewAppError("SqlPluginStore.GetTotalSize", "store.sql_plugin_store.get_total_size.app_error", nil, fmt.Sprintf("plugin_id=%v, err=%v", pluginId, err.Error()), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = size
+	})
+}
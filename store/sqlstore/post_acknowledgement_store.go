@@ -0,0 +1,79 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlPostAcknowledgementStore struct {
+	SqlStore
+}
+
+func NewSqlPostAcknowledgementStore(sqlStore SqlStore) store.PostAcknowledgementStore {
+	s := &SqlPostAcknowledgementStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.PostAcknowledgement{}, "PostAcknowledgements").SetKeys(false, "PostId", "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("PostId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlPostAcknowledgementStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_post_acknowledgements_user_id", "PostAcknowledgements", "UserId")
+}
+
+// Save records that a user acknowledged a post. Acknowledging a post again keeps the time of the first
+// acknowledgement.
+func (s SqlPostAcknowledgementStore) Save(acknowledgement *model.PostAcknowledgement) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		acknowledgement.PreSave()
+		if result.Err = acknowledgement.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(acknowledgement); err != nil {
+			if !IsUniqueConstraintError(err, []string{"PRIMARY", "postacknowledgements_pkey"}) {
+				result.Err = model.NewAppError("SqlPostAcknowledgementStore.Save", "store.sql_post_acknowledgement.save.app_error", nil, "user_id="+acknowledgement.UserId+", post_id="+acknowledgement.PostId+", "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			var existing model.PostAcknowledgement
+			if err := s.GetMaster().SelectOne(&existing, "SELECT * FROM PostAcknowledgements WHERE PostId = :PostId AND UserId = :UserId", map[string]interface{}{"PostId": acknowledgement.PostId, "UserId": acknowledgement.UserId}); err != nil {
+				result.Err = model.NewAppError("SqlPostAcknowledgementStore.Save", "store.sql_post_acknowledgement.save.app_error", nil, "user_id="+acknowledgement.UserId+", post_id="+acknowledgement.PostId+", "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			acknowledgement = &existing
+		}
+
+		result.Data = acknowledgement
+	})
+}
+
+func (s SqlPostAcknowledgementStore) Delete(userId string, postId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM PostAcknowledgements WHERE PostId = :PostId AND UserId = :UserId", map[string]interface{}{"PostId": postId, "UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlPostAcknowledgementStore.Delete", "store.sql_post_acknowledgement.delete.app_error", nil, "user_id="+userId+", post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// GetForPost returns the acknowledgements of a post in the order that they were made.
+func (s SqlPostAcknowledgementStore) GetForPost(postId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var acknowledgements []*model.PostAcknowledgement
+		if _, err := s.GetReplica().Select(&acknowledgements, "SELECT * FROM PostAcknowledgements WHERE PostId = :PostId ORDER BY AcknowledgedAt ASC", map[string]interface{}{"PostId": postId}); err != nil {
+			result.Err = model.NewAppError("SqlPostAcknowledgementStore.GetForPost", "store.sql_post_acknowledgement.get_for_post.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = acknowledgements
+		}
+	})
+}
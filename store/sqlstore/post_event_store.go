@@ -0,0 +1,103 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/mattermost/gorp"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlPostEventStore struct {
+	SqlStore
+}
+
+func NewSqlPostEventStore(sqlStore SqlStore) store.PostEventStore {
+	s := &SqlPostEventStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.PostEvent{}, "PostEvents").SetKeys(true, "Seq")
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("PostId").SetMaxSize(26)
+		table.ColMap("Type").SetMaxSize(32)
+		table.ColMap("Data").SetMaxSize(model.POST_EVENT_DATA_MAX_LENGTH)
+	}
+
+	return s
+}
+
+func (s SqlPostEventStore) CreateIndexesIfNotExists() {
+	s.CreateCompositeIndexIfNotExists("idx_post_events_channel_id_seq", "PostEvents", []string{"ChannelId", "Seq"})
+	s.CreateIndexIfNotExists("idx_post_events_create_at", "PostEvents", "CreateAt")
+}
+
+// savePostEvent appends an event to the post event log. The channel is taken from the post so that events for
+// reactions end up in the right channel.
+func savePostEvent(executor gorp.SqlExecutor, event *model.PostEvent) error {
+	query := `INSERT INTO PostEvents (ChannelId, PostId, Type, Data, CreateAt)
+		SELECT ChannelId, Id, :Type, :Data, :CreateAt FROM Posts WHERE Id = :PostId`
+
+	_, err := executor.Exec(query, map[string]interface{}{"PostId": event.PostId, "Type": event.Type, "Data": event.Data, "CreateAt": event.CreateAt})
+	return err
+}
+
+// changePostWithEvent makes a change to the posts and, when the post event log is enabled, records the event for it
+// in the same transaction so that the log never disagrees with the posts themselves.
+func changePostWithEvent(sqlStore SqlStore, event *model.PostEvent, change func(executor gorp.SqlExecutor) error) error {
+	if !sqlStore.PostEventsEnabled() {
+		return change(sqlStore.GetMaster())
+	}
+
+	transaction, err := sqlStore.GetMaster().Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := change(transaction); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := savePostEvent(transaction, event); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
+}
+
+// GetForChannel returns the events in a channel after the given cursor, oldest first. Only events created before the
+// given time are returned so that a transaction which took a lower Seq but committed late isn't skipped over.
+func (s SqlPostEventStore) GetForChannel(channelId string, afterSeq int64, createdBefore int64, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := `SELECT * FROM PostEvents
+			WHERE ChannelId = :ChannelId AND Seq > :AfterSeq AND CreateAt < :CreatedBefore
+			ORDER BY Seq ASC LIMIT :Limit`
+
+		// Replicas could be missing events that are older than ones they already have
+		var events []*model.PostEvent
+		if _, err := s.GetMaster().Select(&events, query, map[string]interface{}{"ChannelId": channelId, "AfterSeq": afterSeq, "CreatedBefore": createdBefore, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlPostEventStore.GetForChannel", "store.sql_post_event.get_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = events
+		}
+	})
+}
+
+// GetLatestSeq returns the Seq of the newest event in a channel that was created before the given time, or 0 if there
+// aren't any.
+func (s SqlPostEventStore) GetLatestSeq(channelId string, createdBefore int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := "SELECT COALESCE(MAX(Seq), 0) FROM PostEvents WHERE ChannelId = :ChannelId AND CreateAt < :CreatedBefore"
+
+		if seq, err := s.GetMaster().SelectInt(query, map[string]interface{}{"ChannelId": channelId, "CreatedBefore": createdBefore}); err != nil {
+			result.Err = model.NewAppError("SqlPostEventStore.GetLatestSeq", "store.sql_post_event.get_latest_seq.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = seq
+		}
+	})
+}
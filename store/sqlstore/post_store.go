@@ -5,6 +5,7 @@ package sqlstore
 
 import (
 	"bytes"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/mattermost/gorp"
 	"github.com/mattermost/mattermost-server/einterfaces"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
@@ -69,6 +71,7 @@ func NewSqlPostStore(sqlStore SqlStore, metrics einterfaces.MetricsInterface) st
 		table.ColMap("Props").SetMaxSize(8000)
 		table.ColMap("Filenames").SetMaxSize(model.POST_FILENAMES_MAX_RUNES)
 		table.ColMap("FileIds").SetMaxSize(150)
+		table.ColMap("RemoteId").SetMaxSize(model.REMOTE_ID_MAX_LENGTH)
 	}
 
 	return s
@@ -82,6 +85,7 @@ func (s *SqlPostStore) CreateIndexesIfNotExists() {
 	s.CreateIndexIfNotExists("idx_posts_root_id", "Posts", "RootId")
 	s.CreateIndexIfNotExists("idx_posts_user_id", "Posts", "UserId")
 	s.CreateIndexIfNotExists("idx_posts_is_pinned", "Posts", "IsPinned")
+	s.CreateUniqueIndexIfNotExists("idx_posts_remote_id_unique", "Posts", "RemoteId")
 
 	s.CreateCompositeIndexIfNotExists("idx_posts_channel_id_update_at", "Posts", []string{"ChannelId", "UpdateAt"})
 	s.CreateCompositeIndexIfNotExists("idx_posts_channel_id_delete_at_create_at", "Posts", []string{"ChannelId", "DeleteAt", "CreateAt"})
@@ -110,8 +114,15 @@ func (s *SqlPostStore) Save(post *model.Post) store.StoreChannel {
 			return
 		}
 
-		if err := s.GetMaster().Insert(post); err != nil {
-			result.Err = model.NewAppError("SqlPostStore.Save", "store.sql_post.save.app_error", nil, "id="+post.Id+", "+err.Error(), http.StatusInternalServerError)
+		err := changePostWithEvent(s, model.NewPostEvent(model.POST_EVENT_TYPE_CREATED, post), func(executor gorp.SqlExecutor) error {
+			return executor.Insert(post)
+		})
+		if err != nil {
+			if IsUniqueConstraintError(err, []string{"RemoteId", "idx_posts_remote_id_unique"}) {
+				result.Err = model.NewAppError("SqlPostStore.Save", "store.sql_post.save.remote_id_exists.app_error", nil, "id="+post.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlPostStore.Save", "store.sql_post.save.app_error", nil, "id="+post.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
 		} else {
 			time := post.UpdateAt
 
@@ -143,6 +154,7 @@ func (s *SqlPostStore) Update(newPost *model.Post, oldPost *model.Post) store.St
 		oldPost.UpdateAt = newPost.UpdateAt
 		oldPost.OriginalId = oldPost.Id
 		oldPost.Id = model.NewId()
+		oldPost.RemoteId = nil
 		oldPost.PreCommit()
 
 		var maxPostSize int
@@ -157,8 +169,16 @@ func (s *SqlPostStore) Update(newPost *model.Post, oldPost *model.Post) store.St
 			return
 		}
 
-		if _, err := s.GetMaster().Update(newPost); err != nil {
-			result.Err = model.NewAppError("SqlPostStore.Update", "store.sql_post.update.app_error", nil, "id="+newPost.Id+", "+err.Error(), http.StatusInternalServerError)
+		err := changePostWithEvent(s, model.NewPostEvent(model.POST_EVENT_TYPE_EDITED, newPost), func(executor gorp.SqlExecutor) error {
+			_, err := executor.Update(newPost)
+			return err
+		})
+		if err != nil {
+			if IsUniqueConstraintError(err, []string{"RemoteId", "idx_posts_remote_id_unique"}) {
+				result.Err = model.NewAppError("SqlPostStore.Update", "store.sql_post.update.remote_id_exists.app_error", nil, "id="+newPost.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlPostStore.Update", "store.sql_post.update.app_error", nil, "id="+newPost.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
 		} else {
 			time := model.GetMillis()
 			s.GetMaster().Exec("UPDATE Channels SET LastPostAt = :LastPostAt  WHERE Id = :ChannelId", map[string]interface{}{"LastPostAt": time, "ChannelId": newPost.ChannelId})
@@ -348,6 +368,22 @@ func (s *SqlPostStore) GetSingle(id string) store.StoreChannel {
 	})
 }
 
+func (s *SqlPostStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var post model.Post
+		if err := s.GetReplica().SelectOne(&post, "SELECT * FROM Posts WHERE RemoteId = :RemoteId AND DeleteAt = 0", map[string]interface{}{"RemoteId": remoteId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlPostStore.GetByRemoteId", "store.sql_post.get_by_remote_id.missing.app_error", nil, "remote_id="+remoteId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewAppError("SqlPostStore.GetByRemoteId", "store.sql_post.get_by_remote_id.app_error", nil, "remote_id="+remoteId+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result.Data = &post
+	})
+}
+
 type etagPosts struct {
 	Id       string
 	UpdateAt int64
@@ -412,8 +448,14 @@ func (s *SqlPostStore) Delete(postId string, time int64, deleteByID string) stor
 		}
 
 		post.Props[model.POST_PROPS_DELETE_BY] = deleteByID
+		post.DeleteAt = time
+		post.UpdateAt = time
 
-		_, err = s.GetMaster().Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id OR RootId = :RootId", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": postId, "RootId": postId, "Props": model.StringInterfaceToJson(post.Props)})
+		// Replies are deleted along with the post, so the one event covers the whole thread
+		err = changePostWithEvent(s, model.NewPostEvent(model.POST_EVENT_TYPE_DELETED, &post), func(executor gorp.SqlExecutor) error {
+			_, err := executor.Exec("UPDATE Posts SET DeleteAt = :DeleteAt, UpdateAt = :UpdateAt, Props = :Props WHERE Id = :Id OR RootId = :RootId", map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": postId, "RootId": postId, "Props": model.StringInterfaceToJson(post.Props)})
+			return err
+		})
 		if err != nil {
 			result.Err = appErr(err.Error())
 		}
@@ -571,7 +613,7 @@ func (s *SqlPostStore) GetPostsSince(channelId string, time int64, allowFromCach
 		}
 
 		var posts []*model.Post
-		_, err := s.GetReplica().Select(&posts,
+		_, err := s.GetReplicaFor("Post.GetPostsSince").Select(&posts,
 			`(SELECT
 			    *
 			FROM
@@ -646,7 +688,7 @@ func (s *SqlPostStore) getPostsAround(channelId string, postId string, numPosts
 
 		var posts []*model.Post
 		var parents []*model.Post
-		_, err1 := s.GetReplica().Select(&posts,
+		_, err1 := s.GetReplicaFor("Post.GetPostsAround").Select(&posts,
 			`(SELECT
 			    *
 			FROM
@@ -659,7 +701,7 @@ func (s *SqlPostStore) getPostsAround(channelId string, postId string, numPosts
 			LIMIT :NumPosts
 			OFFSET :Offset)`,
 			map[string]interface{}{"ChannelId": channelId, "PostId": postId, "NumPosts": numPosts, "Offset": offset})
-		_, err2 := s.GetReplica().Select(&parents,
+		_, err2 := s.GetReplicaFor("Post.GetPostsAround").Select(&parents,
 			`(SELECT
 			    *
 			FROM
@@ -713,10 +755,88 @@ func (s *SqlPostStore) getPostsAround(channelId string, postId string, numPosts
 	})
 }
 
+// GetPostsByCursor returns the posts in the channel that come before or after the cursor, along with the roots of any
+// threads that they're in. Posts are ordered by CreateAt and then Id, so posts made in the same millisecond are still
+// returned exactly once as the cursor moves through them.
+func (s *SqlPostStore) GetPostsByCursor(channelId string, cursor *model.PostCursor, before bool, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		// The first condition on CreateAt is redundant, but it lets the database seek straight to the cursor in the
+		// index instead of scanning every post in the channel.
+		var condition string
+		var sort string
+		if before {
+			condition = "CreateAt <= :CreateAt AND (CreateAt < :CreateAt OR Id < :Id)"
+			sort = "DESC"
+		} else {
+			condition = "CreateAt >= :CreateAt AND (CreateAt > :CreateAt OR Id > :Id)"
+			sort = "ASC"
+		}
+
+		var posts []*model.Post
+		if _, err := s.GetReplicaFor("Post.GetPostsByCursor").Select(&posts,
+			`SELECT
+				*
+			FROM
+				Posts
+			WHERE
+				ChannelId = :ChannelId
+				AND DeleteAt = 0
+				AND `+condition+`
+			ORDER BY CreateAt `+sort+`, Id `+sort+`
+			LIMIT :Limit`,
+			map[string]interface{}{"ChannelId": channelId, "CreateAt": cursor.CreateAt, "Id": cursor.Id, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlPostStore.GetPostsByCursor", "store.sql_post.get_posts_by_cursor.app_error", nil, "channelId="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		list := model.NewPostList()
+
+		// Lists are ordered newest first, so flip the posts if they were selected oldest first
+		for i := range posts {
+			post := posts[i]
+			if !before {
+				post = posts[len(posts)-i-1]
+			}
+
+			list.AddPost(post)
+			list.AddOrder(post.Id)
+		}
+
+		keys := bytes.Buffer{}
+		params := map[string]interface{}{}
+		for _, post := range posts {
+			if post.RootId == "" || list.Posts[post.RootId] != nil || params["Root"+post.RootId] != nil {
+				continue
+			}
+
+			if keys.Len() > 0 {
+				keys.WriteString(",")
+			}
+
+			keys.WriteString(":Root" + post.RootId)
+			params["Root"+post.RootId] = post.RootId
+		}
+
+		if keys.Len() > 0 {
+			var parents []*model.Post
+			if _, err := s.GetReplicaFor("Post.GetPostsByCursor").Select(&parents, "SELECT * FROM Posts WHERE Id IN ("+keys.String()+")", params); err != nil {
+				result.Err = model.NewAppError("SqlPostStore.GetPostsByCursor", "store.sql_post.get_posts_by_cursor.get_parent.app_error", nil, "channelId="+channelId+", "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			for _, parent := range parents {
+				list.AddPost(parent)
+			}
+		}
+
+		result.Data = list
+	})
+}
+
 func (s *SqlPostStore) getRootPosts(channelId string, offset int, limit int) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		var posts []*model.Post
-		_, err := s.GetReplica().Select(&posts, "SELECT * FROM Posts WHERE ChannelId = :ChannelId AND DeleteAt = 0 ORDER BY CreateAt DESC LIMIT :Limit OFFSET :Offset", map[string]interface{}{"ChannelId": channelId, "Offset": offset, "Limit": limit})
+		_, err := s.GetReplicaFor("Post.GetPosts").Select(&posts, "SELECT * FROM Posts WHERE ChannelId = :ChannelId AND DeleteAt = 0 ORDER BY CreateAt DESC LIMIT :Limit OFFSET :Offset", map[string]interface{}{"ChannelId": channelId, "Offset": offset, "Limit": limit})
 		if err != nil {
 			result.Err = model.NewAppError("SqlPostStore.GetLinearPosts", "store.sql_post.get_root_posts.app_error", nil, "channelId="+channelId+err.Error(), http.StatusInternalServerError)
 		} else {
@@ -728,7 +848,7 @@ func (s *SqlPostStore) getRootPosts(channelId string, offset int, limit int) sto
 func (s *SqlPostStore) getParentsPosts(channelId string, offset int, limit int) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		var posts []*model.Post
-		_, err := s.GetReplica().Select(&posts,
+		_, err := s.GetReplicaFor("Post.GetPosts").Select(&posts,
 			`SELECT
 			    q2.*
 			FROM
@@ -1029,7 +1149,7 @@ func (s *SqlPostStore) AnalyticsUserCountsWithPostsByDay(teamId string) store.St
 		start := utils.MillisFromTime(utils.StartOfDay(utils.Yesterday().AddDate(0, 0, -31)))
 
 		var rows model.AnalyticsRows
-		_, err := s.GetReplica().Select(
+		_, err := s.GetReplicaFor("Post.AnalyticsUserCountsWithPostsByDay").Select(
 			&rows,
 			query,
 			map[string]interface{}{"TeamId": teamId, "StartTime": start, "EndTime": end})
@@ -1084,7 +1204,7 @@ func (s *SqlPostStore) AnalyticsPostCountsByDay(teamId string) store.StoreChanne
 		start := utils.MillisFromTime(utils.StartOfDay(utils.Yesterday().AddDate(0, 0, -31)))
 
 		var rows model.AnalyticsRows
-		_, err := s.GetReplica().Select(
+		_, err := s.GetReplicaFor("Post.AnalyticsPostCountsByDay").Select(
 			&rows,
 			query,
 			map[string]interface{}{"TeamId": teamId, "StartTime": start, "EndTime": end})
@@ -1119,7 +1239,7 @@ func (s *SqlPostStore) AnalyticsPostCount(teamId string, mustHaveFile bool, must
 			query += " AND Posts.Hashtags != ''"
 		}
 
-		if v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
+		if v, err := s.GetReplicaFor("Post.AnalyticsPostCount").SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
 			result.Err = model.NewAppError("SqlPostStore.AnalyticsPostCount", "store.sql_post.analytics_posts_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = v
@@ -1308,3 +1428,25 @@ func (s *SqlPostStore) GetMaxPostSize() store.StoreChannel {
 		result.Data = s.maxPostSizeCached
 	})
 }
+
+// GetRootPostsForExport returns a page of the channel's posts that aren't replies, oldest first.
+func (s *SqlPostStore) GetRootPostsForExport(channelId string, offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var posts []*model.Post
+		if _, err := s.GetReplica().Select(&posts, `
+			SELECT
+				*
+			FROM
+				Posts
+			WHERE
+				ChannelId = :ChannelId
+				AND RootId = ''
+				AND DeleteAt = 0
+			ORDER BY CreateAt ASC, Id ASC
+			LIMIT :Limit OFFSET :Offset`, map[string]interface{}{"ChannelId": channelId, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlPostStore.GetRootPostsForExport", "store.sql_post.get_root_posts_for_export.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = posts
+		}
+	})
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"github.com/go-redis/redis"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+)
+
+// UseRedisCaches moves the profile and channel member caches into Redis, under keys that start with prefix, so that
+// they're shared by every server in the cluster. Passing a nil client moves them back into memory.
+func UseRedisCaches(client *redis.Client, prefix string) {
+	if client == nil {
+		profilesInChannelCache = utils.NewLru(PROFILES_IN_CHANNEL_CACHE_SIZE)
+		profileByIdsCache = utils.NewLru(PROFILE_BY_IDS_CACHE_SIZE)
+		channelMemberCountsCache = utils.NewLru(CHANNEL_MEMBERS_COUNTS_CACHE_SIZE)
+		allChannelMembersForUserCache = utils.NewLru(ALL_CHANNEL_MEMBERS_FOR_USER_CACHE_SIZE)
+		allChannelMembersNotifyPropsForChannelCache = utils.NewLru(ALL_CHANNEL_MEMBERS_NOTIFY_PROPS_FOR_CHANNEL_CACHE_SIZE)
+		return
+	}
+
+	profilesInChannelCache = utils.NewRedisCache(client, prefix+"profiles_in_channel:", "Profiles in Channel", map[string]*model.User{}, PROFILES_IN_CHANNEL_CACHE_SEC, "")
+	profileByIdsCache = utils.NewRedisCache(client, prefix+"profile_by_ids:", "Profile By Ids", &model.User{}, PROFILE_BY_IDS_CACHE_SEC, "")
+	channelMemberCountsCache = utils.NewRedisCache(client, prefix+"channel_member_counts:", "Channel Member Counts", int64(0), CHANNEL_MEMBERS_COUNTS_CACHE_SEC, "")
+	allChannelMembersForUserCache = utils.NewRedisCache(client, prefix+"all_channel_members_for_user:", "All Channel Members for User", map[string]string{}, ALL_CHANNEL_MEMBERS_FOR_USER_CACHE_SEC, "")
+	allChannelMembersNotifyPropsForChannelCache = utils.NewRedisCache(client, prefix+"all_channel_members_notify_props:", "All Channel Members Notify Props for Channel", map[string]model.StringMap{}, ALL_CHANNEL_MEMBERS_NOTIFY_PROPS_FOR_CHANNEL_CACHE_SEC, "")
+}
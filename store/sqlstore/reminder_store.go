@@ -0,0 +1,96 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlReminderStore struct {
+	SqlStore
+}
+
+func NewSqlReminderStore(sqlStore SqlStore) store.ReminderStore {
+	s := &SqlReminderStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.Reminder{}, "Reminders").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("ChannelId").SetMaxSize(26)
+		table.ColMap("Message").SetMaxSize(model.REMINDER_MESSAGE_MAX_RUNES * 4)
+	}
+
+	return s
+}
+
+func (s SqlReminderStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_reminders_user_id", "Reminders", "UserId")
+	s.CreateIndexIfNotExists("idx_reminders_remind_at", "Reminders", "RemindAt")
+}
+
+func (s SqlReminderStore) Save(reminder *model.Reminder) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		reminder.PreSave()
+		if result.Err = reminder.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(reminder); err != nil {
+			result.Err = model.NewAppError("SqlReminderStore.Save", "store.sql_reminder.save.app_error", nil, "id="+reminder.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = reminder
+		}
+	})
+}
+
+func (s SqlReminderStore) Get(reminderId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var reminder model.Reminder
+		if err := s.GetReplica().SelectOne(&reminder, "SELECT * FROM Reminders WHERE Id = :Id", map[string]interface{}{"Id": reminderId}); err != nil {
+			result.Err = model.NewAppError("SqlReminderStore.Get", "store.sql_reminder.get.app_error", nil, "id="+reminderId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &reminder
+		}
+	})
+}
+
+// GetForUser returns the reminders that a user has set which haven't been delivered yet, soonest first.
+func (s SqlReminderStore) GetForUser(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		reminders := []*model.Reminder{}
+		if _, err := s.GetReplica().Select(&reminders, "SELECT * FROM Reminders WHERE UserId = :UserId ORDER BY RemindAt, Id", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlReminderStore.GetForUser", "store.sql_reminder.get_for_user.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = reminders
+		}
+	})
+}
+
+// GetDue returns up to limit reminders that should be delivered before the given time, oldest first.
+func (s SqlReminderStore) GetDue(before int64, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		reminders := []*model.Reminder{}
+		if _, err := s.GetMaster().Select(&reminders, "SELECT * FROM Reminders WHERE RemindAt <= :Before ORDER BY RemindAt, Id LIMIT :Limit", map[string]interface{}{"Before": before, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlReminderStore.GetDue", "store.sql_reminder.get_due.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = reminders
+		}
+	})
+}
+
+func (s SqlReminderStore) Delete(reminderId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM Reminders WHERE Id = :Id", map[string]interface{}{"Id": reminderId}); err != nil {
+			result.Err = model.NewAppError("SqlReminderStore.Delete", "store.sql_reminder.delete.app_error", nil, "id="+reminderId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -215,7 +215,7 @@ func (me SqlSessionStore) AnalyticsSessionCount() store.StoreChannel {
                 Sessions
             WHERE ExpiresAt > :Time`
 
-		if c, err := me.GetReplica().SelectInt(query, map[string]interface{}{"Time": model.GetMillis()}); err != nil {
+		if c, err := me.GetReplicaFor("Session.AnalyticsSessionCount").SelectInt(query, map[string]interface{}{"Time": model.GetMillis()}); err != nil {
 			result.Err = model.NewAppError("SqlSessionStore.AnalyticsSessionCount", "store.sql_session.analytics_session_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = c
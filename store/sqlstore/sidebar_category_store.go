@@ -0,0 +1,109 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlSidebarCategoryStore struct {
+	SqlStore
+}
+
+func NewSqlSidebarCategoryStore(sqlStore SqlStore) store.SidebarCategoryStore {
+	s := &SqlSidebarCategoryStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.SidebarCategory{}, "SidebarCategories").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("TeamId").SetMaxSize(26)
+		table.ColMap("DisplayName").SetMaxSize(model.SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES * 4)
+		table.ColMap("ChannelIds").SetMaxSize(model.SIDEBAR_CATEGORY_MAX_CHANNELS * 29)
+	}
+
+	return s
+}
+
+func (s SqlSidebarCategoryStore) CreateIndexesIfNotExists() {
+	s.CreateCompositeIndexIfNotExists("idx_sidebar_categories_user_id_team_id", "SidebarCategories", []string{"UserId", "TeamId"})
+}
+
+func (s SqlSidebarCategoryStore) Save(category *model.SidebarCategory) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(category.Id) > 0 {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Save", "store.sql_sidebar_category.save.existing.app_error", nil, "id="+category.Id, http.StatusBadRequest)
+			return
+		}
+
+		category.PreSave()
+		if result.Err = category.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(category); err != nil {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Save", "store.sql_sidebar_category.save.app_error", nil, "id="+category.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = category
+		}
+	})
+}
+
+func (s SqlSidebarCategoryStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var category model.SidebarCategory
+
+		if err := s.GetReplica().SelectOne(&category, "SELECT * FROM SidebarCategories WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlSidebarCategoryStore.Get", "store.sql_sidebar_category.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+			} else {
+				result.Err = model.NewAppError("SqlSidebarCategoryStore.Get", "store.sql_sidebar_category.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result.Data = &category
+	})
+}
+
+// GetForTeam returns the categories that a user has in a team's sidebar, in the order that they're shown.
+func (s SqlSidebarCategoryStore) GetForTeam(userId string, teamId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		categories := []*model.SidebarCategory{}
+
+		if _, err := s.GetReplica().Select(&categories, "SELECT * FROM SidebarCategories WHERE UserId = :UserId AND TeamId = :TeamId ORDER BY SortOrder, CreateAt, Id", map[string]interface{}{"UserId": userId, "TeamId": teamId}); err != nil {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.GetForTeam", "store.sql_sidebar_category.get_for_team.app_error", nil, "user_id="+userId+", team_id="+teamId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = categories
+	})
+}
+
+func (s SqlSidebarCategoryStore) Update(category *model.SidebarCategory) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		category.PreUpdate()
+		if result.Err = category.IsValid(); result.Err != nil {
+			return
+		}
+
+		if _, err := s.GetMaster().Update(category); err != nil {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Update", "store.sql_sidebar_category.update.app_error", nil, "id="+category.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = category
+		}
+	})
+}
+
+func (s SqlSidebarCategoryStore) Delete(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM SidebarCategories WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Delete", "store.sql_sidebar_category.delete.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
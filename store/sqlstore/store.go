@@ -45,6 +45,7 @@ type SqlStore interface {
 	GetMaster() *gorp.DbMap
 	GetSearchReplica() *gorp.DbMap
 	GetReplica() *gorp.DbMap
+	GetReplicaFor(method string) *gorp.DbMap
 	TotalMasterDbConnections() int
 	TotalReadDbConnections() int
 	TotalSearchDbConnections() int
@@ -67,6 +68,7 @@ type SqlStore interface {
 	Close()
 	LockToMaster()
 	UnlockFromMaster()
+	PostEventsEnabled() bool
 	Team() store.TeamStore
 	Channel() store.ChannelStore
 	Post() store.PostStore
@@ -89,6 +91,21 @@ type SqlStore interface {
 	Reaction() store.ReactionStore
 	Job() store.JobStore
 	Plugin() store.PluginStore
+	EmailDigest() store.EmailDigestStore
+	WebPushSubscription() store.WebPushSubscriptionStore
+	ChannelBridge() store.ChannelBridgeStore
+	LinkMetadata() store.LinkMetadataStore
+	PostAcknowledgement() store.PostAcknowledgementStore
+	PostEvent() store.PostEventStore
+	ThreadMembership() store.ThreadMembershipStore
+	UserGroup() store.UserGroupStore
+	AutoResponderSchedule() store.AutoResponderScheduleStore
+	ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore
+	Reminder() store.ReminderStore
+	Bot() store.BotStore
+	IntegrationDelivery() store.IntegrationDeliveryStore
+	EventSubscription() store.EventSubscriptionStore
+	SidebarCategory() store.SidebarCategoryStore
 	UserAccessToken() store.UserAccessTokenStore
 	Role() store.RoleStore
 	Scheme() store.SchemeStore
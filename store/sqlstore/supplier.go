@@ -65,32 +65,47 @@ const (
 )
 
 type SqlSupplierOldStores struct {
-	team                 store.TeamStore
-	channel              store.ChannelStore
-	post                 store.PostStore
-	user                 store.UserStore
-	audit                store.AuditStore
-	cluster              store.ClusterDiscoveryStore
-	compliance           store.ComplianceStore
-	session              store.SessionStore
-	oauth                store.OAuthStore
-	system               store.SystemStore
-	webhook              store.WebhookStore
-	command              store.CommandStore
-	commandWebhook       store.CommandWebhookStore
-	preference           store.PreferenceStore
-	license              store.LicenseStore
-	token                store.TokenStore
-	emoji                store.EmojiStore
-	status               store.StatusStore
-	fileInfo             store.FileInfoStore
-	reaction             store.ReactionStore
-	job                  store.JobStore
-	userAccessToken      store.UserAccessTokenStore
-	plugin               store.PluginStore
-	channelMemberHistory store.ChannelMemberHistoryStore
-	role                 store.RoleStore
-	scheme               store.SchemeStore
+	team                  store.TeamStore
+	channel               store.ChannelStore
+	post                  store.PostStore
+	user                  store.UserStore
+	audit                 store.AuditStore
+	cluster               store.ClusterDiscoveryStore
+	compliance            store.ComplianceStore
+	session               store.SessionStore
+	oauth                 store.OAuthStore
+	system                store.SystemStore
+	webhook               store.WebhookStore
+	command               store.CommandStore
+	commandWebhook        store.CommandWebhookStore
+	preference            store.PreferenceStore
+	license               store.LicenseStore
+	token                 store.TokenStore
+	emoji                 store.EmojiStore
+	status                store.StatusStore
+	fileInfo              store.FileInfoStore
+	reaction              store.ReactionStore
+	job                   store.JobStore
+	userAccessToken       store.UserAccessTokenStore
+	plugin                store.PluginStore
+	channelMemberHistory  store.ChannelMemberHistoryStore
+	role                  store.RoleStore
+	scheme                store.SchemeStore
+	emailDigest           store.EmailDigestStore
+	webPushSubscription   store.WebPushSubscriptionStore
+	channelBridge         store.ChannelBridgeStore
+	linkMetadata          store.LinkMetadataStore
+	postAcknowledgement   store.PostAcknowledgementStore
+	postEvent             store.PostEventStore
+	threadMembership      store.ThreadMembershipStore
+	userGroup             store.UserGroupStore
+	autoResponderSchedule store.AutoResponderScheduleStore
+	channelNotifyDefaults store.ChannelNotifyDefaultsStore
+	reminder              store.ReminderStore
+	bot                   store.BotStore
+	integrationDelivery   store.IntegrationDeliveryStore
+	eventSubscription     store.EventSubscriptionStore
+	sidebarCategory       store.SidebarCategoryStore
 }
 
 type SqlSupplier struct {
@@ -105,6 +120,11 @@ type SqlSupplier struct {
 	oldStores      SqlSupplierOldStores
 	settings       *model.SqlSettings
 	lockedToMaster bool
+
+	// Whether each of the replicas and search replicas answered its last health check, which are updated atomically
+	replicaHealth           []int32
+	searchReplicaHealth     []int32
+	stopReplicaHealthChecks chan struct{}
 }
 
 func NewSqlSupplier(settings model.SqlSettings, metrics einterfaces.MetricsInterface) *SqlSupplier {
@@ -139,6 +159,21 @@ func NewSqlSupplier(settings model.SqlSettings, metrics einterfaces.MetricsInter
 	supplier.oldStores.userAccessToken = NewSqlUserAccessTokenStore(supplier)
 	supplier.oldStores.channelMemberHistory = NewSqlChannelMemberHistoryStore(supplier)
 	supplier.oldStores.plugin = NewSqlPluginStore(supplier)
+	supplier.oldStores.emailDigest = NewSqlEmailDigestStore(supplier)
+	supplier.oldStores.webPushSubscription = NewSqlWebPushSubscriptionStore(supplier)
+	supplier.oldStores.channelBridge = NewSqlChannelBridgeStore(supplier)
+	supplier.oldStores.linkMetadata = NewSqlLinkMetadataStore(supplier)
+	supplier.oldStores.postAcknowledgement = NewSqlPostAcknowledgementStore(supplier)
+	supplier.oldStores.postEvent = NewSqlPostEventStore(supplier)
+	supplier.oldStores.threadMembership = NewSqlThreadMembershipStore(supplier)
+	supplier.oldStores.userGroup = NewSqlUserGroupStore(supplier)
+	supplier.oldStores.autoResponderSchedule = NewSqlAutoResponderScheduleStore(supplier)
+	supplier.oldStores.channelNotifyDefaults = NewSqlChannelNotifyDefaultsStore(supplier)
+	supplier.oldStores.reminder = NewSqlReminderStore(supplier)
+	supplier.oldStores.bot = NewSqlBotStore(supplier)
+	supplier.oldStores.integrationDelivery = NewSqlIntegrationDeliveryStore(supplier)
+	supplier.oldStores.eventSubscription = NewSqlEventSubscriptionStore(supplier)
+	supplier.oldStores.sidebarCategory = NewSqlSidebarCategoryStore(supplier)
 
 	initSqlSupplierReactions(supplier)
 	initSqlSupplierRoles(supplier)
@@ -174,6 +209,21 @@ func NewSqlSupplier(settings model.SqlSettings, metrics einterfaces.MetricsInter
 	supplier.oldStores.job.(*SqlJobStore).CreateIndexesIfNotExists()
 	supplier.oldStores.userAccessToken.(*SqlUserAccessTokenStore).CreateIndexesIfNotExists()
 	supplier.oldStores.plugin.(*SqlPluginStore).CreateIndexesIfNotExists()
+	supplier.oldStores.emailDigest.(*SqlEmailDigestStore).CreateIndexesIfNotExists()
+	supplier.oldStores.webPushSubscription.(*SqlWebPushSubscriptionStore).CreateIndexesIfNotExists()
+	supplier.oldStores.channelBridge.(*SqlChannelBridgeStore).CreateIndexesIfNotExists()
+	supplier.oldStores.linkMetadata.(*SqlLinkMetadataStore).CreateIndexesIfNotExists()
+	supplier.oldStores.postAcknowledgement.(*SqlPostAcknowledgementStore).CreateIndexesIfNotExists()
+	supplier.oldStores.postEvent.(*SqlPostEventStore).CreateIndexesIfNotExists()
+	supplier.oldStores.threadMembership.(*SqlThreadMembershipStore).CreateIndexesIfNotExists()
+	supplier.oldStores.userGroup.(*SqlUserGroupStore).CreateIndexesIfNotExists()
+	supplier.oldStores.autoResponderSchedule.(*SqlAutoResponderScheduleStore).CreateIndexesIfNotExists()
+	supplier.oldStores.channelNotifyDefaults.(*SqlChannelNotifyDefaultsStore).CreateIndexesIfNotExists()
+	supplier.oldStores.reminder.(*SqlReminderStore).CreateIndexesIfNotExists()
+	supplier.oldStores.bot.(*SqlBotStore).CreateIndexesIfNotExists()
+	supplier.oldStores.integrationDelivery.(*SqlIntegrationDeliveryStore).CreateIndexesIfNotExists()
+	supplier.oldStores.eventSubscription.(*SqlEventSubscriptionStore).CreateIndexesIfNotExists()
+	supplier.oldStores.sidebarCategory.(*SqlSidebarCategoryStore).CreateIndexesIfNotExists()
 
 	supplier.oldStores.preference.(*SqlPreferenceStore).DeleteUnusedFeatures()
 
@@ -258,6 +308,77 @@ func (s *SqlSupplier) initConnection() {
 			s.searchReplicas[i] = setupConnection(fmt.Sprintf("search-replica-%v", i), replica, s.settings)
 		}
 	}
+
+	s.replicaHealth = newReplicaHealth(len(s.replicas))
+	s.searchReplicaHealth = newReplicaHealth(len(s.searchReplicas))
+
+	interval := s.settings.ReplicaHealthCheckIntervalSeconds
+	if interval != nil && *interval > 0 && len(s.replicas)+len(s.searchReplicas) > 0 {
+		s.stopReplicaHealthChecks = make(chan struct{})
+		go s.checkReplicaHealth(time.Duration(*interval) * time.Second)
+	}
+}
+
+func newReplicaHealth(count int) []int32 {
+	health := make([]int32, count)
+	for i := range health {
+		health[i] = 1
+	}
+	return health
+}
+
+// checkReplicaHealth pings the replicas periodically so that reads are only sent to those that are available, and are
+// sent to them again once they recover.
+func (s *SqlSupplier) checkReplicaHealth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			updateReplicaHealth("replica", s.replicas, s.replicaHealth)
+			updateReplicaHealth("search-replica", s.searchReplicas, s.searchReplicaHealth)
+		case <-s.stopReplicaHealthChecks:
+			return
+		}
+	}
+}
+
+func updateReplicaHealth(conType string, replicas []*gorp.DbMap, health []int32) {
+	for i, replica := range replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), DB_PING_TIMEOUT_SECS*time.Second)
+		err := replica.Db.PingContext(ctx)
+		cancel()
+
+		var healthy int32
+		if err == nil {
+			healthy = 1
+		}
+
+		if atomic.SwapInt32(&health[i], healthy) == healthy {
+			continue
+		}
+
+		name := fmt.Sprintf("%v-%v", conType, i)
+		if err != nil {
+			mlog.Warn("Unable to reach a database replica, so reads will be sent elsewhere until it recovers", mlog.String("replica", name), mlog.Err(err))
+		} else {
+			mlog.Info("A database replica has recovered and will be sent reads again", mlog.String("replica", name))
+		}
+	}
+}
+
+// pickReplica chooses the next of the replicas that passed their last health check, or returns nil if none did.
+func pickReplica(replicas []*gorp.DbMap, health []int32, counter *int64) *gorp.DbMap {
+	start := atomic.AddInt64(counter, 1)
+	for i := int64(0); i < int64(len(replicas)); i++ {
+		rrNum := (start + i) % int64(len(replicas))
+		if atomic.LoadInt32(&health[rrNum]) == 1 {
+			return replicas[rrNum]
+		}
+	}
+
+	return nil
 }
 
 func (ss *SqlSupplier) DriverName() string {
@@ -273,22 +394,77 @@ func (ss *SqlSupplier) GetMaster() *gorp.DbMap {
 	return ss.master
 }
 
+// GetSearchReplica returns one of the search replicas, falling back to the other replicas when none are configured or
+// available.
 func (ss *SqlSupplier) GetSearchReplica() *gorp.DbMap {
 	if len(ss.settings.DataSourceSearchReplicas) == 0 {
 		return ss.GetReplica()
 	}
 
-	rrNum := atomic.AddInt64(&ss.srCounter, 1) % int64(len(ss.searchReplicas))
-	return ss.searchReplicas[rrNum]
+	if replica := pickReplica(ss.searchReplicas, ss.searchReplicaHealth, &ss.srCounter); replica != nil {
+		return replica
+	}
+
+	return ss.GetReplica()
 }
 
+// GetReplica returns one of the replicas for reads that can tolerate replication lag, falling back to the master when
+// none are configured or available.
 func (ss *SqlSupplier) GetReplica() *gorp.DbMap {
 	if len(ss.settings.DataSourceReplicas) == 0 || ss.lockedToMaster {
 		return ss.GetMaster()
 	}
 
-	rrNum := atomic.AddInt64(&ss.rrCounter, 1) % int64(len(ss.replicas))
-	return ss.replicas[rrNum]
+	if replica := pickReplica(ss.replicas, ss.replicaHealth, &ss.rrCounter); replica != nil {
+		return replica
+	}
+
+	return ss.GetMaster()
+}
+
+// The store methods that read a lot and can tolerate replication lag, which are designated to read from the replicas
+// whenever any are available.
+var replicaReadMethods = map[string]bool{
+	"Channel.AnalyticsDeletedTypeCount":      true,
+	"Channel.AnalyticsTypeCount":             true,
+	"Channel.AutocompleteInTeam":             true,
+	"Command.AnalyticsCommandCount":          true,
+	"Post.AnalyticsPostCount":                true,
+	"Post.AnalyticsPostCountsByDay":          true,
+	"Post.AnalyticsUserCountsWithPostsByDay": true,
+	"Post.GetPosts":                          true,
+	"Post.GetPostsAround":                    true,
+	"Post.GetPostsByCursor":                  true,
+	"Post.GetPostsSince":                     true,
+	"Session.AnalyticsSessionCount":          true,
+	"Team.AnalyticsGetTeamCountForScheme":    true,
+	"Team.AnalyticsTeamCount":                true,
+	"User.AnalyticsActiveCount":              true,
+	"User.AnalyticsGetInactiveUsersCount":    true,
+	"User.AnalyticsGetSystemAdminCount":      true,
+	"User.AnalyticsUniqueUserCount":          true,
+	"User.Search":                            true,
+	"Webhook.AnalyticsIncomingCount":         true,
+	"Webhook.AnalyticsOutgoingCount":         true,
+}
+
+// GetReplicaFor returns the connection that the named store method should read from. Designated methods read from one
+// of the available replicas even while the store is locked to the master, and only fall back to the master when none
+// are configured or available. Every other method reads from GetReplica.
+func (ss *SqlSupplier) GetReplicaFor(method string) *gorp.DbMap {
+	if !replicaReadMethods[method] {
+		return ss.GetReplica()
+	}
+
+	if len(ss.settings.DataSourceReplicas) == 0 {
+		return ss.GetMaster()
+	}
+
+	if replica := pickReplica(ss.replicas, ss.replicaHealth, &ss.rrCounter); replica != nil {
+		return replica
+	}
+
+	return ss.GetMaster()
 }
 
 func (ss *SqlSupplier) TotalMasterDbConnections() int {
@@ -795,6 +971,9 @@ func (ss *SqlSupplier) GetAllConns() []*gorp.DbMap {
 
 func (ss *SqlSupplier) Close() {
 	mlog.Info("Closing SqlStore")
+	if ss.stopReplicaHealthChecks != nil {
+		close(ss.stopReplicaHealthChecks)
+	}
 	ss.master.Db.Close()
 	for _, replica := range ss.replicas {
 		replica.Db.Close()
@@ -809,6 +988,11 @@ func (ss *SqlSupplier) UnlockFromMaster() {
 	ss.lockedToMaster = false
 }
 
+// PostEventsEnabled returns whether changes to posts are recorded in the post event log.
+func (ss *SqlSupplier) PostEventsEnabled() bool {
+	return ss.settings.EnablePostEvents != nil && *ss.settings.EnablePostEvents
+}
+
 func (ss *SqlSupplier) Team() store.TeamStore {
 	return ss.oldStores.team
 }
@@ -905,6 +1089,66 @@ func (ss *SqlSupplier) Plugin() store.PluginStore {
 	return ss.oldStores.plugin
 }
 
+func (ss *SqlSupplier) EmailDigest() store.EmailDigestStore {
+	return ss.oldStores.emailDigest
+}
+
+func (ss *SqlSupplier) WebPushSubscription() store.WebPushSubscriptionStore {
+	return ss.oldStores.webPushSubscription
+}
+
+func (ss *SqlSupplier) ChannelBridge() store.ChannelBridgeStore {
+	return ss.oldStores.channelBridge
+}
+
+func (ss *SqlSupplier) LinkMetadata() store.LinkMetadataStore {
+	return ss.oldStores.linkMetadata
+}
+
+func (ss *SqlSupplier) PostAcknowledgement() store.PostAcknowledgementStore {
+	return ss.oldStores.postAcknowledgement
+}
+
+func (ss *SqlSupplier) PostEvent() store.PostEventStore {
+	return ss.oldStores.postEvent
+}
+
+func (ss *SqlSupplier) ThreadMembership() store.ThreadMembershipStore {
+	return ss.oldStores.threadMembership
+}
+
+func (ss *SqlSupplier) UserGroup() store.UserGroupStore {
+	return ss.oldStores.userGroup
+}
+
+func (ss *SqlSupplier) AutoResponderSchedule() store.AutoResponderScheduleStore {
+	return ss.oldStores.autoResponderSchedule
+}
+
+func (ss *SqlSupplier) ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore {
+	return ss.oldStores.channelNotifyDefaults
+}
+
+func (ss *SqlSupplier) Reminder() store.ReminderStore {
+	return ss.oldStores.reminder
+}
+
+func (ss *SqlSupplier) Bot() store.BotStore {
+	return ss.oldStores.bot
+}
+
+func (ss *SqlSupplier) IntegrationDelivery() store.IntegrationDeliveryStore {
+	return ss.oldStores.integrationDelivery
+}
+
+func (ss *SqlSupplier) EventSubscription() store.EventSubscriptionStore {
+	return ss.oldStores.eventSubscription
+}
+
+func (ss *SqlSupplier) SidebarCategory() store.SidebarCategoryStore {
+	return ss.oldStores.sidebarCategory
+}
+
 func (ss *SqlSupplier) Role() store.RoleStore {
 	return ss.oldStores.role
 }
@@ -932,6 +1176,16 @@ func (me mattermConverter) ToDb(val interface{}) (interface{}, error) {
 		return model.StringInterfaceToJson(t), nil
 	case map[string]interface{}:
 		return model.StringInterfaceToJson(model.StringInterface(t)), nil
+	case *model.AutocompleteData:
+		if t == nil {
+			return "", nil
+		}
+		return t.ToJson(), nil
+	case *model.TeamUploadPolicy:
+		if t == nil {
+			return "", nil
+		}
+		return t.ToJson(), nil
 	}
 
 	return val, nil
@@ -989,6 +1243,32 @@ func (me mattermConverter) FromDb(target interface{}) (gorp.CustomScanner, bool)
 			return json.Unmarshal(b, target)
 		}
 		return gorp.CustomScanner{Holder: new(string), Target: target, Binder: binder}, true
+	case **model.AutocompleteData:
+		binder := func(holder, target interface{}) error {
+			s, ok := holder.(*dbsql.NullString)
+			if !ok {
+				return errors.New(utils.T("store.sql.convert_autocomplete_data"))
+			}
+			// Commands that were created before autocomplete data existed have none
+			if !s.Valid || s.String == "" {
+				return nil
+			}
+			return json.Unmarshal([]byte(s.String), target)
+		}
+		return gorp.CustomScanner{Holder: new(dbsql.NullString), Target: target, Binder: binder}, true
+	case **model.TeamUploadPolicy:
+		binder := func(holder, target interface{}) error {
+			s, ok := holder.(*dbsql.NullString)
+			if !ok {
+				return errors.New(utils.T("store.sql.convert_team_upload_policy"))
+			}
+			// Teams without a policy allow every file
+			if !s.Valid || s.String == "" {
+				return nil
+			}
+			return json.Unmarshal([]byte(s.String), target)
+		}
+		return gorp.CustomScanner{Holder: new(dbsql.NullString), Target: target, Binder: binder}, true
 	}
 
 	return gorp.CustomScanner{}, false
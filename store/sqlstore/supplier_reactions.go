@@ -35,6 +35,9 @@ func (s *SqlSupplier) ReactionSave(ctx context.Context, reaction *model.Reaction
 		result.Err = model.NewAppError("SqlReactionStore.Save", "store.sql_reaction.save.begin.app_error", nil, err.Error(), http.StatusInternalServerError)
 	} else {
 		err := saveReactionAndUpdatePost(transaction, reaction)
+		if err == nil && s.PostEventsEnabled() {
+			err = savePostEvent(transaction, model.NewReactionPostEvent(model.POST_EVENT_TYPE_REACTION_ADDED, reaction))
+		}
 
 		if err != nil {
 			transaction.Rollback()
@@ -65,6 +68,9 @@ func (s *SqlSupplier) ReactionDelete(ctx context.Context, reaction *model.Reacti
 		result.Err = model.NewAppError("SqlReactionStore.Delete", "store.sql_reaction.delete.begin.app_error", nil, err.Error(), http.StatusInternalServerError)
 	} else {
 		err := deleteReactionAndUpdatePost(transaction, reaction)
+		if err == nil && s.PostEventsEnabled() {
+			err = savePostEvent(transaction, model.NewReactionPostEvent(model.POST_EVENT_TYPE_REACTION_REMOVED, reaction))
+		}
 
 		if err != nil {
 			transaction.Rollback()
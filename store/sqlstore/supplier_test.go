@@ -2,10 +2,12 @@ package sqlstore_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mattermost/gorp"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/store/sqlstore"
@@ -231,3 +233,121 @@ func TestGetAllConns(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReplicaSkipsUnavailableReplicas(t *testing.T) {
+	t.Parallel()
+
+	driverName := model.DATABASE_DRIVER_SQLITE
+	dataSource := ":memory:"
+	maxIdleConns := 1
+	connMaxLifetimeMilliseconds := 3600000
+	maxOpenConns := 1
+	queryTimeout := 5
+	replicaHealthCheckIntervalSeconds := 1
+
+	settings := model.SqlSettings{
+		DriverName:                        &driverName,
+		DataSource:                        &dataSource,
+		MaxIdleConns:                      &maxIdleConns,
+		ConnMaxLifetimeMilliseconds:       &connMaxLifetimeMilliseconds,
+		MaxOpenConns:                      &maxOpenConns,
+		QueryTimeout:                      &queryTimeout,
+		DataSourceReplicas:                []string{":memory:", ":memory:"},
+		DataSourceSearchReplicas:          []string{":memory:"},
+		ReplicaHealthCheckIntervalSeconds: &replicaHealthCheckIntervalSeconds,
+	}
+	supplier := sqlstore.NewSqlSupplier(settings, nil)
+	defer supplier.Close()
+
+	// The replicas are checked every second, so wait for a few checks
+	waitFor := func(condition func() bool, message string) {
+		t.Helper()
+
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+			if condition() {
+				return
+			}
+		}
+
+		t.Fatal(message)
+	}
+
+	unavailable := supplier.GetReplica()
+	require.NotEqual(t, supplier.GetMaster(), unavailable)
+	unavailable.Db.Close()
+
+	waitFor(func() bool {
+		for i := 0; i < 4; i++ {
+			if supplier.GetReplica() == unavailable {
+				return false
+			}
+		}
+		return true
+	}, "should stop reading from the unavailable replica")
+
+	available := supplier.GetReplica()
+	assert.NotEqual(t, supplier.GetMaster(), available)
+	available.Db.Close()
+
+	waitFor(func() bool {
+		return supplier.GetReplica() == supplier.GetMaster()
+	}, "should read from the master once no replicas are available")
+
+	searchReplica := supplier.GetSearchReplica()
+	assert.NotEqual(t, supplier.GetMaster(), searchReplica)
+	searchReplica.Db.Close()
+
+	waitFor(func() bool {
+		return supplier.GetSearchReplica() == supplier.GetMaster()
+	}, "should fall back from the search replicas as well")
+}
+
+func TestGetReplicaFor(t *testing.T) {
+	t.Parallel()
+
+	driverName := model.DATABASE_DRIVER_SQLITE
+	dataSource := ":memory:"
+	maxIdleConns := 1
+	connMaxLifetimeMilliseconds := 3600000
+	maxOpenConns := 1
+	queryTimeout := 5
+
+	newSupplier := func(replicas []string) *sqlstore.SqlSupplier {
+		return sqlstore.NewSqlSupplier(model.SqlSettings{
+			DriverName:                  &driverName,
+			DataSource:                  &dataSource,
+			MaxIdleConns:                &maxIdleConns,
+			ConnMaxLifetimeMilliseconds: &connMaxLifetimeMilliseconds,
+			MaxOpenConns:                &maxOpenConns,
+			QueryTimeout:                &queryTimeout,
+			DataSourceReplicas:          replicas,
+		}, nil)
+	}
+
+	t.Run("designated methods read from the replicas", func(t *testing.T) {
+		supplier := newSupplier([]string{":memory:"})
+		defer supplier.Close()
+
+		assert.NotEqual(t, supplier.GetMaster(), supplier.GetReplicaFor("Post.GetPosts"))
+		assert.NotEqual(t, supplier.GetMaster(), supplier.GetReplicaFor("User.Search"))
+	})
+
+	t.Run("designated methods read from the replicas while locked to the master", func(t *testing.T) {
+		supplier := newSupplier([]string{":memory:"})
+		defer supplier.Close()
+
+		supplier.LockToMaster()
+		defer supplier.UnlockFromMaster()
+
+		assert.Equal(t, supplier.GetMaster(), supplier.GetReplica())
+		assert.NotEqual(t, supplier.GetMaster(), supplier.GetReplicaFor("Post.GetPosts"))
+		assert.Equal(t, supplier.GetMaster(), supplier.GetReplicaFor("Post.Get"), "other methods should follow GetReplica")
+	})
+
+	t.Run("designated methods read from the master without replicas", func(t *testing.T) {
+		supplier := newSupplier(nil)
+		defer supplier.Close()
+
+		assert.Equal(t, supplier.GetMaster(), supplier.GetReplicaFor("Post.GetPosts"))
+	})
+}
@@ -145,6 +145,7 @@ func NewSqlTeamStore(sqlStore SqlStore) store.TeamStore {
 		table.ColMap("CompanyName").SetMaxSize(64)
 		table.ColMap("AllowedDomains").SetMaxSize(500)
 		table.ColMap("InviteId").SetMaxSize(32)
+		table.ColMap("UploadPolicy").SetMaxSize(model.TEAM_UPLOAD_POLICY_MAX_SIZE)
 
 		tablem := db.AddTableWithName(teamMember{}, "TeamMembers").SetKeys(false, "TeamId", "UserId")
 		tablem.ColMap("TeamId").SetMaxSize(26)
@@ -455,7 +456,7 @@ func (s SqlTeamStore) PermanentDelete(teamId string) store.StoreChannel {
 
 func (s SqlTeamStore) AnalyticsTeamCount() store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
-		c, err := s.GetReplica().SelectInt("SELECT COUNT(*) FROM Teams WHERE DeleteAt = 0", map[string]interface{}{})
+		c, err := s.GetReplicaFor("Team.AnalyticsTeamCount").SelectInt("SELECT COUNT(*) FROM Teams WHERE DeleteAt = 0", map[string]interface{}{})
 		if err != nil {
 			result.Err = model.NewAppError("SqlTeamStore.AnalyticsTeamCount", "store.sql_team.analytics_team_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 			return
@@ -916,7 +917,7 @@ func (s SqlTeamStore) ClearAllCustomRoleAssignments() store.StoreChannel {
 
 func (s SqlTeamStore) AnalyticsGetTeamCountForScheme(schemeId string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
-		count, err := s.GetReplica().SelectInt("SELECT count(*) FROM Teams WHERE SchemeId = :SchemeId AND DeleteAt = 0", map[string]interface{}{"SchemeId": schemeId})
+		count, err := s.GetReplicaFor("Team.AnalyticsGetTeamCountForScheme").SelectInt("SELECT count(*) FROM Teams WHERE SchemeId = :SchemeId AND DeleteAt = 0", map[string]interface{}{"SchemeId": schemeId})
 		if err != nil {
 			result.Err = model.NewAppError("SqlTeamStore.AnalyticsGetTeamCountForScheme", "store.sql_team.analytics_get_team_count_for_scheme.app_error", nil, "schemeId="+schemeId+" "+err.Error(), http.StatusInternalServerError)
 			return
@@ -924,3 +925,16 @@ func (s SqlTeamStore) AnalyticsGetTeamCountForScheme(schemeId string) store.Stor
 		result.Data = count
 	})
 }
+
+// GetScheduledForDeletion returns the archived teams whose deletion grace period ended at or before the given time.
+func (s SqlTeamStore) GetScheduledForDeletion(before int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var teams []*model.Team
+		if _, err := s.GetReplica().Select(&teams, "SELECT * FROM Teams WHERE DeleteAt != 0 AND ScheduledDeleteAt != 0 AND ScheduledDeleteAt <= :Before ORDER BY ScheduledDeleteAt ASC", map[string]interface{}{"Before": before}); err != nil {
+			result.Err = model.NewAppError("SqlTeamStore.GetScheduledForDeletion", "store.sql_team.get_scheduled_for_deletion.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = teams
+	})
+}
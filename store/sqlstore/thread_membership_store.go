@@ -0,0 +1,123 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// Selects threads along with how many replies each member hasn't seen, not counting their own replies
+const THREAD_SELECT_QUERY = `SELECT
+		ThreadMemberships.*,
+		(SELECT COUNT(*) FROM Posts
+			WHERE Posts.RootId = ThreadMemberships.PostId
+			AND Posts.CreateAt > ThreadMemberships.LastViewed
+			AND Posts.UserId != ThreadMemberships.UserId
+			AND Posts.DeleteAt = 0) AS UnreadReplies
+	FROM ThreadMemberships`
+
+type SqlThreadMembershipStore struct {
+	SqlStore
+}
+
+func NewSqlThreadMembershipStore(sqlStore SqlStore) store.ThreadMembershipStore {
+	s := &SqlThreadMembershipStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.ThreadMembership{}, "ThreadMemberships").SetKeys(false, "PostId", "UserId")
+		table.ColMap("PostId").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlThreadMembershipStore) CreateIndexesIfNotExists() {
+	s.CreateCompositeIndexIfNotExists("idx_thread_memberships_user_id_last_updated", "ThreadMemberships", []string{"UserId", "LastUpdated"})
+}
+
+// Save stores a user's membership of a thread, replacing their previous membership of it.
+func (s SqlThreadMembershipStore) Save(membership *model.ThreadMembership) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		membership.PreSave()
+		if result.Err = membership.IsValid(); result.Err != nil {
+			return
+		}
+
+		count, err := s.GetMaster().Update(membership)
+		if err == nil && count == 0 {
+			err = s.GetMaster().Insert(membership)
+
+			// MySQL doesn't count rows that were left unchanged as updated, and the user may also have joined the
+			// thread from another request in the meantime
+			if err != nil && IsUniqueConstraintError(err, []string{"PRIMARY", "threadmemberships_pkey"}) {
+				_, err = s.GetMaster().Update(membership)
+			}
+		}
+
+		if err != nil {
+			result.Err = model.NewAppError("SqlThreadMembershipStore.Save", "store.sql_thread_membership.save.app_error", nil, "user_id="+membership.UserId+", post_id="+membership.PostId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = membership
+		}
+	})
+}
+
+func (s SqlThreadMembershipStore) Get(userId string, postId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var membership model.ThreadMembership
+		if err := s.GetMaster().SelectOne(&membership, "SELECT * FROM ThreadMemberships WHERE PostId = :PostId AND UserId = :UserId", map[string]interface{}{"PostId": postId, "UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlThreadMembershipStore.Get", "store.sql_thread_membership.get.app_error", nil, "user_id="+userId+", post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &membership
+		}
+	})
+}
+
+// GetThreadsForUser returns the threads that a user follows, most recently updated first.
+func (s SqlThreadMembershipStore) GetThreadsForUser(userId string, offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := THREAD_SELECT_QUERY + `
+			WHERE ThreadMemberships.UserId = :UserId AND ThreadMemberships.Following = :Following
+			ORDER BY ThreadMemberships.LastUpdated DESC
+			LIMIT :Limit OFFSET :Offset`
+
+		var threads []*model.Thread
+		if _, err := s.GetReplica().Select(&threads, query, map[string]interface{}{"UserId": userId, "Following": true, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlThreadMembershipStore.GetThreadsForUser", "store.sql_thread_membership.get_threads_for_user.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = threads
+		}
+	})
+}
+
+// GetThreadsForPost returns the thread as seen by each of its members, including those who have stopped following it.
+func (s SqlThreadMembershipStore) GetThreadsForPost(postId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := THREAD_SELECT_QUERY + " WHERE ThreadMemberships.PostId = :PostId"
+
+		// This runs right after a reply is saved, so it can't wait for replicas to catch up
+		var threads []*model.Thread
+		if _, err := s.GetMaster().Select(&threads, query, map[string]interface{}{"PostId": postId}); err != nil {
+			result.Err = model.NewAppError("SqlThreadMembershipStore.GetThreadsForPost", "store.sql_thread_membership.get_threads_for_post.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = threads
+		}
+	})
+}
+
+func (s SqlThreadMembershipStore) UpdateLastUpdated(postId string, lastUpdated int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("UPDATE ThreadMemberships SET LastUpdated = :LastUpdated WHERE PostId = :PostId", map[string]interface{}{"PostId": postId, "LastUpdated": lastUpdated}); err != nil {
+			result.Err = model.NewAppError("SqlThreadMembershipStore.UpdateLastUpdated", "store.sql_thread_membership.update_last_updated.app_error", nil, "post_id="+postId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -488,6 +488,27 @@ func UpgradeDatabaseToVersion53(sqlStore SqlStore) {
 	// if shouldPerformUpgrade(sqlStore, VERSION_5_2_0, VERSION_5_3_0) {
 	sqlStore.AlterColumnTypeIfExists("OutgoingWebhooks", "Description", "varchar(500)", "varchar(500)")
 	sqlStore.AlterColumnTypeIfExists("IncomingWebhooks", "Description", "varchar(500)", "varchar(500)")
+	sqlStore.CreateColumnIfNotExists("Teams", "ScheduledDeleteAt", "bigint", "bigint", "0")
+	sqlStore.CreateColumnIfNotExists("Teams", "ArchivedAt", "bigint", "bigint", "0")
+	sqlStore.CreateColumnIfNotExistsNoDefault("Posts", "RemoteId", "varchar(128)", "varchar(128)")
+	sqlStore.CreateColumnIfNotExistsNoDefault("Users", "RemoteId", "varchar(128)", "varchar(128)")
+	sqlStore.CreateColumnIfNotExistsNoDefault("Channels", "RemoteId", "varchar(128)", "varchar(128)")
+	sqlStore.CreateColumnIfNotExists("Channels", "ReadReceipts", "boolean", "boolean", "0")
+	sqlStore.CreateColumnIfNotExists("IncomingWebhooks", "RateLimitPerMinute", "int", "integer", "0")
+	sqlStore.CreateColumnIfNotExists("IncomingWebhooks", "RateLimitBurst", "int", "integer", "0")
+	sqlStore.CreateColumnIfNotExistsNoDefault("Commands", "AutocompleteData", "text", "text")
+	sqlStore.CreateColumnIfNotExistsNoDefault("Teams", "UploadPolicy", "text", "text")
+	sqlStore.CreateColumnIfNotExists("Users", "IsBot", "boolean", "boolean", "0")
+	sqlStore.CreateColumnIfNotExists("OAuthApps", "IsPublic", "boolean", "boolean", "0")
+	sqlStore.CreateColumnIfNotExists("OAuthApps", "LastIconUpdate", "bigint", "bigint", "0")
+	sqlStore.CreateColumnIfNotExists("OAuthAuthData", "CodeChallenge", "varchar(128)", "varchar(128)", "")
+	sqlStore.CreateColumnIfNotExists("OAuthAuthData", "CodeChallengeMethod", "varchar(16)", "varchar(16)", "")
+	sqlStore.CreateColumnIfNotExists("OutgoingWebhooks", "SigningSecret", "varchar(128)", "varchar(128)", "")
+	sqlStore.CreateColumnIfNotExists("OutgoingWebhooks", "TriggerEvents", "varchar(1024)", "varchar(1024)", "[]")
+	sqlStore.CreateColumnIfNotExists("CommandWebhooks", "EphemeralPostId", "varchar(26)", "varchar(26)", "")
+	sqlStore.CreateColumnIfNotExistsNoDefault("CommandWebhooks", "EphemeralMessage", "text", "text")
+	sqlStore.CreateColumnIfNotExists("IncomingWebhooks", "PayloadTemplate", "varchar(4000)", "varchar(4000)", "")
+	sqlStore.CreateColumnIfNotExists("PluginKeyValueStore", "ExpireAt", "bigint", "bigint", "0")
 	// 	saveSchemaVersion(sqlStore, VERSION_5_3_0)
 	// }
 }
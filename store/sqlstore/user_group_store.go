@@ -0,0 +1,225 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlUserGroupStore struct {
+	SqlStore
+}
+
+func NewSqlUserGroupStore(sqlStore SqlStore) store.UserGroupStore {
+	s := &SqlUserGroupStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.UserGroup{}, "UserGroups").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("Name").SetMaxSize(model.USER_NAME_MAX_LENGTH).SetUnique(true)
+		table.ColMap("DisplayName").SetMaxSize(model.USER_GROUP_DISPLAY_NAME_MAX_RUNES)
+		table.ColMap("Description").SetMaxSize(model.USER_GROUP_DESCRIPTION_MAX_RUNES)
+		table.ColMap("CreatorId").SetMaxSize(26)
+
+		tableMembers := db.AddTableWithName(model.UserGroupMember{}, "UserGroupMembers").SetKeys(false, "GroupId", "UserId")
+		tableMembers.ColMap("GroupId").SetMaxSize(26)
+		tableMembers.ColMap("UserId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlUserGroupStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_user_group_members_user_id", "UserGroupMembers", "UserId")
+}
+
+func (s SqlUserGroupStore) Save(group *model.UserGroup) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(group.Id) > 0 {
+			result.Err = model.NewAppError("SqlUserGroupStore.Save", "store.sql_user_group.save.existing.app_error", nil, "id="+group.Id, http.StatusBadRequest)
+			return
+		}
+
+		group.PreSave()
+		if result.Err = group.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(group); err != nil {
+			if IsUniqueConstraintError(err, []string{"Name", "usergroups_name_key"}) {
+				result.Err = model.NewAppError("SqlUserGroupStore.Save", "store.sql_user_group.save.name_exists.app_error", nil, "name="+group.Name+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlUserGroupStore.Save", "store.sql_user_group.save.app_error", nil, "id="+group.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
+		} else {
+			result.Data = group
+		}
+	})
+}
+
+func (s SqlUserGroupStore) Update(group *model.UserGroup) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		group.PreUpdate()
+		if result.Err = group.IsValid(); result.Err != nil {
+			return
+		}
+
+		if count, err := s.GetMaster().Update(group); err != nil {
+			if IsUniqueConstraintError(err, []string{"Name", "usergroups_name_key"}) {
+				result.Err = model.NewAppError("SqlUserGroupStore.Update", "store.sql_user_group.save.name_exists.app_error", nil, "name="+group.Name+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewAppError("SqlUserGroupStore.Update", "store.sql_user_group.update.app_error", nil, "id="+group.Id+", "+err.Error(), http.StatusInternalServerError)
+			}
+		} else if count != 1 {
+			result.Err = model.NewAppError("SqlUserGroupStore.Update", "store.sql_user_group.get.app_error", nil, "id="+group.Id, http.StatusNotFound)
+		} else {
+			result.Data = group
+		}
+	})
+}
+
+func (s SqlUserGroupStore) Get(groupId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var group model.UserGroup
+		if err := s.GetReplica().SelectOne(&group, "SELECT * FROM UserGroups WHERE Id = :Id", map[string]interface{}{"Id": groupId}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.Get", "store.sql_user_group.get.app_error", nil, "id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &group
+		}
+	})
+}
+
+func (s SqlUserGroupStore) GetByName(name string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var group model.UserGroup
+		if err := s.GetReplica().SelectOne(&group, "SELECT * FROM UserGroups WHERE Name = :Name", map[string]interface{}{"Name": name}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.GetByName", "store.sql_user_group.get.app_error", nil, "name="+name+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &group
+		}
+	})
+}
+
+func (s SqlUserGroupStore) GetAll(offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var groups []*model.UserGroup
+		if _, err := s.GetReplica().Select(&groups, "SELECT * FROM UserGroups ORDER BY Name ASC LIMIT :Limit OFFSET :Offset", map[string]interface{}{"Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.GetAll", "store.sql_user_group.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = groups
+		}
+	})
+}
+
+// Delete deletes a group along with its memberships so that its name can be used again straight away.
+func (s SqlUserGroupStore) Delete(groupId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		transaction, err := s.GetMaster().Begin()
+		if err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.Delete", "store.sql_user_group.delete.app_error", nil, "id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if _, err := transaction.Exec("DELETE FROM UserGroupMembers WHERE GroupId = :GroupId", map[string]interface{}{"GroupId": groupId}); err != nil {
+			transaction.Rollback()
+			result.Err = model.NewAppError("SqlUserGroupStore.Delete", "store.sql_user_group.delete.app_error", nil, "id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if _, err := transaction.Exec("DELETE FROM UserGroups WHERE Id = :Id", map[string]interface{}{"Id": groupId}); err != nil {
+			transaction.Rollback()
+			result.Err = model.NewAppError("SqlUserGroupStore.Delete", "store.sql_user_group.delete.app_error", nil, "id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if err := transaction.Commit(); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.Delete", "store.sql_user_group.delete.app_error", nil, "id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// SaveMember adds a user to a group. Adding a user who's already a member returns their existing membership.
+func (s SqlUserGroupStore) SaveMember(member *model.UserGroupMember) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		member.PreSave()
+		if result.Err = member.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(member); err != nil {
+			if !IsUniqueConstraintError(err, []string{"PRIMARY", "usergroupmembers_pkey"}) {
+				result.Err = model.NewAppError("SqlUserGroupStore.SaveMember", "store.sql_user_group.save_member.app_error", nil, "group_id="+member.GroupId+", user_id="+member.UserId+", "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			var existing model.UserGroupMember
+			if err := s.GetMaster().SelectOne(&existing, "SELECT * FROM UserGroupMembers WHERE GroupId = :GroupId AND UserId = :UserId", map[string]interface{}{"GroupId": member.GroupId, "UserId": member.UserId}); err != nil {
+				result.Err = model.NewAppError("SqlUserGroupStore.SaveMember", "store.sql_user_group.save_member.app_error", nil, "group_id="+member.GroupId+", user_id="+member.UserId+", "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			member = &existing
+		}
+
+		result.Data = member
+	})
+}
+
+func (s SqlUserGroupStore) DeleteMember(groupId string, userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM UserGroupMembers WHERE GroupId = :GroupId AND UserId = :UserId", map[string]interface{}{"GroupId": groupId, "UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.DeleteMember", "store.sql_user_group.delete_member.app_error", nil, "group_id="+groupId+", user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// GetMembers returns a page of the members of a group in the order that they were added.
+func (s SqlUserGroupStore) GetMembers(groupId string, offset int, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var members []*model.UserGroupMember
+		if _, err := s.GetReplica().Select(&members, "SELECT * FROM UserGroupMembers WHERE GroupId = :GroupId ORDER BY CreateAt ASC, UserId ASC LIMIT :Limit OFFSET :Offset", map[string]interface{}{"GroupId": groupId, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.GetMembers", "store.sql_user_group.get_members.app_error", nil, "group_id="+groupId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = members
+		}
+	})
+}
+
+// GetMemberIdsInChannel returns the ids of the members of each group who are in the given channel, keyed by the name
+// of the group. Groups without any members in the channel are left out.
+func (s SqlUserGroupStore) GetMemberIdsInChannel(channelId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		query := `SELECT UserGroups.Name, UserGroupMembers.UserId
+			FROM UserGroups
+				INNER JOIN UserGroupMembers ON UserGroupMembers.GroupId = UserGroups.Id
+				INNER JOIN ChannelMembers ON ChannelMembers.UserId = UserGroupMembers.UserId
+			WHERE ChannelMembers.ChannelId = :ChannelId`
+
+		var rows []struct {
+			Name   string
+			UserId string
+		}
+		if _, err := s.GetReplica().Select(&rows, query, map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewAppError("SqlUserGroupStore.GetMemberIdsInChannel", "store.sql_user_group.get_member_ids_in_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		memberIds := make(map[string][]string)
+		for _, row := range rows {
+			memberIds[row.Name] = append(memberIds[row.Name], row.UserId)
+		}
+
+		result.Data = memberIds
+	})
+}
@@ -37,8 +37,8 @@ type SqlUserStore struct {
 	metrics einterfaces.MetricsInterface
 }
 
-var profilesInChannelCache *utils.Cache = utils.NewLru(PROFILES_IN_CHANNEL_CACHE_SIZE)
-var profileByIdsCache *utils.Cache = utils.NewLru(PROFILE_BY_IDS_CACHE_SIZE)
+var profilesInChannelCache utils.ObjectCache = utils.NewLru(PROFILES_IN_CHANNEL_CACHE_SIZE)
+var profileByIdsCache utils.ObjectCache = utils.NewLru(PROFILE_BY_IDS_CACHE_SIZE)
 
 func (us SqlUserStore) ClearCaches() {
 	profilesInChannelCache.Purge()
@@ -82,6 +82,7 @@ func NewSqlUserStore(sqlStore SqlStore, metrics einterfaces.MetricsInterface) st
 		table.ColMap("MfaSecret").SetMaxSize(128)
 		table.ColMap("Position").SetMaxSize(128)
 		table.ColMap("Timezone").SetMaxSize(256)
+		table.ColMap("RemoteId").SetMaxSize(model.REMOTE_ID_MAX_LENGTH)
 	}
 
 	return us
@@ -92,6 +93,7 @@ func (us SqlUserStore) CreateIndexesIfNotExists() {
 	us.CreateIndexIfNotExists("idx_users_update_at", "Users", "UpdateAt")
 	us.CreateIndexIfNotExists("idx_users_create_at", "Users", "CreateAt")
 	us.CreateIndexIfNotExists("idx_users_delete_at", "Users", "DeleteAt")
+	us.CreateUniqueIndexIfNotExists("idx_users_remote_id_unique", "Users", "RemoteId")
 
 	if us.DriverName() == model.DATABASE_DRIVER_POSTGRES {
 		us.CreateIndexIfNotExists("idx_users_email_lower", "Users", "lower(Email)")
@@ -124,6 +126,8 @@ func (us SqlUserStore) Save(user *model.User) store.StoreChannel {
 				result.Err = model.NewAppError("SqlUserStore.Save", "store.sql_user.save.email_exists.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
 			} else if IsUniqueConstraintError(err, []string{"Username", "users_username_key", "idx_users_username_unique"}) {
 				result.Err = model.NewAppError("SqlUserStore.Save", "store.sql_user.save.username_exists.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
+			} else if IsUniqueConstraintError(err, []string{"RemoteId", "idx_users_remote_id_unique"}) {
+				result.Err = model.NewAppError("SqlUserStore.Save", "store.sql_user.save.remote_id_exists.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
 			} else {
 				result.Err = model.NewAppError("SqlUserStore.Save", "store.sql_user.save.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusInternalServerError)
 			}
@@ -157,10 +161,21 @@ func (us SqlUserStore) Update(user *model.User, trustedUpdateData bool) store.St
 			user.FailedAttempts = oldUser.FailedAttempts
 			user.MfaSecret = oldUser.MfaSecret
 			user.MfaActive = oldUser.MfaActive
+			user.IsBot = oldUser.IsBot
 
 			if !trustedUpdateData {
 				user.Roles = oldUser.Roles
 				user.DeleteAt = oldUser.DeleteAt
+
+				// The phone number can only be changed by verifying it
+				if phoneNumber := oldUser.GetPhoneNumber(); phoneNumber != "" {
+					if user.Props == nil {
+						user.Props = model.StringMap{}
+					}
+					user.Props[model.USER_PROP_PHONE_NUMBER] = phoneNumber
+				} else {
+					delete(user.Props, model.USER_PROP_PHONE_NUMBER)
+				}
 			}
 
 			if user.IsOAuthUser() {
@@ -186,6 +201,8 @@ func (us SqlUserStore) Update(user *model.User, trustedUpdateData bool) store.St
 					result.Err = model.NewAppError("SqlUserStore.Update", "store.sql_user.update.email_taken.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
 				} else if IsUniqueConstraintError(err, []string{"Username", "users_username_key", "idx_users_username_unique"}) {
 					result.Err = model.NewAppError("SqlUserStore.Update", "store.sql_user.update.username_taken.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
+				} else if IsUniqueConstraintError(err, []string{"RemoteId", "idx_users_remote_id_unique"}) {
+					result.Err = model.NewAppError("SqlUserStore.Update", "store.sql_user.update.remote_id_taken.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusBadRequest)
 				} else {
 					result.Err = model.NewAppError("SqlUserStore.Update", "store.sql_user.update.updating.app_error", nil, "user_id="+user.Id+", "+err.Error(), http.StatusInternalServerError)
 				}
@@ -682,6 +699,134 @@ func (us SqlUserStore) GetNewUsersForTeam(teamId string, offset, limit int) stor
 	})
 }
 
+var userDirectorySortColumns = map[string]string{
+	model.USER_DIRECTORY_SORT_USERNAME:         "u.Username",
+	model.USER_DIRECTORY_SORT_EMAIL:            "u.Email",
+	model.USER_DIRECTORY_SORT_FIRST_NAME:       "u.FirstName",
+	model.USER_DIRECTORY_SORT_LAST_NAME:        "u.LastName",
+	model.USER_DIRECTORY_SORT_AUTH_SERVICE:     "u.AuthService",
+	model.USER_DIRECTORY_SORT_CREATE_AT:        "u.CreateAt",
+	model.USER_DIRECTORY_SORT_LAST_ACTIVITY_AT: "LastActivityAt",
+}
+
+func (us SqlUserStore) GetDirectory(options *model.UserDirectoryOptions, offset, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var users []*UserWithLastActivityAt
+
+		parameters := map[string]interface{}{"Offset": offset, "Limit": limit}
+		joins := ""
+		conditions := []string{}
+
+		if options.TeamId != "" {
+			joins = "INNER JOIN TeamMembers AS t ON t.UserId = u.Id AND t.TeamId = :TeamId AND t.DeleteAt = 0"
+			parameters["TeamId"] = options.TeamId
+		}
+
+		if options.Role != "" {
+			// Roles are separated by spaces, so padding them with spaces matches whole roles instead of any role that
+			// contains the name, such as team_admin for admin
+			if us.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+				conditions = append(conditions, "(' ' || u.Roles || ' ') LIKE :Role")
+			} else {
+				conditions = append(conditions, "CONCAT(' ', u.Roles, ' ') LIKE :Role")
+			}
+			parameters["Role"] = "% " + options.Role + " %"
+		}
+
+		if options.AuthService == model.USER_AUTH_SERVICE_EMAIL {
+			conditions = append(conditions, "u.AuthService = ''")
+		} else if options.AuthService != "" {
+			conditions = append(conditions, "u.AuthService = :AuthService")
+			parameters["AuthService"] = options.AuthService
+		}
+
+		if options.LastActivityBefore > 0 {
+			conditions = append(conditions, "COALESCE(s.LastActivityAt, 0) < :LastActivityBefore")
+			parameters["LastActivityBefore"] = options.LastActivityBefore
+		}
+
+		where := ""
+		if len(conditions) > 0 {
+			where = "WHERE " + strings.Join(conditions, " AND ")
+		}
+
+		orderBy := []string{}
+		for _, sort := range options.Sort {
+			column, ok := userDirectorySortColumns[sort.Column]
+			if !ok {
+				continue
+			}
+
+			if sort.Descending {
+				orderBy = append(orderBy, column+" DESC")
+			} else {
+				orderBy = append(orderBy, column+" ASC")
+			}
+		}
+		// Always finish with a unique column so that pages are stable.
+		orderBy = append(orderBy, "u.Id ASC")
+
+		query := `
+            SELECT
+                u.*,
+                COALESCE(s.LastActivityAt, 0) AS LastActivityAt
+            FROM Users AS u
+                LEFT JOIN Status AS s ON s.UserId = u.Id
+                ` + joins + `
+            ` + where + `
+            ORDER BY ` + strings.Join(orderBy, ", ") + `
+            LIMIT :Limit OFFSET :Offset`
+
+		if _, err := us.GetReplica().Select(&users, query, parameters); err != nil {
+			result.Err = model.NewAppError("SqlUserStore.GetDirectory", "store.sql_user.get_directory.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			userList := []*model.User{}
+
+			for _, userWithLastActivityAt := range users {
+				u := userWithLastActivityAt.User
+				u.Sanitize(map[string]bool{})
+				u.LastActivityAt = userWithLastActivityAt.LastActivityAt
+				userList = append(userList, &u)
+			}
+
+			result.Data = userList
+		}
+	})
+}
+
+// GetInactive returns the active users who were created and were last active before the given time. Users who have
+// never been active are treated as having been last active at 0.
+func (us SqlUserStore) GetInactive(lastActivityBefore int64, offset, limit int) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var users []*UserWithLastActivityAt
+
+		if _, err := us.GetReplica().Select(&users, `
+            SELECT
+                u.*,
+                COALESCE(s.LastActivityAt, 0) AS LastActivityAt
+            FROM Users AS u
+                LEFT JOIN Status AS s ON s.UserId = u.Id
+            WHERE
+                u.DeleteAt = 0
+                AND u.CreateAt < :LastActivityBefore
+                AND COALESCE(s.LastActivityAt, 0) < :LastActivityBefore
+            ORDER BY u.Id ASC
+            LIMIT :Limit OFFSET :Offset`, map[string]interface{}{"LastActivityBefore": lastActivityBefore, "Offset": offset, "Limit": limit}); err != nil {
+			result.Err = model.NewAppError("SqlUserStore.GetInactive", "store.sql_user.get_inactive.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			userList := []*model.User{}
+
+			for _, userWithLastActivityAt := range users {
+				u := userWithLastActivityAt.User
+				u.LastActivityAt = userWithLastActivityAt.LastActivityAt
+				userList = append(userList, &u)
+			}
+
+			result.Data = userList
+		}
+	})
+}
+
 func (us SqlUserStore) GetProfileByIds(userIds []string, allowFromCache bool) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		users := []*model.User{}
@@ -776,6 +921,23 @@ func (us SqlUserStore) GetByEmail(email string) store.StoreChannel {
 	})
 }
 
+func (us SqlUserStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		user := model.User{}
+
+		if err := us.GetReplica().SelectOne(&user, "SELECT * FROM Users WHERE RemoteId = :RemoteId", map[string]interface{}{"RemoteId": remoteId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlUserStore.GetByRemoteId", store.MISSING_ACCOUNT_ERROR, nil, "remote_id="+remoteId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewAppError("SqlUserStore.GetByRemoteId", "store.sql_user.get_by_remote_id.app_error", nil, "remote_id="+remoteId+", "+err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result.Data = &user
+	})
+}
+
 func (us SqlUserStore) GetByAuth(authData *string, authService string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		if authData == nil || *authData == "" {
@@ -888,7 +1050,7 @@ func (us SqlUserStore) AnalyticsUniqueUserCount(teamId string) store.StoreChanne
 			query = "SELECT COUNT(DISTINCT Email) FROM Users WHERE DeleteAt = 0"
 		}
 
-		v, err := us.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId})
+		v, err := us.GetReplicaFor("User.AnalyticsUniqueUserCount").SelectInt(query, map[string]interface{}{"TeamId": teamId})
 		if err != nil {
 			result.Err = model.NewAppError("SqlUserStore.AnalyticsUniqueUserCount", "store.sql_user.analytics_unique_user_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
@@ -903,7 +1065,7 @@ func (us SqlUserStore) AnalyticsActiveCount(timePeriod int64) store.StoreChannel
 
 		query := "SELECT COUNT(*) FROM Status WHERE LastActivityAt > :Time"
 
-		v, err := us.GetReplica().SelectInt(query, map[string]interface{}{"Time": time})
+		v, err := us.GetReplicaFor("User.AnalyticsActiveCount").SelectInt(query, map[string]interface{}{"Time": time})
 		if err != nil {
 			result.Err = model.NewAppError("SqlUserStore.AnalyticsDailyActiveUsers", "store.sql_user.analytics_daily_active_users.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
@@ -912,15 +1074,17 @@ func (us SqlUserStore) AnalyticsActiveCount(timePeriod int64) store.StoreChannel
 	})
 }
 
-func (us SqlUserStore) GetUnreadCount(userId string) store.StoreChannel {
-	return store.Do(func(result *store.StoreResult) {
-		if count, err := us.GetReplica().SelectInt(`
+const userUnreadCountQuery = `
 		SELECT SUM(CASE WHEN c.Type = 'D' THEN (c.TotalMsgCount - cm.MsgCount) ELSE cm.MentionCount END)
 		FROM Channels c
 		INNER JOIN ChannelMembers cm
 		      ON cm.ChannelId = c.Id
 		      AND cm.UserId = :UserId
-		      AND c.DeleteAt = 0`, map[string]interface{}{"UserId": userId}); err != nil {
+		      AND c.DeleteAt = 0`
+
+func (us SqlUserStore) GetUnreadCount(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if count, err := us.GetReplica().SelectInt(userUnreadCountQuery, map[string]interface{}{"UserId": userId}); err != nil {
 			result.Err = model.NewAppError("SqlUserStore.GetMentionCount", "store.sql_user.get_unread_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = count
@@ -928,6 +1092,18 @@ func (us SqlUserStore) GetUnreadCount(userId string) store.StoreChannel {
 	})
 }
 
+// GetUnreadCountFromMaster is the same as GetUnreadCount, but reads from the master so that the count includes
+// changes that were just made, such as a channel that was just viewed.
+func (us SqlUserStore) GetUnreadCountFromMaster(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if count, err := us.GetMaster().SelectInt(userUnreadCountQuery, map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlUserStore.GetUnreadCountFromMaster", "store.sql_user.get_unread_count.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = count
+		}
+	})
+}
+
 func (us SqlUserStore) GetUnreadCountForChannel(userId string, channelId string) store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
 		if count, err := us.GetReplica().SelectInt("SELECT SUM(CASE WHEN c.Type = 'D' THEN (c.TotalMsgCount - cm.MsgCount) ELSE cm.MentionCount END) FROM Channels c INNER JOIN ChannelMembers cm ON c.Id = :ChannelId AND cm.ChannelId = :ChannelId AND cm.UserId = :UserId", map[string]interface{}{"ChannelId": channelId, "UserId": userId}); err != nil {
@@ -1165,7 +1341,7 @@ func (us SqlUserStore) performSearch(searchQuery string, term string, options ma
 
 	var users []*model.User
 
-	if _, err := us.GetReplica().Select(&users, searchQuery, parameters); err != nil {
+	if _, err := us.GetReplicaFor("User.Search").Select(&users, searchQuery, parameters); err != nil {
 		result.Err = model.NewAppError("SqlUserStore.Search", "store.sql_user.search.app_error", nil,
 			fmt.Sprintf("term=%v, search_type=%v, %v", term, searchType, err.Error()), http.StatusInternalServerError)
 	} else {
@@ -1181,7 +1357,7 @@ func (us SqlUserStore) performSearch(searchQuery string, term string, options ma
 
 func (us SqlUserStore) AnalyticsGetInactiveUsersCount() store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
-		if count, err := us.GetReplica().SelectInt("SELECT COUNT(Id) FROM Users WHERE DeleteAt > 0"); err != nil {
+		if count, err := us.GetReplicaFor("User.AnalyticsGetInactiveUsersCount").SelectInt("SELECT COUNT(Id) FROM Users WHERE DeleteAt > 0"); err != nil {
 			result.Err = model.NewAppError("SqlUserStore.AnalyticsGetInactiveUsersCount", "store.sql_user.analytics_get_inactive_users_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = count
@@ -1191,7 +1367,7 @@ func (us SqlUserStore) AnalyticsGetInactiveUsersCount() store.StoreChannel {
 
 func (us SqlUserStore) AnalyticsGetSystemAdminCount() store.StoreChannel {
 	return store.Do(func(result *store.StoreResult) {
-		if count, err := us.GetReplica().SelectInt("SELECT count(*) FROM Users WHERE Roles LIKE :Roles and DeleteAt = 0", map[string]interface{}{"Roles": "%system_admin%"}); err != nil {
+		if count, err := us.GetReplicaFor("User.AnalyticsGetSystemAdminCount").SelectInt("SELECT count(*) FROM Users WHERE Roles LIKE :Roles and DeleteAt = 0", map[string]interface{}{"Roles": "%system_admin%"}); err != nil {
 			result.Err = model.NewAppError("SqlUserStore.AnalyticsGetSystemAdminCount", "store.sql_user.analytics_get_system_admin_count.app_error", nil, err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = count
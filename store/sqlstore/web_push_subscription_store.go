@@ -0,0 +1,124 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlWebPushSubscriptionStore struct {
+	SqlStore
+}
+
+func NewSqlWebPushSubscriptionStore(sqlStore SqlStore) store.WebPushSubscriptionStore {
+	s := &SqlWebPushSubscriptionStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.WebPushSubscription{}, "WebPushSubscriptions").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("SessionId").SetMaxSize(26)
+		table.ColMap("Endpoint").SetMaxSize(model.WEB_PUSH_SUBSCRIPTION_ENDPOINT_MAX_LENGTH)
+		table.ColMap("P256dh").SetMaxSize(128)
+		table.ColMap("Auth").SetMaxSize(64)
+	}
+
+	return s
+}
+
+func (s SqlWebPushSubscriptionStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_web_push_subscriptions_user_id", "WebPushSubscriptions", "UserId")
+	s.CreateIndexIfNotExists("idx_web_push_subscriptions_session_id", "WebPushSubscriptions", "SessionId")
+}
+
+func (s SqlWebPushSubscriptionStore) Save(subscription *model.WebPushSubscription) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if len(subscription.Id) > 0 {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.Save", "store.sql_web_push_subscription.save.existing.app_error", nil, "id="+subscription.Id, http.StatusBadRequest)
+			return
+		}
+
+		subscription.PreSave()
+		if result.Err = subscription.IsValid(); result.Err != nil {
+			return
+		}
+
+		if err := s.GetMaster().Insert(subscription); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.Save", "store.sql_web_push_subscription.save.app_error", nil, "id="+subscription.Id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = subscription
+		}
+	})
+}
+
+func (s SqlWebPushSubscriptionStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var subscription model.WebPushSubscription
+
+		if err := s.GetReplica().SelectOne(&subscription, "SELECT * FROM WebPushSubscriptions WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.Get", "store.sql_web_push_subscription.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+			if err == sql.ErrNoRows {
+				result.Err.StatusCode = http.StatusNotFound
+			}
+		} else {
+			result.Data = &subscription
+		}
+	})
+}
+
+// GetForUser returns the user's subscriptions that belong to sessions that haven't expired.
+func (s SqlWebPushSubscriptionStore) GetForUser(userId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var subscriptions []*model.WebPushSubscription
+
+		if _, err := s.GetReplica().Select(&subscriptions, `
+			SELECT
+				w.*
+			FROM
+				WebPushSubscriptions AS w
+				INNER JOIN Sessions AS s ON s.Id = w.SessionId
+			WHERE
+				w.UserId = :UserId
+				AND (s.ExpiresAt = 0 OR s.ExpiresAt > :Now)
+			ORDER BY w.CreateAt ASC`, map[string]interface{}{"UserId": userId, "Now": model.GetMillis()}); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.GetForUser", "store.sql_web_push_subscription.get_for_user.app_error", nil, "user_id="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = subscriptions
+		}
+	})
+}
+
+func (s SqlWebPushSubscriptionStore) Delete(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM WebPushSubscriptions WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.Delete", "store.sql_web_push_subscription.delete.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = id
+		}
+	})
+}
+
+func (s SqlWebPushSubscriptionStore) DeleteByEndpoint(endpoint string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM WebPushSubscriptions WHERE Endpoint = :Endpoint", map[string]interface{}{"Endpoint": endpoint}); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.DeleteByEndpoint", "store.sql_web_push_subscription.delete_by_endpoint.app_error", nil, err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = endpoint
+		}
+	})
+}
+
+func (s SqlWebPushSubscriptionStore) DeleteBySessionId(sessionId string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if _, err := s.GetMaster().Exec("DELETE FROM WebPushSubscriptions WHERE SessionId = :SessionId", map[string]interface{}{"SessionId": sessionId}); err != nil {
+			result.Err = model.NewAppError("SqlWebPushSubscriptionStore.DeleteBySessionId", "store.sql_web_push_subscription.delete_by_session_id.app_error", nil, "session_id="+sessionId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = sessionId
+		}
+	})
+}
@@ -48,6 +48,7 @@ func NewSqlWebhookStore(sqlStore SqlStore, metrics einterfaces.MetricsInterface)
 		table.ColMap("TeamId").SetMaxSize(26)
 		table.ColMap("DisplayName").SetMaxSize(64)
 		table.ColMap("Description").SetMaxSize(500)
+		table.ColMap("PayloadTemplate").SetMaxSize(model.INCOMING_WEBHOOK_PAYLOAD_TEMPLATE_MAX_LENGTH)
 
 		tableo := db.AddTableWithName(model.OutgoingWebhook{}, "OutgoingWebhooks").SetKeys(false, "Id")
 		tableo.ColMap("Id").SetMaxSize(26)
@@ -63,6 +64,8 @@ func NewSqlWebhookStore(sqlStore SqlStore, metrics einterfaces.MetricsInterface)
 		tableo.ColMap("TriggerWhen").SetMaxSize(1)
 		tableo.ColMap("Username").SetMaxSize(64)
 		tableo.ColMap("IconURL").SetMaxSize(1024)
+		tableo.ColMap("SigningSecret").SetMaxSize(128)
+		tableo.ColMap("TriggerEvents").SetMaxSize(1024)
 	}
 
 	return s
@@ -361,7 +364,7 @@ func (s SqlWebhookStore) AnalyticsIncomingCount(teamId string) store.StoreChanne
 			query += " AND TeamId = :TeamId"
 		}
 
-		if v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
+		if v, err := s.GetReplicaFor("Webhook.AnalyticsIncomingCount").SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
 			result.Err = model.NewAppError("SqlWebhookStore.AnalyticsIncomingCount", "store.sql_webhooks.analytics_incoming_count.app_error", nil, "team_id="+teamId+", err="+err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = v
@@ -383,7 +386,7 @@ func (s SqlWebhookStore) AnalyticsOutgoingCount(teamId string) store.StoreChanne
 			query += " AND TeamId = :TeamId"
 		}
 
-		if v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
+		if v, err := s.GetReplicaFor("Webhook.AnalyticsOutgoingCount").SelectInt(query, map[string]interface{}{"TeamId": teamId}); err != nil {
 			result.Err = model.NewAppError("SqlWebhookStore.AnalyticsOutgoingCount", "store.sql_webhooks.analytics_outgoing_count.app_error", nil, "team_id="+teamId+", err="+err.Error(), http.StatusInternalServerError)
 		} else {
 			result.Data = v
@@ -4,6 +4,7 @@
 package store
 
 import (
+	"runtime"
 	"time"
 
 	"github.com/mattermost/mattermost-server/model"
@@ -18,9 +19,22 @@ type StoreChannel chan StoreResult
 
 func Do(f func(result *StoreResult)) StoreChannel {
 	storeChannel := make(StoreChannel, 1)
+
+	tracer := getQueryTracer()
+	var pc uintptr
+	if tracer != nil {
+		pc, _, _, _ = runtime.Caller(1)
+	}
+
 	go func() {
 		result := StoreResult{}
-		f(&result)
+		if tracer != nil {
+			start := time.Now()
+			f(&result)
+			tracer.TraceQuery(tracedMethodName(pc), time.Since(start), resultRows(result.Data), result.Err != nil)
+		} else {
+			f(&result)
+		}
 		storeChannel <- result
 		close(storeChannel)
 	}()
@@ -65,6 +79,21 @@ type Store interface {
 	UserAccessToken() UserAccessTokenStore
 	ChannelMemberHistory() ChannelMemberHistoryStore
 	Plugin() PluginStore
+	EmailDigest() EmailDigestStore
+	WebPushSubscription() WebPushSubscriptionStore
+	ChannelBridge() ChannelBridgeStore
+	LinkMetadata() LinkMetadataStore
+	PostAcknowledgement() PostAcknowledgementStore
+	PostEvent() PostEventStore
+	ThreadMembership() ThreadMembershipStore
+	UserGroup() UserGroupStore
+	AutoResponderSchedule() AutoResponderScheduleStore
+	ChannelNotifyDefaults() ChannelNotifyDefaultsStore
+	Reminder() ReminderStore
+	Bot() BotStore
+	IntegrationDelivery() IntegrationDeliveryStore
+	EventSubscription() EventSubscriptionStore
+	SidebarCategory() SidebarCategoryStore
 	MarkSystemRanUnitTests()
 	Close()
 	LockToMaster()
@@ -111,6 +140,7 @@ type TeamStore interface {
 	ResetAllTeamSchemes() StoreChannel
 	ClearAllCustomRoleAssignments() StoreChannel
 	AnalyticsGetTeamCountForScheme(schemeId string) StoreChannel
+	GetScheduledForDeletion(before int64) StoreChannel
 }
 
 type ChannelStore interface {
@@ -130,6 +160,7 @@ type ChannelStore interface {
 	GetByName(team_id string, name string, allowFromCache bool) StoreChannel
 	GetByNames(team_id string, names []string, allowFromCache bool) StoreChannel
 	GetByNameIncludeDeleted(team_id string, name string, allowFromCache bool) StoreChannel
+	GetByRemoteId(remoteId string) StoreChannel
 	GetDeletedByName(team_id string, name string) StoreChannel
 	GetDeleted(team_id string, offset int, limit int) StoreChannel
 	GetChannels(teamId string, userId string, includeDeleted bool) StoreChannel
@@ -158,6 +189,8 @@ type ChannelStore interface {
 	PermanentDeleteMembersByUser(userId string) StoreChannel
 	PermanentDeleteMembersByChannel(channelId string) StoreChannel
 	UpdateLastViewedAt(channelIds []string, userId string) StoreChannel
+	GetReadReceipts(channelId string, since int64) StoreChannel
+	GetSnoozedMembers(offset int, limit int) StoreChannel
 	IncrementMentionCount(channelId string, userId string) StoreChannel
 	AnalyticsTypeCount(teamId string, channelType string) StoreChannel
 	GetMembersForUser(teamId string, userId string) StoreChannel
@@ -165,6 +198,7 @@ type ChannelStore interface {
 	SearchInTeam(teamId string, term string, includeDeleted bool) StoreChannel
 	SearchMore(userId string, teamId string, term string) StoreChannel
 	GetMembersByIds(channelId string, userIds []string) StoreChannel
+	GetMembersForUserByChannelIds(userId string, channelIds []string) StoreChannel
 	AnalyticsDeletedTypeCount(teamId string, channelType string) StoreChannel
 	GetChannelUnread(channelId, userId string) StoreChannel
 	ClearCaches()
@@ -187,6 +221,7 @@ type PostStore interface {
 	Update(newPost *model.Post, oldPost *model.Post) StoreChannel
 	Get(id string) StoreChannel
 	GetSingle(id string) StoreChannel
+	GetByRemoteId(remoteId string) StoreChannel
 	Delete(postId string, time int64, deleteByID string) StoreChannel
 	PermanentDeleteByUser(userId string) StoreChannel
 	PermanentDeleteByChannel(channelId string) StoreChannel
@@ -196,6 +231,7 @@ type PostStore interface {
 	GetFlaggedPostsForChannel(userId, channelId string, offset int, limit int) StoreChannel
 	GetPostsBefore(channelId string, postId string, numPosts int, offset int) StoreChannel
 	GetPostsAfter(channelId string, postId string, numPosts int, offset int) StoreChannel
+	GetPostsByCursor(channelId string, cursor *model.PostCursor, before bool, limit int) StoreChannel
 	GetPostsSince(channelId string, time int64, allowFromCache bool) StoreChannel
 	GetEtag(channelId string, allowFromCache bool) StoreChannel
 	Search(teamId string, userId string, params *model.SearchParams) StoreChannel
@@ -211,6 +247,7 @@ type PostStore interface {
 	PermanentDeleteBatch(endTime int64, limit int64) StoreChannel
 	GetOldest() StoreChannel
 	GetMaxPostSize() StoreChannel
+	GetRootPostsForExport(channelId string, offset int, limit int) StoreChannel
 }
 
 type UserStore interface {
@@ -239,6 +276,7 @@ type UserStore interface {
 	InvalidatProfileCacheForUser(userId string)
 	GetByEmail(email string) StoreChannel
 	GetByAuth(authData *string, authService string) StoreChannel
+	GetByRemoteId(remoteId string) StoreChannel
 	GetAllUsingAuthService(authService string) StoreChannel
 	GetByUsername(username string) StoreChannel
 	GetForLogin(loginId string, allowSignInWithUsername, allowSignInWithEmail bool) StoreChannel
@@ -252,9 +290,12 @@ type UserStore interface {
 	AnalyticsUniqueUserCount(teamId string) StoreChannel
 	AnalyticsActiveCount(time int64) StoreChannel
 	GetUnreadCount(userId string) StoreChannel
+	GetUnreadCountFromMaster(userId string) StoreChannel
 	GetUnreadCountForChannel(userId string, channelId string) StoreChannel
 	GetRecentlyActiveUsersForTeam(teamId string, offset, limit int) StoreChannel
 	GetNewUsersForTeam(teamId string, offset, limit int) StoreChannel
+	GetDirectory(options *model.UserDirectoryOptions, offset, limit int) StoreChannel
+	GetInactive(lastActivityBefore int64, offset, limit int) StoreChannel
 	Search(teamId string, term string, options map[string]bool) StoreChannel
 	SearchNotInTeam(notInTeamId string, term string, options map[string]bool) StoreChannel
 	SearchInChannel(channelId string, term string, options map[string]bool) StoreChannel
@@ -286,6 +327,7 @@ type SessionStore interface {
 type AuditStore interface {
 	Save(audit *model.Audit) StoreChannel
 	Get(user_id string, offset int, limit int) StoreChannel
+	Search(options *model.AuditSearchOptions, limit int) StoreChannel
 	PermanentDeleteByUser(userId string) StoreChannel
 	PermanentDeleteBatch(endTime int64, limit int64) StoreChannel
 }
@@ -381,6 +423,7 @@ type CommandWebhookStore interface {
 	Save(webhook *model.CommandWebhook) StoreChannel
 	Get(id string) StoreChannel
 	TryUse(id string, limit int) StoreChannel
+	UpdateEphemeralPost(id string, postId string, message string) StoreChannel
 	Cleanup()
 }
 
@@ -434,7 +477,9 @@ type FileInfoStore interface {
 	Save(info *model.FileInfo) StoreChannel
 	Get(id string) StoreChannel
 	GetByPath(path string) StoreChannel
+	GetByIds(fileIds []string) StoreChannel
 	GetForPost(postId string, readFromMaster bool, allowFromCache bool) StoreChannel
+	GetForPosts(postIds []string) StoreChannel
 	GetForUser(userId string) StoreChannel
 	InvalidateFileInfosForPostCache(postId string)
 	AttachToPost(fileId string, postId string) StoreChannel
@@ -483,8 +528,134 @@ type UserAccessTokenStore interface {
 
 type PluginStore interface {
 	SaveOrUpdate(keyVal *model.PluginKeyValue) StoreChannel
+	CompareAndSet(keyVal *model.PluginKeyValue, oldValue []byte) StoreChannel
 	Get(pluginId, key string) StoreChannel
 	Delete(pluginId, key string) StoreChannel
+	CompareAndDelete(pluginId, key string, oldValue []byte) StoreChannel
+	DeleteAllExpired() StoreChannel
+	GetTotalSize(pluginId string) StoreChannel
+}
+
+type EmailDigestStore interface {
+	Save(entry *model.EmailDigestEntry) StoreChannel
+	GetUserIdsWithEntries() StoreChannel
+	GetForUser(userId string) StoreChannel
+	DeleteForUser(userId string, before int64) StoreChannel
+}
+
+type WebPushSubscriptionStore interface {
+	Save(subscription *model.WebPushSubscription) StoreChannel
+	Get(id string) StoreChannel
+	GetForUser(userId string) StoreChannel
+	Delete(id string) StoreChannel
+	DeleteByEndpoint(endpoint string) StoreChannel
+	DeleteBySessionId(sessionId string) StoreChannel
+}
+
+type ChannelBridgeStore interface {
+	Save(bridge *model.ChannelBridge) StoreChannel
+	Get(id string) StoreChannel
+	GetAll() StoreChannel
+	GetForChannel(channelId string) StoreChannel
+	Update(bridge *model.ChannelBridge) StoreChannel
+	Delete(id string) StoreChannel
+	DeleteForChannel(channelId string) StoreChannel
+}
+
+type LinkMetadataStore interface {
+	Save(metadata *model.LinkMetadata) StoreChannel
+	Get(url string) StoreChannel
+	Touch(hash int64, accessAt int64) StoreChannel
+	GetExpired(createdBefore int64, limit int) StoreChannel
+	Delete(hash int64) StoreChannel
+	DeleteForDomain(domain string) StoreChannel
+	Count() StoreChannel
+	DeleteLeastRecentlyUsed(count int64) StoreChannel
+}
+
+type PostAcknowledgementStore interface {
+	Save(acknowledgement *model.PostAcknowledgement) StoreChannel
+	Delete(userId string, postId string) StoreChannel
+	GetForPost(postId string) StoreChannel
+}
+
+type PostEventStore interface {
+	GetForChannel(channelId string, afterSeq int64, createdBefore int64, limit int) StoreChannel
+	GetLatestSeq(channelId string, createdBefore int64) StoreChannel
+}
+
+type ThreadMembershipStore interface {
+	Save(membership *model.ThreadMembership) StoreChannel
+	Get(userId string, postId string) StoreChannel
+	GetThreadsForUser(userId string, offset int, limit int) StoreChannel
+	GetThreadsForPost(postId string) StoreChannel
+	UpdateLastUpdated(postId string, lastUpdated int64) StoreChannel
+}
+
+type UserGroupStore interface {
+	Save(group *model.UserGroup) StoreChannel
+	Update(group *model.UserGroup) StoreChannel
+	Get(groupId string) StoreChannel
+	GetByName(name string) StoreChannel
+	GetAll(offset int, limit int) StoreChannel
+	Delete(groupId string) StoreChannel
+	SaveMember(member *model.UserGroupMember) StoreChannel
+	DeleteMember(groupId string, userId string) StoreChannel
+	GetMembers(groupId string, offset int, limit int) StoreChannel
+	GetMemberIdsInChannel(channelId string) StoreChannel
+}
+
+type AutoResponderScheduleStore interface {
+	Save(schedule *model.AutoResponderSchedule) StoreChannel
+	Get(userId string) StoreChannel
+	Delete(userId string) StoreChannel
+	GetDue(now int64, limit int) StoreChannel
+}
+
+type ChannelNotifyDefaultsStore interface {
+	Save(defaults *model.ChannelNotifyDefaults) StoreChannel
+	Get(channelId string) StoreChannel
+	Delete(channelId string) StoreChannel
+}
+
+type ReminderStore interface {
+	Save(reminder *model.Reminder) StoreChannel
+	Get(reminderId string) StoreChannel
+	GetForUser(userId string) StoreChannel
+	GetDue(before int64, limit int) StoreChannel
+	Delete(reminderId string) StoreChannel
+}
+
+type BotStore interface {
+	Save(bot *model.Bot) StoreChannel
+	Get(userId string, includeDeleted bool) StoreChannel
+	GetAll(options *model.BotGetOptions) StoreChannel
+	Update(bot *model.Bot) StoreChannel
+	PermanentDelete(userId string) StoreChannel
+}
+
+type IntegrationDeliveryStore interface {
+	Save(delivery *model.IntegrationDelivery) StoreChannel
+	GetDeliveries(integrationType string, integrationId string, offset int, limit int) StoreChannel
+	PermanentDeleteOverLimit(limit int) StoreChannel
+}
+
+type EventSubscriptionStore interface {
+	Save(subscription *model.EventSubscription) StoreChannel
+	Get(id string) StoreChannel
+	GetByTeam(teamId string, offset int, limit int) StoreChannel
+	GetActiveForEvent(eventType string, teamId string, channelId string) StoreChannel
+	Update(subscription *model.EventSubscription) StoreChannel
+	Delete(id string, time int64) StoreChannel
+	RecordDeliveryResult(id string, succeeded bool) StoreChannel
+}
+
+type SidebarCategoryStore interface {
+	Save(category *model.SidebarCategory) StoreChannel
+	Get(id string) StoreChannel
+	GetForTeam(userId string, teamId string) StoreChannel
+	Update(category *model.SidebarCategory) StoreChannel
+	Delete(id string) StoreChannel
 }
 
 type RoleStore interface {
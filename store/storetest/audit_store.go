@@ -7,6 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/store"
 )
@@ -14,6 +17,7 @@ import (
 func TestAuditStore(t *testing.T, ss store.Store) {
 	t.Run("", func(t *testing.T) { testAuditStore(t, ss) })
 	t.Run("PermanentDeleteBatch", func(t *testing.T) { testAuditStorePermanentDeleteBatch(t, ss) })
+	t.Run("Search", func(t *testing.T) { testAuditStoreSearch(t, ss) })
 }
 
 func testAuditStore(t *testing.T, ss store.Store) {
@@ -89,3 +93,56 @@ func testAuditStorePermanentDeleteBatch(t *testing.T, ss store.Store) {
 		t.Fatal(r2.Err)
 	}
 }
+
+func testAuditStoreSearch(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	a1 := &model.Audit{UserId: userId, IpAddress: "ipaddress", Action: "/api/v4/users/login"}
+	store.Must(ss.Audit().Save(a1))
+	time.Sleep(10 * time.Millisecond)
+	a2 := &model.Audit{UserId: userId, IpAddress: "ipaddress", Action: "/api/v4/users_extra/login"}
+	store.Must(ss.Audit().Save(a2))
+	time.Sleep(10 * time.Millisecond)
+	a3 := &model.Audit{UserId: userId, IpAddress: "ipaddress", Action: "/api/v4/teams/" + model.NewId() + "/members"}
+	store.Must(ss.Audit().Save(a3))
+	time.Sleep(10 * time.Millisecond)
+	a4 := &model.Audit{UserId: model.NewId(), IpAddress: "ipaddress", Action: "/api/v4/users/login"}
+	store.Must(ss.Audit().Save(a4))
+
+	search := func(options *model.AuditSearchOptions, limit int) model.Audits {
+		t.Helper()
+		result := <-ss.Audit().Search(options, limit)
+		require.Nil(t, result.Err)
+		return result.Data.(model.Audits)
+	}
+
+	audits := search(&model.AuditSearchOptions{UserId: userId}, 100)
+	require.Len(t, audits, 3)
+	assert.Equal(t, a3.Id, audits[0].Id)
+	assert.Equal(t, a1.Id, audits[2].Id)
+
+	audits = search(&model.AuditSearchOptions{UserId: userId, TargetType: "users"}, 100)
+	require.Len(t, audits, 1, "shouldn't treat the underscore as a wildcard")
+	assert.Equal(t, a1.Id, audits[0].Id)
+
+	audits = search(&model.AuditSearchOptions{UserId: userId, Action: "/api/v4/teams/"}, 100)
+	require.Len(t, audits, 1)
+	assert.Equal(t, a3.Id, audits[0].Id)
+
+	audits = search(&model.AuditSearchOptions{UserId: userId, Since: a2.CreateAt, Until: a2.CreateAt}, 100)
+	require.Len(t, audits, 1)
+	assert.Equal(t, a2.Id, audits[0].Id)
+
+	audits = search(&model.AuditSearchOptions{UserId: userId}, 2)
+	require.Len(t, audits, 2)
+	cursor := &model.AuditCursor{CreateAt: audits[1].CreateAt, Id: audits[1].Id}
+
+	audits = search(&model.AuditSearchOptions{UserId: userId, Cursor: cursor}, 2)
+	require.Len(t, audits, 1)
+	assert.Equal(t, a1.Id, audits[0].Id)
+
+	result := <-ss.Audit().Search(&model.AuditSearchOptions{}, model.AUDIT_SEARCH_MAX_PER_PAGE+1)
+	assert.NotNil(t, result.Err)
+
+	store.Must(ss.Audit().PermanentDeleteByUser(userId))
+	store.Must(ss.Audit().PermanentDeleteByUser(a4.UserId))
+}
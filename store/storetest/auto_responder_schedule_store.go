@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestAutoResponderScheduleStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testAutoResponderScheduleStoreSave(t, ss) })
+	t.Run("GetDue", func(t *testing.T) { testAutoResponderScheduleStoreGetDue(t, ss) })
+}
+
+func testAutoResponderScheduleStoreSave(t *testing.T, ss store.Store) {
+	schedule := &model.AutoResponderSchedule{UserId: model.NewId(), StartAt: 1000, EndAt: 2000, SetStatus: true}
+
+	result := <-ss.AutoResponderSchedule().Save(schedule)
+	require.Nil(t, result.Err)
+
+	schedule.EndAt = 3000
+	result = <-ss.AutoResponderSchedule().Save(schedule)
+	require.Nil(t, result.Err)
+
+	result = <-ss.AutoResponderSchedule().Get(schedule.UserId)
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(3000), result.Data.(*model.AutoResponderSchedule).EndAt)
+
+	result = <-ss.AutoResponderSchedule().Save(&model.AutoResponderSchedule{UserId: model.NewId(), StartAt: 2000, EndAt: 1000})
+	assert.NotNil(t, result.Err, "shouldn't save a schedule that ends before it starts")
+
+	result = <-ss.AutoResponderSchedule().Delete(schedule.UserId)
+	require.Nil(t, result.Err)
+
+	result = <-ss.AutoResponderSchedule().Get(schedule.UserId)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testAutoResponderScheduleStoreGetDue(t *testing.T, ss store.Store) {
+	now := model.GetMillis()
+
+	starting := store.Must(ss.AutoResponderSchedule().Save(&model.AutoResponderSchedule{UserId: model.NewId(), StartAt: now - 1000, EndAt: now + 1000})).(*model.AutoResponderSchedule)
+	started := store.Must(ss.AutoResponderSchedule().Save(&model.AutoResponderSchedule{UserId: model.NewId(), StartAt: now - 1000, EndAt: now + 1000, Active: true})).(*model.AutoResponderSchedule)
+	ended := store.Must(ss.AutoResponderSchedule().Save(&model.AutoResponderSchedule{UserId: model.NewId(), StartAt: now - 2000, EndAt: now - 1000, Active: true})).(*model.AutoResponderSchedule)
+	future := store.Must(ss.AutoResponderSchedule().Save(&model.AutoResponderSchedule{UserId: model.NewId(), StartAt: now + 1000, EndAt: now + 2000})).(*model.AutoResponderSchedule)
+
+	defer func() {
+		for _, schedule := range []*model.AutoResponderSchedule{starting, started, ended, future} {
+			store.Must(ss.AutoResponderSchedule().Delete(schedule.UserId))
+		}
+	}()
+
+	result := <-ss.AutoResponderSchedule().GetDue(now, 100)
+	require.Nil(t, result.Err)
+
+	due := map[string]bool{}
+	for _, schedule := range result.Data.([]*model.AutoResponderSchedule) {
+		due[schedule.UserId] = true
+	}
+
+	assert.True(t, due[starting.UserId], "should return schedules that have started")
+	assert.False(t, due[started.UserId], "shouldn't return schedules that have already been turned on")
+	assert.True(t, due[ended.UserId], "should return schedules that have ended")
+	assert.False(t, due[future.UserId], "shouldn't return schedules that haven't started")
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestBotStore(t *testing.T, ss store.Store) {
+	t.Run("SaveGetUpdate", func(t *testing.T) { testBotStoreSaveGetUpdate(t, ss) })
+	t.Run("GetAll", func(t *testing.T) { testBotStoreGetAll(t, ss) })
+	t.Run("PermanentDelete", func(t *testing.T) { testBotStorePermanentDelete(t, ss) })
+}
+
+func saveTestBot(t *testing.T, ss store.Store, ownerId string) *model.Bot {
+	bot := &model.Bot{
+		Username:    "bot" + model.NewId(),
+		DisplayName: "Test Bot",
+		Description: "Tests things",
+		OwnerId:     ownerId,
+	}
+
+	user := store.Must(ss.User().Save(bot.User())).(*model.User)
+	bot.UserId = user.Id
+
+	result := <-ss.Bot().Save(bot)
+	require.Nil(t, result.Err)
+
+	return result.Data.(*model.Bot)
+}
+
+func testBotStoreSaveGetUpdate(t *testing.T, ss store.Store) {
+	bot := saveTestBot(t, ss, model.NewId())
+
+	result := <-ss.Bot().Get(bot.UserId, false)
+	require.Nil(t, result.Err)
+	assert.Equal(t, bot, result.Data.(*model.Bot), "should load the username and display name from the bot's user")
+
+	bot.Description = "Updated"
+	bot.DeleteAt = model.GetMillis()
+	result = <-ss.Bot().Update(bot)
+	require.Nil(t, result.Err)
+
+	result = <-ss.Bot().Get(bot.UserId, false)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode, "shouldn't get disabled bots")
+
+	result = <-ss.Bot().Get(bot.UserId, true)
+	require.Nil(t, result.Err)
+	assert.Equal(t, "Updated", result.Data.(*model.Bot).Description)
+	assert.Equal(t, bot.DeleteAt, result.Data.(*model.Bot).DeleteAt)
+
+	result = <-ss.Bot().Update(&model.Bot{UserId: model.NewId(), Username: "missing", OwnerId: model.NewId(), CreateAt: 1})
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.Bot().Get(model.NewId(), true)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testBotStoreGetAll(t *testing.T, ss store.Store) {
+	owner := store.Must(ss.User().Save(&model.User{Email: MakeEmail(), Username: "u" + model.NewId()})).(*model.User)
+
+	first := saveTestBot(t, ss, owner.Id)
+	second := saveTestBot(t, ss, owner.Id)
+	disabled := saveTestBot(t, ss, owner.Id)
+	orphaned := saveTestBot(t, ss, model.NewId())
+
+	disabled.DeleteAt = model.GetMillis()
+	store.Must(ss.Bot().Update(disabled))
+
+	result := <-ss.Bot().GetAll(&model.BotGetOptions{OwnerId: owner.Id, PerPage: 100})
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.Bot{first, second}, result.Data.([]*model.Bot))
+
+	result = <-ss.Bot().GetAll(&model.BotGetOptions{OwnerId: owner.Id, IncludeDeleted: true, PerPage: 100})
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.Bot), 3)
+
+	result = <-ss.Bot().GetAll(&model.BotGetOptions{OwnerId: owner.Id, Page: 1, PerPage: 1})
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.Bot{second}, result.Data.([]*model.Bot))
+
+	result = <-ss.Bot().GetAll(&model.BotGetOptions{OnlyOrphaned: true, PerPage: 1000})
+	require.Nil(t, result.Err)
+	found := false
+	for _, bot := range result.Data.([]*model.Bot) {
+		assert.NotEqual(t, owner.Id, bot.OwnerId)
+		if bot.UserId == orphaned.UserId {
+			found = true
+		}
+	}
+	assert.True(t, found, "should include bots whose owner doesn't exist")
+}
+
+func testBotStorePermanentDelete(t *testing.T, ss store.Store) {
+	bot := saveTestBot(t, ss, model.NewId())
+
+	result := <-ss.Bot().PermanentDelete(bot.UserId)
+	require.Nil(t, result.Err)
+
+	result = <-ss.Bot().Get(bot.UserId, true)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestChannelBridgeStore(t *testing.T, ss store.Store) {
+	t.Run("SaveAndGet", func(t *testing.T) { testChannelBridgeStoreSaveAndGet(t, ss) })
+	t.Run("GetForChannel", func(t *testing.T) { testChannelBridgeStoreGetForChannel(t, ss) })
+	t.Run("Update", func(t *testing.T) { testChannelBridgeStoreUpdate(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testChannelBridgeStoreDelete(t, ss) })
+}
+
+func makeChannelBridge(channelId string) *model.ChannelBridge {
+	return &model.ChannelBridge{
+		ChannelId:       channelId,
+		Service:         "Slack",
+		RemoteChannelId: "C" + model.NewId(),
+		OutgoingURL:     "https://bridge.example.com/events",
+		CreatorId:       model.NewId(),
+	}
+}
+
+func testChannelBridgeStoreSaveAndGet(t *testing.T, ss store.Store) {
+	b1 := store.Must(ss.ChannelBridge().Save(makeChannelBridge(model.NewId()))).(*model.ChannelBridge)
+	assert.Equal(t, "slack", b1.Service)
+	assert.Equal(t, model.CHANNEL_BRIDGE_CONFLICT_POLICY_LATEST_WINS, b1.ConflictPolicy)
+	assert.True(t, model.IsValidId(b1.Token))
+
+	result := <-ss.ChannelBridge().Save(b1)
+	assert.NotNil(t, result.Err, "should not be able to save an existing bridge")
+
+	duplicate := makeChannelBridge(model.NewId())
+	duplicate.RemoteChannelId = b1.RemoteChannelId
+	result = <-ss.ChannelBridge().Save(duplicate)
+	require.NotNil(t, result.Err, "should not be able to bridge the same remote channel twice")
+	assert.Equal(t, "store.sql_channel_bridge.save.remote_channel_exists.app_error", result.Err.Id)
+
+	result = <-ss.ChannelBridge().Get(b1.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, b1, result.Data.(*model.ChannelBridge))
+
+	result = <-ss.ChannelBridge().Get(model.NewId())
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.ChannelBridge().GetAll()
+	require.Nil(t, result.Err)
+	found := false
+	for _, bridge := range result.Data.([]*model.ChannelBridge) {
+		if bridge.Id == b1.Id {
+			found = true
+		}
+	}
+	assert.True(t, found, "should return all bridges")
+}
+
+func testChannelBridgeStoreGetForChannel(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+
+	b1 := store.Must(ss.ChannelBridge().Save(makeChannelBridge(channelId))).(*model.ChannelBridge)
+	b2 := makeChannelBridge(channelId)
+	b2.Service = "msteams"
+	b2 = store.Must(ss.ChannelBridge().Save(b2)).(*model.ChannelBridge)
+	store.Must(ss.ChannelBridge().Save(makeChannelBridge(model.NewId())))
+
+	result := <-ss.ChannelBridge().GetForChannel(channelId)
+	require.Nil(t, result.Err)
+	bridges := result.Data.([]*model.ChannelBridge)
+	require.Len(t, bridges, 2)
+	assert.Equal(t, b1.Id, bridges[0].Id)
+	assert.Equal(t, b2.Id, bridges[1].Id)
+
+	result = <-ss.ChannelBridge().GetForChannel(model.NewId())
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.ChannelBridge), 0)
+}
+
+func testChannelBridgeStoreUpdate(t *testing.T, ss store.Store) {
+	b1 := store.Must(ss.ChannelBridge().Save(makeChannelBridge(model.NewId()))).(*model.ChannelBridge)
+
+	b1.ConflictPolicy = model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS
+	result := <-ss.ChannelBridge().Update(b1)
+	require.Nil(t, result.Err)
+
+	updated := store.Must(ss.ChannelBridge().Get(b1.Id)).(*model.ChannelBridge)
+	assert.Equal(t, model.CHANNEL_BRIDGE_CONFLICT_POLICY_REMOTE_WINS, updated.ConflictPolicy)
+
+	b1.ConflictPolicy = "junk"
+	result = <-ss.ChannelBridge().Update(b1)
+	assert.NotNil(t, result.Err, "should not save an invalid conflict policy")
+}
+
+func testChannelBridgeStoreDelete(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+
+	b1 := store.Must(ss.ChannelBridge().Save(makeChannelBridge(channelId))).(*model.ChannelBridge)
+	b2 := store.Must(ss.ChannelBridge().Save(makeChannelBridge(channelId))).(*model.ChannelBridge)
+
+	store.Must(ss.ChannelBridge().Delete(b1.Id))
+
+	result := <-ss.ChannelBridge().Get(b1.Id)
+	assert.NotNil(t, result.Err)
+
+	store.Must(ss.ChannelBridge().DeleteForChannel(channelId))
+
+	result = <-ss.ChannelBridge().Get(b2.Id)
+	assert.NotNil(t, result.Err)
+}
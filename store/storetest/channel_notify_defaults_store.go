@@ -0,0 +1,48 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestChannelNotifyDefaultsStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testChannelNotifyDefaultsStoreSave(t, ss) })
+}
+
+func testChannelNotifyDefaultsStoreSave(t *testing.T, ss store.Store) {
+	defaults := &model.ChannelNotifyDefaults{
+		ChannelId:   model.NewId(),
+		NotifyProps: model.StringMap{model.DESKTOP_NOTIFY_PROP: model.CHANNEL_NOTIFY_MENTION},
+		UpdatedBy:   model.NewId(),
+	}
+
+	result := <-ss.ChannelNotifyDefaults().Save(defaults)
+	require.Nil(t, result.Err)
+
+	defaults.NotifyProps[model.PUSH_NOTIFY_PROP] = model.CHANNEL_NOTIFY_NONE
+	result = <-ss.ChannelNotifyDefaults().Save(defaults)
+	require.Nil(t, result.Err)
+
+	result = <-ss.ChannelNotifyDefaults().Get(defaults.ChannelId)
+	require.Nil(t, result.Err)
+	assert.Equal(t, defaults.NotifyProps, result.Data.(*model.ChannelNotifyDefaults).NotifyProps)
+
+	result = <-ss.ChannelNotifyDefaults().Save(&model.ChannelNotifyDefaults{ChannelId: model.NewId(), NotifyProps: model.StringMap{model.DESKTOP_NOTIFY_PROP: "sometimes"}, UpdatedBy: model.NewId()})
+	assert.NotNil(t, result.Err, "shouldn't save an invalid notify level")
+
+	result = <-ss.ChannelNotifyDefaults().Delete(defaults.ChannelId)
+	require.Nil(t, result.Err)
+
+	result = <-ss.ChannelNotifyDefaults().Get(defaults.ChannelId)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
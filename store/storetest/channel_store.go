@@ -4,6 +4,7 @@
 package storetest
 
 import (
+	"net/http"
 	"sort"
 	"strings"
 	"testing"
@@ -29,6 +30,7 @@ func TestChannelStore(t *testing.T, ss store.Store) {
 	t.Run("Restore", func(t *testing.T) { testChannelStoreRestore(t, ss) })
 	t.Run("Delete", func(t *testing.T) { testChannelStoreDelete(t, ss) })
 	t.Run("GetByName", func(t *testing.T) { testChannelStoreGetByName(t, ss) })
+	t.Run("GetByRemoteId", func(t *testing.T) { testChannelStoreGetByRemoteId(t, ss) })
 	t.Run("GetByNames", func(t *testing.T) { testChannelStoreGetByNames(t, ss) })
 	t.Run("GetDeletedByName", func(t *testing.T) { testChannelStoreGetDeletedByName(t, ss) })
 	t.Run("GetDeleted", func(t *testing.T) { testChannelStoreGetDeleted(t, ss) })
@@ -41,6 +43,8 @@ func TestChannelStore(t *testing.T, ss store.Store) {
 	t.Run("GetChannelCounts", func(t *testing.T) { testChannelStoreGetChannelCounts(t, ss) })
 	t.Run("GetMembersForUser", func(t *testing.T) { testChannelStoreGetMembersForUser(t, ss) })
 	t.Run("UpdateLastViewedAt", func(t *testing.T) { testChannelStoreUpdateLastViewedAt(t, ss) })
+	t.Run("GetReadReceipts", func(t *testing.T) { testChannelStoreGetReadReceipts(t, ss) })
+	t.Run("GetSnoozedMembers", func(t *testing.T) { testChannelStoreGetSnoozedMembers(t, ss) })
 	t.Run("IncrementMentionCount", func(t *testing.T) { testChannelStoreIncrementMentionCount(t, ss) })
 	t.Run("UpdateChannelMember", func(t *testing.T) { testUpdateChannelMember(t, ss) })
 	t.Run("GetMember", func(t *testing.T) { testGetMember(t, ss) })
@@ -49,6 +53,7 @@ func TestChannelStore(t *testing.T, ss store.Store) {
 	t.Run("SearchMore", func(t *testing.T) { testChannelStoreSearchMore(t, ss) })
 	t.Run("SearchInTeam", func(t *testing.T) { testChannelStoreSearchInTeam(t, ss) })
 	t.Run("GetMembersByIds", func(t *testing.T) { testChannelStoreGetMembersByIds(t, ss) })
+	t.Run("GetMembersForUserByChannelIds", func(t *testing.T) { testChannelStoreGetMembersForUserByChannelIds(t, ss) })
 	t.Run("AnalyticsDeletedTypeCount", func(t *testing.T) { testChannelStoreAnalyticsDeletedTypeCount(t, ss) })
 	t.Run("GetPinnedPosts", func(t *testing.T) { testChannelStoreGetPinnedPosts(t, ss) })
 	t.Run("MaxChannelsPerTeam", func(t *testing.T) { testChannelStoreMaxChannelsPerTeam(t, ss) })
@@ -1360,6 +1365,88 @@ func testChannelStoreUpdateLastViewedAt(t *testing.T, ss store.Store) {
 	}
 }
 
+func testChannelStoreGetReadReceipts(t *testing.T, ss store.Store) {
+	o1 := model.Channel{}
+	o1.TeamId = model.NewId()
+	o1.DisplayName = "Channel1"
+	o1.Name = "zz" + model.NewId() + "b"
+	o1.Type = model.CHANNEL_OPEN
+	store.Must(ss.Channel().Save(&o1, -1))
+
+	m1 := model.ChannelMember{}
+	m1.ChannelId = o1.Id
+	m1.UserId = model.NewId()
+	m1.NotifyProps = model.GetDefaultChannelNotifyProps()
+	m1.LastViewedAt = 2000
+	store.Must(ss.Channel().SaveMember(&m1))
+
+	m2 := model.ChannelMember{}
+	m2.ChannelId = o1.Id
+	m2.UserId = model.NewId()
+	m2.NotifyProps = model.GetDefaultChannelNotifyProps()
+	m2.LastViewedAt = 1000
+	store.Must(ss.Channel().SaveMember(&m2))
+
+	result := <-ss.Channel().GetReadReceipts(o1.Id, 0)
+	require.Nil(t, result.Err)
+	receipts := result.Data.([]*model.ReadReceipt)
+	require.Len(t, receipts, 2)
+	assert.Equal(t, &model.ReadReceipt{ChannelId: o1.Id, UserId: m1.UserId, LastViewedAt: 2000}, receipts[0])
+	assert.Equal(t, m2.UserId, receipts[1].UserId)
+
+	result = <-ss.Channel().GetReadReceipts(o1.Id, 1500)
+	require.Nil(t, result.Err)
+	receipts = result.Data.([]*model.ReadReceipt)
+	require.Len(t, receipts, 1)
+	assert.Equal(t, m1.UserId, receipts[0].UserId)
+
+	result = <-ss.Channel().GetReadReceipts(model.NewId(), 0)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.ReadReceipt), 0)
+}
+
+func testChannelStoreGetSnoozedMembers(t *testing.T, ss store.Store) {
+	o1 := model.Channel{}
+	o1.TeamId = model.NewId()
+	o1.DisplayName = "Channel1"
+	o1.Name = "zz" + model.NewId() + "b"
+	o1.Type = model.CHANNEL_OPEN
+	store.Must(ss.Channel().Save(&o1, -1))
+
+	m1 := model.ChannelMember{}
+	m1.ChannelId = o1.Id
+	m1.UserId = model.NewId()
+	m1.NotifyProps = model.GetDefaultChannelNotifyProps()
+	m1.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP] = "2000"
+	store.Must(ss.Channel().SaveMember(&m1))
+
+	m2 := model.ChannelMember{}
+	m2.ChannelId = o1.Id
+	m2.UserId = model.NewId()
+	m2.NotifyProps = model.GetDefaultChannelNotifyProps()
+	store.Must(ss.Channel().SaveMember(&m2))
+
+	found := false
+	for offset := 0; ; offset += 100 {
+		result := <-ss.Channel().GetSnoozedMembers(offset, 100)
+		require.Nil(t, result.Err)
+		members := *result.Data.(*model.ChannelMembers)
+
+		for _, member := range members {
+			assert.NotEqual(t, m2.UserId, member.UserId, "should only return snoozed members")
+			if member.UserId == m1.UserId {
+				found = true
+				assert.Equal(t, "2000", member.NotifyProps[model.MUTED_UNTIL_NOTIFY_PROP])
+			}
+		}
+
+		if len(members) < 100 {
+			break
+		}
+	}
+	assert.True(t, found, "should return the snoozed member")
+}
+
 func testChannelStoreIncrementMentionCount(t *testing.T, ss store.Store) {
 	o1 := model.Channel{}
 	o1.TeamId = model.NewId()
@@ -2091,6 +2178,41 @@ func testChannelStoreGetMembersByIds(t *testing.T, ss store.Store) {
 	}
 }
 
+func testChannelStoreGetMembersForUserByChannelIds(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+
+	var channelIds []string
+	for i := 0; i < 3; i++ {
+		channel := model.Channel{
+			TeamId:      model.NewId(),
+			DisplayName: "ChannelA",
+			Name:        "zz" + model.NewId() + "b",
+			Type:        model.CHANNEL_OPEN,
+		}
+		store.Must(ss.Channel().Save(&channel, -1))
+		channelIds = append(channelIds, channel.Id)
+	}
+
+	store.Must(ss.Channel().SaveMember(&model.ChannelMember{ChannelId: channelIds[0], UserId: userId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+	store.Must(ss.Channel().SaveMember(&model.ChannelMember{ChannelId: channelIds[1], UserId: userId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+	store.Must(ss.Channel().SaveMember(&model.ChannelMember{ChannelId: channelIds[2], UserId: model.NewId(), NotifyProps: model.GetDefaultChannelNotifyProps()}))
+
+	result := <-ss.Channel().GetMembersForUserByChannelIds(userId, []string{channelIds[0], channelIds[2], model.NewId()})
+	require.Nil(t, result.Err)
+
+	members := *result.Data.(*model.ChannelMembers)
+	require.Len(t, members, 1)
+	assert.Equal(t, channelIds[0], members[0].ChannelId)
+	assert.Equal(t, userId, members[0].UserId)
+
+	result = <-ss.Channel().GetMembersForUserByChannelIds(userId, channelIds)
+	require.Nil(t, result.Err)
+	assert.Len(t, *result.Data.(*model.ChannelMembers), 2)
+
+	result = <-ss.Channel().GetMembersForUserByChannelIds(userId, []string{})
+	assert.NotNil(t, result.Err, "empty channel ids - should have failed")
+}
+
 func testChannelStoreAnalyticsDeletedTypeCount(t *testing.T, ss store.Store) {
 	o1 := model.Channel{}
 	o1.TeamId = model.NewId()
@@ -2476,3 +2598,33 @@ func testChannelStoreClearAllCustomRoleAssignments(t *testing.T, ss store.Store)
 	require.Nil(t, r4.Err)
 	assert.Equal(t, "", r4.Data.(*model.ChannelMember).Roles)
 }
+
+func testChannelStoreGetByRemoteId(t *testing.T, ss store.Store) {
+	remoteId := model.NewRemoteId("ms-teams", model.NewId())
+
+	o1 := model.Channel{}
+	o1.TeamId = model.NewId()
+	o1.DisplayName = "Name"
+	o1.Name = "zz" + model.NewId() + "b"
+	o1.Type = model.CHANNEL_OPEN
+	o1.RemoteId = model.NewString(remoteId)
+	store.Must(ss.Channel().Save(&o1, -1))
+
+	r1 := <-ss.Channel().GetByRemoteId(remoteId)
+	require.Nil(t, r1.Err)
+	assert.Equal(t, o1.Id, r1.Data.(*model.Channel).Id)
+
+	r2 := <-ss.Channel().GetByRemoteId(model.NewRemoteId("ms-teams", model.NewId()))
+	require.NotNil(t, r2.Err)
+	assert.Equal(t, http.StatusNotFound, r2.Err.StatusCode)
+
+	o2 := model.Channel{}
+	o2.TeamId = o1.TeamId
+	o2.DisplayName = "Name"
+	o2.Name = "zz" + model.NewId() + "b"
+	o2.Type = model.CHANNEL_OPEN
+	o2.RemoteId = model.NewString(remoteId)
+	r3 := <-ss.Channel().Save(&o2, -1)
+	require.NotNil(t, r3.Err, "remote ids should be unique")
+	assert.Equal(t, "store.sql_channel.save_channel.remote_id_exists.app_error", r3.Err.Id)
+}
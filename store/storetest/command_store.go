@@ -20,6 +20,7 @@ func TestCommandStore(t *testing.T, ss store.Store) {
 	t.Run("DeleteByUser", func(t *testing.T) { testCommandStoreDeleteByUser(t, ss) })
 	t.Run("Update", func(t *testing.T) { testCommandStoreUpdate(t, ss) })
 	t.Run("CommandCount", func(t *testing.T) { testCommandCount(t, ss) })
+	t.Run("AutocompleteData", func(t *testing.T) { testCommandStoreAutocompleteData(t, ss) })
 }
 
 func testCommandStoreSave(t *testing.T, ss store.Store) {
@@ -255,3 +256,37 @@ func testCommandCount(t *testing.T, ss store.Store) {
 		}
 	}
 }
+
+func testCommandStoreAutocompleteData(t *testing.T, ss store.Store) {
+	o1 := &model.Command{}
+	o1.CreatorId = model.NewId()
+	o1.Method = model.COMMAND_METHOD_POST
+	o1.TeamId = model.NewId()
+	o1.URL = "http://nowhere.com/"
+	o1.Trigger = "trigger"
+
+	o1 = (<-ss.Command().Save(o1)).Data.(*model.Command)
+
+	if r1 := <-ss.Command().Get(o1.Id); r1.Err != nil {
+		t.Fatal(r1.Err)
+	} else if r1.Data.(*model.Command).AutocompleteData != nil {
+		t.Fatal("should not have autocomplete data")
+	}
+
+	o1.AutocompleteData = &model.AutocompleteData{
+		Trigger: "trigger",
+		Arguments: []*model.AutocompleteArg{
+			{Hint: "[name]", Type: model.AUTOCOMPLETE_ARG_TYPE_TEXT},
+		},
+	}
+
+	if err := (<-ss.Command().Update(o1)).Err; err != nil {
+		t.Fatal(err)
+	}
+
+	if r1 := <-ss.Command().Get(o1.Id); r1.Err != nil {
+		t.Fatal(r1.Err)
+	} else if data := r1.Data.(*model.Command).AutocompleteData; data == nil || len(data.Arguments) != 1 || data.Arguments[0].Hint != "[name]" {
+		t.Fatal("should have saved autocomplete data")
+	}
+}
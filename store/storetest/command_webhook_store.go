@@ -65,4 +65,15 @@ func testCommandWebhookStore(t *testing.T, ss store.Store) {
 	if err := (<-cws.TryUse(h1.Id, 1)).Err; err == nil || err.StatusCode != http.StatusBadRequest {
 		t.Fatal("Should be able to use webhook once")
 	}
+
+	postId := model.NewId()
+	if err := (<-cws.UpdateEphemeralPost(h1.Id, postId, "working...")).Err; err != nil {
+		t.Fatal(err)
+	}
+
+	if r1 := <-cws.Get(h1.Id); r1.Err != nil {
+		t.Fatal(r1.Err)
+	} else if hook := r1.Data.(*model.CommandWebhook); hook.EphemeralPostId != postId || hook.EphemeralMessage != "working..." {
+		t.Fatal("should have remembered the ephemeral post")
+	}
 }
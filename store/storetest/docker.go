@@ -86,6 +86,7 @@ func databaseSettings(driver, dataSource string) *model.SqlSettings {
 		Trace:                       false,
 		AtRestEncryptKey:            model.NewRandomString(32),
 		QueryTimeout:                new(int),
+		EnablePostEvents:            model.NewBool(true),
 	}
 	*settings.MaxIdleConns = 10
 	*settings.ConnMaxLifetimeMilliseconds = 3600000
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestEmailDigestStore(t *testing.T, ss store.Store) {
+	t.Run("SaveAndGetForUser", func(t *testing.T) { testEmailDigestStoreSaveAndGetForUser(t, ss) })
+	t.Run("DeleteForUser", func(t *testing.T) { testEmailDigestStoreDeleteForUser(t, ss) })
+}
+
+func makeEmailDigestEntry(userId string, createAt int64) *model.EmailDigestEntry {
+	return &model.EmailDigestEntry{
+		UserId:    userId,
+		PostId:    model.NewId(),
+		ChannelId: model.NewId(),
+		TeamId:    model.NewId(),
+		CreateAt:  createAt,
+	}
+}
+
+func testEmailDigestStoreSaveAndGetForUser(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	otherUserId := model.NewId()
+
+	e1 := store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(userId, 2000))).(*model.EmailDigestEntry)
+	e2 := store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(userId, 1000))).(*model.EmailDigestEntry)
+	store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(otherUserId, 1000)))
+
+	result := <-ss.EmailDigest().Save(e1)
+	assert.NotNil(t, result.Err, "should not be able to save an existing entry")
+
+	result = <-ss.EmailDigest().GetForUser(userId)
+	require.Nil(t, result.Err)
+	entries := result.Data.([]*model.EmailDigestEntry)
+	require.Len(t, entries, 2)
+	assert.Equal(t, e2.Id, entries[0].Id)
+	assert.Equal(t, e1.Id, entries[1].Id)
+
+	result = <-ss.EmailDigest().GetUserIdsWithEntries()
+	require.Nil(t, result.Err)
+	userIds := result.Data.([]string)
+	assert.Contains(t, userIds, userId)
+	assert.Contains(t, userIds, otherUserId)
+}
+
+func testEmailDigestStoreDeleteForUser(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	otherUserId := model.NewId()
+
+	store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(userId, 1000)))
+	e2 := store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(userId, 3000))).(*model.EmailDigestEntry)
+	store.Must(ss.EmailDigest().Save(makeEmailDigestEntry(otherUserId, 1000)))
+
+	result := <-ss.EmailDigest().DeleteForUser(userId, 2000)
+	require.Nil(t, result.Err)
+
+	entries := store.Must(ss.EmailDigest().GetForUser(userId)).([]*model.EmailDigestEntry)
+	require.Len(t, entries, 1)
+	assert.Equal(t, e2.Id, entries[0].Id)
+
+	entries = store.Must(ss.EmailDigest().GetForUser(otherUserId)).([]*model.EmailDigestEntry)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestEventSubscriptionStore(t *testing.T, ss store.Store) {
+	t.Run("SaveGet", func(t *testing.T) { testEventSubscriptionStoreSaveGet(t, ss) })
+	t.Run("GetByTeam", func(t *testing.T) { testEventSubscriptionStoreGetByTeam(t, ss) })
+	t.Run("GetActiveForEvent", func(t *testing.T) { testEventSubscriptionStoreGetActiveForEvent(t, ss) })
+	t.Run("UpdateDelete", func(t *testing.T) { testEventSubscriptionStoreUpdateDelete(t, ss) })
+	t.Run("RecordDeliveryResult", func(t *testing.T) { testEventSubscriptionStoreRecordDeliveryResult(t, ss) })
+}
+
+func saveTestEventSubscription(t *testing.T, ss store.Store, teamId string, eventType string, channelId string) *model.EventSubscription {
+	result := <-ss.EventSubscription().Save(&model.EventSubscription{
+		CreatorId:   model.NewId(),
+		TeamId:      teamId,
+		ChannelId:   channelId,
+		EventType:   eventType,
+		CallbackURL: "https://example.com/events",
+	})
+	require.Nil(t, result.Err)
+
+	return result.Data.(*model.EventSubscription)
+}
+
+func testEventSubscriptionStoreSaveGet(t *testing.T, ss store.Store) {
+	subscription := saveTestEventSubscription(t, ss, model.NewId(), model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+	assert.True(t, subscription.Active)
+	assert.NotEmpty(t, subscription.SigningSecret)
+
+	result := <-ss.EventSubscription().Get(subscription.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, subscription, result.Data.(*model.EventSubscription))
+
+	result = <-ss.EventSubscription().Get(model.NewId())
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.EventSubscription().Save(subscription)
+	assert.NotNil(t, result.Err, "shouldn't save a subscription that already exists")
+
+	result = <-ss.EventSubscription().Save(&model.EventSubscription{
+		CreatorId:   model.NewId(),
+		TeamId:      model.NewId(),
+		EventType:   model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED,
+		CallbackURL: "https://example.com/events",
+	})
+	assert.NotNil(t, result.Err, "shouldn't save a subscription to posts without a channel")
+}
+
+func testEventSubscriptionStoreGetByTeam(t *testing.T, ss store.Store) {
+	teamId := model.NewId()
+
+	first := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+	second := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED, "")
+	saveTestEventSubscription(t, ss, model.NewId(), model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+
+	result := <-ss.EventSubscription().GetByTeam(teamId, 0, 10)
+	require.Nil(t, result.Err)
+	subscriptions := result.Data.([]*model.EventSubscription)
+	require.Len(t, subscriptions, 2)
+	assert.ElementsMatch(t, []string{first.Id, second.Id}, []string{subscriptions[0].Id, subscriptions[1].Id})
+
+	result = <-ss.EventSubscription().GetByTeam(teamId, 1, 10)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.EventSubscription), 1)
+}
+
+func testEventSubscriptionStoreGetActiveForEvent(t *testing.T, ss store.Store) {
+	teamId := model.NewId()
+	channelId := model.NewId()
+
+	posts := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED, channelId)
+	saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED, model.NewId())
+	users := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+
+	inactive := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+	inactive.Active = false
+	require.Nil(t, (<-ss.EventSubscription().Update(inactive)).Err)
+
+	deleted := saveTestEventSubscription(t, ss, teamId, model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+	require.Nil(t, (<-ss.EventSubscription().Delete(deleted.Id, model.GetMillis())).Err)
+
+	result := <-ss.EventSubscription().GetActiveForEvent(model.EVENT_SUBSCRIPTION_EVENT_POST_CREATED, teamId, channelId)
+	require.Nil(t, result.Err)
+	subscriptions := result.Data.([]*model.EventSubscription)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, posts.Id, subscriptions[0].Id)
+
+	result = <-ss.EventSubscription().GetActiveForEvent(model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, teamId, "")
+	require.Nil(t, result.Err)
+	subscriptions = result.Data.([]*model.EventSubscription)
+	require.Len(t, subscriptions, 1, "should only return active subscriptions")
+	assert.Equal(t, users.Id, subscriptions[0].Id)
+
+	result = <-ss.EventSubscription().GetActiveForEvent(model.EVENT_SUBSCRIPTION_EVENT_CHANNEL_ARCHIVED, teamId, "")
+	require.Nil(t, result.Err)
+	assert.Empty(t, result.Data.([]*model.EventSubscription))
+}
+
+func testEventSubscriptionStoreUpdateDelete(t *testing.T, ss store.Store) {
+	subscription := saveTestEventSubscription(t, ss, model.NewId(), model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+
+	subscription.CallbackURL = "https://example.com/other"
+	result := <-ss.EventSubscription().Update(subscription)
+	require.Nil(t, result.Err)
+
+	result = <-ss.EventSubscription().Get(subscription.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, "https://example.com/other", result.Data.(*model.EventSubscription).CallbackURL)
+
+	subscription.CallbackURL = "junk"
+	result = <-ss.EventSubscription().Update(subscription)
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.EventSubscription().Delete(subscription.Id, model.GetMillis())
+	require.Nil(t, result.Err)
+
+	result = <-ss.EventSubscription().Get(subscription.Id)
+	assert.NotNil(t, result.Err, "shouldn't get deleted subscriptions")
+}
+
+func testEventSubscriptionStoreRecordDeliveryResult(t *testing.T, ss store.Store) {
+	subscription := saveTestEventSubscription(t, ss, model.NewId(), model.EVENT_SUBSCRIPTION_EVENT_USER_ADDED, "")
+
+	for i := 0; i < model.EVENT_SUBSCRIPTION_MAX_FAILURES-1; i++ {
+		result := <-ss.EventSubscription().RecordDeliveryResult(subscription.Id, false)
+		require.Nil(t, result.Err)
+		assert.False(t, result.Data.(bool))
+	}
+
+	result := <-ss.EventSubscription().RecordDeliveryResult(subscription.Id, true)
+	require.Nil(t, result.Err)
+
+	result = <-ss.EventSubscription().Get(subscription.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, 0, result.Data.(*model.EventSubscription).Failures, "should reset the failures after a successful delivery")
+
+	for i := 0; i < model.EVENT_SUBSCRIPTION_MAX_FAILURES-1; i++ {
+		<-ss.EventSubscription().RecordDeliveryResult(subscription.Id, false)
+	}
+
+	result = <-ss.EventSubscription().RecordDeliveryResult(subscription.Id, false)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool), "should report that the subscription was deactivated")
+
+	result = <-ss.EventSubscription().RecordDeliveryResult(subscription.Id, false)
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(bool), "should only report the deactivation once")
+
+	result = <-ss.EventSubscription().Get(subscription.Id)
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(*model.EventSubscription).Active)
+	assert.Equal(t, model.EVENT_SUBSCRIPTION_MAX_FAILURES+1, result.Data.(*model.EventSubscription).Failures)
+}
@@ -15,6 +15,8 @@ func TestFileInfoStore(t *testing.T, ss store.Store) {
 	t.Run("FileInfoSaveGet", func(t *testing.T) { testFileInfoSaveGet(t, ss) })
 	t.Run("FileInfoSaveGetByPath", func(t *testing.T) { testFileInfoSaveGetByPath(t, ss) })
 	t.Run("FileInfoGetForPost", func(t *testing.T) { testFileInfoGetForPost(t, ss) })
+	t.Run("FileInfoGetForPosts", func(t *testing.T) { testFileInfoGetForPosts(t, ss) })
+	t.Run("FileInfoGetByIds", func(t *testing.T) { testFileInfoGetByIds(t, ss) })
 	t.Run("FileInfoGetForUser", func(t *testing.T) { testFileInfoGetForUser(t, ss) })
 	t.Run("FileInfoAttachToPost", func(t *testing.T) { testFileInfoAttachToPost(t, ss) })
 	t.Run("FileInfoDeleteForPost", func(t *testing.T) { testFileInfoDeleteForPost(t, ss) })
@@ -155,6 +157,88 @@ func testFileInfoGetForPost(t *testing.T, ss store.Store) {
 	}
 }
 
+func testFileInfoGetForPosts(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	postId1 := model.NewId()
+	postId2 := model.NewId()
+
+	infos := []*model.FileInfo{
+		{
+			PostId:    postId1,
+			CreatorId: userId,
+			Path:      "file.txt",
+		},
+		{
+			PostId:    postId2,
+			CreatorId: userId,
+			Path:      "file.txt",
+		},
+		{
+			PostId:    postId2,
+			CreatorId: userId,
+			Path:      "file.txt",
+			DeleteAt:  123,
+		},
+		{
+			PostId:    model.NewId(),
+			CreatorId: userId,
+			Path:      "file.txt",
+		},
+	}
+
+	for i, info := range infos {
+		infos[i] = store.Must(ss.FileInfo().Save(info)).(*model.FileInfo)
+		defer func(id string) {
+			<-ss.FileInfo().PermanentDelete(id)
+		}(infos[i].Id)
+	}
+
+	if result := <-ss.FileInfo().GetForPosts([]string{postId1, postId2}); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if returned := result.Data.([]*model.FileInfo); len(returned) != 2 {
+		t.Fatal("should've returned exactly 2 file infos")
+	}
+
+	if result := <-ss.FileInfo().GetForPosts([]string{}); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if returned := result.Data.([]*model.FileInfo); len(returned) != 0 {
+		t.Fatal("shouldn't have returned any file infos")
+	}
+}
+
+func testFileInfoGetByIds(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+
+	infos := []*model.FileInfo{
+		{
+			CreatorId: userId,
+			Path:      "file.txt",
+		},
+		{
+			CreatorId: userId,
+			Path:      "file.txt",
+		},
+		{
+			CreatorId: userId,
+			Path:      "file.txt",
+			DeleteAt:  123,
+		},
+	}
+
+	for i, info := range infos {
+		infos[i] = store.Must(ss.FileInfo().Save(info)).(*model.FileInfo)
+		defer func(id string) {
+			<-ss.FileInfo().PermanentDelete(id)
+		}(infos[i].Id)
+	}
+
+	if result := <-ss.FileInfo().GetByIds([]string{infos[0].Id, infos[2].Id, model.NewId()}); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if returned := result.Data.([]*model.FileInfo); len(returned) != 1 || returned[0].Id != infos[0].Id {
+		t.Fatal("should've returned only the file info that isn't deleted")
+	}
+}
+
 func testFileInfoGetForUser(t *testing.T, ss store.Store) {
 	userId := model.NewId()
 	userId2 := model.NewId()
@@ -0,0 +1,93 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestIntegrationDeliveryStore(t *testing.T, ss store.Store) {
+	t.Run("SaveGetDeliveries", func(t *testing.T) { testIntegrationDeliveryStoreSaveGetDeliveries(t, ss) })
+	t.Run("PermanentDeleteOverLimit", func(t *testing.T) { testIntegrationDeliveryStorePermanentDeleteOverLimit(t, ss) })
+}
+
+func saveTestIntegrationDelivery(t *testing.T, ss store.Store, integrationType string, integrationId string, createAt int64) *model.IntegrationDelivery {
+	result := <-ss.IntegrationDelivery().Save(&model.IntegrationDelivery{
+		IntegrationType: integrationType,
+		IntegrationId:   integrationId,
+		URL:             "https://example.com/hook",
+		Request:         `{"text":"hello"}`,
+		StatusCode:      200,
+		Latency:         12,
+		CreateAt:        createAt,
+	})
+	require.Nil(t, result.Err)
+
+	return result.Data.(*model.IntegrationDelivery)
+}
+
+func testIntegrationDeliveryStoreSaveGetDeliveries(t *testing.T, ss store.Store) {
+	hookId := model.NewId()
+	commandId := model.NewId()
+	now := model.GetMillis()
+
+	older := saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_OUTGOING_WEBHOOK, hookId, now)
+	newer := saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_OUTGOING_WEBHOOK, hookId, now+1)
+	command := saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_COMMAND, commandId, now+2)
+
+	result := <-ss.IntegrationDelivery().GetDeliveries("", hookId, 0, 10)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.IntegrationDelivery{newer, older}, result.Data.([]*model.IntegrationDelivery), "should return newest first")
+
+	result = <-ss.IntegrationDelivery().GetDeliveries("", hookId, 1, 10)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.IntegrationDelivery{older}, result.Data.([]*model.IntegrationDelivery))
+
+	result = <-ss.IntegrationDelivery().GetDeliveries(model.INTEGRATION_TYPE_COMMAND, "", 0, 1)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.IntegrationDelivery{command}, result.Data.([]*model.IntegrationDelivery))
+
+	result = <-ss.IntegrationDelivery().GetDeliveries(model.INTEGRATION_TYPE_INCOMING_WEBHOOK, hookId, 0, 10)
+	require.Nil(t, result.Err)
+	assert.Empty(t, result.Data.([]*model.IntegrationDelivery))
+
+	result = <-ss.IntegrationDelivery().Save(&model.IntegrationDelivery{
+		IntegrationType: model.INTEGRATION_TYPE_INCOMING_WEBHOOK,
+		IntegrationId:   hookId,
+		Request:         strings.Repeat("a", model.INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH+1),
+	})
+	require.Nil(t, result.Err, "should save deliveries with large requests")
+	assert.Len(t, result.Data.(*model.IntegrationDelivery).Request, model.INTEGRATION_DELIVERY_REQUEST_MAX_LENGTH)
+
+	result = <-ss.IntegrationDelivery().Save(&model.IntegrationDelivery{IntegrationType: "junk", IntegrationId: hookId})
+	assert.NotNil(t, result.Err)
+}
+
+func testIntegrationDeliveryStorePermanentDeleteOverLimit(t *testing.T, ss store.Store) {
+	hookId := model.NewId()
+	now := model.GetMillis() + 60000
+
+	saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_INCOMING_WEBHOOK, hookId, now)
+	second := saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_INCOMING_WEBHOOK, hookId, now+1)
+	third := saveTestIntegrationDelivery(t, ss, model.INTEGRATION_TYPE_INCOMING_WEBHOOK, hookId, now+2)
+
+	result := <-ss.IntegrationDelivery().PermanentDeleteOverLimit(2)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(int64) >= 1)
+
+	result = <-ss.IntegrationDelivery().GetDeliveries("", hookId, 0, 10)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.IntegrationDelivery{third, second}, result.Data.([]*model.IntegrationDelivery), "should have kept the newest deliveries")
+
+	result = <-ss.IntegrationDelivery().PermanentDeleteOverLimit(2)
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(0), result.Data.(int64))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestLinkMetadataStore(t *testing.T, ss store.Store) {
+	t.Run("SaveAndGet", func(t *testing.T) { testLinkMetadataStoreSaveAndGet(t, ss) })
+	t.Run("Touch", func(t *testing.T) { testLinkMetadataStoreTouch(t, ss) })
+	t.Run("GetExpired", func(t *testing.T) { testLinkMetadataStoreGetExpired(t, ss) })
+	t.Run("DeleteForDomain", func(t *testing.T) { testLinkMetadataStoreDeleteForDomain(t, ss) })
+	t.Run("DeleteLeastRecentlyUsed", func(t *testing.T) { testLinkMetadataStoreDeleteLeastRecentlyUsed(t, ss) })
+}
+
+func makeLinkMetadata(domain string) *model.LinkMetadata {
+	return &model.LinkMetadata{
+		URL:  "https://" + domain + "/" + model.NewId(),
+		Data: `{"title": "test"}`,
+	}
+}
+
+func testLinkMetadataStoreSaveAndGet(t *testing.T, ss store.Store) {
+	domain := model.NewId() + ".example.com"
+	m1 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata(domain))).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m1.Hash)
+	assert.Equal(t, domain, m1.Domain)
+	assert.Equal(t, m1.CreateAt, m1.LastAccessAt)
+
+	received := store.Must(ss.LinkMetadata().Get(m1.URL)).(*model.LinkMetadata)
+	assert.Equal(t, m1, received)
+
+	// saving the same link again replaces it
+	m1.Data = `{"title": "updated"}`
+	m1.CreateAt = model.GetMillis() + 1
+	store.Must(ss.LinkMetadata().Save(m1))
+
+	received = store.Must(ss.LinkMetadata().Get(m1.URL)).(*model.LinkMetadata)
+	assert.Equal(t, `{"title": "updated"}`, received.Data)
+
+	result := <-ss.LinkMetadata().Get("https://" + domain + "/missing")
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.LinkMetadata().Save(&model.LinkMetadata{})
+	assert.NotNil(t, result.Err)
+}
+
+func testLinkMetadataStoreTouch(t *testing.T, ss store.Store) {
+	m1 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata("touch.example.com"))).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m1.Hash)
+
+	store.Must(ss.LinkMetadata().Touch(m1.Hash, m1.CreateAt+1000))
+	store.Must(ss.LinkMetadata().Touch(m1.Hash, m1.CreateAt+2000))
+
+	received := store.Must(ss.LinkMetadata().Get(m1.URL)).(*model.LinkMetadata)
+	assert.Equal(t, m1.CreateAt+2000, received.LastAccessAt)
+	assert.Equal(t, int64(2), received.AccessCount)
+	assert.Equal(t, m1.CreateAt, received.CreateAt)
+}
+
+func testLinkMetadataStoreGetExpired(t *testing.T, ss store.Store) {
+	m1 := makeLinkMetadata("expired.example.com")
+	m1.CreateAt = 1000
+	m1 = store.Must(ss.LinkMetadata().Save(m1)).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m1.Hash)
+
+	m2 := makeLinkMetadata("expired.example.com")
+	m2.CreateAt = 2000
+	m2 = store.Must(ss.LinkMetadata().Save(m2)).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m2.Hash)
+
+	expired := store.Must(ss.LinkMetadata().GetExpired(1500, 10)).([]*model.LinkMetadata)
+	require.Len(t, expired, 1)
+	assert.Equal(t, m1.Hash, expired[0].Hash)
+
+	expired = store.Must(ss.LinkMetadata().GetExpired(2500, 10)).([]*model.LinkMetadata)
+	require.Len(t, expired, 2)
+	assert.Equal(t, m1.Hash, expired[0].Hash)
+
+	expired = store.Must(ss.LinkMetadata().GetExpired(2500, 1)).([]*model.LinkMetadata)
+	require.Len(t, expired, 1)
+}
+
+func testLinkMetadataStoreDeleteForDomain(t *testing.T, ss store.Store) {
+	domain := model.NewId() + ".example.com"
+	m1 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata(domain))).(*model.LinkMetadata)
+	m2 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata(domain))).(*model.LinkMetadata)
+	m3 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata("other." + domain))).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m3.Hash)
+
+	deleted := store.Must(ss.LinkMetadata().DeleteForDomain(domain)).(int64)
+	assert.Equal(t, int64(2), deleted)
+
+	result := <-ss.LinkMetadata().Get(m1.URL)
+	assert.NotNil(t, result.Err)
+	result = <-ss.LinkMetadata().Get(m2.URL)
+	assert.NotNil(t, result.Err)
+	result = <-ss.LinkMetadata().Get(m3.URL)
+	assert.Nil(t, result.Err)
+}
+
+func testLinkMetadataStoreDeleteLeastRecentlyUsed(t *testing.T, ss store.Store) {
+	m1 := makeLinkMetadata("lru.example.com")
+	m1.LastAccessAt = 1
+	m1 = store.Must(ss.LinkMetadata().Save(m1)).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m1.Hash)
+
+	m2 := makeLinkMetadata("lru.example.com")
+	m2.LastAccessAt = 2
+	m2 = store.Must(ss.LinkMetadata().Save(m2)).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m2.Hash)
+
+	m3 := store.Must(ss.LinkMetadata().Save(makeLinkMetadata("lru.example.com"))).(*model.LinkMetadata)
+	defer ss.LinkMetadata().Delete(m3.Hash)
+
+	count := store.Must(ss.LinkMetadata().Count()).(int64)
+	assert.True(t, count >= 3)
+
+	store.Must(ss.LinkMetadata().DeleteLeastRecentlyUsed(2))
+
+	result := <-ss.LinkMetadata().Get(m1.URL)
+	assert.NotNil(t, result.Err)
+	result = <-ss.LinkMetadata().Get(m2.URL)
+	assert.NotNil(t, result.Err)
+	result = <-ss.LinkMetadata().Get(m3.URL)
+	assert.Nil(t, result.Err)
+	assert.Equal(t, count-2, store.Must(ss.LinkMetadata().Count()).(int64))
+}
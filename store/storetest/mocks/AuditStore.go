@@ -76,3 +76,19 @@ func (_m *AuditStore) Save(audit *model.Audit) store.StoreChannel {
 
 	return r0
 }
+
+// Search provides a mock function with given fields: options, limit
+func (_m *AuditStore) Search(options *model.AuditSearchOptions, limit int) store.StoreChannel {
+	ret := _m.Called(options, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(*model.AuditSearchOptions, int) store.StoreChannel); ok {
+		r0 = rf(options, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
@@ -226,6 +226,22 @@ func (_m *ChannelStore) GetByNames(team_id string, names []string, allowFromCach
 	return r0
 }
 
+// GetByRemoteId provides a mock function with given fields: remoteId
+func (_m *ChannelStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	ret := _m.Called(remoteId)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string) store.StoreChannel); ok {
+		r0 = rf(remoteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetChannelCounts provides a mock function with given fields: teamId, userId
 func (_m *ChannelStore) GetChannelCounts(teamId string, userId string) store.StoreChannel {
 	ret := _m.Called(teamId, userId)
@@ -432,6 +448,22 @@ func (_m *ChannelStore) GetMembers(channelId string, offset int, limit int) stor
 	return r0
 }
 
+// GetMembersForUserByChannelIds provides a mock function with given fields: userId, channelIds
+func (_m *ChannelStore) GetMembersForUserByChannelIds(userId string, channelIds []string) store.StoreChannel {
+	ret := _m.Called(userId, channelIds)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, []string) store.StoreChannel); ok {
+		r0 = rf(userId, channelIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetMembersByIds provides a mock function with given fields: channelId, userIds
 func (_m *ChannelStore) GetMembersByIds(channelId string, userIds []string) store.StoreChannel {
 	ret := _m.Called(channelId, userIds)
@@ -528,6 +560,38 @@ func (_m *ChannelStore) GetPublicChannelsForTeam(teamId string, offset int, limi
 	return r0
 }
 
+// GetReadReceipts provides a mock function with given fields: channelId, since
+func (_m *ChannelStore) GetReadReceipts(channelId string, since int64) store.StoreChannel {
+	ret := _m.Called(channelId, since)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, int64) store.StoreChannel); ok {
+		r0 = rf(channelId, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
+// GetSnoozedMembers provides a mock function with given fields: offset, limit
+func (_m *ChannelStore) GetSnoozedMembers(offset int, limit int) store.StoreChannel {
+	ret := _m.Called(offset, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(int, int) store.StoreChannel); ok {
+		r0 = rf(offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetTeamChannels provides a mock function with given fields: teamId
 func (_m *ChannelStore) GetTeamChannels(teamId string) store.StoreChannel {
 	ret := _m.Called(teamId)
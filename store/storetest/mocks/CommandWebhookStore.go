@@ -65,3 +65,19 @@ func (_m *CommandWebhookStore) TryUse(id string, limit int) store.StoreChannel {
 
 	return r0
 }
+
+// UpdateEphemeralPost provides a mock function with given fields: id, postId, message
+func (_m *CommandWebhookStore) UpdateEphemeralPost(id string, postId string, message string) store.StoreChannel {
+	ret := _m.Called(id, postId, message)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, string, string) store.StoreChannel); ok {
+		r0 = rf(id, postId, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
@@ -66,6 +66,22 @@ func (_m *FileInfoStore) Get(id string) store.StoreChannel {
 	return r0
 }
 
+// GetByIds provides a mock function with given fields: fileIds
+func (_m *FileInfoStore) GetByIds(fileIds []string) store.StoreChannel {
+	ret := _m.Called(fileIds)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func([]string) store.StoreChannel); ok {
+		r0 = rf(fileIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetByPath provides a mock function with given fields: path
 func (_m *FileInfoStore) GetByPath(path string) store.StoreChannel {
 	ret := _m.Called(path)
@@ -98,6 +114,22 @@ func (_m *FileInfoStore) GetForPost(postId string, readFromMaster bool, allowFro
 	return r0
 }
 
+// GetForPosts provides a mock function with given fields: postIds
+func (_m *FileInfoStore) GetForPosts(postIds []string) store.StoreChannel {
+	ret := _m.Called(postIds)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func([]string) store.StoreChannel); ok {
+		r0 = rf(postIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetForUser provides a mock function with given fields: userId
 func (_m *FileInfoStore) GetForUser(userId string) store.StoreChannel {
 	ret := _m.Called(userId)
@@ -30,6 +30,38 @@ func (_m *LayeredStoreDatabaseLayer) Audit() store.AuditStore {
 	return r0
 }
 
+// AutoResponderSchedule provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) AutoResponderSchedule() store.AutoResponderScheduleStore {
+	ret := _m.Called()
+
+	var r0 store.AutoResponderScheduleStore
+	if rf, ok := ret.Get(0).(func() store.AutoResponderScheduleStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AutoResponderScheduleStore)
+		}
+	}
+
+	return r0
+}
+
+// Bot provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) Bot() store.BotStore {
+	ret := _m.Called()
+
+	var r0 store.BotStore
+	if rf, ok := ret.Get(0).(func() store.BotStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.BotStore)
+		}
+	}
+
+	return r0
+}
+
 // Channel provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Channel() store.ChannelStore {
 	ret := _m.Called()
@@ -46,6 +78,22 @@ func (_m *LayeredStoreDatabaseLayer) Channel() store.ChannelStore {
 	return r0
 }
 
+// ChannelBridge provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) ChannelBridge() store.ChannelBridgeStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelBridgeStore
+	if rf, ok := ret.Get(0).(func() store.ChannelBridgeStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelBridgeStore)
+		}
+	}
+
+	return r0
+}
+
 // ChannelMemberHistory provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	ret := _m.Called()
@@ -62,6 +110,22 @@ func (_m *LayeredStoreDatabaseLayer) ChannelMemberHistory() store.ChannelMemberH
 	return r0
 }
 
+// ChannelNotifyDefaults provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelNotifyDefaultsStore
+	if rf, ok := ret.Get(0).(func() store.ChannelNotifyDefaultsStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelNotifyDefaultsStore)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Close() {
 	_m.Called()
@@ -136,6 +200,22 @@ func (_m *LayeredStoreDatabaseLayer) DropAllTables() {
 	_m.Called()
 }
 
+// EmailDigest provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) EmailDigest() store.EmailDigestStore {
+	ret := _m.Called()
+
+	var r0 store.EmailDigestStore
+	if rf, ok := ret.Get(0).(func() store.EmailDigestStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EmailDigestStore)
+		}
+	}
+
+	return r0
+}
+
 // Emoji provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Emoji() store.EmojiStore {
 	ret := _m.Called()
@@ -152,6 +232,22 @@ func (_m *LayeredStoreDatabaseLayer) Emoji() store.EmojiStore {
 	return r0
 }
 
+// EventSubscription provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) EventSubscription() store.EventSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.EventSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.EventSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EventSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // FileInfo provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) FileInfo() store.FileInfoStore {
 	ret := _m.Called()
@@ -168,6 +264,22 @@ func (_m *LayeredStoreDatabaseLayer) FileInfo() store.FileInfoStore {
 	return r0
 }
 
+// IntegrationDelivery provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) IntegrationDelivery() store.IntegrationDeliveryStore {
+	ret := _m.Called()
+
+	var r0 store.IntegrationDeliveryStore
+	if rf, ok := ret.Get(0).(func() store.IntegrationDeliveryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.IntegrationDeliveryStore)
+		}
+	}
+
+	return r0
+}
+
 // Job provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Job() store.JobStore {
 	ret := _m.Called()
@@ -200,6 +312,22 @@ func (_m *LayeredStoreDatabaseLayer) License() store.LicenseStore {
 	return r0
 }
 
+// LinkMetadata provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) LinkMetadata() store.LinkMetadataStore {
+	ret := _m.Called()
+
+	var r0 store.LinkMetadataStore
+	if rf, ok := ret.Get(0).(func() store.LinkMetadataStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.LinkMetadataStore)
+		}
+	}
+
+	return r0
+}
+
 // LockToMaster provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) LockToMaster() {
 	_m.Called()
@@ -274,6 +402,38 @@ func (_m *LayeredStoreDatabaseLayer) Post() store.PostStore {
 	return r0
 }
 
+// PostAcknowledgement provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) PostAcknowledgement() store.PostAcknowledgementStore {
+	ret := _m.Called()
+
+	var r0 store.PostAcknowledgementStore
+	if rf, ok := ret.Get(0).(func() store.PostAcknowledgementStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostAcknowledgementStore)
+		}
+	}
+
+	return r0
+}
+
+// PostEvent provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) PostEvent() store.PostEventStore {
+	ret := _m.Called()
+
+	var r0 store.PostEventStore
+	if rf, ok := ret.Get(0).(func() store.PostEventStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostEventStore)
+		}
+	}
+
+	return r0
+}
+
 // Preference provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Preference() store.PreferenceStore {
 	ret := _m.Called()
@@ -421,6 +581,22 @@ func (_m *LayeredStoreDatabaseLayer) ReactionSave(ctx context.Context, reaction
 	return r0
 }
 
+// Reminder provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) Reminder() store.ReminderStore {
+	ret := _m.Called()
+
+	var r0 store.ReminderStore
+	if rf, ok := ret.Get(0).(func() store.ReminderStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ReminderStore)
+		}
+	}
+
+	return r0
+}
+
 // Role provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Role() store.RoleStore {
 	ret := _m.Called()
@@ -750,6 +926,22 @@ func (_m *LayeredStoreDatabaseLayer) SetChainNext(_a0 store.LayeredStoreSupplier
 	_m.Called(_a0)
 }
 
+// SidebarCategory provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) SidebarCategory() store.SidebarCategoryStore {
+	ret := _m.Called()
+
+	var r0 store.SidebarCategoryStore
+	if rf, ok := ret.Get(0).(func() store.SidebarCategoryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SidebarCategoryStore)
+		}
+	}
+
+	return r0
+}
+
 // Status provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Status() store.StatusStore {
 	ret := _m.Called()
@@ -798,6 +990,22 @@ func (_m *LayeredStoreDatabaseLayer) Team() store.TeamStore {
 	return r0
 }
 
+// ThreadMembership provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) ThreadMembership() store.ThreadMembershipStore {
+	ret := _m.Called()
+
+	var r0 store.ThreadMembershipStore
+	if rf, ok := ret.Get(0).(func() store.ThreadMembershipStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ThreadMembershipStore)
+		}
+	}
+
+	return r0
+}
+
 // Token provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Token() store.TokenStore {
 	ret := _m.Called()
@@ -893,6 +1101,38 @@ func (_m *LayeredStoreDatabaseLayer) UserAccessToken() store.UserAccessTokenStor
 	return r0
 }
 
+// UserGroup provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) UserGroup() store.UserGroupStore {
+	ret := _m.Called()
+
+	var r0 store.UserGroupStore
+	if rf, ok := ret.Get(0).(func() store.UserGroupStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.UserGroupStore)
+		}
+	}
+
+	return r0
+}
+
+// WebPushSubscription provides a mock function with given fields:
+func (_m *LayeredStoreDatabaseLayer) WebPushSubscription() store.WebPushSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.WebPushSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.WebPushSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebPushSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // Webhook provides a mock function with given fields:
 func (_m *LayeredStoreDatabaseLayer) Webhook() store.WebhookStore {
 	ret := _m.Called()
@@ -13,6 +13,38 @@ type PluginStore struct {
 	mock.Mock
 }
 
+// CompareAndDelete provides a mock function with given fields: pluginId, key, oldValue
+func (_m *PluginStore) CompareAndDelete(pluginId string, key string, oldValue []byte) store.StoreChannel {
+	ret := _m.Called(pluginId, key, oldValue)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, string, []byte) store.StoreChannel); ok {
+		r0 = rf(pluginId, key, oldValue)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
+// CompareAndSet provides a mock function with given fields: keyVal, oldValue
+func (_m *PluginStore) CompareAndSet(keyVal *model.PluginKeyValue, oldValue []byte) store.StoreChannel {
+	ret := _m.Called(keyVal, oldValue)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(*model.PluginKeyValue, []byte) store.StoreChannel); ok {
+		r0 = rf(keyVal, oldValue)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: pluginId, key
 func (_m *PluginStore) Delete(pluginId string, key string) store.StoreChannel {
 	ret := _m.Called(pluginId, key)
@@ -29,6 +61,22 @@ func (_m *PluginStore) Delete(pluginId string, key string) store.StoreChannel {
 	return r0
 }
 
+// DeleteAllExpired provides a mock function with given fields:
+func (_m *PluginStore) DeleteAllExpired() store.StoreChannel {
+	ret := _m.Called()
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func() store.StoreChannel); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: pluginId, key
 func (_m *PluginStore) Get(pluginId string, key string) store.StoreChannel {
 	ret := _m.Called(pluginId, key)
@@ -45,6 +93,22 @@ func (_m *PluginStore) Get(pluginId string, key string) store.StoreChannel {
 	return r0
 }
 
+// GetTotalSize provides a mock function with given fields: pluginId
+func (_m *PluginStore) GetTotalSize(pluginId string) store.StoreChannel {
+	ret := _m.Called(pluginId)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string) store.StoreChannel); ok {
+		r0 = rf(pluginId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // SaveOrUpdate provides a mock function with given fields: keyVal
 func (_m *PluginStore) SaveOrUpdate(keyVal *model.PluginKeyValue) store.StoreChannel {
 	ret := _m.Called(keyVal)
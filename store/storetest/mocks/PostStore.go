@@ -98,6 +98,22 @@ func (_m *PostStore) Get(id string) store.StoreChannel {
 	return r0
 }
 
+// GetByRemoteId provides a mock function with given fields: remoteId
+func (_m *PostStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	ret := _m.Called(remoteId)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string) store.StoreChannel); ok {
+		r0 = rf(remoteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetEtag provides a mock function with given fields: channelId, allowFromCache
 func (_m *PostStore) GetEtag(channelId string, allowFromCache bool) store.StoreChannel {
 	ret := _m.Called(channelId, allowFromCache)
@@ -258,6 +274,22 @@ func (_m *PostStore) GetPostsBefore(channelId string, postId string, numPosts in
 	return r0
 }
 
+// GetPostsByCursor provides a mock function with given fields: channelId, cursor, before, limit
+func (_m *PostStore) GetPostsByCursor(channelId string, cursor *model.PostCursor, before bool, limit int) store.StoreChannel {
+	ret := _m.Called(channelId, cursor, before, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, *model.PostCursor, bool, int) store.StoreChannel); ok {
+		r0 = rf(channelId, cursor, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetPostsByIds provides a mock function with given fields: postIds
 func (_m *PostStore) GetPostsByIds(postIds []string) store.StoreChannel {
 	ret := _m.Called(postIds)
@@ -306,6 +338,22 @@ func (_m *PostStore) GetPostsSince(channelId string, time int64, allowFromCache
 	return r0
 }
 
+// GetRootPostsForExport provides a mock function with given fields: channelId, offset, limit
+func (_m *PostStore) GetRootPostsForExport(channelId string, offset int, limit int) store.StoreChannel {
+	ret := _m.Called(channelId, offset, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string, int, int) store.StoreChannel); ok {
+		r0 = rf(channelId, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetSingle provides a mock function with given fields: id
 func (_m *PostStore) GetSingle(id string) store.StoreChannel {
 	ret := _m.Called(id)
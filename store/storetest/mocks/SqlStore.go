@@ -44,6 +44,38 @@ func (_m *SqlStore) Audit() store.AuditStore {
 	return r0
 }
 
+// AutoResponderSchedule provides a mock function with given fields:
+func (_m *SqlStore) AutoResponderSchedule() store.AutoResponderScheduleStore {
+	ret := _m.Called()
+
+	var r0 store.AutoResponderScheduleStore
+	if rf, ok := ret.Get(0).(func() store.AutoResponderScheduleStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AutoResponderScheduleStore)
+		}
+	}
+
+	return r0
+}
+
+// Bot provides a mock function with given fields:
+func (_m *SqlStore) Bot() store.BotStore {
+	ret := _m.Called()
+
+	var r0 store.BotStore
+	if rf, ok := ret.Get(0).(func() store.BotStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.BotStore)
+		}
+	}
+
+	return r0
+}
+
 // Channel provides a mock function with given fields:
 func (_m *SqlStore) Channel() store.ChannelStore {
 	ret := _m.Called()
@@ -60,6 +92,38 @@ func (_m *SqlStore) Channel() store.ChannelStore {
 	return r0
 }
 
+// ChannelBridge provides a mock function with given fields:
+func (_m *SqlStore) ChannelBridge() store.ChannelBridgeStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelBridgeStore
+	if rf, ok := ret.Get(0).(func() store.ChannelBridgeStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelBridgeStore)
+		}
+	}
+
+	return r0
+}
+
+// ChannelNotifyDefaults provides a mock function with given fields:
+func (_m *SqlStore) ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelNotifyDefaultsStore
+	if rf, ok := ret.Get(0).(func() store.ChannelNotifyDefaultsStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelNotifyDefaultsStore)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *SqlStore) Close() {
 	_m.Called()
@@ -255,6 +319,22 @@ func (_m *SqlStore) DriverName() string {
 	return r0
 }
 
+// EmailDigest provides a mock function with given fields:
+func (_m *SqlStore) EmailDigest() store.EmailDigestStore {
+	ret := _m.Called()
+
+	var r0 store.EmailDigestStore
+	if rf, ok := ret.Get(0).(func() store.EmailDigestStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EmailDigestStore)
+		}
+	}
+
+	return r0
+}
+
 // Emoji provides a mock function with given fields:
 func (_m *SqlStore) Emoji() store.EmojiStore {
 	ret := _m.Called()
@@ -271,6 +351,22 @@ func (_m *SqlStore) Emoji() store.EmojiStore {
 	return r0
 }
 
+// EventSubscription provides a mock function with given fields:
+func (_m *SqlStore) EventSubscription() store.EventSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.EventSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.EventSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EventSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // FileInfo provides a mock function with given fields:
 func (_m *SqlStore) FileInfo() store.FileInfoStore {
 	ret := _m.Called()
@@ -363,6 +459,22 @@ func (_m *SqlStore) GetReplica() *gorp.DbMap {
 	return r0
 }
 
+// GetReplicaFor provides a mock function with given fields: method
+func (_m *SqlStore) GetReplicaFor(method string) *gorp.DbMap {
+	ret := _m.Called(method)
+
+	var r0 *gorp.DbMap
+	if rf, ok := ret.Get(0).(func(string) *gorp.DbMap); ok {
+		r0 = rf(method)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gorp.DbMap)
+		}
+	}
+
+	return r0
+}
+
 // GetSearchReplica provides a mock function with given fields:
 func (_m *SqlStore) GetSearchReplica() *gorp.DbMap {
 	ret := _m.Called()
@@ -379,6 +491,22 @@ func (_m *SqlStore) GetSearchReplica() *gorp.DbMap {
 	return r0
 }
 
+// IntegrationDelivery provides a mock function with given fields:
+func (_m *SqlStore) IntegrationDelivery() store.IntegrationDeliveryStore {
+	ret := _m.Called()
+
+	var r0 store.IntegrationDeliveryStore
+	if rf, ok := ret.Get(0).(func() store.IntegrationDeliveryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.IntegrationDeliveryStore)
+		}
+	}
+
+	return r0
+}
+
 // Job provides a mock function with given fields:
 func (_m *SqlStore) Job() store.JobStore {
 	ret := _m.Called()
@@ -411,6 +539,22 @@ func (_m *SqlStore) License() store.LicenseStore {
 	return r0
 }
 
+// LinkMetadata provides a mock function with given fields:
+func (_m *SqlStore) LinkMetadata() store.LinkMetadataStore {
+	ret := _m.Called()
+
+	var r0 store.LinkMetadataStore
+	if rf, ok := ret.Get(0).(func() store.LinkMetadataStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.LinkMetadataStore)
+		}
+	}
+
+	return r0
+}
+
 // LockToMaster provides a mock function with given fields:
 func (_m *SqlStore) LockToMaster() {
 	_m.Called()
@@ -469,6 +613,52 @@ func (_m *SqlStore) Post() store.PostStore {
 	return r0
 }
 
+// PostAcknowledgement provides a mock function with given fields:
+func (_m *SqlStore) PostAcknowledgement() store.PostAcknowledgementStore {
+	ret := _m.Called()
+
+	var r0 store.PostAcknowledgementStore
+	if rf, ok := ret.Get(0).(func() store.PostAcknowledgementStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostAcknowledgementStore)
+		}
+	}
+
+	return r0
+}
+
+// PostEvent provides a mock function with given fields:
+func (_m *SqlStore) PostEvent() store.PostEventStore {
+	ret := _m.Called()
+
+	var r0 store.PostEventStore
+	if rf, ok := ret.Get(0).(func() store.PostEventStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostEventStore)
+		}
+	}
+
+	return r0
+}
+
+// PostEventsEnabled provides a mock function with given fields:
+func (_m *SqlStore) PostEventsEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Preference provides a mock function with given fields:
 func (_m *SqlStore) Preference() store.PreferenceStore {
 	ret := _m.Called()
@@ -501,6 +691,22 @@ func (_m *SqlStore) Reaction() store.ReactionStore {
 	return r0
 }
 
+// Reminder provides a mock function with given fields:
+func (_m *SqlStore) Reminder() store.ReminderStore {
+	ret := _m.Called()
+
+	var r0 store.ReminderStore
+	if rf, ok := ret.Get(0).(func() store.ReminderStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ReminderStore)
+		}
+	}
+
+	return r0
+}
+
 // RemoveColumnIfExists provides a mock function with given fields: tableName, columnName
 func (_m *SqlStore) RemoveColumnIfExists(tableName string, columnName string) bool {
 	ret := _m.Called(tableName, columnName)
@@ -605,6 +811,22 @@ func (_m *SqlStore) Session() store.SessionStore {
 	return r0
 }
 
+// SidebarCategory provides a mock function with given fields:
+func (_m *SqlStore) SidebarCategory() store.SidebarCategoryStore {
+	ret := _m.Called()
+
+	var r0 store.SidebarCategoryStore
+	if rf, ok := ret.Get(0).(func() store.SidebarCategoryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SidebarCategoryStore)
+		}
+	}
+
+	return r0
+}
+
 // Status provides a mock function with given fields:
 func (_m *SqlStore) Status() store.StatusStore {
 	ret := _m.Called()
@@ -653,6 +875,22 @@ func (_m *SqlStore) Team() store.TeamStore {
 	return r0
 }
 
+// ThreadMembership provides a mock function with given fields:
+func (_m *SqlStore) ThreadMembership() store.ThreadMembershipStore {
+	ret := _m.Called()
+
+	var r0 store.ThreadMembershipStore
+	if rf, ok := ret.Get(0).(func() store.ThreadMembershipStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ThreadMembershipStore)
+		}
+	}
+
+	return r0
+}
+
 // Token provides a mock function with given fields:
 func (_m *SqlStore) Token() store.TokenStore {
 	ret := _m.Called()
@@ -748,6 +986,38 @@ func (_m *SqlStore) UserAccessToken() store.UserAccessTokenStore {
 	return r0
 }
 
+// UserGroup provides a mock function with given fields:
+func (_m *SqlStore) UserGroup() store.UserGroupStore {
+	ret := _m.Called()
+
+	var r0 store.UserGroupStore
+	if rf, ok := ret.Get(0).(func() store.UserGroupStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.UserGroupStore)
+		}
+	}
+
+	return r0
+}
+
+// WebPushSubscription provides a mock function with given fields:
+func (_m *SqlStore) WebPushSubscription() store.WebPushSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.WebPushSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.WebPushSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebPushSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // Webhook provides a mock function with given fields:
 func (_m *SqlStore) Webhook() store.WebhookStore {
 	ret := _m.Called()
@@ -28,6 +28,38 @@ func (_m *Store) Audit() store.AuditStore {
 	return r0
 }
 
+// AutoResponderSchedule provides a mock function with given fields:
+func (_m *Store) AutoResponderSchedule() store.AutoResponderScheduleStore {
+	ret := _m.Called()
+
+	var r0 store.AutoResponderScheduleStore
+	if rf, ok := ret.Get(0).(func() store.AutoResponderScheduleStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.AutoResponderScheduleStore)
+		}
+	}
+
+	return r0
+}
+
+// Bot provides a mock function with given fields:
+func (_m *Store) Bot() store.BotStore {
+	ret := _m.Called()
+
+	var r0 store.BotStore
+	if rf, ok := ret.Get(0).(func() store.BotStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.BotStore)
+		}
+	}
+
+	return r0
+}
+
 // Channel provides a mock function with given fields:
 func (_m *Store) Channel() store.ChannelStore {
 	ret := _m.Called()
@@ -44,6 +76,22 @@ func (_m *Store) Channel() store.ChannelStore {
 	return r0
 }
 
+// ChannelBridge provides a mock function with given fields:
+func (_m *Store) ChannelBridge() store.ChannelBridgeStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelBridgeStore
+	if rf, ok := ret.Get(0).(func() store.ChannelBridgeStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelBridgeStore)
+		}
+	}
+
+	return r0
+}
+
 // ChannelMemberHistory provides a mock function with given fields:
 func (_m *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	ret := _m.Called()
@@ -60,6 +108,22 @@ func (_m *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	return r0
 }
 
+// ChannelNotifyDefaults provides a mock function with given fields:
+func (_m *Store) ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore {
+	ret := _m.Called()
+
+	var r0 store.ChannelNotifyDefaultsStore
+	if rf, ok := ret.Get(0).(func() store.ChannelNotifyDefaultsStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ChannelNotifyDefaultsStore)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Store) Close() {
 	_m.Called()
@@ -134,6 +198,22 @@ func (_m *Store) DropAllTables() {
 	_m.Called()
 }
 
+// EmailDigest provides a mock function with given fields:
+func (_m *Store) EmailDigest() store.EmailDigestStore {
+	ret := _m.Called()
+
+	var r0 store.EmailDigestStore
+	if rf, ok := ret.Get(0).(func() store.EmailDigestStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EmailDigestStore)
+		}
+	}
+
+	return r0
+}
+
 // Emoji provides a mock function with given fields:
 func (_m *Store) Emoji() store.EmojiStore {
 	ret := _m.Called()
@@ -150,6 +230,22 @@ func (_m *Store) Emoji() store.EmojiStore {
 	return r0
 }
 
+// EventSubscription provides a mock function with given fields:
+func (_m *Store) EventSubscription() store.EventSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.EventSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.EventSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.EventSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // FileInfo provides a mock function with given fields:
 func (_m *Store) FileInfo() store.FileInfoStore {
 	ret := _m.Called()
@@ -166,6 +262,22 @@ func (_m *Store) FileInfo() store.FileInfoStore {
 	return r0
 }
 
+// IntegrationDelivery provides a mock function with given fields:
+func (_m *Store) IntegrationDelivery() store.IntegrationDeliveryStore {
+	ret := _m.Called()
+
+	var r0 store.IntegrationDeliveryStore
+	if rf, ok := ret.Get(0).(func() store.IntegrationDeliveryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.IntegrationDeliveryStore)
+		}
+	}
+
+	return r0
+}
+
 // Job provides a mock function with given fields:
 func (_m *Store) Job() store.JobStore {
 	ret := _m.Called()
@@ -198,6 +310,22 @@ func (_m *Store) License() store.LicenseStore {
 	return r0
 }
 
+// LinkMetadata provides a mock function with given fields:
+func (_m *Store) LinkMetadata() store.LinkMetadataStore {
+	ret := _m.Called()
+
+	var r0 store.LinkMetadataStore
+	if rf, ok := ret.Get(0).(func() store.LinkMetadataStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.LinkMetadataStore)
+		}
+	}
+
+	return r0
+}
+
 // LockToMaster provides a mock function with given fields:
 func (_m *Store) LockToMaster() {
 	_m.Called()
@@ -256,6 +384,38 @@ func (_m *Store) Post() store.PostStore {
 	return r0
 }
 
+// PostAcknowledgement provides a mock function with given fields:
+func (_m *Store) PostAcknowledgement() store.PostAcknowledgementStore {
+	ret := _m.Called()
+
+	var r0 store.PostAcknowledgementStore
+	if rf, ok := ret.Get(0).(func() store.PostAcknowledgementStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostAcknowledgementStore)
+		}
+	}
+
+	return r0
+}
+
+// PostEvent provides a mock function with given fields:
+func (_m *Store) PostEvent() store.PostEventStore {
+	ret := _m.Called()
+
+	var r0 store.PostEventStore
+	if rf, ok := ret.Get(0).(func() store.PostEventStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.PostEventStore)
+		}
+	}
+
+	return r0
+}
+
 // Preference provides a mock function with given fields:
 func (_m *Store) Preference() store.PreferenceStore {
 	ret := _m.Called()
@@ -288,6 +448,22 @@ func (_m *Store) Reaction() store.ReactionStore {
 	return r0
 }
 
+// Reminder provides a mock function with given fields:
+func (_m *Store) Reminder() store.ReminderStore {
+	ret := _m.Called()
+
+	var r0 store.ReminderStore
+	if rf, ok := ret.Get(0).(func() store.ReminderStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ReminderStore)
+		}
+	}
+
+	return r0
+}
+
 // Role provides a mock function with given fields:
 func (_m *Store) Role() store.RoleStore {
 	ret := _m.Called()
@@ -336,6 +512,22 @@ func (_m *Store) Session() store.SessionStore {
 	return r0
 }
 
+// SidebarCategory provides a mock function with given fields:
+func (_m *Store) SidebarCategory() store.SidebarCategoryStore {
+	ret := _m.Called()
+
+	var r0 store.SidebarCategoryStore
+	if rf, ok := ret.Get(0).(func() store.SidebarCategoryStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SidebarCategoryStore)
+		}
+	}
+
+	return r0
+}
+
 // Status provides a mock function with given fields:
 func (_m *Store) Status() store.StatusStore {
 	ret := _m.Called()
@@ -384,6 +576,22 @@ func (_m *Store) Team() store.TeamStore {
 	return r0
 }
 
+// ThreadMembership provides a mock function with given fields:
+func (_m *Store) ThreadMembership() store.ThreadMembershipStore {
+	ret := _m.Called()
+
+	var r0 store.ThreadMembershipStore
+	if rf, ok := ret.Get(0).(func() store.ThreadMembershipStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.ThreadMembershipStore)
+		}
+	}
+
+	return r0
+}
+
 // Token provides a mock function with given fields:
 func (_m *Store) Token() store.TokenStore {
 	ret := _m.Called()
@@ -479,6 +687,38 @@ func (_m *Store) UserAccessToken() store.UserAccessTokenStore {
 	return r0
 }
 
+// UserGroup provides a mock function with given fields:
+func (_m *Store) UserGroup() store.UserGroupStore {
+	ret := _m.Called()
+
+	var r0 store.UserGroupStore
+	if rf, ok := ret.Get(0).(func() store.UserGroupStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.UserGroupStore)
+		}
+	}
+
+	return r0
+}
+
+// WebPushSubscription provides a mock function with given fields:
+func (_m *Store) WebPushSubscription() store.WebPushSubscriptionStore {
+	ret := _m.Called()
+
+	var r0 store.WebPushSubscriptionStore
+	if rf, ok := ret.Get(0).(func() store.WebPushSubscriptionStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.WebPushSubscriptionStore)
+		}
+	}
+
+	return r0
+}
+
 // Webhook provides a mock function with given fields:
 func (_m *Store) Webhook() store.WebhookStore {
 	ret := _m.Called()
@@ -210,6 +210,22 @@ func (_m *UserStore) GetByEmail(email string) store.StoreChannel {
 	return r0
 }
 
+// GetByRemoteId provides a mock function with given fields: remoteId
+func (_m *UserStore) GetByRemoteId(remoteId string) store.StoreChannel {
+	ret := _m.Called(remoteId)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string) store.StoreChannel); ok {
+		r0 = rf(remoteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetByUsername provides a mock function with given fields: username
 func (_m *UserStore) GetByUsername(username string) store.StoreChannel {
 	ret := _m.Called(username)
@@ -226,6 +242,22 @@ func (_m *UserStore) GetByUsername(username string) store.StoreChannel {
 	return r0
 }
 
+// GetDirectory provides a mock function with given fields: options, offset, limit
+func (_m *UserStore) GetDirectory(options *model.UserDirectoryOptions, offset int, limit int) store.StoreChannel {
+	ret := _m.Called(options, offset, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(*model.UserDirectoryOptions, int, int) store.StoreChannel); ok {
+		r0 = rf(options, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetEtagForAllProfiles provides a mock function with given fields:
 func (_m *UserStore) GetEtagForAllProfiles() store.StoreChannel {
 	ret := _m.Called()
@@ -290,6 +322,22 @@ func (_m *UserStore) GetForLogin(loginId string, allowSignInWithUsername bool, a
 	return r0
 }
 
+// GetInactive provides a mock function with given fields: lastActivityBefore, offset, limit
+func (_m *UserStore) GetInactive(lastActivityBefore int64, offset int, limit int) store.StoreChannel {
+	ret := _m.Called(lastActivityBefore, offset, limit)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(int64, int, int) store.StoreChannel); ok {
+		r0 = rf(lastActivityBefore, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // GetNewUsersForTeam provides a mock function with given fields: teamId, offset, limit
 func (_m *UserStore) GetNewUsersForTeam(teamId string, offset int, limit int) store.StoreChannel {
 	ret := _m.Called(teamId, offset, limit)
@@ -514,6 +562,22 @@ func (_m *UserStore) GetUnreadCountForChannel(userId string, channelId string) s
 	return r0
 }
 
+// GetUnreadCountFromMaster provides a mock function with given fields: userId
+func (_m *UserStore) GetUnreadCountFromMaster(userId string) store.StoreChannel {
+	ret := _m.Called(userId)
+
+	var r0 store.StoreChannel
+	if rf, ok := ret.Get(0).(func(string) store.StoreChannel); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.StoreChannel)
+		}
+	}
+
+	return r0
+}
+
 // InferSystemInstallDate provides a mock function with given fields:
 func (_m *UserStore) InferSystemInstallDate() store.StoreChannel {
 	ret := _m.Called()
@@ -178,6 +178,7 @@ func testOAuthUpdateAccessData(t *testing.T, ss store.Store) {
 
 	// Should update fine
 	a1.RedirectUri = "http://example.com"
+	a1.Scope = model.OAUTH_SCOPE_READ_POSTS
 	if result := <-ss.OAuth().UpdateAccessData(&a1); result.Err != nil {
 		t.Fatal(result.Err)
 	} else {
@@ -186,6 +187,12 @@ func testOAuthUpdateAccessData(t *testing.T, ss store.Store) {
 			t.Fatal("refresh tokens didn't match")
 		}
 	}
+
+	if result := <-ss.OAuth().GetAccessData(a1.Token); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.AccessData).Scope != model.OAUTH_SCOPE_READ_POSTS {
+		t.Fatal("should have updated the scope")
+	}
 }
 
 func testOAuthStoreGetAccessData(t *testing.T, ss store.Store) {
@@ -4,16 +4,22 @@
 package storetest
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/store"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPluginStore(t *testing.T, ss store.Store) {
 	t.Run("PluginSaveGet", func(t *testing.T) { testPluginSaveGet(t, ss) })
 	t.Run("PluginDelete", func(t *testing.T) { testPluginDelete(t, ss) })
+	t.Run("PluginExpiry", func(t *testing.T) { testPluginExpiry(t, ss) })
+	t.Run("PluginCompareAndSet", func(t *testing.T) { testPluginCompareAndSet(t, ss) })
+	t.Run("PluginCompareAndDelete", func(t *testing.T) { testPluginCompareAndDelete(t, ss) })
+	t.Run("PluginGetTotalSize", func(t *testing.T) { testPluginGetTotalSize(t, ss) })
 }
 
 func testPluginSaveGet(t *testing.T, ss store.Store) {
@@ -67,3 +73,164 @@ func testPluginDelete(t *testing.T, ss store.Store) {
 		t.Fatal(result.Err)
 	}
 }
+
+func testPluginExpiry(t *testing.T, ss store.Store) {
+	pluginId := model.NewId()
+
+	expired := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      model.NewId(),
+		Value:    []byte(model.NewId()),
+		ExpireAt: model.GetMillis() - 1000,
+	})).(*model.PluginKeyValue)
+
+	unexpired := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      model.NewId(),
+		Value:    []byte(model.NewId()),
+		ExpireAt: model.GetMillis() + 60*1000,
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(pluginId, unexpired.Key)
+	}()
+
+	result := <-ss.Plugin().Get(pluginId, expired.Key)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.Plugin().Get(pluginId, unexpired.Key)
+	require.Nil(t, result.Err)
+	assert.Equal(t, unexpired.Value, result.Data.(*model.PluginKeyValue).Value)
+	assert.Equal(t, unexpired.ExpireAt, result.Data.(*model.PluginKeyValue).ExpireAt)
+
+	result = <-ss.Plugin().DeleteAllExpired()
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(int64) >= 1)
+
+	// The expired value is gone, so it can be set again as if it had never been set
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: pluginId, Key: expired.Key, Value: []byte("new")}, nil)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool))
+	<-ss.Plugin().Delete(pluginId, expired.Key)
+
+	result = <-ss.Plugin().Get(pluginId, unexpired.Key)
+	require.Nil(t, result.Err, "should not delete values that haven't expired")
+}
+
+func testPluginCompareAndSet(t *testing.T, ss store.Store) {
+	kv := &model.PluginKeyValue{
+		PluginId: model.NewId(),
+		Key:      model.NewId(),
+		Value:    []byte("first"),
+	}
+	defer func() {
+		<-ss.Plugin().Delete(kv.PluginId, kv.Key)
+	}()
+
+	result := <-ss.Plugin().CompareAndSet(kv, nil)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool), "should set a value that doesn't exist")
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: kv.PluginId, Key: kv.Key, Value: []byte("second")}, nil)
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(bool), "should not set a value that already exists")
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: kv.PluginId, Key: kv.Key, Value: []byte("second")}, []byte("wrong"))
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(bool), "should not set a value when the old value doesn't match")
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: kv.PluginId, Key: kv.Key, Value: []byte("second")}, []byte("first"))
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool))
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: kv.PluginId, Key: kv.Key, Value: []byte("second")}, []byte("second"))
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool), "should succeed when the value doesn't change")
+
+	result = <-ss.Plugin().Get(kv.PluginId, kv.Key)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []byte("second"), result.Data.(*model.PluginKeyValue).Value)
+
+	// A lock that has expired can be taken by someone else
+	lock := &model.PluginKeyValue{PluginId: kv.PluginId, Key: model.NewId(), Value: []byte("owner1"), ExpireAt: model.GetMillis() - 1000}
+	store.Must(ss.Plugin().SaveOrUpdate(lock))
+	defer func() {
+		<-ss.Plugin().Delete(lock.PluginId, lock.Key)
+	}()
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: lock.PluginId, Key: lock.Key, Value: []byte("owner2")}, []byte("owner1"))
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(bool), "should not match a value that has expired")
+
+	result = <-ss.Plugin().CompareAndSet(&model.PluginKeyValue{PluginId: lock.PluginId, Key: lock.Key, Value: []byte("owner2")}, nil)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool), "should replace a value that has expired")
+}
+
+func testPluginCompareAndDelete(t *testing.T, ss store.Store) {
+	kv := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: model.NewId(),
+		Key:      model.NewId(),
+		Value:    []byte("value"),
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(kv.PluginId, kv.Key)
+	}()
+
+	result := <-ss.Plugin().CompareAndDelete(kv.PluginId, kv.Key, []byte("wrong"))
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(bool))
+
+	result = <-ss.Plugin().CompareAndDelete(kv.PluginId, kv.Key, []byte("value"))
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(bool))
+
+	result = <-ss.Plugin().Get(kv.PluginId, kv.Key)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testPluginGetTotalSize(t *testing.T, ss store.Store) {
+	pluginId := model.NewId()
+
+	assert.Equal(t, int64(0), store.Must(ss.Plugin().GetTotalSize(pluginId)).(int64))
+
+	kv1 := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      "key1",
+		Value:    []byte("value"),
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(kv1.PluginId, kv1.Key)
+	}()
+
+	kv2 := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      "key2",
+		Value:    []byte("longer value"),
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(kv2.PluginId, kv2.Key)
+	}()
+
+	expired := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: pluginId,
+		Key:      "key3",
+		Value:    []byte("expired value"),
+		ExpireAt: model.GetMillis() - 1000,
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(expired.PluginId, expired.Key)
+	}()
+
+	other := store.Must(ss.Plugin().SaveOrUpdate(&model.PluginKeyValue{
+		PluginId: model.NewId(),
+		Key:      "key1",
+		Value:    []byte("other plugin's value"),
+	})).(*model.PluginKeyValue)
+	defer func() {
+		<-ss.Plugin().Delete(other.PluginId, other.Key)
+	}()
+
+	assert.Equal(t, int64(len("key1")+len("value")+len("key2")+len("longer value")), store.Must(ss.Plugin().GetTotalSize(pluginId)).(int64))
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestPostAcknowledgementStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testPostAcknowledgementStoreSave(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testPostAcknowledgementStoreDelete(t, ss) })
+}
+
+func testPostAcknowledgementStoreSave(t *testing.T, ss store.Store) {
+	postId := model.NewId()
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+
+	result := <-ss.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: userId1, PostId: postId, AcknowledgedAt: 1000})
+	require.Nil(t, result.Err)
+
+	result = <-ss.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: userId2, PostId: postId, AcknowledgedAt: 2000})
+	require.Nil(t, result.Err)
+
+	result = <-ss.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: userId1, PostId: postId, AcknowledgedAt: 3000})
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(1000), result.Data.(*model.PostAcknowledgement).AcknowledgedAt, "should keep the first acknowledgement")
+
+	result = <-ss.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: "junk", PostId: postId})
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.PostAcknowledgement().GetForPost(postId)
+	require.Nil(t, result.Err)
+	acknowledgements := result.Data.([]*model.PostAcknowledgement)
+	require.Len(t, acknowledgements, 2)
+	assert.Equal(t, userId1, acknowledgements[0].UserId)
+	assert.Equal(t, userId2, acknowledgements[1].UserId)
+
+	result = <-ss.PostAcknowledgement().GetForPost(model.NewId())
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.PostAcknowledgement), 0)
+}
+
+func testPostAcknowledgementStoreDelete(t *testing.T, ss store.Store) {
+	postId := model.NewId()
+	userId := model.NewId()
+
+	store.Must(ss.PostAcknowledgement().Save(&model.PostAcknowledgement{UserId: userId, PostId: postId}))
+
+	result := <-ss.PostAcknowledgement().Delete(userId, postId)
+	require.Nil(t, result.Err)
+
+	result = <-ss.PostAcknowledgement().GetForPost(postId)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.PostAcknowledgement), 0)
+
+	result = <-ss.PostAcknowledgement().Delete(userId, postId)
+	assert.Nil(t, result.Err, "deleting a missing acknowledgement shouldn't fail")
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestPostEventStore(t *testing.T, ss store.Store) {
+	t.Run("PostMutations", func(t *testing.T) { testPostEventStorePostMutations(t, ss) })
+	t.Run("Cursor", func(t *testing.T) { testPostEventStoreCursor(t, ss) })
+}
+
+func getPostEvents(t *testing.T, ss store.Store, channelId string, afterSeq int64) []*model.PostEvent {
+	result := <-ss.PostEvent().GetForChannel(channelId, afterSeq, model.GetMillis()+1, model.POST_EVENTS_MAX_LIMIT)
+	require.Nil(t, result.Err)
+	return result.Data.([]*model.PostEvent)
+}
+
+func testPostEventStorePostMutations(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+	userId := model.NewId()
+
+	post := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, Message: "original"})).(*model.Post)
+
+	edited := *post
+	edited.Message = "edited"
+	oldPost := *post
+	store.Must(ss.Post().Update(&edited, &oldPost))
+
+	reaction := &model.Reaction{UserId: userId, PostId: post.Id, EmojiName: "smile"}
+	store.Must(ss.Reaction().Save(reaction))
+	store.Must(ss.Reaction().Delete(reaction))
+
+	store.Must(ss.Post().Delete(post.Id, model.GetMillis(), userId))
+
+	events := getPostEvents(t, ss, channelId, 0)
+	require.Len(t, events, 5)
+
+	expectedTypes := []string{
+		model.POST_EVENT_TYPE_CREATED,
+		model.POST_EVENT_TYPE_EDITED,
+		model.POST_EVENT_TYPE_REACTION_ADDED,
+		model.POST_EVENT_TYPE_REACTION_REMOVED,
+		model.POST_EVENT_TYPE_DELETED,
+	}
+	for i, event := range events {
+		assert.Equal(t, expectedTypes[i], event.Type)
+		assert.Equal(t, channelId, event.ChannelId, "reaction events should take the channel from the post")
+		assert.Equal(t, post.Id, event.PostId)
+		if i > 0 {
+			assert.True(t, event.Seq > events[i-1].Seq)
+		}
+	}
+
+	assert.Contains(t, events[1].Data, "edited")
+	assert.Contains(t, events[2].Data, "smile")
+}
+
+func testPostEventStoreCursor(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+	userId := model.NewId()
+
+	result := <-ss.PostEvent().GetLatestSeq(channelId, model.GetMillis()+1)
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(0), result.Data.(int64))
+
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, Message: "first"}))
+	store.Must(ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: userId, Message: "elsewhere"}))
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, Message: "second"}))
+
+	events := getPostEvents(t, ss, channelId, 0)
+	require.Len(t, events, 2)
+
+	result = <-ss.PostEvent().GetLatestSeq(channelId, model.GetMillis()+1)
+	require.Nil(t, result.Err)
+	assert.Equal(t, events[1].Seq, result.Data.(int64))
+
+	after := getPostEvents(t, ss, channelId, events[0].Seq)
+	require.Len(t, after, 1)
+	assert.Equal(t, events[1].Seq, after[0].Seq)
+
+	result = <-ss.PostEvent().GetForChannel(channelId, 0, model.GetMillis()+1, 1)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.PostEvent), 1)
+
+	result = <-ss.PostEvent().GetForChannel(channelId, 0, events[0].CreateAt, model.POST_EVENTS_MAX_LIMIT)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.PostEvent), 0, "should leave out events that might not have been committed yet")
+}
@@ -5,6 +5,8 @@ package storetest
 
 import (
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -20,6 +22,7 @@ func TestPostStore(t *testing.T, ss store.Store) {
 	t.Run("Save", func(t *testing.T) { testPostStoreSave(t, ss) })
 	t.Run("Get", func(t *testing.T) { testPostStoreGet(t, ss) })
 	t.Run("GetSingle", func(t *testing.T) { testPostStoreGetSingle(t, ss) })
+	t.Run("GetByRemoteId", func(t *testing.T) { testPostStoreGetByRemoteId(t, ss) })
 	t.Run("GetEtagCache", func(t *testing.T) { testGetEtagCache(t, ss) })
 	t.Run("Update", func(t *testing.T) { testPostStoreUpdate(t, ss) })
 	t.Run("Delete", func(t *testing.T) { testPostStoreDelete(t, ss) })
@@ -30,6 +33,7 @@ func TestPostStore(t *testing.T, ss store.Store) {
 	t.Run("GetWithChildren", func(t *testing.T) { testPostStoreGetWithChildren(t, ss) })
 	t.Run("GetPostsWithDetails", func(t *testing.T) { testPostStoreGetPostsWithDetails(t, ss) })
 	t.Run("GetPostsBeforeAfter", func(t *testing.T) { testPostStoreGetPostsBeforeAfter(t, ss) })
+	t.Run("GetPostsByCursor", func(t *testing.T) { testPostStoreGetPostsByCursor(t, ss) })
 	t.Run("GetPostsSince", func(t *testing.T) { testPostStoreGetPostsSince(t, ss) })
 	t.Run("Search", func(t *testing.T) { testPostStoreSearch(t, ss) })
 	t.Run("UserCountsWithPostsByDay", func(t *testing.T) { testUserCountsWithPostsByDay(t, ss) })
@@ -44,6 +48,7 @@ func TestPostStore(t *testing.T, ss store.Store) {
 	t.Run("PermanentDeleteBatch", func(t *testing.T) { testPostStorePermanentDeleteBatch(t, ss) })
 	t.Run("GetOldest", func(t *testing.T) { testPostStoreGetOldest(t, ss) })
 	t.Run("TestGetMaxPostSize", func(t *testing.T) { testGetMaxPostSize(t, ss) })
+	t.Run("GetRootPostsForExport", func(t *testing.T) { testPostStoreGetRootPostsForExport(t, ss) })
 }
 
 func testPostStoreSave(t *testing.T, ss store.Store) {
@@ -726,6 +731,93 @@ func testPostStoreGetPostsBeforeAfter(t *testing.T, ss store.Store) {
 	}
 }
 
+func testPostStoreGetPostsByCursor(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+	createAt := model.GetMillis()
+
+	// The middle three posts share a CreateAt, so they can only be told apart by Id
+	var posts []*model.Post
+	for i, offset := range []int64{0, 1, 1, 1, 2} {
+		post := &model.Post{
+			ChannelId: channelId,
+			UserId:    model.NewId(),
+			Message:   "zz" + model.NewId() + "b",
+			CreateAt:  createAt + offset,
+		}
+		if i == 4 {
+			post.RootId = posts[0].Id
+			post.ParentId = posts[0].Id
+		}
+
+		result := <-ss.Post().Save(post)
+		require.Nil(t, result.Err)
+		posts = append(posts, result.Data.(*model.Post))
+	}
+
+	// Posts are returned newest first, and the ones made in the same millisecond are ordered by Id
+	sorted := make([]*model.Post, len(posts))
+	copy(sorted, posts)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreateAt != sorted[j].CreateAt {
+			return sorted[i].CreateAt > sorted[j].CreateAt
+		}
+		return sorted[i].Id > sorted[j].Id
+	})
+
+	var expected []string
+	for _, post := range sorted {
+		expected = append(expected, post.Id)
+	}
+
+	t.Run("before", func(t *testing.T) {
+		var seen []string
+		cursor := &model.PostCursor{CreateAt: createAt + 3, Id: model.NewId()}
+		for {
+			result := <-ss.Post().GetPostsByCursor(channelId, cursor, true, 2)
+			require.Nil(t, result.Err)
+
+			list := result.Data.(*model.PostList)
+			if len(list.Order) == 0 {
+				break
+			}
+
+			seen = append(seen, list.Order...)
+			oldest := list.Posts[list.Order[len(list.Order)-1]]
+			cursor = model.NewPostCursor(oldest)
+		}
+
+		assert.Equal(t, expected, seen)
+	})
+
+	t.Run("after", func(t *testing.T) {
+		var seen []string
+		cursor := &model.PostCursor{CreateAt: createAt - 1, Id: model.NewId()}
+		for {
+			result := <-ss.Post().GetPostsByCursor(channelId, cursor, false, 2)
+			require.Nil(t, result.Err)
+
+			list := result.Data.(*model.PostList)
+			if len(list.Order) == 0 {
+				break
+			}
+
+			seen = append(list.Order, seen...)
+			cursor = model.NewPostCursor(list.Posts[list.Order[0]])
+		}
+
+		assert.Equal(t, expected, seen)
+	})
+
+	t.Run("includes thread roots", func(t *testing.T) {
+		result := <-ss.Post().GetPostsByCursor(channelId, &model.PostCursor{CreateAt: createAt + 3, Id: model.NewId()}, true, 1)
+		require.Nil(t, result.Err)
+
+		list := result.Data.(*model.PostList)
+		assert.Equal(t, []string{posts[4].Id}, list.Order)
+		assert.NotNil(t, list.Posts[posts[0].Id])
+	})
+}
+
 func testPostStoreGetPostsSince(t *testing.T, ss store.Store) {
 	o0 := &model.Post{}
 	o0.ChannelId = model.NewId()
@@ -1814,3 +1906,67 @@ func testGetMaxPostSize(t *testing.T, ss store.Store) {
 	assert.Equal(t, model.POST_MESSAGE_MAX_RUNES_V2, (<-ss.Post().GetMaxPostSize()).Data.(int))
 	assert.Equal(t, model.POST_MESSAGE_MAX_RUNES_V2, (<-ss.Post().GetMaxPostSize()).Data.(int))
 }
+
+func testPostStoreGetRootPostsForExport(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+
+	o1 := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), Message: "zz" + model.NewId(), CreateAt: 2000})).(*model.Post)
+	o2 := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), Message: "zz" + model.NewId(), CreateAt: 1000})).(*model.Post)
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), Message: "zz" + model.NewId(), CreateAt: 3000, RootId: o2.Id, ParentId: o2.Id}))
+	o4 := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), Message: "zz" + model.NewId(), CreateAt: 4000})).(*model.Post)
+	store.Must(ss.Post().Delete(o4.Id, model.GetMillis(), ""))
+	store.Must(ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "zz" + model.NewId(), CreateAt: 1000}))
+
+	result := <-ss.Post().GetRootPostsForExport(channelId, 0, 10)
+	require.Nil(t, result.Err)
+	posts := result.Data.([]*model.Post)
+	require.Len(t, posts, 2)
+	assert.Equal(t, o2.Id, posts[0].Id)
+	assert.Equal(t, o1.Id, posts[1].Id)
+
+	result = <-ss.Post().GetRootPostsForExport(channelId, 1, 10)
+	require.Nil(t, result.Err)
+	posts = result.Data.([]*model.Post)
+	require.Len(t, posts, 1)
+	assert.Equal(t, o1.Id, posts[0].Id)
+}
+
+func testPostStoreGetByRemoteId(t *testing.T, ss store.Store) {
+	remoteId := model.NewRemoteId("slack", model.NewId())
+
+	o1 := &model.Post{}
+	o1.ChannelId = model.NewId()
+	o1.UserId = model.NewId()
+	o1.Message = "zz" + model.NewId() + "b"
+	o1.RemoteId = model.NewString(remoteId)
+	o1 = store.Must(ss.Post().Save(o1)).(*model.Post)
+
+	r1 := <-ss.Post().GetByRemoteId(remoteId)
+	require.Nil(t, r1.Err)
+	assert.Equal(t, o1.Id, r1.Data.(*model.Post).Id)
+
+	r2 := <-ss.Post().GetByRemoteId(model.NewRemoteId("slack", model.NewId()))
+	require.NotNil(t, r2.Err)
+	assert.Equal(t, http.StatusNotFound, r2.Err.StatusCode)
+
+	o2 := &model.Post{}
+	o2.ChannelId = o1.ChannelId
+	o2.UserId = o1.UserId
+	o2.Message = "zz" + model.NewId() + "b"
+	o2.RemoteId = model.NewString(remoteId)
+	r3 := <-ss.Post().Save(o2)
+	require.NotNil(t, r3.Err, "remote ids should be unique")
+	assert.Equal(t, "store.sql_post.save.remote_id_exists.app_error", r3.Err.Id)
+
+	// editing the post keeps its remote id without copying it to the edit history
+	edited := *o1
+	edited.Message = "edited"
+	old := *o1
+	r4 := <-ss.Post().Update(&edited, &old)
+	require.Nil(t, r4.Err)
+
+	r5 := <-ss.Post().GetByRemoteId(remoteId)
+	require.Nil(t, r5.Err)
+	assert.Equal(t, o1.Id, r5.Data.(*model.Post).Id)
+	assert.Equal(t, "edited", r5.Data.(*model.Post).Message)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestReminderStore(t *testing.T, ss store.Store) {
+	t.Run("SaveGetDelete", func(t *testing.T) { testReminderStoreSaveGetDelete(t, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testReminderStoreGetForUser(t, ss) })
+	t.Run("GetDue", func(t *testing.T) { testReminderStoreGetDue(t, ss) })
+}
+
+func saveTestReminder(t *testing.T, ss store.Store, userId string, remindAt int64) *model.Reminder {
+	result := <-ss.Reminder().Save(&model.Reminder{
+		UserId:    userId,
+		ChannelId: model.NewId(),
+		Message:   "call Bob",
+		RemindAt:  remindAt,
+	})
+	require.Nil(t, result.Err)
+
+	return result.Data.(*model.Reminder)
+}
+
+func testReminderStoreSaveGetDelete(t *testing.T, ss store.Store) {
+	reminder := saveTestReminder(t, ss, model.NewId(), model.GetMillis()+60000)
+
+	result := <-ss.Reminder().Get(reminder.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, reminder, result.Data.(*model.Reminder))
+
+	result = <-ss.Reminder().Save(&model.Reminder{UserId: model.NewId(), ChannelId: model.NewId(), Message: "call Bob", RemindAt: 1})
+	assert.NotNil(t, result.Err, "shouldn't save a reminder in the past")
+
+	result = <-ss.Reminder().Delete(reminder.Id)
+	require.Nil(t, result.Err)
+
+	result = <-ss.Reminder().Get(reminder.Id)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testReminderStoreGetForUser(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	now := model.GetMillis()
+
+	later := saveTestReminder(t, ss, userId, now+120000)
+	sooner := saveTestReminder(t, ss, userId, now+60000)
+	saveTestReminder(t, ss, model.NewId(), now+60000)
+
+	result := <-ss.Reminder().GetForUser(userId)
+	require.Nil(t, result.Err)
+	assert.Equal(t, []*model.Reminder{sooner, later}, result.Data.([]*model.Reminder))
+
+	result = <-ss.Reminder().GetForUser(model.NewId())
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.Reminder), 0)
+}
+
+func testReminderStoreGetDue(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	now := model.GetMillis()
+
+	first := saveTestReminder(t, ss, userId, now+60000)
+	second := saveTestReminder(t, ss, userId, now+120000)
+	notDue := saveTestReminder(t, ss, userId, now+600000)
+
+	result := <-ss.Reminder().GetDue(now+180000, 1000)
+	require.Nil(t, result.Err)
+
+	due := map[string]bool{}
+	for _, reminder := range result.Data.([]*model.Reminder) {
+		due[reminder.Id] = true
+	}
+	assert.True(t, due[first.Id])
+	assert.True(t, due[second.Id])
+	assert.False(t, due[notDue.Id])
+
+	result = <-ss.Reminder().GetDue(now+180000, 1)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.Reminder), 1)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestSidebarCategoryStore(t *testing.T, ss store.Store) {
+	t.Run("SaveGet", func(t *testing.T) { testSidebarCategoryStoreSaveGet(t, ss) })
+	t.Run("GetForTeam", func(t *testing.T) { testSidebarCategoryStoreGetForTeam(t, ss) })
+	t.Run("UpdateDelete", func(t *testing.T) { testSidebarCategoryStoreUpdateDelete(t, ss) })
+}
+
+func saveTestSidebarCategory(t *testing.T, ss store.Store, userId string, teamId string, sortOrder int64) *model.SidebarCategory {
+	result := <-ss.SidebarCategory().Save(&model.SidebarCategory{
+		UserId:      userId,
+		TeamId:      teamId,
+		DisplayName: "Category",
+		SortOrder:   sortOrder,
+		ChannelIds:  model.StringArray{model.NewId(), model.NewId()},
+	})
+	require.Nil(t, result.Err)
+
+	return result.Data.(*model.SidebarCategory)
+}
+
+func testSidebarCategoryStoreSaveGet(t *testing.T, ss store.Store) {
+	category := saveTestSidebarCategory(t, ss, model.NewId(), model.NewId(), 0)
+	defer func() { <-ss.SidebarCategory().Delete(category.Id) }()
+
+	result := <-ss.SidebarCategory().Get(category.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, category, result.Data.(*model.SidebarCategory))
+
+	result = <-ss.SidebarCategory().Save(category)
+	require.NotNil(t, result.Err, "shouldn't save an existing category")
+
+	result = <-ss.SidebarCategory().Save(&model.SidebarCategory{UserId: model.NewId(), TeamId: model.NewId()})
+	require.NotNil(t, result.Err, "shouldn't save an invalid category")
+
+	result = <-ss.SidebarCategory().Get(model.NewId())
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testSidebarCategoryStoreGetForTeam(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	teamId := model.NewId()
+
+	second := saveTestSidebarCategory(t, ss, userId, teamId, 10)
+	defer func() { <-ss.SidebarCategory().Delete(second.Id) }()
+	first := saveTestSidebarCategory(t, ss, userId, teamId, 0)
+	defer func() { <-ss.SidebarCategory().Delete(first.Id) }()
+	otherTeam := saveTestSidebarCategory(t, ss, userId, model.NewId(), 0)
+	defer func() { <-ss.SidebarCategory().Delete(otherTeam.Id) }()
+	otherUser := saveTestSidebarCategory(t, ss, model.NewId(), teamId, 0)
+	defer func() { <-ss.SidebarCategory().Delete(otherUser.Id) }()
+
+	result := <-ss.SidebarCategory().GetForTeam(userId, teamId)
+	require.Nil(t, result.Err)
+	categories := result.Data.([]*model.SidebarCategory)
+	require.Len(t, categories, 2)
+	assert.Equal(t, first.Id, categories[0].Id)
+	assert.Equal(t, second.Id, categories[1].Id)
+
+	result = <-ss.SidebarCategory().GetForTeam(model.NewId(), teamId)
+	require.Nil(t, result.Err)
+	assert.Empty(t, result.Data.([]*model.SidebarCategory))
+}
+
+func testSidebarCategoryStoreUpdateDelete(t *testing.T, ss store.Store) {
+	category := saveTestSidebarCategory(t, ss, model.NewId(), model.NewId(), 0)
+	defer func() { <-ss.SidebarCategory().Delete(category.Id) }()
+
+	category.DisplayName = "Renamed"
+	category.ChannelIds = model.StringArray{model.NewId()}
+	result := <-ss.SidebarCategory().Update(category)
+	require.Nil(t, result.Err)
+
+	result = <-ss.SidebarCategory().Get(category.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, "Renamed", result.Data.(*model.SidebarCategory).DisplayName)
+	assert.Equal(t, category.ChannelIds, result.Data.(*model.SidebarCategory).ChannelIds)
+
+	category.DisplayName = ""
+	result = <-ss.SidebarCategory().Update(category)
+	require.NotNil(t, result.Err)
+
+	result = <-ss.SidebarCategory().Delete(category.Id)
+	require.Nil(t, result.Err)
+
+	result = <-ss.SidebarCategory().Get(category.Id)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
@@ -19,59 +19,99 @@ func NewStoreChannel(result store.StoreResult) store.StoreChannel {
 
 // Store can be used to provide mock stores for testing.
 type Store struct {
-	TeamStore                 mocks.TeamStore
-	ChannelStore              mocks.ChannelStore
-	PostStore                 mocks.PostStore
-	UserStore                 mocks.UserStore
-	AuditStore                mocks.AuditStore
-	ClusterDiscoveryStore     mocks.ClusterDiscoveryStore
-	ComplianceStore           mocks.ComplianceStore
-	SessionStore              mocks.SessionStore
-	OAuthStore                mocks.OAuthStore
-	SystemStore               mocks.SystemStore
-	WebhookStore              mocks.WebhookStore
-	CommandStore              mocks.CommandStore
-	CommandWebhookStore       mocks.CommandWebhookStore
-	PreferenceStore           mocks.PreferenceStore
-	LicenseStore              mocks.LicenseStore
-	TokenStore                mocks.TokenStore
-	EmojiStore                mocks.EmojiStore
-	StatusStore               mocks.StatusStore
-	FileInfoStore             mocks.FileInfoStore
-	ReactionStore             mocks.ReactionStore
-	JobStore                  mocks.JobStore
-	UserAccessTokenStore      mocks.UserAccessTokenStore
-	PluginStore               mocks.PluginStore
-	ChannelMemberHistoryStore mocks.ChannelMemberHistoryStore
-	RoleStore                 mocks.RoleStore
-	SchemeStore               mocks.SchemeStore
+	TeamStore                  mocks.TeamStore
+	ChannelStore               mocks.ChannelStore
+	PostStore                  mocks.PostStore
+	UserStore                  mocks.UserStore
+	AuditStore                 mocks.AuditStore
+	ClusterDiscoveryStore      mocks.ClusterDiscoveryStore
+	ComplianceStore            mocks.ComplianceStore
+	SessionStore               mocks.SessionStore
+	OAuthStore                 mocks.OAuthStore
+	SystemStore                mocks.SystemStore
+	WebhookStore               mocks.WebhookStore
+	CommandStore               mocks.CommandStore
+	CommandWebhookStore        mocks.CommandWebhookStore
+	PreferenceStore            mocks.PreferenceStore
+	LicenseStore               mocks.LicenseStore
+	TokenStore                 mocks.TokenStore
+	EmojiStore                 mocks.EmojiStore
+	StatusStore                mocks.StatusStore
+	FileInfoStore              mocks.FileInfoStore
+	ReactionStore              mocks.ReactionStore
+	JobStore                   mocks.JobStore
+	UserAccessTokenStore       mocks.UserAccessTokenStore
+	PluginStore                mocks.PluginStore
+	ChannelMemberHistoryStore  mocks.ChannelMemberHistoryStore
+	RoleStore                  mocks.RoleStore
+	SchemeStore                mocks.SchemeStore
+	EmailDigestStore           mocks.EmailDigestStore
+	WebPushSubscriptionStore   mocks.WebPushSubscriptionStore
+	ChannelBridgeStore         mocks.ChannelBridgeStore
+	LinkMetadataStore          mocks.LinkMetadataStore
+	PostAcknowledgementStore   mocks.PostAcknowledgementStore
+	PostEventStore             mocks.PostEventStore
+	ThreadMembershipStore      mocks.ThreadMembershipStore
+	UserGroupStore             mocks.UserGroupStore
+	AutoResponderScheduleStore mocks.AutoResponderScheduleStore
+	ChannelNotifyDefaultsStore mocks.ChannelNotifyDefaultsStore
+	ReminderStore              mocks.ReminderStore
+	BotStore                   mocks.BotStore
+	IntegrationDeliveryStore   mocks.IntegrationDeliveryStore
+	EventSubscriptionStore     mocks.EventSubscriptionStore
+	SidebarCategoryStore       mocks.SidebarCategoryStore
 }
 
-func (s *Store) Team() store.TeamStore                         { return &s.TeamStore }
-func (s *Store) Channel() store.ChannelStore                   { return &s.ChannelStore }
-func (s *Store) Post() store.PostStore                         { return &s.PostStore }
-func (s *Store) User() store.UserStore                         { return &s.UserStore }
-func (s *Store) Audit() store.AuditStore                       { return &s.AuditStore }
-func (s *Store) ClusterDiscovery() store.ClusterDiscoveryStore { return &s.ClusterDiscoveryStore }
-func (s *Store) Compliance() store.ComplianceStore             { return &s.ComplianceStore }
-func (s *Store) Session() store.SessionStore                   { return &s.SessionStore }
-func (s *Store) OAuth() store.OAuthStore                       { return &s.OAuthStore }
-func (s *Store) System() store.SystemStore                     { return &s.SystemStore }
-func (s *Store) Webhook() store.WebhookStore                   { return &s.WebhookStore }
-func (s *Store) Command() store.CommandStore                   { return &s.CommandStore }
-func (s *Store) CommandWebhook() store.CommandWebhookStore     { return &s.CommandWebhookStore }
-func (s *Store) Preference() store.PreferenceStore             { return &s.PreferenceStore }
-func (s *Store) License() store.LicenseStore                   { return &s.LicenseStore }
-func (s *Store) Token() store.TokenStore                       { return &s.TokenStore }
-func (s *Store) Emoji() store.EmojiStore                       { return &s.EmojiStore }
-func (s *Store) Status() store.StatusStore                     { return &s.StatusStore }
-func (s *Store) FileInfo() store.FileInfoStore                 { return &s.FileInfoStore }
-func (s *Store) Reaction() store.ReactionStore                 { return &s.ReactionStore }
-func (s *Store) Job() store.JobStore                           { return &s.JobStore }
-func (s *Store) UserAccessToken() store.UserAccessTokenStore   { return &s.UserAccessTokenStore }
-func (s *Store) Plugin() store.PluginStore                     { return &s.PluginStore }
-func (s *Store) Role() store.RoleStore                         { return &s.RoleStore }
-func (s *Store) Scheme() store.SchemeStore                     { return &s.SchemeStore }
+func (s *Store) Team() store.TeamStore                           { return &s.TeamStore }
+func (s *Store) Channel() store.ChannelStore                     { return &s.ChannelStore }
+func (s *Store) Post() store.PostStore                           { return &s.PostStore }
+func (s *Store) User() store.UserStore                           { return &s.UserStore }
+func (s *Store) Audit() store.AuditStore                         { return &s.AuditStore }
+func (s *Store) ClusterDiscovery() store.ClusterDiscoveryStore   { return &s.ClusterDiscoveryStore }
+func (s *Store) Compliance() store.ComplianceStore               { return &s.ComplianceStore }
+func (s *Store) Session() store.SessionStore                     { return &s.SessionStore }
+func (s *Store) OAuth() store.OAuthStore                         { return &s.OAuthStore }
+func (s *Store) System() store.SystemStore                       { return &s.SystemStore }
+func (s *Store) Webhook() store.WebhookStore                     { return &s.WebhookStore }
+func (s *Store) Command() store.CommandStore                     { return &s.CommandStore }
+func (s *Store) CommandWebhook() store.CommandWebhookStore       { return &s.CommandWebhookStore }
+func (s *Store) Preference() store.PreferenceStore               { return &s.PreferenceStore }
+func (s *Store) License() store.LicenseStore                     { return &s.LicenseStore }
+func (s *Store) Token() store.TokenStore                         { return &s.TokenStore }
+func (s *Store) Emoji() store.EmojiStore                         { return &s.EmojiStore }
+func (s *Store) Status() store.StatusStore                       { return &s.StatusStore }
+func (s *Store) FileInfo() store.FileInfoStore                   { return &s.FileInfoStore }
+func (s *Store) Reaction() store.ReactionStore                   { return &s.ReactionStore }
+func (s *Store) Job() store.JobStore                             { return &s.JobStore }
+func (s *Store) UserAccessToken() store.UserAccessTokenStore     { return &s.UserAccessTokenStore }
+func (s *Store) Plugin() store.PluginStore                       { return &s.PluginStore }
+func (s *Store) Role() store.RoleStore                           { return &s.RoleStore }
+func (s *Store) Scheme() store.SchemeStore                       { return &s.SchemeStore }
+func (s *Store) EmailDigest() store.EmailDigestStore             { return &s.EmailDigestStore }
+func (s *Store) ChannelBridge() store.ChannelBridgeStore         { return &s.ChannelBridgeStore }
+func (s *Store) LinkMetadata() store.LinkMetadataStore           { return &s.LinkMetadataStore }
+func (s *Store) PostEvent() store.PostEventStore                 { return &s.PostEventStore }
+func (s *Store) ThreadMembership() store.ThreadMembershipStore   { return &s.ThreadMembershipStore }
+func (s *Store) UserGroup() store.UserGroupStore                 { return &s.UserGroupStore }
+func (s *Store) Reminder() store.ReminderStore                   { return &s.ReminderStore }
+func (s *Store) Bot() store.BotStore                             { return &s.BotStore }
+func (s *Store) EventSubscription() store.EventSubscriptionStore { return &s.EventSubscriptionStore }
+func (s *Store) SidebarCategory() store.SidebarCategoryStore     { return &s.SidebarCategoryStore }
+func (s *Store) IntegrationDelivery() store.IntegrationDeliveryStore {
+	return &s.IntegrationDeliveryStore
+}
+func (s *Store) ChannelNotifyDefaults() store.ChannelNotifyDefaultsStore {
+	return &s.ChannelNotifyDefaultsStore
+}
+func (s *Store) AutoResponderSchedule() store.AutoResponderScheduleStore {
+	return &s.AutoResponderScheduleStore
+}
+func (s *Store) PostAcknowledgement() store.PostAcknowledgementStore {
+	return &s.PostAcknowledgementStore
+}
+func (s *Store) WebPushSubscription() store.WebPushSubscriptionStore {
+	return &s.WebPushSubscriptionStore
+}
 func (s *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	return &s.ChannelMemberHistoryStore
 }
@@ -112,5 +152,20 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.PluginStore,
 		&s.RoleStore,
 		&s.SchemeStore,
+		&s.EmailDigestStore,
+		&s.WebPushSubscriptionStore,
+		&s.ChannelBridgeStore,
+		&s.LinkMetadataStore,
+		&s.PostAcknowledgementStore,
+		&s.PostEventStore,
+		&s.ThreadMembershipStore,
+		&s.UserGroupStore,
+		&s.AutoResponderScheduleStore,
+		&s.ChannelNotifyDefaultsStore,
+		&s.ReminderStore,
+		&s.BotStore,
+		&s.IntegrationDeliveryStore,
+		&s.EventSubscriptionStore,
+		&s.SidebarCategoryStore,
 	)
 }
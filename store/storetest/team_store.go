@@ -45,6 +45,7 @@ func TestTeamStore(t *testing.T, ss store.Store) {
 	t.Run("ResetAllTeamSchemes", func(t *testing.T) { testResetAllTeamSchemes(t, ss) })
 	t.Run("ClearAllCustomRoleAssignments", func(t *testing.T) { testTeamStoreClearAllCustomRoleAssignments(t, ss) })
 	t.Run("AnalyticsGetTeamCountForScheme", func(t *testing.T) { testTeamStoreAnalyticsGetTeamCountForScheme(t, ss) })
+	t.Run("GetScheduledForDeletion", func(t *testing.T) { testTeamStoreGetScheduledForDeletion(t, ss) })
 }
 
 func testTeamStoreSave(t *testing.T, ss store.Store) {
@@ -1327,3 +1328,37 @@ func testTeamStoreAnalyticsGetTeamCountForScheme(t *testing.T, ss store.Store) {
 	count5 := (<-ss.Team().AnalyticsGetTeamCountForScheme(s1.Id)).Data.(int64)
 	assert.Equal(t, int64(2), count5)
 }
+
+func testTeamStoreGetScheduledForDeletion(t *testing.T, ss store.Store) {
+	now := model.GetMillis()
+
+	makeTeam := func(deleteAt, scheduledDeleteAt int64) *model.Team {
+		team := &model.Team{
+			Name:              "zz" + model.NewId(),
+			DisplayName:       model.NewId(),
+			Email:             MakeEmail(),
+			Type:              model.TEAM_OPEN,
+			DeleteAt:          deleteAt,
+			ScheduledDeleteAt: scheduledDeleteAt,
+		}
+		return store.Must(ss.Team().Save(team)).(*model.Team)
+	}
+
+	due := makeTeam(now-2000, now-1000)
+	notDue := makeTeam(now-2000, now+100000)
+	restored := makeTeam(0, now-1000)
+	archived := makeTeam(now-2000, 0)
+
+	result := <-ss.Team().GetScheduledForDeletion(now)
+	require.Nil(t, result.Err)
+
+	ids := []string{}
+	for _, team := range result.Data.([]*model.Team) {
+		ids = append(ids, team.Id)
+	}
+
+	assert.Contains(t, ids, due.Id)
+	assert.NotContains(t, ids, notDue.Id)
+	assert.NotContains(t, ids, restored.Id)
+	assert.NotContains(t, ids, archived.Id)
+}
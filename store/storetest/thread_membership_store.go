@@ -0,0 +1,111 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestThreadMembershipStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testThreadMembershipStoreSave(t, ss) })
+	t.Run("GetThreads", func(t *testing.T) { testThreadMembershipStoreGetThreads(t, ss) })
+	t.Run("UpdateLastUpdated", func(t *testing.T) { testThreadMembershipStoreUpdateLastUpdated(t, ss) })
+}
+
+func testThreadMembershipStoreSave(t *testing.T, ss store.Store) {
+	membership := &model.ThreadMembership{PostId: model.NewId(), UserId: model.NewId(), Following: true, LastViewed: 1000}
+
+	result := <-ss.ThreadMembership().Save(membership)
+	require.Nil(t, result.Err)
+
+	result = <-ss.ThreadMembership().Get(membership.UserId, membership.PostId)
+	require.Nil(t, result.Err)
+	assert.True(t, result.Data.(*model.ThreadMembership).Following)
+
+	membership.Following = false
+	result = <-ss.ThreadMembership().Save(membership)
+	require.Nil(t, result.Err)
+
+	// Saving it again without any changes shouldn't fail
+	result = <-ss.ThreadMembership().Save(membership)
+	require.Nil(t, result.Err)
+
+	result = <-ss.ThreadMembership().Get(membership.UserId, membership.PostId)
+	require.Nil(t, result.Err)
+	assert.False(t, result.Data.(*model.ThreadMembership).Following)
+
+	result = <-ss.ThreadMembership().Get(model.NewId(), membership.PostId)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+
+	result = <-ss.ThreadMembership().Save(&model.ThreadMembership{PostId: "junk", UserId: model.NewId()})
+	assert.NotNil(t, result.Err)
+}
+
+func testThreadMembershipStoreGetThreads(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+	userId := model.NewId()
+	otherUserId := model.NewId()
+
+	root := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, Message: "root"})).(*model.Post)
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, RootId: root.Id, Message: "own reply", CreateAt: root.CreateAt + 1}))
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: otherUserId, RootId: root.Id, Message: "reply", CreateAt: root.CreateAt + 2}))
+	store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: otherUserId, RootId: root.Id, Message: "reply", CreateAt: root.CreateAt + 3}))
+
+	otherRoot := store.Must(ss.Post().Save(&model.Post{ChannelId: channelId, UserId: otherUserId, Message: "other root"})).(*model.Post)
+
+	store.Must(ss.ThreadMembership().Save(&model.ThreadMembership{PostId: root.Id, UserId: userId, Following: true, LastViewed: root.CreateAt + 2, LastUpdated: 1000}))
+	store.Must(ss.ThreadMembership().Save(&model.ThreadMembership{PostId: root.Id, UserId: otherUserId, Following: false, LastViewed: root.CreateAt, LastUpdated: 1000}))
+	store.Must(ss.ThreadMembership().Save(&model.ThreadMembership{PostId: otherRoot.Id, UserId: userId, Following: true, LastViewed: 0, LastUpdated: 2000}))
+
+	result := <-ss.ThreadMembership().GetThreadsForUser(userId, 0, 10)
+	require.Nil(t, result.Err)
+	threads := result.Data.([]*model.Thread)
+	require.Len(t, threads, 2)
+	assert.Equal(t, otherRoot.Id, threads[0].PostId, "should return the most recently updated thread first")
+	assert.Equal(t, root.Id, threads[1].PostId)
+	assert.Equal(t, int64(1), threads[1].UnreadReplies, "should only count replies by others since the thread was viewed")
+
+	result = <-ss.ThreadMembership().GetThreadsForUser(userId, 1, 10)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.Thread), 1)
+
+	result = <-ss.ThreadMembership().GetThreadsForUser(otherUserId, 0, 10)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.Thread), 0, "should leave out threads that aren't being followed")
+
+	result = <-ss.ThreadMembership().GetThreadsForPost(root.Id)
+	require.Nil(t, result.Err)
+	threads = result.Data.([]*model.Thread)
+	require.Len(t, threads, 2)
+	for _, thread := range threads {
+		if thread.UserId == otherUserId {
+			assert.False(t, thread.Following)
+			assert.Equal(t, int64(1), thread.UnreadReplies)
+		} else {
+			assert.True(t, thread.Following)
+		}
+	}
+}
+
+func testThreadMembershipStoreUpdateLastUpdated(t *testing.T, ss store.Store) {
+	postId := model.NewId()
+	userId := model.NewId()
+
+	store.Must(ss.ThreadMembership().Save(&model.ThreadMembership{PostId: postId, UserId: userId, Following: true, LastUpdated: 1000}))
+
+	result := <-ss.ThreadMembership().UpdateLastUpdated(postId, 5000)
+	require.Nil(t, result.Err)
+
+	result = <-ss.ThreadMembership().Get(userId, postId)
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(5000), result.Data.(*model.ThreadMembership).LastUpdated)
+}
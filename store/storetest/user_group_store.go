@@ -0,0 +1,168 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestUserGroupStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testUserGroupStoreSave(t, ss) })
+	t.Run("Update", func(t *testing.T) { testUserGroupStoreUpdate(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testUserGroupStoreDelete(t, ss) })
+	t.Run("Members", func(t *testing.T) { testUserGroupStoreMembers(t, ss) })
+	t.Run("GetMemberIdsInChannel", func(t *testing.T) { testUserGroupStoreGetMemberIdsInChannel(t, ss) })
+}
+
+func newTestUserGroup() *model.UserGroup {
+	return &model.UserGroup{
+		Name:        "group" + model.NewId(),
+		DisplayName: "Group",
+		CreatorId:   model.NewId(),
+	}
+}
+
+func testUserGroupStoreSave(t *testing.T, ss store.Store) {
+	group := newTestUserGroup()
+	group.Name = "Oncall" + model.NewId()
+
+	result := <-ss.UserGroup().Save(group)
+	require.Nil(t, result.Err)
+	saved := result.Data.(*model.UserGroup)
+	assert.Len(t, saved.Id, 26)
+	assert.Equal(t, "oncall", saved.Name[:6], "should store names in lower case")
+
+	result = <-ss.UserGroup().Get(saved.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, saved.Name, result.Data.(*model.UserGroup).Name)
+
+	result = <-ss.UserGroup().GetByName(saved.Name)
+	require.Nil(t, result.Err)
+	assert.Equal(t, saved.Id, result.Data.(*model.UserGroup).Id)
+
+	duplicate := newTestUserGroup()
+	duplicate.Name = saved.Name
+	result = <-ss.UserGroup().Save(duplicate)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, "store.sql_user_group.save.name_exists.app_error", result.Err.Id)
+
+	result = <-ss.UserGroup().Save(saved)
+	assert.NotNil(t, result.Err, "shouldn't save a group that already exists")
+
+	result = <-ss.UserGroup().Get(model.NewId())
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testUserGroupStoreUpdate(t *testing.T, ss store.Store) {
+	group := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+	other := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+
+	group.DisplayName = "Renamed"
+	result := <-ss.UserGroup().Update(group)
+	require.Nil(t, result.Err)
+
+	result = <-ss.UserGroup().Get(group.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, "Renamed", result.Data.(*model.UserGroup).DisplayName)
+
+	group.Name = other.Name
+	result = <-ss.UserGroup().Update(group)
+	require.NotNil(t, result.Err)
+	assert.Equal(t, "store.sql_user_group.save.name_exists.app_error", result.Err.Id)
+}
+
+func testUserGroupStoreDelete(t *testing.T, ss store.Store) {
+	group := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+	store.Must(ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: group.Id, UserId: model.NewId()}))
+
+	result := <-ss.UserGroup().Delete(group.Id)
+	require.Nil(t, result.Err)
+
+	result = <-ss.UserGroup().Get(group.Id)
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.UserGroup().GetMembers(group.Id, 0, 100)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.UserGroupMember), 0)
+
+	reused := newTestUserGroup()
+	reused.Name = group.Name
+	result = <-ss.UserGroup().Save(reused)
+	assert.Nil(t, result.Err, "should be able to reuse the name of a deleted group")
+}
+
+func testUserGroupStoreMembers(t *testing.T, ss store.Store) {
+	group := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+
+	result := <-ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: group.Id, UserId: userId1, CreateAt: 1000})
+	require.Nil(t, result.Err)
+
+	result = <-ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: group.Id, UserId: userId2, CreateAt: 2000})
+	require.Nil(t, result.Err)
+
+	result = <-ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: group.Id, UserId: userId1, CreateAt: 3000})
+	require.Nil(t, result.Err)
+	assert.Equal(t, int64(1000), result.Data.(*model.UserGroupMember).CreateAt, "should keep the existing membership")
+
+	result = <-ss.UserGroup().GetMembers(group.Id, 0, 100)
+	require.Nil(t, result.Err)
+	members := result.Data.([]*model.UserGroupMember)
+	require.Len(t, members, 2)
+	assert.Equal(t, userId1, members[0].UserId)
+	assert.Equal(t, userId2, members[1].UserId)
+
+	result = <-ss.UserGroup().GetMembers(group.Id, 1, 100)
+	require.Nil(t, result.Err)
+	assert.Len(t, result.Data.([]*model.UserGroupMember), 1)
+
+	result = <-ss.UserGroup().DeleteMember(group.Id, userId1)
+	require.Nil(t, result.Err)
+
+	result = <-ss.UserGroup().GetMembers(group.Id, 0, 100)
+	require.Nil(t, result.Err)
+	members = result.Data.([]*model.UserGroupMember)
+	require.Len(t, members, 1)
+	assert.Equal(t, userId2, members[0].UserId)
+}
+
+func testUserGroupStoreGetMemberIdsInChannel(t *testing.T, ss store.Store) {
+	channelId := model.NewId()
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+	outsiderId := model.NewId()
+
+	for _, userId := range []string{userId1, userId2} {
+		store.Must(ss.Channel().SaveMember(&model.ChannelMember{ChannelId: channelId, UserId: userId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+	}
+
+	group := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+	otherGroup := store.Must(ss.UserGroup().Save(newTestUserGroup())).(*model.UserGroup)
+
+	for _, userId := range []string{userId1, userId2, outsiderId} {
+		store.Must(ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: group.Id, UserId: userId}))
+	}
+	store.Must(ss.UserGroup().SaveMember(&model.UserGroupMember{GroupId: otherGroup.Id, UserId: outsiderId}))
+
+	result := <-ss.UserGroup().GetMemberIdsInChannel(channelId)
+	require.Nil(t, result.Err)
+	memberIds := result.Data.(map[string][]string)
+
+	require.Len(t, memberIds, 1, "should leave out groups without members in the channel")
+	ids := memberIds[group.Name]
+	sort.Strings(ids)
+	expected := []string{userId1, userId2}
+	sort.Strings(expected)
+	assert.Equal(t, expected, ids)
+}
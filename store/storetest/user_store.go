@@ -4,6 +4,7 @@
 package storetest
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -34,6 +35,7 @@ func TestUserStore(t *testing.T, ss store.Store) {
 	t.Run("GetProfilesByUsernames", func(t *testing.T) { testUserStoreGetProfilesByUsernames(t, ss) })
 	t.Run("GetSystemAdminProfiles", func(t *testing.T) { testUserStoreGetSystemAdminProfiles(t, ss) })
 	t.Run("GetByEmail", func(t *testing.T) { testUserStoreGetByEmail(t, ss) })
+	t.Run("GetByRemoteId", func(t *testing.T) { testUserStoreGetByRemoteId(t, ss) })
 	t.Run("GetByAuthData", func(t *testing.T) { testUserStoreGetByAuthData(t, ss) })
 	t.Run("GetByUsername", func(t *testing.T) { testUserStoreGetByUsername(t, ss) })
 	t.Run("GetForLogin", func(t *testing.T) { testUserStoreGetForLogin(t, ss) })
@@ -45,6 +47,8 @@ func TestUserStore(t *testing.T, ss store.Store) {
 	t.Run("UpdateMfaActive", func(t *testing.T) { testUserStoreUpdateMfaActive(t, ss) })
 	t.Run("GetRecentlyActiveUsersForTeam", func(t *testing.T) { testUserStoreGetRecentlyActiveUsersForTeam(t, ss) })
 	t.Run("GetNewUsersForTeam", func(t *testing.T) { testUserStoreGetNewUsersForTeam(t, ss) })
+	t.Run("GetDirectory", func(t *testing.T) { testUserStoreGetDirectory(t, ss) })
+	t.Run("GetInactive", func(t *testing.T) { testUserStoreGetInactive(t, ss) })
 	t.Run("Search", func(t *testing.T) { testUserStoreSearch(t, ss) })
 	t.Run("SearchWithoutTeam", func(t *testing.T) { testUserStoreSearchWithoutTeam(t, ss) })
 	t.Run("AnalyticsGetInactiveUsersCount", func(t *testing.T) { testUserStoreAnalyticsGetInactiveUsersCount(t, ss) })
@@ -170,6 +174,20 @@ func testUserStoreUpdate(t *testing.T, ss store.Store) {
 		}
 	}
 
+	u3.Props = model.StringMap{model.USER_PROP_PHONE_NUMBER: "+15555550123"}
+	if result := <-ss.User().Update(u3, false); result.Err != nil {
+		t.Fatal("Update should not have failed")
+	} else if newUser := result.Data.([2]*model.User)[0]; newUser.GetPhoneNumber() != "" {
+		t.Fatal("Phone number should not have been updated as the update is not trusted")
+	}
+
+	u3.Props = model.StringMap{model.USER_PROP_PHONE_NUMBER: "+15555550123"}
+	if result := <-ss.User().Update(u3, true); result.Err != nil {
+		t.Fatal("Update should not have failed")
+	} else if newUser := result.Data.([2]*model.User)[0]; newUser.GetPhoneNumber() != "+15555550123" {
+		t.Fatal("Phone number should have been updated as the update is trusted")
+	}
+
 	if result := <-ss.User().UpdateLastPictureUpdate(u1.Id); result.Err != nil {
 		t.Fatal("Update should not have failed")
 	}
@@ -1259,6 +1277,11 @@ func testUserUnreadCount(t *testing.T, ss store.Store) {
 		t.Fatal("should have 3 unread messages")
 	}
 
+	badge = (<-ss.User().GetUnreadCountFromMaster(u2.Id)).Data.(int64)
+	if badge != 3 {
+		t.Fatal("should have 3 unread messages from master")
+	}
+
 	badge = (<-ss.User().GetUnreadCountForChannel(u2.Id, c1.Id)).Data.(int64)
 	if badge != 1 {
 		t.Fatal("should have 1 unread messages for that channel")
@@ -1334,6 +1357,116 @@ func testUserStoreGetNewUsersForTeam(t *testing.T, ss store.Store) {
 	}
 }
 
+func testUserStoreGetDirectory(t *testing.T, ss store.Store) {
+	teamId := model.NewId()
+
+	u1 := &model.User{Email: MakeEmail(), Username: "a" + model.NewId(), Roles: model.SYSTEM_USER_ROLE_ID}
+	store.Must(ss.User().Save(u1))
+	store.Must(ss.Status().SaveOrUpdate(&model.Status{UserId: u1.Id, Status: model.STATUS_OFFLINE, LastActivityAt: 1000}))
+	store.Must(ss.Team().SaveMember(&model.TeamMember{TeamId: teamId, UserId: u1.Id}, -1))
+
+	u2 := &model.User{Email: MakeEmail(), Username: "b" + model.NewId(), Roles: model.SYSTEM_USER_ROLE_ID + " " + model.SYSTEM_ADMIN_ROLE_ID}
+	store.Must(ss.User().Save(u2))
+	store.Must(ss.Status().SaveOrUpdate(&model.Status{UserId: u2.Id, Status: model.STATUS_ONLINE, LastActivityAt: model.GetMillis()}))
+	store.Must(ss.Team().SaveMember(&model.TeamMember{TeamId: teamId, UserId: u2.Id}, -1))
+
+	u3 := &model.User{Email: MakeEmail(), Username: "c" + model.NewId(), AuthService: model.USER_AUTH_SERVICE_GITLAB, AuthData: model.NewString(model.NewId())}
+	store.Must(ss.User().Save(u3))
+	store.Must(ss.Team().SaveMember(&model.TeamMember{TeamId: teamId, UserId: u3.Id}, -1))
+
+	t.Run("team", func(t *testing.T) {
+		options := &model.UserDirectoryOptions{TeamId: teamId, Sort: []model.UserDirectorySort{{Column: model.USER_DIRECTORY_SORT_USERNAME}}}
+		users := store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		require.Len(t, users, 3)
+		assert.Equal(t, u1.Id, users[0].Id)
+		assert.Equal(t, int64(1000), users[0].LastActivityAt)
+		assert.Equal(t, u3.Id, users[2].Id)
+		assert.Empty(t, users[0].Password)
+	})
+
+	t.Run("descending sort", func(t *testing.T) {
+		options := &model.UserDirectoryOptions{TeamId: teamId, Sort: []model.UserDirectorySort{{Column: model.USER_DIRECTORY_SORT_USERNAME, Descending: true}}}
+		users := store.Must(ss.User().GetDirectory(options, 0, 2)).([]*model.User)
+		require.Len(t, users, 2)
+		assert.Equal(t, u3.Id, users[0].Id)
+		assert.Equal(t, u2.Id, users[1].Id)
+	})
+
+	t.Run("role", func(t *testing.T) {
+		options := &model.UserDirectoryOptions{TeamId: teamId, Role: model.SYSTEM_ADMIN_ROLE_ID}
+		users := store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		require.Len(t, users, 1)
+		assert.Equal(t, u2.Id, users[0].Id)
+
+		// Only whole roles match
+		options.Role = "admin"
+		users = store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		assert.Empty(t, users)
+	})
+
+	t.Run("auth service", func(t *testing.T) {
+		options := &model.UserDirectoryOptions{TeamId: teamId, AuthService: model.USER_AUTH_SERVICE_GITLAB}
+		users := store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		require.Len(t, users, 1)
+		assert.Equal(t, u3.Id, users[0].Id)
+
+		options.AuthService = model.USER_AUTH_SERVICE_EMAIL
+		users = store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		require.Len(t, users, 2)
+	})
+
+	t.Run("last activity before", func(t *testing.T) {
+		options := &model.UserDirectoryOptions{TeamId: teamId, LastActivityBefore: 2000, Sort: []model.UserDirectorySort{{Column: model.USER_DIRECTORY_SORT_LAST_ACTIVITY_AT}}}
+		users := store.Must(ss.User().GetDirectory(options, 0, 100)).([]*model.User)
+		require.Len(t, users, 2)
+		assert.Equal(t, u3.Id, users[0].Id)
+		assert.Equal(t, u1.Id, users[1].Id)
+	})
+}
+
+func testUserStoreGetInactive(t *testing.T, ss store.Store) {
+	cutoff := model.GetMillis() + 60000
+
+	u1 := &model.User{Email: MakeEmail(), Username: "a" + model.NewId()}
+	store.Must(ss.User().Save(u1))
+	store.Must(ss.Status().SaveOrUpdate(&model.Status{UserId: u1.Id, Status: model.STATUS_OFFLINE, LastActivityAt: 1000}))
+
+	u2 := &model.User{Email: MakeEmail(), Username: "b" + model.NewId()}
+	store.Must(ss.User().Save(u2))
+	store.Must(ss.Status().SaveOrUpdate(&model.Status{UserId: u2.Id, Status: model.STATUS_ONLINE, LastActivityAt: cutoff + 1000}))
+
+	u3 := &model.User{Email: MakeEmail(), Username: "c" + model.NewId()}
+	store.Must(ss.User().Save(u3))
+
+	u4 := &model.User{Email: MakeEmail(), Username: "d" + model.NewId(), DeleteAt: 1000}
+	store.Must(ss.User().Save(u4))
+
+	found := make(map[string]*model.User)
+	for page := 0; ; page++ {
+		users := store.Must(ss.User().GetInactive(cutoff, page*100, 100)).([]*model.User)
+		for _, user := range users {
+			found[user.Id] = user
+		}
+
+		if len(users) < 100 {
+			break
+		}
+	}
+
+	require.Contains(t, found, u1.Id)
+	assert.Equal(t, int64(1000), found[u1.Id].LastActivityAt)
+	assert.Equal(t, u1.Email, found[u1.Id].Email)
+	assert.NotContains(t, found, u2.Id, "recently active user")
+	require.Contains(t, found, u3.Id, "never active user")
+	assert.Equal(t, int64(0), found[u3.Id].LastActivityAt)
+	assert.NotContains(t, found, u4.Id, "deactivated user")
+
+	users := store.Must(ss.User().GetInactive(u1.CreateAt, 0, 10000)).([]*model.User)
+	for _, user := range users {
+		assert.NotEqual(t, u1.Id, user.Id, "users created after the cutoff should not be returned")
+	}
+}
+
 func testUserStoreSearch(t *testing.T, ss store.Store) {
 	u1 := &model.User{}
 	u1.Username = "jimbo" + model.NewId()
@@ -2164,3 +2297,27 @@ func testUserStoreClearAllCustomRoleAssignments(t *testing.T, ss store.Store) {
 	require.Nil(t, r4.Err)
 	assert.Equal(t, "", r4.Data.(*model.User).Roles)
 }
+
+func testUserStoreGetByRemoteId(t *testing.T, ss store.Store) {
+	remoteId := model.NewRemoteId("jira", model.NewId())
+
+	u1 := &model.User{}
+	u1.Email = MakeEmail()
+	u1.RemoteId = model.NewString(remoteId)
+	store.Must(ss.User().Save(u1))
+
+	r1 := <-ss.User().GetByRemoteId(remoteId)
+	require.Nil(t, r1.Err)
+	assert.Equal(t, u1.Id, r1.Data.(*model.User).Id)
+
+	r2 := <-ss.User().GetByRemoteId(model.NewRemoteId("jira", model.NewId()))
+	require.NotNil(t, r2.Err)
+	assert.Equal(t, http.StatusNotFound, r2.Err.StatusCode)
+
+	u2 := &model.User{}
+	u2.Email = MakeEmail()
+	u2.RemoteId = model.NewString(remoteId)
+	r3 := <-ss.User().Save(u2)
+	require.NotNil(t, r3.Err, "remote ids should be unique")
+	assert.Equal(t, "store.sql_user.save.remote_id_exists.app_error", r3.Err.Id)
+}
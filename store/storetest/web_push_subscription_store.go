@@ -0,0 +1,126 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+func TestWebPushSubscriptionStore(t *testing.T, ss store.Store) {
+	t.Run("SaveAndGet", func(t *testing.T) { testWebPushSubscriptionStoreSaveAndGet(t, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testWebPushSubscriptionStoreGetForUser(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testWebPushSubscriptionStoreDelete(t, ss) })
+	t.Run("DeleteByEndpoint", func(t *testing.T) { testWebPushSubscriptionStoreDeleteByEndpoint(t, ss) })
+	t.Run("DeleteBySessionId", func(t *testing.T) { testWebPushSubscriptionStoreDeleteBySessionId(t, ss) })
+}
+
+func makeWebPushSubscription(userId, sessionId string) *model.WebPushSubscription {
+	p256dh := make([]byte, model.WEB_PUSH_SUBSCRIPTION_P256DH_LENGTH)
+	p256dh[0] = 0x04
+
+	return &model.WebPushSubscription{
+		UserId:    userId,
+		SessionId: sessionId,
+		Endpoint:  "https://push.example.com/" + model.NewId(),
+		P256dh:    base64.RawURLEncoding.EncodeToString(p256dh),
+		Auth:      base64.RawURLEncoding.EncodeToString(make([]byte, model.WEB_PUSH_SUBSCRIPTION_AUTH_LENGTH)),
+	}
+}
+
+func makeWebPushSession(t *testing.T, ss store.Store, userId string, expiresAt int64) *model.Session {
+	result := <-ss.Session().Save(&model.Session{UserId: userId, ExpiresAt: expiresAt})
+	require.Nil(t, result.Err)
+	return result.Data.(*model.Session)
+}
+
+func testWebPushSubscriptionStoreSaveAndGet(t *testing.T, ss store.Store) {
+	s1 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(model.NewId(), model.NewId()))).(*model.WebPushSubscription)
+
+	result := <-ss.WebPushSubscription().Save(s1)
+	assert.NotNil(t, result.Err, "should not be able to save an existing subscription")
+
+	invalid := makeWebPushSubscription(model.NewId(), model.NewId())
+	invalid.Endpoint = "http://push.example.com/insecure"
+	result = <-ss.WebPushSubscription().Save(invalid)
+	assert.NotNil(t, result.Err, "should not be able to save an invalid subscription")
+
+	result = <-ss.WebPushSubscription().Get(s1.Id)
+	require.Nil(t, result.Err)
+	assert.Equal(t, s1, result.Data.(*model.WebPushSubscription))
+
+	result = <-ss.WebPushSubscription().Get(model.NewId())
+	require.NotNil(t, result.Err)
+	assert.Equal(t, http.StatusNotFound, result.Err.StatusCode)
+}
+
+func testWebPushSubscriptionStoreGetForUser(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+
+	session := makeWebPushSession(t, ss, userId, 0)
+	expiredSession := makeWebPushSession(t, ss, userId, model.GetMillis()-1000)
+	otherSession := makeWebPushSession(t, ss, model.NewId(), 0)
+
+	s1 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, session.Id))).(*model.WebPushSubscription)
+	store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, expiredSession.Id)))
+	store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(otherSession.UserId, otherSession.Id)))
+
+	// the session for this subscription doesn't exist
+	store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, model.NewId())))
+
+	result := <-ss.WebPushSubscription().GetForUser(userId)
+	require.Nil(t, result.Err)
+	subscriptions := result.Data.([]*model.WebPushSubscription)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, s1.Id, subscriptions[0].Id)
+}
+
+func testWebPushSubscriptionStoreDelete(t *testing.T, ss store.Store) {
+	s1 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(model.NewId(), model.NewId()))).(*model.WebPushSubscription)
+
+	store.Must(ss.WebPushSubscription().Delete(s1.Id))
+
+	result := <-ss.WebPushSubscription().Get(s1.Id)
+	assert.NotNil(t, result.Err)
+}
+
+func testWebPushSubscriptionStoreDeleteByEndpoint(t *testing.T, ss store.Store) {
+	s1 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(model.NewId(), model.NewId()))).(*model.WebPushSubscription)
+	s2 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(model.NewId(), model.NewId()))).(*model.WebPushSubscription)
+
+	store.Must(ss.WebPushSubscription().DeleteByEndpoint(s1.Endpoint))
+
+	result := <-ss.WebPushSubscription().Get(s1.Id)
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.WebPushSubscription().Get(s2.Id)
+	assert.Nil(t, result.Err)
+}
+
+func testWebPushSubscriptionStoreDeleteBySessionId(t *testing.T, ss store.Store) {
+	userId := model.NewId()
+	sessionId := model.NewId()
+
+	s1 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, sessionId))).(*model.WebPushSubscription)
+	s2 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, sessionId))).(*model.WebPushSubscription)
+	s3 := store.Must(ss.WebPushSubscription().Save(makeWebPushSubscription(userId, model.NewId()))).(*model.WebPushSubscription)
+
+	store.Must(ss.WebPushSubscription().DeleteBySessionId(sessionId))
+
+	result := <-ss.WebPushSubscription().Get(s1.Id)
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.WebPushSubscription().Get(s2.Id)
+	assert.NotNil(t, result.Err)
+
+	result = <-ss.WebPushSubscription().Get(s3.Id)
+	assert.Nil(t, result.Err)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// QueryTracer is told about every call that a store method makes through Do, so that the store methods can be counted
+// and timed without wrapping each of the stores.
+type QueryTracer interface {
+	// TraceQuery is called once the work of a store method has finished. Method names the store method, such as
+	// "sqlstore.SqlPostStore.Get", and rows is the number of results that it returned.
+	TraceQuery(method string, elapsed time.Duration, rows int, failed bool)
+}
+
+// queryTracerHolder lets a nil tracer be stored, since an atomic.Value can't hold nil.
+type queryTracerHolder struct {
+	tracer QueryTracer
+}
+
+var queryTracer atomic.Value
+
+// The names of the store methods, keyed by the program counter of their call to Do
+var tracedMethodNames sync.Map
+
+// SetQueryTracer starts sending the calls made through Do to the given tracer, or stops tracing them if it's nil.
+func SetQueryTracer(tracer QueryTracer) {
+	queryTracer.Store(queryTracerHolder{tracer: tracer})
+}
+
+func getQueryTracer() QueryTracer {
+	holder, _ := queryTracer.Load().(queryTracerHolder)
+	return holder.tracer
+}
+
+// tracedMethodName returns the name of the function that called Do, without its package path.
+func tracedMethodName(pc uintptr) string {
+	if name, ok := tracedMethodNames.Load(pc); ok {
+		return name.(string)
+	}
+
+	name := "unknown"
+	if f := runtime.FuncForPC(pc); f != nil {
+		name = f.Name()
+		if slash := strings.LastIndex(name, "/"); slash != -1 {
+			name = name[slash+1:]
+		}
+		name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	}
+
+	tracedMethodNames.Store(pc, name)
+	return name
+}
+
+// resultRows counts the results that a store method returned. Lists and maps count each of their entries, and anything
+// else counts as a single row.
+func resultRows(data interface{}) int {
+	if data == nil {
+		return 0
+	}
+
+	if postList, ok := data.(*model.PostList); ok {
+		if postList == nil {
+			return 0
+		}
+		return len(postList.Posts)
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return 0
+		}
+		if elem := value.Elem(); elem.Kind() == reflect.Slice || elem.Kind() == reflect.Map {
+			value = elem
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len()
+	}
+
+	return 1
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package teamdeletion
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	tjobs "github.com/mattermost/mattermost-server/jobs/interfaces"
+)
+
+type TeamDeletionJobInterfaceImpl struct {
+	App *app.App
+}
+
+func init() {
+	app.RegisterJobsTeamDeletionJobInterface(func(a *app.App) tjobs.TeamDeletionJobInterface {
+		return &TeamDeletionJobInterfaceImpl{a}
+	})
+}
@@ -159,8 +159,7 @@ func SaveConfig(fileName string, config *model.Config) *model.AppError {
 			map[string]interface{}{"Filename": fileName}, err.Error(), http.StatusBadRequest)
 	}
 
-	err = ioutil.WriteFile(fileName, b, 0644)
-	if err != nil {
+	if err = writeFileAtomically(fileName, b, 0644); err != nil {
 		return model.NewAppError("SaveConfig", "utils.config.save_config.saving.app_error",
 			map[string]interface{}{"Filename": fileName}, err.Error(), http.StatusInternalServerError)
 	}
@@ -168,6 +167,41 @@ func SaveConfig(fileName string, config *model.Config) *model.AppError {
 	return nil
 }
 
+// writeFileAtomically writes a file by replacing it with a temporary file in the same directory so that it's never
+// left partially written if the server stops while saving it.
+func writeFileAtomically(fileName string, data []byte, perm os.FileMode) error {
+	dir, base := filepath.Split(fileName)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tempFileName := f.Name()
+
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFileName, perm)
+	}
+	if err == nil {
+		err = os.Rename(tempFileName, fileName)
+	}
+
+	if err != nil {
+		os.Remove(tempFileName)
+		return err
+	}
+
+	return nil
+}
+
 type ConfigWatcher struct {
 	watcher *fsnotify.Watcher
 	close   chan struct{}
@@ -527,17 +561,22 @@ func GenerateClientConfig(c *model.Config, diagnosticId string, license *model.L
 	props["ExperimentalEnableDefaultChannelLeaveJoinMessages"] = strconv.FormatBool(*c.ServiceSettings.ExperimentalEnableDefaultChannelLeaveJoinMessages)
 	props["ExperimentalGroupUnreadChannels"] = *c.ServiceSettings.ExperimentalGroupUnreadChannels
 
-	if *c.ServiceSettings.ExperimentalChannelOrganization || *c.ServiceSettings.ExperimentalGroupUnreadChannels != model.GROUP_UNREAD_CHANNELS_DISABLED {
+	// Older clients still look for the experimental settings, so they're reported using the stable ones
+	props["EnableChannelOrganization"] = strconv.FormatBool(*c.ServiceSettings.EnableChannelOrganization)
+	if *c.ServiceSettings.EnableChannelOrganization || *c.ServiceSettings.ExperimentalGroupUnreadChannels != model.GROUP_UNREAD_CHANNELS_DISABLED {
 		props["ExperimentalChannelOrganization"] = strconv.FormatBool(true)
 	} else {
 		props["ExperimentalChannelOrganization"] = strconv.FormatBool(false)
 	}
 
 	props["ExperimentalEnableAutomaticReplies"] = strconv.FormatBool(*c.TeamSettings.ExperimentalEnableAutomaticReplies)
-	props["ExperimentalTimezone"] = strconv.FormatBool(*c.DisplaySettings.ExperimentalTimezone)
+	props["EnableTimezone"] = strconv.FormatBool(*c.DisplaySettings.EnableTimezone)
+	props["ExperimentalTimezone"] = strconv.FormatBool(*c.DisplaySettings.EnableTimezone)
 
 	props["SendEmailNotifications"] = strconv.FormatBool(c.EmailSettings.SendEmailNotifications)
 	props["SendPushNotifications"] = strconv.FormatBool(*c.EmailSettings.SendPushNotifications)
+	props["EnableWebPushNotifications"] = strconv.FormatBool(*c.EmailSettings.EnableWebPushNotifications)
+	props["EnableSmsNotifications"] = strconv.FormatBool(*c.SmsSettings.Enable)
 	props["RequireEmailVerification"] = strconv.FormatBool(c.EmailSettings.RequireEmailVerification)
 	props["EnableEmailBatching"] = strconv.FormatBool(*c.EmailSettings.EnableEmailBatching)
 	props["EnablePreviewModeBanner"] = strconv.FormatBool(*c.EmailSettings.EnablePreviewModeBanner)
@@ -568,6 +607,7 @@ func GenerateClientConfig(c *model.Config, diagnosticId string, license *model.L
 	props["TimeBetweenUserTypingUpdatesMilliseconds"] = strconv.FormatInt(*c.ServiceSettings.TimeBetweenUserTypingUpdatesMilliseconds, 10)
 	props["EnableUserTypingMessages"] = strconv.FormatBool(*c.ServiceSettings.EnableUserTypingMessages)
 	props["EnableChannelViewedMessages"] = strconv.FormatBool(*c.ServiceSettings.EnableChannelViewedMessages)
+	props["ReadReceipts"] = *c.ServiceSettings.ReadReceipts
 
 	props["PluginsEnabled"] = strconv.FormatBool(*c.PluginSettings.Enable)
 
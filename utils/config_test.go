@@ -80,6 +80,33 @@ func TestReadConfig_PluginSettings(t *testing.T) {
 	}, *config.PluginSettings.PluginStates["com.example.plugin"])
 }
 
+func TestSaveConfig(t *testing.T) {
+	TranslationsPreInit()
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(fileName, []byte("{}"), 0644))
+
+	config := &model.Config{}
+	config.SetDefaults()
+	*config.ServiceSettings.SiteURL = "http://saved.example.com"
+
+	require.Nil(t, SaveConfig(fileName, config))
+
+	saved, _, err := ReadConfigFile(fileName, false)
+	require.NoError(t, err)
+	assert.Equal(t, "http://saved.example.com", *saved.ServiceSettings.SiteURL)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "shouldn't leave temporary files behind")
+
+	assert.NotNil(t, SaveConfig(filepath.Join(dir, "missing", "config.json"), config))
+}
+
 func TestTimezoneConfig(t *testing.T) {
 	TranslationsPreInit()
 	supportedTimezones := LoadTimezones("timezones.json")
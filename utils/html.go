@@ -9,10 +9,12 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"sync/atomic"
+	texttemplate "text/template"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/mattermost/mattermost-server/mlog"
@@ -20,9 +22,13 @@ import (
 )
 
 type HTMLTemplateWatcher struct {
-	templates atomic.Value
-	stop      chan struct{}
-	stopped   chan struct{}
+	templates     atomic.Value
+	textTemplates atomic.Value
+	templatesDir  string
+	overridesDir  atomic.Value
+	watcher       *fsnotify.Watcher
+	stop          chan struct{}
+	stopped       chan struct{}
 }
 
 func NewHTMLTemplateWatcher(directory string) (*HTMLTemplateWatcher, error) {
@@ -30,9 +36,11 @@ func NewHTMLTemplateWatcher(directory string) (*HTMLTemplateWatcher, error) {
 	mlog.Debug(fmt.Sprintf("Parsing server templates at %v", templatesDir))
 
 	ret := &HTMLTemplateWatcher{
-		stop:    make(chan struct{}),
-		stopped: make(chan struct{}),
+		templatesDir: templatesDir,
+		stop:         make(chan struct{}),
+		stopped:      make(chan struct{}),
 	}
+	ret.overridesDir.Store("")
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -40,13 +48,17 @@ func NewHTMLTemplateWatcher(directory string) (*HTMLTemplateWatcher, error) {
 	}
 
 	if err = watcher.Add(templatesDir); err != nil {
+		watcher.Close()
 		return nil, err
 	}
+	ret.watcher = watcher
 
-	if htmlTemplates, err := template.ParseGlob(filepath.Join(templatesDir, "*.html")); err != nil {
+	if htmlTemplates, textTemplates, err := ParseTemplatesWithOverrides(templatesDir, ""); err != nil {
+		watcher.Close()
 		return nil, err
 	} else {
 		ret.templates.Store(htmlTemplates)
+		ret.textTemplates.Store(textTemplates)
 	}
 
 	go func() {
@@ -58,12 +70,10 @@ func NewHTMLTemplateWatcher(directory string) (*HTMLTemplateWatcher, error) {
 			case <-ret.stop:
 				return
 			case event := <-watcher.Events:
-				if event.Op&fsnotify.Write == fsnotify.Write {
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
 					mlog.Info(fmt.Sprintf("Re-parsing templates because of modified file %v", event.Name))
-					if htmlTemplates, err := template.ParseGlob(filepath.Join(templatesDir, "*.html")); err != nil {
+					if err := ret.reload(ret.overridesDir.Load().(string)); err != nil {
 						mlog.Error(fmt.Sprintf("Failed to parse templates %v", err))
-					} else {
-						ret.templates.Store(htmlTemplates)
 					}
 				}
 			case err := <-watcher.Errors:
@@ -75,20 +85,162 @@ func NewHTMLTemplateWatcher(directory string) (*HTMLTemplateWatcher, error) {
 	return ret, nil
 }
 
+func (w *HTMLTemplateWatcher) reload(overridesDir string) error {
+	htmlTemplates, textTemplates, err := ParseTemplatesWithOverrides(w.templatesDir, overridesDir)
+	if err != nil {
+		return err
+	}
+
+	w.templates.Store(htmlTemplates)
+	w.textTemplates.Store(textTemplates)
+	return nil
+}
+
+// SetOverridesDirectory changes the directory that templates are overridden from, with an empty directory going back
+// to the built-in templates. The templates that were loaded before are kept if the overrides aren't valid.
+func (w *HTMLTemplateWatcher) SetOverridesDirectory(directory string) error {
+	previous := w.overridesDir.Load().(string)
+	if directory == previous {
+		return nil
+	}
+
+	if err := w.reload(directory); err != nil {
+		return err
+	}
+
+	if previous != "" {
+		w.watcher.Remove(previous)
+	}
+
+	if directory != "" {
+		if err := w.watcher.Add(directory); err != nil {
+			mlog.Warn(fmt.Sprintf("Failed to watch template overrides at %v, err=%v", directory, err))
+		}
+	}
+
+	w.overridesDir.Store(directory)
+	return nil
+}
+
 func (w *HTMLTemplateWatcher) Templates() *template.Template {
 	return w.templates.Load().(*template.Template)
 }
 
+// TextTemplates returns the plain text templates from the overrides directory, or nil if there aren't any.
+func (w *HTMLTemplateWatcher) TextTemplates() *texttemplate.Template {
+	return w.textTemplates.Load().(*texttemplate.Template)
+}
+
 func (w *HTMLTemplateWatcher) Close() {
 	close(w.stop)
 	<-w.stopped
 }
 
+// ParseTemplatesWithOverrides parses the built-in templates and then any overrides for them. HTML overrides (*.html)
+// replace the built-in template with the same name, and text overrides (*.txt) are used as the plain text version of
+// the HTML template with the same name. Overrides are checked by rendering them, so mistakes are caught when they're
+// loaded rather than when an email is sent.
+func ParseTemplatesWithOverrides(templatesDir, overridesDir string) (*template.Template, *texttemplate.Template, error) {
+	htmlTemplates, err := template.ParseGlob(filepath.Join(templatesDir, "*.html"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if overridesDir == "" {
+		return htmlTemplates, nil, nil
+	}
+
+	htmlFiles, _ := filepath.Glob(filepath.Join(overridesDir, "*.html"))
+	textFiles, _ := filepath.Glob(filepath.Join(overridesDir, "*.txt"))
+
+	var overridden []string
+	for _, file := range htmlFiles {
+		fileTemplates, err := template.ParseFiles(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var defined []string
+		for _, t := range fileTemplates.Templates() {
+			defined = append(defined, t.Name())
+		}
+
+		names, err := checkTemplateOverrideNames(htmlTemplates, file, defined)
+		if err != nil {
+			return nil, nil, err
+		}
+		overridden = append(overridden, names...)
+	}
+
+	if len(htmlFiles) > 0 {
+		if _, err := htmlTemplates.ParseFiles(htmlFiles...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var textTemplates *texttemplate.Template
+	if len(textFiles) > 0 {
+		if textTemplates, err = texttemplate.ParseFiles(textFiles...); err != nil {
+			return nil, nil, err
+		}
+
+		for _, file := range textFiles {
+			fileTemplates, err := texttemplate.ParseFiles(file)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var defined []string
+			for _, t := range fileTemplates.Templates() {
+				defined = append(defined, t.Name())
+			}
+
+			names, err := checkTemplateOverrideNames(htmlTemplates, file, defined)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, name := range names {
+				if err := textTemplates.ExecuteTemplate(ioutil.Discard, name, NewHTMLTemplate(nil, name)); err != nil {
+					return nil, nil, fmt.Errorf("template %v in %v can't be rendered: %v", name, file, err)
+				}
+			}
+		}
+	}
+
+	for _, name := range overridden {
+		if err := htmlTemplates.ExecuteTemplate(ioutil.Discard, name, NewHTMLTemplate(nil, name)); err != nil {
+			return nil, nil, fmt.Errorf("template %v can't be rendered: %v", name, err)
+		}
+	}
+
+	return htmlTemplates, textTemplates, nil
+}
+
+// checkTemplateOverrideNames returns the names of the templates defined in an override file, making sure that each of
+// them overrides a built-in template.
+func checkTemplateOverrideNames(htmlTemplates *template.Template, file string, defined []string) ([]string, error) {
+	var overridden []string
+	for _, name := range defined {
+		if name == filepath.Base(file) {
+			continue
+		}
+
+		if htmlTemplates.Lookup(name) == nil {
+			return nil, fmt.Errorf("template %v in %v doesn't override a built-in template", name, file)
+		}
+		overridden = append(overridden, name)
+	}
+
+	return overridden, nil
+}
+
 type HTMLTemplate struct {
-	Templates    *template.Template
-	TemplateName string
-	Props        map[string]interface{}
-	Html         map[string]template.HTML
+	Templates     *template.Template
+	TextTemplates *texttemplate.Template
+	TemplateName  string
+	Props         map[string]interface{}
+	Html          map[string]template.HTML
 }
 
 func NewHTMLTemplate(templates *template.Template, templateName string) *HTMLTemplate {
@@ -119,6 +271,21 @@ func (t *HTMLTemplate) RenderToWriter(w io.Writer) error {
 	return nil
 }
 
+// RenderText renders the plain text version of the template, returning an empty string if there isn't one.
+func (t *HTMLTemplate) RenderText() string {
+	if t.TextTemplates == nil || t.TextTemplates.Lookup(t.TemplateName) == nil {
+		return ""
+	}
+
+	var text bytes.Buffer
+	if err := t.TextTemplates.ExecuteTemplate(&text, t.TemplateName, t); err != nil {
+		mlog.Error(fmt.Sprintf("Error rendering text template %v err=%v", t.TemplateName, err))
+		return ""
+	}
+
+	return text.String()
+}
+
 func TranslateAsHtml(t i18n.TranslateFunc, translationID string, args map[string]interface{}) template.HTML {
 	message := t(translationID, escapeForHtml(args))
 	message = strings.Replace(message, "[[", "<strong>", -1)
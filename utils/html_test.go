@@ -75,6 +75,75 @@ func TestHTMLTemplateWatcher_BadDirectory(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestHTMLTemplateWatcher_Overrides(t *testing.T) {
+	TranslationsPreInit()
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templatesDir := filepath.Join(dir, "templates")
+	overridesDir := filepath.Join(dir, "overrides")
+	require.NoError(t, os.Mkdir(templatesDir, 0700))
+	require.NoError(t, os.Mkdir(overridesDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "foo.html"), []byte(`{{ define "foo" }}foo{{ end }}`), 0600))
+
+	watcher, err := NewHTMLTemplateWatcher(templatesDir)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(overridesDir, "bar.html"), []byte(`{{ define "bar" }}bar{{ end }}`), 0600))
+	assert.Error(t, watcher.SetOverridesDirectory(overridesDir), "shouldn't allow templates that don't override a built-in one")
+	assert.Equal(t, "foo", NewHTMLTemplate(watcher.Templates(), "foo").Render())
+
+	require.NoError(t, os.Remove(filepath.Join(overridesDir, "bar.html")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(overridesDir, "foo.html"), []byte(`{{ define "foo" }}custom {{ .Props.Name }}{{ end }}`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(overridesDir, "foo.txt"), []byte(`{{ define "foo" }}plain {{ .Props.Name }}{{ end }}`), 0600))
+	require.NoError(t, watcher.SetOverridesDirectory(overridesDir))
+
+	tpl := NewHTMLTemplate(watcher.Templates(), "foo")
+	tpl.TextTemplates = watcher.TextTemplates()
+	tpl.Props["Name"] = "<b>name</b>"
+	assert.Equal(t, "custom &lt;b&gt;name&lt;/b&gt;", tpl.Render())
+	assert.Equal(t, "plain <b>name</b>", tpl.RenderText())
+
+	require.NoError(t, watcher.SetOverridesDirectory(""))
+	assert.Equal(t, "foo", NewHTMLTemplate(watcher.Templates(), "foo").Render())
+	assert.Nil(t, watcher.TextTemplates())
+}
+
+func TestParseTemplatesWithOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templatesDir := filepath.Join(dir, "templates")
+	overridesDir := filepath.Join(dir, "overrides")
+	require.NoError(t, os.Mkdir(templatesDir, 0700))
+	require.NoError(t, os.Mkdir(overridesDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "foo.html"), []byte(`{{ define "foo" }}foo{{ end }}`), 0600))
+
+	for name, contents := range map[string]string{
+		"foo.html": `{{ define "foo" }}{{ .Props.Name }`,
+		"foo.txt":  `{{ define "bar" }}bar{{ end }}`,
+		"baz.html": `{{ define "foo" }}{{ template "missing" }}{{ end }}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(overridesDir, name)
+			require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+			defer os.Remove(path)
+
+			_, _, err := ParseTemplatesWithOverrides(templatesDir, overridesDir)
+			assert.Error(t, err)
+		})
+	}
+
+	htmlTemplates, textTemplates, err := ParseTemplatesWithOverrides(templatesDir, overridesDir)
+	require.NoError(t, err)
+	assert.NotNil(t, htmlTemplates.Lookup("foo"))
+	assert.Nil(t, textTemplates)
+}
+
 func TestHTMLTemplate(t *testing.T) {
 	tpl := template.New("test")
 	_, err := tpl.Parse(`{{ define "foo" }}foo{{ .Props.Bar }}{{ end }}`)
@@ -20,6 +20,7 @@ type ObjectCache interface {
 	Purge()
 	Get(key interface{}) (value interface{}, ok bool)
 	Remove(key interface{})
+	Keys() []interface{}
 	Len() int
 	Name() string
 	GetInvalidateClusterEvent() string
@@ -0,0 +1,148 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/mattermost/mattermost-server/mlog"
+)
+
+// The number of keys asked for at a time when going through all of the keys of a cache
+const REDIS_CACHE_SCAN_COUNT = 1000
+
+// RedisCache is an ObjectCache that keeps its values in Redis so that it's shared by every server that uses the same
+// Redis database. Values are encoded with gob, so each cache holds values of a single type, which is given when it's
+// created. Since Redis can't be reached without a round trip, failures are treated as cache misses.
+type RedisCache struct {
+	client                 *redis.Client
+	prefix                 string
+	name                   string
+	valueType              reflect.Type
+	defaultExpiry          int64
+	invalidateClusterEvent string
+}
+
+// NewRedisCache creates a cache that stores its values under keys that start with prefix. The type of value is the
+// type of everything that's stored in the cache.
+func NewRedisCache(client *redis.Client, prefix string, name string, value interface{}, defaultExpiry int64, invalidateClusterEvent string) *RedisCache {
+	return &RedisCache{
+		client:                 client,
+		prefix:                 prefix,
+		name:                   name,
+		valueType:              reflect.TypeOf(value),
+		defaultExpiry:          defaultExpiry,
+		invalidateClusterEvent: invalidateClusterEvent,
+	}
+}
+
+func (c *RedisCache) key(key interface{}) string {
+	return c.prefix + fmt.Sprint(key)
+}
+
+func (c *RedisCache) AddWithDefaultExpires(key, value interface{}) {
+	c.AddWithExpiresInSecs(key, value, c.defaultExpiry)
+}
+
+func (c *RedisCache) AddWithExpiresInSecs(key, value interface{}, expireAtSecs int64) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		mlog.Error("Unable to encode a value for the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+		return
+	}
+
+	var expiry time.Duration
+	if expireAtSecs > 0 {
+		expiry = time.Duration(expireAtSecs) * time.Second
+	}
+
+	if err := c.client.Set(c.key(key), buf.Bytes(), expiry).Err(); err != nil {
+		mlog.Debug("Unable to add a value to the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+	}
+}
+
+func (c *RedisCache) Get(key interface{}) (interface{}, bool) {
+	data, err := c.client.Get(c.key(key)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			mlog.Debug("Unable to get a value from the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+		}
+		return nil, false
+	}
+
+	value := reflect.New(c.valueType)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(value.Interface()); err != nil {
+		mlog.Error("Unable to decode a value from the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+		return nil, false
+	}
+
+	return value.Elem().Interface(), true
+}
+
+func (c *RedisCache) Remove(key interface{}) {
+	if err := c.client.Del(c.key(key)).Err(); err != nil {
+		mlog.Warn("Unable to remove a value from the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+	}
+}
+
+// Purge removes everything from the cache, for every server that shares it.
+func (c *RedisCache) Purge() {
+	c.scan(func(keys []string) {
+		if err := c.client.Del(keys...).Err(); err != nil {
+			mlog.Warn("Unable to purge the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+		}
+	})
+}
+
+// Keys returns the keys of every value in the cache. It has to go through the whole cache, so it should be avoided on
+// hot paths.
+func (c *RedisCache) Keys() []interface{} {
+	keys := []interface{}{}
+	c.scan(func(batch []string) {
+		for _, key := range batch {
+			keys = append(keys, key[len(c.prefix):])
+		}
+	})
+
+	return keys
+}
+
+func (c *RedisCache) Len() int {
+	return len(c.Keys())
+}
+
+func (c *RedisCache) Name() string {
+	return c.name
+}
+
+func (c *RedisCache) GetInvalidateClusterEvent() string {
+	return c.invalidateClusterEvent
+}
+
+// scan calls f with each batch of the cache's keys.
+func (c *RedisCache) scan(f func(keys []string)) {
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(cursor, c.prefix+"*", REDIS_CACHE_SCAN_COUNT).Result()
+		if err != nil {
+			mlog.Warn("Unable to list the keys of the Redis cache", mlog.String("cache", c.name), mlog.Err(err))
+			return
+		}
+
+		if len(keys) > 0 {
+			f(keys)
+		}
+
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
@@ -4,6 +4,7 @@
 package utils
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
@@ -111,6 +112,18 @@ func UpdateAssetsSubpath(subpath string) error {
 	return nil
 }
 
+// RewritePluginAssetsSubpath rewrites the references to `/static/plugins/*` in a plugin's webapp bundle to assume
+// the application is hosted at the given subpath instead of at the root.
+func RewritePluginAssetsSubpath(contents []byte, subpath string) []byte {
+	if subpath == "" || subpath == "/" {
+		return contents
+	}
+
+	newPath := path.Join(subpath, "static", "plugins") + "/"
+
+	return bytes.Replace(contents, []byte("/static/plugins/"), []byte(newPath), -1)
+}
+
 // UpdateAssetsSubpathFromConfig uses UpdateAssetsSubpath and any path defined in the SiteURL.
 func UpdateAssetsSubpathFromConfig(config *model.Config) error {
 	// Don't rewrite in development environments, since webpack in developer mode constantly
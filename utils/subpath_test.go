@@ -410,3 +410,15 @@ const newSubpathManifestJson = `{
   "background_color": "#ffffff"
 }
 `
+
+func TestRewritePluginAssetsSubpath(t *testing.T) {
+	bundle := []byte(`__webpack_require__.p = "/static/plugins/com.example/"; var img = "/static/plugins/com.example/icon.png";`)
+
+	require.Equal(t, string(bundle), string(utils.RewritePluginAssetsSubpath(bundle, "")))
+	require.Equal(t, string(bundle), string(utils.RewritePluginAssetsSubpath(bundle, "/")))
+	require.Equal(
+		t,
+		`__webpack_require__.p = "/subpath/static/plugins/com.example/"; var img = "/subpath/static/plugins/com.example/icon.png";`,
+		string(utils.RewritePluginAssetsSubpath(bundle, "/subpath")),
+	)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TimingHistogramBuckets are the upper bounds, in milliseconds, of the buckets that durations are counted in. Anything
+// slower than the last bound is counted in an extra overflow bucket.
+var TimingHistogramBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// TimingHistogram counts durations in fixed buckets so that it can be kept in memory indefinitely. It is safe for
+// concurrent use.
+type TimingHistogram struct {
+	mutex  sync.Mutex
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+// TimingHistogramSnapshot is a copy of the values of a TimingHistogram. Counts has one more entry than
+// TimingHistogramBuckets for the durations that were slower than all of them.
+type TimingHistogramSnapshot struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []int64   `json:"counts"`
+	Count   int64     `json:"count"`
+	SumMs   float64   `json:"sum_ms"`
+}
+
+func NewTimingHistogram() *TimingHistogram {
+	return &TimingHistogram{
+		counts: make([]int64, len(TimingHistogramBuckets)+1),
+	}
+}
+
+func (h *TimingHistogram) Observe(elapsed time.Duration) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	bucket := len(TimingHistogramBuckets)
+	for i, bound := range TimingHistogramBuckets {
+		if ms <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.counts[bucket]++
+	h.count++
+	h.sum += ms
+}
+
+// Snapshot returns the current values of the histogram, clearing them first if reset is true.
+func (h *TimingHistogram) Snapshot(reset bool) *TimingHistogramSnapshot {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	snapshot := &TimingHistogramSnapshot{
+		Buckets: TimingHistogramBuckets,
+		Counts:  make([]int64, len(h.counts)),
+		Count:   h.count,
+		SumMs:   h.sum,
+	}
+	copy(snapshot.Counts, h.counts)
+
+	if reset {
+		h.counts = make([]int64, len(TimingHistogramBuckets)+1)
+		h.count = 0
+		h.sum = 0
+	}
+
+	return snapshot
+}
+
+// Percentile returns the upper bound of the bucket that contains the given percentile, or -1 if it falls in the
+// overflow bucket. Zero is returned when nothing has been observed.
+func (s *TimingHistogramSnapshot) Percentile(percentile float64) float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	target := int64(math.Ceil(float64(s.Count) * percentile / 100))
+	if target < 1 {
+		target = 1
+	}
+
+	var seen int64
+	for i, count := range s.Counts {
+		seen += count
+		if seen >= target {
+			if i < len(s.Buckets) {
+				return s.Buckets[i]
+			}
+			break
+		}
+	}
+
+	return -1
+}
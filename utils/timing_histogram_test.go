@@ -0,0 +1,39 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingHistogram(t *testing.T) {
+	h := NewTimingHistogram()
+
+	snapshot := h.Snapshot(false)
+	assert.Equal(t, int64(0), snapshot.Count)
+	assert.Equal(t, float64(0), snapshot.Percentile(50))
+
+	for i := 0; i < 8; i++ {
+		h.Observe(3 * time.Millisecond)
+	}
+	h.Observe(80 * time.Millisecond)
+	h.Observe(time.Minute)
+
+	snapshot = h.Snapshot(true)
+	assert.Equal(t, int64(10), snapshot.Count)
+	assert.InDelta(t, 8*3+80+60000, snapshot.SumMs, 0.001)
+	assert.Len(t, snapshot.Counts, len(TimingHistogramBuckets)+1)
+	assert.Equal(t, int64(8), snapshot.Counts[1])
+	assert.Equal(t, int64(1), snapshot.Counts[5])
+	assert.Equal(t, int64(1), snapshot.Counts[len(TimingHistogramBuckets)])
+
+	assert.Equal(t, float64(5), snapshot.Percentile(50))
+	assert.Equal(t, float64(100), snapshot.Percentile(90))
+	assert.Equal(t, float64(-1), snapshot.Percentile(99))
+
+	assert.Equal(t, int64(0), h.Snapshot(false).Count, "the histogram should be cleared after a reset")
+}
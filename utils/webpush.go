@@ -0,0 +1,154 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+)
+
+const (
+	WEB_PUSH_RECORD_SIZE     = 4096
+	WEB_PUSH_SALT_LENGTH     = 16
+	WEB_PUSH_AUTH_LENGTH     = 16
+	WEB_PUSH_PUBLIC_KEY_SIZE = 65
+	WEB_PUSH_TAG_LENGTH      = 16
+
+	// The largest payload that fits in a single aes128gcm record once the padding delimiter and tag are added.
+	WEB_PUSH_MAX_PAYLOAD_SIZE = WEB_PUSH_RECORD_SIZE - WEB_PUSH_TAG_LENGTH - 1
+)
+
+// EncryptWebPushPayload encrypts a message for a push subscription using the aes128gcm content encoding described in
+// RFC 8291. The userAgentPublicKey and authSecret are the decoded p256dh and auth keys of the subscription.
+func EncryptWebPushPayload(payload []byte, userAgentPublicKey []byte, authSecret []byte) ([]byte, error) {
+	if len(payload) > WEB_PUSH_MAX_PAYLOAD_SIZE {
+		return nil, errors.New("web push payload is too large")
+	}
+
+	if len(authSecret) != WEB_PUSH_AUTH_LENGTH {
+		return nil, errors.New("invalid web push auth secret")
+	}
+
+	curve := elliptic.P256()
+
+	uaX, uaY := elliptic.Unmarshal(curve, userAgentPublicKey)
+	if uaX == nil {
+		return nil, errors.New("invalid web push public key")
+	}
+
+	// a new key pair is used for every message
+	asPrivate, asX, asY, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asX, asY)
+
+	salt := make([]byte, WEB_PUSH_SALT_LENGTH)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	sharedX, _ := curve.ScalarMult(uaX, uaY, asPrivate)
+	sharedSecret := make([]byte, 32)
+	sharedX.FillBytes(sharedSecret)
+
+	contentEncryptionKey, nonce := deriveWebPushKeys(sharedSecret, authSecret, salt, userAgentPublicKey, asPublic)
+
+	block, err := aes.NewCipher(contentEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 marks the last and only record
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, WEB_PUSH_SALT_LENGTH+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[WEB_PUSH_SALT_LENGTH:], WEB_PUSH_RECORD_SIZE)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func deriveWebPushKeys(sharedSecret, authSecret, salt, userAgentPublicKey, applicationServerPublicKey []byte) ([]byte, []byte) {
+	keyInfo := append([]byte("WebPush: info\x00"), userAgentPublicKey...)
+	keyInfo = append(keyInfo, applicationServerPublicKey...)
+	ikm := hkdfExpand(hkdfExtract(authSecret, sharedSecret), keyInfo, 32)
+
+	prk := hkdfExtract(salt, ikm)
+	contentEncryptionKey := hkdfExpand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdfExpand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+
+	return contentEncryptionKey, nonce
+}
+
+func hkdfExtract(salt, ikm []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand only supports outputs up to the length of a single SHA-256 block, which is all web push needs.
+func hkdfExpand(prk, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{1})
+	return mac.Sum(nil)[:length]
+}
+
+// CreateVapidAuthorization returns the value of the Authorization header that identifies the application server to
+// the push service hosting the given endpoint, as described in RFC 8292.
+func CreateVapidAuthorization(endpoint string, subject string, key *ecdsa.PrivateKey, expiresAt time.Time) (string, error) {
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	header, _ := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"aud": endpointUrl.Scheme + "://" + endpointUrl.Host,
+		"exp": expiresAt.Unix(),
+		"sub": subject,
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	return "vapid t=" + token + ", k=" + EncodeVapidPublicKey(&key.PublicKey), nil
+}
+
+// EncodeVapidPublicKey returns the public key in the form expected by browsers when subscribing to push messages.
+func EncodeVapidPublicKey(key *ecdsa.PublicKey) string {
+	return base64.RawURLEncoding.EncodeToString(elliptic.Marshal(key.Curve, key.X, key.Y))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decryptWebPushPayload decrypts a message the same way that a browser receiving it would.
+func decryptWebPushPayload(t *testing.T, body []byte, userAgentPrivateKey []byte, userAgentPublicKey []byte, authSecret []byte) []byte {
+	require.True(t, len(body) > WEB_PUSH_SALT_LENGTH+5)
+
+	salt := body[:WEB_PUSH_SALT_LENGTH]
+	assert.Equal(t, uint32(WEB_PUSH_RECORD_SIZE), binary.BigEndian.Uint32(body[WEB_PUSH_SALT_LENGTH:]))
+
+	keyLength := int(body[WEB_PUSH_SALT_LENGTH+4])
+	require.Equal(t, WEB_PUSH_PUBLIC_KEY_SIZE, keyLength)
+	asPublic := body[WEB_PUSH_SALT_LENGTH+5 : WEB_PUSH_SALT_LENGTH+5+keyLength]
+	ciphertext := body[WEB_PUSH_SALT_LENGTH+5+keyLength:]
+
+	curve := elliptic.P256()
+	asX, asY := elliptic.Unmarshal(curve, asPublic)
+	require.NotNil(t, asX)
+
+	sharedX, _ := curve.ScalarMult(asX, asY, userAgentPrivateKey)
+	sharedSecret := make([]byte, 32)
+	sharedX.FillBytes(sharedSecret)
+
+	contentEncryptionKey, nonce := deriveWebPushKeys(sharedSecret, authSecret, salt, userAgentPublicKey, asPublic)
+
+	block, err := aes.NewCipher(contentEncryptionKey)
+	require.Nil(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.Nil(t, err)
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.Nil(t, err)
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncryptWebPushPayload(t *testing.T) {
+	curve := elliptic.P256()
+	uaPrivate, uaX, uaY, err := elliptic.GenerateKey(curve, rand.Reader)
+	require.Nil(t, err)
+	uaPublic := elliptic.Marshal(curve, uaX, uaY)
+
+	authSecret := make([]byte, WEB_PUSH_AUTH_LENGTH)
+	_, err = rand.Read(authSecret)
+	require.Nil(t, err)
+
+	payload := []byte(`{"message":"hello"}`)
+
+	body, err := EncryptWebPushPayload(payload, uaPublic, authSecret)
+	require.Nil(t, err)
+	assert.Equal(t, payload, decryptWebPushPayload(t, body, uaPrivate, uaPublic, authSecret))
+
+	_, err = EncryptWebPushPayload(payload, uaPublic[1:], authSecret)
+	assert.NotNil(t, err, "should fail with an invalid public key")
+
+	_, err = EncryptWebPushPayload(payload, uaPublic, authSecret[1:])
+	assert.NotNil(t, err, "should fail with an invalid auth secret")
+
+	_, err = EncryptWebPushPayload(make([]byte, WEB_PUSH_MAX_PAYLOAD_SIZE+1), uaPublic, authSecret)
+	assert.NotNil(t, err, "should fail with a payload that's too large")
+}
+
+func TestCreateVapidAuthorization(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+	authorization, err := CreateVapidAuthorization("https://push.example.com/send/abc?x=1", "https://mattermost.example.com", key, expiresAt)
+	require.Nil(t, err)
+
+	require.True(t, strings.HasPrefix(authorization, "vapid t="))
+	parts := strings.Split(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	require.Len(t, parts, 2)
+	assert.Equal(t, EncodeVapidPublicKey(&key.PublicKey), parts[1])
+
+	token := strings.Split(parts[0], ".")
+	require.Len(t, token, 3)
+
+	claimsJson, err := base64.RawURLEncoding.DecodeString(token[1])
+	require.Nil(t, err)
+	var claims map[string]interface{}
+	require.Nil(t, json.Unmarshal(claimsJson, &claims))
+	assert.Equal(t, "https://push.example.com", claims["aud"])
+	assert.Equal(t, "https://mattermost.example.com", claims["sub"])
+	assert.Equal(t, float64(expiresAt.Unix()), claims["exp"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(token[2])
+	require.Nil(t, err)
+	require.Len(t, signature, 64)
+
+	hash := sha256.Sum256([]byte(token[0] + "." + token[1]))
+	assert.True(t, ecdsa.Verify(&key.PublicKey, hash[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+}
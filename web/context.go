@@ -140,6 +140,18 @@ func (c *Context) MfaRequired() {
 	}
 }
 
+// OAuthScopeRequired checks that the scope of an OAuth access token allows a request that needs the given scope. Routes
+// that don't give a scope need the default scope, which grants full access.
+func (c *Context) OAuthScopeRequired(scope string) {
+	if scope == "" {
+		scope = model.DEFAULT_SCOPE
+	}
+
+	if !c.Session.HasOAuthScope(scope) {
+		c.Err = model.NewAppError("", "api.context.oauth_scope.app_error", map[string]interface{}{"Scope": scope}, "OAuthScopeRequired", http.StatusForbidden)
+	}
+}
+
 func (c *Context) RemoveSessionCookie(w http.ResponseWriter, r *http.Request) {
 	cookie := &http.Cookie{
 		Name:     model.SESSION_COOKIE_TOKEN,
@@ -180,6 +192,28 @@ func (c *Context) HandleEtag(etag string, routeName string, w http.ResponseWrite
 	return false
 }
 
+// HandleLastModified responds with 304 Not Modified if the client's copy is at least as new as lastModified, which is
+// given in milliseconds. It only applies when the client didn't send an etag, since an etag is more precise, so it
+// should be used after HandleEtag.
+func (c *Context) HandleLastModified(lastModified int64, w http.ResponseWriter, r *http.Request) bool {
+	if lastModified <= 0 || r.Header.Get(model.HEADER_ETAG_CLIENT) != "" {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get(model.HEADER_IF_MODIFIED_SINCE))
+	if err != nil {
+		return false
+	}
+
+	// HTTP dates only have a precision of seconds
+	if lastModified/1000 > since.Unix() {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
 func NewInvalidParamError(parameter string) *model.AppError {
 	err := model.NewAppError("Context", "api.context.invalid_body_param.app_error", map[string]interface{}{"Name": parameter}, "", http.StatusBadRequest)
 	return err
@@ -412,6 +446,18 @@ func (c *Context) RequireService() *Context {
 	return c
 }
 
+func (c *Context) RequireExternalId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.ExternalId) == 0 {
+		c.SetInvalidUrlParam("external_id")
+	}
+
+	return c
+}
+
 func (c *Context) RequirePreferenceName() *Context {
 	if c.Err != nil {
 		return c
@@ -516,6 +562,72 @@ func (c *Context) RequireSchemeId() *Context {
 	return c
 }
 
+func (c *Context) RequireSubscriptionId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.SubscriptionId) != 26 {
+		c.SetInvalidUrlParam("subscription_id")
+	}
+	return c
+}
+
+func (c *Context) RequireBridgeId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.BridgeId) != 26 {
+		c.SetInvalidUrlParam("bridge_id")
+	}
+	return c
+}
+
+func (c *Context) RequireGroupId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.GroupId) != 26 {
+		c.SetInvalidUrlParam("group_id")
+	}
+	return c
+}
+
+func (c *Context) RequireReminderId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.ReminderId) != 26 {
+		c.SetInvalidUrlParam("reminder_id")
+	}
+	return c
+}
+
+func (c *Context) RequireBotUserId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.BotUserId) != 26 {
+		c.SetInvalidUrlParam("bot_user_id")
+	}
+	return c
+}
+
+func (c *Context) RequireCategoryId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if len(c.Params.CategoryId) != 26 {
+		c.SetInvalidUrlParam("category_id")
+	}
+	return c
+}
+
 func (c *Context) RequireRoleName() *Context {
 	if c.Err != nil {
 		return c
@@ -0,0 +1,100 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package web
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// debugBodyBuffer keeps the first part of a request or response body so that it can be recorded.
+type debugBodyBuffer struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *debugBodyBuffer) write(p []byte) {
+	remaining := model.DEBUG_RECORDING_MAX_BODY_SIZE - b.buf.Len()
+	if len(p) > remaining {
+		p = p[:remaining]
+		b.truncated = true
+	}
+
+	b.buf.Write(p)
+}
+
+// debugRequestBody records the parts of a request body that the handler reads. Bodies that a handler never reads
+// aren't read just to record them.
+type debugRequestBody struct {
+	io.ReadCloser
+	body debugBodyBuffer
+}
+
+func (r *debugRequestBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.body.write(p[:n])
+	return n, err
+}
+
+// debugResponseWriter records the status code and body of a response as it's written.
+type debugResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       debugBodyBuffer
+}
+
+func (w *debugResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *debugResponseWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	w.body.write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush allows handlers that stream their responses to keep doing so while they're being recorded.
+func (w *debugResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func newDebugRecordedRequest(c *Context, r *http.Request, w *debugResponseWriter, integration *model.IntegrationSource, start time.Time) *model.DebugRecordedRequest {
+	request := &model.DebugRecordedRequest{
+		RequestId:       c.RequestId,
+		Timestamp:       start.UnixNano() / int64(time.Millisecond),
+		ElapsedMs:       float64(time.Since(start)) / float64(time.Millisecond),
+		Method:          r.Method,
+		Path:            r.URL.Path,
+		Query:           model.SanitizeDebugQuery(r.URL.RawQuery),
+		UserId:          c.Session.UserId,
+		Integration:     integration,
+		RequestHeaders:  model.SanitizeDebugHeaders(r.Header),
+		StatusCode:      w.statusCode,
+		ResponseHeaders: model.SanitizeDebugHeaders(w.Header()),
+		ResponseBody:    model.SanitizeDebugBody(w.Header().Get("Content-Type"), w.body.buf.Bytes()),
+	}
+	request.ResponseTruncated = w.body.truncated
+
+	if body, ok := r.Body.(*debugRequestBody); ok {
+		request.RequestBody = model.SanitizeDebugBody(r.Header.Get("Content-Type"), body.body.buf.Bytes())
+		request.RequestTruncated = body.body.truncated
+	}
+
+	if request.StatusCode == 0 {
+		request.StatusCode = http.StatusOK
+	}
+
+	return request
+}
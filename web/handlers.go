@@ -43,6 +43,7 @@ type Handler struct {
 	TrustRequester bool
 	RequireMfa     bool
 	IsStatic       bool
+	OAuthScope     string
 }
 
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -119,6 +120,23 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		mlog.String("method", r.Method),
 	)
 
+	integration := model.GetIntegrationSource(&c.Session, c.Path, r.UserAgent())
+	if integration != nil {
+		c.Log = c.Log.With(
+			mlog.String("integration_type", integration.Type),
+			mlog.String("integration_id", integration.Id),
+		)
+	}
+
+	var debugWriter *debugResponseWriter
+	if r.URL.Path != model.API_URL_SUFFIX+"/websocket" && c.App.ShouldRecordDebugRequest(r.URL.Path, integration) {
+		debugWriter = &debugResponseWriter{ResponseWriter: w}
+		w = debugWriter
+		if r.Body != nil {
+			r.Body = &debugRequestBody{ReadCloser: r.Body}
+		}
+	}
+
 	if c.Err == nil && h.RequireSession {
 		c.SessionRequired()
 	}
@@ -127,6 +145,10 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		c.MfaRequired()
 	}
 
+	if c.Err == nil && h.RequireSession && c.Session.IsOAuth {
+		c.OAuthScopeRequired(h.OAuthScope)
+	}
+
 	if c.Err == nil {
 		h.HandleFunc(c, w, r)
 	}
@@ -157,6 +179,8 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			c.Err.StatusCode = 500
 			c.Err.Where = ""
 			c.Err.IsOAuth = false
+			c.Err.Code = model.ERROR_CODE_INTERNAL_ERROR
+			c.Err.Hint = c.T(model.GetErrorCodeHintId(model.ERROR_CODE_INTERNAL_ERROR))
 		}
 
 		if IsApiCall(c.App, r) || IsWebhookCall(c.App, r) || len(r.Header.Get("X-Mobile-App")) > 0 {
@@ -179,4 +203,12 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			c.App.Metrics.ObserveHttpRequestDuration(elapsed)
 		}
 	}
+
+	if integration != nil && r.URL.Path != model.API_URL_SUFFIX+"/websocket" {
+		c.App.RecordIntegrationRequest(integration, time.Since(now), c.Err != nil)
+	}
+
+	if debugWriter != nil {
+		c.App.RecordDebugRequest(newDebugRecordedRequest(c, r, debugWriter, integration, now))
+	}
 }
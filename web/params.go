@@ -42,12 +42,19 @@ type Params struct {
 	EmojiName      string
 	Category       string
 	Service        string
+	ExternalId     string
 	JobId          string
 	JobType        string
 	ActionId       string
 	RoleId         string
 	RoleName       string
 	SchemeId       string
+	SubscriptionId string
+	BridgeId       string
+	GroupId        string
+	ReminderId     string
+	BotUserId      string
+	CategoryId     string
 	Scope          string
 	Page           int
 	PerPage        int
@@ -141,6 +148,10 @@ func ParamsFromRequest(r *http.Request) *Params {
 		params.Service = val
 	}
 
+	if val, ok := props["external_id"]; ok {
+		params.ExternalId = val
+	}
+
 	if val, ok := props["preference_name"]; ok {
 		params.PreferenceName = val
 	}
@@ -173,6 +184,30 @@ func ParamsFromRequest(r *http.Request) *Params {
 		params.SchemeId = val
 	}
 
+	if val, ok := props["subscription_id"]; ok {
+		params.SubscriptionId = val
+	}
+
+	if val, ok := props["bridge_id"]; ok {
+		params.BridgeId = val
+	}
+
+	if val, ok := props["group_id"]; ok {
+		params.GroupId = val
+	}
+
+	if val, ok := props["reminder_id"]; ok {
+		params.ReminderId = val
+	}
+
+	if val, ok := props["bot_user_id"]; ok {
+		params.BotUserId = val
+	}
+
+	if val, ok := props["category_id"]; ok {
+		params.CategoryId = val
+	}
+
 	params.Scope = query.Get("scope")
 
 	if val, err := strconv.Atoi(query.Get("page")); err != nil || val < 0 {
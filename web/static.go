@@ -9,10 +9,10 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
-	"github.com/NYTimes/gziphandler"
-
+	"github.com/mattermost/mattermost-server/app"
 	"github.com/mattermost/mattermost-server/mlog"
 	"github.com/mattermost/mattermost-server/model"
 	"github.com/mattermost/mattermost-server/utils"
@@ -30,14 +30,15 @@ func (w *Web) InitStatic() {
 		mime.AddExtensionType(".wasm", "application/wasm")
 
 		staticHandler := staticFilesHandler(http.StripPrefix(path.Join(subpath, "static"), http.FileServer(http.Dir(staticDir))))
-		pluginHandler := staticFilesHandler(http.StripPrefix(path.Join(subpath, "static", "plugins"), http.FileServer(http.Dir(*w.App.Config().PluginSettings.ClientDirectory))))
+		pluginHandler := http.StripPrefix(path.Join(subpath, "static", "plugins"), pluginAssetsHandler(*w.App.Config().PluginSettings.ClientDirectory))
 
 		if *w.App.Config().ServiceSettings.WebserverMode == "gzip" {
-			staticHandler = gziphandler.GzipHandler(staticHandler)
-			pluginHandler = gziphandler.GzipHandler(pluginHandler)
+			staticHandler = w.App.CompressionHandler(staticHandler)
+			pluginHandler = w.App.CompressionHandler(pluginHandler)
 		}
 
 		w.MainRouter.PathPrefix("/static/plugins/").Handler(pluginHandler)
+		w.MainRouter.PathPrefix("/static/email/").Handler(emailAssetsHandler(w.App, path.Join(subpath, "static", "email")))
 		w.MainRouter.PathPrefix("/static/").Handler(staticHandler)
 		w.MainRouter.Handle("/{anything:.*}", w.NewStaticHandler(root)).Methods("GET")
 
@@ -72,6 +73,58 @@ func root(c *Context, w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, filepath.Join(staticDir, "root.html"))
 }
 
+// emailAssetsHandler serves the branding assets that emails link to from the email templates directory. The directory
+// is looked up for each request so that changes to the config take effect without a restart.
+func emailAssetsHandler(a *app.App, prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		directory := a.EmailAssetsDirectory()
+		if directory == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "max-age=86400, public")
+		http.StripPrefix(prefix, http.FileServer(http.Dir(directory))).ServeHTTP(w, r)
+	})
+}
+
+// Matches the names that plugin webapp bundles are given when they're activated, which include the bundle's hash
+var pluginBundleNameRegexp = regexp.MustCompile(`^.+_([0-9a-f]+)_bundle\.js$`)
+
+// pluginAssetsHandler serves the webapp assets of plugins. Bundles are named after their hash, so they're cached
+// forever, and every other asset has to be revalidated with its ETag since plugins can replace it without renaming it.
+func pluginAssetsHandler(directory string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, err := http.Dir(directory).Open(r.URL.Path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		if matches := pluginBundleNameRegexp.FindStringSubmatch(info.Name()); matches != nil {
+			w.Header().Set("Cache-Control", "max-age=31556926, public, immutable")
+			w.Header().Set("ETag", fmt.Sprintf(`"%s"`, matches[1]))
+		} else {
+			w.Header().Set("Cache-Control", "no-cache, public")
+			w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+		}
+
+		http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+	})
+}
+
 func staticFilesHandler(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Cache-Control", "max-age=31556926, public")
@@ -0,0 +1,75 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginAssetsHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "testplugin"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "testplugin", "testplugin_0123456789abcdef_bundle.js"), []byte("bundle"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "testplugin", "icon.png"), []byte("icon"), 0600))
+
+	handler := http.StripPrefix("/static/plugins", pluginAssetsHandler(dir))
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		for key, values := range header {
+			r.Header[key] = values
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("bundle", func(t *testing.T) {
+		w := serve("/static/plugins/testplugin/testplugin_0123456789abcdef_bundle.js", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "bundle", w.Body.String())
+		assert.Equal(t, "max-age=31556926, public, immutable", w.Header().Get("Cache-Control"))
+		assert.Equal(t, `"0123456789abcdef"`, w.Header().Get("ETag"))
+
+		w = serve("/static/plugins/testplugin/testplugin_0123456789abcdef_bundle.js", http.Header{"If-None-Match": {`"0123456789abcdef"`}})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("other asset", func(t *testing.T) {
+		w := serve("/static/plugins/testplugin/icon.png", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "icon", w.Body.String())
+		assert.Equal(t, "no-cache, public", w.Header().Get("Cache-Control"))
+
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		w = serve("/static/plugins/testplugin/icon.png", http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "testplugin", "icon.png"), []byte("new icon"), 0600))
+
+		w = serve("/static/plugins/testplugin/icon.png", http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "new icon", w.Body.String())
+	})
+
+	t.Run("directories and missing files", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve("/static/plugins/testplugin/", nil).Code)
+		assert.Equal(t, http.StatusNotFound, serve("/static/plugins/testplugin", nil).Code)
+		assert.Equal(t, http.StatusNotFound, serve("/static/plugins/testplugin/missing.js", nil).Code)
+	})
+}
@@ -5,9 +5,10 @@ package web
 
 import (
 	"fmt"
-	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -25,15 +26,19 @@ func incomingWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
+	start := time.Now()
+
 	r.ParseForm()
 
 	var err *model.AppError
 	incomingWebhookPayload := &model.IncomingWebhookRequest{}
+
+	defer func() {
+		recordIncomingWebhookDelivery(c, r, id, incomingWebhookPayload, time.Since(start))
+	}()
 	contentType := r.Header.Get("Content-Type")
 	if strings.Split(contentType, "; ")[0] == "application/x-www-form-urlencoded" {
-		payload := strings.NewReader(r.FormValue("payload"))
-
-		incomingWebhookPayload, err = decodePayload(payload)
+		incomingWebhookPayload, err = c.App.DecodeIncomingWebhookRequest(id, []byte(r.FormValue("payload")))
 		if err != nil {
 			c.Err = err
 			return
@@ -49,7 +54,13 @@ func incomingWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
-		incomingWebhookPayload, err = decodePayload(r.Body)
+		body, readErr := ioutil.ReadAll(r.Body)
+		if readErr != nil {
+			c.Err = model.NewAppError("incomingWebhook", "api.webhook.incoming.error", nil, readErr.Error(), http.StatusBadRequest)
+			return
+		}
+
+		incomingWebhookPayload, err = c.App.DecodeIncomingWebhookRequest(id, body)
 		if err != nil {
 			c.Err = err
 			return
@@ -60,12 +71,19 @@ func incomingWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		mlog.Debug(fmt.Sprint("Incoming webhook received. Content=", incomingWebhookPayload.ToJson()))
 	}
 
-	err = c.App.HandleIncomingWebhook(id, incomingWebhookPayload)
+	post, err := c.App.HandleIncomingWebhook(id, incomingWebhookPayload)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	// Clients that accept JSON get the post's id so that they can update or delete it later
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte((&model.IncomingWebhookResponse{PostId: post.Id}).ToJson()))
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte("ok"))
 }
@@ -90,12 +108,29 @@ func commandWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok"))
 }
 
-func decodePayload(payload io.Reader) (*model.IncomingWebhookRequest, *model.AppError) {
-	incomingWebhookPayload, decodeError := model.IncomingWebhookRequestFromJson(payload)
+// recordIncomingWebhookDelivery adds a request to an incoming webhook to the integration delivery log. Requests to
+// webhooks that don't exist aren't recorded so that they can't push the requests of real integrations out of the log.
+func recordIncomingWebhookDelivery(c *Context, r *http.Request, id string, payload *model.IncomingWebhookRequest, elapsed time.Duration) {
+	if c.Err != nil && c.Err.Id == "web.incoming_webhook.invalid.app_error" {
+		return
+	}
+
+	if _, err := c.App.GetIncomingWebhook(id); err != nil {
+		return
+	}
+
+	statusCode := http.StatusOK
+	deliveryErr := ""
+	if c.Err != nil {
+		c.Err.Translate(c.T)
+		statusCode = c.Err.StatusCode
+		deliveryErr = c.Err.Error()
+	}
 
-	if decodeError != nil {
-		return nil, decodeError
+	var body []byte
+	if payload != nil {
+		body = []byte(payload.ToJson())
 	}
 
-	return incomingWebhookPayload, nil
+	c.App.RecordIntegrationDelivery(model.INTEGRATION_TYPE_INCOMING_WEBHOOK, id, r.URL.Path, "application/json", body, statusCode, elapsed, deliveryErr)
 }
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -230,6 +231,165 @@ func TestIncomingWebhook(t *testing.T) {
 	})
 }
 
+func TestIncomingWebhookRateLimit(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.ServiceSettings.EnableIncomingWebhooks = true
+		*cfg.ServiceSettings.IncomingWebhookRateLimitPerMinute = 0
+	})
+
+	hook, err := th.App.CreateIncomingWebhookForChannel(th.BasicUser.Id, th.BasicChannel, &model.IncomingWebhook{ChannelId: th.BasicChannel.Id, RateLimitPerMinute: 1, RateLimitBurst: 1})
+	require.Nil(t, err)
+
+	unlimitedHook, err := th.App.CreateIncomingWebhookForChannel(th.BasicUser.Id, th.BasicChannel, &model.IncomingWebhook{ChannelId: th.BasicChannel.Id})
+	require.Nil(t, err)
+
+	post := func(hookId string) int {
+		resp, err := http.Post(ApiClient.Url+"/hooks/"+hookId, "application/json", strings.NewReader(`{"text": "test text"}`))
+		require.Nil(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The burst allows one request on top of the rate
+	assert.Equal(t, http.StatusOK, post(hook.Id))
+	assert.Equal(t, http.StatusOK, post(hook.Id))
+	assert.Equal(t, http.StatusTooManyRequests, post(hook.Id))
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, post(unlimitedHook.Id))
+	}
+
+	t.Run("server default", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ServiceSettings.IncomingWebhookRateLimitPerMinute = 1
+			*cfg.ServiceSettings.IncomingWebhookRateLimitBurst = 0
+		})
+
+		assert.Equal(t, http.StatusOK, post(unlimitedHook.Id))
+		assert.Equal(t, http.StatusTooManyRequests, post(unlimitedHook.Id))
+	})
+}
+
+func TestIncomingWebhookChangePost(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableIncomingWebhooks = true })
+
+	hook, err := th.App.CreateIncomingWebhookForChannel(th.BasicUser.Id, th.BasicChannel, &model.IncomingWebhook{ChannelId: th.BasicChannel.Id})
+	require.Nil(t, err)
+
+	otherHook, err := th.App.CreateIncomingWebhookForChannel(th.BasicUser.Id, th.BasicChannel, &model.IncomingWebhook{ChannelId: th.BasicChannel.Id})
+	require.Nil(t, err)
+
+	send := func(hookId string, payload string) (int, string) {
+		req, _ := http.NewRequest("POST", ApiClient.Url+"/hooks/"+hookId, strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, ""
+		}
+
+		response := model.IncomingWebhookResponseFromJson(resp.Body)
+		require.NotNil(t, response)
+		return resp.StatusCode, response.PostId
+	}
+
+	status, postId := send(hook.Id, `{"text": "status: starting"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, postId, "should return the id of the new post")
+
+	status, updatedId := send(hook.Id, fmt.Sprintf(`{"text": "status: done", "post_id": "%v"}`, postId))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, postId, updatedId)
+
+	post, err := th.App.GetSinglePost(postId)
+	require.Nil(t, err)
+	assert.Equal(t, "status: done", post.Message)
+	assert.Equal(t, hook.Id, post.Props[model.POST_PROPS_WEBHOOK_ID])
+
+	status, _ = send(otherHook.Id, fmt.Sprintf(`{"text": "hijacked", "post_id": "%v"}`, postId))
+	assert.Equal(t, http.StatusNotFound, status, "should not change posts created by other hooks")
+
+	userPost, err := th.App.CreatePostMissingChannel(&model.Post{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id, Message: "from a user"}, false)
+	require.Nil(t, err)
+
+	status, _ = send(hook.Id, fmt.Sprintf(`{"text": "not from a hook", "post_id": "%v"}`, userPost.Id))
+	assert.Equal(t, http.StatusNotFound, status, "should not change posts that weren't created by the hook")
+
+	status, _ = send(hook.Id, `{"delete": true}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = send(hook.Id, fmt.Sprintf(`{"delete": true, "post_id": "%v"}`, postId))
+	require.Equal(t, http.StatusOK, status)
+
+	_, err = th.App.GetSinglePost(postId)
+	assert.NotNil(t, err, "should have deleted the post")
+
+	status, _ = send(hook.Id, fmt.Sprintf(`{"text": "status: back", "post_id": "%v"}`, postId))
+	assert.Equal(t, http.StatusNotFound, status, "should not update deleted posts")
+
+	resp, httpErr := http.Post(ApiClient.Url+"/hooks/"+hook.Id, "application/json", strings.NewReader(`{"text": "plain"}`))
+	require.Nil(t, httpErr)
+	body := new(bytes.Buffer)
+	body.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", body.String(), "should keep responding with plain text to other clients")
+}
+
+func TestIncomingWebhookPayloadTemplate(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { cfg.ServiceSettings.EnableIncomingWebhooks = true })
+
+	hook, err := th.App.CreateIncomingWebhookForChannel(th.BasicUser.Id, th.BasicChannel, &model.IncomingWebhook{
+		ChannelId:       th.BasicChannel.Id,
+		PayloadTemplate: "{{ .status }}: {{ range .alerts }}{{ .labels.alertname }} {{ end }}",
+	})
+	require.Nil(t, err)
+
+	send := func(contentType string, payload string) *model.IncomingWebhookResponse {
+		req, _ := http.NewRequest("POST", ApiClient.Url+"/hooks/"+hook.Id, strings.NewReader(payload))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", "application/json")
+
+		resp, httpErr := http.DefaultClient.Do(req)
+		require.Nil(t, httpErr)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil
+		}
+
+		return model.IncomingWebhookResponseFromJson(resp.Body)
+	}
+
+	response := send("application/json", `{"status": "firing", "alerts": [{"labels": {"alertname": "HighLatency"}}, {"labels": {"alertname": "DiskFull"}}]}`)
+	require.NotNil(t, response, "should accept payloads that aren't webhook requests")
+
+	post, err := th.App.GetSinglePost(response.PostId)
+	require.Nil(t, err)
+	assert.Equal(t, "firing: HighLatency DiskFull", post.Message)
+
+	response = send("application/x-www-form-urlencoded", "payload="+url.QueryEscape(`{"status": "resolved", "alerts": []}`))
+	require.NotNil(t, response, "should render form encoded payloads too")
+
+	post, err = th.App.GetSinglePost(response.PostId)
+	require.Nil(t, err)
+	assert.Equal(t, "resolved:", post.Message)
+
+	assert.Nil(t, send("application/json", `{"status": `), "should reject invalid JSON")
+}
+
 func TestCommandWebhooks(t *testing.T) {
 	th := Setup().InitBasic()
 	defer th.TearDown()
@@ -271,3 +431,61 @@ func TestCommandWebhooks(t *testing.T) {
 		t.Fatal("expected error for sixth usage")
 	}
 }
+
+func TestCommandWebhookUpdatesOriginal(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	cmd, err := th.App.CreateCommand(&model.Command{
+		CreatorId: th.BasicUser.Id,
+		TeamId:    th.BasicTeam.Id,
+		URL:       "http://nowhere.com",
+		Method:    model.COMMAND_METHOD_POST,
+		Trigger:   "progress"})
+	require.Nil(t, err)
+
+	hook, err := th.App.CreateCommandWebhook(cmd.Id, &model.CommandArgs{
+		TeamId:    th.BasicTeam.Id,
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+	})
+	require.Nil(t, err)
+
+	send := func(body string) int {
+		resp, httpErr := http.Post(ApiClient.Url+"/hooks/commands/"+hook.Id, "application/json", bytes.NewBufferString(body))
+		require.Nil(t, httpErr)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	getHook := func() *model.CommandWebhook {
+		result := <-th.App.Srv.Store.CommandWebhook().Get(hook.Id)
+		require.Nil(t, result.Err)
+		return result.Data.(*model.CommandWebhook)
+	}
+
+	require.Equal(t, http.StatusOK, send(`{"text":"starting","append_original":true}`))
+	original := getHook()
+	require.Len(t, original.EphemeralPostId, 26, "should remember the ephemeral response")
+	assert.Equal(t, "starting", original.EphemeralMessage)
+
+	// Updates can be sent more often than the webhook can otherwise be used
+	for i := 0; i < 10; i++ {
+		require.Equal(t, http.StatusOK, send(`{"text":"step","append_original":true}`))
+	}
+
+	updated := getHook()
+	assert.Equal(t, original.EphemeralPostId, updated.EphemeralPostId, "should have updated the same response")
+	assert.Equal(t, "starting"+strings.Repeat("\nstep", 10), updated.EphemeralMessage)
+	assert.Equal(t, 1, updated.UseCount)
+
+	require.Equal(t, http.StatusOK, send(`{"text":"done","replace_original":true}`))
+	updated = getHook()
+	assert.Equal(t, original.EphemeralPostId, updated.EphemeralPostId)
+	assert.Equal(t, "done", updated.EphemeralMessage)
+
+	require.Equal(t, http.StatusOK, send(`{"text":"another response"}`))
+	updated = getHook()
+	assert.NotEqual(t, original.EphemeralPostId, updated.EphemeralPostId, "should have sent a new response")
+	assert.Equal(t, 2, updated.UseCount)
+}
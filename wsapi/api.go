@@ -22,6 +22,7 @@ func Init(a *app.App, router *app.WebSocketRouter) {
 	api.InitSystem()
 	api.InitStatus()
 	api.InitWebrtc()
+	api.InitEventFilter()
 
 	a.HubStart()
 }
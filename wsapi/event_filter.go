@@ -0,0 +1,26 @@
+// Copyright (c) 2018-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package wsapi
+
+import (
+	"github.com/mattermost/mattermost-server/app"
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func (api *API) InitEventFilter() {
+	api.Router.Handle("subscribe_events", api.ApiWebSocketConnHandler(api.subscribeEvents))
+}
+
+// subscribeEvents replaces the filter for the events that are sent on a connection. Subscribing without any events or
+// channels goes back to sending every event.
+func (api *API) subscribeEvents(conn *app.WebConn, req *model.WebSocketRequest) (map[string]interface{}, *model.AppError) {
+	filter := model.WebSocketEventFilterFromInterface(req.Data)
+	if err := filter.IsValid(); err != nil {
+		return nil, err
+	}
+
+	conn.SetEventFilter(filter)
+
+	return filter.ToMap(), nil
+}
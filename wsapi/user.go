@@ -23,13 +23,9 @@ func (api *API) userTyping(req *model.WebSocketRequest) (map[string]interface{},
 		parentId = ""
 	}
 
-	omitUsers := make(map[string]bool, 1)
-	omitUsers[req.Session.UserId] = true
-
-	event := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_TYPING, "", channelId, "", omitUsers)
-	event.Add("parent_id", parentId)
-	event.Add("user_id", req.Session.UserId)
-	api.App.Publish(event)
+	if err := api.App.PublishUserTyping(req.Session.UserId, channelId, parentId); err != nil {
+		return nil, err
+	}
 
 	return nil, nil
 }
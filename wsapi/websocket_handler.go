@@ -14,12 +14,19 @@ import (
 )
 
 func (api *API) ApiWebSocketHandler(wh func(*model.WebSocketRequest) (map[string]interface{}, *model.AppError)) webSocketHandler {
+	return webSocketHandler{api.App, func(conn *app.WebConn, r *model.WebSocketRequest) (map[string]interface{}, *model.AppError) {
+		return wh(r)
+	}}
+}
+
+// ApiWebSocketConnHandler is like ApiWebSocketHandler for actions that change the connection that they're sent on.
+func (api *API) ApiWebSocketConnHandler(wh func(*app.WebConn, *model.WebSocketRequest) (map[string]interface{}, *model.AppError)) webSocketHandler {
 	return webSocketHandler{api.App, wh}
 }
 
 type webSocketHandler struct {
 	app         *app.App
-	handlerFunc func(*model.WebSocketRequest) (map[string]interface{}, *model.AppError)
+	handlerFunc func(*app.WebConn, *model.WebSocketRequest) (map[string]interface{}, *model.AppError)
 }
 
 func (wh webSocketHandler) ServeWebSocket(conn *app.WebConn, r *model.WebSocketRequest) {
@@ -42,7 +49,7 @@ func (wh webSocketHandler) ServeWebSocket(conn *app.WebConn, r *model.WebSocketR
 	var data map[string]interface{}
 	var err *model.AppError
 
-	if data, err = wh.handlerFunc(r); err != nil {
+	if data, err = wh.handlerFunc(conn, r); err != nil {
 		mlog.Error(fmt.Sprintf("%v:%v seq=%v uid=%v %v [details: %v]", "websocket", r.Action, r.Seq, r.Session.UserId, err.SystemMessage(utils.T), err.DetailedError))
 		err.DetailedError = ""
 		errResp := model.NewWebSocketError(r.Seq, err)